@@ -59,6 +59,20 @@ func (src *AWSCluster) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Status.Bastion.PrivateDNSName = restored.Status.Bastion.PrivateDNSName
 	}
 	dst.Spec.Partition = restored.Spec.Partition
+	dst.Spec.DefaultLifecycleHooks = restored.Spec.DefaultLifecycleHooks
+	dst.Spec.ResourcePolicyRef = restored.Spec.ResourcePolicyRef
+	dst.Spec.QuotaCheck = restored.Spec.QuotaCheck
+	dst.Status.QuotaUsage = restored.Status.QuotaUsage
+	dst.Status.LastQuotaCheckTime = restored.Status.LastQuotaCheckTime
+	dst.Spec.EventBridge = restored.Spec.EventBridge
+	dst.Status.EventBridge = restored.Status.EventBridge
+	dst.Status.OrphanedResources = restored.Status.OrphanedResources
+	dst.Status.LastOrphanScanTime = restored.Status.LastOrphanScanTime
+	dst.Status.ControlPlaneLoadBalancerMigration = restored.Status.ControlPlaneLoadBalancerMigration
+	dst.Status.UserDataMigrationPending = restored.Status.UserDataMigrationPending
+	dst.Spec.DefaultInstanceMetadataOptions = restored.Spec.DefaultInstanceMetadataOptions
+	dst.Spec.InstanceConnectEndpoint = restored.Spec.InstanceConnectEndpoint
+	dst.Status.InstanceConnectEndpoint = restored.Status.InstanceConnectEndpoint
 
 	for role, sg := range restored.Status.Network.SecurityGroups {
 		dst.Status.Network.SecurityGroups[role] = sg
@@ -99,8 +113,10 @@ func (src *AWSCluster) ConvertTo(dstRaw conversion.Hub) error {
 		restoreIPAMPool(restored.Spec.NetworkSpec.VPC.IPv6.IPAMPool, dst.Spec.NetworkSpec.VPC.IPv6.IPAMPool)
 	}
 
+	dst.Spec.NetworkSpec.VPC.UseDefault = restored.Spec.NetworkSpec.VPC.UseDefault
 	dst.Spec.NetworkSpec.VPC.EmptyRoutesDefaultVPCSecurityGroup = restored.Spec.NetworkSpec.VPC.EmptyRoutesDefaultVPCSecurityGroup
 	dst.Spec.NetworkSpec.VPC.PrivateDNSHostnameTypeOnLaunch = restored.Spec.NetworkSpec.VPC.PrivateDNSHostnameTypeOnLaunch
+	dst.Spec.NetworkSpec.VPC.NATGateway = restored.Spec.NetworkSpec.VPC.NATGateway
 
 	// Restore SubnetSpec.ResourceID field, if any.
 	for _, subnet := range restored.Spec.NetworkSpec.Subnets {
@@ -153,6 +169,7 @@ func restoreControlPlaneLoadBalancer(restored, dst *infrav2.AWSLoadBalancerSpec)
 	dst.LoadBalancerType = restored.LoadBalancerType
 	dst.DisableHostsRewrite = restored.DisableHostsRewrite
 	dst.PreserveClientIP = restored.PreserveClientIP
+	dst.TargetGroupAttributes = restored.TargetGroupAttributes
 	dst.IngressRules = restored.IngressRules
 	dst.AdditionalListeners = restored.AdditionalListeners
 	dst.AdditionalSecurityGroups = restored.AdditionalSecurityGroups
@@ -37,6 +37,12 @@ func (r *AWSClusterTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
+	dst.Spec.Template.Spec.DefaultLifecycleHooks = restored.Spec.Template.Spec.DefaultLifecycleHooks
+	dst.Spec.Template.Spec.ResourcePolicyRef = restored.Spec.Template.Spec.ResourcePolicyRef
+	dst.Spec.Template.Spec.QuotaCheck = restored.Spec.Template.Spec.QuotaCheck
+	dst.Spec.Template.Spec.EventBridge = restored.Spec.Template.Spec.EventBridge
+	dst.Spec.Template.Spec.DefaultInstanceMetadataOptions = restored.Spec.Template.Spec.DefaultInstanceMetadataOptions
+	dst.Spec.Template.Spec.InstanceConnectEndpoint = restored.Spec.Template.Spec.InstanceConnectEndpoint
 
 	return nil
 }
@@ -37,9 +37,16 @@ func (src *AWSMachine) ConvertTo(dstRaw conversion.Hub) error {
 
 	dst.Spec.Ignition = restored.Spec.Ignition
 	dst.Spec.InstanceMetadataOptions = restored.Spec.InstanceMetadataOptions
+	dst.Spec.OS = restored.Spec.OS
 	dst.Spec.PlacementGroupName = restored.Spec.PlacementGroupName
 	dst.Spec.PrivateDNSName = restored.Spec.PrivateDNSName
 	dst.Spec.SecurityGroupOverrides = restored.Spec.SecurityGroupOverrides
+	dst.Spec.UserDataTransform = restored.Spec.UserDataTransform
+	dst.Status.BootstrapDataRenderedAt = restored.Status.BootstrapDataRenderedAt
+	dst.Status.InstanceRequestedAt = restored.Status.InstanceRequestedAt
+	dst.Status.InstanceRunningAt = restored.Status.InstanceRunningAt
+	dst.Status.AddressesAssignedAt = restored.Status.AddressesAssignedAt
+	dst.Status.UserDataDelivery = restored.Status.UserDataDelivery
 
 	return nil
 }
@@ -86,9 +93,11 @@ func (r *AWSMachineTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
 	dst.Spec.Template.Spec.Ignition = restored.Spec.Template.Spec.Ignition
 	dst.Spec.Template.Spec.InstanceMetadataOptions = restored.Spec.Template.Spec.InstanceMetadataOptions
+	dst.Spec.Template.Spec.OS = restored.Spec.Template.Spec.OS
 	dst.Spec.Template.Spec.PlacementGroupName = restored.Spec.Template.Spec.PlacementGroupName
 	dst.Spec.Template.Spec.PrivateDNSName = restored.Spec.Template.Spec.PrivateDNSName
 	dst.Spec.Template.Spec.SecurityGroupOverrides = restored.Spec.Template.Spec.SecurityGroupOverrides
+	dst.Spec.Template.Spec.UserDataTransform = restored.Spec.Template.Spec.UserDataTransform
 
 	return nil
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta2
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -56,6 +57,11 @@ type AWSClusterSpec struct {
 	// +optional
 	AdditionalTags Tags `json:"additionalTags,omitempty"`
 
+	// DefaultInstanceMetadataOptions are the metadata options of the instances of the cluster, i.e. of its
+	// AWSMachines and AWSMachinePools, which don't set their own instanceMetadataOptions.
+	// +optional
+	DefaultInstanceMetadataOptions *InstanceMetadataOptions `json:"defaultInstanceMetadataOptions,omitempty"`
+
 	// ControlPlaneLoadBalancer is optional configuration for customizing control plane behavior.
 	// +optional
 	ControlPlaneLoadBalancer *AWSLoadBalancerSpec `json:"controlPlaneLoadBalancer,omitempty"`
@@ -95,6 +101,22 @@ type AWSClusterSpec struct {
 	// different ImageLookupBaseOS.
 	ImageLookupBaseOS string `json:"imageLookupBaseOS,omitempty"`
 
+	// DefaultLifecycleHooks are lifecycle hooks added to the Auto Scaling groups of all the
+	// AWSMachinePools of the cluster. A lifecycle hook of an AWSMachinePool overrides the
+	// default lifecycle hook with the same name. Removing a default lifecycle hook deletes it
+	// from the Auto Scaling groups that don't define it themselves.
+	// +optional
+	DefaultLifecycleHooks []AWSLifecycleHook `json:"defaultLifecycleHooks,omitempty"`
+
+	// ResourcePolicyRef references a ConfigMap in the namespace of the cluster holding defaults shared
+	// by the clusters which reference it, with additional tags under the additionalTags key and lifecycle
+	// hooks under the lifecycleHooks key, both in YAML. The tags are merged beneath the AdditionalTags
+	// of the cluster and its AWSMachinePools, and the lifecycle hooks beneath the DefaultLifecycleHooks
+	// of the cluster and the lifecycle hooks of its AWSMachinePools, which win on conflict. Changes to
+	// the ConfigMap are applied on the next reconciliation of each object.
+	// +optional
+	ResourcePolicyRef *corev1.LocalObjectReference `json:"resourcePolicyRef,omitempty"`
+
 	// Bastion contains options to configure the bastion host.
 	// +optional
 	Bastion Bastion `json:"bastion"`
@@ -111,6 +133,22 @@ type AWSClusterSpec struct {
 	// BootstrapFormatIgnition feature flag to be enabled).
 	// +optional
 	S3Bucket *S3Bucket `json:"s3Bucket,omitempty"`
+
+	// QuotaCheck configures the check of the AWS service quotas the cluster depends on.
+	// The check is disabled when not set.
+	// +optional
+	QuotaCheck *QuotaCheck `json:"quotaCheck,omitempty"`
+
+	// EventBridge configures the EventBridge rule and SQS queue used to track the state changes of
+	// the EC2 instances of the cluster, regardless of the EventBridgeInstanceState feature flag.
+	// When not set, the rule and queue are managed as long as the feature flag is enabled.
+	// +optional
+	EventBridge *EventBridgeConfig `json:"eventBridge,omitempty"`
+
+	// InstanceConnectEndpoint configures an EC2 Instance Connect Endpoint in the VPC of the cluster, which
+	// gives SSH access to the instances in private subnets without a bastion host or public IP addresses.
+	// +optional
+	InstanceConnectEndpoint *InstanceConnectEndpoint `json:"instanceConnectEndpoint,omitempty"`
 }
 
 // AWSIdentityKind defines allowed AWS identity types.
@@ -248,6 +286,51 @@ type AWSLoadBalancerSpec struct {
 	// PreserveClientIP lets the user control if preservation of client ips must be retained or not.
 	// If this is enabled 6443 will be opened to 0.0.0.0/0.
 	PreserveClientIP bool `json:"preserveClientIP,omitempty"`
+
+	// TargetGroupAttributes sets optional attributes on the target groups of the load balancer.
+	// This is only applicable to V2 load balancer types; the classic ELB does not support it.
+	// Removing the field or one of its attributes leaves the values last applied to the target
+	// groups unchanged; they have to be reset on the target groups directly.
+	// +optional
+	TargetGroupAttributes *TargetGroupAttributesSpec `json:"targetGroupAttributes,omitempty"`
+}
+
+// TargetGroupAttributesSpec defines the optional attributes of the target groups
+// created for a V2 load balancer.
+type TargetGroupAttributesSpec struct {
+	// SlowStartDurationSeconds sets the time period, in seconds, during which a newly registered
+	// target receives a linearly increasing share of the traffic. This lets a new control plane
+	// instance warm up during rolling upgrades. Only supported by Application Load Balancers.
+	// +kubebuilder:validation:Minimum=30
+	// +kubebuilder:validation:Maximum=900
+	// +optional
+	SlowStartDurationSeconds *int64 `json:"slowStartDurationSeconds,omitempty"`
+
+	// Stickiness sets the sticky sessions configuration of the target groups.
+	// +optional
+	Stickiness *TargetGroupStickinessSpec `json:"stickiness,omitempty"`
+}
+
+// TargetGroupStickinessType defines the type of sticky sessions of a target group.
+type TargetGroupStickinessType string
+
+var (
+	// TargetGroupStickinessTypeLBCookie uses a load balancer generated cookie. Only supported by Application Load Balancers.
+	TargetGroupStickinessTypeLBCookie = TargetGroupStickinessType("lb_cookie")
+	// TargetGroupStickinessTypeSourceIP routes on the client source IP. Only supported by Network Load Balancers.
+	TargetGroupStickinessTypeSourceIP = TargetGroupStickinessType("source_ip")
+)
+
+// TargetGroupStickinessSpec defines the sticky sessions configuration of a target group.
+type TargetGroupStickinessSpec struct {
+	// Enabled enables sticky sessions.
+	Enabled bool `json:"enabled"`
+
+	// Type sets the type of sticky sessions. Defaults to lb_cookie for Application Load Balancers
+	// and source_ip for Network Load Balancers.
+	// +kubebuilder:validation:Enum=lb_cookie;source_ip
+	// +optional
+	Type TargetGroupStickinessType `json:"type,omitempty"`
 }
 
 // AdditionalListenerSpec defines the desired state of an
@@ -272,6 +355,265 @@ type AWSClusterStatus struct {
 	FailureDomains clusterv1.FailureDomains `json:"failureDomains,omitempty"`
 	Bastion        *Instance                `json:"bastion,omitempty"`
 	Conditions     clusterv1.Conditions     `json:"conditions,omitempty"`
+
+	// QuotaUsage is the usage of the AWS service quotas the cluster depends on, as reported by
+	// the last quota check.
+	// +optional
+	QuotaUsage []QuotaUsage `json:"quotaUsage,omitempty"`
+
+	// LastQuotaCheckTime is the time of the last quota check.
+	// +optional
+	LastQuotaCheckTime *metav1.Time `json:"lastQuotaCheckTime,omitempty"`
+
+	// EventBridge holds the names of the EventBridge rule and SQS queue managed for the cluster.
+	// +optional
+	EventBridge *EventBridgeStatus `json:"eventBridge,omitempty"`
+
+	// OrphanedResources are the AWS resources owned by the cluster which no object of the cluster
+	// references anymore, as reported by the last scan requested with the scan-orphans annotation.
+	// +optional
+	OrphanedResources []OrphanedResource `json:"orphanedResources,omitempty"`
+
+	// LastOrphanScanTime is the time of the last scan for orphaned resources.
+	// +optional
+	LastOrphanScanTime *metav1.Time `json:"lastOrphanScanTime,omitempty"`
+
+	// ControlPlaneLoadBalancerMigration is the progress of the migration of the control plane load
+	// balancer from a Classic Load Balancer to a Network Load Balancer. It is removed once the
+	// Classic Load Balancer is deleted.
+	// +optional
+	ControlPlaneLoadBalancerMigration *LoadBalancerMigration `json:"controlPlaneLoadBalancerMigration,omitempty"`
+
+	// UserDataMigrationPending is the number of AWSMachines of the cluster whose instance still stores
+	// its bootstrap data in the instance user data, while the cluster has an S3 bucket. These machines
+	// have the NeedsUserDataMigrationAnnotation.
+	// +optional
+	UserDataMigrationPending int32 `json:"userDataMigrationPending,omitempty"`
+
+	// InstanceConnectEndpoint is the EC2 Instance Connect Endpoint managed for the cluster.
+	// +optional
+	InstanceConnectEndpoint *InstanceConnectEndpointStatus `json:"instanceConnectEndpoint,omitempty"`
+}
+
+// InstanceConnectEndpoint configures the EC2 Instance Connect Endpoint of the cluster.
+type InstanceConnectEndpoint struct {
+	// Enabled creates the endpoint. When disabled, the endpoint previously created for the cluster
+	// is deleted.
+	Enabled bool `json:"enabled"`
+
+	// SubnetSelection selects the subnet of the endpoint. Defaults to the first private subnet of the
+	// cluster, ordered by availability zone.
+	// +optional
+	SubnetSelection *InstanceConnectEndpointSubnetSelection `json:"subnetSelection,omitempty"`
+
+	// SecurityGroups are the IDs of the security groups of the endpoint. Defaults to the default
+	// security group of the VPC.
+	// +optional
+	SecurityGroups []string `json:"securityGroups,omitempty"`
+
+	// PreserveClientIP makes the connections to the instances come from the IP address of the client
+	// instead of the IP address of the endpoint.
+	// +optional
+	PreserveClientIP bool `json:"preserveClientIP,omitempty"`
+
+	// Port is the port of the instances which the control plane and node security groups allow from
+	// the security groups of the endpoint. Defaults to 22.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int64 `json:"port,omitempty"`
+}
+
+// InstanceConnectEndpointSubnetSelection selects the subnet of the EC2 Instance Connect Endpoint.
+// At most one of ID and AvailabilityZone can be set.
+type InstanceConnectEndpointSubnetSelection struct {
+	// ID is the ID of the subnet.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// AvailabilityZone selects the private subnet of the cluster in the availability zone.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+}
+
+// InstanceConnectEndpointStatus describes the EC2 Instance Connect Endpoint of the cluster.
+type InstanceConnectEndpointStatus struct {
+	// ID is the ID of the endpoint.
+	ID string `json:"id"`
+
+	// State is the state of the endpoint, e.g. create-in-progress or create-complete.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// SubnetID is the ID of the subnet of the endpoint.
+	// +optional
+	SubnetID string `json:"subnetID,omitempty"`
+
+	// SecurityGroupIDs are the IDs of the security groups of the endpoint.
+	// +optional
+	SecurityGroupIDs []string `json:"securityGroupIDs,omitempty"`
+
+	// DNSName is the DNS name of the endpoint.
+	// +optional
+	DNSName string `json:"dnsName,omitempty"`
+}
+
+// EventBridgeConfig configures the EventBridge rule and SQS queue used to track the state changes
+// of the EC2 instances of the cluster.
+type EventBridgeConfig struct {
+	// Enabled creates the rule and queue of the cluster. When disabled, the rule and queue
+	// previously created for the cluster are deleted.
+	Enabled bool `json:"enabled"`
+
+	// QueueNameOverride is the name of the SQS queue. Defaults to a name derived from the name
+	// and UID of the cluster. It can't be changed once the queue is created.
+	// +kubebuilder:validation:MaxLength=64
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	// +optional
+	QueueNameOverride string `json:"queueNameOverride,omitempty"`
+}
+
+// EventBridgeStatus holds the names of the EventBridge rule and SQS queue managed for the cluster.
+type EventBridgeStatus struct {
+	// QueueName is the name of the SQS queue.
+	QueueName string `json:"queueName"`
+
+	// RuleName is the name of the EventBridge rule.
+	RuleName string `json:"ruleName"`
+}
+
+// QuotaCheck configures the check of the AWS service quotas the cluster depends on, e.g. the
+// Elastic IPs needed by the NAT gateways or the vCPUs of the instances of the machine pools.
+type QuotaCheck struct {
+	// Enabled turns on the check. The usage of the quotas is reported in the status of the
+	// AWSCluster, and a warning event is emitted when the usage projected once the cluster is
+	// fully provisioned exceeds 80% of a limit.
+	// Quotas that can't be read, e.g. because the controller isn't allowed to call the Service
+	// Quotas API, are skipped.
+	Enabled bool `json:"enabled"`
+
+	// Interval is the minimum time between two checks.
+	// Defaults to 1h.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// QuotaUsage is the usage of an AWS service quota.
+type QuotaUsage struct {
+	// ServiceCode is the code of the AWS service the quota belongs to, e.g. ec2.
+	ServiceCode string `json:"serviceCode"`
+
+	// QuotaCode is the code of the quota, e.g. L-0263D0A3.
+	QuotaCode string `json:"quotaCode"`
+
+	// QuotaName is the name of the quota.
+	QuotaName string `json:"quotaName"`
+
+	// Limit is the value of the quota in the region of the cluster.
+	Limit int64 `json:"limit"`
+
+	// Usage is the current usage of the quota in the region of the cluster, including the
+	// resources that don't belong to the cluster.
+	Usage int64 `json:"usage"`
+
+	// ProjectedUsage is the usage of the quota once the resources the cluster is still expected
+	// to create exist, e.g. when its machine pools are scaled to their maximum size.
+	ProjectedUsage int64 `json:"projectedUsage"`
+}
+
+const (
+	// ResourcePolicyAdditionalTagsKey is the key of the additional tags in a resource policy ConfigMap.
+	ResourcePolicyAdditionalTagsKey = "additionalTags"
+
+	// ResourcePolicyLifecycleHooksKey is the key of the lifecycle hooks in a resource policy ConfigMap.
+	ResourcePolicyLifecycleHooksKey = "lifecycleHooks"
+)
+
+// OrphanedResourceKind is the kind of an orphaned resource.
+type OrphanedResourceKind string
+
+const (
+	// OrphanedAutoScalingGroup is an Auto Scaling group of a machine pool.
+	OrphanedAutoScalingGroup OrphanedResourceKind = "AutoScalingGroup"
+
+	// OrphanedLaunchTemplate is a launch template of a machine pool.
+	OrphanedLaunchTemplate OrphanedResourceKind = "LaunchTemplate"
+
+	// OrphanedLoadBalancer is a load balancer of the API server.
+	OrphanedLoadBalancer OrphanedResourceKind = "LoadBalancer"
+)
+
+// LoadBalancerMigrationPhase is a phase of the migration of the control plane load balancer.
+type LoadBalancerMigrationPhase string
+
+const (
+	// LoadBalancerMigrationPhaseProvisioning is the phase in which the Network Load Balancer and its
+	// target groups are created, and the control plane instances are registered with them.
+	LoadBalancerMigrationPhaseProvisioning = LoadBalancerMigrationPhase("Provisioning")
+
+	// LoadBalancerMigrationPhaseWaitingForHealthyTargets is the phase in which the migration waits for
+	// the control plane instances to be healthy targets of the Network Load Balancer.
+	LoadBalancerMigrationPhaseWaitingForHealthyTargets = LoadBalancerMigrationPhase("WaitingForHealthyTargets")
+
+	// LoadBalancerMigrationPhaseUpdatingEndpoint is the phase in which the status and the control plane
+	// endpoint of the AWSCluster are switched to the Network Load Balancer.
+	LoadBalancerMigrationPhaseUpdatingEndpoint = LoadBalancerMigrationPhase("UpdatingEndpoint")
+
+	// LoadBalancerMigrationPhaseDeletingClassicLoadBalancer is the phase in which the Classic Load Balancer
+	// is deleted.
+	LoadBalancerMigrationPhaseDeletingClassicLoadBalancer = LoadBalancerMigrationPhase("DeletingClassicLoadBalancer")
+
+	// LoadBalancerMigrationPhaseAborted is the phase of a migration which was given up because the Network
+	// Load Balancer didn't become healthy in time. The Network Load Balancer is deleted and the Classic Load
+	// Balancer is kept until the load balancer type is set back to classic.
+	LoadBalancerMigrationPhaseAborted = LoadBalancerMigrationPhase("Aborted")
+)
+
+// LoadBalancerMigration is the progress of the migration of the control plane load balancer from a
+// Classic Load Balancer to a Network Load Balancer.
+type LoadBalancerMigration struct {
+	// Phase is the current phase of the migration.
+	// +kubebuilder:validation:Enum=Provisioning;WaitingForHealthyTargets;UpdatingEndpoint;DeletingClassicLoadBalancer;Aborted
+	Phase LoadBalancerMigrationPhase `json:"phase"`
+
+	// StartTime is the time the migration started.
+	StartTime metav1.Time `json:"startTime"`
+
+	// ClassicLoadBalancerName is the name of the Classic Load Balancer being replaced.
+	ClassicLoadBalancerName string `json:"classicLoadBalancerName"`
+
+	// ClassicLoadBalancerDNSName is the DNS name of the Classic Load Balancer being replaced.
+	// +optional
+	ClassicLoadBalancerDNSName string `json:"classicLoadBalancerDNSName,omitempty"`
+
+	// LoadBalancerName is the name of the Network Load Balancer, once it is created.
+	// +optional
+	LoadBalancerName string `json:"loadBalancerName,omitempty"`
+
+	// LoadBalancerDNSName is the DNS name of the Network Load Balancer, once it is created.
+	// +optional
+	LoadBalancerDNSName string `json:"loadBalancerDNSName,omitempty"`
+}
+
+// IsUpdatingEndpoint returns true if the control plane endpoint may be switched to the Network Load Balancer,
+// or has already been.
+func (m *LoadBalancerMigration) IsUpdatingEndpoint() bool {
+	return m != nil && (m.Phase == LoadBalancerMigrationPhaseUpdatingEndpoint || m.Phase == LoadBalancerMigrationPhaseDeletingClassicLoadBalancer)
+}
+
+// OrphanedResource is an AWS resource owned by the cluster which no object of the cluster references.
+type OrphanedResource struct {
+	// Kind is the kind of the resource.
+	// +kubebuilder:validation:Enum=AutoScalingGroup;LaunchTemplate;LoadBalancer
+	Kind OrphanedResourceKind `json:"kind"`
+
+	// ID identifies the resource: the name of an Auto Scaling group, the ID of a launch template or
+	// the ARN of a load balancer.
+	ID string `json:"id"`
+
+	// Name is the name of the resource.
+	// +optional
+	Name string `json:"name,omitempty"`
 }
 
 // S3Bucket defines a supporting S3 bucket for the cluster, currently can be optionally used for Ignition.
@@ -57,8 +57,12 @@ func (r *AWSCluster) ValidateCreate() (admission.Warnings, error) {
 	allErrs = append(allErrs, r.validateSSHKeyName()...)
 	allErrs = append(allErrs, r.Spec.AdditionalTags.Validate()...)
 	allErrs = append(allErrs, r.Spec.S3Bucket.Validate()...)
+	allErrs = append(allErrs, r.Spec.QuotaCheck.Validate()...)
+	allErrs = append(allErrs, r.Spec.InstanceConnectEndpoint.Validate()...)
 	allErrs = append(allErrs, r.validateNetwork()...)
+	allErrs = append(allErrs, r.validateDefaultVPC()...)
 	allErrs = append(allErrs, r.validateControlPlaneLBs()...)
+	allErrs = append(allErrs, ValidateLifecycleHooks(field.NewPath("spec", "defaultLifecycleHooks"), r.Spec.DefaultLifecycleHooks, r.Spec.Region)...)
 
 	return nil, aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
@@ -98,9 +102,11 @@ func (r *AWSCluster) ValidateUpdate(old runtime.Object) (admission.Warnings, err
 
 		allErrs = append(allErrs, r.validateControlPlaneLoadBalancerUpdate(oldLB, newLB)...)
 	}
+	allErrs = append(allErrs, r.validateControlPlaneLoadBalancerMigration(oldC)...)
 
 	if !cmp.Equal(oldC.Spec.ControlPlaneEndpoint, clusterv1.APIEndpoint{}) &&
-		!cmp.Equal(r.Spec.ControlPlaneEndpoint, oldC.Spec.ControlPlaneEndpoint) {
+		!cmp.Equal(r.Spec.ControlPlaneEndpoint, oldC.Spec.ControlPlaneEndpoint) &&
+		!oldC.isMigratedControlPlaneEndpoint(r.Spec.ControlPlaneEndpoint) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "controlPlaneEndpoint"), r.Spec.ControlPlaneEndpoint, "field is immutable"),
 		)
@@ -119,6 +125,12 @@ func (r *AWSCluster) ValidateUpdate(old runtime.Object) (admission.Warnings, err
 		}
 	}
 
+	if oldC.Spec.NetworkSpec.VPC.UseDefault != r.Spec.NetworkSpec.VPC.UseDefault {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "network", "vpc", "useDefault"),
+				r.Spec.NetworkSpec.VPC.UseDefault, "field is immutable"))
+	}
+
 	// If a identityRef is already set, do not allow removal of it.
 	if oldC.Spec.IdentityRef != nil && r.Spec.IdentityRef == nil {
 		allErrs = append(allErrs,
@@ -127,6 +139,14 @@ func (r *AWSCluster) ValidateUpdate(old runtime.Object) (admission.Warnings, err
 		)
 	}
 
+	// Renaming the queue would leave the previous one behind, EventBridge must be disabled first.
+	if oldC.Status.EventBridge != nil && r.Spec.EventBridge.GetQueueNameOverride() != oldC.Spec.EventBridge.GetQueueNameOverride() {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "eventBridge", "queueNameOverride"),
+				r.Spec.EventBridge.GetQueueNameOverride(), "field cannot be modified once the queue is created"),
+		)
+	}
+
 	if annotations.IsExternallyManaged(oldC) && !annotations.IsExternallyManaged(r) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("metadata", "annotations"),
@@ -137,6 +157,11 @@ func (r *AWSCluster) ValidateUpdate(old runtime.Object) (admission.Warnings, err
 	allErrs = append(allErrs, r.Spec.Bastion.Validate()...)
 	allErrs = append(allErrs, r.Spec.AdditionalTags.Validate()...)
 	allErrs = append(allErrs, r.Spec.S3Bucket.Validate()...)
+	allErrs = append(allErrs, r.Spec.QuotaCheck.Validate()...)
+	allErrs = append(allErrs, r.Spec.InstanceConnectEndpoint.Validate()...)
+	allErrs = append(allErrs, r.Spec.InstanceConnectEndpoint.ValidateUpdate(oldC.Spec.InstanceConnectEndpoint, oldC.Status.InstanceConnectEndpoint)...)
+	allErrs = append(allErrs, r.validateTargetGroupAttributes()...)
+	allErrs = append(allErrs, ValidateLifecycleHooks(field.NewPath("spec", "defaultLifecycleHooks"), r.Spec.DefaultLifecycleHooks, r.Spec.Region)...)
 
 	return nil, aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
@@ -194,6 +219,56 @@ func (r *AWSCluster) validateControlPlaneLoadBalancerUpdate(oldlb, newlb *AWSLoa
 	return allErrs
 }
 
+// validateControlPlaneLoadBalancerMigration validates a change of the type of the control plane load balancer
+// from or to classic. The only supported change is the migration from a Classic Load Balancer to a Network
+// Load Balancer, which must be acknowledged with the MigrateControlPlaneLoadBalancerAnnotation, and which can
+// be reverted until the control plane endpoint is switched to the Network Load Balancer.
+func (r *AWSCluster) validateControlPlaneLoadBalancerMigration(oldC *AWSCluster) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if oldC.Spec.ControlPlaneLoadBalancer == nil || r.Spec.ControlPlaneLoadBalancer == nil {
+		return allErrs
+	}
+	oldType, newType := oldC.Spec.ControlPlaneLoadBalancer.LoadBalancerType, r.Spec.ControlPlaneLoadBalancer.LoadBalancerType
+	if oldType == newType || (oldType != LoadBalancerTypeClassic && newType != LoadBalancerTypeClassic) ||
+		oldType == LoadBalancerTypeDisabled || newType == LoadBalancerTypeDisabled {
+		return allErrs
+	}
+
+	typePath := field.NewPath("spec", "controlPlaneLoadBalancer", "type")
+	migration := oldC.Status.ControlPlaneLoadBalancerMigration
+	switch {
+	case oldType == LoadBalancerTypeClassic && newType == LoadBalancerTypeNLB:
+		if r.Annotations[MigrateControlPlaneLoadBalancerAnnotation] != "true" {
+			allErrs = append(allErrs,
+				field.Forbidden(typePath, fmt.Sprintf("migrating the control plane load balancer to a network load balancer changes the control plane endpoint, set the %s annotation to \"true\" to acknowledge it", MigrateControlPlaneLoadBalancerAnnotation)),
+			)
+		}
+		// Both load balancers exist during the migration, so their names can't be the same.
+		if r.Spec.ControlPlaneLoadBalancer.Name != nil {
+			allErrs = append(allErrs,
+				field.Forbidden(typePath, "the control plane load balancer can't be migrated to a network load balancer when its name is set"),
+			)
+		}
+	case oldType == LoadBalancerTypeNLB && newType == LoadBalancerTypeClassic && migration != nil && !migration.IsUpdatingEndpoint():
+		// Aborts the migration, the Classic Load Balancer is still in use.
+	default:
+		allErrs = append(allErrs,
+			field.Invalid(typePath, newType, "field is immutable, the control plane load balancer can only be migrated from classic to nlb"),
+		)
+	}
+
+	return allErrs
+}
+
+// isMigratedControlPlaneEndpoint returns true if the endpoint is the DNS name of the Network Load Balancer
+// the control plane load balancer is migrated to, once the control plane endpoint may be switched to it.
+func (r *AWSCluster) isMigratedControlPlaneEndpoint(endpoint clusterv1.APIEndpoint) bool {
+	migration := r.Status.ControlPlaneLoadBalancerMigration
+	return migration.IsUpdatingEndpoint() && migration.LoadBalancerDNSName != "" &&
+		endpoint.Host == migration.LoadBalancerDNSName && endpoint.Port == r.Spec.ControlPlaneEndpoint.Port
+}
+
 // Default satisfies the defaulting webhook interface.
 func (r *AWSCluster) Default() {
 	SetObjectDefaults_AWSCluster(r)
@@ -267,6 +342,37 @@ func (r *AWSCluster) validateNetwork() field.ErrorList {
 	return allErrs
 }
 
+// validateDefaultVPC checks that the default VPC isn't combined with settings of another VPC. It only applies
+// on creation, as the ID and the CIDR block of the default VPC are set once it is discovered.
+func (r *AWSCluster) validateDefaultVPC() field.ErrorList {
+	var allErrs field.ErrorList
+	vpc := r.Spec.NetworkSpec.VPC
+	if !vpc.UseDefault {
+		return allErrs
+	}
+
+	vpcPath := field.NewPath("spec", "network", "vpc")
+	if vpc.ID != "" {
+		allErrs = append(allErrs, field.Invalid(vpcPath.Child("id"), vpc.ID, "id and useDefault cannot be used together"))
+	}
+	if vpc.CidrBlock != "" {
+		allErrs = append(allErrs, field.Invalid(vpcPath.Child("cidrBlock"), vpc.CidrBlock, "cidrBlock and useDefault cannot be used together"))
+	}
+	if vpc.IPAMPool != nil {
+		allErrs = append(allErrs, field.Invalid(vpcPath.Child("ipamPool"), vpc.IPAMPool, "ipamPool and useDefault cannot be used together"))
+	}
+	if vpc.IPv6 != nil {
+		allErrs = append(allErrs, field.Invalid(vpcPath.Child("ipv6"), vpc.IPv6, "ipv6 and useDefault cannot be used together"))
+	}
+	for i, subnet := range r.Spec.NetworkSpec.Subnets {
+		if subnet.CidrBlock != "" {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "network", "subnets").Index(i).Child("cidrBlock"), subnet.CidrBlock, "subnets of the default VPC must be referenced by id"))
+		}
+	}
+
+	return allErrs
+}
+
 func (r *AWSCluster) validateControlPlaneLBs() field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -352,5 +458,55 @@ func (r *AWSCluster) validateControlPlaneLBs() field.ErrorList {
 		}
 	}
 
+	allErrs = append(allErrs, r.validateTargetGroupAttributes()...)
+
+	return allErrs
+}
+
+// validateTargetGroupAttributes checks that the target group attributes of the control plane
+// load balancers are supported by their load balancer type.
+func (r *AWSCluster) validateTargetGroupAttributes() field.ErrorList {
+	var allErrs field.ErrorList
+
+	loadBalancers := []struct {
+		name string
+		lb   *AWSLoadBalancerSpec
+	}{
+		{name: "controlPlaneLoadBalancer", lb: r.Spec.ControlPlaneLoadBalancer},
+		{name: "secondaryControlPlaneLoadBalancer", lb: r.Spec.SecondaryControlPlaneLoadBalancer},
+	}
+	for _, l := range loadBalancers {
+		lb := l.lb
+		if lb == nil || lb.TargetGroupAttributes == nil {
+			continue
+		}
+		attrsPath := field.NewPath("spec", l.name, "targetGroupAttributes")
+		attrs := lb.TargetGroupAttributes
+
+		switch lb.LoadBalancerType {
+		case LoadBalancerTypeClassic, "":
+			allErrs = append(allErrs, field.Forbidden(attrsPath, "target group attributes are not supported by the classic ELB"))
+			continue
+		case LoadBalancerTypeDisabled:
+			allErrs = append(allErrs, field.Forbidden(attrsPath, "target group attributes cannot be set if the LoadBalancer reconciliation is disabled"))
+			continue
+		}
+
+		if attrs.SlowStartDurationSeconds != nil && lb.LoadBalancerType != LoadBalancerTypeALB {
+			allErrs = append(allErrs, field.Invalid(attrsPath.Child("slowStartDurationSeconds"), *attrs.SlowStartDurationSeconds, "slow start is only supported by Application Load Balancers"))
+		}
+
+		if attrs.Stickiness != nil {
+			switch {
+			case lb.LoadBalancerType != LoadBalancerTypeALB && lb.LoadBalancerType != LoadBalancerTypeNLB:
+				allErrs = append(allErrs, field.Forbidden(attrsPath.Child("stickiness"), "stickiness is only supported by Application and Network Load Balancers"))
+			case attrs.Stickiness.Type == TargetGroupStickinessTypeLBCookie && lb.LoadBalancerType != LoadBalancerTypeALB:
+				allErrs = append(allErrs, field.Invalid(attrsPath.Child("stickiness", "type"), attrs.Stickiness.Type, "lb_cookie stickiness is only supported by Application Load Balancers"))
+			case attrs.Stickiness.Type == TargetGroupStickinessTypeSourceIP && lb.LoadBalancerType != LoadBalancerTypeNLB:
+				allErrs = append(allErrs, field.Invalid(attrsPath.Child("stickiness", "type"), attrs.Stickiness.Type, "source_ip stickiness is only supported by Network Load Balancers"))
+			}
+		}
+	}
+
 	return allErrs
 }
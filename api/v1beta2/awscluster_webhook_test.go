@@ -483,6 +483,62 @@ func TestAWSClusterValidateCreate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "accepts useDefault with subnets referenced by id",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							UseDefault: true,
+						},
+						Subnets: Subnets{{ID: "subnet-1"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects useDefault and id if set together",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							UseDefault: true,
+							ID:         "vpc-1",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects useDefault and cidrBlock if set together",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							UseDefault: true,
+							CidrBlock:  "10.0.0.0/16",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects useDefault with the cidrBlock of a subnet",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							UseDefault: true,
+						},
+						Subnets: Subnets{{CidrBlock: "10.0.0.0/24"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "accepts CP ingress rules with source security group id and role",
 			cluster: &AWSCluster{
@@ -568,6 +624,189 @@ func TestAWSClusterValidateCreate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "rejects target group attributes on a classic ELB",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						LoadBalancerType: LoadBalancerTypeClassic,
+						TargetGroupAttributes: &TargetGroupAttributesSpec{
+							Stickiness: &TargetGroupStickinessSpec{Enabled: true},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects slow start on a network load balancer",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						LoadBalancerType: LoadBalancerTypeNLB,
+						TargetGroupAttributes: &TargetGroupAttributesSpec{
+							SlowStartDurationSeconds: ptr.To[int64](60),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects lb_cookie stickiness on a network load balancer",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						LoadBalancerType: LoadBalancerTypeNLB,
+						TargetGroupAttributes: &TargetGroupAttributesSpec{
+							Stickiness: &TargetGroupStickinessSpec{Enabled: true, Type: TargetGroupStickinessTypeLBCookie},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allows source_ip stickiness on a network load balancer",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						LoadBalancerType: LoadBalancerTypeNLB,
+						TargetGroupAttributes: &TargetGroupAttributesSpec{
+							Stickiness: &TargetGroupStickinessSpec{Enabled: true, Type: TargetGroupStickinessTypeSourceIP},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allows slow start and stickiness on an application load balancer",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						LoadBalancerType: LoadBalancerTypeALB,
+						TargetGroupAttributes: &TargetGroupAttributesSpec{
+							SlowStartDurationSeconds: ptr.To[int64](120),
+							Stickiness:               &TargetGroupStickinessSpec{Enabled: true},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "allows default lifecycle hooks",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					DefaultLifecycleHooks: []AWSLifecycleHook{
+						{
+							Name:                  "drain",
+							LifecycleTransition:   LifecycleTransitionInstanceTerminate,
+							NotificationTargetARN: ptr.To("arn:aws:sqs:us-east-1:123456789012:drain"),
+							RoleARN:               ptr.To("arn:aws:iam::123456789012:role/drain"),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects default lifecycle hooks outside the partition of the region",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					Region: "us-gov-west-1",
+					DefaultLifecycleHooks: []AWSLifecycleHook{
+						{
+							Name:                  "drain",
+							LifecycleTransition:   LifecycleTransitionInstanceTerminate,
+							NotificationTargetARN: ptr.To("arn:aws:sqs:us-east-1:123456789012:drain"),
+							RoleARN:               ptr.To("arn:aws:iam::123456789012:role/drain"),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allows default lifecycle hooks in a region of an unknown partition",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					Region: "xx-unknown-1",
+					DefaultLifecycleHooks: []AWSLifecycleHook{
+						{
+							Name:                  "drain",
+							LifecycleTransition:   LifecycleTransitionInstanceTerminate,
+							NotificationTargetARN: ptr.To("arn:aws-xx:sqs:xx-unknown-1:123456789012:drain"),
+							RoleARN:               ptr.To("arn:aws-xx:iam::123456789012:role/drain"),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects default lifecycle hooks with the same name",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					DefaultLifecycleHooks: []AWSLifecycleHook{
+						{Name: "drain", LifecycleTransition: LifecycleTransitionInstanceTerminate},
+						{Name: "drain", LifecycleTransition: LifecycleTransitionInstanceLaunch},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a default lifecycle hook with the reserved name prefix",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					DefaultLifecycleHooks: []AWSLifecycleHook{
+						{Name: "capa-drain", LifecycleTransition: LifecycleTransitionInstanceTerminate},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a default lifecycle hook with a notification target but no role",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					DefaultLifecycleHooks: []AWSLifecycleHook{
+						{
+							Name:                  "drain",
+							LifecycleTransition:   LifecycleTransitionInstanceTerminate,
+							NotificationTargetARN: ptr.To("arn:aws:sqs:us-east-1:123456789012:drain"),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allows a quota check with an interval",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					QuotaCheck: &QuotaCheck{
+						Enabled:  true,
+						Interval: &metav1.Duration{Duration: 30 * time.Minute},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects a quota check with a negative interval",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					QuotaCheck: &QuotaCheck{
+						Enabled:  true,
+						Interval: &metav1.Duration{Duration: -time.Minute},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -649,6 +888,37 @@ func TestAWSClusterValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "EventBridge queue name override is immutable once the queue is created",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					EventBridge: &EventBridgeConfig{Enabled: true, QueueNameOverride: "old-queue"},
+				},
+				Status: AWSClusterStatus{
+					EventBridge: &EventBridgeStatus{QueueName: "old-queue", RuleName: "test-ec2-rule"},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					EventBridge: &EventBridgeConfig{Enabled: true, QueueNameOverride: "new-queue"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "EventBridge queue name override can be changed before the queue is created",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					EventBridge: &EventBridgeConfig{Enabled: false, QueueNameOverride: "old-queue"},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					EventBridge: &EventBridgeConfig{Enabled: true, QueueNameOverride: "new-queue"},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "region is immutable",
 			oldCluster: &AWSCluster{
@@ -852,6 +1122,39 @@ func TestAWSClusterValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "useDefault is immutable",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{UseDefault: true},
+					},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "the discovered default VPC can be recorded",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{UseDefault: true},
+					},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC:     VPCSpec{UseDefault: true, ID: "vpc-1", CidrBlock: "172.31.0.0/16"},
+						Subnets: Subnets{{ID: "subnet-1", CidrBlock: "172.31.0.0/20"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalid keys are not accepted during update",
 			oldCluster: &AWSCluster{
@@ -987,6 +1290,170 @@ func TestAWSClusterValidateUpdate(t *testing.T) {
 	}
 }
 
+func TestAWSClusterValidateUpdateControlPlaneLoadBalancerMigration(t *testing.T) {
+	const (
+		classicDNSName = "test-apiserver.us-east-1.elb.amazonaws.com"
+		nlbDNSName     = "default-test-apiserver.elb.us-east-1.amazonaws.com"
+	)
+	cluster := func(lbType LoadBalancerType, host string, migration *LoadBalancerMigration, annotations map[string]string) *AWSCluster {
+		return &AWSCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Annotations: annotations},
+			Spec: AWSClusterSpec{
+				ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{LoadBalancerType: lbType},
+				ControlPlaneEndpoint:     clusterv1.APIEndpoint{Host: host, Port: 6443},
+			},
+			Status: AWSClusterStatus{ControlPlaneLoadBalancerMigration: migration},
+		}
+	}
+	migration := func(phase LoadBalancerMigrationPhase) *LoadBalancerMigration {
+		return &LoadBalancerMigration{Phase: phase, ClassicLoadBalancerDNSName: classicDNSName, LoadBalancerDNSName: nlbDNSName}
+	}
+	acknowledged := map[string]string{MigrateControlPlaneLoadBalancerAnnotation: "true"}
+
+	tests := []struct {
+		name       string
+		oldCluster *AWSCluster
+		newCluster *AWSCluster
+		wantErr    string
+	}{
+		{
+			name:       "allows the migration from classic to nlb with the annotation",
+			oldCluster: cluster(LoadBalancerTypeClassic, classicDNSName, nil, nil),
+			newCluster: cluster(LoadBalancerTypeNLB, classicDNSName, nil, acknowledged),
+		},
+		{
+			name:       "rejects the migration from classic to nlb without the annotation",
+			oldCluster: cluster(LoadBalancerTypeClassic, classicDNSName, nil, nil),
+			newCluster: cluster(LoadBalancerTypeNLB, classicDNSName, nil, nil),
+			wantErr:    MigrateControlPlaneLoadBalancerAnnotation,
+		},
+		{
+			name: "rejects the migration from classic to nlb of a named load balancer",
+			oldCluster: func() *AWSCluster {
+				c := cluster(LoadBalancerTypeClassic, classicDNSName, nil, nil)
+				c.Spec.ControlPlaneLoadBalancer.Name = ptr.To("test-apiserver")
+				return c
+			}(),
+			newCluster: func() *AWSCluster {
+				c := cluster(LoadBalancerTypeNLB, classicDNSName, nil, acknowledged)
+				c.Spec.ControlPlaneLoadBalancer.Name = ptr.To("test-apiserver")
+				return c
+			}(),
+			wantErr: "when its name is set",
+		},
+		{
+			name:       "rejects the migration from classic to alb",
+			oldCluster: cluster(LoadBalancerTypeClassic, classicDNSName, nil, nil),
+			newCluster: cluster(LoadBalancerTypeALB, classicDNSName, nil, acknowledged),
+			wantErr:    "can only be migrated from classic to nlb",
+		},
+		{
+			name:       "rejects the change from nlb to classic outside of a migration",
+			oldCluster: cluster(LoadBalancerTypeNLB, nlbDNSName, nil, nil),
+			newCluster: cluster(LoadBalancerTypeClassic, nlbDNSName, nil, nil),
+			wantErr:    "can only be migrated from classic to nlb",
+		},
+		{
+			name:       "allows reverting a migration before the endpoint is updated",
+			oldCluster: cluster(LoadBalancerTypeNLB, classicDNSName, migration(LoadBalancerMigrationPhaseWaitingForHealthyTargets), acknowledged),
+			newCluster: cluster(LoadBalancerTypeClassic, classicDNSName, nil, acknowledged),
+		},
+		{
+			name:       "rejects reverting a migration once the endpoint is updated",
+			oldCluster: cluster(LoadBalancerTypeNLB, nlbDNSName, migration(LoadBalancerMigrationPhaseDeletingClassicLoadBalancer), acknowledged),
+			newCluster: cluster(LoadBalancerTypeClassic, nlbDNSName, nil, acknowledged),
+			wantErr:    "can only be migrated from classic to nlb",
+		},
+		{
+			name:       "allows switching the endpoint to the network load balancer",
+			oldCluster: cluster(LoadBalancerTypeNLB, classicDNSName, migration(LoadBalancerMigrationPhaseUpdatingEndpoint), acknowledged),
+			newCluster: cluster(LoadBalancerTypeNLB, nlbDNSName, nil, acknowledged),
+		},
+		{
+			name:       "rejects switching the endpoint before the targets are healthy",
+			oldCluster: cluster(LoadBalancerTypeNLB, classicDNSName, migration(LoadBalancerMigrationPhaseWaitingForHealthyTargets), acknowledged),
+			newCluster: cluster(LoadBalancerTypeNLB, nlbDNSName, nil, acknowledged),
+			wantErr:    "spec.controlPlaneEndpoint",
+		},
+		{
+			name:       "rejects switching the endpoint to another host",
+			oldCluster: cluster(LoadBalancerTypeNLB, classicDNSName, migration(LoadBalancerMigrationPhaseUpdatingEndpoint), acknowledged),
+			newCluster: cluster(LoadBalancerTypeNLB, "example.com", nil, acknowledged),
+			wantErr:    "spec.controlPlaneEndpoint",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := tt.newCluster.ValidateUpdate(tt.oldCluster)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestAWSClusterValidateUpdateInstanceConnectEndpoint(t *testing.T) {
+	cluster := func(endpoint *InstanceConnectEndpoint, status *InstanceConnectEndpointStatus) *AWSCluster {
+		return &AWSCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec:       AWSClusterSpec{InstanceConnectEndpoint: endpoint},
+			Status:     AWSClusterStatus{InstanceConnectEndpoint: status},
+		}
+	}
+	created := &InstanceConnectEndpointStatus{ID: "eice-1"}
+
+	tests := []struct {
+		name       string
+		oldCluster *AWSCluster
+		newCluster *AWSCluster
+		wantErr    string
+	}{
+		{
+			name:       "security groups are immutable once the endpoint is created",
+			oldCluster: cluster(&InstanceConnectEndpoint{Enabled: true, SecurityGroups: []string{"sg-1"}}, created),
+			newCluster: cluster(&InstanceConnectEndpoint{Enabled: true, SecurityGroups: []string{"sg-2"}}, created),
+			wantErr:    "spec.instanceConnectEndpoint.securityGroups",
+		},
+		{
+			name:       "security groups can be changed before the endpoint is created",
+			oldCluster: cluster(&InstanceConnectEndpoint{Enabled: true, SecurityGroups: []string{"sg-1"}}, nil),
+			newCluster: cluster(&InstanceConnectEndpoint{Enabled: true, SecurityGroups: []string{"sg-2"}}, nil),
+		},
+		{
+			name:       "port can be changed once the endpoint is created",
+			oldCluster: cluster(&InstanceConnectEndpoint{Enabled: true}, created),
+			newCluster: cluster(&InstanceConnectEndpoint{Enabled: true, Port: aws.Int64(2222)}, created),
+		},
+		{
+			name:       "subnet can be changed along with disabling the endpoint",
+			oldCluster: cluster(&InstanceConnectEndpoint{Enabled: true}, created),
+			newCluster: cluster(&InstanceConnectEndpoint{SubnetSelection: &InstanceConnectEndpointSubnetSelection{AvailabilityZone: "us-east-1a"}}, created),
+		},
+		{
+			name:       "subnet can only be selected by ID or availability zone",
+			oldCluster: cluster(nil, nil),
+			newCluster: cluster(&InstanceConnectEndpoint{Enabled: true, SubnetSelection: &InstanceConnectEndpointSubnetSelection{ID: "subnet-1", AvailabilityZone: "us-east-1a"}}, nil),
+			wantErr:    "only one of id and availabilityZone can be set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := tt.newCluster.ValidateUpdate(tt.oldCluster)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
 func TestAWSClusterDefaultCNIIngressRules(t *testing.T) {
 	AZUsageLimit := 3
 	defaultVPCSpec := VPCSpec{
@@ -31,6 +31,14 @@ type AWSClusterIdentitySpec struct {
 	// +optional
 	// +nullable
 	AllowedNamespaces *AllowedNamespaces `json:"allowedNamespaces"`
+
+	// CABundleSecretRef is the name of a secret in the namespace of the controller holding a PEM encoded
+	// bundle of CA certificates under the ca.crt key. The certificates of the AWS API endpoints are verified
+	// against it in addition to the system CAs, e.g. when the AWS API traffic goes through a TLS-intercepting
+	// proxy. It takes precedence over the CA bundle of the controller. Changes to the secret are picked up
+	// on the next reconciliation.
+	// +optional
+	CABundleSecretRef string `json:"caBundleSecretRef,omitempty"`
 }
 
 // AllowedNamespaces is a selector of namespaces that AWSClusters can
@@ -54,6 +54,27 @@ const (
 	IgnitionStorageTypeOptionUnencryptedUserData = IgnitionStorageTypeOption("UnencryptedUserData")
 )
 
+// UserDataDelivery is the mechanism through which an instance receives its bootstrap data.
+type UserDataDelivery string
+
+const (
+	// UserDataDeliverySecretsManager means the user data of the instance fetches the bootstrap data from
+	// AWS Secrets Manager.
+	UserDataDeliverySecretsManager = UserDataDelivery("SecretsManager")
+
+	// UserDataDeliverySSMParameterStore means the user data of the instance fetches the bootstrap data from
+	// AWS Systems Manager Parameter Store.
+	UserDataDeliverySSMParameterStore = UserDataDelivery("SSMParameterStore")
+
+	// UserDataDeliveryS3 means the user data of the instance fetches the bootstrap data from the S3 bucket
+	// of the cluster.
+	UserDataDeliveryS3 = UserDataDelivery("S3")
+
+	// UserDataDeliveryInline means the bootstrap data, including its secrets, is stored in the user data of
+	// the instance.
+	UserDataDeliveryInline = UserDataDelivery("Inline")
+)
+
 // AWSMachineSpec defines the desired state of an Amazon EC2 instance.
 type AWSMachineSpec struct {
 	// ProviderID is the unique identifier as specified by the cloud provider.
@@ -180,6 +201,21 @@ type AWSMachineSpec struct {
 	// PrivateDNSName is the options for the instance hostname.
 	// +optional
 	PrivateDNSName *PrivateDNSName `json:"privateDnsName,omitempty"`
+
+	// OS is the operating system of the node running on the instance. When set to windows, the
+	// bootstrap data is framed as a PowerShell script, the AMI must be a Windows AMI and the
+	// security groups of the cluster allow the traffic used by the Windows CNI.
+	// Ignition is not supported on Windows, and the bootstrap data must not be stored in
+	// AWS Secrets Manager.
+	// When unset, the operating system is assumed to be linux and the AMI platform isn't validated.
+	// +optional
+	OS OSType `json:"os,omitempty"`
+
+	// UserDataTransform references a Go template which renders the user data of the instance from the
+	// bootstrap data, in place of the user data generated by CAPA. AWS Secrets Manager can't be used
+	// together with a transform. Requires the UserDataTransform feature gate.
+	// +optional
+	UserDataTransform *UserDataTransform `json:"userDataTransform,omitempty"`
 }
 
 // CloudInit defines options related to the bootstrapping systems where
@@ -359,6 +395,27 @@ type AWSMachineStatus struct {
 	// Conditions defines current service state of the AWSMachine.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// BootstrapDataRenderedAt is the time the bootstrap data of the instance was first rendered.
+	// +optional
+	BootstrapDataRenderedAt *metav1.Time `json:"bootstrapDataRenderedAt,omitempty"`
+
+	// InstanceRequestedAt is the time the instance was requested from EC2.
+	// +optional
+	InstanceRequestedAt *metav1.Time `json:"instanceRequestedAt,omitempty"`
+
+	// InstanceRunningAt is the time the instance was first seen running.
+	// +optional
+	InstanceRunningAt *metav1.Time `json:"instanceRunningAt,omitempty"`
+
+	// AddressesAssignedAt is the time the addresses of the instance were first set.
+	// +optional
+	AddressesAssignedAt *metav1.Time `json:"addressesAssignedAt,omitempty"`
+
+	// UserDataDelivery is the mechanism through which the instance received its bootstrap data.
+	// +kubebuilder:validation:Enum=SecretsManager;SSMParameterStore;S3;Inline
+	// +optional
+	UserDataDelivery UserDataDelivery `json:"userDataDelivery,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -63,6 +63,8 @@ func (r *AWSMachine) ValidateCreate() (admission.Warnings, error) {
 	allErrs = append(allErrs, r.validateNonRootVolumes()...)
 	allErrs = append(allErrs, r.validateSSHKeyName()...)
 	allErrs = append(allErrs, r.validateAdditionalSecurityGroups()...)
+	allErrs = append(allErrs, validateOS(r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateUserDataTransform(r.Spec, field.NewPath("spec"))...)
 	allErrs = append(allErrs, r.Spec.AdditionalTags.Validate()...)
 
 	return nil, aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
@@ -161,6 +163,43 @@ func (r *AWSMachine) validateCloudInitSecret() field.ErrorList {
 	return allErrs
 }
 
+// validateOS checks that the bootstrap options of a machine spec are supported by its operating system.
+func validateOS(spec AWSMachineSpec, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.OS != OSTypeWindows {
+		return allErrs
+	}
+
+	if spec.Ignition != nil {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("ignition"), "is not supported if os is windows"))
+	}
+	// The bootstrap data stored in AWS Secrets Manager is fetched by a cloud-init boothook.
+	if !spec.CloudInit.InsecureSkipSecretsManager {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("cloudInit", "insecureSkipSecretsManager"), spec.CloudInit.InsecureSkipSecretsManager, "must be true if os is windows"))
+	}
+	if spec.UncompressedUserData != nil && !*spec.UncompressedUserData {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("uncompressedUserData"), *spec.UncompressedUserData, "compressed user data is not supported if os is windows"))
+	}
+
+	return allErrs
+}
+
+// validateUserDataTransform checks the user data transform of a machine spec. The user data rendered by the
+// transform replaces the cloud-init boothook which fetches the bootstrap data from AWS Secrets Manager.
+func validateUserDataTransform(spec AWSMachineSpec, specPath *field.Path) field.ErrorList {
+	if spec.UserDataTransform == nil {
+		return nil
+	}
+
+	allErrs := spec.UserDataTransform.Validate(specPath.Child("userDataTransform"))
+	if spec.Ignition == nil && !spec.CloudInit.InsecureSkipSecretsManager {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("cloudInit", "insecureSkipSecretsManager"), spec.CloudInit.InsecureSkipSecretsManager, "must be true if userDataTransform is set"))
+	}
+
+	return allErrs
+}
+
 func (r *AWSMachine) cloudInitConfigured() bool {
 	configured := false
 
@@ -23,7 +23,9 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/ptr"
 
@@ -353,6 +355,57 @@ func TestAWSMachineCreate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "windows machine with secrets manager skipped is accepted",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType: "test",
+					OS:           OSTypeWindows,
+					CloudInit: CloudInit{
+						InsecureSkipSecretsManager: true,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "windows machine must skip secrets manager",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType: "test",
+					OS:           OSTypeWindows,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "windows machine cannot use ignition",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType: "test",
+					OS:           OSTypeWindows,
+					Ignition: &Ignition{
+						Version:     "3.4",
+						StorageType: IgnitionStorageTypeOptionUnencryptedUserData,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "windows machine cannot compress user data",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType: "test",
+					OS:           OSTypeWindows,
+					CloudInit: CloudInit{
+						InsecureSkipSecretsManager: true,
+					},
+					UncompressedUserData: ptr.To(false),
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "cannot use ignition proxy with version 2.3",
 			machine: &AWSMachine{
@@ -387,6 +440,78 @@ func TestAWSMachineCreate(t *testing.T) {
 	}
 }
 
+func TestValidateUserDataTransform(t *testing.T) {
+	transform := &UserDataTransform{ConfigMapRef: corev1.LocalObjectReference{Name: "user-data"}}
+
+	tests := []struct {
+		name        string
+		spec        AWSMachineSpec
+		gateEnabled bool
+		wantErrs    []string
+	}{
+		{
+			name:        "no transform",
+			spec:        AWSMachineSpec{},
+			gateEnabled: false,
+		},
+		{
+			name: "transform with secrets manager skipped is accepted",
+			spec: AWSMachineSpec{
+				UserDataTransform: transform,
+				CloudInit:         CloudInit{InsecureSkipSecretsManager: true},
+			},
+			gateEnabled: true,
+		},
+		{
+			name: "transform with ignition is accepted",
+			spec: AWSMachineSpec{
+				UserDataTransform: transform,
+				Ignition:          &Ignition{},
+			},
+			gateEnabled: true,
+		},
+		{
+			name: "transform requires the feature gate",
+			spec: AWSMachineSpec{
+				UserDataTransform: transform,
+				CloudInit:         CloudInit{InsecureSkipSecretsManager: true},
+			},
+			gateEnabled: false,
+			wantErrs:    []string{"spec.userDataTransform"},
+		},
+		{
+			name: "transform can't be combined with secrets manager",
+			spec: AWSMachineSpec{
+				UserDataTransform: transform,
+			},
+			gateEnabled: true,
+			wantErrs:    []string{"spec.cloudInit.insecureSkipSecretsManager"},
+		},
+		{
+			name: "transform requires a ConfigMap name and a valid key",
+			spec: AWSMachineSpec{
+				UserDataTransform: &UserDataTransform{TemplateKey: "user data"},
+				CloudInit:         CloudInit{InsecureSkipSecretsManager: true},
+			},
+			gateEnabled: true,
+			wantErrs:    []string{"spec.userDataTransform.configMapRef.name", "spec.userDataTransform.templateKey"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.UserDataTransform, tt.gateEnabled)()
+			g := NewWithT(t)
+
+			errs := validateUserDataTransform(tt.spec, field.NewPath("spec"))
+			fields := []string{}
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			g.Expect(fields).To(ConsistOf(tt.wantErrs))
+		})
+	}
+}
+
 func TestAWSMachineUpdate(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -203,6 +203,8 @@ func (r *AWSMachineTemplateWebhook) ValidateCreate(_ context.Context, raw runtim
 	allErrs = append(allErrs, obj.validateNonRootVolumes()...)
 	allErrs = append(allErrs, obj.validateSSHKeyName()...)
 	allErrs = append(allErrs, obj.validateAdditionalSecurityGroups()...)
+	allErrs = append(allErrs, validateOS(obj.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateUserDataTransform(obj.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
 	allErrs = append(allErrs, obj.Spec.Template.Spec.AdditionalTags.Validate()...)
 
 	return nil, aggregateObjErrors(obj.GroupVersionKind().GroupKind(), obj.Name, allErrs)
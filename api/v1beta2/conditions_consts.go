@@ -27,6 +27,9 @@ const (
 	// CredentialProviderBuildFailedReason used when errors occur during building providers before trying credential retrieval.
 	//nolint:gosec
 	CredentialProviderBuildFailedReason = "CredentialProviderBuildFailed"
+	// IdentityTLSConfigurationInvalidReason used when the CA bundle of the identity is invalid, or the certificate
+	// of an AWS API endpoint can't be verified with it.
+	IdentityTLSConfigurationInvalidReason = "IdentityTLSConfigurationInvalid"
 	// PrincipalUsageAllowedCondition reports on whether Principal and all the nested source identities are allowed to be used in the AWSCluster namespace.
 	PrincipalUsageAllowedCondition clusterv1.ConditionType = "PrincipalUsageAllowed"
 	// PrincipalUsageUnauthorizedReason used when AWSCluster namespace is not in the identity's allowed namespaces list.
@@ -77,6 +80,12 @@ const (
 	NatGatewaysCreationStartedReason = "NatGatewaysCreationStarted"
 	// NatGatewaysReconciliationFailedReason used when any errors occur during reconciliation of NAT gateways.
 	NatGatewaysReconciliationFailedReason = "NatGatewaysReconciliationFailed"
+
+	// NatGatewaysHealthyCondition reports whether the NAT gateways of the private subnets are available.
+	// It is only set when the health check of the NAT gateways is enabled.
+	NatGatewaysHealthyCondition clusterv1.ConditionType = "NatGatewaysHealthy"
+	// NatGatewaysDegradedReason used when NAT gateways of the private subnets are no longer available.
+	NatGatewaysDegradedReason = "NatGatewaysDegraded"
 )
 
 const (
@@ -95,6 +104,18 @@ const (
 	VpcEndpointsReconciliationFailedReason = "VpcEndpointsReconciliationFailed"
 )
 
+const (
+	// InstanceConnectEndpointReadyCondition reports whether the EC2 Instance Connect Endpoint of the cluster is
+	// created. It is only set when the endpoint is enabled.
+	InstanceConnectEndpointReadyCondition clusterv1.ConditionType = "InstanceConnectEndpointReady"
+	// InstanceConnectEndpointCreatingReason used while the EC2 Instance Connect Endpoint is being created.
+	InstanceConnectEndpointCreatingReason = "InstanceConnectEndpointCreating"
+	// InstanceConnectEndpointDeletingReason used while the EC2 Instance Connect Endpoint is being deleted.
+	InstanceConnectEndpointDeletingReason = "InstanceConnectEndpointDeleting"
+	// InstanceConnectEndpointFailedReason used when the EC2 Instance Connect Endpoint can't be created.
+	InstanceConnectEndpointFailedReason = "InstanceConnectEndpointFailed"
+)
+
 const (
 	// SecondaryCidrsReadyCondition reports successful reconciliation of secondary CIDR blocks.
 	// Only applicable to managed clusters.
@@ -108,6 +129,8 @@ const (
 	ClusterSecurityGroupsReadyCondition clusterv1.ConditionType = "ClusterSecurityGroupsReady"
 	// ClusterSecurityGroupReconciliationFailedReason used when any errors occur during reconciliation of security groups.
 	ClusterSecurityGroupReconciliationFailedReason = "SecurityGroupReconciliationFailed"
+	// SecurityGroupRuleLimitExceededReason used when a security group has reached the maximum number of rules per security group.
+	SecurityGroupRuleLimitExceededReason = "SecurityGroupRuleLimitExceeded"
 )
 
 const (
@@ -134,6 +157,35 @@ const (
 	LoadBalancerFailedReason = "LoadBalancerFailed"
 )
 
+const (
+	// LoadBalancerMigrationProvisionedCondition reports on whether the Network Load Balancer replacing the classic
+	// control plane load balancer was created, and the control plane instances registered with it.
+	LoadBalancerMigrationProvisionedCondition clusterv1.ConditionType = "LoadBalancerMigrationProvisioned"
+	// LoadBalancerMigrationTargetsHealthyCondition reports on whether the control plane instances are healthy
+	// targets of the Network Load Balancer replacing the classic control plane load balancer.
+	LoadBalancerMigrationTargetsHealthyCondition clusterv1.ConditionType = "LoadBalancerMigrationTargetsHealthy"
+	// LoadBalancerMigrationEndpointUpdatedCondition reports on whether the status and the control plane endpoint
+	// of the AWSCluster were switched to the Network Load Balancer.
+	LoadBalancerMigrationEndpointUpdatedCondition clusterv1.ConditionType = "LoadBalancerMigrationEndpointUpdated"
+	// LoadBalancerMigrationClassicLoadBalancerDeletedCondition reports on whether the classic control plane load
+	// balancer was deleted once replaced.
+	LoadBalancerMigrationClassicLoadBalancerDeletedCondition clusterv1.ConditionType = "LoadBalancerMigrationClassicLoadBalancerDeleted"
+	// LoadBalancerMigrationFailedReason used when an error occurs during a phase of the migration.
+	LoadBalancerMigrationFailedReason = "LoadBalancerMigrationFailed"
+	// WaitingForHealthyTargetsReason used while the control plane instances aren't healthy targets yet.
+	WaitingForHealthyTargetsReason = "WaitingForHealthyTargets"
+	// WaitingForEndpointUpdateReason used while the control plane endpoint isn't switched yet.
+	WaitingForEndpointUpdateReason = "WaitingForEndpointUpdate"
+	// WaitingForDeletionConfirmationReason used while the deletion of the classic control plane load balancer
+	// isn't confirmed yet.
+	WaitingForDeletionConfirmationReason = "WaitingForDeletionConfirmation"
+	// DeletingClassicLoadBalancerReason used while the classic control plane load balancer is being deleted.
+	DeletingClassicLoadBalancerReason = "DeletingClassicLoadBalancer"
+	// LoadBalancerMigrationAbortedReason used when the migration was given up because the Network Load Balancer
+	// didn't become healthy in time.
+	LoadBalancerMigrationAbortedReason = "LoadBalancerMigrationAborted"
+)
+
 const (
 	// InstanceReadyCondition reports on current status of the EC2 instance. Ready indicates the instance is in a Running state.
 	InstanceReadyCondition clusterv1.ConditionType = "InstanceReady"
@@ -156,6 +208,17 @@ const (
 	WaitingForBootstrapDataReason = "WaitingForBootstrapData"
 )
 
+const (
+	// SSHKeyAvailableCondition reports whether the SSH key pair of an AWSCluster or an AWSMachine exists in the
+	// region of the cluster. It is only set when an SSH key name is set on the AWSCluster or the AWSMachine.
+	SSHKeyAvailableCondition clusterv1.ConditionType = "SSHKeyAvailable"
+
+	// SSHKeyNotFoundReason used when the SSH key pair doesn't exist in the region of the cluster.
+	SSHKeyNotFoundReason = "SSHKeyNotFound"
+	// SSHKeyCheckFailedReason used when the SSH key pair couldn't be described.
+	SSHKeyCheckFailedReason = "SSHKeyCheckFailed"
+)
+
 const (
 	// SecurityGroupsReadyCondition indicates the security groups are up to date on the AWSMachine.
 	SecurityGroupsReadyCondition clusterv1.ConditionType = "SecurityGroupsReady"
@@ -177,6 +240,53 @@ const (
 	ELBDetachFailedReason = "ELBDetachFailed"
 )
 
+const (
+	// UserDataDriftedCondition reports whether the user data of the EC2 instance differs from the user data
+	// it was launched with. It is only set when the UserDataDriftDetection feature gate is enabled.
+	// Note that this condition is true when the user data has drifted.
+	UserDataDriftedCondition clusterv1.ConditionType = "UserDataDrifted"
+
+	// UserDataHashMismatchReason used when the hash of the instance user data doesn't match the hash it was launched with.
+	UserDataHashMismatchReason = "UserDataHashMismatch"
+	// UserDataHashMatchReason used when the hash of the instance user data matches the hash it was launched with.
+	UserDataHashMatchReason = "UserDataHashMatch"
+)
+
+const (
+	// UserDataMigratedCondition reports whether all the AWSMachines of a cluster with an S3 bucket stopped storing
+	// their bootstrap data in the instance user data. It is only set when the cluster has an S3 bucket.
+	UserDataMigratedCondition clusterv1.ConditionType = "UserDataMigrated"
+
+	// UserDataMigrationPendingReason used when some AWSMachines still store their bootstrap data in the instance user data.
+	UserDataMigrationPendingReason = "UserDataMigrationPending"
+	// UserDataMigrationCheckFailedReason used when the AWSMachines of the cluster couldn't be listed.
+	UserDataMigrationCheckFailedReason = "UserDataMigrationCheckFailed"
+)
+
+const (
+	// UserDataTransformReadyCondition reports whether the user data transform of a machine or a launch template
+	// was rendered successfully. It is only set when a transform is referenced.
+	UserDataTransformReadyCondition clusterv1.ConditionType = "UserDataTransformReady"
+
+	// UserDataTransformFailedReason used when the template of a user data transform can't be retrieved, parsed or
+	// rendered, or renders user data which is too large.
+	UserDataTransformFailedReason = "UserDataTransformFailed"
+)
+
+const (
+	// BootstrapTokenLikelyExpiredCondition reports whether the bootstrap token embedded in the bootstrap data of a
+	// machine or a machine pool expired before its instances joined the cluster. It is only set when the expiry of the
+	// token is known, from the BootstrapTokenExpiresAtAnnotation of the bootstrap data secret or from the kubeadm
+	// bootstrap token in the workload cluster.
+	// Note that this condition is true when the bootstrap token has likely expired.
+	BootstrapTokenLikelyExpiredCondition clusterv1.ConditionType = "BootstrapTokenLikelyExpired"
+
+	// BootstrapTokenExpiredReason used when the bootstrap token expired while instances haven't joined the cluster.
+	BootstrapTokenExpiredReason = "BootstrapTokenExpired"
+	// BootstrapTokenValidReason used when the bootstrap token hasn't expired yet.
+	BootstrapTokenValidReason = "BootstrapTokenValid"
+)
+
 const (
 	// S3BucketReadyCondition indicates an S3 bucket has been created successfully.
 	S3BucketReadyCondition clusterv1.ConditionType = "S3BucketCreated"
@@ -184,3 +294,13 @@ const (
 	// S3BucketFailedReason is used when any errors occur during reconciliation of an S3 bucket.
 	S3BucketFailedReason = "S3BucketCreationFailed"
 )
+
+const (
+	// AWSRequestsSucceededCondition reports on the backoff of the reconciliation of an AWSCluster, AWSMachine or
+	// AWSMachinePool after it failed. It is only set while the reconciliation is failing, and removed once it succeeds.
+	AWSRequestsSucceededCondition clusterv1.ConditionType = "AWSRequestsSucceeded"
+
+	// BackingOffReason used when the reconciliation failed and is retried after a delay growing with the consecutive
+	// failures.
+	BackingOffReason = "BackingOff"
+)
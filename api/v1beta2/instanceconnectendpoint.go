@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// DefaultInstanceConnectEndpointPort is the port of the instances the EC2 Instance Connect Endpoint connects to
+// when InstanceConnectEndpoint.Port is not set.
+const DefaultInstanceConnectEndpointPort = 22
+
+// IsEnabled returns whether the EC2 Instance Connect Endpoint is enabled.
+func (e *InstanceConnectEndpoint) IsEnabled() bool {
+	return e != nil && e.Enabled
+}
+
+// GetPort returns the port of the instances the endpoint connects to.
+func (e *InstanceConnectEndpoint) GetPort() int64 {
+	if e == nil || e.Port == nil {
+		return DefaultInstanceConnectEndpointPort
+	}
+	return *e.Port
+}
+
+// Validate validates the EC2 Instance Connect Endpoint configuration.
+func (e *InstanceConnectEndpoint) Validate() field.ErrorList {
+	var errs field.ErrorList
+
+	if e == nil {
+		return errs
+	}
+
+	if e.SubnetSelection != nil && e.SubnetSelection.ID != "" && e.SubnetSelection.AvailabilityZone != "" {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "instanceConnectEndpoint", "subnetSelection"), "only one of id and availabilityZone can be set"))
+	}
+
+	return errs
+}
+
+// ValidateUpdate validates the changes to the EC2 Instance Connect Endpoint configuration. An endpoint can't be
+// modified once created, so its subnet, security groups and client IP preservation can't be changed while it
+// stays enabled.
+func (e *InstanceConnectEndpoint) ValidateUpdate(old *InstanceConnectEndpoint, status *InstanceConnectEndpointStatus) field.ErrorList {
+	var errs field.ErrorList
+
+	if status == nil || !old.IsEnabled() || !e.IsEnabled() {
+		return errs
+	}
+
+	path := field.NewPath("spec", "instanceConnectEndpoint")
+	if !equality.Semantic.DeepEqual(e.SubnetSelection, old.SubnetSelection) {
+		errs = append(errs, field.Invalid(path.Child("subnetSelection"), e.SubnetSelection, "field cannot be modified once the endpoint is created, disable the endpoint first"))
+	}
+	if !equality.Semantic.DeepEqual(e.SecurityGroups, old.SecurityGroups) {
+		errs = append(errs, field.Invalid(path.Child("securityGroups"), e.SecurityGroups, "field cannot be modified once the endpoint is created, disable the endpoint first"))
+	}
+	if e.PreserveClientIP != old.PreserveClientIP {
+		errs = append(errs, field.Invalid(path.Child("preserveClientIP"), e.PreserveClientIP, "field cannot be modified once the endpoint is created, disable the endpoint first"))
+	}
+
+	return errs
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// LifecycleTransition is the state of the EC2 instance to which to attach the lifecycle hook.
+type LifecycleTransition string
+
+const (
+	// LifecycleTransitionInstanceLaunch is the launching state of the EC2 instance.
+	LifecycleTransitionInstanceLaunch LifecycleTransition = "autoscaling:EC2_INSTANCE_LAUNCHING"
+	// LifecycleTransitionInstanceTerminate is the terminating state of the EC2 instance.
+	LifecycleTransitionInstanceTerminate LifecycleTransition = "autoscaling:EC2_INSTANCE_TERMINATING"
+)
+
+func (l LifecycleTransition) String() string {
+	return string(l)
+}
+
+// LifecycleHookDefaultResult is the default result for the lifecycle hook.
+type LifecycleHookDefaultResult string
+
+const (
+	// LifecycleHookDefaultResultContinue is the default result for the lifecycle hook to continue.
+	LifecycleHookDefaultResultContinue LifecycleHookDefaultResult = "CONTINUE"
+	// LifecycleHookDefaultResultAbandon is the default result for the lifecycle hook to abandon.
+	LifecycleHookDefaultResultAbandon LifecycleHookDefaultResult = "ABANDON"
+)
+
+func (d LifecycleHookDefaultResult) String() string {
+	return string(d)
+}
+
+// ReservedLifecycleHookNamePrefix is the prefix of the names of the lifecycle hooks managed by CAPA.
+// User-defined lifecycle hooks can't use it, so that they never collide with the managed ones.
+const ReservedLifecycleHookNamePrefix = "capa-"
+
+// LifecycleHookSyncMode defines how CAPA keeps an existing lifecycle hook in sync with its spec.
+type LifecycleHookSyncMode string
+
+const (
+	// LifecycleHookSyncModeFull creates the lifecycle hook when it is missing and updates it
+	// whenever it drifts from the spec.
+	LifecycleHookSyncModeFull LifecycleHookSyncMode = "Full"
+	// LifecycleHookSyncModeCreateOnly creates the lifecycle hook when it is missing but never
+	// updates it afterwards, so that its settings can be tuned outside of CAPA.
+	LifecycleHookSyncModeCreateOnly LifecycleHookSyncMode = "CreateOnly"
+)
+
+// AWSLifecycleHook describes an AWS lifecycle hook.
+type AWSLifecycleHook struct {
+	// The name of the lifecycle hook. Names starting with capa- are reserved for the lifecycle
+	// hooks managed by CAPA.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	Name string `json:"name"`
+
+	// The ARN of the notification target that Amazon EC2 Auto Scaling uses to
+	// notify you when an instance is in the transition state for the lifecycle hook.
+	// +optional
+	NotificationTargetARN *string `json:"notificationTargetARN,omitempty"`
+
+	// The ARN of the IAM role that allows the Auto Scaling group to publish to the
+	// specified notification target.
+	// +optional
+	RoleARN *string `json:"roleARN,omitempty"`
+
+	// The state of the EC2 instance to which to attach the lifecycle hook.
+	// +kubebuilder:validation:Enum="autoscaling:EC2_INSTANCE_LAUNCHING";"autoscaling:EC2_INSTANCE_TERMINATING"
+	LifecycleTransition LifecycleTransition `json:"lifecycleTransition"`
+
+	// The maximum time, in seconds, that an instance can remain in a Pending:Wait or
+	// Terminating:Wait state. The maximum is 172800 seconds (48 hours) or 100 times
+	// HeartbeatTimeout, whichever is smaller.
+	// +optional
+	// +kubebuilder:validation:Format=duration
+	HeartbeatTimeout *metav1.Duration `json:"heartbeatTimeout,omitempty"`
+
+	// The default result for the lifecycle hook. The possible values are CONTINUE and ABANDON.
+	// +optional
+	// +kubebuilder:validation:Enum=CONTINUE;ABANDON
+	DefaultResult *LifecycleHookDefaultResult `json:"defaultResult,omitempty"`
+
+	// Contains additional metadata that you want to include in the notification.
+	// +optional
+	NotificationMetadata *string `json:"notificationMetadata,omitempty"`
+
+	// SyncMode defines how an existing lifecycle hook is kept in sync with this spec.
+	// With Full (the default), the hook is updated whenever it drifts from the spec.
+	// With CreateOnly, the hook is only created when it is missing and is never updated
+	// afterwards, allowing an external system to tune it at runtime. In both modes the
+	// hook is deleted when it is removed from the spec.
+	// +optional
+	// +kubebuilder:validation:Enum=Full;CreateOnly
+	SyncMode *LifecycleHookSyncMode `json:"syncMode,omitempty"`
+
+	// Priority defines the order in which the lifecycle hooks are reconciled. Hooks with a lower
+	// priority are created first, e.g. to create a launch hook before a termination hook, and hooks
+	// with equal priorities keep the order of the spec. Negative values are allowed.
+	// Defaults to 0.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+}
+
+// IsCreateOnly returns true if the lifecycle hook must not be updated after it has been created.
+func (h *AWSLifecycleHook) IsCreateOnly() bool {
+	return h.SyncMode != nil && *h.SyncMode == LifecycleHookSyncModeCreateOnly
+}
+
+// ValidateLifecycleHooks validates a list of lifecycle hooks found at fldPath, for a cluster in the
+// given region. The region may be empty when it is not known.
+func ValidateLifecycleHooks(fldPath *field.Path, hooks []AWSLifecycleHook, region string) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := make(map[string]struct{}, len(hooks))
+	for i, hook := range hooks {
+		hookPath := fldPath.Index(i)
+
+		if _, ok := names[hook.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(hookPath.Child("name"), hook.Name))
+		}
+		names[hook.Name] = struct{}{}
+
+		if strings.HasPrefix(hook.Name, ReservedLifecycleHookNamePrefix) {
+			allErrs = append(allErrs, field.Invalid(hookPath.Child("name"), hook.Name,
+				fmt.Sprintf("names starting with %q are reserved for the lifecycle hooks managed by CAPA", ReservedLifecycleHookNamePrefix)))
+		}
+
+		if hook.LifecycleTransition != LifecycleTransitionInstanceLaunch && hook.LifecycleTransition != LifecycleTransitionInstanceTerminate {
+			allErrs = append(allErrs, field.NotSupported(hookPath.Child("lifecycleTransition"), hook.LifecycleTransition, []string{LifecycleTransitionInstanceLaunch.String(), LifecycleTransitionInstanceTerminate.String()}))
+		}
+
+		if hook.HeartbeatTimeout != nil {
+			if hook.HeartbeatTimeout.Duration < 30*time.Second || hook.HeartbeatTimeout.Duration > 7200*time.Second {
+				allErrs = append(allErrs, field.Invalid(hookPath.Child("heartbeatTimeout"), hook.HeartbeatTimeout.Duration.String(), "heartbeatTimeout must be between 30 seconds and 7200 seconds"))
+			}
+		}
+
+		if (hook.NotificationTargetARN == nil) != (hook.RoleARN == nil) {
+			allErrs = append(allErrs, field.Forbidden(hookPath, "notificationTargetARN and roleARN must be provided together"))
+		}
+
+		allErrs = append(allErrs, ValidateLifecycleHookARNs(hookPath, hook, region)...)
+	}
+
+	return allErrs
+}
+
+// ValidateLifecycleHookARNs checks that the notification target and role of a lifecycle hook are
+// ARNs of the expected services, in the same partition. When the partition of the region of the
+// cluster is known, both ARNs must also be in it. The region inside the notification target ARN
+// is not used, as the partition is a property of the cluster rather than of the target.
+func ValidateLifecycleHookARNs(hookPath *field.Path, hook AWSLifecycleHook, region string) field.ErrorList {
+	var allErrs field.ErrorList
+
+	clusterPartition := ""
+	if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		clusterPartition = p.ID()
+	}
+
+	targetPartition := ""
+	if hook.NotificationTargetARN != nil {
+		targetPath := hookPath.Child("notificationTargetARN")
+		targetARN, err := arn.Parse(*hook.NotificationTargetARN)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(targetPath, *hook.NotificationTargetARN, "must be a valid ARN"))
+		case targetARN.Service != "sqs" && targetARN.Service != "sns":
+			allErrs = append(allErrs, field.Invalid(targetPath, *hook.NotificationTargetARN, "must be the ARN of an SQS queue or an SNS topic"))
+		case clusterPartition != "" && targetARN.Partition != clusterPartition:
+			allErrs = append(allErrs, field.Invalid(targetPath, *hook.NotificationTargetARN, fmt.Sprintf("partition %q does not match partition %q of region %q", targetARN.Partition, clusterPartition, region)))
+		default:
+			targetPartition = targetARN.Partition
+		}
+	}
+
+	if hook.RoleARN != nil {
+		rolePath := hookPath.Child("roleARN")
+		roleARN, err := arn.Parse(*hook.RoleARN)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(rolePath, *hook.RoleARN, "must be a valid ARN"))
+		case roleARN.Service != "iam":
+			allErrs = append(allErrs, field.Invalid(rolePath, *hook.RoleARN, "must be the ARN of an IAM role"))
+		case clusterPartition != "" && roleARN.Partition != clusterPartition:
+			allErrs = append(allErrs, field.Invalid(rolePath, *hook.RoleARN, fmt.Sprintf("partition %q does not match partition %q of region %q", roleARN.Partition, clusterPartition, region)))
+		case targetPartition != "" && roleARN.Partition != targetPartition:
+			allErrs = append(allErrs, field.Invalid(rolePath, *hook.RoleARN, fmt.Sprintf("partition %q does not match partition %q of the notification target", roleARN.Partition, targetPartition)))
+		}
+	}
+
+	return allErrs
+}
@@ -108,6 +108,12 @@ type TargetGroupAttribute string
 var (
 	// TargetGroupAttributeEnablePreserveClientIP defines the attribute key for enabling preserve client IP.
 	TargetGroupAttributeEnablePreserveClientIP = "preserve_client_ip.enabled"
+	// TargetGroupAttributeSlowStartDurationSeconds defines the attribute key for the slow start duration.
+	TargetGroupAttributeSlowStartDurationSeconds = "slow_start.duration_seconds"
+	// TargetGroupAttributeStickinessEnabled defines the attribute key for enabling sticky sessions.
+	TargetGroupAttributeStickinessEnabled = "stickiness.enabled"
+	// TargetGroupAttributeStickinessType defines the attribute key for the type of sticky sessions.
+	TargetGroupAttributeStickinessType = "stickiness.type"
 )
 
 // LoadBalancerAttribute defines a set of attributes for a V2 load balancer.
@@ -293,6 +299,13 @@ type VPCSpec struct {
 	// ID is the vpc-id of the VPC this provider should use to create resources.
 	ID string `json:"id,omitempty"`
 
+	// UseDefault makes the provider use the default VPC of the region, and its default subnets when no
+	// subnets are specified, instead of creating a managed VPC. The default VPC and its subnets are
+	// treated as unmanaged resources, so they are never modified or deleted by the provider.
+	// Mutually exclusive with ID, CidrBlock, IPAMPool and IPv6.
+	// +optional
+	UseDefault bool `json:"useDefault,omitempty"`
+
 	// CidrBlock is the CIDR block to be used when the provider creates a managed VPC.
 	// Defaults to 10.0.0.0/16.
 	// Mutually exclusive with IPAMPool.
@@ -349,6 +362,35 @@ type VPCSpec struct {
 	// +optional
 	// +kubebuilder:validation:Enum:=ip-name;resource-name
 	PrivateDNSHostnameTypeOnLaunch *string `json:"privateDnsHostnameTypeOnLaunch,omitempty"`
+
+	// NATGateway configures the NAT gateways of a managed VPC.
+	// +optional
+	NATGateway *NATGatewaySpec `json:"natGateway,omitempty"`
+}
+
+// NATGatewaySpec configures the NAT gateways of a managed VPC.
+type NATGatewaySpec struct {
+	// HealthCheck enables the check of the state of the NAT gateways on each reconciliation. The NAT
+	// gateways which are no longer available are reported in the NatGatewaysHealthy condition.
+	// +optional
+	HealthCheck bool `json:"healthCheck,omitempty"`
+
+	// FailoverOnDegraded routes the egress traffic of the private subnets of an availability zone through
+	// an available NAT gateway of another availability zone while the NAT gateway of their availability
+	// zone is degraded, and routes it back once it recovers. It enables the health check.
+	// +optional
+	FailoverOnDegraded bool `json:"failoverOnDegraded,omitempty"`
+}
+
+// IsHealthCheckEnabled returns true if the state of the NAT gateways is checked.
+func (n *NATGatewaySpec) IsHealthCheckEnabled() bool {
+	return n != nil && (n.HealthCheck || n.FailoverOnDegraded)
+}
+
+// IsFailoverEnabled returns true if the egress traffic is routed through another NAT gateway while the NAT
+// gateway of an availability zone is degraded.
+func (n *NATGatewaySpec) IsFailoverEnabled() bool {
+	return n != nil && n.FailoverOnDegraded
 }
 
 // String returns a string representation of the VPC.
@@ -356,9 +398,9 @@ func (v *VPCSpec) String() string {
 	return fmt.Sprintf("id=%s", v.ID)
 }
 
-// IsUnmanaged returns true if the VPC is unmanaged.
+// IsUnmanaged returns true if the VPC is unmanaged. The default VPC is always unmanaged, even before it is discovered.
 func (v *VPCSpec) IsUnmanaged(clusterName string) bool {
-	return v.ID != "" && !v.Tags.HasOwned(clusterName)
+	return v.UseDefault || (v.ID != "" && !v.Tags.HasOwned(clusterName))
 }
 
 // IsManaged returns true if VPC is managed.
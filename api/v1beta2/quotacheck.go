@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// DefaultQuotaCheckInterval is the minimum time between two quota checks when QuotaCheck.Interval is not set.
+const DefaultQuotaCheckInterval = time.Hour
+
+// IsEnabled returns whether the quota check is enabled.
+func (q *QuotaCheck) IsEnabled() bool {
+	return q != nil && q.Enabled
+}
+
+// GetInterval returns the minimum time between two quota checks.
+func (q *QuotaCheck) GetInterval() time.Duration {
+	if q == nil || q.Interval == nil {
+		return DefaultQuotaCheckInterval
+	}
+	return q.Interval.Duration
+}
+
+// Validate validates the quota check configuration.
+func (q *QuotaCheck) Validate() field.ErrorList {
+	var errs field.ErrorList
+
+	if q == nil {
+		return errs
+	}
+
+	if q.Interval != nil && q.Interval.Duration <= 0 {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "quotaCheck", "interval"), q.Interval.Duration.String(), "must be greater than 0"))
+	}
+
+	return errs
+}
@@ -188,13 +188,52 @@ const (
 	// PrivateRoleTagValue describes the value for the private role.
 	PrivateRoleTagValue = "private"
 
+	// InstanceConnectEndpointRoleTagValue describes the value for the instance connect endpoint role.
+	InstanceConnectEndpointRoleTagValue = "instance-connect-endpoint"
+
+	// SpotInterruptionRoleTagValue describes the value for the role of the rule and queue receiving the spot
+	// interruption notices.
+	SpotInterruptionRoleTagValue = "spot-interruption"
+
 	// MachineNameTagKey is the key for machine name.
 	MachineNameTagKey = "MachineName"
 
+	// OSTagKey is the tag we use to record the operating system of the nodes running on an
+	// instance, following the kubernetes.io/os node label convention.
+	OSTagKey = "kubernetes.io/os"
+
+	// ClusterAutoscalerOSLabelTagKey is the tag the cluster autoscaler reads to learn the
+	// kubernetes.io/os label of the nodes of an autoscaling group scaled from zero.
+	ClusterAutoscalerOSLabelTagKey = "k8s.io/cluster-autoscaler/node-template/label/" + OSTagKey
+
+	// ClusterAutoscalerEnabledTagKey is the tag the cluster autoscaler uses to auto-discover the
+	// autoscaling groups it scales, together with the tag of the cluster.
+	ClusterAutoscalerEnabledTagKey = "k8s.io/cluster-autoscaler/enabled"
+
 	// LaunchTemplateBootstrapDataSecret is the tag we use to store the `<namespace>/<name>`
 	// of the bootstrap secret that was used to create the user data for the latest launch
 	// template version.
 	LaunchTemplateBootstrapDataSecret = NameAWSProviderPrefix + "bootstrap-data-secret"
+
+	// InstanceUserDataHashTagKey is the tag we use to store the hash of the user data
+	// an instance was launched with.
+	InstanceUserDataHashTagKey = NameAWSProviderPrefix + "user-data-hash"
+
+	// InstanceUserDataDeliveryTagKey is the tag we use to store the mechanism through which
+	// an instance received its bootstrap data.
+	InstanceUserDataDeliveryTagKey = NameAWSProviderPrefix + "user-data-delivery"
+
+	// ElasticIPAllocatedAtTagKey is the tag we use to store the time, in RFC 3339 format, at
+	// which an Elastic IP was allocated.
+	ElasticIPAllocatedAtTagKey = NameAWSProviderPrefix + "allocated-at"
+
+	// DetachedByCAPATagKey is the tag we use to record the name of the Auto Scaling group an
+	// instance was detached from by CAPA.
+	DetachedByCAPATagKey = NameAWSProviderPrefix + "detached-by-capa"
+
+	// MachinePoolTagKey is the tag we use to record the `<namespace>/<name>` of the AWSMachinePool
+	// which manages an Auto Scaling group.
+	MachinePoolTagKey = NameAWSProviderPrefix + "machine-pool"
 )
 
 // ClusterTagKey generates the key for resources associated with a cluster.
@@ -207,6 +246,40 @@ func ClusterAWSCloudProviderTagKey(name string) string {
 	return fmt.Sprintf("%s%s", NameKubernetesAWSCloudProviderPrefix, name)
 }
 
+// ClusterAutoscalerClusterTagKey generates the key the cluster autoscaler uses to auto-discover the
+// autoscaling groups of a cluster.
+func ClusterAutoscalerClusterTagKey(name string) string {
+	return fmt.Sprintf("k8s.io/cluster-autoscaler/%s", name)
+}
+
+// ClusterAutoscalerTags returns the tags marking an autoscaling group for the auto-discovery of the
+// cluster autoscaler.
+func ClusterAutoscalerTags(name string) Tags {
+	return Tags{
+		ClusterAutoscalerEnabledTagKey:       "true",
+		ClusterAutoscalerClusterTagKey(name): string(ResourceLifecycleOwned),
+	}
+}
+
+// ClusterAutoscalerTagUpdates returns the tags to create on an autoscaling group with the current tags
+// to enable its auto-discovery by the cluster autoscaler, or the tags to remove to disable it. The
+// additional tags are never removed.
+func ClusterAutoscalerTagUpdates(name string, current, additional Tags, enabled bool) (create, remove Tags) {
+	create, remove = Tags{}, Tags{}
+	for key, value := range ClusterAutoscalerTags(name) {
+		currentValue, ok := current[key]
+		switch {
+		case enabled && (!ok || currentValue != value):
+			create[key] = value
+		case !enabled && ok:
+			if _, isAdditional := additional[key]; !isAdditional {
+				remove[key] = currentValue
+			}
+		}
+	}
+	return create, remove
+}
+
 // BuildParams is used to build tags around an aws resource.
 type BuildParams struct {
 	// Lifecycle determines the resource lifecycle.
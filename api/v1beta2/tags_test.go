@@ -370,3 +370,69 @@ func getSortFieldErrorsFunc(errs []*field.Error) func(i, j int) bool {
 		return iBV < jBV
 	}
 }
+
+func TestClusterAutoscalerTagUpdates(t *testing.T) {
+	tests := []struct {
+		name           string
+		current        Tags
+		additional     Tags
+		enabled        bool
+		expectedCreate Tags
+		expectedRemove Tags
+	}{
+		{
+			name:    "enabled without the tags",
+			current: Tags{"a": "b"},
+			enabled: true,
+			expectedCreate: Tags{
+				"k8s.io/cluster-autoscaler/enabled":    "true",
+				"k8s.io/cluster-autoscaler/my-cluster": "owned",
+			},
+			expectedRemove: Tags{},
+		},
+		{
+			name: "enabled with a changed tag",
+			current: Tags{
+				"k8s.io/cluster-autoscaler/enabled":    "false",
+				"k8s.io/cluster-autoscaler/my-cluster": "owned",
+			},
+			enabled:        true,
+			expectedCreate: Tags{"k8s.io/cluster-autoscaler/enabled": "true"},
+			expectedRemove: Tags{},
+		},
+		{
+			name: "disabled with the tags",
+			current: Tags{
+				"a":                                    "b",
+				"k8s.io/cluster-autoscaler/enabled":    "true",
+				"k8s.io/cluster-autoscaler/my-cluster": "owned",
+			},
+			expectedCreate: Tags{},
+			expectedRemove: Tags{
+				"k8s.io/cluster-autoscaler/enabled":    "true",
+				"k8s.io/cluster-autoscaler/my-cluster": "owned",
+			},
+		},
+		{
+			name: "disabled with the tags in the additional tags",
+			current: Tags{
+				"k8s.io/cluster-autoscaler/enabled":    "true",
+				"k8s.io/cluster-autoscaler/my-cluster": "owned",
+			},
+			additional:     Tags{"k8s.io/cluster-autoscaler/enabled": "true"},
+			expectedCreate: Tags{},
+			expectedRemove: Tags{"k8s.io/cluster-autoscaler/my-cluster": "owned"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			create, remove := ClusterAutoscalerTagUpdates("my-cluster", tc.current, tc.additional, tc.enabled)
+			if e, a := tc.expectedCreate, create; !cmp.Equal(e, a) {
+				t.Errorf("expected create %#v, got %#v", e, a)
+			}
+			if e, a := tc.expectedRemove, remove; !cmp.Equal(e, a) {
+				t.Errorf("expected remove %#v, got %#v", e, a)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta2
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -78,6 +79,64 @@ const (
 	// ExternalResourceGCTasksAnnotation is the name of an annotation that indicates what
 	// external resources tasks should be executed by garbage collector for the cluster.
 	ExternalResourceGCTasksAnnotation = "aws.cluster.x-k8s.io/external-resource-tasks-gc"
+
+	// ScanOrphansAnnotation is the name of an annotation of an AWSCluster which requests a scan for
+	// the AWS resources owned by the cluster which no object of the cluster references anymore.
+	// Its value is either ScanOrphansValue or CleanOrphansValue.
+	ScanOrphansAnnotation = "aws.cluster.x-k8s.io/scan-orphans"
+
+	// ScanOrphansValue is the value of the ScanOrphansAnnotation which reports the orphaned resources.
+	ScanOrphansValue = "true"
+
+	// CleanOrphansValue is the value of the ScanOrphansAnnotation which reports the orphaned resources
+	// and deletes the ones reported by the previous scan, when the controller allows it.
+	CleanOrphansValue = "clean"
+
+	// CollectSupportBundleAnnotation is the name of an annotation of an AWSCluster which, when set to "true",
+	// requests the collection of a support bundle of the cluster. It is removed once the bundle is written.
+	CollectSupportBundleAnnotation = "aws.cluster.x-k8s.io/collect-support-bundle"
+
+	// SupportBundleRedactAccountIDsAnnotation is the name of an annotation of an AWSCluster which, when set to
+	// "true", redacts the AWS account IDs from the support bundles of the cluster.
+	SupportBundleRedactAccountIDsAnnotation = "aws.cluster.x-k8s.io/support-bundle-redact-account-ids"
+
+	// SkipSSHKeyCheckAnnotation is the name of an annotation of an AWSCluster or an AWSMachine which, when set
+	// to "true", lets the instances be launched even though their SSH key pair wasn't found in the region.
+	SkipSSHKeyCheckAnnotation = "aws.cluster.x-k8s.io/skip-ssh-key-check"
+
+	// MigrateControlPlaneLoadBalancerAnnotation is the name of an annotation of an AWSCluster which, when set
+	// to "true", lets the type of its control plane load balancer be changed from classic to nlb. It
+	// acknowledges that the control plane endpoint changes to the DNS name of the Network Load Balancer.
+	MigrateControlPlaneLoadBalancerAnnotation = "aws.cluster.x-k8s.io/migrate-control-plane-load-balancer"
+
+	// DeleteClassicControlPlaneLoadBalancerAnnotation is the name of an annotation of an AWSCluster which, when
+	// set to "true", lets the migration of its control plane load balancer delete the Classic Load Balancer once
+	// the control plane endpoint is the DNS name of the Network Load Balancer. It confirms that nothing uses the
+	// endpoint of the Classic Load Balancer anymore.
+	DeleteClassicControlPlaneLoadBalancerAnnotation = "aws.cluster.x-k8s.io/delete-classic-control-plane-load-balancer"
+
+	// ForceNetworkCleanupAnnotation is the name of an annotation of an AWSCluster or an AWSManagedControlPlane
+	// which, when set to "true", lets the deletion of the cluster detach and delete the stale network interfaces
+	// that reference its security groups and block the deletion of its security groups and subnets.
+	ForceNetworkCleanupAnnotation = "aws.cluster.x-k8s.io/force-network-cleanup"
+
+	// NeedsUserDataMigrationAnnotation is the name of an annotation the controller sets to "true" on the
+	// AWSMachines whose instance stores its bootstrap data in the instance user data while their AWSCluster
+	// has an S3 bucket. The user data of a running instance can't be changed, so these machines have to be
+	// replaced to stop exposing the bootstrap data.
+	NeedsUserDataMigrationAnnotation = "aws.cluster.x-k8s.io/needs-userdata-migration"
+
+	// BootstrapTokenExpiresAtAnnotation is the name of an optional annotation of a bootstrap data secret holding the
+	// time, in RFC 3339 format, at which the bootstrap token embedded in the bootstrap data expires. It can be set by
+	// the tooling around the bootstrap provider. Without it, the expiry of a kubeadm bootstrap token is read from the
+	// secret of the token in the workload cluster.
+	BootstrapTokenExpiresAtAnnotation = "aws.cluster.x-k8s.io/bootstrap-token-expires-at"
+
+	// BootstrapTokenExpiredAnnotation is the name of an annotation the controller sets on an AWSMachinePool whose
+	// bootstrap token expired, to the expiry of the token in RFC 3339 format. The hash of the user data of the launch
+	// template is invalidated until a version is created after that time, so that a new version is created with the
+	// current bootstrap data.
+	BootstrapTokenExpiredAnnotation = "aws.cluster.x-k8s.io/bootstrap-token-expired"
 )
 
 // GCTask defines a task to be executed by the garbage collector.
@@ -414,6 +473,20 @@ const (
 	AmazonLinuxGPU EKSAMILookupType = "AmazonLinuxGPU"
 )
 
+// DefaultUserDataTransformTemplateKey is the default key of the template of a UserDataTransform in its ConfigMap.
+const DefaultUserDataTransformTemplateKey = "template"
+
+// OSType is the operating system of the nodes running on a machine.
+// +kubebuilder:validation:Enum:=linux;windows
+type OSType string
+
+const (
+	// OSTypeLinux is the Linux operating system.
+	OSTypeLinux OSType = "linux"
+	// OSTypeWindows is the Windows operating system.
+	OSTypeWindows OSType = "windows"
+)
+
 // PrivateDNSName is the options for the instance hostname.
 type PrivateDNSName struct {
 	// EnableResourceNameDNSAAAARecord indicates whether to respond to DNS queries for instance hostnames with DNS AAAA records.
@@ -427,3 +500,27 @@ type PrivateDNSName struct {
 	// +kubebuilder:validation:Enum:=ip-name;resource-name
 	HostnameType *string `json:"hostnameType,omitempty"`
 }
+
+// UserDataTransform references a Go template which renders the user data of the instances, in place of
+// the user data generated by CAPA. The template is rendered with the following context:
+//
+//   - .BootstrapData is the bootstrap data generated by the bootstrap provider.
+//   - .ClusterName is the name of the cluster.
+//   - .ClusterEndpoint is the URL of the API server of the cluster, e.g. https://example.com:6443.
+//   - .CACertHash is the hash of the public key of the CA of the cluster, in the sha256:<hex> format
+//     used by kubeadm. It is empty if the CA isn't stored in the management cluster.
+//   - .NodeLabels are the labels of the machine which Cluster API propagates to the node.
+//   - .S3URL is the URL of the bootstrap data when it is stored in the S3 bucket of the cluster,
+//     and is empty otherwise.
+//
+// The Base64Encode and Indent functions are available to the template.
+type UserDataTransform struct {
+	// ConfigMapRef is the reference to the ConfigMap holding the template, in the namespace of the
+	// referencing object.
+	ConfigMapRef corev1.LocalObjectReference `json:"configMapRef"`
+
+	// TemplateKey is the key of the template in the ConfigMap.
+	// +kubebuilder:default=template
+	// +optional
+	TemplateKey string `json:"templateKey,omitempty"`
+}
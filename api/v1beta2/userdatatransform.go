@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
+)
+
+// GetTemplateKey returns the key of the template in the ConfigMap.
+func (t *UserDataTransform) GetTemplateKey() string {
+	if t.TemplateKey == "" {
+		return DefaultUserDataTransformTemplateKey
+	}
+	return t.TemplateKey
+}
+
+// Validate validates the UserDataTransform fields. The template itself is read from the ConfigMap when the
+// user data is rendered, and its errors are reported by the UserDataTransformReady condition.
+func (t *UserDataTransform) Validate(fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if t == nil {
+		return errs
+	}
+
+	if !feature.Gates.Enabled(feature.UserDataTransform) {
+		errs = append(errs, field.Forbidden(fldPath, "can be set only if the UserDataTransform feature gate is enabled"))
+	}
+
+	if t.ConfigMapRef.Name == "" {
+		errs = append(errs, field.Required(fldPath.Child("configMapRef", "name"), "can't be empty"))
+	}
+
+	if t.TemplateKey != "" {
+		if msgs := validation.IsConfigMapKey(t.TemplateKey); len(msgs) > 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("templateKey"), t.TemplateKey, strings.Join(msgs, ", ")))
+		}
+	}
+
+	return errs
+}
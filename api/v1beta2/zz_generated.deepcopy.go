@@ -303,6 +303,11 @@ func (in *AWSClusterSpec) DeepCopyInto(out *AWSClusterSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.DefaultInstanceMetadataOptions != nil {
+		in, out := &in.DefaultInstanceMetadataOptions, &out.DefaultInstanceMetadataOptions
+		*out = new(InstanceMetadataOptions)
+		**out = **in
+	}
 	if in.ControlPlaneLoadBalancer != nil {
 		in, out := &in.ControlPlaneLoadBalancer, &out.ControlPlaneLoadBalancer
 		*out = new(AWSLoadBalancerSpec)
@@ -313,6 +318,18 @@ func (in *AWSClusterSpec) DeepCopyInto(out *AWSClusterSpec) {
 		*out = new(AWSLoadBalancerSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DefaultLifecycleHooks != nil {
+		in, out := &in.DefaultLifecycleHooks, &out.DefaultLifecycleHooks
+		*out = make([]AWSLifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResourcePolicyRef != nil {
+		in, out := &in.ResourcePolicyRef, &out.ResourcePolicyRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 	in.Bastion.DeepCopyInto(&out.Bastion)
 	if in.IdentityRef != nil {
 		in, out := &in.IdentityRef, &out.IdentityRef
@@ -324,6 +341,21 @@ func (in *AWSClusterSpec) DeepCopyInto(out *AWSClusterSpec) {
 		*out = new(S3Bucket)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.QuotaCheck != nil {
+		in, out := &in.QuotaCheck, &out.QuotaCheck
+		*out = new(QuotaCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EventBridge != nil {
+		in, out := &in.EventBridge, &out.EventBridge
+		*out = new(EventBridgeConfig)
+		**out = **in
+	}
+	if in.InstanceConnectEndpoint != nil {
+		in, out := &in.InstanceConnectEndpoint, &out.InstanceConnectEndpoint
+		*out = new(InstanceConnectEndpoint)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSClusterSpec.
@@ -433,6 +465,39 @@ func (in *AWSClusterStatus) DeepCopyInto(out *AWSClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.QuotaUsage != nil {
+		in, out := &in.QuotaUsage, &out.QuotaUsage
+		*out = make([]QuotaUsage, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastQuotaCheckTime != nil {
+		in, out := &in.LastQuotaCheckTime, &out.LastQuotaCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EventBridge != nil {
+		in, out := &in.EventBridge, &out.EventBridge
+		*out = new(EventBridgeStatus)
+		**out = **in
+	}
+	if in.OrphanedResources != nil {
+		in, out := &in.OrphanedResources, &out.OrphanedResources
+		*out = make([]OrphanedResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastOrphanScanTime != nil {
+		in, out := &in.LastOrphanScanTime, &out.LastOrphanScanTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ControlPlaneLoadBalancerMigration != nil {
+		in, out := &in.ControlPlaneLoadBalancerMigration, &out.ControlPlaneLoadBalancerMigration
+		*out = new(LoadBalancerMigration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceConnectEndpoint != nil {
+		in, out := &in.InstanceConnectEndpoint, &out.InstanceConnectEndpoint
+		*out = new(InstanceConnectEndpointStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSClusterStatus.
@@ -551,6 +616,51 @@ func (in *AWSIdentityReference) DeepCopy() *AWSIdentityReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSLifecycleHook) DeepCopyInto(out *AWSLifecycleHook) {
+	*out = *in
+	if in.NotificationTargetARN != nil {
+		in, out := &in.NotificationTargetARN, &out.NotificationTargetARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.RoleARN != nil {
+		in, out := &in.RoleARN, &out.RoleARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.HeartbeatTimeout != nil {
+		in, out := &in.HeartbeatTimeout, &out.HeartbeatTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DefaultResult != nil {
+		in, out := &in.DefaultResult, &out.DefaultResult
+		*out = new(LifecycleHookDefaultResult)
+		**out = **in
+	}
+	if in.NotificationMetadata != nil {
+		in, out := &in.NotificationMetadata, &out.NotificationMetadata
+		*out = new(string)
+		**out = **in
+	}
+	if in.SyncMode != nil {
+		in, out := &in.SyncMode, &out.SyncMode
+		*out = new(LifecycleHookSyncMode)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLifecycleHook.
+func (in *AWSLifecycleHook) DeepCopy() *AWSLifecycleHook {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSLifecycleHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSLoadBalancerSpec) DeepCopyInto(out *AWSLoadBalancerSpec) {
 	*out = *in
@@ -591,6 +701,11 @@ func (in *AWSLoadBalancerSpec) DeepCopyInto(out *AWSLoadBalancerSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TargetGroupAttributes != nil {
+		in, out := &in.TargetGroupAttributes, &out.TargetGroupAttributes
+		*out = new(TargetGroupAttributesSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLoadBalancerSpec.
@@ -755,6 +870,11 @@ func (in *AWSMachineSpec) DeepCopyInto(out *AWSMachineSpec) {
 		*out = new(PrivateDNSName)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.UserDataTransform != nil {
+		in, out := &in.UserDataTransform, &out.UserDataTransform
+		*out = new(UserDataTransform)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSMachineSpec.
@@ -797,6 +917,22 @@ func (in *AWSMachineStatus) DeepCopyInto(out *AWSMachineStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.BootstrapDataRenderedAt != nil {
+		in, out := &in.BootstrapDataRenderedAt, &out.BootstrapDataRenderedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.InstanceRequestedAt != nil {
+		in, out := &in.InstanceRequestedAt, &out.InstanceRequestedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.InstanceRunningAt != nil {
+		in, out := &in.InstanceRunningAt, &out.InstanceRunningAt
+		*out = (*in).DeepCopy()
+	}
+	if in.AddressesAssignedAt != nil {
+		in, out := &in.AddressesAssignedAt, &out.AddressesAssignedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSMachineStatus.
@@ -1269,6 +1405,36 @@ func (in *CloudInit) DeepCopy() *CloudInit {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventBridgeConfig) DeepCopyInto(out *EventBridgeConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventBridgeConfig.
+func (in *EventBridgeConfig) DeepCopy() *EventBridgeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EventBridgeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventBridgeStatus) DeepCopyInto(out *EventBridgeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventBridgeStatus.
+func (in *EventBridgeStatus) DeepCopy() *EventBridgeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EventBridgeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Filter) DeepCopyInto(out *Filter) {
 	*out = *in
@@ -1559,6 +1725,71 @@ func (in *Instance) DeepCopy() *Instance {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceConnectEndpoint) DeepCopyInto(out *InstanceConnectEndpoint) {
+	*out = *in
+	if in.SubnetSelection != nil {
+		in, out := &in.SubnetSelection, &out.SubnetSelection
+		*out = new(InstanceConnectEndpointSubnetSelection)
+		**out = **in
+	}
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceConnectEndpoint.
+func (in *InstanceConnectEndpoint) DeepCopy() *InstanceConnectEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceConnectEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceConnectEndpointStatus) DeepCopyInto(out *InstanceConnectEndpointStatus) {
+	*out = *in
+	if in.SecurityGroupIDs != nil {
+		in, out := &in.SecurityGroupIDs, &out.SecurityGroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceConnectEndpointStatus.
+func (in *InstanceConnectEndpointStatus) DeepCopy() *InstanceConnectEndpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceConnectEndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceConnectEndpointSubnetSelection) DeepCopyInto(out *InstanceConnectEndpointSubnetSelection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceConnectEndpointSubnetSelection.
+func (in *InstanceConnectEndpointSubnetSelection) DeepCopy() *InstanceConnectEndpointSubnetSelection {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceConnectEndpointSubnetSelection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceMetadataOptions) DeepCopyInto(out *InstanceMetadataOptions) {
 	*out = *in
@@ -1661,6 +1892,37 @@ func (in *LoadBalancer) DeepCopy() *LoadBalancer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerMigration) DeepCopyInto(out *LoadBalancerMigration) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerMigration.
+func (in *LoadBalancerMigration) DeepCopy() *LoadBalancerMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATGatewaySpec) DeepCopyInto(out *NATGatewaySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATGatewaySpec.
+func (in *NATGatewaySpec) DeepCopy() *NATGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NATGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 	*out = *in
@@ -1732,6 +1994,21 @@ func (in *NetworkStatus) DeepCopy() *NetworkStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanedResource) DeepCopyInto(out *OrphanedResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanedResource.
+func (in *OrphanedResource) DeepCopy() *OrphanedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(OrphanedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrivateDNSName) DeepCopyInto(out *PrivateDNSName) {
 	*out = *in
@@ -1762,6 +2039,41 @@ func (in *PrivateDNSName) DeepCopy() *PrivateDNSName {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaCheck) DeepCopyInto(out *QuotaCheck) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaCheck.
+func (in *QuotaCheck) DeepCopy() *QuotaCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaUsage) DeepCopyInto(out *QuotaUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaUsage.
+func (in *QuotaUsage) DeepCopy() *QuotaUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteTable) DeepCopyInto(out *RouteTable) {
 	*out = *in
@@ -1925,6 +2237,31 @@ func (in Tags) DeepCopy() Tags {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetGroupAttributesSpec) DeepCopyInto(out *TargetGroupAttributesSpec) {
+	*out = *in
+	if in.SlowStartDurationSeconds != nil {
+		in, out := &in.SlowStartDurationSeconds, &out.SlowStartDurationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Stickiness != nil {
+		in, out := &in.Stickiness, &out.Stickiness
+		*out = new(TargetGroupStickinessSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetGroupAttributesSpec.
+func (in *TargetGroupAttributesSpec) DeepCopy() *TargetGroupAttributesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetGroupAttributesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetGroupHealthCheck) DeepCopyInto(out *TargetGroupHealthCheck) {
 	*out = *in
@@ -1990,6 +2327,37 @@ func (in *TargetGroupSpec) DeepCopy() *TargetGroupSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetGroupStickinessSpec) DeepCopyInto(out *TargetGroupStickinessSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetGroupStickinessSpec.
+func (in *TargetGroupStickinessSpec) DeepCopy() *TargetGroupStickinessSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetGroupStickinessSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserDataTransform) DeepCopyInto(out *UserDataTransform) {
+	*out = *in
+	out.ConfigMapRef = in.ConfigMapRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserDataTransform.
+func (in *UserDataTransform) DeepCopy() *UserDataTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(UserDataTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VPCSpec) DeepCopyInto(out *VPCSpec) {
 	*out = *in
@@ -2030,6 +2398,11 @@ func (in *VPCSpec) DeepCopyInto(out *VPCSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.NATGateway != nil {
+		in, out := &in.NATGateway, &out.NATGateway
+		*out = new(NATGatewaySpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VPCSpec.
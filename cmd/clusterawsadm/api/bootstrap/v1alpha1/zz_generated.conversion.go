@@ -204,6 +204,8 @@ func autoConvert_v1beta1_AWSIAMConfigurationSpec_To_v1alpha1_AWSIAMConfiguration
 	out.Region = in.Region
 	out.EKS = (*EKSConfig)(unsafe.Pointer(in.EKS))
 	out.EventBridge = (*EventBridgeConfig)(unsafe.Pointer(in.EventBridge))
+	// WARNING: in.SpotInterruptionHandling requires manual conversion: does not exist in peer-type
+	// WARNING: in.ForceNetworkCleanup requires manual conversion: does not exist in peer-type
 	out.Partition = in.Partition
 	out.SecureSecretsBackends = *(*[]v1beta2.SecretBackend)(unsafe.Pointer(&in.SecureSecretsBackends))
 	// WARNING: in.S3Buckets requires manual conversion: does not exist in peer-type
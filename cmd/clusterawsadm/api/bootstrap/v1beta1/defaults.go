@@ -84,6 +84,16 @@ func SetDefaults_AWSIAMConfigurationSpec(obj *AWSIAMConfigurationSpec) { //nolin
 			Enable: false,
 		}
 	}
+	if obj.SpotInterruptionHandling == nil {
+		obj.SpotInterruptionHandling = &SpotInterruptionHandlingConfig{
+			Enable: false,
+		}
+	}
+	if obj.ForceNetworkCleanup == nil {
+		obj.ForceNetworkCleanup = &ForceNetworkCleanupConfig{
+			Enable: false,
+		}
+	}
 	if obj.EKS.ManagedMachinePool == nil {
 		obj.EKS.ManagedMachinePool = &AWSIAMRoleSpec{
 			Disable: true,
@@ -124,6 +124,20 @@ type EventBridgeConfig struct {
 	Enable bool `json:"enable,omitempty"`
 }
 
+// SpotInterruptionHandlingConfig represents configuration for enabling experimental feature to
+// handle the spot interruption notices of machine pool instances.
+type SpotInterruptionHandlingConfig struct {
+	// Enable controls whether permissions are granted to receive the spot interruption notices
+	Enable bool `json:"enable,omitempty"`
+}
+
+// ForceNetworkCleanupConfig represents configuration for enabling the release by force of the
+// network interfaces blocking the deletion of a cluster.
+type ForceNetworkCleanupConfig struct {
+	// Enable controls whether permissions are granted to delete the network interfaces
+	Enable bool `json:"enable,omitempty"`
+}
+
 // ClusterAPIControllers controls the configuration of the AWS IAM role for
 // the Kubernetes Cluster API Provider AWS controller.
 type ClusterAPIControllers struct {
@@ -210,6 +224,14 @@ type AWSIAMConfigurationSpec struct {
 	// EventBridge controls configuration for consuming EventBridge events
 	EventBridge *EventBridgeConfig `json:"eventBridge,omitempty"`
 
+	// SpotInterruptionHandling controls configuration for handling the spot interruption notices
+	// of machine pool instances
+	SpotInterruptionHandling *SpotInterruptionHandlingConfig `json:"spotInterruptionHandling,omitempty"`
+
+	// ForceNetworkCleanup controls configuration for releasing by force the network interfaces
+	// blocking the deletion of clusters annotated with aws.cluster.x-k8s.io/force-network-cleanup
+	ForceNetworkCleanup *ForceNetworkCleanupConfig `json:"forceNetworkCleanup,omitempty"`
+
 	// Partition is the AWS security partition being used. Defaults to "aws"
 	Partition string `json:"partition,omitempty"`
 
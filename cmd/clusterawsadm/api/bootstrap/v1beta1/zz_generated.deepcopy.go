@@ -80,6 +80,16 @@ func (in *AWSIAMConfigurationSpec) DeepCopyInto(out *AWSIAMConfigurationSpec) {
 		*out = new(EventBridgeConfig)
 		**out = **in
 	}
+	if in.SpotInterruptionHandling != nil {
+		in, out := &in.SpotInterruptionHandling, &out.SpotInterruptionHandling
+		*out = new(SpotInterruptionHandlingConfig)
+		**out = **in
+	}
+	if in.ForceNetworkCleanup != nil {
+		in, out := &in.ForceNetworkCleanup, &out.ForceNetworkCleanup
+		*out = new(ForceNetworkCleanupConfig)
+		**out = **in
+	}
 	if in.SecureSecretsBackends != nil {
 		in, out := &in.SecureSecretsBackends, &out.SecureSecretsBackends
 		*out = make([]v1beta2.SecretBackend, len(*in))
@@ -256,6 +266,21 @@ func (in *EventBridgeConfig) DeepCopy() *EventBridgeConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForceNetworkCleanupConfig) DeepCopyInto(out *ForceNetworkCleanupConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForceNetworkCleanupConfig.
+func (in *ForceNetworkCleanupConfig) DeepCopy() *ForceNetworkCleanupConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ForceNetworkCleanupConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Nodes) DeepCopyInto(out *Nodes) {
 	*out = *in
@@ -286,3 +311,18 @@ func (in *S3Buckets) DeepCopy() *S3Buckets {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpotInterruptionHandlingConfig) DeepCopyInto(out *SpotInterruptionHandlingConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotInterruptionHandlingConfig.
+func (in *SpotInterruptionHandlingConfig) DeepCopy() *SpotInterruptionHandlingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SpotInterruptionHandlingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -51,6 +51,20 @@ func (t Template) controllersPolicyRoleAttachments() []string {
 	return attachments
 }
 
+// controllersPolicyPrincipals returns the ARNs of the roles and user the controllers policy is attached to.
+func (t Template) controllersPolicyPrincipals() iamv1.Resources {
+	principals := iamv1.Resources{
+		fmt.Sprintf("arn:*:iam::*:role/%s", t.NewManagedName("controllers")),
+	}
+	if !t.Spec.ControlPlane.DisableClusterAPIControllerPolicyAttachment {
+		principals = append(principals, fmt.Sprintf("arn:*:iam::*:role/%s", t.NewManagedName("control-plane")))
+	}
+	if t.Spec.BootstrapUser.Enable {
+		principals = append(principals, fmt.Sprintf("arn:*:iam::*:user/%s", t.Spec.BootstrapUser.UserName))
+	}
+	return principals
+}
+
 func (t Template) controllersTrustPolicy() *iamv1.PolicyDocument {
 	policyDocument := ec2AssumeRolePolicy()
 	policyDocument.Statement = append(policyDocument.Statement, t.Spec.ClusterAPIControllers.TrustStatements...)
@@ -103,6 +117,7 @@ func (t Template) ControllersPolicy() *iamv1.PolicyDocument {
 				"ec2:CreateTags",
 				"ec2:CreateVpc",
 				"ec2:CreateVpcEndpoint",
+				"ec2:CreateInstanceConnectEndpoint",
 				"ec2:ModifyVpcAttribute",
 				"ec2:ModifyVpcEndpoint",
 				"ec2:DeleteInternetGateway",
@@ -110,14 +125,18 @@ func (t Template) ControllersPolicy() *iamv1.PolicyDocument {
 				"ec2:DeleteNatGateway",
 				"ec2:DeleteRouteTable",
 				"ec2:ReplaceRoute",
+				"ec2:ReplaceRouteTableAssociation",
 				"ec2:DeleteSecurityGroup",
 				"ec2:DeleteSubnet",
 				"ec2:DeleteTags",
 				"ec2:DeleteVpc",
 				"ec2:DeleteVpcEndpoints",
+				"ec2:DeleteInstanceConnectEndpoint",
 				"ec2:DescribeAccountAttributes",
 				"ec2:DescribeAddresses",
 				"ec2:DescribeAvailabilityZones",
+				"ec2:DescribeInstanceAttribute",
+				"ec2:DescribeInstanceConnectEndpoints",
 				"ec2:DescribeInstances",
 				"ec2:DescribeInstanceTypes",
 				"ec2:DescribeInternetGateways",
@@ -129,6 +148,7 @@ func (t Template) ControllersPolicy() *iamv1.PolicyDocument {
 				"ec2:DescribeNetworkInterfaceAttribute",
 				"ec2:DescribeRouteTables",
 				"ec2:DescribeSecurityGroups",
+				"ec2:DescribeSpotInstanceRequests",
 				"ec2:DescribeSubnets",
 				"ec2:DescribeVpcs",
 				"ec2:DescribeVpcAttribute",
@@ -146,6 +166,7 @@ func (t Template) ControllersPolicy() *iamv1.PolicyDocument {
 				"ec2:RunInstances",
 				"ec2:TerminateInstances",
 				"tag:GetResources",
+				"servicequotas:GetServiceQuota",
 				"elasticloadbalancing:AddTags",
 				"elasticloadbalancing:CreateLoadBalancer",
 				"elasticloadbalancing:ConfigureHealthCheck",
@@ -154,6 +175,7 @@ func (t Template) ControllersPolicy() *iamv1.PolicyDocument {
 				"elasticloadbalancing:DescribeLoadBalancers",
 				"elasticloadbalancing:DescribeLoadBalancerAttributes",
 				"elasticloadbalancing:DescribeTargetGroups",
+				"elasticloadbalancing:DescribeTargetGroupAttributes",
 				"elasticloadbalancing:ApplySecurityGroupsToLoadBalancer",
 				"elasticloadbalancing:DescribeTags",
 				"elasticloadbalancing:ModifyLoadBalancerAttributes",
@@ -167,9 +189,14 @@ func (t Template) ControllersPolicy() *iamv1.PolicyDocument {
 				"elasticloadbalancing:CreateListener",
 				"elasticloadbalancing:DescribeTargetHealth",
 				"elasticloadbalancing:RegisterTargets",
+				"elasticloadbalancing:DeregisterTargets",
 				"elasticloadbalancing:DeleteListener",
 				"autoscaling:DescribeAutoScalingGroups",
 				"autoscaling:DescribeInstanceRefreshes",
+				"autoscaling:DescribeLoadBalancerTargetGroups",
+				"autoscaling:DescribeScheduledActions",
+				"autoscaling:DescribeLifecycleHooks",
+				"autoscaling:DescribeScalingActivities",
 				"ec2:CreateLaunchTemplate",
 				"ec2:CreateLaunchTemplateVersion",
 				"ec2:DescribeLaunchTemplates",
@@ -190,8 +217,23 @@ func (t Template) ControllersPolicy() *iamv1.PolicyDocument {
 				"autoscaling:UpdateAutoScalingGroup",
 				"autoscaling:CreateOrUpdateTags",
 				"autoscaling:StartInstanceRefresh",
+				"autoscaling:CancelInstanceRefresh",
 				"autoscaling:DeleteAutoScalingGroup",
 				"autoscaling:DeleteTags",
+				"autoscaling:AttachLoadBalancerTargetGroups",
+				"autoscaling:DetachLoadBalancerTargetGroups",
+				"autoscaling:AttachLoadBalancers",
+				"autoscaling:DetachLoadBalancers",
+				"autoscaling:EnableMetricsCollection",
+				"autoscaling:DisableMetricsCollection",
+				"autoscaling:PutScheduledUpdateGroupAction",
+				"autoscaling:DeleteScheduledAction",
+				"autoscaling:PutLifecycleHook",
+				"autoscaling:DeleteLifecycleHook",
+				"autoscaling:CompleteLifecycleAction",
+				"autoscaling:RecordLifecycleActionHeartbeat",
+				"autoscaling:SetInstanceProtection",
+				"autoscaling:DetachInstances",
 			},
 		},
 		{
@@ -230,6 +272,18 @@ func (t Template) ControllersPolicy() *iamv1.PolicyDocument {
 				iamv1.StringLike: map[string]string{"iam:AWSServiceName": "spot.amazonaws.com"},
 			},
 		},
+		{
+			Effect: iamv1.EffectAllow,
+			Action: iamv1.Actions{
+				"iam:CreateServiceLinkedRole",
+			},
+			Resource: iamv1.Resources{
+				"arn:*:iam::*:role/aws-service-role/ec2-instance-connect.amazonaws.com/AWSServiceRoleForEC2InstanceConnect",
+			},
+			Condition: iamv1.Conditions{
+				iamv1.StringLike: map[string]string{"iam:AWSServiceName": "ec2-instance-connect.amazonaws.com"},
+			},
+		},
 		{
 			Effect:   iamv1.EffectAllow,
 			Resource: t.allowedEC2InstanceProfiles(),
@@ -237,6 +291,16 @@ func (t Template) ControllersPolicy() *iamv1.PolicyDocument {
 				"iam:PassRole",
 			},
 		},
+		// The controllers simulate their own policies to verify they are allowed to manage lifecycle hooks,
+		// through the role of their assumed role session.
+		{
+			Effect:   iamv1.EffectAllow,
+			Resource: t.controllersPolicyPrincipals(),
+			Action: iamv1.Actions{
+				"iam:GetRole",
+				"iam:SimulatePrincipalPolicy",
+			},
+		},
 	}
 	for _, secureSecretBackend := range t.Spec.SecureSecretsBackends {
 		switch secureSecretBackend {
@@ -292,24 +356,53 @@ func (t Template) ControllersPolicy() *iamv1.PolicyDocument {
 			},
 		})
 	}
-	if t.Spec.EventBridge.Enable {
+	// The spot interruption notices are received from a rule and queue like the EC2 events.
+	if t.Spec.EventBridge.Enable || t.Spec.SpotInterruptionHandling.Enable {
 		statement = append(statement, iamv1.StatementEntry{
 			Effect:   iamv1.EffectAllow,
 			Resource: iamv1.Resources{iamv1.Any},
 			Action: iamv1.Actions{
 				"events:DeleteRule",
 				"events:DescribeRule",
+				"events:ListRules",
+				"events:ListTagsForResource",
 				"events:ListTargetsByRule",
 				"events:PutRule",
 				"events:PutTargets",
 				"events:RemoveTargets",
+				"events:TagResource",
 				"sqs:CreateQueue",
 				"sqs:DeleteMessage",
 				"sqs:DeleteQueue",
 				"sqs:GetQueueAttributes",
 				"sqs:GetQueueUrl",
+				"sqs:ListQueueTags",
+				"sqs:ListQueues",
 				"sqs:ReceiveMessage",
 				"sqs:SetQueueAttributes",
+				"sqs:TagQueue",
+			},
+		})
+	}
+	// The instances of machine pools without Machines are terminated through their Auto Scaling group.
+	if t.Spec.SpotInterruptionHandling.Enable {
+		statement = append(statement, iamv1.StatementEntry{
+			Effect: iamv1.EffectAllow,
+			Resource: iamv1.Resources{
+				"arn:*:autoscaling:*:*:autoScalingGroup:*:autoScalingGroupName/*",
+			},
+			Action: iamv1.Actions{
+				"autoscaling:TerminateInstanceInAutoScalingGroup",
+			},
+		})
+	}
+	// The network interfaces blocking the deletion of a cluster are only deleted by force on request.
+	if t.Spec.ForceNetworkCleanup.Enable {
+		statement = append(statement, iamv1.StatementEntry{
+			Effect:   iamv1.EffectAllow,
+			Resource: iamv1.Resources{iamv1.Any},
+			Action: iamv1.Actions{
+				"ec2:DeleteNetworkInterface",
 			},
 		})
 	}
@@ -383,6 +476,9 @@ func (t Template) ControllersPolicyEKS() *iamv1.PolicyDocument {
 			"iam:CreateRole",
 			"iam:TagRole",
 			"iam:AttachRolePolicy",
+			"iam:GetRolePolicy",
+			"iam:PutRolePolicy",
+			"iam:DeleteRolePolicy",
 		}...)
 
 		statements = append(statements, iamv1.StatementEntry{
@@ -462,6 +558,14 @@ func (t Template) ControllersPolicyEKS() *iamv1.PolicyDocument {
 				"*",
 			},
 			Effect: iamv1.EffectAllow,
+		}, {
+			Action: iamv1.Actions{
+				"logs:DeleteLogGroup",
+			},
+			Resource: iamv1.Resources{
+				"arn:*:logs:*:*:log-group:/aws/eks/*",
+			},
+			Effect: iamv1.EffectAllow,
 		}, {
 			Action: iamv1.Actions{
 				"iam:PassRole",
@@ -175,6 +175,22 @@ func TestRenderCloudformation(t *testing.T) {
 				return t
 			},
 		},
+		{
+			fixture: "with_spot_interruption_handling",
+			template: func() Template {
+				t := NewTemplate()
+				t.Spec.SpotInterruptionHandling.Enable = true
+				return t
+			},
+		},
+		{
+			fixture: "with_force_network_cleanup",
+			template: func() Template {
+				t := NewTemplate()
+				t.Spec.ForceNetworkCleanup.Enable = true
+				return t
+			},
+		},
 		{
 			fixture: "with_allow_assume_role",
 			template: func() Template {
@@ -30,6 +30,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -40,7 +41,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/backoff"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/ec2"
@@ -48,8 +51,12 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/gc"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/instancestate"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/network"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/orphans"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/s3"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/securitygroup"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/servicequotas"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/spotinterruption"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/supportbundle"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/logger"
 	infrautilconditions "sigs.k8s.io/cluster-api-provider-aws/v2/util/conditions"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -74,11 +81,18 @@ type AWSClusterReconciler struct {
 	networkServiceFactory        func(scope.ClusterScope) services.NetworkInterface
 	elbServiceFactory            func(scope.ELBScope) services.ELBInterface
 	securityGroupFactory         func(scope.ClusterScope) services.SecurityGroupInterface
+	spotInterruptionFactory      func(scope.EC2Scope) services.SpotInterruptionInterface
 	Endpoints                    []scope.ServiceEndpoint
 	WatchFilterValue             string
 	ExternalResourceGC           bool
 	AlternativeGCStrategy        bool
 	TagUnmanagedNetworkResources bool
+	// AllowOrphanCleanup allows the deletion of the orphaned resources of the clusters annotated with
+	// the clean value of the scan-orphans annotation.
+	AllowOrphanCleanup bool
+	// Backoff, if set, delays the retries of failed reconciliations. Otherwise they are retried with the
+	// rate limiter of the controller.
+	Backoff *backoff.Tracker
 }
 
 // getEC2Service factory func is added for testing purpose so that we can inject mocked EC2Service to the AWSClusterReconciler.
@@ -105,6 +119,14 @@ func (r *AWSClusterReconciler) getNetworkService(scope scope.ClusterScope) servi
 	return network.NewService(&scope)
 }
 
+// getSpotInterruptionService factory func is added for testing purpose so that we can inject mocked SpotInterruptionService to the AWSClusterReconciler.
+func (r *AWSClusterReconciler) getSpotInterruptionService(scope scope.EC2Scope) services.SpotInterruptionInterface {
+	if r.spotInterruptionFactory != nil {
+		return r.spotInterruptionFactory(scope)
+	}
+	return spotinterruption.NewService(scope)
+}
+
 // securityGroupRolesForCluster returns the security group roles determined by the cluster configuration.
 func securityGroupRolesForCluster(scope scope.ClusterScope) []infrav1.SecurityGroupRole {
 	// Copy to ensure we do not modify the package-level variable.
@@ -130,8 +152,10 @@ func (r *AWSClusterReconciler) getSecurityGroupService(scope scope.ClusterScope)
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclusterroleidentities;awsclusterstaticidentities,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclustercontrolleridentities,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinesets;machinehealthchecks,verbs=get;list
 
-func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, reterr error) {
 	log := logger.FromContext(ctx)
 
 	// Fetch the AWSCluster instance
@@ -190,6 +214,20 @@ func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}()
 
+	// Back off before retrying a failed reconciliation. This runs before the scope is closed, so that the
+	// backoff is persisted in the conditions.
+	if r.Backoff != nil {
+		defer func() {
+			res, reterr = r.Backoff.Result(clusterScope, awsCluster, res, reterr)
+		}()
+	}
+
+	// The support bundle is collected whatever the state of the cluster, as it is mostly needed when it fails.
+	if err := supportbundle.NewService(clusterScope).ReconcileSupportBundle(); err != nil {
+		// non fatal error, so we continue
+		clusterScope.Error(err, "non-fatal: failed to collect support bundle")
+	}
+
 	// Handle deleted clusters
 	if !awsCluster.DeletionTimestamp.IsZero() {
 		return ctrl.Result{}, r.reconcileDelete(ctx, clusterScope)
@@ -213,7 +251,8 @@ func (r *AWSClusterReconciler) reconcileDelete(ctx context.Context, clusterScope
 	sgService := r.getSecurityGroupService(*clusterScope)
 	s3Service := s3.NewService(clusterScope)
 
-	if feature.Gates.Enabled(feature.EventBridgeInstanceState) {
+	// The rule and queue recorded in the status are deleted even if the feature was disabled since.
+	if instancestate.IsEnabled(clusterScope) || clusterScope.EventBridgeStatus() != nil {
 		instancestateSvc := instancestate.NewService(clusterScope)
 		if err := instancestateSvc.DeleteEC2Events(); err != nil {
 			// Not deleting the events isn't critical to cluster deletion
@@ -221,6 +260,12 @@ func (r *AWSClusterReconciler) reconcileDelete(ctx context.Context, clusterScope
 		}
 	}
 
+	// The rule and queue are deleted even if the feature was disabled since, as the pools don't outlive the cluster.
+	if err := r.getSpotInterruptionService(clusterScope).DeleteSpotInterruptionEvents(); err != nil {
+		// Not deleting the spot interruption notifications isn't critical to cluster deletion
+		clusterScope.Error(err, "non-fatal: failed to delete spot interruption notifications")
+	}
+
 	// In this context we try to delete all the resources that we know about,
 	// and run the garbage collector to delete any resources that were tagged, if enabled.
 	//
@@ -323,6 +368,10 @@ func (r *AWSClusterReconciler) reconcileNormal(clusterScope *scope.ClusterScope)
 	sgService := r.getSecurityGroupService(*clusterScope)
 	s3Service := s3.NewService(clusterScope)
 
+	// The quotas are checked before the infrastructure is provisioned, so that a lack of quota is
+	// reported before it makes the provisioning fail.
+	servicequotas.NewService(clusterScope).ReconcileQuotas()
+
 	if err := networkSvc.ReconcileNetwork(); err != nil {
 		clusterScope.Error(err, "failed to reconcile network")
 		return reconcile.Result{}, err
@@ -330,17 +379,24 @@ func (r *AWSClusterReconciler) reconcileNormal(clusterScope *scope.ClusterScope)
 
 	if err := sgService.ReconcileSecurityGroups(); err != nil {
 		clusterScope.Error(err, "failed to reconcile security groups")
-		conditions.MarkFalse(awsCluster, infrav1.ClusterSecurityGroupsReadyCondition, infrav1.ClusterSecurityGroupReconciliationFailedReason, infrautilconditions.ErrorConditionAfterInit(clusterScope.ClusterObj()), err.Error())
+		reason := infrav1.ClusterSecurityGroupReconciliationFailedReason
+		if securitygroup.IsRuleLimitExceeded(err) {
+			reason = infrav1.SecurityGroupRuleLimitExceededReason
+		}
+		conditions.MarkFalse(awsCluster, infrav1.ClusterSecurityGroupsReadyCondition, reason, infrautilconditions.ErrorConditionAfterInit(clusterScope.ClusterObj()), err.Error())
 		return reconcile.Result{}, err
 	}
 
+	r.reconcileSSHKey(clusterScope, ec2Service)
+
 	if err := ec2Service.ReconcileBastion(); err != nil {
 		conditions.MarkFalse(awsCluster, infrav1.BastionHostReadyCondition, infrav1.BastionHostFailedReason, infrautilconditions.ErrorConditionAfterInit(clusterScope.ClusterObj()), err.Error())
 		clusterScope.Error(err, "failed to reconcile bastion host")
 		return reconcile.Result{}, err
 	}
 
-	if feature.Gates.Enabled(feature.EventBridgeInstanceState) {
+	// The clusters which configure EventBridge manage their rule and queue regardless of the feature flag.
+	if feature.Gates.Enabled(feature.EventBridgeInstanceState) || clusterScope.EventBridge() != nil {
 		instancestateSvc := instancestate.NewService(clusterScope)
 		if err := instancestateSvc.ReconcileEC2Events(); err != nil {
 			// non fatal error, so we continue
@@ -359,6 +415,8 @@ func (r *AWSClusterReconciler) reconcileNormal(clusterScope *scope.ClusterScope)
 		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile S3 Bucket for AWSCluster %s/%s", awsCluster.Namespace, awsCluster.Name)
 	}
 
+	r.reconcileUserDataMigration(clusterScope)
+
 	for _, subnet := range clusterScope.Subnets().FilterPrivate() {
 		found := false
 		for _, az := range awsCluster.Status.Network.APIServerELB.AvailabilityZones {
@@ -373,15 +431,79 @@ func (r *AWSClusterReconciler) reconcileNormal(clusterScope *scope.ClusterScope)
 		})
 	}
 
+	if err := orphans.NewService(clusterScope, r.AllowOrphanCleanup).ReconcileOrphans(); err != nil {
+		// non fatal error, so we continue
+		clusterScope.Error(err, "non-fatal: failed to scan for orphaned resources")
+	}
+
 	awsCluster.Status.Ready = true
+
+	// The migration of the control plane load balancer runs one phase per reconciliation, and the updates of
+	// the status alone don't trigger one.
+	if migration := awsCluster.Status.ControlPlaneLoadBalancerMigration; migration != nil && migration.Phase != infrav1.LoadBalancerMigrationPhaseAborted {
+		return reconcile.Result{RequeueAfter: DefaultReconcilerRequeue}, nil
+	}
+	// The instance connect endpoint is polled until it is created or deleted.
+	if reason := conditions.GetReason(awsCluster, infrav1.InstanceConnectEndpointReadyCondition); reason == infrav1.InstanceConnectEndpointCreatingReason || reason == infrav1.InstanceConnectEndpointDeletingReason {
+		return reconcile.Result{RequeueAfter: DefaultReconcilerRequeue}, nil
+	}
 	return reconcile.Result{}, nil
 }
 
+// reconcileSSHKey reports in the SSHKeyAvailable condition whether the SSH key pair of the cluster exists in its
+// region, so that a missing key pair is reported before the instances using it fail to launch.
+func (r *AWSClusterReconciler) reconcileSSHKey(clusterScope *scope.ClusterScope, ec2Service services.EC2Interface) {
+	awsCluster := clusterScope.AWSCluster
+	if awsCluster.Spec.SSHKeyName == nil || *awsCluster.Spec.SSHKeyName == "" {
+		conditions.Delete(awsCluster, infrav1.SSHKeyAvailableCondition)
+		return
+	}
+
+	if err := ec2Service.CheckSSHKey(*awsCluster.Spec.SSHKeyName); err != nil {
+		// non fatal error, so we continue
+		clusterScope.Error(err, "non-fatal: failed to check SSH key pair")
+		reason := infrav1.SSHKeyCheckFailedReason
+		if ec2.IsSSHKeyNotFound(err) {
+			reason = infrav1.SSHKeyNotFoundReason
+		}
+		conditions.MarkFalse(awsCluster, infrav1.SSHKeyAvailableCondition, reason, clusterv1.ConditionSeverityWarning, err.Error())
+		return
+	}
+	conditions.MarkTrue(awsCluster, infrav1.SSHKeyAvailableCondition)
+}
+
+// reconcileUserDataMigration reports in the status of the AWSCluster how many of its AWSMachines still store their
+// bootstrap data in the instance user data while the cluster has an S3 bucket.
+func (r *AWSClusterReconciler) reconcileUserDataMigration(clusterScope *scope.ClusterScope) {
+	awsCluster := clusterScope.AWSCluster
+	if clusterScope.Bucket() == nil {
+		awsCluster.Status.UserDataMigrationPending = 0
+		conditions.Delete(awsCluster, infrav1.UserDataMigratedCondition)
+		return
+	}
+
+	pending, err := clusterScope.UserDataMigrationPending()
+	if err != nil {
+		// non fatal error, so we continue
+		clusterScope.Error(err, "non-fatal: failed to count the machines pending user data migration")
+		conditions.MarkUnknown(awsCluster, infrav1.UserDataMigratedCondition, infrav1.UserDataMigrationCheckFailedReason, err.Error())
+		return
+	}
+
+	awsCluster.Status.UserDataMigrationPending = pending
+	if pending > 0 {
+		conditions.MarkFalse(awsCluster, infrav1.UserDataMigratedCondition, infrav1.UserDataMigrationPendingReason, clusterv1.ConditionSeverityWarning,
+			"%d machines store their bootstrap data in the instance user data", pending)
+		return
+	}
+	conditions.MarkTrue(awsCluster, infrav1.UserDataMigratedCondition)
+}
+
 func (r *AWSClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	log := logger.FromContext(ctx)
 	controller, err := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(options).
-		For(&infrav1.AWSCluster{}).
+		For(&infrav1.AWSCluster{}, builder.WithPredicates(backoff.ConditionUnchanged())).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(log.GetLogger(), r.WatchFilterValue)).
 		WithEventFilter(
 			predicate.Funcs{
@@ -411,6 +533,27 @@ func (r *AWSClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 		return errors.Wrap(err, "error creating controller")
 	}
 
+	// The security group rules depend on the operating system of the AWSMachines, and the status reports the
+	// AWSMachines which need their user data migrated.
+	if err := controller.Watch(
+		source.Kind(mgr.GetCache(), &infrav1.AWSMachine{}),
+		handler.EnqueueRequestsFromMapFunc(r.clusterObjectToAWSCluster(log)),
+		predicate.Or(windowsMachineChanged(), userDataMigrationChanged()),
+	); err != nil {
+		return errors.Wrap(err, "failed adding a watch for AWSMachines")
+	}
+
+	if feature.Gates.Enabled(feature.MachinePool) {
+		// The security group rules depend on the load balancer attachments and the operating system of the AWSMachinePools.
+		if err := controller.Watch(
+			source.Kind(mgr.GetCache(), &expinfrav1.AWSMachinePool{}),
+			handler.EnqueueRequestsFromMapFunc(r.clusterObjectToAWSCluster(log)),
+			machinePoolSecurityGroupInputsChanged(),
+		); err != nil {
+			return errors.Wrap(err, "failed adding a watch for AWSMachinePools")
+		}
+	}
+
 	return controller.Watch(
 		source.Kind(mgr.GetCache(), &clusterv1.Cluster{}),
 		handler.EnqueueRequestsFromMapFunc(r.requeueAWSClusterForUnpausedCluster(ctx, log)),
@@ -418,6 +561,107 @@ func (r *AWSClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 	)
 }
 
+// clusterObjectToAWSCluster maps an object labeled with its cluster name, like an AWSMachine or an
+// AWSMachinePool, to the AWSCluster of its cluster.
+func (r *AWSClusterReconciler) clusterObjectToAWSCluster(log logger.Wrapper) handler.MapFunc {
+	return func(ctx context.Context, o client.Object) []ctrl.Request {
+		clusterName, ok := o.GetLabels()[clusterv1.ClusterNameLabel]
+		if !ok {
+			return nil
+		}
+
+		log := log.WithValues("objectMapper", "clusterObjectToAWSCluster", "object", klog.KObj(o))
+
+		cluster := &clusterv1.Cluster{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: o.GetNamespace(), Name: clusterName}, cluster); err != nil {
+			log.Trace("Failed to get owning cluster, skipping mapping.", "error", err.Error())
+			return nil
+		}
+
+		if cluster.Spec.InfrastructureRef == nil || cluster.Spec.InfrastructureRef.GroupVersionKind().Kind != "AWSCluster" {
+			log.Trace("Cluster does not have an AWSCluster InfrastructureRef, skipping mapping.")
+			return nil
+		}
+
+		return []ctrl.Request{
+			{
+				NamespacedName: client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.InfrastructureRef.Name},
+			},
+		}
+	}
+}
+
+// machinePoolSecurityGroupInputsChanged filters AWSMachinePool events to the ones changing load balancer
+// attachments or Windows nodes.
+func machinePoolSecurityGroupInputsChanged() predicate.Funcs {
+	affectsSecurityGroups := func(o client.Object) bool {
+		pool, ok := o.(*expinfrav1.AWSMachinePool)
+		return ok && (len(pool.Spec.LoadBalancerAttachments) > 0 || pool.Spec.OS == infrav1.OSTypeWindows)
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return affectsSecurityGroups(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPool, okOld := e.ObjectOld.(*expinfrav1.AWSMachinePool)
+			newPool, okNew := e.ObjectNew.(*expinfrav1.AWSMachinePool)
+			return okOld && okNew && (!cmp.Equal(oldPool.Spec.LoadBalancerAttachments, newPool.Spec.LoadBalancerAttachments) ||
+				oldPool.Spec.OS != newPool.Spec.OS)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return affectsSecurityGroups(e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// windowsMachineChanged filters AWSMachine events to the ones adding or removing Windows nodes.
+// The spec of an AWSMachine is immutable, so updates are ignored.
+func windowsMachineChanged() predicate.Funcs {
+	isWindows := func(o client.Object) bool {
+		machine, ok := o.(*infrav1.AWSMachine)
+		return ok && machine.Spec.OS == infrav1.OSTypeWindows
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isWindows(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return isWindows(e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// userDataMigrationChanged returns a predicate which fires when an AWSMachine gains or loses the
+// NeedsUserDataMigrationAnnotation, or is created or deleted with it.
+func userDataMigrationChanged() predicate.Funcs {
+	needsMigration := func(o client.Object) bool {
+		return o.GetAnnotations()[infrav1.NeedsUserDataMigrationAnnotation] == "true"
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return needsMigration(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return needsMigration(e.ObjectOld) != needsMigration(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return needsMigration(e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
 func (r *AWSClusterReconciler) requeueAWSClusterForUnpausedCluster(_ context.Context, log logger.Wrapper) handler.MapFunc {
 	return func(ctx context.Context, o client.Object) []ctrl.Request {
 		c, ok := o.(*clusterv1.Cluster)
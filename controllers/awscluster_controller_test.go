@@ -38,6 +38,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services"
 	ec2Service "sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/ec2"
 	elbService "sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/elb"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/mock_services"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/network"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/securitygroup"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/test/mocks"
@@ -57,9 +58,14 @@ func TestAWSClusterReconcilerIntegrationTests(t *testing.T) {
 		t.Helper()
 		mockCtrl = gomock.NewController(t)
 		recorder = record.NewFakeRecorder(10)
+		spotInterruptionSvc := mock_services.NewMockSpotInterruptionInterface(mockCtrl)
+		spotInterruptionSvc.EXPECT().DeleteSpotInterruptionEvents().Return(nil).AnyTimes()
 		reconciler = AWSClusterReconciler{
 			Client:   testEnv.Client,
 			Recorder: recorder,
+			spotInterruptionFactory: func(scope.EC2Scope) services.SpotInterruptionInterface {
+				return spotInterruptionSvc
+			},
 		}
 		ctx = context.TODO()
 	}
@@ -1182,30 +1188,10 @@ func mockedCallsForMissingEverything(m *mocks.MockEC2APIMockRecorder, e *mocks.M
 		},
 	})).Return(&ec2.DescribeAddressesOutput{
 		Addresses: []*ec2.Address{},
-	}, nil)
+	}, nil).Times(2)
 
-	m.AllocateAddressWithContext(context.TODO(), gomock.Eq(&ec2.AllocateAddressInput{
-		Domain: aws.String("vpc"),
-		TagSpecifications: []*ec2.TagSpecification{
-			{
-				ResourceType: aws.String("elastic-ip"),
-				Tags: []*ec2.Tag{
-					{
-						Key:   aws.String("Name"),
-						Value: aws.String("test-cluster-eip-apiserver"),
-					},
-					{
-						Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
-						Value: aws.String("owned"),
-					},
-					{
-						Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/role"),
-						Value: aws.String("apiserver"),
-					},
-				},
-			},
-		},
-	})).Return(&ec2.AllocateAddressOutput{
+	// The tags of the Elastic IP include the time of its allocation, which is covered by the tests of the network service.
+	m.AllocateAddressWithContext(context.TODO(), gomock.AssignableToTypeOf(&ec2.AllocateAddressInput{})).Return(&ec2.AllocateAddressOutput{
 		AllocationId: aws.String("1234"),
 	}, nil)
 
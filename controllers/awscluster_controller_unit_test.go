@@ -29,18 +29,21 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	utilfeature "k8s.io/component-base/featuregate/testing"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/mock_services"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/logger"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestAWSClusterReconcilerReconcile(t *testing.T) {
@@ -139,6 +142,7 @@ func TestAWSClusterReconcileOperations(t *testing.T) {
 		elbSvc     *mock_services.MockELBInterface
 		networkSvc *mock_services.MockNetworkInterface
 		sgSvc      *mock_services.MockSecurityGroupInterface
+		spotSvc    *mock_services.MockSpotInterruptionInterface
 		recorder   *record.FakeRecorder
 		ctx        context.Context
 	)
@@ -164,6 +168,7 @@ func TestAWSClusterReconcileOperations(t *testing.T) {
 		elbSvc = mock_services.NewMockELBInterface(mockCtrl)
 		networkSvc = mock_services.NewMockNetworkInterface(mockCtrl)
 		sgSvc = mock_services.NewMockSecurityGroupInterface(mockCtrl)
+		spotSvc = mock_services.NewMockSpotInterruptionInterface(mockCtrl)
 
 		recorder = record.NewFakeRecorder(2)
 
@@ -181,6 +186,9 @@ func TestAWSClusterReconcileOperations(t *testing.T) {
 			securityGroupFactory: func(clusterScope scope.ClusterScope) services.SecurityGroupInterface {
 				return sgSvc
 			},
+			spotInterruptionFactory: func(scope.EC2Scope) services.SpotInterruptionInterface {
+				return spotSvc
+			},
 			Recorder: recorder,
 		}
 		return csClient
@@ -397,6 +405,7 @@ func TestAWSClusterReconcileOperations(t *testing.T) {
 				elbSvc.EXPECT().DeleteLoadbalancers().Return(nil)
 				networkSvc.EXPECT().DeleteNetwork().Return(nil)
 				sgSvc.EXPECT().DeleteSecurityGroups().Return(nil)
+				spotSvc.EXPECT().DeleteSpotInterruptionEvents().Return(nil)
 			}
 			t.Run("Should successfully delete AWSCluster with Cluster Finalizer removed", func(t *testing.T) {
 				g := NewWithT(t)
@@ -417,6 +426,30 @@ func TestAWSClusterReconcileOperations(t *testing.T) {
 				g.Expect(err).To(BeNil())
 				g.Expect(awsCluster.GetFinalizers()).ToNot(ContainElement(infrav1.ClusterFinalizer))
 			})
+			t.Run("Should delete the spot interruption notifications with the feature gate disabled", func(t *testing.T) {
+				defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.SpotInterruptionHandling, false)()
+				g := NewWithT(t)
+				awsCluster := getAWSCluster("test", "test")
+				awsCluster.Finalizers = []string{infrav1.ClusterFinalizer}
+				csClient := setup(t, &awsCluster)
+				defer teardown()
+				ec2Svc.EXPECT().DeleteBastion().Return(nil)
+				elbSvc.EXPECT().DeleteLoadbalancers().Return(nil)
+				networkSvc.EXPECT().DeleteNetwork().Return(nil)
+				sgSvc.EXPECT().DeleteSecurityGroups().Return(nil)
+				spotSvc.EXPECT().DeleteSpotInterruptionEvents().Return(nil).Times(1)
+				cs, err := scope.NewClusterScope(
+					scope.ClusterScopeParams{
+						Client:     csClient,
+						Cluster:    &clusterv1.Cluster{},
+						AWSCluster: &awsCluster,
+					},
+				)
+				g.Expect(err).To(BeNil())
+				err = reconciler.reconcileDelete(ctx, cs)
+				g.Expect(err).To(BeNil())
+				g.Expect(awsCluster.GetFinalizers()).ToNot(ContainElement(infrav1.ClusterFinalizer))
+			})
 		})
 		t.Run("Reconcile failure", func(t *testing.T) {
 			expectedErr := errors.New("failed to get resource")
@@ -428,6 +461,7 @@ func TestAWSClusterReconcileOperations(t *testing.T) {
 					ec2Svc.EXPECT().DeleteBastion().Return(nil)
 					networkSvc.EXPECT().DeleteNetwork().Return(nil)
 					sgSvc.EXPECT().DeleteSecurityGroups().Return(nil)
+					spotSvc.EXPECT().DeleteSpotInterruptionEvents().Return(nil)
 				}
 				awsCluster := getAWSCluster("test", "test")
 				awsCluster.Finalizers = []string{infrav1.ClusterFinalizer}
@@ -453,6 +487,7 @@ func TestAWSClusterReconcileOperations(t *testing.T) {
 					elbSvc.EXPECT().DeleteLoadbalancers().Return(nil)
 					networkSvc.EXPECT().DeleteNetwork().Return(nil)
 					sgSvc.EXPECT().DeleteSecurityGroups().Return(nil)
+					spotSvc.EXPECT().DeleteSpotInterruptionEvents().Return(nil)
 				}
 				awsCluster := getAWSCluster("test", "test")
 				awsCluster.Finalizers = []string{infrav1.ClusterFinalizer}
@@ -477,6 +512,7 @@ func TestAWSClusterReconcileOperations(t *testing.T) {
 					ec2Svc.EXPECT().DeleteBastion().Return(nil)
 					elbSvc.EXPECT().DeleteLoadbalancers().Return(nil)
 					sgSvc.EXPECT().DeleteSecurityGroups().Return(expectedErr)
+					spotSvc.EXPECT().DeleteSpotInterruptionEvents().Return(nil)
 					networkSvc.EXPECT().DeleteNetwork().Return(nil)
 				}
 				awsCluster := getAWSCluster("test", "test")
@@ -502,6 +538,7 @@ func TestAWSClusterReconcileOperations(t *testing.T) {
 					ec2Svc.EXPECT().DeleteBastion().Return(nil)
 					elbSvc.EXPECT().DeleteLoadbalancers().Return(nil)
 					sgSvc.EXPECT().DeleteSecurityGroups().Return(nil)
+					spotSvc.EXPECT().DeleteSpotInterruptionEvents().Return(nil)
 					networkSvc.EXPECT().DeleteNetwork().Return(expectedErr)
 				}
 				awsCluster := getAWSCluster("test", "test")
@@ -672,3 +709,73 @@ func TestSecurityGroupRolesForCluster(t *testing.T) {
 		})
 	}
 }
+
+func TestAWSClusterReconcilerReconcileUserDataMigration(t *testing.T) {
+	machine := func(name string, needsMigration bool) *infrav1.AWSMachine {
+		m := &infrav1.AWSMachine{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "test"},
+		}}
+		if needsMigration {
+			m.Annotations = map[string]string{infrav1.NeedsUserDataMigrationAnnotation: "true"}
+		}
+		return m
+	}
+
+	testCases := []struct {
+		name            string
+		bucket          *infrav1.S3Bucket
+		machines        []client.Object
+		expectPending   int32
+		expectCondition *clusterv1.Condition
+	}{
+		{
+			name:     "should not report the migration without an S3 bucket",
+			machines: []client.Object{machine("inline", true)},
+		},
+		{
+			name:            "should count the machines which need their user data migrated",
+			bucket:          &infrav1.S3Bucket{Name: "bucket"},
+			machines:        []client.Object{machine("inline", true), machine("s3", false)},
+			expectPending:   1,
+			expectCondition: &clusterv1.Condition{Status: corev1.ConditionFalse, Severity: clusterv1.ConditionSeverityWarning, Reason: infrav1.UserDataMigrationPendingReason},
+		},
+		{
+			name:            "should mark the migration complete when no machine needs it",
+			bucket:          &infrav1.S3Bucket{Name: "bucket"},
+			machines:        []client.Object{machine("s3", false)},
+			expectCondition: &clusterv1.Condition{Status: corev1.ConditionTrue},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			awsCluster := &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec:       infrav1.AWSClusterSpec{S3Bucket: tc.bucket},
+			}
+			cs, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     fake.NewClientBuilder().WithObjects(tc.machines...).Build(),
+				Cluster:    &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}},
+				AWSCluster: awsCluster,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			reconciler := &AWSClusterReconciler{}
+			reconciler.reconcileUserDataMigration(cs)
+
+			g.Expect(awsCluster.Status.UserDataMigrationPending).To(Equal(tc.expectPending))
+			condition := conditions.Get(awsCluster, infrav1.UserDataMigratedCondition)
+			if tc.expectCondition == nil {
+				g.Expect(condition).To(BeNil())
+				return
+			}
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tc.expectCondition.Status))
+			g.Expect(condition.Severity).To(Equal(tc.expectCondition.Severity))
+			g.Expect(condition.Reason).To(Equal(tc.expectCondition.Reason))
+		})
+	}
+}
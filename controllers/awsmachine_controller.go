@@ -32,11 +32,13 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -49,6 +51,9 @@ import (
 	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/backoff"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/metrics"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/ec2"
@@ -88,6 +93,15 @@ type AWSMachineReconciler struct {
 	Endpoints                    []scope.ServiceEndpoint
 	WatchFilterValue             string
 	TagUnmanagedNetworkResources bool
+	// ReplaceMachinesWithExpiredBootstrapToken enables the termination of the instances still running without a node
+	// once their bootstrap token expired, so that their machines are replaced with machines with fresh bootstrap data.
+	ReplaceMachinesWithExpiredBootstrapToken bool
+	// BootstrapTokenTTL is the TTL of the kubeadm bootstrap tokens, used to estimate when the tokens which were already
+	// deleted from the workload cluster expired.
+	BootstrapTokenTTL time.Duration
+	// Backoff, if set, delays the retries of failed reconciliations. Otherwise they are retried with the
+	// rate limiter of the controller.
+	Backoff *backoff.Tracker
 }
 
 const (
@@ -151,7 +165,7 @@ func (r *AWSMachineReconciler) getObjectStoreService(scope scope.S3Scope) servic
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 
-func (r *AWSMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+func (r *AWSMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, reterr error) {
 	log := logger.FromContext(ctx)
 
 	// Fetch the AWSMachine instance.
@@ -199,6 +213,10 @@ func (r *AWSMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
+	// The metadata options of the cluster are the default of the machines which don't set their own.
+	if awsMachine.Spec.InstanceMetadataOptions == nil {
+		awsMachine.Spec.InstanceMetadataOptions = infraCluster.DefaultInstanceMetadataOptions().DeepCopy()
+	}
 	infrav1.SetDefaults_AWSMachineSpec(&awsMachine.Spec)
 
 	// Create the machine scope
@@ -221,6 +239,14 @@ func (r *AWSMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}()
 
+	// Back off before retrying a failed reconciliation. This runs before the scope is closed, so that the
+	// backoff is persisted in the conditions.
+	if r.Backoff != nil {
+		defer func() {
+			res, reterr = r.Backoff.Result(machineScope, awsMachine, res, reterr)
+		}()
+	}
+
 	switch infraScope := infraCluster.(type) {
 	case *scope.ManagedControlPlaneScope:
 		if !awsMachine.ObjectMeta.DeletionTimestamp.IsZero() {
@@ -245,7 +271,7 @@ func (r *AWSMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 
 	controller, err := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(options).
-		For(&infrav1.AWSMachine{}).
+		For(&infrav1.AWSMachine{}, builder.WithPredicates(backoff.ConditionUnchanged())).
 		Watches(
 			&clusterv1.Machine{},
 			handler.EnqueueRequestsFromMapFunc(util.MachineToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("AWSMachine"))),
@@ -344,7 +370,7 @@ func (r *AWSMachineReconciler) reconcileDelete(machineScope *scope.MachineScope,
 		conditions.MarkFalse(machineScope.AWSMachine, infrav1.ELBAttachedCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
 	}
 
-	if feature.Gates.Enabled(feature.EventBridgeInstanceState) {
+	if instancestate.IsEnabled(ec2Scope) {
 		instancestateSvc := instancestate.NewService(ec2Scope)
 		instancestateSvc.RemoveInstanceFromEventPattern(instance.ID)
 	}
@@ -450,7 +476,7 @@ func (r *AWSMachineReconciler) findInstance(machineScope *scope.MachineScope, ec
 	return instance, nil
 }
 
-func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *scope.MachineScope, clusterScope cloud.ClusterScoper, ec2Scope scope.EC2Scope, elbScope scope.ELBScope, objectStoreScope scope.S3Scope) (ctrl.Result, error) {
+func (r *AWSMachineReconciler) reconcileNormal(ctx context.Context, machineScope *scope.MachineScope, clusterScope cloud.ClusterScoper, ec2Scope scope.EC2Scope, elbScope scope.ELBScope, objectStoreScope scope.S3Scope) (ctrl.Result, error) {
 	machineScope.Trace("Reconciling AWSMachine")
 
 	// If the AWSMachine is in an error state, return early.
@@ -500,6 +526,16 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 
 	// Create new instance since providerId is nil and instance could not be found by tags.
 	if instance == nil {
+		// Don't launch the instance while its SSH key pair doesn't exist, as the launch would fail.
+		if err := r.reconcileSSHKey(ec2svc, machineScope); err != nil {
+			if ec2.IsSSHKeyNotFound(err) {
+				machineScope.Info("Waiting for the SSH key pair to exist before creating the instance", "reason", err.Error())
+				return ctrl.Result{RequeueAfter: ec2.SSHKeyCheckInterval}, nil
+			}
+			machineScope.Error(err, "unable to check SSH key pair")
+			return ctrl.Result{}, err
+		}
+
 		// Avoid a flickering condition between InstanceProvisionStarted and InstanceProvisionFailed if there's a persistent failure with createInstance
 		if conditions.GetReason(machineScope.AWSMachine, infrav1.InstanceReadyCondition) != infrav1.InstanceProvisionFailedReason {
 			conditions.MarkFalse(machineScope.AWSMachine, infrav1.InstanceReadyCondition, infrav1.InstanceProvisionStartedReason, clusterv1.ConditionSeverityInfo, "")
@@ -522,7 +558,7 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 			return ctrl.Result{}, err
 		}
 	}
-	if feature.Gates.Enabled(feature.EventBridgeInstanceState) {
+	if instancestate.IsEnabled(ec2Scope) {
 		instancestateSvc := instancestate.NewService(ec2Scope)
 		if err := instancestateSvc.AddInstanceToEventPattern(instance.ID); err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "failed to add instance to Event Bridge instance state rule")
@@ -557,6 +593,8 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 	case infrav1.InstanceStateRunning:
 		machineScope.SetReady()
 		conditions.MarkTrue(machineScope.AWSMachine, infrav1.InstanceReadyCondition)
+		status := &machineScope.AWSMachine.Status
+		markProvisionPhase(&status.InstanceRunningAt, status.InstanceRequestedAt, metrics.MachineProvisionPhaseInstanceRunning)
 	case infrav1.InstanceStateShuttingDown, infrav1.InstanceStateTerminated:
 		machineScope.SetNotReady()
 		machineScope.Info("Unexpected EC2 instance termination", "state", instance.State, "instance-id", *machineScope.GetInstanceID())
@@ -583,6 +621,7 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 	}
 
 	// tasks that can take place during all known instance states
+	var bootstrapTokenValidFor time.Duration
 	if machineScope.InstanceIsInKnownState() {
 		_, err = r.ensureTags(ec2svc, machineScope.AWSMachine, machineScope.GetInstanceID(), machineScope.AdditionalTags())
 		if err != nil {
@@ -594,6 +633,21 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 			r.ensureStorageTags(ec2svc, instance, machineScope.AWSMachine, machineScope.AdditionalTags())
 		}
 
+		if feature.Gates.Enabled(feature.UserDataDriftDetection) && instance != nil {
+			r.reconcileUserDataDrift(ec2svc, machineScope, instance)
+		}
+
+		if instance != nil {
+			r.reconcileUserDataDelivery(machineScope, instance, objectStoreScope)
+		}
+
+		if instance != nil && instance.State == infrav1.InstanceStateRunning {
+			bootstrapTokenValidFor, err = r.reconcileBootstrapToken(ctx, ec2svc, machineScope, instance, time.Now())
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
 		if err := r.reconcileLBAttachment(machineScope, elbScope, instance); err != nil {
 			machineScope.Error(err, "failed to reconcile LB attachment")
 			return ctrl.Result{}, err
@@ -613,11 +667,19 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 		machineScope.Debug("but find the instance is pending, requeue", "instance", instance.ID)
 		return ctrl.Result{RequeueAfter: DefaultReconcilerRequeue}, nil
 	}
+	if bootstrapTokenValidFor > 0 {
+		// The machine is checked again once its bootstrap token expires, in case it hasn't joined the cluster by then.
+		return ctrl.Result{RequeueAfter: bootstrapTokenValidFor}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
 func (r *AWSMachineReconciler) reconcileOperationalState(ec2svc services.EC2Interface, machineScope *scope.MachineScope, instance *infrav1.Instance) error {
 	machineScope.SetAddresses(instance.Addresses)
+	if len(instance.Addresses) > 0 {
+		status := &machineScope.AWSMachine.Status
+		markProvisionPhase(&status.AddressesAssignedAt, status.InstanceRunningAt, metrics.MachineProvisionPhaseAddresses)
+	}
 
 	existingSecurityGroups, err := ec2svc.GetInstanceSecurityGroups(*machineScope.GetInstanceID())
 	if err != nil {
@@ -683,15 +745,62 @@ func (r *AWSMachineReconciler) createInstance(ec2svc services.EC2Interface, mach
 	if userDataErr != nil {
 		return nil, errors.Wrapf(userDataErr, "failed to resolve userdata")
 	}
+	status := &machineScope.AWSMachine.Status
+	markProvisionPhase(&status.BootstrapDataRenderedAt, &machineScope.AWSMachine.CreationTimestamp, metrics.MachineProvisionPhaseBootstrapData)
+	// The delivery is recorded before the instance is created, so that the EC2 service tags the instance with it.
+	status.UserDataDelivery = userDataDelivery(machineScope, userDataFormat)
 
 	instance, err := ec2svc.CreateInstance(machineScope, userData, userDataFormat)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create AWSMachine instance")
 	}
+	markProvisionPhase(&status.InstanceRequestedAt, status.BootstrapDataRenderedAt, metrics.MachineProvisionPhaseInstanceRequest)
 
 	return instance, nil
 }
 
+// reconcileSSHKey reports in the SSHKeyAvailable condition whether the SSH key pair the instance of the machine
+// is launched with exists. It returns an SSHKeyNotFoundError while the key pair doesn't exist, unless the check
+// is skipped with the SkipSSHKeyCheckAnnotation on the AWSMachine or its AWSCluster.
+func (r *AWSMachineReconciler) reconcileSSHKey(ec2svc services.EC2Interface, machineScope *scope.MachineScope) error {
+	keyName := ptr.Deref(machineScope.SSHKeyName(), "")
+	if keyName == "" || skipSSHKeyCheck(machineScope) {
+		conditions.Delete(machineScope.AWSMachine, infrav1.SSHKeyAvailableCondition)
+		return nil
+	}
+
+	if err := ec2svc.CheckSSHKey(keyName); err != nil {
+		reason := infrav1.SSHKeyCheckFailedReason
+		if ec2.IsSSHKeyNotFound(err) {
+			reason = infrav1.SSHKeyNotFoundReason
+			conditions.MarkFalse(machineScope.AWSMachine, infrav1.InstanceReadyCondition, reason, clusterv1.ConditionSeverityWarning, err.Error())
+		}
+		conditions.MarkFalse(machineScope.AWSMachine, infrav1.SSHKeyAvailableCondition, reason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+	conditions.MarkTrue(machineScope.AWSMachine, infrav1.SSHKeyAvailableCondition)
+	return nil
+}
+
+// skipSSHKeyCheck returns true if the SkipSSHKeyCheckAnnotation is set on the AWSMachine or its AWSCluster.
+func skipSSHKeyCheck(machineScope *scope.MachineScope) bool {
+	return machineScope.AWSMachine.Annotations[infrav1.SkipSSHKeyCheckAnnotation] == "true" ||
+		machineScope.InfraCluster.InfraCluster().GetAnnotations()[infrav1.SkipSSHKeyCheckAnnotation] == "true"
+}
+
+// markProvisionPhase records the completion time of a provisioning phase of an AWSMachine, and observes the
+// duration of the phase since the completion of the previous one. The completion time is only recorded once,
+// and only when the previous phase completed after the timestamps started being recorded, so that the machines
+// provisioned before don't skew the metrics.
+func markProvisionPhase(completedAt **metav1.Time, previousCompletedAt *metav1.Time, phase string) {
+	if *completedAt != nil || previousCompletedAt.IsZero() {
+		return
+	}
+	now := metav1.Now()
+	*completedAt = &now
+	metrics.ObserveMachineProvisionPhase(phase, now.Sub(previousCompletedAt.Time))
+}
+
 func (r *AWSMachineReconciler) resolveUserData(machineScope *scope.MachineScope, clusterScope cloud.ClusterScoper, objectStoreSvc services.ObjectStoreInterface) ([]byte, string, error) {
 	userData, userDataFormat, err := machineScope.GetRawBootstrapDataWithFormat()
 	if err != nil {
@@ -699,19 +808,17 @@ func (r *AWSMachineReconciler) resolveUserData(machineScope *scope.MachineScope,
 		return nil, "", err
 	}
 
+	if feature.Gates.Enabled(feature.UserDataTransform) && machineScope.AWSMachine.Spec.UserDataTransform != nil {
+		userData, err = r.transformUserData(machineScope, objectStoreSvc, userData, userDataFormat)
+		return userData, userDataFormat, err
+	}
+
 	if machineScope.UseSecretsManager(userDataFormat) {
 		userData, err = r.cloudInitUserData(machineScope, clusterScope, userData)
 	}
 
 	if machineScope.UseIgnition(userDataFormat) {
-		var ignitionStorageType infrav1.IgnitionStorageTypeOption
-		if machineScope.AWSMachine.Spec.Ignition == nil {
-			ignitionStorageType = infrav1.IgnitionStorageTypeOptionClusterObjectStore
-		} else {
-			ignitionStorageType = machineScope.AWSMachine.Spec.Ignition.StorageType
-		}
-
-		switch ignitionStorageType {
+		switch ignitionStorageType := getIgnitionStorageType(machineScope); ignitionStorageType {
 		case infrav1.IgnitionStorageTypeOptionClusterObjectStore:
 			userData, err = r.generateIgnitionWithRemoteStorage(machineScope, objectStoreSvc, userData)
 		case infrav1.IgnitionStorageTypeOptionUnencryptedUserData:
@@ -724,6 +831,33 @@ func (r *AWSMachineReconciler) resolveUserData(machineScope *scope.MachineScope,
 	return userData, userDataFormat, err
 }
 
+// transformUserData renders the user data transform of the AWSMachine, whose output replaces the user data CAPA
+// would generate otherwise. Ignition bootstrap data is still stored in the S3 bucket of the cluster, unless the
+// storage type is UnencryptedUserData, so that the template can reference its URL.
+func (r *AWSMachineReconciler) transformUserData(machineScope *scope.MachineScope, objectStoreSvc services.ObjectStoreInterface, userData []byte, userDataFormat string) ([]byte, error) {
+	var s3URL string
+	if machineScope.UseIgnition(userDataFormat) && getIgnitionStorageType(machineScope) == infrav1.IgnitionStorageTypeOptionClusterObjectStore {
+		if objectStoreSvc == nil {
+			return nil, errors.New("storing the Ignition bootstrap data requires a cluster wide object storage configured at `AWSCluster.Spec.Ignition.S3Bucket`")
+		}
+		var err error
+		s3URL, err = objectStoreSvc.Create(machineScope, userData)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating userdata object")
+		}
+	}
+
+	transformed, err := machineScope.TransformUserData(userData, s3URL)
+	if err != nil {
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedUserDataTransform", err.Error())
+		conditions.MarkFalse(machineScope.AWSMachine, infrav1.UserDataTransformReadyCondition, infrav1.UserDataTransformFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return nil, err
+	}
+	conditions.MarkTrue(machineScope.AWSMachine, infrav1.UserDataTransformReadyCondition)
+
+	return transformed, nil
+}
+
 func (r *AWSMachineReconciler) cloudInitUserData(machineScope *scope.MachineScope, clusterScope cloud.ClusterScoper, userData []byte) ([]byte, error) {
 	secretSvc, secretBackendErr := r.getSecretService(machineScope, clusterScope)
 	if secretBackendErr != nil {
@@ -832,6 +966,37 @@ func (r *AWSMachineReconciler) generateIgnitionWithRemoteStorage(scope *scope.Ma
 	}
 }
 
+// userDataDelivery returns the mechanism through which the instance of the machine receives its bootstrap data,
+// following the choices made by resolveUserData. An empty format is resolved from the spec of the AWSMachine only.
+func userDataDelivery(machineScope *scope.MachineScope, userDataFormat string) infrav1.UserDataDelivery {
+	if feature.Gates.Enabled(feature.UserDataTransform) && machineScope.AWSMachine.Spec.UserDataTransform != nil {
+		// The output of the transform is the user data, which only references the bootstrap data when it is stored in S3.
+		if machineScope.UseIgnition(userDataFormat) && getIgnitionStorageType(machineScope) == infrav1.IgnitionStorageTypeOptionClusterObjectStore {
+			return infrav1.UserDataDeliveryS3
+		}
+		return infrav1.UserDataDeliveryInline
+	}
+
+	switch {
+	case machineScope.UseSecretsManager(userDataFormat):
+		if machineScope.SecureSecretsBackend() == infrav1.SecretBackendSSMParameterStore {
+			return infrav1.UserDataDeliverySSMParameterStore
+		}
+		return infrav1.UserDataDeliverySecretsManager
+	case machineScope.UseIgnition(userDataFormat) && getIgnitionStorageType(machineScope) == infrav1.IgnitionStorageTypeOptionClusterObjectStore:
+		return infrav1.UserDataDeliveryS3
+	default:
+		return infrav1.UserDataDeliveryInline
+	}
+}
+
+func getIgnitionStorageType(scope *scope.MachineScope) infrav1.IgnitionStorageTypeOption {
+	if scope.AWSMachine.Spec.Ignition == nil {
+		return infrav1.IgnitionStorageTypeOptionClusterObjectStore
+	}
+	return scope.AWSMachine.Spec.Ignition.StorageType
+}
+
 func getIgnitionVersion(scope *scope.MachineScope) string {
 	if scope.AWSMachine.Spec.Ignition == nil {
 		scope.AWSMachine.Spec.Ignition = &infrav1.Ignition{}
@@ -909,6 +1074,12 @@ func (r *AWSMachineReconciler) reconcileLBAttachment(machineScope *scope.Machine
 		if lbSpec == nil {
 			continue
 		}
+		// While the control plane load balancer is migrated to a network load balancer, the instances keep being
+		// registered with the classic load balancer, whose instances the migration registers with the new one.
+		if lbSpec == elbScope.ControlPlaneLoadBalancer() && elbScope.ControlPlaneLoadBalancerMigration() != nil {
+			lbSpec = lbSpec.DeepCopy()
+			lbSpec.LoadBalancerType = infrav1.LoadBalancerTypeClassic
+		}
 		// In order to prevent sending request to a "not-ready" control plane machines, it is required to remove the machine
 		// from the ELB as soon as the machine or infra machine gets deleted or when the machine is in a not running state.
 		if machineScope.AWSMachineIsDeleted() || machineScope.MachineIsDeleted() || !machineScope.InstanceIsRunning() {
@@ -1223,6 +1394,128 @@ func (r *AWSMachineReconciler) ensureStorageTags(ec2svc services.EC2Interface, i
 	}
 }
 
+// reconcileUserDataDrift compares the hash of the instance user data with the hash tagged on the instance at launch,
+// and reports a drift through the UserDataDrifted condition. The drift is only detected, replacing the machine is left
+// to the usual Cluster API remediation.
+func (r *AWSMachineReconciler) reconcileUserDataDrift(ec2svc services.EC2Interface, machineScope *scope.MachineScope, instance *infrav1.Instance) {
+	expectedHash, ok := instance.Tags[infrav1.InstanceUserDataHashTagKey]
+	if !ok {
+		machineScope.Debug("Instance was not launched with a user data hash tag, skipping user data drift detection", "instance-id", instance.ID)
+		return
+	}
+
+	actualHash, err := ec2svc.GetInstanceUserDataHash(instance.ID)
+	if err != nil {
+		if awserrors.IsPermissionsError(errors.Cause(err)) {
+			machineScope.Debug("Unable to retrieve the instance user data, skipping user data drift detection", "instance-id", instance.ID, "reason", err.Error())
+			return
+		}
+		machineScope.Error(err, "failed to retrieve the instance user data", "instance-id", instance.ID)
+		return
+	}
+
+	if actualHash == expectedHash {
+		conditions.MarkFalse(machineScope.AWSMachine, infrav1.UserDataDriftedCondition, infrav1.UserDataHashMatchReason, clusterv1.ConditionSeverityNone, "")
+		return
+	}
+
+	if !conditions.IsTrue(machineScope.AWSMachine, infrav1.UserDataDriftedCondition) {
+		machineScope.Info("Instance user data differs from the user data it was launched with", "instance-id", instance.ID, "expected-hash", expectedHash, "actual-hash", actualHash)
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "UserDataDrifted", "User data of instance %s differs from the user data it was launched with", instance.ID)
+		metrics.RecordUserDataDrift(machineScope.Namespace(), machineScope.Cluster.Name)
+	}
+	conditions.Set(machineScope.AWSMachine, &clusterv1.Condition{
+		Type:    infrav1.UserDataDriftedCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.UserDataHashMismatchReason,
+		Message: fmt.Sprintf("user data hash %s doesn't match the hash %s the instance was launched with", actualHash, expectedHash),
+	})
+}
+
+// reconcileBootstrapToken sets the BootstrapTokenLikelyExpiredCondition of a machine whose instance is running without
+// a node after the bootstrap token embedded in its bootstrap data expired, e.g. because the instance stayed pending for
+// longer than the token TTL. Such an instance can't join the cluster anymore. When ReplaceMachinesWithExpiredBootstrapToken
+// is set, the instance is terminated and the machine marked as failed, so that it is replaced by a machine with fresh
+// bootstrap data. It returns how long the token is still valid for, if the machine is waiting for its node.
+func (r *AWSMachineReconciler) reconcileBootstrapToken(ctx context.Context, ec2svc services.EC2Interface, machineScope *scope.MachineScope, instance *infrav1.Instance, now time.Time) (time.Duration, error) {
+	if machineScope.Machine.Status.NodeRef != nil || machineScope.HasFailed() {
+		conditions.Delete(machineScope.AWSMachine, infrav1.BootstrapTokenLikelyExpiredCondition)
+		return 0, nil
+	}
+
+	expiresAt, err := machineScope.GetBootstrapTokenExpiry(ctx, r.BootstrapTokenTTL)
+	if err != nil {
+		// The expiry is only informative, so the reconciliation isn't held back when it can't be read.
+		machineScope.Error(err, "failed to get the expiry of the bootstrap token")
+		return 0, nil
+	}
+	if expiresAt == nil {
+		return 0, nil
+	}
+
+	if validFor := expiresAt.Sub(now); validFor > 0 {
+		conditions.MarkFalse(machineScope.AWSMachine, infrav1.BootstrapTokenLikelyExpiredCondition, infrav1.BootstrapTokenValidReason, clusterv1.ConditionSeverityNone, "")
+		return validFor, nil
+	}
+
+	if !conditions.IsTrue(machineScope.AWSMachine, infrav1.BootstrapTokenLikelyExpiredCondition) {
+		machineScope.Info("Bootstrap token expired before the instance joined the cluster", "instance-id", instance.ID, "expired-at", expiresAt)
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "BootstrapTokenLikelyExpired", "Bootstrap token expired at %s before instance %s joined the cluster", expiresAt.Format(time.RFC3339), instance.ID)
+	}
+	conditions.Set(machineScope.AWSMachine, &clusterv1.Condition{
+		Type:    infrav1.BootstrapTokenLikelyExpiredCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.BootstrapTokenExpiredReason,
+		Message: fmt.Sprintf("bootstrap token expired at %s before the instance joined the cluster", expiresAt.Format(time.RFC3339)),
+	})
+
+	if !r.ReplaceMachinesWithExpiredBootstrapToken {
+		return 0, nil
+	}
+	machineScope.Info("Terminating instance with an expired bootstrap token", "instance-id", instance.ID)
+	if err := ec2svc.TerminateInstance(instance.ID); err != nil {
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedTerminate", "Failed to terminate instance %s with an expired bootstrap token: %v", instance.ID, err)
+		return 0, errors.Wrap(err, "failed to terminate instance with an expired bootstrap token")
+	}
+	r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "SuccessfulTerminate", "Terminated instance %s with an expired bootstrap token", instance.ID)
+	machineScope.SetFailureReason(capierrors.CreateMachineError)
+	machineScope.SetFailureMessage(errors.Errorf("instance %s was terminated because its bootstrap token expired before it joined the cluster", instance.ID))
+	return 0, nil
+}
+
+// reconcileUserDataDelivery records in the status how the instance received its bootstrap data, and sets the
+// NeedsUserDataMigrationAnnotation on the AWSMachine while the bootstrap data is stored in the instance user data
+// although the cluster has an S3 bucket. The annotation is removed once it no longer applies.
+func (r *AWSMachineReconciler) reconcileUserDataDelivery(machineScope *scope.MachineScope, instance *infrav1.Instance, objectStoreScope scope.S3Scope) {
+	awsMachine := machineScope.AWSMachine
+	if awsMachine.Status.UserDataDelivery == "" {
+		// The delivery is read from the tags of the instance when the status was lost, and inferred from the spec of
+		// the AWSMachine for the instances launched before it was recorded.
+		if delivery, ok := instance.Tags[infrav1.InstanceUserDataDeliveryTagKey]; ok {
+			awsMachine.Status.UserDataDelivery = infrav1.UserDataDelivery(delivery)
+		} else {
+			awsMachine.Status.UserDataDelivery = userDataDelivery(machineScope, "")
+		}
+	}
+
+	needsMigration := awsMachine.Status.UserDataDelivery == infrav1.UserDataDeliveryInline &&
+		objectStoreScope != nil && objectStoreScope.Bucket() != nil
+	if !needsMigration {
+		delete(awsMachine.Annotations, infrav1.NeedsUserDataMigrationAnnotation)
+		return
+	}
+
+	if awsMachine.Annotations[infrav1.NeedsUserDataMigrationAnnotation] == "true" {
+		return
+	}
+	machineScope.Info("Instance stores its bootstrap data in the instance user data, the machine needs to be replaced", "instance-id", instance.ID)
+	r.Recorder.Eventf(awsMachine, corev1.EventTypeWarning, "UserDataMigrationNeeded", "Instance %s stores its bootstrap data in the instance user data", instance.ID)
+	if awsMachine.Annotations == nil {
+		awsMachine.Annotations = map[string]string{}
+	}
+	awsMachine.Annotations[infrav1.NeedsUserDataMigrationAnnotation] = "true"
+}
+
 func (r *AWSMachineReconciler) ensureInstanceMetadataOptions(ec2svc services.EC2Interface, instance *infrav1.Instance, machine *infrav1.AWSMachine) error {
 	if cmp.Equal(machine.Spec.InstanceMetadataOptions, instance.InstanceMetadataOptions) {
 		return nil
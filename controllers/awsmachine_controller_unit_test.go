@@ -44,6 +44,8 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/metrics"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services"
 	ec2Service "sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/ec2"
@@ -337,6 +339,46 @@ func TestAWSMachineReconciler(t *testing.T) {
 			g.Expect(err.Error()).To(ContainSubstring(expectedErr))
 		})
 
+		t.Run("when the SSH key pair doesn't exist", func(t *testing.T) {
+			t.Run("should not create the instance until the SSH key pair exists", func(t *testing.T) {
+				g := NewWithT(t)
+				awsMachine := getAWSMachine()
+				setup(t, g, awsMachine)
+				defer teardown(t, g)
+
+				ms.AWSMachine.Spec.SSHKeyName = aws.String("my-key")
+				ec2Svc.EXPECT().GetRunningInstanceByTags(gomock.Any()).Return(nil, nil)
+				ec2Svc.EXPECT().CheckSSHKey("my-key").Return(&ec2Service.SSHKeyNotFoundError{KeyName: "my-key", Region: "us-east-1"})
+
+				res, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs, cs, cs)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(res.RequeueAfter).To(Equal(ec2Service.SSHKeyCheckInterval))
+				expectConditions(g, ms.AWSMachine, []conditionAssertion{
+					{infrav1.SSHKeyAvailableCondition, corev1.ConditionFalse, clusterv1.ConditionSeverityWarning, infrav1.SSHKeyNotFoundReason},
+					{infrav1.InstanceReadyCondition, corev1.ConditionFalse, clusterv1.ConditionSeverityWarning, infrav1.SSHKeyNotFoundReason},
+				})
+			})
+
+			t.Run("should create the instance when the SSH key check is skipped", func(t *testing.T) {
+				g := NewWithT(t)
+				awsMachine := getAWSMachine()
+				setup(t, g, awsMachine)
+				defer teardown(t, g)
+
+				ms.AWSMachine.Spec.SSHKeyName = aws.String("my-key")
+				ms.AWSMachine.Annotations = map[string]string{infrav1.SkipSSHKeyCheckAnnotation: "true"}
+				expectedErr := errors.New("Invalid instance")
+				ec2Svc.EXPECT().GetRunningInstanceByTags(gomock.Any()).Return(nil, nil)
+				secretSvc.EXPECT().Create(gomock.Any(), gomock.Any()).Return("test", int32(1), nil).Times(1)
+				secretSvc.EXPECT().UserData(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				ec2Svc.EXPECT().CreateInstance(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, expectedErr)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs, cs, cs)
+				g.Expect(errors.Cause(err)).To(MatchError(expectedErr))
+				g.Expect(ms.AWSMachine.GetConditions()).NotTo(ContainElement(HaveField("Type", infrav1.SSHKeyAvailableCondition)))
+			})
+		})
+
 		t.Run("when instance creation succeeds", func(t *testing.T) {
 			var instance *infrav1.Instance
 
@@ -2235,6 +2277,122 @@ func TestAWSMachineReconcilerIndexAWSMachineByInstanceID(t *testing.T) {
 	})
 }
 
+func TestAWSMachineReconcilerReconcileUserDataDrift(t *testing.T) {
+	testCases := []struct {
+		name            string
+		tags            infrav1.Tags
+		existing        *clusterv1.Condition
+		expect          func(m *mock_services.MockEC2InterfaceMockRecorder)
+		expectCondition *conditionAssertion
+		expectEvent     bool
+	}{
+		{
+			name: "should skip instances launched without a user data hash tag",
+			tags: infrav1.Tags{},
+		},
+		{
+			name: "should mark the condition false when the user data matches",
+			tags: infrav1.Tags{infrav1.InstanceUserDataHashTagKey: "hash"},
+			expect: func(m *mock_services.MockEC2InterfaceMockRecorder) {
+				m.GetInstanceUserDataHash("i-1234").Return("hash", nil)
+			},
+			expectCondition: &conditionAssertion{infrav1.UserDataDriftedCondition, corev1.ConditionFalse, "", infrav1.UserDataHashMatchReason},
+		},
+		{
+			name: "should mark the condition true and emit an event when the user data drifted",
+			tags: infrav1.Tags{infrav1.InstanceUserDataHashTagKey: "hash"},
+			expect: func(m *mock_services.MockEC2InterfaceMockRecorder) {
+				m.GetInstanceUserDataHash("i-1234").Return("other-hash", nil)
+			},
+			expectCondition: &conditionAssertion{infrav1.UserDataDriftedCondition, corev1.ConditionTrue, "", infrav1.UserDataHashMismatchReason},
+			expectEvent:     true,
+		},
+		{
+			name:     "should not emit an event again when the drift was already reported",
+			tags:     infrav1.Tags{infrav1.InstanceUserDataHashTagKey: "hash"},
+			existing: &clusterv1.Condition{Type: infrav1.UserDataDriftedCondition, Status: corev1.ConditionTrue, Reason: infrav1.UserDataHashMismatchReason},
+			expect: func(m *mock_services.MockEC2InterfaceMockRecorder) {
+				m.GetInstanceUserDataHash("i-1234").Return("other-hash", nil)
+			},
+			expectCondition: &conditionAssertion{infrav1.UserDataDriftedCondition, corev1.ConditionTrue, "", infrav1.UserDataHashMismatchReason},
+		},
+		{
+			name: "should leave the condition untouched when the user data can't be retrieved",
+			tags: infrav1.Tags{infrav1.InstanceUserDataHashTagKey: "hash"},
+			expect: func(m *mock_services.MockEC2InterfaceMockRecorder) {
+				m.GetInstanceUserDataHash("i-1234").Return("", awserrors.NewFailedDependency("dependency failure"))
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			ec2Svc := mock_services.NewMockEC2Interface(mockCtrl)
+			if tc.expect != nil {
+				tc.expect(ec2Svc.EXPECT())
+			}
+			recorder := record.NewFakeRecorder(1)
+			reconciler := &AWSMachineReconciler{Recorder: recorder, Log: klog.Background()}
+
+			awsMachine := &infrav1.AWSMachine{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+			if tc.existing != nil {
+				awsMachine.Status.Conditions = clusterv1.Conditions{*tc.existing}
+			}
+			cs, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     fake.NewClientBuilder().Build(),
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			ms, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:       fake.NewClientBuilder().WithObjects(awsMachine).Build(),
+				Cluster:      &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}},
+				Machine:      &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}},
+				InfraCluster: cs,
+				AWSMachine:   awsMachine,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			reconciler.reconcileUserDataDrift(ec2Svc, ms, &infrav1.Instance{ID: "i-1234", Tags: tc.tags})
+
+			if tc.expectCondition != nil {
+				expectConditions(g, ms.AWSMachine, []conditionAssertion{*tc.expectCondition})
+			} else {
+				g.Expect(ms.AWSMachine.Status.Conditions).To(BeEmpty())
+			}
+			if tc.expectEvent {
+				g.Expect(recorder.Events).To(Receive(ContainSubstring("UserDataDrifted")))
+			} else {
+				g.Expect(recorder.Events).NotTo(Receive())
+			}
+		})
+	}
+}
+
+func TestMarkProvisionPhase(t *testing.T) {
+	g := NewWithT(t)
+	createdAt := metav1.NewTime(time.Now().Add(-time.Minute))
+	status := &infrav1.AWSMachineStatus{}
+
+	// A phase isn't recorded until the previous one completed.
+	markProvisionPhase(&status.InstanceRunningAt, status.InstanceRequestedAt, metrics.MachineProvisionPhaseInstanceRunning)
+	g.Expect(status.InstanceRunningAt).To(BeNil())
+
+	markProvisionPhase(&status.BootstrapDataRenderedAt, &createdAt, metrics.MachineProvisionPhaseBootstrapData)
+	g.Expect(status.BootstrapDataRenderedAt).NotTo(BeNil())
+	g.Expect(status.BootstrapDataRenderedAt.After(createdAt.Time)).To(BeTrue())
+
+	// A phase is recorded only once.
+	renderedAt := status.BootstrapDataRenderedAt
+	markProvisionPhase(&status.BootstrapDataRenderedAt, &createdAt, metrics.MachineProvisionPhaseBootstrapData)
+	g.Expect(status.BootstrapDataRenderedAt).To(BeIdenticalTo(renderedAt))
+
+	markProvisionPhase(&status.InstanceRequestedAt, status.BootstrapDataRenderedAt, metrics.MachineProvisionPhaseInstanceRequest)
+	g.Expect(status.InstanceRequestedAt).NotTo(BeNil())
+}
+
 func TestAWSMachineReconcilerReconcile(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -2745,3 +2903,241 @@ func cleanupObject(g *WithT, obj client.Object) {
 		g.Expect(testEnv.Cleanup(ctx, obj)).To(Succeed())
 	}
 }
+
+func TestAWSMachineReconcilerReconcileUserDataDelivery(t *testing.T) {
+	testCases := []struct {
+		name             string
+		spec             infrav1.AWSMachineSpec
+		delivery         infrav1.UserDataDelivery
+		tags             infrav1.Tags
+		annotations      map[string]string
+		bucket           *infrav1.S3Bucket
+		expectDelivery   infrav1.UserDataDelivery
+		expectAnnotation bool
+		expectEvent      bool
+	}{
+		{
+			name:           "should infer the secrets backend of cloud-init machines",
+			spec:           infrav1.AWSMachineSpec{CloudInit: infrav1.CloudInit{SecureSecretsBackend: infrav1.SecretBackendSSMParameterStore}},
+			bucket:         &infrav1.S3Bucket{Name: "bucket"},
+			expectDelivery: infrav1.UserDataDeliverySSMParameterStore,
+		},
+		{
+			name:           "should infer the S3 bucket of Ignition machines",
+			spec:           infrav1.AWSMachineSpec{Ignition: &infrav1.Ignition{StorageType: infrav1.IgnitionStorageTypeOptionClusterObjectStore}},
+			bucket:         &infrav1.S3Bucket{Name: "bucket"},
+			expectDelivery: infrav1.UserDataDeliveryS3,
+		},
+		{
+			name:           "should read the delivery from the tags of the instance",
+			tags:           infrav1.Tags{infrav1.InstanceUserDataDeliveryTagKey: string(infrav1.UserDataDeliveryS3)},
+			bucket:         &infrav1.S3Bucket{Name: "bucket"},
+			expectDelivery: infrav1.UserDataDeliveryS3,
+		},
+		{
+			name:             "should mark the machines storing the bootstrap data in the user data when the cluster has an S3 bucket",
+			spec:             infrav1.AWSMachineSpec{Ignition: &infrav1.Ignition{StorageType: infrav1.IgnitionStorageTypeOptionUnencryptedUserData}},
+			bucket:           &infrav1.S3Bucket{Name: "bucket"},
+			expectDelivery:   infrav1.UserDataDeliveryInline,
+			expectAnnotation: true,
+			expectEvent:      true,
+		},
+		{
+			name:             "should not emit an event again when the machine is already marked",
+			delivery:         infrav1.UserDataDeliveryInline,
+			annotations:      map[string]string{infrav1.NeedsUserDataMigrationAnnotation: "true"},
+			bucket:           &infrav1.S3Bucket{Name: "bucket"},
+			expectDelivery:   infrav1.UserDataDeliveryInline,
+			expectAnnotation: true,
+		},
+		{
+			name:           "should not mark the machines when the cluster has no S3 bucket",
+			spec:           infrav1.AWSMachineSpec{CloudInit: infrav1.CloudInit{InsecureSkipSecretsManager: true}},
+			expectDelivery: infrav1.UserDataDeliveryInline,
+		},
+		{
+			name:           "should remove the annotation once the machine no longer needs the migration",
+			delivery:       infrav1.UserDataDeliverySecretsManager,
+			annotations:    map[string]string{infrav1.NeedsUserDataMigrationAnnotation: "true"},
+			bucket:         &infrav1.S3Bucket{Name: "bucket"},
+			expectDelivery: infrav1.UserDataDeliverySecretsManager,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			recorder := record.NewFakeRecorder(1)
+			reconciler := &AWSMachineReconciler{Recorder: recorder, Log: klog.Background()}
+
+			awsMachine := &infrav1.AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Annotations: tc.annotations},
+				Spec:       tc.spec,
+				Status:     infrav1.AWSMachineStatus{UserDataDelivery: tc.delivery},
+			}
+			cs, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     fake.NewClientBuilder().Build(),
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{ObjectMeta: metav1.ObjectMeta{Name: "test"}, Spec: infrav1.AWSClusterSpec{S3Bucket: tc.bucket}},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			ms, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:       fake.NewClientBuilder().WithObjects(awsMachine).Build(),
+				Cluster:      &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}},
+				Machine:      &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}},
+				InfraCluster: cs,
+				AWSMachine:   awsMachine,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			reconciler.reconcileUserDataDelivery(ms, &infrav1.Instance{ID: "i-1234", Tags: tc.tags}, cs)
+
+			g.Expect(ms.AWSMachine.Status.UserDataDelivery).To(Equal(tc.expectDelivery))
+			if tc.expectAnnotation {
+				g.Expect(ms.AWSMachine.Annotations).To(HaveKeyWithValue(infrav1.NeedsUserDataMigrationAnnotation, "true"))
+			} else {
+				g.Expect(ms.AWSMachine.Annotations).NotTo(HaveKey(infrav1.NeedsUserDataMigrationAnnotation))
+			}
+			if tc.expectEvent {
+				g.Expect(recorder.Events).To(Receive(ContainSubstring("UserDataMigrationNeeded")))
+			} else {
+				g.Expect(recorder.Events).NotTo(Receive())
+			}
+		})
+	}
+}
+
+func TestAWSMachineReconcilerReconcileBootstrapToken(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := func(d time.Duration) map[string]string {
+		return map[string]string{infrav1.BootstrapTokenExpiresAtAnnotation: now.Add(d).Format(time.RFC3339)}
+	}
+
+	testCases := []struct {
+		name              string
+		secretAnnotations map[string]string
+		nodeRef           *corev1.ObjectReference
+		existing          *clusterv1.Condition
+		replace           bool
+		expect            func(m *mock_services.MockEC2InterfaceMockRecorder)
+		expectValidFor    time.Duration
+		expectCondition   *conditionAssertion
+		expectEvents      []string
+		expectFailed      bool
+		expectErr         bool
+	}{
+		{
+			name: "should skip the bootstrap data secrets without an expiry",
+		},
+		{
+			name:              "should skip the machines which joined the cluster",
+			secretAnnotations: expiresAt(-time.Hour),
+			nodeRef:           &corev1.ObjectReference{Name: "node"},
+		},
+		{
+			name:              "should wait for the expiry of the bootstrap token",
+			secretAnnotations: expiresAt(10 * time.Minute),
+			expectValidFor:    10 * time.Minute,
+			expectCondition:   &conditionAssertion{infrav1.BootstrapTokenLikelyExpiredCondition, corev1.ConditionFalse, "", infrav1.BootstrapTokenValidReason},
+		},
+		{
+			name:              "should mark the condition true and emit an event once the bootstrap token expired",
+			secretAnnotations: expiresAt(-time.Second),
+			expectCondition:   &conditionAssertion{infrav1.BootstrapTokenLikelyExpiredCondition, corev1.ConditionTrue, "", infrav1.BootstrapTokenExpiredReason},
+			expectEvents:      []string{"BootstrapTokenLikelyExpired"},
+		},
+		{
+			name:              "should not emit an event again when the expiry was already reported",
+			secretAnnotations: expiresAt(-time.Hour),
+			existing:          &clusterv1.Condition{Type: infrav1.BootstrapTokenLikelyExpiredCondition, Status: corev1.ConditionTrue, Reason: infrav1.BootstrapTokenExpiredReason},
+			expectCondition:   &conditionAssertion{infrav1.BootstrapTokenLikelyExpiredCondition, corev1.ConditionTrue, "", infrav1.BootstrapTokenExpiredReason},
+		},
+		{
+			name:              "should terminate the instance when the replacement is enabled",
+			secretAnnotations: expiresAt(-time.Hour),
+			replace:           true,
+			expect: func(m *mock_services.MockEC2InterfaceMockRecorder) {
+				m.TerminateInstance("i-1234").Return(nil)
+			},
+			expectCondition: &conditionAssertion{infrav1.BootstrapTokenLikelyExpiredCondition, corev1.ConditionTrue, "", infrav1.BootstrapTokenExpiredReason},
+			expectEvents:    []string{"BootstrapTokenLikelyExpired", "SuccessfulTerminate"},
+			expectFailed:    true,
+		},
+		{
+			name:              "should not terminate the instance before the bootstrap token expired",
+			secretAnnotations: expiresAt(time.Minute),
+			replace:           true,
+			expectValidFor:    time.Minute,
+			expectCondition:   &conditionAssertion{infrav1.BootstrapTokenLikelyExpiredCondition, corev1.ConditionFalse, "", infrav1.BootstrapTokenValidReason},
+		},
+		{
+			name:              "should return an error when the instance can't be terminated",
+			secretAnnotations: expiresAt(-time.Hour),
+			replace:           true,
+			expect: func(m *mock_services.MockEC2InterfaceMockRecorder) {
+				m.TerminateInstance("i-1234").Return(errors.New("error"))
+			},
+			expectCondition: &conditionAssertion{infrav1.BootstrapTokenLikelyExpiredCondition, corev1.ConditionTrue, "", infrav1.BootstrapTokenExpiredReason},
+			expectEvents:    []string{"BootstrapTokenLikelyExpired", "FailedTerminate"},
+			expectErr:       true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			ec2Svc := mock_services.NewMockEC2Interface(mockCtrl)
+			if tc.expect != nil {
+				tc.expect(ec2Svc.EXPECT())
+			}
+			recorder := record.NewFakeRecorder(2)
+			reconciler := &AWSMachineReconciler{Recorder: recorder, Log: klog.Background(), ReplaceMachinesWithExpiredBootstrapToken: tc.replace}
+
+			awsMachine := &infrav1.AWSMachine{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+			if tc.existing != nil {
+				awsMachine.Status.Conditions = clusterv1.Conditions{*tc.existing}
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-data", Namespace: "default", Annotations: tc.secretAnnotations},
+				Data:       map[string][]byte{"value": []byte("user data")},
+			}
+			cs, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     fake.NewClientBuilder().Build(),
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			ms, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:  fake.NewClientBuilder().WithObjects(awsMachine, secret).Build(),
+				Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+					Spec:       clusterv1.MachineSpec{Bootstrap: clusterv1.Bootstrap{DataSecretName: ptr.To("bootstrap-data")}},
+					Status:     clusterv1.MachineStatus{NodeRef: tc.nodeRef},
+				},
+				InfraCluster: cs,
+				AWSMachine:   awsMachine,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			validFor, err := reconciler.reconcileBootstrapToken(context.TODO(), ec2Svc, ms, &infrav1.Instance{ID: "i-1234"}, now)
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+
+			g.Expect(validFor).To(Equal(tc.expectValidFor))
+			if tc.expectCondition != nil {
+				expectConditions(g, ms.AWSMachine, []conditionAssertion{*tc.expectCondition})
+			} else {
+				g.Expect(ms.AWSMachine.Status.Conditions).To(BeEmpty())
+			}
+			for _, event := range tc.expectEvents {
+				g.Expect(recorder.Events).To(Receive(ContainSubstring(event)))
+			}
+			g.Expect(recorder.Events).NotTo(Receive())
+			g.Expect(ms.HasFailed()).To(Equal(tc.expectFailed))
+		})
+	}
+}
@@ -40,6 +40,18 @@ func (r *AWSManagedControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	}
 	dst.Spec.VpcCni.Disable = r.Spec.DisableVPCCNI
 	dst.Spec.Partition = restored.Spec.Partition
+	dst.Spec.DefaultLifecycleHooks = restored.Spec.DefaultLifecycleHooks
+	dst.Spec.AutoMode = restored.Spec.AutoMode
+	dst.Spec.CSISupport = restored.Spec.CSISupport
+	dst.Spec.ECRAccess = restored.Spec.ECRAccess
+	dst.Status.AutoMode = restored.Status.AutoMode
+	dst.Status.CSIDriverRoles = restored.Status.CSIDriverRoles
+	dst.Status.ClusterStatus = restored.Status.ClusterStatus
+	dst.Status.Version = restored.Status.Version
+	dst.Status.ECRAccess = restored.Status.ECRAccess
+	if restored.Spec.Logging != nil && dst.Spec.Logging != nil {
+		dst.Spec.Logging.DeleteLogGroupOnDestroy = restored.Spec.Logging.DeleteLogGroupOnDestroy
+	}
 
 	return nil
 }
@@ -112,7 +124,17 @@ func Convert_v1beta2_VpcCni_To_v1beta1_VpcCni(in *ekscontrolplanev1.VpcCni, out
 	return autoConvert_v1beta2_VpcCni_To_v1beta1_VpcCni(in, out, s)
 }
 
+// Convert_v1beta2_ControlPlaneLoggingSpec_To_v1beta1_ControlPlaneLoggingSpec is a conversion function.
+func Convert_v1beta2_ControlPlaneLoggingSpec_To_v1beta1_ControlPlaneLoggingSpec(in *ekscontrolplanev1.ControlPlaneLoggingSpec, out *ControlPlaneLoggingSpec, s apiconversion.Scope) error {
+	return autoConvert_v1beta2_ControlPlaneLoggingSpec_To_v1beta1_ControlPlaneLoggingSpec(in, out, s)
+}
+
 // Convert_v1beta2_AWSManagedControlPlaneSpec_To_v1beta1_AWSManagedControlPlaneSpec is a generated conversion function
 func Convert_v1beta2_AWSManagedControlPlaneSpec_To_v1beta1_AWSManagedControlPlaneSpec(in *ekscontrolplanev1.AWSManagedControlPlaneSpec, out *AWSManagedControlPlaneSpec, scope apiconversion.Scope) error {
 	return autoConvert_v1beta2_AWSManagedControlPlaneSpec_To_v1beta1_AWSManagedControlPlaneSpec(in, out, scope)
 }
+
+// Convert_v1beta2_AWSManagedControlPlaneStatus_To_v1beta1_AWSManagedControlPlaneStatus is a conversion function.
+func Convert_v1beta2_AWSManagedControlPlaneStatus_To_v1beta1_AWSManagedControlPlaneStatus(in *ekscontrolplanev1.AWSManagedControlPlaneStatus, out *AWSManagedControlPlaneStatus, scope apiconversion.Scope) error {
+	return autoConvert_v1beta2_AWSManagedControlPlaneStatus_To_v1beta1_AWSManagedControlPlaneStatus(in, out, scope)
+}
@@ -143,6 +143,13 @@ type AWSManagedControlPlaneSpec struct { //nolint: maligned
 	// different ImageLookupBaseOS.
 	ImageLookupBaseOS string `json:"imageLookupBaseOS,omitempty"`
 
+	// DefaultLifecycleHooks are lifecycle hooks added to the Auto Scaling groups of all the
+	// AWSMachinePools of the cluster. A lifecycle hook of an AWSMachinePool overrides the
+	// default lifecycle hook with the same name. Removing a default lifecycle hook deletes it
+	// from the Auto Scaling groups that don't define it themselves.
+	// +optional
+	DefaultLifecycleHooks []infrav1.AWSLifecycleHook `json:"defaultLifecycleHooks,omitempty"`
+
 	// Bastion contains options to configure the bastion host.
 	// +optional
 	Bastion infrav1.Bastion `json:"bastion"`
@@ -175,6 +182,22 @@ type AWSManagedControlPlaneSpec struct { //nolint: maligned
 
 	// KubeProxy defines managed attributes of the kube-proxy daemonset
 	KubeProxy KubeProxy `json:"kubeProxy,omitempty"`
+
+	// AutoMode configures EKS Auto Mode. When enabled, the control plane can't own managed
+	// node groups or Fargate profiles.
+	// +optional
+	AutoMode *AutoMode `json:"autoMode,omitempty"`
+
+	// CSISupport configures the IAM permissions of the Amazon EBS and EFS CSI drivers, which
+	// are granted either to the roles of the managed node groups or to IAM roles for the service
+	// accounts of the drivers.
+	// +optional
+	CSISupport *CSISupport `json:"csiSupport,omitempty"`
+
+	// ECRAccess grants the roles of the managed node groups created by CAPA access to pull
+	// images from the ECR registries of other AWS accounts.
+	// +optional
+	ECRAccess *ECRAccess `json:"ecrAccess,omitempty"`
 }
 
 // KubeProxy specifies how the kube-proxy daemonset is managed.
@@ -279,6 +302,23 @@ type AWSManagedControlPlaneStatus struct {
 	// associated identity provider
 	// +optional
 	IdentityProviderStatus IdentityProviderStatus `json:"identityProviderStatus,omitempty"`
+	// AutoMode is the state of EKS Auto Mode as reported by EKS. It is only set when
+	// the cluster uses or used EKS Auto Mode.
+	// +optional
+	AutoMode *AutoModeStatus `json:"autoMode,omitempty"`
+	// CSIDriverRoles holds the IAM roles created for the service accounts of the CSI drivers
+	// when CSISupport uses the irsa mode.
+	// +optional
+	CSIDriverRoles *CSIDriverRolesStatus `json:"csiDriverRoles,omitempty"`
+	// ClusterStatus is the status of the EKS cluster, e.g. ACTIVE or UPDATING.
+	// +optional
+	ClusterStatus string `json:"clusterStatus,omitempty"`
+	// Version is the Kubernetes version of the EKS cluster, as reported by EKS.
+	// +optional
+	Version *string `json:"version,omitempty"`
+	// ECRAccess holds the principals granted access to the ECR registries of ECRAccess.
+	// +optional
+	ECRAccess *ECRAccessStatus `json:"ecrAccess,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -288,6 +328,10 @@ type AWSManagedControlPlaneStatus struct {
 // +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this AWSManagedControl belongs"
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Control plane infrastructure is ready for worker nodes"
 // +kubebuilder:printcolumn:name="VPC",type="string",JSONPath=".spec.network.vpc.id",description="AWS VPC the control plane is using"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.clusterStatus",description="Status of the EKS cluster"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="Kubernetes version of the EKS cluster"
+// +kubebuilder:printcolumn:name="Public Access",type="boolean",JSONPath=".spec.endpointAccess.public",description="Whether the API server endpoint is public"
+// +kubebuilder:printcolumn:name="Private Access",type="boolean",JSONPath=".spec.endpointAccess.private",description="Whether the API server endpoint is private"
 // +kubebuilder:printcolumn:name="Endpoint",type="string",JSONPath=".spec.controlPlaneEndpoint.host",description="API Endpoint",priority=1
 // +kubebuilder:printcolumn:name="Bastion IP",type="string",JSONPath=".status.bastion.publicIp",description="Bastion IP address for breakglass access"
 
@@ -19,6 +19,7 @@ package v1beta2
 import (
 	"fmt"
 	"net"
+	"regexp"
 
 	"github.com/apparentlymart/go-cidr/cidr"
 	"github.com/pkg/errors"
@@ -36,16 +37,19 @@ import (
 )
 
 const (
-	minAddonVersion          = "v1.18.0"
-	minKubeVersionForIPv6    = "v1.21.0"
-	minVpcCniVersionForIPv6  = "1.10.2"
-	maxClusterNameLength     = 100
-	hostnameTypeResourceName = "resource-name"
+	minAddonVersion           = "v1.18.0"
+	minKubeVersionForIPv6     = "v1.21.0"
+	minVpcCniVersionForIPv6   = "1.10.2"
+	minKubeVersionForAutoMode = "v1.29.0"
+	maxClusterNameLength      = 100
+	hostnameTypeResourceName  = "resource-name"
 )
 
 // log is for logging in this package.
 var mcpLog = ctrl.Log.WithName("awsmanagedcontrolplane-resource")
 
+var awsAccountIDRegex = regexp.MustCompile(`^[0-9]{12}$`)
+
 const (
 	cidrSizeMax    = 65536
 	cidrSizeMin    = 16
@@ -95,6 +99,10 @@ func (r *AWSManagedControlPlane) ValidateCreate() (admission.Warnings, error) {
 	allErrs = append(allErrs, r.Spec.AdditionalTags.Validate()...)
 	allErrs = append(allErrs, r.validateNetwork()...)
 	allErrs = append(allErrs, r.validatePrivateDNSHostnameTypeOnLaunch()...)
+	allErrs = append(allErrs, infrav1.ValidateLifecycleHooks(field.NewPath("spec", "defaultLifecycleHooks"), r.Spec.DefaultLifecycleHooks, r.Spec.Region)...)
+	allErrs = append(allErrs, r.validateAutoMode(nil)...)
+	allErrs = append(allErrs, r.validateCSISupport()...)
+	allErrs = append(allErrs, r.validateECRAccess()...)
 
 	if len(allErrs) == 0 {
 		return nil, nil
@@ -129,6 +137,10 @@ func (r *AWSManagedControlPlane) ValidateUpdate(old runtime.Object) (admission.W
 	allErrs = append(allErrs, r.validateKubeProxy()...)
 	allErrs = append(allErrs, r.Spec.AdditionalTags.Validate()...)
 	allErrs = append(allErrs, r.validatePrivateDNSHostnameTypeOnLaunch()...)
+	allErrs = append(allErrs, infrav1.ValidateLifecycleHooks(field.NewPath("spec", "defaultLifecycleHooks"), r.Spec.DefaultLifecycleHooks, r.Spec.Region)...)
+	allErrs = append(allErrs, r.validateAutoMode(oldAWSManagedControlplane)...)
+	allErrs = append(allErrs, r.validateCSISupport()...)
+	allErrs = append(allErrs, r.validateECRAccess()...)
 
 	if r.Spec.Region != oldAWSManagedControlplane.Spec.Region {
 		allErrs = append(allErrs,
@@ -429,6 +441,94 @@ func (r *AWSManagedControlPlane) validateNetwork() field.ErrorList {
 	return allErrs
 }
 
+// validateAutoMode validates the EKS Auto Mode configuration. EKS Auto Mode requires the cluster to authenticate
+// with access entries, which the clusters created without it don't, so it can only be set on creation.
+func (r *AWSManagedControlPlane) validateAutoMode(old *AWSManagedControlPlane) field.ErrorList {
+	var allErrs field.ErrorList
+	autoModePath := field.NewPath("spec", "autoMode")
+	autoMode := r.Spec.AutoMode
+
+	if old != nil && old.Spec.AutoMode.IsEnabled() != autoMode.IsEnabled() {
+		allErrs = append(allErrs, field.Invalid(autoModePath.Child("enabled"), autoMode.IsEnabled(), "EKS Auto Mode can only be set when the cluster is created"))
+	}
+
+	if autoMode == nil {
+		return allErrs
+	}
+
+	if !autoMode.Enabled {
+		if len(autoMode.NodePools) > 0 || autoMode.NodeRoleARN != "" {
+			allErrs = append(allErrs, field.Invalid(autoModePath.Child("enabled"), autoMode.Enabled, "nodePools and nodeRoleARN require EKS Auto Mode to be enabled"))
+		}
+		return allErrs
+	}
+
+	if r.Spec.Version != nil {
+		v, err := parseEKSVersion(*r.Spec.Version)
+		minVersion, _ := version.ParseSemantic(minKubeVersionForAutoMode)
+		if err == nil && v.LessThan(minVersion) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "version"), *r.Spec.Version, fmt.Sprintf("EKS Auto Mode requires Kubernetes %s or greater", minKubeVersionForAutoMode)))
+		}
+	}
+
+	nodePools := map[AutoModeNodePool]bool{}
+	for i, nodePool := range autoMode.NodePools {
+		if nodePools[nodePool] {
+			allErrs = append(allErrs, field.Duplicate(autoModePath.Child("nodePools").Index(i), nodePool))
+		}
+		nodePools[nodePool] = true
+	}
+
+	if len(autoMode.NodePools) > 0 && autoMode.NodeRoleARN == "" {
+		allErrs = append(allErrs, field.Required(autoModePath.Child("nodeRoleARN"), "nodeRoleARN is required when nodePools are enabled"))
+	}
+
+	if old != nil && old.Spec.AutoMode != nil && old.Spec.AutoMode.NodeRoleARN != "" && old.Spec.AutoMode.NodeRoleARN != autoMode.NodeRoleARN {
+		allErrs = append(allErrs, field.Invalid(autoModePath.Child("nodeRoleARN"), autoMode.NodeRoleARN, "field is immutable once set"))
+	}
+
+	return allErrs
+}
+
+// validateCSISupport validates the IAM permissions of the CSI drivers. The irsa mode trusts the OIDC
+// provider of the cluster, which CAPA only creates when associateOIDCProvider is set.
+func (r *AWSManagedControlPlane) validateCSISupport() field.ErrorList {
+	var allErrs field.ErrorList
+	csiPath := field.NewPath("spec", "csiSupport")
+
+	if r.Spec.AssociateOIDCProvider {
+		return allErrs
+	}
+	if r.Spec.CSISupport.GetEBSDriverPolicy() == CSIDriverPolicyModeIRSA {
+		allErrs = append(allErrs, field.Invalid(csiPath.Child("ebsDriverPolicy"), CSIDriverPolicyModeIRSA, "irsa requires associateOIDCProvider to be enabled"))
+	}
+	if r.Spec.CSISupport.GetEFSDriverPolicy() == CSIDriverPolicyModeIRSA {
+		allErrs = append(allErrs, field.Invalid(csiPath.Child("efsDriverPolicy"), CSIDriverPolicyModeIRSA, "irsa requires associateOIDCProvider to be enabled"))
+	}
+
+	return allErrs
+}
+
+// validateECRAccess validates the IDs of the accounts owning the ECR registries the nodes are granted
+// access to.
+func (r *AWSManagedControlPlane) validateECRAccess() field.ErrorList {
+	var allErrs field.ErrorList
+	accountIDsPath := field.NewPath("spec", "ecrAccess", "registryAccountIDs")
+
+	seen := map[string]bool{}
+	for i, accountID := range r.Spec.ECRAccess.GetRegistryAccountIDs() {
+		if !awsAccountIDRegex.MatchString(accountID) {
+			allErrs = append(allErrs, field.Invalid(accountIDsPath.Index(i), accountID, "must be a 12 digit AWS account ID"))
+		}
+		if seen[accountID] {
+			allErrs = append(allErrs, field.Duplicate(accountIDsPath.Index(i), accountID))
+		}
+		seen[accountID] = true
+	}
+
+	return allErrs
+}
+
 // Default will set default values for the AWSManagedControlPlane.
 func (r *AWSManagedControlPlane) Default() {
 	mcpLog.Info("AWSManagedControlPlane setting defaults", "control-plane", klog.KObj(r))
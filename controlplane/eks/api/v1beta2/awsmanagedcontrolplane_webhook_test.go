@@ -866,3 +866,204 @@ func TestValidatingWebhookUpdateSecondaryCidr(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatingWebhookAutoMode(t *testing.T) {
+	generalPurpose := &AutoMode{
+		Enabled:     true,
+		NodePools:   []AutoModeNodePool{AutoModeNodePoolGeneralPurpose},
+		NodeRoleARN: "arn:aws:iam::123456789012:role/auto-mode-node",
+	}
+
+	tests := []struct {
+		name        string
+		version     string
+		old         *AutoMode
+		autoMode    *AutoMode
+		expectError bool
+	}{
+		{
+			name:     "enabled with node pools",
+			version:  "v1.30",
+			autoMode: generalPurpose,
+		},
+		{
+			name:        "enabled on a Kubernetes version which doesn't support it",
+			version:     "v1.28",
+			autoMode:    generalPurpose,
+			expectError: true,
+		},
+		{
+			name:        "node pools without a node role",
+			version:     "v1.30",
+			autoMode:    &AutoMode{Enabled: true, NodePools: []AutoModeNodePool{AutoModeNodePoolSystem}},
+			expectError: true,
+		},
+		{
+			name:        "duplicate node pools",
+			version:     "v1.30",
+			autoMode:    &AutoMode{Enabled: true, NodePools: []AutoModeNodePool{AutoModeNodePoolSystem, AutoModeNodePoolSystem}, NodeRoleARN: generalPurpose.NodeRoleARN},
+			expectError: true,
+		},
+		{
+			name:        "node pools while disabled",
+			version:     "v1.30",
+			autoMode:    &AutoMode{NodePools: []AutoModeNodePool{AutoModeNodePoolSystem}},
+			expectError: true,
+		},
+		{
+			name:        "enabled on an existing cluster",
+			version:     "v1.30",
+			old:         &AutoMode{},
+			autoMode:    generalPurpose,
+			expectError: true,
+		},
+		{
+			name:        "disabled on an existing cluster",
+			version:     "v1.30",
+			old:         generalPurpose,
+			autoMode:    nil,
+			expectError: true,
+		},
+		{
+			name:     "node pools changed on an existing cluster",
+			version:  "v1.30",
+			old:      generalPurpose,
+			autoMode: &AutoMode{Enabled: true, NodePools: []AutoModeNodePool{AutoModeNodePoolGeneralPurpose, AutoModeNodePoolSystem}, NodeRoleARN: generalPurpose.NodeRoleARN},
+		},
+		{
+			name:        "node role changed on an existing cluster",
+			version:     "v1.30",
+			old:         generalPurpose,
+			autoMode:    &AutoMode{Enabled: true, NodePools: generalPurpose.NodePools, NodeRoleARN: "arn:aws:iam::123456789012:role/other"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mcp := &AWSManagedControlPlane{
+				Spec: AWSManagedControlPlaneSpec{
+					EKSClusterName: "default_cluster1",
+					Version:        aws.String(tc.version),
+					AutoMode:       tc.autoMode,
+				},
+			}
+			var err error
+			if tc.old != nil {
+				old := mcp.DeepCopy()
+				old.Spec.AutoMode = tc.old
+				_, err = mcp.ValidateUpdate(old)
+			} else {
+				_, err = mcp.ValidateCreate()
+			}
+
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestValidatingWebhookCSISupport(t *testing.T) {
+	tests := []struct {
+		name                  string
+		associateOIDCProvider bool
+		csiSupport            *CSISupport
+		expectError           bool
+	}{
+		{
+			name:       "node role without the OIDC provider",
+			csiSupport: &CSISupport{EBSDriverPolicy: CSIDriverPolicyModeAttachToNodeRole, EFSDriverPolicy: CSIDriverPolicyModeAttachToNodeRole},
+		},
+		{
+			name:                  "irsa with the OIDC provider",
+			associateOIDCProvider: true,
+			csiSupport:            &CSISupport{EBSDriverPolicy: CSIDriverPolicyModeIRSA, EFSDriverPolicy: CSIDriverPolicyModeIRSA},
+		},
+		{
+			name:        "ebs irsa without the OIDC provider",
+			csiSupport:  &CSISupport{EBSDriverPolicy: CSIDriverPolicyModeIRSA},
+			expectError: true,
+		},
+		{
+			name:        "efs irsa without the OIDC provider",
+			csiSupport:  &CSISupport{EBSDriverPolicy: CSIDriverPolicyModeAttachToNodeRole, EFSDriverPolicy: CSIDriverPolicyModeIRSA},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mcp := &AWSManagedControlPlane{
+				Spec: AWSManagedControlPlaneSpec{
+					EKSClusterName:        "default_cluster1",
+					AssociateOIDCProvider: tc.associateOIDCProvider,
+					CSISupport:            tc.csiSupport,
+				},
+			}
+			_, err := mcp.ValidateCreate()
+
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestValidatingWebhookECRAccess(t *testing.T) {
+	tests := []struct {
+		name        string
+		ecrAccess   *ECRAccess
+		expectError bool
+	}{
+		{
+			name: "no ECR access",
+		},
+		{
+			name:      "valid account IDs",
+			ecrAccess: &ECRAccess{RegistryAccountIDs: []string{"123456789012", "210987654321"}},
+		},
+		{
+			name:      "no account IDs",
+			ecrAccess: &ECRAccess{},
+		},
+		{
+			name:        "invalid account ID",
+			ecrAccess:   &ECRAccess{RegistryAccountIDs: []string{"12345"}},
+			expectError: true,
+		},
+		{
+			name:        "duplicate account ID",
+			ecrAccess:   &ECRAccess{RegistryAccountIDs: []string{"123456789012", "123456789012"}},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mcp := &AWSManagedControlPlane{
+				Spec: AWSManagedControlPlaneSpec{
+					EKSClusterName: "default_cluster1",
+					ECRAccess:      tc.ecrAccess,
+				},
+			}
+			_, err := mcp.ValidateCreate()
+
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
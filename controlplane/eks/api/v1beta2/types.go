@@ -43,6 +43,10 @@ type ControlPlaneLoggingSpec struct {
 	// Scheduler indicates if the Kubernetes scheduler (kube-scheduler) log should be enabled
 	// +kubebuilder:default=false
 	Scheduler bool `json:"scheduler"`
+	// DeleteLogGroupOnDestroy indicates if the CloudWatch log group of the EKS cluster
+	// (/aws/eks/<cluster>/cluster) should be deleted when the cluster is deleted
+	// +optional
+	DeleteLogGroupOnDestroy bool `json:"deleteLogGroupOnDestroy,omitempty"`
 }
 
 // IsLogEnabled returns true if the log is enabled.
@@ -279,3 +283,136 @@ type OIDCIdentityProviderConfig struct {
 	// +optional
 	Tags infrav1.Tags `json:"tags,omitempty"`
 }
+
+// AutoModeNodePool is a built-in node pool of EKS Auto Mode.
+// +kubebuilder:validation:Enum=general-purpose;system
+type AutoModeNodePool string
+
+var (
+	// AutoModeNodePoolGeneralPurpose is the built-in node pool for general purpose workloads.
+	AutoModeNodePoolGeneralPurpose = AutoModeNodePool("general-purpose")
+
+	// AutoModeNodePoolSystem is the built-in node pool for the critical add-ons, which tolerates
+	// only the pods with the CriticalAddonsOnly toleration.
+	AutoModeNodePoolSystem = AutoModeNodePool("system")
+)
+
+// AutoMode configures EKS Auto Mode, in which EKS manages the compute, the load balancing and
+// the block storage of the cluster.
+type AutoMode struct {
+	// Enabled turns on EKS Auto Mode. It can only be set when the cluster is created, and requires
+	// Kubernetes 1.29 or greater.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// NodePools are the built-in node pools of EKS Auto Mode to enable.
+	// +optional
+	NodePools []AutoModeNodePool `json:"nodePools,omitempty"`
+
+	// NodeRoleARN is the ARN of the IAM role of the nodes launched by EKS Auto Mode. It is
+	// required when NodePools isn't empty, and can't be changed once set. The role must have the
+	// AmazonEKSWorkerNodeMinimalPolicy and AmazonEC2ContainerRegistryPullOnly policies.
+	// +optional
+	NodeRoleARN string `json:"nodeRoleARN,omitempty"`
+}
+
+// IsEnabled returns true if EKS Auto Mode is enabled.
+func (a *AutoMode) IsEnabled() bool {
+	return a != nil && a.Enabled
+}
+
+// AutoModeStatus is the state of EKS Auto Mode as reported by EKS.
+type AutoModeStatus struct {
+	// Enabled is true when EKS manages the compute of the cluster.
+	Enabled bool `json:"enabled"`
+
+	// NodePools are the built-in node pools enabled.
+	// +optional
+	NodePools []AutoModeNodePool `json:"nodePools,omitempty"`
+
+	// NodeRoleARN is the ARN of the IAM role of the nodes launched by EKS Auto Mode.
+	// +optional
+	NodeRoleARN string `json:"nodeRoleARN,omitempty"`
+}
+
+// CSIDriverPolicyMode is how the IAM policy of a CSI driver is granted.
+// +kubebuilder:validation:Enum=attach-to-node-role;irsa
+type CSIDriverPolicyMode string
+
+var (
+	// CSIDriverPolicyModeAttachToNodeRole attaches the policy of the driver to the IAM roles of the
+	// managed node groups created by CAPA.
+	CSIDriverPolicyModeAttachToNodeRole = CSIDriverPolicyMode("attach-to-node-role")
+
+	// CSIDriverPolicyModeIRSA creates an IAM role for the service account of the controller of the
+	// driver, which requires the OIDC provider of the cluster.
+	CSIDriverPolicyModeIRSA = CSIDriverPolicyMode("irsa")
+)
+
+// CSISupport configures the IAM permissions of the Amazon EBS and EFS CSI drivers.
+type CSISupport struct {
+	// EBSDriverPolicy is how the AmazonEBSCSIDriverPolicy is granted to the EBS CSI driver.
+	// The policy isn't granted when it is empty.
+	// +optional
+	EBSDriverPolicy CSIDriverPolicyMode `json:"ebsDriverPolicy,omitempty"`
+
+	// EFSDriverPolicy is how the AmazonEFSCSIDriverPolicy is granted to the EFS CSI driver.
+	// The policy isn't granted when it is empty.
+	// +optional
+	EFSDriverPolicy CSIDriverPolicyMode `json:"efsDriverPolicy,omitempty"`
+}
+
+// GetEBSDriverPolicy returns how the policy of the EBS CSI driver is granted.
+func (c *CSISupport) GetEBSDriverPolicy() CSIDriverPolicyMode {
+	if c == nil {
+		return ""
+	}
+	return c.EBSDriverPolicy
+}
+
+// GetEFSDriverPolicy returns how the policy of the EFS CSI driver is granted.
+func (c *CSISupport) GetEFSDriverPolicy() CSIDriverPolicyMode {
+	if c == nil {
+		return ""
+	}
+	return c.EFSDriverPolicy
+}
+
+// CSIDriverRolesStatus holds the IAM roles created for the service accounts of the CSI drivers.
+type CSIDriverRolesStatus struct {
+	// EBSRoleARN is the ARN of the role of the EBS CSI driver, to set as the service account
+	// role of the aws-ebs-csi-driver addon.
+	// +optional
+	EBSRoleARN string `json:"ebsRoleARN,omitempty"`
+
+	// EFSRoleARN is the ARN of the role of the EFS CSI driver, to set as the service account
+	// role of the aws-efs-csi-driver addon.
+	// +optional
+	EFSRoleARN string `json:"efsRoleARN,omitempty"`
+}
+
+// ECRAccess configures the access of the nodes to the ECR registries of other AWS accounts.
+type ECRAccess struct {
+	// RegistryAccountIDs are the IDs of the AWS accounts owning the ECR registries the nodes pull
+	// images from. An inline policy allowing to pull images from the repositories of these accounts
+	// is put on the roles of the managed node groups created by CAPA, and removed when it is empty.
+	// +optional
+	RegistryAccountIDs []string `json:"registryAccountIDs,omitempty"`
+}
+
+// GetRegistryAccountIDs returns the IDs of the accounts owning the registries to grant access to.
+func (e *ECRAccess) GetRegistryAccountIDs() []string {
+	if e == nil {
+		return nil
+	}
+	return e.RegistryAccountIDs
+}
+
+// ECRAccessStatus holds the principals granted access to ECR registries of other accounts.
+type ECRAccessStatus struct {
+	// PrincipalARNs are the ARNs of the roles of the managed node groups which were granted access
+	// to the registries. The repository policies of the registries must allow these principals too,
+	// which is up to the owners of the registries.
+	// +optional
+	PrincipalARNs []string `json:"principalARNs,omitempty"`
+}
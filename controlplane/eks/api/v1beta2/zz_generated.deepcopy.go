@@ -148,6 +148,13 @@ func (in *AWSManagedControlPlaneSpec) DeepCopyInto(out *AWSManagedControlPlaneSp
 	}
 	in.EndpointAccess.DeepCopyInto(&out.EndpointAccess)
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	if in.DefaultLifecycleHooks != nil {
+		in, out := &in.DefaultLifecycleHooks, &out.DefaultLifecycleHooks
+		*out = make([]apiv1beta2.AWSLifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.Bastion.DeepCopyInto(&out.Bastion)
 	if in.TokenMethod != nil {
 		in, out := &in.TokenMethod, &out.TokenMethod
@@ -172,6 +179,21 @@ func (in *AWSManagedControlPlaneSpec) DeepCopyInto(out *AWSManagedControlPlaneSp
 	}
 	in.VpcCni.DeepCopyInto(&out.VpcCni)
 	out.KubeProxy = in.KubeProxy
+	if in.AutoMode != nil {
+		in, out := &in.AutoMode, &out.AutoMode
+		*out = new(AutoMode)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CSISupport != nil {
+		in, out := &in.CSISupport, &out.CSISupport
+		*out = new(CSISupport)
+		**out = **in
+	}
+	if in.ECRAccess != nil {
+		in, out := &in.ECRAccess, &out.ECRAccess
+		*out = new(ECRAccess)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSManagedControlPlaneSpec.
@@ -226,6 +248,26 @@ func (in *AWSManagedControlPlaneStatus) DeepCopyInto(out *AWSManagedControlPlane
 		}
 	}
 	out.IdentityProviderStatus = in.IdentityProviderStatus
+	if in.AutoMode != nil {
+		in, out := &in.AutoMode, &out.AutoMode
+		*out = new(AutoModeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CSIDriverRoles != nil {
+		in, out := &in.CSIDriverRoles, &out.CSIDriverRoles
+		*out = new(CSIDriverRolesStatus)
+		**out = **in
+	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+	if in.ECRAccess != nil {
+		in, out := &in.ECRAccess, &out.ECRAccess
+		*out = new(ECRAccessStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSManagedControlPlaneStatus.
@@ -327,6 +369,76 @@ func (in *AddonState) DeepCopy() *AddonState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoMode) DeepCopyInto(out *AutoMode) {
+	*out = *in
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]AutoModeNodePool, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoMode.
+func (in *AutoMode) DeepCopy() *AutoMode {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoMode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoModeStatus) DeepCopyInto(out *AutoModeStatus) {
+	*out = *in
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]AutoModeNodePool, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoModeStatus.
+func (in *AutoModeStatus) DeepCopy() *AutoModeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoModeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSIDriverRolesStatus) DeepCopyInto(out *CSIDriverRolesStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSIDriverRolesStatus.
+func (in *CSIDriverRolesStatus) DeepCopy() *CSIDriverRolesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CSIDriverRolesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSISupport) DeepCopyInto(out *CSISupport) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSISupport.
+func (in *CSISupport) DeepCopy() *CSISupport {
+	if in == nil {
+		return nil
+	}
+	out := new(CSISupport)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlaneLoggingSpec) DeepCopyInto(out *ControlPlaneLoggingSpec) {
 	*out = *in
@@ -342,6 +454,46 @@ func (in *ControlPlaneLoggingSpec) DeepCopy() *ControlPlaneLoggingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ECRAccess) DeepCopyInto(out *ECRAccess) {
+	*out = *in
+	if in.RegistryAccountIDs != nil {
+		in, out := &in.RegistryAccountIDs, &out.RegistryAccountIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ECRAccess.
+func (in *ECRAccess) DeepCopy() *ECRAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(ECRAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ECRAccessStatus) DeepCopyInto(out *ECRAccessStatus) {
+	*out = *in
+	if in.PrincipalARNs != nil {
+		in, out := &in.PrincipalARNs, &out.PrincipalARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ECRAccessStatus.
+func (in *ECRAccessStatus) DeepCopy() *ECRAccessStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ECRAccessStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EncryptionConfig) DeepCopyInto(out *EncryptionConfig) {
 	*out = *in
@@ -31,7 +31,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -40,6 +42,7 @@ import (
 	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/awsnode"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/ec2"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/eks"
@@ -49,6 +52,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/kubeproxy"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/network"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/securitygroup"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/spotinterruption"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/logger"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
@@ -94,6 +98,16 @@ type AWSManagedControlPlaneReconciler struct {
 	AlternativeGCStrategy        bool
 	WaitInfraPeriod              time.Duration
 	TagUnmanagedNetworkResources bool
+
+	spotInterruptionFactory func(scope.EC2Scope) services.SpotInterruptionInterface
+}
+
+// getSpotInterruptionService factory func is added for testing purpose so that we can inject mocked SpotInterruptionService to the AWSManagedControlPlaneReconciler.
+func (r *AWSManagedControlPlaneReconciler) getSpotInterruptionService(scope scope.EC2Scope) services.SpotInterruptionInterface {
+	if r.spotInterruptionFactory != nil {
+		return r.spotInterruptionFactory(scope)
+	}
+	return spotinterruption.NewService(scope)
 }
 
 // SetupWithManager is used to setup the controller.
@@ -126,6 +140,24 @@ func (r *AWSManagedControlPlaneReconciler) SetupWithManager(ctx context.Context,
 		return fmt.Errorf("failed adding a watch for AWSManagedCluster")
 	}
 
+	if feature.Gates.Enabled(feature.MachinePool) {
+		// The ECR access of the control plane is granted to the roles of its managed node groups,
+		// which are named when the node groups are created.
+		if err = c.Watch(
+			source.Kind(mgr.GetCache(), &expinfrav1.AWSManagedMachinePool{}),
+			handler.EnqueueRequestsFromMapFunc(r.managedMachinePoolToManagedControlPlane(ctx, log)),
+			predicate.Funcs{
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					oldPool, okOld := e.ObjectOld.(*expinfrav1.AWSManagedMachinePool)
+					newPool, okNew := e.ObjectNew.(*expinfrav1.AWSManagedMachinePool)
+					return okOld && okNew && oldPool.Spec.RoleName != newPool.Spec.RoleName
+				},
+			},
+		); err != nil {
+			return fmt.Errorf("failed adding a watch for AWSManagedMachinePool: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -270,7 +302,11 @@ func (r *AWSManagedControlPlaneReconciler) reconcileNormal(ctx context.Context,
 	}
 
 	if err := sgService.ReconcileSecurityGroups(); err != nil {
-		conditions.MarkFalse(awsManagedControlPlane, infrav1.ClusterSecurityGroupsReadyCondition, infrav1.ClusterSecurityGroupReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		reason := infrav1.ClusterSecurityGroupReconciliationFailedReason
+		if securitygroup.IsRuleLimitExceeded(err) {
+			reason = infrav1.SecurityGroupRuleLimitExceededReason
+		}
+		conditions.MarkFalse(awsManagedControlPlane, infrav1.ClusterSecurityGroupsReadyCondition, reason, clusterv1.ConditionSeverityError, err.Error())
 		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile general security groups for AWSManagedControlPlane %s/%s", awsManagedControlPlane.Namespace, awsManagedControlPlane.Name)
 	}
 
@@ -283,6 +319,16 @@ func (r *AWSManagedControlPlaneReconciler) reconcileNormal(ctx context.Context,
 		return reconcile.Result{}, fmt.Errorf("failed to reconcile control plane for AWSManagedControlPlane %s/%s: %w", awsManagedControlPlane.Namespace, awsManagedControlPlane.Name, err)
 	}
 
+	if awsManagedControlPlane.Spec.ECRAccess != nil || awsManagedControlPlane.Status.ECRAccess != nil {
+		roleNames, err := r.nodegroupRoleNames(ctx, managedScope)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := ekssvc.ReconcileECRAccess(roleNames); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to reconcile ECR access for AWSManagedControlPlane %s/%s: %w", awsManagedControlPlane.Namespace, awsManagedControlPlane.Name, err)
+		}
+	}
+
 	if err := awsnodeService.ReconcileCNI(ctx); err != nil {
 		conditions.MarkFalse(managedScope.InfraCluster(), infrav1.SecondaryCidrsReadyCondition, infrav1.SecondaryCidrReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
 		return reconcile.Result{}, fmt.Errorf("failed to reconcile control plane for AWSManagedControlPlane %s/%s: %w", awsManagedControlPlane.Namespace, awsManagedControlPlane.Name, err)
@@ -338,6 +384,10 @@ func (r *AWSManagedControlPlaneReconciler) reconcileDelete(ctx context.Context,
 	sgService := securitygroup.NewService(managedScope, securityGroupRolesForControlPlane(managedScope))
 
 	if err := ekssvc.DeleteControlPlane(); err != nil {
+		if errors.Is(err, eks.ErrClusterSecurityGroupInUse) {
+			log.Info("EKS cluster security group still in use - requeue needed", "reason", err.Error())
+			return reconcile.Result{RequeueAfter: deleteRequeueAfter}, nil
+		}
 		log.Error(err, "error deleting EKS cluster for EKS control plane", "namespace", controlPlane.Namespace, "name", controlPlane.Name)
 		return reconcile.Result{}, err
 	}
@@ -352,6 +402,12 @@ func (r *AWSManagedControlPlaneReconciler) reconcileDelete(ctx context.Context,
 		return reconcile.Result{}, err
 	}
 
+	// The rule and queue are deleted even if the feature was disabled since, as the pools don't outlive the cluster.
+	if err := r.getSpotInterruptionService(managedScope).DeleteSpotInterruptionEvents(); err != nil {
+		// non fatal error, so we continue
+		managedScope.Error(err, "non-fatal: failed to delete spot interruption notifications")
+	}
+
 	if r.ExternalResourceGC {
 		gcSvc := gc.NewService(managedScope, gc.WithGCStrategy(r.AlternativeGCStrategy))
 		if gcErr := gcSvc.ReconcileDelete(ctx); gcErr != nil {
@@ -468,3 +524,56 @@ func (r *AWSManagedControlPlaneReconciler) managedClusterToManagedControlPlane(_
 		}
 	}
 }
+
+// nodegroupRoleNames returns the names of the IAM roles of the managed node groups of the cluster.
+func (r *AWSManagedControlPlaneReconciler) nodegroupRoleNames(ctx context.Context, managedScope *scope.ManagedControlPlaneScope) ([]string, error) {
+	if !feature.Gates.Enabled(feature.MachinePool) {
+		return nil, nil
+	}
+
+	managedMachinePools := &expinfrav1.AWSManagedMachinePoolList{}
+	if err := r.Client.List(ctx, managedMachinePools,
+		client.InNamespace(managedScope.Namespace()),
+		client.MatchingLabels(map[string]string{clusterv1.ClusterNameLabel: managedScope.Name()}),
+	); err != nil {
+		return nil, fmt.Errorf("failed to list managed machine pools for cluster %s/%s: %w", managedScope.Namespace(), managedScope.Name(), err)
+	}
+
+	var roleNames []string
+	for _, pool := range managedMachinePools.Items {
+		if pool.Spec.RoleName != "" && pool.DeletionTimestamp.IsZero() {
+			roleNames = append(roleNames, pool.Spec.RoleName)
+		}
+	}
+	return roleNames, nil
+}
+
+func (r *AWSManagedControlPlaneReconciler) managedMachinePoolToManagedControlPlane(_ context.Context, log *logger.Logger) handler.MapFunc {
+	return func(ctx context.Context, o client.Object) []ctrl.Request {
+		managedMachinePool, ok := o.(*expinfrav1.AWSManagedMachinePool)
+		if !ok {
+			log.Error(fmt.Errorf("expected a AWSManagedMachinePool but got a %T", o), "Expected AWSManagedMachinePool")
+			return nil
+		}
+
+		cluster, err := util.GetClusterFromMetadata(ctx, r.Client, managedMachinePool.ObjectMeta)
+		if err != nil {
+			log.Debug("Failed to get the cluster of AWSManagedMachinePool, skipping mapping", "error", err.Error())
+			return nil
+		}
+
+		controlPlaneRef := cluster.Spec.ControlPlaneRef
+		if controlPlaneRef == nil || controlPlaneRef.Kind != awsManagedControlPlaneKind {
+			return nil
+		}
+
+		return []ctrl.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Name:      controlPlaneRef.Name,
+					Namespace: controlPlaneRef.Namespace,
+				},
+			},
+		}
+	}
+}
@@ -41,8 +41,16 @@ func (src *AWSMachinePool) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Spec.SuspendProcesses != nil {
 		dst.Spec.SuspendProcesses = restored.Spec.SuspendProcesses
 	}
-	if dst.Spec.RefreshPreferences != nil && restored.Spec.RefreshPreferences != nil {
+	if restored.Spec.RefreshPreferences != nil {
+		if dst.Spec.RefreshPreferences == nil {
+			dst.Spec.RefreshPreferences = &infrav1exp.RefreshPreferences{}
+		}
 		dst.Spec.RefreshPreferences.Disable = restored.Spec.RefreshPreferences.Disable
+		dst.Spec.RefreshPreferences.DisableInstanceRefreshCancellation = restored.Spec.RefreshPreferences.DisableInstanceRefreshCancellation
+		dst.Spec.RefreshPreferences.SkipMatching = restored.Spec.RefreshPreferences.SkipMatching
+		dst.Spec.RefreshPreferences.AutoRollback = restored.Spec.RefreshPreferences.AutoRollback
+		dst.Spec.RefreshPreferences.CheckpointPercentages = restored.Spec.RefreshPreferences.CheckpointPercentages
+		dst.Spec.RefreshPreferences.CheckpointDelay = restored.Spec.RefreshPreferences.CheckpointDelay
 	}
 	if restored.Spec.AWSLaunchTemplate.InstanceMetadataOptions != nil {
 		dst.Spec.AWSLaunchTemplate.InstanceMetadataOptions = restored.Spec.AWSLaunchTemplate.InstanceMetadataOptions
@@ -55,7 +63,87 @@ func (src *AWSMachinePool) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Spec.AWSLaunchTemplate.PrivateDNSName = restored.Spec.AWSLaunchTemplate.PrivateDNSName
 	}
 
+	if restored.Spec.AWSLaunchTemplate.ExistingTemplate != nil {
+		dst.Spec.AWSLaunchTemplate.ExistingTemplate = restored.Spec.AWSLaunchTemplate.ExistingTemplate
+	}
+
+	if restored.Spec.AWSLaunchTemplate.UserDataTransform != nil {
+		dst.Spec.AWSLaunchTemplate.UserDataTransform = restored.Spec.AWSLaunchTemplate.UserDataTransform
+	}
+
 	dst.Spec.DefaultInstanceWarmup = restored.Spec.DefaultInstanceWarmup
+	dst.Spec.AWSLifecycleHooks = restored.Spec.AWSLifecycleHooks
+	dst.Spec.ManagedLaunchLifecycleHook = restored.Spec.ManagedLaunchLifecycleHook
+	dst.Spec.ScaleUpDelayThreshold = restored.Spec.ScaleUpDelayThreshold
+	dst.Spec.NodeUserDataExtra = restored.Spec.NodeUserDataExtra
+	dst.Spec.LoadBalancerAttachments = restored.Spec.LoadBalancerAttachments
+	dst.Spec.ScaleDownPolicy = restored.Spec.ScaleDownPolicy
+	dst.Spec.TerminationPolicies = restored.Spec.TerminationPolicies
+	dst.Spec.HealthCheckType = restored.Spec.HealthCheckType
+	dst.Spec.HealthCheckGracePeriod = restored.Spec.HealthCheckGracePeriod
+	dst.Spec.MaxInstanceLifetime = restored.Spec.MaxInstanceLifetime
+	dst.Spec.MetricsCollection = restored.Spec.MetricsCollection
+	dst.Spec.OS = restored.Spec.OS
+	dst.Spec.Autoscaling = restored.Spec.Autoscaling
+	dst.Spec.SyncSizeBoundsWithExternalAutoscaler = restored.Spec.SyncSizeBoundsWithExternalAutoscaler
+	dst.Spec.AMIVersionOverride = restored.Spec.AMIVersionOverride
+	dst.Spec.ExcludedClusterTags = restored.Spec.ExcludedClusterTags
+	dst.Spec.PropagateTagsAtLaunch = restored.Spec.PropagateTagsAtLaunch
+	dst.Spec.AutoScalingGroupName = restored.Spec.AutoScalingGroupName
+	dst.Spec.KeepOnDelete = restored.Spec.KeepOnDelete
+	dst.Spec.DeletionTimeout = restored.Spec.DeletionTimeout
+	dst.Spec.NewInstancesProtectedFromScaleIn = restored.Spec.NewInstancesProtectedFromScaleIn
+	dst.Spec.TargetGroupARNs = restored.Spec.TargetGroupARNs
+	dst.Spec.ClassicLoadBalancers = restored.Spec.ClassicLoadBalancers
+	dst.Spec.ScheduledActions = restored.Spec.ScheduledActions
+	dst.Spec.InstancesReadyTimeout = restored.Spec.InstancesReadyTimeout
+	dst.Spec.SpotInterruptionHandling = restored.Spec.SpotInterruptionHandling
+	if restored.Spec.MixedInstancesPolicy != nil && dst.Spec.MixedInstancesPolicy != nil {
+		dst.Spec.MixedInstancesPolicy.Overrides = restored.Spec.MixedInstancesPolicy.Overrides
+	}
+	dst.Status.ScalingState = restored.Status.ScalingState
+	dst.Status.WeightedCapacity = restored.Status.WeightedCapacity
+	dst.Status.PendingLifecycleActions = restored.Status.PendingLifecycleActions
+	dst.Status.LifecycleHooks = restored.Status.LifecycleHooks
+	dst.Status.LifecycleHookCount = restored.Status.LifecycleHookCount
+	dst.Status.LifecycleHooksObservedHash = restored.Status.LifecycleHooksObservedHash
+	dst.Status.LifecycleHooksLastSyncTime = restored.Status.LifecycleHooksLastSyncTime
+	dst.Status.AdditionalTags = restored.Status.AdditionalTags
+	dst.Status.ScaleDown = restored.Status.ScaleDown
+	dst.Status.EvacuatedAvailabilityZones = restored.Status.EvacuatedAvailabilityZones
+	dst.Status.RefreshExcludedInstances = restored.Status.RefreshExcludedInstances
+	dst.Status.ScaleInProtectedInstances = restored.Status.ScaleInProtectedInstances
+	dst.Status.AttachedTargetGroupARNs = restored.Status.AttachedTargetGroupARNs
+	dst.Status.AttachedClassicLoadBalancers = restored.Status.AttachedClassicLoadBalancers
+	dst.Status.ScheduledActions = restored.Status.ScheduledActions
+	dst.Status.SpotInterruptionQueueARN = restored.Status.SpotInterruptionQueueARN
+	dst.Status.DesiredCapacity = restored.Status.DesiredCapacity
+	dst.Status.InstanceRefresh = restored.Status.InstanceRefresh
+	dst.Status.SuspendedProcesses = restored.Status.SuspendedProcesses
+	dst.Status.EnabledMetrics = restored.Status.EnabledMetrics
+	dst.Status.Version = restored.Status.Version
+	dst.Status.AMIKubernetesVersion = restored.Status.AMIKubernetesVersion
+	dst.Status.ScalingBlockers = restored.Status.ScalingBlockers
+	dst.Status.Deletion = restored.Status.Deletion
+	dst.Status.LastLaunchTemplateChange = restored.Status.LastLaunchTemplateChange
+	dst.Status.ReplacedLaunchTemplateID = restored.Status.ReplacedLaunchTemplateID
+	dst.Status.OutdatedReplicas = restored.Status.OutdatedReplicas
+	dst.Status.ASGName = restored.Status.ASGName
+	dst.Status.ReadyReplicas = restored.Status.ReadyReplicas
+	restoredInstances := map[string]infrav1exp.AWSMachinePoolInstanceStatus{}
+	for _, instance := range restored.Status.Instances {
+		restoredInstances[instance.InstanceID] = instance
+	}
+	for i := range dst.Status.Instances {
+		if instance, ok := restoredInstances[dst.Status.Instances[i].InstanceID]; ok {
+			dst.Status.Instances[i].LaunchedAt = instance.LaunchedAt
+			dst.Status.Instances[i].NodeRegisteredAt = instance.NodeRegisteredAt
+			dst.Status.Instances[i].LifecycleState = instance.LifecycleState
+			dst.Status.Instances[i].HealthStatus = instance.HealthStatus
+			dst.Status.Instances[i].AvailabilityZone = instance.AvailabilityZone
+			dst.Status.Instances[i].LaunchTemplateVersion = instance.LaunchTemplateVersion
+		}
+	}
 
 	return nil
 }
@@ -105,10 +193,25 @@ func (src *AWSManagedMachinePool) ConvertTo(dstRaw conversion.Hub) error {
 		if restored.Spec.AWSLaunchTemplate.PrivateDNSName != nil {
 			dst.Spec.AWSLaunchTemplate.PrivateDNSName = restored.Spec.AWSLaunchTemplate.PrivateDNSName
 		}
+
+		if restored.Spec.AWSLaunchTemplate.ExistingTemplate != nil {
+			dst.Spec.AWSLaunchTemplate.ExistingTemplate = restored.Spec.AWSLaunchTemplate.ExistingTemplate
+		}
+
+		if restored.Spec.AWSLaunchTemplate.UserDataTransform != nil {
+			dst.Spec.AWSLaunchTemplate.UserDataTransform = restored.Spec.AWSLaunchTemplate.UserDataTransform
+		}
 	}
 	if restored.Spec.AvailabilityZoneSubnetType != nil {
 		dst.Spec.AvailabilityZoneSubnetType = restored.Spec.AvailabilityZoneSubnetType
 	}
+	dst.Spec.Autoscaling = restored.Spec.Autoscaling
+	dst.Spec.AMIVersionOverride = restored.Spec.AMIVersionOverride
+	dst.Status.LastLaunchTemplateChange = restored.Status.LastLaunchTemplateChange
+	dst.Status.Version = restored.Status.Version
+	dst.Status.AMIKubernetesVersion = restored.Status.AMIKubernetesVersion
+	dst.Status.NodegroupStatus = restored.Status.NodegroupStatus
+	dst.Status.DesiredSize = restored.Status.DesiredSize
 
 	return nil
 }
@@ -201,6 +304,21 @@ func Convert_v1beta2_AWSMachinePoolSpec_To_v1beta1_AWSMachinePoolSpec(in *infrav
 	return autoConvert_v1beta2_AWSMachinePoolSpec_To_v1beta1_AWSMachinePoolSpec(in, out, s)
 }
 
+// Convert_v1beta2_AWSMachinePoolInstanceStatus_To_v1beta1_AWSMachinePoolInstanceStatus converts the v1beta2 AWSMachinePoolInstanceStatus receiver to a v1beta1 AWSMachinePoolInstanceStatus.
+func Convert_v1beta2_AWSMachinePoolInstanceStatus_To_v1beta1_AWSMachinePoolInstanceStatus(in *infrav1exp.AWSMachinePoolInstanceStatus, out *AWSMachinePoolInstanceStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta2_AWSMachinePoolInstanceStatus_To_v1beta1_AWSMachinePoolInstanceStatus(in, out, s)
+}
+
+// Convert_v1beta2_AWSMachinePoolStatus_To_v1beta1_AWSMachinePoolStatus converts the v1beta2 AWSMachinePoolStatus receiver to a v1beta1 AWSMachinePoolStatus.
+func Convert_v1beta2_AWSMachinePoolStatus_To_v1beta1_AWSMachinePoolStatus(in *infrav1exp.AWSMachinePoolStatus, out *AWSMachinePoolStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta2_AWSMachinePoolStatus_To_v1beta1_AWSMachinePoolStatus(in, out, s)
+}
+
+// Convert_v1beta2_AWSManagedMachinePoolStatus_To_v1beta1_AWSManagedMachinePoolStatus converts the v1beta2 AWSManagedMachinePoolStatus receiver to a v1beta1 AWSManagedMachinePoolStatus.
+func Convert_v1beta2_AWSManagedMachinePoolStatus_To_v1beta1_AWSManagedMachinePoolStatus(in *infrav1exp.AWSManagedMachinePoolStatus, out *AWSManagedMachinePoolStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta2_AWSManagedMachinePoolStatus_To_v1beta1_AWSManagedMachinePoolStatus(in, out, s)
+}
+
 func Convert_v1beta1_AutoScalingGroup_To_v1beta2_AutoScalingGroup(in *AutoScalingGroup, out *infrav1exp.AutoScalingGroup, s apiconversion.Scope) error {
 	return autoConvert_v1beta1_AutoScalingGroup_To_v1beta2_AutoScalingGroup(in, out, s)
 }
@@ -215,3 +333,9 @@ func Convert_v1beta2_RefreshPreferences_To_v1beta1_RefreshPreferences(in *infrav
 	// spec.refreshPreferences.disable has been added to v1beta2.
 	return autoConvert_v1beta2_RefreshPreferences_To_v1beta1_RefreshPreferences(in, out, s)
 }
+
+// Convert_v1beta2_Overrides_To_v1beta1_Overrides converts the v1beta2 Overrides receiver to a v1beta1 Overrides.
+func Convert_v1beta2_Overrides_To_v1beta1_Overrides(in *infrav1exp.Overrides, out *Overrides, s apiconversion.Scope) error {
+	// spec.mixedInstancesPolicy.overrides.instanceRequirements and weightedCapacity have been added to v1beta2.
+	return autoConvert_v1beta2_Overrides_To_v1beta1_Overrides(in, out, s)
+}
@@ -17,12 +17,19 @@ limitations under the License.
 package v1beta1
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
 func TestFuzzyConversion(t *testing.T) {
@@ -49,3 +56,188 @@ func TestFuzzyConversion(t *testing.T) {
 		Spoke:  &AWSFargateProfile{},
 	}))
 }
+
+// roundTripThroughV1beta1 converts the hub to v1beta1, stores the v1beta1 object as JSON as the API server
+// would, e.g. while clusterctl moves it, and converts it back to the hub.
+func roundTripThroughV1beta1(g *WithT, hub conversion.Hub, spoke conversion.Convertible, restored conversion.Hub) {
+	g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+	data, err := json.Marshal(spoke)
+	g.Expect(err).NotTo(HaveOccurred())
+	stored := reflect.New(reflect.TypeOf(spoke).Elem()).Interface().(conversion.Convertible)
+	g.Expect(json.Unmarshal(data, stored)).To(Succeed())
+	g.Expect(stored.ConvertTo(restored)).To(Succeed())
+}
+
+func TestAWSMachinePoolHubOnlyFieldsConversion(t *testing.T) {
+	g := NewWithT(t)
+
+	hub := &v1beta2.AWSMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pool",
+			Namespace: "default",
+		},
+		Spec: v1beta2.AWSMachinePoolSpec{
+			RefreshPreferences: &v1beta2.RefreshPreferences{
+				Disable:                            true,
+				DisableInstanceRefreshCancellation: true,
+				SkipMatching:                       ptr.To(true),
+				AutoRollback:                       ptr.To(false),
+				CheckpointPercentages:              []int64{50, 100},
+				CheckpointDelay:                    ptr.To[int64](600),
+			},
+			AWSLifecycleHooks: []infrav1.AWSLifecycleHook{
+				{
+					Name:                "launch-hook",
+					LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch,
+				},
+			},
+			ManagedLaunchLifecycleHook: &v1beta2.ManagedLaunchLifecycleHook{Enabled: true},
+			Autoscaling:                &v1beta2.MachinePoolAutoscaling{Enabled: true, MinSize: 1, MaxSize: 3},
+			AWSLaunchTemplate: v1beta2.AWSLaunchTemplate{
+				ExistingTemplate: &v1beta2.ExistingLaunchTemplate{ID: "lt-0123456789abcdef0", FollowLatest: true},
+			},
+		},
+		Status: v1beta2.AWSMachinePoolStatus{
+			RefreshExcludedInstances: []string{"i-0123456789abcdef0"},
+			InstanceRefresh:          &v1beta2.InstanceRefreshStatus{ID: "refresh-1", Status: "InProgress"},
+			SuspendedProcesses:       []string{"AZRebalance", "ReplaceUnhealthy"},
+			ScalingBlockers:          []v1beta2.ScalingBlocker{{Code: v1beta2.ScalingBlockerSpotCapacity, Message: "no spot capacity"}},
+		},
+	}
+
+	restored := &v1beta2.AWSMachinePool{}
+	roundTripThroughV1beta1(g, hub, &AWSMachinePool{}, restored)
+	g.Expect(restored.Spec).To(Equal(hub.Spec))
+	g.Expect(restored.Status).To(Equal(hub.Status))
+}
+
+func TestAWSMachinePoolInstancesDistributionConversion(t *testing.T) {
+	tests := []struct {
+		name       string
+		base       *int64
+		percentage *int64
+	}{
+		{
+			name:       "spot instances only above a base capacity of 0",
+			base:       ptr.To[int64](0),
+			percentage: ptr.To[int64](0),
+		},
+		{
+			name:       "on-demand base capacity with spot instances above it",
+			base:       ptr.To[int64](2),
+			percentage: ptr.To[int64](0),
+		},
+		{
+			name:       "on-demand instances only",
+			base:       ptr.To[int64](0),
+			percentage: ptr.To[int64](100),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			hub := &v1beta2.AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pool",
+					Namespace: "default",
+				},
+				Spec: v1beta2.AWSMachinePoolSpec{
+					MixedInstancesPolicy: &v1beta2.MixedInstancesPolicy{
+						InstancesDistribution: &v1beta2.InstancesDistribution{
+							OnDemandAllocationStrategy:          v1beta2.OnDemandAllocationStrategyPrioritized,
+							SpotAllocationStrategy:              v1beta2.SpotAllocationStrategyLowestPrice,
+							OnDemandBaseCapacity:                tt.base,
+							OnDemandPercentageAboveBaseCapacity: tt.percentage,
+						},
+						Overrides: []v1beta2.Overrides{{InstanceType: "m5.large"}},
+					},
+				},
+			}
+
+			restored := &v1beta2.AWSMachinePool{}
+			roundTripThroughV1beta1(g, hub, &AWSMachinePool{}, restored)
+			g.Expect(restored.Spec.MixedInstancesPolicy).To(Equal(hub.Spec.MixedInstancesPolicy))
+
+			// The On-Demand capacities are kept by v1beta1 itself, without the annotation of the hub.
+			spoke := &AWSMachinePool{}
+			g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+			delete(spoke.Annotations, utilconversion.DataAnnotation)
+			data, err := json.Marshal(spoke)
+			g.Expect(err).NotTo(HaveOccurred())
+			stored := &AWSMachinePool{}
+			g.Expect(json.Unmarshal(data, stored)).To(Succeed())
+			converted := &v1beta2.AWSMachinePool{}
+			g.Expect(stored.ConvertTo(converted)).To(Succeed())
+			g.Expect(converted.Spec.MixedInstancesPolicy.InstancesDistribution.OnDemandBaseCapacity).To(Equal(tt.base))
+			g.Expect(converted.Spec.MixedInstancesPolicy.InstancesDistribution.OnDemandPercentageAboveBaseCapacity).To(Equal(tt.percentage))
+		})
+	}
+}
+
+func TestAWSManagedMachinePoolHubOnlyFieldsConversion(t *testing.T) {
+	g := NewWithT(t)
+
+	hub := &v1beta2.AWSManagedMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pool",
+			Namespace: "default",
+		},
+		Spec: v1beta2.AWSManagedMachinePoolSpec{
+			AWSLaunchTemplate: &v1beta2.AWSLaunchTemplate{
+				ExistingTemplate: &v1beta2.ExistingLaunchTemplate{ID: "lt-0123456789abcdef0", Version: "3"},
+			},
+			Autoscaling: &v1beta2.MachinePoolAutoscaling{Enabled: true, MinSize: 1, MaxSize: 3},
+		},
+	}
+
+	restored := &v1beta2.AWSManagedMachinePool{}
+	roundTripThroughV1beta1(g, hub, &AWSManagedMachinePool{}, restored)
+	g.Expect(restored.Spec).To(Equal(hub.Spec))
+}
+
+func TestAWSMachinePoolLifecycleHooksConversion(t *testing.T) {
+	g := NewWithT(t)
+
+	hooks := []infrav1.AWSLifecycleHook{
+		{
+			Name:                  "launch-hook",
+			LifecycleTransition:   infrav1.LifecycleTransitionInstanceLaunch,
+			NotificationTargetARN: ptr.To("arn:aws:sqs:us-east-1:123456789012:my-queue"),
+			RoleARN:               ptr.To("arn:aws:iam::123456789012:role/my-role"),
+			HeartbeatTimeout:      &metav1.Duration{Duration: 10 * time.Minute},
+			DefaultResult:         ptr.To(infrav1.LifecycleHookDefaultResultContinue),
+			NotificationMetadata:  ptr.To(`{"key":"value"}`),
+			SyncMode:              ptr.To(infrav1.LifecycleHookSyncModeCreateOnly),
+		},
+		{
+			Name:                "terminate-hook",
+			LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+		},
+	}
+	hub := &v1beta2.AWSMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pool",
+			Namespace: "default",
+		},
+		Spec: v1beta2.AWSMachinePoolSpec{
+			AWSLifecycleHooks: hooks,
+		},
+	}
+
+	// Written as v1beta1.
+	spoke := &AWSMachinePool{}
+	g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+
+	// Read as v1beta2.
+	restored := &v1beta2.AWSMachinePool{}
+	g.Expect(spoke.ConvertTo(restored)).To(Succeed())
+	g.Expect(restored.Spec.AWSLifecycleHooks).To(Equal(hooks))
+
+	// Written back as v1beta1 and read again as v1beta2.
+	spokeAgain := &AWSMachinePool{}
+	g.Expect(spokeAgain.ConvertFrom(restored)).To(Succeed())
+	restoredAgain := &v1beta2.AWSMachinePool{}
+	g.Expect(spokeAgain.ConvertTo(restoredAgain)).To(Succeed())
+	g.Expect(restoredAgain.Spec.AWSLifecycleHooks).To(Equal(hooks))
+}
@@ -80,11 +80,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta2.AWSMachinePoolInstanceStatus)(nil), (*AWSMachinePoolInstanceStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta2_AWSMachinePoolInstanceStatus_To_v1beta1_AWSMachinePoolInstanceStatus(a.(*v1beta2.AWSMachinePoolInstanceStatus), b.(*AWSMachinePoolInstanceStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AWSMachinePoolList)(nil), (*v1beta2.AWSMachinePoolList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_AWSMachinePoolList_To_v1beta2_AWSMachinePoolList(a.(*AWSMachinePoolList), b.(*v1beta2.AWSMachinePoolList), scope)
 	}); err != nil {
@@ -100,11 +95,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta2.AWSMachinePoolStatus)(nil), (*AWSMachinePoolStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta2_AWSMachinePoolStatus_To_v1beta1_AWSMachinePoolStatus(a.(*v1beta2.AWSMachinePoolStatus), b.(*AWSMachinePoolStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*AWSManagedMachinePool)(nil), (*v1beta2.AWSManagedMachinePool)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_AWSManagedMachinePool_To_v1beta2_AWSManagedMachinePool(a.(*AWSManagedMachinePool), b.(*v1beta2.AWSManagedMachinePool), scope)
 	}); err != nil {
@@ -135,11 +125,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta2.AWSManagedMachinePoolStatus)(nil), (*AWSManagedMachinePoolStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta2_AWSManagedMachinePoolStatus_To_v1beta1_AWSManagedMachinePoolStatus(a.(*v1beta2.AWSManagedMachinePoolStatus), b.(*AWSManagedMachinePoolStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*BlockDeviceMapping)(nil), (*v1beta2.BlockDeviceMapping)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_BlockDeviceMapping_To_v1beta2_BlockDeviceMapping(a.(*BlockDeviceMapping), b.(*v1beta2.BlockDeviceMapping), scope)
 	}); err != nil {
@@ -235,11 +220,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta2.Overrides)(nil), (*Overrides)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta2_Overrides_To_v1beta1_Overrides(a.(*v1beta2.Overrides), b.(*Overrides), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*RefreshPreferences)(nil), (*v1beta2.RefreshPreferences)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_RefreshPreferences_To_v1beta2_RefreshPreferences(a.(*RefreshPreferences), b.(*v1beta2.RefreshPreferences), scope)
 	}); err != nil {
@@ -295,21 +275,41 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta2.AWSMachinePoolInstanceStatus)(nil), (*AWSMachinePoolInstanceStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AWSMachinePoolInstanceStatus_To_v1beta1_AWSMachinePoolInstanceStatus(a.(*v1beta2.AWSMachinePoolInstanceStatus), b.(*AWSMachinePoolInstanceStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta2.AWSMachinePoolSpec)(nil), (*AWSMachinePoolSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_AWSMachinePoolSpec_To_v1beta1_AWSMachinePoolSpec(a.(*v1beta2.AWSMachinePoolSpec), b.(*AWSMachinePoolSpec), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta2.AWSMachinePoolStatus)(nil), (*AWSMachinePoolStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AWSMachinePoolStatus_To_v1beta1_AWSMachinePoolStatus(a.(*v1beta2.AWSMachinePoolStatus), b.(*AWSMachinePoolStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta2.AWSManagedMachinePoolSpec)(nil), (*AWSManagedMachinePoolSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_AWSManagedMachinePoolSpec_To_v1beta1_AWSManagedMachinePoolSpec(a.(*v1beta2.AWSManagedMachinePoolSpec), b.(*AWSManagedMachinePoolSpec), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta2.AWSManagedMachinePoolStatus)(nil), (*AWSManagedMachinePoolStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AWSManagedMachinePoolStatus_To_v1beta1_AWSManagedMachinePoolStatus(a.(*v1beta2.AWSManagedMachinePoolStatus), b.(*AWSManagedMachinePoolStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta2.AutoScalingGroup)(nil), (*AutoScalingGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_AutoScalingGroup_To_v1beta1_AutoScalingGroup(a.(*v1beta2.AutoScalingGroup), b.(*AutoScalingGroup), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta2.Overrides)(nil), (*Overrides)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_Overrides_To_v1beta1_Overrides(a.(*v1beta2.Overrides), b.(*Overrides), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta2.RefreshPreferences)(nil), (*RefreshPreferences)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_RefreshPreferences_To_v1beta1_RefreshPreferences(a.(*v1beta2.RefreshPreferences), b.(*RefreshPreferences), scope)
 	}); err != nil {
@@ -408,6 +408,8 @@ func autoConvert_v1beta2_AWSLaunchTemplate_To_v1beta1_AWSLaunchTemplate(in *v1be
 	out.SpotMarketOptions = (*apiv1beta2.SpotMarketOptions)(unsafe.Pointer(in.SpotMarketOptions))
 	// WARNING: in.InstanceMetadataOptions requires manual conversion: does not exist in peer-type
 	// WARNING: in.PrivateDNSName requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExistingTemplate requires manual conversion: does not exist in peer-type
+	// WARNING: in.UserDataTransform requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -457,14 +459,15 @@ func Convert_v1beta1_AWSMachinePoolInstanceStatus_To_v1beta2_AWSMachinePoolInsta
 func autoConvert_v1beta2_AWSMachinePoolInstanceStatus_To_v1beta1_AWSMachinePoolInstanceStatus(in *v1beta2.AWSMachinePoolInstanceStatus, out *AWSMachinePoolInstanceStatus, s conversion.Scope) error {
 	out.InstanceID = in.InstanceID
 	out.Version = (*string)(unsafe.Pointer(in.Version))
+	// WARNING: in.LaunchedAt requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeRegisteredAt requires manual conversion: does not exist in peer-type
+	// WARNING: in.LifecycleState requires manual conversion: does not exist in peer-type
+	// WARNING: in.HealthStatus requires manual conversion: does not exist in peer-type
+	// WARNING: in.AvailabilityZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.LaunchTemplateVersion requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta2_AWSMachinePoolInstanceStatus_To_v1beta1_AWSMachinePoolInstanceStatus is an autogenerated conversion function.
-func Convert_v1beta2_AWSMachinePoolInstanceStatus_To_v1beta1_AWSMachinePoolInstanceStatus(in *v1beta2.AWSMachinePoolInstanceStatus, out *AWSMachinePoolInstanceStatus, s conversion.Scope) error {
-	return autoConvert_v1beta2_AWSMachinePoolInstanceStatus_To_v1beta1_AWSMachinePoolInstanceStatus(in, out, s)
-}
-
 func autoConvert_v1beta1_AWSMachinePoolList_To_v1beta2_AWSMachinePoolList(in *AWSMachinePoolList, out *v1beta2.AWSMachinePoolList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
@@ -517,7 +520,15 @@ func autoConvert_v1beta1_AWSMachinePoolSpec_To_v1beta2_AWSMachinePoolSpec(in *AW
 	if err := Convert_v1beta1_AWSLaunchTemplate_To_v1beta2_AWSLaunchTemplate(&in.AWSLaunchTemplate, &out.AWSLaunchTemplate, s); err != nil {
 		return err
 	}
-	out.MixedInstancesPolicy = (*v1beta2.MixedInstancesPolicy)(unsafe.Pointer(in.MixedInstancesPolicy))
+	if in.MixedInstancesPolicy != nil {
+		in, out := &in.MixedInstancesPolicy, &out.MixedInstancesPolicy
+		*out = new(v1beta2.MixedInstancesPolicy)
+		if err := Convert_v1beta1_MixedInstancesPolicy_To_v1beta2_MixedInstancesPolicy(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.MixedInstancesPolicy = nil
+	}
 	out.ProviderIDList = *(*[]string)(unsafe.Pointer(&in.ProviderIDList))
 	out.DefaultCoolDown = in.DefaultCoolDown
 	if in.RefreshPreferences != nil {
@@ -537,14 +548,26 @@ func autoConvert_v1beta2_AWSMachinePoolSpec_To_v1beta1_AWSMachinePoolSpec(in *v1
 	out.ProviderID = in.ProviderID
 	out.MinSize = in.MinSize
 	out.MaxSize = in.MaxSize
+	// WARNING: in.Autoscaling requires manual conversion: does not exist in peer-type
+	// WARNING: in.SyncSizeBoundsWithExternalAutoscaler requires manual conversion: does not exist in peer-type
 	out.AvailabilityZones = *(*[]string)(unsafe.Pointer(&in.AvailabilityZones))
 	// WARNING: in.AvailabilityZoneSubnetType requires manual conversion: does not exist in peer-type
 	out.Subnets = *(*[]apiv1beta2.AWSResourceReference)(unsafe.Pointer(&in.Subnets))
 	out.AdditionalTags = *(*apiv1beta2.Tags)(unsafe.Pointer(&in.AdditionalTags))
+	// WARNING: in.ExcludedClusterTags requires manual conversion: does not exist in peer-type
+	// WARNING: in.PropagateTagsAtLaunch requires manual conversion: does not exist in peer-type
 	if err := Convert_v1beta2_AWSLaunchTemplate_To_v1beta1_AWSLaunchTemplate(&in.AWSLaunchTemplate, &out.AWSLaunchTemplate, s); err != nil {
 		return err
 	}
-	out.MixedInstancesPolicy = (*MixedInstancesPolicy)(unsafe.Pointer(in.MixedInstancesPolicy))
+	if in.MixedInstancesPolicy != nil {
+		in, out := &in.MixedInstancesPolicy, &out.MixedInstancesPolicy
+		*out = new(MixedInstancesPolicy)
+		if err := Convert_v1beta2_MixedInstancesPolicy_To_v1beta1_MixedInstancesPolicy(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.MixedInstancesPolicy = nil
+	}
 	out.ProviderIDList = *(*[]string)(unsafe.Pointer(&in.ProviderIDList))
 	out.DefaultCoolDown = in.DefaultCoolDown
 	// WARNING: in.DefaultInstanceWarmup requires manual conversion: does not exist in peer-type
@@ -558,7 +581,29 @@ func autoConvert_v1beta2_AWSMachinePoolSpec_To_v1beta1_AWSMachinePoolSpec(in *v1
 		out.RefreshPreferences = nil
 	}
 	out.CapacityRebalance = in.CapacityRebalance
+	// WARNING: in.TerminationPolicies requires manual conversion: does not exist in peer-type
+	// WARNING: in.HealthCheckType requires manual conversion: does not exist in peer-type
+	// WARNING: in.HealthCheckGracePeriod requires manual conversion: does not exist in peer-type
+	// WARNING: in.MaxInstanceLifetime requires manual conversion: does not exist in peer-type
+	// WARNING: in.MetricsCollection requires manual conversion: does not exist in peer-type
 	// WARNING: in.SuspendProcesses requires manual conversion: does not exist in peer-type
+	// WARNING: in.AWSLifecycleHooks requires manual conversion: does not exist in peer-type
+	// WARNING: in.ManagedLaunchLifecycleHook requires manual conversion: does not exist in peer-type
+	// WARNING: in.ScaleUpDelayThreshold requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodeUserDataExtra requires manual conversion: does not exist in peer-type
+	// WARNING: in.LoadBalancerAttachments requires manual conversion: does not exist in peer-type
+	// WARNING: in.ScaleDownPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.OS requires manual conversion: does not exist in peer-type
+	// WARNING: in.AMIVersionOverride requires manual conversion: does not exist in peer-type
+	// WARNING: in.AutoScalingGroupName requires manual conversion: does not exist in peer-type
+	// WARNING: in.KeepOnDelete requires manual conversion: does not exist in peer-type
+	// WARNING: in.DeletionTimeout requires manual conversion: does not exist in peer-type
+	// WARNING: in.NewInstancesProtectedFromScaleIn requires manual conversion: does not exist in peer-type
+	// WARNING: in.TargetGroupARNs requires manual conversion: does not exist in peer-type
+	// WARNING: in.ClassicLoadBalancers requires manual conversion: does not exist in peer-type
+	// WARNING: in.ScheduledActions requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstancesReadyTimeout requires manual conversion: does not exist in peer-type
+	// WARNING: in.SpotInterruptionHandling requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -566,7 +611,17 @@ func autoConvert_v1beta1_AWSMachinePoolStatus_To_v1beta2_AWSMachinePoolStatus(in
 	out.Ready = in.Ready
 	out.Replicas = in.Replicas
 	out.Conditions = *(*clusterapiapiv1beta1.Conditions)(unsafe.Pointer(&in.Conditions))
-	out.Instances = *(*[]v1beta2.AWSMachinePoolInstanceStatus)(unsafe.Pointer(&in.Instances))
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]v1beta2.AWSMachinePoolInstanceStatus, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_AWSMachinePoolInstanceStatus_To_v1beta2_AWSMachinePoolInstanceStatus(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Instances = nil
+	}
 	out.LaunchTemplateID = in.LaunchTemplateID
 	out.LaunchTemplateVersion = (*string)(unsafe.Pointer(in.LaunchTemplateVersion))
 	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
@@ -583,21 +638,55 @@ func Convert_v1beta1_AWSMachinePoolStatus_To_v1beta2_AWSMachinePoolStatus(in *AW
 func autoConvert_v1beta2_AWSMachinePoolStatus_To_v1beta1_AWSMachinePoolStatus(in *v1beta2.AWSMachinePoolStatus, out *AWSMachinePoolStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	out.Replicas = in.Replicas
+	// WARNING: in.OutdatedReplicas requires manual conversion: does not exist in peer-type
+	// WARNING: in.ASGName requires manual conversion: does not exist in peer-type
+	// WARNING: in.WeightedCapacity requires manual conversion: does not exist in peer-type
 	out.Conditions = *(*clusterapiapiv1beta1.Conditions)(unsafe.Pointer(&in.Conditions))
-	out.Instances = *(*[]AWSMachinePoolInstanceStatus)(unsafe.Pointer(&in.Instances))
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]AWSMachinePoolInstanceStatus, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta2_AWSMachinePoolInstanceStatus_To_v1beta1_AWSMachinePoolInstanceStatus(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Instances = nil
+	}
+	// WARNING: in.ReadyReplicas requires manual conversion: does not exist in peer-type
 	out.LaunchTemplateID = in.LaunchTemplateID
 	out.LaunchTemplateVersion = (*string)(unsafe.Pointer(in.LaunchTemplateVersion))
+	// WARNING: in.LastLaunchTemplateChange requires manual conversion: does not exist in peer-type
+	// WARNING: in.ReplacedLaunchTemplateID requires manual conversion: does not exist in peer-type
+	// WARNING: in.ScalingState requires manual conversion: does not exist in peer-type
+	// WARNING: in.PendingLifecycleActions requires manual conversion: does not exist in peer-type
+	// WARNING: in.LifecycleHooks requires manual conversion: does not exist in peer-type
+	// WARNING: in.LifecycleHookCount requires manual conversion: does not exist in peer-type
+	// WARNING: in.LifecycleHooksObservedHash requires manual conversion: does not exist in peer-type
+	// WARNING: in.LifecycleHooksLastSyncTime requires manual conversion: does not exist in peer-type
+	// WARNING: in.AdditionalTags requires manual conversion: does not exist in peer-type
+	// WARNING: in.ScaleDown requires manual conversion: does not exist in peer-type
+	// WARNING: in.EvacuatedAvailabilityZones requires manual conversion: does not exist in peer-type
+	// WARNING: in.RefreshExcludedInstances requires manual conversion: does not exist in peer-type
+	// WARNING: in.ScaleInProtectedInstances requires manual conversion: does not exist in peer-type
+	// WARNING: in.AttachedTargetGroupARNs requires manual conversion: does not exist in peer-type
+	// WARNING: in.AttachedClassicLoadBalancers requires manual conversion: does not exist in peer-type
+	// WARNING: in.ScheduledActions requires manual conversion: does not exist in peer-type
+	// WARNING: in.SpotInterruptionQueueARN requires manual conversion: does not exist in peer-type
+	// WARNING: in.DesiredCapacity requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceRefresh requires manual conversion: does not exist in peer-type
+	// WARNING: in.SuspendedProcesses requires manual conversion: does not exist in peer-type
+	// WARNING: in.EnabledMetrics requires manual conversion: does not exist in peer-type
+	// WARNING: in.Version requires manual conversion: does not exist in peer-type
+	// WARNING: in.AMIKubernetesVersion requires manual conversion: does not exist in peer-type
+	// WARNING: in.ScalingBlockers requires manual conversion: does not exist in peer-type
+	// WARNING: in.Deletion requires manual conversion: does not exist in peer-type
 	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
 	out.ASGStatus = (*ASGStatus)(unsafe.Pointer(in.ASGStatus))
 	return nil
 }
 
-// Convert_v1beta2_AWSMachinePoolStatus_To_v1beta1_AWSMachinePoolStatus is an autogenerated conversion function.
-func Convert_v1beta2_AWSMachinePoolStatus_To_v1beta1_AWSMachinePoolStatus(in *v1beta2.AWSMachinePoolStatus, out *AWSMachinePoolStatus, s conversion.Scope) error {
-	return autoConvert_v1beta2_AWSMachinePoolStatus_To_v1beta1_AWSMachinePoolStatus(in, out, s)
-}
-
 func autoConvert_v1beta1_AWSManagedMachinePool_To_v1beta2_AWSManagedMachinePool(in *AWSManagedMachinePool, out *v1beta2.AWSManagedMachinePool, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1beta1_AWSManagedMachinePoolSpec_To_v1beta2_AWSManagedMachinePoolSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -716,12 +805,14 @@ func autoConvert_v1beta2_AWSManagedMachinePoolSpec_To_v1beta1_AWSManagedMachineP
 	out.RoleAdditionalPolicies = *(*[]string)(unsafe.Pointer(&in.RoleAdditionalPolicies))
 	out.RoleName = in.RoleName
 	out.AMIVersion = (*string)(unsafe.Pointer(in.AMIVersion))
+	// WARNING: in.AMIVersionOverride requires manual conversion: does not exist in peer-type
 	out.AMIType = (*ManagedMachineAMIType)(unsafe.Pointer(in.AMIType))
 	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
 	out.Taints = *(*Taints)(unsafe.Pointer(&in.Taints))
 	out.DiskSize = (*int32)(unsafe.Pointer(in.DiskSize))
 	out.InstanceType = (*string)(unsafe.Pointer(in.InstanceType))
 	out.Scaling = (*ManagedMachinePoolScaling)(unsafe.Pointer(in.Scaling))
+	// WARNING: in.Autoscaling requires manual conversion: does not exist in peer-type
 	out.RemoteAccess = (*ManagedRemoteAccess)(unsafe.Pointer(in.RemoteAccess))
 	out.ProviderIDList = *(*[]string)(unsafe.Pointer(&in.ProviderIDList))
 	out.CapacityType = (*ManagedMachinePoolCapacityType)(unsafe.Pointer(in.CapacityType))
@@ -757,19 +848,19 @@ func Convert_v1beta1_AWSManagedMachinePoolStatus_To_v1beta2_AWSManagedMachinePoo
 func autoConvert_v1beta2_AWSManagedMachinePoolStatus_To_v1beta1_AWSManagedMachinePoolStatus(in *v1beta2.AWSManagedMachinePoolStatus, out *AWSManagedMachinePoolStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	out.Replicas = in.Replicas
+	// WARNING: in.DesiredSize requires manual conversion: does not exist in peer-type
 	out.LaunchTemplateID = (*string)(unsafe.Pointer(in.LaunchTemplateID))
 	out.LaunchTemplateVersion = (*string)(unsafe.Pointer(in.LaunchTemplateVersion))
+	// WARNING: in.LastLaunchTemplateChange requires manual conversion: does not exist in peer-type
+	// WARNING: in.Version requires manual conversion: does not exist in peer-type
+	// WARNING: in.AMIKubernetesVersion requires manual conversion: does not exist in peer-type
+	// WARNING: in.NodegroupStatus requires manual conversion: does not exist in peer-type
 	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
 	out.Conditions = *(*clusterapiapiv1beta1.Conditions)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
-// Convert_v1beta2_AWSManagedMachinePoolStatus_To_v1beta1_AWSManagedMachinePoolStatus is an autogenerated conversion function.
-func Convert_v1beta2_AWSManagedMachinePoolStatus_To_v1beta1_AWSManagedMachinePoolStatus(in *v1beta2.AWSManagedMachinePoolStatus, out *AWSManagedMachinePoolStatus, s conversion.Scope) error {
-	return autoConvert_v1beta2_AWSManagedMachinePoolStatus_To_v1beta1_AWSManagedMachinePoolStatus(in, out, s)
-}
-
 func autoConvert_v1beta1_AutoScalingGroup_To_v1beta2_AutoScalingGroup(in *AutoScalingGroup, out *v1beta2.AutoScalingGroup, s conversion.Scope) error {
 	out.ID = in.ID
 	out.Tags = *(*apiv1beta2.Tags)(unsafe.Pointer(&in.Tags))
@@ -781,7 +872,15 @@ func autoConvert_v1beta1_AutoScalingGroup_To_v1beta2_AutoScalingGroup(in *AutoSc
 	out.Subnets = *(*[]string)(unsafe.Pointer(&in.Subnets))
 	out.DefaultCoolDown = in.DefaultCoolDown
 	out.CapacityRebalance = in.CapacityRebalance
-	out.MixedInstancesPolicy = (*v1beta2.MixedInstancesPolicy)(unsafe.Pointer(in.MixedInstancesPolicy))
+	if in.MixedInstancesPolicy != nil {
+		in, out := &in.MixedInstancesPolicy, &out.MixedInstancesPolicy
+		*out = new(v1beta2.MixedInstancesPolicy)
+		if err := Convert_v1beta1_MixedInstancesPolicy_To_v1beta2_MixedInstancesPolicy(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.MixedInstancesPolicy = nil
+	}
 	out.Status = v1beta2.ASGStatus(in.Status)
 	out.Instances = *(*[]apiv1beta2.Instance)(unsafe.Pointer(&in.Instances))
 	return nil
@@ -799,10 +898,31 @@ func autoConvert_v1beta2_AutoScalingGroup_To_v1beta1_AutoScalingGroup(in *v1beta
 	out.DefaultCoolDown = in.DefaultCoolDown
 	// WARNING: in.DefaultInstanceWarmup requires manual conversion: does not exist in peer-type
 	out.CapacityRebalance = in.CapacityRebalance
-	out.MixedInstancesPolicy = (*MixedInstancesPolicy)(unsafe.Pointer(in.MixedInstancesPolicy))
+	// WARNING: in.TerminationPolicies requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceLaunchTemplates requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceHealthStatuses requires manual conversion: does not exist in peer-type
+	if in.MixedInstancesPolicy != nil {
+		in, out := &in.MixedInstancesPolicy, &out.MixedInstancesPolicy
+		*out = new(MixedInstancesPolicy)
+		if err := Convert_v1beta2_MixedInstancesPolicy_To_v1beta1_MixedInstancesPolicy(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.MixedInstancesPolicy = nil
+	}
+	// WARNING: in.HealthCheckType requires manual conversion: does not exist in peer-type
+	// WARNING: in.HealthCheckGracePeriod requires manual conversion: does not exist in peer-type
+	// WARNING: in.MaxInstanceLifetime requires manual conversion: does not exist in peer-type
+	// WARNING: in.CreatedTime requires manual conversion: does not exist in peer-type
 	out.Status = ASGStatus(in.Status)
 	out.Instances = *(*[]apiv1beta2.Instance)(unsafe.Pointer(&in.Instances))
 	// WARNING: in.CurrentlySuspendProcesses requires manual conversion: does not exist in peer-type
+	// WARNING: in.EnabledMetrics requires manual conversion: does not exist in peer-type
+	// WARNING: in.ProtectedInstances requires manual conversion: does not exist in peer-type
+	// WARNING: in.PropagatedTags requires manual conversion: does not exist in peer-type
+	// WARNING: in.NewInstancesProtectedFromScaleIn requires manual conversion: does not exist in peer-type
+	// WARNING: in.TargetGroupARNs requires manual conversion: does not exist in peer-type
+	// WARNING: in.LoadBalancerNames requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1008,7 +1128,17 @@ func Convert_v1beta2_ManagedRemoteAccess_To_v1beta1_ManagedRemoteAccess(in *v1be
 
 func autoConvert_v1beta1_MixedInstancesPolicy_To_v1beta2_MixedInstancesPolicy(in *MixedInstancesPolicy, out *v1beta2.MixedInstancesPolicy, s conversion.Scope) error {
 	out.InstancesDistribution = (*v1beta2.InstancesDistribution)(unsafe.Pointer(in.InstancesDistribution))
-	out.Overrides = *(*[]v1beta2.Overrides)(unsafe.Pointer(&in.Overrides))
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]v1beta2.Overrides, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_Overrides_To_v1beta2_Overrides(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Overrides = nil
+	}
 	return nil
 }
 
@@ -1019,7 +1149,17 @@ func Convert_v1beta1_MixedInstancesPolicy_To_v1beta2_MixedInstancesPolicy(in *Mi
 
 func autoConvert_v1beta2_MixedInstancesPolicy_To_v1beta1_MixedInstancesPolicy(in *v1beta2.MixedInstancesPolicy, out *MixedInstancesPolicy, s conversion.Scope) error {
 	out.InstancesDistribution = (*InstancesDistribution)(unsafe.Pointer(in.InstancesDistribution))
-	out.Overrides = *(*[]Overrides)(unsafe.Pointer(&in.Overrides))
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]Overrides, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta2_Overrides_To_v1beta1_Overrides(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Overrides = nil
+	}
 	return nil
 }
 
@@ -1040,14 +1180,11 @@ func Convert_v1beta1_Overrides_To_v1beta2_Overrides(in *Overrides, out *v1beta2.
 
 func autoConvert_v1beta2_Overrides_To_v1beta1_Overrides(in *v1beta2.Overrides, out *Overrides, s conversion.Scope) error {
 	out.InstanceType = in.InstanceType
+	// WARNING: in.InstanceRequirements requires manual conversion: does not exist in peer-type
+	// WARNING: in.WeightedCapacity requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta2_Overrides_To_v1beta1_Overrides is an autogenerated conversion function.
-func Convert_v1beta2_Overrides_To_v1beta1_Overrides(in *v1beta2.Overrides, out *Overrides, s conversion.Scope) error {
-	return autoConvert_v1beta2_Overrides_To_v1beta1_Overrides(in, out, s)
-}
-
 func autoConvert_v1beta1_RefreshPreferences_To_v1beta2_RefreshPreferences(in *RefreshPreferences, out *v1beta2.RefreshPreferences, s conversion.Scope) error {
 	out.Strategy = (*string)(unsafe.Pointer(in.Strategy))
 	out.InstanceWarmup = (*int64)(unsafe.Pointer(in.InstanceWarmup))
@@ -1062,9 +1199,14 @@ func Convert_v1beta1_RefreshPreferences_To_v1beta2_RefreshPreferences(in *Refres
 
 func autoConvert_v1beta2_RefreshPreferences_To_v1beta1_RefreshPreferences(in *v1beta2.RefreshPreferences, out *RefreshPreferences, s conversion.Scope) error {
 	// WARNING: in.Disable requires manual conversion: does not exist in peer-type
+	// WARNING: in.DisableInstanceRefreshCancellation requires manual conversion: does not exist in peer-type
 	out.Strategy = (*string)(unsafe.Pointer(in.Strategy))
 	out.InstanceWarmup = (*int64)(unsafe.Pointer(in.InstanceWarmup))
 	out.MinHealthyPercentage = (*int64)(unsafe.Pointer(in.MinHealthyPercentage))
+	// WARNING: in.SkipMatching requires manual conversion: does not exist in peer-type
+	// WARNING: in.AutoRollback requires manual conversion: does not exist in peer-type
+	// WARNING: in.CheckpointPercentages requires manual conversion: does not exist in peer-type
+	// WARNING: in.CheckpointDelay requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -18,9 +18,11 @@ package v1beta2
 
 import (
 	"reflect"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -31,6 +33,64 @@ import (
 const (
 	// LaunchTemplateLatestVersion defines the launching of the latest version of the template.
 	LaunchTemplateLatestVersion = "$Latest"
+
+	// LaunchTemplateDefaultVersion defines the launching of the default version of the template.
+	LaunchTemplateDefaultVersion = "$Default"
+
+	// ManagedLaunchLifecycleHookName is the name of the launch lifecycle hook managed by CAPA.
+	ManagedLaunchLifecycleHookName = infrav1.ReservedLifecycleHookNamePrefix + "managed-launch"
+
+	// DefaultManagedLaunchLifecycleHookTimeout is the default time to wait for the Node of a
+	// launching instance to become Ready.
+	DefaultManagedLaunchLifecycleHookTimeout = 10 * time.Minute
+
+	// MaxInstanceStatuses is the maximum number of instances listed in the status of an AWSMachinePool,
+	// which keeps the size of the object bounded for large Auto Scaling groups.
+	MaxInstanceStatuses = 200
+
+	// DefaultScaleUpDelayThreshold is the default time a scale-up can be outstanding before an event is emitted.
+	DefaultScaleUpDelayThreshold = 10 * time.Minute
+
+	// ScaleDownImmediatelyAnnotation, when set on an AWSMachinePool, bypasses its ScaleDownPolicy and
+	// scales the Auto Scaling group down to the MachinePool replicas in a single step.
+	ScaleDownImmediatelyAnnotation = "aws.cluster.x-k8s.io/scale-down-immediately"
+
+	// ExcludeFromRefreshAnnotation, when set on the Node of an instance of an AWSMachinePool, protects
+	// the instance from scale in, so that instance refreshes skip it. The protection is removed once
+	// the annotation is removed from the Node.
+	ExcludeFromRefreshAnnotation = "aws.cluster.x-k8s.io/exclude-from-refresh"
+
+	// AdoptAutoScalingGroupAnnotation, when set on an AWSMachinePool whose spec.autoScalingGroupName refers
+	// to an existing Auto Scaling group that isn't owned by the cluster, lets CAPA adopt the group instead of
+	// refusing to reconcile it. Groups owned by another cluster are never adopted.
+	AdoptAutoScalingGroupAnnotation = "aws.cluster.x-k8s.io/adopt-auto-scaling-group"
+
+	// ScaleInProtectionAnnotation, when set to "true" on the Machine or the Node of an instance of an
+	// AWSMachinePool, protects the instance from scale in. The protection is removed once the annotation
+	// is removed, or when the Machine is being deleted.
+	ScaleInProtectionAnnotation = "aws.cluster.x-k8s.io/scale-in-protection"
+
+	// DetachInstanceAnnotation, when set to "true" on a Machine of an AWSMachinePool, detaches the instance
+	// of the Machine from the Auto Scaling group when the Machine is deleted, instead of terminating it. The
+	// desired capacity of the group is left unchanged, so that the group launches a replacement. Machines only
+	// exist for the instances of a MachinePool when MachinePool Machines are created for it.
+	DetachInstanceAnnotation = "aws.cluster.x-k8s.io/detach-instance"
+
+	// DetachInstancesAnnotation, when set on an AWSMachinePool to a comma-separated list of instance IDs,
+	// detaches these instances from the Auto Scaling group, like the DetachInstanceAnnotation does for the
+	// instance of a Machine. The IDs of instances which aren't in the group are ignored.
+	DetachInstancesAnnotation = "aws.cluster.x-k8s.io/detach-instances"
+
+	// AZEvacuateAnnotation, when set on an AWSMachinePool to a comma-separated list of availability zones,
+	// e.g. during a zonal shift, removes the subnets of these zones from the Auto Scaling group. The subnets
+	// are added back once the zones are removed from the annotation. Availability zones whose evacuation
+	// would leave the group without subnets aren't evacuated.
+	AZEvacuateAnnotation = "aws.cluster.x-k8s.io/az-evacuate"
+
+	// AZEvacuateSurgeAnnotation, when set to "true" on an AWSMachinePool along with the AZEvacuateAnnotation,
+	// raises the desired capacity of the Auto Scaling group by the number of instances which ran in each
+	// availability zone when it was evacuated, up to the maximum size, until the zone is added back.
+	AZEvacuateSurgeAnnotation = "aws.cluster.x-k8s.io/az-evacuate-surge"
 )
 
 // AWSMachinePoolSpec defines the desired state of AWSMachinePool.
@@ -49,6 +109,18 @@ type AWSMachinePoolSpec struct {
 	// +kubebuilder:validation:Minimum=1
 	MaxSize int32 `json:"maxSize"`
 
+	// Autoscaling, when enabled, lets the cluster autoscaler scale the Auto Scaling group between its
+	// minimum and maximum size, which replace MinSize and MaxSize.
+	// +optional
+	Autoscaling *MachinePoolAutoscaling `json:"autoscaling,omitempty"`
+
+	// SyncSizeBoundsWithExternalAutoscaler keeps the minimum and maximum size of the Auto Scaling group in
+	// sync with MinSize and MaxSize when the replicas of the MachinePool are managed by an external
+	// autoscaler, i.e. the MachinePool has the cluster.x-k8s.io/replicas-managed-by annotation. The
+	// desired capacity, the minimum and the maximum size are otherwise all left to the external autoscaler.
+	// +optional
+	SyncSizeBoundsWithExternalAutoscaler bool `json:"syncSizeBoundsWithExternalAutoscaler,omitempty"`
+
 	// AvailabilityZones is an array of availability zones instances can run in
 	AvailabilityZones []string `json:"availabilityZones,omitempty"`
 
@@ -66,6 +138,18 @@ type AWSMachinePoolSpec struct {
 	// +optional
 	AdditionalTags infrav1.Tags `json:"additionalTags,omitempty"`
 
+	// ExcludedClusterTags are the keys of the additional tags of the AWSCluster which aren't added to the
+	// resources of the pool. The additional tags of the AWSMachinePool take precedence over the ones of
+	// the AWSCluster regardless.
+	// +optional
+	ExcludedClusterTags []string `json:"excludedClusterTags,omitempty"`
+
+	// PropagateTagsAtLaunch propagates the additional tags of the Auto Scaling group to the instances it
+	// launches. By default, the tags aren't propagated, as the launch template already sets them on the
+	// instances and their volumes.
+	// +optional
+	PropagateTagsAtLaunch bool `json:"propagateTagsAtLaunch,omitempty"`
+
 	// AWSLaunchTemplate specifies the launch template and version to use when an instance is launched.
 	// +kubebuilder:validation:Required
 	AWSLaunchTemplate AWSLaunchTemplate `json:"awsLaunchTemplate"`
@@ -83,24 +167,413 @@ type AWSMachinePoolSpec struct {
 	// +optional
 	DefaultCoolDown metav1.Duration `json:"defaultCoolDown,omitempty"`
 
-	// The amount of time, in seconds, until a new instance is considered to
-	// have finished initializing and resource consumption to become stable
-	// after it enters the InService state.
-	// If no value is supplied by user a default value of 300 seconds is set
+	// DefaultInstanceWarmup is the amount of time after an instance comes into service before it
+	// contributes to the metrics of the target tracking scaling policies and to the progress of the instance
+	// refreshes of the Auto Scaling group, in whole seconds. 0 explicitly disables the warmup, in which case
+	// the health check grace period is used instead. When unset, the default instance warmup of the Auto
+	// Scaling group is left unchanged, which is unset for a new Auto Scaling group.
 	// +optional
-	DefaultInstanceWarmup metav1.Duration `json:"defaultInstanceWarmup,omitempty"`
+	DefaultInstanceWarmup *metav1.Duration `json:"defaultInstanceWarmup,omitempty"`
 
 	// RefreshPreferences describes set of preferences associated with the instance refresh request.
 	// +optional
 	RefreshPreferences *RefreshPreferences `json:"refreshPreferences,omitempty"`
 
-	// Enable or disable the capacity rebalance autoscaling group feature
+	// Enable or disable the capacity rebalance autoscaling group feature, with which the ASG proactively
+	// replaces the spot instances that receive a rebalance recommendation. It is disabled when unset.
 	// +optional
 	CapacityRebalance bool `json:"capacityRebalance,omitempty"`
 
+	// TerminationPolicies are the policies the Auto Scaling group uses to select the instances to terminate
+	// when it scales in, in the order in which they are evaluated: Default, OldestInstance, NewestInstance,
+	// OldestLaunchConfiguration, OldestLaunchTemplate, ClosestToNextInstanceHour, AllocationStrategy, or the
+	// ARN of a Lambda function for a custom termination policy. When unset, the termination policies of the
+	// Auto Scaling group are left unchanged, which is Default for a new Auto Scaling group.
+	// +optional
+	TerminationPolicies []string `json:"terminationPolicies,omitempty"`
+
+	// HealthCheckType is the type of health check the Auto Scaling group uses to replace unhealthy
+	// instances. With ELB, the instances failing the health checks of the target groups of the
+	// LoadBalancerAttachments and TargetGroupARNs, or of the ClassicLoadBalancers, are replaced as well. When
+	// unset, the health check type of the Auto Scaling group is left unchanged, which is EC2 for a new Auto
+	// Scaling group.
+	// +kubebuilder:validation:Enum=EC2;ELB
+	// +optional
+	HealthCheckType HealthCheckType `json:"healthCheckType,omitempty"`
+
+	// HealthCheckGracePeriod is the amount of time after an instance comes into service before its
+	// health is checked, in whole seconds. When unset, the health check grace period of the Auto Scaling
+	// group is left unchanged, which is 0 for a new Auto Scaling group.
+	// +optional
+	HealthCheckGracePeriod *metav1.Duration `json:"healthCheckGracePeriod,omitempty"`
+
+	// MaxInstanceLifetime is the maximum amount of time an instance can be in service before the Auto
+	// Scaling group replaces it, between 1 day and 365 days in whole seconds. When unset or 0, the
+	// instances aren't replaced because of their age.
+	// +optional
+	MaxInstanceLifetime *metav1.Duration `json:"maxInstanceLifetime,omitempty"`
+
+	// MetricsCollection enables the group metrics of the Auto Scaling group, e.g. GroupDesiredCapacity, which
+	// are published to CloudWatch. When it is removed, the metrics enabled by CAPA are disabled, while metrics
+	// enabled outside of CAPA are left alone.
+	// +optional
+	MetricsCollection *MetricsCollection `json:"metricsCollection,omitempty"`
+
 	// SuspendProcesses defines a list of processes to suspend for the given ASG. This is constantly reconciled.
-	// If a process is removed from this list it will automatically be resumed.
+	// If a process is removed from this list it will automatically be resumed. Processes suspended outside of
+	// CAPA which aren't listed are left alone.
 	SuspendProcesses *SuspendProcessesTypes `json:"suspendProcesses,omitempty"`
+
+	// AWSLifecycleHooks specifies lifecycle hooks for the Auto Scaling group.
+	// +optional
+	AWSLifecycleHooks []infrav1.AWSLifecycleHook `json:"awsLifecycleHooks,omitempty"`
+
+	// ManagedLaunchLifecycleHook, if enabled, holds launching instances in the Pending:Wait state
+	// until their Node is Ready, so that they don't receive traffic before kubelet has registered.
+	// +optional
+	ManagedLaunchLifecycleHook *ManagedLaunchLifecycleHook `json:"managedLaunchLifecycleHook,omitempty"`
+
+	// ScaleUpDelayThreshold is the amount of time a scale-up can be outstanding before a warning
+	// event is emitted with the dominant error of the recent scaling activities. Defaults to 10m.
+	// +optional
+	// +kubebuilder:validation:Format=duration
+	ScaleUpDelayThreshold *metav1.Duration `json:"scaleUpDelayThreshold,omitempty"`
+
+	// NodeUserDataExtra defines commands and files that are added to the user data of the
+	// instances around the bootstrap data, without having to replace the bootstrap configuration.
+	// +optional
+	NodeUserDataExtra *NodeUserDataExtra `json:"nodeUserDataExtra,omitempty"`
+
+	// LoadBalancerAttachments attaches the Auto Scaling group to the target groups of additional listeners
+	// of the control plane load balancer, for example to make konnectivity agents reachable from the control
+	// plane. The node security group is opened to the control plane load balancer on the listener ports.
+	// Only applicable to AWSClusters with a network load balancer.
+	// +listType=map
+	// +listMapKey=listenerPort
+	// +optional
+	LoadBalancerAttachments []LoadBalancerAttachment `json:"loadBalancerAttachments,omitempty"`
+
+	// ScaleDownPolicy limits how many instances are removed from the Auto Scaling group at once when
+	// the MachinePool replicas are decreased, so that node drains are not rushed. Not applicable when
+	// the replicas are managed by an external autoscaler.
+	// +optional
+	ScaleDownPolicy *ScaleDownPolicy `json:"scaleDownPolicy,omitempty"`
+
+	// OS is the operating system of the nodes of the Auto Scaling group. When set to windows, the
+	// bootstrap data is framed as a PowerShell script, the AMI must be a Windows AMI and the security
+	// groups of the cluster allow the traffic used by the Windows CNI. Ignition and NodeUserDataExtra
+	// are not supported on Windows.
+	// When unset, the operating system is assumed to be linux and the AMI platform isn't validated.
+	// +optional
+	OS infrav1.OSType `json:"os,omitempty"`
+
+	// AMIVersionOverride is the Kubernetes version the AMI of the instances is looked up for, in preference
+	// to the version of the MachinePool, e.g. to keep launching the AMIs of the previous minor version for a
+	// canary period while the MachinePool reports the new version. It must be within the supported version
+	// skew of the control plane. Not applicable when awsLaunchTemplate.ami.id is set.
+	// +optional
+	AMIVersionOverride *string `json:"amiVersionOverride,omitempty"`
+
+	// AutoScalingGroupName is the name of the Auto Scaling group of the machine pool. Defaults to the name
+	// of the AWSMachinePool. An existing group with this name that isn't owned by the cluster is only
+	// adopted when the AWSMachinePool has the aws.cluster.x-k8s.io/adopt-auto-scaling-group annotation,
+	// and never when it is owned by another cluster. It can't be changed once the group exists.
+	// +kubebuilder:validation:MaxLength=255
+	// +optional
+	AutoScalingGroupName string `json:"autoScalingGroupName,omitempty"`
+
+	// KeepOnDelete leaves the Auto Scaling group and its launch template in place when the AWSMachinePool
+	// is deleted, e.g. to hand an adopted group back. The ownership tag of the cluster is removed from the
+	// group, so that it can be adopted again.
+	// +optional
+	KeepOnDelete bool `json:"keepOnDelete,omitempty"`
+
+	// DeletionTimeout bounds each stage of the deletion of the Auto Scaling group. The group is first scaled
+	// to zero, and deleted once its instances are terminated or the timeout expires. When AWS still rejects
+	// the deletion after the timeout, e.g. because of instances that fail to terminate, the group is deleted
+	// with ForceDelete, which terminates its instances without waiting for them. Defaults to the
+	// --machinepool-deletion-timeout flag of the controller.
+	// +optional
+	DeletionTimeout *metav1.Duration `json:"deletionTimeout,omitempty"`
+
+	// NewInstancesProtectedFromScaleIn protects the instances launched by the Auto Scaling group from scale
+	// in. The protection of the existing instances isn't changed.
+	// +optional
+	NewInstancesProtectedFromScaleIn bool `json:"newInstancesProtectedFromScaleIn,omitempty"`
+
+	// TargetGroupARNs are the ARNs of Elastic Load Balancing target groups the Auto Scaling group is
+	// attached to, so that its instances are registered to them, e.g. to expose workloads behind a network
+	// load balancer. Removing an ARN detaches the group from the target group. The target groups of the
+	// control plane load balancer are attached with LoadBalancerAttachments instead.
+	// +listType=set
+	// +optional
+	TargetGroupARNs []string `json:"targetGroupARNs,omitempty"`
+
+	// ClassicLoadBalancers are the names of Classic Load Balancers the Auto Scaling group is attached to,
+	// so that its instances are registered to them. Removing a name detaches the group from the load
+	// balancer.
+	// +listType=set
+	// +optional
+	ClassicLoadBalancers []string `json:"classicLoadBalancers,omitempty"`
+
+	// ScheduledActions are the scheduled actions of the Auto Scaling group, which change its size at given
+	// times, e.g. to scale up for working hours. Removing an action deletes it, while the scheduled actions
+	// created outside of CAPA are left alone. Unless the replicas of the MachinePool are managed by an
+	// external autoscaler, the scheduled actions win over the MachinePool replicas. The desired capacity of the
+	// Auto Scaling group, and its minimum and maximum size when an action sets them, are no longer reconciled
+	// once the group exists, and the desired capacity is reported in status.desiredCapacity.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ScheduledActions []ScheduledAction `json:"scheduledActions,omitempty"`
+
+	// InstancesReadyTimeout is how long the AWSMachinePool waits, after the Auto Scaling group is created, for
+	// enough of its instances to be InService before the ASGReady condition reports the shortfall with the
+	// InsufficientInstances reason. The AWSMachinePool becomes ready once the group has at least as many
+	// InService instances as the replicas of the MachinePool, or as its minimum size when the replicas are
+	// managed by an autoscaler. Defaults to 10 minutes.
+	// +optional
+	InstancesReadyTimeout *metav1.Duration `json:"instancesReadyTimeout,omitempty"`
+
+	// SpotInterruptionHandling, if enabled, deletes the Machine of an instance of the pool when EC2 notifies
+	// its interruption, so that its Node is drained before the instance is terminated. It requires the
+	// SpotInterruptionHandling feature flag.
+	// +optional
+	SpotInterruptionHandling *SpotInterruptionHandling `json:"spotInterruptionHandling,omitempty"`
+}
+
+// ScheduledAction changes the size of the Auto Scaling group once at StartTime, or on the schedule of
+// Recurrence. At least one of MinSize, MaxSize and DesiredCapacity is set.
+type ScheduledAction struct {
+	// Name is the name of the scheduled action, unique within the Auto Scaling group.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	Name string `json:"name"`
+
+	// Recurrence is the schedule of a recurring action, as a cron expression in the Minute Hour
+	// Day-of-month Month Day-of-week format, e.g. "0 8 * * 1-5" for 08:00 on weekdays.
+	// +optional
+	Recurrence string `json:"recurrence,omitempty"`
+
+	// TimeZone is the time zone of Recurrence, as a name of the IANA time zone database, e.g. Europe/Paris.
+	// Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// StartTime is the time at which the action runs once, or at which a recurring action starts.
+	// AWS reports the next run of a recurring action as its start time, so StartTime is only compared with
+	// the one of the Auto Scaling group while it is in the future.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// EndTime is the time after which a recurring action no longer runs.
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// MinSize is the minimum size the Auto Scaling group is set to.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinSize *int32 `json:"minSize,omitempty"`
+
+	// MaxSize is the maximum size the Auto Scaling group is set to.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxSize *int32 `json:"maxSize,omitempty"`
+
+	// DesiredCapacity is the desired capacity the Auto Scaling group is set to.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DesiredCapacity *int32 `json:"desiredCapacity,omitempty"`
+}
+
+// LoadBalancerAttachment references an additional listener of the control plane load balancer.
+type LoadBalancerAttachment struct {
+	// ListenerPort is the port of the additional listener of the control plane load balancer,
+	// as set in the AWSCluster spec.controlPlaneLoadBalancer.additionalListeners.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	ListenerPort int64 `json:"listenerPort"`
+}
+
+// ScaleDownPolicy splits a scale-down of the Auto Scaling group into steps. Each step decreases the
+// desired capacity by at most MaxUnavailable instances. The next step starts once the instances removed
+// by the previous step are terminated, including their terminate lifecycle hooks, and PauseSeconds elapsed.
+type ScaleDownPolicy struct {
+	// MaxUnavailable is the maximum number of instances removed from the Auto Scaling group in a single
+	// step. Value can be an absolute number (ex: 5) or a percentage of the desired capacity at the start
+	// of the step (ex: 10%). Percentages are rounded down, with a minimum of 1 instance. Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// PauseSeconds is the number of seconds to wait after a step before starting the next one.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PauseSeconds int32 `json:"pauseSeconds,omitempty"`
+}
+
+// ScaleDownStatus describes an in-flight scale-down limited by the ScaleDownPolicy.
+type ScaleDownStatus struct {
+	// DesiredCapacity is the desired capacity of the Auto Scaling group for the current step.
+	DesiredCapacity int32 `json:"desiredCapacity"`
+
+	// LastStepTime is the time at which the current step started.
+	// +optional
+	LastStepTime *metav1.Time `json:"lastStepTime,omitempty"`
+}
+
+// EvacuatedAvailabilityZone is an availability zone evacuated with the aws.cluster.x-k8s.io/az-evacuate
+// annotation.
+type EvacuatedAvailabilityZone struct {
+	// Name is the name of the availability zone.
+	Name string `json:"name"`
+
+	// Subnets are the subnets of the availability zone removed from the Auto Scaling group.
+	// +optional
+	Subnets []string `json:"subnets,omitempty"`
+
+	// Surge is the number of instances the desired capacity of the Auto Scaling group is raised by, which
+	// is the number of its instances in the availability zone when it was evacuated.
+	// +optional
+	Surge int32 `json:"surge,omitempty"`
+}
+
+// ASGDeletionPhase is a stage of the deletion of an Auto Scaling group.
+type ASGDeletionPhase string
+
+const (
+	// ASGDeletionPhaseScalingToZero is the stage where the Auto Scaling group is scaled to zero, and its
+	// instances are terminated.
+	ASGDeletionPhaseScalingToZero = ASGDeletionPhase("ScalingToZero")
+
+	// ASGDeletionPhaseDeleting is the stage where the Auto Scaling group is deleted, which AWS rejects while
+	// the group has instances or scaling activities in progress.
+	ASGDeletionPhaseDeleting = ASGDeletionPhase("Deleting")
+
+	// ASGDeletionPhaseForceDeleting is the stage where the Auto Scaling group is deleted with ForceDelete,
+	// which terminates its remaining instances without waiting for them.
+	ASGDeletionPhaseForceDeleting = ASGDeletionPhase("ForceDeleting")
+)
+
+// ASGDeletionStatus describes the deletion of the Auto Scaling group of a deleted AWSMachinePool.
+type ASGDeletionStatus struct {
+	// Phase is the current stage of the deletion.
+	// +kubebuilder:validation:Enum=ScalingToZero;Deleting;ForceDeleting
+	Phase ASGDeletionPhase `json:"phase"`
+
+	// PhaseStartTime is the time at which the current stage started.
+	// +optional
+	PhaseStartTime *metav1.Time `json:"phaseStartTime,omitempty"`
+}
+
+// InstanceRefreshStatus describes the last instance refresh started by CAPA on the Auto Scaling group.
+type InstanceRefreshStatus struct {
+	// ID is the ID of the instance refresh.
+	ID string `json:"id"`
+
+	// Status is the status of the instance refresh, as reported by AWS: Pending, InProgress,
+	// Successful, Failed, Cancelling, Cancelled, Baking, RollbackInProgress, RollbackSuccessful
+	// or RollbackFailed.
+	Status string `json:"status"`
+
+	// StatusReason is the explanation of the status given by AWS, e.g. why the instance refresh failed.
+	// +optional
+	StatusReason string `json:"statusReason,omitempty"`
+
+	// PercentageComplete is the percentage of the instance refresh that is complete.
+	// +optional
+	PercentageComplete int32 `json:"percentageComplete,omitempty"`
+
+	// InstancesToUpdate is the number of instances remaining to update.
+	// +optional
+	InstancesToUpdate int32 `json:"instancesToUpdate,omitempty"`
+
+	// StartTime is the time at which the instance refresh began.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+}
+
+// IsActive returns true if the instance refresh has not reached a final status yet.
+func (s *InstanceRefreshStatus) IsActive() bool {
+	switch s.Status {
+	case "Successful", "Failed", "Cancelled", "RollbackSuccessful", "RollbackFailed":
+		return false
+	}
+	return true
+}
+
+// IsCancelling returns true if the cancellation of the instance refresh was requested and is ongoing.
+func (s *InstanceRefreshStatus) IsCancelling() bool {
+	return s.Status == "Cancelling"
+}
+
+// IsSuccessful returns true if the instance refresh completed successfully.
+func (s *InstanceRefreshStatus) IsSuccessful() bool {
+	return s.Status == "Successful"
+}
+
+// ManagedLaunchLifecycleHook defines an EC2_INSTANCE_LAUNCHING lifecycle hook that is managed by CAPA.
+// CAPA completes the lifecycle action with CONTINUE once the Node of the instance is Ready. If the
+// Node is not Ready before the timeout elapses, the lifecycle action is abandoned and the instance
+// is replaced.
+type ManagedLaunchLifecycleHook struct {
+	// Enabled creates the managed launch lifecycle hook on the Auto Scaling group.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Timeout is the maximum time to wait for the Node of a launching instance to become Ready.
+	// Defaults to 10m.
+	// +optional
+	// +kubebuilder:validation:Format=duration
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// SpotInterruptionHandling defines how the instances of a machine pool are handled ahead of their interruption
+// by EC2. The Spot Instance Interruption Warnings and Rebalance Recommendations are received through an
+// EventBridge rule and an SQS queue of the cluster, which are deleted with the cluster.
+type SpotInterruptionHandling struct {
+	// Enabled deletes the Machine of an instance when EC2 issues a Spot Instance Interruption Warning for
+	// it, two minutes before the instance is interrupted. The instances without a Machine are terminated
+	// through the Auto Scaling group instead.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// RebalanceRecommendations also deletes the Machine of an instance when EC2 issues a Rebalance
+	// Recommendation for it, which usually comes earlier than the interruption warning, so that there is
+	// more time to drain its Node.
+	// +optional
+	RebalanceRecommendations bool `json:"rebalanceRecommendations,omitempty"`
+}
+
+// IsEnabled returns true if the spot interruption handling is enabled.
+func (h *SpotInterruptionHandling) IsEnabled() bool {
+	return h != nil && h.Enabled
+}
+
+// IsManagedLifecycleHookName returns true if name is the name of a lifecycle hook managed by CAPA.
+// Other names with the reserved prefix may be used by other versions of CAPA.
+func IsManagedLifecycleHookName(name string) bool {
+	return name == ManagedLaunchLifecycleHookName
+}
+
+// IsEnabled returns true if the managed launch lifecycle hook is enabled.
+func (h *ManagedLaunchLifecycleHook) IsEnabled() bool {
+	return h != nil && h.Enabled
+}
+
+// LifecycleHook returns the AWS lifecycle hook backing the managed launch lifecycle hook.
+func (h *ManagedLaunchLifecycleHook) LifecycleHook() infrav1.AWSLifecycleHook {
+	timeout := metav1.Duration{Duration: DefaultManagedLaunchLifecycleHookTimeout}
+	if h.Timeout != nil {
+		timeout = *h.Timeout
+	}
+	defaultResult := infrav1.LifecycleHookDefaultResultAbandon
+
+	return infrav1.AWSLifecycleHook{
+		Name:                ManagedLaunchLifecycleHookName,
+		LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch,
+		HeartbeatTimeout:    &timeout,
+		DefaultResult:       &defaultResult,
+	}
 }
 
 // SuspendProcessesTypes contains user friendly auto-completable values for suspended process names.
@@ -149,6 +622,24 @@ func (s *SuspendProcessesTypes) ConvertSetValuesToStringSlice() []string {
 	return result
 }
 
+// ConvertResumedValuesToStringSlice converts the values that are explicitly set to false into a string slice,
+// these processes must not be suspended even when All is set.
+func (s *SuspendProcessesTypes) ConvertResumedValuesToStringSlice() []string {
+	if s == nil || s.Processes == nil {
+		return nil
+	}
+
+	e := reflect.ValueOf(s.Processes).Elem()
+	var result []string
+	for i := 0; i < e.NumField(); i++ {
+		if !e.Field(i).IsNil() && !*e.Field(i).Interface().(*bool) {
+			result = append(result, e.Type().Field(i).Name)
+		}
+	}
+
+	return result
+}
+
 // RefreshPreferences defines the specs for instance refreshing.
 type RefreshPreferences struct {
 	// Disable, if true, disables instance refresh from triggering when new launch templates are detected.
@@ -156,6 +647,14 @@ type RefreshPreferences struct {
 	// +optional
 	Disable bool `json:"disable,omitempty"`
 
+	// DisableInstanceRefreshCancellation, if true, disables the cancellation of an instance refresh
+	// started by CAPA when the launch template changes before it completes. By default, the instance
+	// refresh is cancelled, so that instances stop being replaced with an outdated version of the
+	// launch template, e.g. after a faulty change was reverted, and a new instance refresh is started
+	// once the cancellation completes.
+	// +optional
+	DisableInstanceRefreshCancellation bool `json:"disableInstanceRefreshCancellation,omitempty"`
+
 	// The strategy to use for the instance refresh. The only valid value is Rolling.
 	// A rolling update is an update that is applied to all instances in an Auto
 	// Scaling group until all instances have been updated.
@@ -172,6 +671,28 @@ type RefreshPreferences struct {
 	// during an instance refresh. The default is 90.
 	// +optional
 	MinHealthyPercentage *int64 `json:"minHealthyPercentage,omitempty"`
+
+	// SkipMatching, if true, skips replacing the instances which already use the latest
+	// version of the launch template.
+	// +optional
+	SkipMatching *bool `json:"skipMatching,omitempty"`
+
+	// AutoRollback, if true, rolls back the ASG to its previous configuration if the
+	// instance refresh fails. AWS only supports rollbacks for ASGs which don't use the
+	// $Latest version of their launch template, e.g. with a pinned existing template.
+	// +optional
+	AutoRollback *bool `json:"autoRollback,omitempty"`
+
+	// CheckpointPercentages are the percentages of the instances to replace, in ascending
+	// order, after which the instance refresh pauses for CheckpointDelay. The last value
+	// must be 100 for all the instances to be replaced.
+	// +optional
+	CheckpointPercentages []int64 `json:"checkpointPercentages,omitempty"`
+
+	// CheckpointDelay is the number of seconds to wait after a checkpoint is reached.
+	// The default is 3600 seconds.
+	// +optional
+	CheckpointDelay *int64 `json:"checkpointDelay,omitempty"`
 }
 
 // AWSMachinePoolStatus defines the observed state of AWSMachinePool.
@@ -184,14 +705,33 @@ type AWSMachinePoolStatus struct {
 	// +optional
 	Replicas int32 `json:"replicas"`
 
+	// OutdatedReplicas is the number of instances of the Auto Scaling group which weren't launched from
+	// the current version of the launch template.
+	// +optional
+	OutdatedReplicas int32 `json:"outdatedReplicas"`
+
+	// ASGName is the name of the Auto Scaling group.
+	// +optional
+	ASGName string `json:"asgName,omitempty"`
+
+	// WeightedCapacity is true while the overrides of spec.mixedInstancesPolicy have a weighted capacity.
+	// The replicas of the MachinePool are then capacity units, while Replicas is the number of instances.
+	// +optional
+	WeightedCapacity bool `json:"weightedCapacity,omitempty"`
+
 	// Conditions defines current service state of the AWSMachinePool.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 
-	// Instances contains the status for each instance in the pool
+	// Instances contains the status for each instance in the pool, ordered by instance ID. At most
+	// MaxInstanceStatuses instances are listed.
 	// +optional
 	Instances []AWSMachinePoolInstanceStatus `json:"instances,omitempty"`
 
+	// ReadyReplicas is the number of instances of the Auto Scaling group which are InService and Healthy.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
 	// The ID of the launch template
 	LaunchTemplateID string `json:"launchTemplateID,omitempty"`
 
@@ -199,6 +739,131 @@ type AWSMachinePoolStatus struct {
 	// +optional
 	LaunchTemplateVersion *string `json:"launchTemplateVersion,omitempty"`
 
+	// LastLaunchTemplateChange describes the changes which led to the latest version of the launch template.
+	// +optional
+	LastLaunchTemplateChange *LaunchTemplateChange `json:"lastLaunchTemplateChange,omitempty"`
+
+	// ReplacedLaunchTemplateID is the ID of the launch template managed by CAPA which an existing launch
+	// template replaced. It is deleted once the Auto Scaling group uses the existing launch template.
+	// +optional
+	ReplacedLaunchTemplateID string `json:"replacedLaunchTemplateID,omitempty"`
+
+	// ScalingState contains the in-flight scaling state of the Auto Scaling group.
+	// +optional
+	ScalingState *ScalingState `json:"scalingState,omitempty"`
+
+	// PendingLifecycleActions lists the instances currently held in a wait state by a lifecycle hook.
+	// Entries are removed once the lifecycle actions complete.
+	// +optional
+	PendingLifecycleActions []PendingLifecycleAction `json:"pendingLifecycleActions,omitempty"`
+
+	// LifecycleHooks lists the lifecycle hooks of the Auto Scaling group: the lifecycle hooks of the
+	// resource policy and the default lifecycle hooks of the cluster, overridden by the lifecycle hooks
+	// of the AWSMachinePool with the same name, and the managed launch lifecycle hook.
+	// +optional
+	LifecycleHooks []LifecycleHookStatus `json:"lifecycleHooks,omitempty"`
+
+	// LifecycleHookCount is the number of lifecycle hooks of the Auto Scaling group.
+	// +optional
+	LifecycleHookCount int32 `json:"lifecycleHookCount"`
+
+	// LifecycleHooksObservedHash is a hash of the lifecycle hooks last successfully synced to the
+	// Auto Scaling group. The lifecycle hooks are only synced again when the hash changes, or when
+	// the periodic drift check is due.
+	// +optional
+	LifecycleHooksObservedHash string `json:"lifecycleHooksObservedHash,omitempty"`
+
+	// LifecycleHooksLastSyncTime is the last time the lifecycle hooks were synced to the Auto Scaling group.
+	// +optional
+	LifecycleHooksLastSyncTime *metav1.Time `json:"lifecycleHooksLastSyncTime,omitempty"`
+
+	// AdditionalTags are the effective additional tags of the Auto Scaling group, merged from the resource
+	// policy of the cluster, the cluster and the AWSMachinePool.
+	// +optional
+	AdditionalTags infrav1.Tags `json:"additionalTags,omitempty"`
+
+	// ScaleDown contains the state of an in-flight scale-down limited by the ScaleDownPolicy.
+	// +optional
+	ScaleDown *ScaleDownStatus `json:"scaleDown,omitempty"`
+
+	// EvacuatedAvailabilityZones lists the availability zones evacuated with the aws.cluster.x-k8s.io/az-evacuate
+	// annotation, with what was changed on the Auto Scaling group to evacuate them, so that it can be reverted
+	// once they are removed from the annotation.
+	// +optional
+	EvacuatedAvailabilityZones []EvacuatedAvailabilityZone `json:"evacuatedAvailabilityZones,omitempty"`
+
+	// RefreshExcludedInstances lists the instances protected from scale in by CAPA because their Node
+	// is annotated with aws.cluster.x-k8s.io/exclude-from-refresh. Instance refreshes skip them, so they
+	// keep running their launch template version until the annotation is removed.
+	// +optional
+	RefreshExcludedInstances []string `json:"refreshExcludedInstances,omitempty"`
+
+	// ScaleInProtectedInstances lists the instances protected from scale in by CAPA because their Machine or
+	// Node is annotated with aws.cluster.x-k8s.io/scale-in-protection=true. CAPA only removes the protection
+	// of these instances.
+	// +optional
+	ScaleInProtectedInstances []string `json:"scaleInProtectedInstances,omitempty"`
+
+	// AttachedTargetGroupARNs lists the target groups of spec.targetGroupARNs which CAPA attached the Auto
+	// Scaling group to. CAPA only detaches the group from these target groups once they are removed from
+	// the spec.
+	// +optional
+	AttachedTargetGroupARNs []string `json:"attachedTargetGroupARNs,omitempty"`
+
+	// AttachedClassicLoadBalancers lists the Classic Load Balancers of spec.classicLoadBalancers which CAPA
+	// attached the Auto Scaling group to. CAPA only detaches the group from these load balancers once they
+	// are removed from the spec.
+	// +optional
+	AttachedClassicLoadBalancers []string `json:"attachedClassicLoadBalancers,omitempty"`
+
+	// ScheduledActions lists the scheduled actions of spec.scheduledActions which CAPA created on the Auto
+	// Scaling group. CAPA only deletes these scheduled actions once they are removed from the spec.
+	// +optional
+	ScheduledActions []string `json:"scheduledActions,omitempty"`
+
+	// SpotInterruptionQueueARN is the ARN of the SQS queue of the cluster which receives the Spot Instance
+	// Interruption Warnings and Rebalance Recommendations of the instances of the pool.
+	// +optional
+	SpotInterruptionQueueARN string `json:"spotInterruptionQueueARN,omitempty"`
+
+	// DesiredCapacity is the desired capacity of the Auto Scaling group, which the scheduled actions may set
+	// regardless of the replicas of the MachinePool.
+	// +optional
+	DesiredCapacity *int32 `json:"desiredCapacity,omitempty"`
+
+	// InstanceRefresh contains the state of the last instance refresh started by CAPA. It is updated
+	// on each reconciliation until the instance refresh completes.
+	// +optional
+	InstanceRefresh *InstanceRefreshStatus `json:"instanceRefresh,omitempty"`
+
+	// SuspendedProcesses lists the processes of the ASG suspended by CAPA. They are resumed once they're
+	// removed from spec.suspendProcesses, while processes suspended outside of CAPA are left alone.
+	// +optional
+	SuspendedProcesses []string `json:"suspendedProcesses,omitempty"`
+
+	// EnabledMetrics lists the group metrics of the ASG enabled by CAPA. They're disabled once they're removed
+	// from spec.metricsCollection, while metrics enabled outside of CAPA are left alone.
+	// +optional
+	EnabledMetrics []string `json:"enabledMetrics,omitempty"`
+
+	// Version is the Kubernetes version of the MachinePool, which is reported to Cluster API.
+	// +optional
+	Version *string `json:"version,omitempty"`
+
+	// AMIKubernetesVersion is the Kubernetes version the AMI of the launch template was looked up for. It
+	// differs from Version while spec.amiVersionOverride is set, and is unset when the AMI isn't looked up.
+	// +optional
+	AMIKubernetesVersion *string `json:"amiKubernetesVersion,omitempty"`
+
+	// ScalingBlockers lists what may prevent the ASG from reaching its desired capacity, e.g. failed
+	// scaling activities, an instance refresh or suspended processes. It is computed on each reconciliation.
+	// +optional
+	ScalingBlockers []ScalingBlocker `json:"scalingBlockers,omitempty"`
+
+	// Deletion describes the deletion of the Auto Scaling group, once the AWSMachinePool is deleted.
+	// +optional
+	Deletion *ASGDeletionStatus `json:"deletion,omitempty"`
+
 	// FailureReason will be set in the event that there is a terminal problem
 	// reconciling the Machine and will contain a succinct value suitable
 	// for machine interpretation.
@@ -249,6 +914,31 @@ type AWSMachinePoolInstanceStatus struct {
 	// Version defines the Kubernetes version for the Machine Instance
 	// +optional
 	Version *string `json:"version,omitempty"`
+
+	// LaunchedAt is the start time of the scaling activity that launched the instance, when it is
+	// among the recent scaling activities of the Auto Scaling group.
+	// +optional
+	LaunchedAt *metav1.Time `json:"launchedAt,omitempty"`
+
+	// NodeRegisteredAt is the time the Node of the instance registered with the workload cluster.
+	// +optional
+	NodeRegisteredAt *metav1.Time `json:"nodeRegisteredAt,omitempty"`
+
+	// LifecycleState is the lifecycle state of the instance in the Auto Scaling group, e.g. InService.
+	// +optional
+	LifecycleState string `json:"lifecycleState,omitempty"`
+
+	// HealthStatus is the health status of the instance in the Auto Scaling group, Healthy or Unhealthy.
+	// +optional
+	HealthStatus string `json:"healthStatus,omitempty"`
+
+	// AvailabilityZone is the availability zone of the instance.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// LaunchTemplateVersion is the version of the launch template the instance was launched from.
+	// +optional
+	LaunchTemplateVersion string `json:"launchTemplateVersion,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -257,9 +947,15 @@ type AWSMachinePoolInstanceStatus struct {
 // +kubebuilder:resource:path=awsmachinepools,scope=Namespaced,categories=cluster-api,shortName=awsmp
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Machine ready status"
 // +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicas",description="Machine ready status"
+// +kubebuilder:printcolumn:name="Outdated",type="integer",JSONPath=".status.outdatedReplicas",description="Instances not launched from the current version of the launch template"
 // +kubebuilder:printcolumn:name="MinSize",type="integer",JSONPath=".spec.minSize",description="Minimum instanes in ASG"
 // +kubebuilder:printcolumn:name="MaxSize",type="integer",JSONPath=".spec.maxSize",description="Maximum instanes in ASG"
 // +kubebuilder:printcolumn:name="LaunchTemplate ID",type="string",JSONPath=".status.launchTemplateID",description="Launch Template ID"
+// +kubebuilder:printcolumn:name="LaunchTemplate Version",type="string",JSONPath=".status.launchTemplateVersion",description="Current version of the launch template"
+// +kubebuilder:printcolumn:name="ASG Name",type="string",JSONPath=".status.asgName",description="Name of the Auto Scaling group"
+// +kubebuilder:printcolumn:name="LifecycleHooks",type="integer",JSONPath=".status.lifecycleHookCount",description="Number of lifecycle hooks of the ASG"
+// +kubebuilder:printcolumn:name="LifecycleHooksReady",type="string",JSONPath=".status.conditions[?(@.type=='LifecycleHookExists')].status",description="Lifecycle hooks ready status"
+// +kubebuilder:printcolumn:name="LifecycleHooksReason",type="string",JSONPath=".status.conditions[?(@.type=='LifecycleHookExists')].reason",description="Reason the lifecycle hooks are not ready"
 
 // AWSMachinePool is the Schema for the awsmachinepools API.
 type AWSMachinePool struct {
@@ -293,6 +989,66 @@ func (r *AWSMachinePool) SetConditions(conditions clusterv1.Conditions) {
 	r.Status.Conditions = conditions
 }
 
+// SizeBounds returns the minimum and maximum size of the Auto Scaling group: the bounds of the
+// autoscaling when it is enabled, MinSize and MaxSize otherwise.
+func (r *AWSMachinePool) SizeBounds() (minSize, maxSize int32) {
+	if r.Spec.Autoscaling.IsEnabled() {
+		return r.Spec.Autoscaling.MinSize, r.Spec.Autoscaling.MaxSize
+	}
+	return r.Spec.MinSize, r.Spec.MaxSize
+}
+
+// ScheduledSizes returns whether the scheduled actions set the minimum size, the maximum size and the desired
+// capacity of the Auto Scaling group. The desired capacity is set by any scheduled action, as AWS moves it
+// within the minimum and maximum size.
+func (r *AWSMachinePool) ScheduledSizes() (minSize, maxSize, desiredCapacity bool) {
+	for _, action := range r.Spec.ScheduledActions {
+		minSize = minSize || action.MinSize != nil
+		maxSize = maxSize || action.MaxSize != nil
+	}
+	return minSize, maxSize, len(r.Spec.ScheduledActions) > 0
+}
+
+// EvacuationSurge returns the number of instances the desired capacity of the Auto Scaling group is raised by
+// while availability zones are evacuated.
+func (r *AWSMachinePool) EvacuationSurge() int32 {
+	var surge int32
+	for _, zone := range r.Status.EvacuatedAvailabilityZones {
+		surge += zone.Surge
+	}
+	return surge
+}
+
+// WithoutEvacuatedSubnets returns the subnets which weren't removed from the Auto Scaling group to evacuate
+// their availability zone.
+func (r *AWSMachinePool) WithoutEvacuatedSubnets(subnetIDs []string) []string {
+	if len(r.Status.EvacuatedAvailabilityZones) == 0 {
+		return subnetIDs
+	}
+
+	evacuated := map[string]bool{}
+	for _, zone := range r.Status.EvacuatedAvailabilityZones {
+		for _, id := range zone.Subnets {
+			evacuated[id] = true
+		}
+	}
+	res := make([]string, 0, len(subnetIDs))
+	for _, id := range subnetIDs {
+		if !evacuated[id] {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// ASGName returns the name of the Auto Scaling group of the pool, which defaults to the name of the pool.
+func (r *AWSMachinePool) ASGName() string {
+	if r.Spec.AutoScalingGroupName != "" {
+		return r.Spec.AutoScalingGroupName
+	}
+	return r.Name
+}
+
 // GetObjectKind will return the ObjectKind of an AWSMachinePool.
 func (r *AWSMachinePool) GetObjectKind() schema.ObjectKind {
 	return &r.TypeMeta
@@ -17,11 +17,22 @@ limitations under the License.
 package v1beta2
 
 import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -32,6 +43,44 @@ import (
 
 var log = ctrl.Log.WithName("awsmachinepool-resource")
 
+// maxCheckpointDelaySeconds is the maximum delay after a checkpoint of an instance refresh allowed by AWS.
+const maxCheckpointDelaySeconds = 172800
+
+// minMaxInstanceLifetime and maxMaxInstanceLifetime are the bounds of the max instance lifetime allowed by AWS.
+const (
+	minMaxInstanceLifetime = 24 * time.Hour
+	maxMaxInstanceLifetime = 365 * 24 * time.Hour
+)
+
+// terminationPolicies are the predefined termination policies of Auto Scaling groups.
+var terminationPolicies = sets.New(
+	"Default",
+	"OldestInstance",
+	"NewestInstance",
+	"OldestLaunchConfiguration",
+	"OldestLaunchTemplate",
+	"ClosestToNextInstanceHour",
+	"AllocationStrategy",
+)
+
+// onDemandAllocationStrategies are the allocation strategies of the On-Demand Instances of mixed instances policies.
+var onDemandAllocationStrategies = sets.New(
+	string(OnDemandAllocationStrategyPrioritized),
+	string(OnDemandAllocationStrategyLowestPrice),
+)
+
+// spotAllocationStrategies are the allocation strategies of the Spot Instances of mixed instances policies.
+var spotAllocationStrategies = sets.New(
+	string(SpotAllocationStrategyLowestPrice),
+	string(SpotAllocationStrategyCapacityOptimized),
+	string(SpotAllocationStrategyCapacityOptimizedPrioritized),
+	string(SpotAllocationStrategyPriceCapacityOptimized),
+)
+
+// classicLoadBalancerNameRegex matches the names of Classic Load Balancers: up to 32 alphanumeric characters or
+// hyphens, which don't start or end with a hyphen.
+var classicLoadBalancerNameRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,30}[a-zA-Z0-9])?$`)
+
 // SetupWebhookWithManager will setup the webhooks for the AWSMachinePool.
 func (r *AWSMachinePool) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
@@ -116,6 +165,494 @@ func (r *AWSMachinePool) validateSpotInstances() field.ErrorList {
 	return allErrs
 }
 
+// validateMixedInstancesPolicy checks the allocation strategies and On-Demand capacities of the instances distribution, that each override
+// sets either an instance type or instance requirements, that the ranges of the instance requirements are valid, and that the weighted
+// capacities are set for all the overrides of instance types or none.
+func (r *AWSMachinePool) validateMixedInstancesPolicy() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.MixedInstancesPolicy == nil {
+		return allErrs
+	}
+
+	if distribution := r.Spec.MixedInstancesPolicy.InstancesDistribution; distribution != nil {
+		distributionPath := field.NewPath("spec", "mixedInstancesPolicy", "instancesDistribution")
+		if strategy := distribution.OnDemandAllocationStrategy; strategy != "" && !onDemandAllocationStrategies.Has(string(strategy)) {
+			allErrs = append(allErrs, field.NotSupported(distributionPath.Child("onDemandAllocationStrategy"), strategy, sets.List(onDemandAllocationStrategies)))
+		}
+		if strategy := distribution.SpotAllocationStrategy; strategy != "" && !spotAllocationStrategies.Has(string(strategy)) {
+			allErrs = append(allErrs, field.NotSupported(distributionPath.Child("spotAllocationStrategy"), strategy, sets.List(spotAllocationStrategies)))
+		}
+		if capacity := distribution.OnDemandBaseCapacity; capacity != nil && *capacity < 0 {
+			allErrs = append(allErrs, field.Invalid(distributionPath.Child("onDemandBaseCapacity"), *capacity, "must be greater than or equal to 0"))
+		}
+		if percentage := distribution.OnDemandPercentageAboveBaseCapacity; percentage != nil && (*percentage < 0 || *percentage > 100) {
+			allErrs = append(allErrs, field.Invalid(distributionPath.Child("onDemandPercentageAboveBaseCapacity"), *percentage, "must be between 0 and 100"))
+		}
+	}
+
+	for i, override := range r.Spec.MixedInstancesPolicy.Overrides {
+		overridePath := field.NewPath("spec", "mixedInstancesPolicy", "overrides").Index(i)
+		switch {
+		case override.InstanceType != "" && override.InstanceRequirements != nil:
+			allErrs = append(allErrs, field.Forbidden(overridePath.Child("instanceRequirements"), "either instanceType or instanceRequirements should be used"))
+		case override.InstanceType == "" && override.InstanceRequirements == nil:
+			allErrs = append(allErrs, field.Required(overridePath.Child("instanceType"), "either instanceType or instanceRequirements is required"))
+		case override.InstanceRequirements != nil:
+			allErrs = append(allErrs, validateInstanceRequirements(overridePath.Child("instanceRequirements"), override.InstanceRequirements)...)
+		}
+
+		switch {
+		case override.WeightedCapacity == "":
+			if r.Spec.MixedInstancesPolicy.UsesWeightedCapacity() {
+				allErrs = append(allErrs, field.Required(overridePath.Child("weightedCapacity"), "must be set for all the overrides when it is set for one"))
+			}
+		case override.InstanceRequirements != nil:
+			allErrs = append(allErrs, field.Forbidden(overridePath.Child("weightedCapacity"), "can't be used with instanceRequirements"))
+		default:
+			if weight, err := strconv.Atoi(override.WeightedCapacity); err != nil || weight < 1 || weight > 999 {
+				allErrs = append(allErrs, field.Invalid(overridePath.Child("weightedCapacity"), override.WeightedCapacity, "must be a number between 1 and 999"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateInstanceRequirements(fldPath *field.Path, requirements *InstanceRequirements) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if requirements.VCPUCount.Max != nil && *requirements.VCPUCount.Max < requirements.VCPUCount.Min {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("vCpuCount", "max"), *requirements.VCPUCount.Max, "must not be lower than min"))
+	}
+	if requirements.MemoryMiB.Max != nil && *requirements.MemoryMiB.Max < requirements.MemoryMiB.Min {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("memoryMiB", "max"), *requirements.MemoryMiB.Max, "must not be lower than min"))
+	}
+	if count := requirements.AcceleratorCount; count != nil && count.Min != nil && count.Max != nil && *count.Max < *count.Min {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("acceleratorCount", "max"), *count.Max, "must not be lower than min"))
+	}
+	if len(requirements.AllowedInstanceTypes) > 0 && len(requirements.ExcludedInstanceTypes) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("excludedInstanceTypes"), "either allowedInstanceTypes or excludedInstanceTypes should be used"))
+	}
+
+	return allErrs
+}
+
+// validateLifecycleHooks validates the lifecycle hooks of the AWSMachinePool. The region of the cluster isn't
+// known to the webhook, so the partition of the ARNs is checked against it by the controller.
+func (r *AWSMachinePool) validateLifecycleHooks() field.ErrorList {
+	return v1beta2.ValidateLifecycleHooks(field.NewPath("spec", "awsLifecycleHooks"), r.Spec.AWSLifecycleHooks, "")
+}
+
+func (r *AWSMachinePool) validateManagedLaunchLifecycleHook() field.ErrorList {
+	var allErrs field.ErrorList
+
+	hook := r.Spec.ManagedLaunchLifecycleHook
+	if hook == nil || hook.Timeout == nil {
+		return allErrs
+	}
+
+	if hook.Timeout.Duration < 30*time.Second || hook.Timeout.Duration > 7200*time.Second {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "managedLaunchLifecycleHook", "timeout"), hook.Timeout.Duration.String(), "timeout must be between 30 seconds and 7200 seconds"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachinePool) validateNodeUserDataExtra() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.NodeUserDataExtra == nil {
+		return allErrs
+	}
+
+	for i, f := range r.Spec.NodeUserDataExtra.Files {
+		filePath := field.NewPath("spec", "nodeUserDataExtra", "files").Index(i)
+		if !strings.HasPrefix(f.Path, "/") {
+			allErrs = append(allErrs, field.Invalid(filePath.Child("path"), f.Path, "path must be absolute"))
+		}
+		if f.Permissions != "" {
+			if _, err := strconv.ParseUint(f.Permissions, 8, 32); err != nil {
+				allErrs = append(allErrs, field.Invalid(filePath.Child("permissions"), f.Permissions, "permissions must be in octal notation"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachinePool) validateOS() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.OS != v1beta2.OSTypeWindows {
+		return allErrs
+	}
+
+	if r.Spec.NodeUserDataExtra != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "nodeUserDataExtra"), "is not supported if spec.os is windows"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachinePool) validateScaleDownPolicy() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.ScaleDownPolicy == nil || r.Spec.ScaleDownPolicy.MaxUnavailable == nil {
+		return allErrs
+	}
+
+	maxUnavailable := r.Spec.ScaleDownPolicy.MaxUnavailable
+	maxUnavailablePath := field.NewPath("spec", "scaleDownPolicy", "maxUnavailable")
+	value, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, 100, false)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(maxUnavailablePath, maxUnavailable.String(), "must be an integer or a percentage"))
+	} else if value < 1 {
+		allErrs = append(allErrs, field.Invalid(maxUnavailablePath, maxUnavailable.String(), "must be greater than 0"))
+	}
+
+	return allErrs
+}
+
+// validateHealthCheck checks that the ELB health check type has target groups to take the health checks
+// from, and that the health check grace period is a non-negative number of seconds.
+func (r *AWSMachinePool) validateHealthCheck() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.HealthCheckType == HealthCheckTypeELB && len(r.Spec.LoadBalancerAttachments) == 0 && len(r.Spec.TargetGroupARNs) == 0 && len(r.Spec.ClassicLoadBalancers) == 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "healthCheckType"), r.Spec.HealthCheckType, "ELB health checks require at least one spec.loadBalancerAttachments, spec.targetGroupARNs or spec.classicLoadBalancers"))
+	}
+
+	if gracePeriod := r.Spec.HealthCheckGracePeriod; gracePeriod != nil {
+		gracePeriodPath := field.NewPath("spec", "healthCheckGracePeriod")
+		switch {
+		case gracePeriod.Duration < 0:
+			allErrs = append(allErrs, field.Invalid(gracePeriodPath, gracePeriod.Duration.String(), "must not be negative"))
+		case gracePeriod.Duration%time.Second != 0:
+			allErrs = append(allErrs, field.Invalid(gracePeriodPath, gracePeriod.Duration.String(), "must be a whole number of seconds"))
+		}
+	}
+
+	if warmup := r.Spec.DefaultInstanceWarmup; warmup != nil {
+		warmupPath := field.NewPath("spec", "defaultInstanceWarmup")
+		switch {
+		case warmup.Duration < 0:
+			allErrs = append(allErrs, field.Invalid(warmupPath, warmup.Duration.String(), "must not be negative"))
+		case warmup.Duration%time.Second != 0:
+			allErrs = append(allErrs, field.Invalid(warmupPath, warmup.Duration.String(), "must be a whole number of seconds"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateDeletionTimeout checks that the deletion timeout isn't negative.
+func (r *AWSMachinePool) validateDeletionTimeout() field.ErrorList {
+	if timeout := r.Spec.DeletionTimeout; timeout != nil && timeout.Duration < 0 {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "deletionTimeout"), timeout.Duration.String(), "must not be negative")}
+	}
+	return nil
+}
+
+// validateInstancesReadyTimeout checks that the instances ready timeout isn't negative.
+func (r *AWSMachinePool) validateInstancesReadyTimeout() field.ErrorList {
+	if timeout := r.Spec.InstancesReadyTimeout; timeout != nil && timeout.Duration < 0 {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "instancesReadyTimeout"), timeout.Duration.String(), "must not be negative")}
+	}
+	return nil
+}
+
+// validateMaxInstanceLifetime checks that the max instance lifetime is 0, or a whole number of seconds
+// within the bounds accepted by AWS.
+func (r *AWSMachinePool) validateMaxInstanceLifetime() field.ErrorList {
+	var allErrs field.ErrorList
+
+	lifetime := r.Spec.MaxInstanceLifetime
+	if lifetime == nil || lifetime.Duration == 0 {
+		return allErrs
+	}
+
+	lifetimePath := field.NewPath("spec", "maxInstanceLifetime")
+	switch {
+	case lifetime.Duration < minMaxInstanceLifetime || lifetime.Duration > maxMaxInstanceLifetime:
+		allErrs = append(allErrs, field.Invalid(lifetimePath, lifetime.Duration.String(), "must be 0, or between 1 day and 365 days"))
+	case lifetime.Duration%time.Second != 0:
+		allErrs = append(allErrs, field.Invalid(lifetimePath, lifetime.Duration.String(), "must be a whole number of seconds"))
+	}
+
+	return allErrs
+}
+
+// validateMetricsCollection checks that the metrics to collect are group metrics, each listed once.
+func (r *AWSMachinePool) validateMetricsCollection() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.MetricsCollection == nil {
+		return allErrs
+	}
+
+	metrics := sets.New(ASGMetrics...)
+	seen := sets.New[string]()
+	for i, metric := range r.Spec.MetricsCollection.Metrics {
+		metricPath := field.NewPath("spec", "metricsCollection", "metrics").Index(i)
+		switch {
+		case seen.Has(metric):
+			allErrs = append(allErrs, field.Duplicate(metricPath, metric))
+		case !metrics.Has(metric):
+			allErrs = append(allErrs, field.NotSupported(metricPath, metric, ASGMetrics))
+		}
+		seen.Insert(metric)
+	}
+
+	return allErrs
+}
+
+// validateTerminationPolicies checks that the termination policies are predefined policies or ARNs of
+// Lambda functions, each listed once.
+func (r *AWSMachinePool) validateTerminationPolicies() field.ErrorList {
+	var allErrs field.ErrorList
+
+	seen := sets.New[string]()
+	for i, policy := range r.Spec.TerminationPolicies {
+		policyPath := field.NewPath("spec", "terminationPolicies").Index(i)
+		if seen.Has(policy) {
+			allErrs = append(allErrs, field.Duplicate(policyPath, policy))
+			continue
+		}
+		seen.Insert(policy)
+
+		if terminationPolicies.Has(policy) {
+			continue
+		}
+		if policyARN, err := arn.Parse(policy); err == nil {
+			if policyARN.Service != "lambda" || !strings.HasPrefix(policyARN.Resource, "function:") {
+				allErrs = append(allErrs, field.Invalid(policyPath, policy, "must be the ARN of a Lambda function"))
+			}
+			continue
+		}
+		allErrs = append(allErrs, field.NotSupported(policyPath, policy, append(sets.List(terminationPolicies), "<Lambda function ARN>")))
+	}
+
+	return allErrs
+}
+
+// validateTargetGroupARNs checks that the target groups are Elastic Load Balancing target group ARNs of a
+// known partition, all in the same partition, each listed once.
+func (r *AWSMachinePool) validateTargetGroupARNs() field.ErrorList {
+	var allErrs field.ErrorList
+
+	partitions := sets.New[string]()
+	for _, p := range endpoints.DefaultPartitions() {
+		partitions.Insert(p.ID())
+	}
+
+	seen := sets.New[string]()
+	partition := ""
+	for i, targetGroupARN := range r.Spec.TargetGroupARNs {
+		targetGroupPath := field.NewPath("spec", "targetGroupARNs").Index(i)
+		if seen.Has(targetGroupARN) {
+			allErrs = append(allErrs, field.Duplicate(targetGroupPath, targetGroupARN))
+			continue
+		}
+		seen.Insert(targetGroupARN)
+
+		parsed, err := arn.Parse(targetGroupARN)
+		if err != nil || parsed.Service != "elasticloadbalancing" || !strings.HasPrefix(parsed.Resource, "targetgroup/") || parsed.Region == "" || parsed.AccountID == "" {
+			allErrs = append(allErrs, field.Invalid(targetGroupPath, targetGroupARN, "must be the ARN of an Elastic Load Balancing target group"))
+			continue
+		}
+		switch {
+		case !partitions.Has(parsed.Partition):
+			allErrs = append(allErrs, field.Invalid(targetGroupPath, targetGroupARN, fmt.Sprintf("unknown partition %q", parsed.Partition)))
+		case partition == "":
+			partition = parsed.Partition
+		case parsed.Partition != partition:
+			allErrs = append(allErrs, field.Invalid(targetGroupPath, targetGroupARN, fmt.Sprintf("must be in the %q partition of the other target groups", partition)))
+		}
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachinePool) validateClassicLoadBalancers() field.ErrorList {
+	var allErrs field.ErrorList
+
+	seen := sets.New[string]()
+	for i, name := range r.Spec.ClassicLoadBalancers {
+		namePath := field.NewPath("spec", "classicLoadBalancers").Index(i)
+		if seen.Has(name) {
+			allErrs = append(allErrs, field.Duplicate(namePath, name))
+			continue
+		}
+		seen.Insert(name)
+
+		if !classicLoadBalancerNameRegex.MatchString(name) {
+			allErrs = append(allErrs, field.Invalid(namePath, name, "must be the name of a Classic Load Balancer: up to 32 alphanumeric characters or hyphens, not starting or ending with a hyphen"))
+		}
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachinePool) validateScheduledActions() field.ErrorList {
+	var allErrs field.ErrorList
+
+	seen := sets.New[string]()
+	for i, action := range r.Spec.ScheduledActions {
+		actionPath := field.NewPath("spec", "scheduledActions").Index(i)
+		if seen.Has(action.Name) {
+			allErrs = append(allErrs, field.Duplicate(actionPath.Child("name"), action.Name))
+			continue
+		}
+		seen.Insert(action.Name)
+
+		if action.MinSize == nil && action.MaxSize == nil && action.DesiredCapacity == nil {
+			allErrs = append(allErrs, field.Required(actionPath, "at least one of minSize, maxSize and desiredCapacity must be set"))
+		}
+		if action.MinSize != nil && action.MaxSize != nil && *action.MinSize > *action.MaxSize {
+			allErrs = append(allErrs, field.Invalid(actionPath.Child("minSize"), *action.MinSize, "must be less than or equal to maxSize"))
+		}
+		if action.DesiredCapacity != nil {
+			if (action.MinSize != nil && *action.DesiredCapacity < *action.MinSize) || (action.MaxSize != nil && *action.DesiredCapacity > *action.MaxSize) {
+				allErrs = append(allErrs, field.Invalid(actionPath.Child("desiredCapacity"), *action.DesiredCapacity, "must be between minSize and maxSize"))
+			}
+		}
+
+		if action.Recurrence == "" && action.StartTime == nil {
+			allErrs = append(allErrs, field.Required(actionPath, "either recurrence or startTime must be set"))
+		}
+		if action.Recurrence != "" && len(strings.Fields(action.Recurrence)) != 5 {
+			allErrs = append(allErrs, field.Invalid(actionPath.Child("recurrence"), action.Recurrence, "must be a cron expression in the Minute Hour Day-of-month Month Day-of-week format"))
+		}
+		if action.StartTime != nil && action.EndTime != nil && !action.EndTime.After(action.StartTime.Time) {
+			allErrs = append(allErrs, field.Invalid(actionPath.Child("endTime"), action.EndTime, "must be after startTime"))
+		}
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachinePool) validateRefreshPreferences() field.ErrorList {
+	var allErrs field.ErrorList
+
+	preferences := r.Spec.RefreshPreferences
+	if preferences == nil {
+		return allErrs
+	}
+
+	preferencesPath := field.NewPath("spec", "refreshPreferences")
+	if preferences.InstanceWarmup != nil && *preferences.InstanceWarmup < 0 {
+		allErrs = append(allErrs, field.Invalid(preferencesPath.Child("instanceWarmup"), *preferences.InstanceWarmup, "must be greater than or equal to 0"))
+	}
+	if preferences.MinHealthyPercentage != nil && (*preferences.MinHealthyPercentage < 0 || *preferences.MinHealthyPercentage > 100) {
+		allErrs = append(allErrs, field.Invalid(preferencesPath.Child("minHealthyPercentage"), *preferences.MinHealthyPercentage, "must be between 0 and 100"))
+	}
+
+	checkpointsPath := preferencesPath.Child("checkpointPercentages")
+	for i, percentage := range preferences.CheckpointPercentages {
+		if percentage < 1 || percentage > 100 {
+			allErrs = append(allErrs, field.Invalid(checkpointsPath.Index(i), percentage, "must be between 1 and 100"))
+		} else if i > 0 && percentage <= preferences.CheckpointPercentages[i-1] {
+			allErrs = append(allErrs, field.Invalid(checkpointsPath.Index(i), percentage, "checkpoint percentages must be in ascending order"))
+		}
+	}
+
+	if preferences.CheckpointDelay != nil {
+		delayPath := preferencesPath.Child("checkpointDelay")
+		if len(preferences.CheckpointPercentages) == 0 {
+			allErrs = append(allErrs, field.Forbidden(delayPath, "requires spec.refreshPreferences.checkpointPercentages"))
+		}
+		if *preferences.CheckpointDelay < 0 || *preferences.CheckpointDelay > maxCheckpointDelaySeconds {
+			allErrs = append(allErrs, field.Invalid(delayPath, *preferences.CheckpointDelay, fmt.Sprintf("must be between 0 and %d", maxCheckpointDelaySeconds)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateAutoscaling validates the autoscaling of an AWSMachinePool or an AWSManagedMachinePool.
+func validateAutoscaling(autoscaling *MachinePoolAutoscaling) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !autoscaling.IsEnabled() {
+		return allErrs
+	}
+
+	if autoscaling.MaxSize < autoscaling.MinSize {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "autoscaling", "maxSize"), autoscaling.MaxSize, "must be greater than or equal to spec.autoscaling.minSize"))
+	}
+
+	return allErrs
+}
+
+// validateAMIVersionOverride validates the AMI version override of an AWSMachinePool or an AWSManagedMachinePool.
+// Its skew from the control plane is validated by the controllers, which know the version of the control plane.
+func validateAMIVersionOverride(override *string) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if override == nil {
+		return allErrs
+	}
+
+	if _, err := version.ParseGeneric(*override); err != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "amiVersionOverride"), *override, "must be a Kubernetes version, e.g. v1.28 or v1.28.5"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachinePool) validateAMIVersionOverride() field.ErrorList {
+	allErrs := validateAMIVersionOverride(r.Spec.AMIVersionOverride)
+
+	if r.Spec.AMIVersionOverride != nil && r.Spec.AWSLaunchTemplate.AMI.ID != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "amiVersionOverride"), "can't be set together with spec.awsLaunchTemplate.ami.id"))
+	}
+
+	return allErrs
+}
+
+func (r *AWSMachinePool) validateExistingLaunchTemplate() field.ErrorList {
+	var allErrs field.ErrorList
+
+	lt := r.Spec.AWSLaunchTemplate
+	if lt.ExistingTemplate == nil {
+		return allErrs
+	}
+
+	// These fields only apply to a launch template managed by CAPA.
+	managedFields := []struct {
+		name string
+		set  bool
+	}{
+		{"name", lt.Name != ""},
+		{"iamInstanceProfile", lt.IamInstanceProfile != ""},
+		{"ami", !reflect.DeepEqual(lt.AMI, v1beta2.AMIReference{})},
+		{"imageLookupFormat", lt.ImageLookupFormat != ""},
+		{"imageLookupOrg", lt.ImageLookupOrg != ""},
+		{"imageLookupBaseOS", lt.ImageLookupBaseOS != ""},
+		{"instanceType", lt.InstanceType != ""},
+		{"rootVolume", lt.RootVolume != nil},
+		{"sshKeyName", lt.SSHKeyName != nil},
+		{"versionNumber", lt.VersionNumber != nil},
+		{"additionalSecurityGroups", len(lt.AdditionalSecurityGroups) > 0},
+		{"spotMarketOptions", lt.SpotMarketOptions != nil},
+		{"instanceMetadataOptions", lt.InstanceMetadataOptions != nil},
+		{"privateDnsName", lt.PrivateDNSName != nil},
+		{"userDataTransform", lt.UserDataTransform != nil},
+	}
+	for _, f := range managedFields {
+		if f.set {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "awsLaunchTemplate", f.name), "can't be combined with spec.awsLaunchTemplate.existingTemplate"))
+		}
+	}
+
+	if r.Spec.NodeUserDataExtra != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "nodeUserDataExtra"), "can't be combined with spec.awsLaunchTemplate.existingTemplate, the user data comes from the existing launch template"))
+	}
+
+	return allErrs
+}
+
 // ValidateCreate will do any extra validation when creating a AWSMachinePool.
 func (r *AWSMachinePool) ValidateCreate() (admission.Warnings, error) {
 	log.Info("AWSMachinePool validate create", "machine-pool", klog.KObj(r))
@@ -128,6 +665,26 @@ func (r *AWSMachinePool) ValidateCreate() (admission.Warnings, error) {
 	allErrs = append(allErrs, r.validateSubnets()...)
 	allErrs = append(allErrs, r.validateAdditionalSecurityGroups()...)
 	allErrs = append(allErrs, r.validateSpotInstances()...)
+	allErrs = append(allErrs, r.validateMixedInstancesPolicy()...)
+	allErrs = append(allErrs, r.validateLifecycleHooks()...)
+	allErrs = append(allErrs, r.validateManagedLaunchLifecycleHook()...)
+	allErrs = append(allErrs, r.validateNodeUserDataExtra()...)
+	allErrs = append(allErrs, r.validateScaleDownPolicy()...)
+	allErrs = append(allErrs, r.validateRefreshPreferences()...)
+	allErrs = append(allErrs, r.validateTerminationPolicies()...)
+	allErrs = append(allErrs, r.validateTargetGroupARNs()...)
+	allErrs = append(allErrs, r.validateClassicLoadBalancers()...)
+	allErrs = append(allErrs, r.validateScheduledActions()...)
+	allErrs = append(allErrs, r.validateHealthCheck()...)
+	allErrs = append(allErrs, r.validateMaxInstanceLifetime()...)
+	allErrs = append(allErrs, r.validateDeletionTimeout()...)
+	allErrs = append(allErrs, r.validateInstancesReadyTimeout()...)
+	allErrs = append(allErrs, r.validateMetricsCollection()...)
+	allErrs = append(allErrs, r.validateAMIVersionOverride()...)
+	allErrs = append(allErrs, validateAutoscaling(r.Spec.Autoscaling)...)
+	allErrs = append(allErrs, r.validateOS()...)
+	allErrs = append(allErrs, r.Spec.AWSLaunchTemplate.UserDataTransform.Validate(field.NewPath("spec", "awsLaunchTemplate", "userDataTransform"))...)
+	allErrs = append(allErrs, r.validateExistingLaunchTemplate()...)
 
 	if len(allErrs) == 0 {
 		return nil, nil
@@ -141,14 +698,51 @@ func (r *AWSMachinePool) ValidateCreate() (admission.Warnings, error) {
 }
 
 // ValidateUpdate will do any extra validation when updating a AWSMachinePool.
-func (r *AWSMachinePool) ValidateUpdate(_ runtime.Object) (admission.Warnings, error) {
+func (r *AWSMachinePool) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
 	var allErrs field.ErrorList
 
+	oldPool, ok := old.(*AWSMachinePool)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an AWSMachinePool but got a %T", old))
+	}
+
+	// An existing launch template can replace the CAPA managed one, which is deleted once the ASG uses it,
+	// but CAPA can't take over from an existing launch template.
+	if oldPool.Spec.AWSLaunchTemplate.ExistingTemplate != nil && r.Spec.AWSLaunchTemplate.ExistingTemplate == nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "awsLaunchTemplate", "existingTemplate"), "can't be removed after creation"))
+	}
+
+	// The Auto Scaling group of the pool can't be swapped for another one once it exists. Until then,
+	// the name can still be fixed, e.g. after it collided with a group which isn't owned by the cluster.
+	if oldPool.Status.ASGName != "" && oldPool.ASGName() != r.ASGName() {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "autoScalingGroupName"), r.Spec.AutoScalingGroupName, "can't be changed once the Auto Scaling group exists"))
+	}
+
 	allErrs = append(allErrs, r.validateDefaultCoolDown()...)
 	allErrs = append(allErrs, r.Spec.AdditionalTags.Validate()...)
 	allErrs = append(allErrs, r.validateSubnets()...)
 	allErrs = append(allErrs, r.validateAdditionalSecurityGroups()...)
 	allErrs = append(allErrs, r.validateSpotInstances()...)
+	allErrs = append(allErrs, r.validateMixedInstancesPolicy()...)
+	allErrs = append(allErrs, r.validateLifecycleHooks()...)
+	allErrs = append(allErrs, r.validateManagedLaunchLifecycleHook()...)
+	allErrs = append(allErrs, r.validateNodeUserDataExtra()...)
+	allErrs = append(allErrs, r.validateScaleDownPolicy()...)
+	allErrs = append(allErrs, r.validateRefreshPreferences()...)
+	allErrs = append(allErrs, r.validateTerminationPolicies()...)
+	allErrs = append(allErrs, r.validateTargetGroupARNs()...)
+	allErrs = append(allErrs, r.validateClassicLoadBalancers()...)
+	allErrs = append(allErrs, r.validateScheduledActions()...)
+	allErrs = append(allErrs, r.validateHealthCheck()...)
+	allErrs = append(allErrs, r.validateMaxInstanceLifetime()...)
+	allErrs = append(allErrs, r.validateDeletionTimeout()...)
+	allErrs = append(allErrs, r.validateInstancesReadyTimeout()...)
+	allErrs = append(allErrs, r.validateMetricsCollection()...)
+	allErrs = append(allErrs, r.validateAMIVersionOverride()...)
+	allErrs = append(allErrs, validateAutoscaling(r.Spec.Autoscaling)...)
+	allErrs = append(allErrs, r.validateOS()...)
+	allErrs = append(allErrs, r.Spec.AWSLaunchTemplate.UserDataTransform.Validate(field.NewPath("spec", "awsLaunchTemplate", "userDataTransform"))...)
+	allErrs = append(allErrs, r.validateExistingLaunchTemplate()...)
 
 	if len(allErrs) == 0 {
 		return nil, nil
@@ -173,8 +767,8 @@ func (r *AWSMachinePool) Default() {
 		r.Spec.DefaultCoolDown.Duration = 300 * time.Second
 	}
 
-	if int(r.Spec.DefaultInstanceWarmup.Duration.Seconds()) == 0 {
-		log.Info("DefaultInstanceWarmup is zero, setting 300 seconds as default")
-		r.Spec.DefaultInstanceWarmup.Duration = 300 * time.Second
+	if r.Spec.ManagedLaunchLifecycleHook.IsEnabled() && r.Spec.ManagedLaunchLifecycleHook.Timeout == nil {
+		log.Info("ManagedLaunchLifecycleHook timeout is not set, setting 10 minutes as default")
+		r.Spec.ManagedLaunchLifecycleHook.Timeout = &metav1.Duration{Duration: DefaultManagedLaunchLifecycleHookTimeout}
 	}
 }
@@ -19,10 +19,14 @@ package v1beta2
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
@@ -35,6 +39,10 @@ func TestAWSMachinePoolDefault(t *testing.T) {
 	m.Default()
 	g := NewWithT(t)
 	g.Expect(m.Spec.DefaultCoolDown.Duration).To(BeNumerically(">=", 0))
+
+	m.Spec.ManagedLaunchLifecycleHook = &ManagedLaunchLifecycleHook{Enabled: true}
+	m.Default()
+	g.Expect(m.Spec.ManagedLaunchLifecycleHook.Timeout).To(Equal(&metav1.Duration{Duration: DefaultManagedLaunchLifecycleHookTimeout}))
 }
 
 func TestAWSMachinePoolValidateCreate(t *testing.T) {
@@ -153,113 +161,191 @@ func TestAWSMachinePoolValidateCreate(t *testing.T) {
 			},
 			wantErr: true,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			warn, err := tt.pool.ValidateCreate()
-			if tt.wantErr {
-				g.Expect(err).To(HaveOccurred())
-			} else {
-				g.Expect(err).To(Succeed())
-			}
-			// Nothing emits warnings yet
-			g.Expect(warn).To(BeEmpty())
-		})
-	}
-}
-
-func TestAWSMachinePoolValidateUpdate(t *testing.T) {
-	g := NewWithT(t)
-
-	tests := []struct {
-		name    string
-		new     *AWSMachinePool
-		old     *AWSMachinePool
-		wantErr bool
-	}{
 		{
-			name: "adding tags is accepted",
-			old: &AWSMachinePool{
+			name: "Should pass if mixed instances policy overrides use instance requirements",
+			pool: &AWSMachinePool{
 				Spec: AWSMachinePoolSpec{
-					AdditionalTags: infrav1.Tags{
-						"key-1": "value-1",
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						Overrides: []Overrides{
+							{InstanceType: "t3.medium"},
+							{InstanceRequirements: &InstanceRequirements{
+								VCPUCount:             VCPUCountRequest{Min: 2, Max: aws.Int64(8)},
+								MemoryMiB:             MemoryMiBRequest{Min: 4096},
+								CPUManufacturers:      []CPUManufacturer{CPUManufacturerIntel, CPUManufacturerAMD},
+								ExcludedInstanceTypes: []string{"t2.*"},
+								BurstablePerformance:  InstanceRequirementsFilterExcluded,
+								AcceleratorCount:      &AcceleratorCountRequest{Max: aws.Int64(0)},
+							}},
+						},
 					},
 				},
 			},
-			new: &AWSMachinePool{
+			wantErr: false,
+		},
+		{
+			name: "Should fail if a mixed instances policy override sets both instance type and instance requirements",
+			pool: &AWSMachinePool{
 				Spec: AWSMachinePoolSpec{
-					AdditionalTags: infrav1.Tags{
-						"key-1": "value-1",
-						"key-2": "value-2",
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						Overrides: []Overrides{{
+							InstanceType:         "t3.medium",
+							InstanceRequirements: &InstanceRequirements{VCPUCount: VCPUCountRequest{Min: 2}, MemoryMiB: MemoryMiBRequest{Min: 4096}},
+						}},
 					},
 				},
 			},
-			wantErr: false,
+			wantErr: true,
 		},
 		{
-			name: "adding invalid tags is rejected",
-			old: &AWSMachinePool{
+			name: "Should fail if a mixed instances policy override sets neither instance type nor instance requirements",
+			pool: &AWSMachinePool{
 				Spec: AWSMachinePoolSpec{
-					AdditionalTags: infrav1.Tags{
-						"key-1": "value-1",
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						Overrides: []Overrides{{}},
 					},
 				},
 			},
-			new: &AWSMachinePool{
+			wantErr: true,
+		},
+		{
+			name: "Should fail if the maximum vCPU count of instance requirements is lower than the minimum",
+			pool: &AWSMachinePool{
 				Spec: AWSMachinePoolSpec{
-					AdditionalTags: infrav1.Tags{
-						"key-1":                    "value-1",
-						"":                         "value-2",
-						strings.Repeat("CAPI", 33): "value-3",
-						"key-4":                    strings.Repeat("CAPI", 65),
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						Overrides: []Overrides{{
+							InstanceRequirements: &InstanceRequirements{VCPUCount: VCPUCountRequest{Min: 8, Max: aws.Int64(4)}, MemoryMiB: MemoryMiBRequest{Min: 4096}},
+						}},
 					},
 				},
 			},
 			wantErr: true,
 		},
 		{
-			name: "Should fail update if both subnetID and filters passed in AWSMachinePool spec",
-			old: &AWSMachinePool{
+			name: "Should fail if instance requirements both allow and exclude instance types",
+			pool: &AWSMachinePool{
 				Spec: AWSMachinePoolSpec{
-					AdditionalTags: infrav1.Tags{
-						"key-1": "value-1",
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						Overrides: []Overrides{{
+							InstanceRequirements: &InstanceRequirements{
+								VCPUCount:             VCPUCountRequest{Min: 2},
+								MemoryMiB:             MemoryMiBRequest{Min: 4096},
+								AllowedInstanceTypes:  []string{"m5.*"},
+								ExcludedInstanceTypes: []string{"m5.metal"},
+							},
+						}},
 					},
 				},
 			},
-			new: &AWSMachinePool{
+			wantErr: true,
+		},
+		{
+			name: "Should pass if the allocation strategies are supported",
+			pool: &AWSMachinePool{
 				Spec: AWSMachinePoolSpec{
-					AdditionalTags: infrav1.Tags{
-						"key-1": "value-1",
-						"key-2": "value-2",
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						InstancesDistribution: &InstancesDistribution{
+							OnDemandAllocationStrategy: OnDemandAllocationStrategyLowestPrice,
+							SpotAllocationStrategy:     SpotAllocationStrategyPriceCapacityOptimized,
+						},
+						Overrides: []Overrides{{InstanceType: "m5.large"}},
 					},
-					Subnets: []infrav1.AWSResourceReference{
-						{
-							ID:      ptr.To[string]("subnet-id"),
-							Filters: []infrav1.Filter{{Name: "filter_name", Values: []string{"filter_value"}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should fail if the on-demand allocation strategy isn't supported",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						InstancesDistribution: &InstancesDistribution{
+							OnDemandAllocationStrategy: OnDemandAllocationStrategy("capacity-optimized"),
 						},
+						Overrides: []Overrides{{InstanceType: "m5.large"}},
 					},
 				},
 			},
 			wantErr: true,
 		},
 		{
-			name: "Should pass update if either subnetID or filters passed in AWSMachinePool spec",
-			old: &AWSMachinePool{
+			name: "Should fail if the spot allocation strategy isn't supported",
+			pool: &AWSMachinePool{
 				Spec: AWSMachinePoolSpec{
-					AdditionalTags: infrav1.Tags{
-						"key-1": "value-1",
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						InstancesDistribution: &InstancesDistribution{
+							SpotAllocationStrategy: SpotAllocationStrategy("prioritized"),
+						},
+						Overrides: []Overrides{{InstanceType: "m5.large"}},
 					},
 				},
 			},
-			new: &AWSMachinePool{
+			wantErr: true,
+		},
+		{
+			name: "Should pass if only spot instances are launched above a base capacity of 0",
+			pool: &AWSMachinePool{
 				Spec: AWSMachinePoolSpec{
-					AdditionalTags: infrav1.Tags{
-						"key-1": "value-1",
-						"key-2": "value-2",
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						InstancesDistribution: &InstancesDistribution{
+							OnDemandBaseCapacity:                ptr.To[int64](0),
+							OnDemandPercentageAboveBaseCapacity: ptr.To[int64](0),
+						},
+						Overrides: []Overrides{{InstanceType: "m5.large"}},
 					},
-					Subnets: []infrav1.AWSResourceReference{
-						{
-							ID: ptr.To[string]("subnet-id"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should fail if the on-demand base capacity is negative",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						InstancesDistribution: &InstancesDistribution{
+							OnDemandBaseCapacity: ptr.To[int64](-1),
+						},
+						Overrides: []Overrides{{InstanceType: "m5.large"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail if the on-demand percentage above base capacity exceeds 100",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						InstancesDistribution: &InstancesDistribution{
+							OnDemandPercentageAboveBaseCapacity: ptr.To[int64](101),
+						},
+						Overrides: []Overrides{{InstanceType: "m5.large"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail if the on-demand percentage above base capacity is negative",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						InstancesDistribution: &InstancesDistribution{
+							OnDemandPercentageAboveBaseCapacity: ptr.To[int64](-1),
+						},
+						Overrides: []Overrides{{InstanceType: "m5.large"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should pass if all the overrides have a weighted capacity",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						Overrides: []Overrides{
+							{InstanceType: "m5.4xlarge", WeightedCapacity: "16"},
+							{InstanceType: "m5.2xlarge", WeightedCapacity: "8"},
 						},
 					},
 				},
@@ -267,37 +353,1166 @@ func TestAWSMachinePoolValidateUpdate(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "Should fail update if both spec.awsLaunchTemplate.SpotMarketOptions and spec.MixedInstancesPolicy are passed in AWSMachinePool spec",
-			old: &AWSMachinePool{
+			name: "Should fail if only some of the overrides have a weighted capacity",
+			pool: &AWSMachinePool{
 				Spec: AWSMachinePoolSpec{
 					MixedInstancesPolicy: &MixedInstancesPolicy{
-						Overrides: []Overrides{{InstanceType: "t3.medium"}},
+						Overrides: []Overrides{
+							{InstanceType: "m5.4xlarge", WeightedCapacity: "2"},
+							{InstanceType: "m5.2xlarge"},
+						},
 					},
 				},
 			},
-			new: &AWSMachinePool{
+			wantErr: true,
+		},
+		{
+			name: "Should fail if the weighted capacity isn't a number between 1 and 999",
+			pool: &AWSMachinePool{
 				Spec: AWSMachinePoolSpec{
 					MixedInstancesPolicy: &MixedInstancesPolicy{
-						Overrides: []Overrides{{InstanceType: "t3.medium"}},
+						Overrides: []Overrides{
+							{InstanceType: "m5.4xlarge", WeightedCapacity: "1000"},
+						},
 					},
-					AWSLaunchTemplate: AWSLaunchTemplate{
-						SpotMarketOptions: &infrav1.SpotMarketOptions{MaxPrice: ptr.To[string]("0.1")},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail if the weighted capacity is set with instance requirements",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						Overrides: []Overrides{
+							{InstanceRequirements: &InstanceRequirements{VCPUCount: VCPUCountRequest{Min: 4}, MemoryMiB: MemoryMiBRequest{Min: 8192}}, WeightedCapacity: "2"},
+						},
 					},
 				},
 			},
 			wantErr: true,
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			warn, err := tt.new.ValidateUpdate(tt.old.DeepCopy())
-			if tt.wantErr {
-				g.Expect(err).To(HaveOccurred())
-			} else {
-				g.Expect(err).To(Succeed())
-			}
-			// Nothing emits warnings yet
-			g.Expect(warn).To(BeEmpty())
+		{
+			name: "Should pass if lifecycle hooks are valid",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLifecycleHooks: []infrav1.AWSLifecycleHook{
+						{
+							Name:                  "launch-hook",
+							LifecycleTransition:   infrav1.LifecycleTransitionInstanceLaunch,
+							HeartbeatTimeout:      &metav1.Duration{Duration: 10 * time.Minute},
+							NotificationTargetARN: aws.String("arn:aws:sns:us-east-1:123456789012:my-topic"),
+							RoleARN:               aws.String("arn:aws:iam::123456789012:role/my-role"),
+						},
+						{
+							Name:                "terminate-hook",
+							LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+							SyncMode:            ptr.To(infrav1.LifecycleHookSyncModeCreateOnly),
+							Priority:            -10,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should fail if lifecycle hook names are duplicated",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLifecycleHooks: []infrav1.AWSLifecycleHook{
+						{
+							Name:                "hook",
+							LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch,
+						},
+						{
+							Name:                "hook",
+							LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail if lifecycle hook heartbeat timeout is out of range",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLifecycleHooks: []infrav1.AWSLifecycleHook{
+						{
+							Name:                "hook",
+							LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch,
+							HeartbeatTimeout:    &metav1.Duration{Duration: 10 * time.Second},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail if lifecycle hook notification target is set without a role",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLifecycleHooks: []infrav1.AWSLifecycleHook{
+						{
+							Name:                  "hook",
+							LifecycleTransition:   infrav1.LifecycleTransitionInstanceLaunch,
+							NotificationTargetARN: aws.String("arn:aws:sns:us-east-1:123456789012:my-topic"),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should pass if lifecycle hook ARNs are in the GovCloud partition",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLifecycleHooks: []infrav1.AWSLifecycleHook{
+						{
+							Name:                  "hook",
+							LifecycleTransition:   infrav1.LifecycleTransitionInstanceLaunch,
+							NotificationTargetARN: aws.String("arn:aws-us-gov:sqs:us-gov-west-1:123456789012:my-queue"),
+							RoleARN:               aws.String("arn:aws-us-gov:iam::123456789012:role/my-role"),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should fail if lifecycle hook notification target is not an ARN",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLifecycleHooks: []infrav1.AWSLifecycleHook{
+						{
+							Name:                  "hook",
+							LifecycleTransition:   infrav1.LifecycleTransitionInstanceLaunch,
+							NotificationTargetARN: aws.String("my-queue"),
+							RoleARN:               aws.String("arn:aws:iam::123456789012:role/my-role"),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail if lifecycle hook notification target is not an SQS queue or SNS topic",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLifecycleHooks: []infrav1.AWSLifecycleHook{
+						{
+							Name:                  "hook",
+							LifecycleTransition:   infrav1.LifecycleTransitionInstanceLaunch,
+							NotificationTargetARN: aws.String("arn:aws:lambda:us-east-1:123456789012:function:my-function"),
+							RoleARN:               aws.String("arn:aws:iam::123456789012:role/my-role"),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should not check the partition of lifecycle hook ARNs against the region of the notification target",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLifecycleHooks: []infrav1.AWSLifecycleHook{
+						{
+							Name:                  "hook",
+							LifecycleTransition:   infrav1.LifecycleTransitionInstanceLaunch,
+							NotificationTargetARN: aws.String("arn:aws:sqs:us-gov-west-1:123456789012:my-queue"),
+							RoleARN:               aws.String("arn:aws:iam::123456789012:role/my-role"),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should fail if lifecycle hook role partition does not match the notification target",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLifecycleHooks: []infrav1.AWSLifecycleHook{
+						{
+							Name:                  "hook",
+							LifecycleTransition:   infrav1.LifecycleTransitionInstanceLaunch,
+							NotificationTargetARN: aws.String("arn:aws-cn:sns:cn-north-1:123456789012:my-topic"),
+							RoleARN:               aws.String("arn:aws:iam::123456789012:role/my-role"),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail if lifecycle hook role is not an IAM ARN",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLifecycleHooks: []infrav1.AWSLifecycleHook{
+						{
+							Name:                  "hook",
+							LifecycleTransition:   infrav1.LifecycleTransitionInstanceLaunch,
+							NotificationTargetARN: aws.String("arn:aws:sns:us-east-1:123456789012:my-topic"),
+							RoleARN:               aws.String("arn:aws:sts::123456789012:assumed-role/my-role/session"),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should pass if node user data extra is valid",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					NodeUserDataExtra: &NodeUserDataExtra{
+						PreBootstrap:  []string{"sysctl -w vm.max_map_count=262144"},
+						PostBootstrap: []string{"echo done"},
+						Files: []File{
+							{
+								Path:        "/etc/sysctl.d/99-custom.conf",
+								Content:     "vm.max_map_count=262144",
+								Permissions: "0644",
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should fail if a node user data extra file path is relative",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					NodeUserDataExtra: &NodeUserDataExtra{
+						Files: []File{
+							{
+								Path:    "etc/sysctl.d/99-custom.conf",
+								Content: "vm.max_map_count=262144",
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail if node user data extra file permissions are not octal",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					NodeUserDataExtra: &NodeUserDataExtra{
+						Files: []File{
+							{
+								Path:        "/etc/sysctl.d/99-custom.conf",
+								Content:     "vm.max_map_count=262144",
+								Permissions: "rw-r--r--",
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should pass if the scale-down policy max unavailable is a percentage",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					ScaleDownPolicy: &ScaleDownPolicy{
+						MaxUnavailable: ptr.To(intstr.FromString("25%")),
+						PauseSeconds:   60,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should fail if the scale-down policy max unavailable is zero",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					ScaleDownPolicy: &ScaleDownPolicy{
+						MaxUnavailable: ptr.To(intstr.FromInt32(0)),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail if the scale-down policy max unavailable is not a percentage",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					ScaleDownPolicy: &ScaleDownPolicy{
+						MaxUnavailable: ptr.To(intstr.FromString("five")),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should pass if the refresh preferences are within the AWS ranges",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					RefreshPreferences: &RefreshPreferences{
+						MinHealthyPercentage:  ptr.To[int64](90),
+						SkipMatching:          ptr.To(true),
+						CheckpointPercentages: []int64{20, 50, 100},
+						CheckpointDelay:       ptr.To[int64](600),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should fail if the refresh preferences min healthy percentage exceeds 100",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					RefreshPreferences: &RefreshPreferences{
+						MinHealthyPercentage: ptr.To[int64](110),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail if the refresh preferences checkpoint percentages are not ascending",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					RefreshPreferences: &RefreshPreferences{
+						CheckpointPercentages: []int64{50, 50, 100},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail if the refresh preferences checkpoint delay is set without checkpoint percentages",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					RefreshPreferences: &RefreshPreferences{
+						CheckpointDelay: ptr.To[int64](600),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail if a lifecycle hook uses the managed launch lifecycle hook name",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLifecycleHooks: []infrav1.AWSLifecycleHook{
+						{
+							Name:                ManagedLaunchLifecycleHookName,
+							LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail if a lifecycle hook name starts with the reserved prefix",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLifecycleHooks: []infrav1.AWSLifecycleHook{
+						{
+							Name:                infrav1.ReservedLifecycleHookNamePrefix + "drain",
+							LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should pass if a lifecycle hook name only contains the reserved prefix",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLifecycleHooks: []infrav1.AWSLifecycleHook{
+						{
+							Name:                "drain-capa-nodes",
+							LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should pass if the managed launch lifecycle hook is enabled",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					ManagedLaunchLifecycleHook: &ManagedLaunchLifecycleHook{
+						Enabled: true,
+						Timeout: &metav1.Duration{Duration: 10 * time.Minute},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should fail if the managed launch lifecycle hook timeout is out of range",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					ManagedLaunchLifecycleHook: &ManagedLaunchLifecycleHook{
+						Enabled: true,
+						Timeout: &metav1.Duration{Duration: 3 * time.Hour},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should pass if an existing launch template is used with a mixed instances policy",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLaunchTemplate: AWSLaunchTemplate{
+						ExistingTemplate: &ExistingLaunchTemplate{ID: "lt-0123456789abcdef0", Version: "$Default", FollowLatest: true},
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						Overrides: []Overrides{{InstanceType: "t3.medium"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should fail if an existing launch template is combined with CAPA managed launch template fields",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLaunchTemplate: AWSLaunchTemplate{
+						ExistingTemplate: &ExistingLaunchTemplate{ID: "lt-0123456789abcdef0"},
+						AMI:              infrav1.AMIReference{ID: ptr.To[string]("ami-0123456789abcdef0")},
+						InstanceType:     "t3.medium",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail if an existing launch template is combined with node user data extra",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLaunchTemplate: AWSLaunchTemplate{
+						ExistingTemplate: &ExistingLaunchTemplate{ID: "lt-0123456789abcdef0"},
+					},
+					NodeUserDataExtra: &NodeUserDataExtra{
+						PreBootstrap: []string{"sysctl -w vm.max_map_count=262144"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail if a user data transform is set without the UserDataTransform feature gate",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLaunchTemplate: AWSLaunchTemplate{
+						UserDataTransform: &infrav1.UserDataTransform{ConfigMapRef: corev1.LocalObjectReference{Name: "user-data"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should pass if a windows pool has no node user data extra",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					OS: infrav1.OSTypeWindows,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should fail if a windows pool has node user data extra",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					OS: infrav1.OSTypeWindows,
+					NodeUserDataExtra: &NodeUserDataExtra{
+						PreBootstrap: []string{"Write-Host bootstrap"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should pass if autoscaling is enabled with valid bounds",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					Autoscaling: &MachinePoolAutoscaling{Enabled: true, MinSize: 1, MaxSize: 5},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should fail if autoscaling is enabled with a maxSize lower than the minSize",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					Autoscaling: &MachinePoolAutoscaling{Enabled: true, MinSize: 5, MaxSize: 1},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should pass if autoscaling is disabled with a maxSize lower than the minSize",
+			pool: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					Autoscaling: &MachinePoolAutoscaling{MinSize: 5, MaxSize: 1},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warn, err := tt.pool.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).To(Succeed())
+			}
+			// Nothing emits warnings yet
+			g.Expect(warn).To(BeEmpty())
+		})
+	}
+}
+
+func TestAWSMachinePoolValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		new     *AWSMachinePool
+		old     *AWSMachinePool
+		wantErr bool
+	}{
+		{
+			name: "adding tags is accepted",
+			old: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AdditionalTags: infrav1.Tags{
+						"key-1": "value-1",
+					},
+				},
+			},
+			new: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AdditionalTags: infrav1.Tags{
+						"key-1": "value-1",
+						"key-2": "value-2",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "changing the Auto Scaling group name once the group exists is rejected",
+			old: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AutoScalingGroupName: "legacy-asg",
+				},
+				Status: AWSMachinePoolStatus{
+					ASGName: "legacy-asg",
+				},
+			},
+			new: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AutoScalingGroupName: "other-asg",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "setting the Auto Scaling group name once the group exists is rejected",
+			old: &AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+				Status: AWSMachinePoolStatus{
+					ASGName: "pool",
+				},
+			},
+			new: &AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+				Spec: AWSMachinePoolSpec{
+					AutoScalingGroupName: "team-prod-workers",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "setting the Auto Scaling group name to the name of the existing group is accepted",
+			old: &AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+				Status: AWSMachinePoolStatus{
+					ASGName: "pool",
+				},
+			},
+			new: &AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+				Spec: AWSMachinePoolSpec{
+					AutoScalingGroupName: "pool",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "changing the Auto Scaling group name before the group is created is accepted",
+			old: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AutoScalingGroupName: "team-prod-workers",
+				},
+			},
+			new: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AutoScalingGroupName: "team-prod-workers-2",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "adding invalid tags is rejected",
+			old: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AdditionalTags: infrav1.Tags{
+						"key-1": "value-1",
+					},
+				},
+			},
+			new: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AdditionalTags: infrav1.Tags{
+						"key-1":                    "value-1",
+						"":                         "value-2",
+						strings.Repeat("CAPI", 33): "value-3",
+						"key-4":                    strings.Repeat("CAPI", 65),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should fail update if both subnetID and filters passed in AWSMachinePool spec",
+			old: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AdditionalTags: infrav1.Tags{
+						"key-1": "value-1",
+					},
+				},
+			},
+			new: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AdditionalTags: infrav1.Tags{
+						"key-1": "value-1",
+						"key-2": "value-2",
+					},
+					Subnets: []infrav1.AWSResourceReference{
+						{
+							ID:      ptr.To[string]("subnet-id"),
+							Filters: []infrav1.Filter{{Name: "filter_name", Values: []string{"filter_value"}}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should pass update if either subnetID or filters passed in AWSMachinePool spec",
+			old: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AdditionalTags: infrav1.Tags{
+						"key-1": "value-1",
+					},
+				},
+			},
+			new: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AdditionalTags: infrav1.Tags{
+						"key-1": "value-1",
+						"key-2": "value-2",
+					},
+					Subnets: []infrav1.AWSResourceReference{
+						{
+							ID: ptr.To[string]("subnet-id"),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should fail update if both spec.awsLaunchTemplate.SpotMarketOptions and spec.MixedInstancesPolicy are passed in AWSMachinePool spec",
+			old: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						Overrides: []Overrides{{InstanceType: "t3.medium"}},
+					},
+				},
+			},
+			new: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						Overrides: []Overrides{{InstanceType: "t3.medium"}},
+					},
+					AWSLaunchTemplate: AWSLaunchTemplate{
+						SpotMarketOptions: &infrav1.SpotMarketOptions{MaxPrice: ptr.To[string]("0.1")},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should pass update if the version of an existing launch template changes",
+			old: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLaunchTemplate: AWSLaunchTemplate{
+						ExistingTemplate: &ExistingLaunchTemplate{ID: "lt-0123456789abcdef0", Version: "3"},
+					},
+				},
+			},
+			new: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLaunchTemplate: AWSLaunchTemplate{
+						ExistingTemplate: &ExistingLaunchTemplate{ID: "lt-0123456789abcdef0", Version: "4"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should pass update if an existing launch template replaces the CAPA managed one",
+			old: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLaunchTemplate: AWSLaunchTemplate{
+						InstanceType: "t3.medium",
+					},
+				},
+			},
+			new: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLaunchTemplate: AWSLaunchTemplate{
+						ExistingTemplate: &ExistingLaunchTemplate{ID: "lt-0123456789abcdef0"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should fail update if the existing launch template is removed",
+			old: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLaunchTemplate: AWSLaunchTemplate{
+						ExistingTemplate: &ExistingLaunchTemplate{ID: "lt-0123456789abcdef0"},
+					},
+				},
+			},
+			new: &AWSMachinePool{
+				Spec: AWSMachinePoolSpec{
+					AWSLaunchTemplate: AWSLaunchTemplate{
+						InstanceType: "t3.medium",
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warn, err := tt.new.ValidateUpdate(tt.old.DeepCopy())
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).To(Succeed())
+			}
+			// Nothing emits warnings yet
+			g.Expect(warn).To(BeEmpty())
+		})
+	}
+}
+
+func TestAWSMachinePoolValidateLifecycleHookARNs(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &AWSMachinePool{
+		Spec: AWSMachinePoolSpec{
+			AWSLifecycleHooks: []infrav1.AWSLifecycleHook{
+				{
+					Name:                  "valid-hook",
+					LifecycleTransition:   infrav1.LifecycleTransitionInstanceLaunch,
+					NotificationTargetARN: aws.String("arn:aws-us-gov:sqs:us-gov-west-1:123456789012:my-queue"),
+					RoleARN:               aws.String("arn:aws-us-gov:iam::123456789012:role/my-role"),
+				},
+				{
+					Name:                  "invalid-hook",
+					LifecycleTransition:   infrav1.LifecycleTransitionInstanceLaunch,
+					NotificationTargetARN: aws.String("arn:aws-us-gov:lambda:us-gov-west-1:123456789012:function:my-function"),
+					RoleARN:               aws.String("arn:aws-us-gov:s3:::my-bucket"),
+				},
+			},
+		},
+	}
+
+	errs := pool.validateLifecycleHooks()
+	g.Expect(errs).To(HaveLen(2))
+	g.Expect(errs[0].Field).To(Equal("spec.awsLifecycleHooks[1].notificationTargetARN"))
+	g.Expect(errs[1].Field).To(Equal("spec.awsLifecycleHooks[1].roleARN"))
+}
+
+func TestAWSMachinePoolValidateTerminationPolicies(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &AWSMachinePool{
+		Spec: AWSMachinePoolSpec{
+			TerminationPolicies: []string{
+				"OldestLaunchTemplate",
+				"ClosestToNextInstanceHour",
+				"arn:aws:lambda:us-west-2:123456789012:function:my-termination-policy:1",
+				"Oldest",
+				"arn:aws:sqs:us-west-2:123456789012:my-queue",
+				"OldestLaunchTemplate",
+			},
+		},
+	}
+
+	errs := pool.validateTerminationPolicies()
+	g.Expect(errs).To(HaveLen(3))
+	g.Expect(errs[0].Field).To(Equal("spec.terminationPolicies[3]"))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+	g.Expect(errs[1].Field).To(Equal("spec.terminationPolicies[4]"))
+	g.Expect(errs[1].Type).To(Equal(field.ErrorTypeInvalid))
+	g.Expect(errs[2].Field).To(Equal("spec.terminationPolicies[5]"))
+	g.Expect(errs[2].Type).To(Equal(field.ErrorTypeDuplicate))
+}
+
+func TestAWSMachinePoolValidateTargetGroupARNs(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &AWSMachinePool{
+		Spec: AWSMachinePoolSpec{
+			TargetGroupARNs: []string{
+				"arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/workloads/73e2d6bc24d8a067",
+				"arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/ingress/2453ed029918f21f",
+				"arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/net/workloads/50dc6c495c0c9188",
+				"arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/workloads/73e2d6bc24d8a067",
+				"arn:aws-cn:elasticloadbalancing:cn-north-1:123456789012:targetgroup/workloads/73e2d6bc24d8a067",
+				"arn:aws-unknown:elasticloadbalancing:us-west-2:123456789012:targetgroup/workloads/73e2d6bc24d8a067",
+				"workloads",
+			},
+		},
+	}
+
+	errs := pool.validateTargetGroupARNs()
+	g.Expect(errs).To(HaveLen(5))
+	g.Expect(errs[0].Field).To(Equal("spec.targetGroupARNs[2]"))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+	g.Expect(errs[1].Field).To(Equal("spec.targetGroupARNs[3]"))
+	g.Expect(errs[1].Type).To(Equal(field.ErrorTypeDuplicate))
+	g.Expect(errs[2].Field).To(Equal("spec.targetGroupARNs[4]"))
+	g.Expect(errs[2].Detail).To(ContainSubstring(`"aws" partition`))
+	g.Expect(errs[3].Field).To(Equal("spec.targetGroupARNs[5]"))
+	g.Expect(errs[3].Detail).To(ContainSubstring("unknown partition"))
+	g.Expect(errs[4].Field).To(Equal("spec.targetGroupARNs[6]"))
+	g.Expect(errs[4].Type).To(Equal(field.ErrorTypeInvalid))
+}
+
+func TestAWSMachinePoolValidateClassicLoadBalancers(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &AWSMachinePool{
+		Spec: AWSMachinePoolSpec{
+			ClassicLoadBalancers: []string{
+				"ingress",
+				"legacy-ingress-1",
+				"ingress",
+				"-ingress",
+				"ingress_1",
+				"a-classic-load-balancer-name-longer-than-32-characters",
+			},
+		},
+	}
+
+	errs := pool.validateClassicLoadBalancers()
+	g.Expect(errs).To(HaveLen(4))
+	g.Expect(errs[0].Field).To(Equal("spec.classicLoadBalancers[2]"))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeDuplicate))
+	g.Expect(errs[1].Field).To(Equal("spec.classicLoadBalancers[3]"))
+	g.Expect(errs[1].Type).To(Equal(field.ErrorTypeInvalid))
+	g.Expect(errs[2].Field).To(Equal("spec.classicLoadBalancers[4]"))
+	g.Expect(errs[3].Field).To(Equal("spec.classicLoadBalancers[5]"))
+}
+
+func TestAWSMachinePoolValidateScheduledActions(t *testing.T) {
+	g := NewWithT(t)
+
+	start := metav1.NewTime(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC))
+	pool := &AWSMachinePool{
+		Spec: AWSMachinePoolSpec{
+			ScheduledActions: []ScheduledAction{
+				{Name: "scale-up", Recurrence: "0 8 * * 1-5", TimeZone: "Europe/Paris", MinSize: ptr.To[int32](5), MaxSize: ptr.To[int32](50), DesiredCapacity: ptr.To[int32](50)},
+				{Name: "scale-down", StartTime: &start, EndTime: ptr.To(metav1.NewTime(start.Add(time.Hour))), DesiredCapacity: ptr.To[int32](5)},
+				{Name: "scale-up", Recurrence: "0 8 * * *", DesiredCapacity: ptr.To[int32](1)},
+				{Name: "no-size", Recurrence: "0 8 * * *"},
+				{Name: "inverted-sizes", Recurrence: "0 8 * * *", MinSize: ptr.To[int32](5), MaxSize: ptr.To[int32](1), DesiredCapacity: ptr.To[int32](3)},
+				{Name: "no-schedule", DesiredCapacity: ptr.To[int32](1)},
+				{Name: "invalid-recurrence", Recurrence: "@daily", DesiredCapacity: ptr.To[int32](1)},
+				{Name: "end-before-start", StartTime: &start, EndTime: &start, DesiredCapacity: ptr.To[int32](1)},
+			},
+		},
+	}
+
+	errs := pool.validateScheduledActions()
+	g.Expect(errs).To(HaveLen(7))
+	g.Expect(errs[0].Field).To(Equal("spec.scheduledActions[2].name"))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeDuplicate))
+	g.Expect(errs[1].Field).To(Equal("spec.scheduledActions[3]"))
+	g.Expect(errs[1].Type).To(Equal(field.ErrorTypeRequired))
+	g.Expect(errs[2].Field).To(Equal("spec.scheduledActions[4].minSize"))
+	g.Expect(errs[3].Field).To(Equal("spec.scheduledActions[4].desiredCapacity"))
+	g.Expect(errs[4].Field).To(Equal("spec.scheduledActions[5]"))
+	g.Expect(errs[4].Type).To(Equal(field.ErrorTypeRequired))
+	g.Expect(errs[5].Field).To(Equal("spec.scheduledActions[6].recurrence"))
+	g.Expect(errs[6].Field).To(Equal("spec.scheduledActions[7].endTime"))
+}
+
+func TestAWSMachinePoolValidateHealthCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       AWSMachinePoolSpec
+		wantFields []string
+	}{
+		{
+			name: "EC2 health check with a grace period",
+			spec: AWSMachinePoolSpec{
+				HealthCheckType:        HealthCheckTypeEC2,
+				HealthCheckGracePeriod: &metav1.Duration{Duration: 5 * time.Minute},
+			},
+		},
+		{
+			name: "ELB health check with a load balancer attachment",
+			spec: AWSMachinePoolSpec{
+				HealthCheckType:         HealthCheckTypeELB,
+				LoadBalancerAttachments: []LoadBalancerAttachment{{ListenerPort: 8132}},
+			},
+		},
+		{
+			name: "ELB health check with a target group",
+			spec: AWSMachinePoolSpec{
+				HealthCheckType: HealthCheckTypeELB,
+				TargetGroupARNs: []string{"arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/workloads/73e2d6bc24d8a067"},
+			},
+		},
+		{
+			name: "ELB health check with a classic load balancer",
+			spec: AWSMachinePoolSpec{
+				HealthCheckType:      HealthCheckTypeELB,
+				ClassicLoadBalancers: []string{"ingress"},
+			},
+		},
+		{
+			name: "ELB health check without load balancer attachments",
+			spec: AWSMachinePoolSpec{
+				HealthCheckType: HealthCheckTypeELB,
+			},
+			wantFields: []string{"spec.healthCheckType"},
+		},
+		{
+			name: "negative grace period",
+			spec: AWSMachinePoolSpec{
+				HealthCheckGracePeriod: &metav1.Duration{Duration: -time.Second},
+			},
+			wantFields: []string{"spec.healthCheckGracePeriod"},
+		},
+		{
+			name: "grace period with a fraction of a second",
+			spec: AWSMachinePoolSpec{
+				HealthCheckGracePeriod: &metav1.Duration{Duration: 1500 * time.Millisecond},
+			},
+			wantFields: []string{"spec.healthCheckGracePeriod"},
+		},
+		{
+			name: "negative default instance warmup",
+			spec: AWSMachinePoolSpec{
+				DefaultInstanceWarmup: &metav1.Duration{Duration: -time.Second},
+			},
+			wantFields: []string{"spec.defaultInstanceWarmup"},
+		},
+		{
+			name: "no default instance warmup",
+			spec: AWSMachinePoolSpec{
+				DefaultInstanceWarmup: &metav1.Duration{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			pool := &AWSMachinePool{Spec: tt.spec}
+			var fields []string
+			for _, err := range pool.validateHealthCheck() {
+				fields = append(fields, err.Field)
+			}
+			g.Expect(fields).To(Equal(tt.wantFields))
+		})
+	}
+}
+
+func TestAWSMachinePoolValidateMaxInstanceLifetime(t *testing.T) {
+	tests := []struct {
+		name     string
+		lifetime *metav1.Duration
+		wantErr  bool
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:     "disabled",
+			lifetime: &metav1.Duration{},
+		},
+		{
+			name:     "14 days",
+			lifetime: &metav1.Duration{Duration: 14 * 24 * time.Hour},
+		},
+		{
+			name:     "1 day",
+			lifetime: &metav1.Duration{Duration: 24 * time.Hour},
+		},
+		{
+			name:     "less than a day",
+			lifetime: &metav1.Duration{Duration: 24*time.Hour - time.Second},
+			wantErr:  true,
+		},
+		{
+			name:     "more than 365 days",
+			lifetime: &metav1.Duration{Duration: 366 * 24 * time.Hour},
+			wantErr:  true,
+		},
+		{
+			name:     "negative",
+			lifetime: &metav1.Duration{Duration: -24 * time.Hour},
+			wantErr:  true,
+		},
+		{
+			name:     "fraction of a second",
+			lifetime: &metav1.Duration{Duration: 24*time.Hour + time.Millisecond},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			pool := &AWSMachinePool{Spec: AWSMachinePoolSpec{MaxInstanceLifetime: tt.lifetime}}
+			errs := pool.validateMaxInstanceLifetime()
+			if tt.wantErr {
+				g.Expect(errs).To(HaveLen(1))
+				g.Expect(errs[0].Field).To(Equal("spec.maxInstanceLifetime"))
+				return
+			}
+			g.Expect(errs).To(BeEmpty())
+		})
+	}
+}
+
+func TestAWSMachinePoolValidateDeletionTimeout(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &AWSMachinePool{}
+	g.Expect(pool.validateDeletionTimeout()).To(BeEmpty())
+
+	pool.Spec.DeletionTimeout = &metav1.Duration{}
+	g.Expect(pool.validateDeletionTimeout()).To(BeEmpty())
+
+	pool.Spec.DeletionTimeout = &metav1.Duration{Duration: -time.Minute}
+	errs := pool.validateDeletionTimeout()
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Field).To(Equal("spec.deletionTimeout"))
+}
+
+func TestAWSMachinePoolValidateInstancesReadyTimeout(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &AWSMachinePool{}
+	g.Expect(pool.validateInstancesReadyTimeout()).To(BeEmpty())
+
+	pool.Spec.InstancesReadyTimeout = &metav1.Duration{Duration: time.Minute}
+	g.Expect(pool.validateInstancesReadyTimeout()).To(BeEmpty())
+
+	pool.Spec.InstancesReadyTimeout = &metav1.Duration{Duration: -time.Minute}
+	errs := pool.validateInstancesReadyTimeout()
+	g.Expect(errs).To(HaveLen(1))
+	g.Expect(errs[0].Field).To(Equal("spec.instancesReadyTimeout"))
+}
+
+func TestAWSMachinePoolValidateMetricsCollection(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &AWSMachinePool{
+		Spec: AWSMachinePoolSpec{
+			MetricsCollection: &MetricsCollection{
+				Metrics: []string{
+					"GroupDesiredCapacity",
+					"GroupInServiceInstances",
+					"GroupDesiredCapacity",
+					"GroupCPUUtilization",
+				},
+			},
+		},
+	}
+
+	errs := pool.validateMetricsCollection()
+	g.Expect(errs).To(HaveLen(2))
+	g.Expect(errs[0].Field).To(Equal("spec.metricsCollection.metrics[2]"))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeDuplicate))
+	g.Expect(errs[1].Field).To(Equal("spec.metricsCollection.metrics[3]"))
+	g.Expect(errs[1].Type).To(Equal(field.ErrorTypeNotSupported))
+
+	// All the group metrics are collected when none are listed.
+	pool.Spec.MetricsCollection.Metrics = nil
+	g.Expect(pool.validateMetricsCollection()).To(BeEmpty())
+	g.Expect(pool.Spec.MetricsCollection.GetMetrics()).To(Equal(ASGMetrics))
+	g.Expect(pool.Spec.MetricsCollection.GetGranularity()).To(Equal(MetricsCollectionGranularity1Minute))
+}
+
+func TestAWSMachinePoolValidateAMIVersionOverride(t *testing.T) {
+	tests := []struct {
+		name     string
+		override *string
+		amiID    *string
+		wantErr  field.ErrorType
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:     "minor version",
+			override: ptr.To("v1.28"),
+		},
+		{
+			name:     "patch version without prefix",
+			override: ptr.To("1.28.5"),
+		},
+		{
+			name:     "not a version",
+			override: ptr.To("latest"),
+			wantErr:  field.ErrorTypeInvalid,
+		},
+		{
+			name:     "together with an AMI ID",
+			override: ptr.To("v1.28"),
+			amiID:    ptr.To("ami-0123456789abcdef0"),
+			wantErr:  field.ErrorTypeForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			pool := &AWSMachinePool{Spec: AWSMachinePoolSpec{AMIVersionOverride: tt.override}}
+			pool.Spec.AWSLaunchTemplate.AMI.ID = tt.amiID
+			errs := pool.validateAMIVersionOverride()
+			if tt.wantErr != "" {
+				g.Expect(errs).To(HaveLen(1))
+				g.Expect(errs[0].Field).To(Equal("spec.amiVersionOverride"))
+				g.Expect(errs[0].Type).To(Equal(tt.wantErr))
+				return
+			}
+			g.Expect(errs).To(BeEmpty())
 		})
 	}
 }
@@ -103,6 +103,13 @@ type AWSManagedMachinePoolSpec struct {
 	// +optional
 	AMIVersion *string `json:"amiVersion,omitempty"`
 
+	// AMIVersionOverride is the Kubernetes version of the nodegroup and of its AMI, in preference to the
+	// version of the MachinePool, e.g. to keep launching the AMIs of the previous minor version for a
+	// canary period while the MachinePool reports the new version. It must be within the supported version
+	// skew of the control plane.
+	// +optional
+	AMIVersionOverride *string `json:"amiVersionOverride,omitempty"`
+
 	// AMIType defines the AMI type
 	// +kubebuilder:validation:Enum:=AL2_x86_64;AL2_x86_64_GPU;AL2_ARM_64;CUSTOM
 	// +kubebuilder:default:=AL2_x86_64
@@ -129,6 +136,11 @@ type AWSManagedMachinePoolSpec struct {
 	// +optional
 	Scaling *ManagedMachinePoolScaling `json:"scaling,omitempty"`
 
+	// Autoscaling, when enabled, lets the cluster autoscaler scale the node group between its minimum
+	// and maximum size, which replace Scaling.
+	// +optional
+	Autoscaling *MachinePoolAutoscaling `json:"autoscaling,omitempty"`
+
 	// RemoteAccess specifies how machines can be accessed remotely
 	// +optional
 	RemoteAccess *ManagedRemoteAccess `json:"remoteAccess,omitempty"`
@@ -187,6 +199,11 @@ type AWSManagedMachinePoolStatus struct {
 	// +optional
 	Replicas int32 `json:"replicas"`
 
+	// DesiredSize is the most recently observed desired size of the nodegroup, which an external autoscaler
+	// managing the replicas of the MachinePool may set.
+	// +optional
+	DesiredSize *int32 `json:"desiredSize,omitempty"`
+
 	// The ID of the launch template
 	// +optional
 	LaunchTemplateID *string `json:"launchTemplateID,omitempty"`
@@ -195,6 +212,23 @@ type AWSManagedMachinePoolStatus struct {
 	// +optional
 	LaunchTemplateVersion *string `json:"launchTemplateVersion,omitempty"`
 
+	// LastLaunchTemplateChange describes the changes which led to the latest version of the launch template.
+	// +optional
+	LastLaunchTemplateChange *LaunchTemplateChange `json:"lastLaunchTemplateChange,omitempty"`
+
+	// Version is the Kubernetes version of the MachinePool, which is reported to Cluster API.
+	// +optional
+	Version *string `json:"version,omitempty"`
+
+	// AMIKubernetesVersion is the Kubernetes version of the nodegroup, which its AMI is released for. It
+	// differs from Version while spec.amiVersionOverride is set, or while the nodegroup is upgraded.
+	// +optional
+	AMIKubernetesVersion *string `json:"amiKubernetesVersion,omitempty"`
+
+	// NodegroupStatus is the status of the EKS nodegroup, e.g. ACTIVE or UPDATING.
+	// +optional
+	NodegroupStatus string `json:"nodegroupStatus,omitempty"`
+
 	// FailureReason will be set in the event that there is a terminal problem
 	// reconciling the MachinePool and will contain a succinct value suitable
 	// for machine interpretation.
@@ -244,6 +278,9 @@ type AWSManagedMachinePoolStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="MachinePool ready status"
 // +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicas",description="Number of replicas"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.nodegroupStatus",description="Status of the EKS nodegroup"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="Kubernetes version of the nodegroup"
+// +kubebuilder:printcolumn:name="AMI Type",type="string",JSONPath=".spec.amiType",description="AMI type of the nodegroup"
 
 // AWSManagedMachinePool is the Schema for the awsmanagedmachinepools API.
 type AWSManagedMachinePool struct {
@@ -264,6 +301,19 @@ func (r *AWSManagedMachinePool) SetConditions(conditions clusterv1.Conditions) {
 	r.Status.Conditions = conditions
 }
 
+// ScalingBounds returns the minimum and maximum size of the node group: the bounds of the autoscaling
+// when it is enabled, Scaling otherwise.
+func (r *AWSManagedMachinePool) ScalingBounds() *ManagedMachinePoolScaling {
+	if r.Spec.Autoscaling.IsEnabled() {
+		minSize, maxSize := r.Spec.Autoscaling.MinSize, r.Spec.Autoscaling.MaxSize
+		return &ManagedMachinePoolScaling{
+			MinSize: &minSize,
+			MaxSize: &maxSize,
+		}
+	}
+	return r.Spec.Scaling
+}
+
 // +kubebuilder:object:root=true
 
 // AWSManagedMachinePoolList contains a list of AWSManagedMachinePools.
@@ -135,6 +135,12 @@ func (r *AWSManagedMachinePool) validateLaunchTemplate() field.ErrorList {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "AWSLaunchTemplate", "IamInstanceProfile"), r.Spec.AWSLaunchTemplate.IamInstanceProfile, "IAM instance profile in launch template is prohibited in EKS managed node group"))
 	}
 
+	if r.Spec.AWSLaunchTemplate.ExistingTemplate != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "AWSLaunchTemplate", "ExistingTemplate"), "existing launch templates are not supported in EKS managed node group"))
+	}
+
+	allErrs = append(allErrs, r.Spec.AWSLaunchTemplate.UserDataTransform.Validate(field.NewPath("spec", "awsLaunchTemplate", "userDataTransform"))...)
+
 	return allErrs
 }
 
@@ -153,6 +159,8 @@ func (r *AWSManagedMachinePool) ValidateCreate() (admission.Warnings, error) {
 	if errs := r.validateRemoteAccess(); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
+	allErrs = append(allErrs, validateAutoscaling(r.Spec.Autoscaling)...)
+	allErrs = append(allErrs, validateAMIVersionOverride(r.Spec.AMIVersionOverride)...)
 	if errs := r.validateNodegroupUpdateConfig(); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
@@ -190,6 +198,8 @@ func (r *AWSManagedMachinePool) ValidateUpdate(old runtime.Object) (admission.Wa
 	if errs := r.validateScaling(); errs != nil || len(errs) == 0 {
 		allErrs = append(allErrs, errs...)
 	}
+	allErrs = append(allErrs, validateAutoscaling(r.Spec.Autoscaling)...)
+	allErrs = append(allErrs, validateAMIVersionOverride(r.Spec.AMIVersionOverride)...)
 	if errs := r.validateNodegroupUpdateConfig(); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
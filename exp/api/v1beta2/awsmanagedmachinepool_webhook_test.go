@@ -150,6 +150,36 @@ func TestAWSManagedMachinePoolValidateCreate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "autoscaling with a maxSize lower than the minSize",
+			pool: &AWSManagedMachinePool{
+				Spec: AWSManagedMachinePoolSpec{
+					EKSNodegroupName: "eks-node-group-3",
+					Autoscaling:      &MachinePoolAutoscaling{Enabled: true, MinSize: 3, MaxSize: 2},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AMI version override",
+			pool: &AWSManagedMachinePool{
+				Spec: AWSManagedMachinePoolSpec{
+					EKSNodegroupName:   "eks-node-group-3",
+					AMIVersionOverride: ptr.To("v1.28"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid AMI version override",
+			pool: &AWSManagedMachinePool{
+				Spec: AWSManagedMachinePoolSpec{
+					EKSNodegroupName:   "eks-node-group-3",
+					AMIVersionOverride: ptr.To("1"),
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
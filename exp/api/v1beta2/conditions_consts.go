@@ -25,8 +25,30 @@ const (
 	ASGNotFoundReason = "ASGNotFound"
 	// ASGProvisionFailedReason used for failures during autoscaling group provisioning.
 	ASGProvisionFailedReason = "ASGProvisionFailed"
+	// ASGAdoptionFailedReason used when the existing autoscaling group named by the AWSMachinePool can't be adopted.
+	ASGAdoptionFailedReason = "ASGAdoptionFailed"
 	// ASGDeletionInProgress ASG is in a deletion in progress state.
 	ASGDeletionInProgress = "ASGDeletionInProgress"
+	// ASGWaitingForInstancesReason used while the autoscaling group has fewer InService instances than the
+	// machine pool requires to be ready.
+	ASGWaitingForInstancesReason = "WaitingForInstances"
+	// ASGInsufficientInstancesReason used when the autoscaling group still has fewer InService instances than the
+	// machine pool requires to be ready once spec.instancesReadyTimeout expired.
+	ASGInsufficientInstancesReason = "InsufficientInstances"
+
+	// ScalingActivitiesSucceededCondition reports whether the most recent scaling activity of the autoscaling group
+	// succeeded. It is False while the autoscaling group fails to launch instances, e.g. because of insufficient
+	// capacity or invalid launch template settings, until a subsequent scaling activity succeeds.
+	ScalingActivitiesSucceededCondition clusterv1.ConditionType = "ScalingActivitiesSucceeded"
+
+	// SubnetsReadyCondition reports whether the subnets of the autoscaling group, which spec.subnets may select by
+	// filters, were resolved.
+	SubnetsReadyCondition clusterv1.ConditionType = "SubnetsReady"
+	// SubnetsNotFoundReason used when no subnet matches the filters of an entry of spec.subnets, or when the
+	// autoscaling group would have no subnets.
+	SubnetsNotFoundReason = "SubnetsNotFound"
+	// SubnetsResolutionFailedReason used when the subnets matching the filters of spec.subnets can't be described.
+	SubnetsResolutionFailedReason = "SubnetsResolutionFailed"
 
 	// LaunchTemplateReadyCondition represents the status of an AWSMachinePool's associated Launch Template.
 	LaunchTemplateReadyCondition clusterv1.ConditionType = "LaunchTemplateReady"
@@ -54,6 +76,82 @@ const (
 	InstanceRefreshNotReadyReason = "InstanceRefreshNotReady"
 	// InstanceRefreshFailedReason used to report when there instance refresh is not initiated.
 	InstanceRefreshFailedReason = "InstanceRefreshFailed"
+
+	// LifecycleHookExistsCondition reports on the reconciliation of the lifecycle hooks of the autoscaling group.
+	LifecycleHookExistsCondition clusterv1.ConditionType = "LifecycleHookExists"
+	// InsufficientPermissionsReason used when the controller is not allowed to manage the lifecycle hooks.
+	InsufficientPermissionsReason = "InsufficientPermissions"
+	// LifecycleHookRejectedReason used when AWS rejects a lifecycle hook for a reason that persists until its spec
+	// changes, e.g. the limit of lifecycle hooks per autoscaling group is reached.
+	LifecycleHookRejectedReason = "LifecycleHookRejected"
+	// LifecycleHookDeletionPendingReason used when lifecycle hooks removed from the spec can't be deleted yet
+	// because instances wait on them.
+	LifecycleHookDeletionPendingReason = "LifecycleHookDeletionPending"
+
+	// LoadBalancerAttachmentsReadyCondition reports on the attachment of the autoscaling group to the target groups
+	// of the control plane load balancer listeners referenced in the AWSMachinePool spec.
+	LoadBalancerAttachmentsReadyCondition clusterv1.ConditionType = "LoadBalancerAttachmentsReady"
+	// LoadBalancerListenerNotFoundReason used when a referenced listener doesn't exist on the control plane load balancer.
+	LoadBalancerListenerNotFoundReason = "LoadBalancerListenerNotFound"
+	// LoadBalancerAttachmentsFailedReason used when the autoscaling group could not be attached to or detached from the target groups.
+	LoadBalancerAttachmentsFailedReason = "LoadBalancerAttachmentsFailed"
+
+	// ClassicLoadBalancersReadyCondition reports on the attachment of the autoscaling group to the Classic Load
+	// Balancers referenced in the AWSMachinePool spec.
+	ClassicLoadBalancersReadyCondition clusterv1.ConditionType = "ClassicLoadBalancersReady"
+	// ClassicLoadBalancerAttachmentFailedReason used when the autoscaling group could not be attached to or detached
+	// from the Classic Load Balancers.
+	ClassicLoadBalancerAttachmentFailedReason = "ClassicLoadBalancerAttachmentFailed"
+
+	// ScaleDownCompletedCondition reports on the progress of a scale-down limited by the scale-down policy
+	// of the autoscaling group.
+	ScaleDownCompletedCondition clusterv1.ConditionType = "ScaleDownCompleted"
+	// ScaleDownInProgressReason used when the autoscaling group is being scaled down in steps.
+	ScaleDownInProgressReason = "ScaleDownInProgress"
+	// ScaleDownWaitingForInstancesReason used when a step waits for the instances removed by the previous
+	// step to terminate.
+	ScaleDownWaitingForInstancesReason = "WaitingForInstances"
+
+	// InstanceRefreshReadyCondition reports on the progress of the last instance refresh started on the
+	// autoscaling group. It is False while the instance refresh runs, and when it did not succeed.
+	InstanceRefreshReadyCondition clusterv1.ConditionType = "InstanceRefreshReady"
+	// InstanceRefreshInProgressReason used while an instance refresh is running.
+	InstanceRefreshInProgressReason = "InstanceRefreshInProgress"
+	// InstanceRefreshCancellingReason used while an instance refresh for an outdated launch template is cancelled.
+	InstanceRefreshCancellingReason = "InstanceRefreshCancelling"
+	// InstanceRefreshUnsuccessfulReason used when an instance refresh failed, was cancelled or was rolled back.
+	InstanceRefreshUnsuccessfulReason = "InstanceRefreshUnsuccessful"
+
+	// InstancesInSubnetsCondition reports whether the instances of the autoscaling group run in the availability
+	// zones of its subnets. Instances are left in their availability zone when the subnets of the autoscaling group
+	// are moved to other availability zones, until they are replaced.
+	InstancesInSubnetsCondition clusterv1.ConditionType = "InstancesInSubnets"
+	// InstancesOutsideSubnetsReason used when instances run in availability zones none of the subnets of the
+	// autoscaling group is in. An instance refresh replaces them in the new subnets.
+	InstancesOutsideSubnetsReason = "InstancesOutsideSubnets"
+
+	// AvailabilityZonesEvacuatedCondition reports whether the availability zones of the aws.cluster.x-k8s.io/az-evacuate
+	// annotation are evacuated. It is removed once no availability zone is evacuated.
+	AvailabilityZonesEvacuatedCondition clusterv1.ConditionType = "AvailabilityZonesEvacuated"
+	// AZEvacuationRefusedReason used when evacuating the availability zones would leave the autoscaling group without
+	// subnets.
+	AZEvacuationRefusedReason = "AZEvacuationRefused"
+	// AZEvacuationFailedReason used when the availability zones of the subnets of the autoscaling group aren't known.
+	AZEvacuationFailedReason = "AZEvacuationFailed"
+
+	// WorkloadClusterReachableCondition reports on the connection to the API server of the workload cluster, which
+	// the steps that depend on the Nodes of the autoscaling group require.
+	WorkloadClusterReachableCondition clusterv1.ConditionType = "WorkloadClusterReachable"
+	// WorkloadClusterUnreachableReason used when the Nodes can't be listed from the workload cluster. The steps that
+	// depend on them are skipped, while the autoscaling group is still reconciled.
+	WorkloadClusterUnreachableReason = "WorkloadClusterUnreachable"
+
+	// SpotInterruptionHandlingReadyCondition reports whether the queue receiving the spot interruption notices of the
+	// instances of the cluster is ready. It is removed when the handling of the spot interruptions is disabled.
+	SpotInterruptionHandlingReadyCondition clusterv1.ConditionType = "SpotInterruptionHandlingReady"
+	// SpotInterruptionHandlingFailedReason used when the queue or the rule sending the spot interruption notices to it
+	// can't be created.
+	SpotInterruptionHandlingFailedReason = "SpotInterruptionHandlingFailed"
 )
 
 const (
@@ -64,6 +162,9 @@ const (
 	// WaitingForEKSControlPlaneReason used when the machine pool is waiting for
 	// EKS control plane infrastructure to be ready before proceeding.
 	WaitingForEKSControlPlaneReason = "WaitingForEKSControlPlane"
+	// EKSAutoModeEnabledReason used when a node group or a Fargate profile can't be created because
+	// the EKS control plane uses EKS Auto Mode.
+	EKSAutoModeEnabledReason = "EKSAutoModeEnabled"
 )
 
 const (
@@ -24,8 +24,20 @@ const (
 	MachinePoolFinalizer = "awsmachinepool.infrastructure.cluster.x-k8s.io"
 
 	// ManagedMachinePoolFinalizer allows the controller to clean up resources on delete.
-	ManagedMachinePoolFinalizer = "awsmanagedmachinepools.infrastructure.cluster.x-k8s.io"
+	ManagedMachinePoolFinalizer = "awsmanagedmachinepool.infrastructure.cluster.x-k8s.io"
+
+	// ManagedMachinePoolLegacyFinalizer is the finalizer set by the previous releases. The controller
+	// replaces it with ManagedMachinePoolFinalizer.
+	//
+	// Deprecated: Use ManagedMachinePoolFinalizer.
+	ManagedMachinePoolLegacyFinalizer = "awsmanagedmachinepools.infrastructure.cluster.x-k8s.io"
 
 	// RosaMachinePoolFinalizer allows the controller to clean up resources on delete.
-	RosaMachinePoolFinalizer = "rosamachinepools.infrastructure.cluster.x-k8s.io"
+	RosaMachinePoolFinalizer = "rosamachinepool.infrastructure.cluster.x-k8s.io"
+
+	// RosaMachinePoolLegacyFinalizer is the finalizer set by the previous releases. The controller
+	// replaces it with RosaMachinePoolFinalizer.
+	//
+	// Deprecated: Use RosaMachinePoolFinalizer.
+	RosaMachinePoolLegacyFinalizer = "rosamachinepools.infrastructure.cluster.x-k8s.io"
 )
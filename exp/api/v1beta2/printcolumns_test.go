@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestPrinterColumns(t *testing.T) {
+	tests := []struct {
+		crd       string
+		shortName string
+		columns   map[string]string
+	}{
+		{
+			crd:       "infrastructure.cluster.x-k8s.io_awsmachinepools.yaml",
+			shortName: "awsmp",
+			columns: map[string]string{
+				"Ready":                  ".status.ready",
+				"Replicas":               ".status.replicas",
+				"Outdated":               ".status.outdatedReplicas",
+				"LaunchTemplate Version": ".status.launchTemplateVersion",
+				"ASG Name":               ".status.asgName",
+			},
+		},
+		{
+			crd:       "infrastructure.cluster.x-k8s.io_awsmanagedmachinepools.yaml",
+			shortName: "awsmmp",
+			columns: map[string]string{
+				"Status":   ".status.nodegroupStatus",
+				"Version":  ".status.version",
+				"AMI Type": ".spec.amiType",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.crd, func(t *testing.T) {
+			g := NewWithT(t)
+
+			data, err := os.ReadFile(filepath.Join("..", "..", "..", "config", "crd", "bases", tt.crd))
+			g.Expect(err).NotTo(HaveOccurred())
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			g.Expect(yaml.Unmarshal(data, crd)).To(Succeed())
+
+			g.Expect(crd.Spec.Names.ShortNames).To(ContainElement(tt.shortName))
+			columns := map[string]string{}
+			for _, version := range crd.Spec.Versions {
+				if version.Name != GroupVersion.Version {
+					continue
+				}
+				for _, column := range version.AdditionalPrinterColumns {
+					columns[column.Name] = column.JSONPath
+				}
+			}
+			for name, jsonPath := range tt.columns {
+				g.Expect(columns).To(HaveKeyWithValue(name, jsonPath))
+			}
+		})
+	}
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package v1beta2
 
 import (
+	"fmt"
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
@@ -124,14 +127,221 @@ type AWSLaunchTemplate struct {
 	// PrivateDNSName is the options for the instance hostname.
 	// +optional
 	PrivateDNSName *infrav1.PrivateDNSName `json:"privateDnsName,omitempty"`
+
+	// ExistingTemplate references a launch template that is managed outside of CAPA.
+	// When set, CAPA does not create, version or delete a launch template and the
+	// AutoScalingGroup is configured to use the referenced template instead. It can't
+	// be combined with the other launch template fields, which only apply to a CAPA
+	// managed launch template.
+	// +optional
+	ExistingTemplate *ExistingLaunchTemplate `json:"existingTemplate,omitempty"`
+
+	// UserDataTransform references a Go template which renders the user data of the launch template
+	// from the bootstrap data. A new version of the launch template is created when the template or
+	// the rendered user data changes. Requires the UserDataTransform feature gate.
+	// +optional
+	UserDataTransform *infrav1.UserDataTransform `json:"userDataTransform,omitempty"`
+}
+
+// ExistingLaunchTemplate references a launch template that is managed outside of CAPA.
+type ExistingLaunchTemplate struct {
+	// ID is the ID of the launch template.
+	// +kubebuilder:validation:Pattern=`^lt-[0-9a-f]+$`
+	ID string `json:"id"`
+
+	// Version is the version of the launch template used by the AutoScalingGroup.
+	// Valid values are $Latest, $Default or a version number.
+	// +kubebuilder:default="$Latest"
+	// +kubebuilder:validation:Pattern=`^(\$Latest|\$Default|[1-9][0-9]*)$`
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// FollowLatest triggers an instance refresh of the AutoScalingGroup when the version
+	// the AutoScalingGroup resolves the launch template to changes, for example when a new
+	// version of the template is created while Version is $Latest.
+	// +optional
+	FollowLatest bool `json:"followLatest,omitempty"`
+}
+
+// GetVersion returns the version of the launch template used by the AutoScalingGroup,
+// defaulting to $Latest.
+func (t *ExistingLaunchTemplate) GetVersion() string {
+	if t.Version == "" {
+		return LaunchTemplateLatestVersion
+	}
+	return t.Version
+}
+
+// InstanceLaunchTemplate is the version of the launch template an instance was launched from.
+type InstanceLaunchTemplate struct {
+	// ID is the ID of the launch template.
+	ID string `json:"id,omitempty"`
+
+	// Version is the version of the launch template.
+	Version string `json:"version,omitempty"`
+}
+
+// LaunchTemplateChange describes the changes which led to a new version of the launch template.
+type LaunchTemplateChange struct {
+	// PreviousVersion is the version of the launch template before the change.
+	// +optional
+	PreviousVersion string `json:"previousVersion,omitempty"`
+
+	// Version is the version of the launch template after the change.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Changes lists the changed fields of the launch template with their previous and new values.
+	// The user data is only represented by its hash. At most 10 changes are listed.
+	// +optional
+	Changes []string `json:"changes,omitempty"`
+
+	// Time is the time of the change.
+	Time metav1.Time `json:"time"`
+}
+
+// Summary returns a single line describing the change.
+func (c *LaunchTemplateChange) Summary() string {
+	summary := fmt.Sprintf("launch template version %s -> %s", c.PreviousVersion, c.Version)
+	if len(c.Changes) > 0 {
+		summary += ": " + strings.Join(c.Changes, "; ")
+	}
+	return summary
 }
 
 // Overrides are used to override the instance type specified by the launch template with multiple
 // instance types that can be used to launch On-Demand Instances and Spot Instances.
 type Overrides struct {
-	InstanceType string `json:"instanceType"`
+	// InstanceType is the instance type to launch. Either InstanceType or InstanceRequirements must be set.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// InstanceRequirements are the attributes of the instance types to launch, which AWS selects the
+	// matching instance types with, instead of listing them in InstanceType.
+	// +optional
+	InstanceRequirements *InstanceRequirements `json:"instanceRequirements,omitempty"`
+
+	// WeightedCapacity is the number of capacity units an instance of InstanceType counts for, between 1
+	// and 999, e.g. its number of vCPUs. When it is set, the desired capacity of the Auto Scaling group, and
+	// so the replicas of the MachinePool, are capacity units instead of instances. It must be set for all
+	// the overrides or none, and can't be used with InstanceRequirements.
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]{0,2}$`
+	// +optional
+	WeightedCapacity string `json:"weightedCapacity,omitempty"`
+}
+
+// InstanceRequirements are the attributes of the instance types an Auto Scaling group can launch.
+// An instance type matches when it has all the attributes.
+type InstanceRequirements struct {
+	// VCPUCount is the minimum and maximum number of vCPUs.
+	VCPUCount VCPUCountRequest `json:"vCpuCount"`
+
+	// MemoryMiB is the minimum and maximum amount of memory, in MiB.
+	MemoryMiB MemoryMiBRequest `json:"memoryMiB"`
+
+	// CPUManufacturers are the manufacturers of the CPUs. Any manufacturer matches if it is empty.
+	// +optional
+	// +listType=set
+	CPUManufacturers []CPUManufacturer `json:"cpuManufacturers,omitempty"`
+
+	// AllowedInstanceTypes are the instance types which can match, e.g. m5.8xlarge. An asterisk can be
+	// used as a wildcard, e.g. c5*.* for the c5 family. All instance types can match if it is empty.
+	// It can't be combined with ExcludedInstanceTypes.
+	// +optional
+	// +listType=set
+	AllowedInstanceTypes []string `json:"allowedInstanceTypes,omitempty"`
+
+	// ExcludedInstanceTypes are the instance types which don't match, e.g. m5.8xlarge. An asterisk can be
+	// used as a wildcard, e.g. c5*.* for the c5 family.
+	// +optional
+	// +listType=set
+	ExcludedInstanceTypes []string `json:"excludedInstanceTypes,omitempty"`
+
+	// BareMetal indicates whether bare metal instance types are included, excluded or required.
+	// AWS excludes them if it is omitted.
+	// +kubebuilder:validation:Enum=included;excluded;required
+	// +optional
+	BareMetal InstanceRequirementsFilter `json:"bareMetal,omitempty"`
+
+	// BurstablePerformance indicates whether burstable performance instance types, e.g. the T family,
+	// are included, excluded or required. AWS excludes them if it is omitted.
+	// +kubebuilder:validation:Enum=included;excluded;required
+	// +optional
+	BurstablePerformance InstanceRequirementsFilter `json:"burstablePerformance,omitempty"`
+
+	// AcceleratorCount is the minimum and maximum number of accelerators, e.g. GPUs. A maximum of 0
+	// excludes the instance types with accelerators.
+	// +optional
+	AcceleratorCount *AcceleratorCountRequest `json:"acceleratorCount,omitempty"`
+}
+
+// VCPUCountRequest is a range of number of vCPUs.
+type VCPUCountRequest struct {
+	// Min is the minimum number of vCPUs.
+	// +kubebuilder:validation:Minimum=0
+	Min int64 `json:"min"`
+
+	// Max is the maximum number of vCPUs. There is no maximum if it is omitted.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Max *int64 `json:"max,omitempty"`
+}
+
+// MemoryMiBRequest is a range of amount of memory, in MiB.
+type MemoryMiBRequest struct {
+	// Min is the minimum amount of memory, in MiB.
+	// +kubebuilder:validation:Minimum=0
+	Min int64 `json:"min"`
+
+	// Max is the maximum amount of memory, in MiB. There is no maximum if it is omitted.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Max *int64 `json:"max,omitempty"`
 }
 
+// AcceleratorCountRequest is a range of number of accelerators.
+type AcceleratorCountRequest struct {
+	// Min is the minimum number of accelerators. There is no minimum if it is omitted.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Min *int64 `json:"min,omitempty"`
+
+	// Max is the maximum number of accelerators. There is no maximum if it is omitted.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Max *int64 `json:"max,omitempty"`
+}
+
+// CPUManufacturer is the manufacturer of the CPUs of an instance type.
+// +kubebuilder:validation:Enum=intel;amd;amazon-web-services
+type CPUManufacturer string
+
+const (
+	// CPUManufacturerIntel is Intel.
+	CPUManufacturerIntel = CPUManufacturer("intel")
+
+	// CPUManufacturerAMD is AMD.
+	CPUManufacturerAMD = CPUManufacturer("amd")
+
+	// CPUManufacturerAWS is AWS, for the Graviton CPUs.
+	CPUManufacturerAWS = CPUManufacturer("amazon-web-services")
+)
+
+// InstanceRequirementsFilter indicates whether the instance types with an attribute are included, excluded
+// or required.
+type InstanceRequirementsFilter string
+
+const (
+	// InstanceRequirementsFilterIncluded includes the instance types with the attribute.
+	InstanceRequirementsFilterIncluded = InstanceRequirementsFilter("included")
+
+	// InstanceRequirementsFilterExcluded excludes the instance types with the attribute.
+	InstanceRequirementsFilterExcluded = InstanceRequirementsFilter("excluded")
+
+	// InstanceRequirementsFilterRequired only includes the instance types with the attribute.
+	InstanceRequirementsFilterRequired = InstanceRequirementsFilter("required")
+)
+
 // OnDemandAllocationStrategy indicates how to allocate instance types to fulfill On-Demand capacity.
 type OnDemandAllocationStrategy string
 
@@ -180,9 +390,17 @@ type InstancesDistribution struct {
 	// +kubebuilder:default=lowest-price
 	SpotAllocationStrategy SpotAllocationStrategy `json:"spotAllocationStrategy,omitempty"`
 
+	// OnDemandBaseCapacity is the minimum number of instances of the Auto Scaling group which are
+	// On-Demand Instances. It is fulfilled before OnDemandPercentageAboveBaseCapacity is applied.
+	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:default=0
 	OnDemandBaseCapacity *int64 `json:"onDemandBaseCapacity,omitempty"`
 
+	// OnDemandPercentageAboveBaseCapacity is the percentage of the instances beyond OnDemandBaseCapacity
+	// which are On-Demand Instances, the remaining ones being Spot Instances. 0 only launches Spot Instances
+	// above the base capacity.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
 	// +kubebuilder:default=100
 	OnDemandPercentageAboveBaseCapacity *int64 `json:"onDemandPercentageAboveBaseCapacity,omitempty"`
 }
@@ -190,7 +408,25 @@ type InstancesDistribution struct {
 // MixedInstancesPolicy for an Auto Scaling group.
 type MixedInstancesPolicy struct {
 	InstancesDistribution *InstancesDistribution `json:"instancesDistribution,omitempty"`
-	Overrides             []Overrides            `json:"overrides,omitempty"`
+
+	// Overrides are the instance types of the Auto Scaling group. Their order is the priority of the
+	// instance types when the OnDemandAllocationStrategy is prioritized, or the SpotAllocationStrategy
+	// is capacity-optimized-prioritized, the first override having the highest priority.
+	Overrides []Overrides `json:"overrides,omitempty"`
+}
+
+// UsesWeightedCapacity returns whether the instance types of the overrides count for a number of capacity
+// units, in which case the desired capacity of the Auto Scaling group doesn't match its number of instances.
+func (m *MixedInstancesPolicy) UsesWeightedCapacity() bool {
+	if m == nil {
+		return false
+	}
+	for _, override := range m.Overrides {
+		if override.WeightedCapacity != "" {
+			return true
+		}
+	}
+	return false
 }
 
 // Tags is a mapping for tags.
@@ -199,22 +435,139 @@ type Tags map[string]string
 // AutoScalingGroup describes an AWS autoscaling group.
 type AutoScalingGroup struct {
 	// The tags associated with the instance.
-	ID                    string          `json:"id,omitempty"`
-	Tags                  infrav1.Tags    `json:"tags,omitempty"`
-	Name                  string          `json:"name,omitempty"`
-	DesiredCapacity       *int32          `json:"desiredCapacity,omitempty"`
-	MaxSize               int32           `json:"maxSize,omitempty"`
-	MinSize               int32           `json:"minSize,omitempty"`
-	PlacementGroup        string          `json:"placementGroup,omitempty"`
-	Subnets               []string        `json:"subnets,omitempty"`
-	DefaultCoolDown       metav1.Duration `json:"defaultCoolDown,omitempty"`
-	DefaultInstanceWarmup metav1.Duration `json:"defaultInstanceWarmup,omitempty"`
-	CapacityRebalance     bool            `json:"capacityRebalance,omitempty"`
+	ID                    string           `json:"id,omitempty"`
+	Tags                  infrav1.Tags     `json:"tags,omitempty"`
+	Name                  string           `json:"name,omitempty"`
+	DesiredCapacity       *int32           `json:"desiredCapacity,omitempty"`
+	MaxSize               int32            `json:"maxSize,omitempty"`
+	MinSize               int32            `json:"minSize,omitempty"`
+	PlacementGroup        string           `json:"placementGroup,omitempty"`
+	Subnets               []string         `json:"subnets,omitempty"`
+	DefaultCoolDown       metav1.Duration  `json:"defaultCoolDown,omitempty"`
+	DefaultInstanceWarmup *metav1.Duration `json:"defaultInstanceWarmup,omitempty"`
+	CapacityRebalance     bool             `json:"capacityRebalance,omitempty"`
+	TerminationPolicies   []string         `json:"terminationPolicies,omitempty"`
+	LaunchTemplateID      string           `json:"launchTemplateID,omitempty"`
+	LaunchTemplateVersion string           `json:"launchTemplateVersion,omitempty"`
+
+	// InstanceLaunchTemplates are the launch templates the instances were launched from, by instance ID.
+	// Instances launched from a launch configuration are left out.
+	InstanceLaunchTemplates map[string]InstanceLaunchTemplate `json:"instanceLaunchTemplates,omitempty"`
+
+	// InstanceHealthStatuses are the health statuses of the instances, Healthy or Unhealthy, by instance ID.
+	InstanceHealthStatuses map[string]string `json:"instanceHealthStatuses,omitempty"`
 
 	MixedInstancesPolicy      *MixedInstancesPolicy `json:"mixedInstancesPolicy,omitempty"`
+	HealthCheckType           HealthCheckType       `json:"healthCheckType,omitempty"`
+	HealthCheckGracePeriod    *metav1.Duration      `json:"healthCheckGracePeriod,omitempty"`
+	MaxInstanceLifetime       *metav1.Duration      `json:"maxInstanceLifetime,omitempty"`
+	CreatedTime               metav1.Time           `json:"createdTime,omitempty"`
 	Status                    ASGStatus
 	Instances                 []infrav1.Instance `json:"instances,omitempty"`
 	CurrentlySuspendProcesses []string           `json:"currentlySuspendProcesses,omitempty"`
+	EnabledMetrics            []string           `json:"enabledMetrics,omitempty"`
+	ProtectedInstances        []string           `json:"protectedInstances,omitempty"`
+	PropagatedTags            []string           `json:"propagatedTags,omitempty"`
+
+	NewInstancesProtectedFromScaleIn bool     `json:"newInstancesProtectedFromScaleIn,omitempty"`
+	TargetGroupARNs                  []string `json:"targetGroupARNs,omitempty"`
+	LoadBalancerNames                []string `json:"loadBalancerNames,omitempty"`
+}
+
+// AutoscalingReplicasManagedBy is the value of the cluster.x-k8s.io/replicas-managed-by annotation that
+// CAPA sets on the MachinePool of a machine pool whose autoscaling is enabled. CAPA only removes the
+// annotation with this value once the autoscaling is disabled.
+const AutoscalingReplicasManagedBy = "aws-machinepool-autoscaling"
+
+// MachinePoolAutoscaling lets the cluster autoscaler scale a machine pool between a minimum and a
+// maximum size.
+type MachinePoolAutoscaling struct {
+	// Enabled tags the Auto Scaling group of the machine pool for auto-discovery by the cluster
+	// autoscaler, and leaves the replicas of the MachinePool to the cluster autoscaler.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// MinSize is the minimum size of the machine pool.
+	// +kubebuilder:validation:Minimum=0
+	MinSize int32 `json:"minSize"`
+
+	// MaxSize is the maximum size of the machine pool.
+	// +kubebuilder:validation:Minimum=1
+	MaxSize int32 `json:"maxSize"`
+}
+
+// IsEnabled returns true if the cluster autoscaler scales the machine pool.
+func (a *MachinePoolAutoscaling) IsEnabled() bool {
+	return a != nil && a.Enabled
+}
+
+// HealthCheckType is the type of health check of the instances of an Auto Scaling group.
+type HealthCheckType string
+
+const (
+	// HealthCheckTypeEC2 replaces the instances which fail the EC2 status checks.
+	HealthCheckTypeEC2 HealthCheckType = "EC2"
+	// HealthCheckTypeELB also replaces the instances which fail the health checks of the target groups
+	// of the Auto Scaling group.
+	HealthCheckTypeELB HealthCheckType = "ELB"
+)
+
+// MetricsCollectionGranularity1Minute is the only granularity of the group metrics supported by AWS.
+const MetricsCollectionGranularity1Minute = "1Minute"
+
+// ASGMetrics are the group metrics an Auto Scaling group can publish to CloudWatch.
+var ASGMetrics = []string{
+	"GroupMinSize",
+	"GroupMaxSize",
+	"GroupDesiredCapacity",
+	"GroupInServiceInstances",
+	"GroupPendingInstances",
+	"GroupStandbyInstances",
+	"GroupTerminatingInstances",
+	"GroupTotalInstances",
+	"GroupInServiceCapacity",
+	"GroupPendingCapacity",
+	"GroupStandbyCapacity",
+	"GroupTerminatingCapacity",
+	"GroupTotalCapacity",
+	"WarmPoolDesiredCapacity",
+	"WarmPoolWarmedCapacity",
+	"WarmPoolPendingCapacity",
+	"WarmPoolTerminatingCapacity",
+	"WarmPoolTotalCapacity",
+	"GroupAndWarmPoolDesiredCapacity",
+	"GroupAndWarmPoolTotalCapacity",
+}
+
+// MetricsCollection describes the group metrics an Auto Scaling group publishes to CloudWatch.
+type MetricsCollection struct {
+	// Granularity is the frequency at which the metrics are published. 1Minute is the only granularity
+	// supported by AWS.
+	// +kubebuilder:default="1Minute"
+	// +kubebuilder:validation:Enum="1Minute"
+	// +optional
+	Granularity string `json:"granularity,omitempty"`
+
+	// Metrics are the group metrics to publish, e.g. GroupDesiredCapacity and GroupInServiceInstances.
+	// All the group metrics are published when it is empty.
+	// +optional
+	Metrics []string `json:"metrics,omitempty"`
+}
+
+// GetGranularity returns the granularity of the metrics, defaulting to 1Minute.
+func (m *MetricsCollection) GetGranularity() string {
+	if m.Granularity == "" {
+		return MetricsCollectionGranularity1Minute
+	}
+	return m.Granularity
+}
+
+// GetMetrics returns the group metrics to publish, all of them if none are listed.
+func (m *MetricsCollection) GetMetrics() []string {
+	if len(m.Metrics) == 0 {
+		return ASGMetrics
+	}
+	return m.Metrics
 }
 
 // ASGStatus is a status string returned by the autoscaling API.
@@ -223,6 +576,153 @@ type ASGStatus string
 // ASGStatusDeleteInProgress is the string representing an ASG that is currently deleting.
 var ASGStatusDeleteInProgress = ASGStatus("Delete in progress")
 
+// ScalingActivity describes a scaling activity of an Auto Scaling group.
+type ScalingActivity struct {
+	// ActivityID is the ID of the scaling activity.
+	ActivityID string `json:"activityID"`
+
+	// Description is a friendly, more verbose description of the scaling activity.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// StatusCode is the current status of the scaling activity.
+	StatusCode string `json:"statusCode"`
+
+	// StatusMessage is a friendly, more verbose description of the scaling activity status.
+	// +optional
+	StatusMessage string `json:"statusMessage,omitempty"`
+
+	// StartTime is the start time of the scaling activity.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+}
+
+// ScalingState describes the in-flight scaling state of an Auto Scaling group.
+type ScalingState struct {
+	// PendingInstances is the number of instances in a Pending lifecycle state.
+	// +optional
+	PendingInstances int32 `json:"pendingInstances"`
+
+	// OpenSpotInstanceRequests is the number of open spot instance requests for the launch template.
+	// +optional
+	OpenSpotInstanceRequests int32 `json:"openSpotInstanceRequests"`
+
+	// LatestScalingActivity is the most recent scaling activity of the Auto Scaling group.
+	// +optional
+	LatestScalingActivity *ScalingActivity `json:"latestScalingActivity,omitempty"`
+
+	// ScaleUpPendingSince is the time since which the Auto Scaling group has had fewer
+	// in-service instances than its desired capacity.
+	// +optional
+	ScaleUpPendingSince *metav1.Time `json:"scaleUpPendingSince,omitempty"`
+
+	// ScaleUpDelayed is true once the scale-up has been pending for longer than the scale-up delay threshold.
+	// +optional
+	ScaleUpDelayed bool `json:"scaleUpDelayed,omitempty"`
+}
+
+// ScalingBlockerCode identifies the kind of a ScalingBlocker.
+// +kubebuilder:validation:Enum=FailedScalingActivity;InstanceRefreshInProgress;InstanceRefreshFailed;LaunchTemplateInvalid;SpotCapacity;ProcessesSuspended;QuotaExceeded
+type ScalingBlockerCode string
+
+const (
+	// ScalingBlockerFailedScalingActivity is reported when the scaling activities of the Auto Scaling group fail.
+	ScalingBlockerFailedScalingActivity = ScalingBlockerCode("FailedScalingActivity")
+	// ScalingBlockerInstanceRefreshInProgress is reported while an instance refresh replaces the instances.
+	ScalingBlockerInstanceRefreshInProgress = ScalingBlockerCode("InstanceRefreshInProgress")
+	// ScalingBlockerInstanceRefreshFailed is reported when the last instance refresh failed.
+	ScalingBlockerInstanceRefreshFailed = ScalingBlockerCode("InstanceRefreshFailed")
+	// ScalingBlockerLaunchTemplateInvalid is reported when the launch template can't be reconciled, or
+	// instances can't be launched from its version.
+	ScalingBlockerLaunchTemplateInvalid = ScalingBlockerCode("LaunchTemplateInvalid")
+	// ScalingBlockerSpotCapacity is reported when spot instances can't be launched for lack of capacity.
+	ScalingBlockerSpotCapacity = ScalingBlockerCode("SpotCapacity")
+	// ScalingBlockerProcessesSuspended is reported when scaling processes of the Auto Scaling group are suspended.
+	ScalingBlockerProcessesSuspended = ScalingBlockerCode("ProcessesSuspended")
+	// ScalingBlockerQuotaExceeded is reported when an account quota prevents instances from being launched.
+	ScalingBlockerQuotaExceeded = ScalingBlockerCode("QuotaExceeded")
+)
+
+// ScalingBlocker describes something which may prevent the Auto Scaling group from reaching its desired capacity.
+type ScalingBlocker struct {
+	// Code identifies the kind of the blocker.
+	Code ScalingBlockerCode `json:"code"`
+
+	// Message is a human readable description of the blocker.
+	Message string `json:"message"`
+}
+
+// PendingLifecycleAction describes an instance held in a wait state by a lifecycle hook.
+type PendingLifecycleAction struct {
+	// InstanceID is the ID of the instance waiting on the lifecycle hook.
+	InstanceID string `json:"instanceID"`
+
+	// HookName is the name of the lifecycle hook the instance is waiting on.
+	HookName string `json:"hookName"`
+
+	// Transition is the lifecycle transition of the hook.
+	Transition infrav1.LifecycleTransition `json:"transition"`
+
+	// StartTime is the time at which the instance was first observed waiting on the lifecycle hook.
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// LifecycleHookSource is where the spec of a lifecycle hook of an AWSMachinePool comes from.
+type LifecycleHookSource string
+
+const (
+	// LifecycleHookSourceResourcePolicy is a lifecycle hook of the resource policy of the AWSCluster.
+	LifecycleHookSourceResourcePolicy LifecycleHookSource = "ResourcePolicy"
+	// LifecycleHookSourceCluster is a default lifecycle hook of the AWSCluster or AWSManagedControlPlane.
+	LifecycleHookSourceCluster LifecycleHookSource = "Cluster"
+	// LifecycleHookSourceMachinePool is a lifecycle hook of the AWSMachinePool spec.
+	LifecycleHookSourceMachinePool LifecycleHookSource = "AWSMachinePool"
+	// LifecycleHookSourceManaged is the managed launch lifecycle hook.
+	LifecycleHookSourceManaged LifecycleHookSource = "Managed"
+)
+
+// LifecycleHookStatus describes a lifecycle hook of the Auto Scaling group of an AWSMachinePool.
+type LifecycleHookStatus struct {
+	// Name is the name of the lifecycle hook.
+	Name string `json:"name"`
+
+	// LifecycleTransition is the state of the EC2 instance to which the lifecycle hook is attached.
+	LifecycleTransition infrav1.LifecycleTransition `json:"lifecycleTransition"`
+
+	// Source is where the spec of the lifecycle hook comes from.
+	// +kubebuilder:validation:Enum=ResourcePolicy;Cluster;AWSMachinePool;Managed
+	Source LifecycleHookSource `json:"source"`
+}
+
+const (
+	// ScalingActivityStatusFailed is the status code of a scaling activity that failed.
+	ScalingActivityStatusFailed = "Failed"
+	// ScalingActivityStatusSuccessful is the status code of a scaling activity that succeeded.
+	ScalingActivityStatusSuccessful = "Successful"
+)
+
+var (
+	// InstanceStatePending is the lifecycle state of an ASG instance that is launching.
+	InstanceStatePending = infrav1.InstanceState("Pending")
+	// InstanceStatePendingWait is the lifecycle state of an ASG instance that is held by a launch lifecycle hook.
+	InstanceStatePendingWait = infrav1.InstanceState("Pending:Wait")
+	// InstanceStatePendingProceed is the lifecycle state of an ASG instance whose launch lifecycle hooks completed.
+	InstanceStatePendingProceed = infrav1.InstanceState("Pending:Proceed")
+	// InstanceStateInService is the lifecycle state of an ASG instance that is in service.
+	InstanceStateInService = infrav1.InstanceState("InService")
+	// InstanceStateStandby is the lifecycle state of an ASG instance that is on standby.
+	InstanceStateStandby = infrav1.InstanceState("Standby")
+	// InstanceStateTerminatingWait is the lifecycle state of an ASG instance that is held by a termination lifecycle hook.
+	InstanceStateTerminatingWait = infrav1.InstanceState("Terminating:Wait")
+)
+
+const (
+	// InstanceHealthStatusHealthy is the health status of an ASG instance that is healthy.
+	InstanceHealthStatusHealthy = "Healthy"
+	// InstanceHealthStatusUnhealthy is the health status of an ASG instance that failed its health checks.
+	InstanceHealthStatusUnhealthy = "Unhealthy"
+)
+
 // TaintEffect is the effect for a Kubernetes taint.
 type TaintEffect string
 
@@ -312,3 +812,41 @@ const (
 func NewAZSubnetType(t AZSubnetType) *AZSubnetType {
 	return &t
 }
+
+// NodeUserDataExtra defines additional configuration that CAPA adds to the user data of the
+// instances, around the bootstrap data of the MachinePool.
+type NodeUserDataExtra struct {
+	// PreBootstrap is a list of commands to run before the bootstrap commands.
+	// +optional
+	PreBootstrap []string `json:"preBootstrap,omitempty"`
+
+	// PostBootstrap is a list of commands to run after the bootstrap commands.
+	// +optional
+	PostBootstrap []string `json:"postBootstrap,omitempty"`
+
+	// Files is a list of files to write to the instance before the bootstrap commands are run.
+	// +optional
+	Files []File `json:"files,omitempty"`
+}
+
+// File defines a file to write to the instance.
+type File struct {
+	// Path is the absolute path of the file on the instance.
+	Path string `json:"path"`
+
+	// Content is the content of the file.
+	Content string `json:"content"`
+
+	// Owner specifies the ownership of the file, e.g. "root:root".
+	// +optional
+	Owner string `json:"owner,omitempty"`
+
+	// Permissions specifies the permissions of the file in octal notation, e.g. "0640".
+	// +optional
+	Permissions string `json:"permissions,omitempty"`
+}
+
+// IsEmpty returns true if no extra user data is defined.
+func (e *NodeUserDataExtra) IsEmpty() bool {
+	return e == nil || (len(e.PreBootstrap) == 0 && len(e.PostBootstrap) == 0 && len(e.Files) == 0)
+}
@@ -23,11 +23,31 @@ package v1beta2
 import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	apiv1beta2 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	"sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/errors"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ASGDeletionStatus) DeepCopyInto(out *ASGDeletionStatus) {
+	*out = *in
+	if in.PhaseStartTime != nil {
+		in, out := &in.PhaseStartTime, &out.PhaseStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ASGDeletionStatus.
+func (in *ASGDeletionStatus) DeepCopy() *ASGDeletionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ASGDeletionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSFargateProfile) DeepCopyInto(out *AWSFargateProfile) {
 	*out = *in
@@ -128,6 +148,16 @@ func (in *AWSLaunchTemplate) DeepCopyInto(out *AWSLaunchTemplate) {
 		*out = new(apiv1beta2.PrivateDNSName)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ExistingTemplate != nil {
+		in, out := &in.ExistingTemplate, &out.ExistingTemplate
+		*out = new(ExistingLaunchTemplate)
+		**out = **in
+	}
+	if in.UserDataTransform != nil {
+		in, out := &in.UserDataTransform, &out.UserDataTransform
+		*out = new(apiv1beta2.UserDataTransform)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLaunchTemplate.
@@ -175,6 +205,14 @@ func (in *AWSMachinePoolInstanceStatus) DeepCopyInto(out *AWSMachinePoolInstance
 		*out = new(string)
 		**out = **in
 	}
+	if in.LaunchedAt != nil {
+		in, out := &in.LaunchedAt, &out.LaunchedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.NodeRegisteredAt != nil {
+		in, out := &in.NodeRegisteredAt, &out.NodeRegisteredAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSMachinePoolInstanceStatus.
@@ -222,6 +260,11 @@ func (in *AWSMachinePoolList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSMachinePoolSpec) DeepCopyInto(out *AWSMachinePoolSpec) {
 	*out = *in
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(MachinePoolAutoscaling)
+		**out = **in
+	}
 	if in.AvailabilityZones != nil {
 		in, out := &in.AvailabilityZones, &out.AvailabilityZones
 		*out = make([]string, len(*in))
@@ -246,6 +289,11 @@ func (in *AWSMachinePoolSpec) DeepCopyInto(out *AWSMachinePoolSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.ExcludedClusterTags != nil {
+		in, out := &in.ExcludedClusterTags, &out.ExcludedClusterTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.AWSLaunchTemplate.DeepCopyInto(&out.AWSLaunchTemplate)
 	if in.MixedInstancesPolicy != nil {
 		in, out := &in.MixedInstancesPolicy, &out.MixedInstancesPolicy
@@ -258,17 +306,110 @@ func (in *AWSMachinePoolSpec) DeepCopyInto(out *AWSMachinePoolSpec) {
 		copy(*out, *in)
 	}
 	out.DefaultCoolDown = in.DefaultCoolDown
-	out.DefaultInstanceWarmup = in.DefaultInstanceWarmup
+	if in.DefaultInstanceWarmup != nil {
+		in, out := &in.DefaultInstanceWarmup, &out.DefaultInstanceWarmup
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.RefreshPreferences != nil {
 		in, out := &in.RefreshPreferences, &out.RefreshPreferences
 		*out = new(RefreshPreferences)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TerminationPolicies != nil {
+		in, out := &in.TerminationPolicies, &out.TerminationPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthCheckGracePeriod != nil {
+		in, out := &in.HealthCheckGracePeriod, &out.HealthCheckGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxInstanceLifetime != nil {
+		in, out := &in.MaxInstanceLifetime, &out.MaxInstanceLifetime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MetricsCollection != nil {
+		in, out := &in.MetricsCollection, &out.MetricsCollection
+		*out = new(MetricsCollection)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SuspendProcesses != nil {
 		in, out := &in.SuspendProcesses, &out.SuspendProcesses
 		*out = new(SuspendProcessesTypes)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AWSLifecycleHooks != nil {
+		in, out := &in.AWSLifecycleHooks, &out.AWSLifecycleHooks
+		*out = make([]apiv1beta2.AWSLifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ManagedLaunchLifecycleHook != nil {
+		in, out := &in.ManagedLaunchLifecycleHook, &out.ManagedLaunchLifecycleHook
+		*out = new(ManagedLaunchLifecycleHook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleUpDelayThreshold != nil {
+		in, out := &in.ScaleUpDelayThreshold, &out.ScaleUpDelayThreshold
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.NodeUserDataExtra != nil {
+		in, out := &in.NodeUserDataExtra, &out.NodeUserDataExtra
+		*out = new(NodeUserDataExtra)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LoadBalancerAttachments != nil {
+		in, out := &in.LoadBalancerAttachments, &out.LoadBalancerAttachments
+		*out = make([]LoadBalancerAttachment, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScaleDownPolicy != nil {
+		in, out := &in.ScaleDownPolicy, &out.ScaleDownPolicy
+		*out = new(ScaleDownPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AMIVersionOverride != nil {
+		in, out := &in.AMIVersionOverride, &out.AMIVersionOverride
+		*out = new(string)
+		**out = **in
+	}
+	if in.DeletionTimeout != nil {
+		in, out := &in.DeletionTimeout, &out.DeletionTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TargetGroupARNs != nil {
+		in, out := &in.TargetGroupARNs, &out.TargetGroupARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClassicLoadBalancers != nil {
+		in, out := &in.ClassicLoadBalancers, &out.ClassicLoadBalancers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScheduledActions != nil {
+		in, out := &in.ScheduledActions, &out.ScheduledActions
+		*out = make([]ScheduledAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstancesReadyTimeout != nil {
+		in, out := &in.InstancesReadyTimeout, &out.InstancesReadyTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SpotInterruptionHandling != nil {
+		in, out := &in.SpotInterruptionHandling, &out.SpotInterruptionHandling
+		*out = new(SpotInterruptionHandling)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSMachinePoolSpec.
@@ -303,6 +444,116 @@ func (in *AWSMachinePoolStatus) DeepCopyInto(out *AWSMachinePoolStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.LastLaunchTemplateChange != nil {
+		in, out := &in.LastLaunchTemplateChange, &out.LastLaunchTemplateChange
+		*out = new(LaunchTemplateChange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScalingState != nil {
+		in, out := &in.ScalingState, &out.ScalingState
+		*out = new(ScalingState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingLifecycleActions != nil {
+		in, out := &in.PendingLifecycleActions, &out.PendingLifecycleActions
+		*out = make([]PendingLifecycleAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LifecycleHooks != nil {
+		in, out := &in.LifecycleHooks, &out.LifecycleHooks
+		*out = make([]LifecycleHookStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.LifecycleHooksLastSyncTime != nil {
+		in, out := &in.LifecycleHooksLastSyncTime, &out.LifecycleHooksLastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AdditionalTags != nil {
+		in, out := &in.AdditionalTags, &out.AdditionalTags
+		*out = make(apiv1beta2.Tags, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ScaleDown != nil {
+		in, out := &in.ScaleDown, &out.ScaleDown
+		*out = new(ScaleDownStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EvacuatedAvailabilityZones != nil {
+		in, out := &in.EvacuatedAvailabilityZones, &out.EvacuatedAvailabilityZones
+		*out = make([]EvacuatedAvailabilityZone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RefreshExcludedInstances != nil {
+		in, out := &in.RefreshExcludedInstances, &out.RefreshExcludedInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScaleInProtectedInstances != nil {
+		in, out := &in.ScaleInProtectedInstances, &out.ScaleInProtectedInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AttachedTargetGroupARNs != nil {
+		in, out := &in.AttachedTargetGroupARNs, &out.AttachedTargetGroupARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AttachedClassicLoadBalancers != nil {
+		in, out := &in.AttachedClassicLoadBalancers, &out.AttachedClassicLoadBalancers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScheduledActions != nil {
+		in, out := &in.ScheduledActions, &out.ScheduledActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DesiredCapacity != nil {
+		in, out := &in.DesiredCapacity, &out.DesiredCapacity
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InstanceRefresh != nil {
+		in, out := &in.InstanceRefresh, &out.InstanceRefresh
+		*out = new(InstanceRefreshStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SuspendedProcesses != nil {
+		in, out := &in.SuspendedProcesses, &out.SuspendedProcesses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EnabledMetrics != nil {
+		in, out := &in.EnabledMetrics, &out.EnabledMetrics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+	if in.AMIKubernetesVersion != nil {
+		in, out := &in.AMIKubernetesVersion, &out.AMIKubernetesVersion
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScalingBlockers != nil {
+		in, out := &in.ScalingBlockers, &out.ScalingBlockers
+		*out = make([]ScalingBlocker, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deletion != nil {
+		in, out := &in.Deletion, &out.Deletion
+		*out = new(ASGDeletionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.FailureReason != nil {
 		in, out := &in.FailureReason, &out.FailureReason
 		*out = new(errors.MachineStatusError)
@@ -424,6 +675,11 @@ func (in *AWSManagedMachinePoolSpec) DeepCopyInto(out *AWSManagedMachinePoolSpec
 		*out = new(string)
 		**out = **in
 	}
+	if in.AMIVersionOverride != nil {
+		in, out := &in.AMIVersionOverride, &out.AMIVersionOverride
+		*out = new(string)
+		**out = **in
+	}
 	if in.AMIType != nil {
 		in, out := &in.AMIType, &out.AMIType
 		*out = new(ManagedMachineAMIType)
@@ -456,6 +712,11 @@ func (in *AWSManagedMachinePoolSpec) DeepCopyInto(out *AWSManagedMachinePoolSpec
 		*out = new(ManagedMachinePoolScaling)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(MachinePoolAutoscaling)
+		**out = **in
+	}
 	if in.RemoteAccess != nil {
 		in, out := &in.RemoteAccess, &out.RemoteAccess
 		*out = new(ManagedRemoteAccess)
@@ -496,6 +757,11 @@ func (in *AWSManagedMachinePoolSpec) DeepCopy() *AWSManagedMachinePoolSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSManagedMachinePoolStatus) DeepCopyInto(out *AWSManagedMachinePoolStatus) {
 	*out = *in
+	if in.DesiredSize != nil {
+		in, out := &in.DesiredSize, &out.DesiredSize
+		*out = new(int32)
+		**out = **in
+	}
 	if in.LaunchTemplateID != nil {
 		in, out := &in.LaunchTemplateID, &out.LaunchTemplateID
 		*out = new(string)
@@ -506,6 +772,21 @@ func (in *AWSManagedMachinePoolStatus) DeepCopyInto(out *AWSManagedMachinePoolSt
 		*out = new(string)
 		**out = **in
 	}
+	if in.LastLaunchTemplateChange != nil {
+		in, out := &in.LastLaunchTemplateChange, &out.LastLaunchTemplateChange
+		*out = new(LaunchTemplateChange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+	if in.AMIKubernetesVersion != nil {
+		in, out := &in.AMIKubernetesVersion, &out.AMIKubernetesVersion
+		*out = new(string)
+		**out = **in
+	}
 	if in.FailureReason != nil {
 		in, out := &in.FailureReason, &out.FailureReason
 		*out = new(errors.MachineStatusError)
@@ -535,6 +816,31 @@ func (in *AWSManagedMachinePoolStatus) DeepCopy() *AWSManagedMachinePoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AcceleratorCountRequest) DeepCopyInto(out *AcceleratorCountRequest) {
+	*out = *in
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AcceleratorCountRequest.
+func (in *AcceleratorCountRequest) DeepCopy() *AcceleratorCountRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(AcceleratorCountRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoScalingGroup) DeepCopyInto(out *AutoScalingGroup) {
 	*out = *in
@@ -556,12 +862,46 @@ func (in *AutoScalingGroup) DeepCopyInto(out *AutoScalingGroup) {
 		copy(*out, *in)
 	}
 	out.DefaultCoolDown = in.DefaultCoolDown
-	out.DefaultInstanceWarmup = in.DefaultInstanceWarmup
+	if in.DefaultInstanceWarmup != nil {
+		in, out := &in.DefaultInstanceWarmup, &out.DefaultInstanceWarmup
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TerminationPolicies != nil {
+		in, out := &in.TerminationPolicies, &out.TerminationPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstanceLaunchTemplates != nil {
+		in, out := &in.InstanceLaunchTemplates, &out.InstanceLaunchTemplates
+		*out = make(map[string]InstanceLaunchTemplate, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.InstanceHealthStatuses != nil {
+		in, out := &in.InstanceHealthStatuses, &out.InstanceHealthStatuses
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.MixedInstancesPolicy != nil {
 		in, out := &in.MixedInstancesPolicy, &out.MixedInstancesPolicy
 		*out = new(MixedInstancesPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HealthCheckGracePeriod != nil {
+		in, out := &in.HealthCheckGracePeriod, &out.HealthCheckGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxInstanceLifetime != nil {
+		in, out := &in.MaxInstanceLifetime, &out.MaxInstanceLifetime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	in.CreatedTime.DeepCopyInto(&out.CreatedTime)
 	if in.Instances != nil {
 		in, out := &in.Instances, &out.Instances
 		*out = make([]apiv1beta2.Instance, len(*in))
@@ -574,6 +914,31 @@ func (in *AutoScalingGroup) DeepCopyInto(out *AutoScalingGroup) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.EnabledMetrics != nil {
+		in, out := &in.EnabledMetrics, &out.EnabledMetrics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProtectedInstances != nil {
+		in, out := &in.ProtectedInstances, &out.ProtectedInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PropagatedTags != nil {
+		in, out := &in.PropagatedTags, &out.PropagatedTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetGroupARNs != nil {
+		in, out := &in.TargetGroupARNs, &out.TargetGroupARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LoadBalancerNames != nil {
+		in, out := &in.LoadBalancerNames, &out.LoadBalancerNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoScalingGroup.
@@ -617,6 +982,41 @@ func (in *EBS) DeepCopy() *EBS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvacuatedAvailabilityZone) DeepCopyInto(out *EvacuatedAvailabilityZone) {
+	*out = *in
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvacuatedAvailabilityZone.
+func (in *EvacuatedAvailabilityZone) DeepCopy() *EvacuatedAvailabilityZone {
+	if in == nil {
+		return nil
+	}
+	out := new(EvacuatedAvailabilityZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExistingLaunchTemplate) DeepCopyInto(out *ExistingLaunchTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExistingLaunchTemplate.
+func (in *ExistingLaunchTemplate) DeepCopy() *ExistingLaunchTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ExistingLaunchTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FargateProfileSpec) DeepCopyInto(out *FargateProfileSpec) {
 	*out = *in
@@ -706,18 +1106,104 @@ func (in *FargateSelector) DeepCopy() *FargateSelector {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstancesDistribution) DeepCopyInto(out *InstancesDistribution) {
+func (in *File) DeepCopyInto(out *File) {
 	*out = *in
-	if in.OnDemandBaseCapacity != nil {
-		in, out := &in.OnDemandBaseCapacity, &out.OnDemandBaseCapacity
-		*out = new(int64)
-		**out = **in
-	}
-	if in.OnDemandPercentageAboveBaseCapacity != nil {
-		in, out := &in.OnDemandPercentageAboveBaseCapacity, &out.OnDemandPercentageAboveBaseCapacity
-		*out = new(int64)
-		**out = **in
-	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new File.
+func (in *File) DeepCopy() *File {
+	if in == nil {
+		return nil
+	}
+	out := new(File)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceLaunchTemplate) DeepCopyInto(out *InstanceLaunchTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceLaunchTemplate.
+func (in *InstanceLaunchTemplate) DeepCopy() *InstanceLaunchTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceLaunchTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceRefreshStatus) DeepCopyInto(out *InstanceRefreshStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceRefreshStatus.
+func (in *InstanceRefreshStatus) DeepCopy() *InstanceRefreshStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceRefreshStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceRequirements) DeepCopyInto(out *InstanceRequirements) {
+	*out = *in
+	in.VCPUCount.DeepCopyInto(&out.VCPUCount)
+	in.MemoryMiB.DeepCopyInto(&out.MemoryMiB)
+	if in.CPUManufacturers != nil {
+		in, out := &in.CPUManufacturers, &out.CPUManufacturers
+		*out = make([]CPUManufacturer, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedInstanceTypes != nil {
+		in, out := &in.AllowedInstanceTypes, &out.AllowedInstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedInstanceTypes != nil {
+		in, out := &in.ExcludedInstanceTypes, &out.ExcludedInstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AcceleratorCount != nil {
+		in, out := &in.AcceleratorCount, &out.AcceleratorCount
+		*out = new(AcceleratorCountRequest)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceRequirements.
+func (in *InstanceRequirements) DeepCopy() *InstanceRequirements {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceRequirements)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstancesDistribution) DeepCopyInto(out *InstancesDistribution) {
+	*out = *in
+	if in.OnDemandBaseCapacity != nil {
+		in, out := &in.OnDemandBaseCapacity, &out.OnDemandBaseCapacity
+		*out = new(int64)
+		**out = **in
+	}
+	if in.OnDemandPercentageAboveBaseCapacity != nil {
+		in, out := &in.OnDemandPercentageAboveBaseCapacity, &out.OnDemandPercentageAboveBaseCapacity
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstancesDistribution.
@@ -730,6 +1216,92 @@ func (in *InstancesDistribution) DeepCopy() *InstancesDistribution {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LaunchTemplateChange) DeepCopyInto(out *LaunchTemplateChange) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LaunchTemplateChange.
+func (in *LaunchTemplateChange) DeepCopy() *LaunchTemplateChange {
+	if in == nil {
+		return nil
+	}
+	out := new(LaunchTemplateChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecycleHookStatus) DeepCopyInto(out *LifecycleHookStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecycleHookStatus.
+func (in *LifecycleHookStatus) DeepCopy() *LifecycleHookStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleHookStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerAttachment) DeepCopyInto(out *LoadBalancerAttachment) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerAttachment.
+func (in *LoadBalancerAttachment) DeepCopy() *LoadBalancerAttachment {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerAttachment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolAutoscaling) DeepCopyInto(out *MachinePoolAutoscaling) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolAutoscaling.
+func (in *MachinePoolAutoscaling) DeepCopy() *MachinePoolAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePoolAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedLaunchLifecycleHook) DeepCopyInto(out *ManagedLaunchLifecycleHook) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedLaunchLifecycleHook.
+func (in *ManagedLaunchLifecycleHook) DeepCopy() *ManagedLaunchLifecycleHook {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedLaunchLifecycleHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedMachinePoolScaling) DeepCopyInto(out *ManagedMachinePoolScaling) {
 	*out = *in
@@ -780,6 +1352,46 @@ func (in *ManagedRemoteAccess) DeepCopy() *ManagedRemoteAccess {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryMiBRequest) DeepCopyInto(out *MemoryMiBRequest) {
+	*out = *in
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryMiBRequest.
+func (in *MemoryMiBRequest) DeepCopy() *MemoryMiBRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryMiBRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsCollection) DeepCopyInto(out *MetricsCollection) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsCollection.
+func (in *MetricsCollection) DeepCopy() *MetricsCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsCollection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MixedInstancesPolicy) DeepCopyInto(out *MixedInstancesPolicy) {
 	*out = *in
@@ -791,7 +1403,9 @@ func (in *MixedInstancesPolicy) DeepCopyInto(out *MixedInstancesPolicy) {
 	if in.Overrides != nil {
 		in, out := &in.Overrides, &out.Overrides
 		*out = make([]Overrides, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -805,9 +1419,44 @@ func (in *MixedInstancesPolicy) DeepCopy() *MixedInstancesPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeUserDataExtra) DeepCopyInto(out *NodeUserDataExtra) {
+	*out = *in
+	if in.PreBootstrap != nil {
+		in, out := &in.PreBootstrap, &out.PreBootstrap
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostBootstrap != nil {
+		in, out := &in.PostBootstrap, &out.PostBootstrap
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]File, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUserDataExtra.
+func (in *NodeUserDataExtra) DeepCopy() *NodeUserDataExtra {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeUserDataExtra)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Overrides) DeepCopyInto(out *Overrides) {
 	*out = *in
+	if in.InstanceRequirements != nil {
+		in, out := &in.InstanceRequirements, &out.InstanceRequirements
+		*out = new(InstanceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Overrides.
@@ -820,6 +1469,22 @@ func (in *Overrides) DeepCopy() *Overrides {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingLifecycleAction) DeepCopyInto(out *PendingLifecycleAction) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingLifecycleAction.
+func (in *PendingLifecycleAction) DeepCopy() *PendingLifecycleAction {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingLifecycleAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Processes) DeepCopyInto(out *Processes) {
 	*out = *in
@@ -1054,6 +1719,26 @@ func (in *RefreshPreferences) DeepCopyInto(out *RefreshPreferences) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.SkipMatching != nil {
+		in, out := &in.SkipMatching, &out.SkipMatching
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoRollback != nil {
+		in, out := &in.AutoRollback, &out.AutoRollback
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CheckpointPercentages != nil {
+		in, out := &in.CheckpointPercentages, &out.CheckpointPercentages
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.CheckpointDelay != nil {
+		in, out := &in.CheckpointDelay, &out.CheckpointDelay
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RefreshPreferences.
@@ -1182,6 +1867,156 @@ func (in *RosaTaint) DeepCopy() *RosaTaint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleDownPolicy) DeepCopyInto(out *ScaleDownPolicy) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleDownPolicy.
+func (in *ScaleDownPolicy) DeepCopy() *ScaleDownPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleDownPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleDownStatus) DeepCopyInto(out *ScaleDownStatus) {
+	*out = *in
+	if in.LastStepTime != nil {
+		in, out := &in.LastStepTime, &out.LastStepTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleDownStatus.
+func (in *ScaleDownStatus) DeepCopy() *ScaleDownStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleDownStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingActivity) DeepCopyInto(out *ScalingActivity) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingActivity.
+func (in *ScalingActivity) DeepCopy() *ScalingActivity {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingActivity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingBlocker) DeepCopyInto(out *ScalingBlocker) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingBlocker.
+func (in *ScalingBlocker) DeepCopy() *ScalingBlocker {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingBlocker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingState) DeepCopyInto(out *ScalingState) {
+	*out = *in
+	if in.LatestScalingActivity != nil {
+		in, out := &in.LatestScalingActivity, &out.LatestScalingActivity
+		*out = new(ScalingActivity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleUpPendingSince != nil {
+		in, out := &in.ScaleUpPendingSince, &out.ScaleUpPendingSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingState.
+func (in *ScalingState) DeepCopy() *ScalingState {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledAction) DeepCopyInto(out *ScheduledAction) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DesiredCapacity != nil {
+		in, out := &in.DesiredCapacity, &out.DesiredCapacity
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledAction.
+func (in *ScheduledAction) DeepCopy() *ScheduledAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpotInterruptionHandling) DeepCopyInto(out *SpotInterruptionHandling) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotInterruptionHandling.
+func (in *SpotInterruptionHandling) DeepCopy() *SpotInterruptionHandling {
+	if in == nil {
+		return nil
+	}
+	out := new(SpotInterruptionHandling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SuspendProcessesTypes) DeepCopyInto(out *SuspendProcessesTypes) {
 	*out = *in
@@ -1281,3 +2116,23 @@ func (in *UpdateConfig) DeepCopy() *UpdateConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VCPUCountRequest) DeepCopyInto(out *VCPUCountRequest) {
+	*out = *in
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VCPUCountRequest.
+func (in *VCPUCountRequest) DeepCopy() *VCPUCountRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(VCPUCountRequest)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
@@ -151,6 +152,14 @@ func (r *AWSFargateProfileReconciler) reconcileNormal(
 		}
 	}
 
+	// EKS Auto Mode manages the compute of the cluster, so the Fargate profile isn't created.
+	if fargateProfileScope.ControlPlane.Spec.AutoMode.IsEnabled() {
+		r.Recorder.Eventf(fargateProfileScope.FargateProfile, corev1.EventTypeWarning, "EKSAutoModeEnabled", "Fargate profiles can't be used with EKS Auto Mode")
+		conditions.MarkFalse(fargateProfileScope.FargateProfile, expinfrav1.EKSFargateProfileReadyCondition, expinfrav1.EKSAutoModeEnabledReason, clusterv1.ConditionSeverityError,
+			"Fargate profiles can't be used with the EKS Auto Mode of control plane %s", fargateProfileScope.ControlPlane.Name)
+		return ctrl.Result{}, nil
+	}
+
 	ekssvc := eks.NewFargateService(fargateProfileScope)
 
 	res, err := ekssvc.Reconcile()
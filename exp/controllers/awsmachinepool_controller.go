@@ -19,8 +19,16 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
@@ -28,25 +36,39 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/controllers"
 	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
 	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/backoff"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/metrics"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services"
 	asg "sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/autoscaling"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/ec2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/elb"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/spotinterruption"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/logger"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/util/system"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	expclusterv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
@@ -55,6 +77,35 @@ import (
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
 
+// lifecycleHookDriftCheckInterval is how often the lifecycle hooks of an Auto Scaling group are
+// checked for drift when their spec did not change.
+const lifecycleHookDriftCheckInterval = 10 * time.Minute
+
+// lifecycleHookInUseRequeueAfter is how long to wait before retrying to delete lifecycle hooks that
+// instances still wait on.
+const lifecycleHookInUseRequeueAfter = time.Minute
+
+// classicLoadBalancersRequeueAfter is how long to wait before retrying to attach or detach Classic Load Balancers.
+const classicLoadBalancersRequeueAfter = time.Minute
+
+// instanceRefreshPollInterval is how often the progress of a running instance refresh is checked.
+const instanceRefreshPollInterval = 30 * time.Second
+
+// userDataRegenerationRequeueAfter is how soon a machine pool is reconciled again to create a new version of its
+// launch template after the bootstrap token of its user data expired.
+const userDataRegenerationRequeueAfter = 30 * time.Second
+
+// managedLaunchLifecycleHookRequeueAfter is how often the Nodes of the instances held by the managed launch
+// lifecycle hook are checked for readiness.
+const managedLaunchLifecycleHookRequeueAfter = 30 * time.Second
+
+// asgDeletionRequeueAfter is how often the progress of the deletion of an ASG is checked.
+const asgDeletionRequeueAfter = 30 * time.Second
+
+// DefaultASGDeletionTimeout is the default of how long each stage of the deletion of an ASG may take before
+// the next one is attempted.
+const DefaultASGDeletionTimeout = 10 * time.Minute
+
 // AWSMachinePoolReconciler reconciles a AWSMachinePool object.
 type AWSMachinePoolReconciler struct {
 	client.Client
@@ -63,7 +114,24 @@ type AWSMachinePoolReconciler struct {
 	asgServiceFactory            func(cloud.ClusterScoper) services.ASGInterface
 	ec2ServiceFactory            func(scope.EC2Scope) services.EC2Interface
 	reconcileServiceFactory      func(scope.EC2Scope) services.MachinePoolReconcileInterface
+	elbServiceFactory            func(scope.ELBScope) services.ELBInterface
+	spotInterruptionFactory      func(scope.EC2Scope) services.SpotInterruptionInterface
 	TagUnmanagedNetworkResources bool
+	// SkipLifecycleHookPermissionCheck disables the verification of the lifecycle hook permissions
+	// of the controller, for roles that are not allowed to simulate their own policies.
+	SkipLifecycleHookPermissionCheck bool
+	// ReplaceMachinesWithExpiredBootstrapToken enables creating a new version of the launch template of a pool with
+	// the current bootstrap data once the bootstrap token of its user data expired, before the next scale-up.
+	ReplaceMachinesWithExpiredBootstrapToken bool
+	// BootstrapTokenTTL is the TTL of the kubeadm bootstrap tokens, used to estimate when the tokens which were already
+	// deleted from the workload cluster expired.
+	BootstrapTokenTTL time.Duration
+	// ASGDeletionTimeout is how long each stage of the deletion of an ASG may take before the next one is
+	// attempted, for the AWSMachinePools which don't set it.
+	ASGDeletionTimeout time.Duration
+	// Backoff, if set, delays the retries of failed reconciliations. Otherwise they are retried with the
+	// rate limiter of the controller.
+	Backoff *backoff.Tracker
 }
 
 func (r *AWSMachinePoolReconciler) getASGService(scope cloud.ClusterScoper) services.ASGInterface {
@@ -81,6 +149,22 @@ func (r *AWSMachinePoolReconciler) getEC2Service(scope scope.EC2Scope) services.
 	return ec2.NewService(scope)
 }
 
+func (r *AWSMachinePoolReconciler) getELBService(scope scope.ELBScope) services.ELBInterface {
+	if r.elbServiceFactory != nil {
+		return r.elbServiceFactory(scope)
+	}
+
+	return elb.NewService(scope)
+}
+
+func (r *AWSMachinePoolReconciler) getSpotInterruptionService(scope scope.EC2Scope) services.SpotInterruptionInterface {
+	if r.spotInterruptionFactory != nil {
+		return r.spotInterruptionFactory(scope)
+	}
+
+	return spotinterruption.NewService(scope)
+}
+
 func (r *AWSMachinePoolReconciler) getReconcileService(scope scope.EC2Scope) services.MachinePoolReconcileInterface {
 	if r.reconcileServiceFactory != nil {
 		return r.reconcileServiceFactory(scope)
@@ -92,12 +176,14 @@ func (r *AWSMachinePoolReconciler) getReconcileService(scope scope.EC2Scope) ser
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachinepools,verbs=get;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachinepools/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools;machinepools/status,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=controlplane.cluster.x-k8s.io,resources=awsmanagedcontrolplanes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 
 // Reconcile is the reconciliation loop for AWSMachinePool.
-func (r *AWSMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+func (r *AWSMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, reterr error) {
 	log := logger.FromContext(ctx)
 
 	// Fetch the AWSMachinePool .
@@ -172,37 +258,57 @@ func (r *AWSMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 	}()
 
+	// Back off before retrying a failed reconciliation. This runs before the scope is closed, so that the
+	// backoff is persisted in the conditions.
+	if r.Backoff != nil {
+		defer func() {
+			res, reterr = r.Backoff.Result(machinePoolScope, awsMachinePool, res, reterr)
+		}()
+	}
+
 	switch infraScope := infraCluster.(type) {
 	case *scope.ManagedControlPlaneScope:
 		if !awsMachinePool.ObjectMeta.DeletionTimestamp.IsZero() {
-			return ctrl.Result{}, r.reconcileDelete(machinePoolScope, infraScope, infraScope)
+			return r.reconcileDelete(machinePoolScope, infraScope, infraScope)
 		}
 
-		return ctrl.Result{}, r.reconcileNormal(ctx, machinePoolScope, infraScope, infraScope)
+		return r.reconcileNormal(ctx, machinePoolScope, infraScope, infraScope)
 	case *scope.ClusterScope:
 		if !awsMachinePool.ObjectMeta.DeletionTimestamp.IsZero() {
-			return ctrl.Result{}, r.reconcileDelete(machinePoolScope, infraScope, infraScope)
+			return r.reconcileDelete(machinePoolScope, infraScope, infraScope)
 		}
 
-		return ctrl.Result{}, r.reconcileNormal(ctx, machinePoolScope, infraScope, infraScope)
+		return r.reconcileNormal(ctx, machinePoolScope, infraScope, infraScope)
 	default:
 		return ctrl.Result{}, errors.New("infraCluster has unknown type")
 	}
 }
 
 func (r *AWSMachinePoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	log := logger.FromContext(ctx)
+	gvk := expinfrav1.GroupVersion.WithKind("AWSMachinePool")
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(options).
-		For(&expinfrav1.AWSMachinePool{}).
+		For(&expinfrav1.AWSMachinePool{}, builder.WithPredicates(backoff.ConditionUnchanged())).
 		Watches(
 			&expclusterv1.MachinePool{},
-			handler.EnqueueRequestsFromMapFunc(machinePoolToInfrastructureMapFunc(expinfrav1.GroupVersion.WithKind("AWSMachinePool"))),
+			handler.EnqueueRequestsFromMapFunc(machinePoolToInfrastructureMapFunc(gvk)),
+		).
+		Watches(
+			&infrav1.AWSCluster{},
+			handler.EnqueueRequestsFromMapFunc(awsClusterToAWSMachinePoolMapFunc(r.Client, gvk, log)),
+			builder.WithPredicates(defaultLifecycleHooksChanged()),
+		).
+		Watches(
+			&ekscontrolplanev1.AWSManagedControlPlane{},
+			handler.EnqueueRequestsFromMapFunc(managedControlPlaneToManagedMachinePoolMapFunc(r.Client, gvk, log)),
+			builder.WithPredicates(defaultLifecycleHooksChanged()),
 		).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(logger.FromContext(ctx).GetLogger(), r.WatchFilterValue)).
 		Complete(r)
 }
 
-func (r *AWSMachinePoolReconciler) reconcileNormal(ctx context.Context, machinePoolScope *scope.MachinePoolScope, clusterScope cloud.ClusterScoper, ec2Scope scope.EC2Scope) error {
+func (r *AWSMachinePoolReconciler) reconcileNormal(ctx context.Context, machinePoolScope *scope.MachinePoolScope, clusterScope cloud.ClusterScoper, ec2Scope scope.EC2Scope) (ctrl.Result, error) {
 	clusterScope.Info("Reconciling AWSMachinePool")
 
 	// If the AWSMachine is in an error state, return early.
@@ -211,28 +317,28 @@ func (r *AWSMachinePoolReconciler) reconcileNormal(ctx context.Context, machineP
 
 		// TODO: If we are in a failed state, delete the secret regardless of instance state
 
-		return nil
+		return ctrl.Result{}, nil
 	}
 
 	// If the AWSMachinepool doesn't have our finalizer, add it
 	if controllerutil.AddFinalizer(machinePoolScope.AWSMachinePool, expinfrav1.MachinePoolFinalizer) {
 		// Register finalizer immediately to avoid orphaning AWS resources
 		if err := machinePoolScope.PatchObject(); err != nil {
-			return err
+			return ctrl.Result{}, err
 		}
 	}
 
 	if !machinePoolScope.Cluster.Status.InfrastructureReady {
 		machinePoolScope.Info("Cluster infrastructure is not ready yet")
 		conditions.MarkFalse(machinePoolScope.AWSMachinePool, expinfrav1.ASGReadyCondition, infrav1.WaitingForClusterInfrastructureReason, clusterv1.ConditionSeverityInfo, "")
-		return nil
+		return ctrl.Result{}, nil
 	}
 
 	// Make sure bootstrap data is available and populated
 	if machinePoolScope.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName == nil {
 		machinePoolScope.Info("Bootstrap data secret reference is not yet available")
 		conditions.MarkFalse(machinePoolScope.AWSMachinePool, expinfrav1.ASGReadyCondition, infrav1.WaitingForBootstrapDataReason, clusterv1.ConditionSeverityInfo, "")
-		return nil
+		return ctrl.Result{}, nil
 	}
 
 	ec2Svc := r.getEC2Service(ec2Scope)
@@ -243,7 +349,14 @@ func (r *AWSMachinePoolReconciler) reconcileNormal(ctx context.Context, machineP
 	asg, err := r.findASG(machinePoolScope, asgsvc)
 	if err != nil {
 		conditions.MarkUnknown(machinePoolScope.AWSMachinePool, expinfrav1.ASGReadyCondition, expinfrav1.ASGNotFoundReason, err.Error())
-		return err
+		return ctrl.Result{}, err
+	}
+
+	if asg != nil {
+		if err := r.reconcileAdoption(machinePoolScope, asgsvc, asg, clusterScope.KubernetesClusterName()); err != nil {
+			conditions.MarkFalse(machinePoolScope.AWSMachinePool, expinfrav1.ASGReadyCondition, expinfrav1.ASGAdoptionFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			return ctrl.Result{}, err
+		}
 	}
 
 	canUpdateLaunchTemplate := func() (bool, error) {
@@ -255,7 +368,14 @@ func (r *AWSMachinePoolReconciler) reconcileNormal(ctx context.Context, machineP
 			// But we want to update the LaunchTemplate because an error in the LaunchTemplate may be blocking the ASG creation.
 			return true, nil
 		}
-		return asgsvc.CanStartASGInstanceRefresh(machinePoolScope)
+		canStart, err := asgsvc.CanStartASGInstanceRefresh(machinePoolScope)
+		if err != nil || canStart {
+			return canStart, err
+		}
+		// The instance refresh in progress replaces the instances with a version of the launch template
+		// which no longer matches the spec, so it is cancelled and the launch template is updated once
+		// the cancellation completes.
+		return false, r.cancelOutdatedInstanceRefresh(machinePoolScope, asgsvc)
 	}
 	runPostLaunchTemplateUpdateOperation := func() error {
 		// skip instance refresh if ASG is not created yet
@@ -268,6 +388,13 @@ func (r *AWSMachinePoolReconciler) reconcileNormal(ctx context.Context, machineP
 			machinePoolScope.Debug("instance refresh disabled, skipping instance refresh")
 			return nil
 		}
+		// An existing launch template may be pinned to a version, in which case the ASG has to be
+		// switched to the new version before the instance refresh starts.
+		if machinePoolScope.AWSMachinePool.Spec.AWSLaunchTemplate.ExistingTemplate != nil {
+			if err := asgsvc.UpdateASG(machinePoolScope, asg); err != nil {
+				return err
+			}
+		}
 		// After creating a new version of launch template, instance refresh is required
 		// to trigger a rolling replacement of all previously launched instances.
 		// If ONLY the userdata changed, previously launched instances continue to use the old launch
@@ -278,59 +405,144 @@ func (r *AWSMachinePoolReconciler) reconcileNormal(ctx context.Context, machineP
 		// Launch Template version, and the difference between the older and current versions is _more_
 		// than userdata, we should start an Instance Refresh.
 		machinePoolScope.Info("starting instance refresh", "number of instances", machinePoolScope.MachinePool.Spec.Replicas)
-		return asgsvc.StartASGInstanceRefresh(machinePoolScope)
+		if err := asgsvc.StartASGInstanceRefresh(machinePoolScope); err != nil {
+			return err
+		}
+		if change := machinePoolScope.AWSMachinePool.Status.LastLaunchTemplateChange; change != nil {
+			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "InstanceRefreshStarted", "Started instance refresh for %s", change.Summary())
+		} else {
+			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "InstanceRefreshStarted", "Started instance refresh")
+		}
+		if excluded := machinePoolScope.AWSMachinePool.Status.RefreshExcludedInstances; len(excluded) > 0 {
+			message := refreshExclusionWarning(excluded, len(asg.Instances))
+			machinePoolScope.Info(message, "instances", excluded)
+			r.Recorder.Event(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "InstanceRefreshExcludedInstances", message)
+		}
+		return nil
+	}
+	if err := r.recordReplacedLaunchTemplate(machinePoolScope, ec2Svc); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to look up the launch template replaced by the existing launch template")
 	}
 	if err := reconSvc.ReconcileLaunchTemplate(machinePoolScope, ec2Svc, canUpdateLaunchTemplate, runPostLaunchTemplateUpdateOperation); err != nil {
 		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedLaunchTemplateReconcile", "Failed to reconcile launch template: %v", err)
 		machinePoolScope.Error(err, "failed to reconcile launch template")
-		return err
+		r.reconcileScalingBlockers(machinePoolScope, clusterScope, asg, nil)
+		return ctrl.Result{}, err
 	}
 
 	// set the LaunchTemplateReady condition
 	conditions.MarkTrue(machinePoolScope.AWSMachinePool, expinfrav1.LaunchTemplateReadyCondition)
+	setVersionStatus(machinePoolScope)
+
+	// The replicas are handed over to the autoscaling before the Auto Scaling group is created or
+	// updated, so that its desired capacity isn't reset to the replicas of the MachinePool.
+	if machinePoolScope.AWSMachinePool.Spec.Autoscaling.IsEnabled() {
+		if err := machinePoolScope.SetAutoscalingManagesReplicas(ctx, true); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to hand over the MachinePool replicas to the autoscaling")
+		}
+	}
 
 	if asg == nil {
 		// Create new ASG
 		if err := r.createPool(machinePoolScope, clusterScope); err != nil {
 			conditions.MarkFalse(machinePoolScope.AWSMachinePool, expinfrav1.ASGReadyCondition, expinfrav1.ASGProvisionFailedReason, clusterv1.ConditionSeverityError, err.Error())
-			return err
+			return ctrl.Result{}, err
 		}
-		return nil
+		return ctrl.Result{}, nil
 	}
 
-	if annotations.ReplicasManagedByExternalAutoscaler(machinePoolScope.MachinePool) {
-		// Set MachinePool replicas to the ASG DesiredCapacity
-		if *machinePoolScope.MachinePool.Spec.Replicas != *asg.DesiredCapacity {
+	// The replicas of the MachinePool are back-filled from the Auto Scaling group, which may have been
+	// scaled by the external autoscaler since the last reconciliation.
+	if annotations.ReplicasManagedByExternalAutoscaler(machinePoolScope.MachinePool) && asg.DesiredCapacity != nil {
+		if !ptr.Equal(machinePoolScope.MachinePool.Spec.Replicas, asg.DesiredCapacity) {
 			machinePoolScope.Info("Setting MachinePool replicas to ASG DesiredCapacity",
 				"local", machinePoolScope.MachinePool.Spec.Replicas,
 				"external", asg.DesiredCapacity)
 			machinePoolScope.MachinePool.Spec.Replicas = asg.DesiredCapacity
 			if err := machinePoolScope.PatchCAPIMachinePoolObject(ctx); err != nil {
-				return err
+				return ctrl.Result{}, err
 			}
 		}
 	}
 
+	if err := r.reconcileEvacuation(machinePoolScope, asgsvc, asg); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile the evacuation of availability zones")
+	}
+
+	scaleDownResult, err := r.reconcileScaleDown(machinePoolScope, asg)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile scale-down")
+	}
+
 	if err := r.updatePool(machinePoolScope, clusterScope, asg); err != nil {
 		machinePoolScope.Error(err, "error updating AWSMachinePool")
-		return err
+		return ctrl.Result{}, err
+	}
+
+	if err := r.deleteReplacedLaunchTemplate(machinePoolScope, ec2Svc, asg); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	machinePoolScope.SetLifecycleHooksStatus()
+	machinePoolScope.AWSMachinePool.Status.AdditionalTags = machinePoolScope.AdditionalTags()
+	var lifecycleHooksResult ctrl.Result
+	if r.canManageLifecycleHooks(machinePoolScope, asgsvc, asg) {
+		lifecycleHooksResult, err = r.reconcileLifecycleHooks(machinePoolScope, asgsvc)
+		if err != nil {
+			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedLifecycleHooksReconcile", "Failed to reconcile lifecycle hooks: %v", err)
+			return ctrl.Result{}, errors.Wrap(err, "failed to reconcile lifecycle hooks")
+		}
+	}
+
+	if err := r.reconcileLoadBalancerAttachments(machinePoolScope, ec2Scope, asgsvc); err != nil {
+		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedLoadBalancerAttachmentsReconcile", "Failed to reconcile load balancer attachments: %v", err)
+		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile load balancer attachments")
+	}
+
+	if err := r.reconcileTargetGroups(machinePoolScope, ec2Scope, asgsvc); err != nil {
+		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedTargetGroupsReconcile", "Failed to reconcile target groups: %v", err)
+		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile target groups")
+	}
+
+	if err := r.reconcileScheduledActions(machinePoolScope, asgsvc); err != nil {
+		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedScheduledActionsReconcile", "Failed to reconcile scheduled actions: %v", err)
+		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile scheduled actions")
+	}
+
+	if err := r.reconcileSpotInterruptionHandling(machinePoolScope, ec2Scope); err != nil {
+		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedSpotInterruptionHandlingReconcile", "Failed to reconcile spot interruption handling: %v", err)
+		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile spot interruption handling")
+	}
+
+	// Classic Load Balancers that can't be attached don't hold back the reconciliation of the Auto Scaling group.
+	classicLoadBalancersResult := r.reconcileClassicLoadBalancers(machinePoolScope, asgsvc, asg)
+
+	// The tags of the Auto Scaling group are reconciled with its live tags, before the last applied tags
+	// annotation, which tells the stale tags CAPA applied apart from the ones set by others, is updated.
+	if err := asgsvc.ReconcileASGTags(machinePoolScope, asg); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "error updating tags of the Auto Scaling group")
 	}
 
 	launchTemplateID := machinePoolScope.GetLaunchTemplateIDStatus()
-	asgName := machinePoolScope.Name()
-	resourceServiceToUpdate := []scope.ResourceServiceToUpdate{
-		{
+	var resourceServiceToUpdate []scope.ResourceServiceToUpdate
+	// The tags of a launch template that is managed outside of CAPA are left alone.
+	if machinePoolScope.AWSMachinePool.Spec.AWSLaunchTemplate.ExistingTemplate == nil {
+		resourceServiceToUpdate = append(resourceServiceToUpdate, scope.ResourceServiceToUpdate{
 			ResourceID:      &launchTemplateID,
 			ResourceService: ec2Svc,
-		},
-		{
-			ResourceID:      &asgName,
-			ResourceService: asgsvc,
-		},
+		})
 	}
 	err = reconSvc.ReconcileTags(machinePoolScope, resourceServiceToUpdate)
 	if err != nil {
-		return errors.Wrap(err, "error updating tags")
+		return ctrl.Result{}, errors.Wrap(err, "error updating tags")
+	}
+
+	if err := r.reconcileAutoscaling(ctx, machinePoolScope, asgsvc, asg, clusterScope.KubernetesClusterName()); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile autoscaling")
+	}
+
+	if err := r.reconcileDetachedInstances(ctx, machinePoolScope, asgsvc, ec2Svc, asg); err != nil {
+		machinePoolScope.Error(err, "failed to detach instances")
 	}
 
 	// Make sure Spec.ProviderID is always set.
@@ -344,19 +556,116 @@ func (r *AWSMachinePoolReconciler) reconcileNormal(ctx context.Context, machineP
 	machinePoolScope.SetAnnotation("cluster-api-provider-aws", "true")
 
 	machinePoolScope.AWSMachinePool.Spec.ProviderIDList = providerIDList
+	// The replicas are the instances of the ASG, even when its desired capacity is in capacity units.
 	machinePoolScope.AWSMachinePool.Status.Replicas = int32(len(providerIDList))
-	machinePoolScope.AWSMachinePool.Status.Ready = true
-	conditions.MarkTrue(machinePoolScope.AWSMachinePool, expinfrav1.ASGReadyCondition)
+	machinePoolScope.UpdateInstanceStatuses(asg)
+	machinePoolScope.AWSMachinePool.Status.OutdatedReplicas = outdatedReplicas(asg, machinePoolScope.GetLaunchTemplateIDStatus(), machinePoolScope.GetLaunchTemplateLatestVersionStatus())
+	machinePoolScope.AWSMachinePool.Status.ASGName = asg.Name
+	machinePoolScope.AWSMachinePool.Status.WeightedCapacity = asg.MixedInstancesPolicy.UsesWeightedCapacity()
+	machinePoolScope.AWSMachinePool.Status.DesiredCapacity = asg.DesiredCapacity
+	instancesReadyResult := r.reconcileInstancesReady(machinePoolScope, asg)
+
+	activities, err := r.reconcileScalingState(machinePoolScope, asgsvc, asg)
+	if err != nil {
+		machinePoolScope.Error(err, "failed updating scaling state")
+	} else {
+		r.reconcileScalingActivities(machinePoolScope, activities)
+	}
+
+	if err := r.reconcilePendingLifecycleActions(machinePoolScope, asgsvc, asg); err != nil {
+		machinePoolScope.Error(err, "failed updating pending lifecycle actions")
+	}
 
-	err = machinePoolScope.UpdateInstanceStatuses(ctx, asg.Instances)
+	instanceRefreshResult, err := r.reconcileInstanceRefreshStatus(machinePoolScope, asgsvc)
 	if err != nil {
-		machinePoolScope.Error(err, "failed updating instances", "instances", asg.Instances)
+		machinePoolScope.Error(err, "failed updating instance refresh status")
 	}
 
-	return nil
+	r.reconcileScalingBlockers(machinePoolScope, clusterScope, asg, activities)
+
+	bootstrapTokenResult := r.reconcileBootstrapToken(ctx, machinePoolScope, asg, time.Now())
+
+	// The Nodes are reconciled last, so that an unreachable workload cluster doesn't hold back the
+	// reconciliation of the AWS resources.
+	result, err := r.reconcileWorkloadCluster(ctx, machinePoolScope, asgsvc, asg, activities)
+	result = util.LowestNonZeroResult(result, instanceRefreshResult)
+	result = util.LowestNonZeroResult(result, classicLoadBalancersResult)
+	result = util.LowestNonZeroResult(result, bootstrapTokenResult)
+	result = util.LowestNonZeroResult(result, instancesReadyResult)
+	return util.LowestNonZeroResult(util.LowestNonZeroResult(result, scaleDownResult), lifecycleHooksResult), err
+}
+
+// outdatedReplicas returns the number of instances of the Auto Scaling group which weren't launched from the
+// given version of the launch template. Instances whose launch template isn't known aren't counted.
+func outdatedReplicas(asg *expinfrav1.AutoScalingGroup, launchTemplateID, launchTemplateVersion string) int32 {
+	if launchTemplateID == "" || launchTemplateVersion == "" {
+		return 0
+	}
+	var outdated int32
+	for _, instance := range asg.Instances {
+		launchTemplate, ok := asg.InstanceLaunchTemplates[instance.ID]
+		if !ok {
+			continue
+		}
+		if launchTemplate.ID != launchTemplateID || launchTemplate.Version != launchTemplateVersion {
+			outdated++
+		}
+	}
+	return outdated
+}
+
+// reconcileBootstrapToken sets the BootstrapTokenLikelyExpiredCondition of a machine pool once the bootstrap token
+// embedded in its bootstrap data expired, as the instances launched from its launch template can't join the cluster
+// anymore. When ReplaceMachinesWithExpiredBootstrapToken is set, the hash of the user data of the launch template is
+// invalidated, so that a new version of the launch template is created with the current bootstrap data before the
+// next scale-up. It returns when to check the token again.
+func (r *AWSMachinePoolReconciler) reconcileBootstrapToken(ctx context.Context, machinePoolScope *scope.MachinePoolScope, asg *expinfrav1.AutoScalingGroup, now time.Time) ctrl.Result {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	if awsMachinePool.Spec.AWSLaunchTemplate.ExistingTemplate != nil {
+		// The bootstrap data isn't used by a launch template managed outside of CAPA.
+		return ctrl.Result{}
+	}
+	expiresAt, err := machinePoolScope.GetBootstrapTokenExpiry(ctx, r.BootstrapTokenTTL)
+	if err != nil {
+		// The expiry is only informative, so the reconciliation isn't held back when it can't be read.
+		machinePoolScope.Error(err, "failed to get the expiry of the bootstrap token")
+		return ctrl.Result{}
+	}
+	if expiresAt == nil {
+		conditions.Delete(awsMachinePool, infrav1.BootstrapTokenLikelyExpiredCondition)
+		return ctrl.Result{}
+	}
+
+	if validFor := expiresAt.Sub(now); validFor > 0 {
+		conditions.MarkFalse(awsMachinePool, infrav1.BootstrapTokenLikelyExpiredCondition, infrav1.BootstrapTokenValidReason, clusterv1.ConditionSeverityNone, "")
+		return ctrl.Result{RequeueAfter: validFor}
+	}
+
+	// The instances of the pool which aren't nodes were likely launched with the expired token.
+	withoutNode := max(len(asg.Instances)-len(machinePoolScope.MachinePool.Status.NodeRefs), 0)
+	if !conditions.IsTrue(awsMachinePool, infrav1.BootstrapTokenLikelyExpiredCondition) {
+		machinePoolScope.Info("Bootstrap token of the launch template expired", "expired-at", expiresAt, "instances-without-node", withoutNode)
+		r.Recorder.Eventf(awsMachinePool, corev1.EventTypeWarning, "BootstrapTokenLikelyExpired", "Bootstrap token expired at %s, instances launched from now on can't join the cluster", expiresAt.Format(time.RFC3339))
+	}
+	conditions.Set(awsMachinePool, &clusterv1.Condition{
+		Type:    infrav1.BootstrapTokenLikelyExpiredCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.BootstrapTokenExpiredReason,
+		Message: fmt.Sprintf("bootstrap token expired at %s, %d instances haven't joined the cluster", expiresAt.Format(time.RFC3339), withoutNode),
+	})
+
+	if !r.ReplaceMachinesWithExpiredBootstrapToken {
+		return ctrl.Result{}
+	}
+	if machinePoolScope.RequestUserDataRegeneration(*expiresAt) {
+		machinePoolScope.Info("Requested a new version of the launch template with the current bootstrap data")
+		r.Recorder.Eventf(awsMachinePool, corev1.EventTypeNormal, "UserDataRegenerationRequested", "Requested a new version of the launch template with the current bootstrap data, as the bootstrap token expired at %s", expiresAt.Format(time.RFC3339))
+		return ctrl.Result{RequeueAfter: userDataRegenerationRequeueAfter}
+	}
+	return ctrl.Result{}
 }
 
-func (r *AWSMachinePoolReconciler) reconcileDelete(machinePoolScope *scope.MachinePoolScope, clusterScope cloud.ClusterScoper, ec2Scope scope.EC2Scope) error {
+func (r *AWSMachinePoolReconciler) reconcileDelete(machinePoolScope *scope.MachinePoolScope, clusterScope cloud.ClusterScoper, ec2Scope scope.EC2Scope) (ctrl.Result, error) {
 	clusterScope.Info("Handling deleted AWSMachinePool")
 
 	ec2Svc := r.getEC2Service(ec2Scope)
@@ -364,13 +673,34 @@ func (r *AWSMachinePoolReconciler) reconcileDelete(machinePoolScope *scope.Machi
 
 	asg, err := r.findASG(machinePoolScope, asgSvc)
 	if err != nil {
-		return err
+		return ctrl.Result{}, err
 	}
 
-	if asg == nil {
+	clusterName := clusterScope.KubernetesClusterName()
+	switch {
+	case asg == nil && machinePoolScope.AWSMachinePool.Status.Deletion != nil:
+		machinePoolScope.Info("ASG deleted")
+	case asg == nil:
 		machinePoolScope.Warn("Unable to locate ASG")
 		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, expinfrav1.ASGNotFoundReason, "Unable to find matching ASG")
-	} else {
+	case machinePoolScope.AWSMachinePool.Spec.AutoScalingGroupName != "" && !asg.Tags.HasOwned(clusterName):
+		// The group was never adopted, so it isn't ours to delete.
+		machinePoolScope.Info("ASG isn't owned by the cluster, skipping deletion", "name", asg.Name)
+	case asgOwnerMachinePool(asg.Tags, machinePoolScope.NamespacedName()) != "":
+		// The group is managed by another AWSMachinePool of the cluster, which deletes it.
+		machinePoolScope.Info("ASG is managed by another AWSMachinePool, skipping deletion", "name", asg.Name,
+			"owner", asgOwnerMachinePool(asg.Tags, machinePoolScope.NamespacedName()))
+	case machinePoolScope.AWSMachinePool.Spec.KeepOnDelete:
+		// The ownership tags are removed, so that the group can be adopted again. The launch template is
+		// kept as well, as the group still launches instances from it.
+		machinePoolScope.Info("Keeping ASG and launch template", "name", asg.Name)
+		if err := asgSvc.UpdateResourceTags(ptr.To(asg.Name), nil, ownershipTags(asg.Tags, clusterName)); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to remove the ownership tags of the ASG")
+		}
+		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "KeptASG", "Kept ASG %q and its launch template", asg.Name)
+		controllerutil.RemoveFinalizer(machinePoolScope.AWSMachinePool, expinfrav1.MachinePoolFinalizer)
+		return ctrl.Result{}, nil
+	default:
 		machinePoolScope.SetASGStatus(asg.Status)
 		switch asg.Status {
 		case expinfrav1.ASGStatusDeleteInProgress:
@@ -379,32 +709,62 @@ func (r *AWSMachinePoolReconciler) reconcileDelete(machinePoolScope *scope.Machi
 			conditions.MarkFalse(machinePoolScope.AWSMachinePool, expinfrav1.ASGReadyCondition, expinfrav1.ASGDeletionInProgress, clusterv1.ConditionSeverityWarning, "")
 			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "DeletionInProgress", "ASG deletion in progress: %q", asg.Name)
 			machinePoolScope.Info("ASG is already deleting", "name", asg.Name)
+			return ctrl.Result{RequeueAfter: asgDeletionRequeueAfter}, nil
 		default:
+			if err := r.detachTargetGroups(machinePoolScope, asgSvc, asg.Name); err != nil {
+				r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to detach ASG %q from target groups: %v", asg.Name, err)
+				return ctrl.Result{}, errors.Wrap(err, "failed to detach ASG from target groups")
+			}
+			if err := r.detachClassicLoadBalancers(machinePoolScope, asgSvc, asg); err != nil {
+				r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to detach ASG %q from classic load balancers: %v", asg.Name, err)
+				return ctrl.Result{}, errors.Wrap(err, "failed to detach ASG from classic load balancers")
+			}
 			machinePoolScope.Info("Deleting ASG", "id", asg.Name, "status", asg.Status)
-			if err := asgSvc.DeleteASGAndWait(asg.Name); err != nil {
-				r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to delete ASG %q: %v", asg.Name, err)
-				return errors.Wrap(err, "failed to delete ASG")
+			if err := r.reconcileASGDeletion(machinePoolScope, asgSvc, asg); err != nil {
+				return ctrl.Result{}, err
 			}
+			// The launch template is deleted once the group is gone, as the group may still launch
+			// instances from it until then.
+			return ctrl.Result{RequeueAfter: asgDeletionRequeueAfter}, nil
+		}
+	}
+
+	// The launch template is cleaned up whether or not the ASG was ever created, so that the launch template
+	// of a pool deleted mid-creation doesn't leak.
+	if replacedID := machinePoolScope.AWSMachinePool.Status.ReplacedLaunchTemplateID; replacedID != "" {
+		machinePoolScope.Info("deleting replaced launch template", "id", replacedID)
+		if err := ec2Svc.DeleteLaunchTemplate(replacedID); err != nil {
+			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to delete launch template %q: %v", replacedID, err)
+			return ctrl.Result{}, errors.Wrap(err, "failed to delete replaced launch template")
 		}
+		machinePoolScope.AWSMachinePool.Status.ReplacedLaunchTemplateID = ""
+	}
+
+	if existingTemplate := machinePoolScope.AWSMachinePool.Spec.AWSLaunchTemplate.ExistingTemplate; existingTemplate != nil {
+		machinePoolScope.Info("launch template is not managed by CAPA, skipping deletion", "id", existingTemplate.ID)
+		controllerutil.RemoveFinalizer(machinePoolScope.AWSMachinePool, expinfrav1.MachinePoolFinalizer)
+		return ctrl.Result{}, nil
 	}
 
-	launchTemplateID := machinePoolScope.AWSMachinePool.Status.LaunchTemplateID
-	launchTemplate, _, _, err := ec2Svc.GetLaunchTemplate(machinePoolScope.LaunchTemplateName())
+	// The launch template is looked up by name rather than by the ID in the status, which isn't set when the
+	// status couldn't be patched after the launch template was created.
+	launchTemplateName := machinePoolScope.LaunchTemplateName()
+	launchTemplateID, err := ec2Svc.GetLaunchTemplateID(launchTemplateName)
 	if err != nil {
-		return err
+		return ctrl.Result{}, err
 	}
 
-	if launchTemplate == nil {
+	if launchTemplateID == "" {
 		machinePoolScope.Debug("Unable to locate launch template")
 		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, expinfrav1.ASGNotFoundReason, "Unable to find matching ASG")
 		controllerutil.RemoveFinalizer(machinePoolScope.AWSMachinePool, expinfrav1.MachinePoolFinalizer)
-		return nil
+		return ctrl.Result{}, nil
 	}
 
-	machinePoolScope.Info("deleting launch template", "name", launchTemplate.Name)
+	machinePoolScope.Info("deleting launch template", "name", launchTemplateName, "id", launchTemplateID)
 	if err := ec2Svc.DeleteLaunchTemplate(launchTemplateID); err != nil {
-		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to delete launch template %q: %v", launchTemplate.Name, err)
-		return errors.Wrap(err, "failed to delete ASG")
+		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to delete launch template %q: %v", launchTemplateName, err)
+		return ctrl.Result{}, errors.Wrap(err, "failed to delete launch template")
 	}
 
 	machinePoolScope.Info("successfully deleted AutoScalingGroup and Launch Template")
@@ -412,9 +772,103 @@ func (r *AWSMachinePoolReconciler) reconcileDelete(machinePoolScope *scope.Machi
 	// remove finalizer
 	controllerutil.RemoveFinalizer(machinePoolScope.AWSMachinePool, expinfrav1.MachinePoolFinalizer)
 
+	return ctrl.Result{}, nil
+}
+
+// reconcileASGDeletion advances the deletion of the ASG by one stage, recording the stage in the status and
+// emitting an event whenever it changes. The ASG is scaled to zero, deleted, and deleted with ForceDelete
+// when it still can't be deleted after the deletion timeout.
+func (r *AWSMachinePoolReconciler) reconcileASGDeletion(machinePoolScope *scope.MachinePoolScope, asgSvc services.ASGInterface, asg *expinfrav1.AutoScalingGroup) error {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	timeout := r.ASGDeletionTimeout
+	if timeout == 0 {
+		timeout = DefaultASGDeletionTimeout
+	}
+	if awsMachinePool.Spec.DeletionTimeout != nil {
+		timeout = awsMachinePool.Spec.DeletionTimeout.Duration
+	}
+
+	previous := awsMachinePool.Status.Deletion
+	if isForceDeletingASG(previous, timeout) {
+		r.deleteLifecycleHooks(machinePoolScope, asgSvc, asg.Name)
+	}
+	status, deleted, err := asgSvc.ReconcileASGDeletion(asg, previous, timeout)
+	if status != nil {
+		if previous == nil || previous.Phase != status.Phase {
+			switch status.Phase {
+			case expinfrav1.ASGDeletionPhaseScalingToZero:
+				r.Recorder.Eventf(awsMachinePool, corev1.EventTypeNormal, "ScalingToZero", "Scaling ASG %q to zero before deleting it", asg.Name)
+			case expinfrav1.ASGDeletionPhaseDeleting:
+				r.Recorder.Eventf(awsMachinePool, corev1.EventTypeNormal, "DeletingASG", "Deleting ASG %q", asg.Name)
+			case expinfrav1.ASGDeletionPhaseForceDeleting:
+				r.Recorder.Eventf(awsMachinePool, corev1.EventTypeWarning, "ForceDeletingASG", "ASG %q couldn't be deleted within %s, deleting it with ForceDelete", asg.Name, timeout)
+			}
+		}
+		awsMachinePool.Status.Deletion = status
+	}
+	if err != nil {
+		r.Recorder.Eventf(awsMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to delete ASG %q: %v", asg.Name, err)
+		return errors.Wrap(err, "failed to delete ASG")
+	}
+
+	machinePoolScope.SetNotReady()
+	conditions.MarkFalse(awsMachinePool, expinfrav1.ASGReadyCondition, expinfrav1.ASGDeletionInProgress, clusterv1.ConditionSeverityInfo, "ASG deletion is in phase %s", status.Phase)
+	if deleted {
+		r.Recorder.Eventf(awsMachinePool, corev1.EventTypeNormal, "DeletedASG", "Requested the deletion of ASG %q", asg.Name)
+	}
 	return nil
 }
 
+// isForceDeletingASG returns true if ReconcileASGDeletion deletes the ASG with ForceDelete given the deletion
+// status of the previous reconciliation: the deletion is either forced already, or times out in the Deleting phase.
+func isForceDeletingASG(status *expinfrav1.ASGDeletionStatus, timeout time.Duration) bool {
+	switch {
+	case status == nil:
+		return false
+	case status.Phase == expinfrav1.ASGDeletionPhaseForceDeleting:
+		return true
+	case status.Phase == expinfrav1.ASGDeletionPhaseDeleting:
+		return status.PhaseStartTime == nil || time.Since(status.PhaseStartTime.Time) >= timeout
+	}
+	return false
+}
+
+// deleteLifecycleHooks deletes the lifecycle hooks CAPA manages for the AWSMachinePool before the ASG is deleted
+// with ForceDelete, abandoning their pending lifecycle actions. The hooks are deleted by descending priority, the
+// reverse of the order they are created in. The hooks added outside of CAPA are left to ForceDelete. It is best
+// effort: ForceDelete deletes the outstanding lifecycle actions of the ASG as well, so failures are only logged.
+func (r *AWSMachinePoolReconciler) deleteLifecycleHooks(machinePoolScope *scope.MachinePoolScope, asgSvc services.ASGInterface, asgName string) {
+	log := machinePoolScope.WithValues("asgName", asgName)
+	managed := append([]infrav1.AWSLifecycleHook(nil), machinePoolScope.GetLifecycleHooks()...)
+	if len(managed) == 0 {
+		return
+	}
+	// The priority is not stored in AWS, so it is read from the spec rather than from the described hooks.
+	sort.SliceStable(managed, func(i, j int) bool {
+		return managed[i].Priority > managed[j].Priority
+	})
+
+	hooks, err := asgSvc.DescribeLifecycleHooks(asgName)
+	if err != nil {
+		log.Info("Failed to describe lifecycle hooks, deleting ASG with them", "error", err.Error())
+		return
+	}
+	existing := make(map[string]*infrav1.AWSLifecycleHook, len(hooks))
+	for _, hook := range hooks {
+		existing[hook.Name] = hook
+	}
+	for _, managedHook := range managed {
+		hook, ok := existing[managedHook.Name]
+		if !ok {
+			continue
+		}
+		log.Info("Deleting lifecycle hook", "lifecycleHookName", hook.Name, "lifecycleTransition", hook.LifecycleTransition)
+		if err := asgSvc.DeleteLifecycleHook(asgName, hook, true); err != nil {
+			log.Info("Failed to delete lifecycle hook, deleting ASG with it", "lifecycleHookName", hook.Name, "error", err.Error())
+		}
+	}
+}
+
 func (r *AWSMachinePoolReconciler) updatePool(machinePoolScope *scope.MachinePoolScope, clusterScope cloud.ClusterScoper, existingASG *expinfrav1.AutoScalingGroup) error {
 	asgSvc := r.getASGService(clusterScope)
 
@@ -422,11 +876,14 @@ func (r *AWSMachinePoolReconciler) updatePool(machinePoolScope *scope.MachinePoo
 	if err != nil {
 		return errors.Wrapf(err, "fail to get subnets for ASG")
 	}
+	// The subnets of the evacuated availability zones are left out of the ASG, but its instances still belong to them.
+	specSubnetIDs := subnetIDs
+	subnetIDs = machinePoolScope.AWSMachinePool.WithoutEvacuatedSubnets(subnetIDs)
 	machinePoolScope.Debug("determining if subnets change in machinePoolScope",
 		"subnets of machinePoolScope", subnetIDs,
 		"subnets of existing asg", existingASG.Subnets)
 	less := func(a, b string) bool { return a < b }
-	subnetDiff := cmp.Diff(subnetIDs, existingASG.Subnets, cmpopts.SortSlices(less))
+	subnetDiff := cmp.Diff(subnetIDs, existingASG.Subnets, cmpopts.SortSlices(less), cmpopts.EquateEmpty())
 	if subnetDiff != "" {
 		machinePoolScope.Debug("asg subnet diff detected", "diff", subnetDiff)
 	}
@@ -435,144 +892,1819 @@ func (r *AWSMachinePoolReconciler) updatePool(machinePoolScope *scope.MachinePoo
 	if asgDiff != "" {
 		machinePoolScope.Debug("asg diff detected", "asgDiff", asgDiff, "subnetDiff", subnetDiff)
 	}
-	if asgDiff != "" || subnetDiff != "" {
+	launchTemplateChanged := false
+	if existingTemplate := machinePoolScope.AWSMachinePool.Spec.AWSLaunchTemplate.ExistingTemplate; existingTemplate != nil {
+		launchTemplateChanged = existingASG.LaunchTemplateID != existingTemplate.ID || existingASG.LaunchTemplateVersion != existingTemplate.GetVersion()
+	}
+	if asgDiff != "" || subnetDiff != "" || launchTemplateChanged {
 		machinePoolScope.Info("updating AutoScalingGroup")
 
-		if err := asgSvc.UpdateASG(machinePoolScope); err != nil {
+		if err := asgSvc.UpdateASG(machinePoolScope, existingASG); err != nil {
 			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedUpdate", "Failed to update ASG: %v", err)
 			return errors.Wrap(err, "unable to update ASG")
 		}
+		if subnetDiff != "" {
+			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "SubnetsUpdated", "Updated subnets of ASG to %s", strings.Join(subnetIDs, ","))
+		}
 	}
 
-	suspendedProcessesSlice := machinePoolScope.AWSMachinePool.Spec.SuspendProcesses.ConvertSetValuesToStringSlice()
-	if !cmp.Equal(existingASG.CurrentlySuspendProcesses, suspendedProcessesSlice) {
-		clusterScope.Info("reconciling processes", "suspend-processes", suspendedProcessesSlice)
-		var (
-			toBeSuspended []string
-			toBeResumed   []string
+	r.reconcileInstancesInSubnets(machinePoolScope, specSubnetIDs, existingASG)
 
-			currentlySuspended = make(map[string]struct{})
-			desiredSuspended   = make(map[string]struct{})
-		)
+	if err := r.reconcileSuspendedProcesses(machinePoolScope, asgSvc, existingASG); err != nil {
+		return err
+	}
 
-		// Convert the items to a map, so it's easy to create an effective diff from these two slices.
-		for _, p := range existingASG.CurrentlySuspendProcesses {
-			currentlySuspended[p] = struct{}{}
-		}
+	return r.reconcileMetricsCollection(machinePoolScope, asgSvc, existingASG)
+}
 
-		for _, p := range suspendedProcessesSlice {
-			desiredSuspended[p] = struct{}{}
-		}
+// reconcileInstancesInSubnets sets the InstancesInSubnetsCondition, which recommends an instance refresh when instances
+// of the ASG run in availability zones none of its subnets is in, as after the subnets were moved to other availability
+// zones. The condition is removed when the availability zone of a subnet isn't known from the network of the cluster.
+func (r *AWSMachinePoolReconciler) reconcileInstancesInSubnets(machinePoolScope *scope.MachinePoolScope, subnetIDs []string, asg *expinfrav1.AutoScalingGroup) {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	if len(subnetIDs) == 0 {
+		conditions.Delete(awsMachinePool, expinfrav1.InstancesInSubnetsCondition)
+		return
+	}
 
-		// Anything that remains in the desired items is not currently suspended so must be suspended.
-		// Anything that remains in the currentlySuspended list must be resumed since they were not part of
-		// desiredSuspended.
-		for k := range desiredSuspended {
-			if _, ok := currentlySuspended[k]; ok {
-				delete(desiredSuspended, k)
-			}
-			delete(currentlySuspended, k)
+	zones := sets.New[string]()
+	for _, id := range subnetIDs {
+		subnet := machinePoolScope.InfraCluster.Subnets().FindByID(id)
+		if subnet == nil || subnet.AvailabilityZone == "" {
+			conditions.Delete(awsMachinePool, expinfrav1.InstancesInSubnetsCondition)
+			return
 		}
+		zones.Insert(subnet.AvailabilityZone)
+	}
 
-		// Convert them back into lists to pass them to resume/suspend.
-		for k := range desiredSuspended {
-			toBeSuspended = append(toBeSuspended, k)
+	outside := 0
+	for _, instance := range asg.Instances {
+		if instance.AvailabilityZone != "" && !zones.Has(instance.AvailabilityZone) {
+			outside++
 		}
+	}
+	if outside == 0 {
+		conditions.MarkTrue(awsMachinePool, expinfrav1.InstancesInSubnetsCondition)
+		return
+	}
 
-		for k := range currentlySuspended {
-			toBeResumed = append(toBeResumed, k)
-		}
+	if !conditions.IsFalse(awsMachinePool, expinfrav1.InstancesInSubnetsCondition) {
+		r.Recorder.Eventf(awsMachinePool, corev1.EventTypeWarning, expinfrav1.InstancesOutsideSubnetsReason,
+			"%d instances run outside of the availability zones of the subnets, start an instance refresh to replace them", outside)
+	}
+	conditions.MarkFalse(awsMachinePool, expinfrav1.InstancesInSubnetsCondition, expinfrav1.InstancesOutsideSubnetsReason, clusterv1.ConditionSeverityWarning,
+		"%d instances run outside of availability zones %s, start an instance refresh to replace them", outside, strings.Join(sets.List(zones), ", "))
+}
 
-		if len(toBeSuspended) > 0 {
-			clusterScope.Info("suspending processes", "processes", toBeSuspended)
-			if err := asgSvc.SuspendProcesses(existingASG.Name, toBeSuspended); err != nil {
-				return errors.Wrapf(err, "failed to suspend processes while trying update pool")
-			}
+// reconcileSuspendedProcesses suspends the processes of spec.suspendProcesses which aren't suspended yet, and
+// resumes the suspended processes which are explicitly disabled in the spec or which were suspended by CAPA and
+// have since been removed from the spec. Processes suspended outside of CAPA which the spec doesn't mention are
+// left alone.
+func (r *AWSMachinePoolReconciler) reconcileSuspendedProcesses(machinePoolScope *scope.MachinePoolScope, asgSvc services.ASGInterface, existingASG *expinfrav1.AutoScalingGroup) error {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+
+	desired := sets.New(awsMachinePool.Spec.SuspendProcesses.ConvertSetValuesToStringSlice()...)
+	current := sets.New(existingASG.CurrentlySuspendProcesses...)
+	suspendedByCAPA := sets.New(awsMachinePool.Status.SuspendedProcesses...)
+	managed := suspendedByCAPA.Union(sets.New(awsMachinePool.Spec.SuspendProcesses.ConvertResumedValuesToStringSlice()...))
+
+	toBeSuspended := sets.List(desired.Difference(current))
+	toBeResumed := sets.List(current.Intersection(managed).Difference(desired))
+
+	if len(toBeSuspended) > 0 {
+		machinePoolScope.Info("suspending processes", "processes", toBeSuspended)
+		if err := asgSvc.SuspendProcesses(existingASG.Name, toBeSuspended); err != nil {
+			return errors.Wrapf(err, "failed to suspend processes while trying update pool")
 		}
-		if len(toBeResumed) > 0 {
-			clusterScope.Info("resuming processes", "processes", toBeResumed)
-			if err := asgSvc.ResumeProcesses(existingASG.Name, toBeResumed); err != nil {
-				return errors.Wrapf(err, "failed to resume processes while trying update pool")
-			}
+	}
+	// Processes already suspended when they're added to the spec are considered suspended by CAPA too, so that
+	// they're resumed once they're removed from it.
+	awsMachinePool.Status.SuspendedProcesses = sets.List(suspendedByCAPA.Union(desired))
+
+	if len(toBeResumed) > 0 {
+		machinePoolScope.Info("resuming processes", "processes", toBeResumed)
+		if err := asgSvc.ResumeProcesses(existingASG.Name, toBeResumed); err != nil {
+			return errors.Wrapf(err, "failed to resume processes while trying update pool")
 		}
 	}
+	awsMachinePool.Status.SuspendedProcesses = sets.List(desired)
+
 	return nil
 }
 
-func (r *AWSMachinePoolReconciler) createPool(machinePoolScope *scope.MachinePoolScope, clusterScope cloud.ClusterScoper) error {
-	clusterScope.Info("Initializing ASG client")
+// reconcileMetricsCollection enables the group metrics of spec.metricsCollection which aren't enabled yet, and
+// disables the enabled metrics which were enabled by CAPA and have since been removed from the spec, or all of
+// them once spec.metricsCollection is removed. Metrics enabled outside of CAPA which the spec doesn't mention
+// are left alone.
+func (r *AWSMachinePoolReconciler) reconcileMetricsCollection(machinePoolScope *scope.MachinePoolScope, asgSvc services.ASGInterface, existingASG *expinfrav1.AutoScalingGroup) error {
+	awsMachinePool := machinePoolScope.AWSMachinePool
 
-	asgsvc := r.getASGService(clusterScope)
+	desired := sets.New[string]()
+	if metricsCollection := awsMachinePool.Spec.MetricsCollection; metricsCollection != nil {
+		desired.Insert(metricsCollection.GetMetrics()...)
+	}
+	current := sets.New(existingASG.EnabledMetrics...)
+	enabledByCAPA := sets.New(awsMachinePool.Status.EnabledMetrics...)
 
-	machinePoolScope.Info("Creating Autoscaling Group")
-	if _, err := asgsvc.CreateASG(machinePoolScope); err != nil {
-		return errors.Wrapf(err, "failed to create AWSMachinePool")
+	toBeEnabled := sets.List(desired.Difference(current))
+	toBeDisabled := sets.List(current.Intersection(enabledByCAPA).Difference(desired))
+
+	if len(toBeEnabled) > 0 {
+		machinePoolScope.Info("enabling metrics collection", "metrics", toBeEnabled)
+		if err := asgSvc.EnableMetricsCollection(existingASG.Name, awsMachinePool.Spec.MetricsCollection.GetGranularity(), toBeEnabled); err != nil {
+			return errors.Wrapf(err, "failed to enable metrics collection while trying update pool")
+		}
+	}
+	// Metrics already enabled when they're added to the spec are considered enabled by CAPA too, so that
+	// they're disabled once they're removed from it.
+	awsMachinePool.Status.EnabledMetrics = sets.List(enabledByCAPA.Union(desired))
+
+	if len(toBeDisabled) > 0 {
+		machinePoolScope.Info("disabling metrics collection", "metrics", toBeDisabled)
+		if err := asgSvc.DisableMetricsCollection(existingASG.Name, toBeDisabled); err != nil {
+			return errors.Wrapf(err, "failed to disable metrics collection while trying update pool")
+		}
 	}
+	awsMachinePool.Status.EnabledMetrics = sets.List(desired)
 
 	return nil
 }
 
-func (r *AWSMachinePoolReconciler) findASG(machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface) (*expinfrav1.AutoScalingGroup, error) {
-	// Query the instance using tags.
-	asg, err := asgsvc.GetASGByName(machinePoolScope)
+// canManageLifecycleHooks verifies that the controller is allowed to manage the lifecycle hooks of the ASG. The check is
+// cached per AWS principal and ASG for asg.LifecycleHookPermissionsCheckInterval. When it is not allowed, LifecycleHookExistsCondition
+// is set to false with the InsufficientPermissions reason and the lifecycle hooks are not reconciled until the
+// permissions are granted, instead of failing with AccessDenied on every reconcile.
+func (r *AWSMachinePoolReconciler) canManageLifecycleHooks(machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface, group *expinfrav1.AutoScalingGroup) bool {
+	if r.SkipLifecycleHookPermissionCheck || len(machinePoolScope.GetLifecycleHooks()) == 0 {
+		return true
+	}
+
+	missing, err := asgsvc.MissingLifecycleHookPermissions(group)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to query AWSMachinePool by name")
+		// The check is best effort, let the lifecycle hook reconciliation report any actual error.
+		machinePoolScope.Error(err, "failed to verify lifecycle hook permissions")
+		return true
+	}
+	if len(missing) == 0 {
+		return true
 	}
 
-	return asg, nil
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	if conditions.GetReason(awsMachinePool, expinfrav1.LifecycleHookExistsCondition) != expinfrav1.InsufficientPermissionsReason {
+		r.Recorder.Eventf(awsMachinePool, corev1.EventTypeWarning, "InsufficientLifecycleHookPermissions",
+			"Controller is not allowed to perform %s, lifecycle hooks will not be reconciled until the permissions are granted", strings.Join(missing, ", "))
+	}
+	conditions.MarkFalse(awsMachinePool, expinfrav1.LifecycleHookExistsCondition, expinfrav1.InsufficientPermissionsReason, clusterv1.ConditionSeverityError,
+		"Controller is not allowed to perform %s. Grant the permissions, they are verified again every %s.", strings.Join(missing, ", "), asg.LifecycleHookPermissionsCheckInterval)
+	return false
 }
 
-// diffASG compares incoming AWSMachinePool and compares against existing ASG.
-func diffASG(machinePoolScope *scope.MachinePoolScope, existingASG *expinfrav1.AutoScalingGroup) string {
-	detectedMachinePoolSpec := machinePoolScope.MachinePool.Spec.DeepCopy()
+// reconcileLifecycleHooks makes sure the lifecycle hooks of the ASG match the AWSMachinePool spec.
+// Hooks missing from the ASG are created, drifted hooks are updated unless their sync mode is CreateOnly,
+// and hooks that are no longer part of the spec are deleted unless the AWSMachinePool is externally
+// managed. Hooks are created and updated by ascending priority, so that a failure on one hook doesn't
+// block the hooks that must exist before it. Hooks AWS rejects until their spec changes don't fail the
+// reconciliation, they are reported with the LifecycleHookRejected reason and retried at the resync
+// interval. Hooks that can't be deleted yet because instances wait on them are reported with the
+// LifecycleHookDeletionPending reason and retried after lifecycleHookInUseRequeueAfter. Until the resync
+// interval, only the deletion of the stale hooks is retried, the rejected hooks aren't.
+func (r *AWSMachinePoolReconciler) reconcileLifecycleHooks(machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface) (ctrl.Result, error) {
+	asgName := machinePoolScope.ASGName()
+	log := machinePoolScope.WithValues("asgName", asgName)
+	lifecycleHooks := append([]infrav1.AWSLifecycleHook(nil), machinePoolScope.GetLifecycleHooks()...)
+	sort.SliceStable(lifecycleHooks, func(i, j int) bool {
+		return lifecycleHooks[i].Priority < lifecycleHooks[j].Priority
+	})
 
-	if !annotations.ReplicasManagedByExternalAutoscaler(machinePoolScope.MachinePool) {
-		detectedMachinePoolSpec.Replicas = existingASG.DesiredCapacity
+	// Only sync the lifecycle hooks when they changed since the last successful sync, or when the
+	// drift check is due. The hash covers the effective hooks rather than the generation of the
+	// AWSMachinePool, as default lifecycle hooks of the cluster do not bump it.
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	hash, err := lifecycleHooksHash(lifecycleHooks)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
-	if diff := cmp.Diff(machinePoolScope.MachinePool.Spec, *detectedMachinePoolSpec); diff != "" {
-		return diff
+	lastSync := awsMachinePool.Status.LifecycleHooksLastSyncTime
+	upToDate := hash == awsMachinePool.Status.LifecycleHooksObservedHash && lastSync != nil && time.Since(lastSync.Time) < lifecycleHookDriftCheckInterval
+	previousReason := conditions.GetReason(awsMachinePool, expinfrav1.LifecycleHookExistsCondition)
+	if upToDate && previousReason != expinfrav1.LifecycleHookRejectedReason && previousReason != expinfrav1.LifecycleHookDeletionPendingReason {
+		log.Debug("Lifecycle hooks are up to date, skipping sync")
+		return ctrl.Result{}, nil
 	}
 
-	detectedAWSMachinePoolSpec := machinePoolScope.AWSMachinePool.Spec.DeepCopy()
-	detectedAWSMachinePoolSpec.MaxSize = existingASG.MaxSize
-	detectedAWSMachinePoolSpec.MinSize = existingASG.MinSize
-	detectedAWSMachinePoolSpec.CapacityRebalance = existingASG.CapacityRebalance
-	{
-		mixedInstancesPolicy := machinePoolScope.AWSMachinePool.Spec.MixedInstancesPolicy
-		// InstancesDistribution is optional, and the default values come from AWS, so
-		// they are not set by the AWSMachinePool defaulting webhook. If InstancesDistribution is
-		// not set, we use the AWS values for the purpose of comparison.
-		if mixedInstancesPolicy != nil && mixedInstancesPolicy.InstancesDistribution == nil {
-			mixedInstancesPolicy = machinePoolScope.AWSMachinePool.Spec.MixedInstancesPolicy.DeepCopy()
-			mixedInstancesPolicy.InstancesDistribution = existingASG.MixedInstancesPolicy.InstancesDistribution
-		}
+	existingHooks, err := asgsvc.DescribeLifecycleHooks(asgName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 
-		if !cmp.Equal(mixedInstancesPolicy, existingASG.MixedInstancesPolicy) {
-			detectedAWSMachinePoolSpec.MixedInstancesPolicy = existingASG.MixedInstancesPolicy
+	hooksToSync := lifecycleHooks
+	if upToDate {
+		log.Debug("Lifecycle hooks were synced recently, only retrying the deletion of stale lifecycle hooks")
+		hooksToSync = nil
+	}
+	var rejected []string
+	for i := range hooksToSync {
+		hook := &hooksToSync[i]
+		hookLog := log.WithValues("lifecycleHookName", hook.Name, "lifecycleTransition", hook.LifecycleTransition)
+		// User-defined lifecycle hooks with the reserved prefix would fight with the hooks managed by CAPA.
+		// The webhooks reject them, but they may predate the validation.
+		if strings.HasPrefix(hook.Name, infrav1.ReservedLifecycleHookNamePrefix) && !isManagedLifecycleHook(awsMachinePool, hook.Name) {
+			hookLog.Info("Lifecycle hook name uses the reserved prefix, not syncing it", "prefix", infrav1.ReservedLifecycleHookNamePrefix)
+			rejected = append(rejected, fmt.Sprintf("lifecycle hook %q uses the reserved prefix %q", hook.Name, infrav1.ReservedLifecycleHookNamePrefix))
+			continue
+		}
+		// The webhook doesn't know the region of the cluster, so the partition of the ARNs is checked here.
+		if errs := infrav1.ValidateLifecycleHookARNs(field.NewPath("lifecycleHooks").Key(hook.Name), *hook, machinePoolScope.InfraCluster.Region()); len(errs) > 0 {
+			hookLog.Info("Lifecycle hook ARNs are invalid for the region of the cluster, not syncing it", "error", errs.ToAggregate().Error())
+			rejected = append(rejected, errs.ToAggregate().Error())
+			continue
+		}
+		if err := reconcileLifecycleHook(hookLog, asgsvc, asgName, hook, existingHooks); err != nil {
+			if !asg.IsTerminalLifecycleHookError(err) {
+				return ctrl.Result{}, err
+			}
+			hookLog.Info("Lifecycle hook rejected, not retrying until the resync interval", "error", err.Error())
+			rejected = append(rejected, err.Error())
 		}
 	}
 
-	return cmp.Diff(machinePoolScope.AWSMachinePool.Spec, *detectedAWSMachinePoolSpec)
-}
-
-// getOwnerMachinePool returns the MachinePool object owning the current resource.
-func getOwnerMachinePool(ctx context.Context, c client.Client, obj metav1.ObjectMeta) (*expclusterv1.MachinePool, error) {
-	for _, ref := range obj.OwnerReferences {
-		if ref.Kind != "MachinePool" {
+	// Delete any lifecycle hooks that are not in the spec anymore. Hooks with the reserved prefix are
+	// only deleted when they are managed by this version of CAPA, the others may be managed by other
+	// versions of CAPA. The hooks of externally managed ASGs may have been added by their owner, so
+	// they are never deleted.
+	var staleHooks []*infrav1.AWSLifecycleHook
+	if machinePoolScope.IsExternallyManaged() {
+		log.Info("AWSMachinePool is externally managed, not deleting lifecycle hooks missing from the spec")
+		existingHooks = nil
+	}
+	for _, existingHook := range existingHooks {
+		found := false
+		for _, hook := range lifecycleHooks {
+			if existingHook.Name == hook.Name {
+				found = true
+				break
+			}
+		}
+		if found {
 			continue
 		}
-		gv, err := schema.ParseGroupVersion(ref.APIVersion)
-		if err != nil {
-			return nil, errors.WithStack(err)
+		if strings.HasPrefix(existingHook.Name, infrav1.ReservedLifecycleHookNamePrefix) && !expinfrav1.IsManagedLifecycleHookName(existingHook.Name) {
+			log.Debug("Keeping lifecycle hook with the reserved prefix that is not managed by this version of CAPA", "lifecycleHookName", existingHook.Name)
+			continue
 		}
-		if gv.Group == expclusterv1.GroupVersion.Group {
-			return getMachinePoolByName(ctx, c, obj.Namespace, ref.Name)
+		staleHooks = append(staleHooks, existingHook)
+	}
+	var inUse []string
+	for _, existingHook := range staleHooks {
+		hookLog := log.WithValues("lifecycleHookName", existingHook.Name, "lifecycleTransition", existingHook.LifecycleTransition)
+		hookLog.Info("Deleting lifecycle hook")
+		if err := asgsvc.DeleteLifecycleHook(asgName, existingHook, false); err != nil {
+			if !asg.IsLifecycleHookInUseError(err) {
+				return ctrl.Result{}, err
+			}
+			hookLog.Info("Lifecycle hook has pending lifecycle actions, retrying its deletion later", "requeueAfter", lifecycleHookInUseRequeueAfter)
+			inUse = append(inUse, existingHook.Name)
 		}
 	}
-	return nil, nil
-}
 
-// getMachinePoolByName finds and return a Machine object using the specified params.
+	var result ctrl.Result
+	if len(inUse) > 0 {
+		result = ctrl.Result{RequeueAfter: lifecycleHookInUseRequeueAfter}
+	}
+	// The sync is recorded even when hooks were rejected or are pending deletion, so that the rejected
+	// hooks are only retried at the resync interval.
+	if !upToDate {
+		awsMachinePool.Status.LifecycleHooksObservedHash = hash
+		awsMachinePool.Status.LifecycleHooksLastSyncTime = ptr.To(metav1.Now())
+	}
+
+	if len(rejected) > 0 {
+		if conditions.GetReason(awsMachinePool, expinfrav1.LifecycleHookExistsCondition) != expinfrav1.LifecycleHookRejectedReason {
+			r.Recorder.Eventf(awsMachinePool, corev1.EventTypeWarning, "LifecycleHookRejected", "Lifecycle hooks rejected: %s", strings.Join(rejected, "; "))
+		}
+		conditions.MarkFalse(awsMachinePool, expinfrav1.LifecycleHookExistsCondition, expinfrav1.LifecycleHookRejectedReason, clusterv1.ConditionSeverityError,
+			"%s. Fix the lifecycle hooks spec.", strings.Join(rejected, "; "))
+		return result, nil
+	}
+	if upToDate && previousReason == expinfrav1.LifecycleHookRejectedReason {
+		// The rejected hooks weren't retried, they are still reported.
+		return result, nil
+	}
+	if len(inUse) > 0 {
+		conditions.MarkFalse(awsMachinePool, expinfrav1.LifecycleHookExistsCondition, expinfrav1.LifecycleHookDeletionPendingReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for the pending lifecycle actions of %s to complete before deleting them", strings.Join(inUse, ", "))
+		return result, nil
+	}
+	switch {
+	case len(lifecycleHooks) > 0:
+		conditions.MarkTrue(awsMachinePool, expinfrav1.LifecycleHookExistsCondition)
+	case conditions.GetReason(awsMachinePool, expinfrav1.LifecycleHookExistsCondition) == expinfrav1.LifecycleHookRejectedReason,
+		conditions.GetReason(awsMachinePool, expinfrav1.LifecycleHookExistsCondition) == expinfrav1.LifecycleHookDeletionPendingReason:
+		// The rejected hooks were removed from the spec, or the hooks pending deletion were deleted.
+		conditions.Delete(awsMachinePool, expinfrav1.LifecycleHookExistsCondition)
+	}
+
+	return result, nil
+}
+
+// isManagedLifecycleHook returns true if the lifecycle hook with the given name is managed by CAPA for the
+// AWSMachinePool, rather than defined by the user.
+func isManagedLifecycleHook(awsMachinePool *expinfrav1.AWSMachinePool, name string) bool {
+	return name == expinfrav1.ManagedLaunchLifecycleHookName && awsMachinePool.Spec.ManagedLaunchLifecycleHook.IsEnabled()
+}
+
+// lifecycleHooksHash returns a hash of the lifecycle hooks, used to detect changes since the last sync.
+func lifecycleHooksHash(hooks []infrav1.AWSLifecycleHook) (string, error) {
+	b, err := json.Marshal(hooks)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal lifecycle hooks")
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b)), nil
+}
+
+func reconcileLifecycleHook(log *logger.Logger, asgsvc services.ASGInterface, asgName string, hook *infrav1.AWSLifecycleHook, existingHooks []*infrav1.AWSLifecycleHook) error {
+	log.Debug("Checking for existing lifecycle hook")
+
+	var existingHook *infrav1.AWSLifecycleHook
+	for _, h := range existingHooks {
+		if h.Name == hook.Name {
+			existingHook = h
+			break
+		}
+	}
+
+	if existingHook == nil {
+		log.Info("Creating lifecycle hook")
+		return asgsvc.CreateLifecycleHook(asgName, hook)
+	}
+
+	if !asgsvc.LifecycleHookNeedsUpdate(existingHook, hook) {
+		return nil
+	}
+
+	if hook.IsCreateOnly() {
+		log.Debug("Lifecycle hook differs from spec but sync mode is CreateOnly, skipping update")
+		return nil
+	}
+
+	log.Info("Updating lifecycle hook")
+	return asgsvc.UpdateLifecycleHook(asgName, hook)
+}
+
+// reconcileLoadBalancerAttachments attaches the ASG to the target groups of the control plane load balancer listeners
+// referenced in the AWSMachinePool spec, and detaches it from the other target groups of the control plane load
+// balancer. LoadBalancerAttachmentsReadyCondition is only set while attachments are referenced, so that pools
+// that never used them don't describe the load balancer on every reconcile.
+func (r *AWSMachinePoolReconciler) reconcileLoadBalancerAttachments(machinePoolScope *scope.MachinePoolScope, ec2Scope scope.EC2Scope, asgsvc services.ASGInterface) error {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	attachments := awsMachinePool.Spec.LoadBalancerAttachments
+	if len(attachments) == 0 && !conditions.Has(awsMachinePool, expinfrav1.LoadBalancerAttachmentsReadyCondition) {
+		return nil
+	}
+
+	var lbSpec *infrav1.AWSLoadBalancerSpec
+	elbScope, ok := ec2Scope.(scope.ELBScope)
+	if ok {
+		lbSpec = elbScope.ControlPlaneLoadBalancer()
+	}
+	if lbSpec == nil || lbSpec.LoadBalancerType != infrav1.LoadBalancerTypeNLB {
+		if len(attachments) == 0 {
+			conditions.Delete(awsMachinePool, expinfrav1.LoadBalancerAttachmentsReadyCondition)
+			return nil
+		}
+		err := errors.New("load balancer attachments require a control plane network load balancer")
+		conditions.MarkFalse(awsMachinePool, expinfrav1.LoadBalancerAttachmentsReadyCondition, expinfrav1.LoadBalancerListenerNotFoundReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+
+	listenerPorts := sets.New[int64]()
+	for _, ln := range lbSpec.AdditionalListeners {
+		listenerPorts.Insert(ln.Port)
+	}
+	for _, attachment := range attachments {
+		if !listenerPorts.Has(attachment.ListenerPort) {
+			err := errors.Errorf("control plane load balancer has no additional listener on port %d", attachment.ListenerPort)
+			conditions.MarkFalse(awsMachinePool, expinfrav1.LoadBalancerAttachmentsReadyCondition, expinfrav1.LoadBalancerListenerNotFoundReason, clusterv1.ConditionSeverityError, err.Error())
+			return err
+		}
+	}
+
+	targetGroups, err := r.getELBService(elbScope).GetAPIServerLBListenerTargetGroups(lbSpec)
+	if err != nil {
+		conditions.MarkFalse(awsMachinePool, expinfrav1.LoadBalancerAttachmentsReadyCondition, expinfrav1.LoadBalancerAttachmentsFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+
+	desired := sets.New[string]()
+	for _, attachment := range attachments {
+		arn, ok := targetGroups[attachment.ListenerPort]
+		if !ok {
+			// The listener is in the AWSCluster spec but has not been created on the load balancer yet.
+			err := errors.Errorf("listener on port %d not found on the control plane load balancer", attachment.ListenerPort)
+			conditions.MarkFalse(awsMachinePool, expinfrav1.LoadBalancerAttachmentsReadyCondition, expinfrav1.LoadBalancerListenerNotFoundReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return err
+		}
+		desired.Insert(arn)
+	}
+	lbTargetGroups := sets.New[string]()
+	for _, arn := range targetGroups {
+		lbTargetGroups.Insert(arn)
+	}
+
+	asgName := machinePoolScope.ASGName()
+	attachedARNs, err := asgsvc.DescribeTargetGroupAttachments(asgName)
+	if err != nil {
+		conditions.MarkFalse(awsMachinePool, expinfrav1.LoadBalancerAttachmentsReadyCondition, expinfrav1.LoadBalancerAttachmentsFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+	attached := sets.New[string](attachedARNs...)
+
+	if toAttach := sets.List(desired.Difference(attached)); len(toAttach) > 0 {
+		machinePoolScope.Info("Attaching AutoScalingGroup to control plane load balancer target groups", "asgName", asgName, "targetGroups", toAttach)
+		if err := asgsvc.AttachTargetGroups(asgName, toAttach); err != nil {
+			conditions.MarkFalse(awsMachinePool, expinfrav1.LoadBalancerAttachmentsReadyCondition, expinfrav1.LoadBalancerAttachmentsFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return err
+		}
+	}
+	// Only target groups of the control plane load balancer are detached, others are not managed by CAPA.
+	if toDetach := sets.List(attached.Intersection(lbTargetGroups).Difference(desired)); len(toDetach) > 0 {
+		machinePoolScope.Info("Detaching AutoScalingGroup from control plane load balancer target groups", "asgName", asgName, "targetGroups", toDetach)
+		if err := asgsvc.DetachTargetGroups(asgName, toDetach); err != nil {
+			conditions.MarkFalse(awsMachinePool, expinfrav1.LoadBalancerAttachmentsReadyCondition, expinfrav1.LoadBalancerAttachmentsFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return err
+		}
+	}
+
+	if len(attachments) == 0 {
+		conditions.Delete(awsMachinePool, expinfrav1.LoadBalancerAttachmentsReadyCondition)
+		return nil
+	}
+	conditions.MarkTrue(awsMachinePool, expinfrav1.LoadBalancerAttachmentsReadyCondition)
+	return nil
+}
+
+// reconcileTargetGroups attaches the ASG to the target groups of spec.targetGroupARNs, and detaches it from the
+// target groups CAPA attached it to that were removed from the spec. The attachments made by others are left
+// alone. Pools that never referenced target groups don't describe the attachments of the ASG.
+func (r *AWSMachinePoolReconciler) reconcileTargetGroups(machinePoolScope *scope.MachinePoolScope, ec2Scope scope.EC2Scope, asgsvc services.ASGInterface) error {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	if len(awsMachinePool.Spec.TargetGroupARNs) == 0 && len(awsMachinePool.Status.AttachedTargetGroupARNs) == 0 {
+		return nil
+	}
+
+	// The webhook can't tell the partition of the cluster, so the target groups are checked against it here.
+	partition := system.GetPartitionFromRegion(ec2Scope.Region())
+	for _, targetGroupARN := range awsMachinePool.Spec.TargetGroupARNs {
+		if parsed, err := arn.Parse(targetGroupARN); err != nil || parsed.Partition != partition {
+			return errors.Errorf("target group %q is not in the %q partition of the cluster", targetGroupARN, partition)
+		}
+	}
+
+	asgName := machinePoolScope.ASGName()
+	attachedARNs, err := asgsvc.DescribeTargetGroupAttachments(asgName)
+	if err != nil {
+		return err
+	}
+	attached := sets.New[string](attachedARNs...)
+	desired := sets.New[string](awsMachinePool.Spec.TargetGroupARNs...)
+
+	if toAttach := sets.List(desired.Difference(attached)); len(toAttach) > 0 {
+		machinePoolScope.Info("Attaching AutoScalingGroup to target groups", "asgName", asgName, "targetGroups", toAttach)
+		if err := asgsvc.AttachTargetGroups(asgName, toAttach); err != nil {
+			return err
+		}
+	}
+	removed := sets.New[string](awsMachinePool.Status.AttachedTargetGroupARNs...).Difference(desired)
+	if toDetach := sets.List(removed.Intersection(attached)); len(toDetach) > 0 {
+		machinePoolScope.Info("Detaching AutoScalingGroup from target groups", "asgName", asgName, "targetGroups", toDetach)
+		if err := asgsvc.DetachTargetGroups(asgName, toDetach); err != nil {
+			return err
+		}
+	}
+
+	if desired.Len() == 0 {
+		awsMachinePool.Status.AttachedTargetGroupARNs = nil
+		return nil
+	}
+	awsMachinePool.Status.AttachedTargetGroupARNs = sets.List(desired)
+	return nil
+}
+
+// reconcileScheduledActions creates and updates the scheduled actions of spec.scheduledActions on the ASG, and
+// deletes the ones CAPA created which were removed from the spec. Scheduled actions created outside of CAPA are
+// left alone. Pools that never had scheduled actions don't describe the scheduled actions of the ASG.
+func (r *AWSMachinePoolReconciler) reconcileScheduledActions(machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface) error {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	if len(awsMachinePool.Spec.ScheduledActions) == 0 && len(awsMachinePool.Status.ScheduledActions) == 0 {
+		return nil
+	}
+
+	asgName := machinePoolScope.ASGName()
+	existingActions, err := asgsvc.DescribeScheduledActions(asgName)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*expinfrav1.ScheduledAction, len(existingActions))
+	for _, action := range existingActions {
+		existing[action.Name] = action
+	}
+
+	desired := sets.New[string]()
+	for i := range awsMachinePool.Spec.ScheduledActions {
+		action := &awsMachinePool.Spec.ScheduledActions[i]
+		desired.Insert(action.Name)
+
+		found, ok := existing[action.Name]
+		if !ok {
+			machinePoolScope.Info("Creating scheduled action", "asgName", asgName, "scheduledActionName", action.Name)
+			if err := asgsvc.CreateScheduledAction(asgName, action); err != nil {
+				return err
+			}
+			continue
+		}
+		if asgsvc.ScheduledActionNeedsUpdate(found, action) {
+			machinePoolScope.Info("Updating scheduled action", "asgName", asgName, "scheduledActionName", action.Name)
+			if err := asgsvc.UpdateScheduledAction(asgName, found, action); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range sets.List(sets.New[string](awsMachinePool.Status.ScheduledActions...).Difference(desired)) {
+		if _, ok := existing[name]; !ok {
+			continue
+		}
+		machinePoolScope.Info("Deleting scheduled action", "asgName", asgName, "scheduledActionName", name)
+		if err := asgsvc.DeleteScheduledAction(asgName, name); err != nil {
+			return err
+		}
+	}
+
+	if desired.Len() == 0 {
+		awsMachinePool.Status.ScheduledActions = nil
+		return nil
+	}
+	awsMachinePool.Status.ScheduledActions = sets.List(desired)
+	return nil
+}
+
+// detachTargetGroups detaches the ASG from the target groups of spec.targetGroupARNs before the ASG is deleted,
+// so that its instances are deregistered from them instead of being left behind until they are terminated.
+func (r *AWSMachinePoolReconciler) detachTargetGroups(machinePoolScope *scope.MachinePoolScope, asgSvc services.ASGInterface, asgName string) error {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	managed := sets.New[string](awsMachinePool.Spec.TargetGroupARNs...).Insert(awsMachinePool.Status.AttachedTargetGroupARNs...)
+	if managed.Len() == 0 {
+		return nil
+	}
+
+	attachedARNs, err := asgSvc.DescribeTargetGroupAttachments(asgName)
+	if err != nil {
+		return err
+	}
+	if toDetach := sets.List(managed.Intersection(sets.New[string](attachedARNs...))); len(toDetach) > 0 {
+		machinePoolScope.Info("Detaching AutoScalingGroup from target groups", "asgName", asgName, "targetGroups", toDetach)
+		if err := asgSvc.DetachTargetGroups(asgName, toDetach); err != nil {
+			return err
+		}
+	}
+	awsMachinePool.Status.AttachedTargetGroupARNs = nil
+	return nil
+}
+
+// reconcileClassicLoadBalancers attaches the ASG to the Classic Load Balancers of spec.classicLoadBalancers, and
+// detaches it from the ones CAPA attached it to that were removed from the spec. The attachments are compared
+// with the load balancers of the ASG, so that the ones removed outside of CAPA are restored. Failures are
+// reported on ClassicLoadBalancersReadyCondition and retried, instead of failing the reconciliation of the ASG.
+func (r *AWSMachinePoolReconciler) reconcileClassicLoadBalancers(machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface, asg *expinfrav1.AutoScalingGroup) ctrl.Result {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	if len(awsMachinePool.Spec.ClassicLoadBalancers) == 0 && len(awsMachinePool.Status.AttachedClassicLoadBalancers) == 0 {
+		conditions.Delete(awsMachinePool, expinfrav1.ClassicLoadBalancersReadyCondition)
+		return ctrl.Result{}
+	}
+
+	attached := sets.New[string](asg.LoadBalancerNames...)
+	desired := sets.New[string](awsMachinePool.Spec.ClassicLoadBalancers...)
+
+	if toAttach := sets.List(desired.Difference(attached)); len(toAttach) > 0 {
+		machinePoolScope.Info("Attaching AutoScalingGroup to classic load balancers", "asgName", asg.Name, "loadBalancers", toAttach)
+		if err := asgsvc.AttachLoadBalancers(asg.Name, toAttach); err != nil {
+			r.Recorder.Eventf(awsMachinePool, corev1.EventTypeWarning, "FailedAttachClassicLoadBalancers", "Failed to attach ASG %q to classic load balancers: %v", asg.Name, err)
+			conditions.MarkFalse(awsMachinePool, expinfrav1.ClassicLoadBalancersReadyCondition, expinfrav1.ClassicLoadBalancerAttachmentFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return ctrl.Result{RequeueAfter: classicLoadBalancersRequeueAfter}
+		}
+	}
+	removed := sets.New[string](awsMachinePool.Status.AttachedClassicLoadBalancers...).Difference(desired)
+	if toDetach := sets.List(removed.Intersection(attached)); len(toDetach) > 0 {
+		machinePoolScope.Info("Detaching AutoScalingGroup from classic load balancers", "asgName", asg.Name, "loadBalancers", toDetach)
+		if err := asgsvc.DetachLoadBalancers(asg.Name, toDetach); err != nil {
+			r.Recorder.Eventf(awsMachinePool, corev1.EventTypeWarning, "FailedDetachClassicLoadBalancers", "Failed to detach ASG %q from classic load balancers: %v", asg.Name, err)
+			conditions.MarkFalse(awsMachinePool, expinfrav1.ClassicLoadBalancersReadyCondition, expinfrav1.ClassicLoadBalancerAttachmentFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return ctrl.Result{RequeueAfter: classicLoadBalancersRequeueAfter}
+		}
+	}
+
+	if desired.Len() == 0 {
+		awsMachinePool.Status.AttachedClassicLoadBalancers = nil
+		conditions.Delete(awsMachinePool, expinfrav1.ClassicLoadBalancersReadyCondition)
+		return ctrl.Result{}
+	}
+	awsMachinePool.Status.AttachedClassicLoadBalancers = sets.List(desired)
+	conditions.MarkTrue(awsMachinePool, expinfrav1.ClassicLoadBalancersReadyCondition)
+	return ctrl.Result{}
+}
+
+// detachClassicLoadBalancers detaches the ASG from the Classic Load Balancers of spec.classicLoadBalancers before
+// the ASG is deleted, so that its instances are deregistered from them instead of being left behind until they
+// are terminated.
+func (r *AWSMachinePoolReconciler) detachClassicLoadBalancers(machinePoolScope *scope.MachinePoolScope, asgSvc services.ASGInterface, asg *expinfrav1.AutoScalingGroup) error {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	managed := sets.New[string](awsMachinePool.Spec.ClassicLoadBalancers...).Insert(awsMachinePool.Status.AttachedClassicLoadBalancers...)
+	if toDetach := sets.List(managed.Intersection(sets.New[string](asg.LoadBalancerNames...))); len(toDetach) > 0 {
+		machinePoolScope.Info("Detaching AutoScalingGroup from classic load balancers", "asgName", asg.Name, "loadBalancers", toDetach)
+		if err := asgSvc.DetachLoadBalancers(asg.Name, toDetach); err != nil {
+			return err
+		}
+	}
+	awsMachinePool.Status.AttachedClassicLoadBalancers = nil
+	return nil
+}
+
+// reconcileScalingState surfaces the in-flight scaling state of the ASG in the AWSMachinePool status, and emits
+// an event once a scale-up has been outstanding for longer than the configured threshold. It returns the scaling
+// activities of the ASG, sorted from the most recent one.
+func (r *AWSMachinePoolReconciler) reconcileScalingState(machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface, asg *expinfrav1.AutoScalingGroup) ([]*expinfrav1.ScalingActivity, error) {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	previous := awsMachinePool.Status.ScalingState
+	state := &expinfrav1.ScalingState{}
+
+	// The desired capacity is compared with the capacity units of the instances in service, which are the
+	// instances themselves unless the instance types are weighted.
+	var inService, inServiceCapacity int32
+	for _, instance := range asg.Instances {
+		switch instance.State {
+		case expinfrav1.InstanceStatePending, expinfrav1.InstanceStatePendingWait, expinfrav1.InstanceStatePendingProceed:
+			state.PendingInstances++
+		case expinfrav1.InstanceStateInService:
+			inService++
+			inServiceCapacity += instanceCapacity(asg.MixedInstancesPolicy, instance.Type)
+		}
+	}
+
+	if usesSpotInstances(awsMachinePool) {
+		count, err := asgsvc.OpenSpotInstanceRequestCount(machinePoolScope.GetLaunchTemplateIDStatus())
+		if err != nil {
+			return nil, err
+		}
+		state.OpenSpotInstanceRequests = count
+	}
+
+	activities, err := asgsvc.DescribeScalingActivities(asg.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(activities) > 0 {
+		state.LatestScalingActivity = activities[0]
+	}
+
+	if asg.DesiredCapacity != nil && inServiceCapacity < *asg.DesiredCapacity {
+		state.ScaleUpPendingSince = ptr.To(metav1.Now())
+		if previous != nil && previous.ScaleUpPendingSince != nil {
+			state.ScaleUpPendingSince = previous.ScaleUpPendingSince
+		}
+	}
+	awsMachinePool.Status.ScalingState = state
+
+	if state.ScaleUpPendingSince == nil {
+		return activities, nil
+	}
+
+	threshold := expinfrav1.DefaultScaleUpDelayThreshold
+	if awsMachinePool.Spec.ScaleUpDelayThreshold != nil {
+		threshold = awsMachinePool.Spec.ScaleUpDelayThreshold.Duration
+	}
+	pendingFor := time.Since(state.ScaleUpPendingSince.Time)
+	if pendingFor < threshold {
+		return activities, nil
+	}
+	state.ScaleUpDelayed = true
+	// The event is only emitted when the scale-up becomes delayed, not on every reconciliation while it stays so.
+	if previous != nil && previous.ScaleUpDelayed {
+		return activities, nil
+	}
+
+	message := fmt.Sprintf("Scale-up has been pending for %s with %d of %d desired instances in service", pendingFor.Round(time.Second), inService, *asg.DesiredCapacity)
+	if asg.MixedInstancesPolicy.UsesWeightedCapacity() {
+		message = fmt.Sprintf("Scale-up has been pending for %s with %d instances in service, counting for %d of %d desired capacity units",
+			pendingFor.Round(time.Second), inService, inServiceCapacity, *asg.DesiredCapacity)
+	}
+	if dominantError := dominantScalingError(activities, state.ScaleUpPendingSince.Time); dominantError != "" {
+		message = fmt.Sprintf("%s: %s", message, dominantError)
+	}
+	r.Recorder.Event(awsMachinePool, corev1.EventTypeWarning, "ScaleUpDelayed", message)
+
+	return activities, nil
+}
+
+// reconcileScalingActivities reports in the AWSMachinePool conditions the scaling activities of the ASG which failed
+// to launch instances, e.g. because of insufficient capacity, which the status of the ASG doesn't reflect.
+func (r *AWSMachinePoolReconciler) reconcileScalingActivities(machinePoolScope *scope.MachinePoolScope, activities []*expinfrav1.ScalingActivity) {
+	asg.ReconcileScalingActivities(machinePoolScope, activities, time.Now())
+}
+
+// reconcileInstancesReady marks the AWSMachinePool ready once enough instances of the ASG are InService, so that the
+// MachinePool doesn't report nodes which weren't launched yet.
+func (r *AWSMachinePoolReconciler) reconcileInstancesReady(machinePoolScope *scope.MachinePoolScope, group *expinfrav1.AutoScalingGroup) ctrl.Result {
+	return ctrl.Result{RequeueAfter: asg.ReconcileInstancesReady(machinePoolScope, group, time.Now())}
+}
+
+// instanceCapacity returns the number of capacity units an instance of an ASG counts for, which is the weighted
+// capacity of its instance type, or 1 when the instance types aren't weighted.
+func instanceCapacity(policy *expinfrav1.MixedInstancesPolicy, instanceType string) int32 {
+	if policy == nil {
+		return 1
+	}
+	for _, override := range policy.Overrides {
+		if override.InstanceType != instanceType || override.WeightedCapacity == "" {
+			continue
+		}
+		if weight, err := strconv.ParseInt(override.WeightedCapacity, 10, 32); err == nil {
+			return int32(weight)
+		}
+	}
+	return 1
+}
+
+// reconcileSpotInterruptionHandling creates the queue receiving the spot interruption notices of the instances of the
+// cluster when the AWSMachinePool enables their handling, and records it in the status for the spot interruption
+// controller to consume. The queue is shared by the pools of the cluster and deleted with it.
+func (r *AWSMachinePoolReconciler) reconcileSpotInterruptionHandling(machinePoolScope *scope.MachinePoolScope, ec2Scope scope.EC2Scope) error {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	if !feature.Gates.Enabled(feature.SpotInterruptionHandling) || !awsMachinePool.Spec.SpotInterruptionHandling.IsEnabled() {
+		awsMachinePool.Status.SpotInterruptionQueueARN = ""
+		conditions.Delete(awsMachinePool, expinfrav1.SpotInterruptionHandlingReadyCondition)
+		return nil
+	}
+
+	queueARN, err := r.getSpotInterruptionService(ec2Scope).ReconcileSpotInterruptionEvents()
+	if err != nil {
+		conditions.MarkFalse(awsMachinePool, expinfrav1.SpotInterruptionHandlingReadyCondition, expinfrav1.SpotInterruptionHandlingFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
+		return err
+	}
+
+	awsMachinePool.Status.SpotInterruptionQueueARN = queueARN
+	conditions.MarkTrue(awsMachinePool, expinfrav1.SpotInterruptionHandlingReadyCondition)
+	return nil
+}
+
+// ec2QuotaServiceCode is the code of the EC2 service in the quota usage of the AWSCluster.
+const ec2QuotaServiceCode = "ec2"
+
+// scalingBlockingProcesses are the processes of an ASG which prevent it from scaling when they're suspended.
+var scalingBlockingProcesses = sets.New("Launch", "Terminate", "AlarmNotification")
+
+// reconcileScalingBlockers aggregates in the AWSMachinePool status what may prevent the ASG from reaching its desired
+// capacity, and emits a single event when the blockers change. The activities are nil when they couldn't be described.
+func (r *AWSMachinePoolReconciler) reconcileScalingBlockers(machinePoolScope *scope.MachinePoolScope, clusterScope cloud.ClusterScoper, asg *expinfrav1.AutoScalingGroup, activities []*expinfrav1.ScalingActivity) {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+
+	var quotaUsage []infrav1.QuotaUsage
+	if awsCluster, ok := clusterScope.InfraCluster().(*infrav1.AWSCluster); ok && awsCluster != nil {
+		quotaUsage = awsCluster.Status.QuotaUsage
+	}
+
+	blockers := scalingBlockers(awsMachinePool, asg, activities, quotaUsage)
+	if cmp.Equal(blockers, awsMachinePool.Status.ScalingBlockers, cmpopts.EquateEmpty()) {
+		return
+	}
+	awsMachinePool.Status.ScalingBlockers = blockers
+
+	if len(blockers) == 0 {
+		r.Recorder.Event(awsMachinePool, corev1.EventTypeNormal, "ScalingUnblocked", "No longer blocked from scaling")
+		return
+	}
+	messages := make([]string, len(blockers))
+	for i, blocker := range blockers {
+		messages[i] = fmt.Sprintf("%s: %s", blocker.Code, blocker.Message)
+	}
+	machinePoolScope.Info("Scaling blocked", "blockers", messages)
+	r.Recorder.Event(awsMachinePool, corev1.EventTypeWarning, "ScalingBlocked", strings.Join(messages, "; "))
+}
+
+// scalingBlockers returns what may prevent the ASG from reaching its desired capacity: an invalid launch template,
+// the failed scaling activities more recent than the last successful one, the last instance refresh, the suspended
+// scaling processes and the exhausted EC2 quotas. The activities are sorted from the most recent one, and only the
+// most recent failure of each kind is reported.
+func scalingBlockers(awsMachinePool *expinfrav1.AWSMachinePool, asg *expinfrav1.AutoScalingGroup, activities []*expinfrav1.ScalingActivity, quotaUsage []infrav1.QuotaUsage) []expinfrav1.ScalingBlocker {
+	var blockers []expinfrav1.ScalingBlocker
+	reported := sets.New[expinfrav1.ScalingBlockerCode]()
+	add := func(code expinfrav1.ScalingBlockerCode, message string) {
+		if reported.Has(code) {
+			return
+		}
+		reported.Insert(code)
+		blockers = append(blockers, expinfrav1.ScalingBlocker{Code: code, Message: message})
+	}
+
+	if condition := conditions.Get(awsMachinePool, expinfrav1.LaunchTemplateReadyCondition); condition != nil && condition.Status != corev1.ConditionTrue {
+		add(expinfrav1.ScalingBlockerLaunchTemplateInvalid, condition.Message)
+	}
+
+	for _, activity := range activities {
+		if activity.StatusCode == expinfrav1.ScalingActivityStatusSuccessful {
+			break
+		}
+		if activity.StatusCode != expinfrav1.ScalingActivityStatusFailed {
+			continue
+		}
+		message := activity.StatusMessage
+		if message == "" {
+			message = activity.Description
+		}
+		add(scalingActivityBlockerCode(message), message)
+	}
+
+	if refresh := awsMachinePool.Status.InstanceRefresh; refresh != nil {
+		switch {
+		case refresh.IsActive():
+			add(expinfrav1.ScalingBlockerInstanceRefreshInProgress, fmt.Sprintf("Instance refresh %s is %s: %d%% complete", refresh.ID, refresh.Status, refresh.PercentageComplete))
+		case refresh.Status == "Failed" || refresh.Status == "RollbackFailed":
+			add(expinfrav1.ScalingBlockerInstanceRefreshFailed, fmt.Sprintf("Instance refresh %s is %s: %s", refresh.ID, refresh.Status, refresh.StatusReason))
+		}
+	}
+
+	if asg != nil {
+		if suspended := sets.List(sets.New(asg.CurrentlySuspendProcesses...).Intersection(scalingBlockingProcesses)); len(suspended) > 0 {
+			add(expinfrav1.ScalingBlockerProcessesSuspended, fmt.Sprintf("Scaling processes are suspended: %s", strings.Join(suspended, ", ")))
+		}
+	}
+
+	for _, usage := range quotaUsage {
+		if usage.ServiceCode != ec2QuotaServiceCode || usage.Usage < usage.Limit {
+			continue
+		}
+		add(expinfrav1.ScalingBlockerQuotaExceeded, fmt.Sprintf("Service quota %q (%s/%s) is exhausted with a usage of %d for a limit of %d", usage.QuotaName, usage.ServiceCode, usage.QuotaCode, usage.Usage, usage.Limit))
+	}
+
+	return blockers
+}
+
+// scalingActivityBlockerCode classifies the status message of a failed scaling activity.
+func scalingActivityBlockerCode(message string) expinfrav1.ScalingBlockerCode {
+	message = strings.ToLower(message)
+	switch {
+	case strings.Contains(message, "limitexceeded") || strings.Contains(message, "countexceeded") || strings.Contains(message, "vcpu limit"):
+		return expinfrav1.ScalingBlockerQuotaExceeded
+	case strings.Contains(message, "spot") && strings.Contains(message, "capacity"):
+		return expinfrav1.ScalingBlockerSpotCapacity
+	case strings.Contains(message, "launch template") || strings.Contains(message, "image id"):
+		return expinfrav1.ScalingBlockerLaunchTemplateInvalid
+	}
+	return expinfrav1.ScalingBlockerFailedScalingActivity
+}
+
+// reconcileScaleDown splits a scale-down of the MachinePool into steps of at most ScaleDownPolicy.MaxUnavailable
+// instances. A step starts once the instances removed by the previous step left the ASG, which includes their
+// terminate lifecycle hooks, e.g. node drains, and ScaleDownPolicy.PauseSeconds elapsed. The desired capacity
+// of the current step is stored in the AWSMachinePool status and used when updating the ASG.
+func (r *AWSMachinePoolReconciler) reconcileScaleDown(machinePoolScope *scope.MachinePoolScope, asg *expinfrav1.AutoScalingGroup) (ctrl.Result, error) {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	policy := awsMachinePool.Spec.ScaleDownPolicy
+	replicas := machinePoolScope.MachinePool.Spec.Replicas
+	_, _, managedDesiredCapacity := machinePoolScope.ManagedSizes()
+	if policy == nil || replicas == nil || asg.DesiredCapacity == nil || !managedDesiredCapacity {
+		awsMachinePool.Status.ScaleDown = nil
+		return ctrl.Result{}, nil
+	}
+
+	// The surge of the evacuated availability zones is scaled down like the replicas once the evacuation ends.
+	target := machinePoolScope.TargetDesiredCapacity()
+	current := *asg.DesiredCapacity
+	if target >= current {
+		if awsMachinePool.Status.ScaleDown != nil {
+			machinePoolScope.Info("Scale-down completed", "desiredCapacity", current)
+			conditions.MarkTrue(awsMachinePool, expinfrav1.ScaleDownCompletedCondition)
+		}
+		awsMachinePool.Status.ScaleDown = nil
+		return ctrl.Result{}, nil
+	}
+
+	if _, ok := awsMachinePool.GetAnnotations()[expinfrav1.ScaleDownImmediatelyAnnotation]; ok {
+		machinePoolScope.Info("Scaling down immediately", "annotation", expinfrav1.ScaleDownImmediatelyAnnotation, "desiredCapacity", current, "targetDesiredCapacity", target)
+		awsMachinePool.Status.ScaleDown = &expinfrav1.ScaleDownStatus{DesiredCapacity: target, LastStepTime: ptr.To(metav1.Now())}
+		return ctrl.Result{}, nil
+	}
+
+	// Hold the ASG at its current desired capacity until the next step can start.
+	step := awsMachinePool.Status.ScaleDown
+	if step == nil {
+		step = &expinfrav1.ScaleDownStatus{}
+		awsMachinePool.Status.ScaleDown = step
+	}
+	step.DesiredCapacity = current
+
+	if terminating := int32(len(asg.Instances)) - current; terminating > 0 {
+		conditions.MarkFalse(awsMachinePool, expinfrav1.ScaleDownCompletedCondition, expinfrav1.ScaleDownWaitingForInstancesReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for %d instances to terminate before scaling down from %d to %d instances", terminating, current, target)
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	if step.LastStepTime != nil {
+		pause := time.Duration(policy.PauseSeconds)*time.Second - time.Since(step.LastStepTime.Time)
+		if pause > 0 {
+			conditions.MarkFalse(awsMachinePool, expinfrav1.ScaleDownCompletedCondition, expinfrav1.ScaleDownInProgressReason, clusterv1.ConditionSeverityInfo,
+				"Pausing for %s before scaling down from %d to %d instances", pause.Round(time.Second), current, target)
+			return ctrl.Result{RequeueAfter: pause}, nil
+		}
+	}
+
+	maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(intstr.ValueOrDefault(policy.MaxUnavailable, intstr.FromInt32(1)), int(current), false)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "invalid scale-down policy max unavailable")
+	}
+	step.DesiredCapacity = max(target, current-int32(max(maxUnavailable, 1)))
+	step.LastStepTime = ptr.To(metav1.Now())
+	machinePoolScope.Info("Scaling down", "desiredCapacity", current, "stepDesiredCapacity", step.DesiredCapacity, "targetDesiredCapacity", target)
+	conditions.MarkFalse(awsMachinePool, expinfrav1.ScaleDownCompletedCondition, expinfrav1.ScaleDownInProgressReason, clusterv1.ConditionSeverityInfo,
+		"Scaling down from %d to %d instances, towards %d instances", current, step.DesiredCapacity, target)
+
+	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+}
+
+// reconcileEvacuation records the availability zones of the aws.cluster.x-k8s.io/az-evacuate annotation in the
+// AWSMachinePool status, with the subnets removed from the ASG and, when the aws.cluster.x-k8s.io/az-evacuate-surge
+// annotation is set, the number of instances the desired capacity is raised by to replace the instances which ran
+// in them. The subnets and the desired capacity of the ASG are restored once the annotation is removed. Evacuating
+// every availability zone of the ASG is refused.
+func (r *AWSMachinePoolReconciler) reconcileEvacuation(machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface, asg *expinfrav1.AutoScalingGroup) error {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+
+	requested := evacuatedZoneNames(awsMachinePool.GetAnnotations()[expinfrav1.AZEvacuateAnnotation])
+	if len(requested) == 0 {
+		r.setEvacuatedZones(machinePoolScope, nil)
+		conditions.Delete(awsMachinePool, expinfrav1.AvailabilityZonesEvacuatedCondition)
+		return nil
+	}
+
+	subnetIDs, err := asgsvc.SubnetIDs(machinePoolScope)
+	if err != nil {
+		return errors.Wrap(err, "failed to get subnets for ASG")
+	}
+	subnetsByZone := map[string][]string{}
+	for _, id := range subnetIDs {
+		subnet := machinePoolScope.InfraCluster.Subnets().FindByID(id)
+		if subnet == nil || subnet.AvailabilityZone == "" {
+			if !conditions.IsFalse(awsMachinePool, expinfrav1.AvailabilityZonesEvacuatedCondition) {
+				r.Recorder.Eventf(awsMachinePool, corev1.EventTypeWarning, expinfrav1.AZEvacuationFailedReason,
+					"Can't evacuate availability zones %s, the availability zone of subnet %s is unknown", strings.Join(requested, ", "), id)
+			}
+			conditions.MarkFalse(awsMachinePool, expinfrav1.AvailabilityZonesEvacuatedCondition, expinfrav1.AZEvacuationFailedReason, clusterv1.ConditionSeverityWarning,
+				"The availability zone of subnet %s is unknown", id)
+			return nil
+		}
+		subnetsByZone[subnet.AvailabilityZone] = append(subnetsByZone[subnet.AvailabilityZone], id)
+	}
+
+	previous := map[string]expinfrav1.EvacuatedAvailabilityZone{}
+	for _, zone := range awsMachinePool.Status.EvacuatedAvailabilityZones {
+		previous[zone.Name] = zone
+	}
+	surge := awsMachinePool.GetAnnotations()[expinfrav1.AZEvacuateSurgeAnnotation] == "true"
+
+	var evacuated []expinfrav1.EvacuatedAvailabilityZone
+	for _, name := range requested {
+		subnets, ok := subnetsByZone[name]
+		if !ok {
+			machinePoolScope.Info("Ignoring availability zone without subnets of the ASG", "annotation", expinfrav1.AZEvacuateAnnotation, "availabilityZone", name)
+			continue
+		}
+		zone := expinfrav1.EvacuatedAvailabilityZone{Name: name, Subnets: subnets}
+		// The surge is computed once, when the evacuation starts, as the instances leave the zone afterwards.
+		if prev, ok := previous[name]; ok {
+			zone.Surge = prev.Surge
+		} else if surge {
+			for _, instance := range asg.Instances {
+				if instance.AvailabilityZone == name {
+					zone.Surge++
+				}
+			}
+		}
+		evacuated = append(evacuated, zone)
+	}
+
+	// The previous evacuation is kept when no availability zone would remain.
+	if len(evacuated) > 0 && len(evacuated) == len(subnetsByZone) {
+		if conditions.GetReason(awsMachinePool, expinfrav1.AvailabilityZonesEvacuatedCondition) != expinfrav1.AZEvacuationRefusedReason {
+			r.Recorder.Eventf(awsMachinePool, corev1.EventTypeWarning, expinfrav1.AZEvacuationRefusedReason,
+				"Refusing to evacuate availability zones %s, which would leave the ASG without subnets", strings.Join(requested, ", "))
+		}
+		conditions.MarkFalse(awsMachinePool, expinfrav1.AvailabilityZonesEvacuatedCondition, expinfrav1.AZEvacuationRefusedReason, clusterv1.ConditionSeverityWarning,
+			"Evacuating availability zones %s would leave the ASG without subnets", strings.Join(requested, ", "))
+		return nil
+	}
+
+	r.setEvacuatedZones(machinePoolScope, evacuated)
+	if len(evacuated) == 0 {
+		conditions.Delete(awsMachinePool, expinfrav1.AvailabilityZonesEvacuatedCondition)
+		return nil
+	}
+	conditions.MarkTrue(awsMachinePool, expinfrav1.AvailabilityZonesEvacuatedCondition)
+	return nil
+}
+
+// setEvacuatedZones records the evacuated availability zones in the AWSMachinePool status, with an event for each
+// availability zone evacuated or restored.
+func (r *AWSMachinePoolReconciler) setEvacuatedZones(machinePoolScope *scope.MachinePoolScope, evacuated []expinfrav1.EvacuatedAvailabilityZone) {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+
+	names := sets.New[string]()
+	for _, zone := range evacuated {
+		names.Insert(zone.Name)
+	}
+	previous := sets.New[string]()
+	for _, zone := range awsMachinePool.Status.EvacuatedAvailabilityZones {
+		previous.Insert(zone.Name)
+		if !names.Has(zone.Name) {
+			machinePoolScope.Info("Restoring availability zone", "availabilityZone", zone.Name, "subnets", zone.Subnets)
+			r.Recorder.Eventf(awsMachinePool, corev1.EventTypeNormal, "AZRestored",
+				"Restoring subnets %s of availability zone %s to the ASG", strings.Join(zone.Subnets, ","), zone.Name)
+		}
+	}
+	for _, zone := range evacuated {
+		if previous.Has(zone.Name) {
+			continue
+		}
+		machinePoolScope.Info("Evacuating availability zone", "availabilityZone", zone.Name, "subnets", zone.Subnets, "surge", zone.Surge)
+		r.Recorder.Eventf(awsMachinePool, corev1.EventTypeNormal, "AZEvacuated",
+			"Evacuating availability zone %s, removing subnets %s from the ASG and raising its desired capacity by %d", zone.Name, strings.Join(zone.Subnets, ","), zone.Surge)
+	}
+
+	awsMachinePool.Status.EvacuatedAvailabilityZones = evacuated
+}
+
+// evacuatedZoneNames returns the sorted availability zones of the comma-separated value of the
+// aws.cluster.x-k8s.io/az-evacuate annotation.
+func evacuatedZoneNames(value string) []string {
+	names := sets.New[string]()
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names.Insert(name)
+		}
+	}
+	return sets.List(names)
+}
+
+// reconcilePendingLifecycleActions surfaces the instances held in a wait state by a lifecycle hook in the
+// AWSMachinePool status, so that instances stuck waiting on an external system can be noticed.
+func (r *AWSMachinePoolReconciler) reconcilePendingLifecycleActions(machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface, asg *expinfrav1.AutoScalingGroup) error {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+
+	waiting := false
+	for _, instance := range asg.Instances {
+		if instance.State == expinfrav1.InstanceStatePendingWait || instance.State == expinfrav1.InstanceStateTerminatingWait {
+			waiting = true
+			break
+		}
+	}
+	if !waiting {
+		awsMachinePool.Status.PendingLifecycleActions = nil
+		return nil
+	}
+
+	hooks, err := asgsvc.DescribeLifecycleHooks(asg.Name)
+	if err != nil {
+		return err
+	}
+
+	awsMachinePool.Status.PendingLifecycleActions = pendingLifecycleActions(asg.Instances, hooks, awsMachinePool.Status.PendingLifecycleActions, metav1.Now())
+	return nil
+}
+
+// reconcileInstanceRefreshStatus updates the status of the last instance refresh started by CAPA and the
+// InstanceRefreshReady condition until the instance refresh completes. A running instance refresh is
+// checked again after instanceRefreshPollInterval.
+func (r *AWSMachinePoolReconciler) reconcileInstanceRefreshStatus(machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface) (ctrl.Result, error) {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	previous := awsMachinePool.Status.InstanceRefresh
+	if previous == nil || !previous.IsActive() {
+		return ctrl.Result{}, nil
+	}
+
+	refresh, err := asgsvc.DescribeInstanceRefresh(machinePoolScope.ASGName(), previous.ID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	awsMachinePool.Status.InstanceRefresh = refresh
+
+	switch {
+	case refresh.IsActive():
+		conditions.MarkFalse(awsMachinePool, expinfrav1.InstanceRefreshReadyCondition, expinfrav1.InstanceRefreshInProgressReason, clusterv1.ConditionSeverityInfo,
+			"Instance refresh %s is %s: %d%% complete, %d instances to update", refresh.ID, refresh.Status, refresh.PercentageComplete, refresh.InstancesToUpdate)
+		return ctrl.Result{RequeueAfter: instanceRefreshPollInterval}, nil
+	case refresh.IsSuccessful():
+		conditions.MarkTrue(awsMachinePool, expinfrav1.InstanceRefreshReadyCondition)
+		r.Recorder.Eventf(awsMachinePool, corev1.EventTypeNormal, "InstanceRefreshSucceeded", "Instance refresh %s succeeded", refresh.ID)
+	default:
+		conditions.MarkFalse(awsMachinePool, expinfrav1.InstanceRefreshReadyCondition, expinfrav1.InstanceRefreshUnsuccessfulReason, clusterv1.ConditionSeverityWarning,
+			"Instance refresh %s is %s: %s", refresh.ID, refresh.Status, refresh.StatusReason)
+		r.Recorder.Eventf(awsMachinePool, corev1.EventTypeWarning, "InstanceRefreshUnsuccessful", "Instance refresh %s is %s: %s", refresh.ID, refresh.Status, refresh.StatusReason)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// cancelOutdatedInstanceRefresh cancels the instance refresh started by CAPA for a version of the launch template
+// which is outdated by a change of the spec, unless the cancellation is disabled. An instance refresh which wasn't
+// started by CAPA is left alone.
+func (r *AWSMachinePoolReconciler) cancelOutdatedInstanceRefresh(machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface) error {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	if awsMachinePool.Spec.RefreshPreferences != nil && awsMachinePool.Spec.RefreshPreferences.DisableInstanceRefreshCancellation {
+		machinePoolScope.Debug("instance refresh cancellation disabled, waiting for the instance refresh to complete")
+		return nil
+	}
+
+	refresh := awsMachinePool.Status.InstanceRefresh
+	if refresh == nil || !refresh.IsActive() || refresh.IsCancelling() {
+		return nil
+	}
+
+	machinePoolScope.Info("cancelling instance refresh for an outdated launch template", "id", refresh.ID)
+	if err := asgsvc.CancelASGInstanceRefresh(machinePoolScope); err != nil {
+		r.Recorder.Eventf(awsMachinePool, corev1.EventTypeWarning, "FailedInstanceRefreshCancel", "Failed to cancel instance refresh %s: %v", refresh.ID, err)
+		return err
+	}
+	r.Recorder.Eventf(awsMachinePool, corev1.EventTypeNormal, "InstanceRefreshCancelling",
+		"Cancelling instance refresh %s because the launch template changed, a new instance refresh will start once it is cancelled", refresh.ID)
+	conditions.MarkFalse(awsMachinePool, expinfrav1.InstanceRefreshReadyCondition, expinfrav1.InstanceRefreshCancellingReason, clusterv1.ConditionSeverityInfo,
+		"Instance refresh %s is being cancelled because the launch template changed", refresh.ID)
+
+	return nil
+}
+
+// reconcileAutoscaling tags the Auto Scaling group for the auto-discovery of the cluster autoscaler when the
+// autoscaling of the AWSMachinePool is enabled. Once it is disabled, the tags are removed and the replicas are
+// given back to the MachinePool, unless they were handed over to an external autoscaler by other means.
+func (r *AWSMachinePoolReconciler) reconcileAutoscaling(ctx context.Context, machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface, asg *expinfrav1.AutoScalingGroup, clusterName string) error {
+	enabled := machinePoolScope.AWSMachinePool.Spec.Autoscaling.IsEnabled()
+	if !enabled && !machinePoolScope.AutoscalingManagesReplicas() {
+		return nil
+	}
+
+	create, remove := infrav1.ClusterAutoscalerTagUpdates(clusterName, asg.Tags, machinePoolScope.AdditionalTags(), enabled)
+	if len(create) > 0 || len(remove) > 0 {
+		if err := asgsvc.UpdateResourceTags(ptr.To(asg.Name), create, remove); err != nil {
+			return err
+		}
+		if enabled {
+			r.Recorder.Event(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "AutoscalingEnabled", "Tagged the Auto Scaling group for the cluster autoscaler")
+		} else {
+			r.Recorder.Event(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "AutoscalingDisabled", "Removed the cluster autoscaler tags from the Auto Scaling group")
+		}
+	}
+
+	return machinePoolScope.SetAutoscalingManagesReplicas(ctx, enabled)
+}
+
+// reconcileDetachedInstances detaches the instances listed by the DetachInstancesAnnotation of the AWSMachinePool,
+// or whose Machine is being deleted and is annotated with the DetachInstanceAnnotation, from the Auto Scaling group
+// instead of letting them be terminated. The instances are tagged with the name of the group first, so that they
+// can be told apart once detached. The desired capacity is left unchanged, so the group launches replacements. The
+// detached instances are removed from asg.Instances.
+func (r *AWSMachinePoolReconciler) reconcileDetachedInstances(ctx context.Context, machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface, ec2Svc services.EC2Interface, asg *expinfrav1.AutoScalingGroup) error {
+	instanceIDs, err := machinePoolScope.InstancesToDetach(ctx, asg.Instances)
+	if err != nil {
+		return err
+	}
+	if len(instanceIDs) == 0 {
+		return nil
+	}
+
+	for _, id := range instanceIDs {
+		if err := ec2Svc.UpdateResourceTags(ptr.To(id), map[string]string{infrav1.DetachedByCAPATagKey: asg.Name}, nil); err != nil {
+			return errors.Wrapf(err, "failed to tag instance %q", id)
+		}
+	}
+
+	machinePoolScope.Info("Detaching instances from the Auto Scaling group", "asgName", asg.Name, "instances", instanceIDs)
+	if err := asgsvc.DetachInstances(asg.Name, instanceIDs); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "DetachedInstances", "Detached instances %v from Auto Scaling group %s", instanceIDs, asg.Name)
+
+	detached := sets.New(instanceIDs...)
+	instances := asg.Instances[:0]
+	for _, instance := range asg.Instances {
+		if !detached.Has(instance.ID) {
+			instances = append(instances, instance)
+		}
+	}
+	asg.Instances = instances
+
+	return nil
+}
+
+// reconcileScaleInProtection protects the instances whose Machine or Node is annotated with the
+// ScaleInProtectionAnnotation from scale in, and removes the protection once the annotation is removed or the
+// Machine is being deleted. Only the protection set by CAPA is removed: instances that were already protected
+// by other means are left alone, and the protection of instances excluded from instance refresh is kept.
+// AWS takes up to 50 instances per request, so SetInstanceProtection batches them.
+func (r *AWSMachinePoolReconciler) reconcileScaleInProtection(machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface, asg *expinfrav1.AutoScalingGroup, nodeStatusByProviderID map[string]*scope.NodeStatus) error {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	previous := sets.New(awsMachinePool.Status.ScaleInProtectedInstances...)
+	// The instances protected by CAPA to exclude them from instance refresh are taken over.
+	managed := previous.Union(sets.New(awsMachinePool.Status.RefreshExcludedInstances...))
+	protected := sets.New(asg.ProtectedInstances...)
+
+	var tracked, toProtect, toUnprotect []string
+	for _, instance := range asg.Instances {
+		nodeStatus, ok := nodeStatusByProviderID[fmt.Sprintf("aws:////%s", instance.ID)]
+		wanted := ok && nodeStatus.ScaleInProtection
+		switch {
+		case wanted && !protected.Has(instance.ID):
+			toProtect = append(toProtect, instance.ID)
+			tracked = append(tracked, instance.ID)
+		case wanted && managed.Has(instance.ID):
+			tracked = append(tracked, instance.ID)
+		case previous.Has(instance.ID) && protected.Has(instance.ID) && ok && nodeStatus.ExcludeFromRefresh:
+			tracked = append(tracked, instance.ID)
+		case previous.Has(instance.ID) && protected.Has(instance.ID):
+			toUnprotect = append(toUnprotect, instance.ID)
+		}
+	}
+
+	log := machinePoolScope.WithValues("asgName", asg.Name)
+	if len(toProtect) > 0 {
+		log.Info("Protecting annotated instances from scale in", "instances", toProtect)
+		if err := asgsvc.SetInstanceProtection(asg.Name, toProtect, true); err != nil {
+			return err
+		}
+	}
+	// Keep track of the instances still protected by CAPA until their protection is removed.
+	awsMachinePool.Status.ScaleInProtectedInstances = append(append([]string(nil), tracked...), toUnprotect...)
+	sort.Strings(awsMachinePool.Status.ScaleInProtectedInstances)
+
+	if len(toUnprotect) > 0 {
+		log.Info("Removing the scale-in protection of instances no longer annotated", "instances", toUnprotect)
+		if err := asgsvc.SetInstanceProtection(asg.Name, toUnprotect, false); err != nil {
+			return err
+		}
+	}
+	sort.Strings(tracked)
+	awsMachinePool.Status.ScaleInProtectedInstances = tracked
+
+	return nil
+}
+
+// reconcileRefreshExclusions protects the instances whose Node is annotated with the ExcludeFromRefreshAnnotation
+// from scale in, so that instance refreshes skip them, and removes the protection once the annotation is removed.
+// Only the protection set by CAPA is removed: instances that were already protected by other means are left alone,
+// and the protection of instances protected from scale in by their annotation is kept.
+func (r *AWSMachinePoolReconciler) reconcileRefreshExclusions(machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface, asg *expinfrav1.AutoScalingGroup, nodeStatusByProviderID map[string]*scope.NodeStatus) error {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	previous := sets.New(awsMachinePool.Status.RefreshExcludedInstances...)
+	scaleInProtected := sets.New(awsMachinePool.Status.ScaleInProtectedInstances...)
+	protected := sets.New(asg.ProtectedInstances...)
+
+	var excluded, toProtect, toUnprotect []string
+	for _, instance := range asg.Instances {
+		nodeStatus, ok := nodeStatusByProviderID[fmt.Sprintf("aws:////%s", instance.ID)]
+		annotated := ok && nodeStatus.ExcludeFromRefresh
+		switch {
+		case annotated && !protected.Has(instance.ID):
+			toProtect = append(toProtect, instance.ID)
+			excluded = append(excluded, instance.ID)
+		case annotated && (previous.Has(instance.ID) || scaleInProtected.Has(instance.ID)):
+			excluded = append(excluded, instance.ID)
+		case !annotated && previous.Has(instance.ID) && protected.Has(instance.ID) && !scaleInProtected.Has(instance.ID):
+			toUnprotect = append(toUnprotect, instance.ID)
+		}
+	}
+
+	log := machinePoolScope.WithValues("asgName", asg.Name)
+	if len(toProtect) > 0 {
+		log.Info("Protecting instances excluded from instance refresh from scale in", "instances", toProtect)
+		if err := asgsvc.SetInstanceProtection(asg.Name, toProtect, true); err != nil {
+			return err
+		}
+	}
+	// Keep track of the instances still protected by CAPA until their protection is removed.
+	awsMachinePool.Status.RefreshExcludedInstances = append(append([]string(nil), excluded...), toUnprotect...)
+	sort.Strings(awsMachinePool.Status.RefreshExcludedInstances)
+
+	if len(toUnprotect) > 0 {
+		log.Info("Removing the scale in protection of instances no longer excluded from instance refresh", "instances", toUnprotect)
+		if err := asgsvc.SetInstanceProtection(asg.Name, toUnprotect, false); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(awsMachinePool, corev1.EventTypeNormal, "InstanceRefreshExclusionRemoved",
+			"Instances %s are no longer excluded from instance refresh, they are replaced by the next instance refresh", strings.Join(toUnprotect, ", "))
+	}
+	sort.Strings(excluded)
+	awsMachinePool.Status.RefreshExcludedInstances = excluded
+
+	return nil
+}
+
+// refreshExclusionWarning returns the warning emitted when an instance refresh starts while instances of the
+// Auto Scaling group are excluded from it.
+func refreshExclusionWarning(excluded []string, instances int) string {
+	if len(excluded) >= instances {
+		return fmt.Sprintf("All instances are excluded from the instance refresh by the %s annotation of their Nodes, "+
+			"the instance refresh can't replace any instance until the annotation is removed", expinfrav1.ExcludeFromRefreshAnnotation)
+	}
+	return fmt.Sprintf("Instances %s are excluded from the instance refresh by the %s annotation of their Nodes "+
+		"and keep running the previous launch template version, they are only replaced by an instance refresh started after the annotation is removed",
+		strings.Join(excluded, ", "), expinfrav1.ExcludeFromRefreshAnnotation)
+}
+
+// pendingLifecycleActions returns a pending lifecycle action for each instance in a wait state and each
+// lifecycle hook of the matching transition. The ASG does not report which of the hooks of a transition
+// are still outstanding, nor since when, so the start time is carried over from the previous actions.
+func pendingLifecycleActions(instances []infrav1.Instance, hooks []*infrav1.AWSLifecycleHook, previous []expinfrav1.PendingLifecycleAction, now metav1.Time) []expinfrav1.PendingLifecycleAction {
+	type actionKey struct {
+		instanceID string
+		hookName   string
+	}
+	startTimes := make(map[actionKey]metav1.Time, len(previous))
+	for _, action := range previous {
+		startTimes[actionKey{instanceID: action.InstanceID, hookName: action.HookName}] = action.StartTime
+	}
+
+	var actions []expinfrav1.PendingLifecycleAction
+	for _, instance := range instances {
+		var transition infrav1.LifecycleTransition
+		switch instance.State {
+		case expinfrav1.InstanceStatePendingWait:
+			transition = infrav1.LifecycleTransitionInstanceLaunch
+		case expinfrav1.InstanceStateTerminatingWait:
+			transition = infrav1.LifecycleTransitionInstanceTerminate
+		default:
+			continue
+		}
+
+		for _, hook := range hooks {
+			if hook.LifecycleTransition != transition {
+				continue
+			}
+			startTime, ok := startTimes[actionKey{instanceID: instance.ID, hookName: hook.Name}]
+			if !ok {
+				startTime = now
+			}
+			actions = append(actions, expinfrav1.PendingLifecycleAction{
+				InstanceID: instance.ID,
+				HookName:   hook.Name,
+				Transition: transition,
+				StartTime:  startTime,
+			})
+		}
+	}
+
+	return actions
+}
+
+// dominantScalingError returns the most frequent status message of the scaling activities that failed since the given time.
+func dominantScalingError(activities []*expinfrav1.ScalingActivity, since time.Time) string {
+	counts := map[string]int{}
+	failed := 0
+	dominant := ""
+	for _, activity := range activities {
+		if activity.StatusCode != expinfrav1.ScalingActivityStatusFailed || activity.StatusMessage == "" {
+			continue
+		}
+		if activity.StartTime == nil || activity.StartTime.Time.Before(since) {
+			continue
+		}
+		failed++
+		counts[activity.StatusMessage]++
+		if counts[activity.StatusMessage] > counts[dominant] {
+			dominant = activity.StatusMessage
+		}
+	}
+
+	if dominant == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s (%d of %d failed scaling activities)", dominant, counts[dominant], failed)
+}
+
+// usesSpotInstances returns true if the AWSMachinePool can launch spot instances.
+func usesSpotInstances(awsMachinePool *expinfrav1.AWSMachinePool) bool {
+	if awsMachinePool.Spec.AWSLaunchTemplate.SpotMarketOptions != nil {
+		return true
+	}
+
+	mixedInstancesPolicy := awsMachinePool.Spec.MixedInstancesPolicy
+	if mixedInstancesPolicy == nil || mixedInstancesPolicy.InstancesDistribution == nil {
+		return false
+	}
+
+	// AWS defaults OnDemandPercentageAboveBaseCapacity to 100, i.e. on-demand only.
+	onDemandPercentage := mixedInstancesPolicy.InstancesDistribution.OnDemandPercentageAboveBaseCapacity
+	return onDemandPercentage != nil && *onDemandPercentage < 100
+}
+
+// setVersionStatus records the Kubernetes version of the MachinePool, and the Kubernetes version the AMI of the
+// launch template was looked up for, which differ while the AMI version override is set.
+func setVersionStatus(machinePoolScope *scope.MachinePoolScope) {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+	awsMachinePool.Status.Version = machinePoolScope.MachinePool.Spec.Template.Spec.Version
+	awsMachinePool.Status.AMIKubernetesVersion = nil
+
+	launchTemplate := awsMachinePool.Spec.AWSLaunchTemplate
+	if launchTemplate.ExistingTemplate != nil || launchTemplate.AMI.ID != nil {
+		return
+	}
+	// The launch template was reconciled, so the AMI version override is valid.
+	if amiVersion, err := machinePoolScope.GetAMILookupVersion(); err == nil {
+		awsMachinePool.Status.AMIKubernetesVersion = amiVersion
+	}
+}
+
+// reconcileWorkloadCluster runs the steps of the reconciliation which depend on the Nodes of the instances
+// of the Auto Scaling group: the versions of the instances, the instances excluded from instance refresh, and the
+// completion of the managed launch lifecycle hook. When the Nodes can't be listed from the workload cluster,
+// e.g. because its API server is unreachable, these steps are skipped and the WorkloadClusterReachable
+// condition is set to False. Instances held by the managed launch lifecycle hook are retried shortly.
+func (r *AWSMachinePoolReconciler) reconcileWorkloadCluster(ctx context.Context, machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface, asg *expinfrav1.AutoScalingGroup, activities []*expinfrav1.ScalingActivity) (ctrl.Result, error) {
+	providerIDs := make([]string, len(asg.Instances))
+	for i, instance := range asg.Instances {
+		providerIDs[i] = fmt.Sprintf("aws:////%s", instance.ID)
+	}
+
+	nodeStatusByProviderID, err := machinePoolScope.GetNodeStatusByProviderID(ctx, providerIDs)
+	if err != nil {
+		err = errors.Wrap(err, "failed to get node status by provider id")
+		machinePoolScope.Error(err, "workload cluster is unreachable, skipping the reconciliation of the nodes")
+		conditions.MarkFalse(machinePoolScope.AWSMachinePool, expinfrav1.WorkloadClusterReachableCondition, expinfrav1.WorkloadClusterUnreachableReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
+		if machinePoolScope.AWSMachinePool.Spec.ManagedLaunchLifecycleHook.IsEnabled() && hasPendingWaitInstances(asg) {
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		return ctrl.Result{}, nil
+	}
+	conditions.MarkTrue(machinePoolScope.AWSMachinePool, expinfrav1.WorkloadClusterReachableCondition)
+
+	machinePoolScope.UpdateInstanceVersions(nodeStatusByProviderID)
+	recordInstanceProvisioning(machinePoolScope.AWSMachinePool, activities, nodeStatusByProviderID)
+	if err := machinePoolScope.UpdateMachineScaleInProtection(ctx, nodeStatusByProviderID); err != nil {
+		machinePoolScope.Error(err, "failed to get the scale-in protection of the Machines, skipping the reconciliation of the scale-in protection")
+	} else if err := r.reconcileScaleInProtection(machinePoolScope, asgsvc, asg, nodeStatusByProviderID); err != nil {
+		machinePoolScope.Error(err, "failed updating the scale-in protection of instances")
+	}
+	if err := r.reconcileRefreshExclusions(machinePoolScope, asgsvc, asg, nodeStatusByProviderID); err != nil {
+		machinePoolScope.Error(err, "failed updating instances excluded from instance refresh")
+	}
+
+	return r.reconcileManagedLaunchLifecycleHook(machinePoolScope, asgsvc, asg, nodeStatusByProviderID)
+}
+
+// launchActivityDescription matches the description of the scaling activities launching an instance.
+var launchActivityDescription = regexp.MustCompile(`^Launching a new EC2 instance: (i-[0-9a-f]+)`)
+
+// recordInstanceProvisioning records, in the statuses of the instances, the start time of the scaling activity
+// which launched them and the time their Node registered, and observes the time the Nodes took to register. The
+// times are only recorded once, and the registration of Nodes whose launch time isn't known isn't recorded, as
+// the scaling activities only go back so far.
+func recordInstanceProvisioning(awsMachinePool *expinfrav1.AWSMachinePool, activities []*expinfrav1.ScalingActivity, nodeStatusByProviderID map[string]*scope.NodeStatus) {
+	launchedAt := map[string]*metav1.Time{}
+	for _, activity := range activities {
+		if match := launchActivityDescription.FindStringSubmatch(activity.Description); match != nil && activity.StartTime != nil {
+			launchedAt[match[1]] = activity.StartTime
+		}
+	}
+
+	for i := range awsMachinePool.Status.Instances {
+		instance := &awsMachinePool.Status.Instances[i]
+		if instance.LaunchedAt == nil {
+			instance.LaunchedAt = launchedAt[instance.InstanceID].DeepCopy()
+		}
+		if instance.LaunchedAt == nil || instance.NodeRegisteredAt != nil {
+			continue
+		}
+		nodeStatus, ok := nodeStatusByProviderID[fmt.Sprintf("aws:////%s", instance.InstanceID)]
+		if !ok || nodeStatus.RegisteredAt.IsZero() {
+			continue
+		}
+		instance.NodeRegisteredAt = nodeStatus.RegisteredAt.DeepCopy()
+		metrics.ObserveMachinePoolInstanceProvisionPhase(metrics.MachinePoolInstanceProvisionPhaseNodeRegistration, instance.NodeRegisteredAt.Sub(instance.LaunchedAt.Time))
+	}
+}
+
+// hasPendingWaitInstances returns true if an instance of the Auto Scaling group is held by a launch lifecycle hook.
+func hasPendingWaitInstances(asg *expinfrav1.AutoScalingGroup) bool {
+	for _, instance := range asg.Instances {
+		if instance.State == expinfrav1.InstanceStatePendingWait {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileManagedLaunchLifecycleHook completes the lifecycle action of the managed launch lifecycle hook
+// for every instance held in Pending:Wait whose Node is Ready. Instances whose Node doesn't become Ready
+// before the hook times out are abandoned by AWS and replaced. An instance may still wait on another launch
+// hook, or wait briefly after its lifecycle action was completed, which AWS rejects and is ignored.
+func (r *AWSMachinePoolReconciler) reconcileManagedLaunchLifecycleHook(machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface, group *expinfrav1.AutoScalingGroup, nodeStatusByProviderID map[string]*scope.NodeStatus) (ctrl.Result, error) {
+	if !machinePoolScope.AWSMachinePool.Spec.ManagedLaunchLifecycleHook.IsEnabled() {
+		return ctrl.Result{}, nil
+	}
+
+	log := machinePoolScope.WithValues("asgName", group.Name, "lifecycleHookName", expinfrav1.ManagedLaunchLifecycleHookName, "lifecycleTransition", infrav1.LifecycleTransitionInstanceLaunch)
+	waiting := 0
+	var errs []error
+	for _, instance := range group.Instances {
+		if instance.State != expinfrav1.InstanceStatePendingWait {
+			continue
+		}
+		if nodeStatus, ok := nodeStatusByProviderID[fmt.Sprintf("aws:////%s", instance.ID)]; !ok || !nodeStatus.Ready {
+			waiting++
+			continue
+		}
+
+		log.Info("Node is ready, completing launch lifecycle action", "instance", instance.ID)
+		if err := asgsvc.CompleteLifecycleAction(group.Name, expinfrav1.ManagedLaunchLifecycleHookName, instance.ID, "", infrav1.LifecycleHookDefaultResultContinue); err != nil {
+			if asg.IsNoActiveLifecycleActionError(err) {
+				log.Debug("Instance has no pending lifecycle action for the launch lifecycle hook", "instance", instance.ID)
+				continue
+			}
+			r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedCompleteLifecycleAction", "Failed to complete launch lifecycle action for instance %s: %v", instance.ID, err)
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return ctrl.Result{}, kerrors.NewAggregate(errs)
+	}
+
+	if waiting > 0 {
+		log.Debug("Waiting for nodes to become ready before completing launch lifecycle actions", "instances", waiting)
+		return ctrl.Result{RequeueAfter: managedLaunchLifecycleHookRequeueAfter}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *AWSMachinePoolReconciler) createPool(machinePoolScope *scope.MachinePoolScope, clusterScope cloud.ClusterScoper) error {
+	clusterScope.Info("Initializing ASG client")
+
+	asgsvc := r.getASGService(clusterScope)
+
+	machinePoolScope.Info("Creating Autoscaling Group")
+	if _, err := asgsvc.CreateASG(machinePoolScope); err != nil {
+		return errors.Wrapf(err, "failed to create AWSMachinePool")
+	}
+	// Recorded right away, as the name of the group can't be changed anymore once it exists.
+	machinePoolScope.AWSMachinePool.Status.ASGName = machinePoolScope.ASGName()
+
+	return nil
+}
+
+// recordReplacedLaunchTemplate records the launch template managed by CAPA in the status once an existing launch
+// template replaces it, so that it is deleted after the ASG has been switched to the existing launch template.
+func (r *AWSMachinePoolReconciler) recordReplacedLaunchTemplate(machinePoolScope *scope.MachinePoolScope, ec2Svc services.EC2Interface) error {
+	existingTemplate := machinePoolScope.AWSMachinePool.Spec.AWSLaunchTemplate.ExistingTemplate
+	status := &machinePoolScope.AWSMachinePool.Status
+	if existingTemplate == nil || status.LaunchTemplateID == "" || status.LaunchTemplateID == existingTemplate.ID || status.ReplacedLaunchTemplateID != "" {
+		return nil
+	}
+
+	launchTemplateID, err := ec2Svc.GetLaunchTemplateID(machinePoolScope.LaunchTemplateName())
+	if err != nil {
+		return err
+	}
+	// The previous launch template may have been an existing one too.
+	if launchTemplateID == "" || launchTemplateID == existingTemplate.ID {
+		return nil
+	}
+
+	status.ReplacedLaunchTemplateID = launchTemplateID
+	r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "LaunchTemplateReplaced",
+		"Launch template %s is replaced by existing launch template %s, it will be deleted once the ASG uses the existing launch template", launchTemplateID, existingTemplate.ID)
+	return nil
+}
+
+// deleteReplacedLaunchTemplate deletes the launch template managed by CAPA which an existing launch template
+// replaced, once the ASG uses the existing launch template.
+func (r *AWSMachinePoolReconciler) deleteReplacedLaunchTemplate(machinePoolScope *scope.MachinePoolScope, ec2Svc services.EC2Interface, asg *expinfrav1.AutoScalingGroup) error {
+	existingTemplate := machinePoolScope.AWSMachinePool.Spec.AWSLaunchTemplate.ExistingTemplate
+	replacedID := machinePoolScope.AWSMachinePool.Status.ReplacedLaunchTemplateID
+	if replacedID == "" || existingTemplate == nil || asg.LaunchTemplateID != existingTemplate.ID {
+		return nil
+	}
+
+	machinePoolScope.Info("deleting replaced launch template", "id", replacedID)
+	if err := ec2Svc.DeleteLaunchTemplate(replacedID); err != nil {
+		r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeWarning, "FailedDelete", "Failed to delete replaced launch template %s: %v", replacedID, err)
+		return errors.Wrap(err, "failed to delete replaced launch template")
+	}
+	r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "DeletedLaunchTemplate", "Deleted launch template %s replaced by existing launch template %s", replacedID, existingTemplate.ID)
+	machinePoolScope.AWSMachinePool.Status.ReplacedLaunchTemplateID = ""
+
+	return nil
+}
+
+// reconcileAdoption checks that the Auto Scaling group can be managed by the AWSMachinePool. A group managed by
+// another AWSMachinePool, according to its MachinePoolTagKey tag, is never taken over. A group named by
+// spec.autoScalingGroupName which isn't owned by the cluster is adopted by tagging it as owned and managed by
+// the AWSMachinePool, as long as the AWSMachinePool has the AdoptAutoScalingGroupAnnotation and the group isn't
+// owned by another cluster. The other tags of the cluster are applied with the rest of the tags later in the
+// reconciliation.
+func (r *AWSMachinePoolReconciler) reconcileAdoption(machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface, asg *expinfrav1.AutoScalingGroup, clusterName string) error {
+	if owner := asgOwnerMachinePool(asg.Tags, machinePoolScope.NamespacedName()); owner != "" {
+		return errors.Errorf("ASG %q is managed by AWSMachinePool %q and can't be adopted", asg.Name, owner)
+	}
+	if machinePoolScope.AWSMachinePool.Spec.AutoScalingGroupName == "" || asg.Tags.HasOwned(clusterName) {
+		return nil
+	}
+
+	if owner := asgOwnerCluster(asg.Tags, clusterName); owner != "" {
+		return errors.Errorf("ASG %q is owned by cluster %q and can't be adopted", asg.Name, owner)
+	}
+	if _, ok := machinePoolScope.AWSMachinePool.Annotations[expinfrav1.AdoptAutoScalingGroupAnnotation]; !ok {
+		return errors.Errorf("ASG %q already exists and isn't owned by the cluster, set the %s annotation to adopt it", asg.Name, expinfrav1.AdoptAutoScalingGroupAnnotation)
+	}
+
+	machinePoolScope.Info("Adopting existing ASG", "name", asg.Name)
+	ownerTag := infrav1.Tags{
+		infrav1.ClusterTagKey(clusterName): string(infrav1.ResourceLifecycleOwned),
+		infrav1.MachinePoolTagKey:          machinePoolScope.NamespacedName(),
+	}
+	if err := asgsvc.UpdateResourceTags(ptr.To(asg.Name), ownerTag, nil); err != nil {
+		return errors.Wrapf(err, "failed to adopt ASG %q", asg.Name)
+	}
+	if asg.Tags == nil {
+		asg.Tags = infrav1.Tags{}
+	}
+	asg.Tags.Merge(ownerTag)
+	r.Recorder.Eventf(machinePoolScope.AWSMachinePool, corev1.EventTypeNormal, "AdoptedASG", "Adopted existing ASG %q", asg.Name)
+
+	return nil
+}
+
+// asgOwnerCluster returns the name of the cluster, other than the given one, which owns an Auto Scaling group
+// according to its tags, or an empty string if there is none.
+func asgOwnerCluster(tags infrav1.Tags, clusterName string) string {
+	for key, value := range tags {
+		if infrav1.ResourceLifecycle(value) != infrav1.ResourceLifecycleOwned {
+			continue
+		}
+		for _, prefix := range []string{infrav1.NameAWSProviderOwned, infrav1.NameKubernetesAWSCloudProviderPrefix} {
+			if owner, ok := strings.CutPrefix(key, prefix); ok && owner != clusterName {
+				return owner
+			}
+		}
+	}
+	return ""
+}
+
+// asgOwnerMachinePool returns the `<namespace>/<name>` of the AWSMachinePool, other than the given one, which
+// manages an Auto Scaling group according to its tags, or an empty string if there is none.
+func asgOwnerMachinePool(tags infrav1.Tags, machinePool string) string {
+	if owner := tags[infrav1.MachinePoolTagKey]; owner != machinePool {
+		return owner
+	}
+	return ""
+}
+
+// ownershipTags returns the tags of an Auto Scaling group which mark it as owned by the cluster and managed by
+// an AWSMachinePool.
+func ownershipTags(tags infrav1.Tags, clusterName string) infrav1.Tags {
+	res := infrav1.Tags{}
+	for _, key := range []string{infrav1.ClusterTagKey(clusterName), infrav1.ClusterAWSCloudProviderTagKey(clusterName), infrav1.MachinePoolTagKey} {
+		if value, ok := tags[key]; ok {
+			res[key] = value
+		}
+	}
+	return res
+}
+
+func (r *AWSMachinePoolReconciler) findASG(machinePoolScope *scope.MachinePoolScope, asgsvc services.ASGInterface) (*expinfrav1.AutoScalingGroup, error) {
+	// Query the instance using tags.
+	asg, err := asgsvc.GetASGByName(machinePoolScope)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query AWSMachinePool by name")
+	}
+
+	return asg, nil
+}
+
+// diffASG compares incoming AWSMachinePool and compares against existing ASG.
+func diffASG(machinePoolScope *scope.MachinePoolScope, existingASG *expinfrav1.AutoScalingGroup) string {
+	machinePoolSpec := machinePoolScope.MachinePool.Spec.DeepCopy()
+	detectedMachinePoolSpec := machinePoolScope.MachinePool.Spec.DeepCopy()
+
+	managedMinSize, managedMaxSize, managedDesiredCapacity := machinePoolScope.ManagedSizes()
+	// The desired capacity of the ASG is left to the scheduled actions or the external autoscaler when there are some.
+	if managedDesiredCapacity {
+		// During a scale-down limited by the scale-down policy, the ASG is expected at the desired capacity of the current
+		// step, and during the evacuation of availability zones at the replicas raised by the surge.
+		if machinePoolSpec.Replicas != nil {
+			machinePoolSpec.Replicas = ptr.To(machinePoolScope.DesiredCapacity())
+		}
+		detectedMachinePoolSpec.Replicas = existingASG.DesiredCapacity
+	}
+	if diff := cmp.Diff(*machinePoolSpec, *detectedMachinePoolSpec); diff != "" {
+		return diff
+	}
+
+	// The size of the Auto Scaling group is compared to the bounds of the autoscaling when it is enabled.
+	awsMachinePoolSpec := machinePoolScope.AWSMachinePool.Spec.DeepCopy()
+	awsMachinePoolSpec.MinSize, awsMachinePoolSpec.MaxSize = machinePoolScope.AWSMachinePool.SizeBounds()
+
+	detectedAWSMachinePoolSpec := awsMachinePoolSpec.DeepCopy()
+	detectedAWSMachinePoolSpec.MaxSize = existingASG.MaxSize
+	detectedAWSMachinePoolSpec.MinSize = existingASG.MinSize
+	// The same goes for the sizes which aren't managed by CAPA.
+	if !managedMinSize {
+		awsMachinePoolSpec.MinSize = existingASG.MinSize
+	}
+	if !managedMaxSize {
+		awsMachinePoolSpec.MaxSize = existingASG.MaxSize
+	}
+	detectedAWSMachinePoolSpec.CapacityRebalance = existingASG.CapacityRebalance
+	detectedAWSMachinePoolSpec.NewInstancesProtectedFromScaleIn = existingASG.NewInstancesProtectedFromScaleIn
+	// AWS evaluates the termination policies in order, so a different order is a drift. They are only
+	// compared when set, as the termination policies of the Auto Scaling group are left unchanged otherwise.
+	if len(awsMachinePoolSpec.TerminationPolicies) > 0 {
+		detectedAWSMachinePoolSpec.TerminationPolicies = existingASG.TerminationPolicies
+	}
+	if awsMachinePoolSpec.HealthCheckType != "" {
+		detectedAWSMachinePoolSpec.HealthCheckType = existingASG.HealthCheckType
+	}
+	if awsMachinePoolSpec.HealthCheckGracePeriod != nil {
+		detectedAWSMachinePoolSpec.HealthCheckGracePeriod = existingASG.HealthCheckGracePeriod
+	}
+	if awsMachinePoolSpec.DefaultInstanceWarmup != nil {
+		detectedAWSMachinePoolSpec.DefaultInstanceWarmup = existingASG.DefaultInstanceWarmup
+	}
+	// A max instance lifetime of 0 is the same as an unset one, which is how a disabled max instance
+	// lifetime of the Auto Scaling group is reported.
+	if lifetime := awsMachinePoolSpec.MaxInstanceLifetime; lifetime != nil && lifetime.Duration == 0 {
+		awsMachinePoolSpec.MaxInstanceLifetime = nil
+	}
+	detectedAWSMachinePoolSpec.MaxInstanceLifetime = existingASG.MaxInstanceLifetime
+	{
+		mixedInstancesPolicy := machinePoolScope.AWSMachinePool.Spec.MixedInstancesPolicy
+		// InstancesDistribution is optional, and the default values come from AWS, so
+		// they are not set by the AWSMachinePool defaulting webhook. If InstancesDistribution is
+		// not set, we use the AWS values for the purpose of comparison.
+		// The same goes for the fields of InstancesDistribution which are not set. The On-Demand capacities
+		// are pointers, as 0 is a meaningful value which must be compared to the one of the Auto Scaling group.
+		if mixedInstancesPolicy != nil && existingASG.MixedInstancesPolicy != nil {
+			mixedInstancesPolicy = mixedInstancesPolicy.DeepCopy()
+			mixedInstancesPolicy.InstancesDistribution = detectedInstancesDistribution(mixedInstancesPolicy.InstancesDistribution, existingASG.MixedInstancesPolicy.InstancesDistribution)
+		}
+
+		if !cmp.Equal(mixedInstancesPolicy, existingASG.MixedInstancesPolicy, cmpopts.EquateEmpty()) {
+			detectedAWSMachinePoolSpec.MixedInstancesPolicy = existingASG.MixedInstancesPolicy
+		}
+	}
+
+	return cmp.Diff(*awsMachinePoolSpec, *detectedAWSMachinePoolSpec)
+}
+
+// detectedInstancesDistribution returns the instances distribution of the spec, with the fields which
+// are not set taken from the instances distribution of the Auto Scaling group.
+func detectedInstancesDistribution(distribution, existing *expinfrav1.InstancesDistribution) *expinfrav1.InstancesDistribution {
+	if distribution == nil {
+		return existing
+	}
+	if existing == nil {
+		return distribution
+	}
+
+	res := distribution.DeepCopy()
+	if res.OnDemandAllocationStrategy == "" {
+		res.OnDemandAllocationStrategy = existing.OnDemandAllocationStrategy
+	}
+	if res.SpotAllocationStrategy == "" {
+		res.SpotAllocationStrategy = existing.SpotAllocationStrategy
+	}
+	if res.OnDemandBaseCapacity == nil {
+		res.OnDemandBaseCapacity = existing.OnDemandBaseCapacity
+	}
+	if res.OnDemandPercentageAboveBaseCapacity == nil {
+		res.OnDemandPercentageAboveBaseCapacity = existing.OnDemandPercentageAboveBaseCapacity
+	}
+	return res
+}
+
+// getOwnerMachinePool returns the MachinePool object owning the current resource.
+func getOwnerMachinePool(ctx context.Context, c client.Client, obj metav1.ObjectMeta) (*expclusterv1.MachinePool, error) {
+	for _, ref := range obj.OwnerReferences {
+		if ref.Kind != "MachinePool" {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if gv.Group == expclusterv1.GroupVersion.Group {
+			return getMachinePoolByName(ctx, c, obj.Namespace, ref.Name)
+		}
+	}
+	return nil, nil
+}
+
+// getMachinePoolByName finds and return a Machine object using the specified params.
 func getMachinePoolByName(ctx context.Context, c client.Client, namespace, name string) (*expclusterv1.MachinePool, error) {
 	m := &expclusterv1.MachinePool{}
 	key := client.ObjectKey{Name: name, Namespace: namespace}
@@ -582,6 +2714,75 @@ func getMachinePoolByName(ctx context.Context, c client.Client, namespace, name
 	return m, nil
 }
 
+// awsClusterToAWSMachinePoolMapFunc maps an AWSCluster to the AWSMachinePools of its cluster.
+func awsClusterToAWSMachinePoolMapFunc(c client.Client, gvk schema.GroupVersionKind, log logger.Wrapper) handler.MapFunc {
+	return func(ctx context.Context, o client.Object) []reconcile.Request {
+		awsCluster, ok := o.(*infrav1.AWSCluster)
+		if !ok {
+			klog.Errorf("Expected a AWSCluster but got a %T", o)
+			return nil
+		}
+
+		if !awsCluster.ObjectMeta.DeletionTimestamp.IsZero() {
+			return nil
+		}
+
+		clusterKey, err := GetOwnerClusterKey(awsCluster.ObjectMeta)
+		if err != nil {
+			log.Error(err, "couldn't get AWSCluster owner ObjectKey")
+			return nil
+		}
+		if clusterKey == nil {
+			return nil
+		}
+
+		machinePools := expclusterv1.MachinePoolList{}
+		if err := c.List(
+			ctx, &machinePools, client.InNamespace(clusterKey.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: clusterKey.Name},
+		); err != nil {
+			log.Error(err, "couldn't list pools for cluster")
+			return nil
+		}
+
+		mapFunc := machinePoolToInfrastructureMapFunc(gvk)
+
+		var results []ctrl.Request
+		for i := range machinePools.Items {
+			results = append(results, mapFunc(ctx, &machinePools.Items[i])...)
+		}
+
+		return results
+	}
+}
+
+// defaultLifecycleHooksChanged filters AWSCluster and AWSManagedControlPlane events to the ones changing
+// the default lifecycle hooks of the machine pools.
+func defaultLifecycleHooksChanged() predicate.Funcs {
+	defaultLifecycleHooks := func(o client.Object) []infrav1.AWSLifecycleHook {
+		switch obj := o.(type) {
+		case *infrav1.AWSCluster:
+			return obj.Spec.DefaultLifecycleHooks
+		case *ekscontrolplanev1.AWSManagedControlPlane:
+			return obj.Spec.DefaultLifecycleHooks
+		}
+		return nil
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return len(defaultLifecycleHooks(e.Object)) > 0
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !cmp.Equal(defaultLifecycleHooks(e.ObjectOld), defaultLifecycleHooks(e.ObjectNew))
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
 func machinePoolToInfrastructureMapFunc(gvk schema.GroupVersionKind) handler.MapFunc {
 	return func(ctx context.Context, o client.Object) []reconcile.Request {
 		m, ok := o.(*expclusterv1.MachinePool)
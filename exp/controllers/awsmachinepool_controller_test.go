@@ -22,8 +22,10 @@ import (
 	"flag"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
@@ -32,14 +34,21 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
+	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/mock_services"
@@ -160,7 +169,7 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 		reconSvc = mock_services.NewMockMachinePoolReconcileInterface(mockCtrl)
 
 		// If the test hangs for 9 minutes, increase the value here to the number of events during a reconciliation loop
-		recorder = record.NewFakeRecorder(2)
+		recorder = record.NewFakeRecorder(4)
 
 		reconciler = AWSMachinePoolReconciler{
 			ec2ServiceFactory: func(scope.EC2Scope) services.EC2Interface {
@@ -211,7 +220,7 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 				buf := new(bytes.Buffer)
 				klog.SetOutput(buf)
 
-				_ = reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				_, _ = reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 				g.Expect(buf).To(ContainSubstring("Error state detected, skipping reconciliation"))
 			})
 			t.Run("should add our finalizer to the machinepool", func(t *testing.T) {
@@ -220,7 +229,7 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 				defer teardown(t, g)
 				getASG(t, g)
 
-				_ = reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				_, _ = reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 
 				g.Expect(ms.AWSMachinePool.Finalizers).To(ContainElement(expinfrav1.MachinePoolFinalizer))
 			})
@@ -235,7 +244,7 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 				buf := new(bytes.Buffer)
 				klog.SetOutput(buf)
 
-				err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 				g.Expect(err).To(BeNil())
 				g.Expect(buf.String()).To(ContainSubstring("Cluster infrastructure is not ready yet"))
 				expectConditions(g, ms.AWSMachinePool, []conditionAssertion{{expinfrav1.ASGReadyCondition, corev1.ConditionFalse, clusterv1.ConditionSeverityInfo, infrav1.WaitingForClusterInfrastructureReason}})
@@ -250,7 +259,7 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 				buf := new(bytes.Buffer)
 				klog.SetOutput(buf)
 
-				err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 
 				g.Expect(err).To(BeNil())
 				g.Expect(buf.String()).To(ContainSubstring("Bootstrap data secret reference is not yet available"))
@@ -278,7 +287,7 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 
 				expectedErr := errors.New("no connection available ")
 				reconSvc.EXPECT().ReconcileLaunchTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(expectedErr)
-				err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 				g.Expect(errors.Cause(err)).To(MatchError(expectedErr))
 			})
 		})
@@ -305,10 +314,26 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 				}, nil)
 				asgSvc.EXPECT().SuspendProcesses("name", []string{"Launch", "Terminate"}).Return(nil).AnyTimes().Times(0)
 
-				err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 				g.Expect(err).To(Succeed())
 			})
 		})
+		t.Run("the name of the created ASG is recorded", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(t, g)
+			defer teardown(t, g)
+			ms.AWSMachinePool.Spec.AutoScalingGroupName = "team-prod-workers"
+
+			reconSvc.EXPECT().ReconcileLaunchTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(nil, nil)
+			asgSvc.EXPECT().CreateASG(gomock.Any()).Return(&expinfrav1.AutoScalingGroup{
+				Name: "team-prod-workers",
+			}, nil)
+
+			_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+			g.Expect(err).To(Succeed())
+			g.Expect(ms.AWSMachinePool.Status.ASGName).To(Equal("team-prod-workers"))
+		})
 		t.Run("all processes are suspended", func(t *testing.T) {
 			setSuspendedProcesses := func(t *testing.T, g *WithT) {
 				t.Helper()
@@ -324,11 +349,14 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 				ms.AWSMachinePool.Spec.SuspendProcesses.All = true
 				reconSvc.EXPECT().ReconcileLaunchTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 				reconSvc.EXPECT().ReconcileTags(gomock.Any(), gomock.Any()).Return(nil)
+				asgSvc.EXPECT().ReconcileASGTags(gomock.Any(), gomock.Any()).Return(nil)
 				asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(&expinfrav1.AutoScalingGroup{
 					Name: "name",
 				}, nil)
 				asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{}, nil).Times(1)
-				asgSvc.EXPECT().UpdateASG(gomock.Any()).Return(nil).AnyTimes()
+				asgSvc.EXPECT().DescribeScalingActivities(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 				asgSvc.EXPECT().SuspendProcesses("name", gomock.InAnyOrder([]string{
 					"ScheduledActions",
 					"Launch",
@@ -341,7 +369,7 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 					"ReplaceUnhealthy",
 				})).Return(nil).AnyTimes().Times(1)
 
-				err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 				g.Expect(err).To(Succeed())
 			})
 		})
@@ -356,25 +384,133 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 					},
 				}
 			}
-			t.Run("it should suspend and resume processes that are desired to be suspended and desired to be resumed", func(t *testing.T) {
+			expectUpdatePool := func(currentlySuspended []string) {
+				reconSvc.EXPECT().ReconcileLaunchTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				reconSvc.EXPECT().ReconcileTags(gomock.Any(), gomock.Any()).Return(nil)
+				asgSvc.EXPECT().ReconcileASGTags(gomock.Any(), gomock.Any()).Return(nil)
+				asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(&expinfrav1.AutoScalingGroup{
+					Name:                      "name",
+					CurrentlySuspendProcesses: currentlySuspended,
+				}, nil)
+				asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{}, nil).Times(1)
+				asgSvc.EXPECT().DescribeScalingActivities(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			}
+			t.Run("it should only suspend the processes which aren't suspended yet", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				setSuspendedProcesses(t, g)
+
+				expectUpdatePool([]string{"Launch", "process3"})
+				asgSvc.EXPECT().SuspendProcesses("name", []string{"Terminate"}).Return(nil).Times(1)
+				asgSvc.EXPECT().ResumeProcesses(gomock.Any(), gomock.Any()).Times(0)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+				g.Expect(ms.AWSMachinePool.Status.SuspendedProcesses).To(Equal([]string{"Launch", "Terminate"}))
+			})
+			t.Run("it should not call suspend or resume when the processes are converged", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				setSuspendedProcesses(t, g)
+				ms.AWSMachinePool.Status.SuspendedProcesses = []string{"Launch", "Terminate"}
+
+				expectUpdatePool([]string{"Launch", "Terminate"})
+				asgSvc.EXPECT().SuspendProcesses(gomock.Any(), gomock.Any()).Times(0)
+				asgSvc.EXPECT().ResumeProcesses(gomock.Any(), gomock.Any()).Times(0)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+			})
+			t.Run("it should resume the processes suspended by CAPA which were removed from the spec", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				setSuspendedProcesses(t, g)
+				ms.AWSMachinePool.Status.SuspendedProcesses = []string{"AZRebalance", "Launch", "Terminate"}
+
+				// ReplaceUnhealthy was suspended by an operator, it must not be resumed.
+				expectUpdatePool([]string{"AZRebalance", "Launch", "ReplaceUnhealthy", "Terminate"})
+				asgSvc.EXPECT().SuspendProcesses(gomock.Any(), gomock.Any()).Times(0)
+				asgSvc.EXPECT().ResumeProcesses("name", []string{"AZRebalance"}).Return(nil).Times(1)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+				g.Expect(ms.AWSMachinePool.Status.SuspendedProcesses).To(Equal([]string{"Launch", "Terminate"}))
+			})
+			t.Run("it should resume all the processes suspended by CAPA when suspendProcesses is removed", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				ms.AWSMachinePool.Spec.SuspendProcesses = nil
+				ms.AWSMachinePool.Status.SuspendedProcesses = []string{"Launch", "Terminate"}
+
+				expectUpdatePool([]string{"Launch", "ReplaceUnhealthy", "Terminate"})
+				asgSvc.EXPECT().SuspendProcesses(gomock.Any(), gomock.Any()).Times(0)
+				asgSvc.EXPECT().ResumeProcesses("name", []string{"Launch", "Terminate"}).Return(nil).Times(1)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+				g.Expect(ms.AWSMachinePool.Status.SuspendedProcesses).To(BeEmpty())
+			})
+			t.Run("it should resume the processes explicitly disabled in the spec", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				ms.AWSMachinePool.Spec.SuspendProcesses = &expinfrav1.SuspendProcessesTypes{
+					All: true,
+					Processes: &expinfrav1.Processes{
+						Launch:    ptr.To[bool](false),
+						Terminate: ptr.To[bool](false),
+					},
+				}
+
+				expectUpdatePool([]string{"AZRebalance", "HealthCheck", "Launch"})
+				asgSvc.EXPECT().SuspendProcesses("name", []string{
+					"AddToLoadBalancer",
+					"AlarmNotification",
+					"InstanceRefresh",
+					"ReplaceUnhealthy",
+					"ScheduledActions",
+				}).Return(nil).Times(1)
+				asgSvc.EXPECT().ResumeProcesses("name", []string{"Launch"}).Return(nil).Times(1)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+				g.Expect(ms.AWSMachinePool.Status.SuspendedProcesses).To(Equal([]string{
+					"AZRebalance",
+					"AddToLoadBalancer",
+					"AlarmNotification",
+					"HealthCheck",
+					"InstanceRefresh",
+					"ReplaceUnhealthy",
+					"ScheduledActions",
+				}))
+			})
+			t.Run("it should keep track of the suspended processes when resuming fails", func(t *testing.T) {
 				g := NewWithT(t)
 				setup(t, g)
 				defer teardown(t, g)
 				setSuspendedProcesses(t, g)
+				ms.AWSMachinePool.Status.SuspendedProcesses = []string{"AZRebalance"}
 
+				// The reconciliation stops at the update of the pool.
 				reconSvc.EXPECT().ReconcileLaunchTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
-				reconSvc.EXPECT().ReconcileTags(gomock.Any(), gomock.Any()).Return(nil)
 				asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(&expinfrav1.AutoScalingGroup{
 					Name:                      "name",
-					CurrentlySuspendProcesses: []string{"Launch", "process3"},
+					CurrentlySuspendProcesses: []string{"AZRebalance"},
 				}, nil)
 				asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{}, nil).Times(1)
-				asgSvc.EXPECT().UpdateASG(gomock.Any()).Return(nil).AnyTimes()
-				asgSvc.EXPECT().SuspendProcesses("name", []string{"Terminate"}).Return(nil).AnyTimes().Times(1)
-				asgSvc.EXPECT().ResumeProcesses("name", []string{"process3"}).Return(nil).AnyTimes().Times(1)
+				asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				asgSvc.EXPECT().SuspendProcesses("name", []string{"Launch", "Terminate"}).Return(nil).Times(1)
+				asgSvc.EXPECT().ResumeProcesses("name", []string{"AZRebalance"}).Return(errors.New("throttled")).Times(1)
 
-				err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
-				g.Expect(err).To(Succeed())
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(ms.AWSMachinePool.Status.SuspendedProcesses).To(Equal([]string{"AZRebalance", "Launch", "Terminate"}))
 			})
 		})
 
@@ -390,8 +526,11 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 			reconSvc.EXPECT().ReconcileLaunchTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(&asg, nil)
 			asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{}, nil)
-			asgSvc.EXPECT().UpdateASG(gomock.Any()).Return(nil)
+			asgSvc.EXPECT().DescribeScalingActivities(gomock.Any()).Return(nil, nil)
+			asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil)
+			asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Return(nil)
 			reconSvc.EXPECT().ReconcileTags(gomock.Any(), gomock.Any()).Return(nil)
+			asgSvc.EXPECT().ReconcileASGTags(gomock.Any(), gomock.Any()).Return(nil)
 
 			ms.MachinePool.Annotations = map[string]string{
 				clusterv1.ReplicasManagedByAnnotation: "somehow-externally-managed",
@@ -400,7 +539,7 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 
 			g.Expect(testEnv.Create(ctx, ms.MachinePool)).To(Succeed())
 
-			_ = reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+			_, _ = reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 			g.Expect(*ms.MachinePool.Spec.Replicas).To(Equal(int32(1)))
 		})
 		t.Run("No need to update Asg because asgNeedsUpdates is false and no subnets change", func(t *testing.T) {
@@ -427,11 +566,14 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 				Subnets: []string{"subnet1", "subnet2"}}
 			reconSvc.EXPECT().ReconcileLaunchTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			reconSvc.EXPECT().ReconcileTags(gomock.Any(), gomock.Any()).Return(nil)
+			asgSvc.EXPECT().ReconcileASGTags(gomock.Any(), gomock.Any()).Return(nil)
 			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(&asg, nil).AnyTimes()
 			asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{"subnet2", "subnet1"}, nil).Times(1)
-			asgSvc.EXPECT().UpdateASG(gomock.Any()).Return(nil).Times(0)
+			asgSvc.EXPECT().DescribeScalingActivities(gomock.Any()).Return(nil, nil)
+			asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil)
+			asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Return(nil).Times(0)
 
-			err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+			_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 			g.Expect(err).To(Succeed())
 		})
 		t.Run("update Asg due to subnet changes", func(t *testing.T) {
@@ -445,11 +587,14 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 				Subnets: []string{"subnet1", "subnet2"}}
 			reconSvc.EXPECT().ReconcileLaunchTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			reconSvc.EXPECT().ReconcileTags(gomock.Any(), gomock.Any()).Return(nil)
+			asgSvc.EXPECT().ReconcileASGTags(gomock.Any(), gomock.Any()).Return(nil)
 			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(&asg, nil).AnyTimes()
 			asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{"subnet1"}, nil).Times(1)
-			asgSvc.EXPECT().UpdateASG(gomock.Any()).Return(nil).Times(1)
+			asgSvc.EXPECT().DescribeScalingActivities(gomock.Any()).Return(nil, nil)
+			asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil)
+			asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
-			err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+			_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 			g.Expect(err).To(Succeed())
 		})
 		t.Run("update Asg due to asgNeedsUpdates returns true", func(t *testing.T) {
@@ -463,207 +608,581 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 				Subnets: []string{}}
 			reconSvc.EXPECT().ReconcileLaunchTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			reconSvc.EXPECT().ReconcileTags(gomock.Any(), gomock.Any()).Return(nil)
+			asgSvc.EXPECT().ReconcileASGTags(gomock.Any(), gomock.Any()).Return(nil)
 			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(&asg, nil).AnyTimes()
 			asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{}, nil).Times(1)
-			asgSvc.EXPECT().UpdateASG(gomock.Any()).Return(nil).Times(1)
+			asgSvc.EXPECT().DescribeScalingActivities(gomock.Any()).Return(nil, nil)
+			asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil)
+			asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
-			err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+			_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 			g.Expect(err).To(Succeed())
 		})
 
-		t.Run("ReconcileLaunchTemplate not mocked", func(t *testing.T) {
-			g := NewWithT(t)
-			setup(t, g)
-			reconciler.reconcileServiceFactory = nil // use real implementation, but keep EC2 calls mocked (`ec2ServiceFactory`)
-			reconSvc = nil                           // not used
-			defer teardown(t, g)
+		t.Run("scale-down policy", func(t *testing.T) {
+			newASG := func(desiredCapacity int32, instances int) *expinfrav1.AutoScalingGroup {
+				asg := &expinfrav1.AutoScalingGroup{
+					Name:                 "test",
+					DesiredCapacity:      ptr.To[int32](desiredCapacity),
+					MinSize:              awsMachinePool.Spec.MinSize,
+					MaxSize:              awsMachinePool.Spec.MaxSize,
+					MixedInstancesPolicy: awsMachinePool.Spec.MixedInstancesPolicy.DeepCopy(),
+					Subnets:              []string{},
+				}
+				for i := 0; i < instances; i++ {
+					asg.Instances = append(asg.Instances, infrav1.Instance{ID: fmt.Sprintf("i-%d", i)})
+				}
+				return asg
+			}
+			expectReconcileCalls := func(t *testing.T, g *WithT, asg *expinfrav1.AutoScalingGroup) {
+				t.Helper()
 
-			launchTemplateIDExisting := "lt-existing"
+				reconSvc.EXPECT().ReconcileLaunchTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				reconSvc.EXPECT().ReconcileTags(gomock.Any(), gomock.Any()).Return(nil)
+				asgSvc.EXPECT().ReconcileASGTags(gomock.Any(), gomock.Any()).Return(nil)
+				asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(asg, nil)
+				asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{}, nil)
+				asgSvc.EXPECT().DescribeScalingActivities(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil)
+			}
+			t.Run("should scale down by at most MaxUnavailable instances", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
 
-			t.Run("nothing exists, so launch template and ASG must be created", func(t *testing.T) {
-				ec2Svc.EXPECT().GetLaunchTemplate(gomock.Eq("test")).Return(nil, "", nil, nil)
-				ec2Svc.EXPECT().DiscoverLaunchTemplateAMI(gomock.Any()).Return(ptr.To[string]("ami-abcdef123"), nil)
-				ec2Svc.EXPECT().CreateLaunchTemplate(gomock.Any(), gomock.Eq(ptr.To[string]("ami-abcdef123")), gomock.Eq(userDataSecretKey), gomock.Eq([]byte("shell-script"))).Return("lt-ghijkl456", nil)
-				asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(nil, nil)
-				asgSvc.EXPECT().CreateASG(gomock.Any()).DoAndReturn(func(scope *scope.MachinePoolScope) (*expinfrav1.AutoScalingGroup, error) {
-					g.Expect(scope.Name()).To(Equal("test"))
-					return &expinfrav1.AutoScalingGroup{
-						Name: scope.Name(),
-					}, nil
+				ms.MachinePool.Spec.Replicas = ptr.To[int32](0)
+				ms.AWSMachinePool.Spec.ScaleDownPolicy = &expinfrav1.ScaleDownPolicy{
+					MaxUnavailable: ptr.To(intstr.FromString("30%")),
+				}
+
+				expectReconcileCalls(t, g, newASG(10, 10))
+				asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).DoAndReturn(func(scope *scope.MachinePoolScope, _ *expinfrav1.AutoScalingGroup) error {
+					g.Expect(scope.AWSMachinePool.Status.ScaleDown.DesiredCapacity).To(BeEquivalentTo(7))
+					return nil
 				})
 
-				err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				result, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 				g.Expect(err).To(Succeed())
+				g.Expect(result.RequeueAfter).ToNot(BeZero())
+				g.Expect(conditions.GetReason(ms.AWSMachinePool, expinfrav1.ScaleDownCompletedCondition)).To(Equal(expinfrav1.ScaleDownInProgressReason))
 			})
+			t.Run("should wait for the instances removed by the previous step to terminate", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
 
-			t.Run("launch template and ASG exist and need no update", func(t *testing.T) {
-				// Latest ID and version already stored, no need to retrieve it
-				ms.AWSMachinePool.Status.LaunchTemplateID = launchTemplateIDExisting
-				ms.AWSMachinePool.Status.LaunchTemplateVersion = ptr.To[string]("1")
+				ms.MachinePool.Spec.Replicas = ptr.To[int32](0)
+				ms.AWSMachinePool.Spec.ScaleDownPolicy = &expinfrav1.ScaleDownPolicy{
+					MaxUnavailable: ptr.To(intstr.FromInt32(3)),
+				}
+				ms.AWSMachinePool.Status.ScaleDown = &expinfrav1.ScaleDownStatus{
+					DesiredCapacity: 7,
+					LastStepTime:    &metav1.Time{Time: time.Now().Add(-time.Minute)},
+				}
 
-				ec2Svc.EXPECT().GetLaunchTemplate(gomock.Eq("test")).Return(
-					&expinfrav1.AWSLaunchTemplate{
-						Name: "test",
-						AMI: infrav1.AMIReference{
-							ID: ptr.To[string]("ami-existing"),
-						},
-					},
-					// No change to user data
-					userdata.ComputeHash([]byte("shell-script")),
-					&userDataSecretKey,
-					nil)
-				ec2Svc.EXPECT().DiscoverLaunchTemplateAMI(gomock.Any()).Return(ptr.To[string]("ami-existing"), nil) // no change
-				ec2Svc.EXPECT().LaunchTemplateNeedsUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+				expectReconcileCalls(t, g, newASG(7, 10))
+				asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Times(0)
 
-				asgSvc.EXPECT().GetASGByName(gomock.Any()).DoAndReturn(func(scope *scope.MachinePoolScope) (*expinfrav1.AutoScalingGroup, error) {
-					g.Expect(scope.Name()).To(Equal("test"))
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+				g.Expect(ms.AWSMachinePool.Status.ScaleDown.DesiredCapacity).To(BeEquivalentTo(7))
+				g.Expect(conditions.GetReason(ms.AWSMachinePool, expinfrav1.ScaleDownCompletedCondition)).To(Equal(expinfrav1.ScaleDownWaitingForInstancesReason))
+			})
+			t.Run("should pause between steps", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
 
-					// No difference to `AWSMachinePool.spec`
-					return &expinfrav1.AutoScalingGroup{
-						Name: scope.Name(),
-						Subnets: []string{
-							"subnet-1",
-						},
-						MinSize:              awsMachinePool.Spec.MinSize,
-						MaxSize:              awsMachinePool.Spec.MaxSize,
-						MixedInstancesPolicy: awsMachinePool.Spec.MixedInstancesPolicy.DeepCopy(),
-					}, nil
-				})
-				asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{"subnet-1"}, nil) // no change
-				// No changes, so there must not be an ASG update!
-				asgSvc.EXPECT().UpdateASG(gomock.Any()).Times(0)
+				ms.MachinePool.Spec.Replicas = ptr.To[int32](0)
+				ms.AWSMachinePool.Spec.ScaleDownPolicy = &expinfrav1.ScaleDownPolicy{
+					PauseSeconds: 300,
+				}
+				ms.AWSMachinePool.Status.ScaleDown = &expinfrav1.ScaleDownStatus{
+					DesiredCapacity: 7,
+					LastStepTime:    &metav1.Time{Time: time.Now().Add(-time.Minute)},
+				}
 
-				err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				expectReconcileCalls(t, g, newASG(7, 7))
+				asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Times(0)
+
+				result, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 				g.Expect(err).To(Succeed())
+				g.Expect(result.RequeueAfter).To(BeNumerically("~", 4*time.Minute, 5*time.Second))
 			})
+			t.Run("should scale down immediately when the escape hatch annotation is set", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
 
-			t.Run("launch template and ASG exist and only AMI ID changed", func(t *testing.T) {
-				// Latest ID and version already stored, no need to retrieve it
-				ms.AWSMachinePool.Status.LaunchTemplateID = launchTemplateIDExisting
-				ms.AWSMachinePool.Status.LaunchTemplateVersion = ptr.To[string]("1")
+				ms.MachinePool.Spec.Replicas = ptr.To[int32](0)
+				ms.AWSMachinePool.Spec.ScaleDownPolicy = &expinfrav1.ScaleDownPolicy{}
+				ms.AWSMachinePool.Annotations = map[string]string{expinfrav1.ScaleDownImmediatelyAnnotation: ""}
 
-				ec2Svc.EXPECT().GetLaunchTemplate(gomock.Eq("test")).Return(
-					&expinfrav1.AWSLaunchTemplate{
-						Name: "test",
-						AMI: infrav1.AMIReference{
-							ID: ptr.To[string]("ami-existing"),
-						},
-					},
-					// No change to user data
-					userdata.ComputeHash([]byte("shell-script")),
-					&userDataSecretKey,
-					nil)
-				ec2Svc.EXPECT().DiscoverLaunchTemplateAMI(gomock.Any()).Return(ptr.To[string]("ami-different"), nil)
-				ec2Svc.EXPECT().LaunchTemplateNeedsUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
-				asgSvc.EXPECT().CanStartASGInstanceRefresh(gomock.Any()).Return(true, nil)
-				ec2Svc.EXPECT().PruneLaunchTemplateVersions(gomock.Any()).Return(nil)
-				ec2Svc.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any(), gomock.Eq(ptr.To[string]("ami-different")), gomock.Eq(apimachinerytypes.NamespacedName{Namespace: "default", Name: "bootstrap-data"}), gomock.Any()).Return(nil)
-				ec2Svc.EXPECT().GetLaunchTemplateLatestVersion(gomock.Any()).Return("2", nil)
-				// AMI change should trigger rolling out new nodes
-				asgSvc.EXPECT().StartASGInstanceRefresh(gomock.Any())
+				expectReconcileCalls(t, g, newASG(10, 10))
+				asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).DoAndReturn(func(scope *scope.MachinePoolScope, _ *expinfrav1.AutoScalingGroup) error {
+					g.Expect(scope.AWSMachinePool.Status.ScaleDown.DesiredCapacity).To(BeEquivalentTo(0))
+					return nil
+				})
 
-				asgSvc.EXPECT().GetASGByName(gomock.Any()).DoAndReturn(func(scope *scope.MachinePoolScope) (*expinfrav1.AutoScalingGroup, error) {
-					g.Expect(scope.Name()).To(Equal("test"))
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+			})
+			t.Run("should mark the scale-down completed once the replicas are reached", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
 
-					// No difference to `AWSMachinePool.spec`
-					return &expinfrav1.AutoScalingGroup{
-						Name: scope.Name(),
-						Subnets: []string{
-							"subnet-1",
-						},
-						MinSize:              awsMachinePool.Spec.MinSize,
-						MaxSize:              awsMachinePool.Spec.MaxSize,
-						MixedInstancesPolicy: awsMachinePool.Spec.MixedInstancesPolicy.DeepCopy(),
-					}, nil
-				})
-				asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{"subnet-1"}, nil) // no change
-				// No changes, so there must not be an ASG update!
-				asgSvc.EXPECT().UpdateASG(gomock.Any()).Times(0)
+				ms.MachinePool.Spec.Replicas = ptr.To[int32](0)
+				ms.AWSMachinePool.Spec.ScaleDownPolicy = &expinfrav1.ScaleDownPolicy{}
+				ms.AWSMachinePool.Status.ScaleDown = &expinfrav1.ScaleDownStatus{DesiredCapacity: 0}
 
-				err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				expectReconcileCalls(t, g, newASG(0, 0))
+				asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Times(0)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 				g.Expect(err).To(Succeed())
+				g.Expect(ms.AWSMachinePool.Status.ScaleDown).To(BeNil())
+				g.Expect(conditions.IsTrue(ms.AWSMachinePool, expinfrav1.ScaleDownCompletedCondition)).To(BeTrue())
 			})
+		})
 
-			t.Run("launch template and ASG exist and only bootstrap data secret name changed", func(t *testing.T) {
-				// Latest ID and version already stored, no need to retrieve it
-				ms.AWSMachinePool.Status.LaunchTemplateID = launchTemplateIDExisting
-				ms.AWSMachinePool.Status.LaunchTemplateVersion = ptr.To[string]("1")
+		t.Run("lifecycle hooks", func(t *testing.T) {
+			expectASGUpdateCalls := func(t *testing.T, g *WithT) {
+				t.Helper()
 
-				ec2Svc.EXPECT().GetLaunchTemplate(gomock.Eq("test")).Return(
-					&expinfrav1.AWSLaunchTemplate{
-						Name: "test",
-						AMI: infrav1.AMIReference{
-							ID: ptr.To[string]("ami-existing"),
-						},
-					},
-					// No change to user data
-					userdata.ComputeHash([]byte("shell-script")),
-					// But the name of the secret changes from `previous-secret-name` to `bootstrap-data`
-					&apimachinerytypes.NamespacedName{Namespace: "default", Name: "previous-secret-name"},
-					nil)
-				ec2Svc.EXPECT().DiscoverLaunchTemplateAMI(gomock.Any()).Return(ptr.To[string]("ami-existing"), nil)
-				ec2Svc.EXPECT().LaunchTemplateNeedsUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
-				asgSvc.EXPECT().CanStartASGInstanceRefresh(gomock.Any()).Return(true, nil)
-				ec2Svc.EXPECT().PruneLaunchTemplateVersions(gomock.Any()).Return(nil)
-				ec2Svc.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any(), gomock.Eq(ptr.To[string]("ami-existing")), gomock.Eq(apimachinerytypes.NamespacedName{Namespace: "default", Name: "bootstrap-data"}), gomock.Any()).Return(nil)
-				ec2Svc.EXPECT().GetLaunchTemplateLatestVersion(gomock.Any()).Return("2", nil)
-				// Changing the bootstrap data secret name should trigger rolling out new nodes, no matter what the
-				// content (user data) is. This way, users can enforce a rollout by changing the bootstrap config
-				// reference (`MachinePool.spec.template.spec.bootstrap`).
-				asgSvc.EXPECT().StartASGInstanceRefresh(gomock.Any())
+				asg := expinfrav1.AutoScalingGroup{
+					MinSize: int32(0),
+					MaxSize: int32(100),
+					Subnets: []string{}}
+				reconSvc.EXPECT().ReconcileLaunchTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				reconSvc.EXPECT().ReconcileTags(gomock.Any(), gomock.Any()).Return(nil)
+				asgSvc.EXPECT().ReconcileASGTags(gomock.Any(), gomock.Any()).Return(nil)
+				asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(&asg, nil).AnyTimes()
+				asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{}, nil).Times(1)
+				asgSvc.EXPECT().DescribeScalingActivities(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				asgSvc.EXPECT().MissingLifecycleHookPermissions(gomock.Any()).Return(nil, nil).AnyTimes()
+			}
+			newHook := func() infrav1.AWSLifecycleHook {
+				return infrav1.AWSLifecycleHook{
+					Name:                "hook",
+					LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+					HeartbeatTimeout:    &metav1.Duration{Duration: 600 * time.Second},
+				}
+			}
+			t.Run("should create a lifecycle hook that does not exist yet", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				expectASGUpdateCalls(t, g)
 
-				asgSvc.EXPECT().GetASGByName(gomock.Any()).DoAndReturn(func(scope *scope.MachinePoolScope) (*expinfrav1.AutoScalingGroup, error) {
-					g.Expect(scope.Name()).To(Equal("test"))
+				hook := newHook()
+				ms.AWSMachinePool.Spec.AWSLifecycleHooks = []infrav1.AWSLifecycleHook{hook}
 
-					// No difference to `AWSMachinePool.spec`
-					return &expinfrav1.AutoScalingGroup{
-						Name: scope.Name(),
-						Subnets: []string{
-							"subnet-1",
-						},
-						MinSize:              awsMachinePool.Spec.MinSize,
-						MaxSize:              awsMachinePool.Spec.MaxSize,
-						MixedInstancesPolicy: awsMachinePool.Spec.MixedInstancesPolicy.DeepCopy(),
-					}, nil
-				})
-				asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{"subnet-1"}, nil) // no change
-				// No changes, so there must not be an ASG update!
-				asgSvc.EXPECT().UpdateASG(gomock.Any()).Times(0)
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().CreateLifecycleHook(gomock.Any(), &hook).Return(nil)
 
-				err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 				g.Expect(err).To(Succeed())
 			})
+			t.Run("should update a drifted lifecycle hook", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				expectASGUpdateCalls(t, g)
 
-			t.Run("launch template and ASG created from zero, then bootstrap config reference changes", func(t *testing.T) {
-				ec2Svc.EXPECT().GetLaunchTemplate(gomock.Eq("test")).Return(nil, "", nil, nil)
-				ec2Svc.EXPECT().DiscoverLaunchTemplateAMI(gomock.Any()).Return(ptr.To[string]("ami-abcdef123"), nil)
-				ec2Svc.EXPECT().CreateLaunchTemplate(gomock.Any(), gomock.Eq(ptr.To[string]("ami-abcdef123")), gomock.Eq(userDataSecretKey), gomock.Eq([]byte("shell-script"))).Return("lt-ghijkl456", nil)
-				asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(nil, nil)
-				asgSvc.EXPECT().CreateASG(gomock.Any()).DoAndReturn(func(scope *scope.MachinePoolScope) (*expinfrav1.AutoScalingGroup, error) {
+				hook := newHook()
+				ms.AWSMachinePool.Spec.AWSLifecycleHooks = []infrav1.AWSLifecycleHook{hook}
+				existing := newHook()
+				existing.HeartbeatTimeout = &metav1.Duration{Duration: 300 * time.Second}
+
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return([]*infrav1.AWSLifecycleHook{&existing}, nil)
+				asgSvc.EXPECT().LifecycleHookNeedsUpdate(&existing, &hook).Return(true)
+				asgSvc.EXPECT().UpdateLifecycleHook(gomock.Any(), &hook).Return(nil)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+			})
+			t.Run("should not update a drifted lifecycle hook in CreateOnly sync mode", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				expectASGUpdateCalls(t, g)
+
+				hook := newHook()
+				hook.SyncMode = ptr.To[infrav1.LifecycleHookSyncMode](infrav1.LifecycleHookSyncModeCreateOnly)
+				ms.AWSMachinePool.Spec.AWSLifecycleHooks = []infrav1.AWSLifecycleHook{hook}
+				existing := newHook()
+				existing.HeartbeatTimeout = &metav1.Duration{Duration: 300 * time.Second}
+
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return([]*infrav1.AWSLifecycleHook{&existing}, nil)
+				asgSvc.EXPECT().LifecycleHookNeedsUpdate(&existing, &hook).Return(true)
+				asgSvc.EXPECT().UpdateLifecycleHook(gomock.Any(), gomock.Any()).Times(0)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+			})
+			t.Run("should create the managed launch lifecycle hook when it is enabled", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				expectASGUpdateCalls(t, g)
+
+				ms.AWSMachinePool.Spec.ManagedLaunchLifecycleHook = &expinfrav1.ManagedLaunchLifecycleHook{
+					Enabled: true,
+					Timeout: &metav1.Duration{Duration: 10 * time.Minute},
+				}
+				hook := ms.AWSMachinePool.Spec.ManagedLaunchLifecycleHook.LifecycleHook()
+
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().CreateLifecycleHook(gomock.Any(), &hook).Return(nil)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+			})
+			t.Run("should reconcile the lifecycle hooks when the workload cluster is unreachable", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+
+				// The kubeconfig Secret of the cluster doesn't exist, so the Nodes can't be listed.
+				asg := expinfrav1.AutoScalingGroup{
+					Name:      "test",
+					MinSize:   int32(0),
+					MaxSize:   int32(100),
+					Subnets:   []string{},
+					Instances: []infrav1.Instance{{ID: "i-1", State: expinfrav1.InstanceStatePendingWait}},
+				}
+				reconSvc.EXPECT().ReconcileLaunchTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				reconSvc.EXPECT().ReconcileTags(gomock.Any(), gomock.Any()).Return(nil)
+				asgSvc.EXPECT().ReconcileASGTags(gomock.Any(), gomock.Any()).Return(nil)
+				asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(&asg, nil).AnyTimes()
+				asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{}, nil).Times(1)
+				asgSvc.EXPECT().DescribeScalingActivities(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				asgSvc.EXPECT().MissingLifecycleHookPermissions(gomock.Any()).Return(nil, nil).AnyTimes()
+
+				ms.AWSMachinePool.Spec.ManagedLaunchLifecycleHook = &expinfrav1.ManagedLaunchLifecycleHook{Enabled: true}
+				hook := ms.AWSMachinePool.Spec.ManagedLaunchLifecycleHook.LifecycleHook()
+				// The lifecycle hooks are also described for the pending lifecycle actions of the instance.
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil).Times(2)
+				asgSvc.EXPECT().CreateLifecycleHook(gomock.Any(), &hook).Return(nil)
+				asgSvc.EXPECT().CompleteLifecycleAction(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				result, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+				g.Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+				g.Expect(conditions.IsTrue(ms.AWSMachinePool, expinfrav1.ASGReadyCondition)).To(BeTrue())
+				g.Expect(conditions.IsTrue(ms.AWSMachinePool, expinfrav1.LifecycleHookExistsCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(ms.AWSMachinePool, expinfrav1.WorkloadClusterReachableCondition)).To(Equal(expinfrav1.WorkloadClusterUnreachableReason))
+			})
+			t.Run("should create lifecycle hooks by ascending priority", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				expectASGUpdateCalls(t, g)
+
+				terminateHook := newHook()
+				terminateHook.Name = "terminate-hook"
+				terminateHook.Priority = 10
+				launchHook := newHook()
+				launchHook.Name = "launch-hook"
+				launchHook.LifecycleTransition = infrav1.LifecycleTransitionInstanceLaunch
+				launchHook.Priority = -1
+				ms.AWSMachinePool.Spec.AWSLifecycleHooks = []infrav1.AWSLifecycleHook{terminateHook, launchHook}
+
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil)
+				gomock.InOrder(
+					asgSvc.EXPECT().CreateLifecycleHook(gomock.Any(), &launchHook).Return(nil),
+					asgSvc.EXPECT().CreateLifecycleHook(gomock.Any(), &terminateHook).Return(nil),
+				)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+			})
+			t.Run("should delete a lifecycle hook that was removed from the spec", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				expectASGUpdateCalls(t, g)
+
+				existing := newHook()
+
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return([]*infrav1.AWSLifecycleHook{&existing}, nil)
+				asgSvc.EXPECT().DeleteLifecycleHook(gomock.Any(), &existing, false).Return(nil)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+			})
+			t.Run("should not sync a user lifecycle hook with the reserved name prefix", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				expectASGUpdateCalls(t, g)
+
+				// The hook predates the webhook validation and collides with the managed launch lifecycle hook.
+				hook := newHook()
+				hook.Name = expinfrav1.ManagedLaunchLifecycleHookName
+				ms.AWSMachinePool.Spec.AWSLifecycleHooks = []infrav1.AWSLifecycleHook{hook}
+
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().CreateLifecycleHook(gomock.Any(), gomock.Any()).Times(0)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+				g.Expect(conditions.GetReason(ms.AWSMachinePool, expinfrav1.LifecycleHookExistsCondition)).To(Equal(expinfrav1.LifecycleHookRejectedReason))
+				// The rejected hook is only retried at the resync interval.
+				g.Expect(ms.AWSMachinePool.Status.LifecycleHooksLastSyncTime).NotTo(BeNil())
+			})
+			t.Run("should not sync a lifecycle hook with ARNs outside the partition of the cluster region", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				expectASGUpdateCalls(t, g)
+
+				// The AWSMachinePool webhook doesn't know the region of the cluster, so it accepts the hook.
+				cs.AWSCluster.Spec.Region = "us-east-1"
+				hook := newHook()
+				hook.NotificationTargetARN = ptr.To("arn:aws-us-gov:sqs:us-gov-west-1:123456789012:queue")
+				hook.RoleARN = ptr.To("arn:aws-us-gov:iam::123456789012:role/role")
+				ms.AWSMachinePool.Spec.AWSLifecycleHooks = []infrav1.AWSLifecycleHook{hook}
+
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().CreateLifecycleHook(gomock.Any(), gomock.Any()).Times(0)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+				g.Expect(conditions.GetReason(ms.AWSMachinePool, expinfrav1.LifecycleHookExistsCondition)).To(Equal(expinfrav1.LifecycleHookRejectedReason))
+				g.Expect(conditions.GetMessage(ms.AWSMachinePool, expinfrav1.LifecycleHookExistsCondition)).To(ContainSubstring(`does not match partition "aws" of region "us-east-1"`))
+			})
+			t.Run("should delete the managed launch lifecycle hook once it is disabled", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				expectASGUpdateCalls(t, g)
+
+				existing := (&expinfrav1.ManagedLaunchLifecycleHook{Enabled: true}).LifecycleHook()
+
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return([]*infrav1.AWSLifecycleHook{&existing}, nil)
+				asgSvc.EXPECT().DeleteLifecycleHook(gomock.Any(), &existing, false).Return(nil)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+			})
+			t.Run("should not delete a lifecycle hook with the reserved name prefix that is not managed by this version", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				expectASGUpdateCalls(t, g)
+
+				existing := newHook()
+				existing.Name = infrav1.ReservedLifecycleHookNamePrefix + "managed-terminate"
+
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return([]*infrav1.AWSLifecycleHook{&existing}, nil)
+				asgSvc.EXPECT().DeleteLifecycleHook(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+			})
+			t.Run("should not call AWS when the lifecycle hooks did not change since the last sync", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+
+				hook := newHook()
+				ms.AWSMachinePool.Spec.AWSLifecycleHooks = []infrav1.AWSLifecycleHook{hook}
+				existing := newHook()
+
+				expectASGUpdateCalls(t, g)
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return([]*infrav1.AWSLifecycleHook{&existing}, nil)
+				asgSvc.EXPECT().LifecycleHookNeedsUpdate(&existing, &hook).Return(false)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+				g.Expect(ms.AWSMachinePool.Status.LifecycleHooksObservedHash).ToNot(BeEmpty())
+				g.Expect(ms.AWSMachinePool.Status.LifecycleHooksLastSyncTime).ToNot(BeNil())
+
+				// No-op reconcile: the lifecycle hooks must be neither described nor written.
+				expectASGUpdateCalls(t, g)
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Times(0)
+				asgSvc.EXPECT().CreateLifecycleHook(gomock.Any(), gomock.Any()).Times(0)
+				asgSvc.EXPECT().UpdateLifecycleHook(gomock.Any(), gomock.Any()).Times(0)
+				asgSvc.EXPECT().DeleteLifecycleHook(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				_, err = reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+			})
+			t.Run("should sync the lifecycle hooks again when their spec changes", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+
+				hook := newHook()
+				ms.AWSMachinePool.Spec.AWSLifecycleHooks = []infrav1.AWSLifecycleHook{hook}
+
+				expectASGUpdateCalls(t, g)
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().CreateLifecycleHook(gomock.Any(), &hook).Return(nil)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+
+				existing := hook
+				hook.HeartbeatTimeout = &metav1.Duration{Duration: 300 * time.Second}
+				ms.AWSMachinePool.Spec.AWSLifecycleHooks = []infrav1.AWSLifecycleHook{hook}
+
+				expectASGUpdateCalls(t, g)
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return([]*infrav1.AWSLifecycleHook{&existing}, nil)
+				asgSvc.EXPECT().LifecycleHookNeedsUpdate(&existing, &hook).Return(true)
+				asgSvc.EXPECT().UpdateLifecycleHook(gomock.Any(), &hook).Return(nil)
+
+				_, err = reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+			})
+			t.Run("should check the lifecycle hooks for drift once the drift check interval elapsed", func(t *testing.T) {
+				g := NewWithT(t)
+				setup(t, g)
+				defer teardown(t, g)
+				expectASGUpdateCalls(t, g)
+
+				hook := newHook()
+				ms.AWSMachinePool.Spec.AWSLifecycleHooks = []infrav1.AWSLifecycleHook{hook}
+				hash, err := lifecycleHooksHash([]infrav1.AWSLifecycleHook{hook})
+				g.Expect(err).To(Succeed())
+				ms.AWSMachinePool.Status.LifecycleHooksObservedHash = hash
+				ms.AWSMachinePool.Status.LifecycleHooksLastSyncTime = &metav1.Time{Time: time.Now().Add(-lifecycleHookDriftCheckInterval)}
+
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().CreateLifecycleHook(gomock.Any(), &hook).Return(nil)
+
+				_, err = reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+			})
+		})
+
+		t.Run("ReconcileLaunchTemplate not mocked", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(t, g)
+			reconciler.reconcileServiceFactory = nil // use real implementation, but keep EC2 calls mocked (`ec2ServiceFactory`)
+			reconSvc = nil                           // not used
+			defer teardown(t, g)
+
+			launchTemplateIDExisting := "lt-existing"
+
+			t.Run("nothing exists, so launch template and ASG must be created", func(t *testing.T) {
+				ec2Svc.EXPECT().GetLaunchTemplate(gomock.Eq("test")).Return(nil, "", nil, nil)
+				ec2Svc.EXPECT().DiscoverLaunchTemplateAMI(gomock.Any()).Return(ptr.To[string]("ami-abcdef123"), nil)
+				ec2Svc.EXPECT().CreateLaunchTemplate(gomock.Any(), gomock.Eq(ptr.To[string]("ami-abcdef123")), gomock.Eq(userDataSecretKey), gomock.Eq([]byte("shell-script"))).Return("lt-ghijkl456", nil)
+				asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().CreateASG(gomock.Any()).DoAndReturn(func(scope *scope.MachinePoolScope) (*expinfrav1.AutoScalingGroup, error) {
 					g.Expect(scope.Name()).To(Equal("test"))
 					return &expinfrav1.AutoScalingGroup{
 						Name: scope.Name(),
 					}, nil
 				})
 
-				err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 				g.Expect(err).To(Succeed())
+			})
 
-				g.Expect(ms.AWSMachinePool.Status.LaunchTemplateID).ToNot(BeEmpty())
-				g.Expect(ptr.Deref[string](ms.AWSMachinePool.Status.LaunchTemplateVersion, "")).ToNot(BeEmpty())
+			t.Run("launch template and ASG exist and need no update", func(t *testing.T) {
+				// Latest ID and version already stored, no need to retrieve it
+				ms.AWSMachinePool.Status.LaunchTemplateID = launchTemplateIDExisting
+				ms.AWSMachinePool.Status.LaunchTemplateVersion = ptr.To[string]("1")
 
-				// Data secret name changes
-				newBootstrapSecret := &corev1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      "bootstrap-data-new", // changed
-						Namespace: "default",
+				ec2Svc.EXPECT().GetLaunchTemplate(gomock.Eq("test")).Return(
+					&expinfrav1.AWSLaunchTemplate{
+						Name: "test",
+						AMI: infrav1.AMIReference{
+							ID: ptr.To[string]("ami-existing"),
+						},
 					},
-					Data: map[string][]byte{
-						"value": secret.Data["value"], // not changed
+					// No change to user data
+					userdata.ComputeHash([]byte("shell-script")),
+					&userDataSecretKey,
+					nil)
+				ec2Svc.EXPECT().DiscoverLaunchTemplateAMI(gomock.Any()).Return(ptr.To[string]("ami-existing"), nil) // no change
+				ec2Svc.EXPECT().LaunchTemplateNeedsUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+
+				asgSvc.EXPECT().GetASGByName(gomock.Any()).DoAndReturn(func(scope *scope.MachinePoolScope) (*expinfrav1.AutoScalingGroup, error) {
+					g.Expect(scope.Name()).To(Equal("test"))
+
+					// No difference to `AWSMachinePool.spec`
+					return &expinfrav1.AutoScalingGroup{
+						Name: scope.Name(),
+						Subnets: []string{
+							"subnet-1",
+						},
+						MinSize:              awsMachinePool.Spec.MinSize,
+						MaxSize:              awsMachinePool.Spec.MaxSize,
+						MixedInstancesPolicy: awsMachinePool.Spec.MixedInstancesPolicy.DeepCopy(),
+					}, nil
+				})
+				asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{"subnet-1"}, nil) // no change
+				asgSvc.EXPECT().DescribeScalingActivities(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().ReconcileASGTags(gomock.Any(), gomock.Any()).Return(nil)
+				// No changes, so there must not be an ASG update!
+				asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Times(0)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+			})
+
+			t.Run("launch template and ASG exist and only AMI ID changed", func(t *testing.T) {
+				// Latest ID and version already stored, no need to retrieve it
+				ms.AWSMachinePool.Status.LaunchTemplateID = launchTemplateIDExisting
+				ms.AWSMachinePool.Status.LaunchTemplateVersion = ptr.To[string]("1")
+
+				ec2Svc.EXPECT().GetLaunchTemplate(gomock.Eq("test")).Return(
+					&expinfrav1.AWSLaunchTemplate{
+						Name: "test",
+						AMI: infrav1.AMIReference{
+							ID: ptr.To[string]("ami-existing"),
+						},
 					},
-				}
-				g.Expect(testEnv.Create(ctx, newBootstrapSecret)).To(Succeed())
-				ms.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName = ptr.To[string](newBootstrapSecret.Name)
+					// No change to user data
+					userdata.ComputeHash([]byte("shell-script")),
+					&userDataSecretKey,
+					nil)
+				ec2Svc.EXPECT().DiscoverLaunchTemplateAMI(gomock.Any()).Return(ptr.To[string]("ami-different"), nil)
+				ec2Svc.EXPECT().LaunchTemplateNeedsUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+				asgSvc.EXPECT().CanStartASGInstanceRefresh(gomock.Any()).Return(true, nil)
+				ec2Svc.EXPECT().PruneLaunchTemplateVersions(gomock.Any()).Return(nil)
+				ec2Svc.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any(), gomock.Eq(ptr.To[string]("ami-different")), gomock.Eq(apimachinerytypes.NamespacedName{Namespace: "default", Name: "bootstrap-data"}), gomock.Any()).Return(nil)
+				ec2Svc.EXPECT().GetLaunchTemplateLatestVersion(gomock.Any()).Return("2", nil)
+				// AMI change should trigger rolling out new nodes
+				asgSvc.EXPECT().StartASGInstanceRefresh(gomock.Any())
+
+				asgSvc.EXPECT().GetASGByName(gomock.Any()).DoAndReturn(func(scope *scope.MachinePoolScope) (*expinfrav1.AutoScalingGroup, error) {
+					g.Expect(scope.Name()).To(Equal("test"))
+
+					// No difference to `AWSMachinePool.spec`
+					return &expinfrav1.AutoScalingGroup{
+						Name: scope.Name(),
+						Subnets: []string{
+							"subnet-1",
+						},
+						MinSize:              awsMachinePool.Spec.MinSize,
+						MaxSize:              awsMachinePool.Spec.MaxSize,
+						MixedInstancesPolicy: awsMachinePool.Spec.MixedInstancesPolicy.DeepCopy(),
+					}, nil
+				})
+				asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{"subnet-1"}, nil) // no change
+				asgSvc.EXPECT().DescribeScalingActivities(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().ReconcileASGTags(gomock.Any(), gomock.Any()).Return(nil)
+				// The lifecycle hooks did not change since they were synced by a previous reconcile
+				// No changes, so there must not be an ASG update!
+				asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Times(0)
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+			})
+
+			t.Run("launch template and ASG exist and only bootstrap data secret name changed", func(t *testing.T) {
+				// Latest ID and version already stored, no need to retrieve it
+				ms.AWSMachinePool.Status.LaunchTemplateID = launchTemplateIDExisting
+				ms.AWSMachinePool.Status.LaunchTemplateVersion = ptr.To[string]("1")
 
 				ec2Svc.EXPECT().GetLaunchTemplate(gomock.Eq("test")).Return(
 					&expinfrav1.AWSLaunchTemplate{
@@ -672,19 +1191,20 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 							ID: ptr.To[string]("ami-existing"),
 						},
 					},
-					// No change to user data content
+					// No change to user data
 					userdata.ComputeHash([]byte("shell-script")),
-					&apimachinerytypes.NamespacedName{Namespace: "default", Name: "bootstrap-data"},
+					// But the name of the secret changes from `previous-secret-name` to `bootstrap-data`
+					&apimachinerytypes.NamespacedName{Namespace: "default", Name: "previous-secret-name"},
 					nil)
 				ec2Svc.EXPECT().DiscoverLaunchTemplateAMI(gomock.Any()).Return(ptr.To[string]("ami-existing"), nil)
 				ec2Svc.EXPECT().LaunchTemplateNeedsUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
 				asgSvc.EXPECT().CanStartASGInstanceRefresh(gomock.Any()).Return(true, nil)
 				ec2Svc.EXPECT().PruneLaunchTemplateVersions(gomock.Any()).Return(nil)
-				ec2Svc.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any(), gomock.Eq(ptr.To[string]("ami-existing")), gomock.Eq(apimachinerytypes.NamespacedName{Namespace: "default", Name: "bootstrap-data-new"}), gomock.Any()).Return(nil)
+				ec2Svc.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any(), gomock.Eq(ptr.To[string]("ami-existing")), gomock.Eq(apimachinerytypes.NamespacedName{Namespace: "default", Name: "bootstrap-data"}), gomock.Any()).Return(nil)
 				ec2Svc.EXPECT().GetLaunchTemplateLatestVersion(gomock.Any()).Return("2", nil)
 				// Changing the bootstrap data secret name should trigger rolling out new nodes, no matter what the
 				// content (user data) is. This way, users can enforce a rollout by changing the bootstrap config
-				// reference (`MachinePool.spec.template.spec.bootstrap.configRef`).
+				// reference (`MachinePool.spec.template.spec.bootstrap`).
 				asgSvc.EXPECT().StartASGInstanceRefresh(gomock.Any())
 
 				asgSvc.EXPECT().GetASGByName(gomock.Any()).DoAndReturn(func(scope *scope.MachinePoolScope) (*expinfrav1.AutoScalingGroup, error) {
@@ -702,22 +1222,141 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 					}, nil
 				})
 				asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{"subnet-1"}, nil) // no change
+				asgSvc.EXPECT().DescribeScalingActivities(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().ReconcileASGTags(gomock.Any(), gomock.Any()).Return(nil)
+				// The lifecycle hooks did not change since they were synced by a previous reconcile
 				// No changes, so there must not be an ASG update!
-				asgSvc.EXPECT().UpdateASG(gomock.Any()).Times(0)
+				asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Times(0)
 
-				err = reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
 				g.Expect(err).To(Succeed())
 			})
-		})
-	})
 
-	t.Run("Deleting an AWSMachinePool", func(t *testing.T) {
-		finalizer := func(t *testing.T, g *WithT) {
-			t.Helper()
+			t.Run("launch template and ASG created from zero, then bootstrap config reference changes", func(t *testing.T) {
+				ec2Svc.EXPECT().GetLaunchTemplate(gomock.Eq("test")).Return(nil, "", nil, nil)
+				ec2Svc.EXPECT().DiscoverLaunchTemplateAMI(gomock.Any()).Return(ptr.To[string]("ami-abcdef123"), nil)
+				ec2Svc.EXPECT().CreateLaunchTemplate(gomock.Any(), gomock.Eq(ptr.To[string]("ami-abcdef123")), gomock.Eq(userDataSecretKey), gomock.Eq([]byte("shell-script"))).Return("lt-ghijkl456", nil)
+				asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().CreateASG(gomock.Any()).DoAndReturn(func(scope *scope.MachinePoolScope) (*expinfrav1.AutoScalingGroup, error) {
+					g.Expect(scope.Name()).To(Equal("test"))
+					return &expinfrav1.AutoScalingGroup{
+						Name: scope.Name(),
+					}, nil
+				})
 
-			ms.AWSMachinePool.Finalizers = []string{
-				expinfrav1.MachinePoolFinalizer,
-				metav1.FinalizerDeleteDependents,
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+
+				g.Expect(ms.AWSMachinePool.Status.LaunchTemplateID).ToNot(BeEmpty())
+				g.Expect(ptr.Deref[string](ms.AWSMachinePool.Status.LaunchTemplateVersion, "")).ToNot(BeEmpty())
+
+				// Data secret name changes
+				newBootstrapSecret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "bootstrap-data-new", // changed
+						Namespace: "default",
+					},
+					Data: map[string][]byte{
+						"value": secret.Data["value"], // not changed
+					},
+				}
+				g.Expect(testEnv.Create(ctx, newBootstrapSecret)).To(Succeed())
+				ms.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName = ptr.To[string](newBootstrapSecret.Name)
+
+				ec2Svc.EXPECT().GetLaunchTemplate(gomock.Eq("test")).Return(
+					&expinfrav1.AWSLaunchTemplate{
+						Name: "test",
+						AMI: infrav1.AMIReference{
+							ID: ptr.To[string]("ami-existing"),
+						},
+					},
+					// No change to user data content
+					userdata.ComputeHash([]byte("shell-script")),
+					&apimachinerytypes.NamespacedName{Namespace: "default", Name: "bootstrap-data"},
+					nil)
+				ec2Svc.EXPECT().DiscoverLaunchTemplateAMI(gomock.Any()).Return(ptr.To[string]("ami-existing"), nil)
+				ec2Svc.EXPECT().LaunchTemplateNeedsUpdate(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+				asgSvc.EXPECT().CanStartASGInstanceRefresh(gomock.Any()).Return(true, nil)
+				ec2Svc.EXPECT().PruneLaunchTemplateVersions(gomock.Any()).Return(nil)
+				ec2Svc.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any(), gomock.Eq(ptr.To[string]("ami-existing")), gomock.Eq(apimachinerytypes.NamespacedName{Namespace: "default", Name: "bootstrap-data-new"}), gomock.Any()).Return(nil)
+				ec2Svc.EXPECT().GetLaunchTemplateLatestVersion(gomock.Any()).Return("2", nil)
+				// Changing the bootstrap data secret name should trigger rolling out new nodes, no matter what the
+				// content (user data) is. This way, users can enforce a rollout by changing the bootstrap config
+				// reference (`MachinePool.spec.template.spec.bootstrap.configRef`).
+				asgSvc.EXPECT().StartASGInstanceRefresh(gomock.Any())
+
+				asgSvc.EXPECT().GetASGByName(gomock.Any()).DoAndReturn(func(scope *scope.MachinePoolScope) (*expinfrav1.AutoScalingGroup, error) {
+					g.Expect(scope.Name()).To(Equal("test"))
+
+					// No difference to `AWSMachinePool.spec`
+					return &expinfrav1.AutoScalingGroup{
+						Name: scope.Name(),
+						Subnets: []string{
+							"subnet-1",
+						},
+						MinSize:              awsMachinePool.Spec.MinSize,
+						MaxSize:              awsMachinePool.Spec.MaxSize,
+						MixedInstancesPolicy: awsMachinePool.Spec.MixedInstancesPolicy.DeepCopy(),
+					}, nil
+				})
+				asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{"subnet-1"}, nil) // no change
+				asgSvc.EXPECT().DescribeScalingActivities(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().ReconcileASGTags(gomock.Any(), gomock.Any()).Return(nil)
+				// The lifecycle hooks did not change since they were synced by a previous reconcile
+				// No changes, so there must not be an ASG update!
+				asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Times(0)
+
+				_, err = reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+			})
+
+			t.Run("existing launch template version changed and FollowLatest is set", func(t *testing.T) {
+				ms.AWSMachinePool.Spec.AWSLaunchTemplate.ExistingTemplate = &expinfrav1.ExistingLaunchTemplate{
+					ID:           "lt-0123456789abcdef0",
+					FollowLatest: true,
+				}
+				defer func() { ms.AWSMachinePool.Spec.AWSLaunchTemplate.ExistingTemplate = nil }()
+				ms.AWSMachinePool.Status.LaunchTemplateID = "lt-0123456789abcdef0"
+				ms.AWSMachinePool.Status.LaunchTemplateVersion = ptr.To[string]("3")
+
+				// The existing launch template must never be modified
+				ec2Svc.EXPECT().GetLaunchTemplateVersion(gomock.Eq("lt-0123456789abcdef0"), gomock.Eq("$Latest")).Return("4", nil)
+				asgSvc.EXPECT().CanStartASGInstanceRefresh(gomock.Any()).Return(true, nil)
+				asgSvc.EXPECT().UpdateASG(gomock.Any(), gomock.Any()).Return(nil)
+				asgSvc.EXPECT().StartASGInstanceRefresh(gomock.Any())
+
+				asgSvc.EXPECT().GetASGByName(gomock.Any()).DoAndReturn(func(scope *scope.MachinePoolScope) (*expinfrav1.AutoScalingGroup, error) {
+					return &expinfrav1.AutoScalingGroup{
+						Name: scope.Name(),
+						Subnets: []string{
+							"subnet-1",
+						},
+						MinSize:               awsMachinePool.Spec.MinSize,
+						MaxSize:               awsMachinePool.Spec.MaxSize,
+						MixedInstancesPolicy:  awsMachinePool.Spec.MixedInstancesPolicy.DeepCopy(),
+						LaunchTemplateID:      "lt-0123456789abcdef0",
+						LaunchTemplateVersion: "$Latest",
+					}, nil
+				})
+				asgSvc.EXPECT().SubnetIDs(gomock.Any()).Return([]string{"subnet-1"}, nil) // no change
+				asgSvc.EXPECT().DescribeScalingActivities(gomock.Any()).Return(nil, nil)
+				asgSvc.EXPECT().ReconcileASGTags(gomock.Any(), gomock.Any()).Return(nil)
+				// The lifecycle hooks did not change since they were synced by a previous reconcile
+
+				_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs)
+				g.Expect(err).To(Succeed())
+				g.Expect(ptr.Deref[string](ms.AWSMachinePool.Status.LaunchTemplateVersion, "")).To(Equal("4"))
+			})
+		})
+	})
+
+	t.Run("Deleting an AWSMachinePool", func(t *testing.T) {
+		finalizer := func(t *testing.T, g *WithT) {
+			t.Helper()
+
+			ms.AWSMachinePool.Finalizers = []string{
+				expinfrav1.MachinePoolFinalizer,
+				metav1.FinalizerDeleteDependents,
 			}
 		}
 		t.Run("should exit immediately on an error state", func(t *testing.T) {
@@ -729,7 +1368,7 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 			expectedErr := errors.New("no connection available ")
 			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(nil, expectedErr).AnyTimes()
 
-			err := reconciler.reconcileDelete(ms, cs, cs)
+			_, err := reconciler.reconcileDelete(ms, cs, cs)
 			g.Expect(errors.Cause(err)).To(MatchError(expectedErr))
 		})
 		t.Run("should log and remove finalizer when no machinepool exists", func(t *testing.T) {
@@ -739,12 +1378,12 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 			finalizer(t, g)
 
 			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(nil, nil)
-			ec2Svc.EXPECT().GetLaunchTemplate(gomock.Any()).Return(nil, "", nil, nil).AnyTimes()
+			ec2Svc.EXPECT().GetLaunchTemplateID(gomock.Any()).Return("", nil).AnyTimes()
 
 			buf := new(bytes.Buffer)
 			klog.SetOutput(buf)
 
-			err := reconciler.reconcileDelete(ms, cs, cs)
+			_, err := reconciler.reconcileDelete(ms, cs, cs)
 			g.Expect(err).To(BeNil())
 			g.Expect(buf.String()).To(ContainSubstring("Unable to locate ASG"))
 			g.Expect(ms.AWSMachinePool.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
@@ -761,15 +1400,237 @@ func TestAWSMachinePoolReconciler(t *testing.T) {
 				Status: expinfrav1.ASGStatusDeleteInProgress,
 			}
 			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(&inProgressASG, nil)
-			ec2Svc.EXPECT().GetLaunchTemplate(gomock.Any()).Return(nil, "", nil, nil).AnyTimes()
+			ec2Svc.EXPECT().GetLaunchTemplateID(gomock.Any()).Return("", nil).AnyTimes()
 
 			buf := new(bytes.Buffer)
 			klog.SetOutput(buf)
-			err := reconciler.reconcileDelete(ms, cs, cs)
+			res, err := reconciler.reconcileDelete(ms, cs, cs)
 			g.Expect(err).To(BeNil())
+			g.Expect(res.RequeueAfter).To(BeNumerically(">", 0))
 			g.Expect(ms.AWSMachinePool.Status.Ready).To(BeFalse())
 			g.Eventually(recorder.Events).Should(Receive(ContainSubstring("DeletionInProgress")))
 		})
+		t.Run("should not delete an existing launch template", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(t, g)
+			defer teardown(t, g)
+			finalizer(t, g)
+
+			ms.AWSMachinePool.Spec.AWSLaunchTemplate.ExistingTemplate = &expinfrav1.ExistingLaunchTemplate{ID: "lt-0123456789abcdef0"}
+			ms.AWSMachinePool.Status.LaunchTemplateID = "lt-0123456789abcdef0"
+
+			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(nil, nil)
+			ec2Svc.EXPECT().GetLaunchTemplateID(gomock.Any()).Times(0)
+			ec2Svc.EXPECT().DeleteLaunchTemplate(gomock.Any()).Times(0)
+
+			_, err := reconciler.reconcileDelete(ms, cs, cs)
+			g.Expect(err).To(BeNil())
+			g.Expect(ms.AWSMachinePool.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+		})
+		t.Run("should not delete the lifecycle hooks before the deletion of the ASG is forced", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(t, g)
+			defer teardown(t, g)
+			finalizer(t, g)
+
+			ms.AWSMachinePool.Spec.AWSLaunchTemplate.ExistingTemplate = &expinfrav1.ExistingLaunchTemplate{ID: "lt-0123456789abcdef0"}
+			ms.AWSMachinePool.Spec.AWSLifecycleHooks = []infrav1.AWSLifecycleHook{{Name: "launch-hook", LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch}}
+
+			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(&expinfrav1.AutoScalingGroup{Name: "an-asg"}, nil)
+			asgSvc.EXPECT().DescribeLifecycleHooks(gomock.Any()).Times(0)
+			asgSvc.EXPECT().DeleteLifecycleHook(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			asgSvc.EXPECT().ReconcileASGDeletion(&expinfrav1.AutoScalingGroup{Name: "an-asg"}, nil, DefaultASGDeletionTimeout).
+				Return(&expinfrav1.ASGDeletionStatus{Phase: expinfrav1.ASGDeletionPhaseScalingToZero}, false, nil)
+
+			res, err := reconciler.reconcileDelete(ms, cs, cs)
+			g.Expect(err).To(BeNil())
+			// The finalizer is removed once the ASG is gone.
+			g.Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+			g.Expect(ms.AWSMachinePool.Finalizers).To(ContainElement(expinfrav1.MachinePoolFinalizer))
+			g.Expect(ms.AWSMachinePool.Status.Deletion.Phase).To(Equal(expinfrav1.ASGDeletionPhaseScalingToZero))
+			g.Eventually(recorder.Events).Should(Receive(ContainSubstring("ScalingToZero")))
+		})
+		t.Run("should force delete the managed lifecycle hooks and the ASG once the deletion timeout expires", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(t, g)
+			defer teardown(t, g)
+			finalizer(t, g)
+
+			launchHook := infrav1.AWSLifecycleHook{Name: "launch-hook", LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch}
+			terminateHook := infrav1.AWSLifecycleHook{Name: "terminate-hook", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate}
+			userHook := infrav1.AWSLifecycleHook{Name: "user-hook", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate}
+			ms.AWSMachinePool.Spec.AWSLifecycleHooks = []infrav1.AWSLifecycleHook{launchHook, terminateHook}
+			ms.AWSMachinePool.Spec.DeletionTimeout = &metav1.Duration{Duration: time.Minute}
+			ms.AWSMachinePool.Status.Deletion = &expinfrav1.ASGDeletionStatus{
+				Phase:          expinfrav1.ASGDeletionPhaseDeleting,
+				PhaseStartTime: &metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+			}
+			asg := &expinfrav1.AutoScalingGroup{Name: "an-asg"}
+			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(asg, nil)
+			gomock.InOrder(
+				asgSvc.EXPECT().DescribeLifecycleHooks("an-asg").Return([]*infrav1.AWSLifecycleHook{&launchHook, &userHook, &terminateHook}, nil),
+				asgSvc.EXPECT().DeleteLifecycleHook("an-asg", &launchHook, true).Return(nil),
+				// A hook that can't be deleted must not block the deletion of the ASG.
+				asgSvc.EXPECT().DeleteLifecycleHook("an-asg", &terminateHook, true).Return(errors.New("error")),
+				asgSvc.EXPECT().ReconcileASGDeletion(asg, ms.AWSMachinePool.Status.Deletion, time.Minute).
+					Return(&expinfrav1.ASGDeletionStatus{Phase: expinfrav1.ASGDeletionPhaseForceDeleting}, true, nil),
+			)
+
+			_, err := reconciler.reconcileDelete(ms, cs, cs)
+			g.Expect(err).To(BeNil())
+			g.Expect(ms.AWSMachinePool.Status.Deletion.Phase).To(Equal(expinfrav1.ASGDeletionPhaseForceDeleting))
+			g.Eventually(recorder.Events).Should(Receive(ContainSubstring("ForceDeletingASG")))
+		})
+		t.Run("should force delete the managed lifecycle hooks by descending priority", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(t, g)
+			defer teardown(t, g)
+			finalizer(t, g)
+
+			launchHook := infrav1.AWSLifecycleHook{Name: "launch-hook", LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch, Priority: -1}
+			drainHook := infrav1.AWSLifecycleHook{Name: "drain-hook", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate, Priority: 5}
+			terminateHook := infrav1.AWSLifecycleHook{Name: "terminate-hook", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate, Priority: 10}
+			ms.AWSMachinePool.Spec.AWSLifecycleHooks = []infrav1.AWSLifecycleHook{launchHook, terminateHook, drainHook}
+			ms.AWSMachinePool.Spec.DeletionTimeout = &metav1.Duration{Duration: time.Minute}
+			ms.AWSMachinePool.Status.Deletion = &expinfrav1.ASGDeletionStatus{
+				Phase:          expinfrav1.ASGDeletionPhaseDeleting,
+				PhaseStartTime: &metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+			}
+			// The described hooks don't carry the priority of the spec.
+			described := []*infrav1.AWSLifecycleHook{
+				{Name: "launch-hook", LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch},
+				{Name: "drain-hook", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate},
+				{Name: "terminate-hook", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate},
+			}
+			asg := &expinfrav1.AutoScalingGroup{Name: "an-asg"}
+			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(asg, nil)
+			gomock.InOrder(
+				asgSvc.EXPECT().DescribeLifecycleHooks("an-asg").Return(described, nil),
+				asgSvc.EXPECT().DeleteLifecycleHook("an-asg", described[2], true).Return(nil),
+				asgSvc.EXPECT().DeleteLifecycleHook("an-asg", described[1], true).Return(nil),
+				asgSvc.EXPECT().DeleteLifecycleHook("an-asg", described[0], true).Return(nil),
+				asgSvc.EXPECT().ReconcileASGDeletion(asg, ms.AWSMachinePool.Status.Deletion, time.Minute).
+					Return(&expinfrav1.ASGDeletionStatus{Phase: expinfrav1.ASGDeletionPhaseForceDeleting}, true, nil),
+			)
+
+			_, err := reconciler.reconcileDelete(ms, cs, cs)
+			g.Expect(err).To(BeNil())
+			g.Expect(ms.AWSMachinePool.Status.Deletion.Phase).To(Equal(expinfrav1.ASGDeletionPhaseForceDeleting))
+		})
+		t.Run("should delete the launch template once the ASG is deleted", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(t, g)
+			defer teardown(t, g)
+			finalizer(t, g)
+
+			ms.AWSMachinePool.Status.Deletion = &expinfrav1.ASGDeletionStatus{Phase: expinfrav1.ASGDeletionPhaseDeleting}
+			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(nil, nil)
+			ec2Svc.EXPECT().GetLaunchTemplateID(ms.LaunchTemplateName()).Return("lt-0123456789abcdef0", nil)
+			ec2Svc.EXPECT().DeleteLaunchTemplate("lt-0123456789abcdef0").Return(nil)
+
+			_, err := reconciler.reconcileDelete(ms, cs, cs)
+			g.Expect(err).To(BeNil())
+			g.Expect(ms.AWSMachinePool.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+			g.Consistently(recorder.Events).ShouldNot(Receive(ContainSubstring(expinfrav1.ASGNotFoundReason)))
+		})
+		t.Run("should delete the launch template of a pool whose ASG was never created", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(t, g)
+			defer teardown(t, g)
+			finalizer(t, g)
+
+			// The status wasn't patched after the launch template was created.
+			ms.AWSMachinePool.Status.LaunchTemplateID = ""
+			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(nil, nil)
+			ec2Svc.EXPECT().GetLaunchTemplateID(ms.LaunchTemplateName()).Return("lt-0123456789abcdef0", nil)
+			ec2Svc.EXPECT().DeleteLaunchTemplate("lt-0123456789abcdef0").Return(nil)
+
+			_, err := reconciler.reconcileDelete(ms, cs, cs)
+			g.Expect(err).To(BeNil())
+			g.Expect(ms.AWSMachinePool.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+		})
+		t.Run("should delete the launch template replaced by an existing launch template", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(t, g)
+			defer teardown(t, g)
+			finalizer(t, g)
+
+			ms.AWSMachinePool.Spec.AWSLaunchTemplate.ExistingTemplate = &expinfrav1.ExistingLaunchTemplate{ID: "lt-0123456789abcdef0"}
+			ms.AWSMachinePool.Status.ReplacedLaunchTemplateID = "lt-00000000000000001"
+			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(nil, nil)
+			ec2Svc.EXPECT().DeleteLaunchTemplate("lt-00000000000000001").Return(nil)
+
+			_, err := reconciler.reconcileDelete(ms, cs, cs)
+			g.Expect(err).To(BeNil())
+			g.Expect(ms.AWSMachinePool.Status.ReplacedLaunchTemplateID).To(BeEmpty())
+			g.Expect(ms.AWSMachinePool.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+		})
+		t.Run("should keep the ASG and its launch template when keepOnDelete is set", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(t, g)
+			defer teardown(t, g)
+			finalizer(t, g)
+
+			ms.AWSMachinePool.Spec.KeepOnDelete = true
+			clusterName := cs.KubernetesClusterName()
+			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(&expinfrav1.AutoScalingGroup{
+				Name: "an-asg",
+				Tags: infrav1.Tags{
+					infrav1.ClusterTagKey(clusterName):                 string(infrav1.ResourceLifecycleOwned),
+					infrav1.ClusterAWSCloudProviderTagKey(clusterName): string(infrav1.ResourceLifecycleOwned),
+					infrav1.MachinePoolTagKey:                          ms.NamespacedName(),
+					"team":                                             "blue",
+				},
+			}, nil)
+			asgSvc.EXPECT().UpdateResourceTags(ptr.To("an-asg"), nil, infrav1.Tags{
+				infrav1.ClusterTagKey(clusterName):                 string(infrav1.ResourceLifecycleOwned),
+				infrav1.ClusterAWSCloudProviderTagKey(clusterName): string(infrav1.ResourceLifecycleOwned),
+				infrav1.MachinePoolTagKey:                          ms.NamespacedName(),
+			}).Return(nil)
+			asgSvc.EXPECT().ReconcileASGDeletion(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			ec2Svc.EXPECT().DeleteLaunchTemplate(gomock.Any()).Times(0)
+
+			_, err := reconciler.reconcileDelete(ms, cs, cs)
+			g.Expect(err).To(BeNil())
+			g.Expect(ms.AWSMachinePool.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+			g.Eventually(recorder.Events).Should(Receive(ContainSubstring("KeptASG")))
+		})
+		t.Run("should not delete a named ASG that was never adopted", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(t, g)
+			defer teardown(t, g)
+			finalizer(t, g)
+
+			ms.AWSMachinePool.Spec.AutoScalingGroupName = "legacy-asg"
+			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(&expinfrav1.AutoScalingGroup{Name: "legacy-asg"}, nil)
+			asgSvc.EXPECT().ReconcileASGDeletion(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			ec2Svc.EXPECT().GetLaunchTemplateID(gomock.Any()).Return("", nil)
+
+			_, err := reconciler.reconcileDelete(ms, cs, cs)
+			g.Expect(err).To(BeNil())
+			g.Expect(ms.AWSMachinePool.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+		})
+		t.Run("should not delete an ASG managed by another AWSMachinePool", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(t, g)
+			defer teardown(t, g)
+			finalizer(t, g)
+
+			ms.AWSMachinePool.Spec.AutoScalingGroupName = "other-asg"
+			asgSvc.EXPECT().GetASGByName(gomock.Any()).Return(&expinfrav1.AutoScalingGroup{
+				Name: "other-asg",
+				Tags: infrav1.Tags{
+					infrav1.ClusterTagKey(cs.KubernetesClusterName()): string(infrav1.ResourceLifecycleOwned),
+					infrav1.MachinePoolTagKey:                         ms.Namespace() + "/other",
+				},
+			}, nil)
+			asgSvc.EXPECT().ReconcileASGDeletion(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			ec2Svc.EXPECT().GetLaunchTemplateID(gomock.Any()).Return("", nil)
+
+			_, err := reconciler.reconcileDelete(ms, cs, cs)
+			g.Expect(err).To(BeNil())
+			g.Expect(ms.AWSMachinePool.Finalizers).To(ConsistOf(metav1.FinalizerDeleteDependents))
+		})
 	})
 }
 
@@ -830,6 +1691,7 @@ func TestDiffASG(t *testing.T) {
 							Replicas: ptr.To[int32](0),
 						},
 					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{},
 				},
 				existingASG: &expinfrav1.AutoScalingGroup{
 					DesiredCapacity: ptr.To[int32](1),
@@ -837,6 +1699,58 @@ func TestDiffASG(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "scheduled actions own the desired capacity and the sizes they set",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](0),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MinSize: 0,
+							MaxSize: 2,
+							ScheduledActions: []expinfrav1.ScheduledAction{
+								{Name: "scale-up", Recurrence: "0 8 * * *", MaxSize: ptr.To[int32](10), DesiredCapacity: ptr.To[int32](5)},
+							},
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity: ptr.To[int32](5),
+					MaxSize:         10,
+				},
+			},
+			want: false,
+		},
+		{
+			name: "scheduled actions don't own the sizes they don't set",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](0),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MinSize: 1,
+							MaxSize: 10,
+							ScheduledActions: []expinfrav1.ScheduledAction{
+								{Name: "scale-up", Recurrence: "0 8 * * *", MaxSize: ptr.To[int32](10), DesiredCapacity: ptr.To[int32](5)},
+							},
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity: ptr.To[int32](5),
+					MaxSize:         10,
+				},
+			},
+			want: true,
+		},
 		{
 			name: "replicas (nil) != asg.desiredCapacity",
 			args: args{
@@ -846,6 +1760,7 @@ func TestDiffASG(t *testing.T) {
 							Replicas: nil,
 						},
 					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{},
 				},
 				existingASG: &expinfrav1.AutoScalingGroup{
 					DesiredCapacity: ptr.To[int32](1),
@@ -862,6 +1777,7 @@ func TestDiffASG(t *testing.T) {
 							Replicas: ptr.To[int32](0),
 						},
 					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{},
 				},
 				existingASG: &expinfrav1.AutoScalingGroup{
 					DesiredCapacity: nil,
@@ -941,6 +1857,31 @@ func TestDiffASG(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "newInstancesProtectedFromScaleIn != asg.newInstancesProtectedFromScaleIn",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](1),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize:                          2,
+							MinSize:                          0,
+							NewInstancesProtectedFromScaleIn: true,
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity: ptr.To[int32](1),
+					MaxSize:         2,
+					MinSize:         0,
+				},
+			},
+			want: true,
+		},
 		{
 			name: "MixedInstancesPolicy != asg.MixedInstancesPolicy",
 			args: args{
@@ -1029,7 +1970,7 @@ func TestDiffASG(t *testing.T) {
 			want: true,
 		},
 		{
-			name: "MixedInstancesPolicy.InstancesDistribution unset",
+			name: "MixedInstancesPolicy.InstancesDistribution.OnDemandAllocationStrategy != asg.MixedInstancesPolicy.InstancesDistribution.OnDemandAllocationStrategy",
 			args: args{
 				machinePoolScope: &scope.MachinePoolScope{
 					MachinePool: &expclusterv1.MachinePool{
@@ -1043,6 +1984,12 @@ func TestDiffASG(t *testing.T) {
 							MinSize:           0,
 							CapacityRebalance: true,
 							MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+								InstancesDistribution: &expinfrav1.InstancesDistribution{
+									OnDemandAllocationStrategy:          expinfrav1.OnDemandAllocationStrategyLowestPrice,
+									SpotAllocationStrategy:              expinfrav1.SpotAllocationStrategyPriceCapacityOptimized,
+									OnDemandBaseCapacity:                aws.Int64(0),
+									OnDemandPercentageAboveBaseCapacity: aws.Int64(100),
+								},
 								Overrides: []expinfrav1.Overrides{
 									{
 										InstanceType: "m6a.32xlarge",
@@ -1061,7 +2008,7 @@ func TestDiffASG(t *testing.T) {
 					MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
 						InstancesDistribution: &expinfrav1.InstancesDistribution{
 							OnDemandAllocationStrategy:          expinfrav1.OnDemandAllocationStrategyPrioritized,
-							SpotAllocationStrategy:              expinfrav1.SpotAllocationStrategyLowestPrice,
+							SpotAllocationStrategy:              expinfrav1.SpotAllocationStrategyPriceCapacityOptimized,
 							OnDemandBaseCapacity:                aws.Int64(0),
 							OnDemandPercentageAboveBaseCapacity: aws.Int64(100),
 						},
@@ -1073,10 +2020,10 @@ func TestDiffASG(t *testing.T) {
 					},
 				},
 			},
-			want: false,
+			want: true,
 		},
 		{
-			name: "SuspendProcesses != asg.SuspendProcesses",
+			name: "MixedInstancesPolicy.InstancesDistribution.OnDemandPercentageAboveBaseCapacity != asg.MixedInstancesPolicy.InstancesDistribution.OnDemandPercentageAboveBaseCapacity",
 			args: args{
 				machinePoolScope: &scope.MachinePoolScope{
 					MachinePool: &expclusterv1.MachinePool{
@@ -1091,14 +2038,63 @@ func TestDiffASG(t *testing.T) {
 							CapacityRebalance: true,
 							MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
 								InstancesDistribution: &expinfrav1.InstancesDistribution{
-									OnDemandAllocationStrategy: expinfrav1.OnDemandAllocationStrategyPrioritized,
-								},
-								Overrides: nil,
+									OnDemandAllocationStrategy:          expinfrav1.OnDemandAllocationStrategyPrioritized,
+									SpotAllocationStrategy:              expinfrav1.SpotAllocationStrategyLowestPrice,
+									OnDemandBaseCapacity:                aws.Int64(0),
+									OnDemandPercentageAboveBaseCapacity: aws.Int64(0),
+								},
+								Overrides: []expinfrav1.Overrides{
+									{
+										InstanceType: "m6a.32xlarge",
+									},
+								},
 							},
-							SuspendProcesses: &expinfrav1.SuspendProcessesTypes{
-								Processes: &expinfrav1.Processes{
-									Launch:    ptr.To[bool](true),
-									Terminate: ptr.To[bool](true),
+						},
+					},
+					Logger: *logger.NewLogger(logr.Discard()),
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity:   ptr.To[int32](1),
+					MaxSize:           2,
+					MinSize:           0,
+					CapacityRebalance: true,
+					MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+						InstancesDistribution: &expinfrav1.InstancesDistribution{
+							OnDemandAllocationStrategy:          expinfrav1.OnDemandAllocationStrategyPrioritized,
+							SpotAllocationStrategy:              expinfrav1.SpotAllocationStrategyLowestPrice,
+							OnDemandBaseCapacity:                aws.Int64(0),
+							OnDemandPercentageAboveBaseCapacity: aws.Int64(100),
+						},
+						Overrides: []expinfrav1.Overrides{
+							{
+								InstanceType: "m6a.32xlarge",
+							},
+						},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "MixedInstancesPolicy.InstancesDistribution fields unset",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](1),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize:           2,
+							MinSize:           0,
+							CapacityRebalance: true,
+							MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+								InstancesDistribution: &expinfrav1.InstancesDistribution{},
+								Overrides: []expinfrav1.Overrides{
+									{
+										InstanceType: "m6a.32xlarge",
+									},
 								},
 							},
 						},
@@ -1106,18 +2102,77 @@ func TestDiffASG(t *testing.T) {
 					Logger: *logger.NewLogger(logr.Discard()),
 				},
 				existingASG: &expinfrav1.AutoScalingGroup{
-					DesiredCapacity:           ptr.To[int32](1),
-					MaxSize:                   2,
-					MinSize:                   0,
-					CapacityRebalance:         true,
-					MixedInstancesPolicy:      &expinfrav1.MixedInstancesPolicy{},
-					CurrentlySuspendProcesses: []string{"Launch", "Terminate"},
+					DesiredCapacity:   ptr.To[int32](1),
+					MaxSize:           2,
+					MinSize:           0,
+					CapacityRebalance: true,
+					MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+						InstancesDistribution: &expinfrav1.InstancesDistribution{
+							OnDemandAllocationStrategy:          expinfrav1.OnDemandAllocationStrategyPrioritized,
+							SpotAllocationStrategy:              expinfrav1.SpotAllocationStrategyLowestPrice,
+							OnDemandBaseCapacity:                aws.Int64(0),
+							OnDemandPercentageAboveBaseCapacity: aws.Int64(100),
+						},
+						Overrides: []expinfrav1.Overrides{
+							{
+								InstanceType: "m6a.32xlarge",
+							},
+						},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "MixedInstancesPolicy.Overrides.WeightedCapacity != asg.MixedInstancesPolicy.Overrides.WeightedCapacity",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](1),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize:           2,
+							MinSize:           0,
+							CapacityRebalance: true,
+							MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+								Overrides: []expinfrav1.Overrides{
+									{
+										InstanceType:     "m5.4xlarge",
+										WeightedCapacity: "2",
+									},
+								},
+							},
+						},
+					},
+					Logger: *logger.NewLogger(logr.Discard()),
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity:   ptr.To[int32](1),
+					MaxSize:           2,
+					MinSize:           0,
+					CapacityRebalance: true,
+					MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+						InstancesDistribution: &expinfrav1.InstancesDistribution{
+							OnDemandAllocationStrategy:          expinfrav1.OnDemandAllocationStrategyPrioritized,
+							SpotAllocationStrategy:              expinfrav1.SpotAllocationStrategyLowestPrice,
+							OnDemandBaseCapacity:                aws.Int64(0),
+							OnDemandPercentageAboveBaseCapacity: aws.Int64(100),
+						},
+						Overrides: []expinfrav1.Overrides{
+							{
+								InstanceType: "m5.4xlarge",
+							},
+						},
+					},
 				},
 			},
 			want: true,
 		},
 		{
-			name: "all matches",
+			name: "MixedInstancesPolicy.Overrides.WeightedCapacity == asg.MixedInstancesPolicy.Overrides.WeightedCapacity",
 			args: args{
 				machinePoolScope: &scope.MachinePoolScope{
 					MachinePool: &expclusterv1.MachinePool{
@@ -1131,13 +2186,16 @@ func TestDiffASG(t *testing.T) {
 							MinSize:           0,
 							CapacityRebalance: true,
 							MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
-								InstancesDistribution: &expinfrav1.InstancesDistribution{
-									OnDemandAllocationStrategy: expinfrav1.OnDemandAllocationStrategyPrioritized,
+								Overrides: []expinfrav1.Overrides{
+									{
+										InstanceType:     "m5.4xlarge",
+										WeightedCapacity: "2",
+									},
 								},
-								Overrides: nil,
 							},
 						},
 					},
+					Logger: *logger.NewLogger(logr.Discard()),
 				},
 				existingASG: &expinfrav1.AutoScalingGroup{
 					DesiredCapacity:   ptr.To[int32](1),
@@ -1146,62 +2204,2859 @@ func TestDiffASG(t *testing.T) {
 					CapacityRebalance: true,
 					MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
 						InstancesDistribution: &expinfrav1.InstancesDistribution{
-							OnDemandAllocationStrategy: expinfrav1.OnDemandAllocationStrategyPrioritized,
+							OnDemandAllocationStrategy:          expinfrav1.OnDemandAllocationStrategyPrioritized,
+							SpotAllocationStrategy:              expinfrav1.SpotAllocationStrategyLowestPrice,
+							OnDemandBaseCapacity:                aws.Int64(0),
+							OnDemandPercentageAboveBaseCapacity: aws.Int64(100),
+						},
+						Overrides: []expinfrav1.Overrides{
+							{
+								InstanceType:     "m5.4xlarge",
+								WeightedCapacity: "2",
+							},
 						},
-						Overrides: nil,
 					},
 				},
 			},
 			want: false,
 		},
 		{
-			name: "externally managed annotation ignores difference between desiredCapacity and replicas",
+			name: "MixedInstancesPolicy.Overrides.InstanceRequirements != asg.MixedInstancesPolicy.Overrides.InstanceRequirements",
 			args: args{
 				machinePoolScope: &scope.MachinePoolScope{
 					MachinePool: &expclusterv1.MachinePool{
-						ObjectMeta: metav1.ObjectMeta{
-							Annotations: map[string]string{
-								clusterv1.ReplicasManagedByAnnotation: "", // empty value counts as true (= externally managed)
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](1),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize:           2,
+							MinSize:           0,
+							CapacityRebalance: true,
+							MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+								InstancesDistribution: &expinfrav1.InstancesDistribution{
+									OnDemandAllocationStrategy: expinfrav1.OnDemandAllocationStrategyPrioritized,
+								},
+								Overrides: []expinfrav1.Overrides{
+									{
+										InstanceRequirements: &expinfrav1.InstanceRequirements{
+											VCPUCount: expinfrav1.VCPUCountRequest{Min: 4, Max: aws.Int64(16)},
+											MemoryMiB: expinfrav1.MemoryMiBRequest{Min: 8192},
+										},
+									},
+								},
+							},
+						},
+					},
+					Logger: *logger.NewLogger(logr.Discard()),
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity:   ptr.To[int32](1),
+					MaxSize:           2,
+					MinSize:           0,
+					CapacityRebalance: true,
+					MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+						InstancesDistribution: &expinfrav1.InstancesDistribution{
+							OnDemandAllocationStrategy: expinfrav1.OnDemandAllocationStrategyPrioritized,
+						},
+						Overrides: []expinfrav1.Overrides{
+							{
+								InstanceRequirements: &expinfrav1.InstanceRequirements{
+									VCPUCount: expinfrav1.VCPUCountRequest{Min: 4, Max: aws.Int64(8)},
+									MemoryMiB: expinfrav1.MemoryMiBRequest{Min: 8192},
+								},
 							},
 						},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "MixedInstancesPolicy.InstancesDistribution unset",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
 						Spec: expclusterv1.MachinePoolSpec{
-							Replicas: ptr.To[int32](0),
+							Replicas: ptr.To[int32](1),
 						},
 					},
 					AWSMachinePool: &expinfrav1.AWSMachinePool{
-						Spec: expinfrav1.AWSMachinePoolSpec{},
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize:           2,
+							MinSize:           0,
+							CapacityRebalance: true,
+							MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+								Overrides: []expinfrav1.Overrides{
+									{
+										InstanceType: "m6a.32xlarge",
+									},
+								},
+							},
+						},
 					},
+					Logger: *logger.NewLogger(logr.Discard()),
 				},
 				existingASG: &expinfrav1.AutoScalingGroup{
-					DesiredCapacity: ptr.To[int32](1),
+					DesiredCapacity:   ptr.To[int32](1),
+					MaxSize:           2,
+					MinSize:           0,
+					CapacityRebalance: true,
+					MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+						InstancesDistribution: &expinfrav1.InstancesDistribution{
+							OnDemandAllocationStrategy:          expinfrav1.OnDemandAllocationStrategyPrioritized,
+							SpotAllocationStrategy:              expinfrav1.SpotAllocationStrategyLowestPrice,
+							OnDemandBaseCapacity:                aws.Int64(0),
+							OnDemandPercentageAboveBaseCapacity: aws.Int64(100),
+						},
+						Overrides: []expinfrav1.Overrides{
+							{
+								InstanceType: "m6a.32xlarge",
+							},
+						},
+					},
 				},
 			},
 			want: false,
 		},
 		{
-			name: "without externally managed annotation ignores difference between desiredCapacity and replicas",
+			name: "SuspendProcesses != asg.SuspendProcesses",
 			args: args{
 				machinePoolScope: &scope.MachinePoolScope{
 					MachinePool: &expclusterv1.MachinePool{
 						Spec: expclusterv1.MachinePoolSpec{
-							Replicas: ptr.To[int32](0),
+							Replicas: ptr.To[int32](1),
 						},
 					},
 					AWSMachinePool: &expinfrav1.AWSMachinePool{
-						Spec: expinfrav1.AWSMachinePoolSpec{},
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize:           2,
+							MinSize:           0,
+							CapacityRebalance: true,
+							MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+								InstancesDistribution: &expinfrav1.InstancesDistribution{
+									OnDemandAllocationStrategy: expinfrav1.OnDemandAllocationStrategyPrioritized,
+								},
+								Overrides: nil,
+							},
+							SuspendProcesses: &expinfrav1.SuspendProcessesTypes{
+								Processes: &expinfrav1.Processes{
+									Launch:    ptr.To[bool](true),
+									Terminate: ptr.To[bool](true),
+								},
+							},
+						},
 					},
+					Logger: *logger.NewLogger(logr.Discard()),
 				},
 				existingASG: &expinfrav1.AutoScalingGroup{
-					DesiredCapacity: ptr.To[int32](1),
+					DesiredCapacity:           ptr.To[int32](1),
+					MaxSize:                   2,
+					MinSize:                   0,
+					CapacityRebalance:         true,
+					MixedInstancesPolicy:      &expinfrav1.MixedInstancesPolicy{},
+					CurrentlySuspendProcesses: []string{"Launch", "Terminate"},
 				},
 			},
 			want: true,
 		},
-	}
-	for _, tt := range tests {
+		{
+			name: "all matches",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](1),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize:           2,
+							MinSize:           0,
+							CapacityRebalance: true,
+							MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+								InstancesDistribution: &expinfrav1.InstancesDistribution{
+									OnDemandAllocationStrategy: expinfrav1.OnDemandAllocationStrategyPrioritized,
+								},
+								Overrides: nil,
+							},
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity:   ptr.To[int32](1),
+					MaxSize:           2,
+					MinSize:           0,
+					CapacityRebalance: true,
+					MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+						InstancesDistribution: &expinfrav1.InstancesDistribution{
+							OnDemandAllocationStrategy: expinfrav1.OnDemandAllocationStrategyPrioritized,
+						},
+						Overrides: nil,
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "termination policies in a different order",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](1),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize:             2,
+							MinSize:             0,
+							TerminationPolicies: []string{"OldestLaunchTemplate", "ClosestToNextInstanceHour"},
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity:     ptr.To[int32](1),
+					MaxSize:             2,
+					MinSize:             0,
+					TerminationPolicies: []string{"ClosestToNextInstanceHour", "OldestLaunchTemplate"},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "unset termination policies are not compared",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](1),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize:             2,
+							MinSize:             0,
+							TerminationPolicies: nil,
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity:     ptr.To[int32](1),
+					MaxSize:             2,
+					MinSize:             0,
+					TerminationPolicies: []string{"OldestInstance"},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "health check grace period differs",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](1),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize:                2,
+							MinSize:                0,
+							HealthCheckType:        expinfrav1.HealthCheckTypeELB,
+							HealthCheckGracePeriod: &metav1.Duration{Duration: 5 * time.Minute},
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity:        ptr.To[int32](1),
+					MaxSize:                2,
+					MinSize:                0,
+					HealthCheckType:        expinfrav1.HealthCheckTypeELB,
+					HealthCheckGracePeriod: &metav1.Duration{Duration: 0},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "default instance warmup disabled explicitly differs",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](1),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize:               2,
+							MinSize:               0,
+							DefaultInstanceWarmup: &metav1.Duration{Duration: 0},
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity: ptr.To[int32](1),
+					MaxSize:         2,
+					MinSize:         0,
+				},
+			},
+			want: true,
+		},
+		{
+			name: "unset default instance warmup is not compared",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](1),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize: 2,
+							MinSize: 0,
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity:       ptr.To[int32](1),
+					MaxSize:               2,
+					MinSize:               0,
+					DefaultInstanceWarmup: &metav1.Duration{Duration: 300 * time.Second},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "unset health check is not compared",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](1),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize: 2,
+							MinSize: 0,
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity:        ptr.To[int32](1),
+					MaxSize:                2,
+					MinSize:                0,
+					HealthCheckType:        expinfrav1.HealthCheckTypeEC2,
+					HealthCheckGracePeriod: &metav1.Duration{Duration: 300 * time.Second},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "max instance lifetime differs",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](1),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize:             2,
+							MinSize:             0,
+							MaxInstanceLifetime: &metav1.Duration{Duration: 14 * 24 * time.Hour},
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity:     ptr.To[int32](1),
+					MaxSize:             2,
+					MinSize:             0,
+					MaxInstanceLifetime: &metav1.Duration{Duration: 7 * 24 * time.Hour},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "max instance lifetime is unset and still set on the asg",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](1),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize: 2,
+							MinSize: 0,
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity:     ptr.To[int32](1),
+					MaxSize:             2,
+					MinSize:             0,
+					MaxInstanceLifetime: &metav1.Duration{Duration: 7 * 24 * time.Hour},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "max instance lifetime of 0 matches a disabled one",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](1),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize:             2,
+							MinSize:             0,
+							MaxInstanceLifetime: &metav1.Duration{},
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity: ptr.To[int32](1),
+					MaxSize:         2,
+					MinSize:         0,
+				},
+			},
+			want: false,
+		},
+		{
+			name: "autoscaling bounds match asg.minSize and asg.maxSize",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								clusterv1.ReplicasManagedByAnnotation: expinfrav1.AutoscalingReplicasManagedBy,
+							},
+						},
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](1),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MaxSize:     2,
+							MinSize:     1,
+							Autoscaling: &expinfrav1.MachinePoolAutoscaling{Enabled: true, MinSize: 0, MaxSize: 10},
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity: ptr.To[int32](4),
+					MaxSize:         10,
+					MinSize:         0,
+				},
+			},
+			want: false,
+		},
+		{
+			name: "externally managed annotation ignores difference between desiredCapacity and replicas",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								clusterv1.ReplicasManagedByAnnotation: "", // empty value counts as true (= externally managed)
+							},
+						},
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](0),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity: ptr.To[int32](1),
+				},
+			},
+			want: false,
+		},
+		{
+			name: "externally managed annotation ignores the asg scaled up by the autoscaler between reconciles",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								clusterv1.ReplicasManagedByAnnotation: "cluster-autoscaler",
+							},
+						},
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](3),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MinSize: 1,
+							MaxSize: 5,
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity: ptr.To[int32](8),
+					MinSize:         2,
+					MaxSize:         10,
+				},
+			},
+			want: false,
+		},
+		{
+			name: "externally managed annotation with size bounds synced detects asg.minSize and asg.maxSize changes",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{
+								clusterv1.ReplicasManagedByAnnotation: "cluster-autoscaler",
+							},
+						},
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](3),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{
+							MinSize:                              1,
+							MaxSize:                              5,
+							SyncSizeBoundsWithExternalAutoscaler: true,
+						},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity: ptr.To[int32](8),
+					MinSize:         2,
+					MaxSize:         10,
+				},
+			},
+			want: true,
+		},
+		{
+			name: "without externally managed annotation ignores difference between desiredCapacity and replicas",
+			args: args{
+				machinePoolScope: &scope.MachinePoolScope{
+					MachinePool: &expclusterv1.MachinePool{
+						Spec: expclusterv1.MachinePoolSpec{
+							Replicas: ptr.To[int32](0),
+						},
+					},
+					AWSMachinePool: &expinfrav1.AWSMachinePool{
+						Spec: expinfrav1.AWSMachinePoolSpec{},
+					},
+				},
+				existingASG: &expinfrav1.AutoScalingGroup{
+					DesiredCapacity: ptr.To[int32](1),
+				},
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(diffASG(tt.args.machinePoolScope, tt.args.existingASG) != "").To(Equal(tt.want))
+		})
+	}
+}
+
+func TestDominantScalingError(t *testing.T) {
+	now := time.Now()
+	capacityError := "We currently do not have sufficient m5.large capacity in the Availability Zone you requested."
+	tests := []struct {
+		name       string
+		activities []*expinfrav1.ScalingActivity
+		since      time.Time
+		want       string
+	}{
+		{
+			name: "no failed activities",
+			activities: []*expinfrav1.ScalingActivity{
+				{StatusCode: "Successful", StartTime: &metav1.Time{Time: now}},
+			},
+			since: now.Add(-time.Minute),
+			want:  "",
+		},
+		{
+			name: "most frequent error of the failed activities since the scale-up started",
+			activities: []*expinfrav1.ScalingActivity{
+				{StatusCode: expinfrav1.ScalingActivityStatusFailed, StatusMessage: capacityError, StartTime: &metav1.Time{Time: now}},
+				{StatusCode: expinfrav1.ScalingActivityStatusFailed, StatusMessage: "other error", StartTime: &metav1.Time{Time: now}},
+				{StatusCode: expinfrav1.ScalingActivityStatusFailed, StatusMessage: capacityError, StartTime: &metav1.Time{Time: now}},
+				{StatusCode: expinfrav1.ScalingActivityStatusFailed, StatusMessage: "other error", StartTime: &metav1.Time{Time: now.Add(-time.Hour)}},
+			},
+			since: now.Add(-time.Minute),
+			want:  capacityError + " (2 of 3 failed scaling activities)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(dominantScalingError(tt.activities, tt.since)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestScalingBlockers(t *testing.T) {
+	spotError := "There is no Spot capacity available that matches your request."
+	tests := []struct {
+		name           string
+		awsMachinePool *expinfrav1.AWSMachinePool
+		asg            *expinfrav1.AutoScalingGroup
+		activities     []*expinfrav1.ScalingActivity
+		quotaUsage     []infrav1.QuotaUsage
+		want           []expinfrav1.ScalingBlocker
+	}{
+		{
+			name:           "no blockers",
+			awsMachinePool: &expinfrav1.AWSMachinePool{},
+			asg:            &expinfrav1.AutoScalingGroup{CurrentlySuspendProcesses: []string{"AZRebalance"}},
+			activities: []*expinfrav1.ScalingActivity{
+				{StatusCode: expinfrav1.ScalingActivityStatusSuccessful},
+				{StatusCode: expinfrav1.ScalingActivityStatusFailed, StatusMessage: spotError},
+			},
+			quotaUsage: []infrav1.QuotaUsage{
+				{ServiceCode: "ec2", QuotaCode: "L-1216C47A", QuotaName: "Running On-Demand Standard instances", Limit: 32, Usage: 16},
+				{ServiceCode: "vpc", QuotaCode: "L-FE5A380F", QuotaName: "NAT gateways per Availability Zone", Limit: 5, Usage: 5},
+			},
+		},
+		{
+			name:           "most recent failure of each kind since the last successful scaling activity",
+			awsMachinePool: &expinfrav1.AWSMachinePool{},
+			activities: []*expinfrav1.ScalingActivity{
+				{StatusCode: expinfrav1.ScalingActivityStatusFailed, StatusMessage: spotError},
+				{StatusCode: "InProgress"},
+				{StatusCode: expinfrav1.ScalingActivityStatusFailed, StatusMessage: "There is no Spot capacity available in eu-west-1a."},
+				{StatusCode: expinfrav1.ScalingActivityStatusFailed, StatusMessage: "You have requested more vCPU capacity than your current vCPU limit of 32 allows."},
+				{StatusCode: expinfrav1.ScalingActivityStatusFailed, StatusMessage: "The specified launch template, with template ID lt-0123456789abcdef0, does not exist."},
+				{StatusCode: expinfrav1.ScalingActivityStatusFailed, Description: "Launching a new EC2 instance. Status Reason: Instance became unhealthy."},
+				{StatusCode: expinfrav1.ScalingActivityStatusSuccessful},
+				{StatusCode: expinfrav1.ScalingActivityStatusFailed, StatusMessage: "older error"},
+			},
+			want: []expinfrav1.ScalingBlocker{
+				{Code: expinfrav1.ScalingBlockerSpotCapacity, Message: spotError},
+				{Code: expinfrav1.ScalingBlockerQuotaExceeded, Message: "You have requested more vCPU capacity than your current vCPU limit of 32 allows."},
+				{Code: expinfrav1.ScalingBlockerLaunchTemplateInvalid, Message: "The specified launch template, with template ID lt-0123456789abcdef0, does not exist."},
+				{Code: expinfrav1.ScalingBlockerFailedScalingActivity, Message: "Launching a new EC2 instance. Status Reason: Instance became unhealthy."},
+			},
+		},
+		{
+			name: "launch template, instance refresh, suspended processes and quotas",
+			awsMachinePool: &expinfrav1.AWSMachinePool{
+				Status: expinfrav1.AWSMachinePoolStatus{
+					Conditions: clusterv1.Conditions{
+						*conditions.FalseCondition(expinfrav1.LaunchTemplateReadyCondition, expinfrav1.LaunchTemplateReconcileFailedReason, clusterv1.ConditionSeverityError, "version 3 of launch template lt-0123456789abcdef0 doesn't exist"),
+					},
+					InstanceRefresh: &expinfrav1.InstanceRefreshStatus{ID: "refresh-1", Status: "InProgress", PercentageComplete: 40},
+				},
+			},
+			asg: &expinfrav1.AutoScalingGroup{CurrentlySuspendProcesses: []string{"Terminate", "AZRebalance", "Launch"}},
+			quotaUsage: []infrav1.QuotaUsage{
+				{ServiceCode: "ec2", QuotaCode: "L-1216C47A", QuotaName: "Running On-Demand Standard instances", Limit: 32, Usage: 32},
+			},
+			want: []expinfrav1.ScalingBlocker{
+				{Code: expinfrav1.ScalingBlockerLaunchTemplateInvalid, Message: "version 3 of launch template lt-0123456789abcdef0 doesn't exist"},
+				{Code: expinfrav1.ScalingBlockerInstanceRefreshInProgress, Message: "Instance refresh refresh-1 is InProgress: 40% complete"},
+				{Code: expinfrav1.ScalingBlockerProcessesSuspended, Message: "Scaling processes are suspended: Launch, Terminate"},
+				{Code: expinfrav1.ScalingBlockerQuotaExceeded, Message: `Service quota "Running On-Demand Standard instances" (ec2/L-1216C47A) is exhausted with a usage of 32 for a limit of 32`},
+			},
+		},
+		{
+			name: "failed instance refresh",
+			awsMachinePool: &expinfrav1.AWSMachinePool{
+				Status: expinfrav1.AWSMachinePoolStatus{
+					InstanceRefresh: &expinfrav1.InstanceRefreshStatus{ID: "refresh-1", Status: "Failed", StatusReason: "Instances failed health checks."},
+				},
+			},
+			want: []expinfrav1.ScalingBlocker{
+				{Code: expinfrav1.ScalingBlockerInstanceRefreshFailed, Message: "Instance refresh refresh-1 is Failed: Instances failed health checks."},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(scalingBlockers(tt.awsMachinePool, tt.asg, tt.activities, tt.quotaUsage)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestReconcileScalingBlockers(t *testing.T) {
+	g := NewWithT(t)
+	awsMachinePool := &expinfrav1.AWSMachinePool{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	ms := &scope.MachinePoolScope{
+		Logger:         *logger.NewLogger(logr.Discard()),
+		AWSMachinePool: awsMachinePool,
+	}
+	cs := &scope.ClusterScope{AWSCluster: &infrav1.AWSCluster{}}
+	recorder := record.NewFakeRecorder(4)
+	reconciler := &AWSMachinePoolReconciler{Recorder: recorder}
+	asg := &expinfrav1.AutoScalingGroup{CurrentlySuspendProcesses: []string{"Launch"}}
+
+	reconciler.reconcileScalingBlockers(ms, cs, asg, nil)
+	g.Expect(awsMachinePool.Status.ScalingBlockers).To(Equal([]expinfrav1.ScalingBlocker{
+		{Code: expinfrav1.ScalingBlockerProcessesSuspended, Message: "Scaling processes are suspended: Launch"},
+	}))
+	g.Expect(recorder.Events).To(Receive(Equal("Warning ScalingBlocked ProcessesSuspended: Scaling processes are suspended: Launch")))
+
+	// The event is only emitted when the blockers change.
+	reconciler.reconcileScalingBlockers(ms, cs, asg, nil)
+	g.Expect(recorder.Events).To(BeEmpty())
+
+	cs.AWSCluster.Status.QuotaUsage = []infrav1.QuotaUsage{
+		{ServiceCode: "ec2", QuotaCode: "L-34B43A08", QuotaName: "All Standard Spot Instance Requests", Limit: 64, Usage: 64},
+	}
+	reconciler.reconcileScalingBlockers(ms, cs, asg, nil)
+	g.Expect(awsMachinePool.Status.ScalingBlockers).To(HaveLen(2))
+	g.Expect(recorder.Events).To(Receive(Equal("Warning ScalingBlocked ProcessesSuspended: Scaling processes are suspended: Launch; " +
+		`QuotaExceeded: Service quota "All Standard Spot Instance Requests" (ec2/L-34B43A08) is exhausted with a usage of 64 for a limit of 64`)))
+
+	cs.AWSCluster.Status.QuotaUsage = nil
+	reconciler.reconcileScalingBlockers(ms, cs, &expinfrav1.AutoScalingGroup{}, nil)
+	g.Expect(awsMachinePool.Status.ScalingBlockers).To(BeEmpty())
+	g.Expect(recorder.Events).To(Receive(Equal("Normal ScalingUnblocked No longer blocked from scaling")))
+}
+
+func TestReconcileScalingState(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+	awsMachinePool := &expinfrav1.AWSMachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec:       expinfrav1.AWSMachinePoolSpec{ScaleUpDelayThreshold: &metav1.Duration{Duration: time.Minute}},
+		Status: expinfrav1.AWSMachinePoolStatus{
+			ScalingState: &expinfrav1.ScalingState{ScaleUpPendingSince: ptr.To(metav1.NewTime(time.Now().Add(-10 * time.Minute)))},
+		},
+	}
+	ms := &scope.MachinePoolScope{
+		Logger:         *logger.NewLogger(logr.Discard()),
+		AWSMachinePool: awsMachinePool,
+	}
+	recorder := record.NewFakeRecorder(4)
+	reconciler := &AWSMachinePoolReconciler{Recorder: recorder}
+	asg := &expinfrav1.AutoScalingGroup{Name: "test", DesiredCapacity: ptr.To[int32](2)}
+	asgSvc.EXPECT().DescribeScalingActivities("test").Return(nil, nil).Times(3)
+
+	_, err := reconciler.reconcileScalingState(ms, asgSvc, asg)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(awsMachinePool.Status.ScalingState.ScaleUpDelayed).To(BeTrue())
+	g.Expect(recorder.Events).To(Receive(HavePrefix("Warning ScaleUpDelayed Scale-up has been pending for")))
+
+	// The event is only emitted when the scale-up becomes delayed.
+	_, err = reconciler.reconcileScalingState(ms, asgSvc, asg)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(awsMachinePool.Status.ScalingState.ScaleUpDelayed).To(BeTrue())
+	g.Expect(recorder.Events).To(BeEmpty())
+
+	asg.DesiredCapacity = ptr.To[int32](0)
+	_, err = reconciler.reconcileScalingState(ms, asgSvc, asg)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(awsMachinePool.Status.ScalingState.ScaleUpDelayed).To(BeFalse())
+	g.Expect(recorder.Events).To(BeEmpty())
+}
+
+func TestPendingLifecycleActions(t *testing.T) {
+	now := metav1.Now()
+	earlier := metav1.NewTime(now.Add(-10 * time.Minute))
+	hooks := []*infrav1.AWSLifecycleHook{
+		{Name: "launch-hook", LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch},
+		{Name: "drain-hook", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate},
+	}
+	tests := []struct {
+		name      string
+		instances []infrav1.Instance
+		previous  []expinfrav1.PendingLifecycleAction
+		want      []expinfrav1.PendingLifecycleAction
+	}{
+		{
+			name: "no instances waiting",
+			instances: []infrav1.Instance{
+				{ID: "i-1", State: expinfrav1.InstanceStateInService},
+			},
+			previous: []expinfrav1.PendingLifecycleAction{
+				{InstanceID: "i-1", HookName: "launch-hook", Transition: infrav1.LifecycleTransitionInstanceLaunch, StartTime: earlier},
+			},
+			want: nil,
+		},
+		{
+			name: "instances waiting on the hooks of their transition",
+			instances: []infrav1.Instance{
+				{ID: "i-1", State: expinfrav1.InstanceStatePendingWait},
+				{ID: "i-2", State: expinfrav1.InstanceStateInService},
+				{ID: "i-3", State: expinfrav1.InstanceStateTerminatingWait},
+			},
+			want: []expinfrav1.PendingLifecycleAction{
+				{InstanceID: "i-1", HookName: "launch-hook", Transition: infrav1.LifecycleTransitionInstanceLaunch, StartTime: now},
+				{InstanceID: "i-3", HookName: "drain-hook", Transition: infrav1.LifecycleTransitionInstanceTerminate, StartTime: now},
+			},
+		},
+		{
+			name: "start time of an action still pending is kept",
+			instances: []infrav1.Instance{
+				{ID: "i-3", State: expinfrav1.InstanceStateTerminatingWait},
+			},
+			previous: []expinfrav1.PendingLifecycleAction{
+				{InstanceID: "i-1", HookName: "launch-hook", Transition: infrav1.LifecycleTransitionInstanceLaunch, StartTime: earlier},
+				{InstanceID: "i-3", HookName: "drain-hook", Transition: infrav1.LifecycleTransitionInstanceTerminate, StartTime: earlier},
+			},
+			want: []expinfrav1.PendingLifecycleAction{
+				{InstanceID: "i-3", HookName: "drain-hook", Transition: infrav1.LifecycleTransitionInstanceTerminate, StartTime: earlier},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(pendingLifecycleActions(tt.instances, hooks, tt.previous, now)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestReconcileLoadBalancerAttachments(t *testing.T) {
+	nlb := &infrav1.AWSLoadBalancerSpec{
+		LoadBalancerType: infrav1.LoadBalancerTypeNLB,
+		AdditionalListeners: []infrav1.AdditionalListenerSpec{
+			{Port: 8132, Protocol: infrav1.ELBProtocolTCP},
+			{Port: 8133, Protocol: infrav1.ELBProtocolTCP},
+		},
+	}
+	targetGroups := map[int64]string{
+		6443: "tg-6443",
+		8132: "tg-8132",
+		8133: "tg-8133",
+	}
+	tests := []struct {
+		name              string
+		lbSpec            *infrav1.AWSLoadBalancerSpec
+		attachments       []expinfrav1.LoadBalancerAttachment
+		hasCondition      bool
+		expect            func(e *mock_services.MockELBInterfaceMockRecorder, a *mock_services.MockASGInterfaceMockRecorder)
+		wantErr           bool
+		wantCondition     bool
+		wantConditionTrue bool
+	}{
+		{
+			name:   "should do nothing without attachments",
+			lbSpec: nlb,
+			expect: func(e *mock_services.MockELBInterfaceMockRecorder, a *mock_services.MockASGInterfaceMockRecorder) {},
+		},
+		{
+			name:          "should fail if the control plane load balancer is not a network load balancer",
+			lbSpec:        &infrav1.AWSLoadBalancerSpec{LoadBalancerType: infrav1.LoadBalancerTypeClassic},
+			attachments:   []expinfrav1.LoadBalancerAttachment{{ListenerPort: 8132}},
+			expect:        func(e *mock_services.MockELBInterfaceMockRecorder, a *mock_services.MockASGInterfaceMockRecorder) {},
+			wantErr:       true,
+			wantCondition: true,
+		},
+		{
+			name:          "should fail if the listener is not an additional listener of the load balancer",
+			lbSpec:        nlb,
+			attachments:   []expinfrav1.LoadBalancerAttachment{{ListenerPort: 6443}},
+			expect:        func(e *mock_services.MockELBInterfaceMockRecorder, a *mock_services.MockASGInterfaceMockRecorder) {},
+			wantErr:       true,
+			wantCondition: true,
+		},
+		{
+			name:        "should attach the target groups that are not attached yet",
+			lbSpec:      nlb,
+			attachments: []expinfrav1.LoadBalancerAttachment{{ListenerPort: 8132}, {ListenerPort: 8133}},
+			expect: func(e *mock_services.MockELBInterfaceMockRecorder, a *mock_services.MockASGInterfaceMockRecorder) {
+				e.GetAPIServerLBListenerTargetGroups(nlb).Return(targetGroups, nil)
+				a.DescribeTargetGroupAttachments("test").Return([]string{"tg-8132"}, nil)
+				a.AttachTargetGroups("test", []string{"tg-8133"}).Return(nil)
+			},
+			wantCondition:     true,
+			wantConditionTrue: true,
+		},
+		{
+			name:         "should only detach the target groups of the control plane load balancer",
+			lbSpec:       nlb,
+			hasCondition: true,
+			expect: func(e *mock_services.MockELBInterfaceMockRecorder, a *mock_services.MockASGInterfaceMockRecorder) {
+				e.GetAPIServerLBListenerTargetGroups(nlb).Return(targetGroups, nil)
+				a.DescribeTargetGroupAttachments("test").Return([]string{"tg-8132", "tg-other"}, nil)
+				a.DetachTargetGroups("test", []string{"tg-8132"}).Return(nil)
+			},
+		},
+		{
+			name:        "should fail if the listener has not been created on the load balancer yet",
+			lbSpec:      nlb,
+			attachments: []expinfrav1.LoadBalancerAttachment{{ListenerPort: 8133}},
+			expect: func(e *mock_services.MockELBInterfaceMockRecorder, a *mock_services.MockASGInterfaceMockRecorder) {
+				e.GetAPIServerLBListenerTargetGroups(nlb).Return(map[int64]string{6443: "tg-6443"}, nil)
+			},
+			wantErr:       true,
+			wantCondition: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			elbSvc := mock_services.NewMockELBInterface(mockCtrl)
+			asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+			tt.expect(elbSvc.EXPECT(), asgSvc.EXPECT())
+
+			cs, err := setupCluster("test-cluster")
+			g.Expect(err).NotTo(HaveOccurred())
+			cs.AWSCluster.Spec.ControlPlaneLoadBalancer = tt.lbSpec
+
+			awsMachinePool := &expinfrav1.AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: expinfrav1.AWSMachinePoolSpec{
+					LoadBalancerAttachments: tt.attachments,
+				},
+			}
+			if tt.hasCondition {
+				conditions.MarkTrue(awsMachinePool, expinfrav1.LoadBalancerAttachmentsReadyCondition)
+			}
+			ms := &scope.MachinePoolScope{
+				Logger:         *logger.NewLogger(logr.Discard()),
+				InfraCluster:   cs,
+				AWSMachinePool: awsMachinePool,
+			}
+			reconciler := &AWSMachinePoolReconciler{
+				elbServiceFactory: func(scope.ELBScope) services.ELBInterface {
+					return elbSvc
+				},
+			}
+
+			err = reconciler.reconcileLoadBalancerAttachments(ms, cs, asgSvc)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(conditions.Has(awsMachinePool, expinfrav1.LoadBalancerAttachmentsReadyCondition)).To(Equal(tt.wantCondition))
+			if tt.wantCondition {
+				g.Expect(conditions.IsTrue(awsMachinePool, expinfrav1.LoadBalancerAttachmentsReadyCondition)).To(Equal(tt.wantConditionTrue))
+			}
+		})
+	}
+}
+
+func TestReconcileTargetGroups(t *testing.T) {
+	const (
+		workloads = "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/workloads/73e2d6bc24d8a067"
+		ingress   = "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/ingress/2453ed029918f21f"
+		other     = "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/other/50dc6c495c0c9188"
+	)
+
+	tests := []struct {
+		name         string
+		targetGroups []string
+		attached     []string
+		expect       func(a *mock_services.MockASGInterfaceMockRecorder)
+		wantErr      bool
+		wantAttached []string
+	}{
+		{
+			name:   "should do nothing without target groups",
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {},
+		},
+		{
+			name:         "should attach the target groups that are not attached yet",
+			targetGroups: []string{workloads, ingress},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeTargetGroupAttachments("test").Return([]string{workloads, other}, nil)
+				a.AttachTargetGroups("test", []string{ingress}).Return(nil)
+			},
+			wantAttached: []string{ingress, workloads},
+		},
+		{
+			name:         "should only detach the removed target groups CAPA attached",
+			targetGroups: []string{workloads},
+			attached:     []string{ingress, workloads},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeTargetGroupAttachments("test").Return([]string{ingress, other, workloads}, nil)
+				a.DetachTargetGroups("test", []string{ingress}).Return(nil)
+			},
+			wantAttached: []string{workloads},
+		},
+		{
+			name:     "should detach all the target groups CAPA attached when the spec is emptied",
+			attached: []string{workloads},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeTargetGroupAttachments("test").Return([]string{workloads}, nil)
+				a.DetachTargetGroups("test", []string{workloads}).Return(nil)
+			},
+		},
+		{
+			name:         "should fail if a target group is in another partition",
+			targetGroups: []string{"arn:aws-cn:elasticloadbalancing:cn-north-1:123456789012:targetgroup/workloads/73e2d6bc24d8a067"},
+			expect:       func(a *mock_services.MockASGInterfaceMockRecorder) {},
+			wantErr:      true,
+		},
+		{
+			name:         "should keep the attached target groups when attaching fails",
+			targetGroups: []string{workloads, ingress},
+			attached:     []string{workloads},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeTargetGroupAttachments("test").Return([]string{workloads}, nil)
+				a.AttachTargetGroups("test", []string{ingress}).Return(errors.New("an error"))
+			},
+			wantErr:      true,
+			wantAttached: []string{workloads},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+			tt.expect(asgSvc.EXPECT())
+
+			cs, err := setupCluster("test-cluster")
+			g.Expect(err).NotTo(HaveOccurred())
+			cs.AWSCluster.Spec.Region = "us-east-1"
+
+			awsMachinePool := &expinfrav1.AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec:       expinfrav1.AWSMachinePoolSpec{TargetGroupARNs: tt.targetGroups},
+				Status:     expinfrav1.AWSMachinePoolStatus{AttachedTargetGroupARNs: tt.attached},
+			}
+			ms := &scope.MachinePoolScope{
+				Logger:         *logger.NewLogger(logr.Discard()),
+				InfraCluster:   cs,
+				AWSMachinePool: awsMachinePool,
+			}
+			reconciler := &AWSMachinePoolReconciler{}
+
+			err = reconciler.reconcileTargetGroups(ms, cs, asgSvc)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(awsMachinePool.Status.AttachedTargetGroupARNs).To(Equal(tt.wantAttached))
+		})
+	}
+}
+
+func TestReconcileScheduledActions(t *testing.T) {
+	scaleUp := expinfrav1.ScheduledAction{Name: "scale-up", Recurrence: "0 8 * * 1-5", DesiredCapacity: ptr.To[int32](5)}
+	scaleDown := expinfrav1.ScheduledAction{Name: "scale-down", Recurrence: "0 20 * * 1-5", DesiredCapacity: ptr.To[int32](1)}
+	other := expinfrav1.ScheduledAction{Name: "other", Recurrence: "0 0 * * *", MinSize: ptr.To[int32](0)}
+
+	tests := []struct {
+		name          string
+		actions       []expinfrav1.ScheduledAction
+		created       []string
+		expect        func(a *mock_services.MockASGInterfaceMockRecorder)
+		wantErr       bool
+		wantScheduled []string
+	}{
+		{
+			name:   "should do nothing without scheduled actions",
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {},
+		},
+		{
+			name:    "should create the missing scheduled actions and update the drifted ones",
+			actions: []expinfrav1.ScheduledAction{scaleUp, scaleDown},
+			created: []string{"scale-up"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				drifted := scaleUp.DeepCopy()
+				drifted.DesiredCapacity = ptr.To[int32](3)
+				a.DescribeScheduledActions("test").Return([]*expinfrav1.ScheduledAction{drifted, &other}, nil)
+				a.ScheduledActionNeedsUpdate(drifted, &scaleUp).Return(true)
+				a.UpdateScheduledAction("test", drifted, &scaleUp).Return(nil)
+				a.CreateScheduledAction("test", &scaleDown).Return(nil)
+			},
+			wantScheduled: []string{"scale-down", "scale-up"},
+		},
+		{
+			name:    "should only delete the removed scheduled actions CAPA created",
+			actions: []expinfrav1.ScheduledAction{scaleUp},
+			created: []string{"scale-down", "scale-up"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeScheduledActions("test").Return([]*expinfrav1.ScheduledAction{&scaleUp, &scaleDown, &other}, nil)
+				a.ScheduledActionNeedsUpdate(&scaleUp, &scaleUp).Return(false)
+				a.DeleteScheduledAction("test", "scale-down").Return(nil)
+			},
+			wantScheduled: []string{"scale-up"},
+		},
+		{
+			name:    "should not delete the removed scheduled actions which are already gone",
+			created: []string{"scale-down"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeScheduledActions("test").Return([]*expinfrav1.ScheduledAction{&other}, nil)
+			},
+		},
+		{
+			name:    "should keep the created scheduled actions when creating fails",
+			actions: []expinfrav1.ScheduledAction{scaleUp, scaleDown},
+			created: []string{"scale-up"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeScheduledActions("test").Return([]*expinfrav1.ScheduledAction{&scaleUp}, nil)
+				a.ScheduledActionNeedsUpdate(&scaleUp, &scaleUp).Return(false)
+				a.CreateScheduledAction("test", &scaleDown).Return(errors.New("an error"))
+			},
+			wantErr:       true,
+			wantScheduled: []string{"scale-up"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+			tt.expect(asgSvc.EXPECT())
+
+			awsMachinePool := &expinfrav1.AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec:       expinfrav1.AWSMachinePoolSpec{ScheduledActions: tt.actions},
+				Status:     expinfrav1.AWSMachinePoolStatus{ScheduledActions: tt.created},
+			}
+			ms := &scope.MachinePoolScope{
+				Logger:         *logger.NewLogger(logr.Discard()),
+				AWSMachinePool: awsMachinePool,
+			}
+			reconciler := &AWSMachinePoolReconciler{}
+
+			err := reconciler.reconcileScheduledActions(ms, asgSvc)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(awsMachinePool.Status.ScheduledActions).To(Equal(tt.wantScheduled))
+		})
+	}
+}
+
+func TestReconcileClassicLoadBalancers(t *testing.T) {
+	tests := []struct {
+		name          string
+		loadBalancers []string
+		attached      []string
+		asgAttached   []string
+		expect        func(a *mock_services.MockASGInterfaceMockRecorder)
+		wantAttached  []string
+		wantRequeue   bool
+		wantCondition *corev1.ConditionStatus
+	}{
+		{
+			name:   "should do nothing without classic load balancers",
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {},
+		},
+		{
+			name:          "should attach the classic load balancers that are not attached yet",
+			loadBalancers: []string{"ingress", "legacy"},
+			asgAttached:   []string{"legacy", "other"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.AttachLoadBalancers("test", []string{"ingress"}).Return(nil)
+			},
+			wantAttached:  []string{"ingress", "legacy"},
+			wantCondition: ptr.To(corev1.ConditionTrue),
+		},
+		{
+			name:          "should reattach the classic load balancers detached outside of CAPA",
+			loadBalancers: []string{"ingress"},
+			attached:      []string{"ingress"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.AttachLoadBalancers("test", []string{"ingress"}).Return(nil)
+			},
+			wantAttached:  []string{"ingress"},
+			wantCondition: ptr.To(corev1.ConditionTrue),
+		},
+		{
+			name:          "should only detach the removed classic load balancers CAPA attached",
+			loadBalancers: []string{"ingress"},
+			attached:      []string{"ingress", "legacy"},
+			asgAttached:   []string{"ingress", "legacy", "other"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DetachLoadBalancers("test", []string{"legacy"}).Return(nil)
+			},
+			wantAttached:  []string{"ingress"},
+			wantCondition: ptr.To(corev1.ConditionTrue),
+		},
+		{
+			name:        "should detach all the classic load balancers CAPA attached when the spec is emptied",
+			attached:    []string{"ingress"},
+			asgAttached: []string{"ingress"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DetachLoadBalancers("test", []string{"ingress"}).Return(nil)
+			},
+		},
+		{
+			name:          "should report the failure to attach on the condition and retry",
+			loadBalancers: []string{"ingress", "legacy"},
+			attached:      []string{"legacy"},
+			asgAttached:   []string{"legacy"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.AttachLoadBalancers("test", []string{"ingress"}).Return(errors.New("an error"))
+			},
+			wantAttached:  []string{"legacy"},
+			wantRequeue:   true,
+			wantCondition: ptr.To(corev1.ConditionFalse),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+			tt.expect(asgSvc.EXPECT())
+
+			awsMachinePool := &expinfrav1.AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec:       expinfrav1.AWSMachinePoolSpec{ClassicLoadBalancers: tt.loadBalancers},
+				Status:     expinfrav1.AWSMachinePoolStatus{AttachedClassicLoadBalancers: tt.attached},
+			}
+			ms := &scope.MachinePoolScope{
+				Logger:         *logger.NewLogger(logr.Discard()),
+				AWSMachinePool: awsMachinePool,
+			}
+			reconciler := &AWSMachinePoolReconciler{Recorder: record.NewFakeRecorder(2)}
+
+			result := reconciler.reconcileClassicLoadBalancers(ms, asgSvc, &expinfrav1.AutoScalingGroup{Name: "test", LoadBalancerNames: tt.asgAttached})
+			g.Expect(result.RequeueAfter > 0).To(Equal(tt.wantRequeue))
+			g.Expect(awsMachinePool.Status.AttachedClassicLoadBalancers).To(Equal(tt.wantAttached))
+			condition := conditions.Get(awsMachinePool, expinfrav1.ClassicLoadBalancersReadyCondition)
+			if tt.wantCondition == nil {
+				g.Expect(condition).To(BeNil())
+				return
+			}
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(*tt.wantCondition))
+			if *tt.wantCondition == corev1.ConditionFalse {
+				g.Expect(condition.Reason).To(Equal(expinfrav1.ClassicLoadBalancerAttachmentFailedReason))
+			}
+		})
+	}
+}
+
+func TestReconcileLifecycleHooksRejected(t *testing.T) {
+	hook := infrav1.AWSLifecycleHook{
+		Name:                "hook",
+		LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+	}
+	staleHook := infrav1.AWSLifecycleHook{
+		Name:                "stale-hook",
+		LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+	}
+	limitExceeded := errors.Wrap(awserr.New(awserrors.LimitExceeded, "Lifecycle hook limit exceeded", nil), "failed to create lifecycle hook")
+	inUse := errors.Wrap(awserr.New(awserrors.ValidationError, "Lifecycle hook stale-hook is in use by a pending lifecycle action", nil), "failed to delete lifecycle hook")
+	tests := []struct {
+		name           string
+		hooks          []infrav1.AWSLifecycleHook
+		existingReason string
+		synced         bool
+		annotations    map[string]string
+		expect         func(a *mock_services.MockASGInterfaceMockRecorder)
+		wantErr        bool
+		wantCondition  bool
+		wantReason     string
+		wantEvents     int
+		wantRequeue    bool
+	}{
+		{
+			name:  "should mark the condition true once the hooks are reconciled",
+			hooks: []infrav1.AWSLifecycleHook{hook},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeLifecycleHooks("test").Return(nil, nil)
+				a.CreateLifecycleHook("test", &hook).Return(nil)
+			},
+			wantCondition: true,
+		},
+		{
+			name:  "should not fail on a rejected hook",
+			hooks: []infrav1.AWSLifecycleHook{hook},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeLifecycleHooks("test").Return(nil, nil)
+				a.CreateLifecycleHook("test", &hook).Return(limitExceeded)
+			},
+			wantCondition: true,
+			wantReason:    expinfrav1.LifecycleHookRejectedReason,
+			wantEvents:    1,
+		},
+		{
+			name:           "should only record an event when the hook is first rejected",
+			hooks:          []infrav1.AWSLifecycleHook{hook},
+			existingReason: expinfrav1.LifecycleHookRejectedReason,
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeLifecycleHooks("test").Return(nil, nil)
+				a.CreateLifecycleHook("test", &hook).Return(limitExceeded)
+			},
+			wantCondition: true,
+			wantReason:    expinfrav1.LifecycleHookRejectedReason,
+		},
+		{
+			name:  "should fail on other errors",
+			hooks: []infrav1.AWSLifecycleHook{hook},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeLifecycleHooks("test").Return(nil, nil)
+				a.CreateLifecycleHook("test", &hook).Return(awserr.New("Throttling", "Rate exceeded", nil))
+			},
+			wantErr: true,
+		},
+		{
+			name: "should requeue when a stale hook has pending lifecycle actions",
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeLifecycleHooks("test").Return([]*infrav1.AWSLifecycleHook{&staleHook}, nil)
+				a.DeleteLifecycleHook("test", &staleHook, false).Return(inUse)
+			},
+			wantCondition: true,
+			wantReason:    expinfrav1.LifecycleHookDeletionPendingReason,
+			wantRequeue:   true,
+		},
+		{
+			name:           "should remove the condition once the hooks pending deletion are deleted",
+			existingReason: expinfrav1.LifecycleHookDeletionPendingReason,
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeLifecycleHooks("test").Return([]*infrav1.AWSLifecycleHook{&staleHook}, nil)
+				a.DeleteLifecycleHook("test", &staleHook, false).Return(nil)
+			},
+		},
+		{
+			name: "should fail when deleting a stale hook fails for another reason",
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeLifecycleHooks("test").Return([]*infrav1.AWSLifecycleHook{&staleHook}, nil)
+				a.DeleteLifecycleHook("test", &staleHook, false).Return(awserr.New("Throttling", "Rate exceeded", nil))
+			},
+			wantErr: true,
+		},
+		{
+			name:  "should delete hooks missing from the spec",
+			hooks: []infrav1.AWSLifecycleHook{hook},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeLifecycleHooks("test").Return([]*infrav1.AWSLifecycleHook{&staleHook}, nil)
+				a.CreateLifecycleHook("test", &hook).Return(nil)
+				a.DeleteLifecycleHook("test", &staleHook, false).Return(nil)
+			},
+			wantCondition: true,
+		},
+		{
+			name:        "should not delete hooks missing from the spec when externally managed",
+			hooks:       []infrav1.AWSLifecycleHook{hook},
+			annotations: map[string]string{clusterv1.ManagedByAnnotation: ""},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeLifecycleHooks("test").Return([]*infrav1.AWSLifecycleHook{&staleHook}, nil)
+				a.CreateLifecycleHook("test", &hook).Return(nil)
+			},
+			wantCondition: true,
+		},
+		{
+			name:   "should skip the sync while the hooks are up to date",
+			hooks:  []infrav1.AWSLifecycleHook{hook},
+			synced: true,
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {},
+		},
+		{
+			name:           "should not retry a rejected hook until the resync interval",
+			hooks:          []infrav1.AWSLifecycleHook{hook},
+			existingReason: expinfrav1.LifecycleHookRejectedReason,
+			synced:         true,
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeLifecycleHooks("test").Return([]*infrav1.AWSLifecycleHook{&staleHook}, nil)
+				a.DeleteLifecycleHook("test", &staleHook, false).Return(inUse)
+			},
+			wantCondition: true,
+			wantReason:    expinfrav1.LifecycleHookRejectedReason,
+			wantRequeue:   true,
+		},
+		{
+			name:           "should only retry the deletion of stale hooks until the resync interval",
+			hooks:          []infrav1.AWSLifecycleHook{hook},
+			existingReason: expinfrav1.LifecycleHookDeletionPendingReason,
+			synced:         true,
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeLifecycleHooks("test").Return([]*infrav1.AWSLifecycleHook{&staleHook}, nil)
+				a.DeleteLifecycleHook("test", &staleHook, false).Return(nil)
+			},
+			wantCondition: true,
+		},
+		{
+			name:           "should remove the condition once the rejected hooks are removed from the spec",
+			existingReason: expinfrav1.LifecycleHookRejectedReason,
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DescribeLifecycleHooks("test").Return(nil, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+			tt.expect(asgSvc.EXPECT())
+
+			awsMachinePool := &expinfrav1.AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Annotations: tt.annotations},
+				Spec: expinfrav1.AWSMachinePoolSpec{
+					AWSLifecycleHooks: tt.hooks,
+				},
+			}
+			if tt.synced {
+				hash, err := lifecycleHooksHash(tt.hooks)
+				g.Expect(err).NotTo(HaveOccurred())
+				awsMachinePool.Status.LifecycleHooksObservedHash = hash
+				awsMachinePool.Status.LifecycleHooksLastSyncTime = ptr.To(metav1.Now())
+			}
+			if tt.existingReason != "" {
+				conditions.MarkFalse(awsMachinePool, expinfrav1.LifecycleHookExistsCondition, tt.existingReason, clusterv1.ConditionSeverityError, "")
+			}
+			cs, err := setupCluster("test-cluster")
+			g.Expect(err).NotTo(HaveOccurred())
+			ms := &scope.MachinePoolScope{
+				Logger:         *logger.NewLogger(logr.Discard()),
+				InfraCluster:   cs,
+				AWSMachinePool: awsMachinePool,
+			}
+			recorder := record.NewFakeRecorder(2)
+			reconciler := &AWSMachinePoolReconciler{Recorder: recorder}
+
+			result, err := reconciler.reconcileLifecycleHooks(ms, asgSvc)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if tt.wantRequeue {
+				g.Expect(result.RequeueAfter).To(Equal(lifecycleHookInUseRequeueAfter))
+			} else {
+				g.Expect(result.IsZero()).To(BeTrue())
+			}
+			g.Expect(conditions.Has(awsMachinePool, expinfrav1.LifecycleHookExistsCondition)).To(Equal(tt.wantCondition))
+			if tt.wantCondition {
+				g.Expect(conditions.IsTrue(awsMachinePool, expinfrav1.LifecycleHookExistsCondition)).To(Equal(tt.wantReason == ""))
+				g.Expect(conditions.GetReason(awsMachinePool, expinfrav1.LifecycleHookExistsCondition)).To(Equal(tt.wantReason))
+			}
+			// The sync is recorded even when hooks are rejected, so that they are only retried at the resync interval.
+			g.Expect(awsMachinePool.Status.LifecycleHooksLastSyncTime).NotTo(BeNil())
+			g.Expect(recorder.Events).To(HaveLen(tt.wantEvents))
+		})
+	}
+}
+
+func TestReconcileRefreshExclusions(t *testing.T) {
+	instances := []infrav1.Instance{{ID: "i-1"}, {ID: "i-2"}, {ID: "i-3"}}
+	tests := []struct {
+		name             string
+		annotated        []string
+		protected        []string
+		previous         []string
+		scaleInProtected []string
+		expect           func(a *mock_services.MockASGInterfaceMockRecorder)
+		wantErr          bool
+		wantExcluded     []string
+		wantEvents       int
+	}{
+		{
+			name: "should do nothing when no Node is annotated",
+		},
+		{
+			name:      "should protect the instances of annotated Nodes",
+			annotated: []string{"i-2", "i-1"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.SetInstanceProtection("test", []string{"i-1", "i-2"}, true).Return(nil)
+			},
+			wantExcluded: []string{"i-1", "i-2"},
+		},
+		{
+			name:         "should not protect the instances again",
+			annotated:    []string{"i-1"},
+			protected:    []string{"i-1"},
+			previous:     []string{"i-1"},
+			wantExcluded: []string{"i-1"},
+		},
+		{
+			name:      "should remove the protection once the annotation is removed",
+			protected: []string{"i-1"},
+			previous:  []string{"i-1"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.SetInstanceProtection("test", []string{"i-1"}, false).Return(nil)
+			},
+			wantEvents: 1,
+		},
+		{
+			name:      "should not remove the protection it didn't set",
+			annotated: []string{"i-2"},
+			protected: []string{"i-1", "i-2"},
+		},
+		{
+			name:             "should keep the protection of instances protected from scale in",
+			protected:        []string{"i-1"},
+			previous:         []string{"i-1"},
+			scaleInProtected: []string{"i-1"},
+		},
+		{
+			name:         "should forget the instances that are gone",
+			annotated:    []string{"i-1"},
+			protected:    []string{"i-1"},
+			previous:     []string{"i-1", "i-4"},
+			wantExcluded: []string{"i-1"},
+		},
+		{
+			name:      "should keep track of the protected instances when removing a protection fails",
+			annotated: []string{"i-1"},
+			protected: []string{"i-2"},
+			previous:  []string{"i-2"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.SetInstanceProtection("test", []string{"i-1"}, true).Return(nil)
+				a.SetInstanceProtection("test", []string{"i-2"}, false).Return(awserr.New("Throttling", "Rate exceeded", nil))
+			},
+			wantErr:      true,
+			wantExcluded: []string{"i-1", "i-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+			if tt.expect != nil {
+				tt.expect(asgSvc.EXPECT())
+			}
+
+			awsMachinePool := &expinfrav1.AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Status: expinfrav1.AWSMachinePoolStatus{
+					RefreshExcludedInstances:  tt.previous,
+					ScaleInProtectedInstances: tt.scaleInProtected,
+				},
+			}
+			ms := &scope.MachinePoolScope{
+				Logger:         *logger.NewLogger(logr.Discard()),
+				AWSMachinePool: awsMachinePool,
+			}
+			nodeStatusByProviderID := map[string]*scope.NodeStatus{}
+			for _, instance := range instances {
+				nodeStatusByProviderID["aws:////"+instance.ID] = &scope.NodeStatus{
+					ExcludeFromRefresh: sets.New(tt.annotated...).Has(instance.ID),
+				}
+			}
+			recorder := record.NewFakeRecorder(2)
+			reconciler := &AWSMachinePoolReconciler{Recorder: recorder}
+			asg := &expinfrav1.AutoScalingGroup{
+				Name:               "test",
+				Instances:          instances,
+				ProtectedInstances: tt.protected,
+			}
+
+			err := reconciler.reconcileRefreshExclusions(ms, asgSvc, asg, nodeStatusByProviderID)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(awsMachinePool.Status.RefreshExcludedInstances).To(Equal(tt.wantExcluded))
+			g.Expect(recorder.Events).To(HaveLen(tt.wantEvents))
+		})
+	}
+}
+
+func TestReconcileScaleInProtection(t *testing.T) {
+	instances := []infrav1.Instance{{ID: "i-1"}, {ID: "i-2"}, {ID: "i-3"}}
+	tests := []struct {
+		name            string
+		annotated       []string
+		excluded        []string
+		protected       []string
+		previous        []string
+		refreshExcluded []string
+		expect          func(a *mock_services.MockASGInterfaceMockRecorder)
+		wantErr         bool
+		wantProtected   []string
+	}{
+		{
+			name: "should do nothing when no instance is annotated",
+		},
+		{
+			name:      "should protect the annotated instances",
+			annotated: []string{"i-2", "i-1"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.SetInstanceProtection("test", []string{"i-1", "i-2"}, true).Return(nil)
+			},
+			wantProtected: []string{"i-1", "i-2"},
+		},
+		{
+			name:          "should not protect the instances again",
+			annotated:     []string{"i-1"},
+			protected:     []string{"i-1"},
+			previous:      []string{"i-1"},
+			wantProtected: []string{"i-1"},
+		},
+		{
+			name:      "should remove the protection once the annotation is removed",
+			protected: []string{"i-1"},
+			previous:  []string{"i-1"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.SetInstanceProtection("test", []string{"i-1"}, false).Return(nil)
+			},
+		},
+		{
+			name:      "should not remove the protection it didn't set",
+			annotated: []string{"i-2"},
+			protected: []string{"i-1", "i-2"},
+		},
+		{
+			name:            "should take over the protection of instances excluded from instance refresh",
+			annotated:       []string{"i-1"},
+			protected:       []string{"i-1"},
+			refreshExcluded: []string{"i-1"},
+			wantProtected:   []string{"i-1"},
+		},
+		{
+			name:          "should keep the protection of instances excluded from instance refresh",
+			excluded:      []string{"i-1"},
+			protected:     []string{"i-1"},
+			previous:      []string{"i-1"},
+			wantProtected: []string{"i-1"},
+		},
+		{
+			name:      "should keep track of the protected instances when removing a protection fails",
+			annotated: []string{"i-1"},
+			protected: []string{"i-2"},
+			previous:  []string{"i-2"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.SetInstanceProtection("test", []string{"i-1"}, true).Return(nil)
+				a.SetInstanceProtection("test", []string{"i-2"}, false).Return(awserr.New("Throttling", "Rate exceeded", nil))
+			},
+			wantErr:       true,
+			wantProtected: []string{"i-1", "i-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+			if tt.expect != nil {
+				tt.expect(asgSvc.EXPECT())
+			}
+
+			awsMachinePool := &expinfrav1.AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Status: expinfrav1.AWSMachinePoolStatus{
+					ScaleInProtectedInstances: tt.previous,
+					RefreshExcludedInstances:  tt.refreshExcluded,
+				},
+			}
+			ms := &scope.MachinePoolScope{
+				Logger:         *logger.NewLogger(logr.Discard()),
+				AWSMachinePool: awsMachinePool,
+			}
+			nodeStatusByProviderID := map[string]*scope.NodeStatus{}
+			for _, instance := range instances {
+				nodeStatusByProviderID["aws:////"+instance.ID] = &scope.NodeStatus{
+					ScaleInProtection:  sets.New(tt.annotated...).Has(instance.ID),
+					ExcludeFromRefresh: sets.New(tt.excluded...).Has(instance.ID),
+				}
+			}
+			reconciler := &AWSMachinePoolReconciler{Recorder: record.NewFakeRecorder(2)}
+			asg := &expinfrav1.AutoScalingGroup{
+				Name:               "test",
+				Instances:          instances,
+				ProtectedInstances: tt.protected,
+			}
+
+			err := reconciler.reconcileScaleInProtection(ms, asgSvc, asg, nodeStatusByProviderID)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(awsMachinePool.Status.ScaleInProtectedInstances).To(Equal(tt.wantProtected))
+		})
+	}
+}
+
+func TestReconcileDetachedInstances(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	machine := func(name, instanceID string, annotations map[string]string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				Labels:            map[string]string{clusterv1.ClusterNameLabel: "test"},
+				Annotations:       annotations,
+				DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				Finalizers:        []string{clusterv1.MachineFinalizer},
+			},
+			Spec: clusterv1.MachineSpec{ClusterName: "test", ProviderID: ptr.To("aws:///us-east-1a/" + instanceID)},
+		}
+	}
+	detach := map[string]string{expinfrav1.DetachInstanceAnnotation: "true"}
+
+	machines := []client.Object{
+		machine("detach-1", "i-1", detach),
+		machine("detach-2", "i-2", detach),
+		machine("terminate", "i-3", nil),
+	}
+
+	tests := []struct {
+		name          string
+		machines      []client.Object
+		annotations   map[string]string
+		expect        func(a *mock_services.MockASGInterfaceMockRecorder, e *mock_services.MockEC2InterfaceMockRecorder)
+		wantErr       string
+		wantInstances []string
+	}{
+		{
+			name:     "should tag and detach the instances of the annotated Machines",
+			machines: machines,
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder, e *mock_services.MockEC2InterfaceMockRecorder) {
+				e.UpdateResourceTags(ptr.To("i-1"), map[string]string{infrav1.DetachedByCAPATagKey: "test"}, nil).Return(nil)
+				e.UpdateResourceTags(ptr.To("i-2"), map[string]string{infrav1.DetachedByCAPATagKey: "test"}, nil).Return(nil)
+				a.DetachInstances("test", []string{"i-1", "i-2"}).Return(nil)
+			},
+			wantInstances: []string{"i-3"},
+		},
+		{
+			name:        "should tag and detach the instances listed on the AWSMachinePool when no Machine exists",
+			annotations: map[string]string{expinfrav1.DetachInstancesAnnotation: "i-2,i-4"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder, e *mock_services.MockEC2InterfaceMockRecorder) {
+				e.UpdateResourceTags(ptr.To("i-2"), map[string]string{infrav1.DetachedByCAPATagKey: "test"}, nil).Return(nil)
+				a.DetachInstances("test", []string{"i-2"}).Return(nil)
+			},
+			wantInstances: []string{"i-1", "i-3"},
+		},
+		{
+			name:     "should not detach the instances which couldn't be tagged",
+			machines: machines,
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder, e *mock_services.MockEC2InterfaceMockRecorder) {
+				e.UpdateResourceTags(ptr.To("i-1"), gomock.Any(), nil).Return(errors.New("an error"))
+			},
+			wantErr:       `failed to tag instance "i-1"`,
+			wantInstances: []string{"i-1", "i-2", "i-3"},
+		},
+		{
+			name:     "should keep the instances when they couldn't be detached",
+			machines: machines,
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder, e *mock_services.MockEC2InterfaceMockRecorder) {
+				e.UpdateResourceTags(gomock.Any(), gomock.Any(), nil).Return(nil).Times(2)
+				a.DetachInstances("test", []string{"i-1", "i-2"}).Return(errors.New("an error"))
+			},
+			wantErr:       "an error",
+			wantInstances: []string{"i-1", "i-2", "i-3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+			ec2Svc := mock_services.NewMockEC2Interface(mockCtrl)
+			tt.expect(asgSvc.EXPECT(), ec2Svc.EXPECT())
+
+			ms := &scope.MachinePoolScope{
+				Logger:         *logger.NewLogger(logr.Discard()),
+				Client:         fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.machines...).Build(),
+				Cluster:        &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}},
+				AWSMachinePool: &expinfrav1.AWSMachinePool{ObjectMeta: metav1.ObjectMeta{Name: "test", Annotations: tt.annotations}},
+			}
+			recorder := record.NewFakeRecorder(1)
+			reconciler := &AWSMachinePoolReconciler{Recorder: recorder}
+			asg := &expinfrav1.AutoScalingGroup{
+				Name: "test",
+				Instances: []infrav1.Instance{
+					{ID: "i-1", State: expinfrav1.InstanceStateInService},
+					{ID: "i-2", State: expinfrav1.InstanceStateInService},
+					{ID: "i-3", State: expinfrav1.InstanceStateInService},
+				},
+			}
+
+			err := reconciler.reconcileDetachedInstances(context.Background(), ms, asgSvc, ec2Svc, asg)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				g.Expect(recorder.Events).To(BeEmpty())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(recorder.Events).To(Receive(ContainSubstring("DetachedInstances")))
+			}
+			var instanceIDs []string
+			for _, instance := range asg.Instances {
+				instanceIDs = append(instanceIDs, instance.ID)
+			}
+			g.Expect(instanceIDs).To(Equal(tt.wantInstances))
+		})
+	}
+}
+
+func TestReconcileWorkloadClusterUnreachable(t *testing.T) {
+	tests := []struct {
+		name        string
+		managedHook bool
+		instances   []infrav1.Instance
+		wantRequeue bool
+	}{
+		{
+			name:      "should skip the nodes without requeueing",
+			instances: []infrav1.Instance{{ID: "i-1", State: expinfrav1.InstanceStateInService}},
+		},
+		{
+			name:        "should requeue when instances wait on the managed launch lifecycle hook",
+			managedHook: true,
+			instances:   []infrav1.Instance{{ID: "i-1", State: expinfrav1.InstanceStatePendingWait}},
+			wantRequeue: true,
+		},
+		{
+			name:        "should not requeue when no instance waits on the managed launch lifecycle hook",
+			managedHook: true,
+			instances:   []infrav1.Instance{{ID: "i-1", State: expinfrav1.InstanceStateInService}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			// No lifecycle action may be completed, nor any instance protected, without the Nodes.
+			asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+
+			awsMachinePool := &expinfrav1.AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Status: expinfrav1.AWSMachinePoolStatus{
+					Instances:                []expinfrav1.AWSMachinePoolInstanceStatus{{InstanceID: "i-1"}},
+					RefreshExcludedInstances: []string{"i-1"},
+				},
+			}
+			if tt.managedHook {
+				awsMachinePool.Spec.ManagedLaunchLifecycleHook = &expinfrav1.ManagedLaunchLifecycleHook{Enabled: true}
+			}
+			// The kubeconfig Secret of the cluster doesn't exist, so the workload cluster can't be reached.
+			ms := &scope.MachinePoolScope{
+				Logger:         *logger.NewLogger(logr.Discard()),
+				Client:         fake.NewClientBuilder().Build(),
+				Cluster:        &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}},
+				AWSMachinePool: awsMachinePool,
+			}
+			recorder := record.NewFakeRecorder(2)
+			reconciler := &AWSMachinePoolReconciler{Recorder: recorder}
+			asg := &expinfrav1.AutoScalingGroup{
+				Name:               "test",
+				Instances:          tt.instances,
+				ProtectedInstances: []string{"i-1"},
+			}
+
+			result, err := reconciler.reconcileWorkloadCluster(context.Background(), ms, asgSvc, asg, nil)
+			g.Expect(err).NotTo(HaveOccurred())
+			if tt.wantRequeue {
+				g.Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+			} else {
+				g.Expect(result.IsZero()).To(BeTrue())
+			}
+			g.Expect(conditions.IsFalse(awsMachinePool, expinfrav1.WorkloadClusterReachableCondition)).To(BeTrue())
+			g.Expect(conditions.GetReason(awsMachinePool, expinfrav1.WorkloadClusterReachableCondition)).To(Equal(expinfrav1.WorkloadClusterUnreachableReason))
+			g.Expect(conditions.GetSeverity(awsMachinePool, expinfrav1.WorkloadClusterReachableCondition)).To(HaveValue(Equal(clusterv1.ConditionSeverityWarning)))
+			// The status derived from the Nodes is kept as is until they can be listed again.
+			g.Expect(awsMachinePool.Status.Instances).To(HaveLen(1))
+			g.Expect(awsMachinePool.Status.RefreshExcludedInstances).To(Equal([]string{"i-1"}))
+			g.Expect(recorder.Events).To(BeEmpty())
+		})
+	}
+}
+
+func TestRecordInstanceProvisioning(t *testing.T) {
+	g := NewWithT(t)
+	launchedAt := metav1.NewTime(time.Now().Add(-5 * time.Minute).Truncate(time.Second))
+	registeredAt := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	previouslyRegisteredAt := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+
+	awsMachinePool := &expinfrav1.AWSMachinePool{
+		Status: expinfrav1.AWSMachinePoolStatus{
+			Instances: []expinfrav1.AWSMachinePoolInstanceStatus{
+				{InstanceID: "i-1"},
+				{InstanceID: "i-2"},
+				{InstanceID: "i-3"},
+				{InstanceID: "i-4", LaunchedAt: &launchedAt, NodeRegisteredAt: &previouslyRegisteredAt},
+			},
+		},
+	}
+	activities := []*expinfrav1.ScalingActivity{
+		{Description: "Launching a new EC2 instance: i-1", StartTime: &launchedAt},
+		{Description: "Launching a new EC2 instance: i-2", StartTime: &launchedAt},
+		{Description: "Terminating EC2 instance: i-3", StartTime: &launchedAt},
+		{Description: "Launching a new EC2 instance.  Status Reason: We currently do not have sufficient capacity.", StartTime: &launchedAt},
+	}
+	nodeStatusByProviderID := map[string]*scope.NodeStatus{
+		"aws:////i-1": {RegisteredAt: registeredAt},
+		"aws:////i-2": {},
+		"aws:////i-3": {RegisteredAt: registeredAt},
+		"aws:////i-4": {RegisteredAt: registeredAt},
+	}
+
+	recordInstanceProvisioning(awsMachinePool, activities, nodeStatusByProviderID)
+	instances := awsMachinePool.Status.Instances
+	// The Node registered after the instance was launched.
+	g.Expect(instances[0].LaunchedAt).To(Equal(&launchedAt))
+	g.Expect(instances[0].NodeRegisteredAt).To(Equal(&registeredAt))
+	// The Node didn't register yet.
+	g.Expect(instances[1].LaunchedAt).To(Equal(&launchedAt))
+	g.Expect(instances[1].NodeRegisteredAt).To(BeNil())
+	// The launch of the instance isn't known.
+	g.Expect(instances[2].LaunchedAt).To(BeNil())
+	g.Expect(instances[2].NodeRegisteredAt).To(BeNil())
+	// The times are recorded once.
+	g.Expect(instances[3].NodeRegisteredAt).To(Equal(&previouslyRegisteredAt))
+}
+
+func TestReconcileManagedLaunchLifecycleHook(t *testing.T) {
+	instances := []infrav1.Instance{
+		{ID: "i-1", State: expinfrav1.InstanceStatePendingWait},
+		{ID: "i-2", State: expinfrav1.InstanceStatePendingWait},
+		{ID: "i-3", State: expinfrav1.InstanceStateInService},
+	}
+	tests := []struct {
+		name        string
+		disabled    bool
+		ready       []string
+		expect      func(a *mock_services.MockASGInterfaceMockRecorder)
+		wantErr     bool
+		wantRequeue bool
+	}{
+		{
+			name:     "should do nothing when the hook is disabled",
+			disabled: true,
+			ready:    []string{"i-1", "i-2"},
+		},
+		{
+			name:  "should complete the lifecycle actions of the instances whose Node is ready",
+			ready: []string{"i-1", "i-2", "i-3"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.CompleteLifecycleAction("test", expinfrav1.ManagedLaunchLifecycleHookName, "i-1", "", infrav1.LifecycleHookDefaultResultContinue).Return(nil)
+				a.CompleteLifecycleAction("test", expinfrav1.ManagedLaunchLifecycleHookName, "i-2", "", infrav1.LifecycleHookDefaultResultContinue).Return(nil)
+			},
+		},
+		{
+			name:  "should requeue while a Node is not ready",
+			ready: []string{"i-2"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.CompleteLifecycleAction("test", expinfrav1.ManagedLaunchLifecycleHookName, "i-2", "", infrav1.LifecycleHookDefaultResultContinue).Return(nil)
+			},
+			wantRequeue: true,
+		},
+		{
+			name:  "should ignore the instances which don't wait on the hook",
+			ready: []string{"i-1", "i-2"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.CompleteLifecycleAction("test", expinfrav1.ManagedLaunchLifecycleHookName, "i-1", "", infrav1.LifecycleHookDefaultResultContinue).
+					Return(errors.Wrap(awserr.New(awserrors.ValidationError, "No active Lifecycle Action found with instance ID i-1", nil), "failed to complete lifecycle action"))
+				a.CompleteLifecycleAction("test", expinfrav1.ManagedLaunchLifecycleHookName, "i-2", "", infrav1.LifecycleHookDefaultResultContinue).Return(nil)
+			},
+		},
+		{
+			name:  "should fail when a lifecycle action can't be completed, after completing the others",
+			ready: []string{"i-1", "i-2"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.CompleteLifecycleAction("test", expinfrav1.ManagedLaunchLifecycleHookName, "i-1", "", infrav1.LifecycleHookDefaultResultContinue).Return(awserr.New("Throttling", "Rate exceeded", nil))
+				a.CompleteLifecycleAction("test", expinfrav1.ManagedLaunchLifecycleHookName, "i-2", "", infrav1.LifecycleHookDefaultResultContinue).Return(nil)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+			if tt.expect != nil {
+				tt.expect(asgSvc.EXPECT())
+			}
+
+			awsMachinePool := &expinfrav1.AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: expinfrav1.AWSMachinePoolSpec{
+					ManagedLaunchLifecycleHook: &expinfrav1.ManagedLaunchLifecycleHook{Enabled: !tt.disabled},
+				},
+			}
+			ms := &scope.MachinePoolScope{
+				Logger:         *logger.NewLogger(logr.Discard()),
+				AWSMachinePool: awsMachinePool,
+			}
+			nodeStatusByProviderID := map[string]*scope.NodeStatus{}
+			for _, instance := range instances {
+				nodeStatusByProviderID["aws:////"+instance.ID] = &scope.NodeStatus{
+					Ready: sets.New(tt.ready...).Has(instance.ID),
+				}
+			}
+			reconciler := &AWSMachinePoolReconciler{Recorder: record.NewFakeRecorder(2)}
+			asg := &expinfrav1.AutoScalingGroup{Name: "test", Instances: instances}
+
+			result, err := reconciler.reconcileManagedLaunchLifecycleHook(ms, asgSvc, asg, nodeStatusByProviderID)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.RequeueAfter != 0).To(Equal(tt.wantRequeue))
+		})
+	}
+}
+
+func TestReconcileMetricsCollection(t *testing.T) {
+	tests := []struct {
+		name              string
+		metricsCollection *expinfrav1.MetricsCollection
+		current           []string
+		previous          []string
+		expect            func(a *mock_services.MockASGInterfaceMockRecorder)
+		wantErr           bool
+		wantEnabled       []string
+	}{
+		{
+			name: "should do nothing without metrics collection",
+		},
+		{
+			name:              "should enable all the metrics when none are listed",
+			metricsCollection: &expinfrav1.MetricsCollection{},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.EnableMetricsCollection("test", expinfrav1.MetricsCollectionGranularity1Minute, sets.List(sets.New(expinfrav1.ASGMetrics...))).Return(nil)
+			},
+			wantEnabled: sets.List(sets.New(expinfrav1.ASGMetrics...)),
+		},
+		{
+			name:              "should only enable the metrics added to the spec",
+			metricsCollection: &expinfrav1.MetricsCollection{Metrics: []string{"GroupDesiredCapacity", "GroupInServiceInstances", "GroupMaxSize"}},
+			current:           []string{"GroupDesiredCapacity"},
+			previous:          []string{"GroupDesiredCapacity"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.EnableMetricsCollection("test", expinfrav1.MetricsCollectionGranularity1Minute, []string{"GroupInServiceInstances", "GroupMaxSize"}).Return(nil)
+			},
+			wantEnabled: []string{"GroupDesiredCapacity", "GroupInServiceInstances", "GroupMaxSize"},
+		},
+		{
+			name:              "should only disable the metrics removed from the spec",
+			metricsCollection: &expinfrav1.MetricsCollection{Metrics: []string{"GroupDesiredCapacity"}},
+			current:           []string{"GroupDesiredCapacity", "GroupInServiceInstances", "GroupMaxSize"},
+			previous:          []string{"GroupDesiredCapacity", "GroupInServiceInstances", "GroupMaxSize"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DisableMetricsCollection("test", []string{"GroupInServiceInstances", "GroupMaxSize"}).Return(nil)
+			},
+			wantEnabled: []string{"GroupDesiredCapacity"},
+		},
+		{
+			name:              "should add and remove metrics at once",
+			metricsCollection: &expinfrav1.MetricsCollection{Metrics: []string{"GroupDesiredCapacity", "GroupTotalInstances"}},
+			current:           []string{"GroupDesiredCapacity", "GroupInServiceInstances"},
+			previous:          []string{"GroupDesiredCapacity", "GroupInServiceInstances"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.EnableMetricsCollection("test", expinfrav1.MetricsCollectionGranularity1Minute, []string{"GroupTotalInstances"}).Return(nil)
+				a.DisableMetricsCollection("test", []string{"GroupInServiceInstances"}).Return(nil)
+			},
+			wantEnabled: []string{"GroupDesiredCapacity", "GroupTotalInstances"},
+		},
+		{
+			name:     "should disable the metrics it enabled once metrics collection is removed",
+			current:  []string{"GroupDesiredCapacity", "GroupInServiceInstances", "GroupMaxSize"},
+			previous: []string{"GroupDesiredCapacity", "GroupInServiceInstances"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.DisableMetricsCollection("test", []string{"GroupDesiredCapacity", "GroupInServiceInstances"}).Return(nil)
+			},
+		},
+		{
+			name:              "should do nothing when the metrics are enabled",
+			metricsCollection: &expinfrav1.MetricsCollection{Metrics: []string{"GroupDesiredCapacity"}},
+			current:           []string{"GroupDesiredCapacity", "GroupMaxSize"},
+			previous:          []string{"GroupDesiredCapacity"},
+			wantEnabled:       []string{"GroupDesiredCapacity"},
+		},
+		{
+			name:              "should keep track of the enabled metrics when disabling metrics fails",
+			metricsCollection: &expinfrav1.MetricsCollection{Metrics: []string{"GroupTotalInstances"}},
+			current:           []string{"GroupDesiredCapacity"},
+			previous:          []string{"GroupDesiredCapacity"},
+			expect: func(a *mock_services.MockASGInterfaceMockRecorder) {
+				a.EnableMetricsCollection("test", expinfrav1.MetricsCollectionGranularity1Minute, []string{"GroupTotalInstances"}).Return(nil)
+				a.DisableMetricsCollection("test", []string{"GroupDesiredCapacity"}).Return(awserr.New("Throttling", "Rate exceeded", nil))
+			},
+			wantErr:     true,
+			wantEnabled: []string{"GroupDesiredCapacity", "GroupTotalInstances"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+			if tt.expect != nil {
+				tt.expect(asgSvc.EXPECT())
+			}
+
+			awsMachinePool := &expinfrav1.AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec:       expinfrav1.AWSMachinePoolSpec{MetricsCollection: tt.metricsCollection},
+				Status:     expinfrav1.AWSMachinePoolStatus{EnabledMetrics: tt.previous},
+			}
+			ms := &scope.MachinePoolScope{
+				Logger:         *logger.NewLogger(logr.Discard()),
+				AWSMachinePool: awsMachinePool,
+			}
+			reconciler := &AWSMachinePoolReconciler{}
+			asg := &expinfrav1.AutoScalingGroup{Name: "test", EnabledMetrics: tt.current}
+
+			err := reconciler.reconcileMetricsCollection(ms, asgSvc, asg)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tt.wantEnabled == nil {
+				g.Expect(awsMachinePool.Status.EnabledMetrics).To(BeEmpty())
+			} else {
+				g.Expect(awsMachinePool.Status.EnabledMetrics).To(Equal(tt.wantEnabled))
+			}
+		})
+	}
+}
+
+func TestReconcileInstanceRefreshStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		previous       *expinfrav1.InstanceRefreshStatus
+		refresh        *expinfrav1.InstanceRefreshStatus
+		wantResult     ctrl.Result
+		wantCondition  *clusterv1.Condition
+		wantEvents     int
+		wantDescribed  bool
+		wantRefreshNil bool
+	}{
+		{
+			name:           "should do nothing without instance refresh",
+			wantRefreshNil: true,
+		},
+		{
+			name:     "should not describe a completed instance refresh again",
+			previous: &expinfrav1.InstanceRefreshStatus{ID: "refresh-1", Status: "Successful"},
+		},
+		{
+			name:          "should report the progress of a running instance refresh",
+			previous:      &expinfrav1.InstanceRefreshStatus{ID: "refresh-1", Status: "Pending"},
+			refresh:       &expinfrav1.InstanceRefreshStatus{ID: "refresh-1", Status: "InProgress", PercentageComplete: 50, InstancesToUpdate: 2},
+			wantDescribed: true,
+			wantResult:    ctrl.Result{RequeueAfter: instanceRefreshPollInterval},
+			wantCondition: &clusterv1.Condition{
+				Type:     expinfrav1.InstanceRefreshReadyCondition,
+				Status:   corev1.ConditionFalse,
+				Severity: clusterv1.ConditionSeverityInfo,
+				Reason:   expinfrav1.InstanceRefreshInProgressReason,
+				Message:  "Instance refresh refresh-1 is InProgress: 50% complete, 2 instances to update",
+			},
+		},
+		{
+			name:          "should mark a successful instance refresh as ready",
+			previous:      &expinfrav1.InstanceRefreshStatus{ID: "refresh-1", Status: "InProgress"},
+			refresh:       &expinfrav1.InstanceRefreshStatus{ID: "refresh-1", Status: "Successful", PercentageComplete: 100},
+			wantDescribed: true,
+			wantCondition: &clusterv1.Condition{
+				Type:   expinfrav1.InstanceRefreshReadyCondition,
+				Status: corev1.ConditionTrue,
+			},
+			wantEvents: 1,
+		},
+		{
+			name:          "should report the reason of a failed instance refresh",
+			previous:      &expinfrav1.InstanceRefreshStatus{ID: "refresh-1", Status: "InProgress"},
+			refresh:       &expinfrav1.InstanceRefreshStatus{ID: "refresh-1", Status: "Failed", StatusReason: "Instances failed health checks."},
+			wantDescribed: true,
+			wantCondition: &clusterv1.Condition{
+				Type:     expinfrav1.InstanceRefreshReadyCondition,
+				Status:   corev1.ConditionFalse,
+				Severity: clusterv1.ConditionSeverityWarning,
+				Reason:   expinfrav1.InstanceRefreshUnsuccessfulReason,
+				Message:  "Instance refresh refresh-1 is Failed: Instances failed health checks.",
+			},
+			wantEvents: 1,
+		},
+	}
+	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
-			g.Expect(diffASG(tt.args.machinePoolScope, tt.args.existingASG) != "").To(Equal(tt.want))
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+			if tt.wantDescribed {
+				asgSvc.EXPECT().DescribeInstanceRefresh("test", "refresh-1").Return(tt.refresh, nil)
+			}
+
+			awsMachinePool := &expinfrav1.AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Status: expinfrav1.AWSMachinePoolStatus{
+					InstanceRefresh: tt.previous,
+				},
+			}
+			ms := &scope.MachinePoolScope{
+				Logger:         *logger.NewLogger(logr.Discard()),
+				AWSMachinePool: awsMachinePool,
+			}
+			recorder := record.NewFakeRecorder(2)
+			reconciler := &AWSMachinePoolReconciler{Recorder: recorder}
+
+			result, err := reconciler.reconcileInstanceRefreshStatus(ms, asgSvc)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result).To(Equal(tt.wantResult))
+			if tt.wantRefreshNil {
+				g.Expect(awsMachinePool.Status.InstanceRefresh).To(BeNil())
+			} else if tt.refresh != nil {
+				g.Expect(awsMachinePool.Status.InstanceRefresh).To(Equal(tt.refresh))
+			}
+			condition := conditions.Get(awsMachinePool, expinfrav1.InstanceRefreshReadyCondition)
+			if tt.wantCondition == nil {
+				g.Expect(condition).To(BeNil())
+			} else {
+				g.Expect(condition).NotTo(BeNil())
+				condition.LastTransitionTime = metav1.Time{}
+				g.Expect(*condition).To(Equal(*tt.wantCondition))
+			}
+			g.Expect(recorder.Events).To(HaveLen(tt.wantEvents))
+		})
+	}
+}
+
+func TestCancelOutdatedInstanceRefresh(t *testing.T) {
+	tests := []struct {
+		name                string
+		refresh             *expinfrav1.InstanceRefreshStatus
+		disableCancellation bool
+		wantCancelled       bool
+	}{
+		{
+			name: "should not cancel an instance refresh which wasn't started by CAPA",
+		},
+		{
+			name:    "should not cancel a completed instance refresh",
+			refresh: &expinfrav1.InstanceRefreshStatus{ID: "refresh-1", Status: "Successful"},
+		},
+		{
+			name:          "should cancel a running instance refresh",
+			refresh:       &expinfrav1.InstanceRefreshStatus{ID: "refresh-1", Status: "InProgress"},
+			wantCancelled: true,
+		},
+		{
+			name:    "should wait for the cancellation of an instance refresh to complete",
+			refresh: &expinfrav1.InstanceRefreshStatus{ID: "refresh-1", Status: "Cancelling"},
+		},
+		{
+			name:                "should not cancel a running instance refresh when the cancellation is disabled",
+			refresh:             &expinfrav1.InstanceRefreshStatus{ID: "refresh-1", Status: "InProgress"},
+			disableCancellation: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+			if tt.wantCancelled {
+				asgSvc.EXPECT().CancelASGInstanceRefresh(gomock.Any()).Return(nil)
+			}
+
+			awsMachinePool := &expinfrav1.AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: expinfrav1.AWSMachinePoolSpec{
+					RefreshPreferences: &expinfrav1.RefreshPreferences{
+						DisableInstanceRefreshCancellation: tt.disableCancellation,
+					},
+				},
+				Status: expinfrav1.AWSMachinePoolStatus{
+					InstanceRefresh: tt.refresh,
+				},
+			}
+			ms := &scope.MachinePoolScope{
+				Logger:         *logger.NewLogger(logr.Discard()),
+				AWSMachinePool: awsMachinePool,
+			}
+			recorder := record.NewFakeRecorder(2)
+			reconciler := &AWSMachinePoolReconciler{Recorder: recorder}
+
+			g.Expect(reconciler.cancelOutdatedInstanceRefresh(ms, asgSvc)).To(Succeed())
+			condition := conditions.Get(awsMachinePool, expinfrav1.InstanceRefreshReadyCondition)
+			if tt.wantCancelled {
+				g.Expect(condition).NotTo(BeNil())
+				g.Expect(condition.Reason).To(Equal(expinfrav1.InstanceRefreshCancellingReason))
+				g.Expect(recorder.Events).To(HaveLen(1))
+			} else {
+				g.Expect(condition).To(BeNil())
+				g.Expect(recorder.Events).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestRefreshExclusionWarning(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(refreshExclusionWarning([]string{"i-1", "i-2"}, 3)).To(HavePrefix("Instances i-1, i-2 are excluded from the instance refresh"))
+	g.Expect(refreshExclusionWarning([]string{"i-1", "i-2"}, 2)).To(HavePrefix("All instances are excluded from the instance refresh"))
+}
+
+func TestSetVersionStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	ms := &scope.MachinePoolScope{
+		MachinePool: &expclusterv1.MachinePool{
+			Spec: expclusterv1.MachinePoolSpec{
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{Version: ptr.To("v1.29.1")},
+				},
+			},
+		},
+		AWSMachinePool: &expinfrav1.AWSMachinePool{},
+	}
+
+	setVersionStatus(ms)
+	g.Expect(ms.AWSMachinePool.Status.Version).To(HaveValue(Equal("v1.29.1")))
+	g.Expect(ms.AWSMachinePool.Status.AMIKubernetesVersion).To(HaveValue(Equal("v1.29.1")))
+
+	ms.AWSMachinePool.Spec.AMIVersionOverride = ptr.To("v1.28.5")
+	setVersionStatus(ms)
+	g.Expect(ms.AWSMachinePool.Status.Version).To(HaveValue(Equal("v1.29.1")))
+	g.Expect(ms.AWSMachinePool.Status.AMIKubernetesVersion).To(HaveValue(Equal("v1.28.5")))
+
+	// The AMI isn't looked up when its ID is set.
+	ms.AWSMachinePool.Spec.AMIVersionOverride = nil
+	ms.AWSMachinePool.Spec.AWSLaunchTemplate.AMI.ID = ptr.To("ami-0123456789abcdef0")
+	setVersionStatus(ms)
+	g.Expect(ms.AWSMachinePool.Status.AMIKubernetesVersion).To(BeNil())
+}
+
+func TestUsesSpotInstances(t *testing.T) {
+	tests := []struct {
+		name string
+		spec expinfrav1.AWSMachinePoolSpec
+		want bool
+	}{
+		{
+			name: "on-demand only",
+			spec: expinfrav1.AWSMachinePoolSpec{},
+			want: false,
+		},
+		{
+			name: "spot market options",
+			spec: expinfrav1.AWSMachinePoolSpec{
+				AWSLaunchTemplate: expinfrav1.AWSLaunchTemplate{
+					SpotMarketOptions: &infrav1.SpotMarketOptions{},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "mixed instances policy with spot capacity",
+			spec: expinfrav1.AWSMachinePoolSpec{
+				MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+					InstancesDistribution: &expinfrav1.InstancesDistribution{
+						OnDemandPercentageAboveBaseCapacity: aws.Int64(50),
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "mixed instances policy with on-demand capacity only",
+			spec: expinfrav1.AWSMachinePoolSpec{
+				MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+					InstancesDistribution: &expinfrav1.InstancesDistribution{
+						OnDemandPercentageAboveBaseCapacity: aws.Int64(100),
+					},
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(usesSpotInstances(&expinfrav1.AWSMachinePool{Spec: tt.spec})).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestInstanceCapacity(t *testing.T) {
+	g := NewWithT(t)
+
+	policy := &expinfrav1.MixedInstancesPolicy{
+		Overrides: []expinfrav1.Overrides{
+			{InstanceType: "m5.4xlarge", WeightedCapacity: "2"},
+			{InstanceType: "m5.2xlarge", WeightedCapacity: "1"},
+		},
+	}
+	g.Expect(instanceCapacity(policy, "m5.4xlarge")).To(Equal(int32(2)))
+	g.Expect(instanceCapacity(policy, "m5.2xlarge")).To(Equal(int32(1)))
+	// An instance of a type removed from the overrides counts for a single unit.
+	g.Expect(instanceCapacity(policy, "m5.xlarge")).To(Equal(int32(1)))
+	g.Expect(instanceCapacity(nil, "m5.4xlarge")).To(Equal(int32(1)))
+	g.Expect(instanceCapacity(&expinfrav1.MixedInstancesPolicy{Overrides: []expinfrav1.Overrides{{InstanceType: "m5.4xlarge"}}}, "m5.4xlarge")).To(Equal(int32(1)))
+}
+
+func TestOutdatedReplicas(t *testing.T) {
+	g := NewWithT(t)
+
+	asg := &expinfrav1.AutoScalingGroup{
+		Instances: []infrav1.Instance{{ID: "i-current"}, {ID: "i-old-version"}, {ID: "i-replaced-template"}, {ID: "i-launch-configuration"}},
+		InstanceLaunchTemplates: map[string]expinfrav1.InstanceLaunchTemplate{
+			"i-current":           {ID: "lt-1", Version: "2"},
+			"i-old-version":       {ID: "lt-1", Version: "1"},
+			"i-replaced-template": {ID: "lt-0", Version: "2"},
+		},
+	}
+	g.Expect(outdatedReplicas(asg, "lt-1", "2")).To(Equal(int32(2)))
+	// Nothing is outdated until the launch template is known.
+	g.Expect(outdatedReplicas(asg, "", "")).To(Equal(int32(0)))
+}
+
+func TestReconcileInstancesInSubnets(t *testing.T) {
+	g := NewWithT(t)
+
+	cs, err := setupCluster("test-cluster")
+	g.Expect(err).NotTo(HaveOccurred())
+	cs.AWSCluster.Spec.NetworkSpec.Subnets = infrav1.Subnets{
+		{ID: "subnet-a", AvailabilityZone: "us-east-1a"},
+		{ID: "subnet-b", AvailabilityZone: "us-east-1b"},
+	}
+	awsMachinePool := &expinfrav1.AWSMachinePool{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	ms := &scope.MachinePoolScope{
+		Logger:         *logger.NewLogger(logr.Discard()),
+		InfraCluster:   cs,
+		AWSMachinePool: awsMachinePool,
+	}
+	recorder := record.NewFakeRecorder(2)
+	reconciler := &AWSMachinePoolReconciler{Recorder: recorder}
+	asg := &expinfrav1.AutoScalingGroup{
+		Instances: []infrav1.Instance{{ID: "i-1", AvailabilityZone: "us-east-1a"}, {ID: "i-2", AvailabilityZone: "us-east-1a"}},
+	}
+
+	reconciler.reconcileInstancesInSubnets(ms, []string{"subnet-a"}, asg)
+	g.Expect(conditions.IsTrue(awsMachinePool, expinfrav1.InstancesInSubnetsCondition)).To(BeTrue())
+
+	// The pool moved to another availability zone, which its instances aren't replaced in.
+	reconciler.reconcileInstancesInSubnets(ms, []string{"subnet-b"}, asg)
+	g.Expect(conditions.IsFalse(awsMachinePool, expinfrav1.InstancesInSubnetsCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(awsMachinePool, expinfrav1.InstancesInSubnetsCondition)).To(Equal(expinfrav1.InstancesOutsideSubnetsReason))
+	g.Expect(conditions.GetMessage(awsMachinePool, expinfrav1.InstancesInSubnetsCondition)).To(HavePrefix("2 instances"))
+	g.Expect(recorder.Events).To(Receive(ContainSubstring(expinfrav1.InstancesOutsideSubnetsReason)))
+
+	// The event is only recorded once.
+	reconciler.reconcileInstancesInSubnets(ms, []string{"subnet-b"}, asg)
+	g.Expect(recorder.Events).NotTo(Receive())
+
+	// The availability zone of a subnet outside of the network of the cluster isn't known.
+	reconciler.reconcileInstancesInSubnets(ms, []string{"subnet-b", "subnet-external"}, asg)
+	g.Expect(conditions.Has(awsMachinePool, expinfrav1.InstancesInSubnetsCondition)).To(BeFalse())
+}
+
+type fakeSpotInterruptionService struct {
+	queueARN string
+	err      error
+}
+
+func (s *fakeSpotInterruptionService) ReconcileSpotInterruptionEvents() (string, error) {
+	return s.queueARN, s.err
+}
+
+func (s *fakeSpotInterruptionService) DeleteSpotInterruptionEvents() error {
+	return s.err
+}
+
+func TestReconcileSpotInterruptionHandling(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.SpotInterruptionHandling, true)()
+	g := NewWithT(t)
+
+	cs, err := setupCluster("test-cluster")
+	g.Expect(err).NotTo(HaveOccurred())
+	awsMachinePool := &expinfrav1.AWSMachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: expinfrav1.AWSMachinePoolSpec{
+			SpotInterruptionHandling: &expinfrav1.SpotInterruptionHandling{Enabled: true},
+		},
+	}
+	ms := &scope.MachinePoolScope{
+		Logger:         *logger.NewLogger(logr.Discard()),
+		InfraCluster:   cs,
+		AWSMachinePool: awsMachinePool,
+	}
+	svc := &fakeSpotInterruptionService{queueARN: "arn:aws:sqs:us-east-1:123456789012:test-spot-queue"}
+	reconciler := &AWSMachinePoolReconciler{
+		spotInterruptionFactory: func(scope.EC2Scope) services.SpotInterruptionInterface { return svc },
+	}
+
+	g.Expect(reconciler.reconcileSpotInterruptionHandling(ms, cs)).To(Succeed())
+	g.Expect(awsMachinePool.Status.SpotInterruptionQueueARN).To(Equal(svc.queueARN))
+	g.Expect(conditions.IsTrue(awsMachinePool, expinfrav1.SpotInterruptionHandlingReadyCondition)).To(BeTrue())
+
+	// The queue can't be created.
+	svc.err = errors.New("access denied")
+	g.Expect(reconciler.reconcileSpotInterruptionHandling(ms, cs)).NotTo(Succeed())
+	g.Expect(conditions.IsFalse(awsMachinePool, expinfrav1.SpotInterruptionHandlingReadyCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(awsMachinePool, expinfrav1.SpotInterruptionHandlingReadyCondition)).To(Equal(expinfrav1.SpotInterruptionHandlingFailedReason))
+
+	// The pool disables the handling, which leaves the queue to the other pools of the cluster.
+	awsMachinePool.Spec.SpotInterruptionHandling.Enabled = false
+	g.Expect(reconciler.reconcileSpotInterruptionHandling(ms, cs)).To(Succeed())
+	g.Expect(awsMachinePool.Status.SpotInterruptionQueueARN).To(BeEmpty())
+	g.Expect(conditions.Has(awsMachinePool, expinfrav1.SpotInterruptionHandlingReadyCondition)).To(BeFalse())
+}
+
+func TestReconcileEvacuation(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+
+	cs, err := setupCluster("test-cluster")
+	g.Expect(err).NotTo(HaveOccurred())
+	cs.AWSCluster.Spec.NetworkSpec.Subnets = infrav1.Subnets{
+		{ID: "subnet-a", AvailabilityZone: "us-east-1a"},
+		{ID: "subnet-b", AvailabilityZone: "us-east-1b"},
+	}
+	awsMachinePool := &expinfrav1.AWSMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+			Annotations: map[string]string{
+				expinfrav1.AZEvacuateAnnotation:      "us-east-1a",
+				expinfrav1.AZEvacuateSurgeAnnotation: "true",
+			},
+		},
+		Spec: expinfrav1.AWSMachinePoolSpec{MinSize: 1, MaxSize: 10},
+	}
+	ms := &scope.MachinePoolScope{
+		Logger:         *logger.NewLogger(logr.Discard()),
+		InfraCluster:   cs,
+		MachinePool:    &expclusterv1.MachinePool{Spec: expclusterv1.MachinePoolSpec{Replicas: ptr.To[int32](3)}},
+		AWSMachinePool: awsMachinePool,
+	}
+	recorder := record.NewFakeRecorder(2)
+	reconciler := &AWSMachinePoolReconciler{Recorder: recorder}
+	asg := &expinfrav1.AutoScalingGroup{
+		Instances: []infrav1.Instance{
+			{ID: "i-1", AvailabilityZone: "us-east-1a"},
+			{ID: "i-2", AvailabilityZone: "us-east-1a"},
+			{ID: "i-3", AvailabilityZone: "us-east-1b"},
+		},
+	}
+	asgSvc.EXPECT().SubnetIDs(ms).Return([]string{"subnet-a", "subnet-b"}, nil).AnyTimes()
+
+	// The instances of the evacuated availability zone are surged in the other ones.
+	g.Expect(reconciler.reconcileEvacuation(ms, asgSvc, asg)).To(Succeed())
+	g.Expect(awsMachinePool.Status.EvacuatedAvailabilityZones).To(Equal([]expinfrav1.EvacuatedAvailabilityZone{{Name: "us-east-1a", Subnets: []string{"subnet-a"}, Surge: 2}}))
+	g.Expect(awsMachinePool.WithoutEvacuatedSubnets([]string{"subnet-a", "subnet-b"})).To(Equal([]string{"subnet-b"}))
+	g.Expect(ms.DesiredCapacity()).To(Equal(int32(5)))
+	g.Expect(conditions.IsTrue(awsMachinePool, expinfrav1.AvailabilityZonesEvacuatedCondition)).To(BeTrue())
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("AZEvacuated")))
+
+	// The surge isn't computed again once the instances left the availability zone.
+	asg.Instances = []infrav1.Instance{{ID: "i-3", AvailabilityZone: "us-east-1b"}}
+	g.Expect(reconciler.reconcileEvacuation(ms, asgSvc, asg)).To(Succeed())
+	g.Expect(ms.AWSMachinePool.EvacuationSurge()).To(Equal(int32(2)))
+	g.Expect(recorder.Events).NotTo(Receive())
+
+	// Evacuating every availability zone is refused, and the previous evacuation is kept.
+	awsMachinePool.Annotations[expinfrav1.AZEvacuateAnnotation] = "us-east-1a, us-east-1b"
+	g.Expect(reconciler.reconcileEvacuation(ms, asgSvc, asg)).To(Succeed())
+	g.Expect(awsMachinePool.Status.EvacuatedAvailabilityZones).To(HaveLen(1))
+	g.Expect(conditions.GetReason(awsMachinePool, expinfrav1.AvailabilityZonesEvacuatedCondition)).To(Equal(expinfrav1.AZEvacuationRefusedReason))
+	g.Expect(recorder.Events).To(Receive(ContainSubstring(expinfrav1.AZEvacuationRefusedReason)))
+
+	// Removing the annotation restores the availability zone.
+	delete(awsMachinePool.Annotations, expinfrav1.AZEvacuateAnnotation)
+	g.Expect(reconciler.reconcileEvacuation(ms, asgSvc, asg)).To(Succeed())
+	g.Expect(awsMachinePool.Status.EvacuatedAvailabilityZones).To(BeEmpty())
+	g.Expect(ms.DesiredCapacity()).To(Equal(int32(3)))
+	g.Expect(conditions.Has(awsMachinePool, expinfrav1.AvailabilityZonesEvacuatedCondition)).To(BeFalse())
+	g.Expect(recorder.Events).To(Receive(ContainSubstring("AZRestored")))
+}
+
+func TestReconcileAdoption(t *testing.T) {
+	ownedBy := func(cluster string) infrav1.Tags {
+		return infrav1.Tags{infrav1.ClusterTagKey(cluster): string(infrav1.ResourceLifecycleOwned)}
+	}
+	managedBy := func(machinePool string) infrav1.Tags {
+		tags := ownedBy("test")
+		tags[infrav1.MachinePoolTagKey] = machinePool
+		return tags
+	}
+
+	tests := []struct {
+		name      string
+		asgName   string
+		annotated bool
+		tags      infrav1.Tags
+		wantAdopt bool
+		wantErr   string
+	}{
+		{
+			name:      "should not check the ASG named after the AWSMachinePool",
+			annotated: true,
+		},
+		{
+			name:    "should manage a named ASG owned by the cluster",
+			asgName: "legacy-asg",
+			tags:    ownedBy("test"),
+		},
+		{
+			name:    "should refuse a named ASG without the adoption annotation",
+			asgName: "legacy-asg",
+			wantErr: "set the aws.cluster.x-k8s.io/adopt-auto-scaling-group annotation to adopt it",
+		},
+		{
+			name:      "should refuse an ASG owned by another cluster",
+			asgName:   "legacy-asg",
+			annotated: true,
+			tags:      ownedBy("other"),
+			wantErr:   `is owned by cluster "other"`,
+		},
+		{
+			name:      "should refuse an ASG owned by another cluster according to the cloud provider tag",
+			asgName:   "legacy-asg",
+			annotated: true,
+			tags:      infrav1.Tags{infrav1.ClusterAWSCloudProviderTagKey("other"): string(infrav1.ResourceLifecycleOwned)},
+			wantErr:   `is owned by cluster "other"`,
+		},
+		{
+			name:      "should adopt an annotated ASG shared with another cluster",
+			asgName:   "legacy-asg",
+			annotated: true,
+			tags:      infrav1.Tags{infrav1.ClusterAWSCloudProviderTagKey("other"): string(infrav1.ResourceLifecycleShared)},
+			wantAdopt: true,
+		},
+		{
+			name:    "should manage a named ASG managed by the AWSMachinePool",
+			asgName: "legacy-asg",
+			tags:    managedBy("default/test"),
+		},
+		{
+			name:      "should refuse a named ASG managed by another AWSMachinePool of the cluster",
+			asgName:   "legacy-asg",
+			annotated: true,
+			tags:      managedBy("default/other"),
+			wantErr:   `is managed by AWSMachinePool "default/other"`,
+		},
+		{
+			name:    "should refuse an ASG named after the AWSMachinePool managed by another AWSMachinePool",
+			tags:    managedBy("other/test"),
+			wantErr: `is managed by AWSMachinePool "other/test"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			asgSvc := mock_services.NewMockASGInterface(mockCtrl)
+
+			awsMachinePool := &expinfrav1.AWSMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec:       expinfrav1.AWSMachinePoolSpec{AutoScalingGroupName: tt.asgName},
+			}
+			if tt.annotated {
+				awsMachinePool.Annotations = map[string]string{expinfrav1.AdoptAutoScalingGroupAnnotation: ""}
+			}
+			ms := &scope.MachinePoolScope{
+				Logger:         *logger.NewLogger(logr.Discard()),
+				AWSMachinePool: awsMachinePool,
+			}
+			recorder := record.NewFakeRecorder(2)
+			reconciler := &AWSMachinePoolReconciler{Recorder: recorder}
+			asg := &expinfrav1.AutoScalingGroup{Name: "legacy-asg", Tags: tt.tags}
+			if tt.wantAdopt {
+				asgSvc.EXPECT().UpdateResourceTags(ptr.To("legacy-asg"), managedBy("default/test"), nil).Return(nil)
+			}
+
+			err := reconciler.reconcileAdoption(ms, asgSvc, asg, "test")
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if tt.wantAdopt {
+				g.Expect(asg.Tags.HasOwned("test")).To(BeTrue())
+				g.Expect(asg.Tags).To(HaveKeyWithValue(infrav1.MachinePoolTagKey, "default/test"))
+				g.Expect(recorder.Events).To(Receive(ContainSubstring("AdoptedASG")))
+			}
+		})
+	}
+}
+
+func TestReplacedLaunchTemplate(t *testing.T) {
+	const (
+		managedID  = "lt-00000000000000001"
+		existingID = "lt-0123456789abcdef0"
+	)
+
+	t.Run("should record the CAPA managed launch template replaced by an existing one", func(t *testing.T) {
+		g := NewWithT(t)
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		ec2Svc := mock_services.NewMockEC2Interface(mockCtrl)
+
+		awsMachinePool := &expinfrav1.AWSMachinePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: expinfrav1.AWSMachinePoolSpec{
+				AWSLaunchTemplate: expinfrav1.AWSLaunchTemplate{ExistingTemplate: &expinfrav1.ExistingLaunchTemplate{ID: existingID}},
+			},
+			Status: expinfrav1.AWSMachinePoolStatus{LaunchTemplateID: managedID},
+		}
+		ms := &scope.MachinePoolScope{
+			Logger:         *logger.NewLogger(logr.Discard()),
+			AWSMachinePool: awsMachinePool,
+		}
+		recorder := record.NewFakeRecorder(2)
+		reconciler := &AWSMachinePoolReconciler{Recorder: recorder}
+
+		ec2Svc.EXPECT().GetLaunchTemplateID("test").Return(managedID, nil)
+		g.Expect(reconciler.recordReplacedLaunchTemplate(ms, ec2Svc)).To(Succeed())
+		g.Expect(awsMachinePool.Status.ReplacedLaunchTemplateID).To(Equal(managedID))
+		g.Expect(recorder.Events).To(Receive(ContainSubstring("LaunchTemplateReplaced")))
+
+		// Once recorded, the launch template isn't looked up again.
+		g.Expect(reconciler.recordReplacedLaunchTemplate(ms, ec2Svc)).To(Succeed())
+
+		// It is kept while the ASG still uses it.
+		g.Expect(reconciler.deleteReplacedLaunchTemplate(ms, ec2Svc, &expinfrav1.AutoScalingGroup{LaunchTemplateID: managedID})).To(Succeed())
+		g.Expect(awsMachinePool.Status.ReplacedLaunchTemplateID).To(Equal(managedID))
+
+		ec2Svc.EXPECT().DeleteLaunchTemplate(managedID).Return(nil)
+		g.Expect(reconciler.deleteReplacedLaunchTemplate(ms, ec2Svc, &expinfrav1.AutoScalingGroup{LaunchTemplateID: existingID})).To(Succeed())
+		g.Expect(awsMachinePool.Status.ReplacedLaunchTemplateID).To(BeEmpty())
+		g.Expect(recorder.Events).To(Receive(ContainSubstring("DeletedLaunchTemplate")))
+	})
+
+	t.Run("should not record a previous existing launch template", func(t *testing.T) {
+		g := NewWithT(t)
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		ec2Svc := mock_services.NewMockEC2Interface(mockCtrl)
+
+		awsMachinePool := &expinfrav1.AWSMachinePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: expinfrav1.AWSMachinePoolSpec{
+				AWSLaunchTemplate: expinfrav1.AWSLaunchTemplate{ExistingTemplate: &expinfrav1.ExistingLaunchTemplate{ID: existingID}},
+			},
+			Status: expinfrav1.AWSMachinePoolStatus{LaunchTemplateID: "lt-00000000000000002"},
+		}
+		ms := &scope.MachinePoolScope{
+			Logger:         *logger.NewLogger(logr.Discard()),
+			AWSMachinePool: awsMachinePool,
+		}
+		reconciler := &AWSMachinePoolReconciler{Recorder: record.NewFakeRecorder(2)}
+
+		ec2Svc.EXPECT().GetLaunchTemplateID("test").Return("", nil)
+		g.Expect(reconciler.recordReplacedLaunchTemplate(ms, ec2Svc)).To(Succeed())
+		g.Expect(awsMachinePool.Status.ReplacedLaunchTemplateID).To(BeEmpty())
+	})
+}
+
+func TestReconcileBootstrapToken(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	tests := []struct {
+		name             string
+		expiresIn        *time.Duration
+		replace          bool
+		poolAnnotations  map[string]string
+		wantRequeueAfter time.Duration
+		wantCondition    *corev1.ConditionStatus
+		wantEvents       []string
+		wantAnnotation   bool
+	}{
+		{
+			name: "bootstrap data secret without an expiry",
+		},
+		{
+			name:             "bootstrap token not expired yet",
+			expiresIn:        ptr.To(5 * time.Minute),
+			wantRequeueAfter: 5 * time.Minute,
+			wantCondition:    ptr.To(corev1.ConditionFalse),
+		},
+		{
+			name:          "bootstrap token expired",
+			expiresIn:     ptr.To(-time.Minute),
+			wantCondition: ptr.To(corev1.ConditionTrue),
+			wantEvents:    []string{"BootstrapTokenLikelyExpired"},
+		},
+		{
+			name:             "bootstrap token expired, a new version of the launch template is requested",
+			expiresIn:        ptr.To(-time.Minute),
+			replace:          true,
+			wantRequeueAfter: userDataRegenerationRequeueAfter,
+			wantCondition:    ptr.To(corev1.ConditionTrue),
+			wantEvents:       []string{"BootstrapTokenLikelyExpired", "UserDataRegenerationRequested"},
+			wantAnnotation:   true,
+		},
+		{
+			name:            "bootstrap token expired, a new version of the launch template was already requested",
+			expiresIn:       ptr.To(-time.Minute),
+			replace:         true,
+			poolAnnotations: map[string]string{infrav1.BootstrapTokenExpiredAnnotation: now.Add(-time.Minute).Format(time.RFC3339)},
+			wantCondition:   ptr.To(corev1.ConditionTrue),
+			wantEvents:      []string{"BootstrapTokenLikelyExpired"},
+			wantAnnotation:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-data", Namespace: "default"},
+				Data:       map[string][]byte{"value": []byte("user data")},
+			}
+			if tt.expiresIn != nil {
+				secret.Annotations = map[string]string{infrav1.BootstrapTokenExpiresAtAnnotation: now.Add(*tt.expiresIn).Format(time.RFC3339)}
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+			ms := &scope.MachinePoolScope{
+				Logger:         *logger.NewLogger(logr.Discard()),
+				Client:         c,
+				AWSMachinePool: &expinfrav1.AWSMachinePool{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", Annotations: tt.poolAnnotations}},
+				MachinePool: &expclusterv1.MachinePool{
+					Spec: expclusterv1.MachinePoolSpec{Template: clusterv1.MachineTemplateSpec{Spec: clusterv1.MachineSpec{
+						Bootstrap: clusterv1.Bootstrap{DataSecretName: ptr.To("bootstrap-data")},
+					}}},
+					Status: expclusterv1.MachinePoolStatus{NodeRefs: []corev1.ObjectReference{{Name: "node-1"}}},
+				},
+			}
+			recorder := record.NewFakeRecorder(2)
+			reconciler := &AWSMachinePoolReconciler{Recorder: recorder, ReplaceMachinesWithExpiredBootstrapToken: tt.replace}
+			asg := &expinfrav1.AutoScalingGroup{Name: "test", Instances: []infrav1.Instance{{ID: "i-1"}, {ID: "i-2"}}}
+
+			result := reconciler.reconcileBootstrapToken(context.Background(), ms, asg, now)
+			g.Expect(result.RequeueAfter).To(Equal(tt.wantRequeueAfter))
+
+			condition := conditions.Get(ms.AWSMachinePool, infrav1.BootstrapTokenLikelyExpiredCondition)
+			if tt.wantCondition == nil {
+				g.Expect(condition).To(BeNil())
+			} else {
+				g.Expect(condition.Status).To(Equal(*tt.wantCondition))
+				if *tt.wantCondition == corev1.ConditionTrue {
+					g.Expect(condition.Message).To(ContainSubstring("1 instances haven't joined the cluster"))
+				}
+			}
+			for _, event := range tt.wantEvents {
+				g.Expect(recorder.Events).To(Receive(ContainSubstring(event)))
+			}
+			g.Expect(recorder.Events).NotTo(Receive())
+
+			if tt.wantAnnotation {
+				g.Expect(ms.AWSMachinePool.Annotations).To(HaveKeyWithValue(infrav1.BootstrapTokenExpiredAnnotation, now.Add(-time.Minute).Format(time.RFC3339)))
+			} else {
+				g.Expect(ms.AWSMachinePool.Annotations).NotTo(HaveKey(infrav1.BootstrapTokenExpiredAnnotation))
+			}
 		})
 	}
 }
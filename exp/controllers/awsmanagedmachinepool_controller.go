@@ -30,7 +30,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -199,12 +198,21 @@ func (r *AWSManagedMachinePoolReconciler) reconcileNormal(
 ) error {
 	machinePoolScope.Info("Reconciling AWSManagedMachinePool")
 
-	if controllerutil.AddFinalizer(machinePoolScope.ManagedMachinePool, expinfrav1.ManagedMachinePoolFinalizer) {
+	//nolint:staticcheck
+	if replaceFinalizer(machinePoolScope.ManagedMachinePool, expinfrav1.ManagedMachinePoolFinalizer, expinfrav1.ManagedMachinePoolLegacyFinalizer) {
 		if err := machinePoolScope.PatchObject(); err != nil {
 			return err
 		}
 	}
 
+	// EKS Auto Mode manages the compute of the cluster, so the node group isn't created.
+	if machinePoolScope.ControlPlane.Spec.AutoMode.IsEnabled() {
+		r.Recorder.Eventf(machinePoolScope.ManagedMachinePool, corev1.EventTypeWarning, "EKSAutoModeEnabled", "Managed node groups can't be used with EKS Auto Mode")
+		conditions.MarkFalse(machinePoolScope.ManagedMachinePool, expinfrav1.EKSNodegroupReadyCondition, expinfrav1.EKSAutoModeEnabledReason, clusterv1.ConditionSeverityError,
+			"managed node groups can't be used with the EKS Auto Mode of control plane %s", machinePoolScope.ControlPlane.Name)
+		return nil
+	}
+
 	ekssvc := eks.NewNodegroupService(machinePoolScope)
 	ec2svc := r.getEC2Service(ec2Scope)
 	reconSvc := r.getReconcileService(ec2Scope)
@@ -236,10 +244,26 @@ func (r *AWSManagedMachinePoolReconciler) reconcileNormal(
 		conditions.MarkTrue(machinePoolScope.ManagedMachinePool, expinfrav1.LaunchTemplateReadyCondition)
 	}
 
+	// The replicas are handed over to the autoscaling before the node group is reconciled, so that its
+	// desired size isn't reset to the replicas of the MachinePool, and given back once the node group
+	// was reconciled, as its cluster autoscaler tags are removed with the replicas-managed-by annotation.
+	autoscaling := machinePoolScope.ManagedMachinePool.Spec.Autoscaling.IsEnabled()
+	if autoscaling {
+		if err := machinePoolScope.SetAutoscalingManagesReplicas(ctx, true); err != nil {
+			return errors.Wrap(err, "failed to hand over the MachinePool replicas to the autoscaling")
+		}
+	}
+
 	if err := ekssvc.ReconcilePool(ctx); err != nil {
 		return errors.Wrapf(err, "failed to reconcile machine pool for AWSManagedMachinePool %s/%s", machinePoolScope.ManagedMachinePool.Namespace, machinePoolScope.ManagedMachinePool.Name)
 	}
 
+	if !autoscaling {
+		if err := machinePoolScope.SetAutoscalingManagesReplicas(ctx, false); err != nil {
+			return errors.Wrap(err, "failed to give the replicas back to the MachinePool")
+		}
+	}
+
 	return nil
 }
 
@@ -267,7 +291,8 @@ func (r *AWSManagedMachinePoolReconciler) reconcileDelete(
 		if launchTemplate == nil {
 			machinePoolScope.Debug("Unable to find matching launch template")
 			r.Recorder.Eventf(machinePoolScope.ManagedMachinePool, corev1.EventTypeNormal, "NoLaunchTemplateFound", "Unable to find matching launch template")
-			controllerutil.RemoveFinalizer(machinePoolScope.ManagedMachinePool, expinfrav1.ManagedMachinePoolFinalizer)
+			//nolint:staticcheck
+			removeFinalizers(machinePoolScope.ManagedMachinePool, expinfrav1.ManagedMachinePoolFinalizer, expinfrav1.ManagedMachinePoolLegacyFinalizer)
 			return nil
 		}
 
@@ -280,7 +305,8 @@ func (r *AWSManagedMachinePoolReconciler) reconcileDelete(
 		machinePoolScope.Info("successfully deleted launch template")
 	}
 
-	controllerutil.RemoveFinalizer(machinePoolScope.ManagedMachinePool, expinfrav1.ManagedMachinePoolFinalizer)
+	//nolint:staticcheck
+	removeFinalizers(machinePoolScope.ManagedMachinePool, expinfrav1.ManagedMachinePoolFinalizer, expinfrav1.ManagedMachinePoolLegacyFinalizer)
 
 	return nil
 }
@@ -0,0 +1,36 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// replaceFinalizer adds the finalizer to the object in place of the legacy finalizer set by the
+// previous releases, and returns whether the finalizers of the object changed.
+func replaceFinalizer(obj client.Object, finalizer, legacyFinalizer string) bool {
+	added := controllerutil.AddFinalizer(obj, finalizer)
+	removed := controllerutil.RemoveFinalizer(obj, legacyFinalizer)
+	return added || removed
+}
+
+// removeFinalizers removes the finalizer and the legacy finalizer from the object.
+func removeFinalizers(obj client.Object, finalizer, legacyFinalizer string) {
+	controllerutil.RemoveFinalizer(obj, finalizer)
+	controllerutil.RemoveFinalizer(obj, legacyFinalizer)
+}
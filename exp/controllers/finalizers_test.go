@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+)
+
+func TestReplaceFinalizer(t *testing.T) {
+	tests := []struct {
+		name               string
+		finalizers         []string
+		expectedFinalizers []string
+		expectChanged      bool
+	}{
+		{
+			name:               "should add the finalizer to a new object",
+			finalizers:         nil,
+			expectedFinalizers: []string{expinfrav1.ManagedMachinePoolFinalizer},
+			expectChanged:      true,
+		},
+		{
+			name:               "should replace the legacy finalizer",
+			finalizers:         []string{"other", expinfrav1.ManagedMachinePoolLegacyFinalizer}, //nolint:staticcheck
+			expectedFinalizers: []string{"other", expinfrav1.ManagedMachinePoolFinalizer},
+			expectChanged:      true,
+		},
+		{
+			name:               "should not change an object with the finalizer",
+			finalizers:         []string{expinfrav1.ManagedMachinePoolFinalizer},
+			expectedFinalizers: []string{expinfrav1.ManagedMachinePoolFinalizer},
+			expectChanged:      false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			pool := &expinfrav1.AWSManagedMachinePool{}
+			pool.Finalizers = tc.finalizers
+
+			//nolint:staticcheck
+			changed := replaceFinalizer(pool, expinfrav1.ManagedMachinePoolFinalizer, expinfrav1.ManagedMachinePoolLegacyFinalizer)
+			g.Expect(changed).To(Equal(tc.expectChanged))
+			g.Expect(pool.Finalizers).To(ConsistOf(tc.expectedFinalizers))
+		})
+	}
+}
+
+func TestRemoveFinalizers(t *testing.T) {
+	g := NewWithT(t)
+	pool := &expinfrav1.ROSAMachinePool{}
+	//nolint:staticcheck
+	pool.Finalizers = []string{"other", expinfrav1.RosaMachinePoolFinalizer, expinfrav1.RosaMachinePoolLegacyFinalizer}
+
+	//nolint:staticcheck
+	removeFinalizers(pool, expinfrav1.RosaMachinePoolFinalizer, expinfrav1.RosaMachinePoolLegacyFinalizer)
+	g.Expect(pool.Finalizers).To(ConsistOf("other"))
+}
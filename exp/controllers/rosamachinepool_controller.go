@@ -23,7 +23,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -178,7 +177,8 @@ func (r *ROSAMachinePoolReconciler) reconcileNormal(ctx context.Context,
 ) (ctrl.Result, error) {
 	machinePoolScope.Info("Reconciling ROSAMachinePool")
 
-	if controllerutil.AddFinalizer(machinePoolScope.RosaMachinePool, expinfrav1.RosaMachinePoolFinalizer) {
+	//nolint:staticcheck
+	if replaceFinalizer(machinePoolScope.RosaMachinePool, expinfrav1.RosaMachinePoolFinalizer, expinfrav1.RosaMachinePoolLegacyFinalizer) {
 		if err := machinePoolScope.PatchObject(); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -307,7 +307,8 @@ func (r *ROSAMachinePoolReconciler) reconcileDelete(
 		}
 	}
 
-	controllerutil.RemoveFinalizer(machinePoolScope.RosaMachinePool, expinfrav1.RosaMachinePoolFinalizer)
+	//nolint:staticcheck
+	removeFinalizers(machinePoolScope.RosaMachinePool, expinfrav1.RosaMachinePoolFinalizer, expinfrav1.RosaMachinePoolLegacyFinalizer)
 
 	return nil
 }
@@ -97,8 +97,15 @@ func (r *AwsInstanceStateReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return reconcile.Result{}, nil
 	}
 
-	// retrieve queue URL if it isn't already tracked
-	if _, ok := r.queueURLs.Load(awsCluster.Name); !ok {
+	// Stop watching the queue of clusters for which EventBridge is disabled
+	if !instancestate.Enabled(awsCluster.Spec.EventBridge) {
+		r.queueURLs.Delete(req.Name)
+		return reconcile.Result{}, nil
+	}
+
+	// retrieve queue URL if it isn't already tracked, or if the queue of the cluster changed
+	queueName := getQueueName(awsCluster)
+	if qp, ok := r.queueURLs.Load(awsCluster.Name); !ok || qp.(queueParams).name != queueName {
 		URL, err := r.getQueueURL(awsCluster)
 		if err != nil {
 			if queueNotFoundError(err) {
@@ -106,7 +113,7 @@ func (r *AwsInstanceStateReconciler) Reconcile(ctx context.Context, req ctrl.Req
 			}
 			return reconcile.Result{}, err
 		}
-		r.queueURLs.Store(awsCluster.Name, queueParams{region: awsCluster.Spec.Region, URL: URL})
+		r.queueURLs.Store(awsCluster.Name, queueParams{region: awsCluster.Spec.Region, name: queueName, URL: URL})
 	}
 
 	return ctrl.Result{}, nil
@@ -128,8 +135,11 @@ func (r *AwsInstanceStateReconciler) watchQueuesForInstanceEvents() {
 	awsClusterList := &infrav1.AWSClusterList{}
 	if err := r.Client.List(ctx, awsClusterList); err == nil {
 		for i, cluster := range awsClusterList.Items {
+			if !instancestate.Enabled(cluster.Spec.EventBridge) {
+				continue
+			}
 			if URL, err := r.getQueueURL(&awsClusterList.Items[i]); err == nil {
-				r.queueURLs.Store(cluster.Name, queueParams{region: cluster.Spec.Region, URL: URL})
+				r.queueURLs.Store(cluster.Name, queueParams{region: cluster.Spec.Region, name: getQueueName(&awsClusterList.Items[i]), URL: URL})
 			}
 		}
 	}
@@ -220,8 +230,7 @@ func (r *AwsInstanceStateReconciler) getQueueURL(cluster *infrav1.AWSCluster) (s
 	if err != nil {
 		return "", err
 	}
-	queueName := instancestate.GenerateQueueName(cluster.Name)
-	resp, err := sqsSvs.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(queueName)})
+	resp, err := sqsSvs.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(getQueueName(cluster))})
 
 	if err != nil {
 		return "", err
@@ -230,6 +239,15 @@ func (r *AwsInstanceStateReconciler) getQueueURL(cluster *infrav1.AWSCluster) (s
 	return *resp.QueueUrl, nil
 }
 
+// getQueueName returns the name of the SQS queue of a cluster, as recorded in its status. Clusters
+// which didn't record it yet use the name of the queues created before it was recorded.
+func getQueueName(cluster *infrav1.AWSCluster) string {
+	if cluster.Status.EventBridge != nil {
+		return cluster.Status.EventBridge.QueueName
+	}
+	return instancestate.GenerateQueueName(cluster.Name)
+}
+
 func queueNotFoundError(err error) bool {
 	if aerr, ok := err.(awserr.Error); ok {
 		if aerr.Code() == sqs.ErrCodeQueueDoesNotExist {
@@ -241,6 +259,7 @@ func queueNotFoundError(err error) bool {
 
 type queueParams struct {
 	region string
+	name   string
 	URL    string
 }
 
@@ -0,0 +1,401 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spotinterruption provides a controller that listens for the spot interruption notices of the instances
+// of AWSMachinePools and deletes their Machines, so that they are drained before the instances are interrupted. The
+// instances without a Machine are terminated through their Auto Scaling group, which runs its termination lifecycle hooks.
+package spotinterruption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/controllers"
+	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/spotinterruption"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/logger"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// AWSSpotInterruptionReconciler tracks the queues receiving the spot interruption notices of the AWSMachinePools
+// which enable their handling, and deletes the Machines of the instances to be interrupted.
+type AWSSpotInterruptionReconciler struct {
+	client.Client
+	Log               logr.Logger
+	Recorder          record.EventRecorder
+	sqsServiceFactory func() sqsiface.SQSAPI
+	asgServiceFactory func(cloud.ClusterScoper) autoscalingiface.AutoScalingAPI
+	// queues maps the AWSMachinePools to the queue they record in their status.
+	queues sync.Map
+	// polling holds the URLs of the queues being polled, so that a queue shared by several AWSMachinePools
+	// isn't polled more than once at a time.
+	polling          sync.Map
+	Endpoints        []scope.ServiceEndpoint
+	WatchFilterValue string
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachinepools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=get;list;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=controlplane.cluster.x-k8s.io,resources=awsmanagedcontrolplanes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
+
+func (r *AWSSpotInterruptionReconciler) getSQSService(region string) (sqsiface.SQSAPI, error) {
+	if r.sqsServiceFactory != nil {
+		return r.sqsServiceFactory(), nil
+	}
+
+	globalScope, err := scope.NewGlobalScope(scope.GlobalScopeParams{
+		ControllerName: "awsspotinterruption",
+		Region:         region,
+		Endpoints:      r.Endpoints,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scope.NewGlobalSQSClient(globalScope, globalScope), nil
+}
+
+// getASGService returns an Auto Scaling client using the identity of the cluster of a machine pool, so that its
+// requests are audited and attributed to the cluster like the ones of the AWSMachinePool controller.
+func (r *AWSSpotInterruptionReconciler) getASGService(ctx context.Context, awsMachinePool *expinfrav1.AWSMachinePool) (autoscalingiface.AutoScalingAPI, error) {
+	clusterScope, err := r.getClusterScope(ctx, awsMachinePool)
+	if err != nil {
+		return nil, err
+	}
+	if r.asgServiceFactory != nil {
+		return r.asgServiceFactory(clusterScope), nil
+	}
+	return scope.NewASGClient(clusterScope, clusterScope, clusterScope, clusterScope.InfraCluster()), nil
+}
+
+// getClusterScope returns the scope of the cluster of a machine pool, which is either managed by an
+// AWSManagedControlPlane or an AWSCluster.
+func (r *AWSSpotInterruptionReconciler) getClusterScope(ctx context.Context, awsMachinePool *expinfrav1.AWSMachinePool) (cloud.ClusterScoper, error) {
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, awsMachinePool.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+	log := logger.NewLogger(r.Log)
+
+	if cluster.Spec.ControlPlaneRef != nil && cluster.Spec.ControlPlaneRef.Kind == controllers.AWSManagedControlPlaneRefKind {
+		controlPlane := &ekscontrolplanev1.AWSManagedControlPlane{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.ControlPlaneRef.Name}, controlPlane); err != nil {
+			return nil, err
+		}
+		return scope.NewManagedControlPlaneScope(scope.ManagedControlPlaneScopeParams{
+			Client:         r.Client,
+			Logger:         log,
+			Cluster:        cluster,
+			ControlPlane:   controlPlane,
+			ControllerName: "awsspotinterruption",
+			Endpoints:      r.Endpoints,
+		})
+	}
+
+	if cluster.Spec.InfrastructureRef == nil {
+		return nil, fmt.Errorf("cluster %s has no infrastructure reference", klog.KObj(cluster))
+	}
+	awsCluster := &infrav1.AWSCluster{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.InfrastructureRef.Name}, awsCluster); err != nil {
+		return nil, err
+	}
+	return scope.NewClusterScope(scope.ClusterScopeParams{
+		Client:         r.Client,
+		Logger:         log,
+		Cluster:        cluster,
+		AWSCluster:     awsCluster,
+		ControllerName: "awsspotinterruption",
+		Endpoints:      r.Endpoints,
+	})
+}
+
+func (r *AWSSpotInterruptionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	awsMachinePool := &expinfrav1.AWSMachinePool{}
+	if err := r.Get(ctx, req.NamespacedName, awsMachinePool); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Log.Info("machine pool not found, removing queue URL", "awsMachinePool", klog.KRef(req.Namespace, req.Name))
+			r.queues.Delete(req.NamespacedName)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Stop watching the queue of deleted machine pools, and of the ones which disabled the handling
+	queueARN := awsMachinePool.Status.SpotInterruptionQueueARN
+	if !awsMachinePool.DeletionTimestamp.IsZero() || !awsMachinePool.Spec.SpotInterruptionHandling.IsEnabled() || queueARN == "" {
+		r.queues.Delete(req.NamespacedName)
+		return reconcile.Result{}, nil
+	}
+
+	if qp, ok := r.queues.Load(req.NamespacedName); ok && qp.(queueParams).arn == queueARN {
+		return reconcile.Result{}, nil
+	}
+
+	qp, err := r.getQueueParams(queueARN)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	r.queues.Store(req.NamespacedName, qp)
+
+	return reconcile.Result{}, nil
+}
+
+func (r *AWSSpotInterruptionReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	go func() {
+		r.watchQueuesForSpotInterruptions()
+	}()
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&expinfrav1.AWSMachinePool{}).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(logger.FromContext(ctx).GetLogger(), r.WatchFilterValue)).
+		Complete(r)
+}
+
+func (r *AWSSpotInterruptionReconciler) watchQueuesForSpotInterruptions() {
+	ctx := context.TODO()
+	for range time.Tick(1 * time.Second) {
+		// the machine pools of a cluster share its queue, which is polled once
+		queues := map[string]queueParams{}
+		r.queues.Range(func(_, val interface{}) bool {
+			qp := val.(queueParams)
+			queues[qp.URL] = qp
+			return true
+		})
+
+		for _, qp := range queues {
+			if _, polling := r.polling.LoadOrStore(qp.URL, struct{}{}); polling {
+				continue
+			}
+			go func(qp queueParams) {
+				defer r.polling.Delete(qp.URL)
+				r.pollQueue(ctx, qp)
+			}(qp)
+		}
+	}
+}
+
+// pollQueue receives the messages of a queue, and deletes them once processed. The notices which can't be processed
+// aren't retried, as the instances they are about are interrupted by the time they would be.
+func (r *AWSSpotInterruptionReconciler) pollQueue(ctx context.Context, qp queueParams) {
+	sqsSvs, err := r.getSQSService(qp.region)
+	if err != nil {
+		r.Log.Error(err, "unable to create SQS client")
+		return
+	}
+	resp, err := sqsSvs.ReceiveMessage(&sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(qp.URL),
+		MaxNumberOfMessages: aws.Int64(10),
+	})
+	if err != nil {
+		r.Log.Error(err, "failed to receive messages", "queueURL", qp.URL)
+		return
+	}
+
+	for _, msg := range resp.Messages {
+		m := message{}
+		if err := json.Unmarshal([]byte(aws.StringValue(msg.Body)), &m); err != nil {
+			r.Log.Error(err, "unable to unmarshal message", "queueURL", qp.URL)
+		} else {
+			r.processMessage(ctx, m)
+		}
+
+		_, err = sqsSvs.DeleteMessage(&sqs.DeleteMessageInput{
+			QueueUrl:      aws.String(qp.URL),
+			ReceiptHandle: msg.ReceiptHandle,
+		})
+		if err != nil {
+			r.Log.Error(err, "error deleting message", "queueURL", qp.URL, "messageReceiptHandle", msg.ReceiptHandle)
+		}
+	}
+}
+
+// processMessage deletes the Machine of the instance a spot interruption notice is about, if the instance belongs to
+// an AWSMachinePool which handles the notice. The instances of pools without MachinePool Machines are terminated
+// through their Auto Scaling group instead, which replaces them and runs the termination lifecycle hooks draining
+// them. The rule of a cluster matches the notices of every instance of the region, so the notices of the instances
+// of other clusters are ignored.
+func (r *AWSSpotInterruptionReconciler) processMessage(ctx context.Context, msg message) {
+	if msg.Source != "aws.ec2" || msg.MessageDetail == nil || msg.MessageDetail.InstanceID == "" {
+		return
+	}
+	if msg.DetailType != spotinterruption.SpotInterruptionWarning && msg.DetailType != spotinterruption.RebalanceRecommendation {
+		return
+	}
+	instanceID := msg.MessageDetail.InstanceID
+
+	awsMachinePool, err := r.findMachinePool(ctx, instanceID)
+	if err != nil {
+		r.Log.Error(err, "unable to list machine pools", "instanceID", instanceID)
+		return
+	}
+	if awsMachinePool == nil {
+		return
+	}
+	if msg.DetailType == spotinterruption.RebalanceRecommendation && !awsMachinePool.Spec.SpotInterruptionHandling.RebalanceRecommendations {
+		return
+	}
+
+	log := r.Log.WithValues("awsMachinePool", klog.KObj(awsMachinePool), "instanceID", instanceID, "notice", msg.DetailType)
+	machine, err := r.findMachine(ctx, awsMachinePool, instanceID)
+	if err != nil {
+		log.Error(err, "unable to list machines")
+		return
+	}
+	if machine == nil {
+		r.terminateInstance(ctx, log, awsMachinePool, msg)
+		return
+	}
+	if !machine.DeletionTimestamp.IsZero() {
+		return
+	}
+
+	log.Info("Deleting the Machine of an instance to be interrupted", "machine", klog.KObj(machine))
+	if err := r.Delete(ctx, machine); err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, "unable to delete machine", "machine", klog.KObj(machine))
+		return
+	}
+	r.Recorder.Eventf(awsMachinePool, corev1.EventTypeNormal, "SpotInterruptionMachineDeleted", "Deleted Machine %s after receiving %q for instance %s", machine.Name, msg.DetailType, instanceID)
+}
+
+// terminateInstance terminates an instance without a Machine through its Auto Scaling group, without decrementing the
+// desired capacity of the group so that the instance is replaced.
+func (r *AWSSpotInterruptionReconciler) terminateInstance(ctx context.Context, log logr.Logger, awsMachinePool *expinfrav1.AWSMachinePool, msg message) {
+	instanceID := msg.MessageDetail.InstanceID
+	asgSvc, err := r.getASGService(ctx, awsMachinePool)
+	if err != nil {
+		log.Error(err, "unable to create ASG client")
+		return
+	}
+
+	log.Info("Terminating an instance to be interrupted which has no Machine")
+	_, err = asgSvc.TerminateInstanceInAutoScalingGroupWithContext(ctx, &autoscaling.TerminateInstanceInAutoScalingGroupInput{
+		InstanceId:                     aws.String(instanceID),
+		ShouldDecrementDesiredCapacity: aws.Bool(false),
+	})
+	if err != nil {
+		log.Error(err, "unable to terminate instance")
+		r.Recorder.Eventf(awsMachinePool, corev1.EventTypeWarning, "FailedSpotInterruptionInstanceTermination", "Failed to terminate instance %s after receiving %q: %v", instanceID, msg.DetailType, err)
+		return
+	}
+	r.Recorder.Eventf(awsMachinePool, corev1.EventTypeNormal, "SpotInterruptionInstanceTerminated", "Terminated instance %s, which has no Machine, after receiving %q", instanceID, msg.DetailType)
+}
+
+// findMachinePool returns the AWSMachinePool handling the spot interruptions of an instance, if any.
+func (r *AWSSpotInterruptionReconciler) findMachinePool(ctx context.Context, instanceID string) (*expinfrav1.AWSMachinePool, error) {
+	awsMachinePools := &expinfrav1.AWSMachinePoolList{}
+	if err := r.List(ctx, awsMachinePools); err != nil {
+		return nil, err
+	}
+	for i, awsMachinePool := range awsMachinePools.Items {
+		if !awsMachinePool.Spec.SpotInterruptionHandling.IsEnabled() {
+			continue
+		}
+		for _, providerID := range awsMachinePool.Spec.ProviderIDList {
+			if instanceIDFromProviderID(providerID) == instanceID {
+				return &awsMachinePools.Items[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// findMachine returns the Machine of the cluster of an AWSMachinePool whose provider ID is the one of an instance.
+func (r *AWSSpotInterruptionReconciler) findMachine(ctx context.Context, awsMachinePool *expinfrav1.AWSMachinePool, instanceID string) (*clusterv1.Machine, error) {
+	clusterName, ok := awsMachinePool.Labels[clusterv1.ClusterNameLabel]
+	if !ok {
+		return nil, fmt.Errorf("machine pool has no %s label", clusterv1.ClusterNameLabel)
+	}
+	machines := &clusterv1.MachineList{}
+	if err := r.List(ctx, machines, client.InNamespace(awsMachinePool.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: clusterName}); err != nil {
+		return nil, err
+	}
+	for i, machine := range machines.Items {
+		if machine.Spec.ProviderID != nil && instanceIDFromProviderID(*machine.Spec.ProviderID) == instanceID {
+			return &machines.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// getQueueParams returns the region and URL of a queue given its ARN.
+func (r *AWSSpotInterruptionReconciler) getQueueParams(queueARN string) (queueParams, error) {
+	parsed, err := arn.Parse(queueARN)
+	if err != nil {
+		return queueParams{}, fmt.Errorf("invalid queue ARN %q: %w", queueARN, err)
+	}
+	sqsSvs, err := r.getSQSService(parsed.Region)
+	if err != nil {
+		return queueParams{}, err
+	}
+	resp, err := sqsSvs.GetQueueUrl(&sqs.GetQueueUrlInput{
+		QueueName:              aws.String(parsed.Resource),
+		QueueOwnerAWSAccountId: aws.String(parsed.AccountID),
+	})
+	if err != nil {
+		return queueParams{}, err
+	}
+	return queueParams{region: parsed.Region, arn: queueARN, URL: aws.StringValue(resp.QueueUrl)}, nil
+}
+
+// instanceIDFromProviderID returns the instance ID of a provider ID, e.g. i-0123 for aws:///us-east-1a/i-0123.
+func instanceIDFromProviderID(providerID string) string {
+	return providerID[strings.LastIndex(providerID, "/")+1:]
+}
+
+type queueParams struct {
+	region string
+	arn    string
+	URL    string
+}
+
+type message struct {
+	Source        string         `json:"source"`
+	Region        string         `json:"region"`
+	DetailType    string         `json:"detail-type,omitempty"`
+	MessageDetail *messageDetail `json:"detail,omitempty"`
+}
+
+type messageDetail struct {
+	InstanceID string `json:"instance-id,omitempty"`
+}
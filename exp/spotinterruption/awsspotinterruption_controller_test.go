@@ -0,0 +1,314 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotinterruption
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/autoscaling/mock_autoscalingiface"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/instancestate/mock_sqsiface"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/spotinterruption"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const testQueueARN = "arn:aws:sqs:us-east-1:123456789012:test-cluster-uid-spot-queue"
+
+func setupScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	_ = ekscontrolplanev1.AddToScheme(scheme)
+	_ = expinfrav1.AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+	return scheme
+}
+
+func newAWSMachinePool(name string, handling *expinfrav1.SpotInterruptionHandling, providerIDs ...string) *expinfrav1.AWSMachinePool {
+	return &expinfrav1.AWSMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "test-cluster"},
+		},
+		Spec: expinfrav1.AWSMachinePoolSpec{
+			ProviderIDList:           providerIDs,
+			SpotInterruptionHandling: handling,
+		},
+		Status: expinfrav1.AWSMachinePoolStatus{SpotInterruptionQueueARN: testQueueARN},
+	}
+}
+
+func newMachine(name, providerID string) *clusterv1.Machine {
+	return &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterNameLabel: "test-cluster"},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "test-cluster",
+			ProviderID:  ptr.To(providerID),
+		},
+	}
+}
+
+func newCluster(controlPlaneRef *corev1.ObjectReference) []client.Object {
+	return []client.Object{
+		&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: clusterv1.ClusterSpec{
+				ControlPlaneRef:   controlPlaneRef,
+				InfrastructureRef: &corev1.ObjectReference{Kind: "AWSCluster", Name: "test-aws-cluster"},
+			},
+		},
+		&infrav1.AWSCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-aws-cluster", Namespace: "default"},
+			Spec:       infrav1.AWSClusterSpec{Region: "us-east-1"},
+		},
+		&ekscontrolplanev1.AWSManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-control-plane", Namespace: "default"},
+			Spec:       ekscontrolplanev1.AWSManagedControlPlaneSpec{Region: "us-east-1"},
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	sqsSvs := mock_sqsiface.NewMockSQSAPI(mockCtrl)
+	sqsSvs.EXPECT().GetQueueUrl(&sqs.GetQueueUrlInput{
+		QueueName:              aws.String("test-cluster-uid-spot-queue"),
+		QueueOwnerAWSAccountId: aws.String("123456789012"),
+	}).Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("queue-url")}, nil).Times(1)
+
+	enabled := newAWSMachinePool("enabled", &expinfrav1.SpotInterruptionHandling{Enabled: true})
+	disabled := newAWSMachinePool("disabled", nil)
+	c := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(enabled, disabled).Build()
+	r := &AWSSpotInterruptionReconciler{
+		Client:            c,
+		Log:               logr.Discard(),
+		sqsServiceFactory: func() sqsiface.SQSAPI { return sqsSvs },
+	}
+
+	enabledKey := types.NamespacedName{Namespace: "default", Name: "enabled"}
+	disabledKey := types.NamespacedName{Namespace: "default", Name: "disabled"}
+	for i := 0; i < 2; i++ {
+		// The URL of a tracked queue isn't retrieved again.
+		_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: enabledKey})
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+	_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: disabledKey})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	qp, ok := r.queues.Load(enabledKey)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(qp).To(Equal(queueParams{region: "us-east-1", arn: testQueueARN, URL: "queue-url"}))
+	_, ok = r.queues.Load(disabledKey)
+	g.Expect(ok).To(BeFalse())
+
+	// The queue of a deleted machine pool isn't watched anymore.
+	g.Expect(c.Delete(context.TODO(), enabled)).To(Succeed())
+	_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: enabledKey})
+	g.Expect(err).NotTo(HaveOccurred())
+	_, ok = r.queues.Load(enabledKey)
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestProcessMessage(t *testing.T) {
+	testCases := []struct {
+		name           string
+		handling       *expinfrav1.SpotInterruptionHandling
+		msg            message
+		expectDeleted  bool
+		expectEvent    string
+		withoutMachine bool
+		terminateErr   error
+	}{
+		{
+			name:          "deletes the Machine of an instance to be interrupted",
+			handling:      &expinfrav1.SpotInterruptionHandling{Enabled: true},
+			msg:           message{Source: "aws.ec2", DetailType: spotinterruption.SpotInterruptionWarning, MessageDetail: &messageDetail{InstanceID: "i-1"}},
+			expectDeleted: true,
+			expectEvent:   "SpotInterruptionMachineDeleted",
+		},
+		{
+			name:          "deletes the Machine of an instance with a rebalance recommendation if the pool handles them",
+			handling:      &expinfrav1.SpotInterruptionHandling{Enabled: true, RebalanceRecommendations: true},
+			msg:           message{Source: "aws.ec2", DetailType: spotinterruption.RebalanceRecommendation, MessageDetail: &messageDetail{InstanceID: "i-1"}},
+			expectDeleted: true,
+			expectEvent:   "SpotInterruptionMachineDeleted",
+		},
+		{
+			name:     "ignores rebalance recommendations if the pool doesn't handle them",
+			handling: &expinfrav1.SpotInterruptionHandling{Enabled: true},
+			msg:      message{Source: "aws.ec2", DetailType: spotinterruption.RebalanceRecommendation, MessageDetail: &messageDetail{InstanceID: "i-1"}},
+		},
+		{
+			name:     "ignores the instances of the pools which don't handle spot interruptions",
+			handling: &expinfrav1.SpotInterruptionHandling{Enabled: false},
+			msg:      message{Source: "aws.ec2", DetailType: spotinterruption.SpotInterruptionWarning, MessageDetail: &messageDetail{InstanceID: "i-1"}},
+		},
+		{
+			name:     "ignores the instances of other clusters",
+			handling: &expinfrav1.SpotInterruptionHandling{Enabled: true},
+			msg:      message{Source: "aws.ec2", DetailType: spotinterruption.SpotInterruptionWarning, MessageDetail: &messageDetail{InstanceID: "i-other"}},
+		},
+		{
+			name:     "ignores other notices",
+			handling: &expinfrav1.SpotInterruptionHandling{Enabled: true},
+			msg:      message{Source: "aws.ec2", DetailType: "EC2 Instance State-change Notification", MessageDetail: &messageDetail{InstanceID: "i-1"}},
+		},
+		{
+			name:           "terminates the instance through its Auto Scaling group if it has no Machine",
+			handling:       &expinfrav1.SpotInterruptionHandling{Enabled: true},
+			msg:            message{Source: "aws.ec2", Region: "us-east-1", DetailType: spotinterruption.SpotInterruptionWarning, MessageDetail: &messageDetail{InstanceID: "i-1"}},
+			withoutMachine: true,
+			expectEvent:    "SpotInterruptionInstanceTerminated",
+		},
+		{
+			name:           "records an event if the instance without a Machine can't be terminated",
+			handling:       &expinfrav1.SpotInterruptionHandling{Enabled: true},
+			msg:            message{Source: "aws.ec2", Region: "us-east-1", DetailType: spotinterruption.SpotInterruptionWarning, MessageDetail: &messageDetail{InstanceID: "i-1"}},
+			withoutMachine: true,
+			terminateErr:   awserr.New("ValidationError", "instance is not part of an Auto Scaling group", nil),
+			expectEvent:    "FailedSpotInterruptionInstanceTermination",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			asgSvc := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+			if tc.withoutMachine {
+				asgSvc.EXPECT().TerminateInstanceInAutoScalingGroupWithContext(context.TODO(), &autoscaling.TerminateInstanceInAutoScalingGroupInput{
+					InstanceId:                     aws.String("i-1"),
+					ShouldDecrementDesiredCapacity: aws.Bool(false),
+				}).Return(&autoscaling.TerminateInstanceInAutoScalingGroupOutput{}, tc.terminateErr)
+			}
+
+			objects := append(newCluster(nil),
+				newAWSMachinePool("pool", tc.handling, "aws:///us-east-1a/i-1", "aws:///us-east-1a/i-2"),
+				newMachine("machine-2", "aws:///us-east-1a/i-2"),
+			)
+			if !tc.withoutMachine {
+				objects = append(objects, newMachine("machine-1", "aws:///us-east-1a/i-1"))
+			}
+			c := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(objects...).Build()
+			recorder := record.NewFakeRecorder(1)
+			r := &AWSSpotInterruptionReconciler{
+				Client:            c,
+				Log:               logr.Discard(),
+				Recorder:          recorder,
+				asgServiceFactory: func(cloud.ClusterScoper) autoscalingiface.AutoScalingAPI { return asgSvc },
+			}
+
+			r.processMessage(context.TODO(), tc.msg)
+
+			err := c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "machine-1"}, &clusterv1.Machine{})
+			if tc.expectDeleted || tc.withoutMachine {
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "machine-2"}, &clusterv1.Machine{})).To(Succeed())
+			if tc.expectEvent != "" {
+				g.Expect(recorder.Events).To(Receive(ContainSubstring(tc.expectEvent)))
+			} else {
+				g.Expect(recorder.Events).NotTo(Receive())
+			}
+		})
+	}
+}
+
+func TestTerminateInstanceUsesClusterScope(t *testing.T) {
+	testCases := []struct {
+		name               string
+		controlPlaneRef    *corev1.ObjectReference
+		expectInfraCluster string
+	}{
+		{
+			name:               "uses the scope of the AWSCluster",
+			controlPlaneRef:    &corev1.ObjectReference{Kind: "KubeadmControlPlane", Name: "test-control-plane"},
+			expectInfraCluster: "test-aws-cluster",
+		},
+		{
+			name:               "uses the scope of the AWSManagedControlPlane",
+			controlPlaneRef:    &corev1.ObjectReference{Kind: "AWSManagedControlPlane", Name: "test-control-plane"},
+			expectInfraCluster: "test-control-plane",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			asgSvc := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+			asgSvc.EXPECT().TerminateInstanceInAutoScalingGroupWithContext(context.TODO(), gomock.Any()).
+				Return(&autoscaling.TerminateInstanceInAutoScalingGroupOutput{}, nil)
+
+			pool := newAWSMachinePool("pool", &expinfrav1.SpotInterruptionHandling{Enabled: true}, "aws:///us-east-1a/i-1")
+			c := fake.NewClientBuilder().WithScheme(setupScheme()).WithObjects(append(newCluster(tc.controlPlaneRef), pool)...).Build()
+			var clusterScope cloud.ClusterScoper
+			r := &AWSSpotInterruptionReconciler{
+				Client:   c,
+				Log:      logr.Discard(),
+				Recorder: record.NewFakeRecorder(1),
+				asgServiceFactory: func(s cloud.ClusterScoper) autoscalingiface.AutoScalingAPI {
+					clusterScope = s
+					return asgSvc
+				},
+			}
+
+			r.processMessage(context.TODO(), message{Source: "aws.ec2", Region: "us-east-1", DetailType: spotinterruption.SpotInterruptionWarning, MessageDetail: &messageDetail{InstanceID: "i-1"}})
+
+			g.Expect(clusterScope).NotTo(BeNil())
+			g.Expect(clusterScope.InfraClusterName()).To(Equal(tc.expectInfraCluster))
+			g.Expect(clusterScope.ControllerName()).To(Equal("awsspotinterruption"))
+		})
+	}
+}
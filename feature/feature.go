@@ -86,6 +86,31 @@ const (
 	// owner: @enxebre
 	// alpha: v2.2
 	ROSA featuregate.Feature = "ROSA"
+
+	// SecurityGroupRuleCompaction is used to compact security group ingress rules by merging contiguous
+	// port ranges and adjacent CIDR blocks, to stay below the rules per security group quota.
+	// owner: @sebltm
+	// alpha: v2.5
+	SecurityGroupRuleCompaction featuregate.Feature = "SecurityGroupRuleCompaction"
+
+	// UserDataDriftDetection is used to tag instances with the hash of the user data they were launched with,
+	// and to report AWSMachines whose instance user data no longer matches it.
+	// owner: @sebltm
+	// alpha: v2.5
+	UserDataDriftDetection featuregate.Feature = "UserDataDriftDetection"
+
+	// UserDataTransform is used to render the user data of instances with a Go template referenced by
+	// AWSMachines and launch templates.
+	// owner: @sebltm
+	// alpha: v2.5
+	UserDataTransform featuregate.Feature = "UserDataTransform"
+
+	// SpotInterruptionHandling is used to receive the spot interruption notices of the instances of
+	// AWSMachinePools through EventBridge and SQS, and to delete their Machines ahead of the interruption, or to
+	// terminate the instances without a Machine through their Auto Scaling group.
+	// owner: @sebltm
+	// alpha: v2.5
+	SpotInterruptionHandling featuregate.Feature = "SpotInterruptionHandling"
 )
 
 func init() {
@@ -108,4 +133,8 @@ var defaultCAPAFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	AlternativeGCStrategy:         {Default: false, PreRelease: featuregate.Alpha},
 	TagUnmanagedNetworkResources:  {Default: true, PreRelease: featuregate.Alpha},
 	ROSA:                          {Default: false, PreRelease: featuregate.Alpha},
+	SecurityGroupRuleCompaction:   {Default: false, PreRelease: featuregate.Alpha},
+	UserDataDriftDetection:        {Default: false, PreRelease: featuregate.Alpha},
+	UserDataTransform:             {Default: false, PreRelease: featuregate.Alpha},
+	SpotInterruptionHandling:      {Default: false, PreRelease: featuregate.Alpha},
 }
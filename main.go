@@ -59,9 +59,14 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/v2/exp/controlleridentitycreator"
 	expcontrollers "sigs.k8s.io/cluster-api-provider-aws/v2/exp/controllers"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/exp/instancestate"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/exp/spotinterruption"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/audit"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/backoff"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/endpoints"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	ec2service "sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/ec2"
+	eniservice "sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/eni"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/logger"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/version"
@@ -106,6 +111,26 @@ var (
 	webhookCertDir           string
 	healthAddr               string
 	serviceEndpoints         string
+	caBundleFile             string
+
+	skipLifecycleHookPermissionCheck bool
+	allowOrphanCleanup               bool
+
+	backoffInitialDelay time.Duration
+	backoffMaxDelay     time.Duration
+	backoffJitter       float64
+
+	auditLogPath                 string
+	auditLogParametersSampleRate float64
+
+	launchTemplateVersionsToRetain int
+
+	forceNetworkCleanupMinAge time.Duration
+
+	machinePoolDeletionTimeout time.Duration
+
+	replaceMachinesWithExpiredBootstrapToken bool
+	bootstrapTokenTTL                        time.Duration
 
 	// maxEKSSyncPeriod is the maximum allowed duration for the sync-period flag when using EKS. It is set to 10 minutes
 	// because during resync it will create a new AWS auth token which can a maximum life of 15 minutes and this ensures
@@ -113,6 +138,7 @@ var (
 	maxEKSSyncPeriod         = time.Minute * 10
 	errMaxSyncPeriodExceeded = errors.New("sync period greater than maximum allowed")
 	errEKSInvalidFlags       = errors.New("invalid EKS flag combination")
+	errInvalidBackoffFlags   = errors.New("invalid backoff flags")
 
 	logOptions         = logs.NewOptions()
 	diagnosticsOptions = flags.DiagnosticsOptions{}
@@ -217,6 +243,40 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The CA bundle is validated before any AWS session is created, so that a bad bundle fails fast.
+	if caBundleFile != "" {
+		caBundle, err := os.ReadFile(caBundleFile)
+		if err != nil {
+			setupLog.Error(err, "unable to read AWS CA bundle", "file", caBundleFile)
+			os.Exit(1)
+		}
+		if err := scope.SetDefaultCABundle(caBundle); err != nil {
+			setupLog.Error(err, "invalid AWS CA bundle", "file", caBundleFile)
+			os.Exit(1)
+		}
+		setupLog.Info("Verifying the AWS API endpoints with the CA bundle", "file", caBundleFile)
+	}
+
+	if auditLogPath != "" {
+		auditLogger, err := audit.New(audit.Config{Path: auditLogPath, ParametersSampleRate: auditLogParametersSampleRate})
+		if err != nil {
+			setupLog.Error(err, "unable to open audit log", "path", auditLogPath)
+			os.Exit(1)
+		}
+		audit.SetDefaultLogger(auditLogger)
+		setupLog.Info("Recording the mutating AWS requests in the audit log", "path", auditLogPath)
+	}
+
+	if err := ec2service.SetLaunchTemplateVersionsToRetain(launchTemplateVersionsToRetain); err != nil {
+		setupLog.Error(err, "invalid number of launch template versions to retain")
+		os.Exit(1)
+	}
+
+	if err := eniservice.SetForceCleanupMinAge(forceNetworkCleanupMinAge); err != nil {
+		setupLog.Error(err, "invalid minimum age of the network interfaces released by force")
+		os.Exit(1)
+	}
+
 	setupReconcilersAndWebhooks(ctx, mgr, awsServiceEndpoints, externalResourceGC, alternativeGCStrategy)
 	if feature.Gates.Enabled(feature.EKS) {
 		setupEKSReconcilersAndWebhooks(ctx, mgr, awsServiceEndpoints, externalResourceGC, alternativeGCStrategy, waitInfraPeriod)
@@ -289,13 +349,31 @@ func main() {
 func setupReconcilersAndWebhooks(ctx context.Context, mgr ctrl.Manager, awsServiceEndpoints []scope.ServiceEndpoint,
 	externalResourceGC, alternativeGCStrategy bool,
 ) {
+	// The backoff of failed reconciliations is shared by the controllers, and tracked by the UID of the objects.
+	var backoffTracker *backoff.Tracker
+	if backoffInitialDelay > 0 {
+		if backoffMaxDelay < backoffInitialDelay || backoffJitter < 0 || backoffJitter > 1 {
+			setupLog.Error(errInvalidBackoffFlags, "the maximum delay must not be lower than the initial delay, and the jitter must be between 0 and 1",
+				"backoff-initial-delay", backoffInitialDelay, "backoff-max-delay", backoffMaxDelay, "backoff-jitter", backoffJitter)
+			os.Exit(1)
+		}
+		backoffTracker = backoff.NewTracker(backoff.Config{
+			InitialDelay: backoffInitialDelay,
+			MaxDelay:     backoffMaxDelay,
+			Jitter:       backoffJitter,
+		})
+	}
+
 	if err := (&controllers.AWSMachineReconciler{
-		Client:                       mgr.GetClient(),
-		Log:                          ctrl.Log.WithName("controllers").WithName("AWSMachine"),
-		Recorder:                     mgr.GetEventRecorderFor("awsmachine-controller"),
-		Endpoints:                    awsServiceEndpoints,
-		WatchFilterValue:             watchFilterValue,
-		TagUnmanagedNetworkResources: feature.Gates.Enabled(feature.TagUnmanagedNetworkResources),
+		Client:                                   mgr.GetClient(),
+		Log:                                      ctrl.Log.WithName("controllers").WithName("AWSMachine"),
+		Recorder:                                 mgr.GetEventRecorderFor("awsmachine-controller"),
+		Endpoints:                                awsServiceEndpoints,
+		WatchFilterValue:                         watchFilterValue,
+		TagUnmanagedNetworkResources:             feature.Gates.Enabled(feature.TagUnmanagedNetworkResources),
+		ReplaceMachinesWithExpiredBootstrapToken: replaceMachinesWithExpiredBootstrapToken,
+		BootstrapTokenTTL:                        bootstrapTokenTTL,
+		Backoff:                                  backoffTracker,
 	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: awsMachineConcurrency, RecoverPanic: ptr.To[bool](true)}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AWSMachine")
 		os.Exit(1)
@@ -309,6 +387,8 @@ func setupReconcilersAndWebhooks(ctx context.Context, mgr ctrl.Manager, awsServi
 		ExternalResourceGC:           externalResourceGC,
 		AlternativeGCStrategy:        alternativeGCStrategy,
 		TagUnmanagedNetworkResources: feature.Gates.Enabled(feature.TagUnmanagedNetworkResources),
+		AllowOrphanCleanup:           allowOrphanCleanup,
+		Backoff:                      backoffTracker,
 	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: awsClusterConcurrency, RecoverPanic: ptr.To[bool](true)}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AWSCluster")
 		os.Exit(1)
@@ -317,10 +397,15 @@ func setupReconcilersAndWebhooks(ctx context.Context, mgr ctrl.Manager, awsServi
 	if feature.Gates.Enabled(feature.MachinePool) {
 		setupLog.Debug("enabling machine pool controller and webhook")
 		if err := (&expcontrollers.AWSMachinePoolReconciler{
-			Client:                       mgr.GetClient(),
-			Recorder:                     mgr.GetEventRecorderFor("awsmachinepool-controller"),
-			WatchFilterValue:             watchFilterValue,
-			TagUnmanagedNetworkResources: feature.Gates.Enabled(feature.TagUnmanagedNetworkResources),
+			Client:                                   mgr.GetClient(),
+			Recorder:                                 mgr.GetEventRecorderFor("awsmachinepool-controller"),
+			WatchFilterValue:                         watchFilterValue,
+			TagUnmanagedNetworkResources:             feature.Gates.Enabled(feature.TagUnmanagedNetworkResources),
+			SkipLifecycleHookPermissionCheck:         skipLifecycleHookPermissionCheck,
+			ReplaceMachinesWithExpiredBootstrapToken: replaceMachinesWithExpiredBootstrapToken,
+			BootstrapTokenTTL:                        bootstrapTokenTTL,
+			ASGDeletionTimeout:                       machinePoolDeletionTimeout,
+			Backoff:                                  backoffTracker,
 		}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: instanceStateConcurrency, RecoverPanic: ptr.To[bool](true)}); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "AWSMachinePool")
 			os.Exit(1)
@@ -332,15 +417,28 @@ func setupReconcilersAndWebhooks(ctx context.Context, mgr ctrl.Manager, awsServi
 		}
 	}
 
-	if feature.Gates.Enabled(feature.EventBridgeInstanceState) {
-		setupLog.Info("EventBridge notifications enabled. enabling AWSInstanceStateController")
-		if err := (&instancestate.AwsInstanceStateReconciler{
+	// The clusters which configure EventBridge enable it regardless of the feature flag, the controller
+	// only watches the queues of the clusters for which it is enabled.
+	if err := (&instancestate.AwsInstanceStateReconciler{
+		Client:           mgr.GetClient(),
+		Log:              ctrl.Log.WithName("controllers").WithName("AWSInstanceStateController"),
+		Endpoints:        awsServiceEndpoints,
+		WatchFilterValue: watchFilterValue,
+	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: instanceStateConcurrency, RecoverPanic: ptr.To[bool](true)}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AWSInstanceStateController")
+		os.Exit(1)
+	}
+
+	if feature.Gates.Enabled(feature.MachinePool) && feature.Gates.Enabled(feature.SpotInterruptionHandling) {
+		setupLog.Info("Spot interruption handling enabled. enabling AWSSpotInterruptionController")
+		if err := (&spotinterruption.AWSSpotInterruptionReconciler{
 			Client:           mgr.GetClient(),
-			Log:              ctrl.Log.WithName("controllers").WithName("AWSInstanceStateController"),
+			Log:              ctrl.Log.WithName("controllers").WithName("AWSSpotInterruptionController"),
+			Recorder:         mgr.GetEventRecorderFor("awsspotinterruption-controller"),
 			Endpoints:        awsServiceEndpoints,
 			WatchFilterValue: watchFilterValue,
 		}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: instanceStateConcurrency, RecoverPanic: ptr.To[bool](true)}); err != nil {
-			setupLog.Error(err, "unable to create controller", "controller", "AWSInstanceStateController")
+			setupLog.Error(err, "unable to create controller", "controller", "AWSSpotInterruptionController")
 			os.Exit(1)
 		}
 	}
@@ -567,6 +665,84 @@ func initFlags(fs *pflag.FlagSet) {
 		"Set custom AWS service endpoins in semi-colon separated format: ${SigningRegion1}:${ServiceID1}=${URL},${ServiceID2}=${URL};${SigningRegion2}...",
 	)
 
+	fs.StringVar(&caBundleFile,
+		"aws-ca-bundle",
+		"",
+		"Path to a PEM encoded bundle of CA certificates the AWS API endpoints are verified with in addition to the system CAs, e.g. when the AWS API traffic goes through a TLS-intercepting proxy. Identities with a caBundleSecretRef use their own CA bundle instead.",
+	)
+
+	fs.BoolVar(&skipLifecycleHookPermissionCheck,
+		"skip-lifecycle-hook-permission-check",
+		false,
+		"Skip the verification of the lifecycle hook permissions of the controller with iam:SimulatePrincipalPolicy, for roles that are not allowed to simulate their own policies.",
+	)
+
+	fs.BoolVar(&allowOrphanCleanup,
+		"allow-orphan-cleanup",
+		false,
+		fmt.Sprintf("Allow the deletion of the orphaned resources of the AWSClusters annotated with %s=%s.", infrav1.ScanOrphansAnnotation, infrav1.CleanOrphansValue),
+	)
+
+	fs.DurationVar(&backoffInitialDelay,
+		"backoff-initial-delay",
+		backoff.DefaultInitialDelay,
+		"The delay before retrying the reconciliation of an AWSCluster, AWSMachine or AWSMachinePool after it failed. It doubles with each consecutive failure with the same class of error (throttle, auth, capacity, other), and restarts when the class changes. Disabled by default, the failed reconciliations being retried with the rate limiter of the controllers. A delay of 5s is a good start when enabling it.",
+	)
+
+	fs.DurationVar(&backoffMaxDelay,
+		"backoff-max-delay",
+		backoff.DefaultMaxDelay,
+		"The maximum delay before retrying a failed reconciliation.",
+	)
+
+	fs.Float64Var(&backoffJitter,
+		"backoff-jitter",
+		backoff.DefaultJitter,
+		"The fraction, between 0 and 1, by which the delays before retrying failed reconciliations are randomly reduced.",
+	)
+
+	fs.StringVar(&auditLogPath,
+		"audit-log",
+		"",
+		fmt.Sprintf("Path of a file the mutating AWS requests made for the clusters are appended to as JSON lines, or %q for the standard output. The file is reopened when it is moved or removed, e.g. by logrotate. Disabled when empty.", audit.StdoutPath),
+	)
+
+	fs.Float64Var(&auditLogParametersSampleRate,
+		"audit-log-parameters-sample-rate",
+		0,
+		"The fraction, between 0 and 1, of the records of the audit log which include the parameters of the request. The sensitive parameters, e.g. the user data, are always redacted.",
+	)
+
+	fs.IntVar(&launchTemplateVersionsToRetain,
+		"launch-template-versions-to-retain",
+		ec2service.DefaultLaunchTemplateVersionsToRetain,
+		"The number of the most recent versions of the launch templates of the machine pools kept when a new version is created, in addition to the default version. The older versions are deleted, a bounded number at a time.",
+	)
+
+	fs.DurationVar(&forceNetworkCleanupMinAge,
+		"force-network-cleanup-min-age",
+		eniservice.DefaultForceCleanupMinAge,
+		fmt.Sprintf("How long the deletion of a cluster annotated with %s=true must have lasted, and a network interface blocking the deletion of its security groups or subnets must have been attached, before the network interface is detached and deleted by force.", infrav1.ForceNetworkCleanupAnnotation),
+	)
+
+	fs.BoolVar(&replaceMachinesWithExpiredBootstrapToken,
+		"replace-machines-with-expired-bootstrap-token",
+		false,
+		"Terminate the instances of AWSMachines still running without a node once their bootstrap token expired, so that the machines are replaced with fresh bootstrap data, and create a new version of the launch template of the AWSMachinePools whose bootstrap token expired with their current bootstrap data.",
+	)
+
+	fs.DurationVar(&bootstrapTokenTTL,
+		"bootstrap-token-ttl",
+		scope.DefaultBootstrapTokenTTL,
+		fmt.Sprintf("The TTL of the kubeadm bootstrap tokens, used to estimate when the bootstrap token of a machine expired once it was deleted from the workload cluster. The expiry is read from the %s annotation of the bootstrap data secret when it is set, and otherwise from the secret of the bootstrap token in the workload cluster.", infrav1.BootstrapTokenExpiresAtAnnotation),
+	)
+
+	fs.DurationVar(&machinePoolDeletionTimeout,
+		"machinepool-deletion-timeout",
+		expcontrollers.DefaultASGDeletionTimeout,
+		"How long each stage of the deletion of the Auto Scaling group of an AWSMachinePool may take, i.e. scaling it to zero and deleting it, before it is deleted with ForceDelete. AWSMachinePools can override it with spec.deletionTimeout.",
+	)
+
 	fs.StringVar(
 		&watchFilterValue,
 		"watch-filter",
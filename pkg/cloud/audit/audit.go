@@ -0,0 +1,453 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit writes an audit log of the mutating AWS requests made for the clusters, as JSON lines.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// SchemaVersion is the version of the schema of the records. Fields are only ever added to a version of
+// the schema, a field is removed or its meaning changed in a new version only.
+const SchemaVersion = "v1"
+
+// StdoutPath is the path of the audit log to write the records to the standard output.
+const StdoutPath = "-"
+
+// redacted replaces the values of the sensitive parameters of the requests.
+const redacted = "REDACTED"
+
+// maxResourceIDs is the maximum number of resource IDs of a record.
+const maxResourceIDs = 100
+
+// readOnlyOperationPrefixes are the prefixes of the names of the operations which don't mutate resources.
+var readOnlyOperationPrefixes = []string{"Describe", "Get", "Head", "List", "Lookup", "Receive", "Search", "Simulate", "Validate"}
+
+// redactedParameters are the parameters of the requests whose values are never written to the audit
+// log, in lower case.
+var redactedParameters = map[string]bool{
+	"userdata":        true,
+	"secretstring":    true,
+	"secretbinary":    true,
+	"password":        true,
+	"privatekey":      true,
+	"secretaccesskey": true,
+	"sessiontoken":    true,
+	"body":            true,
+}
+
+// ignoredResourceIDs are the fields ending with Id which don't identify a resource acted on.
+var ignoredResourceIDs = map[string]bool{
+	"OwnerId":     true,
+	"RequesterId": true,
+	"RequestId":   true,
+	"ClientToken": true,
+}
+
+// Record is a line of the audit log, describing a mutating AWS request.
+type Record struct {
+	// SchemaVersion is the version of the schema of the record.
+	SchemaVersion string `json:"schemaVersion"`
+
+	// Time is the time the request completed.
+	Time time.Time `json:"time"`
+
+	// Controller is the name of the controller which made the request.
+	Controller string `json:"controller"`
+
+	// Service is the name of the AWS service, e.g. ec2.
+	Service string `json:"service"`
+
+	// Region is the AWS region of the request.
+	Region string `json:"region,omitempty"`
+
+	// Operation is the name of the operation, e.g. RunInstances.
+	Operation string `json:"operation"`
+
+	// RequestID is the ID AWS assigned to the request.
+	RequestID string `json:"requestID,omitempty"`
+
+	// StatusCode is the HTTP status code of the response, 0 if no response was received.
+	StatusCode int `json:"statusCode"`
+
+	// ErrorCode is the code of the error of the request, empty if the request succeeded.
+	ErrorCode string `json:"errorCode,omitempty"`
+
+	// ResourceIDs are the IDs and ARNs of the resources found in the response, or in the parameters of
+	// the request when the response has none, e.g. for a deletion.
+	ResourceIDs []string `json:"resourceIDs,omitempty"`
+
+	// Cluster is the name of the Cluster the request was made for.
+	Cluster string `json:"cluster,omitempty"`
+
+	// Namespace is the namespace of the Cluster and of the object.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Object is the object whose reconciliation made the request.
+	Object ObjectReference `json:"object"`
+
+	// AssumedRoleARN is the ARN of the role assumed to make the request, empty when the request was made
+	// with the credentials of the controller or static credentials.
+	AssumedRoleARN string `json:"assumedRoleARN,omitempty"`
+
+	// Parameters are the parameters of the request, with the sensitive ones redacted. They are only set
+	// for the sampled records.
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// ObjectReference identifies the generation of an object.
+type ObjectReference struct {
+	// Kind is the kind of the object.
+	Kind string `json:"kind"`
+
+	// Name is the name of the object.
+	Name string `json:"name"`
+
+	// Generation is the generation of the spec of the object.
+	Generation int64 `json:"generation"`
+}
+
+// Config configures the audit log.
+type Config struct {
+	// Path is the path of the file the records are appended to, or StdoutPath.
+	Path string
+
+	// ParametersSampleRate is the fraction, between 0 and 1, of the records which include the redacted
+	// parameters of the request.
+	ParametersSampleRate float64
+}
+
+// Logger writes the records of the audit log.
+type Logger struct {
+	mu                   sync.Mutex
+	out                  io.Writer
+	parametersSampleRate float64
+	random               func() float64
+	now                  func() time.Time
+}
+
+// New returns a Logger writing to the path of the configuration.
+func New(cfg Config) (*Logger, error) {
+	if cfg.ParametersSampleRate < 0 || cfg.ParametersSampleRate > 1 {
+		return nil, errors.Errorf("parameters sample rate %v must be between 0 and 1", cfg.ParametersSampleRate)
+	}
+
+	var out io.Writer = os.Stdout
+	if cfg.Path != StdoutPath {
+		f, err := openFile(cfg.Path)
+		if err != nil {
+			return nil, err
+		}
+		out = f
+	}
+	return newLogger(out, cfg.ParametersSampleRate), nil
+}
+
+func newLogger(out io.Writer, parametersSampleRate float64) *Logger {
+	return &Logger{
+		out:                  out,
+		parametersSampleRate: parametersSampleRate,
+		random:               rand.Float64, //nolint:gosec // The sampling doesn't need a cryptographically secure random number.
+		now:                  time.Now,
+	}
+}
+
+// Write appends a record to the audit log.
+func (l *Logger) Write(record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode audit record")
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// The record is written at once, so that the lines of the concurrent requests aren't interleaved.
+	if _, err := l.out.Write(data); err != nil {
+		return errors.Wrap(err, "failed to write audit record")
+	}
+	return nil
+}
+
+// Close closes the file of the audit log.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if c, ok := l.out.(io.Closer); ok && l.out != os.Stdout {
+		return c.Close()
+	}
+	return nil
+}
+
+var defaultLogger atomic.Pointer[Logger]
+
+// SetDefaultLogger sets the Logger the requests are recorded with. The requests aren't recorded when
+// it is nil, which is the default.
+func SetDefaultLogger(l *Logger) {
+	defaultLogger.Store(l)
+}
+
+type assumedRoleARNKey struct{}
+
+// AssumedRoleARNHandler returns a handler of the SDK recording the ARN of the role assumed by a session
+// in the context of its requests.
+func AssumedRoleARNHandler(roleARN string) request.NamedHandler {
+	return request.NamedHandler{
+		Name: "capa/audit-assumed-role",
+		Fn: func(r *request.Request) {
+			r.SetContext(context.WithValue(r.Context(), assumedRoleARNKey{}, roleARN))
+		},
+	}
+}
+
+// IsMutatingOperation returns whether an operation mutates resources, based on its name.
+func IsMutatingOperation(operation string) bool {
+	for _, prefix := range readOnlyOperationPrefixes {
+		if strings.HasPrefix(operation, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// RecordRequest returns a handler of the SDK writing the mutating requests of a client to the default
+// Logger, on behalf of the controller and for the object the client was created for.
+func RecordRequest(controller string, target runtime.Object) func(r *request.Request) {
+	return func(r *request.Request) {
+		l := defaultLogger.Load()
+		if l == nil || r.Operation == nil || !IsMutatingOperation(r.Operation.Name) {
+			return
+		}
+		if err := l.Write(l.newRecord(controller, target, r)); err != nil {
+			ctrl.Log.WithName("audit").Error(err, "failed to record AWS request", "operation", r.Operation.Name)
+		}
+	}
+}
+
+func (l *Logger) newRecord(controller string, target runtime.Object, r *request.Request) *Record {
+	record := &Record{
+		SchemaVersion: SchemaVersion,
+		Time:          l.now().UTC(),
+		Controller:    controller,
+		Service:       r.ClientInfo.ServiceName,
+		Region:        aws.StringValue(r.Config.Region),
+		Operation:     r.Operation.Name,
+		RequestID:     r.RequestID,
+		StatusCode:    statusCode(r.HTTPResponse),
+	}
+	if r.Error != nil {
+		var ok bool
+		if record.ErrorCode, ok = awserrors.Code(r.Error); !ok {
+			record.ErrorCode = "internal"
+		}
+	} else {
+		record.ResourceIDs = resourceIDs(r.Data)
+	}
+	if len(record.ResourceIDs) == 0 {
+		record.ResourceIDs = resourceIDs(r.Params)
+	}
+	if roleARN, ok := r.Context().Value(assumedRoleARNKey{}).(string); ok {
+		record.AssumedRoleARN = roleARN
+	}
+	if target != nil {
+		setObject(record, target)
+	}
+	if l.parametersSampleRate > 0 && l.random() < l.parametersSampleRate {
+		record.Parameters = redactedParams(r.Params)
+	}
+	return record
+}
+
+// setObject sets the object and the cluster of a record. The cluster is the one of the cluster name
+// label of the object, or else the Cluster owning it.
+func setObject(record *Record, target runtime.Object) {
+	obj, err := meta.Accessor(target)
+	if err != nil {
+		return
+	}
+	kind := target.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		// The type meta of typed objects is usually not set.
+		if t := reflect.TypeOf(target); t.Kind() == reflect.Ptr {
+			kind = t.Elem().Name()
+		}
+	}
+	record.Object = ObjectReference{Kind: kind, Name: obj.GetName(), Generation: obj.GetGeneration()}
+	record.Namespace = obj.GetNamespace()
+
+	record.Cluster = obj.GetLabels()[clusterv1.ClusterNameLabel]
+	if record.Cluster == "" {
+		for _, ref := range obj.GetOwnerReferences() {
+			if ref.Kind == "Cluster" && strings.HasPrefix(ref.APIVersion, clusterv1.GroupVersion.Group+"/") {
+				record.Cluster = ref.Name
+				break
+			}
+		}
+	}
+}
+
+// resourceIDs returns the values of the fields of the input or output of a request which end with Id,
+// Ids, Arn or Arns, deduplicated and sorted.
+func resourceIDs(v interface{}) []string {
+	values, ok := toJSONValue(v).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	ids := map[string]bool{}
+	collectResourceIDs(values, ids)
+
+	res := make([]string, 0, len(ids))
+	for id := range ids {
+		res = append(res, id)
+	}
+	sort.Strings(res)
+	if len(res) > maxResourceIDs {
+		res = res[:maxResourceIDs]
+	}
+	return res
+}
+
+func collectResourceIDs(v interface{}, ids map[string]bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isResourceIDField(key) && !ignoredResourceIDs[key] {
+				switch value := value.(type) {
+				case string:
+					if value != "" {
+						ids[value] = true
+					}
+					continue
+				case []interface{}:
+					for _, item := range value {
+						if s, ok := item.(string); ok && s != "" {
+							ids[s] = true
+						}
+					}
+					continue
+				}
+			}
+			collectResourceIDs(value, ids)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectResourceIDs(item, ids)
+		}
+	}
+}
+
+func isResourceIDField(name string) bool {
+	for _, suffix := range []string{"Id", "Ids", "Arn", "Arns", "ARN", "ARNs"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactedParams returns the parameters of a request, with the values of the sensitive ones redacted.
+func redactedParams(params interface{}) map[string]interface{} {
+	values, ok := toJSONValue(params).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	redact(values)
+	return values
+}
+
+func redact(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if redactedParameters[strings.ToLower(key)] {
+				v[key] = redacted
+				continue
+			}
+			redact(value)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redact(item)
+		}
+	}
+}
+
+// toJSONValue returns the generic JSON representation of the input or output of a request, nil if it
+// can't be encoded, e.g. when it contains a stream.
+func toJSONValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var res interface{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil
+	}
+	pruneNulls(res)
+	return res
+}
+
+// pruneNulls removes the fields which aren't set, as the inputs and outputs of the SDK have a pointer
+// for each field.
+func pruneNulls(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if value == nil {
+				delete(v, key)
+				continue
+			}
+			pruneNulls(value)
+		}
+	case []interface{}:
+		for _, item := range v {
+			pruneNulls(item)
+		}
+	}
+}
+
+// statusCode returns the status code of a response, 0 if there is none.
+func statusCode(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
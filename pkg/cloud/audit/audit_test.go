@@ -0,0 +1,224 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestRecordRequest(t *testing.T) {
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-aws-cluster",
+			Namespace:  "default",
+			Generation: 3,
+			Labels:     map[string]string{clusterv1.ClusterNameLabel: "test-cluster"},
+		},
+	}
+	runInstances := func() *request.Request {
+		r := newRequest("RunInstances",
+			&ec2.RunInstancesInput{ImageId: aws.String("ami-1"), SubnetId: aws.String("subnet-1"), UserData: aws.String("c2VjcmV0")},
+			&ec2.Reservation{OwnerId: aws.String("123456789012"), Instances: []*ec2.Instance{{InstanceId: aws.String("i-1")}, {InstanceId: aws.String("i-2")}}})
+		AssumedRoleARNHandler("arn:aws:iam::123456789012:role/capa").Fn(r)
+		return r
+	}
+
+	tests := []struct {
+		name       string
+		request    func() *request.Request
+		target     *infrav1.AWSCluster
+		sampleRate float64
+		want       []map[string]interface{}
+	}{
+		{
+			name:       "records a mutating request",
+			request:    runInstances,
+			target:     awsCluster,
+			sampleRate: 1,
+			want: []map[string]interface{}{{
+				"schemaVersion":  "v1",
+				"time":           "2024-05-02T10:00:00Z",
+				"controller":     "awscluster",
+				"service":        "ec2",
+				"region":         "us-east-1",
+				"operation":      "RunInstances",
+				"requestID":      "request-1",
+				"statusCode":     float64(200),
+				"resourceIDs":    []interface{}{"i-1", "i-2"},
+				"cluster":        "test-cluster",
+				"namespace":      "default",
+				"object":         map[string]interface{}{"kind": "AWSCluster", "name": "test-aws-cluster", "generation": float64(3)},
+				"assumedRoleARN": "arn:aws:iam::123456789012:role/capa",
+				"parameters":     map[string]interface{}{"ImageId": "ami-1", "SubnetId": "subnet-1", "UserData": "REDACTED"},
+			}},
+		},
+		{
+			name:    "doesn't record the parameters of the requests which aren't sampled",
+			request: runInstances,
+			target:  awsCluster,
+			want: []map[string]interface{}{{
+				"schemaVersion":  "v1",
+				"time":           "2024-05-02T10:00:00Z",
+				"controller":     "awscluster",
+				"service":        "ec2",
+				"region":         "us-east-1",
+				"operation":      "RunInstances",
+				"requestID":      "request-1",
+				"statusCode":     float64(200),
+				"resourceIDs":    []interface{}{"i-1", "i-2"},
+				"cluster":        "test-cluster",
+				"namespace":      "default",
+				"object":         map[string]interface{}{"kind": "AWSCluster", "name": "test-aws-cluster", "generation": float64(3)},
+				"assumedRoleARN": "arn:aws:iam::123456789012:role/capa",
+			}},
+		},
+		{
+			name: "records a failed request with the resource IDs of its parameters",
+			request: func() *request.Request {
+				r := newRequest("DeleteSubnet", &ec2.DeleteSubnetInput{SubnetId: aws.String("subnet-1")}, &ec2.DeleteSubnetOutput{})
+				r.HTTPResponse.StatusCode = 400
+				r.Error = awserr.New("DependencyViolation", "the subnet has dependencies", nil)
+				return r
+			},
+			target: &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "test-aws-cluster",
+					Namespace:       "default",
+					Generation:      1,
+					OwnerReferences: []metav1.OwnerReference{{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "owner-cluster"}},
+				},
+			},
+			want: []map[string]interface{}{{
+				"schemaVersion": "v1",
+				"time":          "2024-05-02T10:00:00Z",
+				"controller":    "awscluster",
+				"service":       "ec2",
+				"region":        "us-east-1",
+				"operation":     "DeleteSubnet",
+				"requestID":     "request-1",
+				"statusCode":    float64(400),
+				"errorCode":     "DependencyViolation",
+				"resourceIDs":   []interface{}{"subnet-1"},
+				"cluster":       "owner-cluster",
+				"namespace":     "default",
+				"object":        map[string]interface{}{"kind": "AWSCluster", "name": "test-aws-cluster", "generation": float64(1)},
+			}},
+		},
+		{
+			name: "doesn't record a read-only request",
+			request: func() *request.Request {
+				return newRequest("DescribeInstances", &ec2.DescribeInstancesInput{}, &ec2.DescribeInstancesOutput{})
+			},
+			target: awsCluster,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var out bytes.Buffer
+			l := newLogger(&out, tt.sampleRate)
+			l.random = func() float64 { return 0.5 }
+			l.now = func() time.Time { return time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC) }
+			SetDefaultLogger(l)
+			defer SetDefaultLogger(nil)
+
+			RecordRequest("awscluster", tt.target)(tt.request())
+			g.Expect(readRecords(g, &out)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestRecordRequestWithoutLogger(t *testing.T) {
+	g := NewWithT(t)
+
+	r := newRequest("TerminateInstances", &ec2.TerminateInstancesInput{InstanceIds: aws.StringSlice([]string{"i-1"})}, &ec2.TerminateInstancesOutput{})
+	g.Expect(func() { RecordRequest("awsmachine", nil)(r) }).NotTo(Panic())
+}
+
+func TestNew(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := New(Config{Path: StdoutPath, ParametersSampleRate: 1.5})
+	g.Expect(err).To(MatchError(ContainSubstring("must be between 0 and 1")))
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := New(Config{Path: path})
+	g.Expect(err).NotTo(HaveOccurred())
+	defer l.Close()
+
+	// The audit log is reopened when it is rotated.
+	g.Expect(l.Write(&Record{SchemaVersion: SchemaVersion, Operation: "CreateVpc"})).To(Succeed())
+	g.Expect(os.Rename(path, path+".1")).To(Succeed())
+	g.Expect(l.Write(&Record{SchemaVersion: SchemaVersion, Operation: "DeleteVpc"})).To(Succeed())
+
+	rotated, err := os.ReadFile(path + ".1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(readRecords(g, bytes.NewBuffer(rotated))).To(ConsistOf(HaveKeyWithValue("operation", "CreateVpc")))
+	current, err := os.ReadFile(path)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(readRecords(g, bytes.NewBuffer(current))).To(ConsistOf(HaveKeyWithValue("operation", "DeleteVpc")))
+}
+
+func TestIsMutatingOperation(t *testing.T) {
+	g := NewWithT(t)
+
+	for _, operation := range []string{"RunInstances", "CreateLoadBalancer", "DeleteSubnet", "ModifyInstanceAttribute", "RegisterTargets", "SendMessage"} {
+		g.Expect(IsMutatingOperation(operation)).To(BeTrue(), operation)
+	}
+	for _, operation := range []string{"DescribeInstances", "GetParameter", "ListTagsForResource", "HeadBucket", "ReceiveMessage", "SimulatePrincipalPolicy"} {
+		g.Expect(IsMutatingOperation(operation)).To(BeFalse(), operation)
+	}
+}
+
+func newRequest(operation string, params, data interface{}) *request.Request {
+	r := request.New(aws.Config{Region: aws.String("us-east-1")}, metadata.ClientInfo{ServiceName: "ec2", Endpoint: "https://ec2.us-east-1.amazonaws.com"},
+		request.Handlers{}, nil, &request.Operation{Name: operation}, params, data)
+	r.RequestID = "request-1"
+	r.HTTPResponse = &http.Response{StatusCode: 200}
+	return r
+}
+
+// readRecords decodes the records of an audit log as generic JSON, to check the schema of the records.
+func readRecords(g *WithT, out *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		record := map[string]interface{}{}
+		g.Expect(json.Unmarshal(scanner.Bytes(), &record)).To(Succeed())
+		records = append(records, record)
+	}
+	return records
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// file appends to the file of the audit log, which it reopens when it was moved or removed, e.g. by
+// logrotate, so that the audit log can be rotated without restarting the controller. A file truncated
+// in place keeps being appended to.
+type file struct {
+	path string
+	f    *os.File
+}
+
+func openFile(path string) (*file, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open audit log %s", path)
+	}
+	return &file{path: path, f: f}, nil
+}
+
+func (w *file) Write(p []byte) (int, error) {
+	if err := w.reopenIfRotated(); err != nil {
+		return 0, err
+	}
+	return w.f.Write(p)
+}
+
+func (w *file) Close() error {
+	return w.f.Close()
+}
+
+func (w *file) reopenIfRotated() error {
+	current, err := w.f.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "failed to stat audit log %s", w.path)
+	}
+	info, err := os.Stat(w.path)
+	if err == nil && os.SameFile(current, info) {
+		return nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to stat audit log %s", w.path)
+	}
+
+	f, err := openFile(w.path)
+	if err != nil {
+		return err
+	}
+	_ = w.f.Close()
+	w.f = f.f
+	return nil
+}
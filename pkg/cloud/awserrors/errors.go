@@ -26,8 +26,11 @@ import (
 
 // Error singletons for AWS errors.
 const (
+	AccessDenied                      = "AccessDenied"
+	AllocationIDNotFound              = "InvalidAllocationID.NotFound"
 	AssociationIDNotFound             = "InvalidAssociationID.NotFound"
 	AuthFailure                       = "AuthFailure"
+	DependencyViolation               = "DependencyViolation"
 	BucketAlreadyOwnedByYou           = "BucketAlreadyOwnedByYou"
 	EIPNotFound                       = "InvalidElasticIpID.NotFound"
 	GatewayNotFound                   = "InvalidGatewayID.NotFound"
@@ -39,19 +42,25 @@ const (
 	InvalidClientTokenID              = "InvalidClientTokenId"
 	InvalidInstanceID                 = "InvalidInstanceID.NotFound"
 	InvalidSubnet                     = "InvalidSubnet"
+	KeyPairNotFound                   = "InvalidKeyPair.NotFound"
 	LaunchTemplateNameNotFound        = "InvalidLaunchTemplateName.NotFoundException"
+	LaunchTemplateIDNotFound          = "InvalidLaunchTemplateId.NotFound"
+	LimitExceeded                     = "LimitExceeded"
 	LoadBalancerNotFound              = "LoadBalancerNotFound"
 	NATGatewayNotFound                = "InvalidNatGatewayID.NotFound"
 	//nolint:gosec
 	NoCredentialProviders                   = "NoCredentialProviders"
 	NoSuchKey                               = "NoSuchKey"
 	PermissionNotFound                      = "InvalidPermission.NotFound"
+	ResourceAlreadyAssociated               = "Resource.AlreadyAssociated"
 	ResourceExists                          = "ResourceExistsException"
 	ResourceNotFound                        = "InvalidResourceID.NotFound"
 	RouteTableNotFound                      = "InvalidRouteTableID.NotFound"
+	RulesPerSecurityGroupLimitExceeded      = "RulesPerSecurityGroupLimitExceeded"
 	SubnetNotFound                          = "InvalidSubnetID.NotFound"
 	UnrecognizedClientException             = "UnrecognizedClientException"
 	UnauthorizedOperation                   = "UnauthorizedOperation"
+	ValidationError                         = "ValidationError"
 	VPCNotFound                             = "InvalidVpcID.NotFound"
 	VPCMissingParameter                     = "MissingParameter"
 	ErrCodeRepositoryAlreadyExistsException = "RepositoryAlreadyExistsException"
@@ -171,6 +180,8 @@ func IsInvalidNotFoundError(err error) bool {
 			return true
 		case LaunchTemplateNameNotFound:
 			return true
+		case LaunchTemplateIDNotFound:
+			return true
 		}
 	}
 
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backoff computes the delay before the reconciliation of an object is retried after it
+// failed, growing exponentially with the consecutive failures of the object.
+package backoff
+
+import (
+	"math/rand"
+	"reflect"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/metrics"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/logger"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// DefaultInitialDelay is the default delay after the first failure. It disables the backoff, so
+	// that the failed reconciliations are retried with the rate limiter of the controllers.
+	DefaultInitialDelay = time.Duration(0)
+
+	// DefaultMaxDelay is the default maximum delay.
+	DefaultMaxDelay = 10 * time.Minute
+
+	// DefaultJitter is the default fraction by which the delays are randomly reduced.
+	DefaultJitter = 0.2
+)
+
+// Config configures the delays before retrying a failed reconciliation.
+type Config struct {
+	// InitialDelay is the delay after the first failure. It doubles with each consecutive failure
+	// of the same class, and restarts from the initial delay when the class changes.
+	InitialDelay time.Duration
+
+	// MaxDelay is the maximum delay.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction, between 0 and 1, by which the delays are randomly reduced, so that
+	// objects failing together are not retried together.
+	Jitter float64
+}
+
+// Backoff describes the retry of a failed reconciliation.
+type Backoff struct {
+	// Class is the class of the error.
+	Class ErrorClass
+
+	// Failures is the number of consecutive failures with errors of the class.
+	Failures int
+
+	// Delay is the delay before the reconciliation is retried.
+	Delay time.Duration
+}
+
+type failures struct {
+	class ErrorClass
+	count int
+}
+
+// Tracker tracks the consecutive failures of the reconciliations of objects, by UID. It is safe for
+// concurrent use, so that it can be shared by controllers.
+type Tracker struct {
+	config Config
+
+	mu       sync.Mutex
+	failures map[types.UID]failures
+
+	// random returns a number in [0, 1). It is replaced in tests.
+	random func() float64
+}
+
+// NewTracker returns a Tracker computing the delays according to config.
+func NewTracker(config Config) *Tracker {
+	return &Tracker{
+		config:   config,
+		failures: map[types.UID]failures{},
+		random:   rand.Float64, //nolint:gosec // The jitter doesn't need a cryptographically secure random number.
+	}
+}
+
+// Failure records a failed reconciliation of the object and returns the delay before it is retried.
+// The failures are counted from zero again when the class of the error differs from the previous one.
+func (t *Tracker) Failure(uid types.UID, err error) Backoff {
+	class := Classify(err)
+
+	t.mu.Lock()
+	f := t.failures[uid]
+	if f.class != class {
+		f = failures{class: class}
+	}
+	f.count++
+	t.failures[uid] = f
+	t.mu.Unlock()
+
+	return Backoff{
+		Class:    class,
+		Failures: f.count,
+		Delay:    t.delay(f.count),
+	}
+}
+
+// Reset forgets the failures of the object, after it was reconciled successfully or deleted.
+func (t *Tracker) Reset(uid types.UID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.failures, uid)
+}
+
+// Result returns the result of a reconciliation of the object. A failed reconciliation is retried
+// after the delay of its backoff, which is reported in the AWSRequestsSucceeded condition of the
+// object. The error is logged and counted in the capa_reconcile_errors_total metric instead of
+// returned, as controller-runtime ignores the result of failed reconciliations. A successful
+// reconciliation resets the backoff.
+func (t *Tracker) Result(log logger.Wrapper, obj conditions.Setter, result ctrl.Result, err error) (ctrl.Result, error) {
+	if err == nil {
+		t.Reset(obj.GetUID())
+		conditions.Delete(obj, infrav1.AWSRequestsSucceededCondition)
+		return result, nil
+	}
+
+	b := t.Failure(obj.GetUID(), err)
+	metrics.RecordReconcileError(reflect.Indirect(reflect.ValueOf(obj)).Type().Name(), string(b.Class))
+	log.Error(err, "Reconciliation failed, backing off", "errorClass", b.Class, "failures", b.Failures, "requeueAfter", b.Delay)
+	conditions.MarkFalse(obj, infrav1.AWSRequestsSucceededCondition, infrav1.BackingOffReason, clusterv1.ConditionSeverityWarning,
+		"Retrying in %s after %d consecutive %s errors: %v", b.Delay.Round(time.Second), b.Failures, b.Class, err)
+
+	return ctrl.Result{RequeueAfter: b.Delay}, nil
+}
+
+// delay returns the delay after the given number of consecutive failures.
+func (t *Tracker) delay(failures int) time.Duration {
+	d := t.config.InitialDelay
+	for i := 1; i < failures && d < t.config.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, t.config.MaxDelay)
+	return d - time.Duration(t.config.Jitter*t.random()*float64(d))
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/logger"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func newTestTracker(random float64) *Tracker {
+	t := NewTracker(Config{
+		InitialDelay: 5 * time.Second,
+		MaxDelay:     time.Minute,
+		Jitter:       0.2,
+	})
+	t.random = func() float64 { return random }
+	return t
+}
+
+func TestTrackerFailure(t *testing.T) {
+	g := NewWithT(t)
+	tracker := newTestTracker(0)
+	authErr := errors.Wrap(awserr.New(awserrors.UnauthorizedOperation, "", nil), "failed to describe VPC")
+
+	var delays []time.Duration
+	for i := 0; i < 6; i++ {
+		delays = append(delays, tracker.Failure("uid", authErr).Delay)
+	}
+	g.Expect(delays).To(Equal([]time.Duration{
+		5 * time.Second,
+		10 * time.Second,
+		20 * time.Second,
+		40 * time.Second,
+		time.Minute,
+		time.Minute,
+	}))
+
+	// The failures of another class or another object start from the initial delay.
+	g.Expect(tracker.Failure("uid", errors.New("some error"))).To(Equal(Backoff{Class: ErrorClassOther, Failures: 1, Delay: 5 * time.Second}))
+	g.Expect(tracker.Failure("other-uid", authErr)).To(Equal(Backoff{Class: ErrorClassAuth, Failures: 1, Delay: 5 * time.Second}))
+
+	// The failures of the previous class are forgotten when the class changes.
+	g.Expect(tracker.Failure("uid", authErr)).To(Equal(Backoff{Class: ErrorClassAuth, Failures: 1, Delay: 5 * time.Second}))
+
+	tracker.Reset("uid")
+	g.Expect(tracker.Failure("uid", authErr)).To(Equal(Backoff{Class: ErrorClassAuth, Failures: 1, Delay: 5 * time.Second}))
+	g.Expect(tracker.Failure("other-uid", authErr)).To(Equal(Backoff{Class: ErrorClassAuth, Failures: 2, Delay: 10 * time.Second}))
+}
+
+func TestTrackerFailureJitter(t *testing.T) {
+	g := NewWithT(t)
+	tracker := newTestTracker(0.5)
+
+	g.Expect(tracker.Failure("uid", errors.New("some error")).Delay).To(Equal(4500 * time.Millisecond))
+	for i := 0; i < 10; i++ {
+		tracker.Failure("uid", errors.New("some error"))
+	}
+	// The jitter reduces the delay, so that it never exceeds the maximum delay.
+	g.Expect(tracker.Failure("uid", errors.New("some error")).Delay).To(Equal(54 * time.Second))
+}
+
+func TestTrackerResult(t *testing.T) {
+	g := NewWithT(t)
+	tracker := newTestTracker(0)
+	log := logger.NewLogger(ctrl.Log)
+	awsCluster := &infrav1.AWSCluster{}
+	awsCluster.UID = "uid"
+	throttleErrors := reconcileErrors(g, "AWSCluster", string(ErrorClassThrottle))
+
+	res, err := tracker.Result(log, awsCluster, ctrl.Result{}, awserr.New("Throttling", "Rate exceeded", nil))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res).To(Equal(ctrl.Result{RequeueAfter: 5 * time.Second}))
+	res, err = tracker.Result(log, awsCluster, ctrl.Result{}, awserr.New("Throttling", "Rate exceeded", nil))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res).To(Equal(ctrl.Result{RequeueAfter: 10 * time.Second}))
+
+	condition := conditions.Get(awsCluster, infrav1.AWSRequestsSucceededCondition)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Status).To(BeEquivalentTo("False"))
+	g.Expect(condition.Reason).To(Equal(infrav1.BackingOffReason))
+	g.Expect(condition.Severity).To(Equal(clusterv1.ConditionSeverityWarning))
+	g.Expect(condition.Message).To(Equal("Retrying in 10s after 2 consecutive Throttle errors: Throttling: Rate exceeded"))
+	// The errors aren't returned to controller-runtime, they are counted by CAPA.
+	g.Expect(reconcileErrors(g, "AWSCluster", string(ErrorClassThrottle))).To(Equal(throttleErrors + 2))
+
+	res, err = tracker.Result(log, awsCluster, ctrl.Result{RequeueAfter: time.Minute}, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+	g.Expect(conditions.Has(awsCluster, infrav1.AWSRequestsSucceededCondition)).To(BeFalse())
+
+	res, _ = tracker.Result(log, awsCluster, ctrl.Result{}, awserr.New("Throttling", "Rate exceeded", nil))
+	g.Expect(res).To(Equal(ctrl.Result{RequeueAfter: 5 * time.Second}))
+}
+
+// reconcileErrors returns the number of failed reconciliations of the kind with errors of the class
+// counted in the capa_reconcile_errors_total metric.
+func reconcileErrors(g *WithT, kind, class string) float64 {
+	families, err := ctrlmetrics.Registry.Gather()
+	g.Expect(err).NotTo(HaveOccurred())
+	for _, family := range families {
+		if family.GetName() != "capa_reconcile_errors_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["kind"] == kind && labels["error_class"] == class {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestClassify(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected ErrorClass
+	}{
+		{
+			name:     "throttling",
+			err:      awserr.New("RequestLimitExceeded", "", nil),
+			expected: ErrorClassThrottle,
+		},
+		{
+			name:     "wrapped permission error",
+			err:      errors.Wrap(errors.Wrap(awserr.New(awserrors.AuthFailure, "", nil), "failed"), "failed again"),
+			expected: ErrorClassAuth,
+		},
+		{
+			name:     "insufficient capacity",
+			err:      awserr.New("InsufficientInstanceCapacity", "", nil),
+			expected: ErrorClassCapacity,
+		},
+		{
+			name:     "other AWS error",
+			err:      awserr.New(awserrors.VPCNotFound, "", nil),
+			expected: ErrorClassOther,
+		},
+		{
+			name:     "not an AWS error",
+			err:      errors.New("some error"),
+			expected: ErrorClassOther,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(Classify(tc.err)).To(Equal(tc.expected))
+		})
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backoff
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+)
+
+// ErrorClass is the class of an error. The backoff restarts when the class of the errors changes, so
+// that e.g. a throttled request doesn't retry with the delay reached by a persistent permission error.
+type ErrorClass string
+
+const (
+	// ErrorClassThrottle is the class of the errors returned when AWS throttles the requests.
+	ErrorClassThrottle = ErrorClass("Throttle")
+
+	// ErrorClassAuth is the class of the errors returned when the credentials are missing or
+	// invalid, or don't allow the request.
+	ErrorClassAuth = ErrorClass("Auth")
+
+	// ErrorClassCapacity is the class of the errors returned when AWS lacks capacity, or a quota of
+	// the account is reached.
+	ErrorClassCapacity = ErrorClass("Capacity")
+
+	// ErrorClassOther is the class of all other errors.
+	ErrorClassOther = ErrorClass("Other")
+)
+
+var authErrorCodes = map[string]bool{
+	awserrors.AccessDenied:                true,
+	"AccessDeniedException":               true,
+	awserrors.AuthFailure:                 true,
+	"ExpiredToken":                        true,
+	"ExpiredTokenException":               true,
+	awserrors.InvalidAccessKeyID:          true,
+	awserrors.InvalidClientTokenID:        true,
+	awserrors.NoCredentialProviders:       true,
+	awserrors.UnauthorizedOperation:       true,
+	awserrors.UnrecognizedClientException: true,
+}
+
+var capacityErrorCodes = map[string]bool{
+	"AddressLimitExceeded":              true,
+	"InsufficientAddressCapacity":       true,
+	"InsufficientFreeAddressesInSubnet": true,
+	"InsufficientInstanceCapacity":      true,
+	"InstanceLimitExceeded":             true,
+	awserrors.LimitExceeded:             true,
+	"LimitExceededException":            true,
+	"MaxSpotInstanceCountExceeded":      true,
+	"ServiceQuotaExceededException":     true,
+	"VcpuLimitExceeded":                 true,
+}
+
+// Classify returns the class of an error, looking for an AWS error in its chain.
+func Classify(err error) ErrorClass {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return ErrorClassOther
+	}
+
+	switch {
+	case request.IsErrorThrottle(awsErr):
+		return ErrorClassThrottle
+	case authErrorCodes[awsErr.Code()]:
+		return ErrorClassAuth
+	case capacityErrorCodes[awsErr.Code()]:
+		return ErrorClassCapacity
+	default:
+		return ErrorClassOther
+	}
+}
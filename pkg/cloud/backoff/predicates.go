@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backoff
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// ConditionUnchanged ignores the updates of an object which only change its AWSRequestsSucceeded
+// condition, so that reporting the backoff of a failed reconciliation doesn't retry it right away.
+// It must be set on every controller whose reconciliations are delayed by a Tracker.
+func ConditionUnchanged() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return true
+			}
+			oldObj, ok := e.ObjectOld.DeepCopyObject().(conditions.Setter)
+			if !ok {
+				return true
+			}
+			newObj, ok := e.ObjectNew.DeepCopyObject().(conditions.Setter)
+			if !ok {
+				return true
+			}
+
+			for _, obj := range []conditions.Setter{oldObj, newObj} {
+				conditions.Delete(obj, infrav1.AWSRequestsSucceededCondition)
+				obj.SetResourceVersion("")
+				obj.SetManagedFields(nil)
+			}
+
+			return !equality.Semantic.DeepEqual(oldObj, newObj)
+		},
+	}
+}
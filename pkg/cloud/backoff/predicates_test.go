@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backoff
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestConditionUnchanged(t *testing.T) {
+	oldMachine := &infrav1.AWSMachine{}
+	oldMachine.ResourceVersion = "1"
+
+	backingOff := oldMachine.DeepCopy()
+	backingOff.ResourceVersion = "2"
+	conditions.MarkFalse(backingOff, infrav1.AWSRequestsSucceededCondition, infrav1.BackingOffReason, clusterv1.ConditionSeverityWarning, "Retrying in 5s")
+
+	ready := backingOff.DeepCopy()
+	ready.ResourceVersion = "3"
+	conditions.MarkTrue(ready, infrav1.InstanceReadyCondition)
+
+	tests := []struct {
+		name     string
+		old, new *infrav1.AWSMachine
+		expected bool
+	}{
+		{
+			name:     "should ignore an update of the AWSRequestsSucceeded condition only",
+			old:      oldMachine,
+			new:      backingOff,
+			expected: false,
+		},
+		{
+			name:     "should not ignore an update of another condition",
+			old:      backingOff,
+			new:      ready,
+			expected: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(ConditionUnchanged().Update(event.UpdateEvent{ObjectOld: tc.old, ObjectNew: tc.new})).To(Equal(tc.expected))
+		})
+	}
+}
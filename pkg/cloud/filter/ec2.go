@@ -26,12 +26,15 @@ import (
 )
 
 const (
-	filterNameTagKey        = "tag-key"
-	filterNameVpcID         = "vpc-id"
-	filterNameState         = "state"
-	filterNameVpcAttachment = "attachment.vpc-id"
-	filterAvailabilityZone  = "availability-zone"
-	filterNameIPAMPoolID    = "ipam-pool-id"
+	filterNameTagKey              = "tag-key"
+	filterNameVpcID               = "vpc-id"
+	filterNameState               = "state"
+	filterNameVpcAttachment       = "attachment.vpc-id"
+	filterAvailabilityZone        = "availability-zone"
+	filterNameIPAMPoolID          = "ipam-pool-id"
+	filterNameAssociationSubnetID = "association.subnet-id"
+	filterNameIsDefault           = "isDefault"
+	filterNameDefaultForAZ        = "default-for-az"
 )
 
 // EC2 exposes the ec2 sdk related filters.
@@ -105,6 +108,22 @@ func (ec2Filters) VPC(vpcID string) *ec2.Filter {
 	}
 }
 
+// DefaultVPC returns a filter matching the default VPC of the region.
+func (ec2Filters) DefaultVPC() *ec2.Filter {
+	return &ec2.Filter{
+		Name:   aws.String(filterNameIsDefault),
+		Values: aws.StringSlice([]string{"true"}),
+	}
+}
+
+// DefaultForAZ returns a filter matching the default subnets of the availability zones.
+func (ec2Filters) DefaultForAZ() *ec2.Filter {
+	return &ec2.Filter{
+		Name:   aws.String(filterNameDefaultForAZ),
+		Values: aws.StringSlice([]string{"true"}),
+	}
+}
+
 // VPCAttachment returns a filter based on the vpc id attached to the resource.
 func (ec2Filters) VPCAttachment(vpcID string) *ec2.Filter {
 	return &ec2.Filter{
@@ -113,6 +132,14 @@ func (ec2Filters) VPCAttachment(vpcID string) *ec2.Filter {
 	}
 }
 
+// AssociationSubnet returns a filter based on the id of the subnet associated with the resource.
+func (ec2Filters) AssociationSubnet(subnetID string) *ec2.Filter {
+	return &ec2.Filter{
+		Name:   aws.String(filterNameAssociationSubnetID),
+		Values: aws.StringSlice([]string{subnetID}),
+	}
+}
+
 // Available returns a filter based on the state being available.
 func (ec2Filters) Available() *ec2.Filter {
 	return &ec2.Filter{
@@ -153,6 +180,14 @@ func (ec2Filters) SubnetStates(states ...string) *ec2.Filter {
 	}
 }
 
+// InstanceConnectEndpointStates returns a filter based on the list of states passed in.
+func (ec2Filters) InstanceConnectEndpointStates(states ...string) *ec2.Filter {
+	return &ec2.Filter{
+		Name:   aws.String("state"),
+		Values: aws.StringSlice(states),
+	}
+}
+
 func (ec2Filters) AvailabilityZone(zone string) *ec2.Filter {
 	return &ec2.Filter{
 		Name:   aws.String(filterAvailabilityZone),
@@ -173,3 +208,19 @@ func (ec2Filters) SecurityGroupName(name string) *ec2.Filter {
 		Values: aws.StringSlice([]string{name}),
 	}
 }
+
+// SecurityGroupID returns a filter based on the id of a security group the resource references.
+func (ec2Filters) SecurityGroupID(id string) *ec2.Filter {
+	return &ec2.Filter{
+		Name:   aws.String("group-id"),
+		Values: aws.StringSlice([]string{id}),
+	}
+}
+
+// SubnetID returns a filter based on the id of the subnet of the resource.
+func (ec2Filters) SubnetID(id string) *ec2.Filter {
+	return &ec2.Filter{
+		Name:   aws.String("subnet-id"),
+		Values: aws.StringSlice([]string{id}),
+	}
+}
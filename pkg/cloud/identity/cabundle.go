@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/pkg/errors"
+)
+
+// CABundleKey is the key of the PEM encoded CA bundle in the secret referenced by the CABundleSecretRef
+// of an identity.
+const CABundleKey = "ca.crt"
+
+// ParseCABundle parses a PEM encoded bundle of CA certificates. The bundle must hold at least one
+// certificate, and nothing but certificates.
+func ParseCABundle(caBundle []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := caBundle
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, errors.Errorf("CA bundle holds a %q PEM block, only certificates are allowed", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse certificate of CA bundle")
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("CA bundle holds no PEM encoded certificate")
+	}
+	return certs, nil
+}
+
+// NewHTTPClient returns an HTTP client which verifies the certificates of the AWS API endpoints with the
+// certificates of the CA bundle in addition to the system CAs. The proxy settings of the environment are
+// honoured like with the default client of the AWS SDK. It returns nil for an empty CA bundle, so that
+// the AWS SDK falls back to its default client.
+func NewHTTPClient(caBundle []byte) (*http.Client, error) {
+	if len(caBundle) == 0 {
+		return nil, nil
+	}
+	certs, err := ParseCABundle(caBundle)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    pool,
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// IsTLSVerificationError returns true if the error, or one of the errors it wraps, including the original
+// errors of AWS SDK errors, is a failure to verify the certificate of an endpoint.
+func IsTLSVerificationError(err error) bool {
+	for err != nil {
+		var unknownAuthorityErr x509.UnknownAuthorityError
+		var certificateInvalidErr x509.CertificateInvalidError
+		var hostnameErr x509.HostnameError
+		var verificationErr *tls.CertificateVerificationError
+		if errors.As(err, &unknownAuthorityErr) || errors.As(err, &certificateInvalidErr) ||
+			errors.As(err, &hostnameErr) || errors.As(err, &verificationErr) {
+			return true
+		}
+
+		var awsErr awserr.Error
+		if !errors.As(err, &awsErr) {
+			return false
+		}
+		err = awsErr.OrigErr()
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestParseCABundle(t *testing.T) {
+	caCert := newCACertificate(t)
+
+	tests := []struct {
+		name      string
+		caBundle  []byte
+		wantCerts int
+		wantErr   string
+	}{
+		{
+			name:      "bundle of certificates",
+			caBundle:  append(append([]byte{}, caCert...), caCert...),
+			wantCerts: 2,
+		},
+		{
+			name:    "empty bundle",
+			wantErr: "no PEM encoded certificate",
+		},
+		{
+			name:     "private key in the bundle",
+			caBundle: append(append([]byte{}, caCert...), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")})...),
+			wantErr:  `"PRIVATE KEY" PEM block`,
+		},
+		{
+			name:     "invalid certificate",
+			caBundle: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("certificate")}),
+			wantErr:  "failed to parse certificate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			certs, err := ParseCABundle(tt.caBundle)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(certs).To(HaveLen(tt.wantCerts))
+		})
+	}
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	g := NewWithT(t)
+
+	httpClient, err := NewHTTPClient(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(httpClient).To(BeNil())
+
+	httpClient, err = NewHTTPClient(newCACertificate(t))
+	g.Expect(err).NotTo(HaveOccurred())
+	transport, ok := httpClient.Transport.(*http.Transport)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(transport.TLSClientConfig.RootCAs).NotTo(BeNil())
+	g.Expect(transport.Proxy).NotTo(BeNil())
+}
+
+func TestIsTLSVerificationError(t *testing.T) {
+	g := NewWithT(t)
+
+	requestErr := awserr.New("RequestError", "send request failed", &url.Error{Op: "Post", URL: "https://sts.amazonaws.com", Err: x509.UnknownAuthorityError{}})
+	g.Expect(IsTLSVerificationError(requestErr)).To(BeTrue())
+	g.Expect(IsTLSVerificationError(errors.Wrap(requestErr, "failed to assume role"))).To(BeTrue())
+	g.Expect(IsTLSVerificationError(awserr.New("AccessDenied", "not authorized", nil))).To(BeFalse())
+	g.Expect(IsTLSVerificationError(errors.New("some error"))).To(BeFalse())
+}
+
+// newCACertificate returns a PEM encoded self-signed CA certificate.
+func newCACertificate(t *testing.T) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "proxy-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
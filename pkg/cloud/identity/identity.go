@@ -133,6 +133,9 @@ type AWSRolePrincipalTypeProvider struct {
 	sourceProvider AWSPrincipalTypeProvider
 	log            logger.Wrapper
 	stsClient      stsiface.STSAPI
+	// CABundle is the PEM encoded CA bundle the certificate of the STS endpoint is verified with, in
+	// addition to the system CAs. It is part of the hash, so that a rotated bundle creates a new provider.
+	CABundle []byte
 }
 
 // Hash returns the byte encoded AWSRolePrincipalTypeProvider.
@@ -162,6 +165,13 @@ func (p *AWSRolePrincipalTypeProvider) Retrieve() (credentials.Value, error) {
 			}
 			awsConfig = awsConfig.WithCredentials(credentials.NewStaticCredentialsFromCreds(sourceCreds))
 		}
+		httpClient, err := NewHTTPClient(p.CABundle)
+		if err != nil {
+			return credentials.Value{}, err
+		}
+		if httpClient != nil {
+			awsConfig = awsConfig.WithHTTPClient(httpClient)
+		}
 
 		creds := GetAssumeRoleCredentials(p, awsConfig)
 		// Update credentials
@@ -32,16 +32,41 @@ import (
 )
 
 const (
-	metricAWSSubsystem       = "aws"
-	metricRequestCountKey    = "api_requests_total"
-	metricRequestDurationKey = "api_request_duration_seconds"
-	metricAPICallRetries     = "api_call_retries"
-	metricServiceLabel       = "service"
-	metricRegionLabel        = "region"
-	metricOperationLabel     = "operation"
-	metricControllerLabel    = "controller"
-	metricStatusCodeLabel    = "status_code"
-	metricErrorCodeLabel     = "error_code"
+	metricCAPANamespace                        = "capa"
+	metricAWSSubsystem                         = "aws"
+	metricRequestCountKey                      = "api_requests_total"
+	metricRequestDurationKey                   = "api_request_duration_seconds"
+	metricAPICallRetries                       = "api_call_retries"
+	metricUserDataDriftKey                     = "machine_user_data_drift_total"
+	metricMachineProvisionPhaseKey             = "machine_provision_phase_seconds"
+	metricMachinePoolInstanceProvisionPhaseKey = "machinepool_instance_provision_phase_seconds"
+	metricReconcileErrorsKey                   = "reconcile_errors_total"
+	metricServiceLabel                         = "service"
+	metricRegionLabel                          = "region"
+	metricOperationLabel                       = "operation"
+	metricControllerLabel                      = "controller"
+	metricStatusCodeLabel                      = "status_code"
+	metricErrorCodeLabel                       = "error_code"
+	metricNamespaceLabel                       = "namespace"
+	metricClusterLabel                         = "cluster"
+	metricPhaseLabel                           = "phase"
+	metricKindLabel                            = "kind"
+	metricErrorClassLabel                      = "error_class"
+)
+
+const (
+	// MachineProvisionPhaseBootstrapData is the phase from the creation of an AWSMachine to the rendering of its bootstrap data.
+	MachineProvisionPhaseBootstrapData = "bootstrap_data"
+	// MachineProvisionPhaseInstanceRequest is the phase from the rendering of the bootstrap data to the request of the instance.
+	MachineProvisionPhaseInstanceRequest = "instance_request"
+	// MachineProvisionPhaseInstanceRunning is the phase from the request of the instance to the instance running.
+	MachineProvisionPhaseInstanceRunning = "instance_running"
+	// MachineProvisionPhaseAddresses is the phase from the instance running to the assignment of its addresses.
+	MachineProvisionPhaseAddresses = "addresses"
+
+	// MachinePoolInstanceProvisionPhaseNodeRegistration is the phase from the start of the scaling activity
+	// launching an instance of an Auto Scaling group to the registration of its Node.
+	MachinePoolInstanceProvisionPhaseNodeRegistration = "node_registration"
 )
 
 var (
@@ -61,12 +86,58 @@ var (
 		Help:      "Number of retries made against an AWS API",
 		Buckets:   []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
 	}, []string{metricControllerLabel, metricServiceLabel, metricRegionLabel, metricOperationLabel})
+	userDataDriftCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: metricAWSSubsystem,
+		Name:      metricUserDataDriftKey,
+		Help:      "Total number of AWSMachines detected with EC2 instance user data that differs from the user data they were launched with",
+	}, []string{metricNamespaceLabel, metricClusterLabel})
+	machineProvisionPhaseSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricCAPANamespace,
+		Name:      metricMachineProvisionPhaseKey,
+		Help:      "Duration of the provisioning phases of AWSMachines",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{metricPhaseLabel})
+	machinePoolInstanceProvisionPhaseSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricCAPANamespace,
+		Name:      metricMachinePoolInstanceProvisionPhaseKey,
+		Help:      "Duration of the provisioning phases of the instances of AWSMachinePools",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{metricPhaseLabel})
+	reconcileErrorsCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricCAPANamespace,
+		Name:      metricReconcileErrorsKey,
+		Help:      "Total number of failed reconciliations retried after a backoff delay, which controller-runtime doesn't count as errors",
+	}, []string{metricKindLabel, metricErrorClassLabel})
 )
 
 func init() {
 	metrics.Registry.MustRegister(awsRequestCount)
 	metrics.Registry.MustRegister(awsRequestDurationSeconds)
 	metrics.Registry.MustRegister(awsCallRetries)
+	metrics.Registry.MustRegister(userDataDriftCount)
+	metrics.Registry.MustRegister(machineProvisionPhaseSeconds)
+	metrics.Registry.MustRegister(machinePoolInstanceProvisionPhaseSeconds)
+	metrics.Registry.MustRegister(reconcileErrorsCount)
+}
+
+// RecordUserDataDrift records the detection of an AWSMachine whose instance user data has drifted.
+func RecordUserDataDrift(namespace, cluster string) {
+	userDataDriftCount.WithLabelValues(namespace, cluster).Inc()
+}
+
+// ObserveMachineProvisionPhase records the duration of a provisioning phase of an AWSMachine.
+func ObserveMachineProvisionPhase(phase string, duration time.Duration) {
+	machineProvisionPhaseSeconds.WithLabelValues(phase).Observe(duration.Seconds())
+}
+
+// ObserveMachinePoolInstanceProvisionPhase records the duration of a provisioning phase of an instance of an AWSMachinePool.
+func ObserveMachinePoolInstanceProvisionPhase(phase string, duration time.Duration) {
+	machinePoolInstanceProvisionPhaseSeconds.WithLabelValues(phase).Observe(duration.Seconds())
+}
+
+// RecordReconcileError records a failed reconciliation of an object of the kind, retried after a backoff delay.
+func RecordReconcileError(kind, errorClass string) {
+	reconcileErrorsCount.WithLabelValues(kind, errorClass).Inc()
 }
 
 // CaptureRequestMetrics will monitor and capture request metrics.
@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// maxAMIVersionSkew is the number of minor versions the kubelet may be older than the API server, as
+// defined by the Kubernetes version skew policy.
+const maxAMIVersionSkew = 3
+
+// amiLookupVersion returns the Kubernetes version the AMI of a pool is looked up for: the AMI version
+// override of the pool if set, or else the version of its MachinePool. The override must be within the
+// supported version skew of the control plane, when the version of the control plane is known.
+func amiLookupVersion(override, machinePoolVersion, controlPlaneVersion *string) (*string, error) {
+	if override == nil {
+		return machinePoolVersion, nil
+	}
+	amiVersion, err := version.ParseGeneric(*override)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid AMI version override %q", *override)
+	}
+	if controlPlaneVersion == nil {
+		return override, nil
+	}
+	cpVersion, err := version.ParseGeneric(*controlPlaneVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid control plane version %q", *controlPlaneVersion)
+	}
+
+	if amiVersion.Major() != cpVersion.Major() || amiVersion.Minor() > cpVersion.Minor() || amiVersion.Minor()+maxAMIVersionSkew < cpVersion.Minor() {
+		return nil, errors.Errorf("AMI version override %s is outside of the supported version skew of the control plane version %s: it can't be newer, nor more than %d minor versions older",
+			*override, *controlPlaneVersion, maxAMIVersionSkew)
+	}
+	return override, nil
+}
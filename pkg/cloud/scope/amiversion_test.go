@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+)
+
+func TestAMILookupVersion(t *testing.T) {
+	tests := []struct {
+		name                string
+		override            *string
+		controlPlaneVersion *string
+		want                *string
+		wantErr             string
+	}{
+		{
+			name: "should use the version of the MachinePool without override",
+			want: ptr.To("v1.29.1"),
+		},
+		{
+			name:                "should prefer the override",
+			override:            ptr.To("v1.28.5"),
+			controlPlaneVersion: ptr.To("v1.29"),
+			want:                ptr.To("v1.28.5"),
+		},
+		{
+			name:     "should use the override when the control plane version is unknown",
+			override: ptr.To("1.27"),
+			want:     ptr.To("1.27"),
+		},
+		{
+			name:                "should accept an override as old as the supported skew",
+			override:            ptr.To("v1.26.0"),
+			controlPlaneVersion: ptr.To("v1.29.0"),
+			want:                ptr.To("v1.26.0"),
+		},
+		{
+			name:                "should reject an override older than the supported skew",
+			override:            ptr.To("v1.25.0"),
+			controlPlaneVersion: ptr.To("v1.29.0"),
+			wantErr:             "outside of the supported version skew",
+		},
+		{
+			name:                "should reject an override newer than the control plane",
+			override:            ptr.To("v1.30.0"),
+			controlPlaneVersion: ptr.To("v1.29.0"),
+			wantErr:             "outside of the supported version skew",
+		},
+		{
+			name:     "should reject an invalid override",
+			override: ptr.To("latest"),
+			wantErr:  "invalid AMI version override",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := amiLookupVersion(tt.override, ptr.To("v1.29.1"), tt.controlPlaneVersion)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
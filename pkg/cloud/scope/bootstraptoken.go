@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// DefaultBootstrapTokenTTL is the default TTL of the bootstrap tokens of the kubeadm bootstrap provider. It is used
+// to estimate when the tokens which were already deleted from the workload cluster expired.
+const DefaultBootstrapTokenTTL = 15 * time.Minute
+
+// bootstrapTokenRegexp matches the kubeadm bootstrap token of the join configuration in the bootstrap data, and
+// captures its ID.
+var bootstrapTokenRegexp = regexp.MustCompile(`token:\s*["']?([a-z0-9]{6})\.[a-z0-9]{16}["']?`)
+
+// newWorkloadClusterClient returns a client for the workload cluster. It is a variable so that tests can replace it.
+var newWorkloadClusterClient = func(ctx context.Context, c client.Client, cluster *clusterv1.Cluster) (client.Client, error) {
+	return remote.NewClusterClient(ctx, "", c, util.ObjectKey(cluster))
+}
+
+// GetBootstrapTokenExpiry returns when the bootstrap token embedded in the bootstrap data of the machine expires,
+// or nil if it can't be told. See bootstrapTokenExpiry for how the expiry is read.
+func (m *MachineScope) GetBootstrapTokenExpiry(ctx context.Context, ttl time.Duration) (*time.Time, error) {
+	return bootstrapTokenExpiry(ctx, m.client, m.Cluster, m.Namespace(), m.Machine.Spec.Bootstrap.DataSecretName, ttl)
+}
+
+// GetBootstrapTokenExpiry returns when the bootstrap token embedded in the bootstrap data of the machine pool
+// expires, or nil if it can't be told. See bootstrapTokenExpiry for how the expiry is read.
+func (m *MachinePoolScope) GetBootstrapTokenExpiry(ctx context.Context, ttl time.Duration) (*time.Time, error) {
+	return bootstrapTokenExpiry(ctx, m.Client, m.Cluster, m.Namespace(), m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName, ttl)
+}
+
+// RequestUserDataRegeneration sets the BootstrapTokenExpiredAnnotation on the AWSMachinePool, so that a new version
+// of its launch template is created with the current bootstrap data even if the bootstrap data didn't change. It
+// returns false when the AWSMachinePool was already annotated for the expiry.
+func (m *MachinePoolScope) RequestUserDataRegeneration(expiresAt time.Time) bool {
+	value := expiresAt.UTC().Format(time.RFC3339)
+	if m.AWSMachinePool.Annotations[infrav1.BootstrapTokenExpiredAnnotation] == value {
+		return false
+	}
+	if m.AWSMachinePool.Annotations == nil {
+		m.AWSMachinePool.Annotations = map[string]string{}
+	}
+	m.AWSMachinePool.Annotations[infrav1.BootstrapTokenExpiredAnnotation] = value
+	return true
+}
+
+// bootstrapTokenExpiry returns the expiry of the bootstrap token of a bootstrap data secret. It is read from the
+// BootstrapTokenExpiresAtAnnotation of the secret when it is set. Otherwise the kubeadm bootstrap token of the
+// bootstrap data is looked up in the workload cluster, once its control plane is initialized: the expiry is the one
+// of the secret of the token in kube-system, or, when the token was already deleted from the workload cluster, the
+// time the bootstrap data was last written plus the TTL of the tokens.
+func bootstrapTokenExpiry(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, namespace string, dataSecretName *string, ttl time.Duration) (*time.Time, error) {
+	if dataSecretName == nil {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: namespace, Name: *dataSecretName}
+	if err := c.Get(ctx, key, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve bootstrap data secret %s", key)
+	}
+
+	value, ok := secret.Annotations[infrav1.BootstrapTokenExpiresAtAnnotation]
+	if !ok {
+		return kubeadmBootstrapTokenExpiry(ctx, c, cluster, secret, ttl)
+	}
+	expiresAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the %s annotation of bootstrap data secret %s", infrav1.BootstrapTokenExpiresAtAnnotation, key)
+	}
+	return &expiresAt, nil
+}
+
+// kubeadmBootstrapTokenExpiry returns the expiry of the kubeadm bootstrap token of a bootstrap data secret, or nil
+// if the bootstrap data has no such token or the token never expires.
+func kubeadmBootstrapTokenExpiry(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, secret *corev1.Secret, ttl time.Duration) (*time.Time, error) {
+	match := bootstrapTokenRegexp.FindSubmatch(secret.Data["value"])
+	if match == nil || cluster == nil || !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+		return nil, nil
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultBootstrapTokenTTL
+	}
+
+	workloadClient, err := newWorkloadClusterClient(ctx, c, cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create workload cluster client")
+	}
+	tokenSecret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "bootstrap-token-" + string(match[1])}
+	if err := workloadClient.Get(ctx, key, tokenSecret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to retrieve bootstrap token secret %s", key)
+		}
+		// The token cleaner deletes the tokens once expired. The token was issued when the bootstrap data was last
+		// written at the latest, so it expired at most a TTL after. A token deleted before then was revoked, and is
+		// reported as expired when the bootstrap data was written.
+		writtenAt := lastWriteTime(secret)
+		expiresAt := writtenAt.Add(ttl)
+		if expiresAt.After(time.Now()) {
+			expiresAt = writtenAt
+		}
+		return &expiresAt, nil
+	}
+
+	value, ok := tokenSecret.Data["expiration"]
+	if !ok {
+		return nil, nil
+	}
+	expiresAt, err := time.Parse(time.RFC3339, string(value))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the expiration of bootstrap token secret %s", key)
+	}
+	return &expiresAt, nil
+}
+
+// lastWriteTime returns when an object was last written, as recorded in its managed fields, or its creation time.
+func lastWriteTime(obj metav1.Object) time.Time {
+	writtenAt := obj.GetCreationTimestamp().Time
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Time != nil && entry.Time.After(writtenAt) {
+			writtenAt = entry.Time.Time
+		}
+	}
+	return writtenAt
+}
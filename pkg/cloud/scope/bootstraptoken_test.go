@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestMachineScopeGetBootstrapTokenExpiry(t *testing.T) {
+	g := NewWithT(t)
+
+	scope, err := setupMachineScope()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	setAnnotation := func(value string) {
+		secret := &corev1.Secret{}
+		g.Expect(scope.client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "my-machine-0"}, secret)).To(Succeed())
+		secret.Annotations = map[string]string{infrav1.BootstrapTokenExpiresAtAnnotation: value}
+		g.Expect(scope.client.Update(context.TODO(), secret)).To(Succeed())
+	}
+
+	// The expiry is unknown when the bootstrap data secret doesn't have the annotation.
+	expiresAt, err := scope.GetBootstrapTokenExpiry(context.TODO(), DefaultBootstrapTokenTTL)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(expiresAt).To(BeNil())
+
+	setAnnotation("2024-01-01T12:00:00Z")
+	expiresAt, err = scope.GetBootstrapTokenExpiry(context.TODO(), DefaultBootstrapTokenTTL)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*expiresAt).To(BeTemporally("==", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)))
+
+	setAnnotation("15m")
+	_, err = scope.GetBootstrapTokenExpiry(context.TODO(), DefaultBootstrapTokenTTL)
+	g.Expect(err).To(MatchError(ContainSubstring("failed to parse")))
+}
+
+func TestKubeadmBootstrapTokenExpiry(t *testing.T) {
+	writtenAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	expiration := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		bootstrapData        string
+		controlPlaneNotReady bool
+		tokenSecret          *corev1.Secret
+		ttl                  time.Duration
+		wantExpiresAt        *time.Time
+	}{
+		{
+			name:          "no kubeadm bootstrap token in the bootstrap data",
+			bootstrapData: "#cloud-config\n",
+		},
+		{
+			name:                 "control plane not initialized",
+			bootstrapData:        "token: abcdef.0123456789abcdef\n",
+			controlPlaneNotReady: true,
+		},
+		{
+			name:          "token secret with an expiration",
+			bootstrapData: "token: abcdef.0123456789abcdef\n",
+			tokenSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: "bootstrap-token-abcdef"},
+				Data:       map[string][]byte{"expiration": []byte(expiration.Format(time.RFC3339))},
+			},
+			wantExpiresAt: &expiration,
+		},
+		{
+			name:          "token secret without an expiration",
+			bootstrapData: "token: abcdef.0123456789abcdef\n",
+			tokenSecret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: "bootstrap-token-abcdef"},
+			},
+		},
+		{
+			name:          "token secret deleted, the expiry is estimated with the default TTL",
+			bootstrapData: "token: abcdef.0123456789abcdef\n",
+			wantExpiresAt: ptr.To(writtenAt.Add(DefaultBootstrapTokenTTL)),
+		},
+		{
+			name:          "token secret deleted before the end of its TTL",
+			bootstrapData: "token: abcdef.0123456789abcdef\n",
+			ttl:           2 * time.Hour,
+			wantExpiresAt: &writtenAt,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme, err := setupScheme()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			cluster := newCluster("my-cluster")
+			if !tt.controlPlaneNotReady {
+				conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "default",
+					Name:              "bootstrap-data",
+					CreationTimestamp: metav1.NewTime(writtenAt),
+				},
+				Data: map[string][]byte{"value": []byte(tt.bootstrapData)},
+			}
+
+			workloadObjects := []client.Object{}
+			if tt.tokenSecret != nil {
+				workloadObjects = append(workloadObjects, tt.tokenSecret)
+			}
+			workloadClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workloadObjects...).Build()
+			oldNewWorkloadClusterClient := newWorkloadClusterClient
+			newWorkloadClusterClient = func(context.Context, client.Client, *clusterv1.Cluster) (client.Client, error) {
+				return workloadClient, nil
+			}
+			t.Cleanup(func() { newWorkloadClusterClient = oldNewWorkloadClusterClient })
+
+			expiresAt, err := kubeadmBootstrapTokenExpiry(context.TODO(), nil, cluster, secret, tt.ttl)
+			g.Expect(err).NotTo(HaveOccurred())
+			if tt.wantExpiresAt == nil {
+				g.Expect(expiresAt).To(BeNil())
+			} else {
+				g.Expect(expiresAt).NotTo(BeNil())
+				g.Expect(*expiresAt).To(BeTemporally("==", *tt.wantExpiresAt))
+			}
+		})
+	}
+}
+
+func TestMachinePoolScopeRequestUserDataRegeneration(t *testing.T) {
+	g := NewWithT(t)
+
+	scope := &MachinePoolScope{AWSMachinePool: &expinfrav1.AWSMachinePool{}}
+	expiresAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	g.Expect(scope.RequestUserDataRegeneration(expiresAt)).To(BeTrue())
+	g.Expect(scope.AWSMachinePool.Annotations).To(HaveKeyWithValue(infrav1.BootstrapTokenExpiredAnnotation, "2024-01-01T12:00:00Z"))
+
+	// The regeneration is only requested once per expiry.
+	g.Expect(scope.RequestUserDataRegeneration(expiresAt)).To(BeFalse())
+	g.Expect(scope.RequestUserDataRegeneration(expiresAt.Add(time.Hour))).To(BeTrue())
+	g.Expect(scope.AWSMachinePool.Annotations).To(HaveKeyWithValue(infrav1.BootstrapTokenExpiredAnnotation, "2024-01-01T13:00:00Z"))
+}
@@ -22,6 +22,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/eks"
@@ -40,6 +42,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/secretsmanager"
 	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/aws/aws-sdk-go/service/ssm"
@@ -49,6 +53,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/audit"
 	awslogs "sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/logs"
 	awsmetrics "sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/metrics"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/logger"
@@ -62,6 +67,7 @@ func NewASGClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logg
 	asgClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	asgClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	asgClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	asgClient.Handlers.Complete.PushBack(audit.RecordRequest(scopeUser.ControllerName(), target))
 
 	return asgClient
 }
@@ -78,6 +84,7 @@ func NewEC2Client(scopeUser cloud.ScopeUsage, session cloud.Session, logger logg
 		ec2Client.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(ec2.ServiceID).ReviewResponse)
 	}
 	ec2Client.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	ec2Client.Handlers.Complete.PushBack(audit.RecordRequest(scopeUser.ControllerName(), target))
 
 	return ec2Client
 }
@@ -90,6 +97,7 @@ func NewELBClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logg
 	elbClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	elbClient.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(elb.ServiceID).ReviewResponse)
 	elbClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	elbClient.Handlers.Complete.PushBack(audit.RecordRequest(scopeUser.ControllerName(), target))
 
 	return elbClient
 }
@@ -102,6 +110,7 @@ func NewELBv2Client(scopeUser cloud.ScopeUsage, session cloud.Session, logger lo
 	elbClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	elbClient.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(elbv2.ServiceID).ReviewResponse)
 	elbClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	elbClient.Handlers.Complete.PushBack(audit.RecordRequest(scopeUser.ControllerName(), target))
 
 	return elbClient
 }
@@ -112,6 +121,7 @@ func NewEventBridgeClient(scopeUser cloud.ScopeUsage, session cloud.Session, tar
 	eventBridgeClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	eventBridgeClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	eventBridgeClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	eventBridgeClient.Handlers.Complete.PushBack(audit.RecordRequest(scopeUser.ControllerName(), target))
 
 	return eventBridgeClient
 }
@@ -122,6 +132,7 @@ func NewSQSClient(scopeUser cloud.ScopeUsage, session cloud.Session, target runt
 	SQSClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	SQSClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	SQSClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	SQSClient.Handlers.Complete.PushBack(audit.RecordRequest(scopeUser.ControllerName(), target))
 
 	return SQSClient
 }
@@ -131,6 +142,7 @@ func NewGlobalSQSClient(scopeUser cloud.ScopeUsage, session cloud.Session) sqsif
 	SQSClient := sqs.New(session.Session())
 	SQSClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	SQSClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+	SQSClient.Handlers.Complete.PushBack(audit.RecordRequest(scopeUser.ControllerName(), nil))
 
 	return SQSClient
 }
@@ -143,6 +155,7 @@ func NewResourgeTaggingClient(scopeUser cloud.ScopeUsage, session cloud.Session,
 	resourceTagging.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	resourceTagging.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(resourceTagging.ServiceID).ReviewResponse)
 	resourceTagging.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	resourceTagging.Handlers.Complete.PushBack(audit.RecordRequest(scopeUser.ControllerName(), target))
 
 	return resourceTagging
 }
@@ -155,6 +168,7 @@ func NewSecretsManagerClient(scopeUser cloud.ScopeUsage, session cloud.Session,
 	secretsClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	secretsClient.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(secretsClient.ServiceID).ReviewResponse)
 	secretsClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	secretsClient.Handlers.Complete.PushBack(audit.RecordRequest(scopeUser.ControllerName(), target))
 
 	return secretsClient
 }
@@ -165,16 +179,29 @@ func NewEKSClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logg
 	eksClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	eksClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	eksClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	eksClient.Handlers.Complete.PushBack(audit.RecordRequest(scopeUser.ControllerName(), target))
 
 	return eksClient
 }
 
+// NewCloudWatchLogsClient creates a new CloudWatch Logs API client for a given session.
+func NewCloudWatchLogsClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logger.Wrapper, target runtime.Object) cloudwatchlogsiface.CloudWatchLogsAPI {
+	logsClient := cloudwatchlogs.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger.GetLogger())).WithLogger(awslogs.NewWrapLogr(logger.GetLogger())))
+	logsClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	logsClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+	logsClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	logsClient.Handlers.Complete.PushBack(audit.RecordRequest(scopeUser.ControllerName(), target))
+
+	return logsClient
+}
+
 // NewIAMClient creates a new IAM API client for a given session.
 func NewIAMClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logger.Wrapper, target runtime.Object) iamiface.IAMAPI {
 	iamClient := iam.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger.GetLogger())).WithLogger(awslogs.NewWrapLogr(logger.GetLogger())))
 	iamClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	iamClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	iamClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	iamClient.Handlers.Complete.PushBack(audit.RecordRequest(scopeUser.ControllerName(), target))
 
 	return iamClient
 }
@@ -185,6 +212,7 @@ func NewSTSClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logg
 	stsClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	stsClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	stsClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	stsClient.Handlers.Complete.PushBack(audit.RecordRequest(scopeUser.ControllerName(), target))
 
 	return stsClient
 }
@@ -195,20 +223,34 @@ func NewSSMClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logg
 	ssmClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	ssmClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	ssmClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	ssmClient.Handlers.Complete.PushBack(audit.RecordRequest(scopeUser.ControllerName(), target))
 
 	return ssmClient
 }
 
 // NewS3Client creates a new S3 API client for a given session.
-func NewS3Client(scopeUser cloud.ScopeUsage, session cloud.Session, logger logger.Wrapper, target runtime.Object) s3iface.S3API {
-	s3Client := s3.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger.GetLogger())).WithLogger(awslogs.NewWrapLogr(logger.GetLogger())))
+// The additional configurations are merged in order, e.g. to adapt the client to the partition.
+func NewS3Client(scopeUser cloud.ScopeUsage, session cloud.Session, logger logger.Wrapper, target runtime.Object, cfgs ...*aws.Config) s3iface.S3API {
+	cfgs = append([]*aws.Config{aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger.GetLogger())).WithLogger(awslogs.NewWrapLogr(logger.GetLogger()))}, cfgs...)
+	s3Client := s3.New(session.Session(), cfgs...)
 	s3Client.Handlers.Build.PushFrontNamed(getUserAgentHandler())
 	s3Client.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	s3Client.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	s3Client.Handlers.Complete.PushBack(audit.RecordRequest(scopeUser.ControllerName(), target))
 
 	return s3Client
 }
 
+// NewServiceQuotasClient creates a new Service Quotas API client for a given session.
+// The quota check is opt-in and degrades silently, so permission issues are not recorded as events.
+func NewServiceQuotasClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logger.Wrapper) servicequotasiface.ServiceQuotasAPI {
+	serviceQuotasClient := servicequotas.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger.GetLogger())).WithLogger(awslogs.NewWrapLogr(logger.GetLogger())))
+	serviceQuotasClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	serviceQuotasClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+
+	return serviceQuotasClient
+}
+
 func recordAWSPermissionsIssue(target runtime.Object) func(r *request.Request) {
 	return func(r *request.Request) {
 		if awsErr, ok := r.Error.(awserr.Error); ok {
@@ -22,16 +22,27 @@ import (
 
 	awsclient "github.com/aws/aws-sdk-go/aws/client"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/throttle"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/logger"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/util/system"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	expclusterv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
@@ -86,6 +97,15 @@ func NewClusterScope(params ClusterScopeParams) (*ClusterScope, error) {
 	clusterScope.session = session
 	clusterScope.serviceLimiters = serviceLimiters
 
+	// The resource policy isn't needed to delete the cluster, which mustn't be blocked by a missing or invalid ConfigMap.
+	if ref := params.AWSCluster.Spec.ResourcePolicyRef; ref != nil && params.AWSCluster.DeletionTimestamp.IsZero() {
+		policy, err := getResourcePolicy(context.TODO(), params.Client, params.AWSCluster.Namespace, ref, params.AWSCluster.Spec.Region)
+		if err != nil {
+			return nil, err
+		}
+		clusterScope.resourcePolicy = policy
+	}
+
 	return clusterScope, nil
 }
 
@@ -101,6 +121,7 @@ type ClusterScope struct {
 	session         awsclient.ConfigProvider
 	serviceLimiters throttle.ServiceLimiters
 	controllerName  string
+	resourcePolicy  *ResourcePolicy
 
 	tagUnmanagedNetworkResources bool
 }
@@ -209,11 +230,26 @@ func (s *ClusterScope) ControlPlaneLoadBalancerName() *string {
 	return nil
 }
 
+// ControlPlaneLoadBalancerMigration returns the progress of the migration of the control plane load balancer.
+func (s *ClusterScope) ControlPlaneLoadBalancerMigration() *infrav1.LoadBalancerMigration {
+	return s.AWSCluster.Status.ControlPlaneLoadBalancerMigration
+}
+
+// SetControlPlaneLoadBalancerMigration sets the progress of the migration of the control plane load balancer.
+func (s *ClusterScope) SetControlPlaneLoadBalancerMigration(migration *infrav1.LoadBalancerMigration) {
+	s.AWSCluster.Status.ControlPlaneLoadBalancerMigration = migration
+}
+
 // ControlPlaneEndpoint returns the cluster control plane endpoint.
 func (s *ClusterScope) ControlPlaneEndpoint() clusterv1.APIEndpoint {
 	return s.AWSCluster.Spec.ControlPlaneEndpoint
 }
 
+// SetControlPlaneEndpoint sets the cluster control plane endpoint.
+func (s *ClusterScope) SetControlPlaneEndpoint(endpoint clusterv1.APIEndpoint) {
+	s.AWSCluster.Spec.ControlPlaneEndpoint = endpoint
+}
+
 // Bucket returns the cluster bucket configuration.
 func (s *ClusterScope) Bucket() *infrav1.S3Bucket {
 	return s.AWSCluster.Spec.S3Bucket
@@ -280,8 +316,14 @@ func (s *ClusterScope) PatchObject() error {
 			infrav1.ClusterSecurityGroupsReadyCondition,
 			infrav1.BastionHostReadyCondition,
 			infrav1.LoadBalancerReadyCondition,
+			infrav1.LoadBalancerMigrationProvisionedCondition,
+			infrav1.LoadBalancerMigrationTargetsHealthyCondition,
+			infrav1.LoadBalancerMigrationEndpointUpdatedCondition,
+			infrav1.LoadBalancerMigrationClassicLoadBalancerDeletedCondition,
 			infrav1.PrincipalUsageAllowedCondition,
 			infrav1.PrincipalCredentialRetrievedCondition,
+			infrav1.SSHKeyAvailableCondition,
+			infrav1.AWSRequestsSucceededCondition,
 		}})
 }
 
@@ -291,12 +333,22 @@ func (s *ClusterScope) Close() error {
 }
 
 // AdditionalTags returns AdditionalTags from the scope's AWSCluster. The returned value will never be nil.
+// The AWSCluster isn't modified, since the tags are read by the resources of the availability zones which are
+// created concurrently.
 func (s *ClusterScope) AdditionalTags() infrav1.Tags {
-	if s.AWSCluster.Spec.AdditionalTags == nil {
-		s.AWSCluster.Spec.AdditionalTags = infrav1.Tags{}
+	if s.resourcePolicy == nil {
+		tags := s.AWSCluster.Spec.AdditionalTags.DeepCopy()
+		if tags == nil {
+			tags = infrav1.Tags{}
+		}
+		return tags
 	}
-
-	return s.AWSCluster.Spec.AdditionalTags.DeepCopy()
+	tags := s.resourcePolicy.AdditionalTags.DeepCopy()
+	if tags == nil {
+		tags = infrav1.Tags{}
+	}
+	tags.Merge(s.AWSCluster.Spec.AdditionalTags)
+	return tags
 }
 
 // APIServerPort returns the APIServerPort to use when creating the load balancer.
@@ -368,6 +420,11 @@ func (s *ClusterScope) SSHKeyName() *string {
 	return s.AWSCluster.Spec.SSHKeyName
 }
 
+// DefaultInstanceMetadataOptions returns the metadata options of the instances which don't set their own.
+func (s *ClusterScope) DefaultInstanceMetadataOptions() *infrav1.InstanceMetadataOptions {
+	return s.AWSCluster.Spec.DefaultInstanceMetadataOptions
+}
+
 // ControllerName returns the name of the controller that
 // created the ClusterScope.
 func (s *ClusterScope) ControllerName() string {
@@ -389,6 +446,19 @@ func (s *ClusterScope) ImageLookupBaseOS() string {
 	return s.AWSCluster.Spec.ImageLookupBaseOS
 }
 
+// DefaultLifecycleHooks returns the lifecycle hooks added to all the machine pools of the cluster.
+func (s *ClusterScope) DefaultLifecycleHooks() []infrav1.AWSLifecycleHook {
+	return s.AWSCluster.Spec.DefaultLifecycleHooks
+}
+
+// ResourcePolicyLifecycleHooks returns the lifecycle hooks of the resource policy of the cluster.
+func (s *ClusterScope) ResourcePolicyLifecycleHooks() []infrav1.AWSLifecycleHook {
+	if s.resourcePolicy == nil {
+		return nil
+	}
+	return s.resourcePolicy.LifecycleHooks
+}
+
 // Partition returns the cluster partition.
 func (s *ClusterScope) Partition() string {
 	if s.AWSCluster.Spec.Partition == "" {
@@ -402,8 +472,327 @@ func (s *ClusterScope) AdditionalControlPlaneIngressRules() []infrav1.IngressRul
 	return s.AWSCluster.Spec.NetworkSpec.DeepCopy().AdditionalControlPlaneIngressRules
 }
 
+// MachinePoolLoadBalancerListenerPorts returns the ports of the control plane load balancer additional listeners
+// that AWSMachinePools of the cluster are attached to.
+func (s *ClusterScope) MachinePoolLoadBalancerListenerPorts() ([]int64, error) {
+	if !feature.Gates.Enabled(feature.MachinePool) {
+		return nil, nil
+	}
+
+	pools := &expinfrav1.AWSMachinePoolList{}
+	if err := s.client.List(context.TODO(), pools, client.InNamespace(s.Namespace()), s.ListOptionsLabelSelector()); err != nil {
+		return nil, errors.Wrap(err, "failed to list AWSMachinePools")
+	}
+
+	ports := sets.New[int64]()
+	for _, pool := range pools.Items {
+		for _, attachment := range pool.Spec.LoadBalancerAttachments {
+			ports.Insert(attachment.ListenerPort)
+		}
+	}
+	return sets.List(ports), nil
+}
+
+// HasWindowsNodes returns true if AWSMachines or AWSMachinePools of the cluster run Windows nodes.
+func (s *ClusterScope) HasWindowsNodes() (bool, error) {
+	machines := &infrav1.AWSMachineList{}
+	if err := s.client.List(context.TODO(), machines, client.InNamespace(s.Namespace()), s.ListOptionsLabelSelector()); err != nil {
+		return false, errors.Wrap(err, "failed to list AWSMachines")
+	}
+	for _, machine := range machines.Items {
+		if machine.Spec.OS == infrav1.OSTypeWindows {
+			return true, nil
+		}
+	}
+
+	if !feature.Gates.Enabled(feature.MachinePool) {
+		return false, nil
+	}
+
+	pools := &expinfrav1.AWSMachinePoolList{}
+	if err := s.client.List(context.TODO(), pools, client.InNamespace(s.Namespace()), s.ListOptionsLabelSelector()); err != nil {
+		return false, errors.Wrap(err, "failed to list AWSMachinePools")
+	}
+	for _, pool := range pools.Items {
+		if pool.Spec.OS == infrav1.OSTypeWindows {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// UserDataMigrationPending returns the number of AWSMachines of the cluster with the NeedsUserDataMigrationAnnotation.
+func (s *ClusterScope) UserDataMigrationPending() (int32, error) {
+	machines := &infrav1.AWSMachineList{}
+	if err := s.client.List(context.TODO(), machines, client.InNamespace(s.Namespace()), s.ListOptionsLabelSelector()); err != nil {
+		return 0, errors.Wrap(err, "failed to list AWSMachines")
+	}
+	var pending int32
+	for _, machine := range machines.Items {
+		if machine.Annotations[infrav1.NeedsUserDataMigrationAnnotation] == "true" {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// QuotaCheck returns the configuration of the quota check.
+func (s *ClusterScope) QuotaCheck() *infrav1.QuotaCheck {
+	return s.AWSCluster.Spec.QuotaCheck
+}
+
+// LastQuotaCheckTime returns the time of the last quota check, nil if the quotas were never checked.
+func (s *ClusterScope) LastQuotaCheckTime() *metav1.Time {
+	return s.AWSCluster.Status.LastQuotaCheckTime
+}
+
+// SetQuotaUsage sets the usage of the service quotas and the time of the check in the status of the cluster.
+func (s *ClusterScope) SetQuotaUsage(usage []infrav1.QuotaUsage, checkTime *metav1.Time) {
+	s.AWSCluster.Status.QuotaUsage = usage
+	s.AWSCluster.Status.LastQuotaCheckTime = checkTime
+}
+
+// ScanOrphans returns the value of the scan-orphans annotation of the cluster, empty if it isn't set.
+func (s *ClusterScope) ScanOrphans() string {
+	return s.AWSCluster.Annotations[infrav1.ScanOrphansAnnotation]
+}
+
+// LastOrphanScanTime returns the time of the last scan for orphaned resources, nil if there was none.
+func (s *ClusterScope) LastOrphanScanTime() *metav1.Time {
+	return s.AWSCluster.Status.LastOrphanScanTime
+}
+
+// OrphanedResources returns the orphaned resources reported by the last scan.
+func (s *ClusterScope) OrphanedResources() []infrav1.OrphanedResource {
+	return s.AWSCluster.Status.OrphanedResources
+}
+
+// SetOrphanedResources sets the orphaned resources and the time of the scan in the status of the cluster.
+func (s *ClusterScope) SetOrphanedResources(resources []infrav1.OrphanedResource, scanTime *metav1.Time) {
+	s.AWSCluster.Status.OrphanedResources = resources
+	s.AWSCluster.Status.LastOrphanScanTime = scanTime
+}
+
+// AWSMachinePools returns the AWSMachinePools of the cluster, including the ones being deleted.
+// It returns none when the machine pools are disabled.
+func (s *ClusterScope) AWSMachinePools() ([]expinfrav1.AWSMachinePool, error) {
+	if !feature.Gates.Enabled(feature.MachinePool) {
+		return nil, nil
+	}
+
+	pools := &expinfrav1.AWSMachinePoolList{}
+	if err := s.client.List(context.TODO(), pools, client.InNamespace(s.Namespace()), s.ListOptionsLabelSelector()); err != nil {
+		return nil, errors.Wrap(err, "failed to list AWSMachinePools")
+	}
+	return pools.Items, nil
+}
+
+// EventBridge returns the configuration of the EventBridge rule and SQS queue of the cluster.
+func (s *ClusterScope) EventBridge() *infrav1.EventBridgeConfig {
+	return s.AWSCluster.Spec.EventBridge
+}
+
+// EventBridgeStatus returns the names of the EventBridge rule and SQS queue of the cluster, nil if
+// they were not recorded yet.
+func (s *ClusterScope) EventBridgeStatus() *infrav1.EventBridgeStatus {
+	return s.AWSCluster.Status.EventBridge
+}
+
+// SetEventBridgeStatus sets the names of the EventBridge rule and SQS queue in the status of the cluster.
+func (s *ClusterScope) SetEventBridgeStatus(status *infrav1.EventBridgeStatus) {
+	s.AWSCluster.Status.EventBridge = status
+}
+
+// InstanceConnectEndpoint returns the configuration of the EC2 Instance Connect Endpoint of the cluster.
+func (s *ClusterScope) InstanceConnectEndpoint() *infrav1.InstanceConnectEndpoint {
+	return s.AWSCluster.Spec.InstanceConnectEndpoint
+}
+
+// InstanceConnectEndpointStatus returns the EC2 Instance Connect Endpoint of the cluster, nil if it doesn't exist.
+func (s *ClusterScope) InstanceConnectEndpointStatus() *infrav1.InstanceConnectEndpointStatus {
+	return s.AWSCluster.Status.InstanceConnectEndpoint
+}
+
+// SetInstanceConnectEndpointStatus sets the EC2 Instance Connect Endpoint in the status of the cluster.
+func (s *ClusterScope) SetInstanceConnectEndpointStatus(status *infrav1.InstanceConnectEndpointStatus) {
+	s.AWSCluster.Status.InstanceConnectEndpoint = status
+}
+
+// PendingOnDemandInstances returns, by instance type used by the cluster, the number of on-demand
+// instances the cluster is still expected to launch: one for each AWSMachine without an instance,
+// and the instances the AWSMachinePools launch when scaled to their maximum size. The instance
+// types used by the cluster without pending instances are returned with 0. Spot instances are not
+// counted.
+func (s *ClusterScope) PendingOnDemandInstances() (map[string]int64, error) {
+	pending := map[string]int64{}
+
+	machines := &infrav1.AWSMachineList{}
+	if err := s.client.List(context.TODO(), machines, client.InNamespace(s.Namespace()), s.ListOptionsLabelSelector()); err != nil {
+		return nil, errors.Wrap(err, "failed to list AWSMachines")
+	}
+	for _, machine := range machines.Items {
+		if machine.Spec.SpotMarketOptions != nil || machine.Spec.InstanceType == "" {
+			continue
+		}
+		count := pending[machine.Spec.InstanceType]
+		if machine.Spec.InstanceID == nil {
+			count++
+		}
+		pending[machine.Spec.InstanceType] = count
+	}
+
+	if !feature.Gates.Enabled(feature.MachinePool) {
+		return pending, nil
+	}
+
+	pools := &expinfrav1.AWSMachinePoolList{}
+	if err := s.client.List(context.TODO(), pools, client.InNamespace(s.Namespace()), s.ListOptionsLabelSelector()); err != nil {
+		return nil, errors.Wrap(err, "failed to list AWSMachinePools")
+	}
+	for _, pool := range pools.Items {
+		if pool.Spec.AWSLaunchTemplate.SpotMarketOptions != nil {
+			continue
+		}
+		instanceType := pool.Spec.AWSLaunchTemplate.InstanceType
+		if pool.Spec.MixedInstancesPolicy != nil && len(pool.Spec.MixedInstancesPolicy.Overrides) > 0 {
+			// The first override is the preferred instance type.
+			instanceType = pool.Spec.MixedInstancesPolicy.Overrides[0].InstanceType
+		}
+		if instanceType == "" {
+			continue
+		}
+		_, maxSize := pool.SizeBounds()
+		pending[instanceType] += max(0, int64(maxSize)-int64(pool.Status.Replicas))
+	}
+
+	return pending, nil
+}
+
 // UnstructuredControlPlane returns the unstructured object for the control plane, if any.
 // When the reference is not set, it returns an empty object.
 func (s *ClusterScope) UnstructuredControlPlane() (*unstructured.Unstructured, error) {
 	return getUnstructuredControlPlane(context.TODO(), s.client, s.Cluster)
 }
+
+// supportBundleKinds are the kinds of the objects labeled with the name of the cluster which are collected in
+// its support bundles.
+var supportBundleKinds = []schema.GroupVersionKind{
+	clusterv1.GroupVersion.WithKind("MachineDeployment"),
+	clusterv1.GroupVersion.WithKind("MachineSet"),
+	clusterv1.GroupVersion.WithKind("Machine"),
+	clusterv1.GroupVersion.WithKind("MachineHealthCheck"),
+	expclusterv1.GroupVersion.WithKind("MachinePool"),
+	infrav1.GroupVersion.WithKind("AWSMachine"),
+	expinfrav1.GroupVersion.WithKind("AWSMachinePool"),
+	expinfrav1.GroupVersion.WithKind("AWSManagedMachinePool"),
+	expinfrav1.GroupVersion.WithKind("AWSFargateProfile"),
+}
+
+// SupportBundleRequested returns whether the collect-support-bundle annotation of the cluster is set to "true".
+func (s *ClusterScope) SupportBundleRequested() bool {
+	return s.AWSCluster.Annotations[infrav1.CollectSupportBundleAnnotation] == "true"
+}
+
+// SupportBundleRedactsAccountIDs returns whether the AWS account IDs are redacted from the support bundles.
+func (s *ClusterScope) SupportBundleRedactsAccountIDs() bool {
+	return s.AWSCluster.Annotations[infrav1.SupportBundleRedactAccountIDsAnnotation] == "true"
+}
+
+// SupportBundleCollected removes the collect-support-bundle annotation of the cluster.
+func (s *ClusterScope) SupportBundleCollected() {
+	delete(s.AWSCluster.Annotations, infrav1.CollectSupportBundleAnnotation)
+}
+
+// SupportBundleObjects returns the Cluster, the AWSCluster, the control plane and the objects labeled with the
+// name of the cluster. The objects are read as unstructured objects, which bypass the cache of the client, so
+// that the kinds the controller doesn't watch aren't cached. The kinds which aren't installed are skipped.
+func (s *ClusterScope) SupportBundleObjects() ([]unstructured.Unstructured, error) {
+	ctx := context.TODO()
+	objects := []unstructured.Unstructured{}
+	errs := []error{}
+
+	refs := []*corev1.ObjectReference{
+		{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: s.Cluster.Name},
+		{APIVersion: infrav1.GroupVersion.String(), Kind: "AWSCluster", Name: s.AWSCluster.Name},
+	}
+	if s.Cluster.Spec.ControlPlaneRef != nil {
+		refs = append(refs, s.Cluster.Spec.ControlPlaneRef)
+	}
+	for _, ref := range refs {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = s.Namespace()
+		}
+		obj, err := external.Get(ctx, s.client, ref, namespace)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		objects = append(objects, *obj)
+	}
+
+	for _, gvk := range supportBundleKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := s.client.List(ctx, list, client.InNamespace(s.Namespace()), s.ListOptionsLabelSelector()); err != nil {
+			if meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+				continue
+			}
+			errs = append(errs, errors.Wrapf(err, "failed to list %s", gvk.Kind))
+			continue
+		}
+		objects = append(objects, list.Items...)
+	}
+
+	return objects, kerrors.NewAggregate(errs)
+}
+
+// Events returns the events of the namespace of the cluster. They are read as unstructured objects, so that
+// no informer is started for the events.
+func (s *ClusterScope) Events() ([]corev1.Event, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("EventList"))
+	if err := s.client.List(context.TODO(), list, client.InNamespace(s.Namespace())); err != nil {
+		return nil, errors.Wrap(err, "failed to list events")
+	}
+
+	events := make([]corev1.Event, 0, len(list.Items))
+	for _, item := range list.Items {
+		event := corev1.Event{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &event); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert event %s", item.GetName())
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// WriteSupportBundle writes the files of a support bundle to a ConfigMap owned by the cluster, or to a Secret
+// when they are sensitive.
+func (s *ClusterScope) WriteSupportBundle(name string, files map[string]string, sensitive bool) error {
+	objectMeta := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: s.Namespace(),
+		Labels:    map[string]string{clusterv1.ClusterNameLabel: s.Name()},
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: infrav1.GroupVersion.String(),
+			Kind:       "AWSCluster",
+			Name:       s.AWSCluster.Name,
+			UID:        s.AWSCluster.UID,
+		}},
+	}
+
+	var obj client.Object = &corev1.ConfigMap{ObjectMeta: objectMeta, Data: files}
+	if sensitive {
+		data := make(map[string][]byte, len(files))
+		for key, value := range files {
+			data[key] = []byte(value)
+		}
+		obj = &corev1.Secret{ObjectMeta: objectMeta, Type: corev1.SecretTypeOpaque, Data: data}
+	}
+	if err := s.client.Create(context.TODO(), obj); err != nil {
+		return errors.Wrapf(err, "failed to create support bundle %s", name)
+	}
+	return nil
+}
@@ -46,6 +46,9 @@ type EC2Scope interface {
 	// SSHKeyName returns the SSH key name to use for instances.
 	SSHKeyName() *string
 
+	// DefaultInstanceMetadataOptions returns the metadata options of the instances which don't set their own.
+	DefaultInstanceMetadataOptions() *infrav1.InstanceMetadataOptions
+
 	// ImageLookupFormat returns the format string to use when looking up AMIs
 	ImageLookupFormat() string
 
@@ -54,4 +57,11 @@ type EC2Scope interface {
 
 	// ImageLookupBaseOS returns the base operating system name to use when looking up AMIs
 	ImageLookupBaseOS() string
+
+	// DefaultLifecycleHooks returns the lifecycle hooks added to all the machine pools of the cluster.
+	DefaultLifecycleHooks() []infrav1.AWSLifecycleHook
+
+	// ResourcePolicyLifecycleHooks returns the lifecycle hooks of the resource policy of the cluster, which
+	// are overridden by the default lifecycle hooks with the same name.
+	ResourcePolicyLifecycleHooks() []infrav1.AWSLifecycleHook
 }
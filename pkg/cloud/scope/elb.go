@@ -52,7 +52,17 @@ type ELBScope interface {
 	// ControlPlaneEndpoint returns AWSCluster control plane endpoint
 	ControlPlaneEndpoint() clusterv1.APIEndpoint
 
+	// SetControlPlaneEndpoint sets the AWSCluster control plane endpoint.
+	SetControlPlaneEndpoint(endpoint clusterv1.APIEndpoint)
+
 	// ControlPlaneLoadBalancers returns both the ControlPlaneLoadBalancer and SecondaryControlPlaneLoadBalancer AWSLoadBalancerSpecs.
 	// The control plane load balancers should always be returned in the above order.
 	ControlPlaneLoadBalancers() []*infrav1.AWSLoadBalancerSpec
+
+	// ControlPlaneLoadBalancerMigration returns the progress of the migration of the control plane load balancer
+	// from a Classic Load Balancer to a Network Load Balancer, or nil if it isn't migrated.
+	ControlPlaneLoadBalancerMigration() *infrav1.LoadBalancerMigration
+
+	// SetControlPlaneLoadBalancerMigration sets the progress of the migration of the control plane load balancer.
+	SetControlPlaneLoadBalancerMigration(migration *infrav1.LoadBalancerMigration)
 }
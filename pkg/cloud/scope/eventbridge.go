@@ -0,0 +1,37 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+)
+
+// EventBridgeScope is the interface for the scope of a cluster which configures its EventBridge rule
+// and SQS queue, and records their names, to be used with the instance state service.
+type EventBridgeScope interface {
+	EC2Scope
+
+	// EventBridge returns the configuration of the EventBridge rule and SQS queue of the cluster.
+	EventBridge() *infrav1.EventBridgeConfig
+
+	// EventBridgeStatus returns the names of the EventBridge rule and SQS queue of the cluster, nil if
+	// they were not recorded yet.
+	EventBridgeStatus() *infrav1.EventBridgeStatus
+
+	// SetEventBridgeStatus sets the names of the EventBridge rule and SQS queue in the status of the cluster.
+	SetEventBridgeStatus(status *infrav1.EventBridgeStatus)
+}
@@ -37,10 +37,17 @@ type LaunchTemplateScope interface {
 	SetLaunchTemplateIDStatus(id string)
 	GetLaunchTemplateLatestVersionStatus() string
 	SetLaunchTemplateLatestVersionStatus(version string)
+	GetLaunchTemplateChangeStatus() *expinfrav1.LaunchTemplateChange
+	SetLaunchTemplateChangeStatus(change *expinfrav1.LaunchTemplateChange)
 	GetRawBootstrapData() ([]byte, *types.NamespacedName, error)
+	TransformUserData(bootstrapData []byte) ([]byte, error)
 
 	IsEKSManaged() bool
+	GetAMILookupVersion() (*string, error)
+	GetOS() infrav1.OSType
 	AdditionalTags() infrav1.Tags
+	SSHKeyName() *string
+	InstanceMetadataOptions() *infrav1.InstanceMetadataOptions
 
 	GetObjectMeta() *metav1.ObjectMeta
 	GetSetter() conditions.Setter
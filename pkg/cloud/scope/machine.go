@@ -285,6 +285,12 @@ func (m *MachineScope) GetRawBootstrapDataWithFormat() ([]byte, string, error) {
 	return value, string(secret.Data["format"]), nil
 }
 
+// TransformUserData renders the user data transform of the AWSMachine with the bootstrap data. s3URL is the URL
+// of the bootstrap data if it is stored in the S3 bucket of the cluster.
+func (m *MachineScope) TransformUserData(bootstrapData []byte, s3URL string) ([]byte, error) {
+	return transformUserData(context.TODO(), m.client, m.Namespace(), m.AWSMachine.Spec.UserDataTransform, m.Cluster, m.Machine.Labels, bootstrapData, s3URL)
+}
+
 // PatchObject persists the machine spec and status.
 func (m *MachineScope) PatchObject() error {
 	// Always update the readyCondition by summarizing the state of other conditions.
@@ -312,6 +318,11 @@ func (m *MachineScope) PatchObject() error {
 			infrav1.InstanceReadyCondition,
 			infrav1.SecurityGroupsReadyCondition,
 			infrav1.ELBAttachedCondition,
+			infrav1.UserDataDriftedCondition,
+			infrav1.UserDataTransformReadyCondition,
+			infrav1.SSHKeyAvailableCondition,
+			infrav1.AWSRequestsSucceededCondition,
+			infrav1.BootstrapTokenLikelyExpiredCondition,
 		}})
 }
 
@@ -323,16 +334,27 @@ func (m *MachineScope) Close() error {
 // AdditionalTags merges AdditionalTags from the scope's AWSCluster and AWSMachine. If the same key is present in both,
 // the value from AWSMachine takes precedence. The returned Tags will never be nil.
 func (m *MachineScope) AdditionalTags() infrav1.Tags {
-	tags := make(infrav1.Tags)
-
-	// Start with the cluster-wide tags...
-	tags.Merge(m.InfraCluster.AdditionalTags())
-	// ... and merge in the Machine's
-	tags.Merge(m.AWSMachine.Spec.AdditionalTags)
+	tags := resolveAdditionalTags(m.InfraCluster.AdditionalTags(), nil, m.AWSMachine.Spec.AdditionalTags)
+	// Add the operating system of the node.
+	if m.AWSMachine.Spec.OS != "" {
+		tags[infrav1.OSTagKey] = string(m.AWSMachine.Spec.OS)
+	}
 
 	return tags
 }
 
+// SSHKeyName returns the SSH key name of the AWSMachine, or else the one of the AWSCluster. An empty string means
+// that no SSH key is used, and nil that neither set one.
+func (m *MachineScope) SSHKeyName() *string {
+	return resolveSSHKeyName(m.AWSMachine.Spec.SSHKeyName, m.InfraCluster.SSHKeyName())
+}
+
+// InstanceMetadataOptions returns the metadata options of the AWSMachine, or else the default metadata options of
+// the AWSCluster.
+func (m *MachineScope) InstanceMetadataOptions() *infrav1.InstanceMetadataOptions {
+	return resolveInstanceMetadataOptions(m.AWSMachine.Spec.InstanceMetadataOptions, m.InfraCluster.DefaultInstanceMetadataOptions())
+}
+
 // HasFailed returns the failure state of the machine scope.
 func (m *MachineScope) HasFailed() bool {
 	return m.AWSMachine.Status.FailureReason != nil || m.AWSMachine.Status.FailureMessage != nil
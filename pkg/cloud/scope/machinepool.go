@@ -19,6 +19,7 @@ package scope
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -26,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -33,12 +35,14 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
 	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/userdata"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/logger"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	expclusterv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
@@ -54,6 +58,10 @@ type MachinePoolScope struct {
 	MachinePool    *expclusterv1.MachinePool
 	InfraCluster   EC2Scope
 	AWSMachinePool *expinfrav1.AWSMachinePool
+
+	// resolvedSubnetIDs caches the subnets of the Auto Scaling group for the reconciliation, so that the filters
+	// of the subnets are only resolved once.
+	resolvedSubnetIDs []string
 }
 
 // MachinePoolScopeParams defines a scope defined around a machine and its cluster.
@@ -126,17 +134,45 @@ func (m *MachinePoolScope) Name() string {
 	return m.AWSMachinePool.Name
 }
 
+// ASGName returns the name of the Auto Scaling group, which defaults to the AWSMachinePool name.
+func (m *MachinePoolScope) ASGName() string {
+	return m.AWSMachinePool.ASGName()
+}
+
 // Namespace returns the namespace name.
 func (m *MachinePoolScope) Namespace() string {
 	return m.AWSMachinePool.Namespace
 }
 
+// NamespacedName returns the `<namespace>/<name>` of the AWSMachinePool.
+func (m *MachinePoolScope) NamespacedName() string {
+	return client.ObjectKeyFromObject(m.AWSMachinePool).String()
+}
+
 // GetRawBootstrapData returns the bootstrap data from the secret in the Machine's bootstrap.dataSecretName,
-// including the secret's namespaced name.
+// including the secret's namespaced name. The NodeUserDataExtra of the AWSMachinePool is added around it.
 func (m *MachinePoolScope) GetRawBootstrapData() ([]byte, *types.NamespacedName, error) {
-	data, _, bootstrapDataSecretKey, err := m.getBootstrapData()
+	data, format, bootstrapDataSecretKey, err := m.getBootstrapData()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err = userdata.AddNodeUserDataExtra(data, format, m.AWSMachinePool.Spec.NodeUserDataExtra)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to add extra user data for AWSMachinePool %s/%s", m.Namespace(), m.Name())
+	}
+
+	data, err = userdata.ForOS(data, format, m.GetOS())
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to frame user data for AWSMachinePool %s/%s", m.Namespace(), m.Name())
+	}
+
+	return data, bootstrapDataSecretKey, nil
+}
 
-	return data, bootstrapDataSecretKey, err
+// TransformUserData renders the user data transform of the launch template with the bootstrap data.
+func (m *MachinePoolScope) TransformUserData(bootstrapData []byte) ([]byte, error) {
+	return transformUserData(context.TODO(), m.Client, m.Namespace(), m.AWSMachinePool.Spec.AWSLaunchTemplate.UserDataTransform, m.Cluster, m.MachinePool.Spec.Template.Labels, bootstrapData, "")
 }
 
 func (m *MachinePoolScope) getBootstrapData() ([]byte, string, *types.NamespacedName, error) {
@@ -159,19 +195,32 @@ func (m *MachinePoolScope) getBootstrapData() ([]byte, string, *types.Namespaced
 	return value, string(secret.Data["format"]), &key, nil
 }
 
-// AdditionalTags merges AdditionalTags from the scope's AWSCluster and AWSMachinePool. If the same key is present in both,
-// the value from AWSMachinePool takes precedence. The returned Tags will never be nil.
+// AdditionalTags merges AdditionalTags from the scope's AWSCluster, except the ExcludedClusterTags, and AWSMachinePool.
+// If the same key is present in both, the value from AWSMachinePool takes precedence. The returned Tags will never be nil.
 func (m *MachinePoolScope) AdditionalTags() infrav1.Tags {
-	tags := make(infrav1.Tags)
-
-	// Start with the cluster-wide tags...
-	tags.Merge(m.InfraCluster.AdditionalTags())
-	// ... and merge in the Machine's
-	tags.Merge(m.AWSMachinePool.Spec.AdditionalTags)
+	tags := resolveAdditionalTags(m.InfraCluster.AdditionalTags(), m.AWSMachinePool.Spec.ExcludedClusterTags, m.AWSMachinePool.Spec.AdditionalTags)
+	// Add the operating system of the nodes, so that the cluster autoscaler
+	// knows it when scaling the Auto Scaling group from zero.
+	if os := m.GetOS(); os != "" {
+		tags[infrav1.OSTagKey] = string(os)
+		tags[infrav1.ClusterAutoscalerOSLabelTagKey] = string(os)
+	}
 
 	return tags
 }
 
+// SSHKeyName returns the SSH key name of the launch template of the AWSMachinePool, or else the one of the AWSCluster.
+// An empty string means that no SSH key is used, and nil that neither set one.
+func (m *MachinePoolScope) SSHKeyName() *string {
+	return resolveSSHKeyName(m.AWSMachinePool.Spec.AWSLaunchTemplate.SSHKeyName, m.InfraCluster.SSHKeyName())
+}
+
+// InstanceMetadataOptions returns the metadata options of the launch template of the AWSMachinePool, or else the
+// default metadata options of the AWSCluster.
+func (m *MachinePoolScope) InstanceMetadataOptions() *infrav1.InstanceMetadataOptions {
+	return resolveInstanceMetadataOptions(m.AWSMachinePool.Spec.AWSLaunchTemplate.InstanceMetadataOptions, m.InfraCluster.DefaultInstanceMetadataOptions())
+}
+
 // PatchObject persists the machinepool spec and status.
 func (m *MachinePoolScope) PatchObject() error {
 	return m.patchHelper.Patch(
@@ -179,7 +228,21 @@ func (m *MachinePoolScope) PatchObject() error {
 		m.AWSMachinePool,
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			expinfrav1.ASGReadyCondition,
+			expinfrav1.ScalingActivitiesSucceededCondition,
+			expinfrav1.SubnetsReadyCondition,
 			expinfrav1.LaunchTemplateReadyCondition,
+			expinfrav1.InstanceRefreshReadyCondition,
+			expinfrav1.WorkloadClusterReachableCondition,
+			expinfrav1.SpotInterruptionHandlingReadyCondition,
+			expinfrav1.LifecycleHookExistsCondition,
+			expinfrav1.LoadBalancerAttachmentsReadyCondition,
+			expinfrav1.ClassicLoadBalancersReadyCondition,
+			expinfrav1.InstancesInSubnetsCondition,
+			expinfrav1.AvailabilityZonesEvacuatedCondition,
+			expinfrav1.ScaleDownCompletedCondition,
+			infrav1.UserDataTransformReadyCondition,
+			infrav1.AWSRequestsSucceededCondition,
+			infrav1.BootstrapTokenLikelyExpiredCondition,
 		}})
 }
 
@@ -272,11 +335,119 @@ func (m *MachinePoolScope) SetLaunchTemplateLatestVersionStatus(version string)
 	m.AWSMachinePool.Status.LaunchTemplateVersion = &version
 }
 
+// GetLaunchTemplateChangeStatus returns the changes which led to the latest version of the launch template.
+func (m *MachinePoolScope) GetLaunchTemplateChangeStatus() *expinfrav1.LaunchTemplateChange {
+	return m.AWSMachinePool.Status.LastLaunchTemplateChange
+}
+
+// SetLaunchTemplateChangeStatus sets the changes which led to the latest version of the launch template.
+func (m *MachinePoolScope) SetLaunchTemplateChangeStatus(change *expinfrav1.LaunchTemplateChange) {
+	m.AWSMachinePool.Status.LastLaunchTemplateChange = change
+}
+
 // IsEKSManaged checks if the AWSMachinePool is EKS managed.
 func (m *MachinePoolScope) IsEKSManaged() bool {
 	return m.InfraCluster.InfraCluster().GetObjectKind().GroupVersionKind().Kind == ekscontrolplanev1.AWSManagedControlPlaneKind
 }
 
+// GetAMILookupVersion returns the Kubernetes version the AMI of the instances is looked up for: the AMI
+// version override of the AWSMachinePool, or the version of the MachinePool. The override is validated
+// against the version of the control plane of EKS clusters.
+func (m *MachinePoolScope) GetAMILookupVersion() (*string, error) {
+	var controlPlaneVersion *string
+	if controlPlaneScope, ok := m.InfraCluster.(*ManagedControlPlaneScope); ok {
+		controlPlaneVersion = controlPlaneScope.ControlPlane.Spec.Version
+	}
+	return amiLookupVersion(m.AWSMachinePool.Spec.AMIVersionOverride, m.MachinePool.Spec.Template.Spec.Version, controlPlaneVersion)
+}
+
+// IsExternallyManaged checks if the AWSMachinePool is annotated as externally managed, e.g. because
+// its ASG was adopted.
+func (m *MachinePoolScope) IsExternallyManaged() bool {
+	return annotations.IsExternallyManaged(m.AWSMachinePool)
+}
+
+// AutoscalingManagesReplicas returns true if the replicas of the MachinePool are managed by the
+// autoscaling of the AWSMachinePool.
+func (m *MachinePoolScope) AutoscalingManagesReplicas() bool {
+	return autoscalingManagesReplicas(m.MachinePool)
+}
+
+// ManagedSizes returns whether CAPA manages the minimum size, the maximum size and the desired capacity of
+// the Auto Scaling group. The sizes set by the scheduled actions are left to them. The desired capacity is
+// left to the external autoscaler managing the replicas of the MachinePool, and so are the minimum and
+// maximum size, unless they are the bounds of the autoscaling of the AWSMachinePool or
+// spec.syncSizeBoundsWithExternalAutoscaler is set.
+func (m *MachinePoolScope) ManagedSizes() (minSize, maxSize, desiredCapacity bool) {
+	scheduledMinSize, scheduledMaxSize, scheduledDesiredCapacity := m.AWSMachinePool.ScheduledSizes()
+	minSize, maxSize, desiredCapacity = !scheduledMinSize, !scheduledMaxSize, !scheduledDesiredCapacity
+
+	if annotations.ReplicasManagedByExternalAutoscaler(m.MachinePool) {
+		desiredCapacity = false
+		if !m.AutoscalingManagesReplicas() && !m.AWSMachinePool.Spec.SyncSizeBoundsWithExternalAutoscaler {
+			minSize, maxSize = false, false
+		}
+	}
+	return minSize, maxSize, desiredCapacity
+}
+
+// TargetDesiredCapacity returns the desired capacity the Auto Scaling group is scaled to: the replicas of the
+// MachinePool, raised by the surge of the evacuated availability zones within the maximum size.
+func (m *MachinePoolScope) TargetDesiredCapacity() int32 {
+	replicas := ptr.Deref(m.MachinePool.Spec.Replicas, 0)
+	surge := m.AWSMachinePool.EvacuationSurge()
+	if surge == 0 {
+		return replicas
+	}
+	_, maxSize := m.AWSMachinePool.SizeBounds()
+	return max(replicas, min(replicas+surge, maxSize))
+}
+
+// DesiredCapacity returns the desired capacity of the Auto Scaling group when it is managed by CAPA. During a
+// scale-down limited by the scale-down policy, it is the desired capacity of the current step.
+func (m *MachinePoolScope) DesiredCapacity() int32 {
+	if scaleDown := m.AWSMachinePool.Status.ScaleDown; scaleDown != nil {
+		return scaleDown.DesiredCapacity
+	}
+	return m.TargetDesiredCapacity()
+}
+
+// SetAutoscalingManagesReplicas hands the replicas of the MachinePool over to the autoscaling of the
+// AWSMachinePool, or gives them back to the MachinePool, and patches the MachinePool if needed.
+func (m *MachinePoolScope) SetAutoscalingManagesReplicas(ctx context.Context, enabled bool) error {
+	if !setAutoscalingManagesReplicas(m.MachinePool, enabled) {
+		return nil
+	}
+	m.Info("Updating the replicas management of the MachinePool", "autoscaling", enabled)
+	return m.PatchCAPIMachinePoolObject(ctx)
+}
+
+// autoscalingManagesReplicas returns true if the replicas of the MachinePool were handed over to the
+// autoscaling of its infrastructure by CAPA.
+func autoscalingManagesReplicas(machinePool *expclusterv1.MachinePool) bool {
+	return machinePool.GetAnnotations()[clusterv1.ReplicasManagedByAnnotation] == expinfrav1.AutoscalingReplicasManagedBy
+}
+
+// setAutoscalingManagesReplicas sets the replicas-managed-by annotation of the MachinePool when the
+// autoscaling is enabled, unless the replicas are already managed by an external autoscaler, and
+// removes it when the autoscaling is disabled, if it was set by CAPA. It returns true if the
+// annotations changed.
+func setAutoscalingManagesReplicas(machinePool *expclusterv1.MachinePool, enabled bool) bool {
+	switch {
+	case enabled && !annotations.ReplicasManagedByExternalAutoscaler(machinePool):
+		return annotations.AddAnnotations(machinePool, map[string]string{clusterv1.ReplicasManagedByAnnotation: expinfrav1.AutoscalingReplicasManagedBy})
+	case !enabled && autoscalingManagesReplicas(machinePool):
+		delete(machinePool.Annotations, clusterv1.ReplicasManagedByAnnotation)
+		return true
+	}
+	return false
+}
+
+// GetOS returns the operating system of the nodes of the machine pool.
+func (m *MachinePoolScope) GetOS() infrav1.OSType {
+	return m.AWSMachinePool.Spec.OS
+}
+
 // SubnetIDs returns the machine pool subnet IDs.
 func (m *MachinePoolScope) SubnetIDs(subnetIDs []string) ([]string, error) {
 	strategy, err := newDefaultSubnetPlacementStrategy(&m.Logger)
@@ -293,47 +464,83 @@ func (m *MachinePoolScope) SubnetIDs(subnetIDs []string) ([]string, error) {
 	})
 }
 
+// ResolvedSubnetIDs returns the subnets of the Auto Scaling group resolved during the reconciliation, and whether
+// they were resolved.
+func (m *MachinePoolScope) ResolvedSubnetIDs() ([]string, bool) {
+	return m.resolvedSubnetIDs, m.resolvedSubnetIDs != nil
+}
+
+// SetResolvedSubnetIDs caches the subnets of the Auto Scaling group for the rest of the reconciliation.
+func (m *MachinePoolScope) SetResolvedSubnetIDs(subnetIDs []string) {
+	m.resolvedSubnetIDs = subnetIDs
+}
+
 // NodeStatus represents the status of a Kubernetes node.
 type NodeStatus struct {
 	Ready   bool
 	Version string
-}
 
-// UpdateInstanceStatuses ties ASG instances and Node status data together and updates AWSMachinePool
-// This updates if ASG instances ready and kubelet version running on the node..
-func (m *MachinePoolScope) UpdateInstanceStatuses(ctx context.Context, instances []infrav1.Instance) error {
-	providerIDs := make([]string, len(instances))
-	for i, instance := range instances {
-		providerIDs[i] = fmt.Sprintf("aws:////%s", instance.ID)
-	}
+	// ExcludeFromRefresh is true when the node is annotated with the ExcludeFromRefreshAnnotation.
+	ExcludeFromRefresh bool
 
-	nodeStatusByProviderID, err := m.getNodeStatusByProviderID(ctx, providerIDs)
-	if err != nil {
-		return errors.Wrap(err, "failed to get node status by provider id")
+	// ScaleInProtection is true when the node, or its Machine, is annotated with the ScaleInProtectionAnnotation
+	// set to "true", unless the Machine is being deleted.
+	ScaleInProtection bool
+
+	// RegisteredAt is the time the node registered with the workload cluster, or zero if there is no node.
+	RegisteredAt metav1.Time
+}
+
+// UpdateInstanceStatuses updates the statuses of the instances of the AWSMachinePool from the instances of the
+// Auto Scaling group, ordered by instance ID and bounded to MaxInstanceStatuses, and the number of instances which
+// are InService and Healthy. The Kubernetes versions and provisioning times of the instances are kept.
+func (m *MachinePoolScope) UpdateInstanceStatuses(asg *expinfrav1.AutoScalingGroup) {
+	previous := map[string]expinfrav1.AWSMachinePoolInstanceStatus{}
+	for _, instanceStatus := range m.AWSMachinePool.Status.Instances {
+		previous[instanceStatus.InstanceID] = instanceStatus
 	}
 
 	var readyReplicas int32
-	instanceStatuses := make([]expinfrav1.AWSMachinePoolInstanceStatus, len(instances))
-	for i, instance := range instances {
+	instanceStatuses := make([]expinfrav1.AWSMachinePoolInstanceStatus, len(asg.Instances))
+	for i, instance := range asg.Instances {
+		healthStatus := asg.InstanceHealthStatuses[instance.ID]
 		instanceStatuses[i] = expinfrav1.AWSMachinePoolInstanceStatus{
-			InstanceID: instance.ID,
+			InstanceID:            instance.ID,
+			Version:               previous[instance.ID].Version,
+			LaunchedAt:            previous[instance.ID].LaunchedAt,
+			NodeRegisteredAt:      previous[instance.ID].NodeRegisteredAt,
+			LifecycleState:        string(instance.State),
+			HealthStatus:          healthStatus,
+			AvailabilityZone:      instance.AvailabilityZone,
+			LaunchTemplateVersion: asg.InstanceLaunchTemplates[instance.ID].Version,
 		}
-
-		instanceStatus := instanceStatuses[i]
-		if nodeStatus, ok := nodeStatusByProviderID[fmt.Sprintf("aws:////%s", instanceStatus.InstanceID)]; ok {
-			instanceStatus.Version = &nodeStatus.Version
-			if nodeStatus.Ready {
-				readyReplicas++
-			}
+		if instance.State == expinfrav1.InstanceStateInService && healthStatus == expinfrav1.InstanceHealthStatusHealthy {
+			readyReplicas++
 		}
 	}
+	sort.Slice(instanceStatuses, func(i, j int) bool {
+		return instanceStatuses[i].InstanceID < instanceStatuses[j].InstanceID
+	})
+	if len(instanceStatuses) > expinfrav1.MaxInstanceStatuses {
+		instanceStatuses = instanceStatuses[:expinfrav1.MaxInstanceStatuses]
+	}
 
-	// TODO: readyReplicas can be used as status.replicas but this will delay machinepool to become ready. next reconcile updates this.
 	m.AWSMachinePool.Status.Instances = instanceStatuses
-	return nil
+	m.AWSMachinePool.Status.ReadyReplicas = readyReplicas
 }
 
-func (m *MachinePoolScope) getNodeStatusByProviderID(ctx context.Context, providerIDList []string) (map[string]*NodeStatus, error) {
+// UpdateInstanceVersions records, in the statuses of the instances, the kubelet version of their Nodes.
+func (m *MachinePoolScope) UpdateInstanceVersions(nodeStatusByProviderID map[string]*NodeStatus) {
+	for i := range m.AWSMachinePool.Status.Instances {
+		instanceStatus := &m.AWSMachinePool.Status.Instances[i]
+		if nodeStatus, ok := nodeStatusByProviderID[fmt.Sprintf("aws:////%s", instanceStatus.InstanceID)]; ok && nodeStatus.Version != "" {
+			instanceStatus.Version = ptr.To(nodeStatus.Version)
+		}
+	}
+}
+
+// GetNodeStatusByProviderID returns the status of the workload cluster Nodes, keyed by the given provider IDs.
+func (m *MachinePoolScope) GetNodeStatusByProviderID(ctx context.Context, providerIDList []string) (map[string]*NodeStatus, error) {
 	nodeStatusMap := map[string]*NodeStatus{}
 	for _, id := range providerIDList {
 		nodeStatusMap[id] = &NodeStatus{}
@@ -356,6 +563,9 @@ func (m *MachinePoolScope) getNodeStatusByProviderID(ctx context.Context, provid
 			if status, ok := nodeStatusMap[fmt.Sprintf("aws:////%s", strList[len(strList)-1])]; ok {
 				status.Ready = nodeIsReady(node)
 				status.Version = node.Status.NodeInfo.KubeletVersion
+				_, status.ExcludeFromRefresh = node.Annotations[expinfrav1.ExcludeFromRefreshAnnotation]
+				status.ScaleInProtection = node.Annotations[expinfrav1.ScaleInProtectionAnnotation] == "true"
+				status.RegisteredAt = node.CreationTimestamp
 			}
 		}
 
@@ -367,6 +577,86 @@ func (m *MachinePoolScope) getNodeStatusByProviderID(ctx context.Context, provid
 	return nodeStatusMap, nil
 }
 
+// UpdateMachineScaleInProtection updates the scale-in protection of the node statuses from the Machines of the
+// cluster with the same provider ID. A Machine annotated with the ScaleInProtectionAnnotation set to "true"
+// protects its instance, and a Machine being deleted removes the protection whatever the annotations.
+func (m *MachinePoolScope) UpdateMachineScaleInProtection(ctx context.Context, nodeStatusByProviderID map[string]*NodeStatus) error {
+	machines, err := m.listMachines(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, machine := range machines {
+		if machine.Spec.ProviderID == nil {
+			continue
+		}
+		status, ok := nodeStatusByProviderID[fmt.Sprintf("aws:////%s", instanceIDFromProviderID(*machine.Spec.ProviderID))]
+		if !ok {
+			continue
+		}
+		switch {
+		case !machine.DeletionTimestamp.IsZero():
+			status.ScaleInProtection = false
+		case machine.Annotations[expinfrav1.ScaleInProtectionAnnotation] == "true":
+			status.ScaleInProtection = true
+		}
+	}
+
+	return nil
+}
+
+// InstancesToDetach returns the sorted IDs of the instances of the Auto Scaling group to detach: the instances
+// in service or on standby which are listed by the DetachInstancesAnnotation of the AWSMachinePool, or whose
+// Machine is being deleted and is annotated with the DetachInstanceAnnotation set to "true".
+func (m *MachinePoolScope) InstancesToDetach(ctx context.Context, instances []infrav1.Instance) ([]string, error) {
+	requested := sets.New[string]()
+	for _, id := range strings.Split(m.AWSMachinePool.GetAnnotations()[expinfrav1.DetachInstancesAnnotation], ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			requested.Insert(id)
+		}
+	}
+
+	machines, err := m.listMachines(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, machine := range machines {
+		if machine.Spec.ProviderID == nil || machine.DeletionTimestamp.IsZero() || machine.Annotations[expinfrav1.DetachInstanceAnnotation] != "true" {
+			continue
+		}
+		requested.Insert(instanceIDFromProviderID(*machine.Spec.ProviderID))
+	}
+
+	res := sets.New[string]()
+	for _, instance := range instances {
+		if !requested.Has(instance.ID) {
+			continue
+		}
+		// Only instances in service or on standby can be detached.
+		if instance.State != expinfrav1.InstanceStateInService && instance.State != expinfrav1.InstanceStateStandby {
+			continue
+		}
+		res.Insert(instance.ID)
+	}
+
+	return sets.List(res), nil
+}
+
+// listMachines lists the Machines of the cluster.
+func (m *MachinePoolScope) listMachines(ctx context.Context) ([]clusterv1.Machine, error) {
+	machines := &clusterv1.MachineList{}
+	if err := m.Client.List(ctx, machines, client.InNamespace(m.Cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: m.Cluster.Name}); err != nil {
+		return nil, errors.Wrap(err, "failed to list Machines")
+	}
+	return machines.Items, nil
+}
+
+// instanceIDFromProviderID returns the instance ID of a provider ID, e.g. i-0123 for aws:///us-east-1a/i-0123.
+func instanceIDFromProviderID(providerID string) string {
+	strList := strings.Split(providerID, "/")
+	return strList[len(strList)-1]
+}
+
 func nodeIsReady(node corev1.Node) bool {
 	for _, n := range node.Status.Conditions {
 		if n.Type == corev1.NodeReady {
@@ -376,6 +666,75 @@ func nodeIsReady(node corev1.Node) bool {
 	return false
 }
 
+// GetLifecycleHooks returns the lifecycle hooks of the Auto Scaling group: the default lifecycle hooks
+// of the cluster, overridden by the lifecycle hooks of the AWSMachinePool with the same name, followed
+// by the managed launch lifecycle hook when it is enabled.
+func (m *MachinePoolScope) GetLifecycleHooks() []infrav1.AWSLifecycleHook {
+	hooks, _ := m.lifecycleHooks()
+	return hooks
+}
+
+// SetLifecycleHooksStatus sets the lifecycle hooks of the Auto Scaling group in the AWSMachinePool status,
+// along with where their spec comes from.
+func (m *MachinePoolScope) SetLifecycleHooksStatus() {
+	hooks, sources := m.lifecycleHooks()
+	var statuses []expinfrav1.LifecycleHookStatus
+	for i, hook := range hooks {
+		statuses = append(statuses, expinfrav1.LifecycleHookStatus{
+			Name:                hook.Name,
+			LifecycleTransition: hook.LifecycleTransition,
+			Source:              sources[i],
+		})
+	}
+	m.AWSMachinePool.Status.LifecycleHooks = statuses
+	m.AWSMachinePool.Status.LifecycleHookCount = int32(len(statuses))
+}
+
+// lifecycleHooks returns the lifecycle hooks of the Auto Scaling group and the source of each of them.
+func (m *MachinePoolScope) lifecycleHooks() ([]infrav1.AWSLifecycleHook, []expinfrav1.LifecycleHookSource) {
+	poolHooks := m.AWSMachinePool.Spec.AWSLifecycleHooks
+	managedHook := m.AWSMachinePool.Spec.ManagedLaunchLifecycleHook
+
+	overridden := sets.New[string]()
+	for _, hook := range poolHooks {
+		overridden.Insert(hook.Name)
+	}
+	if managedHook.IsEnabled() {
+		overridden.Insert(expinfrav1.ManagedLaunchLifecycleHookName)
+	}
+
+	var hooks []infrav1.AWSLifecycleHook
+	var sources []expinfrav1.LifecycleHookSource
+	if m.InfraCluster != nil {
+		defaultHooks := m.InfraCluster.DefaultLifecycleHooks()
+		policyOverridden := overridden.Clone()
+		for _, hook := range defaultHooks {
+			policyOverridden.Insert(hook.Name)
+		}
+		for _, hook := range m.InfraCluster.ResourcePolicyLifecycleHooks() {
+			if !policyOverridden.Has(hook.Name) {
+				hooks = append(hooks, hook)
+				sources = append(sources, expinfrav1.LifecycleHookSourceResourcePolicy)
+			}
+		}
+		for _, hook := range defaultHooks {
+			if !overridden.Has(hook.Name) {
+				hooks = append(hooks, hook)
+				sources = append(sources, expinfrav1.LifecycleHookSourceCluster)
+			}
+		}
+	}
+	for _, hook := range poolHooks {
+		hooks = append(hooks, hook)
+		sources = append(sources, expinfrav1.LifecycleHookSourceMachinePool)
+	}
+	if managedHook.IsEnabled() {
+		hooks = append(hooks, managedHook.LifecycleHook())
+		sources = append(sources, expinfrav1.LifecycleHookSourceManaged)
+	}
+	return hooks, sources
+}
+
 // GetLaunchTemplate returns the launch template.
 func (m *MachinePoolScope) GetLaunchTemplate() *expinfrav1.AWSLaunchTemplate {
 	return &m.AWSMachinePool.Spec.AWSLaunchTemplate
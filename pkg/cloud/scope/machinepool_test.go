@@ -0,0 +1,527 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expclusterv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+func TestMachinePoolScopeGetLifecycleHooks(t *testing.T) {
+	drain := infrav1.AWSLifecycleHook{Name: "drain", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate}
+	poolDrain := infrav1.AWSLifecycleHook{Name: "drain", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate, Priority: 10}
+	warmup := infrav1.AWSLifecycleHook{Name: "warmup", LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch}
+	managed := infrav1.AWSLifecycleHook{Name: expinfrav1.ManagedLaunchLifecycleHookName, LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch}
+	managedHook := &expinfrav1.ManagedLaunchLifecycleHook{Enabled: true}
+
+	tests := []struct {
+		name         string
+		policyHooks  []infrav1.AWSLifecycleHook
+		defaultHooks []infrav1.AWSLifecycleHook
+		spec         expinfrav1.AWSMachinePoolSpec
+		want         []infrav1.AWSLifecycleHook
+		wantStatus   []expinfrav1.LifecycleHookStatus
+	}{
+		{
+			name: "no lifecycle hooks",
+		},
+		{
+			name:         "default lifecycle hooks are added to the pool",
+			defaultHooks: []infrav1.AWSLifecycleHook{drain},
+			spec:         expinfrav1.AWSMachinePoolSpec{AWSLifecycleHooks: []infrav1.AWSLifecycleHook{warmup}},
+			want:         []infrav1.AWSLifecycleHook{drain, warmup},
+			wantStatus: []expinfrav1.LifecycleHookStatus{
+				{Name: "drain", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate, Source: expinfrav1.LifecycleHookSourceCluster},
+				{Name: "warmup", LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch, Source: expinfrav1.LifecycleHookSourceMachinePool},
+			},
+		},
+		{
+			name:         "pool lifecycle hooks override default lifecycle hooks with the same name",
+			defaultHooks: []infrav1.AWSLifecycleHook{drain},
+			spec:         expinfrav1.AWSMachinePoolSpec{AWSLifecycleHooks: []infrav1.AWSLifecycleHook{poolDrain}},
+			want:         []infrav1.AWSLifecycleHook{poolDrain},
+			wantStatus: []expinfrav1.LifecycleHookStatus{
+				{Name: "drain", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate, Source: expinfrav1.LifecycleHookSourceMachinePool},
+			},
+		},
+		{
+			name:         "default and pool lifecycle hooks override resource policy lifecycle hooks with the same name",
+			policyHooks:  []infrav1.AWSLifecycleHook{drain, warmup, {Name: "audit", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate}},
+			defaultHooks: []infrav1.AWSLifecycleHook{drain},
+			spec:         expinfrav1.AWSMachinePoolSpec{AWSLifecycleHooks: []infrav1.AWSLifecycleHook{warmup}},
+			want: []infrav1.AWSLifecycleHook{
+				{Name: "audit", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate},
+				drain,
+				warmup,
+			},
+			wantStatus: []expinfrav1.LifecycleHookStatus{
+				{Name: "audit", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate, Source: expinfrav1.LifecycleHookSourceResourcePolicy},
+				{Name: "drain", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate, Source: expinfrav1.LifecycleHookSourceCluster},
+				{Name: "warmup", LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch, Source: expinfrav1.LifecycleHookSourceMachinePool},
+			},
+		},
+		{
+			name:         "the managed launch lifecycle hook overrides a default lifecycle hook with the same name",
+			defaultHooks: []infrav1.AWSLifecycleHook{drain, managed},
+			spec:         expinfrav1.AWSMachinePoolSpec{ManagedLaunchLifecycleHook: managedHook},
+			want:         []infrav1.AWSLifecycleHook{drain, managedHook.LifecycleHook()},
+			wantStatus: []expinfrav1.LifecycleHookStatus{
+				{Name: "drain", LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate, Source: expinfrav1.LifecycleHookSourceCluster},
+				{Name: expinfrav1.ManagedLaunchLifecycleHookName, LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch, Source: expinfrav1.LifecycleHookSourceManaged},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			m := &MachinePoolScope{
+				InfraCluster: &ClusterScope{
+					AWSCluster: &infrav1.AWSCluster{
+						Spec: infrav1.AWSClusterSpec{DefaultLifecycleHooks: tt.defaultHooks},
+					},
+					resourcePolicy: &ResourcePolicy{LifecycleHooks: tt.policyHooks},
+				},
+				AWSMachinePool: &expinfrav1.AWSMachinePool{Spec: tt.spec},
+			}
+
+			g.Expect(m.GetLifecycleHooks()).To(Equal(tt.want))
+			m.SetLifecycleHooksStatus()
+			g.Expect(m.AWSMachinePool.Status.LifecycleHooks).To(Equal(tt.wantStatus))
+			g.Expect(m.AWSMachinePool.Status.LifecycleHookCount).To(BeEquivalentTo(len(tt.wantStatus)))
+		})
+	}
+}
+
+func TestMachinePoolScopeGetRawBootstrapData(t *testing.T) {
+	tests := []struct {
+		name    string
+		os      infrav1.OSType
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "linux bootstrap data is returned unchanged",
+			os:     infrav1.OSTypeLinux,
+			format: "cloud-config",
+			want:   "#cloud-config\nruncmd:\n- kubeadm join\n",
+		},
+		{
+			name: "bootstrap data is returned unchanged when the operating system is not set",
+			want: "#cloud-config\nruncmd:\n- kubeadm join\n",
+		},
+		{
+			name: "windows bootstrap data is wrapped in powershell tags",
+			os:   infrav1.OSTypeWindows,
+			want: "<powershell>\n#cloud-config\nruncmd:\n- kubeadm join\n</powershell>",
+		},
+		{
+			name:    "windows rejects ignition bootstrap data",
+			os:      infrav1.OSTypeWindows,
+			format:  "ignition",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme, err := setupScheme()
+			g.Expect(err).NotTo(HaveOccurred())
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-data", Namespace: "default"},
+				Data: map[string][]byte{
+					"value":  []byte("#cloud-config\nruncmd:\n- kubeadm join\n"),
+					"format": []byte(tt.format),
+				},
+			}
+
+			m := &MachinePoolScope{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+				MachinePool: &expclusterv1.MachinePool{
+					Spec: expclusterv1.MachinePoolSpec{
+						Template: clusterv1.MachineTemplateSpec{
+							Spec: clusterv1.MachineSpec{
+								Bootstrap: clusterv1.Bootstrap{DataSecretName: ptr.To("bootstrap-data")},
+							},
+						},
+					},
+				},
+				AWSMachinePool: &expinfrav1.AWSMachinePool{
+					ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default"},
+					Spec:       expinfrav1.AWSMachinePoolSpec{OS: tt.os},
+				},
+			}
+
+			data, secretKey, err := m.GetRawBootstrapData()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(data)).To(Equal(tt.want))
+			g.Expect(secretKey.Name).To(Equal("bootstrap-data"))
+		})
+	}
+}
+
+func TestMachinePoolScopeAdditionalTags(t *testing.T) {
+	g := NewWithT(t)
+
+	m := &MachinePoolScope{
+		InfraCluster: &ClusterScope{
+			AWSCluster: &infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{AdditionalTags: infrav1.Tags{"team": "infra"}},
+			},
+			resourcePolicy: &ResourcePolicy{AdditionalTags: infrav1.Tags{"team": "platform", "cost-center": "1234"}},
+		},
+		AWSMachinePool: &expinfrav1.AWSMachinePool{
+			Spec: expinfrav1.AWSMachinePoolSpec{OS: infrav1.OSTypeWindows},
+		},
+	}
+
+	g.Expect(m.AdditionalTags()).To(Equal(infrav1.Tags{
+		"team":                                 "infra",
+		"cost-center":                          "1234",
+		infrav1.OSTagKey:                       "windows",
+		infrav1.ClusterAutoscalerOSLabelTagKey: "windows",
+	}))
+}
+
+func TestSetAutoscalingManagesReplicas(t *testing.T) {
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		enabled         bool
+		wantChanged     bool
+		wantAnnotations map[string]string
+	}{
+		{
+			name:            "enabling hands the replicas over to the autoscaling",
+			enabled:         true,
+			wantChanged:     true,
+			wantAnnotations: map[string]string{clusterv1.ReplicasManagedByAnnotation: expinfrav1.AutoscalingReplicasManagedBy},
+		},
+		{
+			name:            "enabling keeps the replicas managed by an external autoscaler",
+			annotations:     map[string]string{clusterv1.ReplicasManagedByAnnotation: "rosa"},
+			enabled:         true,
+			wantAnnotations: map[string]string{clusterv1.ReplicasManagedByAnnotation: "rosa"},
+		},
+		{
+			name:            "disabling gives the replicas back to the MachinePool",
+			annotations:     map[string]string{clusterv1.ReplicasManagedByAnnotation: expinfrav1.AutoscalingReplicasManagedBy, "other": "value"},
+			wantChanged:     true,
+			wantAnnotations: map[string]string{"other": "value"},
+		},
+		{
+			name:            "disabling keeps the replicas managed by an external autoscaler",
+			annotations:     map[string]string{clusterv1.ReplicasManagedByAnnotation: ""},
+			wantAnnotations: map[string]string{clusterv1.ReplicasManagedByAnnotation: ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machinePool := &expclusterv1.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+			}
+			g.Expect(setAutoscalingManagesReplicas(machinePool, tt.enabled)).To(Equal(tt.wantChanged))
+			g.Expect(machinePool.Annotations).To(Equal(tt.wantAnnotations))
+			g.Expect(autoscalingManagesReplicas(machinePool)).To(Equal(tt.enabled && tt.wantChanged))
+		})
+	}
+}
+
+func TestMachinePoolScopeUpdateMachineScaleInProtection(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	machine := func(name, cluster, providerID string, annotations map[string]string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Labels:      map[string]string{clusterv1.ClusterNameLabel: cluster},
+				Annotations: annotations,
+			},
+			Spec: clusterv1.MachineSpec{ClusterName: cluster, ProviderID: ptr.To(providerID)},
+		}
+	}
+	protect := map[string]string{expinfrav1.ScaleInProtectionAnnotation: "true"}
+	deleting := machine("deleting", "test", "aws:///us-east-1a/i-3", protect)
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	deleting.Finalizers = []string{clusterv1.MachineFinalizer}
+
+	m := &MachinePoolScope{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			machine("annotated", "test", "aws:///us-east-1a/i-1", protect),
+			machine("not-true", "test", "aws:///us-east-1a/i-2", map[string]string{expinfrav1.ScaleInProtectionAnnotation: "false"}),
+			deleting,
+			machine("other-cluster", "other", "aws:///us-east-1a/i-4", protect),
+		).Build(),
+		Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}},
+	}
+
+	// The annotations of the Nodes are already set in the node statuses.
+	nodeStatusByProviderID := map[string]*NodeStatus{
+		"aws:////i-1": {},
+		"aws:////i-2": {ScaleInProtection: true},
+		"aws:////i-3": {ScaleInProtection: true},
+		"aws:////i-4": {},
+	}
+	g.Expect(m.UpdateMachineScaleInProtection(context.Background(), nodeStatusByProviderID)).To(Succeed())
+	g.Expect(nodeStatusByProviderID["aws:////i-1"].ScaleInProtection).To(BeTrue())
+	g.Expect(nodeStatusByProviderID["aws:////i-2"].ScaleInProtection).To(BeTrue())
+	g.Expect(nodeStatusByProviderID["aws:////i-3"].ScaleInProtection).To(BeFalse())
+	g.Expect(nodeStatusByProviderID["aws:////i-4"].ScaleInProtection).To(BeFalse())
+}
+
+func TestMachinePoolScopeInstancesToDetach(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	machine := func(name, providerID string, deleting bool, annotations map[string]string) *clusterv1.Machine {
+		machine := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Labels:      map[string]string{clusterv1.ClusterNameLabel: "test"},
+				Annotations: annotations,
+			},
+			Spec: clusterv1.MachineSpec{ClusterName: "test", ProviderID: ptr.To(providerID)},
+		}
+		if deleting {
+			machine.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			machine.Finalizers = []string{clusterv1.MachineFinalizer}
+		}
+		return machine
+	}
+	detach := map[string]string{expinfrav1.DetachInstanceAnnotation: "true"}
+
+	m := &MachinePoolScope{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			machine("detached", "aws:///us-east-1a/i-1", true, detach),
+			machine("standby", "aws:///us-east-1a/i-2", true, detach),
+			machine("not-deleting", "aws:///us-east-1a/i-3", false, detach),
+			machine("not-annotated", "aws:///us-east-1a/i-4", true, nil),
+			machine("terminating", "aws:///us-east-1a/i-5", true, detach),
+		).Build(),
+		Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}},
+		AWSMachinePool: &expinfrav1.AWSMachinePool{ObjectMeta: metav1.ObjectMeta{
+			Name:        "test",
+			Annotations: map[string]string{expinfrav1.DetachInstancesAnnotation: "i-7, i-8,i-9"},
+		}},
+	}
+
+	instances := []infrav1.Instance{
+		{ID: "i-1", State: "InService"},
+		{ID: "i-2", State: "Standby"},
+		{ID: "i-3", State: "InService"},
+		{ID: "i-4", State: "InService"},
+		{ID: "i-5", State: "Terminating"},
+		{ID: "i-6", State: "InService"},
+		{ID: "i-7", State: "InService"},
+		{ID: "i-8", State: "Pending"},
+	}
+	got, err := m.InstancesToDetach(context.Background(), instances)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(Equal([]string{"i-1", "i-2", "i-7"}))
+}
+
+func TestMachinePoolScopeManagedSizes(t *testing.T) {
+	minSize := int32(1)
+
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		spec            expinfrav1.AWSMachinePoolSpec
+		wantMinSize     bool
+		wantMaxSize     bool
+		wantDesiredSize bool
+	}{
+		{
+			name:            "all sizes are managed",
+			wantMinSize:     true,
+			wantMaxSize:     true,
+			wantDesiredSize: true,
+		},
+		{
+			name:        "external autoscaler manages all sizes",
+			annotations: map[string]string{clusterv1.ReplicasManagedByAnnotation: "cluster-autoscaler"},
+		},
+		{
+			name:        "external autoscaler manages the desired capacity when the size bounds are synced",
+			annotations: map[string]string{clusterv1.ReplicasManagedByAnnotation: "cluster-autoscaler"},
+			spec:        expinfrav1.AWSMachinePoolSpec{SyncSizeBoundsWithExternalAutoscaler: true},
+			wantMinSize: true,
+			wantMaxSize: true,
+		},
+		{
+			name:        "autoscaling of the machine pool manages the desired capacity",
+			annotations: map[string]string{clusterv1.ReplicasManagedByAnnotation: expinfrav1.AutoscalingReplicasManagedBy},
+			spec:        expinfrav1.AWSMachinePoolSpec{Autoscaling: &expinfrav1.MachinePoolAutoscaling{Enabled: true, MinSize: 1, MaxSize: 5}},
+			wantMinSize: true,
+			wantMaxSize: true,
+		},
+		{
+			name:        "scheduled actions manage the sizes they set and the desired capacity",
+			spec:        expinfrav1.AWSMachinePoolSpec{ScheduledActions: []expinfrav1.ScheduledAction{{Name: "night", MinSize: &minSize}}},
+			wantMaxSize: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &MachinePoolScope{
+				MachinePool:    &expclusterv1.MachinePool{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}},
+				AWSMachinePool: &expinfrav1.AWSMachinePool{Spec: tt.spec},
+			}
+			gotMinSize, gotMaxSize, gotDesiredSize := m.ManagedSizes()
+			g.Expect(gotMinSize).To(Equal(tt.wantMinSize))
+			g.Expect(gotMaxSize).To(Equal(tt.wantMaxSize))
+			g.Expect(gotDesiredSize).To(Equal(tt.wantDesiredSize))
+		})
+	}
+}
+
+func TestMachinePoolScopeDesiredCapacity(t *testing.T) {
+	tests := []struct {
+		name   string
+		status expinfrav1.AWSMachinePoolStatus
+		want   int32
+	}{
+		{
+			name: "replicas",
+			want: 3,
+		},
+		{
+			name: "replicas raised by the surge of the evacuated availability zones",
+			status: expinfrav1.AWSMachinePoolStatus{EvacuatedAvailabilityZones: []expinfrav1.EvacuatedAvailabilityZone{
+				{Name: "us-east-1a", Surge: 1},
+				{Name: "us-east-1b", Surge: 1},
+			}},
+			want: 5,
+		},
+		{
+			name: "surge within the maximum size",
+			status: expinfrav1.AWSMachinePoolStatus{EvacuatedAvailabilityZones: []expinfrav1.EvacuatedAvailabilityZone{
+				{Name: "us-east-1a", Surge: 4},
+			}},
+			want: 6,
+		},
+		{
+			name:   "current step of a scale-down",
+			status: expinfrav1.AWSMachinePoolStatus{ScaleDown: &expinfrav1.ScaleDownStatus{DesiredCapacity: 4}},
+			want:   4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &MachinePoolScope{
+				MachinePool: &expclusterv1.MachinePool{Spec: expclusterv1.MachinePoolSpec{Replicas: ptr.To[int32](3)}},
+				AWSMachinePool: &expinfrav1.AWSMachinePool{
+					Spec:   expinfrav1.AWSMachinePoolSpec{MinSize: 1, MaxSize: 6},
+					Status: tt.status,
+				},
+			}
+			g.Expect(m.DesiredCapacity()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachinePoolScopeUpdateInstanceStatuses(t *testing.T) {
+	g := NewWithT(t)
+
+	launchedAt := metav1.NewTime(time.Now().Truncate(time.Second))
+	m := &MachinePoolScope{
+		AWSMachinePool: &expinfrav1.AWSMachinePool{
+			Status: expinfrav1.AWSMachinePoolStatus{
+				Instances: []expinfrav1.AWSMachinePoolInstanceStatus{
+					{InstanceID: "i-2", Version: ptr.To("v1.29.0"), LaunchedAt: &launchedAt},
+					{InstanceID: "i-9"},
+				},
+			},
+		},
+	}
+	asg := &expinfrav1.AutoScalingGroup{
+		Instances: []infrav1.Instance{
+			{ID: "i-3", State: expinfrav1.InstanceStatePending, AvailabilityZone: "us-east-1a"},
+			{ID: "i-1", State: expinfrav1.InstanceStateInService, AvailabilityZone: "us-east-1b"},
+			{ID: "i-2", State: expinfrav1.InstanceStateInService, AvailabilityZone: "us-east-1a"},
+			{ID: "i-4", State: expinfrav1.InstanceStateInService, AvailabilityZone: "us-east-1a"},
+		},
+		InstanceHealthStatuses: map[string]string{
+			"i-1": expinfrav1.InstanceHealthStatusHealthy,
+			"i-2": expinfrav1.InstanceHealthStatusHealthy,
+			"i-3": expinfrav1.InstanceHealthStatusHealthy,
+			"i-4": expinfrav1.InstanceHealthStatusUnhealthy,
+		},
+		InstanceLaunchTemplates: map[string]expinfrav1.InstanceLaunchTemplate{
+			"i-2": {ID: "lt-1", Version: "2"},
+		},
+	}
+
+	m.UpdateInstanceStatuses(asg)
+	// The statuses are ordered by instance ID, and the ones of the instances which left the group are removed.
+	g.Expect(m.AWSMachinePool.Status.Instances).To(Equal([]expinfrav1.AWSMachinePoolInstanceStatus{
+		{InstanceID: "i-1", LifecycleState: "InService", HealthStatus: "Healthy", AvailabilityZone: "us-east-1b"},
+		{InstanceID: "i-2", Version: ptr.To("v1.29.0"), LaunchedAt: &launchedAt, LifecycleState: "InService", HealthStatus: "Healthy", AvailabilityZone: "us-east-1a", LaunchTemplateVersion: "2"},
+		{InstanceID: "i-3", LifecycleState: "Pending", HealthStatus: "Healthy", AvailabilityZone: "us-east-1a"},
+		{InstanceID: "i-4", LifecycleState: "InService", HealthStatus: "Unhealthy", AvailabilityZone: "us-east-1a"},
+	}))
+	// Only the instances which are InService and Healthy are ready.
+	g.Expect(m.AWSMachinePool.Status.ReadyReplicas).To(Equal(int32(2)))
+
+	m.UpdateInstanceVersions(map[string]*NodeStatus{
+		"aws:////i-1": {Version: "v1.30.0"},
+		"aws:////i-3": {},
+	})
+	g.Expect(m.AWSMachinePool.Status.Instances[0].Version).To(HaveValue(Equal("v1.30.0")))
+	g.Expect(m.AWSMachinePool.Status.Instances[1].Version).To(HaveValue(Equal("v1.29.0")))
+	g.Expect(m.AWSMachinePool.Status.Instances[2].Version).To(BeNil())
+
+	// The number of statuses is bounded.
+	asg.Instances = make([]infrav1.Instance, expinfrav1.MaxInstanceStatuses+1)
+	for i := range asg.Instances {
+		asg.Instances[i] = infrav1.Instance{ID: fmt.Sprintf("i-%04d", expinfrav1.MaxInstanceStatuses-i), State: expinfrav1.InstanceStateInService}
+	}
+	m.UpdateInstanceStatuses(asg)
+	g.Expect(m.AWSMachinePool.Status.Instances).To(HaveLen(expinfrav1.MaxInstanceStatuses))
+	g.Expect(m.AWSMachinePool.Status.Instances[0].InstanceID).To(Equal("i-0000"))
+}
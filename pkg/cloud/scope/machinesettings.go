@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+)
+
+// The settings of the instances of a cluster, which are launched for AWSMachines or from the launch templates of
+// AWSMachinePools, are resolved by the helpers below, so that both resolve them the same way: the setting of the
+// AWSMachine or of the AWSMachinePool takes precedence over the cluster-wide default.
+
+// resolveSSHKeyName returns the SSH key name of the machine, or else the one of the cluster. An empty string means
+// that no SSH key is used, and nil that neither the machine nor the cluster set one.
+func resolveSSHKeyName(machine, cluster *string) *string {
+	if machine != nil {
+		return machine
+	}
+	return cluster
+}
+
+// resolveInstanceMetadataOptions returns the metadata options of the machine, or else the default metadata options
+// of the cluster. The options aren't merged field by field, as the unset fields are defaulted by the API server.
+func resolveInstanceMetadataOptions(machine, cluster *infrav1.InstanceMetadataOptions) *infrav1.InstanceMetadataOptions {
+	if machine != nil {
+		return machine
+	}
+	return cluster
+}
+
+// resolveAdditionalTags merges the additional tags of the cluster, except the excluded keys, with the ones of the
+// machine, which take precedence. The returned Tags will never be nil.
+func resolveAdditionalTags(cluster infrav1.Tags, excludedClusterTags []string, machine infrav1.Tags) infrav1.Tags {
+	tags := make(infrav1.Tags)
+
+	// Start with the cluster-wide tags...
+	tags.Merge(cluster)
+	for _, key := range excludedClusterTags {
+		delete(tags, key)
+	}
+	// ... and merge in the machine's.
+	tags.Merge(machine)
+
+	return tags
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+)
+
+func TestResolveAdditionalTags(t *testing.T) {
+	tests := []struct {
+		name                string
+		cluster             infrav1.Tags
+		excludedClusterTags []string
+		machine             infrav1.Tags
+		want                infrav1.Tags
+	}{
+		{
+			name: "no tags",
+			want: infrav1.Tags{},
+		},
+		{
+			name:    "the tags of the machine take precedence over the ones of the cluster",
+			cluster: infrav1.Tags{"team": "platform", "env": "prod"},
+			machine: infrav1.Tags{"team": "batch"},
+			want:    infrav1.Tags{"team": "batch", "env": "prod"},
+		},
+		{
+			name:                "the excluded tags of the cluster are dropped",
+			cluster:             infrav1.Tags{"team": "platform", "env": "prod"},
+			excludedClusterTags: []string{"env", "unknown"},
+			machine:             infrav1.Tags{"pool": "spot"},
+			want:                infrav1.Tags{"team": "platform", "pool": "spot"},
+		},
+		{
+			name:                "an excluded tag of the cluster may still be set by the machine",
+			cluster:             infrav1.Tags{"env": "prod"},
+			excludedClusterTags: []string{"env"},
+			machine:             infrav1.Tags{"env": "dev"},
+			want:                infrav1.Tags{"env": "dev"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(resolveAdditionalTags(tt.cluster, tt.excludedClusterTags, tt.machine)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestResolveSSHKeyName(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(resolveSSHKeyName(nil, nil)).To(BeNil())
+	g.Expect(resolveSSHKeyName(nil, ptr.To("cluster"))).To(Equal(ptr.To("cluster")))
+	g.Expect(resolveSSHKeyName(ptr.To("machine"), ptr.To("cluster"))).To(Equal(ptr.To("machine")))
+	g.Expect(resolveSSHKeyName(ptr.To(""), ptr.To("cluster"))).To(Equal(ptr.To("")))
+}
+
+func TestResolveInstanceMetadataOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &infrav1.InstanceMetadataOptions{HTTPTokens: infrav1.HTTPTokensStateRequired}
+	machine := &infrav1.InstanceMetadataOptions{HTTPTokens: infrav1.HTTPTokensStateOptional}
+
+	g.Expect(resolveInstanceMetadataOptions(nil, nil)).To(BeNil())
+	g.Expect(resolveInstanceMetadataOptions(nil, cluster)).To(Equal(cluster))
+	g.Expect(resolveInstanceMetadataOptions(machine, cluster)).To(Equal(machine))
+}
@@ -269,11 +269,11 @@ func (s *ManagedControlPlaneScope) Close() error {
 
 // AdditionalTags returns AdditionalTags from the scope's EksControlPlane. The returned value will never be nil.
 func (s *ManagedControlPlaneScope) AdditionalTags() infrav1.Tags {
-	if s.ControlPlane.Spec.AdditionalTags == nil {
-		s.ControlPlane.Spec.AdditionalTags = infrav1.Tags{}
+	tags := s.ControlPlane.Spec.AdditionalTags.DeepCopy()
+	if tags == nil {
+		tags = infrav1.Tags{}
 	}
-
-	return s.ControlPlane.Spec.AdditionalTags.DeepCopy()
+	return tags
 }
 
 // APIServerPort returns the port to use when communicating with the API server.
@@ -309,6 +309,20 @@ func (s *ManagedControlPlaneScope) Bastion() *infrav1.Bastion {
 	return &s.ControlPlane.Spec.Bastion
 }
 
+// InstanceConnectEndpoint returns nil, EC2 Instance Connect Endpoints are only managed for AWSClusters.
+func (s *ManagedControlPlaneScope) InstanceConnectEndpoint() *infrav1.InstanceConnectEndpoint {
+	return nil
+}
+
+// InstanceConnectEndpointStatus returns nil, EC2 Instance Connect Endpoints are only managed for AWSClusters.
+func (s *ManagedControlPlaneScope) InstanceConnectEndpointStatus() *infrav1.InstanceConnectEndpointStatus {
+	return nil
+}
+
+// SetInstanceConnectEndpointStatus does nothing, EC2 Instance Connect Endpoints are only managed for AWSClusters.
+func (s *ManagedControlPlaneScope) SetInstanceConnectEndpointStatus(_ *infrav1.InstanceConnectEndpointStatus) {
+}
+
 // Bucket returns the bucket details.
 // For ManagedControlPlane this is always nil, as we don't support S3 buckets for managed clusters.
 func (s *ManagedControlPlaneScope) Bucket() *infrav1.S3Bucket {
@@ -330,6 +344,11 @@ func (s *ManagedControlPlaneScope) SSHKeyName() *string {
 	return s.ControlPlane.Spec.SSHKeyName
 }
 
+// DefaultInstanceMetadataOptions returns nil, the AWSManagedControlPlane has no default metadata options.
+func (s *ManagedControlPlaneScope) DefaultInstanceMetadataOptions() *infrav1.InstanceMetadataOptions {
+	return nil
+}
+
 // ControllerName returns the name of the controller that
 // created the ManagedControlPlane.
 func (s *ManagedControlPlaneScope) ControllerName() string {
@@ -376,6 +395,16 @@ func (s *ManagedControlPlaneScope) ImageLookupBaseOS() string {
 	return s.ControlPlane.Spec.ImageLookupBaseOS
 }
 
+// DefaultLifecycleHooks returns the lifecycle hooks added to all the machine pools of the cluster.
+func (s *ManagedControlPlaneScope) DefaultLifecycleHooks() []infrav1.AWSLifecycleHook {
+	return s.ControlPlane.Spec.DefaultLifecycleHooks
+}
+
+// ResourcePolicyLifecycleHooks returns nil, as managed control planes don't reference a resource policy.
+func (s *ManagedControlPlaneScope) ResourcePolicyLifecycleHooks() []infrav1.AWSLifecycleHook {
+	return nil
+}
+
 // IAMAuthConfig returns the IAM authenticator config. The returned value will never be nil.
 func (s *ManagedControlPlaneScope) IAMAuthConfig() *ekscontrolplanev1.IAMAuthenticatorConfig {
 	if s.ControlPlane.Spec.IAMAuthenticatorConfig == nil {
@@ -443,6 +472,16 @@ func (s *ManagedControlPlaneScope) AdditionalControlPlaneIngressRules() []infrav
 	return nil
 }
 
+// MachinePoolLoadBalancerListenerPorts returns nil, managed control planes have no control plane load balancer.
+func (s *ManagedControlPlaneScope) MachinePoolLoadBalancerListenerPorts() ([]int64, error) {
+	return nil, nil
+}
+
+// HasWindowsNodes returns false, the node security group rules of managed control planes are managed by EKS.
+func (s *ManagedControlPlaneScope) HasWindowsNodes() (bool, error) {
+	return false, nil
+}
+
 // UnstructuredControlPlane returns the unstructured object for the control plane, if any.
 // When the reference is not set, it returns an empty object.
 func (s *ManagedControlPlaneScope) UnstructuredControlPlane() (*unstructured.Unstructured, error) {
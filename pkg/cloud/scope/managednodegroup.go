@@ -268,6 +268,7 @@ func (s *ManagedMachinePoolScope) PatchObject() error {
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			expinfrav1.EKSNodegroupReadyCondition,
 			expinfrav1.IAMNodegroupRolesReadyCondition,
+			infrav1.UserDataTransformReadyCondition,
 		}})
 }
 
@@ -279,6 +280,22 @@ func (s *ManagedMachinePoolScope) PatchCAPIMachinePoolObject(ctx context.Context
 	)
 }
 
+// AutoscalingManagesReplicas returns true if the replicas of the MachinePool are managed by the
+// autoscaling of the AWSManagedMachinePool.
+func (s *ManagedMachinePoolScope) AutoscalingManagesReplicas() bool {
+	return autoscalingManagesReplicas(s.MachinePool)
+}
+
+// SetAutoscalingManagesReplicas hands the replicas of the MachinePool over to the autoscaling of the
+// AWSManagedMachinePool, or gives them back to the MachinePool, and patches the MachinePool if needed.
+func (s *ManagedMachinePoolScope) SetAutoscalingManagesReplicas(ctx context.Context, enabled bool) error {
+	if !setAutoscalingManagesReplicas(s.MachinePool, enabled) {
+		return nil
+	}
+	s.Info("Updating the replicas management of the MachinePool", "autoscaling", enabled)
+	return s.PatchCAPIMachinePoolObject(ctx)
+}
+
 // Close closes the current scope persisting the control plane configuration and status.
 func (s *ManagedMachinePoolScope) Close() error {
 	return s.PatchObject()
@@ -346,6 +363,11 @@ func (s *ManagedMachinePoolScope) GetRawBootstrapData() ([]byte, *types.Namespac
 	return value, &key, nil
 }
 
+// TransformUserData renders the user data transform of the launch template with the bootstrap data.
+func (s *ManagedMachinePoolScope) TransformUserData(bootstrapData []byte) ([]byte, error) {
+	return transformUserData(context.TODO(), s.Client, s.Namespace(), s.ManagedMachinePool.Spec.AWSLaunchTemplate.UserDataTransform, s.Cluster, s.MachinePool.Spec.Template.Labels, bootstrapData, "")
+}
+
 // GetObjectMeta returns the ObjectMeta for the AWSManagedMachinePool.
 func (s *ManagedMachinePoolScope) GetObjectMeta() *metav1.ObjectMeta {
 	return &s.ManagedMachinePool.ObjectMeta
@@ -361,11 +383,40 @@ func (s *ManagedMachinePoolScope) GetEC2Scope() EC2Scope {
 	return s.EC2Scope
 }
 
+// SSHKeyName returns the SSH key name of the launch template. The SSH key of the AWSManagedControlPlane isn't used,
+// as the remote access of the node group sets the SSH key of its instances.
+func (s *ManagedMachinePoolScope) SSHKeyName() *string {
+	if s.ManagedMachinePool.Spec.AWSLaunchTemplate == nil {
+		return nil
+	}
+	return s.ManagedMachinePool.Spec.AWSLaunchTemplate.SSHKeyName
+}
+
+// InstanceMetadataOptions returns the metadata options of the launch template.
+func (s *ManagedMachinePoolScope) InstanceMetadataOptions() *infrav1.InstanceMetadataOptions {
+	if s.ManagedMachinePool.Spec.AWSLaunchTemplate == nil {
+		return nil
+	}
+	return s.ManagedMachinePool.Spec.AWSLaunchTemplate.InstanceMetadataOptions
+}
+
 // IsEKSManaged returns true if the control plane is managed by EKS.
 func (s *ManagedMachinePoolScope) IsEKSManaged() bool {
 	return true
 }
 
+// GetAMILookupVersion returns the Kubernetes version of the nodegroup and of its AMI: the AMI version
+// override of the AWSManagedMachinePool, or the version of the MachinePool.
+func (s *ManagedMachinePoolScope) GetAMILookupVersion() (*string, error) {
+	return amiLookupVersion(s.ManagedMachinePool.Spec.AMIVersionOverride, s.MachinePool.Spec.Template.Spec.Version, s.ControlPlane.Spec.Version)
+}
+
+// GetOS returns the operating system of the nodes. The AMI of EKS managed node groups is
+// chosen by EKS, so the operating system is left unset.
+func (s *ManagedMachinePoolScope) GetOS() infrav1.OSType {
+	return ""
+}
+
 // GetLaunchTemplateIDStatus returns the launch template ID status.
 func (s *ManagedMachinePoolScope) GetLaunchTemplateIDStatus() string {
 	if s.ManagedMachinePool.Status.LaunchTemplateID != nil {
@@ -392,6 +443,16 @@ func (s *ManagedMachinePoolScope) SetLaunchTemplateLatestVersionStatus(version s
 	s.ManagedMachinePool.Status.LaunchTemplateVersion = &version
 }
 
+// GetLaunchTemplateChangeStatus returns the changes which led to the latest version of the launch template.
+func (s *ManagedMachinePoolScope) GetLaunchTemplateChangeStatus() *expinfrav1.LaunchTemplateChange {
+	return s.ManagedMachinePool.Status.LastLaunchTemplateChange
+}
+
+// SetLaunchTemplateChangeStatus sets the changes which led to the latest version of the launch template.
+func (s *ManagedMachinePoolScope) SetLaunchTemplateChangeStatus(change *expinfrav1.LaunchTemplateChange) {
+	s.ManagedMachinePool.Status.LastLaunchTemplateChange = change
+}
+
 // GetLaunchTemplate returns the launch template.
 func (s *ManagedMachinePoolScope) GetLaunchTemplate() *expinfrav1.AWSLaunchTemplate {
 	return s.ManagedMachinePool.Spec.AWSLaunchTemplate
@@ -53,4 +53,11 @@ type NetworkScope interface {
 	SetNatGatewaysIPs(ips []string)
 	// GetNatGatewaysIPs gets the Nat Gateways Public IPs.
 	GetNatGatewaysIPs() []string
+
+	// InstanceConnectEndpoint returns the configuration of the EC2 Instance Connect Endpoint of the cluster.
+	InstanceConnectEndpoint() *infrav1.InstanceConnectEndpoint
+	// InstanceConnectEndpointStatus returns the EC2 Instance Connect Endpoint of the cluster, nil if it doesn't exist.
+	InstanceConnectEndpointStatus() *infrav1.InstanceConnectEndpointStatus
+	// SetInstanceConnectEndpointStatus sets the EC2 Instance Connect Endpoint in the status of the cluster.
+	SetInstanceConnectEndpointStatus(status *infrav1.InstanceConnectEndpointStatus)
 }
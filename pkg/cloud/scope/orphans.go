@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud"
+)
+
+// OrphansScope is the interface for the scope to be used with the orphans service.
+type OrphansScope interface {
+	cloud.ClusterScoper
+
+	// Network returns the cluster network object.
+	Network() *infrav1.NetworkStatus
+
+	// ControlPlaneLoadBalancerMigration returns the progress of the migration of the control plane load balancer
+	// from a Classic Load Balancer to a Network Load Balancer, or nil if it isn't migrated.
+	ControlPlaneLoadBalancerMigration() *infrav1.LoadBalancerMigration
+
+	// ScanOrphans returns the value of the scan-orphans annotation of the cluster, empty if it isn't set.
+	ScanOrphans() string
+
+	// LastOrphanScanTime returns the time of the last scan for orphaned resources, nil if there was none.
+	LastOrphanScanTime() *metav1.Time
+
+	// OrphanedResources returns the orphaned resources reported by the last scan.
+	OrphanedResources() []infrav1.OrphanedResource
+
+	// SetOrphanedResources sets the orphaned resources and the time of the scan in the status of the cluster.
+	SetOrphanedResources(resources []infrav1.OrphanedResource, scanTime *metav1.Time)
+
+	// AWSMachinePools returns the AWSMachinePools of the cluster, including the ones being deleted.
+	AWSMachinePools() ([]expinfrav1.AWSMachinePool, error)
+}
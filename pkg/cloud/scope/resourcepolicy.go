@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+)
+
+// ResourcePolicy holds the defaults read from the resource policy ConfigMap of a cluster.
+type ResourcePolicy struct {
+	// AdditionalTags are merged beneath the additional tags of the cluster.
+	AdditionalTags infrav1.Tags
+
+	// LifecycleHooks are added to the Auto Scaling groups of all the machine pools of the cluster,
+	// unless the cluster or the machine pool defines a lifecycle hook with the same name.
+	LifecycleHooks []infrav1.AWSLifecycleHook
+}
+
+// getResourcePolicy reads the resource policy from the referenced ConfigMap in the namespace, for a cluster in the given region.
+func getResourcePolicy(ctx context.Context, c client.Client, namespace string, ref *corev1.LocalObjectReference, region string) (*ResourcePolicy, error) {
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: namespace, Name: ref.Name}
+	if err := c.Get(ctx, key, configMap); err != nil {
+		return nil, errors.Wrapf(err, "failed to get resource policy ConfigMap %s", key)
+	}
+
+	policy := &ResourcePolicy{}
+	if text, ok := configMap.Data[infrav1.ResourcePolicyAdditionalTagsKey]; ok {
+		if err := yaml.UnmarshalStrict([]byte(text), &policy.AdditionalTags); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse key %q of resource policy ConfigMap %s", infrav1.ResourcePolicyAdditionalTagsKey, key)
+		}
+	}
+	if text, ok := configMap.Data[infrav1.ResourcePolicyLifecycleHooksKey]; ok {
+		if err := yaml.UnmarshalStrict([]byte(text), &policy.LifecycleHooks); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse key %q of resource policy ConfigMap %s", infrav1.ResourcePolicyLifecycleHooksKey, key)
+		}
+	}
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, policy.AdditionalTags.Validate()...)
+	allErrs = append(allErrs, infrav1.ValidateLifecycleHooks(field.NewPath(infrav1.ResourcePolicyLifecycleHooksKey), policy.LifecycleHooks, region)...)
+	if len(allErrs) > 0 {
+		return nil, errors.Wrapf(allErrs.ToAggregate(), "invalid resource policy ConfigMap %s", key)
+	}
+
+	return policy, nil
+}
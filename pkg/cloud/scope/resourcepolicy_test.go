@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+)
+
+func TestGetResourcePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    *ResourcePolicy
+		wantErr string
+	}{
+		{
+			name: "empty policy",
+			data: map[string]string{},
+			want: &ResourcePolicy{},
+		},
+		{
+			name: "tags and lifecycle hooks",
+			data: map[string]string{
+				infrav1.ResourcePolicyAdditionalTagsKey: "team: platform\ncost-center: \"1234\"\n",
+				infrav1.ResourcePolicyLifecycleHooksKey: "- name: drain\n  lifecycleTransition: autoscaling:EC2_INSTANCE_TERMINATING\n  heartbeatTimeout: 5m\n",
+			},
+			want: &ResourcePolicy{
+				AdditionalTags: infrav1.Tags{"team": "platform", "cost-center": "1234"},
+				LifecycleHooks: []infrav1.AWSLifecycleHook{{
+					Name:                "drain",
+					LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+					HeartbeatTimeout:    &metav1.Duration{Duration: 5 * time.Minute},
+				}},
+			},
+		},
+		{
+			name:    "unknown lifecycle hook field",
+			data:    map[string]string{infrav1.ResourcePolicyLifecycleHooksKey: "- name: drain\n  transition: autoscaling:EC2_INSTANCE_TERMINATING\n"},
+			wantErr: `failed to parse key "lifecycleHooks"`,
+		},
+		{
+			name:    "invalid tag",
+			data:    map[string]string{infrav1.ResourcePolicyAdditionalTagsKey: "aws:team: platform\n"},
+			wantErr: "invalid resource policy ConfigMap default/policy",
+		},
+		{
+			name:    "invalid lifecycle hook",
+			data:    map[string]string{infrav1.ResourcePolicyLifecycleHooksKey: "- name: drain\n  lifecycleTransition: terminate\n"},
+			wantErr: "lifecycleHooks[0].lifecycleTransition",
+		},
+		{
+			name: "lifecycle hook in another partition than the cluster",
+			data: map[string]string{infrav1.ResourcePolicyLifecycleHooksKey: "- name: drain\n  lifecycleTransition: autoscaling:EC2_INSTANCE_TERMINATING\n" +
+				"  notificationTargetARN: arn:aws-us-gov:sqs:us-gov-west-1:123456789012:queue\n  roleARN: arn:aws-us-gov:iam::123456789012:role/role\n"},
+			wantErr: `partition "aws-us-gov" does not match partition "aws" of region "us-east-1"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme, err := setupScheme()
+			g.Expect(err).NotTo(HaveOccurred())
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "default"},
+				Data:       tt.data,
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+
+			policy, err := getResourcePolicy(context.TODO(), c, "default", &corev1.LocalObjectReference{Name: "policy"}, "us-east-1")
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(policy).To(Equal(tt.want))
+		})
+	}
+
+	t.Run("missing ConfigMap", func(t *testing.T) {
+		g := NewWithT(t)
+
+		scheme, err := setupScheme()
+		g.Expect(err).NotTo(HaveOccurred())
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		_, err = getResourcePolicy(context.TODO(), c, "default", &corev1.LocalObjectReference{Name: "policy"}, "us-east-1")
+		g.Expect(err).To(MatchError(ContainSubstring("failed to get resource policy ConfigMap default/policy")))
+	})
+}
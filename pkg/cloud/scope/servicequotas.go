@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud"
+)
+
+// ServiceQuotasScope is the interface for the scope to be used with the service quotas service.
+type ServiceQuotasScope interface {
+	cloud.ClusterScoper
+
+	// VPC returns the cluster VPC.
+	VPC() *infrav1.VPCSpec
+
+	// Subnets returns the cluster subnets.
+	Subnets() infrav1.Subnets
+
+	// SecurityGroups returns the cluster security groups as a map, it creates the map if empty.
+	SecurityGroups() map[infrav1.SecurityGroupRole]infrav1.SecurityGroup
+
+	// QuotaCheck returns the configuration of the quota check.
+	QuotaCheck() *infrav1.QuotaCheck
+
+	// LastQuotaCheckTime returns the time of the last quota check, nil if the quotas were never checked.
+	LastQuotaCheckTime() *metav1.Time
+
+	// SetQuotaUsage sets the usage of the service quotas and the time of the check in the status of the cluster.
+	SetQuotaUsage(usage []infrav1.QuotaUsage, checkTime *metav1.Time)
+
+	// PendingOnDemandInstances returns, by instance type, the number of on-demand instances the cluster
+	// is still expected to launch.
+	PendingOnDemandInstances() (map[string]int64, error)
+}
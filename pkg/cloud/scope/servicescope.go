@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	awsclient "github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/throttle"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/logger"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// DefaultServiceControllerName is the name the metrics of the AWS requests of a ServiceScope are recorded with
+// when no controller name is set.
+const DefaultServiceControllerName = "capa-services"
+
+// ServiceScopeParams defines the input parameters used to create a new ServiceScope.
+type ServiceScopeParams struct {
+	// Session is the AWS session the clients of the services are created from. The region of the session is the
+	// region of the resources.
+	Session awsclient.ConfigProvider
+
+	// ClusterName is the name of the Kubernetes cluster the resources belong to. It is used in the ownership tags
+	// of the resources.
+	ClusterName string
+
+	// AdditionalTags are added to the resources created by the services.
+	AdditionalTags infrav1.Tags
+
+	// ControllerName is the name the metrics of the AWS requests are recorded with. Defaults to
+	// DefaultServiceControllerName.
+	ControllerName string
+
+	// Logger is the logger of the services. Defaults to a logger discarding everything.
+	Logger *logger.Logger
+}
+
+// ServiceScope is the scope of the services when they are used outside of the CAPA controllers. It is created from
+// an AWS session and the name of the cluster, without any Cluster API object, so the methods returning objects of
+// the management cluster return empty values.
+type ServiceScope struct {
+	logger.Logger
+
+	session        awsclient.ConfigProvider
+	clusterName    string
+	additionalTags infrav1.Tags
+	controllerName string
+}
+
+var _ EC2Scope = &ServiceScope{}
+
+// NewServiceScope creates a new ServiceScope from the supplied parameters.
+func NewServiceScope(params ServiceScopeParams) (*ServiceScope, error) {
+	if params.Session == nil {
+		return nil, errors.New("session is required when creating a ServiceScope")
+	}
+	if params.ClusterName == "" {
+		return nil, errors.New("cluster name is required when creating a ServiceScope")
+	}
+	if params.ControllerName == "" {
+		params.ControllerName = DefaultServiceControllerName
+	}
+	if params.Logger == nil {
+		params.Logger = logger.NewLogger(logr.Discard())
+	}
+
+	return &ServiceScope{
+		Logger:         *params.Logger,
+		session:        params.Session,
+		clusterName:    params.ClusterName,
+		additionalTags: params.AdditionalTags.DeepCopy(),
+		controllerName: params.ControllerName,
+	}, nil
+}
+
+// Session returns the AWS SDK session. Used for creating clients.
+func (s *ServiceScope) Session() awsclient.ConfigProvider {
+	return s.session
+}
+
+// ServiceLimiter returns nil, the requests of a ServiceScope aren't rate limited by CAPA.
+func (s *ServiceScope) ServiceLimiter(_ string) *throttle.ServiceLimiter {
+	return nil
+}
+
+// ControllerName returns the name the metrics of the AWS requests are recorded with.
+func (s *ServiceScope) ControllerName() string {
+	return s.controllerName
+}
+
+// Name returns the cluster name.
+func (s *ServiceScope) Name() string {
+	return s.clusterName
+}
+
+// Namespace returns an empty string, the cluster isn't a Kubernetes object.
+func (s *ServiceScope) Namespace() string {
+	return ""
+}
+
+// InfraClusterName returns the cluster name.
+func (s *ServiceScope) InfraClusterName() string {
+	return s.clusterName
+}
+
+// KubernetesClusterName returns the cluster name.
+func (s *ServiceScope) KubernetesClusterName() string {
+	return s.clusterName
+}
+
+// Region returns the region of the session.
+func (s *ServiceScope) Region() string {
+	return aws.StringValue(s.session.ClientConfig(ec2.EndpointsID).Config.Region)
+}
+
+// InfraCluster returns nil, there is no AWS infrastructure cluster object.
+func (s *ServiceScope) InfraCluster() cloud.ClusterObject {
+	return nil
+}
+
+// ClusterObj returns nil, there is no cluster object.
+func (s *ServiceScope) ClusterObj() cloud.ClusterObject {
+	return nil
+}
+
+// UnstructuredControlPlane returns an error, there is no control plane object.
+func (s *ServiceScope) UnstructuredControlPlane() (*unstructured.Unstructured, error) {
+	return nil, errors.New("a ServiceScope has no control plane")
+}
+
+// IdentityRef returns nil, the identity is the one of the session.
+func (s *ServiceScope) IdentityRef() *infrav1.AWSIdentityReference {
+	return nil
+}
+
+// ListOptionsLabelSelector returns a ListOptions with a label selector for the cluster name.
+func (s *ServiceScope) ListOptionsLabelSelector() client.ListOption {
+	return client.MatchingLabels(map[string]string{
+		clusterv1.ClusterNameLabel: s.clusterName,
+	})
+}
+
+// APIServerPort returns the default API server port.
+func (s *ServiceScope) APIServerPort() int32 {
+	return infrav1.DefaultAPIServerPort
+}
+
+// AdditionalTags returns the tags added to the resources created by the services.
+func (s *ServiceScope) AdditionalTags() infrav1.Tags {
+	tags := s.additionalTags.DeepCopy()
+	if tags == nil {
+		tags = infrav1.Tags{}
+	}
+	return tags
+}
+
+// SetFailureDomain does nothing, there is no cluster object to set it on.
+func (s *ServiceScope) SetFailureDomain(_ string, _ clusterv1.FailureDomainSpec) {}
+
+// PatchObject does nothing, there is no object to patch.
+func (s *ServiceScope) PatchObject() error {
+	return nil
+}
+
+// Close does nothing, there is no object to patch.
+func (s *ServiceScope) Close() error {
+	return nil
+}
+
+// VPC returns an empty VPC, the network of the cluster isn't known.
+func (s *ServiceScope) VPC() *infrav1.VPCSpec {
+	return &infrav1.VPCSpec{}
+}
+
+// Subnets returns nil, the network of the cluster isn't known.
+func (s *ServiceScope) Subnets() infrav1.Subnets {
+	return nil
+}
+
+// Network returns an empty network status, the network of the cluster isn't known.
+func (s *ServiceScope) Network() *infrav1.NetworkStatus {
+	return &infrav1.NetworkStatus{}
+}
+
+// SecurityGroups returns an empty map, the security groups of the cluster aren't known.
+func (s *ServiceScope) SecurityGroups() map[infrav1.SecurityGroupRole]infrav1.SecurityGroup {
+	return map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{}
+}
+
+// Bastion returns an empty bastion, which is disabled.
+func (s *ServiceScope) Bastion() *infrav1.Bastion {
+	return &infrav1.Bastion{}
+}
+
+// SetBastionInstance does nothing, there is no cluster status to set it in.
+func (s *ServiceScope) SetBastionInstance(_ *infrav1.Instance) {}
+
+// SSHKeyName returns nil, there is no default SSH key.
+func (s *ServiceScope) SSHKeyName() *string {
+	return nil
+}
+
+// DefaultInstanceMetadataOptions returns nil, there are no default metadata options.
+func (s *ServiceScope) DefaultInstanceMetadataOptions() *infrav1.InstanceMetadataOptions {
+	return nil
+}
+
+// ImageLookupFormat returns an empty string, the default format is used.
+func (s *ServiceScope) ImageLookupFormat() string {
+	return ""
+}
+
+// ImageLookupOrg returns an empty string, the default organization is used.
+func (s *ServiceScope) ImageLookupOrg() string {
+	return ""
+}
+
+// ImageLookupBaseOS returns an empty string, the default base operating system is used.
+func (s *ServiceScope) ImageLookupBaseOS() string {
+	return ""
+}
+
+// DefaultLifecycleHooks returns nil, there are no default lifecycle hooks.
+func (s *ServiceScope) DefaultLifecycleHooks() []infrav1.AWSLifecycleHook {
+	return nil
+}
+
+// ResourcePolicyLifecycleHooks returns nil, there is no resource policy.
+func (s *ServiceScope) ResourcePolicyLifecycleHooks() []infrav1.AWSLifecycleHook {
+	return nil
+}
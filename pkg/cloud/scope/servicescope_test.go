@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+)
+
+func TestNewServiceScope(t *testing.T) {
+	g := NewWithT(t)
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String("eu-west-1")})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = NewServiceScope(ServiceScopeParams{ClusterName: "test-cluster"})
+	g.Expect(err).To(HaveOccurred())
+	_, err = NewServiceScope(ServiceScopeParams{Session: sess})
+	g.Expect(err).To(HaveOccurred())
+
+	tags := infrav1.Tags{"team": "a"}
+	s, err := NewServiceScope(ServiceScopeParams{
+		Session:        sess,
+		ClusterName:    "test-cluster",
+		AdditionalTags: tags,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s.Region()).To(Equal("eu-west-1"))
+	g.Expect(s.KubernetesClusterName()).To(Equal("test-cluster"))
+	g.Expect(s.ControllerName()).To(Equal(DefaultServiceControllerName))
+	g.Expect(s.InfraCluster()).To(BeNil())
+
+	// The tags of the scope can't be changed through the parameters or the returned tags.
+	tags["team"] = "b"
+	s.AdditionalTags()["team"] = "c"
+	g.Expect(s.AdditionalTags()).To(Equal(infrav1.Tags{"team": "a"}))
+}
@@ -18,6 +18,7 @@ package scope
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"sync"
 
@@ -38,6 +39,7 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/audit"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/identity"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/throttle"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/logger"
@@ -62,9 +64,34 @@ type ServiceEndpoint struct {
 var sessionCache sync.Map
 var providerCache sync.Map
 
+// defaultCABundle is the CA bundle of the controller, used by the identities without a CA bundle of their own.
+var defaultCABundle []byte
+
 type sessionCacheEntry struct {
 	session         *session.Session
 	serviceLimiters throttle.ServiceLimiters
+	caBundleHash    string
+}
+
+// caBundleError is returned when the CA bundle referenced by an identity can't be read or is invalid.
+type caBundleError struct {
+	secretName string
+	err        error
+}
+
+func (e *caBundleError) Error() string {
+	return fmt.Sprintf("invalid CA bundle in secret %s/%s: %v", system.GetManagerNamespace(), e.secretName, e.err)
+}
+
+// SetDefaultCABundle sets the PEM encoded CA bundle the certificates of the AWS API endpoints are verified with,
+// in addition to the system CAs, for the identities without a CA bundle of their own. It must be called before
+// any session is created.
+func SetDefaultCABundle(caBundle []byte) error {
+	if _, err := identity.ParseCABundle(caBundle); err != nil {
+		return err
+	}
+	defaultCABundle = caBundle
+	return nil
 }
 
 // SessionInterface is the interface for AWSCluster and ManagedCluster to be used to get session using identityRef.
@@ -88,9 +115,14 @@ func sessionForRegion(region string, endpoint []ServiceEndpoint) (*session.Sessi
 		}
 		return endpoints.DefaultResolver().EndpointFor(service, region, optFns...)
 	}
+	httpClient, err := identity.NewHTTPClient(defaultCABundle)
+	if err != nil {
+		return nil, nil, err
+	}
 	ns, err := session.NewSession(&aws.Config{
 		Region:           aws.String(region),
 		EndpointResolver: endpoints.ResolverFunc(resolver),
+		HTTPClient:       httpClient,
 	})
 	if err != nil {
 		return nil, nil, err
@@ -123,10 +155,18 @@ func sessionForClusterWithRegion(k8sClient client.Client, clusterScoper cloud.Se
 	providers, err := getProvidersForCluster(context.Background(), k8sClient, clusterScoper, log)
 	if err != nil {
 		// could not get providers and retrieve the credentials
-		conditions.MarkFalse(clusterScoper.InfraCluster(), infrav1.PrincipalCredentialRetrievedCondition, infrav1.PrincipalCredentialRetrievalFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		conditions.MarkFalse(clusterScoper.InfraCluster(), infrav1.PrincipalCredentialRetrievedCondition, credentialRetrievalFailedReason(err), clusterv1.ConditionSeverityError, err.Error())
 		return nil, nil, errors.Wrap(err, "Failed to get providers for cluster")
 	}
 
+	// The CA bundle is read on every call, so that the session is recreated when the secret is rotated.
+	caBundle, err := getCABundleForRef(context.Background(), k8sClient, clusterScoper.IdentityRef())
+	if err != nil {
+		conditions.MarkFalse(clusterScoper.InfraCluster(), infrav1.PrincipalCredentialRetrievedCondition, credentialRetrievalFailedReason(err), clusterv1.ConditionSeverityError, err.Error())
+		return nil, nil, errors.Wrap(err, "Failed to get CA bundle for cluster")
+	}
+	caBundleHash := fmt.Sprintf("%x", sha256.Sum256(caBundle))
+
 	isChanged := false
 	awsProviders := make([]credentials.Provider, len(providers))
 	for i, provider := range providers {
@@ -149,19 +189,33 @@ func sessionForClusterWithRegion(k8sClient client.Client, clusterScoper cloud.Se
 	if !isChanged {
 		if s, ok := sessionCache.Load(getSessionName(region, clusterScoper)); ok {
 			entry := s.(*sessionCacheEntry)
-			return entry.session, entry.serviceLimiters, nil
+			if entry.caBundleHash == caBundleHash {
+				return entry.session, entry.serviceLimiters, nil
+			}
 		}
 	}
+	httpClient, err := identity.NewHTTPClient(caBundle)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to create HTTP client")
+	}
 	awsConfig := &aws.Config{
 		Region:           aws.String(region),
 		EndpointResolver: endpoints.ResolverFunc(resolver),
+		HTTPClient:       httpClient,
 	}
 
 	if len(providers) > 0 {
 		// Check if identity credentials can be retrieved. One reason this will fail is that source identity is not authorized for assume role.
 		_, err := providers[0].Retrieve()
 		if err != nil {
-			conditions.MarkUnknown(clusterScoper.InfraCluster(), infrav1.PrincipalCredentialRetrievedCondition, infrav1.CredentialProviderBuildFailedReason, err.Error())
+			if identity.IsTLSVerificationError(err) {
+				// Surface TLS errors, e.g. a proxy intercepting the traffic with a CA missing from the CA bundle,
+				// instead of a generic retrieval failure.
+				conditions.MarkFalse(clusterScoper.InfraCluster(), infrav1.PrincipalCredentialRetrievedCondition, infrav1.IdentityTLSConfigurationInvalidReason, clusterv1.ConditionSeverityError,
+					"Failed to verify the certificate of the AWS API endpoint, check the CA bundle of the identity: %s", err.Error())
+			} else {
+				conditions.MarkUnknown(clusterScoper.InfraCluster(), infrav1.PrincipalCredentialRetrievedCondition, infrav1.CredentialProviderBuildFailedReason, err.Error())
+			}
 
 			// delete the existing session from cache. Otherwise, we give back a defective session on next method invocation with same cluster scope
 			sessionCache.Delete(getSessionName(region, clusterScoper))
@@ -177,15 +231,32 @@ func sessionForClusterWithRegion(k8sClient client.Client, clusterScoper cloud.Se
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "Failed to create a new AWS session")
 	}
+	// The role assumed by the session is recorded in the audit log of its requests.
+	if len(providers) > 0 {
+		if roleProvider, ok := providers[0].(*identity.AWSRolePrincipalTypeProvider); ok {
+			ns.Handlers.Build.PushBackNamed(audit.AssumedRoleARNHandler(roleProvider.Principal.Spec.RoleArn))
+		}
+	}
 	sl := newServiceLimiters()
 	sessionCache.Store(getSessionName(region, clusterScoper), &sessionCacheEntry{
 		session:         ns,
 		serviceLimiters: sl,
+		caBundleHash:    caBundleHash,
 	})
 
 	return ns, sl, nil
 }
 
+// credentialRetrievalFailedReason returns the reason of the PrincipalCredentialRetrieved condition for an error
+// building the providers or reading the CA bundle of a cluster.
+func credentialRetrievalFailedReason(err error) string {
+	var caErr *caBundleError
+	if errors.As(err, &caErr) {
+		return infrav1.IdentityTLSConfigurationInvalidReason
+	}
+	return infrav1.PrincipalCredentialRetrievalFailedReason
+}
+
 func getSessionName(region string, clusterScoper cloud.SessionMetadata) string {
 	return fmt.Sprintf("%s-%s-%s", region, clusterScoper.InfraClusterName(), clusterScoper.Namespace())
 }
@@ -313,7 +384,13 @@ func buildProvidersForRef(
 			}
 		}
 
-		provider = identity.NewAWSRolePrincipalTypeProvider(roleIdentity, sourceProvider, log)
+		caBundle, err := getCABundle(ctx, k8sClient, roleIdentity.Spec.CABundleSecretRef)
+		if err != nil {
+			return providers, err
+		}
+		roleProvider := identity.NewAWSRolePrincipalTypeProvider(roleIdentity, sourceProvider, log)
+		roleProvider.CABundle = caBundle
+		provider = roleProvider
 		providers = append(providers, provider)
 	default:
 		return providers, errors.Errorf("No such provider known: '%s'", ref.Kind)
@@ -404,6 +481,58 @@ func buildAWSClusterControllerIdentity(ctx context.Context, identityObjectKey cl
 	return nil
 }
 
+// getCABundleForRef returns the CA bundle of the identity, or the CA bundle of the controller when the cluster
+// has no identity or the identity has no CA bundle of its own.
+func getCABundleForRef(ctx context.Context, k8sClient client.Client, ref *infrav1.AWSIdentityReference) ([]byte, error) {
+	if ref == nil {
+		return defaultCABundle, nil
+	}
+
+	var identitySpec *infrav1.AWSClusterIdentitySpec
+	key := client.ObjectKey{Name: ref.Name}
+	switch ref.Kind {
+	case infrav1.ControllerIdentityKind:
+		controllerIdentity := &infrav1.AWSClusterControllerIdentity{}
+		if err := k8sClient.Get(ctx, key, controllerIdentity); err != nil {
+			return nil, err
+		}
+		identitySpec = &controllerIdentity.Spec.AWSClusterIdentitySpec
+	case infrav1.ClusterStaticIdentityKind:
+		staticIdentity := &infrav1.AWSClusterStaticIdentity{}
+		if err := k8sClient.Get(ctx, key, staticIdentity); err != nil {
+			return nil, err
+		}
+		identitySpec = &staticIdentity.Spec.AWSClusterIdentitySpec
+	case infrav1.ClusterRoleIdentityKind:
+		roleIdentity := &infrav1.AWSClusterRoleIdentity{}
+		if err := k8sClient.Get(ctx, key, roleIdentity); err != nil {
+			return nil, err
+		}
+		identitySpec = &roleIdentity.Spec.AWSClusterIdentitySpec
+	default:
+		return nil, errors.Errorf("No such provider known: '%s'", ref.Kind)
+	}
+	return getCABundle(ctx, k8sClient, identitySpec.CABundleSecretRef)
+}
+
+// getCABundle returns the validated CA bundle of the secret referenced by the CABundleSecretRef of an identity,
+// or the CA bundle of the controller when no secret is referenced.
+func getCABundle(ctx context.Context, k8sClient client.Client, secretName string) ([]byte, error) {
+	if secretName == "" {
+		return defaultCABundle, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: secretName, Namespace: system.GetManagerNamespace()}, secret); err != nil {
+		return nil, &caBundleError{secretName: secretName, err: err}
+	}
+	caBundle := secret.Data[identity.CABundleKey]
+	if _, err := identity.ParseCABundle(caBundle); err != nil {
+		return nil, &caBundleError{secretName: secretName, err: err}
+	}
+	return caBundle, nil
+}
+
 func getProvidersForCluster(ctx context.Context, k8sClient client.Client, clusterScoper cloud.SessionMetadata, log logger.Wrapper) ([]identity.AWSPrincipalTypeProvider, error) {
 	providers := make([]identity.AWSPrincipalTypeProvider, 0)
 	providers, err := buildProvidersForRef(ctx, providers, k8sClient, clusterScoper, clusterScoper.IdentityRef(), log)
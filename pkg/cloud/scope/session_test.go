@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/logger"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/util/system"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestIsClusterPermittedToUsePrincipal(t *testing.T) {
@@ -503,3 +504,63 @@ func TestPrincipalParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionForClusterWithRegionCABundle(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	g.Expect(k8sClient.Create(context.Background(), &infrav1.AWSClusterControllerIdentity{
+		ObjectMeta: metav1.ObjectMeta{Name: infrav1.AWSClusterControllerIdentityName},
+		Spec: infrav1.AWSClusterControllerIdentitySpec{
+			AWSClusterIdentitySpec: infrav1.AWSClusterIdentitySpec{
+				AllowedNamespaces: &infrav1.AllowedNamespaces{},
+				CABundleSecretRef: "ca-bundle",
+			},
+		},
+	})).To(Succeed())
+
+	// A missing or invalid CA bundle is reported as an invalid TLS configuration of the identity.
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: "default"},
+		Spec: infrav1.AWSClusterSpec{
+			IdentityRef: &infrav1.AWSIdentityReference{Name: infrav1.AWSClusterControllerIdentityName, Kind: infrav1.ControllerIdentityKind},
+		},
+	}
+	clusterScope := &ClusterScope{AWSCluster: awsCluster, Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: "default"}}}
+	log := logger.NewLogger(klog.Background())
+	_, _, err = sessionForClusterWithRegion(k8sClient, clusterScope, "us-east-1", nil, log)
+	g.Expect(err).To(MatchError(ContainSubstring("invalid CA bundle")))
+	condition := conditions.Get(awsCluster, infrav1.PrincipalCredentialRetrievedCondition)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Reason).To(Equal(infrav1.IdentityTLSConfigurationInvalidReason))
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: system.GetManagerNamespace()},
+		Data:       map[string][]byte{identity.CABundleKey: []byte("not a certificate")},
+	}
+	g.Expect(k8sClient.Create(context.Background(), secret)).To(Succeed())
+	_, _, err = sessionForClusterWithRegion(k8sClient, clusterScope, "us-east-1", nil, log)
+	g.Expect(err).To(MatchError(ContainSubstring("no PEM encoded certificate")))
+	g.Expect(conditions.GetReason(awsCluster, infrav1.PrincipalCredentialRetrievedCondition)).To(Equal(infrav1.IdentityTLSConfigurationInvalidReason))
+
+	// The session is cached until the CA bundle is rotated.
+	caCert, _ := newCACertificate(t)
+	secret.Data[identity.CABundleKey] = caCert
+	g.Expect(k8sClient.Update(context.Background(), secret)).To(Succeed())
+	sess, _, err := sessionForClusterWithRegion(k8sClient, clusterScope, "us-east-1", nil, log)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sess.Config.HTTPClient.Transport).NotTo(BeNil())
+	g.Expect(conditions.IsTrue(awsCluster, infrav1.PrincipalCredentialRetrievedCondition)).To(BeTrue())
+	cached, _, err := sessionForClusterWithRegion(k8sClient, clusterScope, "us-east-1", nil, log)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cached).To(BeIdenticalTo(sess))
+
+	rotatedCACert, _ := newCACertificate(t)
+	secret.Data[identity.CABundleKey] = rotatedCACert
+	g.Expect(k8sClient.Update(context.Background(), secret)).To(Succeed())
+	rotated, _, err := sessionForClusterWithRegion(k8sClient, clusterScope, "us-east-1", nil, log)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rotated).NotTo(BeIdenticalTo(sess))
+}
@@ -54,4 +54,16 @@ type SGScope interface {
 
 	// AdditionalControlPlaneIngressRules returns the additional ingress rules for the control plane security group.
 	AdditionalControlPlaneIngressRules() []infrav1.IngressRule
+
+	// MachinePoolLoadBalancerListenerPorts returns the ports of the control plane load balancer additional listeners
+	// that machine pools of the cluster are attached to.
+	MachinePoolLoadBalancerListenerPorts() ([]int64, error)
+
+	// HasWindowsNodes returns true if machines or machine pools of the cluster run Windows nodes.
+	HasWindowsNodes() (bool, error)
+
+	// InstanceConnectEndpoint returns the configuration of the EC2 Instance Connect Endpoint of the cluster.
+	InstanceConnectEndpoint() *infrav1.InstanceConnectEndpoint
+	// InstanceConnectEndpointStatus returns the EC2 Instance Connect Endpoint of the cluster, nil if it doesn't exist.
+	InstanceConnectEndpointStatus() *infrav1.InstanceConnectEndpointStatus
 }
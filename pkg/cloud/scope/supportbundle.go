@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud"
+)
+
+// SupportBundleScope is the interface for the scope to be used with the support bundle service.
+type SupportBundleScope interface {
+	cloud.ClusterScoper
+
+	// Network returns the cluster network object.
+	Network() *infrav1.NetworkStatus
+
+	// SupportBundleRequested returns whether the collect-support-bundle annotation of the cluster is set to "true".
+	SupportBundleRequested() bool
+
+	// SupportBundleRedactsAccountIDs returns whether the AWS account IDs are redacted from the support bundles.
+	SupportBundleRedactsAccountIDs() bool
+
+	// SupportBundleCollected removes the collect-support-bundle annotation of the cluster.
+	SupportBundleCollected()
+
+	// SupportBundleObjects returns the Cluster API and CAPA objects of the cluster. The kinds which couldn't
+	// be read are reported in the error, along with the objects of the other kinds.
+	SupportBundleObjects() ([]unstructured.Unstructured, error)
+
+	// Events returns the events of the namespace of the cluster.
+	Events() ([]corev1.Event, error)
+
+	// WriteSupportBundle writes the files of a support bundle to a ConfigMap owned by the cluster, or to a
+	// Secret when they are sensitive.
+	WriteSupportBundle(name string, files map[string]string, sensitive bool) error
+}
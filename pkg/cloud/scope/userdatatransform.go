@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/userdata"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+// transformUserData renders the template of a user data transform, which is read from a ConfigMap in the
+// namespace, with the bootstrap data and the context of the cluster. The labels are the labels of the
+// machine, of which only the ones propagated to the node by Cluster API are passed to the template.
+func transformUserData(ctx context.Context, c client.Client, namespace string, transform *infrav1.UserDataTransform, cluster *clusterv1.Cluster, labels map[string]string, bootstrapData []byte, s3URL string) ([]byte, error) {
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: namespace, Name: transform.ConfigMapRef.Name}
+	if err := c.Get(ctx, key, configMap); err != nil {
+		return nil, errors.Wrapf(err, "failed to get user data transform ConfigMap %s", key)
+	}
+
+	text, ok := configMap.Data[transform.GetTemplateKey()]
+	if !ok {
+		return nil, errors.Errorf("user data transform ConfigMap %s has no key %q", key, transform.GetTemplateKey())
+	}
+
+	caCertHash, err := clusterCACertHash(ctx, c, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return userdata.RenderTransform(text, &userdata.TransformInput{
+		BootstrapData:   string(bootstrapData),
+		ClusterName:     cluster.Name,
+		ClusterEndpoint: clusterEndpoint(cluster),
+		CACertHash:      caCertHash,
+		NodeLabels:      nodeLabels(labels),
+		S3URL:           s3URL,
+	})
+}
+
+// clusterEndpoint returns the URL of the API server of the cluster, or an empty string if its endpoint isn't known yet.
+func clusterEndpoint(cluster *clusterv1.Cluster) string {
+	endpoint := cluster.Spec.ControlPlaneEndpoint
+	if !endpoint.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("https://%s", net.JoinHostPort(endpoint.Host, strconv.Itoa(int(endpoint.Port))))
+}
+
+// clusterCACertHash returns the hash of the public key of the CA of the cluster, in the format used by
+// kubeadm discovery, or an empty string if the CA isn't stored in the management cluster.
+func clusterCACertHash(ctx context.Context, c client.Client, cluster *clusterv1.Cluster) (string, error) {
+	caSecret, err := secret.GetFromNamespacedName(ctx, c, types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}, secret.ClusterCA)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get CA of cluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+
+	block, _ := pem.Decode(caSecret.Data[secret.TLSCrtDataName])
+	if block == nil {
+		return "", errors.Errorf("failed to decode CA certificate of cluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse CA certificate of cluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return "sha256:" + hex.EncodeToString(hash[:]), nil
+}
+
+// nodeLabels returns the labels which Cluster API propagates from a machine to its node.
+func nodeLabels(labels map[string]string) map[string]string {
+	res := map[string]string{}
+	for k, v := range labels {
+		prefix, _, found := strings.Cut(k, "/")
+		if !found {
+			continue
+		}
+		if prefix == clusterv1.NodeRoleLabelPrefix ||
+			prefix == clusterv1.NodeRestrictionLabelDomain || strings.HasSuffix(prefix, "."+clusterv1.NodeRestrictionLabelDomain) ||
+			prefix == clusterv1.ManagedNodeLabelDomain || strings.HasSuffix(prefix, "."+clusterv1.ManagedNodeLabelDomain) {
+			res[k] = v
+		}
+	}
+	return res
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+const userDataTransformTemplate = `{"endpoint":"{{ .ClusterEndpoint }}","caHash":"{{ .CACertHash }}","labels":{{ len .NodeLabels }},"data":"{{ .BootstrapData }}"}`
+
+func TestMachineScopeTransformUserData(t *testing.T) {
+	g := NewWithT(t)
+
+	scope, err := setupMachineScope()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	scope.AWSMachine.Spec.UserDataTransform = &infrav1.UserDataTransform{
+		ConfigMapRef: corev1.LocalObjectReference{Name: "user-data"},
+		TemplateKey:  "custom",
+	}
+	scope.Cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "example.com", Port: 6443}
+	scope.Machine.Labels["node-role.kubernetes.io/worker"] = ""
+	scope.Machine.Labels["example.node-restriction.kubernetes.io/zone"] = "a"
+	scope.Machine.Labels["not-propagated"] = "b"
+
+	// The key of the template must exist.
+	g.Expect(scope.client.Create(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "user-data", Namespace: "default"},
+		Data:       map[string]string{"template": userDataTransformTemplate},
+	})).To(Succeed())
+	_, err = scope.TransformUserData([]byte("user data"), "")
+	g.Expect(err).To(MatchError(ContainSubstring(`has no key "custom"`)))
+
+	// The CA hash is empty until the CA of the cluster is stored.
+	scope.AWSMachine.Spec.UserDataTransform.TemplateKey = ""
+	userData, err := scope.TransformUserData([]byte("user data"), "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(userData)).To(Equal(`{"endpoint":"https://example.com:6443","caHash":"","labels":2,"data":"user data"}`))
+
+	caCert, caHash := newCACertificate(t)
+	g.Expect(scope.client.Create(context.TODO(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secret.Name(scope.Cluster.Name, secret.ClusterCA), Namespace: "default"},
+		Data:       map[string][]byte{secret.TLSCrtDataName: caCert},
+	})).To(Succeed())
+	userData, err = scope.TransformUserData([]byte("user data"), "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(userData)).To(Equal(`{"endpoint":"https://example.com:6443","caHash":"` + caHash + `","labels":2,"data":"user data"}`))
+}
+
+// newCACertificate returns a PEM encoded self-signed certificate, and the hash of its public key.
+func newCACertificate(t *testing.T) ([]byte, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kubernetes"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), "sha256:" + hex.EncodeToString(hash[:])
+}
@@ -22,21 +22,27 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/ptr"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/filter"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 // SDKToAutoScalingGroup converts an AWS EC2 SDK AutoScalingGroup to the CAPA AutoScalingGroup type.
@@ -45,15 +51,49 @@ func (s *Service) SDKToAutoScalingGroup(v *autoscaling.Group) (*expinfrav1.AutoS
 		ID:   aws.StringValue(v.AutoScalingGroupARN),
 		Name: aws.StringValue(v.AutoScalingGroupName),
 		// TODO(rudoi): this is just terrible
-		DesiredCapacity:   aws.Int32(int32(aws.Int64Value(v.DesiredCapacity))),
-		MaxSize:           int32(aws.Int64Value(v.MaxSize)),
-		MinSize:           int32(aws.Int64Value(v.MinSize)),
-		CapacityRebalance: aws.BoolValue(v.CapacityRebalance),
+		DesiredCapacity:                  aws.Int32(int32(aws.Int64Value(v.DesiredCapacity))),
+		MaxSize:                          int32(aws.Int64Value(v.MaxSize)),
+		MinSize:                          int32(aws.Int64Value(v.MinSize)),
+		CapacityRebalance:                aws.BoolValue(v.CapacityRebalance),
+		NewInstancesProtectedFromScaleIn: aws.BoolValue(v.NewInstancesProtectedFromScaleIn),
+		CreatedTime:                      metav1.NewTime(aws.TimeValue(v.CreatedTime)),
 		// TODO: determine what additional values go here and what else should be in the struct
 	}
 
 	if v.VPCZoneIdentifier != nil {
-		i.Subnets = strings.Split(*v.VPCZoneIdentifier, ",")
+		for _, subnet := range strings.Split(*v.VPCZoneIdentifier, ",") {
+			if subnet = strings.TrimSpace(subnet); subnet != "" {
+				i.Subnets = append(i.Subnets, subnet)
+			}
+		}
+	}
+
+	if len(v.TerminationPolicies) > 0 {
+		i.TerminationPolicies = aws.StringValueSlice(v.TerminationPolicies)
+	}
+
+	if len(v.LoadBalancerNames) > 0 {
+		i.LoadBalancerNames = aws.StringValueSlice(v.LoadBalancerNames)
+	}
+
+	i.HealthCheckType = expinfrav1.HealthCheckType(aws.StringValue(v.HealthCheckType))
+	if v.HealthCheckGracePeriod != nil {
+		i.HealthCheckGracePeriod = &metav1.Duration{Duration: time.Duration(*v.HealthCheckGracePeriod) * time.Second}
+	}
+	if v.DefaultInstanceWarmup != nil {
+		i.DefaultInstanceWarmup = &metav1.Duration{Duration: time.Duration(*v.DefaultInstanceWarmup) * time.Second}
+	}
+	if aws.Int64Value(v.MaxInstanceLifetime) > 0 {
+		i.MaxInstanceLifetime = &metav1.Duration{Duration: time.Duration(*v.MaxInstanceLifetime) * time.Second}
+	}
+
+	launchTemplate := v.LaunchTemplate
+	if v.MixedInstancesPolicy != nil && v.MixedInstancesPolicy.LaunchTemplate != nil {
+		launchTemplate = v.MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification
+	}
+	if launchTemplate != nil {
+		i.LaunchTemplateID = aws.StringValue(launchTemplate.LaunchTemplateId)
+		i.LaunchTemplateVersion = aws.StringValue(launchTemplate.Version)
 	}
 
 	if v.MixedInstancesPolicy != nil {
@@ -65,7 +105,11 @@ func (s *Service) SDKToAutoScalingGroup(v *autoscaling.Group) (*expinfrav1.AutoS
 		}
 
 		for _, override := range v.MixedInstancesPolicy.LaunchTemplate.Overrides {
-			i.MixedInstancesPolicy.Overrides = append(i.MixedInstancesPolicy.Overrides, expinfrav1.Overrides{InstanceType: aws.StringValue(override.InstanceType)})
+			i.MixedInstancesPolicy.Overrides = append(i.MixedInstancesPolicy.Overrides, expinfrav1.Overrides{
+				InstanceType:         aws.StringValue(override.InstanceType),
+				InstanceRequirements: instanceRequirementsFromSDK(override.InstanceRequirements),
+				WeightedCapacity:     aws.StringValue(override.WeightedCapacity),
+			})
 		}
 
 		onDemandAllocationStrategy := aws.StringValue(v.MixedInstancesPolicy.InstancesDistribution.OnDemandAllocationStrategy)
@@ -99,16 +143,35 @@ func (s *Service) SDKToAutoScalingGroup(v *autoscaling.Group) (*expinfrav1.AutoS
 
 	if len(v.Tags) > 0 {
 		i.Tags = converters.ASGTagsToMap(v.Tags)
+		i.PropagatedTags = propagatedTagKeys(v.Tags)
 	}
 
 	if len(v.Instances) > 0 {
 		for _, autoscalingInstance := range v.Instances {
 			tmp := &infrav1.Instance{
 				ID:               aws.StringValue(autoscalingInstance.InstanceId),
+				Type:             aws.StringValue(autoscalingInstance.InstanceType),
 				State:            infrav1.InstanceState(*autoscalingInstance.LifecycleState),
 				AvailabilityZone: *autoscalingInstance.AvailabilityZone,
 			}
 			i.Instances = append(i.Instances, *tmp)
+
+			if autoscalingInstance.HealthStatus != nil {
+				if i.InstanceHealthStatuses == nil {
+					i.InstanceHealthStatuses = map[string]string{}
+				}
+				i.InstanceHealthStatuses[tmp.ID] = aws.StringValue(autoscalingInstance.HealthStatus)
+			}
+
+			if autoscalingInstance.LaunchTemplate != nil {
+				if i.InstanceLaunchTemplates == nil {
+					i.InstanceLaunchTemplates = map[string]expinfrav1.InstanceLaunchTemplate{}
+				}
+				i.InstanceLaunchTemplates[tmp.ID] = expinfrav1.InstanceLaunchTemplate{
+					ID:      aws.StringValue(autoscalingInstance.LaunchTemplate.LaunchTemplateId),
+					Version: aws.StringValue(autoscalingInstance.LaunchTemplate.Version),
+				}
+			}
 		}
 	}
 
@@ -120,6 +183,16 @@ func (s *Service) SDKToAutoScalingGroup(v *autoscaling.Group) (*expinfrav1.AutoS
 		i.CurrentlySuspendProcesses = currentlySuspendedProcesses
 	}
 
+	for _, metric := range v.EnabledMetrics {
+		i.EnabledMetrics = append(i.EnabledMetrics, aws.StringValue(metric.Metric))
+	}
+
+	for _, autoscalingInstance := range v.Instances {
+		if aws.BoolValue(autoscalingInstance.ProtectedFromScaleIn) {
+			i.ProtectedInstances = append(i.ProtectedInstances, aws.StringValue(autoscalingInstance.InstanceId))
+		}
+	}
+
 	return i, nil
 }
 
@@ -136,23 +209,40 @@ func (s *Service) ASGIfExists(name *string) (*expinfrav1.AutoScalingGroup, error
 		AutoScalingGroupNames: []*string{name},
 	}
 
-	out, err := s.ASGClient.DescribeAutoScalingGroupsWithContext(context.TODO(), input)
+	groups, err := s.describeAutoScalingGroups(input)
 	switch {
 	case awserrors.IsNotFound(err):
 		return nil, nil
 	case err != nil:
 		record.Eventf(s.scope.InfraCluster(), "FailedDescribeAutoScalingGroups", "failed to describe ASG %q: %v", *name, err)
 		return nil, errors.Wrapf(err, "failed to describe AutoScaling Group: %q", *name)
-	case len(out.AutoScalingGroups) == 0:
+	case len(groups) == 0:
 		record.Eventf(s.scope.InfraCluster(), corev1.EventTypeNormal, expinfrav1.ASGNotFoundReason, "Unable to find ASG matching %q", *name)
 		return nil, nil
 	}
-	return s.SDKToAutoScalingGroup(out.AutoScalingGroups[0])
+	return s.SDKToAutoScalingGroup(groups[0])
+}
+
+// describeAutoScalingGroups returns the autoscaling groups matching the input from all the pages of results.
+// A page may hold no group even though the next one does.
+func (s *Service) describeAutoScalingGroups(input *autoscaling.DescribeAutoScalingGroupsInput) ([]*autoscaling.Group, error) {
+	var groups []*autoscaling.Group
+	for {
+		out, err := s.ASGClient.DescribeAutoScalingGroupsWithContext(context.TODO(), input)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, out.AutoScalingGroups...)
+		if aws.StringValue(out.NextToken) == "" {
+			return groups, nil
+		}
+		input.NextToken = out.NextToken
+	}
 }
 
 // GetASGByName returns the existing ASG or nothing if it doesn't exist.
 func (s *Service) GetASGByName(scope *scope.MachinePoolScope) (*expinfrav1.AutoScalingGroup, error) {
-	name := scope.Name()
+	name := scope.ASGName()
 	return s.ASGIfExists(&name)
 }
 
@@ -163,15 +253,23 @@ func (s *Service) CreateASG(machinePoolScope *scope.MachinePoolScope) (*expinfra
 		return nil, fmt.Errorf("getting subnets for ASG: %w", err)
 	}
 
+	minSize, maxSize := machinePoolScope.AWSMachinePool.SizeBounds()
 	input := &expinfrav1.AutoScalingGroup{
-		Name:                  machinePoolScope.Name(),
-		MaxSize:               machinePoolScope.AWSMachinePool.Spec.MaxSize,
-		MinSize:               machinePoolScope.AWSMachinePool.Spec.MinSize,
-		Subnets:               subnets,
-		DefaultCoolDown:       machinePoolScope.AWSMachinePool.Spec.DefaultCoolDown,
-		DefaultInstanceWarmup: machinePoolScope.AWSMachinePool.Spec.DefaultInstanceWarmup,
-		CapacityRebalance:     machinePoolScope.AWSMachinePool.Spec.CapacityRebalance,
-		MixedInstancesPolicy:  machinePoolScope.AWSMachinePool.Spec.MixedInstancesPolicy,
+		Name:                             machinePoolScope.ASGName(),
+		MaxSize:                          maxSize,
+		MinSize:                          minSize,
+		Subnets:                          subnets,
+		DefaultCoolDown:                  machinePoolScope.AWSMachinePool.Spec.DefaultCoolDown,
+		DefaultInstanceWarmup:            machinePoolScope.AWSMachinePool.Spec.DefaultInstanceWarmup,
+		CapacityRebalance:                machinePoolScope.AWSMachinePool.Spec.CapacityRebalance,
+		TerminationPolicies:              machinePoolScope.AWSMachinePool.Spec.TerminationPolicies,
+		MixedInstancesPolicy:             machinePoolScope.AWSMachinePool.Spec.MixedInstancesPolicy,
+		HealthCheckType:                  machinePoolScope.AWSMachinePool.Spec.HealthCheckType,
+		HealthCheckGracePeriod:           machinePoolScope.AWSMachinePool.Spec.HealthCheckGracePeriod,
+		MaxInstanceLifetime:              machinePoolScope.AWSMachinePool.Spec.MaxInstanceLifetime,
+		NewInstancesProtectedFromScaleIn: machinePoolScope.AWSMachinePool.Spec.NewInstancesProtectedFromScaleIn,
+		TargetGroupARNs:                  machinePoolScope.AWSMachinePool.Spec.TargetGroupARNs,
+		LoadBalancerNames:                machinePoolScope.AWSMachinePool.Spec.ClassicLoadBalancers,
 	}
 
 	// Default value of MachinePool replicas set by CAPI is 1.
@@ -179,7 +277,7 @@ func (s *Service) CreateASG(machinePoolScope *scope.MachinePoolScope) (*expinfra
 
 	// Check that MachinePool replicas number is between the minimum and maximum size of the AWSMachinePool.
 	// Ignore the problem for externally managed clusters because MachinePool replicas will be updated to the right value automatically.
-	if mpReplicas >= machinePoolScope.AWSMachinePool.Spec.MinSize && mpReplicas <= machinePoolScope.AWSMachinePool.Spec.MaxSize {
+	if mpReplicas >= minSize && mpReplicas <= maxSize {
 		input.DesiredCapacity = &mpReplicas
 	} else if !annotations.ReplicasManagedByExternalAutoscaler(machinePoolScope.MachinePool) {
 		return nil, fmt.Errorf("incorrect number of replicas %d in MachinePool %v", mpReplicas, machinePoolScope.MachinePool.Name)
@@ -189,21 +287,14 @@ func (s *Service) CreateASG(machinePoolScope *scope.MachinePoolScope) (*expinfra
 		return nil, errors.New("AWSMachinePool has no LaunchTemplateID for some reason")
 	}
 
-	// Make sure to use the MachinePoolScope here to get the merger of AWSCluster and AWSMachinePool tags
-	additionalTags := machinePoolScope.AdditionalTags()
-	// Set the cloud provider tag
-	additionalTags[infrav1.ClusterAWSCloudProviderTagKey(s.scope.KubernetesClusterName())] = string(infrav1.ResourceLifecycleOwned)
-
-	input.Tags = infrav1.Build(infrav1.BuildParams{
-		ClusterName: s.scope.KubernetesClusterName(),
-		Lifecycle:   infrav1.ResourceLifecycleOwned,
-		Name:        aws.String(machinePoolScope.Name()),
-		Role:        aws.String("node"),
-		Additional:  additionalTags,
-	})
+	input.Tags, input.PropagatedTags = s.desiredTags(machinePoolScope)
+	// Set the tags for the auto-discovery of the cluster autoscaler
+	if machinePoolScope.AWSMachinePool.Spec.Autoscaling.IsEnabled() {
+		input.Tags.Merge(infrav1.ClusterAutoscalerTags(s.scope.KubernetesClusterName()))
+	}
 
 	s.scope.Info("Running instance")
-	if err := s.runPool(input, machinePoolScope.AWSMachinePool.Status.LaunchTemplateID); err != nil {
+	if err := s.runPool(input, launchTemplateSpecification(machinePoolScope), machinePoolScope.GetLifecycleHooks()); err != nil {
 		// Only record the failure event if the error is not related to failed dependencies.
 		// This is to avoid spamming failure events since the machine will be requeued by the actuator.
 		// if !awserrors.IsFailedDependency(errors.Cause(err)) {
@@ -212,37 +303,69 @@ func (s *Service) CreateASG(machinePoolScope *scope.MachinePoolScope) (*expinfra
 		s.scope.Error(err, "unable to create AutoScalingGroup")
 		return nil, err
 	}
-	record.Eventf(machinePoolScope.AWSMachinePool, "SuccessfulCreate", "Created new ASG: %s", machinePoolScope.Name())
+	record.Eventf(machinePoolScope.AWSMachinePool, "SuccessfulCreate", "Created new ASG: %s", machinePoolScope.ASGName())
 
 	return nil, nil
 }
 
-func (s *Service) runPool(i *expinfrav1.AutoScalingGroup, launchTemplateID string) error {
+func (s *Service) runPool(i *expinfrav1.AutoScalingGroup, launchTemplate *autoscaling.LaunchTemplateSpecification, lifecycleHooks []infrav1.AWSLifecycleHook) error {
 	input := &autoscaling.CreateAutoScalingGroupInput{
-		AutoScalingGroupName:  aws.String(i.Name),
-		MaxSize:               aws.Int64(int64(i.MaxSize)),
-		MinSize:               aws.Int64(int64(i.MinSize)),
-		VPCZoneIdentifier:     aws.String(strings.Join(i.Subnets, ", ")),
-		DefaultCooldown:       aws.Int64(int64(i.DefaultCoolDown.Duration.Seconds())),
-		DefaultInstanceWarmup: aws.Int64(int64(i.DefaultInstanceWarmup.Duration.Seconds())),
-		CapacityRebalance:     aws.Bool(i.CapacityRebalance),
+		AutoScalingGroupName:             aws.String(i.Name),
+		MaxSize:                          aws.Int64(int64(i.MaxSize)),
+		MinSize:                          aws.Int64(int64(i.MinSize)),
+		VPCZoneIdentifier:                aws.String(strings.Join(i.Subnets, ",")),
+		DefaultCooldown:                  aws.Int64(int64(i.DefaultCoolDown.Duration.Seconds())),
+		CapacityRebalance:                aws.Bool(i.CapacityRebalance),
+		NewInstancesProtectedFromScaleIn: aws.Bool(i.NewInstancesProtectedFromScaleIn),
 	}
 
 	if i.DesiredCapacity != nil {
 		input.DesiredCapacity = aws.Int64(int64(aws.Int32Value(i.DesiredCapacity)))
 	}
 
+	if len(i.TerminationPolicies) > 0 {
+		input.TerminationPolicies = aws.StringSlice(i.TerminationPolicies)
+	}
+
+	// Attach the target groups at creation time so that instances launched by the
+	// initial desired capacity are registered to them.
+	if len(i.TargetGroupARNs) > 0 {
+		input.TargetGroupARNs = aws.StringSlice(i.TargetGroupARNs)
+	}
+	if len(i.LoadBalancerNames) > 0 {
+		input.LoadBalancerNames = aws.StringSlice(i.LoadBalancerNames)
+	}
+
+	if i.HealthCheckType != "" {
+		input.HealthCheckType = aws.String(string(i.HealthCheckType))
+	}
+
+	if i.HealthCheckGracePeriod != nil {
+		input.HealthCheckGracePeriod = aws.Int64(int64(i.HealthCheckGracePeriod.Duration.Seconds()))
+	}
+
+	if i.DefaultInstanceWarmup != nil {
+		input.DefaultInstanceWarmup = aws.Int64(int64(i.DefaultInstanceWarmup.Duration.Seconds()))
+	}
+
+	if lifetime := maxInstanceLifetimeSeconds(i.MaxInstanceLifetime); lifetime > 0 {
+		input.MaxInstanceLifetime = aws.Int64(lifetime)
+	}
+
 	if i.MixedInstancesPolicy != nil {
-		input.MixedInstancesPolicy = createSDKMixedInstancesPolicy(i.Name, i.MixedInstancesPolicy)
+		input.MixedInstancesPolicy = createSDKMixedInstancesPolicy(launchTemplate, i.MixedInstancesPolicy)
 	} else {
-		input.LaunchTemplate = &autoscaling.LaunchTemplateSpecification{
-			LaunchTemplateId: aws.String(launchTemplateID),
-			Version:          aws.String(expinfrav1.LaunchTemplateLatestVersion),
-		}
+		input.LaunchTemplate = launchTemplate
 	}
 
 	if i.Tags != nil {
-		input.Tags = BuildTagsFromMap(i.Name, i.Tags)
+		input.Tags = propagateTagsAtLaunch(BuildTagsFromMap(i.Name, i.Tags), i.PropagatedTags)
+	}
+
+	// Attach the lifecycle hooks at creation time so that instances launched by the
+	// initial desired capacity already go through them.
+	if len(lifecycleHooks) > 0 {
+		input.LifecycleHookSpecificationList = getLifecycleHookSpecificationList(lifecycleHooks)
 	}
 
 	if _, err := s.ASGClient.CreateAutoScalingGroupWithContext(context.TODO(), input); err != nil {
@@ -271,13 +394,71 @@ func (s *Service) DeleteASGAndWait(name string) error {
 	return nil
 }
 
-// DeleteASG will delete the ASG of a service.
+// DeleteASG will delete the ASG of a service, terminating its instances.
 func (s *Service) DeleteASG(name string) error {
-	s.scope.Debug("Attempting to delete ASG", "name", name)
+	return s.deleteASG(name, true)
+}
+
+// ReconcileASGDeletion advances the deletion of an ASG by one stage, and returns the updated deletion status
+// and whether the deletion request was accepted. The group is first scaled to zero and deleted once its
+// instances are terminated, or once the timeout expires. When the deletion is still rejected after another
+// timeout, e.g. because instances fail to terminate, the group is deleted with ForceDelete. The deletion
+// doesn't wait, the caller is expected to call it again until the group is deleted.
+func (s *Service) ReconcileASGDeletion(asg *expinfrav1.AutoScalingGroup, status *expinfrav1.ASGDeletionStatus, timeout time.Duration) (*expinfrav1.ASGDeletionStatus, bool, error) {
+	now := metav1.Now()
+	if status == nil {
+		status = &expinfrav1.ASGDeletionStatus{Phase: expinfrav1.ASGDeletionPhaseScalingToZero, PhaseStartTime: &now}
+	} else {
+		status = status.DeepCopy()
+	}
+	expired := func() bool {
+		return status.PhaseStartTime == nil || now.Sub(status.PhaseStartTime.Time) >= timeout
+	}
+
+	if status.Phase == expinfrav1.ASGDeletionPhaseScalingToZero {
+		if asg.MinSize != 0 || asg.MaxSize != 0 || aws.Int32Value(asg.DesiredCapacity) != 0 {
+			s.scope.Info("Scaling ASG to zero before deleting it", "name", asg.Name)
+			input := &autoscaling.UpdateAutoScalingGroupInput{
+				AutoScalingGroupName: aws.String(asg.Name),
+				MinSize:              aws.Int64(0),
+				MaxSize:              aws.Int64(0),
+				DesiredCapacity:      aws.Int64(0),
+			}
+			if _, err := s.ASGClient.UpdateAutoScalingGroupWithContext(context.TODO(), input); err != nil {
+				return status, false, errors.Wrapf(err, "failed to scale ASG %q to zero", asg.Name)
+			}
+		}
+		if len(asg.Instances) > 0 && !expired() {
+			s.scope.Info("Waiting for the instances of the ASG to terminate", "name", asg.Name, "instances", len(asg.Instances))
+			return status, false, nil
+		}
+		status.Phase, status.PhaseStartTime = expinfrav1.ASGDeletionPhaseDeleting, &now
+	}
+
+	if status.Phase == expinfrav1.ASGDeletionPhaseDeleting && expired() {
+		s.scope.Info("ASG deletion timed out, deleting it with ForceDelete", "name", asg.Name, "timeout", timeout)
+		status.Phase, status.PhaseStartTime = expinfrav1.ASGDeletionPhaseForceDeleting, &now
+	}
+
+	force := status.Phase == expinfrav1.ASGDeletionPhaseForceDeleting
+	if err := s.deleteASG(asg.Name, force); err != nil {
+		code, _ := awserrors.Code(errors.Cause(err))
+		if !force && (code == autoscaling.ErrCodeResourceInUseFault || code == autoscaling.ErrCodeScalingActivityInProgressFault) {
+			s.scope.Info("ASG deletion was rejected, retrying later", "name", asg.Name, "reason", code)
+			return status, false, nil
+		}
+		return status, false, err
+	}
+
+	return status, true, nil
+}
+
+func (s *Service) deleteASG(name string, force bool) error {
+	s.scope.Debug("Attempting to delete ASG", "name", name, "force", force)
 
 	input := &autoscaling.DeleteAutoScalingGroupInput{
 		AutoScalingGroupName: aws.String(name),
-		ForceDelete:          aws.Bool(true),
+		ForceDelete:          aws.Bool(force),
 	}
 
 	if _, err := s.ASGClient.DeleteAutoScalingGroupWithContext(context.TODO(), input); err != nil {
@@ -288,36 +469,73 @@ func (s *Service) DeleteASG(name string) error {
 	return nil
 }
 
-// UpdateASG will update the ASG of a service.
-func (s *Service) UpdateASG(machinePoolScope *scope.MachinePoolScope) error {
+// UpdateASG will update the ASG of a service. The subnets of the spec are resolved again, and the VPCZoneIdentifier
+// of the existing ASG is only updated when they differ from its subnets.
+func (s *Service) UpdateASG(machinePoolScope *scope.MachinePoolScope, existingASG *expinfrav1.AutoScalingGroup) error {
 	subnetIDs, err := s.SubnetIDs(machinePoolScope)
 	if err != nil {
 		return fmt.Errorf("getting subnets for ASG: %w", err)
 	}
+	// The subnets of the evacuated availability zones are left out until the evacuation ends.
+	subnetIDs = machinePoolScope.AWSMachinePool.WithoutEvacuatedSubnets(subnetIDs)
 
+	minSize, maxSize := machinePoolScope.AWSMachinePool.SizeBounds()
+	managedMinSize, managedMaxSize, managedDesiredCapacity := machinePoolScope.ManagedSizes()
 	input := &autoscaling.UpdateAutoScalingGroupInput{
-		AutoScalingGroupName: aws.String(machinePoolScope.Name()), // TODO: define dynamically - borrow logic from ec2
-		MaxSize:              aws.Int64(int64(machinePoolScope.AWSMachinePool.Spec.MaxSize)),
-		MinSize:              aws.Int64(int64(machinePoolScope.AWSMachinePool.Spec.MinSize)),
-		VPCZoneIdentifier:    aws.String(strings.Join(subnetIDs, ",")),
-		CapacityRebalance:    aws.Bool(machinePoolScope.AWSMachinePool.Spec.CapacityRebalance),
+		AutoScalingGroupName:             aws.String(machinePoolScope.ASGName()), // TODO: define dynamically - borrow logic from ec2
+		CapacityRebalance:                aws.Bool(machinePoolScope.AWSMachinePool.Spec.CapacityRebalance),
+		NewInstancesProtectedFromScaleIn: aws.Bool(machinePoolScope.AWSMachinePool.Spec.NewInstancesProtectedFromScaleIn),
+	}
+	if existingASG == nil || subnetsChanged(subnetIDs, existingASG.Subnets) {
+		if existingASG != nil {
+			machinePoolScope.Info("updating subnets of ASG", "subnets", subnetIDs, "previous-subnets", existingASG.Subnets)
+		}
+		input.VPCZoneIdentifier = aws.String(strings.Join(subnetIDs, ","))
+	}
+
+	// The sizes which aren't managed by CAPA are omitted, so that they aren't reset to the spec when they
+	// were changed by the scheduled actions or an external autoscaler since the last reconciliation.
+	if managedMinSize {
+		input.MinSize = aws.Int64(int64(minSize))
+	}
+	if managedMaxSize {
+		input.MaxSize = aws.Int64(int64(maxSize))
+	}
+
+	// The termination policies of the Auto Scaling group are left unchanged when they aren't set.
+	if len(machinePoolScope.AWSMachinePool.Spec.TerminationPolicies) > 0 {
+		input.TerminationPolicies = aws.StringSlice(machinePoolScope.AWSMachinePool.Spec.TerminationPolicies)
 	}
 
-	if machinePoolScope.MachinePool.Spec.Replicas != nil && !annotations.ReplicasManagedByExternalAutoscaler(machinePoolScope.MachinePool) {
-		input.DesiredCapacity = aws.Int64(int64(*machinePoolScope.MachinePool.Spec.Replicas))
+	// The same goes for the health check type and grace period.
+	if healthCheckType := machinePoolScope.AWSMachinePool.Spec.HealthCheckType; healthCheckType != "" {
+		input.HealthCheckType = aws.String(string(healthCheckType))
+	}
+	if gracePeriod := machinePoolScope.AWSMachinePool.Spec.HealthCheckGracePeriod; gracePeriod != nil {
+		input.HealthCheckGracePeriod = aws.Int64(int64(gracePeriod.Duration.Seconds()))
+	}
+	// An explicit 0 disables the default instance warmup, while an unset one leaves it unchanged.
+	if warmup := machinePoolScope.AWSMachinePool.Spec.DefaultInstanceWarmup; warmup != nil {
+		input.DefaultInstanceWarmup = aws.Int64(int64(warmup.Duration.Seconds()))
+	}
+
+	// The max instance lifetime is always set, as 0 clears the max instance lifetime of the Auto Scaling group.
+	input.MaxInstanceLifetime = aws.Int64(maxInstanceLifetimeSeconds(machinePoolScope.AWSMachinePool.Spec.MaxInstanceLifetime))
+
+	if machinePoolScope.MachinePool.Spec.Replicas != nil && managedDesiredCapacity {
+		// A scale-down limited by the scale-down policy goes through the desired capacity of the current step,
+		// and the evacuation of availability zones may raise it above the replicas.
+		input.DesiredCapacity = aws.Int64(int64(machinePoolScope.DesiredCapacity()))
 	}
 
 	if machinePoolScope.AWSMachinePool.Spec.MixedInstancesPolicy != nil {
-		input.MixedInstancesPolicy = createSDKMixedInstancesPolicy(machinePoolScope.Name(), machinePoolScope.AWSMachinePool.Spec.MixedInstancesPolicy)
+		input.MixedInstancesPolicy = createSDKMixedInstancesPolicy(launchTemplateSpecification(machinePoolScope), machinePoolScope.AWSMachinePool.Spec.MixedInstancesPolicy)
 	} else {
-		input.LaunchTemplate = &autoscaling.LaunchTemplateSpecification{
-			LaunchTemplateId: aws.String(machinePoolScope.AWSMachinePool.Status.LaunchTemplateID),
-			Version:          aws.String(expinfrav1.LaunchTemplateLatestVersion),
-		}
+		input.LaunchTemplate = launchTemplateSpecification(machinePoolScope)
 	}
 
 	if _, err := s.ASGClient.UpdateAutoScalingGroupWithContext(context.TODO(), input); err != nil {
-		return errors.Wrapf(err, "failed to update ASG %q", machinePoolScope.Name())
+		return errors.Wrapf(err, "failed to update ASG %q", machinePoolScope.ASGName())
 	}
 
 	return nil
@@ -325,7 +543,7 @@ func (s *Service) UpdateASG(machinePoolScope *scope.MachinePoolScope) error {
 
 // CanStartASGInstanceRefresh will start an ASG instance with refresh.
 func (s *Service) CanStartASGInstanceRefresh(scope *scope.MachinePoolScope) (bool, error) {
-	describeInput := &autoscaling.DescribeInstanceRefreshesInput{AutoScalingGroupName: aws.String(scope.Name())}
+	describeInput := &autoscaling.DescribeInstanceRefreshesInput{AutoScalingGroupName: aws.String(scope.ASGName())}
 	refreshes, err := s.ASGClient.DescribeInstanceRefreshesWithContext(context.TODO(), describeInput)
 	if err != nil {
 		return false, err
@@ -349,63 +567,243 @@ func (s *Service) CanStartASGInstanceRefresh(scope *scope.MachinePoolScope) (boo
 // StartASGInstanceRefresh will start an ASG instance with refresh.
 func (s *Service) StartASGInstanceRefresh(scope *scope.MachinePoolScope) error {
 	strategy := ptr.To[string](autoscaling.RefreshStrategyRolling)
-	var minHealthyPercentage, instanceWarmup *int64
-	if scope.AWSMachinePool.Spec.RefreshPreferences != nil {
-		if scope.AWSMachinePool.Spec.RefreshPreferences.Strategy != nil {
-			strategy = scope.AWSMachinePool.Spec.RefreshPreferences.Strategy
+	preferences := &autoscaling.RefreshPreferences{}
+	if refreshPreferences := scope.AWSMachinePool.Spec.RefreshPreferences; refreshPreferences != nil {
+		if refreshPreferences.Strategy != nil {
+			strategy = refreshPreferences.Strategy
 		}
-		if scope.AWSMachinePool.Spec.RefreshPreferences.InstanceWarmup != nil {
-			instanceWarmup = scope.AWSMachinePool.Spec.RefreshPreferences.InstanceWarmup
-		}
-		if scope.AWSMachinePool.Spec.RefreshPreferences.MinHealthyPercentage != nil {
-			minHealthyPercentage = scope.AWSMachinePool.Spec.RefreshPreferences.MinHealthyPercentage
+		preferences.InstanceWarmup = refreshPreferences.InstanceWarmup
+		preferences.MinHealthyPercentage = refreshPreferences.MinHealthyPercentage
+		preferences.SkipMatching = refreshPreferences.SkipMatching
+		preferences.AutoRollback = refreshPreferences.AutoRollback
+		if len(refreshPreferences.CheckpointPercentages) > 0 {
+			preferences.CheckpointPercentages = aws.Int64Slice(refreshPreferences.CheckpointPercentages)
+			preferences.CheckpointDelay = refreshPreferences.CheckpointDelay
 		}
 	}
 
 	input := &autoscaling.StartInstanceRefreshInput{
-		AutoScalingGroupName: aws.String(scope.Name()),
+		AutoScalingGroupName: aws.String(scope.ASGName()),
 		Strategy:             strategy,
-		Preferences: &autoscaling.RefreshPreferences{
-			InstanceWarmup:       instanceWarmup,
-			MinHealthyPercentage: minHealthyPercentage,
-		},
+		Preferences:          preferences,
+	}
+	// The instances excluded from instance refreshes are protected from scale in. By default, the
+	// instance refresh waits for the protection to be removed and fails after an hour.
+	if len(scope.AWSMachinePool.Status.RefreshExcludedInstances) > 0 {
+		input.Preferences.ScaleInProtectedInstances = aws.String(autoscaling.ScaleInProtectedInstancesIgnore)
+	}
+
+	out, err := s.ASGClient.StartInstanceRefreshWithContext(context.TODO(), input)
+	if err != nil {
+		return errors.Wrapf(err, "failed to start ASG instance refresh %q", scope.ASGName())
+	}
+
+	// The progress of the instance refresh is tracked in the status until it completes.
+	scope.AWSMachinePool.Status.InstanceRefresh = &expinfrav1.InstanceRefreshStatus{
+		ID:        aws.StringValue(out.InstanceRefreshId),
+		Status:    autoscaling.InstanceRefreshStatusPending,
+		StartTime: ptr.To(metav1.Now()),
+	}
+
+	return nil
+}
+
+// CancelASGInstanceRefresh cancels the active instance refresh of an autoscaling group. The cancellation is
+// asynchronous: the instance refresh is Cancelling until the instances being replaced are done.
+func (s *Service) CancelASGInstanceRefresh(scope *scope.MachinePoolScope) error {
+	input := &autoscaling.CancelInstanceRefreshInput{
+		AutoScalingGroupName: aws.String(scope.ASGName()),
+	}
+
+	if _, err := s.ASGClient.CancelInstanceRefreshWithContext(context.TODO(), input); err != nil {
+		// The instance refresh completed in the meantime.
+		if code, ok := awserrors.Code(err); ok && code == autoscaling.ErrCodeActiveInstanceRefreshNotFoundFault {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to cancel ASG instance refresh %q", scope.ASGName())
 	}
 
-	if _, err := s.ASGClient.StartInstanceRefreshWithContext(context.TODO(), input); err != nil {
-		return errors.Wrapf(err, "failed to start ASG instance refresh %q", scope.Name())
+	if refresh := scope.AWSMachinePool.Status.InstanceRefresh; refresh != nil && refresh.IsActive() {
+		refresh.Status = autoscaling.InstanceRefreshStatusCancelling
 	}
 
 	return nil
 }
 
-func createSDKMixedInstancesPolicy(name string, i *expinfrav1.MixedInstancesPolicy) *autoscaling.MixedInstancesPolicy {
+// DescribeInstanceRefresh returns the status of an instance refresh of an autoscaling group.
+func (s *Service) DescribeInstanceRefresh(name, id string) (*expinfrav1.InstanceRefreshStatus, error) {
+	input := &autoscaling.DescribeInstanceRefreshesInput{
+		AutoScalingGroupName: aws.String(name),
+		InstanceRefreshIds:   aws.StringSlice([]string{id}),
+	}
+
+	out, err := s.ASGClient.DescribeInstanceRefreshesWithContext(context.TODO(), input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe instance refresh %q of AutoScalingGroup: %q", id, name)
+	}
+	if len(out.InstanceRefreshes) == 0 {
+		return nil, errors.Errorf("instance refresh %q of AutoScalingGroup %q not found", id, name)
+	}
+
+	refresh := out.InstanceRefreshes[0]
+	status := &expinfrav1.InstanceRefreshStatus{
+		ID:                 aws.StringValue(refresh.InstanceRefreshId),
+		Status:             aws.StringValue(refresh.Status),
+		StatusReason:       aws.StringValue(refresh.StatusReason),
+		PercentageComplete: int32(aws.Int64Value(refresh.PercentageComplete)),
+		InstancesToUpdate:  int32(aws.Int64Value(refresh.InstancesToUpdate)),
+	}
+	if refresh.StartTime != nil {
+		status.StartTime = &metav1.Time{Time: *refresh.StartTime}
+	}
+
+	return status, nil
+}
+
+// launchTemplateSpecification returns the launch template the AutoScalingGroup of the machine pool is configured
+// with. A launch template managed by CAPA is always used at its latest version.
+func launchTemplateSpecification(machinePoolScope *scope.MachinePoolScope) *autoscaling.LaunchTemplateSpecification {
+	if existingTemplate := machinePoolScope.AWSMachinePool.Spec.AWSLaunchTemplate.ExistingTemplate; existingTemplate != nil {
+		return &autoscaling.LaunchTemplateSpecification{
+			LaunchTemplateId: aws.String(existingTemplate.ID),
+			Version:          aws.String(existingTemplate.GetVersion()),
+		}
+	}
+
+	if machinePoolScope.AWSMachinePool.Spec.MixedInstancesPolicy != nil {
+		return &autoscaling.LaunchTemplateSpecification{
+			LaunchTemplateName: aws.String(machinePoolScope.Name()),
+			Version:            aws.String(expinfrav1.LaunchTemplateLatestVersion),
+		}
+	}
+
+	return &autoscaling.LaunchTemplateSpecification{
+		LaunchTemplateId: aws.String(machinePoolScope.AWSMachinePool.Status.LaunchTemplateID),
+		Version:          aws.String(expinfrav1.LaunchTemplateLatestVersion),
+	}
+}
+
+func createSDKMixedInstancesPolicy(launchTemplate *autoscaling.LaunchTemplateSpecification, i *expinfrav1.MixedInstancesPolicy) *autoscaling.MixedInstancesPolicy {
 	mixedInstancesPolicy := &autoscaling.MixedInstancesPolicy{
 		LaunchTemplate: &autoscaling.LaunchTemplate{
-			LaunchTemplateSpecification: &autoscaling.LaunchTemplateSpecification{
-				LaunchTemplateName: aws.String(name),
-				Version:            aws.String(expinfrav1.LaunchTemplateLatestVersion),
-			},
+			LaunchTemplateSpecification: launchTemplate,
 		},
 	}
 
 	if i.InstancesDistribution != nil {
 		mixedInstancesPolicy.InstancesDistribution = &autoscaling.InstancesDistribution{
-			OnDemandAllocationStrategy:          aws.String(string(i.InstancesDistribution.OnDemandAllocationStrategy)),
 			OnDemandBaseCapacity:                i.InstancesDistribution.OnDemandBaseCapacity,
 			OnDemandPercentageAboveBaseCapacity: i.InstancesDistribution.OnDemandPercentageAboveBaseCapacity,
-			SpotAllocationStrategy:              aws.String(string(i.InstancesDistribution.SpotAllocationStrategy)),
+		}
+		// AWS uses its default allocation strategies for the strategies which aren't set.
+		if strategy := i.InstancesDistribution.OnDemandAllocationStrategy; strategy != "" {
+			mixedInstancesPolicy.InstancesDistribution.OnDemandAllocationStrategy = aws.String(string(strategy))
+		}
+		if strategy := i.InstancesDistribution.SpotAllocationStrategy; strategy != "" {
+			mixedInstancesPolicy.InstancesDistribution.SpotAllocationStrategy = aws.String(string(strategy))
 		}
 	}
 
 	for _, override := range i.Overrides {
-		mixedInstancesPolicy.LaunchTemplate.Overrides = append(mixedInstancesPolicy.LaunchTemplate.Overrides, &autoscaling.LaunchTemplateOverrides{
-			InstanceType: aws.String(override.InstanceType),
-		})
+		sdkOverride := &autoscaling.LaunchTemplateOverrides{
+			InstanceRequirements: sdkInstanceRequirements(override.InstanceRequirements),
+		}
+		if override.InstanceType != "" {
+			sdkOverride.InstanceType = aws.String(override.InstanceType)
+		}
+		if override.WeightedCapacity != "" {
+			sdkOverride.WeightedCapacity = aws.String(override.WeightedCapacity)
+		}
+		mixedInstancesPolicy.LaunchTemplate.Overrides = append(mixedInstancesPolicy.LaunchTemplate.Overrides, sdkOverride)
 	}
 
 	return mixedInstancesPolicy
 }
 
+// sdkInstanceRequirements returns the instance requirements of a mixed instances policy override in the format of
+// the SDK, or nil if the override lists an instance type instead.
+func sdkInstanceRequirements(r *expinfrav1.InstanceRequirements) *autoscaling.InstanceRequirements {
+	if r == nil {
+		return nil
+	}
+
+	requirements := &autoscaling.InstanceRequirements{
+		VCpuCount: &autoscaling.VCpuCountRequest{
+			Min: aws.Int64(r.VCPUCount.Min),
+			Max: r.VCPUCount.Max,
+		},
+		MemoryMiB: &autoscaling.MemoryMiBRequest{
+			Min: aws.Int64(r.MemoryMiB.Min),
+			Max: r.MemoryMiB.Max,
+		},
+	}
+	if len(r.AllowedInstanceTypes) > 0 {
+		requirements.AllowedInstanceTypes = aws.StringSlice(r.AllowedInstanceTypes)
+	}
+	if len(r.ExcludedInstanceTypes) > 0 {
+		requirements.ExcludedInstanceTypes = aws.StringSlice(r.ExcludedInstanceTypes)
+	}
+	for _, manufacturer := range r.CPUManufacturers {
+		requirements.CpuManufacturers = append(requirements.CpuManufacturers, aws.String(string(manufacturer)))
+	}
+	if r.BareMetal != "" {
+		requirements.BareMetal = aws.String(string(r.BareMetal))
+	}
+	if r.BurstablePerformance != "" {
+		requirements.BurstablePerformance = aws.String(string(r.BurstablePerformance))
+	}
+	if r.AcceleratorCount != nil {
+		requirements.AcceleratorCount = &autoscaling.AcceleratorCountRequest{
+			Min: r.AcceleratorCount.Min,
+			Max: r.AcceleratorCount.Max,
+		}
+	}
+
+	return requirements
+}
+
+// instanceRequirementsFromSDK returns the instance requirements of a mixed instances policy override of an Auto
+// Scaling group, or nil if the override lists an instance type instead.
+func instanceRequirementsFromSDK(r *autoscaling.InstanceRequirements) *expinfrav1.InstanceRequirements {
+	if r == nil {
+		return nil
+	}
+
+	requirements := &expinfrav1.InstanceRequirements{
+		BareMetal:            expinfrav1.InstanceRequirementsFilter(aws.StringValue(r.BareMetal)),
+		BurstablePerformance: expinfrav1.InstanceRequirementsFilter(aws.StringValue(r.BurstablePerformance)),
+	}
+	if r.VCpuCount != nil {
+		requirements.VCPUCount = expinfrav1.VCPUCountRequest{
+			Min: aws.Int64Value(r.VCpuCount.Min),
+			Max: r.VCpuCount.Max,
+		}
+	}
+	if r.MemoryMiB != nil {
+		requirements.MemoryMiB = expinfrav1.MemoryMiBRequest{
+			Min: aws.Int64Value(r.MemoryMiB.Min),
+			Max: r.MemoryMiB.Max,
+		}
+	}
+	for _, manufacturer := range r.CpuManufacturers {
+		requirements.CPUManufacturers = append(requirements.CPUManufacturers, expinfrav1.CPUManufacturer(aws.StringValue(manufacturer)))
+	}
+	if len(r.AllowedInstanceTypes) > 0 {
+		requirements.AllowedInstanceTypes = aws.StringValueSlice(r.AllowedInstanceTypes)
+	}
+	if len(r.ExcludedInstanceTypes) > 0 {
+		requirements.ExcludedInstanceTypes = aws.StringValueSlice(r.ExcludedInstanceTypes)
+	}
+	if r.AcceleratorCount != nil {
+		requirements.AcceleratorCount = &expinfrav1.AcceleratorCountRequest{
+			Min: r.AcceleratorCount.Min,
+			Max: r.AcceleratorCount.Max,
+		}
+	}
+
+	return requirements
+}
+
 // BuildTagsFromMap takes a map of keys and values and returns them as autoscaling group tags.
 func BuildTagsFromMap(asgName string, inTags map[string]string) []*autoscaling.Tag {
 	if inTags == nil {
@@ -496,6 +894,221 @@ func (s *Service) ResumeProcesses(name string, processes []string) error {
 	return nil
 }
 
+// EnableMetricsCollection enables the collection of group metrics for an autoscaling group.
+func (s *Service) EnableMetricsCollection(name, granularity string, metrics []string) error {
+	input := autoscaling.EnableMetricsCollectionInput{
+		AutoScalingGroupName: aws.String(name),
+		Granularity:          aws.String(granularity),
+		Metrics:              aws.StringSlice(metrics),
+	}
+	if _, err := s.ASGClient.EnableMetricsCollectionWithContext(context.TODO(), &input); err != nil {
+		return errors.Wrapf(err, "failed to enable metrics collection for AutoScalingGroup: %q", name)
+	}
+	return nil
+}
+
+// DisableMetricsCollection disables the collection of group metrics for an autoscaling group.
+func (s *Service) DisableMetricsCollection(name string, metrics []string) error {
+	input := autoscaling.DisableMetricsCollectionInput{
+		AutoScalingGroupName: aws.String(name),
+		Metrics:              aws.StringSlice(metrics),
+	}
+	if _, err := s.ASGClient.DisableMetricsCollectionWithContext(context.TODO(), &input); err != nil {
+		return errors.Wrapf(err, "failed to disable metrics collection for AutoScalingGroup: %q", name)
+	}
+	return nil
+}
+
+// maxTargetGroupsPerRequest is the maximum number of target groups that can be attached to
+// or detached from an autoscaling group in a single request.
+const maxTargetGroupsPerRequest = 10
+
+// DescribeTargetGroupAttachments returns the ARNs of the target groups attached to an autoscaling group,
+// excluding the ones that are being detached.
+func (s *Service) DescribeTargetGroupAttachments(name string) ([]string, error) {
+	input := &autoscaling.DescribeLoadBalancerTargetGroupsInput{
+		AutoScalingGroupName: aws.String(name),
+	}
+
+	var arns []string
+	for {
+		out, err := s.ASGClient.DescribeLoadBalancerTargetGroupsWithContext(context.TODO(), input)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to describe target groups of AutoScalingGroup: %q", name)
+		}
+		for _, tg := range out.LoadBalancerTargetGroups {
+			switch aws.StringValue(tg.State) {
+			case "Removing", "Removed":
+				continue
+			}
+			arns = append(arns, aws.StringValue(tg.LoadBalancerTargetGroupARN))
+		}
+		if out.NextToken == nil {
+			return arns, nil
+		}
+		input.NextToken = out.NextToken
+	}
+}
+
+// AttachTargetGroups attaches the target groups to an autoscaling group.
+func (s *Service) AttachTargetGroups(name string, arns []string) error {
+	for start := 0; start < len(arns); start += maxTargetGroupsPerRequest {
+		end := min(start+maxTargetGroupsPerRequest, len(arns))
+		input := &autoscaling.AttachLoadBalancerTargetGroupsInput{
+			AutoScalingGroupName: aws.String(name),
+			TargetGroupARNs:      aws.StringSlice(arns[start:end]),
+		}
+		if _, err := s.ASGClient.AttachLoadBalancerTargetGroupsWithContext(context.TODO(), input); err != nil {
+			return errors.Wrapf(err, "failed to attach target groups to AutoScalingGroup: %q", name)
+		}
+	}
+	return nil
+}
+
+// DetachTargetGroups detaches the target groups from an autoscaling group.
+func (s *Service) DetachTargetGroups(name string, arns []string) error {
+	for start := 0; start < len(arns); start += maxTargetGroupsPerRequest {
+		end := min(start+maxTargetGroupsPerRequest, len(arns))
+		input := &autoscaling.DetachLoadBalancerTargetGroupsInput{
+			AutoScalingGroupName: aws.String(name),
+			TargetGroupARNs:      aws.StringSlice(arns[start:end]),
+		}
+		if _, err := s.ASGClient.DetachLoadBalancerTargetGroupsWithContext(context.TODO(), input); err != nil {
+			return errors.Wrapf(err, "failed to detach target groups from AutoScalingGroup: %q", name)
+		}
+	}
+	return nil
+}
+
+// maxLoadBalancersPerRequest is the maximum number of Classic Load Balancers that can be attached to
+// or detached from an autoscaling group in a single request.
+const maxLoadBalancersPerRequest = 10
+
+// AttachLoadBalancers attaches the Classic Load Balancers to an autoscaling group.
+func (s *Service) AttachLoadBalancers(name string, loadBalancerNames []string) error {
+	for start := 0; start < len(loadBalancerNames); start += maxLoadBalancersPerRequest {
+		end := min(start+maxLoadBalancersPerRequest, len(loadBalancerNames))
+		input := &autoscaling.AttachLoadBalancersInput{
+			AutoScalingGroupName: aws.String(name),
+			LoadBalancerNames:    aws.StringSlice(loadBalancerNames[start:end]),
+		}
+		if _, err := s.ASGClient.AttachLoadBalancersWithContext(context.TODO(), input); err != nil {
+			return errors.Wrapf(err, "failed to attach classic load balancers to AutoScalingGroup: %q", name)
+		}
+	}
+	return nil
+}
+
+// DetachLoadBalancers detaches the Classic Load Balancers from an autoscaling group.
+func (s *Service) DetachLoadBalancers(name string, loadBalancerNames []string) error {
+	for start := 0; start < len(loadBalancerNames); start += maxLoadBalancersPerRequest {
+		end := min(start+maxLoadBalancersPerRequest, len(loadBalancerNames))
+		input := &autoscaling.DetachLoadBalancersInput{
+			AutoScalingGroupName: aws.String(name),
+			LoadBalancerNames:    aws.StringSlice(loadBalancerNames[start:end]),
+		}
+		if _, err := s.ASGClient.DetachLoadBalancersWithContext(context.TODO(), input); err != nil {
+			return errors.Wrapf(err, "failed to detach classic load balancers from AutoScalingGroup: %q", name)
+		}
+	}
+	return nil
+}
+
+// maxInstancesPerProtectionRequest is the maximum number of instances whose scale-in protection can
+// be set in a single request.
+const maxInstancesPerProtectionRequest = 50
+
+// SetInstanceProtection sets or removes the scale-in protection of instances of an autoscaling group.
+func (s *Service) SetInstanceProtection(name string, instanceIDs []string, protected bool) error {
+	for start := 0; start < len(instanceIDs); start += maxInstancesPerProtectionRequest {
+		end := min(start+maxInstancesPerProtectionRequest, len(instanceIDs))
+		input := &autoscaling.SetInstanceProtectionInput{
+			AutoScalingGroupName: aws.String(name),
+			InstanceIds:          aws.StringSlice(instanceIDs[start:end]),
+			ProtectedFromScaleIn: aws.Bool(protected),
+		}
+		if _, err := s.ASGClient.SetInstanceProtectionWithContext(context.TODO(), input); err != nil {
+			return errors.Wrapf(err, "failed to set instance protection for AutoScalingGroup: %q", name)
+		}
+	}
+	return nil
+}
+
+// maxInstancesPerDetachRequest is the maximum number of instances that can be detached in a single request.
+const maxInstancesPerDetachRequest = 20
+
+// DetachInstances detaches instances from an autoscaling group without decrementing its desired capacity, so
+// that the group launches new instances to replace them. The detached instances keep running.
+func (s *Service) DetachInstances(name string, instanceIDs []string) error {
+	for start := 0; start < len(instanceIDs); start += maxInstancesPerDetachRequest {
+		end := min(start+maxInstancesPerDetachRequest, len(instanceIDs))
+		input := &autoscaling.DetachInstancesInput{
+			AutoScalingGroupName:           aws.String(name),
+			InstanceIds:                    aws.StringSlice(instanceIDs[start:end]),
+			ShouldDecrementDesiredCapacity: aws.Bool(false),
+		}
+		if _, err := s.ASGClient.DetachInstancesWithContext(context.TODO(), input); err != nil {
+			return errors.Wrapf(err, "failed to detach instances from AutoScalingGroup: %q", name)
+		}
+	}
+	return nil
+}
+
+// DescribeScalingActivities returns the most recent scaling activities of an autoscaling group, newest first.
+func (s *Service) DescribeScalingActivities(name string) ([]*expinfrav1.ScalingActivity, error) {
+	input := &autoscaling.DescribeScalingActivitiesInput{
+		AutoScalingGroupName: aws.String(name),
+		MaxRecords:           aws.Int64(20),
+	}
+
+	out, err := s.ASGClient.DescribeScalingActivitiesWithContext(context.TODO(), input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe scaling activities for AutoScalingGroup: %q", name)
+	}
+
+	activities := make([]*expinfrav1.ScalingActivity, 0, len(out.Activities))
+	for _, activity := range out.Activities {
+		a := &expinfrav1.ScalingActivity{
+			ActivityID:    aws.StringValue(activity.ActivityId),
+			Description:   aws.StringValue(activity.Description),
+			StatusCode:    aws.StringValue(activity.StatusCode),
+			StatusMessage: aws.StringValue(activity.StatusMessage),
+		}
+		if activity.StartTime != nil {
+			a.StartTime = &metav1.Time{Time: *activity.StartTime}
+		}
+		activities = append(activities, a)
+	}
+
+	return activities, nil
+}
+
+// OpenSpotInstanceRequestCount returns the number of open spot instance requests for a launch template.
+func (s *Service) OpenSpotInstanceRequestCount(launchTemplateID string) (int32, error) {
+	input := &ec2.DescribeSpotInstanceRequestsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("state"),
+				Values: aws.StringSlice([]string{ec2.SpotInstanceStateOpen}),
+			},
+			{
+				Name:   aws.String("tag:aws:ec2launchtemplate:id"),
+				Values: aws.StringSlice([]string{launchTemplateID}),
+			},
+		},
+	}
+
+	var count int32
+	if err := s.EC2Client.DescribeSpotInstanceRequestsPagesWithContext(context.TODO(), input, func(out *ec2.DescribeSpotInstanceRequestsOutput, _ bool) bool {
+		count += int32(len(out.SpotInstanceRequests))
+		return true
+	}); err != nil {
+		return 0, errors.Wrapf(err, "failed to describe spot instance requests for launch template: %q", launchTemplateID)
+	}
+
+	return count, nil
+}
+
 func mapToTags(input map[string]string, resourceID *string) []*autoscaling.Tag {
 	tags := make([]*autoscaling.Tag, 0)
 	for k, v := range input {
@@ -514,43 +1127,84 @@ func mapToTags(input map[string]string, resourceID *string) []*autoscaling.Tag {
 	return tags
 }
 
-// SubnetIDs return subnet IDs of a AWSMachinePool based on given subnetIDs and filters.
+// SubnetIDs returns the subnets of the Auto Scaling group of an AWSMachinePool. Every entry of spec.subnets selects
+// either the subnet of its ID, or the subnets matching all its filters, which must match at least one subnet. The
+// subnets matching the filters are ordered by ID, so that the subnets of the Auto Scaling group don't change with
+// the order they are described in. The subnets are only resolved once per reconciliation, and the resolution is
+// reported by the SubnetsReadyCondition.
 func (s *Service) SubnetIDs(scope *scope.MachinePoolScope) ([]string, error) {
-	subnetIDs := make([]string, 0)
-	var inputFilters = make([]*ec2.Filter, 0)
+	if subnetIDs, ok := scope.ResolvedSubnetIDs(); ok {
+		return subnetIDs, nil
+	}
 
+	subnetIDs := make([]string, 0)
 	for _, subnet := range scope.AWSMachinePool.Spec.Subnets {
 		switch {
 		case subnet.ID != nil:
 			subnetIDs = append(subnetIDs, aws.StringValue(subnet.ID))
 		case subnet.Filters != nil:
+			criteria := []*ec2.Filter{filter.EC2.SubnetStates(ec2.SubnetStatePending, ec2.SubnetStateAvailable)}
 			for _, eachFilter := range subnet.Filters {
-				inputFilters = append(inputFilters, &ec2.Filter{
+				criteria = append(criteria, &ec2.Filter{
 					Name:   aws.String(eachFilter.Name),
 					Values: aws.StringSlice(eachFilter.Values),
 				})
 			}
-		}
-	}
 
-	if len(inputFilters) > 0 {
-		out, err := s.EC2Client.DescribeSubnetsWithContext(context.TODO(), &ec2.DescribeSubnetsInput{
-			Filters: inputFilters,
-		})
-		if err != nil {
-			return nil, err
-		}
+			out, err := s.EC2Client.DescribeSubnetsWithContext(context.TODO(), &ec2.DescribeSubnetsInput{
+				Filters: criteria,
+			})
+			if err != nil {
+				conditions.MarkFalse(scope.AWSMachinePool, expinfrav1.SubnetsReadyCondition, expinfrav1.SubnetsResolutionFailedReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
+				return nil, errors.Wrapf(err, "failed to describe subnets matching criteria %q", criteria)
+			}
+			if len(out.Subnets) == 0 {
+				errMessage := fmt.Sprintf("failed to create ASG %q, no subnets available matching criteria %q", scope.ASGName(), criteria)
+				record.Warnf(scope.AWSMachinePool, "FailedCreate", errMessage)
+				conditions.MarkFalse(scope.AWSMachinePool, expinfrav1.SubnetsReadyCondition, expinfrav1.SubnetsNotFoundReason, clusterv1.ConditionSeverityError, "no subnets available matching criteria %q", criteria)
+				return nil, awserrors.NewFailedDependency(errMessage)
+			}
 
-		for _, subnet := range out.Subnets {
-			subnetIDs = append(subnetIDs, *subnet.SubnetId)
+			matching := make([]string, 0, len(out.Subnets))
+			for _, subnet := range out.Subnets {
+				matching = append(matching, aws.StringValue(subnet.SubnetId))
+			}
+			sort.Strings(matching)
+			subnetIDs = append(subnetIDs, matching...)
 		}
+	}
 
-		if len(subnetIDs) == 0 {
-			errMessage := fmt.Sprintf("failed to create ASG %q, no subnets available matching criteria %q", scope.Name(), inputFilters)
-			record.Warnf(scope.AWSMachinePool, "FailedCreate", errMessage)
-			return subnetIDs, awserrors.NewFailedDependency(errMessage)
+	// A subnet selected by several entries is only used once.
+	seen := sets.New[string]()
+	uniqueSubnetIDs := make([]string, 0, len(subnetIDs))
+	for _, id := range subnetIDs {
+		if !seen.Has(id) {
+			seen.Insert(id)
+			uniqueSubnetIDs = append(uniqueSubnetIDs, id)
 		}
 	}
 
-	return scope.SubnetIDs(subnetIDs)
+	placed, err := scope.SubnetIDs(uniqueSubnetIDs)
+	if err != nil {
+		conditions.MarkFalse(scope.AWSMachinePool, expinfrav1.SubnetsReadyCondition, expinfrav1.SubnetsNotFoundReason, clusterv1.ConditionSeverityError, "%s", err.Error())
+		return nil, err
+	}
+	conditions.MarkTrue(scope.AWSMachinePool, expinfrav1.SubnetsReadyCondition)
+	scope.SetResolvedSubnetIDs(placed)
+	return placed, nil
+}
+
+// subnetsChanged returns whether the desired subnets of an Auto Scaling group differ from its current subnets, in
+// any order.
+func subnetsChanged(desired, current []string) bool {
+	return !sets.New(desired...).Equal(sets.New(current...))
+}
+
+// maxInstanceLifetimeSeconds returns the max instance lifetime of an Auto Scaling group in seconds,
+// 0 when it is unset.
+func maxInstanceLifetimeSeconds(lifetime *metav1.Duration) int64 {
+	if lifetime == nil {
+		return 0
+	}
+	return int64(lifetime.Duration.Seconds())
 }
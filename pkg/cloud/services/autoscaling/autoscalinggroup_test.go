@@ -18,10 +18,13 @@ package asg
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -39,9 +42,11 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/autoscaling/mock_autoscalingiface"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/fakeaws"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/test/mocks"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	expclusterv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestServiceGetASGByName(t *testing.T) {
@@ -108,6 +113,35 @@ func TestServiceGetASGByName(t *testing.T) {
 						}}, nil)
 			},
 		},
+		{
+			name:            "should return ASG, if found on the second page of results",
+			machinePoolName: "test-group-on-second-page",
+			wantErr:         false,
+			wantASG:         true,
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.DescribeAutoScalingGroupsWithContext(context.TODO(), gomock.Eq(&autoscaling.DescribeAutoScalingGroupsInput{
+					AutoScalingGroupNames: []*string{
+						aws.String("test-group-on-second-page"),
+					},
+				})).
+					Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+						AutoScalingGroups: []*autoscaling.Group{},
+						NextToken:         aws.String("next"),
+					}, nil)
+				m.DescribeAutoScalingGroupsWithContext(context.TODO(), gomock.Eq(&autoscaling.DescribeAutoScalingGroupsInput{
+					AutoScalingGroupNames: []*string{
+						aws.String("test-group-on-second-page"),
+					},
+					NextToken: aws.String("next"),
+				})).
+					Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+						AutoScalingGroups: []*autoscaling.Group{
+							{
+								AutoScalingGroupName: aws.String("test-group-on-second-page"),
+							},
+						}}, nil)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -148,6 +182,7 @@ func TestServiceSDKToAutoScalingGroup(t *testing.T) {
 				MaxSize:              aws.Int64(1234),
 				MinSize:              aws.Int64(1234),
 				CapacityRebalance:    aws.Bool(true),
+				CreatedTime:          aws.Time(time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)),
 				MixedInstancesPolicy: &autoscaling.MixedInstancesPolicy{
 					InstancesDistribution: &autoscaling.InstancesDistribution{
 						OnDemandAllocationStrategy:          aws.String("prioritized"),
@@ -172,6 +207,7 @@ func TestServiceSDKToAutoScalingGroup(t *testing.T) {
 				MaxSize:           int32(1234),
 				MinSize:           int32(1234),
 				CapacityRebalance: true,
+				CreatedTime:       metav1.NewTime(time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)),
 				MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
 					InstancesDistribution: &expinfrav1.InstancesDistribution{
 						OnDemandAllocationStrategy:          expinfrav1.OnDemandAllocationStrategyPrioritized,
@@ -181,13 +217,49 @@ func TestServiceSDKToAutoScalingGroup(t *testing.T) {
 					},
 					Overrides: []expinfrav1.Overrides{
 						{
-							InstanceType: "t2.medium",
+							InstanceType:     "t2.medium",
+							WeightedCapacity: "test-weighted-cap",
 						},
 					},
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid input - capacity rebalance omitted for on-demand groups",
+			input: &autoscaling.Group{
+				AutoScalingGroupName: aws.String("test-name"),
+				DesiredCapacity:      aws.Int64(1),
+				MaxSize:              aws.Int64(2),
+				MinSize:              aws.Int64(1),
+			},
+			want: &expinfrav1.AutoScalingGroup{
+				Name:              "test-name",
+				DesiredCapacity:   aws.Int32(1),
+				MaxSize:           int32(2),
+				MinSize:           int32(1),
+				CapacityRebalance: false,
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid input - classic load balancers",
+			input: &autoscaling.Group{
+				AutoScalingGroupName: aws.String("test-name"),
+				DesiredCapacity:      aws.Int64(1),
+				MaxSize:              aws.Int64(2),
+				MinSize:              aws.Int64(1),
+				LoadBalancerNames:    aws.StringSlice([]string{"ingress", "legacy"}),
+			},
+			want: &expinfrav1.AutoScalingGroup{
+				Name:              "test-name",
+				DesiredCapacity:   aws.Int32(1),
+				MaxSize:           int32(2),
+				MinSize:           int32(1),
+				LoadBalancerNames: []string{"ingress", "legacy"},
+			},
+			wantErr: false,
+		},
 		{
 			name: "valid input - suspended processes",
 			input: &autoscaling.Group{
@@ -209,6 +281,87 @@ func TestServiceSDKToAutoScalingGroup(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid input - termination policies in evaluation order",
+			input: &autoscaling.Group{
+				DesiredCapacity:     aws.Int64(1),
+				MaxSize:             aws.Int64(2),
+				MinSize:             aws.Int64(1),
+				TerminationPolicies: aws.StringSlice([]string{"OldestLaunchTemplate", "ClosestToNextInstanceHour"}),
+			},
+			want: &expinfrav1.AutoScalingGroup{
+				DesiredCapacity:     aws.Int32(1),
+				MaxSize:             int32(2),
+				MinSize:             int32(1),
+				TerminationPolicies: []string{"OldestLaunchTemplate", "ClosestToNextInstanceHour"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid input - health check",
+			input: &autoscaling.Group{
+				DesiredCapacity:        aws.Int64(1),
+				MaxSize:                aws.Int64(2),
+				MinSize:                aws.Int64(1),
+				HealthCheckType:        aws.String("ELB"),
+				HealthCheckGracePeriod: aws.Int64(120),
+			},
+			want: &expinfrav1.AutoScalingGroup{
+				DesiredCapacity:        aws.Int32(1),
+				MaxSize:                int32(2),
+				MinSize:                int32(1),
+				HealthCheckType:        expinfrav1.HealthCheckTypeELB,
+				HealthCheckGracePeriod: &metav1.Duration{Duration: 2 * time.Minute},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid input - default instance warmup",
+			input: &autoscaling.Group{
+				DesiredCapacity:       aws.Int64(1),
+				MaxSize:               aws.Int64(2),
+				MinSize:               aws.Int64(1),
+				DefaultInstanceWarmup: aws.Int64(0),
+			},
+			want: &expinfrav1.AutoScalingGroup{
+				DesiredCapacity:       aws.Int32(1),
+				MaxSize:               int32(2),
+				MinSize:               int32(1),
+				DefaultInstanceWarmup: &metav1.Duration{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid input - max instance lifetime",
+			input: &autoscaling.Group{
+				DesiredCapacity:     aws.Int64(1),
+				MaxSize:             aws.Int64(2),
+				MinSize:             aws.Int64(1),
+				MaxInstanceLifetime: aws.Int64(14 * 86400),
+			},
+			want: &expinfrav1.AutoScalingGroup{
+				DesiredCapacity:     aws.Int32(1),
+				MaxSize:             int32(2),
+				MinSize:             int32(1),
+				MaxInstanceLifetime: &metav1.Duration{Duration: 14 * 24 * time.Hour},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid input - disabled max instance lifetime",
+			input: &autoscaling.Group{
+				DesiredCapacity:     aws.Int64(1),
+				MaxSize:             aws.Int64(2),
+				MinSize:             aws.Int64(1),
+				MaxInstanceLifetime: aws.Int64(0),
+			},
+			want: &expinfrav1.AutoScalingGroup{
+				DesiredCapacity: aws.Int32(1),
+				MaxSize:         int32(2),
+				MinSize:         int32(1),
+			},
+			wantErr: false,
+		},
 		{
 			name: "valid input - all fields filled",
 			input: &autoscaling.Group{
@@ -265,7 +418,8 @@ func TestServiceSDKToAutoScalingGroup(t *testing.T) {
 					},
 					Overrides: []expinfrav1.Overrides{
 						{
-							InstanceType: "t2.medium",
+							InstanceType:     "t2.medium",
+							WeightedCapacity: "test-weighted-cap",
 						},
 					},
 				},
@@ -488,16 +642,14 @@ func TestServiceCreateASG(t *testing.T) {
 			wantASG:               false,
 			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
 				expected := &autoscaling.CreateAutoScalingGroupInput{
-					AutoScalingGroupName:  aws.String("create-asg-success"),
-					CapacityRebalance:     aws.Bool(false),
-					DefaultCooldown:       aws.Int64(0),
-					DefaultInstanceWarmup: aws.Int64(0),
+					AutoScalingGroupName: aws.String("create-asg-success"),
+					CapacityRebalance:    aws.Bool(false),
+					DefaultCooldown:      aws.Int64(0),
 					MixedInstancesPolicy: &autoscaling.MixedInstancesPolicy{
 						InstancesDistribution: &autoscaling.InstancesDistribution{
 							OnDemandAllocationStrategy:          aws.String("prioritized"),
 							OnDemandBaseCapacity:                aws.Int64(0),
 							OnDemandPercentageAboveBaseCapacity: aws.Int64(100),
-							SpotAllocationStrategy:              aws.String(""),
 						},
 						LaunchTemplate: &autoscaling.LaunchTemplate{
 							LaunchTemplateSpecification: &autoscaling.LaunchTemplateSpecification{
@@ -511,9 +663,10 @@ func TestServiceCreateASG(t *testing.T) {
 							},
 						},
 					},
-					DesiredCapacity: aws.Int64(1),
-					MaxSize:         aws.Int64(2),
-					MinSize:         aws.Int64(1),
+					DesiredCapacity:                  aws.Int64(1),
+					MaxSize:                          aws.Int64(2),
+					MinSize:                          aws.Int64(1),
+					NewInstancesProtectedFromScaleIn: aws.Bool(false),
 					Tags: []*autoscaling.Tag{
 						{
 							Key:               aws.String("kubernetes.io/cluster/test"),
@@ -529,6 +682,13 @@ func TestServiceCreateASG(t *testing.T) {
 							ResourceType:      aws.String("auto-scaling-group"),
 							Value:             aws.String("owned"),
 						},
+						{
+							Key:               aws.String("sigs.k8s.io/cluster-api-provider-aws/machine-pool"),
+							PropagateAtLaunch: aws.Bool(false),
+							ResourceId:        aws.String("create-asg-success"),
+							ResourceType:      aws.String("auto-scaling-group"),
+							Value:             aws.String("/create-asg-success"),
+						},
 						{
 							Key:               aws.String("sigs.k8s.io/cluster-api-provider-aws/role"),
 							PropagateAtLaunch: aws.Bool(false),
@@ -608,6 +768,27 @@ func TestServiceCreateASG(t *testing.T) {
 					})
 			},
 		},
+		{
+			name:            "should attach the target groups and classic load balancers at creation",
+			machinePoolName: "create-asg-success",
+			setupMachinePoolScope: func(mps *scope.MachinePoolScope) {
+				mps.AWSMachinePool.Spec.TargetGroupARNs = []string{"arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/workloads/73e2d6bc24d8a067"}
+				mps.AWSMachinePool.Spec.ClassicLoadBalancers = []string{"ingress"}
+			},
+			wantErr: false,
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.CreateAutoScalingGroupWithContext(context.TODO(), gomock.AssignableToTypeOf(&autoscaling.CreateAutoScalingGroupInput{})).Do(
+					func(ctx context.Context, actual *autoscaling.CreateAutoScalingGroupInput, requestOptions ...request.Option) (*autoscaling.CreateAutoScalingGroupOutput, error) {
+						if got := aws.StringValueSlice(actual.TargetGroupARNs); len(got) != 1 || got[0] != "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/workloads/73e2d6bc24d8a067" {
+							t.Fatalf("Actual TargetGroupARNs did not match expected, Actual: %v", got)
+						}
+						if got := aws.StringValueSlice(actual.LoadBalancerNames); len(got) != 1 || got[0] != "ingress" {
+							t.Fatalf("Actual LoadBalancerNames did not match expected, Actual: %v", got)
+						}
+						return &autoscaling.CreateAutoScalingGroupOutput{}, nil
+					})
+			},
+		},
 		{
 			name:            "should return error if MachinePool replicas number is less than AWSMachinePool MinSize",
 			machinePoolName: "create-asg-fail",
@@ -700,6 +881,7 @@ func TestServiceUpdateASG(t *testing.T) {
 		name                  string
 		machinePoolName       string
 		setupMachinePoolScope func(*scope.MachinePoolScope)
+		existingASG           *expinfrav1.AutoScalingGroup
 		wantErr               bool
 		expect                func(e *mocks.MockEC2APIMockRecorder, m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder, g *WithT)
 	}{
@@ -723,6 +905,81 @@ func TestServiceUpdateASG(t *testing.T) {
 				})
 			},
 		},
+		{
+			name:            "termination policies are updated in order",
+			machinePoolName: "update-asg-termination-policies",
+			wantErr:         false,
+			setupMachinePoolScope: func(mps *scope.MachinePoolScope) {
+				mps.AWSMachinePool.Spec.TerminationPolicies = []string{"OldestLaunchTemplate", "ClosestToNextInstanceHour"}
+			},
+			expect: func(e *mocks.MockEC2APIMockRecorder, m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder, g *WithT) {
+				m.UpdateAutoScalingGroupWithContext(context.TODO(), gomock.AssignableToTypeOf(&autoscaling.UpdateAutoScalingGroupInput{})).DoAndReturn(func(ctx context.Context, input *autoscaling.UpdateAutoScalingGroupInput, options ...request.Option) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
+					g.Expect(aws.StringValueSlice(input.TerminationPolicies)).To(Equal([]string{"OldestLaunchTemplate", "ClosestToNextInstanceHour"}))
+					g.Expect(input.HealthCheckType).To(BeNil())
+					g.Expect(input.HealthCheckGracePeriod).To(BeNil())
+					g.Expect(input.DefaultInstanceWarmup).To(BeNil())
+					return &autoscaling.UpdateAutoScalingGroupOutput{}, nil
+				})
+			},
+		},
+		{
+			name:            "health check is updated",
+			machinePoolName: "update-asg-health-check",
+			wantErr:         false,
+			setupMachinePoolScope: func(mps *scope.MachinePoolScope) {
+				mps.AWSMachinePool.Spec.HealthCheckType = expinfrav1.HealthCheckTypeELB
+				mps.AWSMachinePool.Spec.HealthCheckGracePeriod = &metav1.Duration{Duration: 90 * time.Second}
+			},
+			expect: func(e *mocks.MockEC2APIMockRecorder, m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder, g *WithT) {
+				m.UpdateAutoScalingGroupWithContext(context.TODO(), gomock.AssignableToTypeOf(&autoscaling.UpdateAutoScalingGroupInput{})).DoAndReturn(func(ctx context.Context, input *autoscaling.UpdateAutoScalingGroupInput, options ...request.Option) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
+					g.Expect(input.HealthCheckType).To(BeComparableTo(ptr.To("ELB")))
+					g.Expect(input.HealthCheckGracePeriod).To(BeComparableTo(ptr.To[int64](90)))
+					return &autoscaling.UpdateAutoScalingGroupOutput{}, nil
+				})
+			},
+		},
+		{
+			name:            "default instance warmup is disabled explicitly",
+			machinePoolName: "update-asg-default-instance-warmup",
+			wantErr:         false,
+			setupMachinePoolScope: func(mps *scope.MachinePoolScope) {
+				mps.AWSMachinePool.Spec.DefaultInstanceWarmup = &metav1.Duration{}
+			},
+			expect: func(e *mocks.MockEC2APIMockRecorder, m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder, g *WithT) {
+				m.UpdateAutoScalingGroupWithContext(context.TODO(), gomock.AssignableToTypeOf(&autoscaling.UpdateAutoScalingGroupInput{})).DoAndReturn(func(ctx context.Context, input *autoscaling.UpdateAutoScalingGroupInput, options ...request.Option) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
+					g.Expect(input.DefaultInstanceWarmup).To(BeComparableTo(ptr.To[int64](0)))
+					return &autoscaling.UpdateAutoScalingGroupOutput{}, nil
+				})
+			},
+		},
+		{
+			name:            "max instance lifetime is updated",
+			machinePoolName: "update-asg-max-instance-lifetime",
+			wantErr:         false,
+			setupMachinePoolScope: func(mps *scope.MachinePoolScope) {
+				mps.AWSMachinePool.Spec.MaxInstanceLifetime = &metav1.Duration{Duration: 14 * 24 * time.Hour}
+			},
+			expect: func(e *mocks.MockEC2APIMockRecorder, m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder, g *WithT) {
+				m.UpdateAutoScalingGroupWithContext(context.TODO(), gomock.AssignableToTypeOf(&autoscaling.UpdateAutoScalingGroupInput{})).DoAndReturn(func(ctx context.Context, input *autoscaling.UpdateAutoScalingGroupInput, options ...request.Option) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
+					g.Expect(input.MaxInstanceLifetime).To(BeComparableTo(ptr.To[int64](1209600)))
+					return &autoscaling.UpdateAutoScalingGroupOutput{}, nil
+				})
+			},
+		},
+		{
+			name:            "unset max instance lifetime is cleared",
+			machinePoolName: "update-asg-no-max-instance-lifetime",
+			wantErr:         false,
+			setupMachinePoolScope: func(mps *scope.MachinePoolScope) {
+				mps.AWSMachinePool.Spec.MaxInstanceLifetime = nil
+			},
+			expect: func(e *mocks.MockEC2APIMockRecorder, m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder, g *WithT) {
+				m.UpdateAutoScalingGroupWithContext(context.TODO(), gomock.AssignableToTypeOf(&autoscaling.UpdateAutoScalingGroupInput{})).DoAndReturn(func(ctx context.Context, input *autoscaling.UpdateAutoScalingGroupInput, options ...request.Option) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
+					g.Expect(input.MaxInstanceLifetime).To(BeComparableTo(ptr.To[int64](0)))
+					return &autoscaling.UpdateAutoScalingGroupOutput{}, nil
+				})
+			},
+		},
 		{
 			name:            "should return error if update ASG fails",
 			machinePoolName: "update-asg-fail",
@@ -745,6 +1002,28 @@ func TestServiceUpdateASG(t *testing.T) {
 				mps.AWSMachinePool.Spec.MinSize = 20
 				mps.AWSMachinePool.Spec.MaxSize = 50
 			},
+			expect: func(e *mocks.MockEC2APIMockRecorder, m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder, g *WithT) {
+				m.UpdateAutoScalingGroupWithContext(context.TODO(), gomock.AssignableToTypeOf(&autoscaling.UpdateAutoScalingGroupInput{})).DoAndReturn(func(ctx context.Context, input *autoscaling.UpdateAutoScalingGroupInput, options ...request.Option) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
+					// CAPA should leave min/max and the "desired" number of instances to the external autoscaler
+					g.Expect(input.MinSize).To(BeNil())
+					g.Expect(input.MaxSize).To(BeNil())
+					g.Expect(input.DesiredCapacity).To(BeNil())
+					return &autoscaling.UpdateAutoScalingGroupOutput{}, nil
+				})
+			},
+		},
+		{
+			name:            "externally managed replicas annotation with size bounds synced",
+			machinePoolName: "update-asg-externally-managed-replicas-sync-size-bounds",
+			wantErr:         false,
+			setupMachinePoolScope: func(mps *scope.MachinePoolScope) {
+				mps.MachinePool.SetAnnotations(map[string]string{clusterv1.ReplicasManagedByAnnotation: "cluster-autoscaler"})
+
+				mps.MachinePool.Spec.Replicas = ptr.To[int32](40)
+				mps.AWSMachinePool.Spec.MinSize = 20
+				mps.AWSMachinePool.Spec.MaxSize = 50
+				mps.AWSMachinePool.Spec.SyncSizeBoundsWithExternalAutoscaler = true
+			},
 			expect: func(e *mocks.MockEC2APIMockRecorder, m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder, g *WithT) {
 				m.UpdateAutoScalingGroupWithContext(context.TODO(), gomock.AssignableToTypeOf(&autoscaling.UpdateAutoScalingGroupInput{})).DoAndReturn(func(ctx context.Context, input *autoscaling.UpdateAutoScalingGroupInput, options ...request.Option) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
 					// CAPA should set min/max, but not the externally managed "desired" number of instances
@@ -755,6 +1034,111 @@ func TestServiceUpdateASG(t *testing.T) {
 				})
 			},
 		},
+		{
+			name:            "autoscaling enabled",
+			machinePoolName: "update-asg-autoscaling-enabled",
+			wantErr:         false,
+			setupMachinePoolScope: func(mps *scope.MachinePoolScope) {
+				mps.MachinePool.SetAnnotations(map[string]string{clusterv1.ReplicasManagedByAnnotation: expinfrav1.AutoscalingReplicasManagedBy})
+
+				mps.MachinePool.Spec.Replicas = ptr.To[int32](4)
+				mps.AWSMachinePool.Spec.MinSize = 2
+				mps.AWSMachinePool.Spec.MaxSize = 5
+				mps.AWSMachinePool.Spec.Autoscaling = &expinfrav1.MachinePoolAutoscaling{Enabled: true, MinSize: 1, MaxSize: 10}
+			},
+			expect: func(e *mocks.MockEC2APIMockRecorder, m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder, g *WithT) {
+				m.UpdateAutoScalingGroupWithContext(context.TODO(), gomock.AssignableToTypeOf(&autoscaling.UpdateAutoScalingGroupInput{})).DoAndReturn(func(ctx context.Context, input *autoscaling.UpdateAutoScalingGroupInput, options ...request.Option) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
+					// CAPA should set the bounds of the autoscaling, but not the desired number of instances it manages
+					g.Expect(input.MinSize).To(BeComparableTo(ptr.To[int64](1)))
+					g.Expect(input.MaxSize).To(BeComparableTo(ptr.To[int64](10)))
+					g.Expect(input.DesiredCapacity).To(BeNil())
+					return &autoscaling.UpdateAutoScalingGroupOutput{}, nil
+				})
+			},
+		},
+		{
+			name:            "new instances protected from scale in",
+			machinePoolName: "update-asg-new-instances-protected",
+			wantErr:         false,
+			setupMachinePoolScope: func(mps *scope.MachinePoolScope) {
+				mps.AWSMachinePool.Spec.NewInstancesProtectedFromScaleIn = true
+			},
+			expect: func(e *mocks.MockEC2APIMockRecorder, m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder, g *WithT) {
+				m.UpdateAutoScalingGroupWithContext(context.TODO(), gomock.AssignableToTypeOf(&autoscaling.UpdateAutoScalingGroupInput{})).DoAndReturn(func(ctx context.Context, input *autoscaling.UpdateAutoScalingGroupInput, options ...request.Option) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
+					g.Expect(input.NewInstancesProtectedFromScaleIn).To(BeComparableTo(aws.Bool(true)))
+					return &autoscaling.UpdateAutoScalingGroupOutput{}, nil
+				})
+			},
+		},
+		{
+			name:            "scale-down in progress",
+			machinePoolName: "update-asg-scale-down-in-progress",
+			wantErr:         false,
+			setupMachinePoolScope: func(mps *scope.MachinePoolScope) {
+				mps.MachinePool.Spec.Replicas = ptr.To[int32](0)
+				mps.AWSMachinePool.Spec.MinSize = 0
+				mps.AWSMachinePool.Spec.MaxSize = 10
+				mps.AWSMachinePool.Status.ScaleDown = &expinfrav1.ScaleDownStatus{DesiredCapacity: 8}
+			},
+			expect: func(e *mocks.MockEC2APIMockRecorder, m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder, g *WithT) {
+				m.UpdateAutoScalingGroupWithContext(context.TODO(), gomock.AssignableToTypeOf(&autoscaling.UpdateAutoScalingGroupInput{})).DoAndReturn(func(ctx context.Context, input *autoscaling.UpdateAutoScalingGroupInput, options ...request.Option) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
+					// The desired capacity is the one of the current scale-down step, not the MachinePool replicas
+					g.Expect(input.DesiredCapacity).To(BeComparableTo(ptr.To[int64](8)))
+					return &autoscaling.UpdateAutoScalingGroupOutput{}, nil
+				})
+			},
+		},
+		{
+			name:            "subnets are updated when they changed",
+			machinePoolName: "update-asg-subnets-changed",
+			wantErr:         false,
+			setupMachinePoolScope: func(mps *scope.MachinePoolScope) {
+				mps.AWSMachinePool.Spec.Subnets = []infrav1.AWSResourceReference{{ID: aws.String("subnet-1")}, {ID: aws.String("subnet-3")}}
+			},
+			existingASG: &expinfrav1.AutoScalingGroup{Subnets: []string{"subnet-1", "subnet-2"}},
+			expect: func(e *mocks.MockEC2APIMockRecorder, m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder, g *WithT) {
+				m.UpdateAutoScalingGroupWithContext(context.TODO(), gomock.AssignableToTypeOf(&autoscaling.UpdateAutoScalingGroupInput{})).DoAndReturn(func(ctx context.Context, input *autoscaling.UpdateAutoScalingGroupInput, options ...request.Option) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
+					g.Expect(input.VPCZoneIdentifier).To(BeComparableTo(aws.String("subnet-1,subnet-3")))
+					return &autoscaling.UpdateAutoScalingGroupOutput{}, nil
+				})
+			},
+		},
+		{
+			name:            "subnets are left alone when they didn't change",
+			machinePoolName: "update-asg-subnets-unchanged",
+			wantErr:         false,
+			setupMachinePoolScope: func(mps *scope.MachinePoolScope) {
+				mps.AWSMachinePool.Spec.Subnets = []infrav1.AWSResourceReference{{ID: aws.String("subnet-1")}, {ID: aws.String("subnet-2")}}
+			},
+			existingASG: &expinfrav1.AutoScalingGroup{Subnets: []string{"subnet-2", "subnet-1"}},
+			expect: func(e *mocks.MockEC2APIMockRecorder, m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder, g *WithT) {
+				m.UpdateAutoScalingGroupWithContext(context.TODO(), gomock.AssignableToTypeOf(&autoscaling.UpdateAutoScalingGroupInput{})).DoAndReturn(func(ctx context.Context, input *autoscaling.UpdateAutoScalingGroupInput, options ...request.Option) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
+					g.Expect(input.VPCZoneIdentifier).To(BeNil())
+					return &autoscaling.UpdateAutoScalingGroupOutput{}, nil
+				})
+			},
+		},
+		{
+			name:            "existing launch template",
+			machinePoolName: "update-asg-existing-launch-template",
+			wantErr:         false,
+			setupMachinePoolScope: func(mps *scope.MachinePoolScope) {
+				mps.AWSMachinePool.Spec.MixedInstancesPolicy = nil
+				mps.AWSMachinePool.Spec.AWSLaunchTemplate.ExistingTemplate = &expinfrav1.ExistingLaunchTemplate{
+					ID:      "lt-0123456789abcdef0",
+					Version: "7",
+				}
+			},
+			expect: func(e *mocks.MockEC2APIMockRecorder, m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder, g *WithT) {
+				m.UpdateAutoScalingGroupWithContext(context.TODO(), gomock.AssignableToTypeOf(&autoscaling.UpdateAutoScalingGroupInput{})).DoAndReturn(func(ctx context.Context, input *autoscaling.UpdateAutoScalingGroupInput, options ...request.Option) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
+					g.Expect(input.LaunchTemplate).To(BeComparableTo(&autoscaling.LaunchTemplateSpecification{
+						LaunchTemplateId: aws.String("lt-0123456789abcdef0"),
+						Version:          aws.String("7"),
+					}))
+					return &autoscaling.UpdateAutoScalingGroupOutput{}, nil
+				})
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -774,7 +1158,7 @@ func TestServiceUpdateASG(t *testing.T) {
 			mps.AWSMachinePool.Name = tt.machinePoolName
 			tt.setupMachinePoolScope(mps)
 
-			err = s.UpdateASG(mps)
+			err = s.UpdateASG(mps, tt.existingASG)
 			checkErr(tt.wantErr, err, g)
 		})
 	}
@@ -863,12 +1247,83 @@ func TestServiceUpdateASGWithSubnetFilters(t *testing.T) {
 			mps.AWSMachinePool.Name = tt.machinePoolName
 			mps.AWSMachinePool.Spec.Subnets = tt.awsResourceReference
 
-			err = s.UpdateASG(mps)
+			err = s.UpdateASG(mps, nil)
 			checkErr(tt.wantErr, err, g)
 		})
 	}
 }
 
+func TestServiceSubnetIDs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	t.Run("resolves every entry once per reconciliation", func(t *testing.T) {
+		g := NewWithT(t)
+		fakeClient := getFakeClient()
+		clusterScope, err := getClusterScope(fakeClient)
+		g.Expect(err).ToNot(HaveOccurred())
+		ec2Mock := mocks.NewMockEC2API(mockCtrl)
+		s := NewService(clusterScope)
+		s.EC2Client = ec2Mock
+
+		mps, err := getMachinePoolScope(fakeClient, clusterScope)
+		g.Expect(err).ToNot(HaveOccurred())
+		mps.AWSMachinePool.Spec.Subnets = []infrav1.AWSResourceReference{
+			{Filters: []infrav1.Filter{{Name: "tag:Tier", Values: []string{"private"}}}},
+			{ID: aws.String("subnet-01")},
+			{Filters: []infrav1.Filter{{Name: "tag:kubernetes.io/role/internal-elb", Values: []string{"1"}}}},
+		}
+
+		// The subnets are described for each entry, and the filters of an entry aren't combined with the ones of the others.
+		ec2Mock.EXPECT().DescribeSubnetsWithContext(context.TODO(), gomock.AssignableToTypeOf(&ec2.DescribeSubnetsInput{})).DoAndReturn(func(_ context.Context, input *ec2.DescribeSubnetsInput, _ ...request.Option) (*ec2.DescribeSubnetsOutput, error) {
+			g.Expect(input.Filters).To(HaveLen(2))
+			g.Expect(aws.StringValue(input.Filters[0].Name)).To(Equal("state"))
+			g.Expect(aws.StringValue(input.Filters[1].Name)).To(Equal("tag:Tier"))
+			return &ec2.DescribeSubnetsOutput{Subnets: []*ec2.Subnet{{SubnetId: aws.String("subnet-03")}, {SubnetId: aws.String("subnet-02")}}}, nil
+		})
+		ec2Mock.EXPECT().DescribeSubnetsWithContext(context.TODO(), gomock.AssignableToTypeOf(&ec2.DescribeSubnetsInput{})).DoAndReturn(func(_ context.Context, input *ec2.DescribeSubnetsInput, _ ...request.Option) (*ec2.DescribeSubnetsOutput, error) {
+			g.Expect(aws.StringValue(input.Filters[1].Name)).To(Equal("tag:kubernetes.io/role/internal-elb"))
+			return &ec2.DescribeSubnetsOutput{Subnets: []*ec2.Subnet{{SubnetId: aws.String("subnet-04")}, {SubnetId: aws.String("subnet-02")}}}, nil
+		})
+
+		subnetIDs, err := s.SubnetIDs(mps)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(subnetIDs).To(Equal([]string{"subnet-02", "subnet-03", "subnet-01", "subnet-04"}))
+		g.Expect(conditions.IsTrue(mps.AWSMachinePool, expinfrav1.SubnetsReadyCondition)).To(BeTrue())
+
+		// The resolved subnets are cached for the rest of the reconciliation.
+		subnetIDs, err = s.SubnetIDs(mps)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(subnetIDs).To(Equal([]string{"subnet-02", "subnet-03", "subnet-01", "subnet-04"}))
+	})
+
+	t.Run("reports an entry which matches no subnet", func(t *testing.T) {
+		g := NewWithT(t)
+		fakeClient := getFakeClient()
+		clusterScope, err := getClusterScope(fakeClient)
+		g.Expect(err).ToNot(HaveOccurred())
+		ec2Mock := mocks.NewMockEC2API(mockCtrl)
+		s := NewService(clusterScope)
+		s.EC2Client = ec2Mock
+
+		mps, err := getMachinePoolScope(fakeClient, clusterScope)
+		g.Expect(err).ToNot(HaveOccurred())
+		mps.AWSMachinePool.Spec.Subnets = []infrav1.AWSResourceReference{
+			{ID: aws.String("subnet-01")},
+			{Filters: []infrav1.Filter{{Name: "tag:Tier", Values: []string{"non-existent"}}}},
+		}
+
+		ec2Mock.EXPECT().DescribeSubnetsWithContext(context.TODO(), gomock.AssignableToTypeOf(&ec2.DescribeSubnetsInput{})).Return(&ec2.DescribeSubnetsOutput{}, nil)
+
+		_, err = s.SubnetIDs(mps)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(conditions.IsFalse(mps.AWSMachinePool, expinfrav1.SubnetsReadyCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(mps.AWSMachinePool, expinfrav1.SubnetsReadyCondition)).To(Equal(expinfrav1.SubnetsNotFoundReason))
+		_, resolved := mps.ResolvedSubnetIDs()
+		g.Expect(resolved).To(BeFalse())
+	})
+}
+
 func TestServiceUpdateResourceTags(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -1189,9 +1644,12 @@ func TestServiceStartASGInstanceRefresh(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	tests := []struct {
-		name    string
-		wantErr bool
-		expect  func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder)
+		name          string
+		wantErr       bool
+		excluded      []string
+		preferences   func(p *expinfrav1.RefreshPreferences)
+		expect        func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder)
+		wantRefreshID string
 	}{
 		{
 			name:    "should return error if start instance refresh failed",
@@ -1219,6 +1677,49 @@ func TestServiceStartASGInstanceRefresh(t *testing.T) {
 						InstanceWarmup:       aws.Int64(100),
 						MinHealthyPercentage: aws.Int64(80),
 					},
+				})).
+					Return(&autoscaling.StartInstanceRefreshOutput{InstanceRefreshId: aws.String("refresh-1")}, nil)
+			},
+			wantRefreshID: "refresh-1",
+		},
+		{
+			name:     "should ignore the instances protected from scale in when instances are excluded from instance refresh",
+			wantErr:  false,
+			excluded: []string{"i-1"},
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.StartInstanceRefreshWithContext(context.TODO(), gomock.Eq(&autoscaling.StartInstanceRefreshInput{
+					AutoScalingGroupName: aws.String("mpn"),
+					Strategy:             aws.String("Rolling"),
+					Preferences: &autoscaling.RefreshPreferences{
+						InstanceWarmup:            aws.Int64(100),
+						MinHealthyPercentage:      aws.Int64(80),
+						ScaleInProtectedInstances: aws.String(autoscaling.ScaleInProtectedInstancesIgnore),
+					},
+				})).
+					Return(&autoscaling.StartInstanceRefreshOutput{}, nil)
+			},
+		},
+		{
+			name:    "should pass the skip matching, rollback and checkpoint preferences",
+			wantErr: false,
+			preferences: func(p *expinfrav1.RefreshPreferences) {
+				p.SkipMatching = aws.Bool(true)
+				p.AutoRollback = aws.Bool(true)
+				p.CheckpointPercentages = []int64{25, 100}
+				p.CheckpointDelay = aws.Int64(600)
+			},
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.StartInstanceRefreshWithContext(context.TODO(), gomock.Eq(&autoscaling.StartInstanceRefreshInput{
+					AutoScalingGroupName: aws.String("mpn"),
+					Strategy:             aws.String("Rolling"),
+					Preferences: &autoscaling.RefreshPreferences{
+						InstanceWarmup:        aws.Int64(100),
+						MinHealthyPercentage:  aws.Int64(80),
+						SkipMatching:          aws.Bool(true),
+						AutoRollback:          aws.Bool(true),
+						CheckpointPercentages: aws.Int64Slice([]int64{25, 100}),
+						CheckpointDelay:       aws.Int64(600),
+					},
 				})).
 					Return(&autoscaling.StartInstanceRefreshOutput{}, nil)
 			},
@@ -1240,11 +1741,620 @@ func TestServiceStartASGInstanceRefresh(t *testing.T) {
 			mps, err := getMachinePoolScope(fakeClient, clusterScope)
 			g.Expect(err).ToNot(HaveOccurred())
 			mps.AWSMachinePool.Name = "mpn"
+			mps.AWSMachinePool.Status.RefreshExcludedInstances = tt.excluded
+			if tt.preferences != nil {
+				tt.preferences(mps.AWSMachinePool.Spec.RefreshPreferences)
+			}
 
 			err = s.StartASGInstanceRefresh(mps)
 			checkErr(tt.wantErr, err, g)
+			if tt.wantRefreshID != "" {
+				g.Expect(mps.AWSMachinePool.Status.InstanceRefresh).NotTo(BeNil())
+				g.Expect(mps.AWSMachinePool.Status.InstanceRefresh.ID).To(Equal(tt.wantRefreshID))
+				g.Expect(mps.AWSMachinePool.Status.InstanceRefresh.Status).To(Equal(autoscaling.InstanceRefreshStatusPending))
+			}
+		})
+	}
+}
+
+func TestServiceCancelASGInstanceRefresh(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name       string
+		wantErr    bool
+		wantStatus string
+		cancelErr  error
+	}{
+		{
+			name:       "should mark the instance refresh as cancelling",
+			wantStatus: autoscaling.InstanceRefreshStatusCancelling,
+		},
+		{
+			name:       "should ignore an instance refresh which completed in the meantime",
+			cancelErr:  awserr.New(autoscaling.ErrCodeActiveInstanceRefreshNotFoundFault, "", nil),
+			wantStatus: autoscaling.InstanceRefreshStatusInProgress,
+		},
+		{
+			name:       "should return error if cancel instance refresh failed",
+			cancelErr:  awserr.New(autoscaling.ErrCodeResourceContentionFault, "", nil),
+			wantErr:    true,
+			wantStatus: autoscaling.InstanceRefreshStatusInProgress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			fakeClient := getFakeClient()
+
+			clusterScope, err := getClusterScope(fakeClient)
+			g.Expect(err).ToNot(HaveOccurred())
+			asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+			asgMock.EXPECT().CancelInstanceRefreshWithContext(context.TODO(), gomock.Eq(&autoscaling.CancelInstanceRefreshInput{
+				AutoScalingGroupName: aws.String("mpn"),
+			})).Return(&autoscaling.CancelInstanceRefreshOutput{}, tt.cancelErr)
+			s := NewService(clusterScope)
+			s.ASGClient = asgMock
+
+			mps, err := getMachinePoolScope(fakeClient, clusterScope)
+			g.Expect(err).ToNot(HaveOccurred())
+			mps.AWSMachinePool.Name = "mpn"
+			mps.AWSMachinePool.Status.InstanceRefresh = &expinfrav1.InstanceRefreshStatus{ID: "refresh-1", Status: autoscaling.InstanceRefreshStatusInProgress}
+
+			err = s.CancelASGInstanceRefresh(mps)
+			checkErr(tt.wantErr, err, g)
+			g.Expect(mps.AWSMachinePool.Status.InstanceRefresh.Status).To(Equal(tt.wantStatus))
+		})
+	}
+}
+
+func TestServiceDescribeInstanceRefresh(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	startTime := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		wantErr     bool
+		wantRefresh *expinfrav1.InstanceRefreshStatus
+		expect      func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder)
+	}{
+		{
+			name:    "should return error if describe instance refreshes failed",
+			wantErr: true,
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.DescribeInstanceRefreshesWithContext(context.TODO(), gomock.Eq(&autoscaling.DescribeInstanceRefreshesInput{
+					AutoScalingGroupName: aws.String("mpn"),
+					InstanceRefreshIds:   aws.StringSlice([]string{"refresh-1"}),
+				})).
+					Return(nil, awserrors.NewFailedDependency("dependency failure"))
+			},
+		},
+		{
+			name:    "should return error if the instance refresh is not found",
+			wantErr: true,
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.DescribeInstanceRefreshesWithContext(context.TODO(), gomock.Any()).
+					Return(&autoscaling.DescribeInstanceRefreshesOutput{}, nil)
+			},
+		},
+		{
+			name:    "should return the status of the instance refresh",
+			wantErr: false,
+			wantRefresh: &expinfrav1.InstanceRefreshStatus{
+				ID:                 "refresh-1",
+				Status:             "Failed",
+				StatusReason:       "The instance refresh failed because instances failed health checks.",
+				PercentageComplete: 40,
+				InstancesToUpdate:  3,
+				StartTime:          &metav1.Time{Time: startTime},
+			},
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.DescribeInstanceRefreshesWithContext(context.TODO(), gomock.Any()).
+					Return(&autoscaling.DescribeInstanceRefreshesOutput{
+						InstanceRefreshes: []*autoscaling.InstanceRefresh{
+							{
+								InstanceRefreshId:  aws.String("refresh-1"),
+								Status:             aws.String("Failed"),
+								StatusReason:       aws.String("The instance refresh failed because instances failed health checks."),
+								PercentageComplete: aws.Int64(40),
+								InstancesToUpdate:  aws.Int64(3),
+								StartTime:          aws.Time(startTime),
+							},
+						},
+					}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			fakeClient := getFakeClient()
+
+			clusterScope, err := getClusterScope(fakeClient)
+			g.Expect(err).ToNot(HaveOccurred())
+			asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+			tt.expect(asgMock.EXPECT())
+			s := NewService(clusterScope)
+			s.ASGClient = asgMock
+
+			refresh, err := s.DescribeInstanceRefresh("mpn", "refresh-1")
+			checkErr(tt.wantErr, err, g)
+			g.Expect(refresh).To(Equal(tt.wantRefresh))
+		})
+	}
+}
+
+func TestServiceDescribeScalingActivities(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	startTime := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		wantErr        bool
+		wantActivities []*expinfrav1.ScalingActivity
+		expect         func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder)
+	}{
+		{
+			name:    "should return error if describe scaling activities failed",
+			wantErr: true,
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.DescribeScalingActivitiesWithContext(context.TODO(), gomock.Eq(&autoscaling.DescribeScalingActivitiesInput{
+					AutoScalingGroupName: aws.String("mpn"),
+					MaxRecords:           aws.Int64(20),
+				})).
+					Return(nil, awserrors.NewFailedDependency("dependency failure"))
+			},
+		},
+		{
+			name:    "should return the scaling activities",
+			wantErr: false,
+			wantActivities: []*expinfrav1.ScalingActivity{
+				{
+					ActivityID:    "activity-1",
+					Description:   "Launching a new EC2 instance. Status Reason: Could not launch Spot Instances.",
+					StatusCode:    "Failed",
+					StatusMessage: "Could not launch Spot Instances. InsufficientInstanceCapacity.",
+					StartTime:     &metav1.Time{Time: startTime},
+				},
+			},
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.DescribeScalingActivitiesWithContext(context.TODO(), gomock.Eq(&autoscaling.DescribeScalingActivitiesInput{
+					AutoScalingGroupName: aws.String("mpn"),
+					MaxRecords:           aws.Int64(20),
+				})).
+					Return(&autoscaling.DescribeScalingActivitiesOutput{
+						Activities: []*autoscaling.Activity{
+							{
+								ActivityId:    aws.String("activity-1"),
+								Description:   aws.String("Launching a new EC2 instance. Status Reason: Could not launch Spot Instances."),
+								StatusCode:    aws.String("Failed"),
+								StatusMessage: aws.String("Could not launch Spot Instances. InsufficientInstanceCapacity."),
+								StartTime:     aws.Time(startTime),
+							},
+						},
+					}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			fakeClient := getFakeClient()
+
+			clusterScope, err := getClusterScope(fakeClient)
+			g.Expect(err).ToNot(HaveOccurred())
+			asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+			tt.expect(asgMock.EXPECT())
+			s := NewService(clusterScope)
+			s.ASGClient = asgMock
+
+			activities, err := s.DescribeScalingActivities("mpn")
+			checkErr(tt.wantErr, err, g)
+			if !tt.wantErr {
+				g.Expect(activities).To(Equal(tt.wantActivities))
+			}
+		})
+	}
+}
+
+func TestServiceOpenSpotInstanceRequestCount(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	g := NewWithT(t)
+	fakeClient := getFakeClient()
+
+	clusterScope, err := getClusterScope(fakeClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	ec2Mock := mocks.NewMockEC2API(mockCtrl)
+	ec2Mock.EXPECT().DescribeSpotInstanceRequestsPagesWithContext(context.TODO(), gomock.Eq(&ec2.DescribeSpotInstanceRequestsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("state"),
+				Values: aws.StringSlice([]string{"open"}),
+			},
+			{
+				Name:   aws.String("tag:aws:ec2launchtemplate:id"),
+				Values: aws.StringSlice([]string{"lt-12345"}),
+			},
+		},
+	}), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *ec2.DescribeSpotInstanceRequestsInput, fn func(*ec2.DescribeSpotInstanceRequestsOutput, bool) bool, _ ...request.Option) error {
+			fn(&ec2.DescribeSpotInstanceRequestsOutput{
+				SpotInstanceRequests: []*ec2.SpotInstanceRequest{
+					{SpotInstanceRequestId: aws.String("sir-1")},
+					{SpotInstanceRequestId: aws.String("sir-2")},
+				},
+			}, true)
+			return nil
 		})
+	s := NewService(clusterScope)
+	s.EC2Client = ec2Mock
+
+	count, err := s.OpenSpotInstanceRequestCount("lt-12345")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(count).To(Equal(int32(2)))
+}
+
+func TestServiceDescribeTargetGroupAttachments(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	g := NewWithT(t)
+	asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+	asgMock.EXPECT().DescribeLoadBalancerTargetGroupsWithContext(context.TODO(), gomock.Eq(&autoscaling.DescribeLoadBalancerTargetGroupsInput{
+		AutoScalingGroupName: aws.String("asg"),
+	})).Return(&autoscaling.DescribeLoadBalancerTargetGroupsOutput{
+		LoadBalancerTargetGroups: []*autoscaling.LoadBalancerTargetGroupState{
+			{LoadBalancerTargetGroupARN: aws.String("tg-1"), State: aws.String("InService")},
+			{LoadBalancerTargetGroupARN: aws.String("tg-2"), State: aws.String("Removing")},
+		},
+		NextToken: aws.String("next"),
+	}, nil)
+	asgMock.EXPECT().DescribeLoadBalancerTargetGroupsWithContext(context.TODO(), gomock.Eq(&autoscaling.DescribeLoadBalancerTargetGroupsInput{
+		AutoScalingGroupName: aws.String("asg"),
+		NextToken:            aws.String("next"),
+	})).Return(&autoscaling.DescribeLoadBalancerTargetGroupsOutput{
+		LoadBalancerTargetGroups: []*autoscaling.LoadBalancerTargetGroupState{
+			{LoadBalancerTargetGroupARN: aws.String("tg-3"), State: aws.String("Adding")},
+		},
+	}, nil)
+	cs, err := getClusterScope(getFakeClient())
+	g.Expect(err).NotTo(HaveOccurred())
+	s := &Service{scope: cs, ASGClient: asgMock}
+
+	arns, err := s.DescribeTargetGroupAttachments("asg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(arns).To(Equal([]string{"tg-1", "tg-3"}))
+}
+
+func TestServiceAttachTargetGroups(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	g := NewWithT(t)
+	arns := make([]string, 12)
+	for i := range arns {
+		arns[i] = fmt.Sprintf("tg-%d", i)
+	}
+	asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+	asgMock.EXPECT().AttachLoadBalancerTargetGroupsWithContext(context.TODO(), gomock.Eq(&autoscaling.AttachLoadBalancerTargetGroupsInput{
+		AutoScalingGroupName: aws.String("asg"),
+		TargetGroupARNs:      aws.StringSlice(arns[:10]),
+	})).Return(&autoscaling.AttachLoadBalancerTargetGroupsOutput{}, nil)
+	asgMock.EXPECT().AttachLoadBalancerTargetGroupsWithContext(context.TODO(), gomock.Eq(&autoscaling.AttachLoadBalancerTargetGroupsInput{
+		AutoScalingGroupName: aws.String("asg"),
+		TargetGroupARNs:      aws.StringSlice(arns[10:]),
+	})).Return(&autoscaling.AttachLoadBalancerTargetGroupsOutput{}, nil)
+	cs, err := getClusterScope(getFakeClient())
+	g.Expect(err).NotTo(HaveOccurred())
+	s := &Service{scope: cs, ASGClient: asgMock}
+
+	g.Expect(s.AttachTargetGroups("asg", arns)).To(Succeed())
+}
+
+func TestServiceDetachTargetGroups(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	g := NewWithT(t)
+	asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+	asgMock.EXPECT().DetachLoadBalancerTargetGroupsWithContext(context.TODO(), gomock.Eq(&autoscaling.DetachLoadBalancerTargetGroupsInput{
+		AutoScalingGroupName: aws.String("asg"),
+		TargetGroupARNs:      aws.StringSlice([]string{"tg-1"}),
+	})).Return(nil, awserrors.NewFailedDependency("dependency failure"))
+	cs, err := getClusterScope(getFakeClient())
+	g.Expect(err).NotTo(HaveOccurred())
+	s := &Service{scope: cs, ASGClient: asgMock}
+
+	g.Expect(s.DetachTargetGroups("asg", []string{"tg-1"})).NotTo(Succeed())
+}
+
+func TestServiceAttachLoadBalancers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	g := NewWithT(t)
+	names := make([]string, 12)
+	for i := range names {
+		names[i] = fmt.Sprintf("elb-%d", i)
+	}
+	asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+	asgMock.EXPECT().AttachLoadBalancersWithContext(context.TODO(), gomock.Eq(&autoscaling.AttachLoadBalancersInput{
+		AutoScalingGroupName: aws.String("asg"),
+		LoadBalancerNames:    aws.StringSlice(names[:10]),
+	})).Return(&autoscaling.AttachLoadBalancersOutput{}, nil)
+	asgMock.EXPECT().AttachLoadBalancersWithContext(context.TODO(), gomock.Eq(&autoscaling.AttachLoadBalancersInput{
+		AutoScalingGroupName: aws.String("asg"),
+		LoadBalancerNames:    aws.StringSlice(names[10:]),
+	})).Return(&autoscaling.AttachLoadBalancersOutput{}, nil)
+	cs, err := getClusterScope(getFakeClient())
+	g.Expect(err).NotTo(HaveOccurred())
+	s := &Service{scope: cs, ASGClient: asgMock}
+
+	g.Expect(s.AttachLoadBalancers("asg", names)).To(Succeed())
+}
+
+func TestServiceDetachLoadBalancers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	g := NewWithT(t)
+	asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+	asgMock.EXPECT().DetachLoadBalancersWithContext(context.TODO(), gomock.Eq(&autoscaling.DetachLoadBalancersInput{
+		AutoScalingGroupName: aws.String("asg"),
+		LoadBalancerNames:    aws.StringSlice([]string{"elb-1"}),
+	})).Return(nil, awserrors.NewFailedDependency("dependency failure"))
+	cs, err := getClusterScope(getFakeClient())
+	g.Expect(err).NotTo(HaveOccurred())
+	s := &Service{scope: cs, ASGClient: asgMock}
+
+	g.Expect(s.DetachLoadBalancers("asg", []string{"elb-1"})).NotTo(Succeed())
+}
+
+func TestServiceSetInstanceProtection(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	g := NewWithT(t)
+	instanceIDs := make([]string, 60)
+	for i := range instanceIDs {
+		instanceIDs[i] = fmt.Sprintf("i-%d", i)
+	}
+	asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+	asgMock.EXPECT().SetInstanceProtectionWithContext(context.TODO(), gomock.Eq(&autoscaling.SetInstanceProtectionInput{
+		AutoScalingGroupName: aws.String("asg"),
+		InstanceIds:          aws.StringSlice(instanceIDs[:50]),
+		ProtectedFromScaleIn: aws.Bool(true),
+	})).Return(&autoscaling.SetInstanceProtectionOutput{}, nil)
+	asgMock.EXPECT().SetInstanceProtectionWithContext(context.TODO(), gomock.Eq(&autoscaling.SetInstanceProtectionInput{
+		AutoScalingGroupName: aws.String("asg"),
+		InstanceIds:          aws.StringSlice(instanceIDs[50:]),
+		ProtectedFromScaleIn: aws.Bool(true),
+	})).Return(&autoscaling.SetInstanceProtectionOutput{}, nil)
+	cs, err := getClusterScope(getFakeClient())
+	g.Expect(err).NotTo(HaveOccurred())
+	s := &Service{scope: cs, ASGClient: asgMock}
+
+	g.Expect(s.SetInstanceProtection("asg", instanceIDs, true)).To(Succeed())
+}
+
+func TestServiceDetachInstances(t *testing.T) {
+	g := NewWithT(t)
+
+	// More instances than can be detached in a single request.
+	newInstance := func(id string) *autoscaling.Instance {
+		return &autoscaling.Instance{
+			InstanceId:       aws.String(id),
+			AvailabilityZone: aws.String("us-east-1a"),
+			LifecycleState:   aws.String(autoscaling.LifecycleStateInService),
+		}
+	}
+	instances := make([]*autoscaling.Instance, 25)
+	instanceIDs := make([]string, len(instances))
+	for i := range instances {
+		instanceIDs[i] = fmt.Sprintf("i-%d", i)
+		instances[i] = newInstance(instanceIDs[i])
+	}
+	asgFake := fakeaws.NewAutoScalingAPI()
+	asgFake.AddAutoScalingGroup(&autoscaling.Group{AutoScalingGroupName: aws.String("asg"), DesiredCapacity: aws.Int64(26)})
+	g.Expect(asgFake.SetInstances("asg", append(instances, newInstance("i-kept"))...)).To(Succeed())
+	cs, err := getClusterScope(getFakeClient())
+	g.Expect(err).NotTo(HaveOccurred())
+	s := &Service{scope: cs, ASGClient: asgFake}
+
+	g.Expect(s.DetachInstances("asg", instanceIDs)).To(Succeed())
+
+	asg, err := s.ASGIfExists(aws.String("asg"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(asg.Instances).To(HaveLen(1))
+	g.Expect(asg.Instances[0].ID).To(Equal("i-kept"))
+	// The desired capacity isn't decremented, so that the group replaces the detached instances.
+	g.Expect(asg.DesiredCapacity).To(HaveValue(BeEquivalentTo(26)))
+
+	g.Expect(s.DetachInstances("asg", []string{"i-0"})).To(MatchError(ContainSubstring("failed to detach instances")))
+}
+
+func TestServiceReconcileASGDeletion(t *testing.T) {
+	g := NewWithT(t)
+	asgFake := fakeaws.NewAutoScalingAPI()
+	asgFake.AddAutoScalingGroup(&autoscaling.Group{AutoScalingGroupName: aws.String("asg"), MinSize: aws.Int64(1), MaxSize: aws.Int64(3), DesiredCapacity: aws.Int64(2)})
+	g.Expect(asgFake.SetInstances("asg", &autoscaling.Instance{
+		InstanceId:       aws.String("i-0"),
+		AvailabilityZone: aws.String("us-east-1a"),
+		LifecycleState:   aws.String(autoscaling.LifecycleStateTerminating),
+	})).To(Succeed())
+	cs, err := getClusterScope(getFakeClient())
+	g.Expect(err).NotTo(HaveOccurred())
+	s := &Service{scope: cs, ASGClient: asgFake}
+
+	getASG := func() *expinfrav1.AutoScalingGroup {
+		asg, err := s.ASGIfExists(aws.String("asg"))
+		g.Expect(err).NotTo(HaveOccurred())
+		return asg
+	}
+
+	// The group is scaled to zero, and isn't deleted while its instances terminate.
+	status, deleted, err := s.ReconcileASGDeletion(getASG(), nil, time.Minute)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleted).To(BeFalse())
+	g.Expect(status.Phase).To(Equal(expinfrav1.ASGDeletionPhaseScalingToZero))
+	asg := getASG()
+	g.Expect(asg.MinSize).To(BeZero())
+	g.Expect(asg.MaxSize).To(BeZero())
+	g.Expect(asg.DesiredCapacity).To(HaveValue(BeZero()))
+
+	// Once the timeout expires, the deletion is attempted, and rejected while instances remain.
+	status.PhaseStartTime = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+	status, deleted, err = s.ReconcileASGDeletion(asg, status, time.Minute)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleted).To(BeFalse())
+	g.Expect(status.Phase).To(Equal(expinfrav1.ASGDeletionPhaseDeleting))
+	g.Expect(getASG()).NotTo(BeNil())
+
+	status, deleted, err = s.ReconcileASGDeletion(asg, status, time.Minute)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleted).To(BeFalse())
+	g.Expect(status.Phase).To(Equal(expinfrav1.ASGDeletionPhaseDeleting))
+
+	// The deletion is escalated to ForceDelete once the timeout expires again.
+	status.PhaseStartTime = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+	status, deleted, err = s.ReconcileASGDeletion(asg, status, time.Minute)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleted).To(BeTrue())
+	g.Expect(status.Phase).To(Equal(expinfrav1.ASGDeletionPhaseForceDeleting))
+	g.Expect(getASG()).To(BeNil())
+}
+
+func TestServiceReconcileASGDeletionWithoutInstances(t *testing.T) {
+	g := NewWithT(t)
+	asgFake := fakeaws.NewAutoScalingAPI()
+	asgFake.AddAutoScalingGroup(&autoscaling.Group{AutoScalingGroupName: aws.String("asg")})
+	cs, err := getClusterScope(getFakeClient())
+	g.Expect(err).NotTo(HaveOccurred())
+	s := &Service{scope: cs, ASGClient: asgFake}
+
+	asg, err := s.ASGIfExists(aws.String("asg"))
+	g.Expect(err).NotTo(HaveOccurred())
+	status, deleted, err := s.ReconcileASGDeletion(asg, nil, time.Minute)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleted).To(BeTrue())
+	g.Expect(status.Phase).To(Equal(expinfrav1.ASGDeletionPhaseDeleting))
+	asg, err = s.ASGIfExists(aws.String("asg"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(asg).To(BeNil())
+}
+
+func TestServiceMetricsCollection(t *testing.T) {
+	g := NewWithT(t)
+	asgFake := fakeaws.NewAutoScalingAPI()
+	asgFake.AddAutoScalingGroup(&autoscaling.Group{AutoScalingGroupName: aws.String("asg")})
+	cs, err := getClusterScope(getFakeClient())
+	g.Expect(err).NotTo(HaveOccurred())
+	s := &Service{scope: cs, ASGClient: asgFake}
+
+	enabledMetrics := func() []string {
+		asg, err := s.ASGIfExists(aws.String("asg"))
+		g.Expect(err).NotTo(HaveOccurred())
+		return asg.EnabledMetrics
 	}
+
+	g.Expect(s.EnableMetricsCollection("asg", expinfrav1.MetricsCollectionGranularity1Minute, []string{"GroupDesiredCapacity"})).To(Succeed())
+	g.Expect(enabledMetrics()).To(Equal([]string{"GroupDesiredCapacity"}))
+
+	// The metrics are added to the enabled ones...
+	g.Expect(s.EnableMetricsCollection("asg", expinfrav1.MetricsCollectionGranularity1Minute, []string{"GroupInServiceInstances", "GroupMaxSize"})).To(Succeed())
+	g.Expect(enabledMetrics()).To(Equal([]string{"GroupDesiredCapacity", "GroupInServiceInstances", "GroupMaxSize"}))
+
+	// ... and removed from them.
+	g.Expect(s.DisableMetricsCollection("asg", []string{"GroupDesiredCapacity", "GroupMaxSize"})).To(Succeed())
+	g.Expect(enabledMetrics()).To(Equal([]string{"GroupInServiceInstances"}))
+
+	g.Expect(s.EnableMetricsCollection("asg", "5Minute", []string{"GroupMaxSize"})).NotTo(Succeed())
+	g.Expect(s.DisableMetricsCollection("unknown", []string{"GroupMaxSize"})).NotTo(Succeed())
+}
+
+func TestCreateSDKMixedInstancesPolicyInstanceRequirements(t *testing.T) {
+	g := NewWithT(t)
+
+	requirements := &expinfrav1.InstanceRequirements{
+		VCPUCount:             expinfrav1.VCPUCountRequest{Min: 4, Max: aws.Int64(16)},
+		MemoryMiB:             expinfrav1.MemoryMiBRequest{Min: 8192},
+		CPUManufacturers:      []expinfrav1.CPUManufacturer{expinfrav1.CPUManufacturerIntel, expinfrav1.CPUManufacturerAMD},
+		ExcludedInstanceTypes: []string{"t2.*", "m4.*"},
+		BareMetal:             expinfrav1.InstanceRequirementsFilterExcluded,
+		BurstablePerformance:  expinfrav1.InstanceRequirementsFilterIncluded,
+		AcceleratorCount:      &expinfrav1.AcceleratorCountRequest{Max: aws.Int64(0)},
+	}
+	policy := createSDKMixedInstancesPolicy(&autoscaling.LaunchTemplateSpecification{LaunchTemplateName: aws.String("lt")}, &expinfrav1.MixedInstancesPolicy{
+		Overrides: []expinfrav1.Overrides{
+			{InstanceType: "m5.large"},
+			{InstanceRequirements: requirements},
+		},
+	})
+
+	g.Expect(policy.LaunchTemplate.Overrides).To(Equal([]*autoscaling.LaunchTemplateOverrides{
+		{InstanceType: aws.String("m5.large")},
+		{InstanceRequirements: &autoscaling.InstanceRequirements{
+			VCpuCount:             &autoscaling.VCpuCountRequest{Min: aws.Int64(4), Max: aws.Int64(16)},
+			MemoryMiB:             &autoscaling.MemoryMiBRequest{Min: aws.Int64(8192)},
+			CpuManufacturers:      aws.StringSlice([]string{"intel", "amd"}),
+			ExcludedInstanceTypes: aws.StringSlice([]string{"t2.*", "m4.*"}),
+			BareMetal:             aws.String("excluded"),
+			BurstablePerformance:  aws.String("included"),
+			AcceleratorCount:      &autoscaling.AcceleratorCountRequest{Max: aws.Int64(0)},
+		}},
+	}))
+
+	// The instance requirements reported by the Auto Scaling group are compared with the spec for drift detection.
+	g.Expect(instanceRequirementsFromSDK(policy.LaunchTemplate.Overrides[1].InstanceRequirements)).To(Equal(requirements))
+	g.Expect(instanceRequirementsFromSDK(policy.LaunchTemplate.Overrides[0].InstanceRequirements)).To(BeNil())
+}
+
+func TestCreateSDKMixedInstancesPolicyWeightedCapacity(t *testing.T) {
+	g := NewWithT(t)
+
+	policy := createSDKMixedInstancesPolicy(&autoscaling.LaunchTemplateSpecification{LaunchTemplateName: aws.String("lt")}, &expinfrav1.MixedInstancesPolicy{
+		InstancesDistribution: &expinfrav1.InstancesDistribution{
+			OnDemandAllocationStrategy: expinfrav1.OnDemandAllocationStrategyPrioritized,
+		},
+		Overrides: []expinfrav1.Overrides{
+			{InstanceType: "m5.4xlarge", WeightedCapacity: "16"},
+			{InstanceType: "m5.2xlarge", WeightedCapacity: "8"},
+		},
+	})
+
+	// The order of the overrides is their priority, so it is kept.
+	g.Expect(policy.LaunchTemplate.Overrides).To(Equal([]*autoscaling.LaunchTemplateOverrides{
+		{InstanceType: aws.String("m5.4xlarge"), WeightedCapacity: aws.String("16")},
+		{InstanceType: aws.String("m5.2xlarge"), WeightedCapacity: aws.String("8")},
+	}))
+}
+
+func TestCreateSDKMixedInstancesPolicyAllocationStrategies(t *testing.T) {
+	g := NewWithT(t)
+
+	launchTemplate := &autoscaling.LaunchTemplateSpecification{LaunchTemplateName: aws.String("lt")}
+	policy := createSDKMixedInstancesPolicy(launchTemplate, &expinfrav1.MixedInstancesPolicy{
+		InstancesDistribution: &expinfrav1.InstancesDistribution{
+			OnDemandAllocationStrategy: expinfrav1.OnDemandAllocationStrategyLowestPrice,
+			SpotAllocationStrategy:     expinfrav1.SpotAllocationStrategyPriceCapacityOptimized,
+		},
+	})
+	g.Expect(policy.InstancesDistribution.OnDemandAllocationStrategy).To(Equal(aws.String("lowest-price")))
+	g.Expect(policy.InstancesDistribution.SpotAllocationStrategy).To(Equal(aws.String("price-capacity-optimized")))
+
+	// AWS uses its default strategies when they aren't set.
+	policy = createSDKMixedInstancesPolicy(launchTemplate, &expinfrav1.MixedInstancesPolicy{
+		InstancesDistribution: &expinfrav1.InstancesDistribution{OnDemandBaseCapacity: aws.Int64(1)},
+	})
+	g.Expect(policy.InstancesDistribution).To(Equal(&autoscaling.InstancesDistribution{OnDemandBaseCapacity: aws.Int64(1)}))
 }
 
 func getFakeClient() client.Client {
@@ -0,0 +1,388 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asg
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+)
+
+const (
+	// defaultHeartbeatTimeout is the heartbeat timeout AWS applies when none is specified.
+	defaultHeartbeatTimeout = 3600 * time.Second
+	// defaultLifecycleHookDefaultResult is the default result AWS applies when none is specified.
+	defaultLifecycleHookDefaultResult = infrav1.LifecycleHookDefaultResultAbandon
+)
+
+// terminalLifecycleHookValidationErrors are fragments, in lower case, of the ValidationError messages returned for
+// lifecycle hooks AWS rejects until their spec changes: the limit of lifecycle hooks per AutoScalingGroup is reached,
+// the role doesn't trust Auto Scaling, or the notification metadata is malformed.
+var terminalLifecycleHookValidationErrors = []string{
+	"lifecycle hook limit",
+	"unable to assume role",
+	"'notificationmetadata'",
+}
+
+// lifecycleHookInUseValidationErrors are fragments, in lower case, of the ValidationError messages DeleteLifecycleHook
+// returns for lifecycle hooks that instances still wait on.
+var lifecycleHookInUseValidationErrors = []string{
+	"in use",
+	"pending lifecycle action",
+}
+
+// noActiveLifecycleActionValidationError is a fragment, in lower case, of the ValidationError message
+// CompleteLifecycleAction returns for instances which don't wait on the given lifecycle hook.
+const noActiveLifecycleActionValidationError = "no active lifecycle action found"
+
+// DescribeLifecycleHooks returns the lifecycle hooks for the given AutoScalingGroup after retrieving them from the AWS API.
+func (s *Service) DescribeLifecycleHooks(asgName string) ([]*infrav1.AWSLifecycleHook, error) {
+	s.scope.Debug("Describing lifecycle hooks", "asgName", asgName)
+
+	input := &autoscaling.DescribeLifecycleHooksInput{
+		AutoScalingGroupName: aws.String(asgName),
+	}
+
+	out, err := s.ASGClient.DescribeLifecycleHooksWithContext(context.TODO(), input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe lifecycle hooks for AutoScalingGroup: %q", asgName)
+	}
+
+	hooks := make([]*infrav1.AWSLifecycleHook, len(out.LifecycleHooks))
+	for i, hook := range out.LifecycleHooks {
+		hooks[i] = s.SDKToLifecycleHook(hook)
+	}
+
+	return hooks, nil
+}
+
+func getPutLifecycleHookInput(asgName string, hook *infrav1.AWSLifecycleHook) *autoscaling.PutLifecycleHookInput {
+	input := &autoscaling.PutLifecycleHookInput{
+		AutoScalingGroupName: aws.String(asgName),
+		LifecycleHookName:    aws.String(hook.Name),
+		LifecycleTransition:  aws.String(hook.LifecycleTransition.String()),
+
+		// Optional
+		RoleARN:               hook.RoleARN,
+		NotificationTargetARN: hook.NotificationTargetARN,
+		NotificationMetadata:  hook.NotificationMetadata,
+	}
+
+	// Optional parameters
+	if hook.DefaultResult != nil {
+		input.DefaultResult = aws.String(hook.DefaultResult.String())
+	}
+
+	if hook.HeartbeatTimeout != nil {
+		timeoutSeconds := hook.HeartbeatTimeout.Duration.Seconds()
+		input.HeartbeatTimeout = aws.Int64(int64(timeoutSeconds))
+	}
+
+	return input
+}
+
+// CreateLifecycleHook creates a lifecycle hook for the given AutoScalingGroup.
+func (s *Service) CreateLifecycleHook(asgName string, hook *infrav1.AWSLifecycleHook) error {
+	input := getPutLifecycleHookInput(asgName, hook)
+
+	if _, err := s.ASGClient.PutLifecycleHookWithContext(context.TODO(), input); err != nil {
+		return errors.Wrapf(err, "failed to create lifecycle hook %q for AutoScalingGroup: %q", hook.Name, asgName)
+	}
+	s.scope.Debug("Created lifecycle hook", "asgName", asgName, "lifecycleHookName", hook.Name, "lifecycleTransition", hook.LifecycleTransition)
+
+	return nil
+}
+
+// UpdateLifecycleHook updates a lifecycle hook for the given AutoScalingGroup.
+func (s *Service) UpdateLifecycleHook(asgName string, hook *infrav1.AWSLifecycleHook) error {
+	input := getPutLifecycleHookInput(asgName, hook)
+
+	if _, err := s.ASGClient.PutLifecycleHookWithContext(context.TODO(), input); err != nil {
+		return errors.Wrapf(err, "failed to update lifecycle hook %q for AutoScalingGroup: %q", hook.Name, asgName)
+	}
+	s.scope.Debug("Updated lifecycle hook", "asgName", asgName, "lifecycleHookName", hook.Name, "lifecycleTransition", hook.LifecycleTransition)
+
+	return nil
+}
+
+// DeleteLifecycleHook deletes a lifecycle hook for the given AutoScalingGroup.
+// AWS refuses to delete a lifecycle hook while instances wait on it. With forceDelete, the pending lifecycle actions
+// of the hook are completed with ABANDON and the deletion is retried, otherwise the error is returned as is and can be
+// detected with IsLifecycleHookInUseError.
+func (s *Service) DeleteLifecycleHook(asgName string, hook *infrav1.AWSLifecycleHook, forceDelete bool) error {
+	err := s.deleteLifecycleHook(asgName, hook)
+	if err == nil || !forceDelete || !IsLifecycleHookInUseError(err) {
+		return err
+	}
+
+	s.scope.Info("Abandoning pending lifecycle actions to delete lifecycle hook", "asgName", asgName, "lifecycleHookName", hook.Name)
+	if err := s.abandonLifecycleActions(asgName, hook); err != nil {
+		return err
+	}
+	return s.deleteLifecycleHook(asgName, hook)
+}
+
+func (s *Service) deleteLifecycleHook(asgName string, hook *infrav1.AWSLifecycleHook) error {
+	input := &autoscaling.DeleteLifecycleHookInput{
+		AutoScalingGroupName: aws.String(asgName),
+		LifecycleHookName:    aws.String(hook.Name),
+	}
+
+	if _, err := s.ASGClient.DeleteLifecycleHookWithContext(context.TODO(), input); err != nil {
+		return errors.Wrapf(err, "failed to delete lifecycle hook %q for AutoScalingGroup: %q", hook.Name, asgName)
+	}
+	s.scope.Debug("Deleted lifecycle hook", "asgName", asgName, "lifecycleHookName", hook.Name, "lifecycleTransition", hook.LifecycleTransition)
+
+	return nil
+}
+
+// abandonLifecycleActions completes with ABANDON the lifecycle actions of the given hook for the instances of the
+// given AutoScalingGroup that wait in the lifecycle transition of the hook. Abandoning a launch terminates the instance.
+// An instance in the wait state may wait on another hook of the same transition, AWS rejects completing the lifecycle
+// action of the given hook for it, which is ignored.
+func (s *Service) abandonLifecycleActions(asgName string, hook *infrav1.AWSLifecycleHook) error {
+	waitState := autoscaling.LifecycleStatePendingWait
+	if hook.LifecycleTransition == infrav1.LifecycleTransitionInstanceTerminate {
+		waitState = autoscaling.LifecycleStateTerminatingWait
+	}
+
+	groups, err := s.describeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: aws.StringSlice([]string{asgName}),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to describe AutoScalingGroup: %q", asgName)
+	}
+
+	for _, group := range groups {
+		for _, instance := range group.Instances {
+			if aws.StringValue(instance.LifecycleState) != waitState {
+				continue
+			}
+			instanceID := aws.StringValue(instance.InstanceId)
+			if err := s.CompleteLifecycleAction(asgName, hook.Name, instanceID, "", infrav1.LifecycleHookDefaultResultAbandon); err != nil {
+				if code, _ := awserrors.Code(errors.Cause(err)); code != awserrors.ValidationError {
+					return err
+				}
+				s.scope.Debug("Instance has no pending lifecycle action for the lifecycle hook", "asgName", asgName, "lifecycleHookName", hook.Name, "instanceID", instanceID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// CompleteLifecycleAction completes the lifecycle action of the given hook for an instance of the given AutoScalingGroup.
+// The lifecycle action is identified by the lifecycle action token if set, otherwise by the instance ID.
+func (s *Service) CompleteLifecycleAction(asgName, hookName, instanceID, lifecycleActionToken string, result infrav1.LifecycleHookDefaultResult) error {
+	input := &autoscaling.CompleteLifecycleActionInput{
+		AutoScalingGroupName:  aws.String(asgName),
+		LifecycleHookName:     aws.String(hookName),
+		LifecycleActionResult: aws.String(result.String()),
+	}
+	input.InstanceId, input.LifecycleActionToken = lifecycleActionTarget(instanceID, lifecycleActionToken)
+
+	if _, err := s.ASGClient.CompleteLifecycleActionWithContext(context.TODO(), input); err != nil {
+		return errors.Wrapf(err, "failed to complete lifecycle action of hook %q for instance %q in AutoScalingGroup: %q", hookName, instanceID, asgName)
+	}
+	s.scope.Debug("Completed lifecycle action", "asgName", asgName, "lifecycleHookName", hookName, "instanceID", instanceID, "result", result)
+
+	return nil
+}
+
+// RecordLifecycleActionHeartbeat extends the timeout of the lifecycle action of the given hook for an instance of the
+// given AutoScalingGroup by the heartbeat timeout of the hook. The lifecycle action is identified by the lifecycle action
+// token if set, otherwise by the instance ID.
+func (s *Service) RecordLifecycleActionHeartbeat(asgName, hookName, instanceID, lifecycleActionToken string) error {
+	input := &autoscaling.RecordLifecycleActionHeartbeatInput{
+		AutoScalingGroupName: aws.String(asgName),
+		LifecycleHookName:    aws.String(hookName),
+	}
+	input.InstanceId, input.LifecycleActionToken = lifecycleActionTarget(instanceID, lifecycleActionToken)
+
+	if _, err := s.ASGClient.RecordLifecycleActionHeartbeatWithContext(context.TODO(), input); err != nil {
+		return errors.Wrapf(err, "failed to record lifecycle action heartbeat of hook %q for instance %q in AutoScalingGroup: %q", hookName, instanceID, asgName)
+	}
+	s.scope.Debug("Recorded lifecycle action heartbeat", "asgName", asgName, "lifecycleHookName", hookName, "instanceID", instanceID)
+
+	return nil
+}
+
+// lifecycleActionTarget returns the instance ID and the lifecycle action token to identify a lifecycle action with.
+// AWS accepts either of them, the instance ID is omitted when the lifecycle action token is set.
+func lifecycleActionTarget(instanceID, lifecycleActionToken string) (*string, *string) {
+	if lifecycleActionToken != "" {
+		return nil, aws.String(lifecycleActionToken)
+	}
+	return aws.String(instanceID), nil
+}
+
+// SDKToLifecycleHook converts an AWS SDK LifecycleHook to the CAPA lifecycle hook type.
+func (s *Service) SDKToLifecycleHook(hook *autoscaling.LifecycleHook) *infrav1.AWSLifecycleHook {
+	timeoutDuration := time.Duration(aws.Int64Value(hook.HeartbeatTimeout)) * time.Second
+	metav1Duration := metav1.Duration{Duration: timeoutDuration}
+	defaultResult := infrav1.LifecycleHookDefaultResult(aws.StringValue(hook.DefaultResult))
+	lifecycleTransition := infrav1.LifecycleTransition(aws.StringValue(hook.LifecycleTransition))
+
+	return &infrav1.AWSLifecycleHook{
+		Name:                  aws.StringValue(hook.LifecycleHookName),
+		DefaultResult:         &defaultResult,
+		HeartbeatTimeout:      &metav1Duration,
+		LifecycleTransition:   lifecycleTransition,
+		NotificationTargetARN: hook.NotificationTargetARN,
+		RoleARN:               hook.RoleARN,
+		NotificationMetadata:  hook.NotificationMetadata,
+	}
+}
+
+// LifecycleHookNeedsUpdate returns true if the existing lifecycle hook differs from the expected one.
+// Optional fields that are not set in the expected hook are compared against the defaults AWS applies.
+// The differences are logged at V(2); the notification metadata is not logged as it may hold secrets.
+func (s *Service) LifecycleHookNeedsUpdate(existing *infrav1.AWSLifecycleHook, expected *infrav1.AWSLifecycleHook) bool {
+	expectedHeartbeatTimeout := defaultHeartbeatTimeout
+	if expected.HeartbeatTimeout != nil {
+		expectedHeartbeatTimeout = expected.HeartbeatTimeout.Duration
+	}
+	expectedDefaultResult := defaultLifecycleHookDefaultResult
+	if expected.DefaultResult != nil {
+		expectedDefaultResult = *expected.DefaultResult
+	}
+
+	existingHeartbeatTimeout := defaultHeartbeatTimeout
+	if existing.HeartbeatTimeout != nil {
+		existingHeartbeatTimeout = existing.HeartbeatTimeout.Duration
+	}
+	existingDefaultResult := defaultLifecycleHookDefaultResult
+	if existing.DefaultResult != nil {
+		existingDefaultResult = *existing.DefaultResult
+	}
+
+	var diff []any
+	addDiff := func(field string, oldValue, newValue any) {
+		diff = append(diff, field, map[string]any{"old": oldValue, "new": newValue})
+	}
+
+	if existingDefaultResult != expectedDefaultResult {
+		addDiff("defaultResult", existingDefaultResult, expectedDefaultResult)
+	}
+	if existingHeartbeatTimeout != expectedHeartbeatTimeout {
+		addDiff("heartbeatTimeout", existingHeartbeatTimeout.String(), expectedHeartbeatTimeout.String())
+	}
+	if existing.LifecycleTransition != expected.LifecycleTransition {
+		addDiff("lifecycleTransition", existing.LifecycleTransition, expected.LifecycleTransition)
+	}
+	if aws.StringValue(existing.NotificationTargetARN) != aws.StringValue(expected.NotificationTargetARN) {
+		addDiff("notificationTargetARN", aws.StringValue(existing.NotificationTargetARN), aws.StringValue(expected.NotificationTargetARN))
+	}
+	if aws.StringValue(existing.RoleARN) != aws.StringValue(expected.RoleARN) {
+		addDiff("roleARN", aws.StringValue(existing.RoleARN), aws.StringValue(expected.RoleARN))
+	}
+	if aws.StringValue(existing.NotificationMetadata) != aws.StringValue(expected.NotificationMetadata) {
+		diff = append(diff, "notificationMetadata", "changed")
+	}
+
+	if len(diff) == 0 {
+		return false
+	}
+
+	keysAndValues := append([]any{"lifecycleHookName", expected.Name}, diff...)
+	s.scope.GetLogger().V(2).Info("Lifecycle hook differs from spec", keysAndValues...)
+
+	return true
+}
+
+func getLifecycleHookSpecificationList(lifecycleHooks []infrav1.AWSLifecycleHook) (ret []*autoscaling.LifecycleHookSpecification) {
+	for _, hook := range lifecycleHooks {
+		spec := &autoscaling.LifecycleHookSpecification{
+			LifecycleHookName:     aws.String(hook.Name),
+			LifecycleTransition:   aws.String(hook.LifecycleTransition.String()),
+			NotificationTargetARN: hook.NotificationTargetARN,
+			RoleARN:               hook.RoleARN,
+			NotificationMetadata:  hook.NotificationMetadata,
+		}
+		if hook.DefaultResult != nil {
+			spec.DefaultResult = aws.String(hook.DefaultResult.String())
+		}
+		if hook.HeartbeatTimeout != nil {
+			spec.HeartbeatTimeout = aws.Int64(int64(hook.HeartbeatTimeout.Duration.Seconds()))
+		}
+		ret = append(ret, spec)
+	}
+
+	return
+}
+
+// IsTerminalLifecycleHookError returns true if creating or updating a lifecycle hook failed for a reason that persists
+// until the lifecycle hook spec changes, e.g. the limit of lifecycle hooks per AutoScalingGroup is reached, the role
+// doesn't trust Auto Scaling or the notification metadata is malformed. Retrying such errors is pointless. The other
+// ValidationErrors may be transient, e.g. the test notification fails while a freshly created role or notification
+// target propagates, so they are retried.
+func IsTerminalLifecycleHookError(err error) bool {
+	cause := errors.Cause(err)
+	code, ok := awserrors.Code(cause)
+	if !ok {
+		return false
+	}
+
+	switch code {
+	case awserrors.LimitExceeded:
+		return true
+	case awserrors.ValidationError:
+		msg := strings.ToLower(awserrors.Message(cause))
+		for _, fragment := range terminalLifecycleHookValidationErrors {
+			if strings.Contains(msg, fragment) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsLifecycleHookInUseError returns true if deleting a lifecycle hook failed because instances still wait on it.
+// The deletion succeeds once their lifecycle actions are completed, or once they time out.
+func IsLifecycleHookInUseError(err error) bool {
+	cause := errors.Cause(err)
+	if code, ok := awserrors.Code(cause); !ok || code != awserrors.ValidationError {
+		return false
+	}
+
+	msg := strings.ToLower(awserrors.Message(cause))
+	for _, fragment := range lifecycleHookInUseValidationErrors {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNoActiveLifecycleActionError returns true if completing a lifecycle action failed because the instance doesn't
+// wait on the lifecycle hook, e.g. because it still waits on another hook of the same transition, or because its
+// lifecycle action was already completed.
+func IsNoActiveLifecycleActionError(err error) bool {
+	cause := errors.Cause(err)
+	if code, ok := awserrors.Code(cause); !ok || code != awserrors.ValidationError {
+		return false
+	}
+	return strings.Contains(strings.ToLower(awserrors.Message(cause)), noActiveLifecycleActionValidationError)
+}
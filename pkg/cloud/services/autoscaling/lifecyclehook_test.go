@@ -0,0 +1,464 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/autoscaling/mock_autoscalingiface"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/fakeaws"
+)
+
+func TestServiceDescribeLifecycleHooks(t *testing.T) {
+	tests := []struct {
+		name      string
+		wantErr   bool
+		wantHooks []*infrav1.AWSLifecycleHook
+		setup     func(f *fakeaws.AutoScalingAPI)
+	}{
+		{
+			name:    "should return the lifecycle hooks of the ASG",
+			wantErr: false,
+			wantHooks: []*infrav1.AWSLifecycleHook{
+				{
+					Name:                "hook",
+					LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+					HeartbeatTimeout:    &metav1.Duration{Duration: 300 * time.Second},
+					DefaultResult:       ptr.To[infrav1.LifecycleHookDefaultResult](infrav1.LifecycleHookDefaultResultContinue),
+				},
+			},
+			setup: func(f *fakeaws.AutoScalingAPI) {
+				f.AddAutoScalingGroup(&autoscaling.Group{AutoScalingGroupName: aws.String("asg")})
+				_, err := f.PutLifecycleHookWithContext(context.TODO(), &autoscaling.PutLifecycleHookInput{
+					AutoScalingGroupName: aws.String("asg"),
+					LifecycleHookName:    aws.String("hook"),
+					LifecycleTransition:  aws.String("autoscaling:EC2_INSTANCE_TERMINATING"),
+					HeartbeatTimeout:     aws.Int64(300),
+					DefaultResult:        aws.String("CONTINUE"),
+				})
+				if err != nil {
+					panic(err)
+				}
+			},
+		},
+		{
+			name:    "should return error if the ASG doesn't exist",
+			wantErr: true,
+			setup:   func(f *fakeaws.AutoScalingAPI) {},
+		},
+		{
+			name:    "should return error if describe lifecycle hooks failed",
+			wantErr: true,
+			setup: func(f *fakeaws.AutoScalingAPI) {
+				f.AddAutoScalingGroup(&autoscaling.Group{AutoScalingGroupName: aws.String("asg")})
+				f.InjectError("DescribeLifecycleHooks", awserrors.NewFailedDependency("dependency failure"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			asgFake := fakeaws.NewAutoScalingAPI()
+			tt.setup(asgFake)
+			cs, err := getClusterScope(getFakeClient())
+			g.Expect(err).NotTo(HaveOccurred())
+			s := &Service{scope: cs, ASGClient: asgFake}
+
+			hooks, err := s.DescribeLifecycleHooks("asg")
+			checkErr(tt.wantErr, err, g)
+			if !tt.wantErr {
+				g.Expect(hooks).To(Equal(tt.wantHooks))
+			}
+		})
+	}
+}
+
+func TestServiceCreateLifecycleHook(t *testing.T) {
+	g := NewWithT(t)
+	asgFake := fakeaws.NewAutoScalingAPI()
+	asgFake.AddAutoScalingGroup(&autoscaling.Group{AutoScalingGroupName: aws.String("asg")})
+	cs, err := getClusterScope(getFakeClient())
+	g.Expect(err).NotTo(HaveOccurred())
+	s := &Service{scope: cs, ASGClient: asgFake}
+
+	hook := &infrav1.AWSLifecycleHook{
+		Name:                  "hook",
+		LifecycleTransition:   infrav1.LifecycleTransitionInstanceLaunch,
+		HeartbeatTimeout:      &metav1.Duration{Duration: 600 * time.Second},
+		DefaultResult:         ptr.To[infrav1.LifecycleHookDefaultResult](infrav1.LifecycleHookDefaultResultAbandon),
+		NotificationTargetARN: aws.String("arn:aws:sqs:us-east-1:123456789012:queue"),
+		RoleARN:               aws.String("arn:aws:iam::123456789012:role/role"),
+	}
+	g.Expect(s.CreateLifecycleHook("asg", hook)).To(Succeed())
+
+	// The hook read back from the ASG must not be seen as drifted, otherwise it would be updated on every reconcile.
+	hooks, err := s.DescribeLifecycleHooks("asg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hooks).To(HaveLen(1))
+	g.Expect(hooks[0]).To(Equal(hook))
+	g.Expect(s.LifecycleHookNeedsUpdate(hooks[0], hook)).To(BeFalse())
+
+	// A hook relying on the AWS defaults must not be seen as drifted either.
+	defaultsHook := &infrav1.AWSLifecycleHook{
+		Name:                "defaults-hook",
+		LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+	}
+	g.Expect(s.CreateLifecycleHook("asg", defaultsHook)).To(Succeed())
+	hooks, err = s.DescribeLifecycleHooks("asg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hooks).To(HaveLen(2))
+	g.Expect(s.LifecycleHookNeedsUpdate(hooks[1], defaultsHook)).To(BeFalse())
+
+	g.Expect(s.DeleteLifecycleHook("asg", hook, false)).To(Succeed())
+	hooks, err = s.DescribeLifecycleHooks("asg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hooks).To(ConsistOf(HaveField("Name", "defaults-hook")))
+	g.Expect(asgFake.Calls()).To(Equal([]string{
+		"PutLifecycleHook", "DescribeLifecycleHooks", "PutLifecycleHook", "DescribeLifecycleHooks", "DeleteLifecycleHook", "DescribeLifecycleHooks",
+	}))
+}
+
+func TestServiceDeleteLifecycleHookInUse(t *testing.T) {
+	hook := &infrav1.AWSLifecycleHook{
+		Name:                "launch-hook",
+		LifecycleTransition: infrav1.LifecycleTransitionInstanceLaunch,
+	}
+	setup := func(g *WithT) (*Service, *fakeaws.AutoScalingAPI) {
+		asgFake := fakeaws.NewAutoScalingAPI()
+		asgFake.AddAutoScalingGroup(&autoscaling.Group{AutoScalingGroupName: aws.String("asg")})
+		cs, err := getClusterScope(getFakeClient())
+		g.Expect(err).NotTo(HaveOccurred())
+		s := &Service{scope: cs, ASGClient: asgFake}
+		g.Expect(s.CreateLifecycleHook("asg", hook)).To(Succeed())
+		g.Expect(asgFake.SetInstances("asg",
+			&autoscaling.Instance{InstanceId: aws.String("i-launching"), LifecycleState: aws.String(autoscaling.LifecycleStatePendingWait)},
+			&autoscaling.Instance{InstanceId: aws.String("i-terminating"), LifecycleState: aws.String(autoscaling.LifecycleStateTerminatingWait)},
+		)).To(Succeed())
+		return s, asgFake
+	}
+
+	t.Run("should return an in use error without forceDelete", func(t *testing.T) {
+		g := NewWithT(t)
+		s, asgFake := setup(g)
+
+		err := s.DeleteLifecycleHook("asg", hook, false)
+		g.Expect(IsLifecycleHookInUseError(err)).To(BeTrue())
+		g.Expect(IsTerminalLifecycleHookError(err)).To(BeFalse())
+		g.Expect(asgFake.CompletedLifecycleActions()).To(BeEmpty())
+	})
+
+	t.Run("should abandon the pending lifecycle actions of the hook with forceDelete", func(t *testing.T) {
+		g := NewWithT(t)
+		s, asgFake := setup(g)
+
+		g.Expect(s.DeleteLifecycleHook("asg", hook, true)).To(Succeed())
+		g.Expect(asgFake.CompletedLifecycleActions()).To(Equal([]*autoscaling.CompleteLifecycleActionInput{{
+			AutoScalingGroupName:  aws.String("asg"),
+			LifecycleHookName:     aws.String("launch-hook"),
+			InstanceId:            aws.String("i-launching"),
+			LifecycleActionResult: aws.String("ABANDON"),
+		}}))
+		hooks, err := s.DescribeLifecycleHooks("asg")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(hooks).To(BeEmpty())
+	})
+
+	t.Run("should not treat other validation errors as in use", func(t *testing.T) {
+		g := NewWithT(t)
+		s, _ := setup(g)
+
+		err := s.DeleteLifecycleHook("asg", &infrav1.AWSLifecycleHook{Name: "missing-hook"}, true)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(IsLifecycleHookInUseError(err)).To(BeFalse())
+	})
+}
+
+func TestServiceCompleteLifecycleAction(t *testing.T) {
+	tests := []struct {
+		name                 string
+		lifecycleActionToken string
+		expect               func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder)
+		wantErr              bool
+	}{
+		{
+			name: "should complete the lifecycle action of the instance",
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.CompleteLifecycleActionWithContext(context.TODO(), gomock.Eq(&autoscaling.CompleteLifecycleActionInput{
+					AutoScalingGroupName:  aws.String("asg"),
+					LifecycleHookName:     aws.String(expinfrav1.ManagedLaunchLifecycleHookName),
+					InstanceId:            aws.String("i-1234567890abcdef0"),
+					LifecycleActionResult: aws.String("CONTINUE"),
+				})).Return(&autoscaling.CompleteLifecycleActionOutput{}, nil)
+			},
+		},
+		{
+			name:                 "should omit the instance ID when the lifecycle action token is set",
+			lifecycleActionToken: "bcd2f1b8-9a78-44d3-8a7a-4dd07d7cf635",
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.CompleteLifecycleActionWithContext(context.TODO(), gomock.Eq(&autoscaling.CompleteLifecycleActionInput{
+					AutoScalingGroupName:  aws.String("asg"),
+					LifecycleHookName:     aws.String(expinfrav1.ManagedLaunchLifecycleHookName),
+					LifecycleActionToken:  aws.String("bcd2f1b8-9a78-44d3-8a7a-4dd07d7cf635"),
+					LifecycleActionResult: aws.String("CONTINUE"),
+				})).Return(&autoscaling.CompleteLifecycleActionOutput{}, nil)
+			},
+		},
+		{
+			name: "should return an error if the lifecycle action can't be completed",
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.CompleteLifecycleActionWithContext(context.TODO(), gomock.Any()).Return(nil, awserrors.NewFailedDependency("dependency failure"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+			tt.expect(asgMock.EXPECT())
+			cs, err := getClusterScope(getFakeClient())
+			g.Expect(err).NotTo(HaveOccurred())
+			s := &Service{scope: cs, ASGClient: asgMock}
+
+			err = s.CompleteLifecycleAction("asg", expinfrav1.ManagedLaunchLifecycleHookName, "i-1234567890abcdef0", tt.lifecycleActionToken, infrav1.LifecycleHookDefaultResultContinue)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestServiceRecordLifecycleActionHeartbeat(t *testing.T) {
+	tests := []struct {
+		name                 string
+		lifecycleActionToken string
+		expect               func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder)
+		wantErr              bool
+	}{
+		{
+			name: "should record a heartbeat for the lifecycle action of the instance",
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.RecordLifecycleActionHeartbeatWithContext(context.TODO(), gomock.Eq(&autoscaling.RecordLifecycleActionHeartbeatInput{
+					AutoScalingGroupName: aws.String("asg"),
+					LifecycleHookName:    aws.String("drain"),
+					InstanceId:           aws.String("i-1234567890abcdef0"),
+				})).Return(&autoscaling.RecordLifecycleActionHeartbeatOutput{}, nil)
+			},
+		},
+		{
+			name:                 "should omit the instance ID when the lifecycle action token is set",
+			lifecycleActionToken: "bcd2f1b8-9a78-44d3-8a7a-4dd07d7cf635",
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.RecordLifecycleActionHeartbeatWithContext(context.TODO(), gomock.Eq(&autoscaling.RecordLifecycleActionHeartbeatInput{
+					AutoScalingGroupName: aws.String("asg"),
+					LifecycleHookName:    aws.String("drain"),
+					LifecycleActionToken: aws.String("bcd2f1b8-9a78-44d3-8a7a-4dd07d7cf635"),
+				})).Return(&autoscaling.RecordLifecycleActionHeartbeatOutput{}, nil)
+			},
+		},
+		{
+			name: "should return an error if the heartbeat can't be recorded",
+			expect: func(m *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+				m.RecordLifecycleActionHeartbeatWithContext(context.TODO(), gomock.Any()).Return(nil, awserrors.NewFailedDependency("dependency failure"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+			tt.expect(asgMock.EXPECT())
+			cs, err := getClusterScope(getFakeClient())
+			g.Expect(err).NotTo(HaveOccurred())
+			s := &Service{scope: cs, ASGClient: asgMock}
+
+			err = s.RecordLifecycleActionHeartbeat("asg", "drain", "i-1234567890abcdef0", tt.lifecycleActionToken)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestServiceLifecycleHookNeedsUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *infrav1.AWSLifecycleHook
+		expected *infrav1.AWSLifecycleHook
+		want     bool
+	}{
+		{
+			name: "should not need an update if unset fields match the AWS defaults",
+			existing: &infrav1.AWSLifecycleHook{
+				Name:                "hook",
+				LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+				HeartbeatTimeout:    &metav1.Duration{Duration: 3600 * time.Second},
+				DefaultResult:       ptr.To[infrav1.LifecycleHookDefaultResult](infrav1.LifecycleHookDefaultResultAbandon),
+			},
+			expected: &infrav1.AWSLifecycleHook{
+				Name:                "hook",
+				LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+			},
+			want: false,
+		},
+		{
+			name: "should need an update if the heartbeat timeout changed",
+			existing: &infrav1.AWSLifecycleHook{
+				Name:                "hook",
+				LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+				HeartbeatTimeout:    &metav1.Duration{Duration: 300 * time.Second},
+			},
+			expected: &infrav1.AWSLifecycleHook{
+				Name:                "hook",
+				LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+				HeartbeatTimeout:    &metav1.Duration{Duration: 600 * time.Second},
+			},
+			want: true,
+		},
+		{
+			name: "should need an update if the notification target changed",
+			existing: &infrav1.AWSLifecycleHook{
+				Name:                "hook",
+				LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+			},
+			expected: &infrav1.AWSLifecycleHook{
+				Name:                  "hook",
+				LifecycleTransition:   infrav1.LifecycleTransitionInstanceTerminate,
+				NotificationTargetARN: aws.String("arn:aws:sqs:us-east-1:123456789012:queue"),
+				RoleARN:               aws.String("arn:aws:iam::123456789012:role/role"),
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cs, err := getClusterScope(getFakeClient())
+			g.Expect(err).NotTo(HaveOccurred())
+			s := &Service{scope: cs}
+			g.Expect(s.LifecycleHookNeedsUpdate(tt.existing, tt.expected)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestIsTerminalLifecycleHookError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "lifecycle hook limit exceeded",
+			err:  awserr.New(awserrors.LimitExceeded, "Lifecycle hook limit of 50 exceeded for AutoScalingGroup asg", nil),
+			want: true,
+		},
+		{
+			name: "lifecycle hook limit exceeded as a validation error",
+			err:  awserr.New(awserrors.ValidationError, "Lifecycle hook limit exceeded for AutoScalingGroup asg", nil),
+			want: true,
+		},
+		{
+			name: "role doesn't trust Auto Scaling",
+			err: awserr.New(awserrors.ValidationError, "Unable to assume role arn:aws:iam::123456789012:role/role. "+
+				"Please check the trust relationship of the role and try to put lifecycle hook again.", nil),
+			want: true,
+		},
+		{
+			name: "malformed notification metadata",
+			err:  awserr.New(awserrors.ValidationError, "1 validation error detected: Value at 'notificationMetadata' failed to satisfy constraint: Member must have length less than or equal to 1023", nil),
+			want: true,
+		},
+		{
+			name: "wrapped terminal error",
+			err:  errors.Wrap(awserr.New(awserrors.LimitExceeded, "Lifecycle hook limit exceeded", nil), "failed to create lifecycle hook"),
+			want: true,
+		},
+		{
+			name: "test notification failing while the role or notification target propagates",
+			err: awserr.New(awserrors.ValidationError, "Unable to publish test message to notification target arn:aws:sqs:us-east-1:123456789012:queue "+
+				"using IAM role arn:aws:iam::123456789012:role/role. Please check your target and role configuration and try to put lifecycle hook again.", nil),
+			want: false,
+		},
+		{
+			name: "other validation error",
+			err:  awserr.New(awserrors.ValidationError, "The lifecycle hook configuration is invalid", nil),
+			want: false,
+		},
+		{
+			name: "AutoScalingGroup not found",
+			err:  awserr.New(awserrors.ValidationError, "No AutoScalingGroup named asg", nil),
+			want: false,
+		},
+		{
+			name: "lifecycle hook in use",
+			err:  awserr.New(awserrors.ValidationError, "Lifecycle hook hook is in use by a pending lifecycle action for group asg", nil),
+			want: false,
+		},
+		{
+			name: "resource contention",
+			err:  awserr.New(autoscaling.ErrCodeResourceContentionFault, "You already have a pending update to an Auto Scaling resource", nil),
+			want: false,
+		},
+		{
+			name: "throttling",
+			err:  awserr.New("Throttling", "Rate exceeded", nil),
+			want: false,
+		},
+		{
+			name: "not an AWS error",
+			err:  errors.New("connection reset"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(IsTerminalLifecycleHookError(tt.err)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestIsNoActiveLifecycleActionError(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(IsNoActiveLifecycleActionError(errors.Wrap(awserr.New(awserrors.ValidationError,
+		"No active Lifecycle Action found with instance ID i-1", nil), "failed to complete lifecycle action"))).To(BeTrue())
+	g.Expect(IsNoActiveLifecycleActionError(awserr.New(awserrors.ValidationError, "No AutoScalingGroup named asg", nil))).To(BeFalse())
+	g.Expect(IsNoActiveLifecycleActionError(awserr.New("Throttling", "Rate exceeded", nil))).To(BeFalse())
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asg
+
+import (
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+)
+
+// ASGManager manages existing Auto Scaling groups by name, without the scopes of the CAPA controllers.
+//
+// ASGManager is a stable interface for the projects embedding the services: within a major version of CAPA,
+// methods are only added to it, never changed or removed.
+type ASGManager interface {
+	ASGIfExists(name *string) (*expinfrav1.AutoScalingGroup, error)
+	DeleteASGAndWait(name string) error
+	UpdateResourceTags(name *string, create, remove map[string]string) error
+	SuspendProcesses(name string, processes []string) error
+	ResumeProcesses(name string, processes []string) error
+	EnableMetricsCollection(name, granularity string, metrics []string) error
+	DisableMetricsCollection(name string, metrics []string) error
+	SetInstanceProtection(name string, instanceIDs []string, protected bool) error
+	DescribeInstanceRefresh(name, id string) (*expinfrav1.InstanceRefreshStatus, error)
+	DescribeScalingActivities(name string) ([]*expinfrav1.ScalingActivity, error)
+	DescribeTargetGroupAttachments(name string) ([]string, error)
+	AttachTargetGroups(name string, arns []string) error
+	DetachTargetGroups(name string, arns []string) error
+	AttachLoadBalancers(name string, loadBalancerNames []string) error
+	DetachLoadBalancers(name string, loadBalancerNames []string) error
+}
+
+// LifecycleHookManager manages the lifecycle hooks of Auto Scaling groups and the lifecycle actions of their
+// instances, without the scopes of the CAPA controllers.
+//
+// LifecycleHookManager is a stable interface for the projects embedding the services: within a major version of
+// CAPA, methods are only added to it, never changed or removed.
+type LifecycleHookManager interface {
+	DescribeLifecycleHooks(asgName string) ([]*infrav1.AWSLifecycleHook, error)
+	CreateLifecycleHook(asgName string, hook *infrav1.AWSLifecycleHook) error
+	UpdateLifecycleHook(asgName string, hook *infrav1.AWSLifecycleHook) error
+	DeleteLifecycleHook(asgName string, hook *infrav1.AWSLifecycleHook, forceDelete bool) error
+	LifecycleHookNeedsUpdate(existing *infrav1.AWSLifecycleHook, expected *infrav1.AWSLifecycleHook) bool
+	CompleteLifecycleAction(asgName, hookName, instanceID, lifecycleActionToken string, result infrav1.LifecycleHookDefaultResult) error
+	RecordLifecycleActionHeartbeat(asgName, hookName, instanceID, lifecycleActionToken string) error
+}
+
+var (
+	_ ASGManager           = &Service{}
+	_ LifecycleHookManager = &Service{}
+)
+
+// NewASGManager returns an ASGManager using the session and the cluster name of the parameters.
+func NewASGManager(params scope.ServiceScopeParams) (ASGManager, error) {
+	return newServiceFromParams(params)
+}
+
+// NewLifecycleHookManager returns a LifecycleHookManager using the session and the cluster name of the parameters.
+func NewLifecycleHookManager(params scope.ServiceScopeParams) (LifecycleHookManager, error) {
+	return newServiceFromParams(params)
+}
+
+func newServiceFromParams(params scope.ServiceScopeParams) (*Service, error) {
+	serviceScope, err := scope.NewServiceScope(params)
+	if err != nil {
+		return nil, err
+	}
+	return NewService(serviceScope), nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asg
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/fakeaws"
+)
+
+func newTestSession(t *testing.T) *session.Session {
+	t.Helper()
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sess
+}
+
+func TestNewASGManager(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewASGManager(scope.ServiceScopeParams{Session: newTestSession(t)})
+	g.Expect(err).To(MatchError(ContainSubstring("cluster name is required")))
+
+	manager, err := NewASGManager(scope.ServiceScopeParams{
+		Session:     newTestSession(t),
+		ClusterName: "test-cluster",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The AWS API is faked, the manager is otherwise used as by any project embedding it.
+	asgFake := fakeaws.NewAutoScalingAPI()
+	asgFake.AddAutoScalingGroup(&autoscaling.Group{AutoScalingGroupName: aws.String("asg")})
+	manager.(*Service).ASGClient = asgFake
+
+	g.Expect(manager.SuspendProcesses("asg", []string{"Launch"})).To(Succeed())
+	g.Expect(manager.UpdateResourceTags(aws.String("asg"), map[string]string{"team": "a"}, nil)).To(Succeed())
+
+	asg, err := manager.ASGIfExists(aws.String("asg"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(asg.CurrentlySuspendProcesses).To(ConsistOf("Launch"))
+	g.Expect(asg.Tags).To(HaveKeyWithValue("team", "a"))
+
+	asg, err = manager.ASGIfExists(aws.String("missing"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(asg).To(BeNil())
+}
+
+func TestNewLifecycleHookManager(t *testing.T) {
+	g := NewWithT(t)
+
+	manager, err := NewLifecycleHookManager(scope.ServiceScopeParams{
+		Session:     newTestSession(t),
+		ClusterName: "test-cluster",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	asgFake := fakeaws.NewAutoScalingAPI()
+	asgFake.AddAutoScalingGroup(&autoscaling.Group{AutoScalingGroupName: aws.String("asg")})
+	manager.(*Service).ASGClient = asgFake
+
+	hook := &infrav1.AWSLifecycleHook{
+		Name:                "drain",
+		LifecycleTransition: infrav1.LifecycleTransitionInstanceTerminate,
+	}
+	g.Expect(manager.CreateLifecycleHook("asg", hook)).To(Succeed())
+
+	hooks, err := manager.DescribeLifecycleHooks("asg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hooks).To(HaveLen(1))
+	g.Expect(hooks[0].Name).To(Equal("drain"))
+	g.Expect(manager.LifecycleHookNeedsUpdate(hooks[0], hook)).To(BeFalse())
+
+	g.Expect(manager.DeleteLifecycleHook("asg", hook, true)).To(Succeed())
+	hooks, err = manager.DescribeLifecycleHooks("asg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hooks).To(BeEmpty())
+}
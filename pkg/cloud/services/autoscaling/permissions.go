@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asg
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+)
+
+// lifecycleHookActions are the autoscaling actions needed to reconcile lifecycle hooks.
+var lifecycleHookActions = []string{
+	"autoscaling:DeleteLifecycleHook",
+	"autoscaling:DescribeLifecycleHooks",
+	"autoscaling:PutLifecycleHook",
+}
+
+// LifecycleHookPermissionsCheckInterval is how long the result of the lifecycle hook permission check of an
+// AWS identity is cached, after which permissions granted or revoked in the meantime are taken into account.
+const LifecycleHookPermissionsCheckInterval = 10 * time.Minute
+
+// lifecycleHookPermissions caches the missing lifecycle hook permissions per AWS principal and ASG.
+var lifecycleHookPermissions sync.Map
+
+// rolePrincipalARNs caches the ARN of the role of the assumed role sessions, which may include a path.
+var rolePrincipalARNs sync.Map
+
+type lifecycleHookPermissionsEntry struct {
+	missing   []string
+	checkedAt time.Time
+}
+
+// MissingLifecycleHookPermissions returns the autoscaling actions needed to reconcile the lifecycle hooks of the
+// ASG that the AWS principal of the cluster is not allowed to perform. The principal policies are simulated
+// against the ASG and its tags once per principal and ASG, and the result is cached for
+// LifecycleHookPermissionsCheckInterval. If the principal is not allowed to simulate its own policies, or the
+// simulation can't tell whether an action is allowed, the action is not reported missing.
+func (s *Service) MissingLifecycleHookPermissions(group *expinfrav1.AutoScalingGroup) ([]string, error) {
+	principalARN, err := s.principalARN()
+	if err != nil {
+		return nil, err
+	}
+
+	key := principalARN + " " + group.ID
+	if entry, ok := lifecycleHookPermissions.Load(key); ok {
+		if entry := entry.(lifecycleHookPermissionsEntry); time.Since(entry.checkedAt) < LifecycleHookPermissionsCheckInterval {
+			return entry.missing, nil
+		}
+	}
+
+	missing, err := s.simulateLifecycleHookPermissions(principalARN, group)
+	if err != nil {
+		if !isAccessDenied(err) {
+			return nil, err
+		}
+		s.scope.Info("Unable to verify lifecycle hook permissions, assuming they are granted", "reason", err.Error())
+		missing = []string{}
+	}
+
+	lifecycleHookPermissions.Store(key, lifecycleHookPermissionsEntry{missing: missing, checkedAt: time.Now()})
+	return missing, nil
+}
+
+func (s *Service) simulateLifecycleHookPermissions(principalARN string, group *expinfrav1.AutoScalingGroup) ([]string, error) {
+	input := &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principalARN),
+		ActionNames:     aws.StringSlice(lifecycleHookActions),
+	}
+	if group.ID != "" {
+		input.ResourceArns = aws.StringSlice([]string{group.ID})
+	}
+	for _, key := range sets.List(sets.KeySet(group.Tags)) {
+		input.ContextEntries = append(input.ContextEntries, &iam.ContextEntry{
+			ContextKeyName:   aws.String("autoscaling:ResourceTag/" + key),
+			ContextKeyType:   aws.String(iam.ContextKeyTypeEnumString),
+			ContextKeyValues: aws.StringSlice([]string{group.Tags[key]}),
+		})
+	}
+
+	out, err := s.IAMClient.SimulatePrincipalPolicyWithContext(context.TODO(), input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to simulate the policies of principal %q", principalARN)
+	}
+
+	missing := []string{}
+	for _, result := range out.EvaluationResults {
+		switch aws.StringValue(result.EvalDecision) {
+		case iam.PolicyEvaluationDecisionTypeAllowed:
+		case iam.PolicyEvaluationDecisionTypeImplicitDeny:
+			// A statement matching the action whose conditions depend on context the simulation doesn't have,
+			// such as the request tags, may still allow the actual requests.
+			if len(result.MissingContextValues) > 0 || len(result.MatchedStatements) > 0 {
+				s.scope.Debug("Unable to verify lifecycle hook permission, assuming it is granted",
+					"action", aws.StringValue(result.EvalActionName), "missingContextValues", aws.StringValueSlice(result.MissingContextValues))
+				continue
+			}
+			missing = append(missing, aws.StringValue(result.EvalActionName))
+		default:
+			missing = append(missing, aws.StringValue(result.EvalActionName))
+		}
+	}
+	sort.Strings(missing)
+
+	s.scope.Debug("Verified lifecycle hook permissions", "principal", principalARN, "asg", group.ID, "missing", missing)
+	return missing, nil
+}
+
+// principalARN returns the ARN of the IAM user or role the controller acts as. The policies of an
+// assumed role session can only be simulated through the role itself, whose ARN may include a path.
+// If the controller is not allowed to get the role, the ARN of the role without path is assumed.
+func (s *Service) principalARN() (string, error) {
+	identity, err := s.STSClient.GetCallerIdentityWithContext(context.TODO(), &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get caller identity")
+	}
+
+	callerARN := aws.StringValue(identity.Arn)
+	parsed, err := arn.Parse(callerARN)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse caller identity ARN %q", callerARN)
+	}
+
+	parts := strings.Split(parsed.Resource, "/")
+	if parsed.Service != "sts" || len(parts) < 2 || parts[0] != "assumed-role" {
+		return callerARN, nil
+	}
+
+	key := arn.ARN{Partition: parsed.Partition, Service: "iam", AccountID: parsed.AccountID, Resource: "role/" + parts[1]}.String()
+	if roleARN, ok := rolePrincipalARNs.Load(key); ok {
+		return roleARN.(string), nil
+	}
+
+	roleARN := key
+	role, err := s.IAMClient.GetRoleWithContext(context.TODO(), &iam.GetRoleInput{RoleName: aws.String(parts[1])})
+	switch {
+	case err == nil:
+		roleARN = aws.StringValue(role.Role.Arn)
+	case !isAccessDenied(err):
+		return "", errors.Wrapf(err, "failed to get role %q", parts[1])
+	}
+	rolePrincipalARNs.Store(key, roleARN)
+	return roleARN, nil
+}
+
+func isAccessDenied(err error) bool {
+	code, ok := awserrors.Code(errors.Cause(err))
+	return ok && code == awserrors.AccessDenied
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/iamauth/mock_iamauth"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/sts/mock_stsiface"
+)
+
+func TestServiceMissingLifecycleHookPermissions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	group := &expinfrav1.AutoScalingGroup{
+		ID:   "arn:aws:autoscaling:us-east-1:123456789012:autoScalingGroup:uuid:autoScalingGroupName/asg",
+		Name: "asg",
+		Tags: infrav1.Tags{"Name": "asg", "team": "infra"},
+	}
+	simulateInput := func(principal string) *iam.SimulatePrincipalPolicyInput {
+		return &iam.SimulatePrincipalPolicyInput{
+			PolicySourceArn: aws.String(principal),
+			ActionNames:     aws.StringSlice(lifecycleHookActions),
+			ResourceArns:    aws.StringSlice([]string{group.ID}),
+			ContextEntries: []*iam.ContextEntry{
+				{
+					ContextKeyName:   aws.String("autoscaling:ResourceTag/Name"),
+					ContextKeyType:   aws.String(iam.ContextKeyTypeEnumString),
+					ContextKeyValues: aws.StringSlice([]string{"asg"}),
+				},
+				{
+					ContextKeyName:   aws.String("autoscaling:ResourceTag/team"),
+					ContextKeyType:   aws.String(iam.ContextKeyTypeEnumString),
+					ContextKeyValues: aws.StringSlice([]string{"infra"}),
+				},
+			},
+		}
+	}
+	results := func(decisions map[string]string) *iam.SimulatePolicyResponse {
+		out := &iam.SimulatePolicyResponse{}
+		for _, action := range lifecycleHookActions {
+			out.EvaluationResults = append(out.EvaluationResults, &iam.EvaluationResult{
+				EvalActionName: aws.String(action),
+				EvalDecision:   aws.String(decisions[action]),
+			})
+		}
+		return out
+	}
+	callerIdentity := func(s *mock_stsiface.MockSTSAPIMockRecorder, callerARN string) {
+		// The caller identity is resolved on every check, to look up the cached result of its principal.
+		s.GetCallerIdentityWithContext(context.TODO(), gomock.Any()).Return(&sts.GetCallerIdentityOutput{
+			Arn: aws.String(callerARN),
+		}, nil).AnyTimes()
+	}
+	allowed := iam.PolicyEvaluationDecisionTypeAllowed
+	denied := iam.PolicyEvaluationDecisionTypeImplicitDeny
+	resetCache := func() {
+		lifecycleHookPermissions.Range(func(key, _ interface{}) bool {
+			lifecycleHookPermissions.Delete(key)
+			return true
+		})
+		rolePrincipalARNs.Range(func(key, _ interface{}) bool {
+			rolePrincipalARNs.Delete(key)
+			return true
+		})
+	}
+
+	tests := []struct {
+		name        string
+		wantErr     bool
+		wantMissing []string
+		expect      func(i *mock_iamauth.MockIAMAPIMockRecorder, s *mock_stsiface.MockSTSAPIMockRecorder)
+	}{
+		{
+			name:        "should return no missing permission if all actions are allowed on the ASG",
+			wantMissing: []string{},
+			expect: func(i *mock_iamauth.MockIAMAPIMockRecorder, s *mock_stsiface.MockSTSAPIMockRecorder) {
+				callerIdentity(s, "arn:aws:iam::123456789012:user/capa")
+				i.SimulatePrincipalPolicyWithContext(context.TODO(), gomock.Eq(simulateInput("arn:aws:iam::123456789012:user/capa"))).
+					Return(results(map[string]string{
+						"autoscaling:DeleteLifecycleHook":    allowed,
+						"autoscaling:DescribeLifecycleHooks": allowed,
+						"autoscaling:PutLifecycleHook":       allowed,
+					}), nil)
+			},
+		},
+		{
+			name:        "should simulate the policies of the role of an assumed role session",
+			wantMissing: []string{"autoscaling:DeleteLifecycleHook", "autoscaling:PutLifecycleHook"},
+			expect: func(i *mock_iamauth.MockIAMAPIMockRecorder, s *mock_stsiface.MockSTSAPIMockRecorder) {
+				callerIdentity(s, "arn:aws:sts::123456789012:assumed-role/controllers/session")
+				i.GetRoleWithContext(context.TODO(), gomock.Eq(&iam.GetRoleInput{RoleName: aws.String("controllers")})).
+					Return(&iam.GetRoleOutput{Role: &iam.Role{Arn: aws.String("arn:aws:iam::123456789012:role/capa/controllers")}}, nil)
+				i.SimulatePrincipalPolicyWithContext(context.TODO(), gomock.Eq(simulateInput("arn:aws:iam::123456789012:role/capa/controllers"))).
+					Return(results(map[string]string{
+						"autoscaling:DeleteLifecycleHook":    denied,
+						"autoscaling:DescribeLifecycleHooks": allowed,
+						"autoscaling:PutLifecycleHook":       iam.PolicyEvaluationDecisionTypeExplicitDeny,
+					}), nil)
+			},
+		},
+		{
+			name:        "should simulate the policies of the role without path if the controller can't get the role",
+			wantMissing: []string{},
+			expect: func(i *mock_iamauth.MockIAMAPIMockRecorder, s *mock_stsiface.MockSTSAPIMockRecorder) {
+				callerIdentity(s, "arn:aws:sts::123456789012:assumed-role/controllers/session")
+				i.GetRoleWithContext(context.TODO(), gomock.Any()).
+					Return(nil, awserr.New(awserrors.AccessDenied, "not authorized to perform: iam:GetRole", nil))
+				i.SimulatePrincipalPolicyWithContext(context.TODO(), gomock.Eq(simulateInput("arn:aws:iam::123456789012:role/controllers"))).
+					Return(results(map[string]string{
+						"autoscaling:DeleteLifecycleHook":    allowed,
+						"autoscaling:DescribeLifecycleHooks": allowed,
+						"autoscaling:PutLifecycleHook":       allowed,
+					}), nil)
+			},
+		},
+		{
+			name:        "should not report the actions denied for lack of context as missing",
+			wantMissing: []string{"autoscaling:DescribeLifecycleHooks"},
+			expect: func(i *mock_iamauth.MockIAMAPIMockRecorder, s *mock_stsiface.MockSTSAPIMockRecorder) {
+				callerIdentity(s, "arn:aws:iam::123456789012:user/capa")
+				out := results(map[string]string{
+					"autoscaling:DeleteLifecycleHook":    denied,
+					"autoscaling:DescribeLifecycleHooks": denied,
+					"autoscaling:PutLifecycleHook":       denied,
+				})
+				out.EvaluationResults[0].MissingContextValues = aws.StringSlice([]string{"aws:RequestTag/team"})
+				out.EvaluationResults[2].MatchedStatements = []*iam.Statement{{SourcePolicyId: aws.String("capa")}}
+				i.SimulatePrincipalPolicyWithContext(context.TODO(), gomock.Eq(simulateInput("arn:aws:iam::123456789012:user/capa"))).
+					Return(out, nil)
+			},
+		},
+		{
+			name:        "should assume the permissions are granted if the principal can't simulate its policies",
+			wantMissing: []string{},
+			expect: func(i *mock_iamauth.MockIAMAPIMockRecorder, s *mock_stsiface.MockSTSAPIMockRecorder) {
+				callerIdentity(s, "arn:aws:iam::123456789012:user/capa")
+				i.SimulatePrincipalPolicyWithContext(context.TODO(), gomock.Any()).
+					Return(nil, awserr.New(awserrors.AccessDenied, "not authorized to perform: iam:SimulatePrincipalPolicy", nil))
+			},
+		},
+		{
+			name:    "should return error if the caller identity can't be retrieved",
+			wantErr: true,
+			expect: func(i *mock_iamauth.MockIAMAPIMockRecorder, s *mock_stsiface.MockSTSAPIMockRecorder) {
+				s.GetCallerIdentityWithContext(context.TODO(), gomock.Any()).Return(nil, awserrors.NewFailedDependency("dependency failure"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			iamMock := mock_iamauth.NewMockIAMAPI(mockCtrl)
+			stsMock := mock_stsiface.NewMockSTSAPI(mockCtrl)
+			tt.expect(iamMock.EXPECT(), stsMock.EXPECT())
+			cs, err := getClusterScope(getFakeClient())
+			g.Expect(err).NotTo(HaveOccurred())
+			s := &Service{scope: cs, IAMClient: iamMock, STSClient: stsMock}
+
+			resetCache()
+			defer resetCache()
+
+			missing, err := s.MissingLifecycleHookPermissions(group)
+			checkErr(tt.wantErr, err, g)
+			if tt.wantErr {
+				return
+			}
+			g.Expect(missing).To(Equal(tt.wantMissing))
+
+			// The result is cached, the policies are not simulated again.
+			missing, err = s.MissingLifecycleHookPermissions(group)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(missing).To(Equal(tt.wantMissing))
+		})
+	}
+
+	t.Run("should simulate the policies again once the cached result expired", func(t *testing.T) {
+		g := NewWithT(t)
+		iamMock := mock_iamauth.NewMockIAMAPI(mockCtrl)
+		stsMock := mock_stsiface.NewMockSTSAPI(mockCtrl)
+		callerIdentity(stsMock.EXPECT(), "arn:aws:iam::123456789012:user/capa")
+		iamMock.EXPECT().SimulatePrincipalPolicyWithContext(context.TODO(), gomock.Eq(simulateInput("arn:aws:iam::123456789012:user/capa"))).
+			Return(results(map[string]string{
+				"autoscaling:DeleteLifecycleHook":    allowed,
+				"autoscaling:DescribeLifecycleHooks": allowed,
+				"autoscaling:PutLifecycleHook":       allowed,
+			}), nil)
+		cs, err := getClusterScope(getFakeClient())
+		g.Expect(err).NotTo(HaveOccurred())
+		s := &Service{scope: cs, IAMClient: iamMock, STSClient: stsMock}
+
+		resetCache()
+		defer resetCache()
+		// The permissions were missing when they were last checked, and have been granted since.
+		lifecycleHookPermissions.Store("arn:aws:iam::123456789012:user/capa "+group.ID, lifecycleHookPermissionsEntry{
+			missing:   []string{"autoscaling:PutLifecycleHook"},
+			checkedAt: time.Now().Add(-LifecycleHookPermissionsCheckInterval),
+		})
+
+		missing, err := s.MissingLifecycleHookPermissions(group)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(missing).To(BeEmpty())
+	})
+
+	t.Run("should not share the result of a principal across accounts", func(t *testing.T) {
+		g := NewWithT(t)
+		iamMock := mock_iamauth.NewMockIAMAPI(mockCtrl)
+		stsMock := mock_stsiface.NewMockSTSAPI(mockCtrl)
+		callerIdentity(stsMock.EXPECT(), "arn:aws:iam::210987654321:user/capa")
+		iamMock.EXPECT().SimulatePrincipalPolicyWithContext(context.TODO(), gomock.Any()).
+			Return(results(map[string]string{
+				"autoscaling:DeleteLifecycleHook":    allowed,
+				"autoscaling:DescribeLifecycleHooks": allowed,
+				"autoscaling:PutLifecycleHook":       allowed,
+			}), nil)
+		cs, err := getClusterScope(getFakeClient())
+		g.Expect(err).NotTo(HaveOccurred())
+		s := &Service{scope: cs, IAMClient: iamMock, STSClient: stsMock}
+
+		resetCache()
+		defer resetCache()
+		// The same identity lacks the permissions in another account.
+		lifecycleHookPermissions.Store("arn:aws:iam::123456789012:user/capa "+group.ID, lifecycleHookPermissionsEntry{
+			missing:   []string{"autoscaling:PutLifecycleHook"},
+			checkedAt: time.Now(),
+		})
+
+		missing, err := s.MissingLifecycleHookPermissions(&expinfrav1.AutoScalingGroup{
+			ID: "arn:aws:autoscaling:us-east-1:210987654321:autoScalingGroup:uuid:autoScalingGroupName/asg",
+		})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(missing).To(BeEmpty())
+	})
+}
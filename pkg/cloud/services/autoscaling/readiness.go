@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asg
+
+import (
+	"time"
+
+	"k8s.io/utils/ptr"
+
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// DefaultInstancesReadyTimeout is how long an AWSMachinePool waits for the InService instances of its autoscaling
+// group when spec.instancesReadyTimeout isn't set.
+const DefaultInstancesReadyTimeout = 10 * time.Minute
+
+// InstancesReadyPollInterval is how often the instances of an autoscaling group are checked until the
+// AWSMachinePool is ready, as AWS doesn't notify the controller when they come InService.
+const InstancesReadyPollInterval = 30 * time.Second
+
+// ReconcileInstancesReady marks the AWSMachinePool ready once its autoscaling group has at least as many InService
+// instances as RequiredInServiceInstances. Until then, the ASGReadyCondition is False with the WaitingForInstances
+// reason, and once spec.instancesReadyTimeout expired, counted from the creation of the autoscaling group or of the
+// AWSMachinePool, whichever is later, with the InsufficientInstances reason and a Warning event. A ready
+// AWSMachinePool stays ready while its instances are replaced, e.g. by an instance refresh. It returns how long to
+// wait before checking the instances again, or zero once the AWSMachinePool is ready.
+func ReconcileInstancesReady(machinePoolScope *scope.MachinePoolScope, asg *expinfrav1.AutoScalingGroup, now time.Time) time.Duration {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+
+	inService := InServiceInstances(asg)
+	required := RequiredInServiceInstances(machinePoolScope, asg)
+	if awsMachinePool.Status.Ready || inService >= required {
+		awsMachinePool.Status.Ready = true
+		conditions.MarkTrue(awsMachinePool, expinfrav1.ASGReadyCondition)
+		return 0
+	}
+
+	timeout := DefaultInstancesReadyTimeout
+	if awsMachinePool.Spec.InstancesReadyTimeout != nil {
+		timeout = awsMachinePool.Spec.InstancesReadyTimeout.Duration
+	}
+	since := awsMachinePool.CreationTimestamp.Time
+	if asg.CreatedTime.After(since) {
+		since = asg.CreatedTime.Time
+	}
+	if remaining := since.Add(timeout).Sub(now); remaining > 0 {
+		conditions.MarkFalse(awsMachinePool, expinfrav1.ASGReadyCondition, expinfrav1.ASGWaitingForInstancesReason, clusterv1.ConditionSeverityInfo,
+			"%d of %d required instances are InService", inService, required)
+		return min(remaining, InstancesReadyPollInterval)
+	}
+
+	if conditions.GetReason(awsMachinePool, expinfrav1.ASGReadyCondition) != expinfrav1.ASGInsufficientInstancesReason {
+		machinePoolScope.Info("Auto Scaling group has insufficient InService instances", "in-service", inService, "required", required, "timeout", timeout)
+		record.Warnf(awsMachinePool, "InsufficientInstances", "AutoScalingGroup %q has %d of %d required instances InService after %s", machinePoolScope.ASGName(), inService, required, timeout)
+	}
+	conditions.MarkFalse(awsMachinePool, expinfrav1.ASGReadyCondition, expinfrav1.ASGInsufficientInstancesReason, clusterv1.ConditionSeverityWarning,
+		"%d of %d required instances are InService after %s", inService, required, timeout)
+	return InstancesReadyPollInterval
+}
+
+// InServiceInstances returns the number of instances of the autoscaling group which are InService.
+func InServiceInstances(asg *expinfrav1.AutoScalingGroup) int32 {
+	var inService int32
+	for _, instance := range asg.Instances {
+		if instance.State == expinfrav1.InstanceStateInService {
+			inService++
+		}
+	}
+	return inService
+}
+
+// RequiredInServiceInstances returns how many InService instances the autoscaling group needs for the AWSMachinePool
+// to be ready: the replicas of the MachinePool when CAPA manages the desired capacity of the group, or else its
+// minimum size. Zero, e.g. for a machine pool scaled from zero, is ready without instances. As the replicas and sizes
+// are capacity units when the instance types have a weighted capacity, a single InService instance is then enough.
+func RequiredInServiceInstances(machinePoolScope *scope.MachinePoolScope, asg *expinfrav1.AutoScalingGroup) int32 {
+	required := asg.MinSize
+	if _, _, desiredCapacity := machinePoolScope.ManagedSizes(); desiredCapacity {
+		required = ptr.Deref(machinePoolScope.MachinePool.Spec.Replicas, asg.MinSize)
+	}
+	if asg.MixedInstancesPolicy.UsesWeightedCapacity() {
+		required = min(required, 1)
+	}
+	return required
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asg
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileInstancesReady(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	instances := func(states ...infrav1.InstanceState) []infrav1.Instance {
+		var instances []infrav1.Instance
+		for _, state := range states {
+			instances = append(instances, infrav1.Instance{State: state})
+		}
+		return instances
+	}
+
+	tests := []struct {
+		name                  string
+		replicas              *int32
+		externallyManaged     bool
+		ready                 bool
+		instancesReadyTimeout *metav1.Duration
+		asg                   *expinfrav1.AutoScalingGroup
+		wantReady             bool
+		wantReason            string
+		wantSeverity          clusterv1.ConditionSeverity
+		wantMessage           string
+		wantRequeueAfter      time.Duration
+	}{
+		{
+			name:      "scaled from zero without instances",
+			replicas:  ptr.To[int32](0),
+			asg:       &expinfrav1.AutoScalingGroup{CreatedTime: metav1.NewTime(now)},
+			wantReady: true,
+		},
+		{
+			name:      "enough InService instances",
+			replicas:  ptr.To[int32](2),
+			asg:       &expinfrav1.AutoScalingGroup{CreatedTime: metav1.NewTime(now), Instances: instances(expinfrav1.InstanceStateInService, expinfrav1.InstanceStateInService)},
+			wantReady: true,
+		},
+		{
+			name:             "waiting for InService instances",
+			replicas:         ptr.To[int32](2),
+			asg:              &expinfrav1.AutoScalingGroup{CreatedTime: metav1.NewTime(now.Add(-time.Minute)), Instances: instances(expinfrav1.InstanceStateInService, "Pending")},
+			wantReason:       expinfrav1.ASGWaitingForInstancesReason,
+			wantSeverity:     clusterv1.ConditionSeverityInfo,
+			wantMessage:      "1 of 2 required instances are InService",
+			wantRequeueAfter: InstancesReadyPollInterval,
+		},
+		{
+			name:             "waiting for InService instances until the timeout",
+			replicas:         ptr.To[int32](1),
+			asg:              &expinfrav1.AutoScalingGroup{CreatedTime: metav1.NewTime(now.Add(-DefaultInstancesReadyTimeout + 10*time.Second))},
+			wantReason:       expinfrav1.ASGWaitingForInstancesReason,
+			wantSeverity:     clusterv1.ConditionSeverityInfo,
+			wantMessage:      "0 of 1 required instances are InService",
+			wantRequeueAfter: 10 * time.Second,
+		},
+		{
+			name:             "insufficient InService instances after the timeout",
+			replicas:         ptr.To[int32](2),
+			asg:              &expinfrav1.AutoScalingGroup{CreatedTime: metav1.NewTime(now.Add(-DefaultInstancesReadyTimeout)), Instances: instances(expinfrav1.InstanceStateInService)},
+			wantReason:       expinfrav1.ASGInsufficientInstancesReason,
+			wantSeverity:     clusterv1.ConditionSeverityWarning,
+			wantMessage:      "1 of 2 required instances are InService after 10m0s",
+			wantRequeueAfter: InstancesReadyPollInterval,
+		},
+		{
+			name:                  "insufficient InService instances after the timeout of the AWSMachinePool",
+			replicas:              ptr.To[int32](1),
+			instancesReadyTimeout: &metav1.Duration{Duration: time.Minute},
+			asg:                   &expinfrav1.AutoScalingGroup{CreatedTime: metav1.NewTime(now.Add(-2 * time.Minute))},
+			wantReason:            expinfrav1.ASGInsufficientInstancesReason,
+			wantSeverity:          clusterv1.ConditionSeverityWarning,
+			wantMessage:           "0 of 1 required instances are InService after 1m0s",
+			wantRequeueAfter:      InstancesReadyPollInterval,
+		},
+		{
+			name:              "scaled from zero by an external autoscaler",
+			replicas:          ptr.To[int32](3),
+			externallyManaged: true,
+			asg:               &expinfrav1.AutoScalingGroup{CreatedTime: metav1.NewTime(now)},
+			wantReady:         true,
+		},
+		{
+			name:              "waiting for the minimum size when the replicas are managed by an external autoscaler",
+			replicas:          ptr.To[int32](1),
+			externallyManaged: true,
+			asg:               &expinfrav1.AutoScalingGroup{CreatedTime: metav1.NewTime(now), MinSize: 2, Instances: instances(expinfrav1.InstanceStateInService)},
+			wantReason:        expinfrav1.ASGWaitingForInstancesReason,
+			wantSeverity:      clusterv1.ConditionSeverityInfo,
+			wantMessage:       "1 of 2 required instances are InService",
+			wantRequeueAfter:  InstancesReadyPollInterval,
+		},
+		{
+			name:     "a single InService instance is enough with weighted capacity",
+			replicas: ptr.To[int32](4),
+			asg: &expinfrav1.AutoScalingGroup{
+				CreatedTime: metav1.NewTime(now),
+				Instances:   instances(expinfrav1.InstanceStateInService),
+				MixedInstancesPolicy: &expinfrav1.MixedInstancesPolicy{
+					Overrides: []expinfrav1.Overrides{{InstanceType: "m5.xlarge", WeightedCapacity: "4"}},
+				},
+			},
+			wantReady: true,
+		},
+		{
+			name:      "a ready pool stays ready while its instances are replaced",
+			replicas:  ptr.To[int32](2),
+			ready:     true,
+			asg:       &expinfrav1.AutoScalingGroup{CreatedTime: metav1.NewTime(now.Add(-time.Hour)), Instances: instances(expinfrav1.InstanceStateInService, "Pending")},
+			wantReady: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			client := getFakeClient()
+			clusterScope, err := getClusterScope(client)
+			g.Expect(err).NotTo(HaveOccurred())
+			machinePoolScope, err := getMachinePoolScope(client, clusterScope)
+			g.Expect(err).NotTo(HaveOccurred())
+			machinePoolScope.MachinePool.Spec.Replicas = tt.replicas
+			if tt.externallyManaged {
+				machinePoolScope.MachinePool.Annotations = map[string]string{clusterv1.ReplicasManagedByAnnotation: ""}
+			}
+			machinePoolScope.AWSMachinePool.Spec.InstancesReadyTimeout = tt.instancesReadyTimeout
+			machinePoolScope.AWSMachinePool.Status.Ready = tt.ready
+
+			requeueAfter := ReconcileInstancesReady(machinePoolScope, tt.asg, now)
+
+			g.Expect(requeueAfter).To(Equal(tt.wantRequeueAfter))
+			g.Expect(machinePoolScope.AWSMachinePool.Status.Ready).To(Equal(tt.wantReady))
+			condition := conditions.Get(machinePoolScope.AWSMachinePool, expinfrav1.ASGReadyCondition)
+			g.Expect(condition).NotTo(BeNil())
+			if tt.wantReady {
+				g.Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+				return
+			}
+			g.Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+			g.Expect(condition.Reason).To(Equal(tt.wantReason))
+			g.Expect(condition.Severity).To(Equal(tt.wantSeverity))
+			g.Expect(condition.Message).To(Equal(tt.wantMessage))
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asg
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// ScalingActivityFailureWindow bounds the scaling activities whose failure is reported: older failures are
+// considered resolved, as the autoscaling group keeps retrying to launch instances while it is short of capacity.
+const ScalingActivityFailureWindow = 10 * time.Minute
+
+// ReconcileScalingActivities sets the ScalingActivitiesSucceededCondition of the AWSMachinePool from the scaling
+// activities of its autoscaling group, sorted from the most recent one as returned by DescribeScalingActivities.
+// The condition is False with the status message of the most recent completed activity when it failed within
+// ScalingActivityFailureWindow, and a Warning event is emitted whenever this failure changes.
+func ReconcileScalingActivities(machinePoolScope *scope.MachinePoolScope, activities []*expinfrav1.ScalingActivity, now time.Time) {
+	awsMachinePool := machinePoolScope.AWSMachinePool
+
+	failed := latestFailedScalingActivity(activities, now.Add(-ScalingActivityFailureWindow))
+	if failed == nil {
+		conditions.MarkTrue(awsMachinePool, expinfrav1.ScalingActivitiesSucceededCondition)
+		return
+	}
+
+	message := failed.StatusMessage
+	if message == "" {
+		message = failed.Description
+	}
+	previous := conditions.Get(awsMachinePool, expinfrav1.ScalingActivitiesSucceededCondition)
+	if previous == nil || previous.Status != corev1.ConditionFalse || previous.Message != message {
+		machinePoolScope.Info("Scaling activity failed", "activity-id", failed.ActivityID, "message", message)
+		record.Warnf(awsMachinePool, "FailedScalingActivity", "Scaling activity %s of AutoScalingGroup %q failed: %s", failed.ActivityID, machinePoolScope.ASGName(), message)
+	}
+	conditions.MarkFalse(awsMachinePool, expinfrav1.ScalingActivitiesSucceededCondition, expinfrav1.ASGProvisionFailedReason, clusterv1.ConditionSeverityWarning, "%s", message)
+}
+
+// latestFailedScalingActivity returns the most recent completed scaling activity if it failed after the given time.
+// The activities which are still in progress are skipped, so that a failure is reported until an activity succeeds.
+func latestFailedScalingActivity(activities []*expinfrav1.ScalingActivity, since time.Time) *expinfrav1.ScalingActivity {
+	for _, activity := range activities {
+		switch activity.StatusCode {
+		case expinfrav1.ScalingActivityStatusSuccessful:
+			return nil
+		case expinfrav1.ScalingActivityStatusFailed:
+			if activity.StartTime == nil || activity.StartTime.Time.Before(since) {
+				return nil
+			}
+			return activity
+		}
+	}
+	return nil
+}
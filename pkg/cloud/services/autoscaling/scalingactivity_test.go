@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asg
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileScalingActivities(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	activity := func(id, statusCode, statusMessage string, age time.Duration) *expinfrav1.ScalingActivity {
+		return &expinfrav1.ScalingActivity{
+			ActivityID:    id,
+			Description:   "Launching a new EC2 instance",
+			StatusCode:    statusCode,
+			StatusMessage: statusMessage,
+			StartTime:     &metav1.Time{Time: now.Add(-age)},
+		}
+	}
+	insufficientCapacity := "We currently do not have sufficient m5.large capacity in the Availability Zone you requested. InsufficientInstanceCapacity"
+
+	tests := []struct {
+		name        string
+		activities  []*expinfrav1.ScalingActivity
+		wantStatus  corev1.ConditionStatus
+		wantMessage string
+	}{
+		{
+			name:       "no scaling activities",
+			wantStatus: corev1.ConditionTrue,
+		},
+		{
+			name: "latest scaling activity succeeded",
+			activities: []*expinfrav1.ScalingActivity{
+				activity("activity-2", expinfrav1.ScalingActivityStatusSuccessful, "", time.Minute),
+				activity("activity-1", expinfrav1.ScalingActivityStatusFailed, insufficientCapacity, 2*time.Minute),
+			},
+			wantStatus: corev1.ConditionTrue,
+		},
+		{
+			name: "latest completed scaling activity failed",
+			activities: []*expinfrav1.ScalingActivity{
+				activity("activity-3", "InProgress", "", 0),
+				activity("activity-2", expinfrav1.ScalingActivityStatusFailed, insufficientCapacity, time.Minute),
+				activity("activity-1", expinfrav1.ScalingActivityStatusSuccessful, "", 2*time.Minute),
+			},
+			wantStatus:  corev1.ConditionFalse,
+			wantMessage: insufficientCapacity,
+		},
+		{
+			name: "failed scaling activity without a status message",
+			activities: []*expinfrav1.ScalingActivity{
+				activity("activity-1", expinfrav1.ScalingActivityStatusFailed, "", time.Minute),
+			},
+			wantStatus:  corev1.ConditionFalse,
+			wantMessage: "Launching a new EC2 instance",
+		},
+		{
+			name: "scaling activity failed before the failure window",
+			activities: []*expinfrav1.ScalingActivity{
+				activity("activity-1", expinfrav1.ScalingActivityStatusFailed, insufficientCapacity, ScalingActivityFailureWindow+time.Minute),
+			},
+			wantStatus: corev1.ConditionTrue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			client := getFakeClient()
+			clusterScope, err := getClusterScope(client)
+			g.Expect(err).NotTo(HaveOccurred())
+			machinePoolScope, err := getMachinePoolScope(client, clusterScope)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			ReconcileScalingActivities(machinePoolScope, tt.activities, now)
+
+			condition := conditions.Get(machinePoolScope.AWSMachinePool, expinfrav1.ScalingActivitiesSucceededCondition)
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tt.wantStatus))
+			g.Expect(condition.Message).To(Equal(tt.wantMessage))
+			if tt.wantStatus == corev1.ConditionFalse {
+				g.Expect(condition.Reason).To(Equal(expinfrav1.ASGProvisionFailedReason))
+			}
+		})
+	}
+}
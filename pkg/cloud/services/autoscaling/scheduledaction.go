@@ -0,0 +1,210 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asg
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+)
+
+// DescribeScheduledActions returns the scheduled actions of the given AutoScalingGroup.
+func (s *Service) DescribeScheduledActions(asgName string) ([]*expinfrav1.ScheduledAction, error) {
+	s.scope.Debug("Describing scheduled actions", "asgName", asgName)
+
+	input := &autoscaling.DescribeScheduledActionsInput{
+		AutoScalingGroupName: aws.String(asgName),
+	}
+
+	var actions []*expinfrav1.ScheduledAction
+	err := s.ASGClient.DescribeScheduledActionsPagesWithContext(context.TODO(), input, func(out *autoscaling.DescribeScheduledActionsOutput, _ bool) bool {
+		for _, action := range out.ScheduledUpdateGroupActions {
+			actions = append(actions, SDKToScheduledAction(action))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe scheduled actions for AutoScalingGroup: %q", asgName)
+	}
+
+	return actions, nil
+}
+
+func getPutScheduledUpdateGroupActionInput(asgName string, action *expinfrav1.ScheduledAction) *autoscaling.PutScheduledUpdateGroupActionInput {
+	input := &autoscaling.PutScheduledUpdateGroupActionInput{
+		AutoScalingGroupName: aws.String(asgName),
+		ScheduledActionName:  aws.String(action.Name),
+	}
+
+	// Optional parameters
+	if action.Recurrence != "" {
+		input.Recurrence = aws.String(action.Recurrence)
+	}
+	if action.TimeZone != "" {
+		input.TimeZone = aws.String(action.TimeZone)
+	}
+	// AWS rejects start times in the past, which a recurring action that already started has.
+	if action.StartTime != nil && action.StartTime.After(time.Now()) {
+		input.StartTime = aws.Time(action.StartTime.Time)
+	}
+	if action.EndTime != nil {
+		input.EndTime = aws.Time(action.EndTime.Time)
+	}
+	if action.MinSize != nil {
+		input.MinSize = aws.Int64(int64(*action.MinSize))
+	}
+	if action.MaxSize != nil {
+		input.MaxSize = aws.Int64(int64(*action.MaxSize))
+	}
+	if action.DesiredCapacity != nil {
+		input.DesiredCapacity = aws.Int64(int64(*action.DesiredCapacity))
+	}
+
+	return input
+}
+
+// CreateScheduledAction creates a scheduled action for the given AutoScalingGroup.
+func (s *Service) CreateScheduledAction(asgName string, action *expinfrav1.ScheduledAction) error {
+	input := getPutScheduledUpdateGroupActionInput(asgName, action)
+
+	if _, err := s.ASGClient.PutScheduledUpdateGroupActionWithContext(context.TODO(), input); err != nil {
+		return errors.Wrapf(err, "failed to create scheduled action %q for AutoScalingGroup: %q", action.Name, asgName)
+	}
+	s.scope.Debug("Created scheduled action", "asgName", asgName, "scheduledActionName", action.Name)
+
+	return nil
+}
+
+// UpdateScheduledAction updates a scheduled action of the given AutoScalingGroup to the expected one.
+// PutScheduledUpdateGroupAction leaves the fields missing from its input unchanged, so an action on which
+// fields must be unset is deleted and created again.
+func (s *Service) UpdateScheduledAction(asgName string, existing, expected *expinfrav1.ScheduledAction) error {
+	if scheduledActionUnsetsFields(existing, expected) {
+		if err := s.DeleteScheduledAction(asgName, existing.Name); err != nil {
+			return err
+		}
+		return s.CreateScheduledAction(asgName, expected)
+	}
+
+	input := getPutScheduledUpdateGroupActionInput(asgName, expected)
+
+	if _, err := s.ASGClient.PutScheduledUpdateGroupActionWithContext(context.TODO(), input); err != nil {
+		return errors.Wrapf(err, "failed to update scheduled action %q for AutoScalingGroup: %q", expected.Name, asgName)
+	}
+	s.scope.Debug("Updated scheduled action", "asgName", asgName, "scheduledActionName", expected.Name)
+
+	return nil
+}
+
+// DeleteScheduledAction deletes a scheduled action of the given AutoScalingGroup.
+func (s *Service) DeleteScheduledAction(asgName, name string) error {
+	input := &autoscaling.DeleteScheduledActionInput{
+		AutoScalingGroupName: aws.String(asgName),
+		ScheduledActionName:  aws.String(name),
+	}
+
+	if _, err := s.ASGClient.DeleteScheduledActionWithContext(context.TODO(), input); err != nil {
+		return errors.Wrapf(err, "failed to delete scheduled action %q for AutoScalingGroup: %q", name, asgName)
+	}
+	s.scope.Debug("Deleted scheduled action", "asgName", asgName, "scheduledActionName", name)
+
+	return nil
+}
+
+// ScheduledActionNeedsUpdate checks if a scheduled action differs from the expected one. The start time is
+// only compared while the expected one is in the future, as AWS reports the next run of a recurring action
+// as its start time.
+func (s *Service) ScheduledActionNeedsUpdate(existing, expected *expinfrav1.ScheduledAction) bool {
+	if existing.Recurrence != expected.Recurrence ||
+		!isSameTimeZone(existing.TimeZone, expected.TimeZone) ||
+		!isSameTime(existing.EndTime, expected.EndTime) ||
+		!isSameSize(existing.MinSize, expected.MinSize) ||
+		!isSameSize(existing.MaxSize, expected.MaxSize) ||
+		!isSameSize(existing.DesiredCapacity, expected.DesiredCapacity) {
+		return true
+	}
+
+	return expected.StartTime != nil && expected.StartTime.After(time.Now()) && !isSameTime(existing.StartTime, expected.StartTime)
+}
+
+// SDKToScheduledAction converts an AWS SDK ScheduledUpdateGroupAction to the CAPA scheduled action type.
+func SDKToScheduledAction(action *autoscaling.ScheduledUpdateGroupAction) *expinfrav1.ScheduledAction {
+	res := &expinfrav1.ScheduledAction{
+		Name:       aws.StringValue(action.ScheduledActionName),
+		Recurrence: aws.StringValue(action.Recurrence),
+		TimeZone:   aws.StringValue(action.TimeZone),
+	}
+	if action.StartTime != nil {
+		res.StartTime = &metav1.Time{Time: *action.StartTime}
+	}
+	if action.EndTime != nil {
+		res.EndTime = &metav1.Time{Time: *action.EndTime}
+	}
+	if action.MinSize != nil {
+		res.MinSize = aws.Int32(int32(*action.MinSize))
+	}
+	if action.MaxSize != nil {
+		res.MaxSize = aws.Int32(int32(*action.MaxSize))
+	}
+	if action.DesiredCapacity != nil {
+		res.DesiredCapacity = aws.Int32(int32(*action.DesiredCapacity))
+	}
+	return res
+}
+
+// scheduledActionUnsetsFields returns whether the expected scheduled action unsets fields of the existing one.
+func scheduledActionUnsetsFields(existing, expected *expinfrav1.ScheduledAction) bool {
+	return (existing.Recurrence != "" && expected.Recurrence == "") ||
+		(!isSameTimeZone(existing.TimeZone, "") && expected.TimeZone == "") ||
+		(existing.EndTime != nil && expected.EndTime == nil) ||
+		(existing.MinSize != nil && expected.MinSize == nil) ||
+		(existing.MaxSize != nil && expected.MaxSize == nil) ||
+		(existing.DesiredCapacity != nil && expected.DesiredCapacity == nil)
+}
+
+// isSameTimeZone returns whether two time zones of scheduled actions are the same, an empty one being UTC.
+func isSameTimeZone(a, b string) bool {
+	utc := func(tz string) string {
+		if tz == "" || tz == "Etc/UTC" {
+			return "UTC"
+		}
+		return tz
+	}
+	return utc(a) == utc(b)
+}
+
+// isSameTime returns whether two times are the same to the second, which is the precision of the times of
+// scheduled actions.
+func isSameTime(a, b *metav1.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Truncate(time.Second).Equal(b.Truncate(time.Second))
+}
+
+func isSameSize(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
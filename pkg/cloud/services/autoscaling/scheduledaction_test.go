@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/fakeaws"
+)
+
+func TestServiceScheduledActions(t *testing.T) {
+	g := NewWithT(t)
+	asgFake := fakeaws.NewAutoScalingAPI()
+	asgFake.AddAutoScalingGroup(&autoscaling.Group{AutoScalingGroupName: aws.String("asg")})
+	cs, err := getClusterScope(getFakeClient())
+	g.Expect(err).NotTo(HaveOccurred())
+	s := &Service{scope: cs, ASGClient: asgFake}
+
+	endTime := metav1.NewTime(time.Now().Add(24 * time.Hour).Truncate(time.Second))
+	action := &expinfrav1.ScheduledAction{
+		Name:            "scale-up",
+		Recurrence:      "0 8 * * 1-5",
+		TimeZone:        "Europe/Paris",
+		EndTime:         &endTime,
+		MinSize:         aws.Int32(2),
+		MaxSize:         aws.Int32(10),
+		DesiredCapacity: aws.Int32(5),
+	}
+	g.Expect(s.CreateScheduledAction("asg", action)).To(Succeed())
+
+	// The action read back from the ASG must not be seen as drifted, otherwise it would be updated on every reconcile.
+	actions, err := s.DescribeScheduledActions("asg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(actions).To(HaveLen(1))
+	g.Expect(s.ScheduledActionNeedsUpdate(actions[0], action)).To(BeFalse())
+
+	// A start time in the past, which recurring actions that already started have, is neither sent nor compared.
+	started := action.DeepCopy()
+	started.StartTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	g.Expect(s.ScheduledActionNeedsUpdate(actions[0], started)).To(BeFalse())
+
+	// Unsetting a field recreates the action, as PutScheduledUpdateGroupAction leaves missing fields unchanged.
+	expected := action.DeepCopy()
+	expected.DesiredCapacity = nil
+	g.Expect(s.ScheduledActionNeedsUpdate(actions[0], expected)).To(BeTrue())
+	g.Expect(s.UpdateScheduledAction("asg", actions[0], expected)).To(Succeed())
+	actions, err = s.DescribeScheduledActions("asg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(actions).To(HaveLen(1))
+	g.Expect(actions[0].DesiredCapacity).To(BeNil())
+	g.Expect(s.ScheduledActionNeedsUpdate(actions[0], expected)).To(BeFalse())
+
+	// Changing a field updates the action in place.
+	expected.MaxSize = aws.Int32(20)
+	g.Expect(s.UpdateScheduledAction("asg", actions[0], expected)).To(Succeed())
+	actions, err = s.DescribeScheduledActions("asg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(actions[0].MaxSize).To(Equal(aws.Int32(20)))
+
+	g.Expect(s.DeleteScheduledAction("asg", "scale-up")).To(Succeed())
+	actions, err = s.DescribeScheduledActions("asg")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(actions).To(BeEmpty())
+	g.Expect(asgFake.Calls()).To(Equal([]string{
+		"PutScheduledUpdateGroupAction", "DescribeScheduledActions",
+		"DeleteScheduledAction", "PutScheduledUpdateGroupAction", "DescribeScheduledActions",
+		"PutScheduledUpdateGroupAction", "DescribeScheduledActions",
+		"DeleteScheduledAction", "DescribeScheduledActions",
+	}))
+}
@@ -19,6 +19,8 @@ package asg
 import (
 	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
@@ -31,6 +33,8 @@ type Service struct {
 	scope     cloud.ClusterScoper
 	ASGClient autoscalingiface.AutoScalingAPI
 	EC2Client ec2iface.EC2API
+	IAMClient iamiface.IAMAPI
+	STSClient stsiface.STSAPI
 }
 
 // NewService returns a new service given the asg api client.
@@ -39,5 +43,7 @@ func NewService(clusterScope cloud.ClusterScoper) *Service {
 		scope:     clusterScope,
 		ASGClient: scope.NewASGClient(clusterScope, clusterScope, clusterScope, clusterScope.InfraCluster()),
 		EC2Client: scope.NewEC2Client(clusterScope, clusterScope, clusterScope, clusterScope.InfraCluster()),
+		IAMClient: scope.NewIAMClient(clusterScope, clusterScope, clusterScope, clusterScope.InfraCluster()),
+		STSClient: scope.NewSTSClient(clusterScope, clusterScope, clusterScope, clusterScope.InfraCluster()),
 	}
 }
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asg
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	ec2service "sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/ec2"
+)
+
+// desiredTags returns the tags of the Auto Scaling group of the machine pool, apart from the tags for the
+// auto-discovery of the cluster autoscaler, and the keys of the ones that are propagated at launch.
+func (s *Service) desiredTags(machinePoolScope *scope.MachinePoolScope) (infrav1.Tags, []string) {
+	// Make sure to use the MachinePoolScope here to get the merger of AWSCluster and AWSMachinePool tags
+	additionalTags := machinePoolScope.AdditionalTags()
+
+	var propagated []string
+	if machinePoolScope.AWSMachinePool.Spec.PropagateTagsAtLaunch {
+		propagated = sets.List(sets.KeySet(additionalTags))
+	}
+
+	// Set the cloud provider tag
+	additionalTags[infrav1.ClusterAWSCloudProviderTagKey(s.scope.KubernetesClusterName())] = string(infrav1.ResourceLifecycleOwned)
+	// Record the AWSMachinePool managing the group, so that other AWSMachinePools don't take it over.
+	additionalTags[infrav1.MachinePoolTagKey] = machinePoolScope.NamespacedName()
+
+	return infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.scope.KubernetesClusterName(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(machinePoolScope.ASGName()),
+		Role:        aws.String("node"),
+		Additional:  additionalTags,
+	}), propagated
+}
+
+// ReconcileASGTags reconciles the tags of the Auto Scaling group with the live tags returned by AWS. The
+// missing and changed tags, including the ones whose propagation at launch changed, are created or updated.
+// Only the additional tags CAPA applied before, as recorded in the last applied tags annotation of the
+// AWSMachinePool, are deleted once they are removed from the spec: the tags set by users or other
+// controllers, and the kubernetes.io/cluster/<name> tags, are left alone.
+func (s *Service) ReconcileASGTags(machinePoolScope *scope.MachinePoolScope, asg *expinfrav1.AutoScalingGroup) error {
+	desired, propagated := s.desiredTags(machinePoolScope)
+
+	lastApplied, err := ec2service.MachinePoolAnnotationJSON(machinePoolScope, ec2service.TagsLastAppliedAnnotation)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the last applied tags")
+	}
+
+	var keep infrav1.Tags
+	if machinePoolScope.AWSMachinePool.Spec.Autoscaling.IsEnabled() {
+		keep = infrav1.ClusterAutoscalerTags(s.scope.KubernetesClusterName())
+	}
+
+	create, remove := asgTagUpdates(asg, desired, propagated, lastApplied, keep)
+	if len(create) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	s.scope.Info("Updating tags of the Auto Scaling group", "name", asg.Name, "create", create, "remove", remove)
+	if len(create) > 0 {
+		input := &autoscaling.CreateOrUpdateTagsInput{
+			Tags: propagateTagsAtLaunch(mapToTags(create, aws.String(asg.Name)), propagated),
+		}
+		if _, err := s.ASGClient.CreateOrUpdateTagsWithContext(context.TODO(), input); err != nil {
+			return errors.Wrapf(err, "failed to update tags on AutoScalingGroup %q", asg.Name)
+		}
+	}
+
+	if len(remove) > 0 {
+		input := &autoscaling.DeleteTagsInput{
+			Tags: mapToTags(remove, aws.String(asg.Name)),
+		}
+		if _, err := s.ASGClient.DeleteTagsWithContext(context.TODO(), input); err != nil {
+			return errors.Wrapf(err, "failed to delete tags on AutoScalingGroup %q", asg.Name)
+		}
+	}
+
+	return nil
+}
+
+// asgTagUpdates returns the tags to create or update, and the ones to delete, to reconcile the live tags of
+// the Auto Scaling group with the desired ones. Only the stale tags whose keys were last applied by CAPA are
+// deleted, apart from the kubernetes.io/cluster/<name> tags and the ones to keep.
+func asgTagUpdates(asg *expinfrav1.AutoScalingGroup, desired infrav1.Tags, propagated []string, lastApplied map[string]interface{}, keep infrav1.Tags) (create, remove infrav1.Tags) {
+	create, remove = infrav1.Tags{}, infrav1.Tags{}
+
+	livePropagated := sets.New(asg.PropagatedTags...)
+	desiredPropagated := sets.New(propagated...)
+	for key, value := range desired {
+		current, ok := asg.Tags[key]
+		if !ok || current != value || livePropagated.Has(key) != desiredPropagated.Has(key) {
+			create[key] = value
+		}
+	}
+
+	for key := range lastApplied {
+		current, ok := asg.Tags[key]
+		if !ok || strings.HasPrefix(key, infrav1.NameKubernetesAWSCloudProviderPrefix) {
+			continue
+		}
+		if _, isDesired := desired[key]; isDesired {
+			continue
+		}
+		if _, isKept := keep[key]; isKept {
+			continue
+		}
+		remove[key] = current
+	}
+
+	return create, remove
+}
+
+// propagateTagsAtLaunch sets PropagateAtLaunch on the tags with the given keys.
+func propagateTagsAtLaunch(tags []*autoscaling.Tag, keys []string) []*autoscaling.Tag {
+	propagated := sets.New(keys...)
+	for _, tag := range tags {
+		tag.PropagateAtLaunch = aws.Bool(propagated.Has(aws.StringValue(tag.Key)))
+	}
+	return tags
+}
+
+// propagatedTagKeys returns the sorted keys of the tags of an Auto Scaling group that are propagated at launch.
+func propagatedTagKeys(tags []*autoscaling.TagDescription) []string {
+	var keys []string
+	for _, tag := range tags {
+		if aws.BoolValue(tag.PropagateAtLaunch) {
+			keys = append(keys, aws.StringValue(tag.Key))
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
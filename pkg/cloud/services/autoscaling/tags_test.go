@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/autoscaling/mock_autoscalingiface"
+	ec2service "sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/ec2"
+)
+
+func TestASGTagUpdates(t *testing.T) {
+	desired := infrav1.Tags{"owned": "yes", "changed": "new", "added": "1"}
+
+	tests := []struct {
+		name        string
+		asg         *expinfrav1.AutoScalingGroup
+		propagated  []string
+		lastApplied map[string]interface{}
+		keep        infrav1.Tags
+		wantCreate  infrav1.Tags
+		wantRemove  infrav1.Tags
+	}{
+		{
+			name: "creates missing and changed tags",
+			asg: &expinfrav1.AutoScalingGroup{
+				Tags: infrav1.Tags{"owned": "yes", "changed": "old"},
+			},
+			wantCreate: infrav1.Tags{"changed": "new", "added": "1"},
+			wantRemove: infrav1.Tags{},
+		},
+		{
+			name: "updates the tags whose propagation at launch changed",
+			asg: &expinfrav1.AutoScalingGroup{
+				Tags:           infrav1.Tags{"owned": "yes", "changed": "new", "added": "1"},
+				PropagatedTags: []string{"owned"},
+			},
+			propagated: []string{"added"},
+			wantCreate: infrav1.Tags{"owned": "yes", "added": "1"},
+			wantRemove: infrav1.Tags{},
+		},
+		{
+			name: "only deletes the stale tags applied by CAPA",
+			asg: &expinfrav1.AutoScalingGroup{
+				Tags: infrav1.Tags{
+					"owned":                        "yes",
+					"changed":                      "new",
+					"added":                        "1",
+					"stale":                        "x",
+					"user":                         "y",
+					"kubernetes.io/cluster/other":  "shared",
+					"k8s.io/cluster-autoscaler/ok": "true",
+				},
+			},
+			lastApplied: map[string]interface{}{
+				"stale":                        "x",
+				"changed":                      "old",
+				"removed-out-of-band":          "z",
+				"kubernetes.io/cluster/other":  "shared",
+				"k8s.io/cluster-autoscaler/ok": "true",
+			},
+			keep:       infrav1.Tags{"k8s.io/cluster-autoscaler/ok": "true"},
+			wantCreate: infrav1.Tags{},
+			wantRemove: infrav1.Tags{"stale": "x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			create, remove := asgTagUpdates(tt.asg, desired, tt.propagated, tt.lastApplied, tt.keep)
+			g.Expect(create).To(Equal(tt.wantCreate))
+			g.Expect(remove).To(Equal(tt.wantRemove))
+		})
+	}
+}
+
+func TestServiceReconcileASGTags(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	fakeClient := getFakeClient()
+	clusterScope, err := getClusterScope(fakeClient)
+	g.Expect(err).NotTo(HaveOccurred())
+	mps, err := getMachinePoolScope(fakeClient, clusterScope)
+	g.Expect(err).NotTo(HaveOccurred())
+	mps.AWSMachinePool.Name = "pool"
+	mps.AWSMachinePool.Spec.AdditionalTags = infrav1.Tags{"team": "blue"}
+	mps.AWSMachinePool.Spec.PropagateTagsAtLaunch = true
+	g.Expect(ec2service.UpdateMachinePoolAnnotationJSON(mps, ec2service.TagsLastAppliedAnnotation, map[string]interface{}{"team": "red", "stale": "x"})).To(Succeed())
+
+	asg := &expinfrav1.AutoScalingGroup{
+		Name: "pool",
+		Tags: infrav1.Tags{
+			infrav1.ClusterTagKey("test"):                 string(infrav1.ResourceLifecycleOwned),
+			infrav1.ClusterAWSCloudProviderTagKey("test"): string(infrav1.ResourceLifecycleOwned),
+			infrav1.NameAWSClusterAPIRole:                 "node",
+			infrav1.MachinePoolTagKey:                     mps.NamespacedName(),
+			"Name":                                        "pool",
+			"team":                                        "red",
+			"stale":                                       "x",
+			"user":                                        "y",
+		},
+	}
+
+	asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+	asgMock.EXPECT().CreateOrUpdateTagsWithContext(context.TODO(), gomock.Eq(&autoscaling.CreateOrUpdateTagsInput{
+		Tags: []*autoscaling.Tag{{
+			Key:               aws.String("team"),
+			PropagateAtLaunch: aws.Bool(true),
+			ResourceId:        aws.String("pool"),
+			ResourceType:      aws.String("auto-scaling-group"),
+			Value:             aws.String("blue"),
+		}},
+	})).Return(nil, nil)
+	asgMock.EXPECT().DeleteTagsWithContext(context.TODO(), gomock.Eq(&autoscaling.DeleteTagsInput{
+		Tags: mapToTags(map[string]string{"stale": "x"}, aws.String("pool")),
+	})).Return(nil, nil)
+
+	s := NewService(clusterScope)
+	s.ASGClient = asgMock
+	g.Expect(s.ReconcileASGTags(mps, asg)).To(Succeed())
+
+	// Nothing is updated once the tags are in sync.
+	delete(asg.Tags, "stale")
+	asg.Tags["team"] = "blue"
+	asg.PropagatedTags = []string{"team"}
+	g.Expect(s.ReconcileASGTags(mps, asg)).To(Succeed())
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/pkg/errors"
+)
+
+// ResourceType is the type of an AWS resource, in the service:resource-type format of the Resource Groups
+// Tagging API.
+type ResourceType string
+
+const (
+	// ResourceTypeInstance is the type of EC2 instances.
+	ResourceTypeInstance = ResourceType("ec2:instance")
+	// ResourceTypeSecurityGroup is the type of security groups.
+	ResourceTypeSecurityGroup = ResourceType("ec2:security-group")
+	// ResourceTypeLaunchTemplate is the type of launch templates.
+	ResourceTypeLaunchTemplate = ResourceType("ec2:launch-template")
+	// ResourceTypeVPC is the type of VPCs.
+	ResourceTypeVPC = ResourceType("ec2:vpc")
+	// ResourceTypeSubnet is the type of subnets.
+	ResourceTypeSubnet = ResourceType("ec2:subnet")
+	// ResourceTypeLoadBalancer is the type of application, network and gateway load balancers.
+	ResourceTypeLoadBalancer = ResourceType("elasticloadbalancing:loadbalancer")
+	// ResourceTypeClassicLoadBalancer is the type of classic load balancers. The Resource Groups Tagging API
+	// reports them with the type of the other load balancers, and doesn't cover all of them.
+	ResourceTypeClassicLoadBalancer = ResourceType("elasticloadbalancing:loadbalancer/classic")
+	// ResourceTypeTargetGroup is the type of target groups.
+	ResourceTypeTargetGroup = ResourceType("elasticloadbalancing:targetgroup")
+	// ResourceTypeAutoScalingGroup is the type of Auto Scaling groups.
+	ResourceTypeAutoScalingGroup = ResourceType("autoscaling:autoScalingGroup")
+	// ResourceTypeEKSCluster is the type of EKS clusters.
+	ResourceTypeEKSCluster = ResourceType("eks:cluster")
+	// ResourceTypeEKSNodegroup is the type of EKS managed node groups.
+	ResourceTypeEKSNodegroup = ResourceType("eks:nodegroup")
+	// ResourceTypeEKSFargateProfile is the type of EKS Fargate profiles.
+	ResourceTypeEKSFargateProfile = ResourceType("eks:fargateprofile")
+	// ResourceTypeEKSAddon is the type of EKS addons.
+	ResourceTypeEKSAddon = ResourceType("eks:addon")
+)
+
+// filter returns the type of the Resource Groups Tagging API filter which matches the resources of the type.
+func (t ResourceType) filter() string {
+	if t == ResourceTypeClassicLoadBalancer {
+		return string(ResourceTypeLoadBalancer)
+	}
+	return string(t)
+}
+
+// Resource is an AWS resource found by a Discoverer.
+type Resource struct {
+	// ARN is the ARN of the resource.
+	ARN string
+
+	// Type is the type of the resource.
+	Type ResourceType
+
+	// ID is the identifier the API of the service uses for the resource: the ID of EC2 resources, the name
+	// of classic load balancers, Auto Scaling groups and EKS resources, and the ARN otherwise.
+	ID string
+
+	// Name is the name of the resource, if its ARN has one.
+	Name string
+
+	// Parent is the name of the EKS cluster of EKS node groups, Fargate profiles and addons.
+	Parent string
+
+	// Tags are the tags of the resource.
+	Tags map[string]string
+}
+
+// ParseARN returns the resource of an ARN. The ARNs of services which aren't known are returned with
+// the ARN as ID.
+func ParseARN(resourceARN string) (*Resource, error) {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse ARN %q", resourceARN)
+	}
+
+	res := &Resource{ARN: resourceARN, ID: resourceARN}
+	switch parsed.Service {
+	case "ec2":
+		// The resource of EC2 ARNs is <type>/<id>.
+		typ, id, found := strings.Cut(parsed.Resource, "/")
+		if !found || id == "" {
+			return nil, errors.Errorf("unexpected resource %q of EC2 ARN %q", parsed.Resource, resourceARN)
+		}
+		res.Type = ResourceType("ec2:" + typ)
+		res.ID = id
+	case "elasticloadbalancing":
+		// The resource of classic load balancer ARNs is loadbalancer/<name>, and the one of the other load
+		// balancers is loadbalancer/<app|net|gwy>/<name>/<id>.
+		parts := strings.Split(parsed.Resource, "/")
+		switch {
+		case len(parts) < 2:
+			return nil, errors.Errorf("unexpected resource %q of Elastic Load Balancing ARN %q", parsed.Resource, resourceARN)
+		case parts[0] == "loadbalancer" && len(parts) == 2:
+			res.Type = ResourceTypeClassicLoadBalancer
+			res.ID = parts[1]
+			res.Name = parts[1]
+		case parts[0] == "loadbalancer":
+			res.Type = ResourceTypeLoadBalancer
+			res.Name = parts[2]
+		case parts[0] == "targetgroup":
+			res.Type = ResourceTypeTargetGroup
+			res.Name = parts[1]
+		default:
+			// The other resources, e.g. listeners, are identified by their ARN.
+			res.Type = ResourceType("elasticloadbalancing:" + parts[0])
+		}
+	case "autoscaling":
+		// The resource of Auto Scaling group ARNs is autoScalingGroup:<uuid>:autoScalingGroupName/<name>.
+		typ, rest, _ := strings.Cut(parsed.Resource, ":")
+		_, name, found := strings.Cut(rest, ":autoScalingGroupName/")
+		if typ != "autoScalingGroup" || !found || name == "" {
+			return nil, errors.Errorf("unexpected resource %q of Auto Scaling ARN %q", parsed.Resource, resourceARN)
+		}
+		res.Type = ResourceTypeAutoScalingGroup
+		res.ID = name
+		res.Name = name
+	case "eks":
+		// The resource of EKS ARNs is cluster/<name>, or <type>/<cluster>/<name>/<uuid> for the resources of a cluster.
+		parts := strings.Split(parsed.Resource, "/")
+		switch {
+		case len(parts) == 2 && parts[0] == "cluster":
+			res.Name = parts[1]
+		case len(parts) == 4 && parts[0] != "cluster":
+			res.Name = parts[2]
+			res.Parent = parts[1]
+		default:
+			return nil, errors.Errorf("unexpected resource %q of EKS ARN %q", parsed.Resource, resourceARN)
+		}
+		res.Type = ResourceType("eks:" + parts[0])
+		res.ID = res.Name
+	default:
+		typ := strings.FieldsFunc(parsed.Resource, func(r rune) bool { return r == '/' || r == ':' })
+		if len(typ) == 0 {
+			return nil, errors.Errorf("unexpected resource %q of ARN %q", parsed.Resource, resourceARN)
+		}
+		res.Type = ResourceType(parsed.Service + ":" + typ[0])
+	}
+
+	return res, nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseARN(t *testing.T) {
+	tests := []struct {
+		name    string
+		arn     string
+		want    *Resource
+		wantErr bool
+	}{
+		{
+			name: "ec2 instance",
+			arn:  "arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0",
+			want: &Resource{Type: ResourceTypeInstance, ID: "i-0123456789abcdef0"},
+		},
+		{
+			name: "ec2 security group in aws-cn",
+			arn:  "arn:aws-cn:ec2:cn-north-1:123456789012:security-group/sg-0123456789abcdef0",
+			want: &Resource{Type: ResourceTypeSecurityGroup, ID: "sg-0123456789abcdef0"},
+		},
+		{
+			name: "ec2 launch template in aws-us-gov",
+			arn:  "arn:aws-us-gov:ec2:us-gov-west-1:123456789012:launch-template/lt-0123456789abcdef0",
+			want: &Resource{Type: ResourceTypeLaunchTemplate, ID: "lt-0123456789abcdef0"},
+		},
+		{
+			name:    "ec2 without resource ID",
+			arn:     "arn:aws:ec2:us-east-1:123456789012:instance",
+			wantErr: true,
+		},
+		{
+			name: "classic load balancer",
+			arn:  "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/test-apiserver",
+			want: &Resource{Type: ResourceTypeClassicLoadBalancer, ID: "test-apiserver", Name: "test-apiserver"},
+		},
+		{
+			name: "network load balancer in aws-cn",
+			arn:  "arn:aws-cn:elasticloadbalancing:cn-northwest-1:123456789012:loadbalancer/net/test-nlb/0123456789abcdef",
+			want: &Resource{Type: ResourceTypeLoadBalancer, Name: "test-nlb"},
+		},
+		{
+			name: "target group in aws-us-gov",
+			arn:  "arn:aws-us-gov:elasticloadbalancing:us-gov-east-1:123456789012:targetgroup/test-tg/0123456789abcdef",
+			want: &Resource{Type: ResourceTypeTargetGroup, Name: "test-tg"},
+		},
+		{
+			name: "listener",
+			arn:  "arn:aws:elasticloadbalancing:us-east-1:123456789012:listener/net/test-nlb/0123456789abcdef/0123456789abcdef",
+			want: &Resource{Type: ResourceType("elasticloadbalancing:listener")},
+		},
+		{
+			name: "auto scaling group",
+			arn:  "arn:aws:autoscaling:us-east-1:123456789012:autoScalingGroup:01234567-89ab-cdef-0123-456789abcdef:autoScalingGroupName/test-pool",
+			want: &Resource{Type: ResourceTypeAutoScalingGroup, ID: "test-pool", Name: "test-pool"},
+		},
+		{
+			name: "auto scaling group in aws-cn",
+			arn:  "arn:aws-cn:autoscaling:cn-north-1:123456789012:autoScalingGroup:01234567-89ab-cdef-0123-456789abcdef:autoScalingGroupName/test-pool",
+			want: &Resource{Type: ResourceTypeAutoScalingGroup, ID: "test-pool", Name: "test-pool"},
+		},
+		{
+			name:    "auto scaling launch configuration",
+			arn:     "arn:aws:autoscaling:us-east-1:123456789012:launchConfiguration:01234567-89ab-cdef-0123-456789abcdef:launchConfigurationName/test",
+			wantErr: true,
+		},
+		{
+			name: "eks cluster",
+			arn:  "arn:aws:eks:us-east-1:123456789012:cluster/test-cluster",
+			want: &Resource{Type: ResourceTypeEKSCluster, ID: "test-cluster", Name: "test-cluster"},
+		},
+		{
+			name: "eks node group in aws-us-gov",
+			arn:  "arn:aws-us-gov:eks:us-gov-west-1:123456789012:nodegroup/test-cluster/test-pool/01234567-89ab-cdef-0123-456789abcdef",
+			want: &Resource{Type: ResourceTypeEKSNodegroup, ID: "test-pool", Name: "test-pool", Parent: "test-cluster"},
+		},
+		{
+			name: "eks fargate profile in aws-cn",
+			arn:  "arn:aws-cn:eks:cn-north-1:123456789012:fargateprofile/test-cluster/test-profile/01234567-89ab-cdef-0123-456789abcdef",
+			want: &Resource{Type: ResourceTypeEKSFargateProfile, ID: "test-profile", Name: "test-profile", Parent: "test-cluster"},
+		},
+		{
+			name: "eks addon",
+			arn:  "arn:aws:eks:us-east-1:123456789012:addon/test-cluster/vpc-cni/01234567-89ab-cdef-0123-456789abcdef",
+			want: &Resource{Type: ResourceTypeEKSAddon, ID: "vpc-cni", Name: "vpc-cni", Parent: "test-cluster"},
+		},
+		{
+			name:    "eks node group without cluster",
+			arn:     "arn:aws:eks:us-east-1:123456789012:nodegroup/test-pool",
+			wantErr: true,
+		},
+		{
+			name: "other service",
+			arn:  "arn:aws:iam::123456789012:role/test-role",
+			want: &Resource{Type: ResourceType("iam:role")},
+		},
+		{
+			name:    "invalid ARN",
+			arn:     "i-0123456789abcdef0",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := ParseARN(tt.arn)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			want := *tt.want
+			want.ARN = tt.arn
+			if want.ID == "" {
+				want.ID = tt.arn
+			}
+			g.Expect(got).To(Equal(&want))
+		})
+	}
+}
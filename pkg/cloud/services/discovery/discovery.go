@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package discovery provides a way to find the AWS resources which have a tag, e.g. the ownership tag
+// of a cluster, with few API calls.
+package discovery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	rgapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/util/system"
+)
+
+// maxDescribeTagsRequest is the maximum number of load balancers of an ELB DescribeTags request.
+const maxDescribeTagsRequest = 20
+
+// Discoverer finds the resources which have a tag with the Resource Groups Tagging API, which returns
+// the resources of all the services at once.
+type Discoverer struct {
+	region                string
+	resourceTaggingClient resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
+	elbClient             elbiface.ELBAPI
+}
+
+// NewDiscoverer returns a new Discoverer of the resources of a region. The classic load balancers are
+// also listed with the ELB API when elbClient isn't nil, as the Resource Groups Tagging API doesn't
+// cover all of them.
+func NewDiscoverer(region string, resourceTaggingClient resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI, elbClient elbiface.ELBAPI) *Discoverer {
+	return &Discoverer{
+		region:                region,
+		resourceTaggingClient: resourceTaggingClient,
+		elbClient:             elbClient,
+	}
+}
+
+// Discover returns the resources which have the tag key with one of the values. Only the resources of
+// the types are returned, or the resources of all the types when none is given.
+func (d *Discoverer) Discover(ctx context.Context, key string, values []string, types ...ResourceType) ([]*Resource, error) {
+	wanted := sets.New[ResourceType](types...)
+	input := &rgapi.GetResourcesInput{
+		TagFilters: []*rgapi.TagFilter{
+			{
+				Key:    aws.String(key),
+				Values: aws.StringSlice(values),
+			},
+		},
+	}
+	if len(types) > 0 {
+		filters := sets.New[string]()
+		for _, t := range types {
+			filters.Insert(t.filter())
+		}
+		input.ResourceTypeFilters = aws.StringSlice(sets.List(filters))
+	}
+
+	resources := []*Resource{}
+	found := sets.New[string]()
+	for {
+		out, err := d.resourceTaggingClient.GetResourcesWithContext(ctx, input)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get resources with tag %s", key)
+		}
+		for _, mapping := range out.ResourceTagMappingList {
+			res, err := ParseARN(aws.StringValue(mapping.ResourceARN))
+			if err != nil {
+				return nil, err
+			}
+			if wanted.Len() > 0 && !wanted.Has(res.Type) {
+				continue
+			}
+			res.Tags = map[string]string{}
+			for _, tag := range mapping.Tags {
+				res.Tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+			resources = append(resources, res)
+			found.Insert(resourceKey(res))
+		}
+		if aws.StringValue(out.PaginationToken) == "" {
+			break
+		}
+		input.PaginationToken = out.PaginationToken
+	}
+
+	if d.elbClient == nil || (wanted.Len() > 0 && !wanted.Has(ResourceTypeClassicLoadBalancer)) {
+		return resources, nil
+	}
+	classic, err := d.discoverClassicLoadBalancers(ctx, key, values)
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range classic {
+		if !found.Has(resourceKey(res)) {
+			resources = append(resources, res)
+		}
+	}
+	return resources, nil
+}
+
+// discoverClassicLoadBalancers returns the classic load balancers which have the tag key with one of
+// the values. Their ARNs have no account ID, as the ELB API doesn't return it.
+func (d *Discoverer) discoverClassicLoadBalancers(ctx context.Context, key string, values []string) ([]*Resource, error) {
+	names := []string{}
+	if err := d.elbClient.DescribeLoadBalancersPagesWithContext(ctx, &elb.DescribeLoadBalancersInput{}, func(out *elb.DescribeLoadBalancersOutput, _ bool) bool {
+		for _, lb := range out.LoadBalancerDescriptions {
+			names = append(names, aws.StringValue(lb.LoadBalancerName))
+		}
+		return true
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to describe classic load balancers")
+	}
+
+	partition := system.GetPartitionFromRegion(d.region)
+	accepted := sets.New[string](values...)
+	resources := []*Resource{}
+	for i := 0; i < len(names); i += maxDescribeTagsRequest {
+		chunk := names[i:min(i+maxDescribeTagsRequest, len(names))]
+		out, err := d.elbClient.DescribeTagsWithContext(ctx, &elb.DescribeTagsInput{LoadBalancerNames: aws.StringSlice(chunk)})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to describe tags of classic load balancers")
+		}
+		for _, desc := range out.TagDescriptions {
+			tags := converters.ELBTagsToMap(desc.Tags)
+			if value, ok := tags[key]; !ok || !accepted.Has(value) {
+				continue
+			}
+			name := aws.StringValue(desc.LoadBalancerName)
+			resources = append(resources, &Resource{
+				ARN:  fmt.Sprintf("arn:%s:elasticloadbalancing:%s::loadbalancer/%s", partition, d.region, name),
+				Type: ResourceTypeClassicLoadBalancer,
+				ID:   name,
+				Name: name,
+				Tags: tags,
+			})
+		}
+	}
+	return resources, nil
+}
+
+// resourceKey returns the key of a resource, which doesn't depend on how it was found.
+func resourceKey(res *Resource) string {
+	return string(res.Type) + "/" + res.ID
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+	rgapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/test/mocks"
+)
+
+const (
+	testTagKey          = "kubernetes.io/cluster/test-cluster"
+	testInstanceARN     = "arn:aws-cn:ec2:cn-north-1:123456789012:instance/i-0123456789abcdef0"
+	testLoadBalancerARN = "arn:aws-cn:elasticloadbalancing:cn-north-1:123456789012:loadbalancer/net/test-nlb/0123456789abcdef"
+	testClassicARN      = "arn:aws-cn:elasticloadbalancing:cn-north-1:123456789012:loadbalancer/test-apiserver"
+)
+
+func tagFilters() []*rgapi.TagFilter {
+	return []*rgapi.TagFilter{{Key: aws.String(testTagKey), Values: aws.StringSlice([]string{"owned"})}}
+}
+
+func ownedTags() []*rgapi.Tag {
+	return []*rgapi.Tag{{Key: aws.String(testTagKey), Value: aws.String("owned")}}
+}
+
+func TestDiscover(t *testing.T) {
+	tests := []struct {
+		name        string
+		types       []ResourceType
+		withELB     bool
+		expectRG    func(m *mocks.MockResourceGroupsTaggingAPIAPIMockRecorder)
+		expectELB   func(m *mocks.MockELBAPIMockRecorder)
+		wantARNs    []string
+		wantErr     bool
+		wantClassic *Resource
+	}{
+		{
+			name: "follows the pages of the resource tagging API",
+			expectRG: func(m *mocks.MockResourceGroupsTaggingAPIAPIMockRecorder) {
+				m.GetResourcesWithContext(gomock.Any(), &rgapi.GetResourcesInput{TagFilters: tagFilters()}).
+					Return(&rgapi.GetResourcesOutput{
+						ResourceTagMappingList: []*rgapi.ResourceTagMapping{{ResourceARN: aws.String(testInstanceARN), Tags: ownedTags()}},
+						PaginationToken:        aws.String("next"),
+					}, nil)
+				m.GetResourcesWithContext(gomock.Any(), &rgapi.GetResourcesInput{TagFilters: tagFilters(), PaginationToken: aws.String("next")}).
+					Return(&rgapi.GetResourcesOutput{
+						ResourceTagMappingList: []*rgapi.ResourceTagMapping{{ResourceARN: aws.String(testLoadBalancerARN), Tags: ownedTags()}},
+						PaginationToken:        aws.String(""),
+					}, nil)
+			},
+			wantARNs: []string{testInstanceARN, testLoadBalancerARN},
+		},
+		{
+			name:    "filters the load balancers by type without listing the classic ones",
+			types:   []ResourceType{ResourceTypeLoadBalancer},
+			withELB: true,
+			expectRG: func(m *mocks.MockResourceGroupsTaggingAPIAPIMockRecorder) {
+				m.GetResourcesWithContext(gomock.Any(), &rgapi.GetResourcesInput{
+					TagFilters:          tagFilters(),
+					ResourceTypeFilters: aws.StringSlice([]string{"elasticloadbalancing:loadbalancer"}),
+				}).Return(&rgapi.GetResourcesOutput{ResourceTagMappingList: []*rgapi.ResourceTagMapping{
+					{ResourceARN: aws.String(testLoadBalancerARN)},
+					{ResourceARN: aws.String(testClassicARN)},
+				}}, nil)
+			},
+			wantARNs: []string{testLoadBalancerARN},
+		},
+		{
+			name:    "lists the classic load balancers the resource tagging API doesn't return",
+			types:   []ResourceType{ResourceTypeLoadBalancer, ResourceTypeClassicLoadBalancer},
+			withELB: true,
+			expectRG: func(m *mocks.MockResourceGroupsTaggingAPIAPIMockRecorder) {
+				m.GetResourcesWithContext(gomock.Any(), &rgapi.GetResourcesInput{
+					TagFilters:          tagFilters(),
+					ResourceTypeFilters: aws.StringSlice([]string{"elasticloadbalancing:loadbalancer"}),
+				}).Return(&rgapi.GetResourcesOutput{ResourceTagMappingList: []*rgapi.ResourceTagMapping{
+					{ResourceARN: aws.String(testClassicARN), Tags: ownedTags()},
+				}}, nil)
+			},
+			expectELB: func(m *mocks.MockELBAPIMockRecorder) {
+				m.DescribeLoadBalancersPagesWithContext(gomock.Any(), &elb.DescribeLoadBalancersInput{}, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ *elb.DescribeLoadBalancersInput, fn func(*elb.DescribeLoadBalancersOutput, bool) bool, _ ...interface{}) error {
+						fn(&elb.DescribeLoadBalancersOutput{LoadBalancerDescriptions: []*elb.LoadBalancerDescription{
+							{LoadBalancerName: aws.String("test-apiserver")},
+							{LoadBalancerName: aws.String("test-service")},
+							{LoadBalancerName: aws.String("other-service")},
+						}}, true)
+						return nil
+					})
+				m.DescribeTagsWithContext(gomock.Any(), &elb.DescribeTagsInput{
+					LoadBalancerNames: aws.StringSlice([]string{"test-apiserver", "test-service", "other-service"}),
+				}).Return(&elb.DescribeTagsOutput{TagDescriptions: []*elb.TagDescription{
+					{LoadBalancerName: aws.String("test-apiserver"), Tags: []*elb.Tag{{Key: aws.String(testTagKey), Value: aws.String("owned")}}},
+					{LoadBalancerName: aws.String("test-service"), Tags: []*elb.Tag{{Key: aws.String(testTagKey), Value: aws.String("owned")}}},
+					{LoadBalancerName: aws.String("other-service"), Tags: []*elb.Tag{{Key: aws.String(testTagKey), Value: aws.String("shared")}}},
+				}}, nil)
+			},
+			wantARNs: []string{testClassicARN, "arn:aws-cn:elasticloadbalancing:cn-north-1::loadbalancer/test-service"},
+			wantClassic: &Resource{
+				ARN:  "arn:aws-cn:elasticloadbalancing:cn-north-1::loadbalancer/test-service",
+				Type: ResourceTypeClassicLoadBalancer,
+				ID:   "test-service",
+				Name: "test-service",
+				Tags: map[string]string{testTagKey: "owned"},
+			},
+		},
+		{
+			name: "fails on an unexpected ARN",
+			expectRG: func(m *mocks.MockResourceGroupsTaggingAPIAPIMockRecorder) {
+				m.GetResourcesWithContext(gomock.Any(), &rgapi.GetResourcesInput{TagFilters: tagFilters()}).
+					Return(&rgapi.GetResourcesOutput{ResourceTagMappingList: []*rgapi.ResourceTagMapping{
+						{ResourceARN: aws.String("arn:aws:ec2:us-east-1:123456789012:instance")},
+					}}, nil)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			rgMock := mocks.NewMockResourceGroupsTaggingAPIAPI(mockCtrl)
+			tt.expectRG(rgMock.EXPECT())
+			d := NewDiscoverer("cn-north-1", rgMock, nil)
+			if tt.withELB {
+				elbMock := mocks.NewMockELBAPI(mockCtrl)
+				if tt.expectELB != nil {
+					tt.expectELB(elbMock.EXPECT())
+				}
+				d = NewDiscoverer("cn-north-1", rgMock, elbMock)
+			}
+
+			resources, err := d.Discover(context.TODO(), testTagKey, []string{"owned"}, tt.types...)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			arns := []string{}
+			for _, res := range resources {
+				arns = append(arns, res.ARN)
+			}
+			g.Expect(arns).To(Equal(tt.wantARNs))
+			if tt.wantClassic != nil {
+				g.Expect(resources[len(resources)-1]).To(Equal(tt.wantClassic))
+			}
+		})
+	}
+}
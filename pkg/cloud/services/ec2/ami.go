@@ -309,6 +309,35 @@ func (s *Service) defaultBastionAMILookup() (string, error) {
 	return *latestImage.ImageId, nil
 }
 
+// validateImagePlatform checks that the platform of an image matches the operating system of the
+// node that runs on it. The check is skipped when the operating system is not set.
+func (s *Service) validateImagePlatform(imageID string, os infrav1.OSType) error {
+	if os == "" {
+		return nil
+	}
+
+	out, err := s.EC2Client.DescribeImagesWithContext(context.TODO(), &ec2.DescribeImagesInput{
+		ImageIds: aws.StringSlice([]string{imageID}),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to describe image %q", imageID)
+	}
+	if len(out.Images) == 0 {
+		return errors.Errorf("found no AMI with ID %q", imageID)
+	}
+
+	// The platform of an image is only set for Windows images.
+	platform := infrav1.OSTypeLinux
+	if strings.EqualFold(aws.StringValue(out.Images[0].Platform), ec2.PlatformValuesWindows) {
+		platform = infrav1.OSTypeWindows
+	}
+	if platform != os {
+		return errors.Errorf("AMI %q is a %s image, but the operating system is set to %s", imageID, platform, os)
+	}
+
+	return nil
+}
+
 func (s *Service) eksAMILookup(kubernetesVersion string, architecture string, amiType *infrav1.EKSAMILookupType) (string, error) {
 	// format ssm parameter path properly
 	formattedVersion, err := formatVersionForEKS(kubernetesVersion)
@@ -29,6 +29,7 @@ import (
 	"k8s.io/utils/ptr"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/converters"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/filter"
@@ -175,6 +176,11 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte, use
 		}
 	}
 
+	if err := s.validateImagePlatform(input.ImageID, scope.AWSMachine.Spec.OS); err != nil {
+		record.Warnf(scope.AWSMachine, "FailedValidateImage", "Failed to validate AMI: %v", err)
+		return nil, err
+	}
+
 	subnetID, err := s.findSubnet(scope)
 	if err != nil {
 		return nil, err
@@ -186,6 +192,11 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte, use
 		return nil, awserrors.NewFailedDependency("failed to run controlplane, APIServer ELB not available")
 	}
 
+	userData, err = userdata.ForOS(userData, userDataFormat, scope.AWSMachine.Spec.OS)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to frame userdata")
+	}
+
 	if scope.CompressUserData(userDataFormat) {
 		userData, err = userdata.GzipBytes(userData)
 		if err != nil {
@@ -195,6 +206,14 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte, use
 
 	input.UserData = ptr.To[string](base64.StdEncoding.EncodeToString(userData))
 
+	if feature.Gates.Enabled(feature.UserDataDriftDetection) {
+		input.Tags[infrav1.InstanceUserDataHashTagKey] = userdata.ComputeHash(userData)
+	}
+
+	if delivery := scope.AWSMachine.Status.UserDataDelivery; delivery != "" {
+		input.Tags[infrav1.InstanceUserDataDeliveryTagKey] = string(delivery)
+	}
+
 	// Set security groups.
 	ids, err := s.GetCoreSecurityGroups(scope)
 	if err != nil {
@@ -202,25 +221,14 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte, use
 	}
 	input.SecurityGroupIDs = append(input.SecurityGroupIDs, ids...)
 
-	// If SSHKeyName WAS NOT provided in the AWSMachine Spec, fallback to the value provided in the AWSCluster Spec.
-	// If a value was not provided in the AWSCluster Spec, then use the defaultSSHKeyName
-	// Note that:
-	// - a nil AWSMachine.Spec.SSHKeyName value means use the AWSCluster.Spec.SSHKeyName SSH key name value
-	// - nil values for both AWSCluster.Spec.SSHKeyName and AWSMachine.Spec.SSHKeyName means use the default SSH key name value
-	// - an empty string means do not set an SSH key name at all
-	// - otherwise use the value specified in either AWSMachine or AWSCluster
+	// The SSHKeyName of the AWSMachine takes precedence over the one of the AWSCluster. If neither provides one,
+	// then use the defaultSSHKeyName. Note that an empty string means do not set an SSH key name at all.
 	var prioritizedSSHKeyName string
-	switch {
-	case scope.AWSMachine.Spec.SSHKeyName != nil:
-		// prefer AWSMachine.Spec.SSHKeyName if it is defined
-		prioritizedSSHKeyName = *scope.AWSMachine.Spec.SSHKeyName
-	case scope.InfraCluster.SSHKeyName() != nil:
-		// fallback to AWSCluster.Spec.SSHKeyName if it is defined
-		prioritizedSSHKeyName = *scope.InfraCluster.SSHKeyName()
-	default:
-		if !scope.IsExternallyManaged() {
-			prioritizedSSHKeyName = defaultSSHKeyName
-		}
+	switch sshKeyName := scope.SSHKeyName(); {
+	case sshKeyName != nil:
+		prioritizedSSHKeyName = *sshKeyName
+	case !scope.IsExternallyManaged():
+		prioritizedSSHKeyName = defaultSSHKeyName
 	}
 
 	// Only set input.SSHKeyName if the user did not explicitly request no ssh key be set (explicitly setting "" on either the Machine or related Cluster)
@@ -230,7 +238,7 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte, use
 
 	input.SpotMarketOptions = scope.AWSMachine.Spec.SpotMarketOptions
 
-	input.InstanceMetadataOptions = scope.AWSMachine.Spec.InstanceMetadataOptions
+	input.InstanceMetadataOptions = scope.InstanceMetadataOptions()
 
 	input.Tenancy = scope.AWSMachine.Spec.Tenancy
 
@@ -678,6 +686,26 @@ func (s *Service) GetInstanceSecurityGroups(instanceID string) (map[string][]str
 	return out, nil
 }
 
+// GetInstanceUserDataHash returns the hash of the current user data of the given EC2 instance.
+func (s *Service) GetInstanceUserDataHash(instanceID string) (string, error) {
+	out, err := s.EC2Client.DescribeInstanceAttributeWithContext(context.TODO(), &ec2.DescribeInstanceAttributeInput{
+		InstanceId: aws.String(instanceID),
+		Attribute:  aws.String(ec2.InstanceAttributeNameUserData),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe user data of instance %q", instanceID)
+	}
+
+	var userData []byte
+	if out.UserData != nil && out.UserData.Value != nil {
+		userData, err = base64.StdEncoding.DecodeString(aws.StringValue(out.UserData.Value))
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to decode user data of instance %q", instanceID)
+		}
+	}
+	return userdata.ComputeHash(userData), nil
+}
+
 // UpdateInstanceSecurityGroups modifies the security groups of the given
 // EC2 instance.
 func (s *Service) UpdateInstanceSecurityGroups(instanceID string, ids []string) error {
@@ -273,6 +273,95 @@ func TestTerminateInstance(t *testing.T) {
 	}
 }
 
+func TestGetInstanceUserDataHash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	describeInput := &ec2.DescribeInstanceAttributeInput{
+		InstanceId: aws.String("i-1234"),
+		Attribute:  aws.String(ec2.InstanceAttributeNameUserData),
+	}
+
+	testCases := []struct {
+		name     string
+		expect   func(m *mocks.MockEC2APIMockRecorder)
+		wantHash string
+		wantErr  bool
+	}{
+		{
+			name: "returns the hash of the decoded user data",
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				m.DescribeInstanceAttributeWithContext(context.TODO(), gomock.Eq(describeInput)).
+					Return(&ec2.DescribeInstanceAttributeOutput{
+						UserData: &ec2.AttributeValue{Value: aws.String(base64.StdEncoding.EncodeToString([]byte("user-data")))},
+					}, nil)
+			},
+			wantHash: userdata.ComputeHash([]byte("user-data")),
+		},
+		{
+			name: "returns the hash of empty user data if the instance has none",
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				m.DescribeInstanceAttributeWithContext(context.TODO(), gomock.Eq(describeInput)).
+					Return(&ec2.DescribeInstanceAttributeOutput{UserData: &ec2.AttributeValue{}}, nil)
+			},
+			wantHash: userdata.ComputeHash(nil),
+		},
+		{
+			name: "returns an error if the user data is not valid base64",
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				m.DescribeInstanceAttributeWithContext(context.TODO(), gomock.Eq(describeInput)).
+					Return(&ec2.DescribeInstanceAttributeOutput{UserData: &ec2.AttributeValue{Value: aws.String("not base64!")}}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "returns an error if the attribute can't be described",
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				m.DescribeInstanceAttributeWithContext(context.TODO(), gomock.Eq(describeInput)).
+					Return(nil, awserr.New(awserrors.UnauthorizedOperation, "unauthorized", nil))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mocks.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     client,
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			hash, err := s.GetInstanceUserDataHash("i-1234")
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+			if hash != tc.wantHash {
+				t.Fatalf("expected hash %q, got %q", tc.wantHash, hash)
+			}
+		})
+	}
+}
+
 func TestCreateInstance(t *testing.T) {
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -20,20 +20,25 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apimachinerytypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services"
@@ -51,8 +56,30 @@ const (
 	// See https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/
 	// for annotation formatting rules.
 	TagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-aws-last-applied-tags"
+
+	// DefaultLaunchTemplateVersionsToRetain is the default number of the most recent versions of a launch
+	// template kept when a new version is created.
+	DefaultLaunchTemplateVersionsToRetain = 1
+
+	// maxLaunchTemplateVersionsToPrune is the maximum number of versions deleted before a new version is created.
+	maxLaunchTemplateVersionsToPrune = 20
 )
 
+// launchTemplateVersionsToRetain is the number of the most recent versions of a launch template kept when a
+// new version is created.
+var launchTemplateVersionsToRetain int64 = DefaultLaunchTemplateVersionsToRetain
+
+// SetLaunchTemplateVersionsToRetain sets the number of the most recent versions of the launch templates managed
+// by CAPA which are kept when a new version is created, in addition to the default version. It must be called
+// before any launch template is reconciled.
+func SetLaunchTemplateVersionsToRetain(versions int) error {
+	if versions < 1 {
+		return errors.Errorf("the number of launch template versions to retain must be at least 1, got %d", versions)
+	}
+	launchTemplateVersionsToRetain = int64(versions)
+	return nil
+}
+
 // ReconcileLaunchTemplate reconciles a launch template and triggers instance refresh conditionally, depending on
 // changes.
 //
@@ -63,11 +90,32 @@ func (s *Service) ReconcileLaunchTemplate(
 	canUpdateLaunchTemplate func() (bool, error),
 	runPostLaunchTemplateUpdateOperation func() error,
 ) error {
+	if existingTemplate := scope.GetLaunchTemplate().ExistingTemplate; existingTemplate != nil {
+		return s.reconcileExistingLaunchTemplate(scope, ec2svc, existingTemplate, canUpdateLaunchTemplate, runPostLaunchTemplateUpdateOperation)
+	}
+
 	bootstrapData, bootstrapDataSecretKey, err := scope.GetRawBootstrapData()
 	if err != nil {
 		record.Eventf(scope.GetMachinePool(), corev1.EventTypeWarning, "FailedGetBootstrapData", err.Error())
 		return err
 	}
+	// The user data rendered by a transform is compared with the user data of the latest version of the
+	// launch template like the bootstrap data, so that a change of the template or of its inputs creates
+	// a new version.
+	if feature.Gates.Enabled(feature.UserDataTransform) && scope.GetLaunchTemplate().UserDataTransform != nil {
+		bootstrapData, err = scope.TransformUserData(bootstrapData)
+		if err != nil {
+			record.Eventf(scope.GetMachinePool(), corev1.EventTypeWarning, "FailedUserDataTransform", err.Error())
+			conditions.MarkFalse(scope.GetSetter(), infrav1.UserDataTransformReadyCondition, infrav1.UserDataTransformFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			return err
+		}
+		conditions.MarkTrue(scope.GetSetter(), infrav1.UserDataTransformReadyCondition)
+	}
+	if len(bootstrapData) > userdata.MaxUserDataSize {
+		err := errors.Errorf("user data is %d bytes, which exceeds the maximum of %d bytes", len(bootstrapData), userdata.MaxUserDataSize)
+		conditions.MarkFalse(scope.GetSetter(), expinfrav1.LaunchTemplateReadyCondition, expinfrav1.LaunchTemplateCreateFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
 	bootstrapDataHash := userdata.ComputeHash(bootstrapData)
 
 	scope.Info("checking for existing launch template")
@@ -125,7 +173,10 @@ func (s *Service) ReconcileLaunchTemplate(
 	// Check if the instance tags were changed. If they were, create a new LaunchTemplate.
 	tagsChanged, _, _, _ := tagsChanged(annotation, scope.AdditionalTags()) //nolint:dogsled
 
-	needsUpdate, err := ec2svc.LaunchTemplateNeedsUpdate(scope, scope.GetLaunchTemplate(), launchTemplate)
+	// The launch template is compared with the metadata options it is created with, which may be the cluster's.
+	incoming := scope.GetLaunchTemplate().DeepCopy()
+	incoming.InstanceMetadataOptions = scope.InstanceMetadataOptions()
+	needsUpdate, err := ec2svc.LaunchTemplateNeedsUpdate(scope, incoming, launchTemplate)
 	if err != nil {
 		return err
 	}
@@ -151,14 +202,25 @@ func (s *Service) ReconcileLaunchTemplate(
 		}
 	}
 
+	// The hash of the user data of the launch template is invalidated when the user data was rendered before the
+	// bootstrap token it embeds expired, so that a new version is created with the current bootstrap data.
+	if userDataRegenerationRequested(scope) {
+		launchTemplateUserDataHash = ""
+	}
 	userDataHashChanged := launchTemplateUserDataHash != bootstrapDataHash
 
 	// Create a new launch template version if there's a difference in configuration, tags,
 	// userdata, OR we've discovered a new AMI ID.
 	if needsUpdate || tagsChanged || amiChanged || userDataHashChanged || userDataSecretKeyChanged || launchTemplateNeedsUserDataSecretKeyTag {
-		scope.Info("creating new version for launch template", "existing", launchTemplate, "incoming", scope.GetLaunchTemplate(), "needsUpdate", needsUpdate, "tagsChanged", tagsChanged, "amiChanged", amiChanged, "userDataHashChanged", userDataHashChanged, "userDataSecretKeyChanged", userDataSecretKeyChanged)
+		changes := launchTemplateChanges(
+			launchTemplateState{template: launchTemplate, imageID: aws.StringValue(launchTemplate.AMI.ID), userDataHash: launchTemplateUserDataHash, userDataSecretKey: launchTemplateUserDataSecretKey},
+			launchTemplateState{template: scope.GetLaunchTemplate(), imageID: aws.StringValue(imageID), userDataHash: bootstrapDataHash, userDataSecretKey: bootstrapDataSecretKey},
+			needsUpdate, annotation, scope.AdditionalTags(),
+		)
+		scope.Info("creating new version for launch template", "existing", launchTemplate, "incoming", scope.GetLaunchTemplate(), "needsUpdate", needsUpdate, "tagsChanged", tagsChanged, "amiChanged", amiChanged, "userDataHashChanged", userDataHashChanged, "userDataSecretKeyChanged", userDataSecretKeyChanged, "changes", changes)
 		// There is a limit to the number of Launch Template Versions.
-		// We ensure that the number of versions does not grow without bound by following a simple rule: Before we create a new version, we delete one old version, if there is at least one old version that is not in use.
+		// We ensure that the number of versions does not grow without bound by following a simple rule: Before we create a new version,
+		// we delete the oldest versions which are not retained, a bounded number at a time.
 		if err := ec2svc.PruneLaunchTemplateVersions(scope.GetLaunchTemplateIDStatus()); err != nil {
 			return err
 		}
@@ -170,6 +232,12 @@ func (s *Service) ReconcileLaunchTemplate(
 			return err
 		}
 
+		scope.SetLaunchTemplateChangeStatus(&expinfrav1.LaunchTemplateChange{
+			PreviousVersion: scope.GetLaunchTemplateLatestVersionStatus(),
+			Version:         version,
+			Changes:         changes,
+			Time:            metav1.Now(),
+		})
 		scope.SetLaunchTemplateLatestVersionStatus(version)
 		if err := scope.PatchObject(); err != nil {
 			return err
@@ -187,6 +255,73 @@ func (s *Service) ReconcileLaunchTemplate(
 	return nil
 }
 
+// reconcileExistingLaunchTemplate reconciles a launch template that is managed outside of CAPA. The template is
+// never modified. Only the version it resolves to is tracked in the status, and when FollowLatest is set, a change
+// of that version triggers the post launch template update operation.
+func (s *Service) reconcileExistingLaunchTemplate(
+	scope scope.LaunchTemplateScope,
+	ec2svc services.EC2Interface,
+	existingTemplate *expinfrav1.ExistingLaunchTemplate,
+	canUpdateLaunchTemplate func() (bool, error),
+	runPostLaunchTemplateUpdateOperation func() error,
+) error {
+	version, err := ec2svc.GetLaunchTemplateVersion(existingTemplate.ID, existingTemplate.GetVersion())
+	if err != nil {
+		conditions.MarkUnknown(scope.GetSetter(), expinfrav1.LaunchTemplateReadyCondition, expinfrav1.LaunchTemplateNotFoundReason, err.Error())
+		return err
+	}
+
+	previousID := scope.GetLaunchTemplateIDStatus()
+	previousVersion := scope.GetLaunchTemplateLatestVersionStatus()
+	if previousID == existingTemplate.ID && previousVersion == version {
+		return nil
+	}
+
+	// The status is blank the first time the template is seen, and after a `clusterctl move`. Neither case
+	// means that the instances use an outdated template.
+	changed := previousID != "" && previousVersion != ""
+	scope.Info("updating version of existing launch template", "id", existingTemplate.ID, "version", version, "previousID", previousID, "previousVersion", previousVersion)
+
+	if changed && existingTemplate.FollowLatest {
+		canUpdate, err := canUpdateLaunchTemplate()
+		if err != nil {
+			return err
+		}
+		if !canUpdate {
+			conditions.MarkFalse(scope.GetSetter(), expinfrav1.PreLaunchTemplateUpdateCheckCondition, expinfrav1.PreLaunchTemplateUpdateCheckFailedReason, clusterv1.ConditionSeverityWarning, "")
+			return errors.New("Cannot update the launch template, prerequisite not met")
+		}
+	}
+
+	if changed {
+		changes := []string{}
+		if previousID != existingTemplate.ID {
+			changes = append(changes, fmt.Sprintf("launchTemplateID: %s -> %s", previousID, existingTemplate.ID))
+		}
+		scope.SetLaunchTemplateChangeStatus(&expinfrav1.LaunchTemplateChange{
+			PreviousVersion: previousVersion,
+			Version:         version,
+			Changes:         changes,
+			Time:            metav1.Now(),
+		})
+	}
+	scope.SetLaunchTemplateIDStatus(existingTemplate.ID)
+	scope.SetLaunchTemplateLatestVersionStatus(version)
+	if err := scope.PatchObject(); err != nil {
+		return err
+	}
+
+	if changed && existingTemplate.FollowLatest {
+		if err := runPostLaunchTemplateUpdateOperation(); err != nil {
+			conditions.MarkFalse(scope.GetSetter(), expinfrav1.PostLaunchTemplateUpdateOperationCondition, expinfrav1.PostLaunchTemplateUpdateOperationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			return err
+		}
+		conditions.MarkTrue(scope.GetSetter(), expinfrav1.PostLaunchTemplateUpdateOperationCondition)
+	}
+
+	return nil
+}
+
 // ReconcileTags reconciles the tags for the AWSMachinePool instances.
 func (s *Service) ReconcileTags(scope scope.LaunchTemplateScope, resourceServicesToUpdate []scope.ResourceServiceToUpdate) error {
 	additionalTags := scope.AdditionalTags()
@@ -355,7 +490,7 @@ func (s *Service) GetLaunchTemplate(launchTemplateName string) (*expinfrav1.AWSL
 		Versions:           aws.StringSlice([]string{expinfrav1.LaunchTemplateLatestVersion}),
 	}
 
-	out, err := s.EC2Client.DescribeLaunchTemplateVersionsWithContext(context.TODO(), input)
+	versions, err := s.describeLaunchTemplateVersions(input)
 	switch {
 	case awserrors.IsNotFound(err):
 		return nil, "", nil, nil
@@ -363,11 +498,11 @@ func (s *Service) GetLaunchTemplate(launchTemplateName string) (*expinfrav1.AWSL
 		return nil, "", nil, err
 	}
 
-	if out == nil || out.LaunchTemplateVersions == nil || len(out.LaunchTemplateVersions) == 0 {
+	if len(versions) == 0 {
 		return nil, "", nil, nil
 	}
 
-	return s.SDKToLaunchTemplate(out.LaunchTemplateVersions[0])
+	return s.SDKToLaunchTemplate(versions[0])
 }
 
 // GetLaunchTemplateID returns the existing LaunchTemplateId or empty string if it doesn't exist.
@@ -381,7 +516,7 @@ func (s *Service) GetLaunchTemplateID(launchTemplateName string) (string, error)
 		Versions:           aws.StringSlice([]string{expinfrav1.LaunchTemplateLatestVersion}),
 	}
 
-	out, err := s.EC2Client.DescribeLaunchTemplateVersionsWithContext(context.TODO(), input)
+	versions, err := s.describeLaunchTemplateVersions(input)
 	switch {
 	case awserrors.IsNotFound(err):
 		return "", nil
@@ -390,11 +525,31 @@ func (s *Service) GetLaunchTemplateID(launchTemplateName string) (string, error)
 		return "", err
 	}
 
-	if out == nil || out.LaunchTemplateVersions == nil || len(out.LaunchTemplateVersions) == 0 {
+	if len(versions) == 0 {
 		return "", nil
 	}
 
-	return aws.StringValue(out.LaunchTemplateVersions[0].LaunchTemplateId), nil
+	return aws.StringValue(versions[0].LaunchTemplateId), nil
+}
+
+// describeLaunchTemplateVersions returns the launch template versions matching the input from all the pages
+// of results. A page may hold fewer versions than requested, even none, when there are more.
+func (s *Service) describeLaunchTemplateVersions(input *ec2.DescribeLaunchTemplateVersionsInput) ([]*ec2.LaunchTemplateVersion, error) {
+	var versions []*ec2.LaunchTemplateVersion
+	for {
+		out, err := s.EC2Client.DescribeLaunchTemplateVersionsWithContext(context.TODO(), input)
+		if err != nil {
+			return nil, err
+		}
+		if out == nil {
+			return versions, nil
+		}
+		versions = append(versions, out.LaunchTemplateVersions...)
+		if aws.StringValue(out.NextToken) == "" {
+			return versions, nil
+		}
+		input.NextToken = out.NextToken
+	}
 }
 
 // CreateLaunchTemplate generates a launch template to be used with the autoscaling group.
@@ -466,10 +621,11 @@ func (s *Service) CreateLaunchTemplateVersion(id string, scope scope.LaunchTempl
 func (s *Service) createLaunchTemplateData(scope scope.LaunchTemplateScope, imageID *string, userDataSecretKey apimachinerytypes.NamespacedName, userData []byte) (*ec2.RequestLaunchTemplateData, error) {
 	lt := scope.GetLaunchTemplate()
 
-	// An explicit empty string for SSHKeyName means do not specify a key in the ASG launch
+	// The SSH key and the metadata options of the pool take precedence over the ones of the cluster. An explicit
+	// empty string for SSHKeyName means do not specify a key in the ASG launch.
 	var sshKeyNamePtr *string
-	if lt.SSHKeyName != nil && *lt.SSHKeyName != "" {
-		sshKeyNamePtr = lt.SSHKeyName
+	if sshKeyName := scope.SSHKeyName(); sshKeyName != nil && *sshKeyName != "" {
+		sshKeyNamePtr = sshKeyName
 	}
 
 	data := &ec2.RequestLaunchTemplateData{
@@ -478,17 +634,17 @@ func (s *Service) createLaunchTemplateData(scope scope.LaunchTemplateScope, imag
 		UserData:     ptr.To[string](base64.StdEncoding.EncodeToString(userData)),
 	}
 
-	if lt.InstanceMetadataOptions != nil {
+	if metadataOptions := scope.InstanceMetadataOptions(); metadataOptions != nil {
 		data.MetadataOptions = &ec2.LaunchTemplateInstanceMetadataOptionsRequest{
-			HttpEndpoint:         aws.String(string(lt.InstanceMetadataOptions.HTTPEndpoint)),
-			InstanceMetadataTags: aws.String(string(lt.InstanceMetadataOptions.InstanceMetadataTags)),
+			HttpEndpoint:         aws.String(string(metadataOptions.HTTPEndpoint)),
+			InstanceMetadataTags: aws.String(string(metadataOptions.InstanceMetadataTags)),
 		}
 
-		if lt.InstanceMetadataOptions.HTTPTokens != "" {
-			data.MetadataOptions.HttpTokens = aws.String(string(lt.InstanceMetadataOptions.HTTPTokens))
+		if metadataOptions.HTTPTokens != "" {
+			data.MetadataOptions.HttpTokens = aws.String(string(metadataOptions.HTTPTokens))
 		}
-		if lt.InstanceMetadataOptions.HTTPPutResponseHopLimit != 0 {
-			data.MetadataOptions.HttpPutResponseHopLimit = aws.Int64(lt.InstanceMetadataOptions.HTTPPutResponseHopLimit)
+		if metadataOptions.HTTPPutResponseHopLimit != 0 {
+			data.MetadataOptions.HttpPutResponseHopLimit = aws.Int64(metadataOptions.HTTPPutResponseHopLimit)
 		}
 	}
 
@@ -570,7 +726,8 @@ func volumeToLaunchTemplateBlockDeviceMappingRequest(v *infrav1.Volume) *ec2.Lau
 	}
 }
 
-// DeleteLaunchTemplate delete a launch template.
+// DeleteLaunchTemplate deletes a launch template along with all its versions. A launch template which
+// doesn't exist anymore is considered deleted.
 func (s *Service) DeleteLaunchTemplate(id string) error {
 	s.scope.Debug("Deleting launch template", "id", id)
 
@@ -579,6 +736,10 @@ func (s *Service) DeleteLaunchTemplate(id string) error {
 	}
 
 	if _, err := s.EC2Client.DeleteLaunchTemplateWithContext(context.TODO(), input); err != nil {
+		if awserrors.IsNotFound(err) {
+			s.scope.Debug("Launch template is already deleted", "id", id)
+			return nil
+		}
 		return errors.Wrapf(err, "failed to delete launch template %q", id)
 	}
 
@@ -586,21 +747,39 @@ func (s *Service) DeleteLaunchTemplate(id string) error {
 	return nil
 }
 
-// PruneLaunchTemplateVersions deletes one old launch template version.
-// It does not delete the "latest" version, because that version may still be in use.
-// It does not delete the "default" version, because that version cannot be deleted.
-// It does not assume that versions are sequential. Versions may be deleted out of band.
+// PruneLaunchTemplateVersions deletes the old versions of a launch template, keeping the versions retained
+// as set with SetLaunchTemplateVersionsToRetain.
 func (s *Service) PruneLaunchTemplateVersions(id string) error {
-	// When there is one version available, it is the default and the latest.
-	// When there are two versions available, one the is the default, the other is the latest.
-	// Therefore we only prune when there are at least 3 versions available.
-	const minCountToAllowPrune = 3
+	return s.pruneLaunchTemplateVersions(id, launchTemplateVersionsToRetain)
+}
+
+// pruneLaunchTemplateVersions deletes the oldest versions of a launch template which are numbered below the
+// given number of most recent versions. The latest version is always retained, because that version may still
+// be in use, and the default version too, because that version cannot be deleted.
+// It does not assume that versions are sequential. Versions may be deleted out of band.
+//
+// Templates may have accumulated thousands of versions, so the versions are never listed. The latest and the
+// default versions are requested explicitly, then at most maxLaunchTemplateVersionsToPrune of the oldest
+// versions are deleted. A backlog of versions is therefore pruned over several calls, with a bounded number
+// of requests each.
+func (s *Service) pruneLaunchTemplateVersions(id string, retain int64) error {
+	latest, defaultVersion, err := s.getLaunchTemplateLatestAndDefaultVersions(id)
+	if err != nil {
+		return err
+	}
+
+	maxVersion := latest - max(retain, 1)
+	if maxVersion < 1 {
+		return nil
+	}
 
+	// One more version than can be pruned is requested, in case the default version is among them. Only the
+	// first page of results is used, the versions it misses are pruned by the next reconciliations.
 	input := &ec2.DescribeLaunchTemplateVersionsInput{
 		LaunchTemplateId: aws.String(id),
-		MinVersion:       aws.String("0"),
-		MaxVersion:       aws.String(expinfrav1.LaunchTemplateLatestVersion),
-		MaxResults:       aws.Int64(minCountToAllowPrune),
+		MinVersion:       aws.String("1"),
+		MaxVersion:       aws.String(strconv.FormatInt(maxVersion, 10)),
+		MaxResults:       aws.Int64(maxLaunchTemplateVersionsToPrune + 1),
 	}
 
 	out, err := s.EC2Client.DescribeLaunchTemplateVersionsWithContext(context.TODO(), input)
@@ -609,16 +788,47 @@ func (s *Service) PruneLaunchTemplateVersions(id string) error {
 		return err
 	}
 
-	// len(out.LaunchTemplateVersions)	|	items
-	// -------------------------------- + -----------------------
-	// 								1	|	[default/latest]
-	// 								2	|	[default, latest]
-	// 								3	| 	[default, versionToPrune, latest]
-	if len(out.LaunchTemplateVersions) < minCountToAllowPrune {
+	versionsToPrune := []int64{}
+	for _, v := range out.LaunchTemplateVersions {
+		if number := aws.Int64Value(v.VersionNumber); number != defaultVersion {
+			versionsToPrune = append(versionsToPrune, number)
+		}
+	}
+	if len(versionsToPrune) == 0 {
 		return nil
 	}
-	versionToPrune := out.LaunchTemplateVersions[1].VersionNumber
-	return s.deleteLaunchTemplateVersion(id, versionToPrune)
+	sort.Slice(versionsToPrune, func(i, j int) bool { return versionsToPrune[i] < versionsToPrune[j] })
+	if len(versionsToPrune) > maxLaunchTemplateVersionsToPrune {
+		versionsToPrune = versionsToPrune[:maxLaunchTemplateVersionsToPrune]
+	}
+	return s.deleteLaunchTemplateVersions(id, versionsToPrune)
+}
+
+// getLaunchTemplateLatestAndDefaultVersions returns the numbers of the latest and of the default versions of a
+// launch template, which are requested explicitly so that the cost doesn't depend on the number of versions.
+func (s *Service) getLaunchTemplateLatestAndDefaultVersions(id string) (int64, int64, error) {
+	input := &ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateId: aws.String(id),
+		Versions:         aws.StringSlice([]string{expinfrav1.LaunchTemplateLatestVersion, expinfrav1.LaunchTemplateDefaultVersion}),
+	}
+
+	versions, err := s.describeLaunchTemplateVersions(input)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to describe the latest and default versions of launch template %q", id)
+	}
+	if len(versions) == 0 {
+		return 0, 0, errors.Errorf("latest version of launch template %q not found", id)
+	}
+
+	// The default version is never more recent than the latest version, and both are the same version when
+	// a single one is returned.
+	latest, defaultVersion := int64(0), int64(math.MaxInt64)
+	for _, v := range versions {
+		number := aws.Int64Value(v.VersionNumber)
+		latest = max(latest, number)
+		defaultVersion = min(defaultVersion, number)
+	}
+	return latest, defaultVersion, nil
 }
 
 // GetLaunchTemplateLatestVersion returns the latest version of a launch template.
@@ -628,17 +838,37 @@ func (s *Service) GetLaunchTemplateLatestVersion(id string) (string, error) {
 		Versions:         aws.StringSlice([]string{expinfrav1.LaunchTemplateLatestVersion}),
 	}
 
-	out, err := s.EC2Client.DescribeLaunchTemplateVersionsWithContext(context.TODO(), input)
+	versions, err := s.describeLaunchTemplateVersions(input)
 	if err != nil {
 		s.scope.Info("", "aerr", err.Error())
 		return "", err
 	}
 
-	if len(out.LaunchTemplateVersions) == 0 {
+	if len(versions) == 0 {
 		return "", errors.Wrapf(err, "failed to get latest launch template version %q", id)
 	}
 
-	return strconv.Itoa(int(*out.LaunchTemplateVersions[0].VersionNumber)), nil
+	return strconv.Itoa(int(*versions[0].VersionNumber)), nil
+}
+
+// GetLaunchTemplateVersion returns the number of the version of a launch template that the
+// given version, which can be $Latest, $Default or a version number, resolves to.
+func (s *Service) GetLaunchTemplateVersion(id, version string) (string, error) {
+	input := &ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateId: aws.String(id),
+		Versions:         aws.StringSlice([]string{version}),
+	}
+
+	versions, err := s.describeLaunchTemplateVersions(input)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe version %q of launch template %q", version, id)
+	}
+
+	if len(versions) == 0 {
+		return "", errors.Errorf("version %q of launch template %q not found", version, id)
+	}
+
+	return strconv.FormatInt(aws.Int64Value(versions[0].VersionNumber), 10), nil
 }
 
 func (s *Service) deleteLaunchTemplateVersion(id string, version *int64) error {
@@ -663,6 +893,33 @@ func (s *Service) deleteLaunchTemplateVersion(id string, version *int64) error {
 	return nil
 }
 
+// deleteLaunchTemplateVersions deletes versions of a launch template with a single request. The versions which
+// couldn't be deleted, e.g. because they were deleted out of band, are logged rather than failing the prune.
+func (s *Service) deleteLaunchTemplateVersions(id string, versions []int64) error {
+	s.scope.Debug("Deleting launch template versions", "id", id, "versions", versions)
+
+	input := &ec2.DeleteLaunchTemplateVersionsInput{
+		LaunchTemplateId: aws.String(id),
+	}
+	for _, version := range versions {
+		input.Versions = append(input.Versions, aws.String(strconv.FormatInt(version, 10)))
+	}
+
+	out, err := s.EC2Client.DeleteLaunchTemplateVersionsWithContext(context.TODO(), input)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range out.UnsuccessfullyDeletedLaunchTemplateVersions {
+		if item.ResponseError != nil {
+			s.scope.Debug("Failed to delete launch template version", "id", id, "version", aws.Int64Value(item.VersionNumber), "code", aws.StringValue(item.ResponseError.Code))
+		}
+	}
+
+	s.scope.Debug("Deleted launch template versions", "id", id, "versions", len(out.SuccessfullyDeletedLaunchTemplateVersions))
+	return nil
+}
+
 // SDKToLaunchTemplate converts an AWS EC2 SDK instance to the CAPA instance type.
 func (s *Service) SDKToLaunchTemplate(d *ec2.LaunchTemplateVersion) (*expinfrav1.AWSLaunchTemplate, string, *apimachinerytypes.NamespacedName, error) {
 	v := d.LaunchTemplateData
@@ -745,6 +1002,22 @@ func (s *Service) SDKToLaunchTemplate(d *ec2.LaunchTemplateVersion) (*expinfrav1
 	return i, decodedUserDataHash, nil, nil
 }
 
+// userDataRegenerationRequested returns whether the BootstrapTokenExpiredAnnotation of a machine pool requests a new
+// version of its launch template, i.e. whether the latest version was created before the expiry it holds.
+func userDataRegenerationRequested(scope scope.LaunchTemplateScope) bool {
+	value, ok := scope.GetObjectMeta().Annotations[infrav1.BootstrapTokenExpiredAnnotation]
+	if !ok {
+		return false
+	}
+	expiredAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		scope.Error(err, "failed to parse annotation", "annotation", infrav1.BootstrapTokenExpiredAnnotation)
+		return false
+	}
+	change := scope.GetLaunchTemplateChangeStatus()
+	return change == nil || change.Time.Time.Before(expiredAt)
+}
+
 // LaunchTemplateNeedsUpdate checks if a new launch template version is needed.
 //
 // FIXME(dlipovetsky): This check should account for changed userdata, but does not yet do so.
@@ -788,13 +1061,30 @@ func (s *Service) LaunchTemplateNeedsUpdate(scope scope.LaunchTemplateScope, inc
 
 // DiscoverLaunchTemplateAMI will discover the AMI launch template.
 func (s *Service) DiscoverLaunchTemplateAMI(scope scope.LaunchTemplateScope) (*string, error) {
+	imageID, err := s.discoverLaunchTemplateAMI(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateImagePlatform(*imageID, scope.GetOS()); err != nil {
+		return nil, err
+	}
+
+	return imageID, nil
+}
+
+func (s *Service) discoverLaunchTemplateAMI(scope scope.LaunchTemplateScope) (*string, error) {
 	lt := scope.GetLaunchTemplate()
 
 	if lt.AMI.ID != nil {
 		return lt.AMI.ID, nil
 	}
 
-	templateVersion := scope.GetMachinePool().Spec.Template.Spec.Version
+	// The AMI version override of the pool takes precedence over the version of the MachinePool.
+	templateVersion, err := scope.GetAMILookupVersion()
+	if err != nil {
+		return nil, err
+	}
 	if templateVersion == nil {
 		err := errors.New("Either AWSMachinePool's spec.awslaunchtemplate.ami.id or MachinePool's spec.template.spec.version must be defined")
 		s.scope.Error(err, "")
@@ -802,7 +1092,6 @@ func (s *Service) DiscoverLaunchTemplateAMI(scope scope.LaunchTemplateScope) (*s
 	}
 
 	var lookupAMI string
-	var err error
 
 	imageLookupFormat := lt.ImageLookupFormat
 	if imageLookupFormat == "" {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/base64"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -38,6 +39,7 @@ import (
 	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/fakeaws"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/ssm/mock_ssmiface"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/userdata"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/test/mocks"
@@ -713,6 +715,36 @@ func TestGetLaunchTemplateID(t *testing.T) {
 				g.Expect(launchTemplateID).Should(Equal("lt-12345"))
 			},
 		},
+		{
+			name:               "Should return launch template ID returned on the second page of results",
+			launchTemplateName: "foo",
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				m.DescribeLaunchTemplateVersionsWithContext(context.TODO(), gomock.Eq(&ec2.DescribeLaunchTemplateVersionsInput{
+					LaunchTemplateName: aws.String("foo"),
+					Versions:           []*string{aws.String("$Latest")},
+				})).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+					LaunchTemplateVersions: []*ec2.LaunchTemplateVersion{},
+					NextToken:              aws.String("next"),
+				}, nil)
+				m.DescribeLaunchTemplateVersionsWithContext(context.TODO(), gomock.Eq(&ec2.DescribeLaunchTemplateVersionsInput{
+					LaunchTemplateName: aws.String("foo"),
+					Versions:           []*string{aws.String("$Latest")},
+					NextToken:          aws.String("next"),
+				})).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+					LaunchTemplateVersions: []*ec2.LaunchTemplateVersion{
+						{
+							LaunchTemplateId:   aws.String("lt-12345"),
+							LaunchTemplateName: aws.String("foo"),
+							VersionNumber:      aws.Int64(1),
+						},
+					},
+				}, nil)
+			},
+			check: func(g *WithT, launchTemplateID string, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(launchTemplateID).Should(Equal("lt-12345"))
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -740,31 +772,29 @@ func TestGetLaunchTemplateID(t *testing.T) {
 }
 
 func TestDeleteLaunchTemplate(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
-
 	testCases := []struct {
-		name      string
-		versionID string
-		expect    func(m *mocks.MockEC2APIMockRecorder)
-		wantErr   bool
+		name    string
+		setup   func(f *fakeaws.EC2API) string
+		wantErr bool
 	}{
 		{
-			name:      "Should not return error if successfully deletes given launch template ID",
-			versionID: "1",
-			expect: func(m *mocks.MockEC2APIMockRecorder) {
-				m.DeleteLaunchTemplateWithContext(context.TODO(), gomock.Eq(&ec2.DeleteLaunchTemplateInput{
-					LaunchTemplateId: aws.String("1"),
-				})).Return(&ec2.DeleteLaunchTemplateOutput{}, nil)
+			name: "Should not return error if successfully deletes given launch template ID",
+			setup: func(f *fakeaws.EC2API) string {
+				return createFakeLaunchTemplate(f, "template", 1)
 			},
 		},
 		{
-			name:      "Should return error if failed to delete given launch template ID",
-			versionID: "1",
-			expect: func(m *mocks.MockEC2APIMockRecorder) {
-				m.DeleteLaunchTemplateWithContext(context.TODO(), gomock.Eq(&ec2.DeleteLaunchTemplateInput{
-					LaunchTemplateId: aws.String("1"),
-				})).Return(nil, awserrors.NewFailedDependency("dependency failure"))
+			name: "Should not return error if the launch template doesn't exist",
+			setup: func(f *fakeaws.EC2API) string {
+				return "lt-00000000000000000"
+			},
+		},
+		{
+			name: "Should return error if failed to delete given launch template ID",
+			setup: func(f *fakeaws.EC2API) string {
+				id := createFakeLaunchTemplate(f, "template", 1)
+				f.InjectError("DeleteLaunchTemplate", awserrors.NewFailedDependency("dependency failure"))
+				return id
 			},
 			wantErr: true,
 		},
@@ -779,18 +809,22 @@ func TestDeleteLaunchTemplate(t *testing.T) {
 
 			cs, err := setupClusterScope(client)
 			g.Expect(err).NotTo(HaveOccurred())
-			mockEC2Client := mocks.NewMockEC2API(mockCtrl)
+			ec2Fake := fakeaws.NewEC2API()
+			id := tc.setup(ec2Fake)
 
 			s := NewService(cs)
-			s.EC2Client = mockEC2Client
-			tc.expect(mockEC2Client.EXPECT())
+			s.EC2Client = ec2Fake
 
-			err = s.DeleteLaunchTemplate(tc.versionID)
+			err = s.DeleteLaunchTemplate(id)
 			if tc.wantErr {
 				g.Expect(err).To(HaveOccurred())
 				return
 			}
 			g.Expect(err).NotTo(HaveOccurred())
+
+			// The launch template must be gone once deleted.
+			_, err = s.GetLaunchTemplateLatestVersion(id)
+			g.Expect(err).To(HaveOccurred())
 		})
 	}
 }
@@ -1056,6 +1090,170 @@ func TestLaunchTemplateDataCreation(t *testing.T) {
 	})
 }
 
+func TestUserDataRegenerationRequested(t *testing.T) {
+	expiredAt := metav1.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		annotation *string
+		change     *expinfrav1.LaunchTemplateChange
+		want       bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:       "invalid annotation",
+			annotation: ptr.To("15m"),
+		},
+		{
+			name:       "no launch template version was recorded",
+			annotation: ptr.To("2024-01-01T12:00:00Z"),
+			want:       true,
+		},
+		{
+			name:       "latest launch template version created before the expiry",
+			annotation: ptr.To("2024-01-01T12:00:00Z"),
+			change:     &expinfrav1.LaunchTemplateChange{Time: metav1.NewTime(expiredAt.Add(-time.Hour))},
+			want:       true,
+		},
+		{
+			name:       "latest launch template version created after the expiry",
+			annotation: ptr.To("2024-01-01T12:00:00Z"),
+			change:     &expinfrav1.LaunchTemplateChange{Time: metav1.NewTime(expiredAt.Add(time.Minute))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme, err := setupScheme()
+			g.Expect(err).NotTo(HaveOccurred())
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			cs, err := setupClusterScope(client)
+			g.Expect(err).NotTo(HaveOccurred())
+			ms, err := setupMachinePoolScope(client, cs)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			if tt.annotation != nil {
+				ms.AWSMachinePool.Annotations = map[string]string{infrav1.BootstrapTokenExpiredAnnotation: *tt.annotation}
+			}
+			ms.AWSMachinePool.Status.LastLaunchTemplateChange = tt.change
+
+			g.Expect(userDataRegenerationRequested(ms)).To(Equal(tt.want))
+		})
+	}
+}
+
+// TestLaunchTemplateDataMachineSettings checks that the launch templates of the AWSMachinePools resolve the SSH key
+// and the metadata options the same way as the instances of the AWSMachines.
+func TestLaunchTemplateDataMachineSettings(t *testing.T) {
+	clusterMetadataOptions := &infrav1.InstanceMetadataOptions{
+		HTTPEndpoint:            infrav1.InstanceMetadataEndpointStateEnabled,
+		HTTPPutResponseHopLimit: 2,
+		HTTPTokens:              infrav1.HTTPTokensStateRequired,
+		InstanceMetadataTags:    infrav1.InstanceMetadataEndpointStateDisabled,
+	}
+	machineMetadataOptions := &infrav1.InstanceMetadataOptions{
+		HTTPEndpoint:            infrav1.InstanceMetadataEndpointStateEnabled,
+		HTTPPutResponseHopLimit: 1,
+		HTTPTokens:              infrav1.HTTPTokensStateOptional,
+		InstanceMetadataTags:    infrav1.InstanceMetadataEndpointStateEnabled,
+	}
+
+	tests := []struct {
+		name                   string
+		clusterSSHKeyName      *string
+		clusterMetadataOptions *infrav1.InstanceMetadataOptions
+		machineSSHKeyName      *string
+		machineMetadataOptions *infrav1.InstanceMetadataOptions
+		wantSSHKeyName         *string
+		wantMetadataOptions    *infrav1.InstanceMetadataOptions
+	}{
+		{
+			name: "neither the cluster nor the machine set the settings",
+		},
+		{
+			name:                   "the settings of the cluster are used by default",
+			clusterSSHKeyName:      aws.String("cluster-key"),
+			clusterMetadataOptions: clusterMetadataOptions,
+			wantSSHKeyName:         aws.String("cluster-key"),
+			wantMetadataOptions:    clusterMetadataOptions,
+		},
+		{
+			name:                   "the settings of the machine take precedence over the ones of the cluster",
+			clusterSSHKeyName:      aws.String("cluster-key"),
+			clusterMetadataOptions: clusterMetadataOptions,
+			machineSSHKeyName:      aws.String("machine-key"),
+			machineMetadataOptions: machineMetadataOptions,
+			wantSSHKeyName:         aws.String("machine-key"),
+			wantMetadataOptions:    machineMetadataOptions,
+		},
+		{
+			name:              "an empty SSH key name of the machine disables the SSH key of the cluster",
+			clusterSSHKeyName: aws.String("cluster-key"),
+			machineSSHKeyName: aws.String(""),
+			wantSSHKeyName:    aws.String(""),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme, err := setupScheme()
+			g.Expect(err).NotTo(HaveOccurred())
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			cs, err := setupClusterScope(client)
+			g.Expect(err).NotTo(HaveOccurred())
+			cs.AWSCluster.Spec.SSHKeyName = tt.clusterSSHKeyName
+			cs.AWSCluster.Spec.DefaultInstanceMetadataOptions = tt.clusterMetadataOptions
+
+			mps, err := setupMachinePoolScope(client, cs)
+			g.Expect(err).NotTo(HaveOccurred())
+			mps.AWSMachinePool.Spec.AWSLaunchTemplate.SSHKeyName = tt.machineSSHKeyName
+			mps.AWSMachinePool.Spec.AWSLaunchTemplate.InstanceMetadataOptions = tt.machineMetadataOptions
+
+			ms, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:       client,
+				Cluster:      newCluster(),
+				Machine:      &clusterv1.Machine{},
+				InfraCluster: cs,
+				AWSMachine: &infrav1.AWSMachine{
+					Spec: infrav1.AWSMachineSpec{
+						SSHKeyName:              tt.machineSSHKeyName,
+						InstanceMetadataOptions: tt.machineMetadataOptions,
+					},
+				},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			// The instances of the AWSMachines are launched with the settings of the machine scope.
+			g.Expect(ms.SSHKeyName()).To(Equal(tt.wantSSHKeyName))
+			g.Expect(ms.InstanceMetadataOptions()).To(Equal(tt.wantMetadataOptions))
+
+			s := NewService(cs)
+			data, err := s.createLaunchTemplateData(mps, aws.String("imageID"), types.NamespacedName{}, nil)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			if aws.StringValue(tt.wantSSHKeyName) == "" {
+				g.Expect(data.KeyName).To(BeNil())
+			} else {
+				g.Expect(data.KeyName).To(Equal(tt.wantSSHKeyName))
+			}
+			if tt.wantMetadataOptions == nil {
+				g.Expect(data.MetadataOptions).To(BeNil())
+			} else {
+				g.Expect(data.MetadataOptions).To(Equal(&ec2.LaunchTemplateInstanceMetadataOptionsRequest{
+					HttpEndpoint:            aws.String(string(tt.wantMetadataOptions.HTTPEndpoint)),
+					HttpPutResponseHopLimit: aws.Int64(tt.wantMetadataOptions.HTTPPutResponseHopLimit),
+					HttpTokens:              aws.String(string(tt.wantMetadataOptions.HTTPTokens)),
+					InstanceMetadataTags:    aws.String(string(tt.wantMetadataOptions.InstanceMetadataTags)),
+				}))
+			}
+		})
+	}
+}
+
 func TestCreateLaunchTemplateVersion(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -1271,6 +1469,7 @@ func TestDiscoverLaunchTemplateAMI(t *testing.T) {
 		name              string
 		awsLaunchTemplate expinfrav1.AWSLaunchTemplate
 		machineTemplate   clusterv1.MachineTemplateSpec
+		os                infrav1.OSType
 		expect            func(m *mocks.MockEC2APIMockRecorder)
 		check             func(*WithT, *string, error)
 	}{
@@ -1487,6 +1686,63 @@ func TestDiscoverLaunchTemplateAMI(t *testing.T) {
 				g.Expect(err).To(HaveOccurred())
 			},
 		},
+		{
+			name: "Should return AMI if its platform matches the operating system of the pool",
+			awsLaunchTemplate: expinfrav1.AWSLaunchTemplate{
+				Name: "aws-launch-tmpl",
+				AMI:  infrav1.AMIReference{ID: aws.String("ami-windows")},
+			},
+			os: infrav1.OSTypeWindows,
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				m.DescribeImagesWithContext(context.TODO(), gomock.Eq(&ec2.DescribeImagesInput{
+					ImageIds: aws.StringSlice([]string{"ami-windows"}),
+				})).Return(&ec2.DescribeImagesOutput{
+					Images: []*ec2.Image{{ImageId: aws.String("ami-windows"), Platform: aws.String("windows")}},
+				}, nil)
+			},
+			check: func(g *WithT, res *string, err error) {
+				g.Expect(res).Should(Equal(aws.String("ami-windows")))
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
+		{
+			name: "Should return error if a windows pool uses a linux AMI",
+			awsLaunchTemplate: expinfrav1.AWSLaunchTemplate{
+				Name: "aws-launch-tmpl",
+				AMI:  infrav1.AMIReference{ID: aws.String("ami-linux")},
+			},
+			os: infrav1.OSTypeWindows,
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				m.DescribeImagesWithContext(context.TODO(), gomock.Eq(&ec2.DescribeImagesInput{
+					ImageIds: aws.StringSlice([]string{"ami-linux"}),
+				})).Return(&ec2.DescribeImagesOutput{
+					Images: []*ec2.Image{{ImageId: aws.String("ami-linux")}},
+				}, nil)
+			},
+			check: func(g *WithT, res *string, err error) {
+				g.Expect(res).To(BeNil())
+				g.Expect(err).To(HaveOccurred())
+			},
+		},
+		{
+			name: "Should return error if a linux pool uses a windows AMI",
+			awsLaunchTemplate: expinfrav1.AWSLaunchTemplate{
+				Name: "aws-launch-tmpl",
+				AMI:  infrav1.AMIReference{ID: aws.String("ami-windows")},
+			},
+			os: infrav1.OSTypeLinux,
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				m.DescribeImagesWithContext(context.TODO(), gomock.Eq(&ec2.DescribeImagesInput{
+					ImageIds: aws.StringSlice([]string{"ami-windows"}),
+				})).Return(&ec2.DescribeImagesOutput{
+					Images: []*ec2.Image{{ImageId: aws.String("ami-windows"), Platform: aws.String("windows")}},
+				}, nil)
+			},
+			check: func(g *WithT, res *string, err error) {
+				g.Expect(res).To(BeNil())
+				g.Expect(err).To(HaveOccurred())
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -1506,6 +1762,7 @@ func TestDiscoverLaunchTemplateAMI(t *testing.T) {
 
 			ms.AWSMachinePool.Spec.AWSLaunchTemplate = tc.awsLaunchTemplate
 			ms.MachinePool.Spec.Template = tc.machineTemplate
+			ms.AWSMachinePool.Spec.OS = tc.os
 
 			if tc.expect != nil {
 				tc.expect(ec2Mock.EXPECT())
@@ -1598,53 +1855,133 @@ func TestDiscoverLaunchTemplateAMIForEKS(t *testing.T) {
 }
 
 func TestDeleteLaunchTemplateVersion(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
+	testCases := []struct {
+		name     string
+		version  *int64
+		setup    func(f *fakeaws.EC2API)
+		wantErr  bool
+		versions []int64
+	}{
+		{
+			name:     "Should return error if version is nil",
+			wantErr:  true,
+			versions: []int64{1, 2, 3},
+		},
+		{
+			name:    "Should return error if AWS unable to delete launch template version",
+			version: aws.Int64(2),
+			setup: func(f *fakeaws.EC2API) {
+				f.InjectError("DeleteLaunchTemplateVersions", awserrors.NewFailedDependency("dependency-failure"))
+			},
+			wantErr:  true,
+			versions: []int64{1, 2, 3},
+		},
+		{
+			name:     "Should successfully deletes launch template version if AWS call passed",
+			version:  aws.Int64(2),
+			versions: []int64{1, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme, err := setupScheme()
+			g.Expect(err).NotTo(HaveOccurred())
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			cs, err := setupClusterScope(client)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			ec2Fake := fakeaws.NewEC2API()
+			id := createFakeLaunchTemplate(ec2Fake, "template", 3)
+			if tc.setup != nil {
+				tc.setup(ec2Fake)
+			}
+			s := NewService(cs)
+			s.EC2Client = ec2Fake
 
-	type args struct {
-		id      string
-		version *int64
+			err = s.deleteLaunchTemplateVersion(id, tc.version)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(fakeLaunchTemplateVersions(ec2Fake, id)).To(Equal(tc.versions))
+		})
 	}
+}
+
+func TestPruneLaunchTemplateVersions(t *testing.T) {
 	testCases := []struct {
-		name    string
-		args    args
-		expect  func(m *mocks.MockEC2APIMockRecorder)
-		wantErr bool
+		name     string
+		versions int
+		retain   int64
+		setup    func(f *fakeaws.EC2API, id string)
+		wantErr  bool
+		want     []int64
 	}{
 		{
-			name:    "Should return error if version is nil",
-			wantErr: true,
+			name:     "Should not prune the only version",
+			versions: 1,
+			retain:   1,
+			want:     []int64{1},
 		},
 		{
-			name: "Should return error if AWS unable to delete launch template version",
-			args: args{
-				id:      "id",
-				version: aws.Int64(12),
-			},
-			expect: func(m *mocks.MockEC2APIMockRecorder) {
-				m.DeleteLaunchTemplateVersionsWithContext(context.TODO(), gomock.Eq(
-					&ec2.DeleteLaunchTemplateVersionsInput{
-						LaunchTemplateId: aws.String("id"),
-						Versions:         aws.StringSlice([]string{"12"}),
-					},
-				)).Return(nil, awserrors.NewFailedDependency("dependency-failure"))
-			},
-			wantErr: true,
+			name:     "Should not prune the default and the latest versions",
+			versions: 2,
+			retain:   1,
+			want:     []int64{1, 2},
 		},
 		{
-			name: "Should successfully deletes launch template version if AWS call passed",
-			args: args{
-				id:      "id",
-				version: aws.Int64(12),
+			name:     "Should prune the versions that are neither the default nor the latest",
+			versions: 4,
+			retain:   1,
+			want:     []int64{1, 4},
+		},
+		{
+			name:     "Should retain the most recent versions",
+			versions: 6,
+			retain:   3,
+			want:     []int64{1, 4, 5, 6},
+		},
+		{
+			name:     "Should always retain the latest version",
+			versions: 3,
+			retain:   0,
+			want:     []int64{1, 3},
+		},
+		{
+			name:     "Should prune the remaining versions when versions were deleted out of band",
+			versions: 5,
+			retain:   2,
+			setup: func(f *fakeaws.EC2API, id string) {
+				_, err := f.DeleteLaunchTemplateVersionsWithContext(context.TODO(), &ec2.DeleteLaunchTemplateVersionsInput{
+					LaunchTemplateId: aws.String(id),
+					Versions:         aws.StringSlice([]string{"2"}),
+				})
+				if err != nil {
+					panic(err)
+				}
 			},
-			expect: func(m *mocks.MockEC2APIMockRecorder) {
-				m.DeleteLaunchTemplateVersionsWithContext(context.TODO(), gomock.Eq(
-					&ec2.DeleteLaunchTemplateVersionsInput{
-						LaunchTemplateId: aws.String("id"),
-						Versions:         aws.StringSlice([]string{"12"}),
-					},
-				)).Return(nil, nil)
+			want: []int64{1, 4, 5},
+		},
+		{
+			name:     "Should prune a bounded number of versions at a time",
+			versions: maxLaunchTemplateVersionsToPrune + 5,
+			retain:   1,
+			want:     []int64{1, maxLaunchTemplateVersionsToPrune + 2, maxLaunchTemplateVersionsToPrune + 3, maxLaunchTemplateVersionsToPrune + 4, maxLaunchTemplateVersionsToPrune + 5},
+		},
+		{
+			name:     "Should return error if AWS unable to describe launch template versions",
+			versions: 3,
+			retain:   1,
+			setup: func(f *fakeaws.EC2API, _ string) {
+				f.InjectError("DescribeLaunchTemplateVersions", awserrors.NewFailedDependency("dependency-failure"))
 			},
+			wantErr: true,
+			want:    []int64{1, 2, 3},
 		},
 	}
 
@@ -1659,19 +1996,119 @@ func TestDeleteLaunchTemplateVersion(t *testing.T) {
 			cs, err := setupClusterScope(client)
 			g.Expect(err).NotTo(HaveOccurred())
 
-			ec2Mock := mocks.NewMockEC2API(mockCtrl)
+			ec2Fake := fakeaws.NewEC2API()
+			id := createFakeLaunchTemplate(ec2Fake, "template", tc.versions)
+			if tc.setup != nil {
+				tc.setup(ec2Fake, id)
+			}
 			s := NewService(cs)
-			s.EC2Client = ec2Mock
+			s.EC2Client = ec2Fake
 
-			if tc.expect != nil {
-				tc.expect(ec2Mock.EXPECT())
+			err = s.pruneLaunchTemplateVersions(id, tc.retain)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
 			}
+			g.Expect(fakeLaunchTemplateVersions(ec2Fake, id)).To(Equal(tc.want))
+		})
+	}
+}
 
-			if tc.wantErr {
-				g.Expect(s.deleteLaunchTemplateVersion(tc.args.id, tc.args.version)).To(HaveOccurred())
-				return
+// TestPruneLaunchTemplateVersionsCallCount compares the requests made to prune a launch template which
+// accumulated thousands of versions with the requests needed to page through all of its versions.
+func TestPruneLaunchTemplateVersionsCallCount(t *testing.T) {
+	const versions = 2000
+
+	g := NewWithT(t)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	cs, err := setupClusterScope(client)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ec2Fake := fakeaws.NewEC2API()
+	id := createFakeLaunchTemplate(ec2Fake, "template", versions)
+	s := NewService(cs)
+	s.EC2Client = ec2Fake
+
+	countCalls := func(f func()) map[string]int {
+		before := len(ec2Fake.Calls())
+		f()
+		calls := map[string]int{}
+		for _, call := range ec2Fake.Calls()[before:] {
+			calls[call]++
+		}
+		return calls
+	}
+
+	// Paging through all the versions, at most 200 per page, takes a request per page.
+	listCalls := countCalls(func() {
+		input := &ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateId: aws.String(id), MaxResults: aws.Int64(200)}
+		for {
+			out, err := ec2Fake.DescribeLaunchTemplateVersionsWithContext(context.TODO(), input)
+			g.Expect(err).NotTo(HaveOccurred())
+			if out.NextToken == nil {
+				break
 			}
-			g.Expect(s.deleteLaunchTemplateVersion(tc.args.id, tc.args.version)).NotTo(HaveOccurred())
+			input.NextToken = out.NextToken
+		}
+	})
+	g.Expect(listCalls).To(Equal(map[string]int{"DescribeLaunchTemplateVersions": versions / 200}))
+
+	// Pruning takes the same requests whatever the number of versions.
+	pruneCalls := countCalls(func() {
+		g.Expect(s.pruneLaunchTemplateVersions(id, 1)).To(Succeed())
+	})
+	g.Expect(pruneCalls).To(Equal(map[string]int{"DescribeLaunchTemplateVersions": 2, "DeleteLaunchTemplateVersions": 1}))
+	t.Logf("%d versions: %d requests to list them, %d requests to prune them", versions, listCalls["DescribeLaunchTemplateVersions"], pruneCalls["DescribeLaunchTemplateVersions"]+pruneCalls["DeleteLaunchTemplateVersions"])
+
+	// The backlog of versions shrinks with each prune, down to the default and the latest versions.
+	for i := 0; i < versions/maxLaunchTemplateVersionsToPrune; i++ {
+		g.Expect(s.pruneLaunchTemplateVersions(id, 1)).To(Succeed())
+	}
+	g.Expect(fakeLaunchTemplateVersions(ec2Fake, id)).To(Equal([]int64{1, versions}))
+	g.Expect(countCalls(func() {
+		g.Expect(s.pruneLaunchTemplateVersions(id, 1)).To(Succeed())
+	})).To(Equal(map[string]int{"DescribeLaunchTemplateVersions": 2}))
+}
+
+// createFakeLaunchTemplate creates a launch template with the given number of versions in the fake
+// EC2 client and returns its ID.
+func createFakeLaunchTemplate(f *fakeaws.EC2API, name string, versions int) string {
+	out, err := f.CreateLaunchTemplateWithContext(context.TODO(), &ec2.CreateLaunchTemplateInput{
+		LaunchTemplateName: aws.String(name),
+		LaunchTemplateData: &ec2.RequestLaunchTemplateData{ImageId: aws.String("ami-1")},
+	})
+	if err != nil {
+		panic(err)
+	}
+	id := aws.StringValue(out.LaunchTemplate.LaunchTemplateId)
+	for i := 1; i < versions; i++ {
+		_, err := f.CreateLaunchTemplateVersionWithContext(context.TODO(), &ec2.CreateLaunchTemplateVersionInput{
+			LaunchTemplateId:   aws.String(id),
+			LaunchTemplateData: &ec2.RequestLaunchTemplateData{ImageId: aws.String("ami-1")},
 		})
+		if err != nil {
+			panic(err)
+		}
+	}
+	return id
+}
+
+// fakeLaunchTemplateVersions returns the version numbers of a launch template in the fake EC2 client.
+func fakeLaunchTemplateVersions(f *fakeaws.EC2API, id string) []int64 {
+	out, err := f.DescribeLaunchTemplateVersionsWithContext(context.TODO(), &ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateId: aws.String(id),
+	})
+	if err != nil {
+		panic(err)
+	}
+	versions := []int64{}
+	for _, v := range out.LaunchTemplateVersions {
+		versions = append(versions, aws.Int64Value(v.VersionNumber))
 	}
+	return versions
 }
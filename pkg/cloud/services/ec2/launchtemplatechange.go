@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"fmt"
+	"sort"
+
+	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+)
+
+const (
+	// maxLaunchTemplateChanges is the maximum number of changes recorded for a launch template version.
+	maxLaunchTemplateChanges = 10
+
+	// maxLaunchTemplateChangeLength is the maximum length of a recorded change.
+	maxLaunchTemplateChangeLength = 200
+
+	// userDataHashLength is the length of the prefix of the user data hashes shown in the changes.
+	userDataHashLength = 12
+
+	unsetValue = "<unset>"
+)
+
+// launchTemplateState is the state of a launch template version which is compared to describe the
+// changes of a new version.
+type launchTemplateState struct {
+	template          *expinfrav1.AWSLaunchTemplate
+	imageID           string
+	userDataHash      string
+	userDataSecretKey *apimachinerytypes.NamespacedName
+}
+
+// launchTemplateChanges returns the human-readable changes between the existing and the incoming
+// state of a launch template. The user data, which comes from a secret, is only represented by its
+// hash, and the tags only by their keys. needsUpdate is the result of LaunchTemplateNeedsUpdate,
+// whose comparison of the security groups can't be repeated without calling the EC2 API.
+func launchTemplateChanges(existing, incoming launchTemplateState, needsUpdate bool, lastAppliedTags map[string]interface{}, tags map[string]string) []string {
+	changes := []string{}
+	addChange := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", field, from, to))
+		}
+	}
+
+	addChange("AMI", existing.imageID, incoming.imageID)
+
+	// The fields compared by LaunchTemplateNeedsUpdate. If none of the others changed, the security groups did.
+	compared := len(changes)
+	addChange("instanceType", existing.template.InstanceType, incoming.template.InstanceType)
+	addChange("iamInstanceProfile", valueOrUnset(existing.template.IamInstanceProfile), valueOrUnset(incoming.template.IamInstanceProfile))
+	addChange("instanceMetadataOptions", instanceMetadataOptionsString(existing.template.InstanceMetadataOptions), instanceMetadataOptionsString(incoming.template.InstanceMetadataOptions))
+	if needsUpdate && len(changes) == compared {
+		changes = append(changes, "securityGroups changed")
+	}
+
+	addChange("userDataHash", valueOrUnset(shortHash(existing.userDataHash)), valueOrUnset(shortHash(incoming.userDataHash)))
+	if existing.userDataSecretKey != nil {
+		addChange("userDataSecret", existing.userDataSecretKey.String(), incoming.userDataSecretKey.String())
+	}
+
+	changes = append(changes, tagChanges(lastAppliedTags, tags)...)
+
+	return boundChanges(changes)
+}
+
+// tagChanges returns the keys of the additional tags which were added, changed or removed since
+// they were last applied.
+func tagChanges(lastAppliedTags map[string]interface{}, tags map[string]string) []string {
+	changes := []string{}
+	for k, v := range tags {
+		last, ok := lastAppliedTags[k]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("additionalTags[%s] added", k))
+		case last != v:
+			changes = append(changes, fmt.Sprintf("additionalTags[%s] changed", k))
+		}
+	}
+	for k := range lastAppliedTags {
+		if _, ok := tags[k]; !ok {
+			changes = append(changes, fmt.Sprintf("additionalTags[%s] removed", k))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// boundChanges limits the number and the length of the changes, so that they fit in the status
+// and in an event.
+func boundChanges(changes []string) []string {
+	if len(changes) > maxLaunchTemplateChanges {
+		more := len(changes) - maxLaunchTemplateChanges + 1
+		changes = append(changes[:maxLaunchTemplateChanges-1], fmt.Sprintf("... and %d more changes", more))
+	}
+	for i, c := range changes {
+		if len(c) > maxLaunchTemplateChangeLength {
+			changes[i] = c[:maxLaunchTemplateChangeLength-3] + "..."
+		}
+	}
+	return changes
+}
+
+func instanceMetadataOptionsString(o *infrav1.InstanceMetadataOptions) string {
+	if o == nil {
+		return unsetValue
+	}
+	return fmt.Sprintf("{httpEndpoint: %s, httpPutResponseHopLimit: %d, httpTokens: %s, instanceMetadataTags: %s}",
+		o.HTTPEndpoint, o.HTTPPutResponseHopLimit, o.HTTPTokens, o.InstanceMetadataTags)
+}
+
+func shortHash(hash string) string {
+	if len(hash) > userDataHashLength {
+		return hash[:userDataHashLength]
+	}
+	return hash
+}
+
+func valueOrUnset(v string) string {
+	if v == "" {
+		return unsetValue
+	}
+	return v
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+)
+
+func TestLaunchTemplateChanges(t *testing.T) {
+	existing := launchTemplateState{
+		template: &expinfrav1.AWSLaunchTemplate{
+			InstanceType: "t3.large",
+		},
+		imageID:           "ami-123",
+		userDataHash:      "0123456789abcdef0123456789abcdef",
+		userDataSecretKey: &types.NamespacedName{Namespace: "default", Name: "bootstrap-1"},
+	}
+
+	testCases := []struct {
+		name            string
+		incoming        launchTemplateState
+		needsUpdate     bool
+		lastAppliedTags map[string]interface{}
+		tags            map[string]string
+		expected        []string
+	}{
+		{
+			name:     "no changes",
+			incoming: existing,
+			expected: []string{},
+		},
+		{
+			name: "AMI, instance type and user data changed",
+			incoming: launchTemplateState{
+				template: &expinfrav1.AWSLaunchTemplate{
+					InstanceType: "t3.xlarge",
+				},
+				imageID:           "ami-456",
+				userDataHash:      "fedcba9876543210fedcba9876543210",
+				userDataSecretKey: &types.NamespacedName{Namespace: "default", Name: "bootstrap-2"},
+			},
+			needsUpdate: true,
+			expected: []string{
+				"AMI: ami-123 -> ami-456",
+				"instanceType: t3.large -> t3.xlarge",
+				"userDataHash: 0123456789ab -> fedcba987654",
+				"userDataSecret: default/bootstrap-1 -> default/bootstrap-2",
+			},
+		},
+		{
+			name: "instance metadata options and IAM instance profile changed",
+			incoming: launchTemplateState{
+				template: &expinfrav1.AWSLaunchTemplate{
+					InstanceType:       "t3.large",
+					IamInstanceProfile: "nodes",
+					InstanceMetadataOptions: &infrav1.InstanceMetadataOptions{
+						HTTPEndpoint:            infrav1.InstanceMetadataEndpointStateEnabled,
+						HTTPPutResponseHopLimit: 2,
+						HTTPTokens:              infrav1.HTTPTokensStateRequired,
+						InstanceMetadataTags:    infrav1.InstanceMetadataEndpointStateDisabled,
+					},
+				},
+				imageID:           "ami-123",
+				userDataHash:      existing.userDataHash,
+				userDataSecretKey: existing.userDataSecretKey,
+			},
+			needsUpdate: true,
+			expected: []string{
+				"iamInstanceProfile: <unset> -> nodes",
+				"instanceMetadataOptions: <unset> -> {httpEndpoint: enabled, httpPutResponseHopLimit: 2, httpTokens: required, instanceMetadataTags: disabled}",
+			},
+		},
+		{
+			name: "security groups changed",
+			incoming: launchTemplateState{
+				template:          existing.template,
+				imageID:           "ami-456",
+				userDataHash:      existing.userDataHash,
+				userDataSecretKey: existing.userDataSecretKey,
+			},
+			needsUpdate: true,
+			expected: []string{
+				"AMI: ami-123 -> ami-456",
+				"securityGroups changed",
+			},
+		},
+		{
+			name:            "only the keys of the tags are listed",
+			incoming:        existing,
+			lastAppliedTags: map[string]interface{}{"changed": "a", "removed": "b", "kept": "c"},
+			tags:            map[string]string{"changed": "secret", "added": "d", "kept": "c"},
+			expected: []string{
+				"additionalTags[added] added",
+				"additionalTags[changed] changed",
+				"additionalTags[removed] removed",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(launchTemplateChanges(existing, tc.incoming, tc.needsUpdate, tc.lastAppliedTags, tc.tags)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestBoundChanges(t *testing.T) {
+	g := NewWithT(t)
+
+	changes := []string{strings.Repeat("a", 300)}
+	for i := 0; i < 14; i++ {
+		changes = append(changes, fmt.Sprintf("change %d", i))
+	}
+
+	bounded := boundChanges(changes)
+	g.Expect(bounded).To(HaveLen(maxLaunchTemplateChanges))
+	g.Expect(bounded[0]).To(HaveLen(maxLaunchTemplateChangeLength))
+	g.Expect(bounded[0]).To(HaveSuffix("..."))
+	g.Expect(bounded[maxLaunchTemplateChanges-1]).To(Equal("... and 6 more changes"))
+}
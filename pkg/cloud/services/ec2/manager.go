@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+)
+
+// LaunchTemplateManager manages existing launch templates and their versions, without the scopes of the CAPA
+// controllers.
+//
+// LaunchTemplateManager is a stable interface for the projects embedding the services: within a major version of
+// CAPA, methods are only added to it, never changed or removed.
+type LaunchTemplateManager interface {
+	GetLaunchTemplate(name string) (lt *expinfrav1.AWSLaunchTemplate, userDataHash string, userDataSecretKey *apimachinerytypes.NamespacedName, err error)
+	GetLaunchTemplateID(name string) (string, error)
+	GetLaunchTemplateLatestVersion(id string) (string, error)
+	GetLaunchTemplateVersion(id, version string) (string, error)
+	PruneLaunchTemplateVersions(id string) error
+	DeleteLaunchTemplate(id string) error
+}
+
+var _ LaunchTemplateManager = &Service{}
+
+// NewLaunchTemplateManager returns a LaunchTemplateManager using the session and the cluster name of the parameters.
+func NewLaunchTemplateManager(params scope.ServiceScopeParams) (LaunchTemplateManager, error) {
+	serviceScope, err := scope.NewServiceScope(params)
+	if err != nil {
+		return nil, err
+	}
+	return NewService(serviceScope), nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/fakeaws"
+)
+
+func TestNewLaunchTemplateManager(t *testing.T) {
+	g := NewWithT(t)
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = NewLaunchTemplateManager(scope.ServiceScopeParams{ClusterName: "test-cluster"})
+	g.Expect(err).To(MatchError(ContainSubstring("session is required")))
+
+	manager, err := NewLaunchTemplateManager(scope.ServiceScopeParams{
+		Session:     sess,
+		ClusterName: "test-cluster",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The AWS API is faked, the manager is otherwise used as by any project embedding it.
+	ec2Fake := fakeaws.NewEC2API()
+	manager.(*Service).EC2Client = ec2Fake
+
+	out, err := ec2Fake.CreateLaunchTemplateWithContext(context.TODO(), &ec2.CreateLaunchTemplateInput{
+		LaunchTemplateName: aws.String("lt"),
+		LaunchTemplateData: &ec2.RequestLaunchTemplateData{InstanceType: aws.String("t3.large")},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	id := aws.StringValue(out.LaunchTemplate.LaunchTemplateId)
+
+	g.Expect(manager.GetLaunchTemplateID("lt")).To(Equal(id))
+	g.Expect(manager.GetLaunchTemplateLatestVersion(id)).To(Equal("1"))
+
+	g.Expect(manager.DeleteLaunchTemplate(id)).To(Succeed())
+	g.Expect(manager.GetLaunchTemplateID("lt")).To(BeEmpty())
+}
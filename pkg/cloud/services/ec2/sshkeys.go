@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+)
+
+// SSHKeyCheckInterval is the interval during which the result of checking that an SSH key pair exists is
+// reused for a cluster, before the key pair is described again.
+const SSHKeyCheckInterval = 5 * time.Minute
+
+// SSHKeyNotFoundError is returned when the SSH key pair configured for a cluster or a machine doesn't exist
+// in the region of the cluster.
+type SSHKeyNotFoundError struct {
+	// KeyName is the name of the SSH key pair.
+	KeyName string
+
+	// Region is the region the SSH key pair was looked up in.
+	Region string
+}
+
+func (e *SSHKeyNotFoundError) Error() string {
+	return fmt.Sprintf("SSH key pair %q not found in region %s", e.KeyName, e.Region)
+}
+
+// IsSSHKeyNotFound returns true if the error, or an error it wraps, is an SSHKeyNotFoundError.
+func IsSSHKeyNotFound(err error) bool {
+	var notFound *SSHKeyNotFoundError
+	return errors.As(err, &notFound)
+}
+
+type sshKeyCacheKey struct {
+	cluster string
+	region  string
+	keyName string
+}
+
+type sshKeyCacheEntry struct {
+	found     bool
+	checkedAt time.Time
+}
+
+// sshKeyCache caches whether the SSH key pairs of the clusters exist, so that the key pairs are described once
+// per cluster per SSHKeyCheckInterval, however many machines use them. It is safe for concurrent use, so that
+// it can be shared by the services of the controllers.
+type sshKeyCache struct {
+	mu      sync.Mutex
+	entries map[sshKeyCacheKey]sshKeyCacheEntry
+
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+}
+
+func newSSHKeyCache() *sshKeyCache {
+	return &sshKeyCache{
+		entries: map[sshKeyCacheKey]sshKeyCacheEntry{},
+		now:     time.Now,
+	}
+}
+
+// get returns whether the SSH key pair exists, and whether it was checked less than SSHKeyCheckInterval ago.
+func (c *sshKeyCache) get(key sshKeyCacheKey) (found, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || c.now().Sub(entry.checkedAt) >= SSHKeyCheckInterval {
+		delete(c.entries, key)
+		return false, false
+	}
+	return entry.found, true
+}
+
+func (c *sshKeyCache) set(key sshKeyCacheKey, found bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = sshKeyCacheEntry{found: found, checkedAt: c.now()}
+}
+
+var sshKeys = newSSHKeyCache()
+
+// CheckSSHKey returns an SSHKeyNotFoundError if the SSH key pair doesn't exist in the region of the cluster.
+// The result is cached for the cluster during SSHKeyCheckInterval, so that a key pair created after it was
+// reported missing is found again once the interval elapsed.
+func (s *Service) CheckSSHKey(keyName string) error {
+	key := sshKeyCacheKey{
+		cluster: s.scope.Namespace() + "/" + s.scope.Name(),
+		region:  s.scope.Region(),
+		keyName: keyName,
+	}
+
+	found, ok := sshKeys.get(key)
+	if !ok {
+		_, err := s.EC2Client.DescribeKeyPairsWithContext(context.TODO(), &ec2.DescribeKeyPairsInput{
+			KeyNames: aws.StringSlice([]string{keyName}),
+		})
+		if err != nil {
+			if code, _ := awserrors.Code(errors.Cause(err)); code != awserrors.KeyPairNotFound {
+				record.Warnf(s.scope.InfraCluster(), "FailedDescribeKeyPairs", "Failed to describe SSH key pair %q: %v", keyName, err)
+				return errors.Wrapf(err, "failed to describe SSH key pair %q", keyName)
+			}
+		}
+		found = err == nil
+		sshKeys.set(key, found)
+	}
+
+	if !found {
+		return &SSHKeyNotFoundError{KeyName: keyName, Region: s.scope.Region()}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/test/mocks"
+)
+
+func TestCheckSSHKey(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	now := time.Now()
+	sshKeys = newSSHKeyCache()
+	sshKeys.now = func() time.Time { return now }
+	defer func() { sshKeys = newSSHKeyCache() }()
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+	clusterScope, err := setupClusterScope(fake.NewClientBuilder().WithScheme(scheme).Build())
+	g.Expect(err).NotTo(HaveOccurred())
+	clusterScope.AWSCluster.Spec.Region = "us-east-1"
+
+	ec2Mock := mocks.NewMockEC2API(mockCtrl)
+	s := NewService(clusterScope)
+	s.EC2Client = ec2Mock
+
+	input := &ec2.DescribeKeyPairsInput{KeyNames: aws.StringSlice([]string{"my-key"})}
+	notFound := awserr.New(awserrors.KeyPairNotFound, "The key pair 'my-key' does not exist", nil)
+
+	// A missing key pair is described once during the check interval.
+	ec2Mock.EXPECT().DescribeKeyPairsWithContext(gomock.Any(), input).Return(nil, notFound).Times(1)
+	err = s.CheckSSHKey("my-key")
+	g.Expect(IsSSHKeyNotFound(err)).To(BeTrue())
+	g.Expect(err).To(MatchError(`SSH key pair "my-key" not found in region us-east-1`))
+	now = now.Add(SSHKeyCheckInterval - time.Second)
+	g.Expect(IsSSHKeyNotFound(s.CheckSSHKey("my-key"))).To(BeTrue())
+
+	// Once the interval elapsed, a key pair created in the meantime is found.
+	now = now.Add(time.Second)
+	ec2Mock.EXPECT().DescribeKeyPairsWithContext(gomock.Any(), input).Return(&ec2.DescribeKeyPairsOutput{
+		KeyPairs: []*ec2.KeyPairInfo{{KeyName: aws.String("my-key")}},
+	}, nil).Times(1)
+	g.Expect(s.CheckSSHKey("my-key")).To(Succeed())
+	g.Expect(s.CheckSSHKey("my-key")).To(Succeed())
+
+	// Other errors aren't cached.
+	otherInput := &ec2.DescribeKeyPairsInput{KeyNames: aws.StringSlice([]string{"other-key"})}
+	ec2Mock.EXPECT().DescribeKeyPairsWithContext(gomock.Any(), otherInput).Return(nil, awserr.New(awserrors.UnauthorizedOperation, "", nil)).Times(2)
+	for i := 0; i < 2; i++ {
+		err = s.CheckSSHKey("other-key")
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(IsSSHKeyNotFound(err)).To(BeFalse())
+	}
+}
+
+func TestIsSSHKeyNotFound(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(IsSSHKeyNotFound(errors.Wrap(&SSHKeyNotFoundError{KeyName: "my-key", Region: "us-east-1"}, "failed"))).To(BeTrue())
+	g.Expect(IsSSHKeyNotFound(errors.New("some error"))).To(BeFalse())
+	g.Expect(IsSSHKeyNotFound(nil)).To(BeFalse())
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/wait"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// The version of the AWS SDK in use doesn't model EKS Auto Mode. Its configuration is merged into the
+// JSON body of the CreateCluster and UpdateClusterConfig requests, and read from the JSON body of the
+// DescribeCluster responses, by request handlers.
+
+const (
+	autoModeBuildHandlerName     = "capa/eks/AutoModeBuildHandler"
+	autoModeUnmarshalHandlerName = "capa/eks/AutoModeUnmarshalHandler"
+
+	// authenticationModeAPIAndConfigMap is the authentication mode the clusters using EKS Auto Mode are
+	// created with, as EKS Auto Mode doesn't support the CONFIG_MAP authentication mode.
+	authenticationModeAPIAndConfigMap = "API_AND_CONFIG_MAP"
+)
+
+// autoModeConfig is the configuration of EKS Auto Mode. EKS requires the compute, the load balancing and
+// the block storage to be enabled or disabled together.
+type autoModeConfig struct {
+	Enabled     bool
+	NodePools   []string
+	NodeRoleARN string
+}
+
+type autoModeComputeConfig struct {
+	Enabled     *bool    `json:"enabled,omitempty"`
+	NodePools   []string `json:"nodePools,omitempty"`
+	NodeRoleArn *string  `json:"nodeRoleArn,omitempty"`
+}
+
+type autoModeToggle struct {
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+type autoModeCluster struct {
+	ComputeConfig           *autoModeComputeConfig `json:"computeConfig,omitempty"`
+	KubernetesNetworkConfig *struct {
+		ElasticLoadBalancing *autoModeToggle `json:"elasticLoadBalancing,omitempty"`
+	} `json:"kubernetesNetworkConfig,omitempty"`
+	StorageConfig *struct {
+		BlockStorage *autoModeToggle `json:"blockStorage,omitempty"`
+	} `json:"storageConfig,omitempty"`
+}
+
+// makeAutoModeConfig returns the configuration of EKS Auto Mode of the spec of the control plane.
+func makeAutoModeConfig(autoMode *ekscontrolplanev1.AutoMode) *autoModeConfig {
+	config := &autoModeConfig{Enabled: autoMode.IsEnabled()}
+	if !config.Enabled {
+		return config
+	}
+	for _, nodePool := range autoMode.NodePools {
+		config.NodePools = append(config.NodePools, string(nodePool))
+	}
+	config.NodeRoleARN = autoMode.NodeRoleARN
+	return config
+}
+
+// mergeAutoModeConfig merges the configuration of EKS Auto Mode into the JSON body of a CreateCluster
+// or UpdateClusterConfig request. The access config is only set when creating a cluster.
+func mergeAutoModeConfig(body []byte, config *autoModeConfig, setAccessConfig bool) ([]byte, error) {
+	fields := map[string]interface{}{}
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return nil, errors.Wrap(err, "failed to decode request body")
+		}
+	}
+
+	computeConfig := map[string]interface{}{"enabled": config.Enabled}
+	if len(config.NodePools) > 0 {
+		computeConfig["nodePools"] = config.NodePools
+		computeConfig["nodeRoleArn"] = config.NodeRoleARN
+	}
+	fields["computeConfig"] = computeConfig
+
+	networkConfig, _ := fields["kubernetesNetworkConfig"].(map[string]interface{})
+	if networkConfig == nil {
+		networkConfig = map[string]interface{}{}
+	}
+	networkConfig["elasticLoadBalancing"] = map[string]interface{}{"enabled": config.Enabled}
+	fields["kubernetesNetworkConfig"] = networkConfig
+
+	fields["storageConfig"] = map[string]interface{}{
+		"blockStorage": map[string]interface{}{"enabled": config.Enabled},
+	}
+
+	if setAccessConfig {
+		fields["accessConfig"] = map[string]interface{}{"authenticationMode": authenticationModeAPIAndConfigMap}
+	}
+
+	return json.Marshal(fields)
+}
+
+// parseAutoModeConfig reads the configuration of EKS Auto Mode from the JSON body of a DescribeCluster
+// response. EKS Auto Mode is reported as enabled only when the compute, the load balancing and the
+// block storage are all enabled.
+func parseAutoModeConfig(body []byte) (*autoModeConfig, error) {
+	out := struct {
+		Cluster *autoModeCluster `json:"cluster"`
+	}{}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response body")
+	}
+
+	config := &autoModeConfig{}
+	cluster := out.Cluster
+	if cluster == nil || cluster.ComputeConfig == nil {
+		return config, nil
+	}
+	config.NodePools = cluster.ComputeConfig.NodePools
+	config.NodeRoleARN = aws.StringValue(cluster.ComputeConfig.NodeRoleArn)
+	config.Enabled = aws.BoolValue(cluster.ComputeConfig.Enabled) &&
+		cluster.KubernetesNetworkConfig != nil && cluster.KubernetesNetworkConfig.ElasticLoadBalancing != nil &&
+		aws.BoolValue(cluster.KubernetesNetworkConfig.ElasticLoadBalancing.Enabled) &&
+		cluster.StorageConfig != nil && cluster.StorageConfig.BlockStorage != nil &&
+		aws.BoolValue(cluster.StorageConfig.BlockStorage.Enabled)
+	return config, nil
+}
+
+// withAutoModeConfig returns a request option which sets the configuration of EKS Auto Mode on a
+// CreateCluster or UpdateClusterConfig request.
+func withAutoModeConfig(config *autoModeConfig, setAccessConfig bool) request.Option {
+	return func(r *request.Request) {
+		r.Handlers.Build.PushBackNamed(request.NamedHandler{
+			Name: autoModeBuildHandlerName,
+			Fn: func(r *request.Request) {
+				if r.Error != nil {
+					return
+				}
+				var body []byte
+				if r.GetBody() != nil {
+					b, err := io.ReadAll(r.GetBody())
+					if err != nil {
+						r.Error = awserr.New(request.ErrCodeSerialization, "failed to read request body", err)
+						return
+					}
+					body = b
+				}
+				merged, err := mergeAutoModeConfig(body, config, setAccessConfig)
+				if err != nil {
+					r.Error = awserr.New(request.ErrCodeSerialization, "failed to set EKS Auto Mode configuration", err)
+					return
+				}
+				r.SetBufferBody(merged)
+			},
+		})
+	}
+}
+
+// readAutoModeConfig returns a request option which reads the configuration of EKS Auto Mode from the
+// response of a DescribeCluster request into config.
+func readAutoModeConfig(config **autoModeConfig) request.Option {
+	return func(r *request.Request) {
+		r.Handlers.Unmarshal.PushFrontNamed(request.NamedHandler{
+			Name: autoModeUnmarshalHandlerName,
+			Fn: func(r *request.Request) {
+				body, err := io.ReadAll(r.HTTPResponse.Body)
+				r.HTTPResponse.Body.Close()
+				r.HTTPResponse.Body = io.NopCloser(bytes.NewReader(body))
+				if err != nil {
+					r.Error = awserr.New(request.ErrCodeSerialization, "failed to read response body", err)
+					return
+				}
+				if *config, err = parseAutoModeConfig(body); err != nil {
+					r.Error = awserr.New(request.ErrCodeSerialization, "failed to read EKS Auto Mode configuration", err)
+				}
+			},
+		})
+	}
+}
+
+// describeAutoMode returns the configuration of EKS Auto Mode of the cluster.
+func (s *Service) describeAutoMode() (*autoModeConfig, error) {
+	input := &eks.DescribeClusterInput{
+		Name: aws.String(s.scope.KubernetesClusterName()),
+	}
+
+	config := &autoModeConfig{}
+	if _, err := s.EKSClient.DescribeClusterWithContext(context.TODO(), input, readAutoModeConfig(&config)); err != nil {
+		return nil, errors.Wrap(err, "failed to describe cluster")
+	}
+	return config, nil
+}
+
+// reconcileAutoMode reports the state of EKS Auto Mode in the status of the control plane, and updates
+// its node pools. Whether EKS Auto Mode is enabled can't be changed once the cluster is created.
+func (s *Service) reconcileAutoMode() error {
+	if s.scope.ControlPlane.Spec.AutoMode == nil && s.scope.ControlPlane.Status.AutoMode == nil {
+		return nil
+	}
+
+	observed, err := s.describeAutoMode()
+	if err != nil {
+		return err
+	}
+	return s.updateAutoMode(observed)
+}
+
+func (s *Service) updateAutoMode(observed *autoModeConfig) error {
+	status := &ekscontrolplanev1.AutoModeStatus{
+		Enabled:     observed.Enabled,
+		NodeRoleARN: observed.NodeRoleARN,
+	}
+	for _, nodePool := range observed.NodePools {
+		status.NodePools = append(status.NodePools, ekscontrolplanev1.AutoModeNodePool(nodePool))
+	}
+	s.scope.ControlPlane.Status.AutoMode = status
+
+	desired := makeAutoModeConfig(s.scope.ControlPlane.Spec.AutoMode)
+	if !desired.Enabled || !observed.Enabled {
+		return nil
+	}
+	if sets.New(desired.NodePools...).Equal(sets.New(observed.NodePools...)) {
+		return nil
+	}
+	// EKS processes a single update of the cluster at a time.
+	if conditions.IsTrue(s.scope.ControlPlane, ekscontrolplanev1.EKSControlPlaneUpdatingCondition) {
+		s.scope.Debug("Waiting for the EKS control plane update to complete before updating the EKS Auto Mode node pools")
+		return nil
+	}
+
+	input := &eks.UpdateClusterConfigInput{Name: aws.String(s.scope.KubernetesClusterName())}
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if _, err := s.EKSClient.UpdateClusterConfigWithContext(context.TODO(), input, withAutoModeConfig(desired, false)); err != nil {
+			if aerr, ok := err.(awserr.Error); ok {
+				return false, aerr
+			}
+			return false, err
+		}
+		conditions.MarkTrue(s.scope.ControlPlane, ekscontrolplanev1.EKSControlPlaneUpdatingCondition)
+		record.Eventf(s.scope.ControlPlane, "InitiatedUpdateEKSAutoMode", "Initiated update of the EKS Auto Mode node pools of EKS control plane %s", s.scope.KubernetesClusterName())
+		return true, nil
+	}); err != nil {
+		record.Warnf(s.scope.ControlPlane, "FailedUpdateEKSAutoMode", "Failed to update the EKS Auto Mode node pools: %v", err)
+		return errors.Wrap(err, "failed to update EKS Auto Mode node pools")
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eks
+
+import (
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/eks/mock_eksiface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestMergeAutoModeConfig(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		config          *autoModeConfig
+		setAccessConfig bool
+		want            string
+	}{
+		{
+			name:            "enabled on create",
+			body:            `{"name":"cluster","kubernetesNetworkConfig":{"serviceIpv4Cidr":"10.96.0.0/12"}}`,
+			config:          &autoModeConfig{Enabled: true, NodePools: []string{"general-purpose", "system"}, NodeRoleARN: "arn:aws:iam::123456789012:role/nodes"},
+			setAccessConfig: true,
+			want: `{"name":"cluster",
+				"accessConfig":{"authenticationMode":"API_AND_CONFIG_MAP"},
+				"computeConfig":{"enabled":true,"nodePools":["general-purpose","system"],"nodeRoleArn":"arn:aws:iam::123456789012:role/nodes"},
+				"kubernetesNetworkConfig":{"serviceIpv4Cidr":"10.96.0.0/12","elasticLoadBalancing":{"enabled":true}},
+				"storageConfig":{"blockStorage":{"enabled":true}}}`,
+		},
+		{
+			name:   "node pools removed on update",
+			body:   `{}`,
+			config: &autoModeConfig{Enabled: true},
+			want: `{"computeConfig":{"enabled":true},
+				"kubernetesNetworkConfig":{"elasticLoadBalancing":{"enabled":true}},
+				"storageConfig":{"blockStorage":{"enabled":true}}}`,
+		},
+		{
+			name:   "empty body",
+			config: &autoModeConfig{},
+			want: `{"computeConfig":{"enabled":false},
+				"kubernetesNetworkConfig":{"elasticLoadBalancing":{"enabled":false}},
+				"storageConfig":{"blockStorage":{"enabled":false}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := mergeAutoModeConfig([]byte(tt.body), tt.config, tt.setAccessConfig)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(got)).To(MatchJSON(tt.want))
+		})
+	}
+}
+
+func TestWithAutoModeConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	req, _ := eks.New(sess).CreateClusterRequest(&eks.CreateClusterInput{
+		Name:               aws.String("cluster"),
+		ClientRequestToken: aws.String("token"),
+		RoleArn:            aws.String("arn:aws:iam::123456789012:role/cluster"),
+		ResourcesVpcConfig: &eks.VpcConfigRequest{
+			SubnetIds: []*string{aws.String("subnet-1")},
+		},
+	})
+	req.ApplyOptions(withAutoModeConfig(&autoModeConfig{Enabled: true}, true))
+	g.Expect(req.Build()).To(Succeed())
+
+	body, err := io.ReadAll(req.GetBody())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(body)).To(MatchJSON(`{"name":"cluster","clientRequestToken":"token","roleArn":"arn:aws:iam::123456789012:role/cluster",
+		"resourcesVpcConfig":{"subnetIds":["subnet-1"]},
+		"accessConfig":{"authenticationMode":"API_AND_CONFIG_MAP"},
+		"computeConfig":{"enabled":true},
+		"kubernetesNetworkConfig":{"elasticLoadBalancing":{"enabled":true}},
+		"storageConfig":{"blockStorage":{"enabled":true}}}`))
+}
+
+func TestParseAutoModeConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *autoModeConfig
+	}{
+		{
+			name: "enabled",
+			body: `{"cluster":{"name":"cluster",
+				"computeConfig":{"enabled":true,"nodePools":["system"],"nodeRoleArn":"arn:aws:iam::123456789012:role/nodes"},
+				"kubernetesNetworkConfig":{"serviceIpv4Cidr":"10.96.0.0/12","elasticLoadBalancing":{"enabled":true}},
+				"storageConfig":{"blockStorage":{"enabled":true}}}}`,
+			want: &autoModeConfig{Enabled: true, NodePools: []string{"system"}, NodeRoleARN: "arn:aws:iam::123456789012:role/nodes"},
+		},
+		{
+			name: "partially enabled",
+			body: `{"cluster":{"computeConfig":{"enabled":true},"kubernetesNetworkConfig":{"elasticLoadBalancing":{"enabled":false}}}}`,
+			want: &autoModeConfig{},
+		},
+		{
+			name: "not reported",
+			body: `{"cluster":{"name":"cluster"}}`,
+			want: &autoModeConfig{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := parseAutoModeConfig([]byte(tt.body))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestUpdateAutoMode(t *testing.T) {
+	nodeRoleARN := "arn:aws:iam::123456789012:role/nodes"
+	tests := []struct {
+		name       string
+		autoMode   *ekscontrolplanev1.AutoMode
+		updating   bool
+		observed   *autoModeConfig
+		expect     func(m *mock_eksiface.MockEKSAPIMockRecorder)
+		wantStatus *ekscontrolplanev1.AutoModeStatus
+	}{
+		{
+			name:     "node pools unchanged",
+			autoMode: &ekscontrolplanev1.AutoMode{Enabled: true, NodePools: []ekscontrolplanev1.AutoModeNodePool{"system", "general-purpose"}, NodeRoleARN: nodeRoleARN},
+			observed: &autoModeConfig{Enabled: true, NodePools: []string{"general-purpose", "system"}, NodeRoleARN: nodeRoleARN},
+			expect:   func(m *mock_eksiface.MockEKSAPIMockRecorder) {},
+			wantStatus: &ekscontrolplanev1.AutoModeStatus{
+				Enabled:     true,
+				NodePools:   []ekscontrolplanev1.AutoModeNodePool{"general-purpose", "system"},
+				NodeRoleARN: nodeRoleARN,
+			},
+		},
+		{
+			name:     "node pools changed",
+			autoMode: &ekscontrolplanev1.AutoMode{Enabled: true, NodePools: []ekscontrolplanev1.AutoModeNodePool{"general-purpose"}, NodeRoleARN: nodeRoleARN},
+			observed: &autoModeConfig{Enabled: true, NodePools: []string{"system"}, NodeRoleARN: nodeRoleARN},
+			expect: func(m *mock_eksiface.MockEKSAPIMockRecorder) {
+				m.UpdateClusterConfigWithContext(gomock.Any(), &eks.UpdateClusterConfigInput{Name: aws.String("cluster")}, gomock.Any()).
+					Return(&eks.UpdateClusterConfigOutput{}, nil)
+			},
+			wantStatus: &ekscontrolplanev1.AutoModeStatus{
+				Enabled:     true,
+				NodePools:   []ekscontrolplanev1.AutoModeNodePool{"system"},
+				NodeRoleARN: nodeRoleARN,
+			},
+		},
+		{
+			name:     "node pools changed while the control plane is updating",
+			autoMode: &ekscontrolplanev1.AutoMode{Enabled: true, NodePools: []ekscontrolplanev1.AutoModeNodePool{"general-purpose"}, NodeRoleARN: nodeRoleARN},
+			updating: true,
+			observed: &autoModeConfig{Enabled: true},
+			expect:   func(m *mock_eksiface.MockEKSAPIMockRecorder) {},
+			wantStatus: &ekscontrolplanev1.AutoModeStatus{
+				Enabled: true,
+			},
+		},
+		{
+			name:     "disabled",
+			autoMode: &ekscontrolplanev1.AutoMode{},
+			observed: &autoModeConfig{},
+			expect:   func(m *mock_eksiface.MockEKSAPIMockRecorder) {},
+			wantStatus: &ekscontrolplanev1.AutoModeStatus{
+				Enabled: false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockControl := gomock.NewController(t)
+			defer mockControl.Finish()
+
+			eksMock := mock_eksiface.NewMockEKSAPI(mockControl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			_ = ekscontrolplanev1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			controlPlane := &ekscontrolplanev1.AWSManagedControlPlane{
+				Spec: ekscontrolplanev1.AWSManagedControlPlaneSpec{
+					EKSClusterName: "cluster",
+					Version:        aws.String("1.30"),
+					AutoMode:       tt.autoMode,
+				},
+			}
+			if tt.updating {
+				conditions.MarkTrue(controlPlane, ekscontrolplanev1.EKSControlPlaneUpdatingCondition)
+			}
+			scope, err := scope.NewManagedControlPlaneScope(scope.ManagedControlPlaneScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "ns",
+						Name:      "cluster",
+					},
+				},
+				ControlPlane: controlPlane,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			tt.expect(eksMock.EXPECT())
+			s := NewService(scope)
+			s.EKSClient = eksMock
+
+			g.Expect(s.updateAutoMode(tt.observed)).To(Succeed())
+			g.Expect(controlPlane.Status.AutoMode).To(Equal(tt.wantStatus))
+		})
+	}
+}
@@ -121,6 +121,10 @@ func (s *Service) reconcileCluster(ctx context.Context) error {
 		return errors.Wrap(err, "failed reconciling cluster config")
 	}
 
+	if err := s.reconcileAutoMode(); err != nil {
+		return errors.Wrap(err, "failed reconciling EKS Auto Mode")
+	}
+
 	if err := s.reconcileEKSEncryptionConfig(cluster.EncryptionConfig); err != nil {
 		return errors.Wrap(err, "failed reconciling eks encryption config")
 	}
@@ -133,10 +137,16 @@ func (s *Service) reconcileCluster(ctx context.Context) error {
 		return errors.Wrap(err, "failed reconciling OIDC provider for cluster")
 	}
 
+	if err := s.reconcileCSIDriverRoles(); err != nil {
+		return errors.Wrap(err, "failed reconciling CSI driver roles")
+	}
+
 	return nil
 }
 
 func (s *Service) setStatus(cluster *eks.Cluster) error {
+	s.scope.ControlPlane.Status.ClusterStatus = aws.StringValue(cluster.Status)
+	s.scope.ControlPlane.Status.Version = cluster.Version
 	switch *cluster.Status {
 	case eks.ClusterStatusDeleting:
 		s.scope.ControlPlane.Status.Ready = false
@@ -404,7 +414,12 @@ func (s *Service) createCluster(eksClusterName string) (*eks.Cluster, error) {
 
 	var out *eks.CreateClusterOutput
 	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
-		if out, err = s.EKSClient.CreateCluster(input); err != nil {
+		if autoMode := s.scope.ControlPlane.Spec.AutoMode; autoMode.IsEnabled() {
+			out, err = s.EKSClient.CreateClusterWithContext(context.TODO(), input, withAutoModeConfig(makeAutoModeConfig(autoMode), true))
+		} else {
+			out, err = s.EKSClient.CreateCluster(input)
+		}
+		if err != nil {
 			if aerr, ok := err.(awserr.Error); ok {
 				return false, aerr
 			}
@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
+	iamv1 "sigs.k8s.io/cluster-api-provider-aws/v2/iam/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/eks"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+)
+
+// csiDriver is a CSI driver whose managed IAM policy CAPA grants.
+type csiDriver struct {
+	// name is the name of the driver, which is also the suffix of the name of its IRSA role.
+	name string
+	// policy is the name of the managed policy of the driver.
+	policy string
+	// serviceAccount is the name, or a pattern of names, of the service accounts of the driver in kube-system.
+	serviceAccount string
+}
+
+var (
+	ebsCSIDriver = csiDriver{
+		name:           "ebs-csi-driver",
+		policy:         "AmazonEBSCSIDriverPolicy",
+		serviceAccount: "ebs-csi-controller-sa",
+	}
+	efsCSIDriver = csiDriver{
+		name:           "efs-csi-driver",
+		policy:         "AmazonEFSCSIDriverPolicy",
+		serviceAccount: "efs-csi-*",
+	}
+)
+
+// mode returns how the policy of the driver is granted.
+func (d csiDriver) mode(csiSupport *ekscontrolplanev1.CSISupport) ekscontrolplanev1.CSIDriverPolicyMode {
+	if d == ebsCSIDriver {
+		return csiSupport.GetEBSDriverPolicy()
+	}
+	return csiSupport.GetEFSDriverPolicy()
+}
+
+// policyARN returns the ARN of the managed policy of the driver in the partition.
+func (d csiDriver) policyARN(partition string) string {
+	return fmt.Sprintf("arn:%s:iam::aws:policy/service-role/%s", partition, d.policy)
+}
+
+// roleARN returns the status field holding the ARN of the IRSA role of the driver.
+func (d csiDriver) roleARN(status *ekscontrolplanev1.CSIDriverRolesStatus) *string {
+	if d == ebsCSIDriver {
+		return &status.EBSRoleARN
+	}
+	return &status.EFSRoleARN
+}
+
+// CSIDriverNodeRolePolicies returns the managed policies of the CSI drivers to attach to the roles of
+// the nodes of the control plane.
+func CSIDriverNodeRolePolicies(controlPlane *ekscontrolplanev1.AWSManagedControlPlane, partition string) []string {
+	var policies []string
+	for _, driver := range []csiDriver{ebsCSIDriver, efsCSIDriver} {
+		if driver.mode(controlPlane.Spec.CSISupport) == ekscontrolplanev1.CSIDriverPolicyModeAttachToNodeRole {
+			policies = append(policies, driver.policyARN(partition))
+		}
+	}
+	return policies
+}
+
+// csiDriverTrustRelationship returns the trust policy of the IRSA role of a driver, which can only be
+// assumed by the service accounts of the driver. The condition values are strings, which AWS returns as
+// is, so that the policy doesn't look drifted when it is compared with the one of the role.
+func csiDriverTrustRelationship(providerARN string, driver csiDriver) *iamv1.PolicyDocument {
+	issuer := providerARN[strings.Index(providerARN, "/")+1:]
+	subject := fmt.Sprintf("system:serviceaccount:%s:%s", metav1.NamespaceSystem, driver.serviceAccount)
+
+	conditions := iamv1.Conditions{
+		iamv1.StringEquals: map[string]interface{}{
+			issuer + ":aud": "sts.amazonaws.com",
+		},
+	}
+	if strings.Contains(driver.serviceAccount, "*") {
+		conditions[iamv1.StringLike] = map[string]interface{}{issuer + ":sub": subject}
+	} else {
+		conditions[iamv1.StringEquals].(map[string]interface{})[issuer+":sub"] = subject
+	}
+
+	return &iamv1.PolicyDocument{
+		Version: "2012-10-17",
+		Statement: iamv1.Statements{
+			{
+				Effect: "Allow",
+				Principal: iamv1.Principals{
+					iamv1.PrincipalFederated: iamv1.PrincipalID{providerARN},
+				},
+				Action:    iamv1.Actions{"sts:AssumeRoleWithWebIdentity"},
+				Condition: conditions,
+			},
+		},
+	}
+}
+
+// csiDriverRoleName returns the name of the IRSA role of a driver.
+func (s *Service) csiDriverRoleName(driver csiDriver) (string, error) {
+	roleName, err := eks.GenerateEKSName(driver.name, s.scope.KubernetesClusterName(), maxIAMRoleNameLength)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to generate IAM role name for %s", driver.name)
+	}
+	return roleName, nil
+}
+
+// reconcileCSIDriverRoles creates the IRSA roles of the CSI drivers using the irsa mode, and deletes
+// the ones of the drivers which don't use it anymore.
+func (s *Service) reconcileCSIDriverRoles() error {
+	for _, driver := range []csiDriver{ebsCSIDriver, efsCSIDriver} {
+		if driver.mode(s.scope.ControlPlane.Spec.CSISupport) != ekscontrolplanev1.CSIDriverPolicyModeIRSA {
+			if err := s.deleteCSIDriverRole(driver); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.reconcileCSIDriverRole(driver); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) reconcileCSIDriverRole(driver csiDriver) error {
+	if !s.scope.EnableIAM() {
+		return errors.Errorf("the irsa mode of the %s requires the 'EKSEnableIAM' feature flag", driver.name)
+	}
+	providerARN := s.scope.ControlPlane.Status.OIDCProvider.ARN
+	if providerARN == "" {
+		return errors.Errorf("the irsa mode of the %s requires the OIDC provider of the cluster", driver.name)
+	}
+
+	roleName, err := s.csiDriverRoleName(driver)
+	if err != nil {
+		return err
+	}
+	s.scope.Debug("Reconciling CSI driver IAM role", "driver", driver.name, "role-name", roleName)
+
+	trustRelationship := csiDriverTrustRelationship(providerARN, driver)
+	role, err := s.GetIAMRole(roleName)
+	if err != nil {
+		if !isNotFound(err) {
+			return err
+		}
+
+		role, err = s.CreateRole(roleName, s.scope.Name(), trustRelationship, s.scope.AdditionalTags())
+		if err != nil {
+			record.Warnf(s.scope.ControlPlane, "FailedIAMRoleCreation", "Failed to create %s IAM role %q: %v", driver.name, roleName, err)
+			return fmt.Errorf("creating role %s: %w", roleName, err)
+		}
+		record.Eventf(s.scope.ControlPlane, "SuccessfulIAMRoleCreation", "Created %s IAM role %q", driver.name, roleName)
+	}
+
+	if s.IsUnmanaged(role, s.scope.Name()) {
+		return errors.Errorf("IAM role %q of the %s already exists and isn't owned by the cluster", roleName, driver.name)
+	}
+
+	if _, err := s.EnsureTagsAndPolicy(role, s.scope.Name(), trustRelationship, s.scope.AdditionalTags()); err != nil {
+		return errors.Wrapf(err, "error ensuring tags and policy document are set on %s role", driver.name)
+	}
+
+	policies := []*string{aws.String(driver.policyARN(s.scope.Partition()))}
+	if _, err := s.EnsurePoliciesAttached(role, policies); err != nil {
+		return errors.Wrapf(err, "error ensuring policies are attached: %v", aws.StringValueSlice(policies))
+	}
+
+	if s.scope.ControlPlane.Status.CSIDriverRoles == nil {
+		s.scope.ControlPlane.Status.CSIDriverRoles = &ekscontrolplanev1.CSIDriverRolesStatus{}
+	}
+	*driver.roleARN(s.scope.ControlPlane.Status.CSIDriverRoles) = aws.StringValue(role.Arn)
+
+	return nil
+}
+
+// deleteCSIDriverRoles deletes the IRSA roles of the CSI drivers.
+func (s *Service) deleteCSIDriverRoles() error {
+	for _, driver := range []csiDriver{ebsCSIDriver, efsCSIDriver} {
+		if err := s.deleteCSIDriverRole(driver); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteCSIDriverRole deletes the IRSA role of a driver, if it was created.
+func (s *Service) deleteCSIDriverRole(driver csiDriver) error {
+	status := s.scope.ControlPlane.Status.CSIDriverRoles
+	if status == nil || *driver.roleARN(status) == "" {
+		return nil
+	}
+
+	roleName, err := s.csiDriverRoleName(driver)
+	if err != nil {
+		return err
+	}
+	s.scope.Debug("Deleting CSI driver IAM role", "driver", driver.name, "role-name", roleName)
+
+	role, err := s.GetIAMRole(roleName)
+	if err != nil && !isNotFound(err) {
+		return errors.Wrapf(err, "getting %s IAM role", driver.name)
+	}
+	if err == nil && !s.IsUnmanaged(role, s.scope.Name()) {
+		if err := s.DeleteRole(roleName); err != nil {
+			record.Warnf(s.scope.ControlPlane, "FailedIAMRoleDeletion", "Failed to delete %s IAM role %q: %v", driver.name, roleName, err)
+			return err
+		}
+		record.Eventf(s.scope.ControlPlane, "SuccessfulIAMRoleDeletion", "Deleted %s IAM role %q", driver.name, roleName)
+	}
+
+	*driver.roleARN(status) = ""
+	if status.EBSRoleARN == "" && status.EFSRoleARN == "" {
+		s.scope.ControlPlane.Status.CSIDriverRoles = nil
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eks
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/cmd/clusterawsadm/converters"
+	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/iamauth/mock_iamauth"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestCSIDriverTrustRelationship(t *testing.T) {
+	g := NewWithT(t)
+
+	providerARN := "arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-east-1.amazonaws.com/id/ABCDEF"
+
+	policy, err := converters.IAMPolicyDocumentToJSON(*csiDriverTrustRelationship(providerARN, ebsCSIDriver))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(policy).To(MatchJSON(`{
+		"Version": "2012-10-17",
+		"Statement": [{
+			"Effect": "Allow",
+			"Principal": {"Federated": ["` + providerARN + `"]},
+			"Action": ["sts:AssumeRoleWithWebIdentity"],
+			"Condition": {
+				"StringEquals": {
+					"oidc.eks.us-east-1.amazonaws.com/id/ABCDEF:aud": "sts.amazonaws.com",
+					"oidc.eks.us-east-1.amazonaws.com/id/ABCDEF:sub": "system:serviceaccount:kube-system:ebs-csi-controller-sa"
+				}
+			}
+		}]
+	}`))
+
+	policy, err = converters.IAMPolicyDocumentToJSON(*csiDriverTrustRelationship(providerARN, efsCSIDriver))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(policy).To(MatchJSON(`{
+		"Version": "2012-10-17",
+		"Statement": [{
+			"Effect": "Allow",
+			"Principal": {"Federated": ["` + providerARN + `"]},
+			"Action": ["sts:AssumeRoleWithWebIdentity"],
+			"Condition": {
+				"StringEquals": {"oidc.eks.us-east-1.amazonaws.com/id/ABCDEF:aud": "sts.amazonaws.com"},
+				"StringLike": {"oidc.eks.us-east-1.amazonaws.com/id/ABCDEF:sub": "system:serviceaccount:kube-system:efs-csi-*"}
+			}
+		}]
+	}`))
+}
+
+func TestCSIDriverNodeRolePolicies(t *testing.T) {
+	g := NewWithT(t)
+
+	controlPlane := &ekscontrolplanev1.AWSManagedControlPlane{}
+	g.Expect(CSIDriverNodeRolePolicies(controlPlane, "aws")).To(BeEmpty())
+
+	controlPlane.Spec.CSISupport = &ekscontrolplanev1.CSISupport{
+		EBSDriverPolicy: ekscontrolplanev1.CSIDriverPolicyModeAttachToNodeRole,
+		EFSDriverPolicy: ekscontrolplanev1.CSIDriverPolicyModeIRSA,
+	}
+	g.Expect(CSIDriverNodeRolePolicies(controlPlane, "aws-us-gov")).To(ConsistOf("arn:aws-us-gov:iam::aws:policy/service-role/AmazonEBSCSIDriverPolicy"))
+}
+
+func TestReconcileCSIDriverRoles(t *testing.T) {
+	const (
+		providerARN = "arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-east-1.amazonaws.com/id/ABCDEF"
+		roleName    = "default_cluster_ebs-csi-driver"
+		roleARN     = "arn:aws:iam::123456789012:role/default_cluster_ebs-csi-driver"
+		policyARN   = "arn:aws:iam::aws:policy/service-role/AmazonEBSCSIDriverPolicy"
+	)
+
+	trustPolicy, err := converters.IAMPolicyDocumentToJSON(*csiDriverTrustRelationship(providerARN, ebsCSIDriver))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ownedRole := &iam.Role{
+		RoleName:                 aws.String(roleName),
+		Arn:                      aws.String(roleARN),
+		AssumeRolePolicyDocument: aws.String(trustPolicy),
+		Tags: []*iam.Tag{
+			{Key: aws.String("kubernetes.io/cluster/capi-name"), Value: aws.String("owned")},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		csiSupport *ekscontrolplanev1.CSISupport
+		status     *ekscontrolplanev1.CSIDriverRolesStatus
+		expect     func(m *mock_iamauth.MockIAMAPIMockRecorder)
+		want       *ekscontrolplanev1.CSIDriverRolesStatus
+	}{
+		{
+			name:       "creates the role of the driver",
+			csiSupport: &ekscontrolplanev1.CSISupport{EBSDriverPolicy: ekscontrolplanev1.CSIDriverPolicyModeIRSA},
+			expect: func(m *mock_iamauth.MockIAMAPIMockRecorder) {
+				m.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)}).Return(nil, awserr.New(iam.ErrCodeNoSuchEntityException, "not found", nil))
+				m.CreateRole(gomock.Any()).Return(&iam.CreateRoleOutput{Role: ownedRole}, nil)
+				m.ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{}, nil)
+				m.GetPolicy(&iam.GetPolicyInput{PolicyArn: aws.String(policyARN)}).Return(&iam.GetPolicyOutput{}, nil)
+				m.AttachRolePolicy(&iam.AttachRolePolicyInput{RoleName: aws.String(roleName), PolicyArn: aws.String(policyARN)}).Return(&iam.AttachRolePolicyOutput{}, nil)
+			},
+			want: &ekscontrolplanev1.CSIDriverRolesStatus{EBSRoleARN: roleARN},
+		},
+		{
+			name:       "replaces the policies attached to the role out of band",
+			csiSupport: &ekscontrolplanev1.CSISupport{EBSDriverPolicy: ekscontrolplanev1.CSIDriverPolicyModeIRSA},
+			status:     &ekscontrolplanev1.CSIDriverRolesStatus{EBSRoleARN: roleARN},
+			expect: func(m *mock_iamauth.MockIAMAPIMockRecorder) {
+				m.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)}).Return(&iam.GetRoleOutput{Role: ownedRole}, nil)
+				m.ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{
+					AttachedPolicies: []*iam.AttachedPolicy{{PolicyArn: aws.String("arn:aws:iam::aws:policy/AdministratorAccess")}},
+				}, nil)
+				m.DetachRolePolicy(&iam.DetachRolePolicyInput{RoleName: aws.String(roleName), PolicyArn: aws.String("arn:aws:iam::aws:policy/AdministratorAccess")}).Return(&iam.DetachRolePolicyOutput{}, nil)
+				m.GetPolicy(&iam.GetPolicyInput{PolicyArn: aws.String(policyARN)}).Return(&iam.GetPolicyOutput{}, nil)
+				m.AttachRolePolicy(&iam.AttachRolePolicyInput{RoleName: aws.String(roleName), PolicyArn: aws.String(policyARN)}).Return(&iam.AttachRolePolicyOutput{}, nil)
+			},
+			want: &ekscontrolplanev1.CSIDriverRolesStatus{EBSRoleARN: roleARN},
+		},
+		{
+			name:       "deletes the role when the driver doesn't use the irsa mode anymore",
+			csiSupport: &ekscontrolplanev1.CSISupport{EBSDriverPolicy: ekscontrolplanev1.CSIDriverPolicyModeAttachToNodeRole},
+			status:     &ekscontrolplanev1.CSIDriverRolesStatus{EBSRoleARN: roleARN},
+			expect: func(m *mock_iamauth.MockIAMAPIMockRecorder) {
+				m.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)}).Return(&iam.GetRoleOutput{Role: ownedRole}, nil)
+				m.ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{
+					AttachedPolicies: []*iam.AttachedPolicy{{PolicyArn: aws.String(policyARN)}},
+				}, nil)
+				m.DetachRolePolicy(&iam.DetachRolePolicyInput{RoleName: aws.String(roleName), PolicyArn: aws.String(policyARN)}).Return(&iam.DetachRolePolicyOutput{}, nil)
+				m.DeleteRole(&iam.DeleteRoleInput{RoleName: aws.String(roleName)}).Return(&iam.DeleteRoleOutput{}, nil)
+			},
+			want: nil,
+		},
+		{
+			name:   "nothing to do without CSI support",
+			expect: func(m *mock_iamauth.MockIAMAPIMockRecorder) {},
+			want:   nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockControl := gomock.NewController(t)
+			defer mockControl.Finish()
+
+			scheme := runtime.NewScheme()
+			_ = ekscontrolplanev1.AddToScheme(scheme)
+
+			controlPlane := &ekscontrolplanev1.AWSManagedControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-source",
+					Namespace: "ns",
+				},
+				Spec: ekscontrolplanev1.AWSManagedControlPlaneSpec{
+					EKSClusterName:        "default_cluster",
+					Region:                "us-east-1",
+					AssociateOIDCProvider: true,
+					CSISupport:            tc.csiSupport,
+				},
+				Status: ekscontrolplanev1.AWSManagedControlPlaneStatus{
+					OIDCProvider:   ekscontrolplanev1.OIDCProviderStatus{ARN: providerARN},
+					CSIDriverRoles: tc.status,
+				},
+			}
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(controlPlane).Build()
+			scope, err := scope.NewManagedControlPlaneScope(scope.ManagedControlPlaneScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "ns",
+						Name:      "capi-name",
+					},
+				},
+				ControlPlane: controlPlane,
+				EnableIAM:    true,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			iamMock := mock_iamauth.NewMockIAMAPI(mockControl)
+			tc.expect(iamMock.EXPECT())
+			s := NewService(scope)
+			s.IAMClient = iamMock
+
+			g.Expect(s.reconcileCSIDriverRoles()).To(Succeed())
+			g.Expect(controlPlane.Status.CSIDriverRoles).To(Equal(tc.want))
+		})
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+
+	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
+	iamv1 "sigs.k8s.io/cluster-api-provider-aws/v2/iam/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+)
+
+// ECRAccessPolicyName is the name of the inline policy granting the roles of the managed node groups
+// access to the ECR registries of other accounts.
+const ECRAccessPolicyName = "ecr-registry-access"
+
+// ecrAccessPolicy returns the inline policy allowing to pull images from the repositories of the
+// accounts. GetAuthorizationToken doesn't support resource-level permissions, so it is granted on
+// every resource.
+func ecrAccessPolicy(partition string, accountIDs []string) *iamv1.PolicyDocument {
+	accountIDs = append([]string{}, accountIDs...)
+	sort.Strings(accountIDs)
+
+	repositories := iamv1.Resources{}
+	for _, accountID := range accountIDs {
+		repositories = append(repositories, fmt.Sprintf("arn:%s:ecr:*:%s:repository/*", partition, accountID))
+	}
+
+	return &iamv1.PolicyDocument{
+		Version: "2012-10-17",
+		Statement: iamv1.Statements{
+			{
+				Effect:   iamv1.EffectAllow,
+				Action:   iamv1.Actions{"ecr:GetAuthorizationToken"},
+				Resource: iamv1.Resources{iamv1.Any},
+			},
+			{
+				Effect:   iamv1.EffectAllow,
+				Action:   iamv1.Actions{"ecr:BatchGetImage", "ecr:GetDownloadUrlForLayer"},
+				Resource: repositories,
+			},
+		},
+	}
+}
+
+// roleNameFromARN returns the name of the role of an ARN, which is the last element of its path.
+func roleNameFromARN(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+// ReconcileECRAccess puts the inline policy granting access to the ECR registries of the control plane
+// on the given roles of its managed node groups, replacing it when it drifted, and reports the ARNs of
+// the roles in the status of the control plane. The policy is removed from the roles which aren't
+// granted access anymore, including all of them when the control plane doesn't grant access anymore.
+// Roles which don't exist yet or aren't owned by the cluster are skipped.
+func (s *Service) ReconcileECRAccess(roleNames []string) error {
+	s.scope.Debug("Reconciling ECR access of the nodegroup roles")
+
+	accountIDs := s.scope.ControlPlane.Spec.ECRAccess.GetRegistryAccountIDs()
+	principals := map[string]bool{}
+	if len(accountIDs) > 0 {
+		policy := ecrAccessPolicy(s.scope.Partition(), accountIDs)
+		for _, roleName := range roleNames {
+			role, err := s.GetIAMRole(roleName)
+			if err != nil {
+				if isNotFound(err) {
+					continue
+				}
+				return errors.Wrapf(err, "getting nodegroup IAM role %s", roleName)
+			}
+			if s.IsUnmanaged(role, s.scope.KubernetesClusterName()) {
+				s.scope.Debug("Skipping ECR access of unmanaged nodegroup role", "role-name", roleName)
+				continue
+			}
+
+			updated, err := s.EnsureInlinePolicy(roleName, ECRAccessPolicyName, policy)
+			if err != nil {
+				record.Warnf(s.scope.ControlPlane, "FailedPutECRAccessPolicy", "Failed to put ECR access policy on nodegroup IAM role %q: %v", roleName, err)
+				return err
+			}
+			if updated {
+				record.Eventf(s.scope.ControlPlane, "SuccessfulPutECRAccessPolicy", "Put ECR access policy on nodegroup IAM role %q", roleName)
+			}
+			principals[aws.StringValue(role.Arn)] = true
+		}
+	}
+
+	if status := s.scope.ControlPlane.Status.ECRAccess; status != nil {
+		for _, arn := range status.PrincipalARNs {
+			if principals[arn] {
+				continue
+			}
+			roleName := roleNameFromARN(arn)
+			deleted, err := s.DeleteInlinePolicy(roleName, ECRAccessPolicyName)
+			if err != nil {
+				record.Warnf(s.scope.ControlPlane, "FailedDeleteECRAccessPolicy", "Failed to delete ECR access policy of nodegroup IAM role %q: %v", roleName, err)
+				return err
+			}
+			if deleted {
+				record.Eventf(s.scope.ControlPlane, "SuccessfulDeleteECRAccessPolicy", "Deleted ECR access policy of nodegroup IAM role %q", roleName)
+			}
+		}
+	}
+
+	if len(accountIDs) == 0 {
+		s.scope.ControlPlane.Status.ECRAccess = nil
+		return nil
+	}
+	status := &ekscontrolplanev1.ECRAccessStatus{}
+	for arn := range principals {
+		status.PrincipalARNs = append(status.PrincipalARNs, arn)
+	}
+	sort.Strings(status.PrincipalARNs)
+	s.scope.ControlPlane.Status.ECRAccess = status
+
+	return nil
+}
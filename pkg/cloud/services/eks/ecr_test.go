@@ -0,0 +1,209 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eks
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/cmd/clusterawsadm/converters"
+	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/iamauth/mock_iamauth"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestECRAccessPolicy(t *testing.T) {
+	g := NewWithT(t)
+
+	policy, err := converters.IAMPolicyDocumentToJSON(*ecrAccessPolicy("aws", []string{"210987654321", "123456789012"}))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(policy).To(MatchJSON(`{
+		"Version": "2012-10-17",
+		"Statement": [
+			{
+				"Effect": "Allow",
+				"Action": ["ecr:GetAuthorizationToken"],
+				"Resource": ["*"]
+			},
+			{
+				"Effect": "Allow",
+				"Action": ["ecr:BatchGetImage", "ecr:GetDownloadUrlForLayer"],
+				"Resource": [
+					"arn:aws:ecr:*:123456789012:repository/*",
+					"arn:aws:ecr:*:210987654321:repository/*"
+				]
+			}
+		]
+	}`))
+}
+
+func TestReconcileECRAccess(t *testing.T) {
+	const (
+		roleName      = "nodegroup-role"
+		roleARN       = "arn:aws:iam::111111111111:role/nodegroup-role"
+		otherRoleName = "other-nodegroup-role"
+		otherRoleARN  = "arn:aws:iam::111111111111:role/path/other-nodegroup-role"
+	)
+
+	policy, err := converters.IAMPolicyDocumentToJSON(*ecrAccessPolicy("aws", []string{"123456789012"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ownedRole := &iam.Role{
+		RoleName: aws.String(roleName),
+		Arn:      aws.String(roleARN),
+		Tags: []*iam.Tag{
+			{Key: aws.String("kubernetes.io/cluster/default_cluster"), Value: aws.String("owned")},
+		},
+	}
+	notFound := awserr.New(iam.ErrCodeNoSuchEntityException, "not found", nil)
+	ecrAccess := &ekscontrolplanev1.ECRAccess{RegistryAccountIDs: []string{"123456789012"}}
+
+	tests := []struct {
+		name      string
+		ecrAccess *ekscontrolplanev1.ECRAccess
+		status    *ekscontrolplanev1.ECRAccessStatus
+		roleNames []string
+		expect    func(m *mock_iamauth.MockIAMAPIMockRecorder)
+		want      *ekscontrolplanev1.ECRAccessStatus
+	}{
+		{
+			name:      "puts the policy on the role",
+			ecrAccess: ecrAccess,
+			roleNames: []string{roleName},
+			expect: func(m *mock_iamauth.MockIAMAPIMockRecorder) {
+				m.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)}).Return(&iam.GetRoleOutput{Role: ownedRole}, nil)
+				m.GetRolePolicy(&iam.GetRolePolicyInput{RoleName: aws.String(roleName), PolicyName: aws.String(ECRAccessPolicyName)}).Return(nil, notFound)
+				m.PutRolePolicy(&iam.PutRolePolicyInput{RoleName: aws.String(roleName), PolicyName: aws.String(ECRAccessPolicyName), PolicyDocument: aws.String(policy)}).Return(&iam.PutRolePolicyOutput{}, nil)
+			},
+			want: &ekscontrolplanev1.ECRAccessStatus{PrincipalARNs: []string{roleARN}},
+		},
+		{
+			name:      "keeps the policy of the role when it didn't drift",
+			ecrAccess: ecrAccess,
+			status:    &ekscontrolplanev1.ECRAccessStatus{PrincipalARNs: []string{roleARN}},
+			roleNames: []string{roleName},
+			expect: func(m *mock_iamauth.MockIAMAPIMockRecorder) {
+				m.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)}).Return(&iam.GetRoleOutput{Role: ownedRole}, nil)
+				m.GetRolePolicy(gomock.Any()).Return(&iam.GetRolePolicyOutput{PolicyDocument: aws.String(url.PathEscape(policy))}, nil)
+			},
+			want: &ekscontrolplanev1.ECRAccessStatus{PrincipalARNs: []string{roleARN}},
+		},
+		{
+			name:      "replaces the policy of the role when it drifted",
+			ecrAccess: ecrAccess,
+			status:    &ekscontrolplanev1.ECRAccessStatus{PrincipalARNs: []string{roleARN}},
+			roleNames: []string{roleName},
+			expect: func(m *mock_iamauth.MockIAMAPIMockRecorder) {
+				m.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)}).Return(&iam.GetRoleOutput{Role: ownedRole}, nil)
+				m.GetRolePolicy(gomock.Any()).Return(&iam.GetRolePolicyOutput{PolicyDocument: aws.String(url.PathEscape(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["ecr:*"],"Resource":["*"]}]}`))}, nil)
+				m.PutRolePolicy(&iam.PutRolePolicyInput{RoleName: aws.String(roleName), PolicyName: aws.String(ECRAccessPolicyName), PolicyDocument: aws.String(policy)}).Return(&iam.PutRolePolicyOutput{}, nil)
+			},
+			want: &ekscontrolplanev1.ECRAccessStatus{PrincipalARNs: []string{roleARN}},
+		},
+		{
+			name:      "skips roles which don't exist or aren't owned by the cluster",
+			ecrAccess: ecrAccess,
+			roleNames: []string{roleName, otherRoleName},
+			expect: func(m *mock_iamauth.MockIAMAPIMockRecorder) {
+				m.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)}).Return(&iam.GetRoleOutput{Role: &iam.Role{RoleName: aws.String(roleName), Arn: aws.String(roleARN)}}, nil)
+				m.GetRole(&iam.GetRoleInput{RoleName: aws.String(otherRoleName)}).Return(nil, notFound)
+			},
+			want: &ekscontrolplanev1.ECRAccessStatus{},
+		},
+		{
+			name:      "deletes the policy of the roles which aren't granted access anymore",
+			ecrAccess: ecrAccess,
+			status:    &ekscontrolplanev1.ECRAccessStatus{PrincipalARNs: []string{otherRoleARN, roleARN}},
+			roleNames: []string{roleName},
+			expect: func(m *mock_iamauth.MockIAMAPIMockRecorder) {
+				m.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)}).Return(&iam.GetRoleOutput{Role: ownedRole}, nil)
+				m.GetRolePolicy(gomock.Any()).Return(&iam.GetRolePolicyOutput{PolicyDocument: aws.String(url.PathEscape(policy))}, nil)
+				m.DeleteRolePolicy(&iam.DeleteRolePolicyInput{RoleName: aws.String(otherRoleName), PolicyName: aws.String(ECRAccessPolicyName)}).Return(&iam.DeleteRolePolicyOutput{}, nil)
+			},
+			want: &ekscontrolplanev1.ECRAccessStatus{PrincipalARNs: []string{roleARN}},
+		},
+		{
+			name:      "deletes the policy of all the roles when access is cleared",
+			status:    &ekscontrolplanev1.ECRAccessStatus{PrincipalARNs: []string{otherRoleARN, roleARN}},
+			roleNames: []string{roleName},
+			expect: func(m *mock_iamauth.MockIAMAPIMockRecorder) {
+				m.DeleteRolePolicy(&iam.DeleteRolePolicyInput{RoleName: aws.String(otherRoleName), PolicyName: aws.String(ECRAccessPolicyName)}).Return(nil, notFound)
+				m.DeleteRolePolicy(&iam.DeleteRolePolicyInput{RoleName: aws.String(roleName), PolicyName: aws.String(ECRAccessPolicyName)}).Return(&iam.DeleteRolePolicyOutput{}, nil)
+			},
+			want: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockControl := gomock.NewController(t)
+			defer mockControl.Finish()
+
+			scheme := runtime.NewScheme()
+			_ = ekscontrolplanev1.AddToScheme(scheme)
+
+			controlPlane := &ekscontrolplanev1.AWSManagedControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-source",
+					Namespace: "ns",
+				},
+				Spec: ekscontrolplanev1.AWSManagedControlPlaneSpec{
+					EKSClusterName: "default_cluster",
+					Region:         "us-east-1",
+					ECRAccess:      tc.ecrAccess,
+				},
+				Status: ekscontrolplanev1.AWSManagedControlPlaneStatus{
+					ECRAccess: tc.status,
+				},
+			}
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(controlPlane).Build()
+			scope, err := scope.NewManagedControlPlaneScope(scope.ManagedControlPlaneScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "ns",
+						Name:      "capi-name",
+					},
+				},
+				ControlPlane: controlPlane,
+				EnableIAM:    true,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			iamMock := mock_iamauth.NewMockIAMAPI(mockControl)
+			tc.expect(iamMock.EXPECT())
+			s := NewService(scope)
+			s.IAMClient = iamMock
+
+			g.Expect(s.ReconcileECRAccess(tc.roleNames)).To(Succeed())
+			g.Expect(controlPlane.Status.ECRAccess).To(Equal(tc.want))
+		})
+	}
+}
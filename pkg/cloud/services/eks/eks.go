@@ -19,12 +19,16 @@ package eks
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
 	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/annotations"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -76,11 +80,34 @@ func (s *Service) DeleteControlPlane() (err error) {
 		return err
 	}
 
+	retain, err := s.retainExternalResources()
+	if err != nil {
+		return err
+	}
+	if retain {
+		s.scope.Info("control plane opted-out of garbage collection, skipping deletion of EKS log group and security groups")
+	} else {
+		// EKS cluster log group
+		if err := s.deleteClusterLogGroup(); err != nil {
+			return err
+		}
+
+		// EKS cluster security groups
+		if err := s.deleteClusterSecurityGroups(); err != nil {
+			return err
+		}
+	}
+
 	// Control Plane IAM role
 	if err := s.deleteControlPlaneIAMRole(); err != nil {
 		return err
 	}
 
+	// CSI driver IAM roles, which trust the OIDC provider
+	if err := s.deleteCSIDriverRoles(); err != nil {
+		return err
+	}
+
 	// OIDC Provider
 	if err := s.deleteOIDCProvider(); err != nil {
 		return err
@@ -90,6 +117,22 @@ func (s *Service) DeleteControlPlane() (err error) {
 	return nil
 }
 
+// retainExternalResources returns true if the control plane opted-out of the
+// garbage collection of the resources created outside of CAPA.
+func (s *Service) retainExternalResources() (bool, error) {
+	val, found := annotations.Get(s.scope.ControlPlane, infrav1.ExternalResourceGCAnnotation)
+	if !found {
+		return false, nil
+	}
+
+	shouldGC, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("converting value %s of annotation %s to bool: %w", val, infrav1.ExternalResourceGCAnnotation, err)
+	}
+
+	return !shouldGC, nil
+}
+
 // ReconcilePool is the entrypoint for ManagedMachinePool reconciliation.
 func (s *NodegroupService) ReconcilePool(ctx context.Context) error {
 	s.scope.Debug("Reconciling EKS nodegroup")
@@ -37,4 +37,7 @@ var (
 	ErrCannotUseAdditionalRoles = errors.New("additional rules cannot be added as this has been disabled")
 	// ErrNoSecurityGroup is an error when no security group is found for an EKS cluster.
 	ErrNoSecurityGroup = errors.New("no security group for EKS cluster")
+	// ErrClusterSecurityGroupInUse is an error when the security group EKS created for a cluster
+	// can't be deleted yet because its network interfaces are still being detached.
+	ErrClusterSecurityGroupInUse = errors.New("EKS cluster security group is still in use")
 )
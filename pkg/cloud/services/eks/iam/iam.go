@@ -28,6 +28,7 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
@@ -167,6 +168,60 @@ func (s *IAMService) EnsurePoliciesAttached(role *iam.Role, policies []*string)
 	return updatedPolicies, nil
 }
 
+// EnsureInlinePolicy will ensure the inline policy of the given name of a role has the given document,
+// replacing it when it drifted.
+func (s *IAMService) EnsureInlinePolicy(roleName string, policyName string, policy *iamv1.PolicyDocument) (bool, error) {
+	s.Debug("Ensuring inline policy is set on role", "role", roleName, "policy", policyName)
+
+	out, err := s.IAMClient.GetRolePolicy(&iam.GetRolePolicyInput{
+		RoleName:   aws.String(roleName),
+		PolicyName: aws.String(policyName),
+	})
+	if err != nil && !isNoSuchEntity(err) {
+		return false, errors.Wrapf(err, "error getting inline policy %s of role %s", policyName, roleName)
+	}
+	if err == nil {
+		// A document which can't be decoded was changed out of band, so it is replaced as well.
+		if documentRaw, err := url.PathUnescape(aws.StringValue(out.PolicyDocument)); err == nil {
+			var document iamv1.PolicyDocument
+			if err := json.Unmarshal([]byte(documentRaw), &document); err == nil && cmp.Equal(*policy, document) {
+				return false, nil
+			}
+		}
+	}
+
+	policyJSON, err := converters.IAMPolicyDocumentToJSON(*policy)
+	if err != nil {
+		return false, errors.Wrap(err, "error converting inline policy to json")
+	}
+	if _, err := s.IAMClient.PutRolePolicy(&iam.PutRolePolicyInput{
+		RoleName:       aws.String(roleName),
+		PolicyName:     aws.String(policyName),
+		PolicyDocument: aws.String(policyJSON),
+	}); err != nil {
+		return false, errors.Wrapf(err, "error putting inline policy %s on role %s", policyName, roleName)
+	}
+	s.Debug("Put inline policy on role", "role", roleName, "policy", policyName)
+
+	return true, nil
+}
+
+// DeleteInlinePolicy will delete the inline policy of the given name of a role, if it exists.
+func (s *IAMService) DeleteInlinePolicy(roleName string, policyName string) (bool, error) {
+	if _, err := s.IAMClient.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
+		RoleName:   aws.String(roleName),
+		PolicyName: aws.String(policyName),
+	}); err != nil {
+		if isNoSuchEntity(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "error deleting inline policy %s of role %s", policyName, roleName)
+	}
+	s.Debug("Deleted inline policy of role", "role", roleName, "policy", policyName)
+
+	return true, nil
+}
+
 // RoleTags returns the tags for the given role.
 func RoleTags(key string, additionalTags infrav1.Tags) []*iam.Tag {
 	additionalTags[infrav1.ClusterAWSCloudProviderTagKey(key)] = string(infrav1.ResourceLifecycleOwned)
@@ -405,6 +460,13 @@ func NodegroupTrustRelationship() *iamv1.PolicyDocument {
 	return policy
 }
 
+func isNoSuchEntity(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		return aerr.Code() == iam.ErrCodeNoSuchEntityException
+	}
+	return false
+}
+
 func findStringInSlice(slice []*string, toFind string) bool {
 	for _, item := range slice {
 		if *item == toFind {
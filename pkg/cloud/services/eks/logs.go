@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/pkg/errors"
+
+	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+)
+
+// clusterLogGroupName returns the name of the CloudWatch log group EKS
+// creates when control plane logging is enabled.
+func clusterLogGroupName(eksClusterName string) string {
+	return fmt.Sprintf("/aws/eks/%s/cluster", eksClusterName)
+}
+
+func loggingEnabled(loggingSpec *ekscontrolplanev1.ControlPlaneLoggingSpec) bool {
+	if loggingSpec == nil {
+		return false
+	}
+
+	return loggingSpec.APIServer || loggingSpec.Audit || loggingSpec.Authenticator || loggingSpec.ControllerManager || loggingSpec.Scheduler
+}
+
+func (s *Service) deleteClusterLogGroup() error {
+	eksClusterName := s.scope.KubernetesClusterName()
+	loggingSpec := s.scope.ControlPlane.Spec.Logging
+
+	if eksClusterName == "" || !loggingEnabled(loggingSpec) || !loggingSpec.DeleteLogGroupOnDestroy {
+		s.scope.Debug("EKS cluster log group deletion not requested, skipping")
+		return nil
+	}
+
+	logGroupName := clusterLogGroupName(eksClusterName)
+	s.scope.Info("Deleting EKS cluster log group", "log-group", logGroupName)
+
+	_, err := s.CloudWatchLogsClient.DeleteLogGroupWithContext(context.TODO(), &cloudwatchlogs.DeleteLogGroupInput{
+		LogGroupName: aws.String(logGroupName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == cloudwatchlogs.ErrCodeResourceNotFoundException {
+			s.scope.Trace("EKS cluster log group does not exist", "log-group", logGroupName)
+			return nil
+		}
+		record.Warnf(s.scope.ControlPlane, "FailedDeleteEKSClusterLogGroup", "Failed to delete EKS cluster log group %s: %v", logGroupName, err)
+		return errors.Wrapf(err, "failed to delete log group %s", logGroupName)
+	}
+	record.Eventf(s.scope.ControlPlane, "SuccessfulDeleteEKSClusterLogGroup", "Deleted EKS cluster log group %s", logGroupName)
+
+	return nil
+}
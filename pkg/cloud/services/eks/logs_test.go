@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/test/mocks"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestDeleteClusterLogGroup(t *testing.T) {
+	clusterName := "cluster.default"
+
+	tests := []struct {
+		name        string
+		logging     *ekscontrolplanev1.ControlPlaneLoggingSpec
+		expect      func(m *mocks.MockCloudWatchLogsAPIMockRecorder)
+		expectError bool
+	}{
+		{
+			name:    "logging not configured",
+			logging: nil,
+			expect:  func(m *mocks.MockCloudWatchLogsAPIMockRecorder) {},
+		},
+		{
+			name: "logging enabled without deleteLogGroupOnDestroy",
+			logging: &ekscontrolplanev1.ControlPlaneLoggingSpec{
+				APIServer: true,
+			},
+			expect: func(m *mocks.MockCloudWatchLogsAPIMockRecorder) {},
+		},
+		{
+			name: "logging disabled with deleteLogGroupOnDestroy",
+			logging: &ekscontrolplanev1.ControlPlaneLoggingSpec{
+				DeleteLogGroupOnDestroy: true,
+			},
+			expect: func(m *mocks.MockCloudWatchLogsAPIMockRecorder) {},
+		},
+		{
+			name: "logging enabled with deleteLogGroupOnDestroy",
+			logging: &ekscontrolplanev1.ControlPlaneLoggingSpec{
+				Audit:                   true,
+				DeleteLogGroupOnDestroy: true,
+			},
+			expect: func(m *mocks.MockCloudWatchLogsAPIMockRecorder) {
+				m.DeleteLogGroupWithContext(context.TODO(), &cloudwatchlogs.DeleteLogGroupInput{
+					LogGroupName: aws.String("/aws/eks/cluster.default/cluster"),
+				}).Return(&cloudwatchlogs.DeleteLogGroupOutput{}, nil)
+			},
+		},
+		{
+			name: "log group already deleted",
+			logging: &ekscontrolplanev1.ControlPlaneLoggingSpec{
+				Audit:                   true,
+				DeleteLogGroupOnDestroy: true,
+			},
+			expect: func(m *mocks.MockCloudWatchLogsAPIMockRecorder) {
+				m.DeleteLogGroupWithContext(context.TODO(), gomock.Any()).
+					Return(nil, awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "not found", nil))
+			},
+		},
+		{
+			name: "log group deletion fails",
+			logging: &ekscontrolplanev1.ControlPlaneLoggingSpec{
+				Audit:                   true,
+				DeleteLogGroupOnDestroy: true,
+			},
+			expect: func(m *mocks.MockCloudWatchLogsAPIMockRecorder) {
+				m.DeleteLogGroupWithContext(context.TODO(), gomock.Any()).
+					Return(nil, awserr.New(cloudwatchlogs.ErrCodeServiceUnavailableException, "unavailable", nil))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockControl := gomock.NewController(t)
+			defer mockControl.Finish()
+
+			logsMock := mocks.NewMockCloudWatchLogsAPI(mockControl)
+			tc.expect(logsMock.EXPECT())
+
+			s := NewService(newDeletionTestScope(g, clusterName, nil, tc.logging))
+			s.CloudWatchLogsClient = logsMock
+
+			err := s.deleteClusterLogGroup()
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func newDeletionTestScope(g *WithT, clusterName string, controlPlaneAnnotations map[string]string, logging *ekscontrolplanev1.ControlPlaneLoggingSpec) *scope.ManagedControlPlaneScope {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	_ = ekscontrolplanev1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	controlPlaneScope, err := scope.NewManagedControlPlaneScope(scope.ManagedControlPlaneScopeParams{
+		Client: client,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "capi-name",
+			},
+		},
+		ControlPlane: &ekscontrolplanev1.AWSManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: controlPlaneAnnotations,
+			},
+			Spec: ekscontrolplanev1.AWSManagedControlPlaneSpec{
+				EKSClusterName: clusterName,
+				Logging:        logging,
+			},
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	return controlPlaneScope
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/utils/ptr"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
@@ -81,17 +82,33 @@ func (s *NodegroupService) describeASGs(ng *eks.Nodegroup) (*autoscaling.Group,
 		},
 	}
 
-	out, err := s.AutoscalingClient.DescribeAutoScalingGroupsWithContext(context.TODO(), input)
+	groups, err := s.describeAutoScalingGroups(input)
 	switch {
 	case awserrors.IsNotFound(err):
 		return nil, nil
 	case err != nil:
 		return nil, errors.Wrap(err, "failed to describe ASGs")
-	case len(out.AutoScalingGroups) == 0:
+	case len(groups) == 0:
 		return nil, errors.Wrap(err, "no ASG found")
 	}
 
-	return out.AutoScalingGroups[0], nil
+	return groups[0], nil
+}
+
+// describeAutoScalingGroups returns the ASGs matching the input from all the pages of results.
+func (s *NodegroupService) describeAutoScalingGroups(input *autoscaling.DescribeAutoScalingGroupsInput) ([]*autoscaling.Group, error) {
+	var groups []*autoscaling.Group
+	for {
+		out, err := s.AutoscalingClient.DescribeAutoScalingGroupsWithContext(context.TODO(), input)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, out.AutoScalingGroups...)
+		if aws.StringValue(out.NextToken) == "" {
+			return groups, nil
+		}
+		input.NextToken = out.NextToken
+	}
 }
 
 func (s *NodegroupService) scalingConfig() *eks.NodegroupScalingConfig {
@@ -102,19 +119,74 @@ func (s *NodegroupService) scalingConfig() *eks.NodegroupScalingConfig {
 	cfg := eks.NodegroupScalingConfig{
 		DesiredSize: aws.Int64(int64(replicas)),
 	}
-	scaling := s.scope.ManagedMachinePool.Spec.Scaling
+	scaling := s.scope.ManagedMachinePool.ScalingBounds()
 	if scaling == nil {
 		return &cfg
 	}
 	if scaling.MaxSize != nil {
 		cfg.MaxSize = aws.Int64(int64(*scaling.MaxSize))
 	}
-	if scaling.MaxSize != nil {
+	if scaling.MinSize != nil {
 		cfg.MinSize = aws.Int64(int64(*scaling.MinSize))
 	}
 	return &cfg
 }
 
+// scalingConfigUpdate returns the scaling configuration to update the node group with, holding only the
+// fields which differ from the spec, or nil if none does. The desired size is never sent while the replicas
+// of the MachinePool are managed by an external autoscaler, so that changing the bounds doesn't race with
+// it, unless the new bounds exclude the current desired size: EKS requires it to be within the bounds, so it
+// is clamped to them.
+func (s *NodegroupService) scalingConfigUpdate(ng *eks.Nodegroup) *eks.NodegroupScalingConfig {
+	current := ng.ScalingConfig
+	if current == nil {
+		current = &eks.NodegroupScalingConfig{}
+	}
+	desired := s.scalingConfig()
+	update := &eks.NodegroupScalingConfig{}
+	needsUpdate := false
+	if desired.MinSize != nil && aws.Int64Value(current.MinSize) != *desired.MinSize {
+		update.MinSize = desired.MinSize
+		needsUpdate = true
+	}
+	if desired.MaxSize != nil && aws.Int64Value(current.MaxSize) != *desired.MaxSize {
+		update.MaxSize = desired.MaxSize
+		needsUpdate = true
+	}
+	externallyManaged := annotations.ReplicasManagedByExternalAutoscaler(s.scope.MachinePool)
+	if !externallyManaged && (current.DesiredSize == nil || *current.DesiredSize != *desired.DesiredSize) {
+		update.DesiredSize = desired.DesiredSize
+		needsUpdate = true
+	}
+	if !needsUpdate {
+		return nil
+	}
+
+	if update.DesiredSize == nil && current.DesiredSize != nil {
+		minSize, maxSize := current.MinSize, current.MaxSize
+		if update.MinSize != nil {
+			minSize = update.MinSize
+		}
+		if update.MaxSize != nil {
+			maxSize = update.MaxSize
+		}
+		desiredSize := *current.DesiredSize
+		if minSize != nil && desiredSize < *minSize {
+			desiredSize = *minSize
+		}
+		if maxSize != nil && desiredSize > *maxSize {
+			desiredSize = *maxSize
+		}
+		if desiredSize != *current.DesiredSize {
+			s.scope.Info("Clamping the desired size of the node group to its new bounds", "nodegroup", aws.StringValue(ng.NodegroupName), "desiredSize", *current.DesiredSize, "clampedDesiredSize", desiredSize)
+			record.Warnf(s.scope.ManagedMachinePool, "ClampedNodegroupDesiredSize", "Clamped the desired size of node group %s from %d to %d to fit its new bounds", aws.StringValue(ng.NodegroupName), *current.DesiredSize, desiredSize)
+			update.DesiredSize = aws.Int64(desiredSize)
+		}
+	}
+
+	return update
+}
+
 func (s *NodegroupService) updateConfig() *eks.NodegroupUpdateConfig {
 	updateConfig := s.scope.ManagedMachinePool.Spec.UpdateConfig
 
@@ -248,6 +320,14 @@ func (s *NodegroupService) createNodegroup() (*eks.Nodegroup, error) {
 			Version: s.scope.ManagedMachinePool.Status.LaunchTemplateVersion,
 		}
 	}
+	// Without an override, the nodegroup is created with the version of the control plane.
+	if managedPool.AMIVersionOverride != nil {
+		amiVersion, err := s.scope.GetAMILookupVersion()
+		if err != nil {
+			return nil, err
+		}
+		input.Version = aws.String(versionToEKS(parseEKSVersion(*amiVersion)))
+	}
 
 	if err := input.Validate(); err != nil {
 		return nil, errors.Wrap(err, "created invalid CreateNodegroupInput")
@@ -325,9 +405,14 @@ func (s *NodegroupService) deleteNodegroupAndWait() (reterr error) {
 }
 
 func (s *NodegroupService) reconcileNodegroupVersion(ng *eks.Nodegroup) error {
+	// The AMI version override of the pool takes precedence over the version of the MachinePool.
+	amiVersion, err := s.scope.GetAMILookupVersion()
+	if err != nil {
+		return err
+	}
 	var specVersion *version.Version
-	if s.scope.Version() != nil {
-		specVersion = parseEKSVersion(*s.scope.Version())
+	if amiVersion != nil {
+		specVersion = parseEKSVersion(*amiVersion)
 	}
 	ngVersion := version.MustParseGeneric(*ng.Version)
 	specAMI := s.scope.ManagedMachinePool.Spec.AMIVersion
@@ -462,21 +547,9 @@ func (s *NodegroupService) reconcileNodegroupConfig(ng *eks.Nodegroup) error {
 		input.Taints = taintsPayload
 		needsUpdate = true
 	}
-	if machinePool := s.scope.MachinePool.Spec; machinePool.Replicas == nil {
-		if ng.ScalingConfig.DesiredSize != nil && *ng.ScalingConfig.DesiredSize != 1 {
-			s.Debug("Nodegroup desired size differs from spec, updating scaling configuration", "nodegroup", ng.NodegroupName)
-			input.ScalingConfig = s.scalingConfig()
-			needsUpdate = true
-		}
-	} else if ng.ScalingConfig.DesiredSize == nil || int64(*machinePool.Replicas) != *ng.ScalingConfig.DesiredSize {
-		s.Debug("Nodegroup has no desired size or differs from replicas, updating scaling configuration", "nodegroup", ng.NodegroupName)
-		input.ScalingConfig = s.scalingConfig()
-		needsUpdate = true
-	}
-	if managedPool.Scaling != nil && ((aws.Int64Value(ng.ScalingConfig.MaxSize) != int64(aws.Int32Value(managedPool.Scaling.MaxSize))) ||
-		(aws.Int64Value(ng.ScalingConfig.MinSize) != int64(aws.Int32Value(managedPool.Scaling.MinSize)))) {
-		s.Debug("Nodegroup min/max differ from spec, updating scaling configuration", "nodegroup", ng.NodegroupName)
-		input.ScalingConfig = s.scalingConfig()
+	if scalingConfig := s.scalingConfigUpdate(ng); scalingConfig != nil {
+		s.Debug("Nodegroup scaling configuration differs from spec, updating scaling configuration", "nodegroup", ng.NodegroupName)
+		input.ScalingConfig = scalingConfig
 		needsUpdate = true
 	}
 	currentUpdateConfig := converters.NodegroupUpdateconfigFromSDK(ng.UpdateConfig)
@@ -572,6 +645,7 @@ func (s *NodegroupService) reconcileNodegroup(ctx context.Context) error {
 
 func (s *NodegroupService) setStatus(ng *eks.Nodegroup) error {
 	managedPool := s.scope.ManagedMachinePool
+	managedPool.Status.NodegroupStatus = aws.StringValue(ng.Status)
 	switch *ng.Status {
 	case eks.NodegroupStatusDeleting:
 		managedPool.Status.Ready = false
@@ -596,14 +670,14 @@ func (s *NodegroupService) setStatus(ng *eks.Nodegroup) error {
 		for _, asg := range ng.Resources.AutoScalingGroups {
 			req.AutoScalingGroupNames = append(req.AutoScalingGroupNames, asg.Name)
 		}
-		groups, err := s.AutoscalingClient.DescribeAutoScalingGroupsWithContext(context.TODO(), &req)
+		groups, err := s.describeAutoScalingGroups(&req)
 		if err != nil {
 			return errors.Wrap(err, "failed to describe AutoScalingGroup for nodegroup")
 		}
 
 		var replicas int32
 		var providerIDList []string
-		for _, group := range groups.AutoScalingGroups {
+		for _, group := range groups {
 			replicas += int32(len(group.Instances))
 			for _, instance := range group.Instances {
 				providerIDList = append(providerIDList, fmt.Sprintf("aws:///%s/%s", *instance.AvailabilityZone, *instance.InstanceId))
@@ -612,6 +686,11 @@ func (s *NodegroupService) setStatus(ng *eks.Nodegroup) error {
 		managedPool.Spec.ProviderIDList = providerIDList
 		managedPool.Status.Replicas = replicas
 	}
+	if ng.ScalingConfig != nil && ng.ScalingConfig.DesiredSize != nil {
+		managedPool.Status.DesiredSize = ptr.To(int32(*ng.ScalingConfig.DesiredSize))
+	}
+	managedPool.Status.Version = s.scope.Version()
+	managedPool.Status.AMIKubernetesVersion = ng.Version
 	if err := s.scope.PatchObject(); err != nil {
 		return errors.Wrap(err, "failed to update nodegroup")
 	}
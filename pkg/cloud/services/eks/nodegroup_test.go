@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eks
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/eks/iam"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/eks/mock_eksiface"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/logger"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expclusterv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+func newNodegroupService(replicas *int32, scaling *expinfrav1.ManagedMachinePoolScaling, externallyManaged bool) *NodegroupService {
+	machinePool := &expclusterv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "mp"},
+		Spec:       expclusterv1.MachinePoolSpec{Replicas: replicas},
+	}
+	if externallyManaged {
+		machinePool.Annotations = map[string]string{clusterv1.ReplicasManagedByAnnotation: "cluster-autoscaler"}
+	}
+	machinePoolScope := &scope.ManagedMachinePoolScope{
+		Logger: *logger.NewLogger(logr.Discard()),
+		ControlPlane: &ekscontrolplanev1.AWSManagedControlPlane{
+			Spec: ekscontrolplanev1.AWSManagedControlPlaneSpec{EKSClusterName: "cluster"},
+		},
+		ManagedMachinePool: &expinfrav1.AWSManagedMachinePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "mmp"},
+			Spec: expinfrav1.AWSManagedMachinePoolSpec{
+				EKSNodegroupName: "nodegroup",
+				Scaling:          scaling,
+			},
+		},
+		MachinePool: machinePool,
+	}
+	return &NodegroupService{
+		scope:      machinePoolScope,
+		IAMService: iam.IAMService{Wrapper: &machinePoolScope.Logger},
+	}
+}
+
+func nodegroupWithScaling(minSize, maxSize, desiredSize int64) *eks.Nodegroup {
+	return &eks.Nodegroup{
+		NodegroupName: aws.String("nodegroup"),
+		ScalingConfig: &eks.NodegroupScalingConfig{
+			MinSize:     aws.Int64(minSize),
+			MaxSize:     aws.Int64(maxSize),
+			DesiredSize: aws.Int64(desiredSize),
+		},
+	}
+}
+
+func TestScalingConfigUpdate(t *testing.T) {
+	tests := []struct {
+		name              string
+		replicas          *int32
+		scaling           *expinfrav1.ManagedMachinePoolScaling
+		externallyManaged bool
+		nodegroup         *eks.Nodegroup
+		want              *eks.NodegroupScalingConfig
+	}{
+		{
+			name:      "no update if the scaling configuration matches the spec",
+			replicas:  ptr.To[int32](3),
+			scaling:   &expinfrav1.ManagedMachinePoolScaling{MinSize: ptr.To[int32](1), MaxSize: ptr.To[int32](5)},
+			nodegroup: nodegroupWithScaling(1, 5, 3),
+		},
+		{
+			name:      "only sends the bounds when only they changed",
+			replicas:  ptr.To[int32](3),
+			scaling:   &expinfrav1.ManagedMachinePoolScaling{MinSize: ptr.To[int32](2), MaxSize: ptr.To[int32](5)},
+			nodegroup: nodegroupWithScaling(1, 5, 3),
+			want:      &eks.NodegroupScalingConfig{MinSize: aws.Int64(2)},
+		},
+		{
+			name:      "only sends the desired size when only the replicas changed",
+			replicas:  ptr.To[int32](4),
+			scaling:   &expinfrav1.ManagedMachinePoolScaling{MinSize: ptr.To[int32](1), MaxSize: ptr.To[int32](5)},
+			nodegroup: nodegroupWithScaling(1, 5, 3),
+			want:      &eks.NodegroupScalingConfig{DesiredSize: aws.Int64(4)},
+		},
+		{
+			name:              "never sends the desired size owned by an external autoscaler",
+			replicas:          ptr.To[int32](2),
+			scaling:           &expinfrav1.ManagedMachinePoolScaling{MinSize: ptr.To[int32](1), MaxSize: ptr.To[int32](10)},
+			externallyManaged: true,
+			nodegroup:         nodegroupWithScaling(1, 5, 4),
+			want:              &eks.NodegroupScalingConfig{MaxSize: aws.Int64(10)},
+		},
+		{
+			name:              "no update if only the desired size owned by an external autoscaler differs",
+			replicas:          ptr.To[int32](2),
+			scaling:           &expinfrav1.ManagedMachinePoolScaling{MinSize: ptr.To[int32](1), MaxSize: ptr.To[int32](5)},
+			externallyManaged: true,
+			nodegroup:         nodegroupWithScaling(1, 5, 4),
+		},
+		{
+			name:              "clamps the desired size to a minimum size raised above it",
+			replicas:          ptr.To[int32](2),
+			scaling:           &expinfrav1.ManagedMachinePoolScaling{MinSize: ptr.To[int32](3), MaxSize: ptr.To[int32](5)},
+			externallyManaged: true,
+			nodegroup:         nodegroupWithScaling(1, 5, 2),
+			want:              &eks.NodegroupScalingConfig{MinSize: aws.Int64(3), DesiredSize: aws.Int64(3)},
+		},
+		{
+			name:              "clamps the desired size to a maximum size lowered below it",
+			replicas:          ptr.To[int32](4),
+			scaling:           &expinfrav1.ManagedMachinePoolScaling{MinSize: ptr.To[int32](1), MaxSize: ptr.To[int32](3)},
+			externallyManaged: true,
+			nodegroup:         nodegroupWithScaling(1, 5, 4),
+			want:              &eks.NodegroupScalingConfig{MaxSize: aws.Int64(3), DesiredSize: aws.Int64(3)},
+		},
+		{
+			name:      "sends a desired size of 1 without replicas",
+			nodegroup: nodegroupWithScaling(0, 5, 2),
+			want:      &eks.NodegroupScalingConfig{DesiredSize: aws.Int64(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newNodegroupService(tt.replicas, tt.scaling, tt.externallyManaged)
+
+			g.Expect(s.scalingConfigUpdate(tt.nodegroup)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestReconcileNodegroupConfigScaling(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	eksMock := mock_eksiface.NewMockEKSAPI(mockCtrl)
+	eksMock.EXPECT().UpdateNodegroupConfig(&eks.UpdateNodegroupConfigInput{
+		ClusterName:   aws.String("cluster"),
+		NodegroupName: aws.String("nodegroup"),
+		ScalingConfig: &eks.NodegroupScalingConfig{MinSize: aws.Int64(2), MaxSize: aws.Int64(8)},
+	}).Return(&eks.UpdateNodegroupConfigOutput{}, nil)
+
+	// The autoscaler scaled the node group to 4 nodes while the replicas of the MachinePool are still 3.
+	s := newNodegroupService(ptr.To[int32](3), &expinfrav1.ManagedMachinePoolScaling{MinSize: ptr.To[int32](2), MaxSize: ptr.To[int32](8)}, true)
+	s.EKSClient = eksMock
+
+	g.Expect(s.reconcileNodegroupConfig(nodegroupWithScaling(1, 5, 4))).To(Succeed())
+}
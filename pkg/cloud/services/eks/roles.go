@@ -18,6 +18,7 @@ package eks
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -70,6 +71,14 @@ func FargateRolePoliciesUSGov() []string {
 	}
 }
 
+// autoModeControlPlanePolicies are the managed policies EKS Auto Mode requires on the control plane role.
+var autoModeControlPlanePolicies = []string{
+	"AmazonEKSComputePolicy",
+	"AmazonEKSBlockStoragePolicy",
+	"AmazonEKSLoadBalancingPolicy",
+	"AmazonEKSNetworkingPolicy",
+}
+
 func (s *Service) reconcileControlPlaneIAMRole() error {
 	s.scope.Debug("Reconciling EKS Control Plane IAM Role")
 
@@ -95,7 +104,13 @@ func (s *Service) reconcileControlPlaneIAMRole() error {
 			return fmt.Errorf("getting role %s: %w", *s.scope.ControlPlane.Spec.RoleName, ErrClusterRoleNotFound)
 		}
 
-		role, err = s.CreateRole(*s.scope.ControlPlane.Spec.RoleName, s.scope.Name(), eksiam.ControlPlaneTrustRelationship(false), s.scope.AdditionalTags())
+		trustRelationship := eksiam.ControlPlaneTrustRelationship(false)
+		if s.scope.ControlPlane.Spec.AutoMode.IsEnabled() {
+			// EKS Auto Mode tags the sessions of the role when managing the resources of the cluster.
+			trustRelationship.Statement[0].Action = append(trustRelationship.Statement[0].Action, "sts:TagSession")
+		}
+
+		role, err = s.CreateRole(*s.scope.ControlPlane.Spec.RoleName, s.scope.Name(), trustRelationship, s.scope.AdditionalTags())
 		if err != nil {
 			record.Warnf(s.scope.ControlPlane, "FailedIAMRoleCreation", "Failed to create control plane IAM role %q: %v", *s.scope.ControlPlane.Spec.RoleName, err)
 
@@ -114,6 +129,11 @@ func (s *Service) reconcileControlPlaneIAMRole() error {
 	policies := []*string{
 		aws.String(fmt.Sprintf("arn:%s:iam::aws:policy/AmazonEKSClusterPolicy", s.scope.Partition())),
 	}
+	if s.scope.ControlPlane.Spec.AutoMode.IsEnabled() {
+		for _, policy := range autoModeControlPlanePolicies {
+			policies = append(policies, aws.String(fmt.Sprintf("arn:%s:iam::aws:policy/%s", s.scope.Partition(), policy)))
+		}
+	}
 
 	if s.scope.ControlPlane.Spec.RoleAdditionalPolicies != nil {
 		if !s.scope.AllowAdditionalRoles() && len(*s.scope.ControlPlane.Spec.RoleAdditionalPolicies) > 0 {
@@ -226,6 +246,7 @@ func (s *NodegroupService) reconcileNodegroupIAMRole() error {
 	if strings.Contains(s.scope.Partition(), v1beta1.PartitionNameUSGov) {
 		policies = NodegroupRolePoliciesUSGov()
 	}
+	policies = append(policies, CSIDriverNodeRolePolicies(s.scope.ControlPlane, s.scope.Partition())...)
 
 	if len(s.scope.ManagedMachinePool.Spec.RoleAdditionalPolicies) > 0 {
 		if !s.scope.AllowAdditionalRoles() {
@@ -282,6 +303,13 @@ func (s *NodegroupService) deleteNodegroupIAMRole() (reterr error) {
 		return nil
 	}
 
+	// The role can't be deleted as long as the ECR access policy the control plane put on it is there.
+	if ecrAccess := s.scope.ControlPlane.Status.ECRAccess; ecrAccess != nil && slices.Contains(ecrAccess.PrincipalARNs, aws.StringValue(role.Arn)) {
+		if _, err := s.DeleteInlinePolicy(roleName, ECRAccessPolicyName); err != nil {
+			return err
+		}
+	}
+
 	err = s.DeleteRole(s.scope.RoleName())
 	if err != nil {
 		record.Eventf(s.scope.ManagedMachinePool, "FailedIAMRoleDeletion", "Failed to delete Nodegroup IAM role %q: %v", s.scope.ManagedMachinePool.Spec.RoleName, err)
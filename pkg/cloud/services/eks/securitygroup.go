@@ -23,11 +23,14 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
 	"k8s.io/utils/ptr"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/v2/controlplane/eks/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
 )
 
 func (s *Service) reconcileSecurityGroups(cluster *eks.Cluster) error {
@@ -81,3 +84,57 @@ func (s *Service) reconcileSecurityGroups(cluster *eks.Cluster) error {
 
 	return nil
 }
+
+// deleteClusterSecurityGroups deletes the eks-cluster-sg-* security group EKS
+// creates for the cluster. EKS usually removes it itself, but it can be left
+// behind when the cluster deletion fails part way, which then blocks the
+// deletion of the VPC.
+func (s *Service) deleteClusterSecurityGroups() error {
+	eksClusterName := s.scope.KubernetesClusterName()
+	if eksClusterName == "" {
+		return nil
+	}
+
+	input := &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag:aws:eks:cluster-name"),
+				Values: []*string{aws.String(eksClusterName)},
+			},
+			{
+				Name:   aws.String("group-name"),
+				Values: []*string{aws.String(fmt.Sprintf("eks-cluster-sg-%s-*", eksClusterName))},
+			},
+		},
+	}
+
+	output, err := s.EC2Client.DescribeSecurityGroupsWithContext(context.TODO(), input)
+	if err != nil {
+		return fmt.Errorf("describing EKS cluster security groups: %w", err)
+	}
+
+	for _, sg := range output.SecurityGroups {
+		groupID := aws.StringValue(sg.GroupId)
+		s.scope.Info("Deleting EKS cluster security group", "security-group-id", groupID)
+
+		if _, err := s.EC2Client.DeleteSecurityGroupWithContext(context.TODO(), &ec2.DeleteSecurityGroupInput{
+			GroupId: sg.GroupId,
+		}); err != nil {
+			code, _ := awserrors.Code(err)
+			switch code {
+			case awserrors.GroupNotFound:
+				continue
+			case awserrors.DependencyViolation:
+				// The ENIs of the EKS cluster can take a while to be detached once the
+				// cluster is deleted, so the deletion is retried on a later reconciliation.
+				s.scope.Info("EKS cluster security group is still in use", "security-group-id", groupID)
+				return errors.Wrapf(ErrClusterSecurityGroupInUse, "security group %s", groupID)
+			}
+			record.Warnf(s.scope.ControlPlane, "FailedDeleteEKSClusterSecurityGroup", "Failed to delete EKS cluster security group %s: %v", groupID, err)
+			return errors.Wrapf(err, "failed to delete EKS cluster security group %s", groupID)
+		}
+		record.Eventf(s.scope.ControlPlane, "SuccessfulDeleteEKSClusterSecurityGroup", "Deleted EKS cluster security group %s", groupID)
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/test/mocks"
+)
+
+func TestDeleteClusterSecurityGroups(t *testing.T) {
+	clusterName := "cluster.default"
+	describeInput := &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag:aws:eks:cluster-name"),
+				Values: []*string{aws.String(clusterName)},
+			},
+			{
+				Name:   aws.String("group-name"),
+				Values: []*string{aws.String("eks-cluster-sg-cluster.default-*")},
+			},
+		},
+	}
+	describeOutput := &ec2.DescribeSecurityGroupsOutput{
+		SecurityGroups: []*ec2.SecurityGroup{
+			{
+				GroupId:   aws.String("sg-1"),
+				GroupName: aws.String("eks-cluster-sg-cluster.default-123456"),
+			},
+		},
+	}
+	deleteInput := &ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-1")}
+
+	tests := []struct {
+		name        string
+		expect      func(m *mocks.MockEC2APIMockRecorder)
+		expectError bool
+		expectInUse bool
+	}{
+		{
+			name: "no security group left behind",
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				m.DescribeSecurityGroupsWithContext(context.TODO(), describeInput).Return(&ec2.DescribeSecurityGroupsOutput{}, nil)
+			},
+		},
+		{
+			name: "security group is deleted",
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				m.DescribeSecurityGroupsWithContext(context.TODO(), describeInput).Return(describeOutput, nil)
+				m.DeleteSecurityGroupWithContext(context.TODO(), deleteInput).Return(&ec2.DeleteSecurityGroupOutput{}, nil)
+			},
+		},
+		{
+			name: "security group deletion is requeued while ENIs are being detached",
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				m.DescribeSecurityGroupsWithContext(context.TODO(), describeInput).Return(describeOutput, nil)
+				m.DeleteSecurityGroupWithContext(context.TODO(), deleteInput).
+					Return(nil, awserr.New(awserrors.DependencyViolation, "resource sg-1 has a dependent object", nil))
+			},
+			expectError: true,
+			expectInUse: true,
+		},
+		{
+			name: "security group already deleted",
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				m.DescribeSecurityGroupsWithContext(context.TODO(), describeInput).Return(describeOutput, nil)
+				m.DeleteSecurityGroupWithContext(context.TODO(), deleteInput).
+					Return(nil, awserr.New(awserrors.GroupNotFound, "not found", nil))
+			},
+		},
+		{
+			name: "security group deletion fails",
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				m.DescribeSecurityGroupsWithContext(context.TODO(), describeInput).Return(describeOutput, nil)
+				m.DeleteSecurityGroupWithContext(context.TODO(), deleteInput).
+					Return(nil, awserr.New(awserrors.UnauthorizedOperation, "unauthorized", nil))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockControl := gomock.NewController(t)
+			defer mockControl.Finish()
+
+			ec2Mock := mocks.NewMockEC2API(mockControl)
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(newDeletionTestScope(g, clusterName, nil, nil))
+			s.EC2Client = ec2Mock
+
+			err := s.deleteClusterSecurityGroups()
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(errors.Is(err, ErrClusterSecurityGroupInUse)).To(Equal(tc.expectInUse))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestRetainExternalResources(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+		expectError bool
+	}{
+		{
+			name: "no annotation",
+			want: false,
+		},
+		{
+			name:        "garbage collection enabled",
+			annotations: map[string]string{infrav1.ExternalResourceGCAnnotation: "true"},
+			want:        false,
+		},
+		{
+			name:        "garbage collection disabled",
+			annotations: map[string]string{infrav1.ExternalResourceGCAnnotation: "false"},
+			want:        true,
+		},
+		{
+			name:        "invalid annotation value",
+			annotations: map[string]string{infrav1.ExternalResourceGCAnnotation: "maybe"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			s := NewService(newDeletionTestScope(g, "cluster.default", tc.annotations, nil))
+
+			retain, err := s.retainExternalResources()
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(retain).To(Equal(tc.want))
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/aws/aws-sdk-go/service/eks/eksiface"
@@ -45,9 +46,10 @@ type EKSClient struct {
 // The interfaces are broken down like this to group functions together.
 // One alternative is to have a large list of functions from the ec2 client.
 type Service struct {
-	scope     *scope.ManagedControlPlaneScope
-	EC2Client ec2iface.EC2API
-	EKSClient EKSAPI
+	scope                *scope.ManagedControlPlaneScope
+	EC2Client            ec2iface.EC2API
+	EKSClient            EKSAPI
+	CloudWatchLogsClient cloudwatchlogsiface.CloudWatchLogsAPI
 	iam.IAMService
 	STSClient stsiface.STSAPI
 }
@@ -70,6 +72,7 @@ func NewService(controlPlaneScope *scope.ManagedControlPlaneScope, opts ...Servi
 		EKSClient: EKSClient{
 			EKSAPI: scope.NewEKSClient(controlPlaneScope, controlPlaneScope, controlPlaneScope, controlPlaneScope.ControlPlane),
 		},
+		CloudWatchLogsClient: scope.NewCloudWatchLogsClient(controlPlaneScope, controlPlaneScope, controlPlaneScope, controlPlaneScope.ControlPlane),
 		IAMService: iam.IAMService{
 			Wrapper:   &controlPlaneScope.Logger,
 			IAMClient: scope.NewIAMClient(controlPlaneScope, controlPlaneScope, controlPlaneScope, controlPlaneScope.ControlPlane),
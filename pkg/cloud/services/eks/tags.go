@@ -33,9 +33,8 @@ import (
 )
 
 const (
-	eksClusterNameTag              = "eks:cluster-name"
-	eksNodeGroupNameTag            = "eks:nodegroup-name"
-	eksClusterAutoscalerEnabledTag = "k8s.io/cluster-autoscaler/enabled"
+	eksClusterNameTag   = "eks:cluster-name"
+	eksNodeGroupNameTag = "eks:nodegroup-name"
 )
 
 func (s *Service) reconcileTags(cluster *eks.Cluster) error {
@@ -82,8 +81,8 @@ func getASGTagUpdates(clusterName string, currentTags map[string]string, tags ma
 	officialASGTagsByEKS := []string{
 		eksClusterNameTag,
 		eksNodeGroupNameTag,
-		fmt.Sprintf("k8s.io/cluster-autoscaler/%s", clusterName),
-		eksClusterAutoscalerEnabledTag,
+		infrav1.ClusterAutoscalerClusterTagKey(clusterName),
+		infrav1.ClusterAutoscalerEnabledTagKey,
 		infrav1.ClusterAWSCloudProviderTagKey(clusterName),
 	}
 	tagsToDelete = make(map[string]string)
@@ -130,7 +129,21 @@ func (s *NodegroupService) reconcileASGTags(ng *eks.Nodegroup) error {
 		return errors.Wrap(err, "failed to describe ASG for nodegroup")
 	}
 
-	tagsToDelete, tagsToAdd := getASGTagUpdates(s.scope.ClusterName(), tagDescriptionsToMap(asg.Tags), s.scope.AdditionalTags())
+	currentTags := tagDescriptionsToMap(asg.Tags)
+	tagsToDelete, tagsToAdd := getASGTagUpdates(s.scope.ClusterName(), currentTags, s.scope.AdditionalTags())
+
+	// The cluster autoscaler tags are only removed from the ASG if they were added for the autoscaling
+	// of the AWSManagedMachinePool, as EKS sets them by default.
+	enabled := s.scope.ManagedMachinePool.Spec.Autoscaling.IsEnabled()
+	if enabled || s.scope.AutoscalingManagesReplicas() {
+		create, remove := infrav1.ClusterAutoscalerTagUpdates(s.scope.ClusterName(), currentTags, s.scope.AdditionalTags(), enabled)
+		for k, v := range create {
+			tagsToAdd[k] = v
+		}
+		for k, v := range remove {
+			tagsToDelete[k] = v
+		}
+	}
 	s.scope.Debug("Tags", "tagsToAdd", tagsToAdd, "tagsToDelete", tagsToDelete)
 
 	if len(tagsToAdd) > 0 {
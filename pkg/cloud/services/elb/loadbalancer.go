@@ -66,8 +66,18 @@ func (s *Service) ReconcileLoadbalancers() error {
 		}
 		switch lbSpec.LoadBalancerType {
 		case infrav1.LoadBalancerTypeClassic:
+			if s.scope.ControlPlaneLoadBalancerMigration() != nil {
+				if err := s.revertLoadBalancerMigration(); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+			}
 			errs = append(errs, s.reconcileClassicLoadBalancer())
 		case infrav1.LoadBalancerTypeNLB, infrav1.LoadBalancerTypeALB, infrav1.LoadBalancerTypeELB:
+			if s.isMigratingControlPlaneLoadBalancer(lbSpec) {
+				errs = append(errs, s.reconcileLoadBalancerMigration(lbSpec))
+				continue
+			}
 			errs = append(errs, s.reconcileV2LB(lbSpec))
 		default:
 			errs = append(errs, fmt.Errorf("unknown or unsupported load balancer type on primary load balancer: %s", lbSpec.LoadBalancerType))
@@ -146,6 +156,12 @@ func (s *Service) reconcileV2LB(lbSpec *infrav1.AWSLoadBalancerSpec) error {
 				return errors.Wrapf(err, "failed to apply security groups to load balancer %q", lb.Name)
 			}
 		}
+
+		if lbSpec.TargetGroupAttributes != nil {
+			if err := s.reconcileTargetGroupAttributes(lb, lbSpec); err != nil {
+				return errors.Wrapf(err, "failed to reconcile target group attributes for apiserver load balancer %q", lb.Name)
+			}
+		}
 	} else {
 		s.scope.Trace("Unmanaged control plane load balancer, skipping load balancer configuration", "api-server-elb", lb)
 	}
@@ -159,6 +175,95 @@ func (s *Service) reconcileV2LB(lbSpec *infrav1.AWSLoadBalancerSpec) error {
 	return nil
 }
 
+// reconcileTargetGroupAttributes applies the target group attributes of the load balancer spec
+// to the target groups of an existing load balancer, when they differ. Attributes removed from
+// the spec are not reset, as their previous values aren't recorded.
+func (s *Service) reconcileTargetGroupAttributes(lb *infrav1.LoadBalancer, lbSpec *infrav1.AWSLoadBalancerSpec) error {
+	desired := getTargetGroupAttributes(lbSpec)
+	if len(desired) == 0 {
+		return nil
+	}
+
+	groups, err := s.ELBV2Client.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+		LoadBalancerArn: aws.String(lb.ARN),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to describe target groups for load balancer %q", lb.Name)
+	}
+
+	for _, group := range groups.TargetGroups {
+		out, err := s.ELBV2Client.DescribeTargetGroupAttributes(&elbv2.DescribeTargetGroupAttributesInput{
+			TargetGroupArn: group.TargetGroupArn,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to describe attributes of target group %q", aws.StringValue(group.TargetGroupName))
+		}
+
+		current := make(map[string]string, len(out.Attributes))
+		for _, attr := range out.Attributes {
+			current[aws.StringValue(attr.Key)] = aws.StringValue(attr.Value)
+		}
+
+		var changed []*elbv2.TargetGroupAttribute
+		for _, attr := range desired {
+			if value, ok := current[aws.StringValue(attr.Key)]; !ok || value != aws.StringValue(attr.Value) {
+				changed = append(changed, attr)
+			}
+		}
+		if len(changed) == 0 {
+			continue
+		}
+
+		s.scope.Debug("Updating target group attributes", "target-group", aws.StringValue(group.TargetGroupName), "attributes", changed)
+		if _, err := s.ELBV2Client.ModifyTargetGroupAttributes(&elbv2.ModifyTargetGroupAttributesInput{
+			TargetGroupArn: group.TargetGroupArn,
+			Attributes:     changed,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to modify attributes of target group %q", aws.StringValue(group.TargetGroupName))
+		}
+	}
+
+	return nil
+}
+
+// getTargetGroupAttributes returns the target group attributes configured through
+// the TargetGroupAttributes field of the load balancer spec.
+func getTargetGroupAttributes(lbSpec *infrav1.AWSLoadBalancerSpec) []*elbv2.TargetGroupAttribute {
+	if lbSpec == nil || lbSpec.TargetGroupAttributes == nil {
+		return nil
+	}
+	spec := lbSpec.TargetGroupAttributes
+
+	var attrs []*elbv2.TargetGroupAttribute
+	if spec.SlowStartDurationSeconds != nil {
+		attrs = append(attrs, &elbv2.TargetGroupAttribute{
+			Key:   aws.String(infrav1.TargetGroupAttributeSlowStartDurationSeconds),
+			Value: aws.String(strconv.FormatInt(*spec.SlowStartDurationSeconds, 10)),
+		})
+	}
+	if spec.Stickiness != nil {
+		attrs = append(attrs, &elbv2.TargetGroupAttribute{
+			Key:   aws.String(infrav1.TargetGroupAttributeStickinessEnabled),
+			Value: aws.String(strconv.FormatBool(spec.Stickiness.Enabled)),
+		})
+		if spec.Stickiness.Enabled {
+			stickinessType := spec.Stickiness.Type
+			if stickinessType == "" {
+				stickinessType = infrav1.TargetGroupStickinessTypeSourceIP
+				if lbSpec.LoadBalancerType == infrav1.LoadBalancerTypeALB {
+					stickinessType = infrav1.TargetGroupStickinessTypeLBCookie
+				}
+			}
+			attrs = append(attrs, &elbv2.TargetGroupAttribute{
+				Key:   aws.String(infrav1.TargetGroupAttributeStickinessType),
+				Value: aws.String(string(stickinessType)),
+			})
+		}
+	}
+
+	return attrs
+}
+
 func (s *Service) getAPIServerLBSpec(elbName string, lbSpec *infrav1.AWSLoadBalancerSpec) (*infrav1.LoadBalancer, error) {
 	var securityGroupIDs []string
 	if lbSpec != nil {
@@ -331,15 +436,18 @@ func (s *Service) createLB(spec *infrav1.LoadBalancer, lbSpec *infrav1.AWSLoadBa
 			return nil, errors.New("no target group was created; the returned list is empty")
 		}
 
+		var targetGroupAttributes []*elbv2.TargetGroupAttribute
 		if !lbSpec.PreserveClientIP {
+			targetGroupAttributes = append(targetGroupAttributes, &elbv2.TargetGroupAttribute{
+				Key:   aws.String(infrav1.TargetGroupAttributeEnablePreserveClientIP),
+				Value: aws.String("false"),
+			})
+		}
+		targetGroupAttributes = append(targetGroupAttributes, getTargetGroupAttributes(lbSpec)...)
+		if len(targetGroupAttributes) > 0 {
 			targetGroupAttributeInput := &elbv2.ModifyTargetGroupAttributesInput{
 				TargetGroupArn: group.TargetGroups[0].TargetGroupArn,
-				Attributes: []*elbv2.TargetGroupAttribute{
-					{
-						Key:   aws.String(infrav1.TargetGroupAttributeEnablePreserveClientIP),
-						Value: aws.String("false"),
-					},
-				},
+				Attributes:     targetGroupAttributes,
 			}
 			if _, err := s.ELBV2Client.ModifyTargetGroupAttributes(targetGroupAttributeInput); err != nil {
 				return nil, errors.Wrapf(err, "failed to modify target group attribute")
@@ -835,6 +943,36 @@ func (s *Service) RegisterInstanceWithAPIServerLB(instance *infrav1.Instance, lb
 	return nil
 }
 
+// GetAPIServerLBListenerTargetGroups returns the ARNs of the target groups the listeners of the control plane load
+// balancer forward to, by listener port.
+func (s *Service) GetAPIServerLBListenerTargetGroups(lbSpec *infrav1.AWSLoadBalancerSpec) (map[int64]string, error) {
+	name, err := LBName(s.scope, lbSpec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get control plane load balancer name")
+	}
+	out, err := s.describeLB(name, lbSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	listeners, err := s.ELBV2Client.DescribeListeners(&elbv2.DescribeListenersInput{
+		LoadBalancerArn: aws.String(out.ARN),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe listeners of load balancer %q", name)
+	}
+
+	res := make(map[int64]string, len(listeners.Listeners))
+	for _, listener := range listeners.Listeners {
+		for _, action := range listener.DefaultActions {
+			if aws.StringValue(action.Type) == elbv2.ActionTypeEnumForward && action.TargetGroupArn != nil {
+				res[aws.Int64Value(listener.Port)] = aws.StringValue(action.TargetGroupArn)
+			}
+		}
+	}
+	return res, nil
+}
+
 // getControlPlaneLoadBalancerSubnets retrieves ControlPlaneLoadBalancer subnets information.
 func (s *Service) getControlPlaneLoadBalancerSubnets() (infrav1.Subnets, error) {
 	var subnets infrav1.Subnets
@@ -1993,6 +1993,124 @@ func TestReconcileV2LB(t *testing.T) {
 	}
 }
 
+func TestReconcileTargetGroupAttributes(t *testing.T) {
+	const (
+		elbArn         = "arn::apiserver"
+		targetGroupArn = "arn::target-group"
+	)
+
+	tests := []struct {
+		name          string
+		lbSpec        *infrav1.AWSLoadBalancerSpec
+		elbV2APIMocks func(m *mocks.MockELBV2APIMockRecorder)
+	}{
+		{
+			name: "updates the attributes that differ",
+			lbSpec: &infrav1.AWSLoadBalancerSpec{
+				LoadBalancerType: infrav1.LoadBalancerTypeALB,
+				TargetGroupAttributes: &infrav1.TargetGroupAttributesSpec{
+					SlowStartDurationSeconds: aws.Int64(60),
+					Stickiness:               &infrav1.TargetGroupStickinessSpec{Enabled: true},
+				},
+			},
+			elbV2APIMocks: func(m *mocks.MockELBV2APIMockRecorder) {
+				m.DescribeTargetGroups(gomock.Eq(&elbv2.DescribeTargetGroupsInput{
+					LoadBalancerArn: aws.String(elbArn),
+				})).Return(&elbv2.DescribeTargetGroupsOutput{
+					TargetGroups: []*elbv2.TargetGroup{{TargetGroupArn: aws.String(targetGroupArn), TargetGroupName: aws.String("apiserver-target")}},
+				}, nil)
+				m.DescribeTargetGroupAttributes(gomock.Eq(&elbv2.DescribeTargetGroupAttributesInput{
+					TargetGroupArn: aws.String(targetGroupArn),
+				})).Return(&elbv2.DescribeTargetGroupAttributesOutput{
+					Attributes: []*elbv2.TargetGroupAttribute{
+						{Key: aws.String(infrav1.TargetGroupAttributeSlowStartDurationSeconds), Value: aws.String("0")},
+						{Key: aws.String(infrav1.TargetGroupAttributeStickinessEnabled), Value: aws.String("true")},
+						{Key: aws.String(infrav1.TargetGroupAttributeStickinessType), Value: aws.String("lb_cookie")},
+					},
+				}, nil)
+				m.ModifyTargetGroupAttributes(gomock.Eq(&elbv2.ModifyTargetGroupAttributesInput{
+					TargetGroupArn: aws.String(targetGroupArn),
+					Attributes: []*elbv2.TargetGroupAttribute{
+						{Key: aws.String(infrav1.TargetGroupAttributeSlowStartDurationSeconds), Value: aws.String("60")},
+					},
+				})).Return(&elbv2.ModifyTargetGroupAttributesOutput{}, nil)
+			},
+		},
+		{
+			name: "defaults the stickiness type of a network load balancer to source_ip",
+			lbSpec: &infrav1.AWSLoadBalancerSpec{
+				LoadBalancerType: infrav1.LoadBalancerTypeNLB,
+				TargetGroupAttributes: &infrav1.TargetGroupAttributesSpec{
+					Stickiness: &infrav1.TargetGroupStickinessSpec{Enabled: true},
+				},
+			},
+			elbV2APIMocks: func(m *mocks.MockELBV2APIMockRecorder) {
+				m.DescribeTargetGroups(gomock.Any()).Return(&elbv2.DescribeTargetGroupsOutput{
+					TargetGroups: []*elbv2.TargetGroup{{TargetGroupArn: aws.String(targetGroupArn)}},
+				}, nil)
+				m.DescribeTargetGroupAttributes(gomock.Any()).Return(&elbv2.DescribeTargetGroupAttributesOutput{
+					Attributes: []*elbv2.TargetGroupAttribute{
+						{Key: aws.String(infrav1.TargetGroupAttributeStickinessEnabled), Value: aws.String("false")},
+						{Key: aws.String(infrav1.TargetGroupAttributeStickinessType), Value: aws.String("source_ip")},
+					},
+				}, nil)
+				m.ModifyTargetGroupAttributes(gomock.Eq(&elbv2.ModifyTargetGroupAttributesInput{
+					TargetGroupArn: aws.String(targetGroupArn),
+					Attributes: []*elbv2.TargetGroupAttribute{
+						{Key: aws.String(infrav1.TargetGroupAttributeStickinessEnabled), Value: aws.String("true")},
+					},
+				})).Return(&elbv2.ModifyTargetGroupAttributesOutput{}, nil)
+			},
+		},
+		{
+			name: "does nothing when the attributes are up to date",
+			lbSpec: &infrav1.AWSLoadBalancerSpec{
+				LoadBalancerType: infrav1.LoadBalancerTypeNLB,
+				TargetGroupAttributes: &infrav1.TargetGroupAttributesSpec{
+					Stickiness: &infrav1.TargetGroupStickinessSpec{Enabled: false},
+				},
+			},
+			elbV2APIMocks: func(m *mocks.MockELBV2APIMockRecorder) {
+				m.DescribeTargetGroups(gomock.Any()).Return(&elbv2.DescribeTargetGroupsOutput{
+					TargetGroups: []*elbv2.TargetGroup{{TargetGroupArn: aws.String(targetGroupArn)}},
+				}, nil)
+				m.DescribeTargetGroupAttributes(gomock.Any()).Return(&elbv2.DescribeTargetGroupAttributesOutput{
+					Attributes: []*elbv2.TargetGroupAttribute{
+						{Key: aws.String(infrav1.TargetGroupAttributeStickinessEnabled), Value: aws.String("false")},
+					},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			elbV2APIMocks := mocks.NewMockELBV2API(mockCtrl)
+
+			scheme, err := setupScheme()
+			g.Expect(err).NotTo(HaveOccurred())
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     fake.NewClientBuilder().WithScheme(scheme).Build(),
+				Cluster:    &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "foo"}},
+				AWSCluster: &infrav1.AWSCluster{ObjectMeta: metav1.ObjectMeta{Name: "foo"}},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			tc.elbV2APIMocks(elbV2APIMocks.EXPECT())
+
+			s := &Service{
+				scope:       clusterScope,
+				ELBV2Client: elbV2APIMocks,
+			}
+			err = s.reconcileTargetGroupAttributes(&infrav1.LoadBalancer{ARN: elbArn, Name: "bar-apiserver"}, tc.lbSpec)
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
 func TestReconcileLoadbalancers(t *testing.T) {
 	const (
 		namespace       = "foo"
@@ -2876,6 +2994,81 @@ func TestDescribeV2Loadbalancers(t *testing.T) {
 	}
 }
 
+func TestGetAPIServerLBListenerTargetGroups(t *testing.T) {
+	const (
+		lbName = "bar-apiserver"
+		lbArn  = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/bar-apiserver/1234"
+	)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	g := NewWithT(t)
+
+	elbV2ApiMock := mocks.NewMockELBV2API(mockCtrl)
+	elbV2ApiMock.EXPECT().DescribeLoadBalancers(gomock.Eq(&elbv2.DescribeLoadBalancersInput{
+		Names: aws.StringSlice([]string{lbName}),
+	})).Return(&elbv2.DescribeLoadBalancersOutput{LoadBalancers: []*elbv2.LoadBalancer{{
+		LoadBalancerArn:  aws.String(lbArn),
+		LoadBalancerName: aws.String(lbName),
+		Scheme:           aws.String(string(infrav1.ELBSchemeInternetFacing)),
+	}}}, nil)
+	elbV2ApiMock.EXPECT().DescribeLoadBalancerAttributes(gomock.Any()).Return(&elbv2.DescribeLoadBalancerAttributesOutput{}, nil)
+	elbV2ApiMock.EXPECT().DescribeTags(gomock.Any()).Return(&elbv2.DescribeTagsOutput{
+		TagDescriptions: []*elbv2.TagDescription{{ResourceArn: aws.String(lbArn)}},
+	}, nil)
+	elbV2ApiMock.EXPECT().DescribeListeners(gomock.Eq(&elbv2.DescribeListenersInput{
+		LoadBalancerArn: aws.String(lbArn),
+	})).Return(&elbv2.DescribeListenersOutput{Listeners: []*elbv2.Listener{
+		{
+			Port: aws.Int64(6443),
+			DefaultActions: []*elbv2.Action{{
+				Type:           aws.String(elbv2.ActionTypeEnumForward),
+				TargetGroupArn: aws.String("tg-6443"),
+			}},
+		},
+		{
+			Port: aws.Int64(8132),
+			DefaultActions: []*elbv2.Action{{
+				Type:           aws.String(elbv2.ActionTypeEnumForward),
+				TargetGroupArn: aws.String("tg-8132"),
+			}},
+		},
+	}}, nil)
+
+	scheme, err := setupScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: infrav1.AWSClusterSpec{ControlPlaneLoadBalancer: &infrav1.AWSLoadBalancerSpec{
+			Name:             aws.String(lbName),
+			Scheme:           &infrav1.ELBSchemeInternetFacing,
+			LoadBalancerType: infrav1.LoadBalancerTypeNLB,
+		}},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "foo",
+				Name:      "bar",
+			},
+		},
+		AWSCluster: awsCluster,
+		Client:     client,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	s := &Service{
+		scope:       clusterScope,
+		ELBV2Client: elbV2ApiMock,
+	}
+	targetGroups, err := s.GetAPIServerLBListenerTargetGroups(clusterScope.ControlPlaneLoadBalancer())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(targetGroups).To(Equal(map[int64]string{
+		6443: "tg-6443",
+		8132: "tg-8132",
+	}))
+}
+
 func TestChunkELBs(t *testing.T) {
 	base := "loadbalancer"
 	var names []string
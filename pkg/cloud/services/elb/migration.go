@@ -0,0 +1,380 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elb
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// LoadBalancerMigrationTimeout is how long the migration of the control plane load balancer waits for the
+// control plane instances to be healthy targets of the Network Load Balancer before it is aborted.
+const LoadBalancerMigrationTimeout = 30 * time.Minute
+
+// loadBalancerMigrationConditions are the conditions reporting on the phases of the migration of the control
+// plane load balancer.
+var loadBalancerMigrationConditions = []clusterv1.ConditionType{
+	infrav1.LoadBalancerMigrationProvisionedCondition,
+	infrav1.LoadBalancerMigrationTargetsHealthyCondition,
+	infrav1.LoadBalancerMigrationEndpointUpdatedCondition,
+	infrav1.LoadBalancerMigrationClassicLoadBalancerDeletedCondition,
+}
+
+// isMigratingControlPlaneLoadBalancer returns true if the control plane load balancer is migrated from the
+// Classic Load Balancer of the status to the Network Load Balancer of the spec.
+func (s *Service) isMigratingControlPlaneLoadBalancer(lbSpec *infrav1.AWSLoadBalancerSpec) bool {
+	if lbSpec != s.scope.ControlPlaneLoadBalancer() || lbSpec.LoadBalancerType != infrav1.LoadBalancerTypeNLB {
+		return false
+	}
+	if s.scope.ControlPlaneLoadBalancerMigration() != nil {
+		return true
+	}
+	apiServerELB := s.scope.Network().APIServerELB
+	return apiServerELB.LoadBalancerType == infrav1.LoadBalancerTypeClassic && apiServerELB.Name != ""
+}
+
+// reconcileLoadBalancerMigration migrates the control plane load balancer from a Classic Load Balancer to a
+// Network Load Balancer. Each call runs at most one phase of the migration, whose progress is stored in the
+// status of the cluster, so that the phase is resumed by the next reconciliation.
+func (s *Service) reconcileLoadBalancerMigration(lbSpec *infrav1.AWSLoadBalancerSpec) error {
+	migration := s.scope.ControlPlaneLoadBalancerMigration()
+	if migration == nil {
+		if s.scope.InfraCluster().GetAnnotations()[infrav1.MigrateControlPlaneLoadBalancerAnnotation] != "true" {
+			return errors.Errorf("the control plane load balancer can't be migrated to a network load balancer unless the %s annotation is set to \"true\"",
+				infrav1.MigrateControlPlaneLoadBalancerAnnotation)
+		}
+
+		apiServerELB := s.scope.Network().APIServerELB
+		migration = &infrav1.LoadBalancerMigration{
+			Phase:                      infrav1.LoadBalancerMigrationPhaseProvisioning,
+			StartTime:                  metav1.Now(),
+			ClassicLoadBalancerName:    apiServerELB.Name,
+			ClassicLoadBalancerDNSName: apiServerELB.DNSName,
+		}
+		s.scope.SetControlPlaneLoadBalancerMigration(migration)
+		for _, condition := range loadBalancerMigrationConditions {
+			conditions.MarkUnknown(s.scope.InfraCluster(), condition, "", "")
+		}
+		record.Eventf(s.scope.InfraCluster(), "LoadBalancerMigrationStarted",
+			"Started the migration of control plane load balancer %q to a network load balancer", migration.ClassicLoadBalancerName)
+	}
+
+	switch migration.Phase {
+	case infrav1.LoadBalancerMigrationPhaseProvisioning:
+		return s.provisionMigratedLoadBalancer(lbSpec, migration)
+	case infrav1.LoadBalancerMigrationPhaseWaitingForHealthyTargets:
+		return s.waitForMigratedLoadBalancerTargets(lbSpec, migration)
+	case infrav1.LoadBalancerMigrationPhaseUpdatingEndpoint:
+		return s.updateMigratedLoadBalancerEndpoint(lbSpec, migration)
+	case infrav1.LoadBalancerMigrationPhaseDeletingClassicLoadBalancer:
+		return s.deleteMigratedClassicLoadBalancer(lbSpec, migration)
+	case infrav1.LoadBalancerMigrationPhaseAborted:
+		// The classic load balancer keeps serving the control plane until the type is set back to classic.
+		return s.reconcileClassicLoadBalancer()
+	}
+
+	return errors.Errorf("unknown control plane load balancer migration phase %q", migration.Phase)
+}
+
+// provisionMigratedLoadBalancer creates the Network Load Balancer and its target groups, and registers the
+// control plane instances with them.
+func (s *Service) provisionMigratedLoadBalancer(lbSpec *infrav1.AWSLoadBalancerSpec, migration *infrav1.LoadBalancerMigration) error {
+	if err := s.reconcileClassicLoadBalancer(); err != nil {
+		return err
+	}
+
+	name, err := LBName(s.scope, lbSpec)
+	if err != nil {
+		return errors.Wrap(err, "failed to get control plane load balancer name")
+	}
+	lb, err := s.describeLB(name, lbSpec)
+	if IsNotFound(err) {
+		var spec *infrav1.LoadBalancer
+		spec, err = s.getAPIServerLBSpec(name, lbSpec)
+		if err != nil {
+			return err
+		}
+		lb, err = s.createLB(spec, lbSpec)
+		if err == nil {
+			s.scope.Info("Created network load balancer replacing the classic control plane load balancer", "name", lb.Name)
+		}
+	}
+	if err != nil {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerMigrationProvisionedCondition, infrav1.LoadBalancerMigrationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return errors.Wrapf(err, "failed to provision load balancer %q", name)
+	}
+	migration.LoadBalancerName = lb.Name
+	migration.LoadBalancerDNSName = lb.DNSName
+
+	if _, err := s.syncMigratedLoadBalancerTargets(lb, migration); err != nil {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerMigrationProvisionedCondition, infrav1.LoadBalancerMigrationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+
+	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.LoadBalancerMigrationProvisionedCondition)
+	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerMigrationTargetsHealthyCondition, infrav1.WaitingForHealthyTargetsReason, clusterv1.ConditionSeverityInfo, "")
+	migration.Phase = infrav1.LoadBalancerMigrationPhaseWaitingForHealthyTargets
+	return nil
+}
+
+// waitForMigratedLoadBalancerTargets waits for the control plane instances to be healthy targets of the
+// Network Load Balancer, and aborts the migration if they aren't within LoadBalancerMigrationTimeout.
+func (s *Service) waitForMigratedLoadBalancerTargets(lbSpec *infrav1.AWSLoadBalancerSpec, migration *infrav1.LoadBalancerMigration) error {
+	if err := s.reconcileClassicLoadBalancer(); err != nil {
+		return err
+	}
+
+	lb, err := s.describeLB(migration.LoadBalancerName, lbSpec)
+	if IsNotFound(err) {
+		// The load balancer was deleted behind our back, create it again.
+		migration.Phase = infrav1.LoadBalancerMigrationPhaseProvisioning
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	healthy, err := s.syncMigratedLoadBalancerTargets(lb, migration)
+	if err != nil {
+		return err
+	}
+	if healthy {
+		conditions.MarkTrue(s.scope.InfraCluster(), infrav1.LoadBalancerMigrationTargetsHealthyCondition)
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerMigrationEndpointUpdatedCondition, infrav1.WaitingForEndpointUpdateReason, clusterv1.ConditionSeverityInfo, "")
+		migration.Phase = infrav1.LoadBalancerMigrationPhaseUpdatingEndpoint
+		return nil
+	}
+
+	if time.Since(migration.StartTime.Time) > LoadBalancerMigrationTimeout {
+		return s.abortLoadBalancerMigration(migration)
+	}
+	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerMigrationTargetsHealthyCondition, infrav1.WaitingForHealthyTargetsReason, clusterv1.ConditionSeverityInfo,
+		"waiting for the control plane instances to be healthy targets of load balancer %q", lb.Name)
+	return nil
+}
+
+// updateMigratedLoadBalancerEndpoint switches the status and the control plane endpoint to the Network Load
+// Balancer. The next phase starts once the updated endpoint is observed, so that it isn't skipped if the
+// update of the spec of the cluster is rejected.
+func (s *Service) updateMigratedLoadBalancerEndpoint(lbSpec *infrav1.AWSLoadBalancerSpec, migration *infrav1.LoadBalancerMigration) error {
+	if err := s.reconcileV2LB(lbSpec); err != nil {
+		return err
+	}
+	if _, err := s.syncMigratedLoadBalancerTargets(&s.scope.Network().APIServerELB, migration); err != nil {
+		return err
+	}
+
+	if endpoint := s.scope.ControlPlaneEndpoint(); endpoint.Host != migration.LoadBalancerDNSName {
+		endpoint.Host = migration.LoadBalancerDNSName
+		s.scope.SetControlPlaneEndpoint(endpoint)
+		s.scope.Info("Switched the control plane endpoint to the network load balancer", "host", endpoint.Host)
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerMigrationEndpointUpdatedCondition, infrav1.WaitingForEndpointUpdateReason, clusterv1.ConditionSeverityInfo, "")
+		return nil
+	}
+
+	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.LoadBalancerMigrationEndpointUpdatedCondition)
+	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerMigrationClassicLoadBalancerDeletedCondition, infrav1.WaitingForDeletionConfirmationReason, clusterv1.ConditionSeverityInfo, "")
+	migration.Phase = infrav1.LoadBalancerMigrationPhaseDeletingClassicLoadBalancer
+	return nil
+}
+
+// deleteMigratedClassicLoadBalancer deletes the Classic Load Balancer once the control plane endpoint is the
+// DNS name of the Network Load Balancer and the deletion is confirmed with the
+// DeleteClassicControlPlaneLoadBalancerAnnotation. The migration completes once the Classic Load Balancer is
+// gone, which is checked by the next reconciliation.
+func (s *Service) deleteMigratedClassicLoadBalancer(lbSpec *infrav1.AWSLoadBalancerSpec, migration *infrav1.LoadBalancerMigration) error {
+	if err := s.reconcileV2LB(lbSpec); err != nil {
+		return err
+	}
+
+	// The endpoint of the Cluster, the kubeconfigs and the API server certificates aren't updated by CAPA, so the
+	// Classic Load Balancer may still be in use until the deletion is confirmed.
+	if s.scope.InfraCluster().GetAnnotations()[infrav1.DeleteClassicControlPlaneLoadBalancerAnnotation] != "true" {
+		if _, err := s.syncMigratedLoadBalancerTargets(&s.scope.Network().APIServerELB, migration); err != nil {
+			return err
+		}
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerMigrationClassicLoadBalancerDeletedCondition, infrav1.WaitingForDeletionConfirmationReason, clusterv1.ConditionSeverityInfo,
+			"set the %s annotation to \"true\" once nothing uses the endpoint of classic load balancer %q anymore",
+			infrav1.DeleteClassicControlPlaneLoadBalancerAnnotation, migration.ClassicLoadBalancerName)
+		return nil
+	}
+
+	classicELB, err := s.describeClassicELB(migration.ClassicLoadBalancerName)
+	switch {
+	case IsNotFound(err):
+	case err != nil:
+		return err
+	case classicELB.IsUnmanaged(s.scope.Name()):
+		s.scope.Debug("Found unmanaged classic load balancer for apiserver, skipping deletion", "api-server-elb-name", classicELB.Name)
+	default:
+		if err := s.deleteClassicELB(classicELB.Name); err != nil {
+			conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerMigrationClassicLoadBalancerDeletedCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
+			return errors.Wrapf(err, "failed to delete classic load balancer %q", classicELB.Name)
+		}
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerMigrationClassicLoadBalancerDeletedCondition, infrav1.DeletingClassicLoadBalancerReason, clusterv1.ConditionSeverityInfo, "")
+		return nil
+	}
+
+	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.LoadBalancerMigrationClassicLoadBalancerDeletedCondition)
+	s.scope.SetControlPlaneLoadBalancerMigration(nil)
+	record.Eventf(s.scope.InfraCluster(), "LoadBalancerMigrationCompleted",
+		"Migrated control plane load balancer %q to network load balancer %q", migration.ClassicLoadBalancerName, migration.LoadBalancerName)
+	return nil
+}
+
+// abortLoadBalancerMigration deletes the Network Load Balancer, and keeps the Classic Load Balancer in use.
+func (s *Service) abortLoadBalancerMigration(migration *infrav1.LoadBalancerMigration) error {
+	if err := s.deleteMigratedLoadBalancer(migration.LoadBalancerName); err != nil {
+		return err
+	}
+
+	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerMigrationTargetsHealthyCondition, infrav1.LoadBalancerMigrationAbortedReason, clusterv1.ConditionSeverityWarning,
+		"the control plane instances weren't healthy targets of load balancer %q after %s, classic load balancer %q is kept",
+		migration.LoadBalancerName, LoadBalancerMigrationTimeout, migration.ClassicLoadBalancerName)
+	record.Warnf(s.scope.InfraCluster(), "LoadBalancerMigrationAborted",
+		"Aborted the migration of control plane load balancer %q: load balancer %q didn't become healthy in time", migration.ClassicLoadBalancerName, migration.LoadBalancerName)
+	migration.Phase = infrav1.LoadBalancerMigrationPhaseAborted
+	migration.LoadBalancerName = ""
+	migration.LoadBalancerDNSName = ""
+	return nil
+}
+
+// revertLoadBalancerMigration deletes the Network Load Balancer of a migration which was reverted by setting
+// the type of the control plane load balancer back to classic.
+func (s *Service) revertLoadBalancerMigration() error {
+	name, err := LBName(s.scope, s.scope.ControlPlaneLoadBalancer())
+	if err != nil {
+		return errors.Wrap(err, "failed to get control plane load balancer name")
+	}
+	if err := s.deleteMigratedLoadBalancer(name); err != nil {
+		return err
+	}
+
+	for _, condition := range loadBalancerMigrationConditions {
+		conditions.Delete(s.scope.InfraCluster(), condition)
+	}
+	s.scope.SetControlPlaneLoadBalancerMigration(nil)
+	record.Eventf(s.scope.InfraCluster(), "LoadBalancerMigrationReverted", "Reverted the migration of the control plane load balancer to a network load balancer")
+	return nil
+}
+
+// deleteMigratedLoadBalancer deletes the Network Load Balancer created by a migration, if any.
+func (s *Service) deleteMigratedLoadBalancer(name string) error {
+	if name == "" {
+		return nil
+	}
+	lb, err := s.describeLB(name, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if lb.IsUnmanaged(s.scope.Name()) {
+		return nil
+	}
+
+	if err := s.deleteLB(lb.ARN); err != nil {
+		return errors.Wrapf(err, "failed to delete load balancer %q", name)
+	}
+	s.scope.Info("Deleted network load balancer of the control plane load balancer migration", "name", name)
+	return nil
+}
+
+// syncMigratedLoadBalancerTargets registers the instances of the Classic Load Balancer with the target groups
+// of the Network Load Balancer, and deregisters the targets which are no longer registered with the Classic
+// Load Balancer. It returns true if every instance is a healthy target of every target group.
+func (s *Service) syncMigratedLoadBalancerTargets(lb *infrav1.LoadBalancer, migration *infrav1.LoadBalancerMigration) (bool, error) {
+	out, err := s.ELBClient.DescribeLoadBalancers(&elb.DescribeLoadBalancersInput{
+		LoadBalancerNames: aws.StringSlice([]string{migration.ClassicLoadBalancerName}),
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "error describing ELB %q", migration.ClassicLoadBalancerName)
+	}
+	if len(out.LoadBalancerDescriptions) != 1 {
+		return false, errors.Errorf("expected 1 ELB description for %q, got %d", migration.ClassicLoadBalancerName, len(out.LoadBalancerDescriptions))
+	}
+	instanceIDs := sets.New[string]()
+	for _, instance := range out.LoadBalancerDescriptions[0].Instances {
+		instanceIDs.Insert(aws.StringValue(instance.InstanceId))
+	}
+
+	targetGroups, err := s.ELBV2Client.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+		LoadBalancerArn: aws.String(lb.ARN),
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "error describing ELB's target groups %q", lb.Name)
+	}
+
+	healthy := instanceIDs.Len() > 0 && len(targetGroups.TargetGroups) > 0
+	for _, tg := range targetGroups.TargetGroups {
+		health, err := s.ELBV2Client.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{
+			TargetGroupArn: tg.TargetGroupArn,
+		})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to describe the health of the targets of target group %q", aws.StringValue(tg.TargetGroupName))
+		}
+
+		registered := sets.New[string]()
+		var stale []*elbv2.TargetDescription
+		for _, description := range health.TargetHealthDescriptions {
+			id := aws.StringValue(description.Target.Id)
+			registered.Insert(id)
+			switch {
+			case !instanceIDs.Has(id):
+				stale = append(stale, description.Target)
+			case description.TargetHealth == nil || aws.StringValue(description.TargetHealth.State) != elbv2.TargetHealthStateEnumHealthy:
+				healthy = false
+			}
+		}
+
+		if missing := sets.List(instanceIDs.Difference(registered)); len(missing) > 0 {
+			healthy = false
+			targets := make([]*elbv2.TargetDescription, 0, len(missing))
+			for _, id := range missing {
+				targets = append(targets, &elbv2.TargetDescription{Id: aws.String(id), Port: tg.Port})
+			}
+			if _, err := s.ELBV2Client.RegisterTargets(&elbv2.RegisterTargetsInput{
+				TargetGroupArn: tg.TargetGroupArn,
+				Targets:        targets,
+			}); err != nil {
+				return false, errors.Wrapf(err, "failed to register instances with target group %q", aws.StringValue(tg.TargetGroupName))
+			}
+		}
+		if len(stale) > 0 {
+			if _, err := s.ELBV2Client.DeregisterTargets(&elbv2.DeregisterTargetsInput{
+				TargetGroupArn: tg.TargetGroupArn,
+				Targets:        stale,
+			}); err != nil {
+				return false, errors.Wrapf(err, "failed to deregister instances from target group %q", aws.StringValue(tg.TargetGroupName))
+			}
+		}
+	}
+
+	return healthy, nil
+}
@@ -0,0 +1,454 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/test/mocks"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	migrationClusterName = "bar"
+	migrationClassicName = "bar-apiserver"
+	migrationClassicDNS  = "bar-apiserver.us-west-1.elb.amazonaws.com"
+	migrationNLBName     = "foo-bar-apiserver"
+	migrationNLBArn      = "arn::nlb"
+	migrationNLBDNS      = "foo-bar-apiserver.elb.us-west-1.amazonaws.com"
+	migrationTGArn       = "arn::tg-1"
+	migrationVPCID       = "vpc-id"
+)
+
+func TestReconcileLoadBalancerMigration(t *testing.T) {
+	waiting := func() *infrav1.LoadBalancerMigration {
+		return &infrav1.LoadBalancerMigration{
+			Phase:                      infrav1.LoadBalancerMigrationPhaseWaitingForHealthyTargets,
+			StartTime:                  metav1.Now(),
+			ClassicLoadBalancerName:    migrationClassicName,
+			ClassicLoadBalancerDNSName: migrationClassicDNS,
+			LoadBalancerName:           migrationNLBName,
+			LoadBalancerDNSName:        migrationNLBDNS,
+		}
+	}
+	withPhase := func(m *infrav1.LoadBalancerMigration, phase infrav1.LoadBalancerMigrationPhase) *infrav1.LoadBalancerMigration {
+		m.Phase = phase
+		return m
+	}
+
+	tests := []struct {
+		name          string
+		lbType        infrav1.LoadBalancerType
+		annotated     bool
+		confirmed     bool
+		migration     *infrav1.LoadBalancerMigration
+		endpointHost  string
+		elbAPIMocks   func(m *mocks.MockELBAPIMockRecorder)
+		elbV2APIMocks func(m *mocks.MockELBV2APIMockRecorder)
+		check         func(g *WithT, awsCluster *infrav1.AWSCluster, err error)
+	}{
+		{
+			name:         "does not start without the annotation",
+			lbType:       infrav1.LoadBalancerTypeNLB,
+			endpointHost: migrationClassicDNS,
+			check: func(g *WithT, awsCluster *infrav1.AWSCluster, err error) {
+				g.Expect(err).To(MatchError(ContainSubstring(infrav1.MigrateControlPlaneLoadBalancerAnnotation)))
+				g.Expect(awsCluster.Status.ControlPlaneLoadBalancerMigration).To(BeNil())
+			},
+		},
+		{
+			name:         "registers the instances of the classic load balancer with the network load balancer",
+			lbType:       infrav1.LoadBalancerTypeNLB,
+			annotated:    true,
+			endpointHost: migrationClassicDNS,
+			elbAPIMocks: func(m *mocks.MockELBAPIMockRecorder) {
+				expectMigratedClassicELB(m, false)
+			},
+			elbV2APIMocks: func(m *mocks.MockELBV2APIMockRecorder) {
+				// The network load balancer was created before the controller restarted.
+				expectMigratedNLB(m, false)
+				expectMigratedNLBTargetHealth(m, map[string]string{"i-1": elbv2.TargetHealthStateEnumHealthy, "i-3": elbv2.TargetHealthStateEnumHealthy})
+				m.RegisterTargets(&elbv2.RegisterTargetsInput{
+					TargetGroupArn: aws.String(migrationTGArn),
+					Targets:        []*elbv2.TargetDescription{{Id: aws.String("i-2"), Port: aws.Int64(6443)}},
+				}).Return(&elbv2.RegisterTargetsOutput{}, nil)
+				m.DeregisterTargets(&elbv2.DeregisterTargetsInput{
+					TargetGroupArn: aws.String(migrationTGArn),
+					Targets:        []*elbv2.TargetDescription{{Id: aws.String("i-3"), Port: aws.Int64(6443)}},
+				}).Return(&elbv2.DeregisterTargetsOutput{}, nil)
+			},
+			check: func(g *WithT, awsCluster *infrav1.AWSCluster, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				migration := awsCluster.Status.ControlPlaneLoadBalancerMigration
+				g.Expect(migration).NotTo(BeNil())
+				g.Expect(migration.Phase).To(Equal(infrav1.LoadBalancerMigrationPhaseWaitingForHealthyTargets))
+				g.Expect(migration.ClassicLoadBalancerName).To(Equal(migrationClassicName))
+				g.Expect(migration.ClassicLoadBalancerDNSName).To(Equal(migrationClassicDNS))
+				g.Expect(migration.LoadBalancerName).To(Equal(migrationNLBName))
+				g.Expect(migration.LoadBalancerDNSName).To(Equal(migrationNLBDNS))
+				g.Expect(conditions.IsTrue(awsCluster, infrav1.LoadBalancerMigrationProvisionedCondition)).To(BeTrue())
+				g.Expect(awsCluster.Status.Network.APIServerELB.Name).To(Equal(migrationClassicName))
+			},
+		},
+		{
+			name:         "waits for the targets to be healthy",
+			lbType:       infrav1.LoadBalancerTypeNLB,
+			migration:    waiting(),
+			endpointHost: migrationClassicDNS,
+			elbAPIMocks: func(m *mocks.MockELBAPIMockRecorder) {
+				expectMigratedClassicELB(m, false)
+			},
+			elbV2APIMocks: func(m *mocks.MockELBV2APIMockRecorder) {
+				expectMigratedNLB(m, false)
+				expectMigratedNLBTargetHealth(m, map[string]string{"i-1": elbv2.TargetHealthStateEnumHealthy, "i-2": elbv2.TargetHealthStateEnumInitial})
+			},
+			check: func(g *WithT, awsCluster *infrav1.AWSCluster, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(awsCluster.Status.ControlPlaneLoadBalancerMigration.Phase).To(Equal(infrav1.LoadBalancerMigrationPhaseWaitingForHealthyTargets))
+				g.Expect(conditions.GetReason(awsCluster, infrav1.LoadBalancerMigrationTargetsHealthyCondition)).To(Equal(infrav1.WaitingForHealthyTargetsReason))
+			},
+		},
+		{
+			name:         "switches to updating the endpoint once the targets are healthy",
+			lbType:       infrav1.LoadBalancerTypeNLB,
+			migration:    waiting(),
+			endpointHost: migrationClassicDNS,
+			elbAPIMocks: func(m *mocks.MockELBAPIMockRecorder) {
+				expectMigratedClassicELB(m, false)
+			},
+			elbV2APIMocks: func(m *mocks.MockELBV2APIMockRecorder) {
+				expectMigratedNLB(m, false)
+				expectMigratedNLBTargetHealth(m, map[string]string{"i-1": elbv2.TargetHealthStateEnumHealthy, "i-2": elbv2.TargetHealthStateEnumHealthy})
+			},
+			check: func(g *WithT, awsCluster *infrav1.AWSCluster, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(awsCluster.Status.ControlPlaneLoadBalancerMigration.Phase).To(Equal(infrav1.LoadBalancerMigrationPhaseUpdatingEndpoint))
+				g.Expect(conditions.IsTrue(awsCluster, infrav1.LoadBalancerMigrationTargetsHealthyCondition)).To(BeTrue())
+				// The status is switched by the next reconciliation, once the phase is stored.
+				g.Expect(awsCluster.Status.Network.APIServerELB.Name).To(Equal(migrationClassicName))
+			},
+		},
+		{
+			name:   "aborts when the targets are not healthy in time",
+			lbType: infrav1.LoadBalancerTypeNLB,
+			migration: func() *infrav1.LoadBalancerMigration {
+				m := waiting()
+				m.StartTime = metav1.NewTime(time.Now().Add(-LoadBalancerMigrationTimeout - time.Minute))
+				return m
+			}(),
+			endpointHost: migrationClassicDNS,
+			elbAPIMocks: func(m *mocks.MockELBAPIMockRecorder) {
+				expectMigratedClassicELB(m, false)
+			},
+			elbV2APIMocks: func(m *mocks.MockELBV2APIMockRecorder) {
+				expectMigratedNLB(m, true)
+				expectMigratedNLBTargetHealth(m, map[string]string{"i-1": elbv2.TargetHealthStateEnumUnhealthy, "i-2": elbv2.TargetHealthStateEnumUnhealthy})
+				expectMigratedNLBDeletion(m)
+			},
+			check: func(g *WithT, awsCluster *infrav1.AWSCluster, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				migration := awsCluster.Status.ControlPlaneLoadBalancerMigration
+				g.Expect(migration.Phase).To(Equal(infrav1.LoadBalancerMigrationPhaseAborted))
+				g.Expect(migration.LoadBalancerName).To(BeEmpty())
+				g.Expect(conditions.GetReason(awsCluster, infrav1.LoadBalancerMigrationTargetsHealthyCondition)).To(Equal(infrav1.LoadBalancerMigrationAbortedReason))
+				g.Expect(awsCluster.Status.Network.APIServerELB.Name).To(Equal(migrationClassicName))
+			},
+		},
+		{
+			name:         "keeps the classic load balancer once aborted",
+			lbType:       infrav1.LoadBalancerTypeNLB,
+			migration:    withPhase(waiting(), infrav1.LoadBalancerMigrationPhaseAborted),
+			endpointHost: migrationClassicDNS,
+			elbAPIMocks: func(m *mocks.MockELBAPIMockRecorder) {
+				expectMigratedClassicELB(m, false)
+			},
+			check: func(g *WithT, awsCluster *infrav1.AWSCluster, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(awsCluster.Status.ControlPlaneLoadBalancerMigration.Phase).To(Equal(infrav1.LoadBalancerMigrationPhaseAborted))
+				g.Expect(awsCluster.Status.Network.APIServerELB.Name).To(Equal(migrationClassicName))
+			},
+		},
+		{
+			name:         "switches the status and the endpoint to the network load balancer",
+			lbType:       infrav1.LoadBalancerTypeNLB,
+			migration:    withPhase(waiting(), infrav1.LoadBalancerMigrationPhaseUpdatingEndpoint),
+			endpointHost: migrationClassicDNS,
+			elbAPIMocks: func(m *mocks.MockELBAPIMockRecorder) {
+				expectMigratedClassicELB(m, false)
+			},
+			elbV2APIMocks: func(m *mocks.MockELBV2APIMockRecorder) {
+				expectMigratedNLB(m, false)
+				expectMigratedNLBTargetHealth(m, map[string]string{"i-1": elbv2.TargetHealthStateEnumHealthy, "i-2": elbv2.TargetHealthStateEnumHealthy})
+			},
+			check: func(g *WithT, awsCluster *infrav1.AWSCluster, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(awsCluster.Status.ControlPlaneLoadBalancerMigration.Phase).To(Equal(infrav1.LoadBalancerMigrationPhaseUpdatingEndpoint))
+				g.Expect(awsCluster.Status.Network.APIServerELB.Name).To(Equal(migrationNLBName))
+				g.Expect(awsCluster.Status.Network.APIServerELB.DNSName).To(Equal(migrationNLBDNS))
+				g.Expect(awsCluster.Spec.ControlPlaneEndpoint).To(Equal(clusterv1.APIEndpoint{Host: migrationNLBDNS, Port: 6443}))
+				g.Expect(conditions.GetReason(awsCluster, infrav1.LoadBalancerMigrationEndpointUpdatedCondition)).To(Equal(infrav1.WaitingForEndpointUpdateReason))
+			},
+		},
+		{
+			name:         "switches to deleting the classic load balancer once the endpoint is updated",
+			lbType:       infrav1.LoadBalancerTypeNLB,
+			migration:    withPhase(waiting(), infrav1.LoadBalancerMigrationPhaseUpdatingEndpoint),
+			endpointHost: migrationNLBDNS,
+			elbAPIMocks: func(m *mocks.MockELBAPIMockRecorder) {
+				expectMigratedClassicELB(m, false)
+			},
+			elbV2APIMocks: func(m *mocks.MockELBV2APIMockRecorder) {
+				expectMigratedNLB(m, false)
+				expectMigratedNLBTargetHealth(m, map[string]string{"i-1": elbv2.TargetHealthStateEnumHealthy, "i-2": elbv2.TargetHealthStateEnumHealthy})
+			},
+			check: func(g *WithT, awsCluster *infrav1.AWSCluster, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(awsCluster.Status.ControlPlaneLoadBalancerMigration.Phase).To(Equal(infrav1.LoadBalancerMigrationPhaseDeletingClassicLoadBalancer))
+				g.Expect(conditions.IsTrue(awsCluster, infrav1.LoadBalancerMigrationEndpointUpdatedCondition)).To(BeTrue())
+			},
+		},
+		{
+			name:         "keeps the classic load balancer until its deletion is confirmed",
+			lbType:       infrav1.LoadBalancerTypeNLB,
+			migration:    withPhase(waiting(), infrav1.LoadBalancerMigrationPhaseDeletingClassicLoadBalancer),
+			endpointHost: migrationNLBDNS,
+			elbAPIMocks: func(m *mocks.MockELBAPIMockRecorder) {
+				expectMigratedClassicELB(m, true)
+			},
+			elbV2APIMocks: func(m *mocks.MockELBV2APIMockRecorder) {
+				expectMigratedNLB(m, false)
+				expectMigratedNLBTargetHealth(m, map[string]string{"i-1": elbv2.TargetHealthStateEnumHealthy, "i-2": elbv2.TargetHealthStateEnumHealthy})
+			},
+			check: func(g *WithT, awsCluster *infrav1.AWSCluster, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(awsCluster.Status.ControlPlaneLoadBalancerMigration.Phase).To(Equal(infrav1.LoadBalancerMigrationPhaseDeletingClassicLoadBalancer))
+				g.Expect(conditions.GetReason(awsCluster, infrav1.LoadBalancerMigrationClassicLoadBalancerDeletedCondition)).To(Equal(infrav1.WaitingForDeletionConfirmationReason))
+				g.Expect(conditions.GetMessage(awsCluster, infrav1.LoadBalancerMigrationClassicLoadBalancerDeletedCondition)).To(ContainSubstring(infrav1.DeleteClassicControlPlaneLoadBalancerAnnotation))
+				g.Expect(awsCluster.Status.Network.APIServerELB.Name).To(Equal(migrationNLBName))
+			},
+		},
+		{
+			name:         "deletes the classic load balancer once its deletion is confirmed",
+			lbType:       infrav1.LoadBalancerTypeNLB,
+			confirmed:    true,
+			migration:    withPhase(waiting(), infrav1.LoadBalancerMigrationPhaseDeletingClassicLoadBalancer),
+			endpointHost: migrationNLBDNS,
+			elbAPIMocks: func(m *mocks.MockELBAPIMockRecorder) {
+				expectMigratedClassicELB(m, true).Times(1)
+				m.DeleteLoadBalancer(&elb.DeleteLoadBalancerInput{LoadBalancerName: aws.String(migrationClassicName)}).
+					Return(&elb.DeleteLoadBalancerOutput{}, nil)
+			},
+			elbV2APIMocks: func(m *mocks.MockELBV2APIMockRecorder) {
+				expectMigratedNLB(m, false)
+			},
+			check: func(g *WithT, awsCluster *infrav1.AWSCluster, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(awsCluster.Status.ControlPlaneLoadBalancerMigration.Phase).To(Equal(infrav1.LoadBalancerMigrationPhaseDeletingClassicLoadBalancer))
+				g.Expect(conditions.GetReason(awsCluster, infrav1.LoadBalancerMigrationClassicLoadBalancerDeletedCondition)).To(Equal(infrav1.DeletingClassicLoadBalancerReason))
+			},
+		},
+		{
+			name:         "completes once the classic load balancer is deleted",
+			lbType:       infrav1.LoadBalancerTypeNLB,
+			confirmed:    true,
+			migration:    withPhase(waiting(), infrav1.LoadBalancerMigrationPhaseDeletingClassicLoadBalancer),
+			endpointHost: migrationNLBDNS,
+			elbAPIMocks: func(m *mocks.MockELBAPIMockRecorder) {
+				m.DescribeLoadBalancers(gomock.Any()).
+					Return(nil, awserr.New(elb.ErrCodeAccessPointNotFoundException, "not found", nil))
+			},
+			elbV2APIMocks: func(m *mocks.MockELBV2APIMockRecorder) {
+				expectMigratedNLB(m, false)
+			},
+			check: func(g *WithT, awsCluster *infrav1.AWSCluster, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(awsCluster.Status.ControlPlaneLoadBalancerMigration).To(BeNil())
+				g.Expect(conditions.IsTrue(awsCluster, infrav1.LoadBalancerMigrationClassicLoadBalancerDeletedCondition)).To(BeTrue())
+				g.Expect(awsCluster.Status.Network.APIServerELB.Name).To(Equal(migrationNLBName))
+			},
+		},
+		{
+			name:         "deletes the network load balancer when the type is set back to classic",
+			lbType:       infrav1.LoadBalancerTypeClassic,
+			migration:    waiting(),
+			endpointHost: migrationClassicDNS,
+			elbAPIMocks: func(m *mocks.MockELBAPIMockRecorder) {
+				expectMigratedClassicELB(m, false)
+			},
+			elbV2APIMocks: func(m *mocks.MockELBV2APIMockRecorder) {
+				expectMigratedNLB(m, true)
+				expectMigratedNLBDeletion(m)
+			},
+			check: func(g *WithT, awsCluster *infrav1.AWSCluster, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(awsCluster.Status.ControlPlaneLoadBalancerMigration).To(BeNil())
+				g.Expect(conditions.Has(awsCluster, infrav1.LoadBalancerMigrationProvisionedCondition)).To(BeFalse())
+				g.Expect(awsCluster.Status.Network.APIServerELB.Name).To(Equal(migrationClassicName))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			elbAPIMocks := mocks.NewMockELBAPI(mockCtrl)
+			elbV2APIMocks := mocks.NewMockELBV2API(mockCtrl)
+			if tc.elbAPIMocks != nil {
+				tc.elbAPIMocks(elbAPIMocks.EXPECT())
+			}
+			if tc.elbV2APIMocks != nil {
+				tc.elbV2APIMocks(elbV2APIMocks.EXPECT())
+			}
+
+			scheme, err := setupScheme()
+			g.Expect(err).NotTo(HaveOccurred())
+			awsCluster := &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: migrationClusterName, Namespace: "foo"},
+				Spec: infrav1.AWSClusterSpec{
+					ControlPlaneLoadBalancer: &infrav1.AWSLoadBalancerSpec{LoadBalancerType: tc.lbType},
+					ControlPlaneEndpoint:     clusterv1.APIEndpoint{Host: tc.endpointHost, Port: 6443},
+					NetworkSpec:              infrav1.NetworkSpec{VPC: infrav1.VPCSpec{ID: migrationVPCID}},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.NetworkStatus{
+						APIServerELB: infrav1.LoadBalancer{
+							Name:             migrationClassicName,
+							DNSName:          migrationClassicDNS,
+							LoadBalancerType: infrav1.LoadBalancerTypeClassic,
+						},
+					},
+					ControlPlaneLoadBalancerMigration: tc.migration,
+				},
+			}
+			if tc.annotated {
+				awsCluster.Annotations = map[string]string{infrav1.MigrateControlPlaneLoadBalancerAnnotation: "true"}
+			}
+			if tc.confirmed {
+				awsCluster.Annotations = map[string]string{infrav1.DeleteClassicControlPlaneLoadBalancerAnnotation: "true"}
+			}
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     fake.NewClientBuilder().WithScheme(scheme).Build(),
+				Cluster:    &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: migrationClusterName}},
+				AWSCluster: awsCluster,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			s := &Service{
+				scope:       clusterScope,
+				ELBClient:   elbAPIMocks,
+				ELBV2Client: elbV2APIMocks,
+			}
+			tc.check(g, awsCluster, s.ReconcileLoadbalancers())
+		})
+	}
+}
+
+// expectMigratedClassicELB expects the classic load balancer with the instances i-1 and i-2 to be described.
+func expectMigratedClassicELB(m *mocks.MockELBAPIMockRecorder, managed bool) *gomock.Call {
+	tags := []*elb.Tag{}
+	if managed {
+		tags = append(tags, &elb.Tag{Key: aws.String(infrav1.ClusterTagKey(migrationClusterName)), Value: aws.String(string(infrav1.ResourceLifecycleOwned))})
+	}
+	m.DescribeLoadBalancerAttributes(gomock.Any()).Return(&elb.DescribeLoadBalancerAttributesOutput{
+		LoadBalancerAttributes: &elb.LoadBalancerAttributes{
+			CrossZoneLoadBalancing: &elb.CrossZoneLoadBalancing{Enabled: aws.Bool(false)},
+		},
+	}, nil).AnyTimes()
+	m.DescribeTags(gomock.Any()).Return(&elb.DescribeTagsOutput{
+		TagDescriptions: []*elb.TagDescription{{LoadBalancerName: aws.String(migrationClassicName), Tags: tags}},
+	}, nil).AnyTimes()
+	return m.DescribeLoadBalancers(gomock.Any()).Return(&elb.DescribeLoadBalancersOutput{
+		LoadBalancerDescriptions: []*elb.LoadBalancerDescription{
+			{
+				LoadBalancerName: aws.String(migrationClassicName),
+				DNSName:          aws.String(migrationClassicDNS),
+				Scheme:           aws.String(string(infrav1.ELBSchemeInternetFacing)),
+				VPCId:            aws.String(migrationVPCID),
+				Instances:        []*elb.Instance{{InstanceId: aws.String("i-1")}, {InstanceId: aws.String("i-2")}},
+			},
+		},
+	}, nil).AnyTimes()
+}
+
+// expectMigratedNLB expects the network load balancer and its target group to be described.
+func expectMigratedNLB(m *mocks.MockELBV2APIMockRecorder, managed bool) {
+	tags := []*elbv2.Tag{}
+	if managed {
+		tags = append(tags, &elbv2.Tag{Key: aws.String(infrav1.ClusterTagKey(migrationClusterName)), Value: aws.String(string(infrav1.ResourceLifecycleOwned))})
+	}
+	m.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{Names: aws.StringSlice([]string{migrationNLBName})}).Return(&elbv2.DescribeLoadBalancersOutput{
+		LoadBalancers: []*elbv2.LoadBalancer{
+			{
+				LoadBalancerArn:  aws.String(migrationNLBArn),
+				LoadBalancerName: aws.String(migrationNLBName),
+				DNSName:          aws.String(migrationNLBDNS),
+				Scheme:           aws.String(string(infrav1.ELBSchemeInternetFacing)),
+				VpcId:            aws.String(migrationVPCID),
+			},
+		},
+	}, nil).AnyTimes()
+	m.DescribeLoadBalancerAttributes(&elbv2.DescribeLoadBalancerAttributesInput{LoadBalancerArn: aws.String(migrationNLBArn)}).
+		Return(&elbv2.DescribeLoadBalancerAttributesOutput{}, nil).AnyTimes()
+	m.DescribeTags(&elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice([]string{migrationNLBArn})}).Return(&elbv2.DescribeTagsOutput{
+		TagDescriptions: []*elbv2.TagDescription{{ResourceArn: aws.String(migrationNLBArn), Tags: tags}},
+	}, nil).AnyTimes()
+	m.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{LoadBalancerArn: aws.String(migrationNLBArn)}).Return(&elbv2.DescribeTargetGroupsOutput{
+		TargetGroups: []*elbv2.TargetGroup{
+			{TargetGroupArn: aws.String(migrationTGArn), TargetGroupName: aws.String("apiserver-target"), Port: aws.Int64(6443)},
+		},
+	}, nil).AnyTimes()
+}
+
+// expectMigratedNLBTargetHealth expects the health of the targets of the target group to be described.
+func expectMigratedNLBTargetHealth(m *mocks.MockELBV2APIMockRecorder, states map[string]string) {
+	descriptions := []*elbv2.TargetHealthDescription{}
+	for _, id := range []string{"i-1", "i-2", "i-3"} {
+		if state, ok := states[id]; ok {
+			descriptions = append(descriptions, &elbv2.TargetHealthDescription{
+				Target:       &elbv2.TargetDescription{Id: aws.String(id), Port: aws.Int64(6443)},
+				TargetHealth: &elbv2.TargetHealth{State: aws.String(state)},
+			})
+		}
+	}
+	m.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String(migrationTGArn)}).
+		Return(&elbv2.DescribeTargetHealthOutput{TargetHealthDescriptions: descriptions}, nil)
+}
+
+// expectMigratedNLBDeletion expects the network load balancer, its listener and its target group to be deleted.
+func expectMigratedNLBDeletion(m *mocks.MockELBV2APIMockRecorder) {
+	m.DescribeListeners(&elbv2.DescribeListenersInput{LoadBalancerArn: aws.String(migrationNLBArn)}).Return(&elbv2.DescribeListenersOutput{
+		Listeners: []*elbv2.Listener{{ListenerArn: aws.String("arn::listener")}},
+	}, nil)
+	m.DeleteListener(&elbv2.DeleteListenerInput{ListenerArn: aws.String("arn::listener")}).Return(&elbv2.DeleteListenerOutput{}, nil)
+	m.DeleteTargetGroup(&elbv2.DeleteTargetGroupInput{TargetGroupArn: aws.String(migrationTGArn)}).Return(&elbv2.DeleteTargetGroupOutput{}, nil)
+	m.DeleteLoadBalancer(&elbv2.DeleteLoadBalancerInput{LoadBalancerArn: aws.String(migrationNLBArn)}).Return(&elbv2.DeleteLoadBalancerOutput{}, nil)
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eni
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+)
+
+// ReleaseBlocking adds the network interfaces matching the filter, which block the deletion of a security group
+// or a subnet, to the DependencyViolation error of the deletion. Other errors are returned as is.
+// When the cluster is being deleted and is annotated with ForceNetworkCleanupAnnotation, the network interfaces
+// which reference a security group owned by the cluster and are detached, or attached to an instance which is
+// terminated or missing, are released by force: attached ones are detached, and detached ones are deleted. A
+// network interface is only released once the deletion of the cluster, and its attachment, are older than the
+// minimum age. The network interfaces managed by AWS services are only reported.
+func (s *Service) ReleaseBlocking(deleteErr error, blocking *ec2.Filter) error {
+	if code, ok := awserrors.Code(errors.Cause(deleteErr)); !ok || code != awserrors.DependencyViolation {
+		return deleteErr
+	}
+
+	descriptions, err := s.releaseBlocking(blocking)
+	if err != nil {
+		s.scope.Error(err, "Failed to look up the network interfaces blocking a deletion")
+		return deleteErr
+	}
+	if len(descriptions) == 0 {
+		return deleteErr
+	}
+	return errors.Wrapf(deleteErr, "in use by network interfaces %s", strings.Join(descriptions, ", "))
+}
+
+func (s *Service) releaseBlocking(blocking *ec2.Filter) ([]string, error) {
+	enis, err := s.describeNetworkInterfaces(blocking)
+	if err != nil {
+		return nil, err
+	}
+	if len(enis) == 0 {
+		return nil, nil
+	}
+
+	descriptions := make([]string, len(enis))
+	for i, eni := range enis {
+		descriptions[i] = describe(eni)
+	}
+
+	if !s.forceCleanup() {
+		return descriptions, nil
+	}
+
+	owned, err := s.describeClusterOwnedSecurityGroupIDs()
+	if err != nil {
+		return nil, err
+	}
+	live, err := s.describeLiveInstanceIDs(enis)
+	if err != nil {
+		return nil, err
+	}
+	for _, eni := range enis {
+		if !referencesAny(eni, owned) || !releasable(eni, live) || !oldEnough(eni.Attachment) {
+			continue
+		}
+		s.release(eni)
+	}
+
+	return descriptions, nil
+}
+
+// forceCleanup returns whether the network interfaces may be released by force.
+func (s *Service) forceCleanup() bool {
+	cluster := s.scope.InfraCluster()
+	deletion := cluster.GetDeletionTimestamp()
+	return cluster.GetAnnotations()[infrav1.ForceNetworkCleanupAnnotation] == "true" &&
+		deletion != nil && time.Since(deletion.Time) >= forceCleanupMinAge
+}
+
+// release detaches a network interface, or deletes it once it is detached. Failures are reported, and the
+// network interface is retried with the next deletion attempt of the resource it blocks.
+func (s *Service) release(eni *ec2.NetworkInterface) {
+	id := aws.StringValue(eni.NetworkInterfaceId)
+	if eni.Attachment != nil && aws.StringValue(eni.Attachment.Status) != ec2.AttachmentStatusDetached {
+		if aws.StringValue(eni.Attachment.Status) == ec2.AttachmentStatusDetaching {
+			return
+		}
+		if _, err := s.EC2Client.DetachNetworkInterfaceWithContext(context.TODO(), &ec2.DetachNetworkInterfaceInput{
+			AttachmentId: eni.Attachment.AttachmentId,
+			Force:        aws.Bool(true),
+		}); err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedForceDetachNetworkInterface", "Failed to force-detach network interface %q: %v", id, err)
+			s.scope.Error(err, "Failed to force-detach network interface", "network-interface-id", id)
+			return
+		}
+		record.Eventf(s.scope.InfraCluster(), "SuccessfulForceDetachNetworkInterface", "Force-detached network interface %s", describe(eni))
+		s.scope.Info("Force-detached network interface", "network-interface-id", id, "attachment-id", aws.StringValue(eni.Attachment.AttachmentId))
+		return
+	}
+
+	if _, err := s.EC2Client.DeleteNetworkInterfaceWithContext(context.TODO(), &ec2.DeleteNetworkInterfaceInput{
+		NetworkInterfaceId: eni.NetworkInterfaceId,
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedForceDeleteNetworkInterface", "Failed to force-delete network interface %q: %v", id, err)
+		s.scope.Error(err, "Failed to force-delete network interface", "network-interface-id", id)
+		return
+	}
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulForceDeleteNetworkInterface", "Force-deleted network interface %s", describe(eni))
+	s.scope.Info("Force-deleted network interface", "network-interface-id", id)
+}
+
+func (s *Service) describeNetworkInterfaces(blocking *ec2.Filter) ([]*ec2.NetworkInterface, error) {
+	input := &ec2.DescribeNetworkInterfacesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			blocking,
+		},
+	}
+
+	var enis []*ec2.NetworkInterface
+	err := s.EC2Client.DescribeNetworkInterfacesPagesWithContext(context.TODO(), input, func(out *ec2.DescribeNetworkInterfacesOutput, _ bool) bool {
+		enis = append(enis, out.NetworkInterfaces...)
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe network interfaces in vpc %q", s.scope.VPC().ID)
+	}
+	return enis, nil
+}
+
+func (s *Service) describeClusterOwnedSecurityGroupIDs() (sets.Set[string], error) {
+	input := &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			filter.EC2.ClusterOwned(s.scope.Name()),
+		},
+	}
+
+	ids := sets.New[string]()
+	err := s.EC2Client.DescribeSecurityGroupsPagesWithContext(context.TODO(), input, func(out *ec2.DescribeSecurityGroupsOutput, _ bool) bool {
+		for _, group := range out.SecurityGroups {
+			ids.Insert(aws.StringValue(group.GroupId))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe cluster-owned security groups in vpc %q", s.scope.VPC().ID)
+	}
+	return ids, nil
+}
+
+// describeLiveInstanceIDs returns the instances the network interfaces are attached to which are not terminated.
+// The instances are filtered rather than requested by ID, as DescribeInstances fails for IDs which don't exist.
+func (s *Service) describeLiveInstanceIDs(enis []*ec2.NetworkInterface) (sets.Set[string], error) {
+	var instanceIDs []string
+	for _, eni := range enis {
+		if eni.Attachment != nil && eni.Attachment.InstanceId != nil {
+			instanceIDs = append(instanceIDs, aws.StringValue(eni.Attachment.InstanceId))
+		}
+	}
+	live := sets.New[string]()
+	if len(instanceIDs) == 0 {
+		return live, nil
+	}
+
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("instance-id"), Values: aws.StringSlice(instanceIDs)},
+			filter.EC2.InstanceStates(ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning, ec2.InstanceStateNameStopping, ec2.InstanceStateNameStopped),
+		},
+	}
+	err := s.EC2Client.DescribeInstancesPagesWithContext(context.TODO(), input, func(out *ec2.DescribeInstancesOutput, _ bool) bool {
+		for _, reservation := range out.Reservations {
+			for _, instance := range reservation.Instances {
+				live.Insert(aws.StringValue(instance.InstanceId))
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe the instances of network interfaces")
+	}
+	return live, nil
+}
+
+// referencesAny returns whether the network interface references one of the security groups.
+func referencesAny(eni *ec2.NetworkInterface, groupIDs sets.Set[string]) bool {
+	for _, group := range eni.Groups {
+		if groupIDs.Has(aws.StringValue(group.GroupId)) {
+			return true
+		}
+	}
+	return false
+}
+
+// releasable returns whether a network interface may be released by force: it must be managed by its owner
+// rather than by an AWS service, and be either detached or attached to an instance which is terminated or
+// missing. The network interfaces of AWS services, e.g. Lambda functions, VPC endpoints, NAT gateways and load
+// balancers, are requester-managed or of another type, and their attachments have no instance, so whether
+// their owner still exists is unknown.
+func releasable(eni *ec2.NetworkInterface, liveInstanceIDs sets.Set[string]) bool {
+	if aws.BoolValue(eni.RequesterManaged) {
+		return false
+	}
+	if interfaceType := aws.StringValue(eni.InterfaceType); interfaceType != "" && interfaceType != ec2.NetworkInterfaceTypeInterface {
+		return false
+	}
+	if aws.StringValue(eni.Status) == ec2.NetworkInterfaceStatusAvailable {
+		return true
+	}
+	if eni.Attachment == nil || eni.Attachment.InstanceId == nil {
+		return false
+	}
+	return !liveInstanceIDs.Has(aws.StringValue(eni.Attachment.InstanceId))
+}
+
+// oldEnough returns whether an attachment is older than the minimum age of the network interfaces released by force.
+func oldEnough(attachment *ec2.NetworkInterfaceAttachment) bool {
+	if attachment == nil || attachment.AttachTime == nil {
+		return true
+	}
+	return time.Since(*attachment.AttachTime) >= forceCleanupMinAge
+}
+
+// describe returns the description of a network interface which is reported, e.g.
+// eni-0123456789abcdef0 (lambda, in-use, "AWS Lambda VPC ENI-function").
+func describe(eni *ec2.NetworkInterface) string {
+	return fmt.Sprintf("%s (%s, %s, %q)", aws.StringValue(eni.NetworkInterfaceId), aws.StringValue(eni.InterfaceType),
+		aws.StringValue(eni.Status), aws.StringValue(eni.Description))
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eni
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/test/mocks"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestReleaseBlocking(t *testing.T) {
+	old := aws.Time(time.Now().Add(-2 * DefaultForceCleanupMinAge))
+	networkInterface := func(id, interfaceType, groupID string, attachment *ec2.NetworkInterfaceAttachment) *ec2.NetworkInterface {
+		status := ec2.NetworkInterfaceStatusAvailable
+		if attachment != nil {
+			status = ec2.NetworkInterfaceStatusInUse
+		}
+		return &ec2.NetworkInterface{
+			NetworkInterfaceId: aws.String(id),
+			InterfaceType:      aws.String(interfaceType),
+			RequesterManaged:   aws.Bool(interfaceType != ec2.NetworkInterfaceTypeInterface),
+			Status:             aws.String(status),
+			Description:        aws.String("ENI-" + id),
+			Groups:             []*ec2.GroupIdentifier{{GroupId: aws.String(groupID)}},
+			Attachment:         attachment,
+		}
+	}
+	attachment := func(id, instanceID string, attachTime *time.Time) *ec2.NetworkInterfaceAttachment {
+		res := &ec2.NetworkInterfaceAttachment{
+			AttachmentId: aws.String(id),
+			Status:       aws.String(ec2.AttachmentStatusAttached),
+			AttachTime:   attachTime,
+		}
+		if instanceID != "" {
+			res.InstanceId = aws.String(instanceID)
+		}
+		return res
+	}
+	// The network interfaces of AWS services, whose owner may still exist.
+	serviceENIs := []*ec2.NetworkInterface{
+		networkInterface("eni-lambda", ec2.NetworkInterfaceTypeLambda, "sg-owned", attachment("attach-lambda", "", old)),
+		networkInterface("eni-lambda-detached", ec2.NetworkInterfaceTypeLambda, "sg-owned", nil),
+		networkInterface("eni-vpc-endpoint", ec2.NetworkInterfaceTypeVpcEndpoint, "sg-owned", attachment("attach-vpc-endpoint", "", old)),
+	}
+	enis := append([]*ec2.NetworkInterface{
+		networkInterface("eni-terminated-instance", ec2.NetworkInterfaceTypeInterface, "sg-owned", attachment("attach-terminated", "i-terminated", old)),
+		networkInterface("eni-detached", ec2.NetworkInterfaceTypeInterface, "sg-owned", nil),
+		networkInterface("eni-live-instance", ec2.NetworkInterfaceTypeInterface, "sg-owned", attachment("attach-live", "i-live", old)),
+		networkInterface("eni-unknown-owner", ec2.NetworkInterfaceTypeInterface, "sg-owned", attachment("attach-unknown", "", old)),
+		networkInterface("eni-recent", ec2.NetworkInterfaceTypeInterface, "sg-owned", attachment("attach-recent", "i-recent", aws.Time(time.Now()))),
+		networkInterface("eni-foreign", ec2.NetworkInterfaceTypeInterface, "sg-foreign", nil),
+	}, serviceENIs...)
+	dependencyViolation := awserr.New(awserrors.DependencyViolation, "resource sg-owned has a dependent object", nil)
+
+	expectDescribeNetworkInterfaces := func(enis []*ec2.NetworkInterface) func(m *mocks.MockEC2APIMockRecorder) {
+		return func(m *mocks.MockEC2APIMockRecorder) {
+			m.DescribeNetworkInterfacesPagesWithContext(context.TODO(), &ec2.DescribeNetworkInterfacesInput{
+				Filters: []*ec2.Filter{filter.EC2.VPC("vpc-id"), filter.EC2.SecurityGroupID("sg-owned")},
+			}, gomock.Any()).DoAndReturn(func(_ context.Context, _ *ec2.DescribeNetworkInterfacesInput, fn func(*ec2.DescribeNetworkInterfacesOutput, bool) bool, _ ...request.Option) error {
+				fn(&ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: enis}, true)
+				return nil
+			})
+		}
+	}
+	expectDescribeSecurityGroups := func(m *mocks.MockEC2APIMockRecorder) {
+		m.DescribeSecurityGroupsPagesWithContext(context.TODO(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *ec2.DescribeSecurityGroupsInput, fn func(*ec2.DescribeSecurityGroupsOutput, bool) bool, _ ...request.Option) error {
+				fn(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []*ec2.SecurityGroup{{GroupId: aws.String("sg-owned")}}}, true)
+				return nil
+			})
+	}
+
+	tests := []struct {
+		name        string
+		annotated   bool
+		deletedAt   *time.Time
+		deleteErr   error
+		expect      func(m *mocks.MockEC2APIMockRecorder)
+		wantErrText []string
+	}{
+		{
+			name:        "should return other errors as is",
+			annotated:   true,
+			deletedAt:   old,
+			deleteErr:   errors.New("an error"),
+			expect:      func(m *mocks.MockEC2APIMockRecorder) {},
+			wantErrText: []string{"an error"},
+		},
+		{
+			name:        "should only report the blocking network interfaces without the annotation",
+			deletedAt:   old,
+			deleteErr:   dependencyViolation,
+			expect:      expectDescribeNetworkInterfaces(enis),
+			wantErrText: []string{"in use by network interfaces", `eni-lambda (lambda, in-use, "ENI-eni-lambda")`, "eni-foreign", "DependencyViolation"},
+		},
+		{
+			name:        "should only report the blocking network interfaces while the cluster isn't deleted",
+			annotated:   true,
+			deleteErr:   dependencyViolation,
+			expect:      expectDescribeNetworkInterfaces(enis),
+			wantErrText: []string{"in use by network interfaces"},
+		},
+		{
+			name:        "should only report the blocking network interfaces when the deletion is too recent",
+			annotated:   true,
+			deletedAt:   aws.Time(time.Now()),
+			deleteErr:   dependencyViolation,
+			expect:      expectDescribeNetworkInterfaces(enis),
+			wantErrText: []string{"in use by network interfaces"},
+		},
+		{
+			name:      "should release the blocking network interfaces with a missing owner by force",
+			annotated: true,
+			deletedAt: old,
+			deleteErr: dependencyViolation,
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				expectDescribeNetworkInterfaces(enis)(m)
+				expectDescribeSecurityGroups(m)
+				m.DescribeInstancesPagesWithContext(context.TODO(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool, _ ...request.Option) error {
+						g := NewWithT(t)
+						g.Expect(aws.StringValueSlice(input.Filters[0].Values)).To(ConsistOf("i-terminated", "i-live", "i-recent"))
+						fn(&ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{{InstanceId: aws.String("i-live")}}}}}, true)
+						return nil
+					})
+				// A failure to release a network interface doesn't stop the others from being released.
+				m.DetachNetworkInterfaceWithContext(context.TODO(), &ec2.DetachNetworkInterfaceInput{AttachmentId: aws.String("attach-terminated"), Force: aws.Bool(true)}).
+					Return(nil, errors.New("an error"))
+				m.DeleteNetworkInterfaceWithContext(context.TODO(), &ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: aws.String("eni-detached")}).Return(nil, nil)
+			},
+			wantErrText: []string{"in use by network interfaces"},
+		},
+		{
+			name:      "should only report the network interfaces of AWS services, e.g. Lambda functions and VPC endpoints",
+			annotated: true,
+			deletedAt: old,
+			deleteErr: dependencyViolation,
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				expectDescribeNetworkInterfaces(serviceENIs)(m)
+				expectDescribeSecurityGroups(m)
+			},
+			wantErrText: []string{"eni-lambda (lambda, in-use", "eni-lambda-detached (lambda, available", "eni-vpc-endpoint (vpc_endpoint, in-use"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			ec2Mock := mocks.NewMockEC2API(mockCtrl)
+			tt.expect(ec2Mock.EXPECT())
+
+			scheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+			awsCluster := &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{VPC: infrav1.VPCSpec{ID: "vpc-id"}},
+				},
+			}
+			if tt.annotated {
+				awsCluster.Annotations = map[string]string{infrav1.ForceNetworkCleanupAnnotation: "true"}
+			}
+			if tt.deletedAt != nil {
+				awsCluster.DeletionTimestamp = &metav1.Time{Time: *tt.deletedAt}
+			}
+			cs, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     fake.NewClientBuilder().WithScheme(scheme).Build(),
+				Cluster:    &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"}},
+				AWSCluster: awsCluster,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			err = NewService(cs, ec2Mock).ReleaseBlocking(tt.deleteErr, filter.EC2.SecurityGroupID("sg-owned"))
+			for _, text := range tt.wantErrText {
+				g.Expect(err).To(MatchError(ContainSubstring(text)))
+			}
+		})
+	}
+}
+
+func TestSetForceCleanupMinAge(t *testing.T) {
+	g := NewWithT(t)
+	defer func() { forceCleanupMinAge = DefaultForceCleanupMinAge }()
+
+	g.Expect(SetForceCleanupMinAge(-time.Minute)).NotTo(Succeed())
+	g.Expect(SetForceCleanupMinAge(0)).To(Succeed())
+	g.Expect(forceCleanupMinAge).To(BeZero())
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eni provides a service to report and release the network interfaces which block the deletion
+// of the network resources of a cluster.
+package eni
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud"
+)
+
+// DefaultForceCleanupMinAge is the default of the minimum age of the network interfaces released by force.
+const DefaultForceCleanupMinAge = 30 * time.Minute
+
+// forceCleanupMinAge is how long the deletion of a cluster must have lasted, and a network interface must
+// have been attached, before the network interface is released by force.
+var forceCleanupMinAge = DefaultForceCleanupMinAge
+
+// SetForceCleanupMinAge sets the minimum age of the network interfaces released by force.
+func SetForceCleanupMinAge(age time.Duration) error {
+	if age < 0 {
+		return errors.Errorf("minimum age of the network interfaces released by force must not be negative, got %s", age)
+	}
+	forceCleanupMinAge = age
+	return nil
+}
+
+// Scope is the scope of the service.
+type Scope interface {
+	cloud.ClusterScoper
+
+	// VPC returns the cluster VPC.
+	VPC() *infrav1.VPCSpec
+}
+
+// Service holds a collection of interfaces.
+type Service struct {
+	scope     Scope
+	EC2Client ec2iface.EC2API
+}
+
+// NewService returns a new service given the scope of the cluster and the EC2 client of the calling service.
+func NewService(eniScope Scope, ec2Client ec2iface.EC2API) *Service {
+	return &Service{
+		scope:     eniScope,
+		EC2Client: ec2Client,
+	}
+}
@@ -0,0 +1,1100 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakeaws
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+)
+
+const (
+	// Region is the region of the resources created by the fakes.
+	Region = "us-east-1"
+	// AccountID is the account of the resources created by the fakes.
+	AccountID = "123456789012"
+
+	defaultHeartbeatTimeout = 3600
+	maxGlobalTimeout        = 172800
+)
+
+// scalingProcesses are the processes suspended when SuspendProcesses is called without processes.
+var scalingProcesses = []string{
+	"AZRebalance", "AddToLoadBalancer", "AlarmNotification", "HealthCheck", "InstanceRefresh",
+	"Launch", "ReplaceUnhealthy", "ScheduledActions", "Terminate",
+}
+
+// groupMetrics are the metrics enabled when EnableMetricsCollection is called without metrics.
+var groupMetrics = []string{
+	"GroupAndWarmPoolDesiredCapacity", "GroupAndWarmPoolTotalCapacity", "GroupDesiredCapacity", "GroupInServiceCapacity",
+	"GroupInServiceInstances", "GroupMaxSize", "GroupMinSize", "GroupPendingCapacity", "GroupPendingInstances",
+	"GroupStandbyCapacity", "GroupStandbyInstances", "GroupTerminatingCapacity", "GroupTerminatingInstances",
+	"GroupTotalCapacity", "GroupTotalInstances", "WarmPoolDesiredCapacity", "WarmPoolPendingCapacity",
+	"WarmPoolTerminatingCapacity", "WarmPoolTotalCapacity", "WarmPoolWarmedCapacity",
+}
+
+// AutoScalingAPI is an in-memory implementation of autoscalingiface.AutoScalingAPI.
+type AutoScalingAPI struct {
+	// AutoScalingAPI is nil, calling an operation that is not implemented by the fake panics.
+	autoscalingiface.AutoScalingAPI
+	faults
+
+	mu                        sync.Mutex
+	lastID                    int
+	groups                    map[string]*autoscaling.Group
+	lifecycleHooks            map[string][]*autoscaling.LifecycleHook
+	scheduledActions          map[string][]*autoscaling.ScheduledUpdateGroupAction
+	instanceRefreshes         map[string][]*autoscaling.InstanceRefresh
+	activities                map[string][]*autoscaling.Activity
+	completedLifecycleActions []*autoscaling.CompleteLifecycleActionInput
+}
+
+var _ autoscalingiface.AutoScalingAPI = &AutoScalingAPI{}
+
+// NewAutoScalingAPI returns an AutoScalingAPI without any resources.
+func NewAutoScalingAPI() *AutoScalingAPI {
+	return &AutoScalingAPI{
+		groups:            map[string]*autoscaling.Group{},
+		lifecycleHooks:    map[string][]*autoscaling.LifecycleHook{},
+		scheduledActions:  map[string][]*autoscaling.ScheduledUpdateGroupAction{},
+		instanceRefreshes: map[string][]*autoscaling.InstanceRefresh{},
+		activities:        map[string][]*autoscaling.Activity{},
+	}
+}
+
+// AddAutoScalingGroup adds an existing AutoScalingGroup, e.g. one created out of band.
+func (f *AutoScalingAPI) AddAutoScalingGroup(group *autoscaling.Group) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.groups[aws.StringValue(group.AutoScalingGroupName)] = copyOf(group)
+}
+
+// SetInstances replaces the instances of an AutoScalingGroup.
+func (f *AutoScalingAPI) SetInstances(asgName string, instances ...*autoscaling.Instance) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	group, err := f.group(asgName)
+	if err != nil {
+		return err
+	}
+	group.Instances = copyAll(instances)
+	return nil
+}
+
+// AddScalingActivities adds scaling activities to an AutoScalingGroup, most recent first.
+func (f *AutoScalingAPI) AddScalingActivities(asgName string, activities ...*autoscaling.Activity) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.activities[asgName] = append(copyAll(activities), f.activities[asgName]...)
+}
+
+// SetInstanceRefreshStatus sets the status of an instance refresh, e.g. to complete it.
+func (f *AutoScalingAPI) SetInstanceRefreshStatus(asgName, instanceRefreshID, status string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, refresh := range f.instanceRefreshes[asgName] {
+		if aws.StringValue(refresh.InstanceRefreshId) == instanceRefreshID {
+			refresh.Status = aws.String(status)
+			return nil
+		}
+	}
+	return validationError(fmt.Sprintf("instance refresh %s not found for AutoScalingGroup %s", instanceRefreshID, asgName))
+}
+
+// CompletedLifecycleActions returns the lifecycle actions completed so far, in order.
+func (f *AutoScalingAPI) CompletedLifecycleActions() []*autoscaling.CompleteLifecycleActionInput {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return copyAll(f.completedLifecycleActions)
+}
+
+// CreateAutoScalingGroupWithContext creates an AutoScalingGroup and its lifecycle hooks.
+func (f *AutoScalingAPI) CreateAutoScalingGroupWithContext(_ aws.Context, input *autoscaling.CreateAutoScalingGroupInput, _ ...request.Option) (*autoscaling.CreateAutoScalingGroupOutput, error) {
+	if err := f.call("CreateAutoScalingGroup", input); err != nil {
+		return nil, err
+	}
+	// The input is copied, so that the stored resources don't share pointers with the caller.
+	input = copyOf(input)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := aws.StringValue(input.AutoScalingGroupName)
+	if name == "" || input.MinSize == nil || input.MaxSize == nil {
+		return nil, validationError("AutoScalingGroupName, MinSize and MaxSize are required")
+	}
+	if _, ok := f.groups[name]; ok {
+		return nil, awserr.New(autoscaling.ErrCodeAlreadyExistsFault, fmt.Sprintf("AutoScalingGroup by this name already exists - A group with the name %s already exists", name), nil)
+	}
+	if (input.LaunchTemplate == nil) == (input.MixedInstancesPolicy == nil) {
+		return nil, validationError("Valid requests must contain either LaunchTemplate or MixedInstancesPolicy")
+	}
+
+	f.lastID++
+	group := &autoscaling.Group{
+		AutoScalingGroupARN:              aws.String(fmt.Sprintf("arn:aws:autoscaling:%s:%s:autoScalingGroup:%08d-0000-0000-0000-000000000000:autoScalingGroupName/%s", Region, AccountID, f.lastID, name)),
+		AutoScalingGroupName:             aws.String(name),
+		CapacityRebalance:                aws.Bool(aws.BoolValue(input.CapacityRebalance)),
+		CreatedTime:                      aws.Time(time.Now()),
+		DefaultCooldown:                  aws.Int64(300),
+		DefaultInstanceWarmup:            input.DefaultInstanceWarmup,
+		DesiredCapacity:                  input.DesiredCapacity,
+		HealthCheckType:                  aws.String("EC2"),
+		LaunchTemplate:                   input.LaunchTemplate,
+		MaxSize:                          input.MaxSize,
+		MinSize:                          input.MinSize,
+		MixedInstancesPolicy:             input.MixedInstancesPolicy,
+		NewInstancesProtectedFromScaleIn: aws.Bool(aws.BoolValue(input.NewInstancesProtectedFromScaleIn)),
+		TargetGroupARNs:                  input.TargetGroupARNs,
+		TerminationPolicies:              aws.StringSlice([]string{"Default"}),
+		VPCZoneIdentifier:                normalizeVPCZoneIdentifier(input.VPCZoneIdentifier),
+	}
+	if len(input.TerminationPolicies) > 0 {
+		group.TerminationPolicies = input.TerminationPolicies
+	}
+	if input.HealthCheckType != nil {
+		group.HealthCheckType = input.HealthCheckType
+	}
+	// The health check grace period defaults to 0 when the group is created with the API.
+	group.HealthCheckGracePeriod = aws.Int64(aws.Int64Value(input.HealthCheckGracePeriod))
+	if aws.Int64Value(input.MaxInstanceLifetime) > 0 {
+		group.MaxInstanceLifetime = input.MaxInstanceLifetime
+	}
+	if input.DefaultCooldown != nil {
+		group.DefaultCooldown = input.DefaultCooldown
+	}
+	if group.DesiredCapacity == nil {
+		group.DesiredCapacity = group.MinSize
+	}
+	if err := validateCapacity(group); err != nil {
+		return nil, err
+	}
+	for _, tag := range input.Tags {
+		setTag(group, tag)
+	}
+
+	hooks := make([]*autoscaling.LifecycleHook, 0, len(input.LifecycleHookSpecificationList))
+	for _, spec := range input.LifecycleHookSpecificationList {
+		hook := &autoscaling.LifecycleHook{
+			AutoScalingGroupName:  aws.String(name),
+			DefaultResult:         spec.DefaultResult,
+			HeartbeatTimeout:      spec.HeartbeatTimeout,
+			LifecycleHookName:     spec.LifecycleHookName,
+			LifecycleTransition:   spec.LifecycleTransition,
+			NotificationMetadata:  spec.NotificationMetadata,
+			NotificationTargetARN: spec.NotificationTargetARN,
+			RoleARN:               spec.RoleARN,
+		}
+		defaultLifecycleHook(hook)
+		hooks = append(hooks, copyOf(hook))
+	}
+
+	f.groups[name] = copyOf(group)
+	f.lifecycleHooks[name] = hooks
+	return &autoscaling.CreateAutoScalingGroupOutput{}, nil
+}
+
+// DescribeAutoScalingGroupsWithContext returns the AutoScalingGroups with the given names, or all of
+// them if no names are given. Unknown names are ignored, as AWS does.
+func (f *AutoScalingAPI) DescribeAutoScalingGroupsWithContext(_ aws.Context, input *autoscaling.DescribeAutoScalingGroupsInput, _ ...request.Option) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
+	if err := f.call("DescribeAutoScalingGroups", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(input.Filters) > 0 {
+		return nil, validationError("filters are not supported by the fake AutoScalingAPI")
+	}
+
+	names := aws.StringValueSlice(input.AutoScalingGroupNames)
+	if len(names) == 0 {
+		for name := range f.groups {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	out := &autoscaling.DescribeAutoScalingGroupsOutput{AutoScalingGroups: []*autoscaling.Group{}}
+	for _, name := range names {
+		if group, ok := f.groups[name]; ok {
+			out.AutoScalingGroups = append(out.AutoScalingGroups, copyOf(group))
+		}
+	}
+	return out, nil
+}
+
+// UpdateAutoScalingGroupWithContext updates the fields of an AutoScalingGroup that are set in the input.
+func (f *AutoScalingAPI) UpdateAutoScalingGroupWithContext(_ aws.Context, input *autoscaling.UpdateAutoScalingGroupInput, _ ...request.Option) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
+	if err := f.call("UpdateAutoScalingGroup", input); err != nil {
+		return nil, err
+	}
+	input = copyOf(input)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stored, err := f.group(aws.StringValue(input.AutoScalingGroupName))
+	if err != nil {
+		return nil, err
+	}
+	group := copyOf(stored)
+
+	if input.MinSize != nil {
+		group.MinSize = input.MinSize
+	}
+	if input.MaxSize != nil {
+		group.MaxSize = input.MaxSize
+	}
+	if input.DesiredCapacity != nil {
+		group.DesiredCapacity = input.DesiredCapacity
+	}
+	if input.CapacityRebalance != nil {
+		group.CapacityRebalance = input.CapacityRebalance
+	}
+	if input.NewInstancesProtectedFromScaleIn != nil {
+		group.NewInstancesProtectedFromScaleIn = input.NewInstancesProtectedFromScaleIn
+	}
+	if input.DefaultCooldown != nil {
+		group.DefaultCooldown = input.DefaultCooldown
+	}
+	if input.DefaultInstanceWarmup != nil {
+		group.DefaultInstanceWarmup = input.DefaultInstanceWarmup
+	}
+	if len(input.TerminationPolicies) > 0 {
+		group.TerminationPolicies = input.TerminationPolicies
+	}
+	if input.HealthCheckType != nil {
+		group.HealthCheckType = input.HealthCheckType
+	}
+	if input.HealthCheckGracePeriod != nil {
+		group.HealthCheckGracePeriod = input.HealthCheckGracePeriod
+	}
+	if input.MaxInstanceLifetime != nil {
+		// A max instance lifetime of 0 clears it.
+		group.MaxInstanceLifetime = nil
+		if *input.MaxInstanceLifetime > 0 {
+			group.MaxInstanceLifetime = input.MaxInstanceLifetime
+		}
+	}
+	if input.VPCZoneIdentifier != nil {
+		group.VPCZoneIdentifier = normalizeVPCZoneIdentifier(input.VPCZoneIdentifier)
+	}
+	if input.LaunchTemplate != nil && input.MixedInstancesPolicy != nil {
+		return nil, validationError("Valid requests must contain either LaunchTemplate or MixedInstancesPolicy")
+	}
+	if input.LaunchTemplate != nil {
+		group.LaunchTemplate = input.LaunchTemplate
+		group.MixedInstancesPolicy = nil
+	}
+	if input.MixedInstancesPolicy != nil {
+		group.MixedInstancesPolicy = input.MixedInstancesPolicy
+		group.LaunchTemplate = nil
+	}
+	if err := validateCapacity(group); err != nil {
+		return nil, err
+	}
+
+	f.groups[aws.StringValue(group.AutoScalingGroupName)] = copyOf(group)
+	return &autoscaling.UpdateAutoScalingGroupOutput{}, nil
+}
+
+// DeleteAutoScalingGroupWithContext deletes an AutoScalingGroup and the resources attached to it.
+// The deletion is immediate.
+func (f *AutoScalingAPI) DeleteAutoScalingGroupWithContext(_ aws.Context, input *autoscaling.DeleteAutoScalingGroupInput, _ ...request.Option) (*autoscaling.DeleteAutoScalingGroupOutput, error) {
+	if err := f.call("DeleteAutoScalingGroup", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := aws.StringValue(input.AutoScalingGroupName)
+	group, err := f.group(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(group.Instances) > 0 && !aws.BoolValue(input.ForceDelete) {
+		return nil, awserr.New(autoscaling.ErrCodeResourceInUseFault, "You cannot delete an AutoScalingGroup while there are instances still in the group.", nil)
+	}
+
+	delete(f.groups, name)
+	delete(f.lifecycleHooks, name)
+	delete(f.scheduledActions, name)
+	delete(f.instanceRefreshes, name)
+	delete(f.activities, name)
+	return &autoscaling.DeleteAutoScalingGroupOutput{}, nil
+}
+
+// WaitUntilGroupNotExistsWithContext returns immediately, failing if one of the AutoScalingGroups still exists.
+func (f *AutoScalingAPI) WaitUntilGroupNotExistsWithContext(_ aws.Context, input *autoscaling.DescribeAutoScalingGroupsInput, _ ...request.WaiterOption) error {
+	if err := f.call("WaitUntilGroupNotExists", input); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, name := range aws.StringValueSlice(input.AutoScalingGroupNames) {
+		if _, ok := f.groups[name]; ok {
+			return awserr.New(request.WaiterResourceNotReadyErrorCode, "exceeded wait attempts", nil)
+		}
+	}
+	return nil
+}
+
+// CreateOrUpdateTagsWithContext sets the tags of AutoScalingGroups.
+func (f *AutoScalingAPI) CreateOrUpdateTagsWithContext(_ aws.Context, input *autoscaling.CreateOrUpdateTagsInput, _ ...request.Option) (*autoscaling.CreateOrUpdateTagsOutput, error) {
+	if err := f.call("CreateOrUpdateTags", input); err != nil {
+		return nil, err
+	}
+	input = copyOf(input)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, tag := range input.Tags {
+		group, err := f.group(aws.StringValue(tag.ResourceId))
+		if err != nil {
+			return nil, err
+		}
+		setTag(group, tag)
+	}
+	return &autoscaling.CreateOrUpdateTagsOutput{}, nil
+}
+
+// DeleteTagsWithContext removes tags from AutoScalingGroups. Tags are matched by key.
+func (f *AutoScalingAPI) DeleteTagsWithContext(_ aws.Context, input *autoscaling.DeleteTagsInput, _ ...request.Option) (*autoscaling.DeleteTagsOutput, error) {
+	if err := f.call("DeleteTags", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, tag := range input.Tags {
+		group, err := f.group(aws.StringValue(tag.ResourceId))
+		if err != nil {
+			return nil, err
+		}
+		tags := group.Tags[:0]
+		for _, t := range group.Tags {
+			if aws.StringValue(t.Key) != aws.StringValue(tag.Key) {
+				tags = append(tags, t)
+			}
+		}
+		group.Tags = tags
+	}
+	return &autoscaling.DeleteTagsOutput{}, nil
+}
+
+// SuspendProcessesWithContext suspends the given processes, or all of them if none are given.
+func (f *AutoScalingAPI) SuspendProcessesWithContext(_ aws.Context, input *autoscaling.ScalingProcessQuery, _ ...request.Option) (*autoscaling.SuspendProcessesOutput, error) {
+	if err := f.call("SuspendProcesses", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	group, err := f.group(aws.StringValue(input.AutoScalingGroupName))
+	if err != nil {
+		return nil, err
+	}
+	processes := aws.StringValueSlice(input.ScalingProcesses)
+	if len(processes) == 0 {
+		processes = scalingProcesses
+	}
+	suspended := map[string]*autoscaling.SuspendedProcess{}
+	for _, p := range group.SuspendedProcesses {
+		suspended[aws.StringValue(p.ProcessName)] = p
+	}
+	for _, p := range processes {
+		if _, ok := suspended[p]; !ok {
+			suspended[p] = &autoscaling.SuspendedProcess{
+				ProcessName:      aws.String(p),
+				SuspensionReason: aws.String(fmt.Sprintf("User suspended at %s", time.Now().UTC().Format(time.RFC3339))),
+			}
+		}
+	}
+	group.SuspendedProcesses = sortedSuspendedProcesses(suspended)
+	return &autoscaling.SuspendProcessesOutput{}, nil
+}
+
+// ResumeProcessesWithContext resumes the given processes, or all of them if none are given.
+func (f *AutoScalingAPI) ResumeProcessesWithContext(_ aws.Context, input *autoscaling.ScalingProcessQuery, _ ...request.Option) (*autoscaling.ResumeProcessesOutput, error) {
+	if err := f.call("ResumeProcesses", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	group, err := f.group(aws.StringValue(input.AutoScalingGroupName))
+	if err != nil {
+		return nil, err
+	}
+	suspended := map[string]*autoscaling.SuspendedProcess{}
+	if len(input.ScalingProcesses) > 0 {
+		for _, p := range group.SuspendedProcesses {
+			suspended[aws.StringValue(p.ProcessName)] = p
+		}
+		for _, p := range aws.StringValueSlice(input.ScalingProcesses) {
+			delete(suspended, p)
+		}
+	}
+	group.SuspendedProcesses = sortedSuspendedProcesses(suspended)
+	return &autoscaling.ResumeProcessesOutput{}, nil
+}
+
+// EnableMetricsCollectionWithContext enables the given group metrics, or all of them if none are given.
+func (f *AutoScalingAPI) EnableMetricsCollectionWithContext(_ aws.Context, input *autoscaling.EnableMetricsCollectionInput, _ ...request.Option) (*autoscaling.EnableMetricsCollectionOutput, error) {
+	if err := f.call("EnableMetricsCollection", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if aws.StringValue(input.Granularity) != "1Minute" {
+		return nil, validationError(fmt.Sprintf("Invalid granularity %s", aws.StringValue(input.Granularity)))
+	}
+	group, err := f.group(aws.StringValue(input.AutoScalingGroupName))
+	if err != nil {
+		return nil, err
+	}
+	metrics := aws.StringValueSlice(input.Metrics)
+	if len(metrics) == 0 {
+		metrics = groupMetrics
+	}
+	enabled := map[string]*autoscaling.EnabledMetric{}
+	for _, m := range group.EnabledMetrics {
+		enabled[aws.StringValue(m.Metric)] = m
+	}
+	for _, m := range metrics {
+		enabled[m] = &autoscaling.EnabledMetric{Metric: aws.String(m), Granularity: input.Granularity}
+	}
+	group.EnabledMetrics = sortedEnabledMetrics(enabled)
+	return &autoscaling.EnableMetricsCollectionOutput{}, nil
+}
+
+// DisableMetricsCollectionWithContext disables the given group metrics, or all of them if none are given.
+func (f *AutoScalingAPI) DisableMetricsCollectionWithContext(_ aws.Context, input *autoscaling.DisableMetricsCollectionInput, _ ...request.Option) (*autoscaling.DisableMetricsCollectionOutput, error) {
+	if err := f.call("DisableMetricsCollection", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	group, err := f.group(aws.StringValue(input.AutoScalingGroupName))
+	if err != nil {
+		return nil, err
+	}
+	enabled := map[string]*autoscaling.EnabledMetric{}
+	if len(input.Metrics) > 0 {
+		for _, m := range group.EnabledMetrics {
+			enabled[aws.StringValue(m.Metric)] = m
+		}
+		for _, m := range aws.StringValueSlice(input.Metrics) {
+			delete(enabled, m)
+		}
+	}
+	group.EnabledMetrics = sortedEnabledMetrics(enabled)
+	return &autoscaling.DisableMetricsCollectionOutput{}, nil
+}
+
+// StartInstanceRefreshWithContext starts a pending instance refresh. Refreshes don't progress on their
+// own, use SetInstanceRefreshStatus to change their status.
+func (f *AutoScalingAPI) StartInstanceRefreshWithContext(_ aws.Context, input *autoscaling.StartInstanceRefreshInput, _ ...request.Option) (*autoscaling.StartInstanceRefreshOutput, error) {
+	if err := f.call("StartInstanceRefresh", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := aws.StringValue(input.AutoScalingGroupName)
+	if _, err := f.group(name); err != nil {
+		return nil, err
+	}
+	for _, refresh := range f.instanceRefreshes[name] {
+		switch aws.StringValue(refresh.Status) {
+		case autoscaling.InstanceRefreshStatusPending, autoscaling.InstanceRefreshStatusInProgress, autoscaling.InstanceRefreshStatusCancelling:
+			return nil, awserr.New(autoscaling.ErrCodeInstanceRefreshInProgressFault, fmt.Sprintf("An Instance Refresh is already in progress and blocks the execution of this Instance Refresh for %s", name), nil)
+		}
+	}
+
+	f.lastID++
+	refresh := &autoscaling.InstanceRefresh{
+		AutoScalingGroupName: aws.String(name),
+		InstanceRefreshId:    aws.String(fmt.Sprintf("%08d-0000-0000-0000-000000000000", f.lastID)),
+		Preferences:          copyOf(input.Preferences),
+		StartTime:            aws.Time(time.Now()),
+		Status:               aws.String(autoscaling.InstanceRefreshStatusPending),
+	}
+	// Instance refreshes are described most recent first.
+	f.instanceRefreshes[name] = append([]*autoscaling.InstanceRefresh{refresh}, f.instanceRefreshes[name]...)
+	return &autoscaling.StartInstanceRefreshOutput{InstanceRefreshId: refresh.InstanceRefreshId}, nil
+}
+
+// DescribeInstanceRefreshesWithContext returns the instance refreshes of an AutoScalingGroup, most recent first.
+func (f *AutoScalingAPI) DescribeInstanceRefreshesWithContext(_ aws.Context, input *autoscaling.DescribeInstanceRefreshesInput, _ ...request.Option) (*autoscaling.DescribeInstanceRefreshesOutput, error) {
+	if err := f.call("DescribeInstanceRefreshes", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	refreshes := copyAll(f.instanceRefreshes[aws.StringValue(input.AutoScalingGroupName)])
+	if ids := aws.StringValueSlice(input.InstanceRefreshIds); len(ids) > 0 {
+		filtered := refreshes[:0]
+		for _, refresh := range refreshes {
+			for _, id := range ids {
+				if aws.StringValue(refresh.InstanceRefreshId) == id {
+					filtered = append(filtered, refresh)
+				}
+			}
+		}
+		refreshes = filtered
+	}
+	return &autoscaling.DescribeInstanceRefreshesOutput{InstanceRefreshes: refreshes}, nil
+}
+
+// DescribeScalingActivitiesWithContext returns the scaling activities added with AddScalingActivities.
+func (f *AutoScalingAPI) DescribeScalingActivitiesWithContext(_ aws.Context, input *autoscaling.DescribeScalingActivitiesInput, _ ...request.Option) (*autoscaling.DescribeScalingActivitiesOutput, error) {
+	if err := f.call("DescribeScalingActivities", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return &autoscaling.DescribeScalingActivitiesOutput{
+		Activities: copyAll(f.activities[aws.StringValue(input.AutoScalingGroupName)]),
+	}, nil
+}
+
+// PutLifecycleHookWithContext creates a lifecycle hook, or updates the fields of an existing one that are set in the input.
+func (f *AutoScalingAPI) PutLifecycleHookWithContext(_ aws.Context, input *autoscaling.PutLifecycleHookInput, _ ...request.Option) (*autoscaling.PutLifecycleHookOutput, error) {
+	if err := f.call("PutLifecycleHook", input); err != nil {
+		return nil, err
+	}
+	input = copyOf(input)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	asgName := aws.StringValue(input.AutoScalingGroupName)
+	if _, err := f.group(asgName); err != nil {
+		return nil, err
+	}
+
+	hook := f.lifecycleHook(asgName, aws.StringValue(input.LifecycleHookName))
+	if hook == nil {
+		if input.LifecycleTransition == nil {
+			return nil, validationError("LifecycleTransition is required when creating a lifecycle hook")
+		}
+		hook = &autoscaling.LifecycleHook{
+			AutoScalingGroupName: aws.String(asgName),
+			LifecycleHookName:    input.LifecycleHookName,
+		}
+		f.lifecycleHooks[asgName] = append(f.lifecycleHooks[asgName], hook)
+	}
+	if input.LifecycleTransition != nil {
+		hook.LifecycleTransition = input.LifecycleTransition
+	}
+	if input.DefaultResult != nil {
+		hook.DefaultResult = input.DefaultResult
+	}
+	if input.HeartbeatTimeout != nil {
+		hook.HeartbeatTimeout = input.HeartbeatTimeout
+		hook.GlobalTimeout = nil
+	}
+	if input.NotificationMetadata != nil {
+		hook.NotificationMetadata = input.NotificationMetadata
+	}
+	if input.NotificationTargetARN != nil {
+		hook.NotificationTargetARN = input.NotificationTargetARN
+	}
+	if input.RoleARN != nil {
+		hook.RoleARN = input.RoleARN
+	}
+	defaultLifecycleHook(hook)
+	return &autoscaling.PutLifecycleHookOutput{}, nil
+}
+
+// DescribeLifecycleHooksWithContext returns the lifecycle hooks of an AutoScalingGroup, in creation order.
+func (f *AutoScalingAPI) DescribeLifecycleHooksWithContext(_ aws.Context, input *autoscaling.DescribeLifecycleHooksInput, _ ...request.Option) (*autoscaling.DescribeLifecycleHooksOutput, error) {
+	if err := f.call("DescribeLifecycleHooks", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	asgName := aws.StringValue(input.AutoScalingGroupName)
+	if _, err := f.group(asgName); err != nil {
+		return nil, err
+	}
+
+	out := &autoscaling.DescribeLifecycleHooksOutput{LifecycleHooks: []*autoscaling.LifecycleHook{}}
+	names := aws.StringValueSlice(input.LifecycleHookNames)
+	for _, hook := range f.lifecycleHooks[asgName] {
+		if len(names) > 0 && !contains(names, aws.StringValue(hook.LifecycleHookName)) {
+			continue
+		}
+		out.LifecycleHooks = append(out.LifecycleHooks, copyOf(hook))
+	}
+	return out, nil
+}
+
+// DeleteLifecycleHookWithContext deletes a lifecycle hook.
+func (f *AutoScalingAPI) DeleteLifecycleHookWithContext(_ aws.Context, input *autoscaling.DeleteLifecycleHookInput, _ ...request.Option) (*autoscaling.DeleteLifecycleHookOutput, error) {
+	if err := f.call("DeleteLifecycleHook", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	asgName := aws.StringValue(input.AutoScalingGroupName)
+	group, err := f.group(asgName)
+	if err != nil {
+		return nil, err
+	}
+	hookName := aws.StringValue(input.LifecycleHookName)
+	hook := f.lifecycleHook(asgName, hookName)
+	if hook == nil {
+		return nil, validationError(fmt.Sprintf("No Lifecycle Hook found with name %s for group %s", hookName, asgName))
+	}
+	// Instances in the wait state of the transition of the hook are assumed to wait on it.
+	waitState := autoscaling.LifecycleStatePendingWait
+	if aws.StringValue(hook.LifecycleTransition) == "autoscaling:EC2_INSTANCE_TERMINATING" {
+		waitState = autoscaling.LifecycleStateTerminatingWait
+	}
+	for _, instance := range group.Instances {
+		if aws.StringValue(instance.LifecycleState) == waitState {
+			return nil, validationError(fmt.Sprintf("Lifecycle hook %s is in use by a pending lifecycle action for group %s", hookName, asgName))
+		}
+	}
+
+	hooks := f.lifecycleHooks[asgName][:0]
+	for _, hook := range f.lifecycleHooks[asgName] {
+		if aws.StringValue(hook.LifecycleHookName) != hookName {
+			hooks = append(hooks, hook)
+		}
+	}
+	f.lifecycleHooks[asgName] = hooks
+	return &autoscaling.DeleteLifecycleHookOutput{}, nil
+}
+
+// PutScheduledUpdateGroupActionWithContext creates a scheduled action, or updates the fields of an existing one
+// that are set in the input.
+func (f *AutoScalingAPI) PutScheduledUpdateGroupActionWithContext(_ aws.Context, input *autoscaling.PutScheduledUpdateGroupActionInput, _ ...request.Option) (*autoscaling.PutScheduledUpdateGroupActionOutput, error) {
+	if err := f.call("PutScheduledUpdateGroupAction", input); err != nil {
+		return nil, err
+	}
+	input = copyOf(input)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	asgName := aws.StringValue(input.AutoScalingGroupName)
+	if _, err := f.group(asgName); err != nil {
+		return nil, err
+	}
+	if input.MinSize == nil && input.MaxSize == nil && input.DesiredCapacity == nil {
+		return nil, validationError("At least one of MinSize, MaxSize or DesiredCapacity must be specified")
+	}
+
+	actionName := aws.StringValue(input.ScheduledActionName)
+	action := f.scheduledAction(asgName, actionName)
+	if action == nil {
+		f.lastID++
+		action = &autoscaling.ScheduledUpdateGroupAction{
+			AutoScalingGroupName: aws.String(asgName),
+			ScheduledActionARN:   aws.String(fmt.Sprintf("arn:aws:autoscaling:%s:%s:scheduledUpdateGroupAction:%08d-0000-0000-0000-000000000000:autoScalingGroupName/%s:scheduledActionName/%s", Region, AccountID, f.lastID, asgName, actionName)),
+			ScheduledActionName:  aws.String(actionName),
+		}
+		f.scheduledActions[asgName] = append(f.scheduledActions[asgName], action)
+	}
+	if input.Recurrence != nil {
+		action.Recurrence = input.Recurrence
+	}
+	if input.TimeZone != nil {
+		action.TimeZone = input.TimeZone
+	}
+	if input.StartTime != nil {
+		action.StartTime, action.Time = input.StartTime, input.StartTime
+	}
+	if input.EndTime != nil {
+		action.EndTime = input.EndTime
+	}
+	if input.MinSize != nil {
+		action.MinSize = input.MinSize
+	}
+	if input.MaxSize != nil {
+		action.MaxSize = input.MaxSize
+	}
+	if input.DesiredCapacity != nil {
+		action.DesiredCapacity = input.DesiredCapacity
+	}
+	return &autoscaling.PutScheduledUpdateGroupActionOutput{}, nil
+}
+
+// DescribeScheduledActionsPagesWithContext returns the scheduled actions of an AutoScalingGroup, in creation order
+// and paginated by MaxRecords.
+func (f *AutoScalingAPI) DescribeScheduledActionsPagesWithContext(_ aws.Context, input *autoscaling.DescribeScheduledActionsInput, fn func(*autoscaling.DescribeScheduledActionsOutput, bool) bool, _ ...request.Option) error {
+	if err := f.call("DescribeScheduledActions", input); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	asgName := aws.StringValue(input.AutoScalingGroupName)
+	if _, err := f.group(asgName); err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	names := aws.StringValueSlice(input.ScheduledActionNames)
+	var actions []*autoscaling.ScheduledUpdateGroupAction
+	for _, action := range f.scheduledActions[asgName] {
+		if len(names) > 0 && !contains(names, aws.StringValue(action.ScheduledActionName)) {
+			continue
+		}
+		actions = append(actions, copyOf(action))
+	}
+	f.mu.Unlock()
+
+	var token *string
+	for {
+		start, end, next, err := page(len(actions), token, input.MaxRecords)
+		if err != nil {
+			return err
+		}
+		out := &autoscaling.DescribeScheduledActionsOutput{ScheduledUpdateGroupActions: actions[start:end], NextToken: next}
+		if !fn(out, next == nil) || next == nil {
+			return nil
+		}
+		token = next
+	}
+}
+
+// DeleteScheduledActionWithContext deletes a scheduled action.
+func (f *AutoScalingAPI) DeleteScheduledActionWithContext(_ aws.Context, input *autoscaling.DeleteScheduledActionInput, _ ...request.Option) (*autoscaling.DeleteScheduledActionOutput, error) {
+	if err := f.call("DeleteScheduledAction", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	asgName := aws.StringValue(input.AutoScalingGroupName)
+	if _, err := f.group(asgName); err != nil {
+		return nil, err
+	}
+	actionName := aws.StringValue(input.ScheduledActionName)
+	if f.scheduledAction(asgName, actionName) == nil {
+		return nil, validationError(fmt.Sprintf("Scheduled Update Group Action name not found - Could not find scheduled action %s for group %s", actionName, asgName))
+	}
+
+	actions := f.scheduledActions[asgName][:0]
+	for _, action := range f.scheduledActions[asgName] {
+		if aws.StringValue(action.ScheduledActionName) != actionName {
+			actions = append(actions, action)
+		}
+	}
+	f.scheduledActions[asgName] = actions
+	return &autoscaling.DeleteScheduledActionOutput{}, nil
+}
+
+// CompleteLifecycleActionWithContext records the completed lifecycle action and moves the instance, if any,
+// out of its wait state.
+func (f *AutoScalingAPI) CompleteLifecycleActionWithContext(_ aws.Context, input *autoscaling.CompleteLifecycleActionInput, _ ...request.Option) (*autoscaling.CompleteLifecycleActionOutput, error) {
+	if err := f.call("CompleteLifecycleAction", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	asgName := aws.StringValue(input.AutoScalingGroupName)
+	group, err := f.group(asgName)
+	if err != nil {
+		return nil, err
+	}
+	hookName := aws.StringValue(input.LifecycleHookName)
+	if f.lifecycleHook(asgName, hookName) == nil {
+		return nil, validationError(fmt.Sprintf("No Lifecycle Hook found with name %s for group %s", hookName, asgName))
+	}
+
+	for _, instance := range group.Instances {
+		if aws.StringValue(instance.InstanceId) != aws.StringValue(input.InstanceId) {
+			continue
+		}
+		switch aws.StringValue(instance.LifecycleState) {
+		case autoscaling.LifecycleStatePendingWait:
+			instance.LifecycleState = aws.String(autoscaling.LifecycleStatePendingProceed)
+		case autoscaling.LifecycleStateTerminatingWait:
+			instance.LifecycleState = aws.String(autoscaling.LifecycleStateTerminatingProceed)
+		}
+	}
+	f.completedLifecycleActions = append(f.completedLifecycleActions, copyOf(input))
+	return &autoscaling.CompleteLifecycleActionOutput{}, nil
+}
+
+// AttachLoadBalancerTargetGroupsWithContext attaches target groups to an AutoScalingGroup.
+func (f *AutoScalingAPI) AttachLoadBalancerTargetGroupsWithContext(_ aws.Context, input *autoscaling.AttachLoadBalancerTargetGroupsInput, _ ...request.Option) (*autoscaling.AttachLoadBalancerTargetGroupsOutput, error) {
+	if err := f.call("AttachLoadBalancerTargetGroups", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	group, err := f.group(aws.StringValue(input.AutoScalingGroupName))
+	if err != nil {
+		return nil, err
+	}
+	for _, arn := range aws.StringValueSlice(input.TargetGroupARNs) {
+		if !contains(aws.StringValueSlice(group.TargetGroupARNs), arn) {
+			group.TargetGroupARNs = append(group.TargetGroupARNs, aws.String(arn))
+		}
+	}
+	return &autoscaling.AttachLoadBalancerTargetGroupsOutput{}, nil
+}
+
+// DetachLoadBalancerTargetGroupsWithContext detaches target groups from an AutoScalingGroup. The detachment is immediate.
+func (f *AutoScalingAPI) DetachLoadBalancerTargetGroupsWithContext(_ aws.Context, input *autoscaling.DetachLoadBalancerTargetGroupsInput, _ ...request.Option) (*autoscaling.DetachLoadBalancerTargetGroupsOutput, error) {
+	if err := f.call("DetachLoadBalancerTargetGroups", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	group, err := f.group(aws.StringValue(input.AutoScalingGroupName))
+	if err != nil {
+		return nil, err
+	}
+	detached := aws.StringValueSlice(input.TargetGroupARNs)
+	arns := group.TargetGroupARNs[:0]
+	for _, arn := range group.TargetGroupARNs {
+		if !contains(detached, aws.StringValue(arn)) {
+			arns = append(arns, arn)
+		}
+	}
+	group.TargetGroupARNs = arns
+	return &autoscaling.DetachLoadBalancerTargetGroupsOutput{}, nil
+}
+
+// DescribeLoadBalancerTargetGroupsWithContext returns the target groups attached to an AutoScalingGroup,
+// paginated by MaxRecords.
+func (f *AutoScalingAPI) DescribeLoadBalancerTargetGroupsWithContext(_ aws.Context, input *autoscaling.DescribeLoadBalancerTargetGroupsInput, _ ...request.Option) (*autoscaling.DescribeLoadBalancerTargetGroupsOutput, error) {
+	if err := f.call("DescribeLoadBalancerTargetGroups", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	group, err := f.group(aws.StringValue(input.AutoScalingGroupName))
+	if err != nil {
+		return nil, err
+	}
+	start, end, next, err := page(len(group.TargetGroupARNs), input.NextToken, input.MaxRecords)
+	if err != nil {
+		return nil, err
+	}
+	out := &autoscaling.DescribeLoadBalancerTargetGroupsOutput{
+		LoadBalancerTargetGroups: []*autoscaling.LoadBalancerTargetGroupState{},
+		NextToken:                next,
+	}
+	for _, arn := range group.TargetGroupARNs[start:end] {
+		out.LoadBalancerTargetGroups = append(out.LoadBalancerTargetGroups, &autoscaling.LoadBalancerTargetGroupState{
+			LoadBalancerTargetGroupARN: aws.String(aws.StringValue(arn)),
+			State:                      aws.String("InService"),
+		})
+	}
+	return out, nil
+}
+
+// DetachInstancesWithContext detaches instances from an AutoScalingGroup, and decrements its desired capacity when
+// requested. The instances must be InService or Standby. The detachment is immediate.
+func (f *AutoScalingAPI) DetachInstancesWithContext(_ aws.Context, input *autoscaling.DetachInstancesInput, _ ...request.Option) (*autoscaling.DetachInstancesOutput, error) {
+	if err := f.call("DetachInstances", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if input.ShouldDecrementDesiredCapacity == nil {
+		return nil, validationError("ShouldDecrementDesiredCapacity is required")
+	}
+	group, err := f.group(aws.StringValue(input.AutoScalingGroupName))
+	if err != nil {
+		return nil, err
+	}
+	detached := aws.StringValueSlice(input.InstanceIds)
+	if len(detached) > 20 {
+		return nil, validationError("The number of instances to detach exceeds the maximum of 20")
+	}
+	for _, id := range detached {
+		var found bool
+		for _, instance := range group.Instances {
+			if aws.StringValue(instance.InstanceId) != id {
+				continue
+			}
+			found = true
+			if state := aws.StringValue(instance.LifecycleState); state != autoscaling.LifecycleStateInService && state != autoscaling.LifecycleStateStandby {
+				return nil, validationError(fmt.Sprintf("The instance %s is not in InService or Standby", id))
+			}
+		}
+		if !found {
+			return nil, validationError(fmt.Sprintf("The instance %s is not part of Auto Scaling group %s", id, aws.StringValue(group.AutoScalingGroupName)))
+		}
+	}
+
+	instances := group.Instances[:0]
+	for _, instance := range group.Instances {
+		if !contains(detached, aws.StringValue(instance.InstanceId)) {
+			instances = append(instances, instance)
+		}
+	}
+	group.Instances = instances
+	if aws.BoolValue(input.ShouldDecrementDesiredCapacity) {
+		group.DesiredCapacity = aws.Int64(aws.Int64Value(group.DesiredCapacity) - int64(len(detached)))
+	}
+	return &autoscaling.DetachInstancesOutput{}, nil
+}
+
+// group returns the stored AutoScalingGroup, or the error AWS returns when it doesn't exist.
+func (f *AutoScalingAPI) group(name string) (*autoscaling.Group, error) {
+	group, ok := f.groups[name]
+	if !ok {
+		return nil, validationError(fmt.Sprintf("AutoScalingGroup name not found - AutoScalingGroup '%s' not found", name))
+	}
+	return group, nil
+}
+
+func (f *AutoScalingAPI) lifecycleHook(asgName, hookName string) *autoscaling.LifecycleHook {
+	for _, hook := range f.lifecycleHooks[asgName] {
+		if aws.StringValue(hook.LifecycleHookName) == hookName {
+			return hook
+		}
+	}
+	return nil
+}
+
+func (f *AutoScalingAPI) scheduledAction(asgName, actionName string) *autoscaling.ScheduledUpdateGroupAction {
+	for _, action := range f.scheduledActions[asgName] {
+		if aws.StringValue(action.ScheduledActionName) == actionName {
+			return action
+		}
+	}
+	return nil
+}
+
+func validateCapacity(group *autoscaling.Group) error {
+	minSize, maxSize, desired := aws.Int64Value(group.MinSize), aws.Int64Value(group.MaxSize), aws.Int64Value(group.DesiredCapacity)
+	if minSize > maxSize {
+		return validationError(fmt.Sprintf("Max bound, %d, must be greater than or equal to min bound, %d", maxSize, minSize))
+	}
+	if desired < minSize || desired > maxSize {
+		return validationError(fmt.Sprintf("Desired capacity:%d must be between the specified min size:%d and max size:%d", desired, minSize, maxSize))
+	}
+	return nil
+}
+
+// normalizeVPCZoneIdentifier removes the spaces around the subnet IDs, as AWS does.
+func normalizeVPCZoneIdentifier(v *string) *string {
+	if v == nil {
+		return nil
+	}
+	subnets := strings.Split(*v, ",")
+	for i := range subnets {
+		subnets[i] = strings.TrimSpace(subnets[i])
+	}
+	return aws.String(strings.Join(subnets, ","))
+}
+
+// setTag creates or updates a tag of the AutoScalingGroup, keeping the tags sorted by key.
+func setTag(group *autoscaling.Group, tag *autoscaling.Tag) {
+	description := &autoscaling.TagDescription{
+		Key:               tag.Key,
+		PropagateAtLaunch: aws.Bool(aws.BoolValue(tag.PropagateAtLaunch)),
+		ResourceId:        group.AutoScalingGroupName,
+		ResourceType:      aws.String("auto-scaling-group"),
+		Value:             tag.Value,
+	}
+	for i, t := range group.Tags {
+		if aws.StringValue(t.Key) == aws.StringValue(tag.Key) {
+			group.Tags[i] = description
+			return
+		}
+	}
+	group.Tags = append(group.Tags, description)
+	sort.Slice(group.Tags, func(i, j int) bool { return *group.Tags[i].Key < *group.Tags[j].Key })
+}
+
+// defaultLifecycleHook sets the defaults AWS applies to the fields of a lifecycle hook that are not set.
+func defaultLifecycleHook(hook *autoscaling.LifecycleHook) {
+	if hook.HeartbeatTimeout == nil {
+		hook.HeartbeatTimeout = aws.Int64(defaultHeartbeatTimeout)
+	}
+	if hook.DefaultResult == nil {
+		hook.DefaultResult = aws.String("ABANDON")
+	}
+	if hook.GlobalTimeout == nil {
+		hook.GlobalTimeout = aws.Int64(min(100*aws.Int64Value(hook.HeartbeatTimeout), maxGlobalTimeout))
+	}
+}
+
+func sortedSuspendedProcesses(processes map[string]*autoscaling.SuspendedProcess) []*autoscaling.SuspendedProcess {
+	res := make([]*autoscaling.SuspendedProcess, 0, len(processes))
+	for _, p := range processes {
+		res = append(res, p)
+	}
+	sort.Slice(res, func(i, j int) bool { return *res[i].ProcessName < *res[j].ProcessName })
+	return res
+}
+
+func sortedEnabledMetrics(metrics map[string]*autoscaling.EnabledMetric) []*autoscaling.EnabledMetric {
+	res := make([]*autoscaling.EnabledMetric, 0, len(metrics))
+	for _, m := range metrics {
+		res = append(res, m)
+	}
+	sort.Slice(res, func(i, j int) bool { return *res[i].Metric < *res[j].Metric })
+	return res
+}
+
+// page returns the bounds of the page of items starting at the token, and the token of the next page.
+func page(items int, token *string, maxRecords *int64) (start, end int, next *string, err error) {
+	if token != nil {
+		start, err = strconv.Atoi(*token)
+		if err != nil || start < 0 || start > items {
+			return 0, 0, nil, awserr.New("InvalidNextToken", "The token is invalid", nil)
+		}
+	}
+	end = items
+	if maxRecords != nil && start+int(*maxRecords) < items {
+		end = start + int(*maxRecords)
+		next = aws.String(strconv.Itoa(end))
+	}
+	return start, end, next, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakeaws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	. "github.com/onsi/gomega"
+)
+
+func TestAutoScalingAPIGroups(t *testing.T) {
+	g := NewWithT(t)
+	f := NewAutoScalingAPI()
+
+	input := &autoscaling.CreateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String("asg"),
+		MinSize:              aws.Int64(1),
+		MaxSize:              aws.Int64(3),
+		VPCZoneIdentifier:    aws.String("subnet-1, subnet-2"),
+		LaunchTemplate:       &autoscaling.LaunchTemplateSpecification{LaunchTemplateId: aws.String("lt-1")},
+	}
+	_, err := f.CreateAutoScalingGroupWithContext(context.TODO(), input)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The stored group must not be modified through the input.
+	input.MaxSize = aws.Int64(10)
+
+	_, err = f.CreateAutoScalingGroupWithContext(context.TODO(), input)
+	g.Expect(err).To(MatchError(ContainSubstring(autoscaling.ErrCodeAlreadyExistsFault)))
+
+	out, err := f.DescribeAutoScalingGroupsWithContext(context.TODO(), &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: aws.StringSlice([]string{"asg", "unknown"}),
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(out.AutoScalingGroups).To(HaveLen(1))
+	group := out.AutoScalingGroups[0]
+	g.Expect(group.MaxSize).To(Equal(aws.Int64(3)))
+	g.Expect(group.DesiredCapacity).To(Equal(aws.Int64(1)))
+	g.Expect(group.VPCZoneIdentifier).To(Equal(aws.String("subnet-1,subnet-2")))
+
+	// Neither must it be modified through the output.
+	group.MaxSize = aws.Int64(10)
+	out, err = f.DescribeAutoScalingGroupsWithContext(context.TODO(), &autoscaling.DescribeAutoScalingGroupsInput{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(out.AutoScalingGroups[0].MaxSize).To(Equal(aws.Int64(3)))
+
+	_, err = f.UpdateAutoScalingGroupWithContext(context.TODO(), &autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String("asg"),
+		DesiredCapacity:      aws.Int64(5),
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("ValidationError")))
+
+	_, err = f.DeleteAutoScalingGroupWithContext(context.TODO(), &autoscaling.DeleteAutoScalingGroupInput{AutoScalingGroupName: aws.String("asg")})
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = f.DeleteAutoScalingGroupWithContext(context.TODO(), &autoscaling.DeleteAutoScalingGroupInput{AutoScalingGroupName: aws.String("asg")})
+	g.Expect(err).To(MatchError(ContainSubstring("ValidationError")))
+}
+
+func TestAutoScalingAPIInjectError(t *testing.T) {
+	g := NewWithT(t)
+	f := NewAutoScalingAPI()
+	f.AddAutoScalingGroup(&autoscaling.Group{AutoScalingGroupName: aws.String("asg")})
+
+	injected := awserr.New("Throttling", "Rate exceeded", nil)
+	f.InjectError("DescribeAutoScalingGroups", injected)
+
+	_, err := f.DescribeAutoScalingGroupsWithContext(context.TODO(), &autoscaling.DescribeAutoScalingGroupsInput{})
+	g.Expect(err).To(Equal(injected))
+	// Injected errors are only returned once.
+	_, err = f.DescribeAutoScalingGroupsWithContext(context.TODO(), &autoscaling.DescribeAutoScalingGroupsInput{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	f.InjectErrorFunc(func(operation string, input interface{}) error {
+		if in, ok := input.(*autoscaling.DeleteAutoScalingGroupInput); ok && aws.BoolValue(in.ForceDelete) {
+			return errors.New("force delete")
+		}
+		return nil
+	})
+	_, err = f.DeleteAutoScalingGroupWithContext(context.TODO(), &autoscaling.DeleteAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String("asg"),
+		ForceDelete:          aws.Bool(true),
+	})
+	g.Expect(err).To(MatchError("force delete"))
+	_, err = f.DeleteAutoScalingGroupWithContext(context.TODO(), &autoscaling.DeleteAutoScalingGroupInput{AutoScalingGroupName: aws.String("asg")})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(f.Calls()).To(Equal([]string{
+		"DescribeAutoScalingGroups", "DescribeAutoScalingGroups", "DeleteAutoScalingGroup", "DeleteAutoScalingGroup",
+	}))
+}
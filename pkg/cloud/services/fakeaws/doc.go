@@ -0,0 +1,27 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fakeaws provides stateful in-memory implementations of the AWS SDK clients used by the
+// services, for integration-style tests.
+//
+// Unlike the gomock based mocks, the fakes keep the resources they are given: created resources
+// are returned by the describe calls and removed by the delete calls, so tests can assert the
+// resulting state rather than the exact sequence of calls. Only the operations used by the
+// services are implemented, calling any other operation panics.
+//
+// Errors can be injected per operation with InjectError, and the operations called are recorded
+// in order and returned by Calls.
+package fakeaws
@@ -0,0 +1,674 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakeaws
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+)
+
+const (
+	launchTemplateVersionNotFound = "InvalidLaunchTemplateId.VersionNotFound"
+	launchTemplateNameExists      = "InvalidLaunchTemplateName.AlreadyExistsException"
+	invalidParameterValue         = "InvalidParameterValue"
+	invalidInstanceType           = "InvalidInstanceType"
+
+	latestVersion  = "$Latest"
+	defaultVersion = "$Default"
+)
+
+// EC2API is an in-memory implementation of ec2iface.EC2API, covering launch templates and the
+// lookups of subnets, security groups, spot instance requests, addresses, NAT gateways, instances
+// and instance types.
+type EC2API struct {
+	// EC2API is nil, calling an operation that is not implemented by the fake panics.
+	ec2iface.EC2API
+	faults
+
+	mu                   sync.Mutex
+	lastID               int
+	launchTemplates      map[string]*launchTemplate
+	subnets              []*ec2.Subnet
+	securityGroups       []*ec2.SecurityGroup
+	spotInstanceRequests []*ec2.SpotInstanceRequest
+	addresses            []*ec2.Address
+	natGateways          []*ec2.NatGateway
+	instances            []*ec2.Instance
+	instanceTypes        []*ec2.InstanceTypeInfo
+}
+
+type launchTemplate struct {
+	template *ec2.LaunchTemplate
+	versions []*ec2.LaunchTemplateVersion
+}
+
+var _ ec2iface.EC2API = &EC2API{}
+
+// NewEC2API returns an EC2API without any resources.
+func NewEC2API() *EC2API {
+	return &EC2API{
+		launchTemplates: map[string]*launchTemplate{},
+	}
+}
+
+// AddSubnets adds existing subnets, returned by DescribeSubnets.
+func (f *EC2API) AddSubnets(subnets ...*ec2.Subnet) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.subnets = append(f.subnets, copyAll(subnets)...)
+}
+
+// AddSecurityGroups adds existing security groups, returned by DescribeSecurityGroups.
+func (f *EC2API) AddSecurityGroups(securityGroups ...*ec2.SecurityGroup) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.securityGroups = append(f.securityGroups, copyAll(securityGroups)...)
+}
+
+// AddSpotInstanceRequests adds existing spot instance requests, returned by DescribeSpotInstanceRequests.
+func (f *EC2API) AddSpotInstanceRequests(requests ...*ec2.SpotInstanceRequest) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.spotInstanceRequests = append(f.spotInstanceRequests, copyAll(requests)...)
+}
+
+// AddAddresses adds existing Elastic IP addresses, returned by DescribeAddresses.
+func (f *EC2API) AddAddresses(addresses ...*ec2.Address) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.addresses = append(f.addresses, copyAll(addresses)...)
+}
+
+// AddNatGateways adds existing NAT gateways, returned by DescribeNatGateways.
+func (f *EC2API) AddNatGateways(natGateways ...*ec2.NatGateway) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.natGateways = append(f.natGateways, copyAll(natGateways)...)
+}
+
+// AddInstances adds existing instances, returned by DescribeInstances.
+func (f *EC2API) AddInstances(instances ...*ec2.Instance) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.instances = append(f.instances, copyAll(instances)...)
+}
+
+// AddInstanceTypes adds the instance types offered in the region, returned by DescribeInstanceTypes.
+func (f *EC2API) AddInstanceTypes(instanceTypes ...*ec2.InstanceTypeInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.instanceTypes = append(f.instanceTypes, copyAll(instanceTypes)...)
+}
+
+// CreateLaunchTemplateWithContext creates a launch template with its first version, which is the default one.
+func (f *EC2API) CreateLaunchTemplateWithContext(_ aws.Context, input *ec2.CreateLaunchTemplateInput, _ ...request.Option) (*ec2.CreateLaunchTemplateOutput, error) {
+	if err := f.call("CreateLaunchTemplate", input); err != nil {
+		return nil, err
+	}
+	// The input is copied, so that the stored resources don't share pointers with the caller.
+	input = copyOf(input)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := aws.StringValue(input.LaunchTemplateName)
+	if name == "" || input.LaunchTemplateData == nil {
+		return nil, awserr.New(awserrors.VPCMissingParameter, "LaunchTemplateName and LaunchTemplateData are required", nil)
+	}
+	for _, lt := range f.launchTemplates {
+		if aws.StringValue(lt.template.LaunchTemplateName) == name {
+			return nil, awserr.New(launchTemplateNameExists, fmt.Sprintf("Launch template name already in use: %s", name), nil)
+		}
+	}
+
+	f.lastID++
+	id := fmt.Sprintf("lt-%017x", f.lastID)
+	now := time.Now()
+	lt := &launchTemplate{
+		template: &ec2.LaunchTemplate{
+			CreateTime:           aws.Time(now),
+			DefaultVersionNumber: aws.Int64(1),
+			LatestVersionNumber:  aws.Int64(1),
+			LaunchTemplateId:     aws.String(id),
+			LaunchTemplateName:   aws.String(name),
+		},
+	}
+	for _, spec := range input.TagSpecifications {
+		if aws.StringValue(spec.ResourceType) == ec2.ResourceTypeLaunchTemplate {
+			lt.template.Tags = append(lt.template.Tags, copyAll(spec.Tags)...)
+		}
+	}
+	version, err := newLaunchTemplateVersion(lt.template, 1, input.VersionDescription, input.LaunchTemplateData, now)
+	if err != nil {
+		return nil, err
+	}
+	version.DefaultVersion = aws.Bool(true)
+	lt.versions = []*ec2.LaunchTemplateVersion{version}
+
+	f.launchTemplates[id] = lt
+	return &ec2.CreateLaunchTemplateOutput{LaunchTemplate: copyOf(lt.template)}, nil
+}
+
+// CreateLaunchTemplateVersionWithContext creates a new latest version of a launch template.
+func (f *EC2API) CreateLaunchTemplateVersionWithContext(_ aws.Context, input *ec2.CreateLaunchTemplateVersionInput, _ ...request.Option) (*ec2.CreateLaunchTemplateVersionOutput, error) {
+	if err := f.call("CreateLaunchTemplateVersion", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	lt, err := f.launchTemplate(input.LaunchTemplateId, input.LaunchTemplateName)
+	if err != nil {
+		return nil, err
+	}
+	if input.LaunchTemplateData == nil {
+		return nil, awserr.New(awserrors.VPCMissingParameter, "LaunchTemplateData is required", nil)
+	}
+
+	number := aws.Int64Value(lt.template.LatestVersionNumber) + 1
+	version, err := newLaunchTemplateVersion(lt.template, number, input.VersionDescription, input.LaunchTemplateData, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	lt.versions = append(lt.versions, version)
+	lt.template.LatestVersionNumber = aws.Int64(number)
+	return &ec2.CreateLaunchTemplateVersionOutput{LaunchTemplateVersion: copyOf(version)}, nil
+}
+
+// DescribeLaunchTemplateVersionsWithContext returns the versions of a launch template, either the ones
+// listed in Versions or the ones between MinVersion and MaxVersion, by ascending version number and
+// paginated by MaxResults.
+func (f *EC2API) DescribeLaunchTemplateVersionsWithContext(_ aws.Context, input *ec2.DescribeLaunchTemplateVersionsInput, _ ...request.Option) (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+	if err := f.call("DescribeLaunchTemplateVersions", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	lt, err := f.launchTemplate(input.LaunchTemplateId, input.LaunchTemplateName)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []*ec2.LaunchTemplateVersion
+	if len(input.Versions) > 0 {
+		for _, v := range aws.StringValueSlice(input.Versions) {
+			number, err := lt.versionNumber(v)
+			if err != nil {
+				return nil, err
+			}
+			version := lt.version(number)
+			if version == nil {
+				return nil, awserr.New(launchTemplateVersionNotFound, fmt.Sprintf("Could not find launch template version %s", v), nil)
+			}
+			versions = append(versions, version)
+		}
+	} else {
+		minVersion, maxVersion := int64(0), aws.Int64Value(lt.template.LatestVersionNumber)
+		if input.MinVersion != nil {
+			if minVersion, err = lt.versionNumber(*input.MinVersion); err != nil {
+				return nil, err
+			}
+		}
+		if input.MaxVersion != nil {
+			if maxVersion, err = lt.versionNumber(*input.MaxVersion); err != nil {
+				return nil, err
+			}
+		}
+		for _, version := range lt.versions {
+			if number := aws.Int64Value(version.VersionNumber); number >= minVersion && number <= maxVersion {
+				versions = append(versions, version)
+			}
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool { return *versions[i].VersionNumber < *versions[j].VersionNumber })
+
+	start, end, next, err := page(len(versions), input.NextToken, input.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeLaunchTemplateVersionsOutput{
+		LaunchTemplateVersions: copyAll(versions[start:end]),
+		NextToken:              next,
+	}, nil
+}
+
+// DeleteLaunchTemplateVersionsWithContext deletes versions of a launch template. As in AWS, the default
+// version and unknown versions are reported as unsuccessfully deleted rather than failing the call.
+func (f *EC2API) DeleteLaunchTemplateVersionsWithContext(_ aws.Context, input *ec2.DeleteLaunchTemplateVersionsInput, _ ...request.Option) (*ec2.DeleteLaunchTemplateVersionsOutput, error) {
+	if err := f.call("DeleteLaunchTemplateVersions", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	lt, err := f.launchTemplate(input.LaunchTemplateId, input.LaunchTemplateName)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ec2.DeleteLaunchTemplateVersionsOutput{}
+	for _, v := range aws.StringValueSlice(input.Versions) {
+		number, err := strconv.ParseInt(v, 10, 64)
+		var code, msg string
+		switch {
+		case err != nil || lt.version(number) == nil:
+			code, msg = "launchTemplateVersionDoesNotExist", fmt.Sprintf("The specified launch template version %s does not exist", v)
+		case number == aws.Int64Value(lt.template.DefaultVersionNumber):
+			code, msg = "launchTemplateVersionIsDefaultVersion", "Cannot delete the default version of a launch template"
+		}
+		if code != "" {
+			out.UnsuccessfullyDeletedLaunchTemplateVersions = append(out.UnsuccessfullyDeletedLaunchTemplateVersions, &ec2.DeleteLaunchTemplateVersionsResponseErrorItem{
+				LaunchTemplateId:   lt.template.LaunchTemplateId,
+				LaunchTemplateName: lt.template.LaunchTemplateName,
+				ResponseError:      &ec2.ResponseError{Code: aws.String(code), Message: aws.String(msg)},
+				VersionNumber:      aws.Int64(number),
+			})
+			continue
+		}
+
+		versions := lt.versions[:0]
+		for _, version := range lt.versions {
+			if aws.Int64Value(version.VersionNumber) != number {
+				versions = append(versions, version)
+			}
+		}
+		lt.versions = versions
+		out.SuccessfullyDeletedLaunchTemplateVersions = append(out.SuccessfullyDeletedLaunchTemplateVersions, &ec2.DeleteLaunchTemplateVersionsResponseSuccessItem{
+			LaunchTemplateId:   lt.template.LaunchTemplateId,
+			LaunchTemplateName: lt.template.LaunchTemplateName,
+			VersionNumber:      aws.Int64(number),
+		})
+	}
+	return out, nil
+}
+
+// DeleteLaunchTemplateWithContext deletes a launch template and all its versions.
+func (f *EC2API) DeleteLaunchTemplateWithContext(_ aws.Context, input *ec2.DeleteLaunchTemplateInput, _ ...request.Option) (*ec2.DeleteLaunchTemplateOutput, error) {
+	if err := f.call("DeleteLaunchTemplate", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	lt, err := f.launchTemplate(input.LaunchTemplateId, input.LaunchTemplateName)
+	if err != nil {
+		return nil, err
+	}
+	delete(f.launchTemplates, aws.StringValue(lt.template.LaunchTemplateId))
+	return &ec2.DeleteLaunchTemplateOutput{LaunchTemplate: copyOf(lt.template)}, nil
+}
+
+// DescribeSubnetsWithContext returns the subnets added with AddSubnets matching the input.
+func (f *EC2API) DescribeSubnetsWithContext(_ aws.Context, input *ec2.DescribeSubnetsInput, _ ...request.Option) (*ec2.DescribeSubnetsOutput, error) {
+	if err := f.call("DescribeSubnets", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := &ec2.DescribeSubnetsOutput{Subnets: []*ec2.Subnet{}}
+	for _, subnet := range f.subnets {
+		if len(input.SubnetIds) > 0 && !contains(aws.StringValueSlice(input.SubnetIds), aws.StringValue(subnet.SubnetId)) {
+			continue
+		}
+		ok, err := matchFilters(input.Filters, map[string]string{
+			"availability-zone":    aws.StringValue(subnet.AvailabilityZone),
+			"availability-zone-id": aws.StringValue(subnet.AvailabilityZoneId),
+			"cidr-block":           aws.StringValue(subnet.CidrBlock),
+			"state":                aws.StringValue(subnet.State),
+			"subnet-id":            aws.StringValue(subnet.SubnetId),
+			"vpc-id":               aws.StringValue(subnet.VpcId),
+		}, subnet.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out.Subnets = append(out.Subnets, copyOf(subnet))
+		}
+	}
+	return out, nil
+}
+
+// DescribeSecurityGroupsWithContext returns the security groups added with AddSecurityGroups matching the input.
+func (f *EC2API) DescribeSecurityGroupsWithContext(_ aws.Context, input *ec2.DescribeSecurityGroupsInput, _ ...request.Option) (*ec2.DescribeSecurityGroupsOutput, error) {
+	if err := f.call("DescribeSecurityGroups", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := &ec2.DescribeSecurityGroupsOutput{SecurityGroups: []*ec2.SecurityGroup{}}
+	for _, sg := range f.securityGroups {
+		if len(input.GroupIds) > 0 && !contains(aws.StringValueSlice(input.GroupIds), aws.StringValue(sg.GroupId)) {
+			continue
+		}
+		if len(input.GroupNames) > 0 && !contains(aws.StringValueSlice(input.GroupNames), aws.StringValue(sg.GroupName)) {
+			continue
+		}
+		ok, err := matchFilters(input.Filters, map[string]string{
+			"description": aws.StringValue(sg.Description),
+			"group-id":    aws.StringValue(sg.GroupId),
+			"group-name":  aws.StringValue(sg.GroupName),
+			"vpc-id":      aws.StringValue(sg.VpcId),
+		}, sg.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out.SecurityGroups = append(out.SecurityGroups, copyOf(sg))
+		}
+	}
+	return out, nil
+}
+
+// DescribeSpotInstanceRequestsPagesWithContext calls fn with a single page holding the spot instance
+// requests added with AddSpotInstanceRequests matching the input.
+func (f *EC2API) DescribeSpotInstanceRequestsPagesWithContext(_ aws.Context, input *ec2.DescribeSpotInstanceRequestsInput, fn func(*ec2.DescribeSpotInstanceRequestsOutput, bool) bool, _ ...request.Option) error {
+	if err := f.call("DescribeSpotInstanceRequests", input); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	out := &ec2.DescribeSpotInstanceRequestsOutput{SpotInstanceRequests: []*ec2.SpotInstanceRequest{}}
+	for _, sir := range f.spotInstanceRequests {
+		if len(input.SpotInstanceRequestIds) > 0 && !contains(aws.StringValueSlice(input.SpotInstanceRequestIds), aws.StringValue(sir.SpotInstanceRequestId)) {
+			continue
+		}
+		ok, err := matchFilters(input.Filters, map[string]string{
+			"instance-id":              aws.StringValue(sir.InstanceId),
+			"spot-instance-request-id": aws.StringValue(sir.SpotInstanceRequestId),
+			"state":                    aws.StringValue(sir.State),
+		}, sir.Tags)
+		if err != nil {
+			f.mu.Unlock()
+			return err
+		}
+		if ok {
+			out.SpotInstanceRequests = append(out.SpotInstanceRequests, copyOf(sir))
+		}
+	}
+	f.mu.Unlock()
+
+	// The lock is released so that fn can call the fake.
+	fn(out, true)
+	return nil
+}
+
+// DescribeAddressesWithContext returns the addresses added with AddAddresses matching the input.
+func (f *EC2API) DescribeAddressesWithContext(_ aws.Context, input *ec2.DescribeAddressesInput, _ ...request.Option) (*ec2.DescribeAddressesOutput, error) {
+	if err := f.call("DescribeAddresses", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := &ec2.DescribeAddressesOutput{Addresses: []*ec2.Address{}}
+	for _, address := range f.addresses {
+		if len(input.AllocationIds) > 0 && !contains(aws.StringValueSlice(input.AllocationIds), aws.StringValue(address.AllocationId)) {
+			continue
+		}
+		ok, err := matchFilters(input.Filters, map[string]string{
+			"allocation-id":  aws.StringValue(address.AllocationId),
+			"association-id": aws.StringValue(address.AssociationId),
+			"domain":         aws.StringValue(address.Domain),
+			"public-ip":      aws.StringValue(address.PublicIp),
+		}, address.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out.Addresses = append(out.Addresses, copyOf(address))
+		}
+	}
+	return out, nil
+}
+
+// DescribeNatGatewaysPagesWithContext calls fn with a single page holding the NAT gateways added
+// with AddNatGateways matching the input.
+func (f *EC2API) DescribeNatGatewaysPagesWithContext(_ aws.Context, input *ec2.DescribeNatGatewaysInput, fn func(*ec2.DescribeNatGatewaysOutput, bool) bool, _ ...request.Option) error {
+	if err := f.call("DescribeNatGateways", input); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	out := &ec2.DescribeNatGatewaysOutput{NatGateways: []*ec2.NatGateway{}}
+	for _, ngw := range f.natGateways {
+		if len(input.NatGatewayIds) > 0 && !contains(aws.StringValueSlice(input.NatGatewayIds), aws.StringValue(ngw.NatGatewayId)) {
+			continue
+		}
+		ok, err := matchFilters(input.Filter, map[string]string{
+			"nat-gateway-id": aws.StringValue(ngw.NatGatewayId),
+			"state":          aws.StringValue(ngw.State),
+			"subnet-id":      aws.StringValue(ngw.SubnetId),
+			"vpc-id":         aws.StringValue(ngw.VpcId),
+		}, ngw.Tags)
+		if err != nil {
+			f.mu.Unlock()
+			return err
+		}
+		if ok {
+			out.NatGateways = append(out.NatGateways, copyOf(ngw))
+		}
+	}
+	f.mu.Unlock()
+
+	// The lock is released so that fn can call the fake.
+	fn(out, true)
+	return nil
+}
+
+// DescribeInstancesPagesWithContext calls fn with a single page holding a reservation per instance
+// added with AddInstances matching the input.
+func (f *EC2API) DescribeInstancesPagesWithContext(_ aws.Context, input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool, _ ...request.Option) error {
+	if err := f.call("DescribeInstances", input); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	out := &ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{}}
+	for _, instance := range f.instances {
+		if len(input.InstanceIds) > 0 && !contains(aws.StringValueSlice(input.InstanceIds), aws.StringValue(instance.InstanceId)) {
+			continue
+		}
+		var state string
+		if instance.State != nil {
+			state = aws.StringValue(instance.State.Name)
+		}
+		ok, err := matchFilters(input.Filters, map[string]string{
+			"instance-id":         aws.StringValue(instance.InstanceId),
+			"instance-lifecycle":  aws.StringValue(instance.InstanceLifecycle),
+			"instance-state-name": state,
+			"instance-type":       aws.StringValue(instance.InstanceType),
+			"subnet-id":           aws.StringValue(instance.SubnetId),
+			"vpc-id":              aws.StringValue(instance.VpcId),
+		}, instance.Tags)
+		if err != nil {
+			f.mu.Unlock()
+			return err
+		}
+		if ok {
+			out.Reservations = append(out.Reservations, &ec2.Reservation{Instances: []*ec2.Instance{copyOf(instance)}})
+		}
+	}
+	f.mu.Unlock()
+
+	// The lock is released so that fn can call the fake.
+	fn(out, true)
+	return nil
+}
+
+// DescribeInstanceTypesWithContext returns the instance types added with AddInstanceTypes matching the input.
+// As AWS does, it fails when one of the requested instance types is not offered.
+func (f *EC2API) DescribeInstanceTypesWithContext(_ aws.Context, input *ec2.DescribeInstanceTypesInput, _ ...request.Option) (*ec2.DescribeInstanceTypesOutput, error) {
+	if err := f.call("DescribeInstanceTypes", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := &ec2.DescribeInstanceTypesOutput{InstanceTypes: []*ec2.InstanceTypeInfo{}}
+	for _, requested := range aws.StringValueSlice(input.InstanceTypes) {
+		found := false
+		for _, info := range f.instanceTypes {
+			if aws.StringValue(info.InstanceType) == requested {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, awserr.New(invalidInstanceType, fmt.Sprintf("The following supplied instance types do not exist: [%s]", requested), nil)
+		}
+	}
+	for _, info := range f.instanceTypes {
+		if len(input.InstanceTypes) > 0 && !contains(aws.StringValueSlice(input.InstanceTypes), aws.StringValue(info.InstanceType)) {
+			continue
+		}
+		out.InstanceTypes = append(out.InstanceTypes, copyOf(info))
+	}
+	return out, nil
+}
+
+// launchTemplate returns the stored launch template with the given ID or name, or the error AWS returns
+// when it doesn't exist.
+func (f *EC2API) launchTemplate(id, name *string) (*launchTemplate, error) {
+	if id != nil {
+		lt, ok := f.launchTemplates[*id]
+		if !ok {
+			return nil, awserr.New(awserrors.LaunchTemplateIDNotFound, fmt.Sprintf("The specified launch template, with template ID %s, does not exist.", *id), nil)
+		}
+		return lt, nil
+	}
+	for _, lt := range f.launchTemplates {
+		if aws.StringValue(lt.template.LaunchTemplateName) == aws.StringValue(name) {
+			return lt, nil
+		}
+	}
+	return nil, awserr.New(awserrors.LaunchTemplateNameNotFound, fmt.Sprintf("The specified launch template, with template name %s, does not exist.", aws.StringValue(name)), nil)
+}
+
+// versionNumber resolves a version of the launch template, which can be a number, $Latest or $Default.
+func (lt *launchTemplate) versionNumber(v string) (int64, error) {
+	switch v {
+	case latestVersion:
+		return aws.Int64Value(lt.template.LatestVersionNumber), nil
+	case defaultVersion:
+		return aws.Int64Value(lt.template.DefaultVersionNumber), nil
+	}
+	number, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, awserr.New(invalidParameterValue, fmt.Sprintf("Invalid launch template version %q", v), nil)
+	}
+	return number, nil
+}
+
+func (lt *launchTemplate) version(number int64) *ec2.LaunchTemplateVersion {
+	for _, version := range lt.versions {
+		if aws.Int64Value(version.VersionNumber) == number {
+			return version
+		}
+	}
+	return nil
+}
+
+// newLaunchTemplateVersion returns a version of the launch template holding the requested data.
+func newLaunchTemplateVersion(template *ec2.LaunchTemplate, number int64, description *string, data *ec2.RequestLaunchTemplateData, now time.Time) (*ec2.LaunchTemplateVersion, error) {
+	// The request and response types of the launch template data mirror each other field by field
+	// but are distinct types, a JSON round trip converts one into the other.
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	responseData := &ec2.ResponseLaunchTemplateData{}
+	if err := json.Unmarshal(raw, responseData); err != nil {
+		return nil, err
+	}
+
+	return &ec2.LaunchTemplateVersion{
+		CreateTime:         aws.Time(now),
+		DefaultVersion:     aws.Bool(false),
+		LaunchTemplateData: responseData,
+		LaunchTemplateId:   template.LaunchTemplateId,
+		LaunchTemplateName: template.LaunchTemplateName,
+		VersionDescription: description,
+		VersionNumber:      aws.Int64(number),
+	}, nil
+}
+
+// matchFilters returns whether a resource matches all the filters, as AWS does: a filter matches
+// when any of its values matches, and values may contain the * and ? wildcards. The attributes are
+// the values of the filters supported for the resource, filters on tags are always supported.
+func matchFilters(filters []*ec2.Filter, attributes map[string]string, tags []*ec2.Tag) (bool, error) {
+	for _, filter := range filters {
+		name := aws.StringValue(filter.Name)
+
+		var candidates []string
+		switch {
+		case name == "tag-key":
+			for _, tag := range tags {
+				candidates = append(candidates, aws.StringValue(tag.Key))
+			}
+		case strings.HasPrefix(name, "tag:"):
+			for _, tag := range tags {
+				if aws.StringValue(tag.Key) == strings.TrimPrefix(name, "tag:") {
+					candidates = append(candidates, aws.StringValue(tag.Value))
+				}
+			}
+		default:
+			attribute, ok := attributes[name]
+			if !ok {
+				return false, awserr.New(invalidParameterValue, fmt.Sprintf("The filter '%s' is invalid", name), nil)
+			}
+			candidates = []string{attribute}
+		}
+
+		if !matchAny(aws.StringValueSlice(filter.Values), candidates) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func matchAny(patterns, candidates []string) bool {
+	for _, pattern := range patterns {
+		for _, candidate := range candidates {
+			// The filter values can't contain path separators in a meaningful way, path.Match only
+			// provides the wildcard matching.
+			if ok, _ := path.Match(pattern, candidate); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
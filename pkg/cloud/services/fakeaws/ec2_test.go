@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakeaws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/gomega"
+)
+
+func TestEC2APILaunchTemplateVersions(t *testing.T) {
+	g := NewWithT(t)
+	f := NewEC2API()
+
+	out, err := f.CreateLaunchTemplateWithContext(context.TODO(), &ec2.CreateLaunchTemplateInput{
+		LaunchTemplateName: aws.String("template"),
+		LaunchTemplateData: &ec2.RequestLaunchTemplateData{ImageId: aws.String("ami-1")},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	id := out.LaunchTemplate.LaunchTemplateId
+
+	_, err = f.CreateLaunchTemplateWithContext(context.TODO(), &ec2.CreateLaunchTemplateInput{
+		LaunchTemplateName: aws.String("template"),
+		LaunchTemplateData: &ec2.RequestLaunchTemplateData{},
+	})
+	g.Expect(err).To(HaveOccurred())
+
+	for _, ami := range []string{"ami-2", "ami-3"} {
+		_, err := f.CreateLaunchTemplateVersionWithContext(context.TODO(), &ec2.CreateLaunchTemplateVersionInput{
+			LaunchTemplateId:   id,
+			LaunchTemplateData: &ec2.RequestLaunchTemplateData{ImageId: aws.String(ami)},
+		})
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	versions, err := f.DescribeLaunchTemplateVersionsWithContext(context.TODO(), &ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateName: aws.String("template"),
+		Versions:           aws.StringSlice([]string{"$Latest", "$Default"}),
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(versions.LaunchTemplateVersions).To(HaveLen(2))
+	g.Expect(versions.LaunchTemplateVersions[0].LaunchTemplateData.ImageId).To(Equal(aws.String("ami-1")))
+	g.Expect(versions.LaunchTemplateVersions[1].LaunchTemplateData.ImageId).To(Equal(aws.String("ami-3")))
+
+	deleted, err := f.DeleteLaunchTemplateVersionsWithContext(context.TODO(), &ec2.DeleteLaunchTemplateVersionsInput{
+		LaunchTemplateId: id,
+		Versions:         aws.StringSlice([]string{"1", "2", "7"}),
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleted.SuccessfullyDeletedLaunchTemplateVersions).To(HaveLen(1))
+	g.Expect(deleted.UnsuccessfullyDeletedLaunchTemplateVersions).To(HaveLen(2))
+
+	versions, err = f.DescribeLaunchTemplateVersionsWithContext(context.TODO(), &ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateId: id,
+		MinVersion:       aws.String("0"),
+		MaxVersion:       aws.String("$Latest"),
+		MaxResults:       aws.Int64(1),
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(versions.LaunchTemplateVersions).To(HaveLen(1))
+	g.Expect(versions.LaunchTemplateVersions[0].VersionNumber).To(Equal(aws.Int64(1)))
+	g.Expect(versions.NextToken).NotTo(BeNil())
+
+	_, err = f.DeleteLaunchTemplateWithContext(context.TODO(), &ec2.DeleteLaunchTemplateInput{LaunchTemplateId: id})
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = f.DescribeLaunchTemplateVersionsWithContext(context.TODO(), &ec2.DescribeLaunchTemplateVersionsInput{LaunchTemplateId: id})
+	g.Expect(err).To(MatchError(ContainSubstring("InvalidLaunchTemplateId.NotFound")))
+}
+
+func TestEC2APIFilters(t *testing.T) {
+	g := NewWithT(t)
+	f := NewEC2API()
+	f.AddSubnets(
+		&ec2.Subnet{SubnetId: aws.String("subnet-1"), VpcId: aws.String("vpc-1"), Tags: []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("private-a")}}},
+		&ec2.Subnet{SubnetId: aws.String("subnet-2"), VpcId: aws.String("vpc-1"), Tags: []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("public-a")}}},
+		&ec2.Subnet{SubnetId: aws.String("subnet-3"), VpcId: aws.String("vpc-2")},
+	)
+
+	out, err := f.DescribeSubnetsWithContext(context.TODO(), &ec2.DescribeSubnetsInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("vpc-id"), Values: aws.StringSlice([]string{"vpc-1"})},
+			{Name: aws.String("tag:Name"), Values: aws.StringSlice([]string{"private-*"})},
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(out.Subnets).To(HaveLen(1))
+	g.Expect(out.Subnets[0].SubnetId).To(Equal(aws.String("subnet-1")))
+
+	_, err = f.DescribeSubnetsWithContext(context.TODO(), &ec2.DescribeSubnetsInput{
+		Filters: []*ec2.Filter{{Name: aws.String("unknown"), Values: aws.StringSlice([]string{"value"})}},
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("InvalidParameterValue")))
+}
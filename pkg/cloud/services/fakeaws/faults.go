@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakeaws
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+)
+
+// ErrorFunc returns the error to fail an operation with, or nil to let it proceed.
+// The input is the SDK input of the operation, e.g. *autoscaling.CreateAutoScalingGroupInput.
+type ErrorFunc func(operation string, input interface{}) error
+
+// faults records the operations called on a fake and returns the errors injected for them.
+type faults struct {
+	mu        sync.Mutex
+	calls     []string
+	errs      map[string][]error
+	errorFunc ErrorFunc
+}
+
+// InjectError fails the next call of the operation with err. The operation is the name of the
+// SDK operation without the WithContext or Pages suffix, e.g. "CreateAutoScalingGroup".
+// Errors injected for the same operation are returned by successive calls.
+func (f *faults) InjectError(operation string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.errs == nil {
+		f.errs = map[string][]error{}
+	}
+	f.errs[operation] = append(f.errs[operation], err)
+}
+
+// InjectErrorFunc sets a function called before every operation, failing the operation when it
+// returns an error. Errors injected with InjectError take precedence.
+func (f *faults) InjectErrorFunc(fn ErrorFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.errorFunc = fn
+}
+
+// Calls returns the operations called so far, in order.
+func (f *faults) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.calls...)
+}
+
+// call records the operation and returns the error injected for it, if any.
+func (f *faults) call(operation string, input interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, operation)
+	if errs := f.errs[operation]; len(errs) > 0 {
+		f.errs[operation] = errs[1:]
+		return errs[0]
+	}
+	if f.errorFunc != nil {
+		return f.errorFunc(operation, input)
+	}
+	return nil
+}
+
+// validationError returns the error AWS returns for invalid parameters.
+func validationError(msg string) error {
+	return awserr.New("ValidationError", msg, nil)
+}
+
+// copyOf returns a deep copy of an SDK structure, so that the state of the fakes can't be modified by the callers.
+func copyOf[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	return awsutil.CopyOf(v).(*T)
+}
+
+// copyAll returns a deep copy of a slice of SDK structures.
+func copyAll[T any](v []*T) []*T {
+	if v == nil {
+		return nil
+	}
+	res := make([]*T, len(v))
+	for i := range v {
+		res[i] = copyOf(v[i])
+	}
+	return res
+}
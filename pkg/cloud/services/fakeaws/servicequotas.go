@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakeaws
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
+)
+
+// ServiceQuotasAPI is an in-memory implementation of servicequotasiface.ServiceQuotasAPI, covering
+// the lookup of the applied quotas.
+type ServiceQuotasAPI struct {
+	// ServiceQuotasAPI is nil, calling an operation that is not implemented by the fake panics.
+	servicequotasiface.ServiceQuotasAPI
+	faults
+
+	mu     sync.Mutex
+	quotas map[string]float64
+}
+
+var _ servicequotasiface.ServiceQuotasAPI = &ServiceQuotasAPI{}
+
+// NewServiceQuotasAPI returns a ServiceQuotasAPI without any quota.
+func NewServiceQuotasAPI() *ServiceQuotasAPI {
+	return &ServiceQuotasAPI{
+		quotas: map[string]float64{},
+	}
+}
+
+// SetServiceQuota sets the value of a quota, returned by GetServiceQuota.
+func (f *ServiceQuotasAPI) SetServiceQuota(serviceCode, quotaCode string, value float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.quotas[serviceCode+"/"+quotaCode] = value
+}
+
+// GetServiceQuotaWithContext returns the quota set with SetServiceQuota, or the error AWS returns
+// when it doesn't exist.
+func (f *ServiceQuotasAPI) GetServiceQuotaWithContext(_ aws.Context, input *servicequotas.GetServiceQuotaInput, _ ...request.Option) (*servicequotas.GetServiceQuotaOutput, error) {
+	if err := f.call("GetServiceQuota", input); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	serviceCode, quotaCode := aws.StringValue(input.ServiceCode), aws.StringValue(input.QuotaCode)
+	value, ok := f.quotas[serviceCode+"/"+quotaCode]
+	if !ok {
+		return nil, &servicequotas.NoSuchResourceException{
+			Message_: aws.String(fmt.Sprintf("The request failed because the specified quota %s of service %s doesn't exist.", quotaCode, serviceCode)),
+		}
+	}
+	return &servicequotas.GetServiceQuotaOutput{
+		Quota: &servicequotas.ServiceQuota{
+			ServiceCode: aws.String(serviceCode),
+			QuotaCode:   aws.String(quotaCode),
+			Value:       aws.Float64(value),
+		},
+	}, nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakeaws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	. "github.com/onsi/gomega"
+)
+
+func TestServiceQuotasAPIGetServiceQuota(t *testing.T) {
+	g := NewWithT(t)
+	f := NewServiceQuotasAPI()
+	f.SetServiceQuota("ec2", "L-0263D0A3", 5)
+
+	out, err := f.GetServiceQuotaWithContext(context.TODO(), &servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String("ec2"),
+		QuotaCode:   aws.String("L-0263D0A3"),
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(aws.Float64Value(out.Quota.Value)).To(Equal(5.0))
+
+	_, err = f.GetServiceQuotaWithContext(context.TODO(), &servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String("vpc"),
+		QuotaCode:   aws.String("L-0263D0A3"),
+	})
+	var notFound *servicequotas.NoSuchResourceException
+	g.Expect(err).To(BeAssignableToTypeOf(notFound))
+
+	g.Expect(f.Calls()).To(Equal([]string{"GetServiceQuota", "GetServiceQuota"}))
+}
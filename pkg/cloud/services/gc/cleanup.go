@@ -22,12 +22,9 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/arn"
-	rgapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
-
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/annotations"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/discovery"
 )
 
 const (
@@ -98,39 +95,20 @@ func (s *Service) defaultGetResources(ctx context.Context) ([]*AWSResource, erro
 
 	serviceTag := infrav1.ClusterAWSCloudProviderTagKey(s.scope.KubernetesClusterName())
 
-	awsInput := rgapi.GetResourcesInput{
-		ResourceTypeFilters: nil,
-		TagFilters: []*rgapi.TagFilter{
-			{
-				Key:    aws.String(serviceTag),
-				Values: []*string{aws.String(string(infrav1.ResourceLifecycleOwned))},
-			},
-		},
-	}
-
-	awsOutput, err := s.resourceTaggingClient.GetResourcesWithContext(ctx, &awsInput)
+	discoverer := discovery.NewDiscoverer(s.scope.Region(), s.resourceTaggingClient, s.elbClient)
+	owned, err := discoverer.Discover(ctx, serviceTag, []string{string(infrav1.ResourceLifecycleOwned)})
 	if err != nil {
 		return nil, fmt.Errorf("getting tagged resources: %w", err)
 	}
 
 	resources := []*AWSResource{}
 
-	for i := range awsOutput.ResourceTagMappingList {
-		mapping := awsOutput.ResourceTagMappingList[i]
-		parsedArn, err := arn.Parse(*mapping.ResourceARN)
+	for _, res := range owned {
+		resource, err := composeAWSResource(res.ARN, res.Tags)
 		if err != nil {
-			return nil, fmt.Errorf("parsing resource arn %s: %w", *mapping.ResourceARN, err)
+			return nil, err
 		}
-
-		tags := map[string]string{}
-		for _, rgTag := range mapping.Tags {
-			tags[*rgTag.Key] = *rgTag.Value
-		}
-
-		resources = append(resources, &AWSResource{
-			ARN:  &parsedArn,
-			Tags: tags,
-		})
+		resources = append(resources, resource)
 	}
 
 	return resources, nil
@@ -231,6 +231,68 @@ func TestReconcileDelete(t *testing.T) {
 			ec2Mocks:   func(m *mocks.MockEC2APIMockRecorder) {},
 			expectErr:  false,
 		},
+		{
+			name:         "eks with ELB Service load balancer not returned by the resource tagging API",
+			clusterScope: createManageScope(t, "", ""),
+			rgAPIMocks: func(m *mocks.MockResourceGroupsTaggingAPIAPIMockRecorder) {
+				m.GetResourcesWithContext(gomock.Any(), &rgapi.GetResourcesInput{
+					TagFilters: []*rgapi.TagFilter{
+						{
+							Key:    aws.String("kubernetes.io/cluster/eks-test-cluster"),
+							Values: []*string{aws.String("owned")},
+						},
+					},
+				}).Return(&rgapi.GetResourcesOutput{
+					ResourceTagMappingList: []*rgapi.ResourceTagMapping{},
+				}, nil)
+			},
+			elbMocks: func(m *mocks.MockELBAPIMockRecorder) {
+				m.DescribeLoadBalancersPagesWithContext(gomock.Any(), &elb.DescribeLoadBalancersInput{}, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ *elb.DescribeLoadBalancersInput, fn func(*elb.DescribeLoadBalancersOutput, bool) bool, _ ...request.Option) error {
+						fn(&elb.DescribeLoadBalancersOutput{
+							LoadBalancerDescriptions: []*elb.LoadBalancerDescription{
+								{LoadBalancerName: aws.String("aec24434cd2ce4630bd14a955413ee37")},
+								{LoadBalancerName: aws.String("other-cluster")},
+							},
+						}, true)
+						return nil
+					})
+				m.DescribeTagsWithContext(gomock.Any(), &elb.DescribeTagsInput{
+					LoadBalancerNames: aws.StringSlice([]string{"aec24434cd2ce4630bd14a955413ee37", "other-cluster"}),
+				}).Return(&elb.DescribeTagsOutput{
+					TagDescriptions: []*elb.TagDescription{
+						{
+							LoadBalancerName: aws.String("aec24434cd2ce4630bd14a955413ee37"),
+							Tags: []*elb.Tag{
+								{
+									Key:   aws.String("kubernetes.io/cluster/eks-test-cluster"),
+									Value: aws.String("owned"),
+								},
+								{
+									Key:   aws.String(serviceNameTag),
+									Value: aws.String("default/svc1"),
+								},
+							},
+						},
+						{
+							LoadBalancerName: aws.String("other-cluster"),
+							Tags: []*elb.Tag{
+								{
+									Key:   aws.String("kubernetes.io/cluster/other-cluster"),
+									Value: aws.String("owned"),
+								},
+							},
+						},
+					},
+				}, nil)
+				m.DeleteLoadBalancerWithContext(gomock.Any(), &elb.DeleteLoadBalancerInput{
+					LoadBalancerName: aws.String("aec24434cd2ce4630bd14a955413ee37"),
+				}).Return(&elb.DeleteLoadBalancerOutput{}, nil)
+			},
+			elbv2Mocks: func(m *mocks.MockELBV2APIMockRecorder) {},
+			ec2Mocks:   func(m *mocks.MockEC2APIMockRecorder) {},
+			expectErr:  false,
+		},
 		{
 			name:         "ec2 cluster with ELB Service load balancer",
 			clusterScope: createUnManageScope(t, "", ""),
@@ -810,6 +872,8 @@ func TestReconcileDelete(t *testing.T) {
 
 			tc.rgAPIMocks(rgapiMock.EXPECT())
 			tc.elbMocks(elbapiMock.EXPECT())
+			// The classic load balancers are also listed with the ELB API, as the resource tagging API doesn't cover all of them.
+			elbapiMock.EXPECT().DescribeLoadBalancersPagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 			tc.elbv2Mocks(elbv2Mock.EXPECT())
 			tc.ec2Mocks(ec2Mock.EXPECT())
 
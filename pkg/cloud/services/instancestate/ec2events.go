@@ -16,8 +16,24 @@ limitations under the License.
 
 package instancestate
 
+import (
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+)
+
 // ReconcileEC2Events will reconcile a Service's EC2 events.
+// The rule and queue of a cluster for which they are disabled are deleted instead.
 func (s Service) ReconcileEC2Events() error {
+	if !s.isEnabled() {
+		if ebScope, ok := s.scope.(scope.EventBridgeScope); ok && ebScope.EventBridgeStatus() != nil {
+			return s.DeleteEC2Events()
+		}
+		return nil
+	}
+
+	if err := s.reconcileNames(); err != nil {
+		return err
+	}
+
 	if err := s.reconcileSQSQueue(); err != nil {
 		return err
 	}
@@ -25,11 +41,22 @@ func (s Service) ReconcileEC2Events() error {
 	return s.reconcileRules()
 }
 
-// DeleteEC2Events will delete a Service's EC2 events.
+// DeleteEC2Events will delete a Service's EC2 events, and the leftover rules and queues owned by the cluster.
 func (s Service) DeleteEC2Events() error {
 	if err := s.deleteRules(); err != nil {
 		return err
 	}
 
-	return s.deleteSQSQueue()
+	if err := s.deleteSQSQueue(); err != nil {
+		return err
+	}
+
+	if err := s.deleteLeftovers(); err != nil {
+		return err
+	}
+
+	if ebScope, ok := s.scope.(scope.EventBridgeScope); ok {
+		ebScope.SetEventBridgeStatus(nil)
+	}
+	return nil
 }
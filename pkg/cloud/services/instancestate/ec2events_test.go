@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/instancestate/mock_eventbridgeiface"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/instancestate/mock_sqsiface"
+)
+
+func TestReconcileEC2EventsDisabled(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name              string
+		status            *infrav1.EventBridgeStatus
+		sqsExpect         func(m *mock_sqsiface.MockSQSAPIMockRecorder)
+		eventBridgeExpect func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder)
+	}{
+		{
+			name: "does nothing when the rule and queue were not created",
+		},
+		{
+			name:   "deletes the rule and queue of the cluster",
+			status: &infrav1.EventBridgeStatus{QueueName: "my-queue", RuleName: "my-rule"},
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.RemoveTargets(&eventbridge.RemoveTargetsInput{
+					Rule: aws.String("my-rule"),
+					Ids:  aws.StringSlice([]string{"my-queue"}),
+				}).Return(&eventbridge.RemoveTargetsOutput{}, nil)
+				m.DeleteRule(&eventbridge.DeleteRuleInput{Name: aws.String("my-rule")}).
+					Return(&eventbridge.DeleteRuleOutput{}, nil)
+				m.ListRules(&eventbridge.ListRulesInput{NamePrefix: aws.String("test-cluster")}).
+					Return(&eventbridge.ListRulesOutput{}, nil)
+			},
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String("my-queue")}).
+					Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("my-queue-url")}, nil)
+				m.DeleteQueue(&sqs.DeleteQueueInput{QueueUrl: aws.String("my-queue-url")}).
+					Return(&sqs.DeleteQueueOutput{}, nil)
+				m.ListQueuesPages(&sqs.ListQueuesInput{QueueNamePrefix: aws.String("test-cluster")}, gomock.Any()).
+					Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			sqsMock := mock_sqsiface.NewMockSQSAPI(mockCtrl)
+			eventBridgeMock := mock_eventbridgeiface.NewMockEventBridgeAPI(mockCtrl)
+			clusterScope, err := setupClusterWithEventBridge("test-cluster", &infrav1.EventBridgeConfig{Enabled: false}, tc.status)
+			g.Expect(err).To(Not(HaveOccurred()))
+
+			if tc.sqsExpect != nil {
+				tc.sqsExpect(sqsMock.EXPECT())
+			}
+			if tc.eventBridgeExpect != nil {
+				tc.eventBridgeExpect(eventBridgeMock.EXPECT())
+			}
+			s := NewService(clusterScope)
+			s.SQSClient = sqsMock
+			s.EventBridgeClient = eventBridgeMock
+
+			g.Expect(s.ReconcileEC2Events()).To(Succeed())
+			g.Expect(clusterScope.EventBridgeStatus()).To(BeNil())
+		})
+	}
+}
@@ -26,12 +26,19 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+const testClusterUID = "2f7c1b4e-3f5a-4d8e-9c1b-7a6e5d4c3b2a"
+
 func setupCluster(clusterName string) (*scope.ClusterScope, error) {
+	return setupClusterWithEventBridge(clusterName, nil, nil)
+}
+
+func setupClusterWithEventBridge(clusterName string, config *infrav1.EventBridgeConfig, status *infrav1.EventBridgeStatus) (*scope.ClusterScope, error) {
 	scheme := runtime.NewScheme()
 	_ = infrav1.AddToScheme(scheme)
 	awsCluster := &infrav1.AWSCluster{
-		ObjectMeta: metav1.ObjectMeta{Name: "test"},
-		Spec:       infrav1.AWSClusterSpec{},
+		ObjectMeta: metav1.ObjectMeta{Name: "test", UID: testClusterUID},
+		Spec:       infrav1.AWSClusterSpec{EventBridge: config},
+		Status:     infrav1.AWSClusterStatus{EventBridge: status},
 	}
 	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(awsCluster).Build()
 	return scope.NewClusterScope(scope.ClusterScopeParams{
@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+)
+
+// deleteLeftovers deletes the rules and queues tagged as owned by the cluster which are not the
+// ones recorded in its status, e.g. the ones of a previous cluster with the same name whose
+// deletion was interrupted, or the ones renamed while the cluster was recreated.
+func (s Service) deleteLeftovers() error {
+	rules, err := s.listOwnedRules()
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if err := s.deleteLeftoverRule(rule); err != nil {
+			return err
+		}
+	}
+
+	queueURLs := []*string{}
+	err = s.SQSClient.ListQueuesPages(&sqs.ListQueuesInput{QueueNamePrefix: aws.String(s.leftoverNamePrefix())}, func(out *sqs.ListQueuesOutput, _ bool) bool {
+		queueURLs = append(queueURLs, out.QueueUrls...)
+		return true
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to list queues")
+	}
+	for _, queueURL := range queueURLs {
+		if err := s.deleteLeftoverQueue(queueURL); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listOwnedRules returns the rules of the default event bus tagged as owned by the cluster.
+func (s Service) listOwnedRules() ([]*eventbridge.Rule, error) {
+	owned := []*eventbridge.Rule{}
+	input := &eventbridge.ListRulesInput{NamePrefix: aws.String(s.leftoverNamePrefix())}
+	for {
+		out, err := s.EventBridgeClient.ListRules(input)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to list rules")
+		}
+		for _, rule := range out.Rules {
+			tagsResp, err := s.EventBridgeClient.ListTagsForResource(&eventbridge.ListTagsForResourceInput{ResourceARN: rule.Arn})
+			if err != nil {
+				if resourceNotFoundError(err) {
+					continue
+				}
+				return nil, errors.Wrapf(err, "unable to list tags of rule %s", aws.StringValue(rule.Name))
+			}
+			tags := infrav1.Tags{}
+			for _, tag := range tagsResp.Tags {
+				tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+			if tags.HasOwned(s.scope.Name()) && !isSpotInterruptionResource(tags) {
+				owned = append(owned, rule)
+			}
+		}
+		if aws.StringValue(out.NextToken) == "" {
+			return owned, nil
+		}
+		input.NextToken = out.NextToken
+	}
+}
+
+// deleteLeftoverRule deletes a rule owned by the cluster, and the queues it targets which are
+// also owned by the cluster.
+func (s Service) deleteLeftoverRule(rule *eventbridge.Rule) error {
+	targetsResp, err := s.EventBridgeClient.ListTargetsByRule(&eventbridge.ListTargetsByRuleInput{Rule: rule.Name})
+	if err != nil {
+		if resourceNotFoundError(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "unable to list targets for rule %s", aws.StringValue(rule.Name))
+	}
+
+	ids := []*string{}
+	for _, target := range targetsResp.Targets {
+		ids = append(ids, target.Id)
+
+		targetARN, err := arn.Parse(aws.StringValue(target.Arn))
+		if err != nil || targetARN.Service != sqs.ServiceName {
+			continue
+		}
+		queueURLResp, err := s.SQSClient.GetQueueUrl(&sqs.GetQueueUrlInput{
+			QueueName:              aws.String(targetARN.Resource),
+			QueueOwnerAWSAccountId: aws.String(targetARN.AccountID),
+		})
+		if err != nil {
+			if queueNotFoundError(err) {
+				continue
+			}
+			return errors.Wrapf(err, "unable to get URL of queue %s", targetARN.Resource)
+		}
+		if err := s.deleteLeftoverQueue(queueURLResp.QueueUrl); err != nil {
+			return err
+		}
+	}
+
+	if len(ids) > 0 {
+		_, err = s.EventBridgeClient.RemoveTargets(&eventbridge.RemoveTargetsInput{Rule: rule.Name, Ids: ids})
+		if err != nil && !resourceNotFoundError(err) {
+			return errors.Wrapf(err, "unable to remove targets of rule %s", aws.StringValue(rule.Name))
+		}
+	}
+	_, err = s.EventBridgeClient.DeleteRule(&eventbridge.DeleteRuleInput{Name: rule.Name})
+	if err != nil && !resourceNotFoundError(err) {
+		return errors.Wrapf(err, "unable to delete rule %s", aws.StringValue(rule.Name))
+	}
+
+	s.scope.Info("Deleted leftover EventBridge rule", "rule", aws.StringValue(rule.Name))
+	return nil
+}
+
+// deleteLeftoverQueue deletes a queue if it is owned by the cluster.
+func (s Service) deleteLeftoverQueue(queueURL *string) error {
+	tagsResp, err := s.SQSClient.ListQueueTags(&sqs.ListQueueTagsInput{QueueUrl: queueURL})
+	if err != nil {
+		if queueNotFoundError(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "unable to list tags of queue %s", aws.StringValue(queueURL))
+	}
+	tags := infrav1.Tags(aws.StringValueMap(tagsResp.Tags))
+	if !tags.HasOwned(s.scope.Name()) || isSpotInterruptionResource(tags) {
+		return nil
+	}
+
+	_, err = s.SQSClient.DeleteQueue(&sqs.DeleteQueueInput{QueueUrl: queueURL})
+	if err != nil && !queueNotFoundError(err) {
+		return errors.Wrapf(err, "unable to delete queue %s", aws.StringValue(queueURL))
+	}
+
+	s.scope.Info("Deleted leftover SQS queue", "queue", aws.StringValue(queueURL))
+	return nil
+}
+
+// leftoverNamePrefix returns the prefix shared by the names the rules and queues of the cluster had
+// over time, the part of the cluster name kept in their names up to its first dot.
+func (s Service) leftoverNamePrefix() string {
+	prefix, _, _ := strings.Cut(s.scope.Name(), ".")
+	if len(prefix) > maxClusterNameLength {
+		prefix = prefix[:maxClusterNameLength]
+	}
+	return prefix
+}
+
+// isSpotInterruptionResource returns whether a rule or queue owned by the cluster receives the spot interruption
+// notices of its machine pools, which share the prefix of their names but are deleted with the cluster only.
+func isSpotInterruptionResource(tags infrav1.Tags) bool {
+	return tags[infrav1.NameAWSClusterAPIRole] == infrav1.SpotInterruptionRoleTagValue
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/instancestate/mock_eventbridgeiface"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/instancestate/mock_sqsiface"
+)
+
+func TestDeleteLeftovers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ownedTags := map[string]string{"sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster": "owned"}
+	otherTags := map[string]string{"sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster-2": "owned"}
+	spotTags := map[string]string{
+		"sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster": "owned",
+		"sigs.k8s.io/cluster-api-provider-aws/role":                 "spot-interruption",
+	}
+
+	testCases := []struct {
+		name              string
+		sqsExpect         func(m *mock_sqsiface.MockSQSAPIMockRecorder)
+		eventBridgeExpect func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder)
+		expectErr         bool
+	}{
+		{
+			name: "deletes the rules and queues owned by the cluster",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.ListRules(&eventbridge.ListRulesInput{NamePrefix: aws.String("test-cluster")}).
+					Return(&eventbridge.ListRulesOutput{
+						Rules:     []*eventbridge.Rule{{Name: aws.String("test-cluster-old-ec2-rule"), Arn: aws.String("old-rule-arn")}},
+						NextToken: aws.String("next"),
+					}, nil)
+				m.ListRules(&eventbridge.ListRulesInput{NamePrefix: aws.String("test-cluster"), NextToken: aws.String("next")}).
+					Return(&eventbridge.ListRulesOutput{
+						Rules: []*eventbridge.Rule{{Name: aws.String("test-cluster-2-ec2-rule"), Arn: aws.String("other-rule-arn")}},
+					}, nil)
+				m.ListTagsForResource(&eventbridge.ListTagsForResourceInput{ResourceARN: aws.String("old-rule-arn")}).
+					Return(&eventbridge.ListTagsForResourceOutput{Tags: eventBridgeTags(ownedTags)}, nil)
+				m.ListTagsForResource(&eventbridge.ListTagsForResourceInput{ResourceARN: aws.String("other-rule-arn")}).
+					Return(&eventbridge.ListTagsForResourceOutput{Tags: eventBridgeTags(otherTags)}, nil)
+				m.ListTargetsByRule(&eventbridge.ListTargetsByRuleInput{Rule: aws.String("test-cluster-old-ec2-rule")}).
+					Return(&eventbridge.ListTargetsByRuleOutput{Targets: []*eventbridge.Target{{
+						Id:  aws.String("my-queue"),
+						Arn: aws.String("arn:aws:sqs:us-east-1:123456789012:my-queue"),
+					}}}, nil)
+				m.RemoveTargets(&eventbridge.RemoveTargetsInput{
+					Rule: aws.String("test-cluster-old-ec2-rule"),
+					Ids:  aws.StringSlice([]string{"my-queue"}),
+				}).Return(&eventbridge.RemoveTargetsOutput{}, nil)
+				m.DeleteRule(&eventbridge.DeleteRuleInput{Name: aws.String("test-cluster-old-ec2-rule")}).
+					Return(&eventbridge.DeleteRuleOutput{}, nil)
+			},
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(&sqs.GetQueueUrlInput{
+					QueueName:              aws.String("my-queue"),
+					QueueOwnerAWSAccountId: aws.String("123456789012"),
+				}).Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("my-queue-url")}, nil)
+				m.ListQueueTags(&sqs.ListQueueTagsInput{QueueUrl: aws.String("my-queue-url")}).
+					Return(&sqs.ListQueueTagsOutput{Tags: aws.StringMap(ownedTags)}, nil)
+				m.DeleteQueue(&sqs.DeleteQueueInput{QueueUrl: aws.String("my-queue-url")}).
+					Return(&sqs.DeleteQueueOutput{}, nil)
+				m.ListQueuesPages(&sqs.ListQueuesInput{QueueNamePrefix: aws.String("test-cluster")}, gomock.Any()).
+					DoAndReturn(func(_ *sqs.ListQueuesInput, fn func(*sqs.ListQueuesOutput, bool) bool) error {
+						fn(&sqs.ListQueuesOutput{QueueUrls: aws.StringSlice([]string{"old-queue-url", "other-queue-url", "deleted-queue-url"})}, true)
+						return nil
+					})
+				m.ListQueueTags(&sqs.ListQueueTagsInput{QueueUrl: aws.String("old-queue-url")}).
+					Return(&sqs.ListQueueTagsOutput{Tags: aws.StringMap(ownedTags)}, nil)
+				m.DeleteQueue(&sqs.DeleteQueueInput{QueueUrl: aws.String("old-queue-url")}).
+					Return(&sqs.DeleteQueueOutput{}, nil)
+				m.ListQueueTags(&sqs.ListQueueTagsInput{QueueUrl: aws.String("other-queue-url")}).
+					Return(&sqs.ListQueueTagsOutput{Tags: aws.StringMap(otherTags)}, nil)
+				m.ListQueueTags(&sqs.ListQueueTagsInput{QueueUrl: aws.String("deleted-queue-url")}).
+					Return(nil, awserr.New(sqs.ErrCodeQueueDoesNotExist, "", nil))
+			},
+		},
+		{
+			name: "keeps the spot interruption rule and queue of the cluster",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.ListRules(&eventbridge.ListRulesInput{NamePrefix: aws.String("test-cluster")}).
+					Return(&eventbridge.ListRulesOutput{
+						Rules: []*eventbridge.Rule{{Name: aws.String("test-cluster-uid-spot-rule"), Arn: aws.String("spot-rule-arn")}},
+					}, nil)
+				m.ListTagsForResource(&eventbridge.ListTagsForResourceInput{ResourceARN: aws.String("spot-rule-arn")}).
+					Return(&eventbridge.ListTagsForResourceOutput{Tags: eventBridgeTags(spotTags)}, nil)
+			},
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.ListQueuesPages(&sqs.ListQueuesInput{QueueNamePrefix: aws.String("test-cluster")}, gomock.Any()).
+					DoAndReturn(func(_ *sqs.ListQueuesInput, fn func(*sqs.ListQueuesOutput, bool) bool) error {
+						fn(&sqs.ListQueuesOutput{QueueUrls: aws.StringSlice([]string{"spot-queue-url"})}, true)
+						return nil
+					})
+				m.ListQueueTags(&sqs.ListQueueTagsInput{QueueUrl: aws.String("spot-queue-url")}).
+					Return(&sqs.ListQueueTagsOutput{Tags: aws.StringMap(spotTags)}, nil)
+			},
+		},
+		{
+			name: "returns error if the rules can't be listed",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.ListRules(&eventbridge.ListRulesInput{NamePrefix: aws.String("test-cluster")}).
+					Return(nil, errors.New("some error"))
+			},
+			expectErr: true,
+		},
+		{
+			name: "returns error if a queue owned by the cluster can't be deleted",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.ListRules(&eventbridge.ListRulesInput{NamePrefix: aws.String("test-cluster")}).
+					Return(&eventbridge.ListRulesOutput{}, nil)
+			},
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.ListQueuesPages(&sqs.ListQueuesInput{QueueNamePrefix: aws.String("test-cluster")}, gomock.Any()).
+					DoAndReturn(func(_ *sqs.ListQueuesInput, fn func(*sqs.ListQueuesOutput, bool) bool) error {
+						fn(&sqs.ListQueuesOutput{QueueUrls: aws.StringSlice([]string{"old-queue-url"})}, true)
+						return nil
+					})
+				m.ListQueueTags(&sqs.ListQueueTagsInput{QueueUrl: aws.String("old-queue-url")}).
+					Return(&sqs.ListQueueTagsOutput{Tags: aws.StringMap(ownedTags)}, nil)
+				m.DeleteQueue(&sqs.DeleteQueueInput{QueueUrl: aws.String("old-queue-url")}).
+					Return(nil, errors.New("some error"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			sqsMock := mock_sqsiface.NewMockSQSAPI(mockCtrl)
+			eventBridgeMock := mock_eventbridgeiface.NewMockEventBridgeAPI(mockCtrl)
+			clusterScope, err := setupCluster("test-cluster")
+			g.Expect(err).To(Not(HaveOccurred()))
+
+			if tc.sqsExpect != nil {
+				tc.sqsExpect(sqsMock.EXPECT())
+			}
+			if tc.eventBridgeExpect != nil {
+				tc.eventBridgeExpect(eventBridgeMock.EXPECT())
+			}
+			s := NewService(clusterScope)
+			s.SQSClient = sqsMock
+			s.EventBridgeClient = eventBridgeMock
+
+			err = s.deleteLeftovers()
+
+			if tc.expectErr {
+				g.Expect(err).NotTo(BeNil())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+)
+
+// maxClusterNameLength is the length of the cluster name kept in the names of the queue and rule,
+// so that the names including the UID of the cluster fit in the 64 characters allowed for the name
+// of a rule and the ID of its target.
+const maxClusterNameLength = 18
+
+// Enabled returns whether the rule and queue of a cluster with the given configuration are enabled.
+// The clusters which configure them enable or disable them regardless of the EventBridgeInstanceState
+// feature flag, the others follow the feature flag.
+func Enabled(config *infrav1.EventBridgeConfig) bool {
+	if config == nil {
+		return feature.Gates.Enabled(feature.EventBridgeInstanceState)
+	}
+	return config.Enabled
+}
+
+// IsEnabled returns whether the rule and queue of the cluster of the scope are enabled.
+func IsEnabled(clusterScope scope.EC2Scope) bool {
+	ebScope, ok := clusterScope.(scope.EventBridgeScope)
+	if !ok {
+		return Enabled(nil)
+	}
+	return Enabled(ebScope.EventBridge())
+}
+
+func (s Service) isEnabled() bool {
+	return IsEnabled(s.scope)
+}
+
+// reconcileNames records the names of the rule and queue of the cluster in its status, if not
+// recorded yet. Clusters whose queue was created before the names were recorded keep the names
+// derived from their name alone.
+func (s Service) reconcileNames() error {
+	ebScope, ok := s.scope.(scope.EventBridgeScope)
+	if !ok || ebScope.EventBridgeStatus() != nil {
+		return nil
+	}
+
+	if override := ebScope.EventBridge().GetQueueNameOverride(); override != "" {
+		ebScope.SetEventBridgeStatus(&infrav1.EventBridgeStatus{
+			QueueName: override,
+			RuleName:  generateRuleName(s.scope.Name(), s.scope.InfraCluster().GetUID()),
+		})
+		return nil
+	}
+
+	legacyQueueName := GenerateQueueName(s.scope.Name())
+	resp, err := s.SQSClient.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(legacyQueueName)})
+	switch {
+	case err == nil:
+		if err := s.tagLegacyResources(resp.QueueUrl); err != nil {
+			return err
+		}
+		ebScope.SetEventBridgeStatus(&infrav1.EventBridgeStatus{
+			QueueName: legacyQueueName,
+			RuleName:  legacyRuleName(s.scope.Name()),
+		})
+	case queueNotFoundError(err):
+		ebScope.SetEventBridgeStatus(&infrav1.EventBridgeStatus{
+			QueueName: generateQueueName(s.scope.Name(), s.scope.InfraCluster().GetUID()),
+			RuleName:  generateRuleName(s.scope.Name(), s.scope.InfraCluster().GetUID()),
+		})
+	default:
+		return errors.Wrapf(err, "unable to get URL of queue %s", legacyQueueName)
+	}
+
+	return nil
+}
+
+// tagLegacyResources tags the rule and queue created before they were tagged, so that they are
+// found when deleting the leftovers of the cluster.
+func (s Service) tagLegacyResources(queueURL *string) error {
+	tags := s.tags()
+
+	if _, err := s.SQSClient.TagQueue(&sqs.TagQueueInput{QueueUrl: queueURL, Tags: aws.StringMap(tags)}); err != nil {
+		return errors.Wrapf(err, "unable to tag queue %s", GenerateQueueName(s.scope.Name()))
+	}
+
+	rule, err := s.EventBridgeClient.DescribeRule(&eventbridge.DescribeRuleInput{Name: aws.String(legacyRuleName(s.scope.Name()))})
+	if err != nil {
+		if resourceNotFoundError(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "unable to describe rule %s", legacyRuleName(s.scope.Name()))
+	}
+	if _, err := s.EventBridgeClient.TagResource(&eventbridge.TagResourceInput{ResourceARN: rule.Arn, Tags: eventBridgeTags(tags)}); err != nil {
+		return errors.Wrapf(err, "unable to tag rule %s", legacyRuleName(s.scope.Name()))
+	}
+
+	return nil
+}
+
+// getQueueName returns the name of the queue of the cluster.
+func (s Service) getQueueName() string {
+	if ebScope, ok := s.scope.(scope.EventBridgeScope); ok && ebScope.EventBridgeStatus() != nil {
+		return ebScope.EventBridgeStatus().QueueName
+	}
+	return GenerateQueueName(s.scope.Name())
+}
+
+// getEC2RuleName returns the name of the rule of the cluster.
+func (s Service) getEC2RuleName() string {
+	if ebScope, ok := s.scope.(scope.EventBridgeScope); ok && ebScope.EventBridgeStatus() != nil {
+		return ebScope.EventBridgeStatus().RuleName
+	}
+	return legacyRuleName(s.scope.Name())
+}
+
+// tags returns the tags of the rule and queue of the cluster.
+func (s Service) tags() infrav1.Tags {
+	return infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Additional:  s.scope.AdditionalTags(),
+	})
+}
+
+// GenerateQueueName will generate a queue name.
+// It is the name of the queues created before the names included the UID of the cluster.
+func GenerateQueueName(clusterName string) string {
+	adjusted := strings.ReplaceAll(clusterName, ".", "-")
+	return fmt.Sprintf("%s-queue", adjusted)
+}
+
+// legacyRuleName returns the name of the rules created before the names included the UID of the cluster.
+func legacyRuleName(clusterName string) string {
+	return fmt.Sprintf("%s-ec2-rule", clusterName)
+}
+
+// generateQueueName returns the name of the queue of a cluster, unique across the clusters
+// recreated with the same name.
+func generateQueueName(clusterName string, uid types.UID) string {
+	return fmt.Sprintf("%s-queue", namePrefix(clusterName, uid))
+}
+
+// generateRuleName returns the name of the rule of a cluster, unique across the clusters
+// recreated with the same name.
+func generateRuleName(clusterName string, uid types.UID) string {
+	return fmt.Sprintf("%s-ec2-rule", namePrefix(clusterName, uid))
+}
+
+func namePrefix(clusterName string, uid types.UID) string {
+	adjusted := strings.ReplaceAll(clusterName, ".", "-")
+	if len(adjusted) > maxClusterNameLength {
+		adjusted = adjusted[:maxClusterNameLength]
+	}
+	return fmt.Sprintf("%s-%s", adjusted, uid)
+}
+
+// eventBridgeTags converts tags to EventBridge tags, sorted by key.
+func eventBridgeTags(tags infrav1.Tags) []*eventbridge.Tag {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := make([]*eventbridge.Tag, 0, len(tags))
+	for _, k := range keys {
+		res = append(res, &eventbridge.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	return res
+}
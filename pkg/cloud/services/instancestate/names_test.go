@@ -0,0 +1,210 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancestate
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/instancestate/mock_eventbridgeiface"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/instancestate/mock_sqsiface"
+)
+
+func TestReconcileNames(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ownedTags := map[string]string{"sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster": "owned"}
+
+	testCases := []struct {
+		name              string
+		config            *infrav1.EventBridgeConfig
+		status            *infrav1.EventBridgeStatus
+		sqsExpect         func(m *mock_sqsiface.MockSQSAPIMockRecorder)
+		eventBridgeExpect func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder)
+		expectedStatus    *infrav1.EventBridgeStatus
+		expectErr         bool
+	}{
+		{
+			name:   "keeps the names recorded in the status",
+			status: &infrav1.EventBridgeStatus{QueueName: "some-queue", RuleName: "some-rule"},
+			expectedStatus: &infrav1.EventBridgeStatus{
+				QueueName: "some-queue",
+				RuleName:  "some-rule",
+			},
+		},
+		{
+			name: "generates names including the UID of the cluster for new queues",
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String("test-cluster-queue")}).
+					Return(nil, awserr.New(sqs.ErrCodeQueueDoesNotExist, "", nil))
+			},
+			expectedStatus: &infrav1.EventBridgeStatus{
+				QueueName: "test-cluster-" + testClusterUID + "-queue",
+				RuleName:  "test-cluster-" + testClusterUID + "-ec2-rule",
+			},
+		},
+		{
+			name:   "uses the queue name override",
+			config: &infrav1.EventBridgeConfig{Enabled: true, QueueNameOverride: "my-queue"},
+			expectedStatus: &infrav1.EventBridgeStatus{
+				QueueName: "my-queue",
+				RuleName:  "test-cluster-" + testClusterUID + "-ec2-rule",
+			},
+		},
+		{
+			name: "adopts and tags the queue and rule created before the names were recorded",
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String("test-cluster-queue")}).
+					Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("test-cluster-queue-url")}, nil)
+				m.TagQueue(&sqs.TagQueueInput{QueueUrl: aws.String("test-cluster-queue-url"), Tags: aws.StringMap(ownedTags)}).
+					Return(&sqs.TagQueueOutput{}, nil)
+			},
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.DescribeRule(&eventbridge.DescribeRuleInput{Name: aws.String("test-cluster-ec2-rule")}).
+					Return(&eventbridge.DescribeRuleOutput{Name: aws.String("test-cluster-ec2-rule"), Arn: aws.String("rule-arn")}, nil)
+				m.TagResource(&eventbridge.TagResourceInput{ResourceARN: aws.String("rule-arn"), Tags: eventBridgeTags(ownedTags)}).
+					Return(&eventbridge.TagResourceOutput{}, nil)
+			},
+			expectedStatus: &infrav1.EventBridgeStatus{
+				QueueName: "test-cluster-queue",
+				RuleName:  "test-cluster-ec2-rule",
+			},
+		},
+		{
+			name: "returns error if the queue created before the names were recorded can't be looked up",
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String("test-cluster-queue")}).
+					Return(nil, errors.New("some error"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			sqsMock := mock_sqsiface.NewMockSQSAPI(mockCtrl)
+			eventBridgeMock := mock_eventbridgeiface.NewMockEventBridgeAPI(mockCtrl)
+			clusterScope, err := setupClusterWithEventBridge("test-cluster", tc.config, tc.status)
+			g.Expect(err).To(Not(HaveOccurred()))
+
+			if tc.sqsExpect != nil {
+				tc.sqsExpect(sqsMock.EXPECT())
+			}
+			if tc.eventBridgeExpect != nil {
+				tc.eventBridgeExpect(eventBridgeMock.EXPECT())
+			}
+			s := NewService(clusterScope)
+			s.SQSClient = sqsMock
+			s.EventBridgeClient = eventBridgeMock
+
+			err = s.reconcileNames()
+
+			if tc.expectErr {
+				g.Expect(err).NotTo(BeNil())
+				return
+			}
+			g.Expect(err).To(BeNil())
+			g.Expect(clusterScope.EventBridgeStatus()).To(Equal(tc.expectedStatus))
+		})
+	}
+}
+
+func TestGenerateNames(t *testing.T) {
+	testCases := []struct {
+		name              string
+		clusterName       string
+		expectedQueueName string
+		expectedRuleName  string
+	}{
+		{
+			name:              "includes the cluster name and UID",
+			clusterName:       "test-cluster",
+			expectedQueueName: "test-cluster-" + testClusterUID + "-queue",
+			expectedRuleName:  "test-cluster-" + testClusterUID + "-ec2-rule",
+		},
+		{
+			name:              "replaces . with - and truncates long cluster names",
+			clusterName:       "some.very.long.cluster.name",
+			expectedQueueName: "some-very-long-clu-" + testClusterUID + "-queue",
+			expectedRuleName:  "some-very-long-clu-" + testClusterUID + "-ec2-rule",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			queueName := generateQueueName(tc.clusterName, testClusterUID)
+			ruleName := generateRuleName(tc.clusterName, testClusterUID)
+
+			g.Expect(queueName).To(Equal(tc.expectedQueueName))
+			g.Expect(ruleName).To(Equal(tc.expectedRuleName))
+			g.Expect(len(queueName)).To(BeNumerically("<=", 64))
+			g.Expect(len(ruleName)).To(BeNumerically("<=", 64))
+		})
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	testCases := []struct {
+		name        string
+		config      *infrav1.EventBridgeConfig
+		gateEnabled bool
+		expected    bool
+	}{
+		{
+			name:        "follows the feature flag when the cluster doesn't configure EventBridge",
+			gateEnabled: true,
+			expected:    true,
+		},
+		{
+			name:     "is disabled with the feature flag when the cluster doesn't configure EventBridge",
+			expected: false,
+		},
+		{
+			name:     "is enabled by the cluster regardless of the feature flag",
+			config:   &infrav1.EventBridgeConfig{Enabled: true},
+			expected: true,
+		},
+		{
+			name:        "is disabled by the cluster regardless of the feature flag",
+			config:      &infrav1.EventBridgeConfig{Enabled: false},
+			gateEnabled: true,
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.EventBridgeInstanceState, tc.gateEnabled)()
+
+			g.Expect(Enabled(tc.config)).To(Equal(tc.expected))
+		})
+	}
+}
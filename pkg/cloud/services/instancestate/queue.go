@@ -19,7 +19,6 @@ package instancestate
 import (
 	"encoding/json"
 	"fmt"
-	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -34,8 +33,9 @@ func (s *Service) reconcileSQSQueue() error {
 	attrs[sqs.QueueAttributeNameReceiveMessageWaitTimeSeconds] = "20"
 
 	_, err := s.SQSClient.CreateQueue(&sqs.CreateQueueInput{
-		QueueName:  aws.String(GenerateQueueName(s.scope.Name())),
+		QueueName:  aws.String(s.getQueueName()),
 		Attributes: aws.StringMap(attrs),
+		Tags:       aws.StringMap(s.tags()),
 	})
 
 	if err != nil {
@@ -49,7 +49,7 @@ func (s *Service) reconcileSQSQueue() error {
 }
 
 func (s *Service) deleteSQSQueue() error {
-	resp, err := s.SQSClient.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(GenerateQueueName(s.scope.Name()))})
+	resp, err := s.SQSClient.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(s.getQueueName())})
 	if err != nil {
 		if queueNotFoundError(err) {
 			return nil
@@ -71,7 +71,7 @@ func (s *Service) createPolicyForRule(input *createPolicyForRuleInput) error {
 		ID:      input.QueueArn,
 		Statement: iamv1.Statements{
 			iamv1.StatementEntry{
-				Sid:       fmt.Sprintf("CAPAEvents_%s_%s", s.getEC2RuleName(), GenerateQueueName(s.scope.Name())),
+				Sid:       fmt.Sprintf("CAPAEvents_%s_%s", s.getEC2RuleName(), s.getQueueName()),
 				Effect:    iamv1.EffectAllow,
 				Principal: iamv1.Principals{iamv1.PrincipalService: iamv1.PrincipalID{"events.amazonaws.com"}},
 				Action:    iamv1.Actions{"sqs:SendMessage"},
@@ -96,12 +96,6 @@ func (s *Service) createPolicyForRule(input *createPolicyForRuleInput) error {
 	return errors.Wrap(err, "unable to update queue attributes")
 }
 
-// GenerateQueueName will generate a queue name.
-func GenerateQueueName(clusterName string) string {
-	adjusted := strings.ReplaceAll(clusterName, ".", "-")
-	return fmt.Sprintf("%s-queue", adjusted)
-}
-
 func queueNotFoundError(err error) bool {
 	if aerr, ok := err.(awserr.Error); ok {
 		if aerr.Code() == sqs.ErrCodeQueueDoesNotExist {
@@ -48,6 +48,7 @@ func TestReconcileSQSQueue(t *testing.T) {
 				m.CreateQueue(&sqs.CreateQueueInput{
 					QueueName:  aws.String("test-cluster-queue"),
 					Attributes: aws.StringMap(attrs),
+					Tags:       aws.StringMap(map[string]string{"sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster": "owned"}),
 				}).Return(nil, nil)
 			},
 			expectErr: false,
@@ -60,6 +61,7 @@ func TestReconcileSQSQueue(t *testing.T) {
 				m.CreateQueue(&sqs.CreateQueueInput{
 					QueueName:  aws.String("test-cluster-queue"),
 					Attributes: aws.StringMap(attrs),
+					Tags:       aws.StringMap(map[string]string{"sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster": "owned"}),
 				}).Return(nil, awserr.New(sqs.ErrCodeQueueNameExists, "", nil))
 			},
 			expectErr: false,
@@ -72,6 +74,7 @@ func TestReconcileSQSQueue(t *testing.T) {
 				m.CreateQueue(&sqs.CreateQueueInput{
 					QueueName:  aws.String("test-cluster-queue"),
 					Attributes: aws.StringMap(attrs),
+					Tags:       aws.StringMap(map[string]string{"sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster": "owned"}),
 				}).Return(nil, errors.New("some error"))
 			},
 			expectErr: true,
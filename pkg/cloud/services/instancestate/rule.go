@@ -18,7 +18,6 @@ package instancestate
 
 import (
 	"encoding/json"
-	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -62,7 +61,7 @@ func (s Service) reconcileRules() error {
 	}
 
 	queueURLResp, err := s.SQSClient.GetQueueUrl(&sqs.GetQueueUrlInput{
-		QueueName: aws.String(GenerateQueueName(s.scope.Name())),
+		QueueName: aws.String(s.getQueueName()),
 	})
 
 	if err != nil {
@@ -87,7 +86,7 @@ func (s Service) reconcileRules() error {
 	targetFound := false
 	for _, target := range targetsResp.Targets {
 		// check if queue is already added as a target
-		if *target.Id == GenerateQueueName(s.scope.Name()) && *target.Arn == *queueAttrs.Attributes[sqs.QueueAttributeNameQueueArn] {
+		if *target.Id == s.getQueueName() && *target.Arn == *queueAttrs.Attributes[sqs.QueueAttributeNameQueueArn] {
 			targetFound = true
 		}
 	}
@@ -97,12 +96,12 @@ func (s Service) reconcileRules() error {
 			Rule: ruleResp.Name,
 			Targets: []*eventbridge.Target{{
 				Arn: queueAttrs.Attributes[sqs.QueueAttributeNameQueueArn],
-				Id:  aws.String(GenerateQueueName(s.scope.Name())),
+				Id:  aws.String(s.getQueueName()),
 			}},
 		})
 
 		if err != nil {
-			return errors.Wrapf(err, "unable to add SQS target %s to rule %s", GenerateQueueName(s.scope.Name()), s.getEC2RuleName())
+			return errors.Wrapf(err, "unable to add SQS target %s to rule %s", s.getQueueName(), s.getEC2RuleName())
 		}
 	}
 
@@ -139,6 +138,7 @@ func (s Service) createRule() error {
 		Name:         aws.String(s.getEC2RuleName()),
 		EventPattern: aws.String(string(data)),
 		State:        aws.String(eventbridge.RuleStateDisabled),
+		Tags:         eventBridgeTags(s.tags()),
 	})
 
 	return err
@@ -147,10 +147,10 @@ func (s Service) createRule() error {
 func (s Service) deleteRules() error {
 	_, err := s.EventBridgeClient.RemoveTargets(&eventbridge.RemoveTargetsInput{
 		Rule: aws.String(s.getEC2RuleName()),
-		Ids:  aws.StringSlice([]string{GenerateQueueName(s.scope.Name())}),
+		Ids:  aws.StringSlice([]string{s.getQueueName()}),
 	})
 	if err != nil && !resourceNotFoundError(err) {
-		return errors.Wrapf(err, "unable to remove target %s for rule %s", GenerateQueueName(s.scope.Name()), s.getEC2RuleName())
+		return errors.Wrapf(err, "unable to remove target %s for rule %s", s.getQueueName(), s.getEC2RuleName())
 	}
 	_, err = s.EventBridgeClient.DeleteRule(&eventbridge.DeleteRuleInput{
 		Name: aws.String(s.getEC2RuleName()),
@@ -165,6 +165,10 @@ func (s Service) deleteRules() error {
 
 // AddInstanceToEventPattern will add an instance to an event pattern.
 func (s Service) AddInstanceToEventPattern(instanceID string) error {
+	if !s.isEnabled() {
+		return nil
+	}
+
 	ruleResp, err := s.EventBridgeClient.DescribeRule(&eventbridge.DescribeRuleInput{
 		Name: aws.String(s.getEC2RuleName()),
 	})
@@ -201,6 +205,10 @@ func (s Service) AddInstanceToEventPattern(instanceID string) error {
 // RemoveInstanceFromEventPattern attempts a best effort update to the event rule to remove the instance.
 // Any errors encountered won't be blocking.
 func (s Service) RemoveInstanceFromEventPattern(instanceID string) {
+	if !s.isEnabled() {
+		return
+	}
+
 	ruleResp, err := s.EventBridgeClient.DescribeRule(&eventbridge.DescribeRuleInput{
 		Name: aws.String(s.getEC2RuleName()),
 	})
@@ -241,10 +249,6 @@ func (s Service) RemoveInstanceFromEventPattern(instanceID string) {
 	}
 }
 
-func (s Service) getEC2RuleName() string {
-	return fmt.Sprintf("%s-ec2-rule", s.scope.Name())
-}
-
 func resourceNotFoundError(err error) bool {
 	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == eventbridge.ErrCodeResourceNotFoundException {
 		return true
@@ -27,8 +27,10 @@ import (
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
+	utilfeature "k8s.io/component-base/featuregate/testing"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/instancestate/mock_eventbridgeiface"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/instancestate/mock_sqsiface"
 )
@@ -66,6 +68,10 @@ func TestReconcileRules(t *testing.T) {
 					Name:         aws.String(ruleName),
 					State:        aws.String(eventbridge.RuleStateDisabled),
 					EventPattern: aws.String(string(data)),
+					Tags: []*eventbridge.Tag{{
+						Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+						Value: aws.String("owned"),
+					}},
 				}))
 			},
 			postCreateEventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
@@ -236,6 +242,7 @@ func TestDeleteRules(t *testing.T) {
 func TestAddInstanceToRule(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.EventBridgeInstanceState, true)()
 	pattern := eventPattern{
 		DetailType: []string{Ec2StateChangeNotification},
 		Source:     []string{"aws.ec2"},
@@ -315,6 +322,7 @@ func TestAddInstanceToRule(t *testing.T) {
 func TestRemoveInstanceStateFromEventPattern(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.EventBridgeInstanceState, true)()
 	pattern := eventPattern{
 		DetailType: []string{Ec2StateChangeNotification},
 		Source:     []string{"aws.ec2"},
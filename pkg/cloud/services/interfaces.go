@@ -18,6 +18,8 @@ limitations under the License.
 package services
 
 import (
+	"time"
+
 	apimachinerytypes "k8s.io/apimachinery/pkg/types"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
@@ -40,14 +42,42 @@ type ASGInterface interface {
 	ASGIfExists(id *string) (*expinfrav1.AutoScalingGroup, error)
 	GetASGByName(scope *scope.MachinePoolScope) (*expinfrav1.AutoScalingGroup, error)
 	CreateASG(scope *scope.MachinePoolScope) (*expinfrav1.AutoScalingGroup, error)
-	UpdateASG(scope *scope.MachinePoolScope) error
+	UpdateASG(scope *scope.MachinePoolScope, existingASG *expinfrav1.AutoScalingGroup) error
 	StartASGInstanceRefresh(scope *scope.MachinePoolScope) error
 	CanStartASGInstanceRefresh(scope *scope.MachinePoolScope) (bool, error)
+	CancelASGInstanceRefresh(scope *scope.MachinePoolScope) error
+	DescribeInstanceRefresh(name, id string) (*expinfrav1.InstanceRefreshStatus, error)
 	UpdateResourceTags(resourceID *string, create, remove map[string]string) error
+	ReconcileASGTags(scope *scope.MachinePoolScope, asg *expinfrav1.AutoScalingGroup) error
 	DeleteASGAndWait(id string) error
+	ReconcileASGDeletion(asg *expinfrav1.AutoScalingGroup, status *expinfrav1.ASGDeletionStatus, timeout time.Duration) (*expinfrav1.ASGDeletionStatus, bool, error)
 	SuspendProcesses(name string, processes []string) error
 	ResumeProcesses(name string, processes []string) error
+	EnableMetricsCollection(name, granularity string, metrics []string) error
+	DisableMetricsCollection(name string, metrics []string) error
+	DescribeTargetGroupAttachments(name string) ([]string, error)
+	AttachTargetGroups(name string, arns []string) error
+	DetachTargetGroups(name string, arns []string) error
+	AttachLoadBalancers(name string, loadBalancerNames []string) error
+	DetachLoadBalancers(name string, loadBalancerNames []string) error
+	SetInstanceProtection(name string, instanceIDs []string, protected bool) error
+	DetachInstances(name string, instanceIDs []string) error
 	SubnetIDs(scope *scope.MachinePoolScope) ([]string, error)
+	DescribeScalingActivities(name string) ([]*expinfrav1.ScalingActivity, error)
+	OpenSpotInstanceRequestCount(launchTemplateID string) (int32, error)
+	DescribeLifecycleHooks(asgName string) ([]*infrav1.AWSLifecycleHook, error)
+	CreateLifecycleHook(asgName string, hook *infrav1.AWSLifecycleHook) error
+	UpdateLifecycleHook(asgName string, hook *infrav1.AWSLifecycleHook) error
+	DeleteLifecycleHook(asgName string, hook *infrav1.AWSLifecycleHook, forceDelete bool) error
+	LifecycleHookNeedsUpdate(existing *infrav1.AWSLifecycleHook, expected *infrav1.AWSLifecycleHook) bool
+	CompleteLifecycleAction(asgName, hookName, instanceID, lifecycleActionToken string, result infrav1.LifecycleHookDefaultResult) error
+	RecordLifecycleActionHeartbeat(asgName, hookName, instanceID, lifecycleActionToken string) error
+	MissingLifecycleHookPermissions(group *expinfrav1.AutoScalingGroup) ([]string, error)
+	DescribeScheduledActions(asgName string) ([]*expinfrav1.ScheduledAction, error)
+	CreateScheduledAction(asgName string, action *expinfrav1.ScheduledAction) error
+	UpdateScheduledAction(asgName string, existing, expected *expinfrav1.ScheduledAction) error
+	DeleteScheduledAction(asgName, name string) error
+	ScheduledActionNeedsUpdate(existing, expected *expinfrav1.ScheduledAction) bool
 }
 
 // EC2Interface encapsulates the methods exposed to the machine
@@ -64,6 +94,8 @@ type EC2Interface interface {
 	UpdateInstanceSecurityGroups(id string, securityGroups []string) error
 	UpdateResourceTags(resourceID *string, create, remove map[string]string) error
 	ModifyInstanceMetadataOptions(instanceID string, options *infrav1.InstanceMetadataOptions) error
+	GetInstanceUserDataHash(instanceID string) (string, error)
+	CheckSSHKey(keyName string) error
 
 	TerminateInstanceAndWait(instanceID string) error
 	DetachSecurityGroupsFromNetworkInterface(groups []string, interfaceID string) error
@@ -72,6 +104,7 @@ type EC2Interface interface {
 	GetLaunchTemplate(id string) (lt *expinfrav1.AWSLaunchTemplate, userDataHash string, userDataSecretKey *apimachinerytypes.NamespacedName, err error)
 	GetLaunchTemplateID(id string) (string, error)
 	GetLaunchTemplateLatestVersion(id string) (string, error)
+	GetLaunchTemplateVersion(id, version string) (string, error)
 	CreateLaunchTemplate(scope scope.LaunchTemplateScope, imageID *string, userDataSecretKey apimachinerytypes.NamespacedName, userData []byte) (string, error)
 	CreateLaunchTemplateVersion(id string, scope scope.LaunchTemplateScope, imageID *string, userDataSecretKey apimachinerytypes.NamespacedName, userData []byte) error
 	PruneLaunchTemplateVersions(id string) error
@@ -89,6 +122,13 @@ type MachinePoolReconcileInterface interface {
 	ReconcileTags(scope scope.LaunchTemplateScope, resourceServicesToUpdate []scope.ResourceServiceToUpdate) error
 }
 
+// SpotInterruptionInterface encapsulates the methods exposed to the machine pool reconciler to receive the spot
+// interruption notices of the instances of a cluster.
+type SpotInterruptionInterface interface {
+	ReconcileSpotInterruptionEvents() (string, error)
+	DeleteSpotInterruptionEvents() error
+}
+
 // SecretInterface encapsulated the methods exposed to the
 // machine actuator.
 type SecretInterface interface {
@@ -108,6 +148,7 @@ type ELBInterface interface {
 	DeregisterInstanceFromAPIServerLB(targetGroupArn string, i *infrav1.Instance) error
 	RegisterInstanceWithAPIServerELB(i *infrav1.Instance) error
 	RegisterInstanceWithAPIServerLB(i *infrav1.Instance, lb *infrav1.AWSLoadBalancerSpec) error
+	GetAPIServerLBListenerTargetGroups(lb *infrav1.AWSLoadBalancerSpec) (map[int64]string, error)
 }
 
 // NetworkInterface encapsulates the methods exposed to the cluster
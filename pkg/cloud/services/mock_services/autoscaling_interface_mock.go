@@ -22,9 +22,11 @@ package mock_services
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
-	v1beta2 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	v1beta2 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	v1beta20 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
 	scope "sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
 )
 
@@ -52,10 +54,10 @@ func (m *MockASGInterface) EXPECT() *MockASGInterfaceMockRecorder {
 }
 
 // ASGIfExists mocks base method.
-func (m *MockASGInterface) ASGIfExists(arg0 *string) (*v1beta2.AutoScalingGroup, error) {
+func (m *MockASGInterface) ASGIfExists(arg0 *string) (*v1beta20.AutoScalingGroup, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ASGIfExists", arg0)
-	ret0, _ := ret[0].(*v1beta2.AutoScalingGroup)
+	ret0, _ := ret[0].(*v1beta20.AutoScalingGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -66,6 +68,34 @@ func (mr *MockASGInterfaceMockRecorder) ASGIfExists(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ASGIfExists", reflect.TypeOf((*MockASGInterface)(nil).ASGIfExists), arg0)
 }
 
+// AttachLoadBalancers mocks base method.
+func (m *MockASGInterface) AttachLoadBalancers(arg0 string, arg1 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachLoadBalancers", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachLoadBalancers indicates an expected call of AttachLoadBalancers.
+func (mr *MockASGInterfaceMockRecorder) AttachLoadBalancers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachLoadBalancers", reflect.TypeOf((*MockASGInterface)(nil).AttachLoadBalancers), arg0, arg1)
+}
+
+// AttachTargetGroups mocks base method.
+func (m *MockASGInterface) AttachTargetGroups(arg0 string, arg1 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachTargetGroups", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachTargetGroups indicates an expected call of AttachTargetGroups.
+func (mr *MockASGInterfaceMockRecorder) AttachTargetGroups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachTargetGroups", reflect.TypeOf((*MockASGInterface)(nil).AttachTargetGroups), arg0, arg1)
+}
+
 // CanStartASGInstanceRefresh mocks base method.
 func (m *MockASGInterface) CanStartASGInstanceRefresh(arg0 *scope.MachinePoolScope) (bool, error) {
 	m.ctrl.T.Helper()
@@ -81,11 +111,39 @@ func (mr *MockASGInterfaceMockRecorder) CanStartASGInstanceRefresh(arg0 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanStartASGInstanceRefresh", reflect.TypeOf((*MockASGInterface)(nil).CanStartASGInstanceRefresh), arg0)
 }
 
+// CancelASGInstanceRefresh mocks base method.
+func (m *MockASGInterface) CancelASGInstanceRefresh(arg0 *scope.MachinePoolScope) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelASGInstanceRefresh", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelASGInstanceRefresh indicates an expected call of CancelASGInstanceRefresh.
+func (mr *MockASGInterfaceMockRecorder) CancelASGInstanceRefresh(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelASGInstanceRefresh", reflect.TypeOf((*MockASGInterface)(nil).CancelASGInstanceRefresh), arg0)
+}
+
+// CompleteLifecycleAction mocks base method.
+func (m *MockASGInterface) CompleteLifecycleAction(arg0, arg1, arg2, arg3 string, arg4 v1beta2.LifecycleHookDefaultResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteLifecycleAction", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteLifecycleAction indicates an expected call of CompleteLifecycleAction.
+func (mr *MockASGInterfaceMockRecorder) CompleteLifecycleAction(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteLifecycleAction", reflect.TypeOf((*MockASGInterface)(nil).CompleteLifecycleAction), arg0, arg1, arg2, arg3, arg4)
+}
+
 // CreateASG mocks base method.
-func (m *MockASGInterface) CreateASG(arg0 *scope.MachinePoolScope) (*v1beta2.AutoScalingGroup, error) {
+func (m *MockASGInterface) CreateASG(arg0 *scope.MachinePoolScope) (*v1beta20.AutoScalingGroup, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateASG", arg0)
-	ret0, _ := ret[0].(*v1beta2.AutoScalingGroup)
+	ret0, _ := ret[0].(*v1beta20.AutoScalingGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -96,6 +154,34 @@ func (mr *MockASGInterfaceMockRecorder) CreateASG(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateASG", reflect.TypeOf((*MockASGInterface)(nil).CreateASG), arg0)
 }
 
+// CreateLifecycleHook mocks base method.
+func (m *MockASGInterface) CreateLifecycleHook(arg0 string, arg1 *v1beta2.AWSLifecycleHook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLifecycleHook", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLifecycleHook indicates an expected call of CreateLifecycleHook.
+func (mr *MockASGInterfaceMockRecorder) CreateLifecycleHook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLifecycleHook", reflect.TypeOf((*MockASGInterface)(nil).CreateLifecycleHook), arg0, arg1)
+}
+
+// CreateScheduledAction mocks base method.
+func (m *MockASGInterface) CreateScheduledAction(arg0 string, arg1 *v1beta20.ScheduledAction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduledAction", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateScheduledAction indicates an expected call of CreateScheduledAction.
+func (mr *MockASGInterfaceMockRecorder) CreateScheduledAction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledAction", reflect.TypeOf((*MockASGInterface)(nil).CreateScheduledAction), arg0, arg1)
+}
+
 // DeleteASGAndWait mocks base method.
 func (m *MockASGInterface) DeleteASGAndWait(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -110,11 +196,184 @@ func (mr *MockASGInterfaceMockRecorder) DeleteASGAndWait(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteASGAndWait", reflect.TypeOf((*MockASGInterface)(nil).DeleteASGAndWait), arg0)
 }
 
+// DeleteLifecycleHook mocks base method.
+func (m *MockASGInterface) DeleteLifecycleHook(arg0 string, arg1 *v1beta2.AWSLifecycleHook, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLifecycleHook", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLifecycleHook indicates an expected call of DeleteLifecycleHook.
+func (mr *MockASGInterfaceMockRecorder) DeleteLifecycleHook(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLifecycleHook", reflect.TypeOf((*MockASGInterface)(nil).DeleteLifecycleHook), arg0, arg1, arg2)
+}
+
+// DeleteScheduledAction mocks base method.
+func (m *MockASGInterface) DeleteScheduledAction(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduledAction", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteScheduledAction indicates an expected call of DeleteScheduledAction.
+func (mr *MockASGInterfaceMockRecorder) DeleteScheduledAction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduledAction", reflect.TypeOf((*MockASGInterface)(nil).DeleteScheduledAction), arg0, arg1)
+}
+
+// DescribeInstanceRefresh mocks base method.
+func (m *MockASGInterface) DescribeInstanceRefresh(arg0, arg1 string) (*v1beta20.InstanceRefreshStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeInstanceRefresh", arg0, arg1)
+	ret0, _ := ret[0].(*v1beta20.InstanceRefreshStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeInstanceRefresh indicates an expected call of DescribeInstanceRefresh.
+func (mr *MockASGInterfaceMockRecorder) DescribeInstanceRefresh(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeInstanceRefresh", reflect.TypeOf((*MockASGInterface)(nil).DescribeInstanceRefresh), arg0, arg1)
+}
+
+// DescribeLifecycleHooks mocks base method.
+func (m *MockASGInterface) DescribeLifecycleHooks(arg0 string) ([]*v1beta2.AWSLifecycleHook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeLifecycleHooks", arg0)
+	ret0, _ := ret[0].([]*v1beta2.AWSLifecycleHook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeLifecycleHooks indicates an expected call of DescribeLifecycleHooks.
+func (mr *MockASGInterfaceMockRecorder) DescribeLifecycleHooks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLifecycleHooks", reflect.TypeOf((*MockASGInterface)(nil).DescribeLifecycleHooks), arg0)
+}
+
+// DescribeScalingActivities mocks base method.
+func (m *MockASGInterface) DescribeScalingActivities(arg0 string) ([]*v1beta20.ScalingActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeScalingActivities", arg0)
+	ret0, _ := ret[0].([]*v1beta20.ScalingActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeScalingActivities indicates an expected call of DescribeScalingActivities.
+func (mr *MockASGInterfaceMockRecorder) DescribeScalingActivities(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeScalingActivities", reflect.TypeOf((*MockASGInterface)(nil).DescribeScalingActivities), arg0)
+}
+
+// DescribeScheduledActions mocks base method.
+func (m *MockASGInterface) DescribeScheduledActions(arg0 string) ([]*v1beta20.ScheduledAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeScheduledActions", arg0)
+	ret0, _ := ret[0].([]*v1beta20.ScheduledAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeScheduledActions indicates an expected call of DescribeScheduledActions.
+func (mr *MockASGInterfaceMockRecorder) DescribeScheduledActions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeScheduledActions", reflect.TypeOf((*MockASGInterface)(nil).DescribeScheduledActions), arg0)
+}
+
+// DescribeTargetGroupAttachments mocks base method.
+func (m *MockASGInterface) DescribeTargetGroupAttachments(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeTargetGroupAttachments", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeTargetGroupAttachments indicates an expected call of DescribeTargetGroupAttachments.
+func (mr *MockASGInterfaceMockRecorder) DescribeTargetGroupAttachments(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeTargetGroupAttachments", reflect.TypeOf((*MockASGInterface)(nil).DescribeTargetGroupAttachments), arg0)
+}
+
+// DetachInstances mocks base method.
+func (m *MockASGInterface) DetachInstances(arg0 string, arg1 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachInstances", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachInstances indicates an expected call of DetachInstances.
+func (mr *MockASGInterfaceMockRecorder) DetachInstances(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachInstances", reflect.TypeOf((*MockASGInterface)(nil).DetachInstances), arg0, arg1)
+}
+
+// DetachLoadBalancers mocks base method.
+func (m *MockASGInterface) DetachLoadBalancers(arg0 string, arg1 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachLoadBalancers", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachLoadBalancers indicates an expected call of DetachLoadBalancers.
+func (mr *MockASGInterfaceMockRecorder) DetachLoadBalancers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachLoadBalancers", reflect.TypeOf((*MockASGInterface)(nil).DetachLoadBalancers), arg0, arg1)
+}
+
+// DetachTargetGroups mocks base method.
+func (m *MockASGInterface) DetachTargetGroups(arg0 string, arg1 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachTargetGroups", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachTargetGroups indicates an expected call of DetachTargetGroups.
+func (mr *MockASGInterfaceMockRecorder) DetachTargetGroups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachTargetGroups", reflect.TypeOf((*MockASGInterface)(nil).DetachTargetGroups), arg0, arg1)
+}
+
+// DisableMetricsCollection mocks base method.
+func (m *MockASGInterface) DisableMetricsCollection(arg0 string, arg1 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableMetricsCollection", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableMetricsCollection indicates an expected call of DisableMetricsCollection.
+func (mr *MockASGInterfaceMockRecorder) DisableMetricsCollection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableMetricsCollection", reflect.TypeOf((*MockASGInterface)(nil).DisableMetricsCollection), arg0, arg1)
+}
+
+// EnableMetricsCollection mocks base method.
+func (m *MockASGInterface) EnableMetricsCollection(arg0, arg1 string, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableMetricsCollection", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableMetricsCollection indicates an expected call of EnableMetricsCollection.
+func (mr *MockASGInterfaceMockRecorder) EnableMetricsCollection(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableMetricsCollection", reflect.TypeOf((*MockASGInterface)(nil).EnableMetricsCollection), arg0, arg1, arg2)
+}
+
 // GetASGByName mocks base method.
-func (m *MockASGInterface) GetASGByName(arg0 *scope.MachinePoolScope) (*v1beta2.AutoScalingGroup, error) {
+func (m *MockASGInterface) GetASGByName(arg0 *scope.MachinePoolScope) (*v1beta20.AutoScalingGroup, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetASGByName", arg0)
-	ret0, _ := ret[0].(*v1beta2.AutoScalingGroup)
+	ret0, _ := ret[0].(*v1beta20.AutoScalingGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -125,6 +384,94 @@ func (mr *MockASGInterfaceMockRecorder) GetASGByName(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetASGByName", reflect.TypeOf((*MockASGInterface)(nil).GetASGByName), arg0)
 }
 
+// LifecycleHookNeedsUpdate mocks base method.
+func (m *MockASGInterface) LifecycleHookNeedsUpdate(arg0, arg1 *v1beta2.AWSLifecycleHook) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LifecycleHookNeedsUpdate", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// LifecycleHookNeedsUpdate indicates an expected call of LifecycleHookNeedsUpdate.
+func (mr *MockASGInterfaceMockRecorder) LifecycleHookNeedsUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LifecycleHookNeedsUpdate", reflect.TypeOf((*MockASGInterface)(nil).LifecycleHookNeedsUpdate), arg0, arg1)
+}
+
+// MissingLifecycleHookPermissions mocks base method.
+func (m *MockASGInterface) MissingLifecycleHookPermissions(arg0 *v1beta20.AutoScalingGroup) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MissingLifecycleHookPermissions", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MissingLifecycleHookPermissions indicates an expected call of MissingLifecycleHookPermissions.
+func (mr *MockASGInterfaceMockRecorder) MissingLifecycleHookPermissions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MissingLifecycleHookPermissions", reflect.TypeOf((*MockASGInterface)(nil).MissingLifecycleHookPermissions), arg0)
+}
+
+// OpenSpotInstanceRequestCount mocks base method.
+func (m *MockASGInterface) OpenSpotInstanceRequestCount(arg0 string) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenSpotInstanceRequestCount", arg0)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenSpotInstanceRequestCount indicates an expected call of OpenSpotInstanceRequestCount.
+func (mr *MockASGInterfaceMockRecorder) OpenSpotInstanceRequestCount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenSpotInstanceRequestCount", reflect.TypeOf((*MockASGInterface)(nil).OpenSpotInstanceRequestCount), arg0)
+}
+
+// ReconcileASGDeletion mocks base method.
+func (m *MockASGInterface) ReconcileASGDeletion(arg0 *v1beta20.AutoScalingGroup, arg1 *v1beta20.ASGDeletionStatus, arg2 time.Duration) (*v1beta20.ASGDeletionStatus, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileASGDeletion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1beta20.ASGDeletionStatus)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReconcileASGDeletion indicates an expected call of ReconcileASGDeletion.
+func (mr *MockASGInterfaceMockRecorder) ReconcileASGDeletion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileASGDeletion", reflect.TypeOf((*MockASGInterface)(nil).ReconcileASGDeletion), arg0, arg1, arg2)
+}
+
+// ReconcileASGTags mocks base method.
+func (m *MockASGInterface) ReconcileASGTags(arg0 *scope.MachinePoolScope, arg1 *v1beta20.AutoScalingGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileASGTags", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReconcileASGTags indicates an expected call of ReconcileASGTags.
+func (mr *MockASGInterfaceMockRecorder) ReconcileASGTags(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileASGTags", reflect.TypeOf((*MockASGInterface)(nil).ReconcileASGTags), arg0, arg1)
+}
+
+// RecordLifecycleActionHeartbeat mocks base method.
+func (m *MockASGInterface) RecordLifecycleActionHeartbeat(arg0, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLifecycleActionHeartbeat", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordLifecycleActionHeartbeat indicates an expected call of RecordLifecycleActionHeartbeat.
+func (mr *MockASGInterfaceMockRecorder) RecordLifecycleActionHeartbeat(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLifecycleActionHeartbeat", reflect.TypeOf((*MockASGInterface)(nil).RecordLifecycleActionHeartbeat), arg0, arg1, arg2, arg3)
+}
+
 // ResumeProcesses mocks base method.
 func (m *MockASGInterface) ResumeProcesses(arg0 string, arg1 []string) error {
 	m.ctrl.T.Helper()
@@ -139,6 +486,34 @@ func (mr *MockASGInterfaceMockRecorder) ResumeProcesses(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeProcesses", reflect.TypeOf((*MockASGInterface)(nil).ResumeProcesses), arg0, arg1)
 }
 
+// ScheduledActionNeedsUpdate mocks base method.
+func (m *MockASGInterface) ScheduledActionNeedsUpdate(arg0, arg1 *v1beta20.ScheduledAction) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduledActionNeedsUpdate", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ScheduledActionNeedsUpdate indicates an expected call of ScheduledActionNeedsUpdate.
+func (mr *MockASGInterfaceMockRecorder) ScheduledActionNeedsUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduledActionNeedsUpdate", reflect.TypeOf((*MockASGInterface)(nil).ScheduledActionNeedsUpdate), arg0, arg1)
+}
+
+// SetInstanceProtection mocks base method.
+func (m *MockASGInterface) SetInstanceProtection(arg0 string, arg1 []string, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInstanceProtection", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInstanceProtection indicates an expected call of SetInstanceProtection.
+func (mr *MockASGInterfaceMockRecorder) SetInstanceProtection(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInstanceProtection", reflect.TypeOf((*MockASGInterface)(nil).SetInstanceProtection), arg0, arg1, arg2)
+}
+
 // StartASGInstanceRefresh mocks base method.
 func (m *MockASGInterface) StartASGInstanceRefresh(arg0 *scope.MachinePoolScope) error {
 	m.ctrl.T.Helper()
@@ -183,17 +558,31 @@ func (mr *MockASGInterfaceMockRecorder) SuspendProcesses(arg0, arg1 interface{})
 }
 
 // UpdateASG mocks base method.
-func (m *MockASGInterface) UpdateASG(arg0 *scope.MachinePoolScope) error {
+func (m *MockASGInterface) UpdateASG(arg0 *scope.MachinePoolScope, arg1 *v1beta20.AutoScalingGroup) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateASG", arg0)
+	ret := m.ctrl.Call(m, "UpdateASG", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateASG indicates an expected call of UpdateASG.
-func (mr *MockASGInterfaceMockRecorder) UpdateASG(arg0 interface{}) *gomock.Call {
+func (mr *MockASGInterfaceMockRecorder) UpdateASG(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateASG", reflect.TypeOf((*MockASGInterface)(nil).UpdateASG), arg0, arg1)
+}
+
+// UpdateLifecycleHook mocks base method.
+func (m *MockASGInterface) UpdateLifecycleHook(arg0 string, arg1 *v1beta2.AWSLifecycleHook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLifecycleHook", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLifecycleHook indicates an expected call of UpdateLifecycleHook.
+func (mr *MockASGInterfaceMockRecorder) UpdateLifecycleHook(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateASG", reflect.TypeOf((*MockASGInterface)(nil).UpdateASG), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLifecycleHook", reflect.TypeOf((*MockASGInterface)(nil).UpdateLifecycleHook), arg0, arg1)
 }
 
 // UpdateResourceTags mocks base method.
@@ -209,3 +598,17 @@ func (mr *MockASGInterfaceMockRecorder) UpdateResourceTags(arg0, arg1, arg2 inte
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResourceTags", reflect.TypeOf((*MockASGInterface)(nil).UpdateResourceTags), arg0, arg1, arg2)
 }
+
+// UpdateScheduledAction mocks base method.
+func (m *MockASGInterface) UpdateScheduledAction(arg0 string, arg1, arg2 *v1beta20.ScheduledAction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScheduledAction", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateScheduledAction indicates an expected call of UpdateScheduledAction.
+func (mr *MockASGInterfaceMockRecorder) UpdateScheduledAction(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduledAction", reflect.TypeOf((*MockASGInterface)(nil).UpdateScheduledAction), arg0, arg1, arg2)
+}
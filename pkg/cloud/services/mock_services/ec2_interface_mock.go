@@ -53,6 +53,20 @@ func (m *MockEC2Interface) EXPECT() *MockEC2InterfaceMockRecorder {
 	return m.recorder
 }
 
+// CheckSSHKey mocks base method.
+func (m *MockEC2Interface) CheckSSHKey(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckSSHKey", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckSSHKey indicates an expected call of CheckSSHKey.
+func (mr *MockEC2InterfaceMockRecorder) CheckSSHKey(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckSSHKey", reflect.TypeOf((*MockEC2Interface)(nil).CheckSSHKey), arg0)
+}
+
 // CreateInstance mocks base method.
 func (m *MockEC2Interface) CreateInstance(arg0 *scope.MachineScope, arg1 []byte, arg2 string) (*v1beta2.Instance, error) {
 	m.ctrl.T.Helper()
@@ -199,6 +213,21 @@ func (mr *MockEC2InterfaceMockRecorder) GetInstanceSecurityGroups(arg0 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceSecurityGroups", reflect.TypeOf((*MockEC2Interface)(nil).GetInstanceSecurityGroups), arg0)
 }
 
+// GetInstanceUserDataHash mocks base method.
+func (m *MockEC2Interface) GetInstanceUserDataHash(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstanceUserDataHash", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstanceUserDataHash indicates an expected call of GetInstanceUserDataHash.
+func (mr *MockEC2InterfaceMockRecorder) GetInstanceUserDataHash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceUserDataHash", reflect.TypeOf((*MockEC2Interface)(nil).GetInstanceUserDataHash), arg0)
+}
+
 // GetLaunchTemplate mocks base method.
 func (m *MockEC2Interface) GetLaunchTemplate(arg0 string) (*v1beta20.AWSLaunchTemplate, string, *types.NamespacedName, error) {
 	m.ctrl.T.Helper()
@@ -246,6 +275,21 @@ func (mr *MockEC2InterfaceMockRecorder) GetLaunchTemplateLatestVersion(arg0 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLaunchTemplateLatestVersion", reflect.TypeOf((*MockEC2Interface)(nil).GetLaunchTemplateLatestVersion), arg0)
 }
 
+// GetLaunchTemplateVersion mocks base method.
+func (m *MockEC2Interface) GetLaunchTemplateVersion(arg0, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLaunchTemplateVersion", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLaunchTemplateVersion indicates an expected call of GetLaunchTemplateVersion.
+func (mr *MockEC2InterfaceMockRecorder) GetLaunchTemplateVersion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLaunchTemplateVersion", reflect.TypeOf((*MockEC2Interface)(nil).GetLaunchTemplateVersion), arg0, arg1)
+}
+
 // GetRunningInstanceByTags mocks base method.
 func (m *MockEC2Interface) GetRunningInstanceByTags(arg0 *scope.MachineScope) (*v1beta2.Instance, error) {
 	m.ctrl.T.Helper()
@@ -92,6 +92,21 @@ func (mr *MockELBInterfaceMockRecorder) DeregisterInstanceFromAPIServerLB(arg0,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeregisterInstanceFromAPIServerLB", reflect.TypeOf((*MockELBInterface)(nil).DeregisterInstanceFromAPIServerLB), arg0, arg1)
 }
 
+// GetAPIServerLBListenerTargetGroups mocks base method.
+func (m *MockELBInterface) GetAPIServerLBListenerTargetGroups(arg0 *v1beta2.AWSLoadBalancerSpec) (map[int64]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIServerLBListenerTargetGroups", arg0)
+	ret0, _ := ret[0].(map[int64]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIServerLBListenerTargetGroups indicates an expected call of GetAPIServerLBListenerTargetGroups.
+func (mr *MockELBInterfaceMockRecorder) GetAPIServerLBListenerTargetGroups(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIServerLBListenerTargetGroups", reflect.TypeOf((*MockELBInterface)(nil).GetAPIServerLBListenerTargetGroups), arg0)
+}
+
 // IsInstanceRegisteredWithAPIServerELB mocks base method.
 func (m *MockELBInterface) IsInstanceRegisteredWithAPIServerELB(arg0 *v1beta2.Instance) (bool, error) {
 	m.ctrl.T.Helper()
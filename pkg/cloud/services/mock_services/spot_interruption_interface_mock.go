@@ -0,0 +1,79 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services (interfaces: SpotInterruptionInterface)
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSpotInterruptionInterface is a mock of SpotInterruptionInterface interface.
+type MockSpotInterruptionInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockSpotInterruptionInterfaceMockRecorder
+}
+
+// MockSpotInterruptionInterfaceMockRecorder is the mock recorder for MockSpotInterruptionInterface.
+type MockSpotInterruptionInterfaceMockRecorder struct {
+	mock *MockSpotInterruptionInterface
+}
+
+// NewMockSpotInterruptionInterface creates a new mock instance.
+func NewMockSpotInterruptionInterface(ctrl *gomock.Controller) *MockSpotInterruptionInterface {
+	mock := &MockSpotInterruptionInterface{ctrl: ctrl}
+	mock.recorder = &MockSpotInterruptionInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSpotInterruptionInterface) EXPECT() *MockSpotInterruptionInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteSpotInterruptionEvents mocks base method.
+func (m *MockSpotInterruptionInterface) DeleteSpotInterruptionEvents() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSpotInterruptionEvents")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSpotInterruptionEvents indicates an expected call of DeleteSpotInterruptionEvents.
+func (mr *MockSpotInterruptionInterfaceMockRecorder) DeleteSpotInterruptionEvents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSpotInterruptionEvents", reflect.TypeOf((*MockSpotInterruptionInterface)(nil).DeleteSpotInterruptionEvents))
+}
+
+// ReconcileSpotInterruptionEvents mocks base method.
+func (m *MockSpotInterruptionInterface) ReconcileSpotInterruptionEvents() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileSpotInterruptionEvents")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileSpotInterruptionEvents indicates an expected call of ReconcileSpotInterruptionEvents.
+func (mr *MockSpotInterruptionInterfaceMockRecorder) ReconcileSpotInterruptionEvents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileSpotInterruptionEvents", reflect.TypeOf((*MockSpotInterruptionInterface)(nil).ReconcileSpotInterruptionEvents))
+}
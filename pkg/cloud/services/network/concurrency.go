@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"sync"
+
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// maxConcurrentCreations is the maximum number of resources of the availability zones, e.g. subnets or NAT
+// gateways, which are created at the same time.
+const maxConcurrentCreations = 4
+
+// forEachConcurrently calls fn for each index lower than n, with at most maxConcurrentCreations calls running at
+// the same time, and waits for all of them to return. A failing call doesn't stop the others, so the resources of
+// the other availability zones are still created, and the errors of all the failing calls are aggregated. The
+// error of a single failing call is returned as is.
+func forEachConcurrently(n int, fn func(i int) error) error {
+	errs := make([]error, n)
+	sem := make(chan struct{}, maxConcurrentCreations)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+
+	return kerrors.Reduce(kerrors.NewAggregate(errs))
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestForEachConcurrently(t *testing.T) {
+	g := NewWithT(t)
+
+	var running, maxRunning int32
+	var calls [10]bool
+	err := forEachConcurrently(len(calls), func(i int) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		calls[i] = true
+		if i%4 == 1 {
+			return fmt.Errorf("failed %d", i)
+		}
+		return nil
+	})
+
+	// All the calls are made, even after some of them failed.
+	g.Expect(calls).To(HaveEach(BeTrue()))
+	g.Expect(maxRunning).To(BeNumerically("<=", maxConcurrentCreations))
+	g.Expect(err).To(MatchError("[failed 1, failed 5, failed 9]"))
+
+	singleErr := errors.New("failed")
+	g.Expect(forEachConcurrently(3, func(i int) error {
+		if i == 2 {
+			return singleErr
+		}
+		return nil
+	})).To(BeIdenticalTo(singleErr))
+
+	g.Expect(forEachConcurrently(0, func(i int) error { return singleErr })).To(Succeed())
+}
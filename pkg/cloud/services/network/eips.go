@@ -19,19 +19,25 @@ package network
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/converters"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/filter"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/wait"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/tags"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
 )
 
+// eipReleaseGracePeriod is the time after their allocation during which the unused Elastic IPs aren't released.
+const eipReleaseGracePeriod = 10 * time.Minute
+
 func (s *Service) getOrAllocateAddresses(num int, role string) (eips []string, err error) {
 	out, err := s.describeAddresses(role)
 	if err != nil {
@@ -57,7 +63,10 @@ func (s *Service) getOrAllocateAddresses(num int, role string) (eips []string, e
 }
 
 func (s *Service) allocateAddress(role string) (string, error) {
-	tagSpecifications := tags.BuildParamsToTagSpecification(ec2.ResourceTypeElasticIp, s.getEIPTagParams(role))
+	params := s.getEIPTagParams(role)
+	// The allocation time is recorded, so that an Elastic IP which is about to be used isn't released as unused.
+	params.Additional[infrav1.ElasticIPAllocatedAtTagKey] = time.Now().UTC().Format(time.RFC3339)
+	tagSpecifications := tags.BuildParamsToTagSpecification(ec2.ResourceTypeElasticIp, params)
 	out, err := s.EC2Client.AllocateAddressWithContext(context.TODO(), &ec2.AllocateAddressInput{
 		Domain: aws.String("vpc"),
 		TagSpecifications: []*ec2.TagSpecification{
@@ -145,6 +154,45 @@ func (s *Service) releaseAddresses() error {
 	return nil
 }
 
+// releaseUnusedAddresses releases the Elastic IPs allocated by CAPA for the NAT gateways which are neither
+// associated nor used by a NAT gateway, e.g. after a NAT gateway was replaced. The Elastic IPs allocated
+// less than eipReleaseGracePeriod ago are kept, as they may be about to be used by a NAT gateway.
+func (s *Service) releaseUnusedAddresses(used sets.Set[string]) error {
+	out, err := s.describeAddresses(infrav1.APIServerRoleTagValue)
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeAddresses", "Failed to query addresses for role %q: %v", infrav1.APIServerRoleTagValue, err)
+		return errors.Wrap(err, "failed to query addresses")
+	}
+
+	for _, address := range out.Addresses {
+		allocationID := aws.StringValue(address.AllocationId)
+		if address.AssociationId != nil || used.Has(allocationID) {
+			continue
+		}
+
+		addressTags := infrav1.Tags(converters.TagsToMap(address.Tags))
+		if !addressTags.HasOwned(s.scope.Name()) {
+			continue
+		}
+		// The Elastic IPs allocated before the allocation time was recorded are past the grace period.
+		if allocatedAt, err := time.Parse(time.RFC3339, addressTags[infrav1.ElasticIPAllocatedAtTagKey]); err == nil && time.Since(allocatedAt) < eipReleaseGracePeriod {
+			continue
+		}
+
+		if _, err := s.EC2Client.ReleaseAddressWithContext(context.TODO(), &ec2.ReleaseAddressInput{AllocationId: address.AllocationId}); err != nil {
+			if code, _ := awserrors.Code(err); code == awserrors.AllocationIDNotFound {
+				continue
+			}
+			record.Warnf(s.scope.InfraCluster(), "FailedReleaseEIP", "Failed to release unused Elastic IP %q: %v", allocationID, err)
+			return errors.Wrapf(err, "failed to release unused Elastic IP %q", allocationID)
+		}
+		record.Eventf(s.scope.InfraCluster(), "SuccessfulReleaseEIP", "Released unused Elastic IP %q", allocationID)
+		s.scope.Info("Released unused Elastic IP", "eip", aws.StringValue(address.PublicIp), "allocation-id", allocationID)
+	}
+
+	return nil
+}
+
 func (s *Service) getEIPTagParams(role string) infrav1.BuildParams {
 	name := fmt.Sprintf("%s-eip-%s", s.scope.Name(), role)
 
@@ -0,0 +1,232 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// reconcileInstanceConnectEndpoint creates the EC2 Instance Connect Endpoint of the cluster when it is enabled, and
+// deletes it once it is disabled. The endpoint takes minutes to be created or deleted, so the reconciliation doesn't
+// wait for it: its state is recorded in the status of the cluster and in the InstanceConnectEndpointReady condition.
+func (s *Service) reconcileInstanceConnectEndpoint() error {
+	spec := s.scope.InstanceConnectEndpoint()
+	if !spec.IsEnabled() {
+		if s.scope.InstanceConnectEndpointStatus() == nil {
+			conditions.Delete(s.scope.InfraCluster(), infrav1.InstanceConnectEndpointReadyCondition)
+			return nil
+		}
+		deleted, err := s.deleteInstanceConnectEndpoint()
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			conditions.MarkFalse(s.scope.InfraCluster(), infrav1.InstanceConnectEndpointReadyCondition, infrav1.InstanceConnectEndpointDeletingReason, clusterv1.ConditionSeverityInfo, "")
+			return nil
+		}
+		conditions.Delete(s.scope.InfraCluster(), infrav1.InstanceConnectEndpointReadyCondition)
+		return nil
+	}
+
+	// The endpoint is created in a subnet of the VPC, which must exist first.
+	if s.scope.VPC().ID == "" {
+		return nil
+	}
+
+	endpoint, err := s.describeInstanceConnectEndpoint()
+	if err != nil {
+		return err
+	}
+	if endpoint == nil {
+		if endpoint, err = s.createInstanceConnectEndpoint(spec); err != nil {
+			return err
+		}
+	}
+	s.setInstanceConnectEndpointStatus(endpoint)
+
+	switch state := aws.StringValue(endpoint.State); state {
+	case ec2.Ec2InstanceConnectEndpointStateCreateComplete:
+		conditions.MarkTrue(s.scope.InfraCluster(), infrav1.InstanceConnectEndpointReadyCondition)
+	case ec2.Ec2InstanceConnectEndpointStateCreateInProgress:
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.InstanceConnectEndpointReadyCondition, infrav1.InstanceConnectEndpointCreatingReason, clusterv1.ConditionSeverityInfo, "")
+	default:
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.InstanceConnectEndpointReadyCondition, infrav1.InstanceConnectEndpointFailedReason, clusterv1.ConditionSeverityError,
+			"instance connect endpoint %s is %s: %s", aws.StringValue(endpoint.InstanceConnectEndpointId), state, aws.StringValue(endpoint.StateMessage))
+	}
+	return nil
+}
+
+// deleteInstanceConnectEndpoint deletes the EC2 Instance Connect Endpoint of the cluster, and returns whether it is
+// deleted. Nothing is deleted when the endpoint was neither enabled nor created.
+func (s *Service) deleteInstanceConnectEndpoint() (bool, error) {
+	if s.scope.InstanceConnectEndpointStatus() == nil && !s.scope.InstanceConnectEndpoint().IsEnabled() {
+		return true, nil
+	}
+
+	endpoint, err := s.describeInstanceConnectEndpoint()
+	if err != nil {
+		return false, err
+	}
+	if endpoint == nil {
+		s.scope.SetInstanceConnectEndpointStatus(nil)
+		return true, nil
+	}
+	s.setInstanceConnectEndpointStatus(endpoint)
+
+	id := aws.StringValue(endpoint.InstanceConnectEndpointId)
+	if aws.StringValue(endpoint.State) == ec2.Ec2InstanceConnectEndpointStateDeleteInProgress {
+		s.scope.Debug("Waiting for the instance connect endpoint to be deleted", "instance-connect-endpoint-id", id)
+		return false, nil
+	}
+
+	if _, err := s.EC2Client.DeleteInstanceConnectEndpointWithContext(context.TODO(), &ec2.DeleteInstanceConnectEndpointInput{
+		InstanceConnectEndpointId: aws.String(id),
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedDeleteInstanceConnectEndpoint", "Failed to delete Instance Connect Endpoint %q: %v", id, err)
+		return false, errors.Wrapf(err, "failed to delete instance connect endpoint %q", id)
+	}
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteInstanceConnectEndpoint", "Deleted Instance Connect Endpoint %q", id)
+	s.scope.Info("Deleted instance connect endpoint", "instance-connect-endpoint-id", id)
+
+	s.scope.InstanceConnectEndpointStatus().State = ec2.Ec2InstanceConnectEndpointStateDeleteInProgress
+	return false, nil
+}
+
+// describeInstanceConnectEndpoint returns the EC2 Instance Connect Endpoint owned by the cluster, which is found by
+// its tags so that an endpoint whose creation wasn't recorded in the status isn't created twice.
+func (s *Service) describeInstanceConnectEndpoint() (*ec2.Ec2InstanceConnectEndpoint, error) {
+	filters := []*ec2.Filter{
+		filter.EC2.ProviderOwned(s.scope.Name()),
+		filter.EC2.ProviderRole(infrav1.InstanceConnectEndpointRoleTagValue),
+		filter.EC2.InstanceConnectEndpointStates(
+			ec2.Ec2InstanceConnectEndpointStateCreateInProgress,
+			ec2.Ec2InstanceConnectEndpointStateCreateComplete,
+			ec2.Ec2InstanceConnectEndpointStateCreateFailed,
+			ec2.Ec2InstanceConnectEndpointStateDeleteInProgress,
+			ec2.Ec2InstanceConnectEndpointStateDeleteFailed,
+		),
+	}
+	if s.scope.VPC().ID != "" {
+		filters = append(filters, filter.EC2.VPC(s.scope.VPC().ID))
+	}
+
+	out, err := s.EC2Client.DescribeInstanceConnectEndpointsWithContext(context.TODO(), &ec2.DescribeInstanceConnectEndpointsInput{
+		Filters: filters,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe instance connect endpoints")
+	}
+	if len(out.InstanceConnectEndpoints) == 0 {
+		return nil, nil
+	}
+	return out.InstanceConnectEndpoints[0], nil
+}
+
+func (s *Service) createInstanceConnectEndpoint(spec *infrav1.InstanceConnectEndpoint) (*ec2.Ec2InstanceConnectEndpoint, error) {
+	subnetID, err := s.instanceConnectEndpointSubnetID(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &ec2.CreateInstanceConnectEndpointInput{
+		SubnetId:         aws.String(subnetID),
+		PreserveClientIp: aws.Bool(spec.PreserveClientIP),
+		TagSpecifications: []*ec2.TagSpecification{
+			tags.BuildParamsToTagSpecification(ec2.ResourceTypeInstanceConnectEndpoint, s.getInstanceConnectEndpointTagParams()),
+		},
+	}
+	// The default security group of the VPC is used when none is given.
+	if len(spec.SecurityGroups) > 0 {
+		input.SecurityGroupIds = aws.StringSlice(spec.SecurityGroups)
+	}
+
+	out, err := s.EC2Client.CreateInstanceConnectEndpointWithContext(context.TODO(), input)
+	if err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedCreateInstanceConnectEndpoint", "Failed to create new Instance Connect Endpoint in subnet %q: %v", subnetID, err)
+		return nil, errors.Wrapf(err, "failed to create instance connect endpoint in subnet %q", subnetID)
+	}
+	id := aws.StringValue(out.InstanceConnectEndpoint.InstanceConnectEndpointId)
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateInstanceConnectEndpoint", "Created new Instance Connect Endpoint %q in subnet %q", id, subnetID)
+	s.scope.Info("Created instance connect endpoint", "instance-connect-endpoint-id", id, "subnet-id", subnetID)
+
+	return out.InstanceConnectEndpoint, nil
+}
+
+// instanceConnectEndpointSubnetID returns the subnet the EC2 Instance Connect Endpoint is created in: the selected
+// subnet, or else the first private subnet of the cluster in the selected availability zone, or in any of them.
+func (s *Service) instanceConnectEndpointSubnetID(spec *infrav1.InstanceConnectEndpoint) (string, error) {
+	selection := spec.SubnetSelection
+	if selection != nil && selection.ID != "" {
+		return selection.ID, nil
+	}
+
+	subnets := s.scope.Subnets().FilterPrivate()
+	if selection != nil && selection.AvailabilityZone != "" {
+		subnets = subnets.FilterByZone(selection.AvailabilityZone)
+	}
+	sort.SliceStable(subnets, func(i, j int) bool {
+		if subnets[i].AvailabilityZone != subnets[j].AvailabilityZone {
+			return subnets[i].AvailabilityZone < subnets[j].AvailabilityZone
+		}
+		return subnets[i].GetResourceID() < subnets[j].GetResourceID()
+	})
+	for i := range subnets {
+		if id := subnets[i].GetResourceID(); id != "" {
+			return id, nil
+		}
+	}
+
+	if selection != nil && selection.AvailabilityZone != "" {
+		return "", errors.Errorf("no private subnet found in availability zone %q for the instance connect endpoint", selection.AvailabilityZone)
+	}
+	return "", errors.New("no private subnet found for the instance connect endpoint")
+}
+
+func (s *Service) setInstanceConnectEndpointStatus(endpoint *ec2.Ec2InstanceConnectEndpoint) {
+	securityGroupIDs := aws.StringValueSlice(endpoint.SecurityGroupIds)
+	sort.Strings(securityGroupIDs)
+	s.scope.SetInstanceConnectEndpointStatus(&infrav1.InstanceConnectEndpointStatus{
+		ID:               aws.StringValue(endpoint.InstanceConnectEndpointId),
+		State:            aws.StringValue(endpoint.State),
+		SubnetID:         aws.StringValue(endpoint.SubnetId),
+		SecurityGroupIDs: securityGroupIDs,
+		DNSName:          aws.StringValue(endpoint.DnsName),
+	})
+}
+
+func (s *Service) getInstanceConnectEndpointTagParams() infrav1.BuildParams {
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(fmt.Sprintf("%s-eice", s.scope.Name())),
+		Role:        aws.String(infrav1.InstanceConnectEndpointRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/test/mocks"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileInstanceConnectEndpoint(t *testing.T) {
+	instanceConnectEndpoint := func(state string) *ec2.Ec2InstanceConnectEndpoint {
+		return &ec2.Ec2InstanceConnectEndpoint{
+			InstanceConnectEndpointId: aws.String("eice-1"),
+			State:                     aws.String(state),
+			SubnetId:                  aws.String("subnet-private-a"),
+			SecurityGroupIds:          aws.StringSlice([]string{"sg-2", "sg-1"}),
+			DnsName:                   aws.String("eice-1.ec2-instance-connect-endpoint.us-east-1.amazonaws.com"),
+		}
+	}
+	expectDescribe := func(m *mocks.MockEC2APIMockRecorder, endpoints ...*ec2.Ec2InstanceConnectEndpoint) {
+		m.DescribeInstanceConnectEndpointsWithContext(gomock.Any(), gomock.AssignableToTypeOf(&ec2.DescribeInstanceConnectEndpointsInput{})).
+			Return(&ec2.DescribeInstanceConnectEndpointsOutput{InstanceConnectEndpoints: endpoints}, nil)
+	}
+	expectCreate := func(m *mocks.MockEC2APIMockRecorder, subnetID string, securityGroupIDs []*string) {
+		m.CreateInstanceConnectEndpointWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ aws.Context, input *ec2.CreateInstanceConnectEndpointInput, _ ...interface{}) (*ec2.CreateInstanceConnectEndpointOutput, error) {
+				if aws.StringValue(input.SubnetId) != subnetID {
+					t.Errorf("expected the endpoint to be created in subnet %q, got %q", subnetID, aws.StringValue(input.SubnetId))
+				}
+				if !gomock.Eq(securityGroupIDs).Matches(input.SecurityGroupIds) {
+					t.Errorf("expected security groups %v, got %v", aws.StringValueSlice(securityGroupIDs), aws.StringValueSlice(input.SecurityGroupIds))
+				}
+				if len(input.TagSpecifications) != 1 || aws.StringValue(input.TagSpecifications[0].ResourceType) != ec2.ResourceTypeInstanceConnectEndpoint {
+					t.Errorf("expected the endpoint to be tagged, got %v", input.TagSpecifications)
+				}
+				endpoint := instanceConnectEndpoint(ec2.Ec2InstanceConnectEndpointStateCreateInProgress)
+				endpoint.SubnetId = input.SubnetId
+				return &ec2.CreateInstanceConnectEndpointOutput{InstanceConnectEndpoint: endpoint}, nil
+			})
+	}
+
+	testCases := []struct {
+		name              string
+		spec              *infrav1.InstanceConnectEndpoint
+		status            *infrav1.InstanceConnectEndpointStatus
+		expect            func(m *mocks.MockEC2APIMockRecorder)
+		expectedCondition *clusterv1.Condition
+		expectedStatus    *infrav1.InstanceConnectEndpointStatus
+	}{
+		{
+			name: "disabled",
+			spec: &infrav1.InstanceConnectEndpoint{Enabled: false},
+		},
+		{
+			name: "creates the endpoint in the first private subnet",
+			spec: &infrav1.InstanceConnectEndpoint{Enabled: true},
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				expectDescribe(m)
+				expectCreate(m, "subnet-private-a", nil)
+			},
+			expectedCondition: &clusterv1.Condition{Status: corev1.ConditionFalse, Severity: clusterv1.ConditionSeverityInfo, Reason: infrav1.InstanceConnectEndpointCreatingReason},
+			expectedStatus: &infrav1.InstanceConnectEndpointStatus{
+				ID:               "eice-1",
+				State:            ec2.Ec2InstanceConnectEndpointStateCreateInProgress,
+				SubnetID:         "subnet-private-a",
+				SecurityGroupIDs: []string{"sg-1", "sg-2"},
+				DNSName:          "eice-1.ec2-instance-connect-endpoint.us-east-1.amazonaws.com",
+			},
+		},
+		{
+			name: "creates the endpoint in the selected availability zone with the security groups",
+			spec: &infrav1.InstanceConnectEndpoint{
+				Enabled:         true,
+				SubnetSelection: &infrav1.InstanceConnectEndpointSubnetSelection{AvailabilityZone: "us-east-1b"},
+				SecurityGroups:  []string{"sg-1", "sg-2"},
+			},
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				expectDescribe(m)
+				expectCreate(m, "subnet-private-b", aws.StringSlice([]string{"sg-1", "sg-2"}))
+			},
+			expectedCondition: &clusterv1.Condition{Status: corev1.ConditionFalse, Severity: clusterv1.ConditionSeverityInfo, Reason: infrav1.InstanceConnectEndpointCreatingReason},
+			expectedStatus: &infrav1.InstanceConnectEndpointStatus{
+				ID:               "eice-1",
+				State:            ec2.Ec2InstanceConnectEndpointStateCreateInProgress,
+				SubnetID:         "subnet-private-b",
+				SecurityGroupIDs: []string{"sg-1", "sg-2"},
+				DNSName:          "eice-1.ec2-instance-connect-endpoint.us-east-1.amazonaws.com",
+			},
+		},
+		{
+			name: "existing endpoint is ready",
+			spec: &infrav1.InstanceConnectEndpoint{Enabled: true},
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				expectDescribe(m, instanceConnectEndpoint(ec2.Ec2InstanceConnectEndpointStateCreateComplete))
+			},
+			expectedCondition: &clusterv1.Condition{Status: corev1.ConditionTrue},
+			expectedStatus: &infrav1.InstanceConnectEndpointStatus{
+				ID:               "eice-1",
+				State:            ec2.Ec2InstanceConnectEndpointStateCreateComplete,
+				SubnetID:         "subnet-private-a",
+				SecurityGroupIDs: []string{"sg-1", "sg-2"},
+				DNSName:          "eice-1.ec2-instance-connect-endpoint.us-east-1.amazonaws.com",
+			},
+		},
+		{
+			name: "existing endpoint failed to be created",
+			spec: &infrav1.InstanceConnectEndpoint{Enabled: true},
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				endpoint := instanceConnectEndpoint(ec2.Ec2InstanceConnectEndpointStateCreateFailed)
+				endpoint.StateMessage = aws.String("quota exceeded")
+				expectDescribe(m, endpoint)
+			},
+			expectedCondition: &clusterv1.Condition{
+				Status:   corev1.ConditionFalse,
+				Severity: clusterv1.ConditionSeverityError,
+				Reason:   infrav1.InstanceConnectEndpointFailedReason,
+				Message:  "instance connect endpoint eice-1 is create-failed: quota exceeded",
+			},
+			expectedStatus: &infrav1.InstanceConnectEndpointStatus{
+				ID:               "eice-1",
+				State:            ec2.Ec2InstanceConnectEndpointStateCreateFailed,
+				SubnetID:         "subnet-private-a",
+				SecurityGroupIDs: []string{"sg-1", "sg-2"},
+				DNSName:          "eice-1.ec2-instance-connect-endpoint.us-east-1.amazonaws.com",
+			},
+		},
+		{
+			name:   "deletes the endpoint once disabled",
+			spec:   &infrav1.InstanceConnectEndpoint{Enabled: false},
+			status: &infrav1.InstanceConnectEndpointStatus{ID: "eice-1", State: ec2.Ec2InstanceConnectEndpointStateCreateComplete},
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				expectDescribe(m, instanceConnectEndpoint(ec2.Ec2InstanceConnectEndpointStateCreateComplete))
+				m.DeleteInstanceConnectEndpointWithContext(gomock.Any(), &ec2.DeleteInstanceConnectEndpointInput{InstanceConnectEndpointId: aws.String("eice-1")}).
+					Return(&ec2.DeleteInstanceConnectEndpointOutput{}, nil)
+			},
+			expectedCondition: &clusterv1.Condition{Status: corev1.ConditionFalse, Severity: clusterv1.ConditionSeverityInfo, Reason: infrav1.InstanceConnectEndpointDeletingReason},
+			expectedStatus: &infrav1.InstanceConnectEndpointStatus{
+				ID:               "eice-1",
+				State:            ec2.Ec2InstanceConnectEndpointStateDeleteInProgress,
+				SubnetID:         "subnet-private-a",
+				SecurityGroupIDs: []string{"sg-1", "sg-2"},
+				DNSName:          "eice-1.ec2-instance-connect-endpoint.us-east-1.amazonaws.com",
+			},
+		},
+		{
+			name:   "endpoint is deleted",
+			spec:   &infrav1.InstanceConnectEndpoint{Enabled: false},
+			status: &infrav1.InstanceConnectEndpointStatus{ID: "eice-1", State: ec2.Ec2InstanceConnectEndpointStateDeleteInProgress},
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				expectDescribe(m)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			ec2Mock := mocks.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			awsCluster := &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						VPC: infrav1.VPCSpec{
+							ID: subnetsVPCID,
+							Tags: infrav1.Tags{
+								infrav1.ClusterTagKey("test-cluster"): "owned",
+							},
+						},
+						Subnets: []infrav1.SubnetSpec{
+							{ID: "subnet-public-a", AvailabilityZone: "us-east-1a", CidrBlock: "10.0.10.0/24", IsPublic: true},
+							{ID: "subnet-private-b", AvailabilityZone: "us-east-1b", CidrBlock: "10.0.13.0/24"},
+							{ID: "subnet-private-a", AvailabilityZone: "us-east-1a", CidrBlock: "10.0.11.0/24"},
+							{ID: "subnet-public-b", AvailabilityZone: "us-east-1b", CidrBlock: "10.0.12.0/24", IsPublic: true},
+						},
+					},
+					InstanceConnectEndpoint: tc.spec,
+				},
+				Status: infrav1.AWSClusterStatus{
+					InstanceConnectEndpoint: tc.status,
+				},
+			}
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(awsCluster).WithStatusSubresource(awsCluster).Build()
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: awsCluster,
+				Client:     client,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			if tc.expect != nil {
+				tc.expect(ec2Mock.EXPECT())
+			}
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+
+			g.Expect(s.reconcileInstanceConnectEndpoint()).To(Succeed())
+
+			condition := conditions.Get(awsCluster, infrav1.InstanceConnectEndpointReadyCondition)
+			if tc.expectedCondition == nil {
+				g.Expect(condition).To(BeNil())
+			} else {
+				g.Expect(condition).NotTo(BeNil())
+				g.Expect(condition.Status).To(Equal(tc.expectedCondition.Status))
+				g.Expect(condition.Severity).To(Equal(tc.expectedCondition.Severity))
+				g.Expect(condition.Reason).To(Equal(tc.expectedCondition.Reason))
+				g.Expect(condition.Message).To(Equal(tc.expectedCondition.Message))
+			}
+			g.Expect(awsCluster.Status.InstanceConnectEndpoint).To(Equal(tc.expectedStatus))
+		})
+	}
+}
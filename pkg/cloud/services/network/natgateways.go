@@ -19,11 +19,14 @@ package network
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/pkg/errors"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
@@ -127,9 +130,19 @@ func (s *Service) reconcileNatGateways() error {
 			return err
 		}
 		conditions.MarkTrue(s.scope.InfraCluster(), infrav1.NatGatewaysReadyCondition)
+		for _, ng := range ngws {
+			existing[*ng.SubnetId] = ng
+		}
 	}
 
-	return nil
+	// The Elastic IPs of replaced NAT gateways would otherwise count against the quota of the account forever.
+	used := sets.New[string]()
+	for _, ngw := range existing {
+		for _, address := range ngw.NatGatewayAddresses {
+			used.Insert(aws.StringValue(address.AllocationId))
+		}
+	}
+	return s.releaseUnusedAddresses(used)
 }
 
 func (s *Service) deleteNatGateways() error {
@@ -220,32 +233,28 @@ func (s *Service) getNatGatewayTagParams(id string) infrav1.BuildParams {
 	}
 }
 
+// createNatGateways creates the NAT gateways of the public subnets concurrently, since each of them takes minutes
+// to become available. The NAT gateways which were created are returned along with the errors of the others, so
+// that they are recorded, and the NAT gateways of an interrupted reconciliation are found by their subnet.
 func (s *Service) createNatGateways(subnetIDs []string) (natgateways []*ec2.NatGateway, err error) {
 	eips, err := s.getOrAllocateAddresses(len(subnetIDs), infrav1.APIServerRoleTagValue)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create one or more IP addresses for NAT gateways")
 	}
-	type ngwCreation struct {
-		natGateway *ec2.NatGateway
-		error      error
-	}
-	c := make(chan ngwCreation, len(subnetIDs))
 
-	for i, sn := range subnetIDs {
-		go func(c chan ngwCreation, subnetID, ip string) {
-			ngw, err := s.createNatGateway(subnetID, ip)
-			c <- ngwCreation{natGateway: ngw, error: err}
-		}(c, sn, eips[i])
-	}
+	created := make([]*ec2.NatGateway, len(subnetIDs))
+	err = forEachConcurrently(len(subnetIDs), func(i int) error {
+		ngw, err := s.createNatGateway(subnetIDs[i], eips[i])
+		created[i] = ngw
+		return err
+	})
 
-	for i := 0; i < len(subnetIDs); i++ {
-		ngwResult := <-c
-		if ngwResult.error != nil {
-			return nil, err
+	for _, ngw := range created {
+		if ngw != nil {
+			natgateways = append(natgateways, ngw)
 		}
-		natgateways = append(natgateways, ngwResult.natGateway)
 	}
-	return natgateways, nil
+	return natgateways, err
 }
 
 func (s *Service) createNatGateway(subnetID, ip string) (*ec2.NatGateway, error) {
@@ -326,6 +335,12 @@ func (s *Service) getNatGatewayForSubnet(sn *infrav1.SubnetSpec) (string, error)
 		return "", errors.Errorf("cannot get NAT gateway for a public subnet, got id %q", sn.GetResourceID())
 	}
 
+	// The egress traffic of an availability zone whose NAT gateways are degraded goes through the fallback
+	// NAT gateway, including while a NAT gateway replacing them isn't available yet.
+	if fallback, ok := s.natGatewayFailovers[sn.AvailabilityZone]; ok {
+		return fallback, nil
+	}
+
 	azGateways := make(map[string][]string)
 	for _, psn := range s.scope.Subnets().FilterPublic() {
 		if psn.NatGatewayID == nil {
@@ -341,3 +356,171 @@ func (s *Service) getNatGatewayForSubnet(sn *infrav1.SubnetSpec) (string, error)
 
 	return "", errors.Errorf("no nat gateways available in %q for private subnet %q, current state: %+v", sn.AvailabilityZone, sn.GetResourceID(), azGateways)
 }
+
+// natGatewayZoneHealth is the health of the NAT gateways of the public subnets of an availability zone.
+type natGatewayZoneHealth struct {
+	// available is the ID of an available NAT gateway of the availability zone, if any.
+	available string
+	// degraded are the NAT gateways of the availability zone which are no longer available, with their state.
+	degraded []string
+}
+
+// reconcileNatGatewaysHealth reports the availability zones whose NAT gateways are no longer available,
+// e.g. because they failed or were deleted, and when failover is enabled routes the egress traffic of
+// their private subnets through an available NAT gateway of another availability zone until they recover.
+// It runs before the NAT gateways are reconciled, as replacing a NAT gateway blocks until it's available.
+func (s *Service) reconcileNatGatewaysHealth() error {
+	natGatewaySpec := s.scope.VPC().NATGateway
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) || !natGatewaySpec.IsHealthCheckEnabled() {
+		conditions.Delete(s.scope.InfraCluster(), infrav1.NatGatewaysHealthyCondition)
+		return nil
+	}
+
+	s.scope.Debug("Checking health of NAT gateways")
+
+	zones, natGatewayZones, err := s.describeNatGatewaysHealthByZone()
+	if err != nil {
+		return err
+	}
+
+	var degradedZones, healthyZones, messages []string
+	for zone, health := range zones {
+		switch {
+		case health.available != "":
+			healthyZones = append(healthyZones, zone)
+		case len(health.degraded) > 0:
+			degradedZones = append(degradedZones, zone)
+		}
+	}
+	sort.Strings(degradedZones)
+	sort.Strings(healthyZones)
+
+	if len(degradedZones) == 0 {
+		conditions.MarkTrue(s.scope.InfraCluster(), infrav1.NatGatewaysHealthyCondition)
+	} else {
+		for _, zone := range degradedZones {
+			messages = append(messages, fmt.Sprintf("%s (%s)", zone, strings.Join(zones[zone].degraded, ", ")))
+		}
+		conditions.MarkFalse(
+			s.scope.InfraCluster(),
+			infrav1.NatGatewaysHealthyCondition,
+			infrav1.NatGatewaysDegradedReason,
+			clusterv1.ConditionSeverityWarning,
+			"NAT gateways are degraded in availability zones %s", strings.Join(messages, "; "))
+	}
+
+	if !natGatewaySpec.IsFailoverEnabled() {
+		return nil
+	}
+
+	s.natGatewayFailovers = make(map[string]string)
+	if len(degradedZones) > 0 {
+		if len(healthyZones) == 0 {
+			record.Warnf(s.scope.InfraCluster(), "FailedNATGatewayFailover", "No available NAT gateway to route the egress traffic of availability zones %v through", degradedZones)
+		} else {
+			for _, zone := range degradedZones {
+				s.natGatewayFailovers[zone] = zones[healthyZones[0]].available
+			}
+		}
+	}
+
+	return s.reconcileNatGatewayFailoverRoutes(zones, natGatewayZones)
+}
+
+// reconcileNatGatewayFailoverRoutes routes the egress traffic of the private subnets of the availability
+// zones in failover through their fallback NAT gateway, and routes the one of the other private subnets
+// which is still routed through the NAT gateway of another availability zone back to their own.
+func (s *Service) reconcileNatGatewayFailoverRoutes(zones map[string]*natGatewayZoneHealth, natGatewayZones map[string]string) error {
+	rts, err := s.describeVpcRouteTables()
+	if err != nil {
+		return err
+	}
+	subnetRouteMap := routeTablesBySubnet(rts)
+
+	for _, sn := range s.scope.Subnets().FilterPrivate() {
+		rt, ok := subnetRouteMap[sn.GetResourceID()]
+		if !ok {
+			continue
+		}
+		current := natGatewayOfDefaultRoute(rt)
+
+		if fallback, ok := s.natGatewayFailovers[sn.AvailabilityZone]; ok {
+			if current == fallback {
+				continue
+			}
+			if err := s.reconcileRoutes([]*ec2.Route{s.getNatGatewayPrivateRoute(fallback)}, rt); err != nil {
+				return err
+			}
+			record.Warnf(s.scope.InfraCluster(), "NATGatewayFailover", "Routed egress traffic of subnet %q through NAT gateway %q while the NAT gateways of availability zone %q are degraded", sn.GetResourceID(), fallback, sn.AvailabilityZone)
+			continue
+		}
+
+		health, ok := zones[sn.AvailabilityZone]
+		if !ok || health.available == "" || current == "" || current == health.available {
+			continue
+		}
+		if zone, ok := natGatewayZones[current]; !ok || zone == sn.AvailabilityZone {
+			continue
+		}
+		if err := s.reconcileRoutes([]*ec2.Route{s.getNatGatewayPrivateRoute(health.available)}, rt); err != nil {
+			return err
+		}
+		record.Eventf(s.scope.InfraCluster(), "NATGatewayFailback", "Routed egress traffic of subnet %q back through NAT gateway %q of availability zone %q", sn.GetResourceID(), health.available, sn.AvailabilityZone)
+	}
+
+	return nil
+}
+
+// describeNatGatewaysHealthByZone returns the health of the NAT gateways of the public subnets by
+// availability zone, and the availability zones of these NAT gateways by their IDs. Unlike
+// describeNatGatewaysBySubnet, NAT gateways in all states are returned.
+func (s *Service) describeNatGatewaysHealthByZone() (map[string]*natGatewayZoneHealth, map[string]string, error) {
+	bySubnet := make(map[string][]*ec2.NatGateway)
+	err := s.EC2Client.DescribeNatGatewaysPagesWithContext(context.TODO(), &ec2.DescribeNatGatewaysInput{
+		Filter: []*ec2.Filter{filter.EC2.VPC(s.scope.VPC().ID)},
+	}, func(page *ec2.DescribeNatGatewaysOutput, lastPage bool) bool {
+		for _, ngw := range page.NatGateways {
+			bySubnet[aws.StringValue(ngw.SubnetId)] = append(bySubnet[aws.StringValue(ngw.SubnetId)], ngw)
+		}
+		return !lastPage
+	})
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeNATGateways", "Failed to describe NAT gateways with VPC ID %q: %v", s.scope.VPC().ID, err)
+		return nil, nil, errors.Wrapf(err, "failed to describe NAT gateways with VPC ID %q", s.scope.VPC().ID)
+	}
+
+	zones := make(map[string]*natGatewayZoneHealth)
+	natGatewayZones := make(map[string]string)
+	for _, sn := range s.scope.Subnets().FilterPublic() {
+		health, ok := zones[sn.AvailabilityZone]
+		if !ok {
+			health = &natGatewayZoneHealth{}
+			zones[sn.AvailabilityZone] = health
+		}
+		for _, ngw := range bySubnet[sn.GetResourceID()] {
+			id, state := aws.StringValue(ngw.NatGatewayId), aws.StringValue(ngw.State)
+			natGatewayZones[id] = sn.AvailabilityZone
+			switch state {
+			case ec2.NatGatewayStateAvailable:
+				if health.available == "" {
+					health.available = id
+				}
+			case ec2.NatGatewayStateFailed, ec2.NatGatewayStateDeleting, ec2.NatGatewayStateDeleted:
+				health.degraded = append(health.degraded, fmt.Sprintf("%s %s", id, state))
+			}
+		}
+	}
+
+	return zones, natGatewayZones, nil
+}
+
+// natGatewayOfDefaultRoute returns the ID of the NAT gateway the IPv4 default route of a route table
+// routes to, if any.
+func natGatewayOfDefaultRoute(rt *ec2.RouteTable) string {
+	for _, route := range rt.Routes {
+		if aws.StringValue(route.DestinationCidrBlock) == services.AnyIPv4CidrBlock {
+			return aws.StringValue(route.NatGatewayId)
+		}
+	}
+	return ""
+}
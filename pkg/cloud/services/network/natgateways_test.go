@@ -18,9 +18,12 @@ package network
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/golang/mock/gomock"
@@ -34,6 +37,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/test/mocks"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 const (
@@ -110,10 +114,11 @@ func TestReconcileNatGateways(t *testing.T) {
 					}),
 					gomock.Any()).Return(nil)
 
+				// The addresses are described to allocate the Elastic IPs, then to release the unused ones.
 				m.DescribeAddressesWithContext(context.TODO(), gomock.Any()).
-					Return(&ec2.DescribeAddressesOutput{}, nil)
+					Return(&ec2.DescribeAddressesOutput{}, nil).Times(2)
 
-				m.AllocateAddressWithContext(context.TODO(), &ec2.AllocateAddressInput{
+				m.AllocateAddressWithContext(context.TODO(), allocateAddressInputMatcher{&ec2.AllocateAddressInput{
 					Domain: aws.String("vpc"),
 					TagSpecifications: []*ec2.TagSpecification{
 						{
@@ -134,7 +139,7 @@ func TestReconcileNatGateways(t *testing.T) {
 							},
 						},
 					},
-				}).Return(&ec2.AllocateAddressOutput{
+				}}).Return(&ec2.AllocateAddressOutput{
 					AllocationId: aws.String(ElasticIPAllocationID),
 				}, nil)
 
@@ -217,10 +222,11 @@ func TestReconcileNatGateways(t *testing.T) {
 					}}}, true)
 				}).Return(nil)
 
+				// The addresses are described to allocate the Elastic IPs, then to release the unused ones.
 				m.DescribeAddressesWithContext(context.TODO(), gomock.Any()).
-					Return(&ec2.DescribeAddressesOutput{}, nil)
+					Return(&ec2.DescribeAddressesOutput{}, nil).Times(2)
 
-				m.AllocateAddressWithContext(context.TODO(), &ec2.AllocateAddressInput{
+				m.AllocateAddressWithContext(context.TODO(), allocateAddressInputMatcher{&ec2.AllocateAddressInput{
 					Domain: aws.String("vpc"),
 					TagSpecifications: []*ec2.TagSpecification{
 						{
@@ -241,7 +247,7 @@ func TestReconcileNatGateways(t *testing.T) {
 							},
 						},
 					},
-				}).Return(&ec2.AllocateAddressOutput{
+				}}).Return(&ec2.AllocateAddressOutput{
 					AllocationId: aws.String(ElasticIPAllocationID),
 				}, nil)
 
@@ -334,7 +340,8 @@ func TestReconcileNatGateways(t *testing.T) {
 					}}}, true)
 				}).Return(nil)
 
-				m.DescribeAddressesWithContext(context.TODO(), gomock.Any()).Times(0)
+				m.DescribeAddressesWithContext(context.TODO(), gomock.Any()).
+					Return(&ec2.DescribeAddressesOutput{}, nil)
 				m.AllocateAddressWithContext(context.TODO(), gomock.Any()).Times(0)
 				m.CreateNatGatewayWithContext(context.TODO(), gomock.Any()).Times(0)
 			},
@@ -359,6 +366,8 @@ func TestReconcileNatGateways(t *testing.T) {
 				m.DescribeNatGatewaysPagesWithContext(context.TODO(), gomock.Any(), gomock.Any()).
 					Return(nil).
 					Times(1)
+				m.DescribeAddressesWithContext(context.TODO(), gomock.Any()).
+					Return(&ec2.DescribeAddressesOutput{}, nil)
 			},
 		},
 	}
@@ -407,6 +416,246 @@ func TestReconcileNatGateways(t *testing.T) {
 	}
 }
 
+func TestReconcileNatGatewaysReleasesUnusedAddresses(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ec2Mock := mocks.NewMockEC2API(mockCtrl)
+
+	eipTags := func(lifecycle string, allocatedAt time.Duration) []*ec2.Tag {
+		tags := []*ec2.Tag{
+			{Key: aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"), Value: aws.String(lifecycle)},
+			{Key: aws.String("sigs.k8s.io/cluster-api-provider-aws/role"), Value: aws.String("apiserver")},
+		}
+		if allocatedAt != 0 {
+			tags = append(tags, &ec2.Tag{
+				Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/allocated-at"),
+				Value: aws.String(time.Now().Add(-allocatedAt).UTC().Format(time.RFC3339)),
+			})
+		}
+		return tags
+	}
+
+	// The NAT gateway of subnet-1 was replaced: the Elastic IP of the previous NAT gateway is no longer associated.
+	ec2Mock.EXPECT().DescribeNatGatewaysPagesWithContext(context.TODO(), gomock.Any(), gomock.Any()).
+		Do(func(ctx context.Context, _, y interface{}, requestOptions ...request.Option) {
+			funct := y.(func(page *ec2.DescribeNatGatewaysOutput, lastPage bool) bool)
+			funct(&ec2.DescribeNatGatewaysOutput{NatGateways: []*ec2.NatGateway{
+				{
+					NatGatewayId:        aws.String("nat-replacement"),
+					SubnetId:            aws.String("subnet-1"),
+					NatGatewayAddresses: []*ec2.NatGatewayAddress{{AllocationId: aws.String("eipalloc-replacement")}},
+				},
+				{
+					NatGatewayId:        aws.String("nat-pending"),
+					SubnetId:            aws.String("subnet-3"),
+					State:               aws.String(ec2.NatGatewayStatePending),
+					NatGatewayAddresses: []*ec2.NatGatewayAddress{{AllocationId: aws.String("eipalloc-pending")}},
+				},
+			}}, true)
+		}).Return(nil)
+	ec2Mock.EXPECT().CreateTagsWithContext(context.TODO(), gomock.Any()).Return(nil, nil).AnyTimes()
+	ec2Mock.EXPECT().DescribeAddressesWithContext(context.TODO(), gomock.Any()).Return(&ec2.DescribeAddressesOutput{
+		Addresses: []*ec2.Address{
+			{
+				AllocationId:  aws.String("eipalloc-replacement"),
+				AssociationId: aws.String("eipassoc-replacement"),
+				Tags:          eipTags("owned", time.Minute),
+			},
+			{
+				AllocationId: aws.String("eipalloc-pending"),
+				Tags:         eipTags("owned", time.Minute),
+			},
+			{
+				AllocationId: aws.String("eipalloc-previous"),
+				Tags:         eipTags("owned", eipReleaseGracePeriod+time.Minute),
+			},
+			{
+				AllocationId: aws.String("eipalloc-untagged-allocation-time"),
+				Tags:         eipTags("owned", 0),
+			},
+			{
+				AllocationId: aws.String("eipalloc-within-grace-period"),
+				Tags:         eipTags("owned", eipReleaseGracePeriod-time.Minute),
+			},
+			{
+				AllocationId: aws.String("eipalloc-shared"),
+				Tags:         eipTags("shared", eipReleaseGracePeriod+time.Minute),
+			},
+		},
+	}, nil)
+	ec2Mock.EXPECT().ReleaseAddressWithContext(context.TODO(), &ec2.ReleaseAddressInput{AllocationId: aws.String("eipalloc-previous")}).Return(nil, nil)
+	ec2Mock.EXPECT().ReleaseAddressWithContext(context.TODO(), &ec2.ReleaseAddressInput{AllocationId: aws.String("eipalloc-untagged-allocation-time")}).
+		Return(nil, awserr.New(awserrors.AllocationIDNotFound, "already released", nil))
+
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: infrav1.AWSClusterSpec{
+			NetworkSpec: infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID: subnetsVPCID,
+					Tags: infrav1.Tags{
+						infrav1.ClusterTagKey("test-cluster"): "owned",
+					},
+				},
+				Subnets: []infrav1.SubnetSpec{
+					{ID: "subnet-1", AvailabilityZone: "us-east-1a", CidrBlock: "10.0.10.0/24", IsPublic: true},
+					{ID: "subnet-2", AvailabilityZone: "us-east-1a", CidrBlock: "10.0.12.0/24", IsPublic: false},
+					{ID: "subnet-3", AvailabilityZone: "us-east-1b", CidrBlock: "10.0.14.0/24", IsPublic: true},
+				},
+			},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(awsCluster).WithStatusSubresource(awsCluster).Build()
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: awsCluster,
+		Client:     client,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	s := NewService(clusterScope)
+	s.EC2Client = ec2Mock
+
+	g.Expect(s.reconcileNatGateways()).To(Succeed())
+}
+
+func TestReconcileNatGatewaysPartialFailure(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ec2Mock := mocks.NewMockEC2API(mockCtrl)
+
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: infrav1.AWSClusterSpec{
+			NetworkSpec: infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID: subnetsVPCID,
+					Tags: infrav1.Tags{
+						infrav1.ClusterTagKey("test-cluster"): "owned",
+					},
+				},
+				Subnets: []infrav1.SubnetSpec{
+					{ID: "subnet-1", AvailabilityZone: "us-east-1a", CidrBlock: "10.0.10.0/24", IsPublic: true},
+					{ID: "subnet-2", AvailabilityZone: "us-east-1a", CidrBlock: "10.0.12.0/24", IsPublic: false},
+					{ID: "subnet-3", AvailabilityZone: "us-east-1b", CidrBlock: "10.0.14.0/24", IsPublic: true},
+					{ID: "subnet-4", AvailabilityZone: "us-east-1b", CidrBlock: "10.0.16.0/24", IsPublic: false},
+					{ID: "subnet-5", AvailabilityZone: "us-east-1c", CidrBlock: "10.0.18.0/24", IsPublic: true},
+					{ID: "subnet-6", AvailabilityZone: "us-east-1c", CidrBlock: "10.0.20.0/24", IsPublic: false},
+				},
+			},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(awsCluster).WithStatusSubresource(awsCluster).Build()
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: awsCluster,
+		Client:     client,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	s := NewService(clusterScope)
+	s.EC2Client = ec2Mock
+
+	natGateway := func(subnetID string) *ec2.NatGateway {
+		return &ec2.NatGateway{
+			NatGatewayId:        aws.String("nat-" + subnetID),
+			SubnetId:            aws.String(subnetID),
+			NatGatewayAddresses: []*ec2.NatGatewayAddress{{AllocationId: aws.String("eipalloc-" + subnetID)}},
+		}
+	}
+	describeNatGateways := func(natGateways ...*ec2.NatGateway) {
+		ec2Mock.EXPECT().DescribeNatGatewaysPagesWithContext(context.TODO(), gomock.Any(), gomock.Any()).
+			Do(func(ctx context.Context, _, y interface{}, requestOptions ...request.Option) {
+				funct := y.(func(page *ec2.DescribeNatGatewaysOutput, lastPage bool) bool)
+				funct(&ec2.DescribeNatGatewaysOutput{NatGateways: natGateways}, true)
+			}).Return(nil)
+	}
+	// The Elastic IPs aren't owned by the cluster, so that none of them is released.
+	ec2Mock.EXPECT().DescribeAddressesWithContext(context.TODO(), gomock.Any()).Return(&ec2.DescribeAddressesOutput{
+		Addresses: []*ec2.Address{
+			{AllocationId: aws.String("eipalloc-subnet-1")},
+			{AllocationId: aws.String("eipalloc-subnet-3")},
+			{AllocationId: aws.String("eipalloc-subnet-5")},
+		},
+	}, nil).AnyTimes()
+	ec2Mock.EXPECT().CreateTagsWithContext(context.TODO(), gomock.Any()).Return(nil, nil).AnyTimes()
+	ec2Mock.EXPECT().WaitUntilNatGatewayAvailableWithContext(context.TODO(), gomock.Any()).Return(nil).AnyTimes()
+
+	// The NAT gateways of the other availability zones are created even though creating the NAT gateway of
+	// us-east-1b fails.
+	describeNatGateways()
+	ec2Mock.EXPECT().CreateNatGatewayWithContext(context.TODO(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *ec2.CreateNatGatewayInput, _ ...request.Option) (*ec2.CreateNatGatewayOutput, error) {
+			if aws.StringValue(input.SubnetId) == "subnet-3" {
+				return nil, awserr.New("NatGatewayLimitExceeded", "limit exceeded", nil)
+			}
+			return &ec2.CreateNatGatewayOutput{NatGateway: natGateway(aws.StringValue(input.SubnetId))}, nil
+		}).Times(3)
+
+	err = s.reconcileNatGateways()
+	g.Expect(err).To(MatchError(ContainSubstring(`failed to create NAT gateway for subnet ID "subnet-3"`)))
+	g.Expect(s.scope.Subnets().FindByID("subnet-1").NatGatewayID).To(Equal(aws.String("nat-subnet-1")))
+	g.Expect(s.scope.Subnets().FindByID("subnet-3").NatGatewayID).To(BeNil())
+	g.Expect(s.scope.Subnets().FindByID("subnet-5").NatGatewayID).To(Equal(aws.String("nat-subnet-5")))
+	g.Expect(conditions.IsFalse(awsCluster, infrav1.NatGatewaysReadyCondition)).To(BeTrue())
+
+	// The next reconciliation only creates the missing NAT gateway.
+	describeNatGateways(natGateway("subnet-1"), natGateway("subnet-5"))
+	ec2Mock.EXPECT().CreateNatGatewayWithContext(context.TODO(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *ec2.CreateNatGatewayInput, _ ...request.Option) (*ec2.CreateNatGatewayOutput, error) {
+			g.Expect(input.SubnetId).To(Equal(aws.String("subnet-3")))
+			return &ec2.CreateNatGatewayOutput{NatGateway: natGateway("subnet-3")}, nil
+		})
+
+	g.Expect(s.reconcileNatGateways()).To(Succeed())
+	g.Expect(s.scope.Subnets().FindByID("subnet-3").NatGatewayID).To(Equal(aws.String("nat-subnet-3")))
+	g.Expect(conditions.IsTrue(awsCluster, infrav1.NatGatewaysReadyCondition)).To(BeTrue())
+}
+
+// allocateAddressInputMatcher matches an AllocateAddressInput regardless of the time at which the
+// Elastic IP is allocated, which only has to be tagged in RFC 3339 format.
+type allocateAddressInputMatcher struct {
+	expected *ec2.AllocateAddressInput
+}
+
+func (m allocateAddressInputMatcher) Matches(x interface{}) bool {
+	input, ok := x.(*ec2.AllocateAddressInput)
+	if !ok {
+		return false
+	}
+
+	actual := &ec2.AllocateAddressInput{Domain: input.Domain}
+	for _, spec := range input.TagSpecifications {
+		actualSpec := &ec2.TagSpecification{ResourceType: spec.ResourceType}
+		for _, tag := range spec.Tags {
+			if aws.StringValue(tag.Key) == infrav1.ElasticIPAllocatedAtTagKey {
+				if _, err := time.Parse(time.RFC3339, aws.StringValue(tag.Value)); err != nil {
+					return false
+				}
+				continue
+			}
+			actualSpec.Tags = append(actualSpec.Tags, tag)
+		}
+		actual.TagSpecifications = append(actual.TagSpecifications, actualSpec)
+	}
+
+	return gomock.Eq(m.expected).Matches(actual)
+}
+
+func (m allocateAddressInputMatcher) String() string {
+	return fmt.Sprintf("is equal to %v with an allocation time tag", m.expected)
+}
+
 func TestDeleteNatGateways(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -728,3 +977,228 @@ var mockDescribeNatGatewaysOutput = func(ctx context.Context, _, y interface{},
 		SubnetId:     aws.String("subnet-1"),
 	}}}, true)
 }
+
+func TestReconcileNatGatewaysHealth(t *testing.T) {
+	natGateway := func(id, subnetID, state string) *ec2.NatGateway {
+		return &ec2.NatGateway{NatGatewayId: aws.String(id), SubnetId: aws.String(subnetID), State: aws.String(state)}
+	}
+	routeTable := func(id, subnetID, natGatewayID string) *ec2.RouteTable {
+		return &ec2.RouteTable{
+			RouteTableId: aws.String(id),
+			Associations: []*ec2.RouteTableAssociation{{SubnetId: aws.String(subnetID), RouteTableId: aws.String(id)}},
+			Routes: []*ec2.Route{{
+				DestinationCidrBlock: aws.String("0.0.0.0/0"),
+				NatGatewayId:         aws.String(natGatewayID),
+			}},
+		}
+	}
+	expectNatGateways := func(m *mocks.MockEC2APIMockRecorder, natGateways ...*ec2.NatGateway) {
+		m.DescribeNatGatewaysPagesWithContext(context.TODO(), &ec2.DescribeNatGatewaysInput{
+			Filter: []*ec2.Filter{{Name: aws.String("vpc-id"), Values: aws.StringSlice([]string{subnetsVPCID})}},
+		}, gomock.Any()).Do(func(ctx context.Context, _, y interface{}, requestOptions ...request.Option) {
+			funct := y.(func(page *ec2.DescribeNatGatewaysOutput, lastPage bool) bool)
+			funct(&ec2.DescribeNatGatewaysOutput{NatGateways: natGateways}, true)
+		}).Return(nil)
+	}
+	expectRouteTables := func(m *mocks.MockEC2APIMockRecorder, routeTables ...*ec2.RouteTable) {
+		m.DescribeRouteTablesWithContext(context.TODO(), gomock.Any()).
+			Return(&ec2.DescribeRouteTablesOutput{RouteTables: routeTables}, nil)
+	}
+	expectReplaceRoute := func(m *mocks.MockEC2APIMockRecorder, routeTableID, natGatewayID string) {
+		m.ReplaceRouteWithContext(context.TODO(), &ec2.ReplaceRouteInput{
+			RouteTableId:         aws.String(routeTableID),
+			DestinationCidrBlock: aws.String("0.0.0.0/0"),
+			NatGatewayId:         aws.String(natGatewayID),
+		}).Return(nil, nil)
+	}
+
+	testCases := []struct {
+		name              string
+		natGatewaySpec    *infrav1.NATGatewaySpec
+		expect            func(m *mocks.MockEC2APIMockRecorder)
+		expectedCondition *clusterv1.Condition
+		expectedFailovers map[string]string
+	}{
+		{
+			name:           "health check disabled",
+			natGatewaySpec: nil,
+			expect:         func(m *mocks.MockEC2APIMockRecorder) {},
+		},
+		{
+			name:           "healthy NAT gateways",
+			natGatewaySpec: &infrav1.NATGatewaySpec{HealthCheck: true},
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				expectNatGateways(m,
+					natGateway("nat-a", "subnet-public-a", ec2.NatGatewayStateAvailable),
+					natGateway("nat-b", "subnet-public-b", ec2.NatGatewayStatePending),
+				)
+			},
+			expectedCondition: &clusterv1.Condition{Status: "True"},
+		},
+		{
+			name:           "degraded NAT gateway without failover",
+			natGatewaySpec: &infrav1.NATGatewaySpec{HealthCheck: true},
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				expectNatGateways(m,
+					natGateway("nat-a", "subnet-public-a", ec2.NatGatewayStateFailed),
+					natGateway("nat-b", "subnet-public-b", ec2.NatGatewayStateAvailable),
+				)
+			},
+			expectedCondition: &clusterv1.Condition{
+				Status:   "False",
+				Severity: clusterv1.ConditionSeverityWarning,
+				Reason:   infrav1.NatGatewaysDegradedReason,
+				Message:  "NAT gateways are degraded in availability zones us-east-1a (nat-a failed)",
+			},
+		},
+		{
+			name:           "degraded NAT gateway fails over to another availability zone",
+			natGatewaySpec: &infrav1.NATGatewaySpec{FailoverOnDegraded: true},
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				expectNatGateways(m,
+					natGateway("nat-a", "subnet-public-a", ec2.NatGatewayStateDeleted),
+					natGateway("nat-a-replacement", "subnet-public-a", ec2.NatGatewayStatePending),
+					natGateway("nat-b", "subnet-public-b", ec2.NatGatewayStateAvailable),
+				)
+				expectRouteTables(m,
+					routeTable("rtb-a", "subnet-private-a", "nat-a"),
+					routeTable("rtb-b", "subnet-private-b", "nat-b"),
+				)
+				expectReplaceRoute(m, "rtb-a", "nat-b")
+			},
+			expectedCondition: &clusterv1.Condition{
+				Status:   "False",
+				Severity: clusterv1.ConditionSeverityWarning,
+				Reason:   infrav1.NatGatewaysDegradedReason,
+				Message:  "NAT gateways are degraded in availability zones us-east-1a (nat-a deleted)",
+			},
+			expectedFailovers: map[string]string{"us-east-1a": "nat-b"},
+		},
+		{
+			name:           "routes already failed over are left alone",
+			natGatewaySpec: &infrav1.NATGatewaySpec{FailoverOnDegraded: true},
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				expectNatGateways(m,
+					natGateway("nat-a", "subnet-public-a", ec2.NatGatewayStateFailed),
+					natGateway("nat-b", "subnet-public-b", ec2.NatGatewayStateAvailable),
+				)
+				expectRouteTables(m,
+					routeTable("rtb-a", "subnet-private-a", "nat-b"),
+					routeTable("rtb-b", "subnet-private-b", "nat-b"),
+				)
+			},
+			expectedCondition: &clusterv1.Condition{
+				Status:   "False",
+				Severity: clusterv1.ConditionSeverityWarning,
+				Reason:   infrav1.NatGatewaysDegradedReason,
+				Message:  "NAT gateways are degraded in availability zones us-east-1a (nat-a failed)",
+			},
+			expectedFailovers: map[string]string{"us-east-1a": "nat-b"},
+		},
+		{
+			name:           "routes fail back once the availability zone recovers",
+			natGatewaySpec: &infrav1.NATGatewaySpec{FailoverOnDegraded: true},
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				expectNatGateways(m,
+					natGateway("nat-a", "subnet-public-a", ec2.NatGatewayStateFailed),
+					natGateway("nat-a-replacement", "subnet-public-a", ec2.NatGatewayStateAvailable),
+					natGateway("nat-b", "subnet-public-b", ec2.NatGatewayStateAvailable),
+				)
+				expectRouteTables(m,
+					routeTable("rtb-a", "subnet-private-a", "nat-b"),
+					routeTable("rtb-b", "subnet-private-b", "nat-b"),
+				)
+				expectReplaceRoute(m, "rtb-a", "nat-a-replacement")
+			},
+			expectedCondition: &clusterv1.Condition{Status: "True"},
+			expectedFailovers: map[string]string{},
+		},
+		{
+			name:           "no available NAT gateway to fail over to",
+			natGatewaySpec: &infrav1.NATGatewaySpec{FailoverOnDegraded: true},
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				expectNatGateways(m,
+					natGateway("nat-a", "subnet-public-a", ec2.NatGatewayStateFailed),
+					natGateway("nat-b", "subnet-public-b", ec2.NatGatewayStateFailed),
+				)
+				expectRouteTables(m,
+					routeTable("rtb-a", "subnet-private-a", "nat-a"),
+					routeTable("rtb-b", "subnet-private-b", "nat-b"),
+				)
+			},
+			expectedCondition: &clusterv1.Condition{
+				Status:   "False",
+				Severity: clusterv1.ConditionSeverityWarning,
+				Reason:   infrav1.NatGatewaysDegradedReason,
+				Message:  "NAT gateways are degraded in availability zones us-east-1a (nat-a failed); us-east-1b (nat-b failed)",
+			},
+			expectedFailovers: map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			ec2Mock := mocks.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			awsCluster := &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						VPC: infrav1.VPCSpec{
+							ID: subnetsVPCID,
+							Tags: infrav1.Tags{
+								infrav1.ClusterTagKey("test-cluster"): "owned",
+							},
+							NATGateway: tc.natGatewaySpec,
+						},
+						Subnets: []infrav1.SubnetSpec{
+							{ID: "subnet-public-a", AvailabilityZone: "us-east-1a", CidrBlock: "10.0.10.0/24", IsPublic: true},
+							{ID: "subnet-private-a", AvailabilityZone: "us-east-1a", CidrBlock: "10.0.11.0/24"},
+							{ID: "subnet-public-b", AvailabilityZone: "us-east-1b", CidrBlock: "10.0.12.0/24", IsPublic: true},
+							{ID: "subnet-private-b", AvailabilityZone: "us-east-1b", CidrBlock: "10.0.13.0/24"},
+						},
+					},
+				},
+			}
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(awsCluster).WithStatusSubresource(awsCluster).Build()
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: awsCluster,
+				Client:     client,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			tc.expect(ec2Mock.EXPECT())
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+
+			g.Expect(s.reconcileNatGatewaysHealth()).To(Succeed())
+
+			condition := conditions.Get(awsCluster, infrav1.NatGatewaysHealthyCondition)
+			if tc.expectedCondition == nil {
+				g.Expect(condition).To(BeNil())
+			} else {
+				g.Expect(condition).NotTo(BeNil())
+				g.Expect(condition.Status).To(Equal(tc.expectedCondition.Status))
+				g.Expect(condition.Severity).To(Equal(tc.expectedCondition.Severity))
+				g.Expect(condition.Reason).To(Equal(tc.expectedCondition.Reason))
+				g.Expect(condition.Message).To(Equal(tc.expectedCondition.Message))
+			}
+			g.Expect(s.natGatewayFailovers).To(Equal(tc.expectedFailovers))
+
+			// The route tables are reconciled with the NAT gateway the traffic fails over to.
+			if fallback, ok := tc.expectedFailovers["us-east-1a"]; ok {
+				natGatewayID, err := s.getNatGatewayForSubnet(&awsCluster.Spec.NetworkSpec.Subnets[1])
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(natGatewayID).To(Equal(fallback))
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package network
 
 import (
+	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
@@ -61,6 +62,12 @@ func (s *Service) ReconcileNetwork() (err error) {
 		return err
 	}
 
+	// NAT Gateways health, which is checked first to fail over while degraded NAT gateways are replaced.
+	if err := s.reconcileNatGatewaysHealth(); err != nil {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.NatGatewaysHealthyCondition, infrav1.NatGatewaysReconciliationFailedReason, infrautilconditions.ErrorConditionAfterInit(s.scope.ClusterObj()), err.Error())
+		return err
+	}
+
 	// NAT Gateways.
 	if err := s.reconcileNatGateways(); err != nil {
 		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.NatGatewaysReadyCondition, infrav1.NatGatewaysReconciliationFailedReason, infrautilconditions.ErrorConditionAfterInit(s.scope.ClusterObj()), err.Error())
@@ -79,6 +86,12 @@ func (s *Service) ReconcileNetwork() (err error) {
 		return err
 	}
 
+	// EC2 Instance Connect Endpoint, which only gives debugging access, so its errors don't fail the reconciliation.
+	if err := s.reconcileInstanceConnectEndpoint(); err != nil {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.InstanceConnectEndpointReadyCondition, infrav1.InstanceConnectEndpointFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		s.scope.Error(err, "non-fatal: failed to reconcile instance connect endpoint")
+	}
+
 	s.scope.Debug("Reconcile network completed successfully")
 	return nil
 }
@@ -103,8 +116,20 @@ func (s *Service) DeleteNetwork() (err error) {
 		s.scope.Error(err, "non-fatal: VPC ID is missing, ")
 	}
 
+	// The default VPC stays unmanaged, even when it wasn't discovered.
+	vpc.UseDefault = s.scope.VPC().UseDefault
 	vpc.DeepCopyInto(s.scope.VPC())
 
+	// EC2 Instance Connect Endpoint, whose network interface prevents the deletion of its subnet.
+	deleted, err := s.deleteInstanceConnectEndpoint()
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.InstanceConnectEndpointReadyCondition, infrav1.InstanceConnectEndpointDeletingReason, clusterv1.ConditionSeverityInfo, "")
+		return errors.New("waiting for the instance connect endpoint to be deleted")
+	}
+
 	// VPC Endpoints.
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.VpcEndpointsReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
 	if err := s.scope.PatchObject(); err != nil {
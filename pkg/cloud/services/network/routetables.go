@@ -47,16 +47,19 @@ func (s *Service) reconcileRouteTables() error {
 
 	s.scope.Debug("Reconciling routing tables")
 
-	subnetRouteMap, err := s.describeVpcRouteTablesBySubnet()
+	rts, err := s.describeVpcRouteTables()
 	if err != nil {
 		return err
 	}
+	subnetRouteMap := routeTablesBySubnet(rts)
+	unassociatedRouteTables := unassociatedRouteTablesByName(rts)
 
 	subnets := s.scope.Subnets()
 	defer func() {
 		s.scope.SetSubnets(subnets)
 	}()
 
+	var missing []*routeTableAssociation
 	for i := range subnets {
 		sn := &subnets[i]
 		// We need to compile the minimum routes for this subnet first, so we can compare it or create them.
@@ -88,19 +91,9 @@ func (s *Service) reconcileRouteTables() error {
 
 		if rt, ok := subnetRouteMap[sn.GetResourceID()]; ok {
 			s.scope.Debug("Subnet is already associated with route table", "subnet-id", sn.GetResourceID(), "route-table-id", *rt.RouteTableId)
-			// TODO(vincepri): check that everything is in order, e.g. routes match the subnet type.
-
-			// For managed environments we need to reconcile the routes of our tables if there is a mistmatch.
-			// For example, a gateway can be deleted and our controller will re-create it, then we replace the route
-			// for the subnet to allow traffic to flow.
-			for _, currentRoute := range rt.Routes {
-				for i := range routes {
-					// Routes destination cidr blocks must be unique within a routing table.
-					// If there is a mistmatch, we replace the routing association.
-					if err := s.fixMismatchedRouting(routes[i], currentRoute, rt); err != nil {
-						return err
-					}
-				}
+
+			if err := s.reconcileRoutes(routes, rt); err != nil {
+				return err
 			}
 
 			// Make sure tags are up-to-date.
@@ -121,27 +114,77 @@ func (s *Service) reconcileRouteTables() error {
 		}
 		s.scope.Debug("Subnet isn't associated with route table", "subnet-id", sn.GetResourceID())
 
+		// A route table of the cluster without any subnet lost its association, e.g. because it was
+		// deleted manually, or because the reconciliation was interrupted before associating it. It is
+		// reused for a subnet of the same type and availability zone, after its routes are verified,
+		// instead of leaving it behind and creating another one.
+		association := &routeTableAssociation{subnet: sn, routes: routes}
+		name := s.getRouteTableName(sn.IsPublic, sn.AvailabilityZone)
+		if candidates := unassociatedRouteTables[name]; len(candidates) > 0 {
+			association.unassociated = candidates[0]
+			unassociatedRouteTables[name] = candidates[1:]
+		}
+		missing = append(missing, association)
+	}
+
+	// The route tables of the subnets are independent of each other, so they're created and associated
+	// concurrently once the route tables to reuse have been chosen.
+	if err := forEachConcurrently(len(missing), func(i int) error {
+		return s.associateSubnetRouteTable(missing[i])
+	}); err != nil {
+		return err
+	}
+
+	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.RouteTablesReadyCondition)
+	return nil
+}
+
+// routeTableAssociation is a subnet which isn't associated with a route table yet.
+type routeTableAssociation struct {
+	subnet *infrav1.SubnetSpec
+	// routes are the routes the route table of the subnet must have.
+	routes []*ec2.Route
+	// unassociated is the route table of the cluster without subnet to associate with the subnet, if any,
+	// instead of creating a new route table.
+	unassociated *ec2.RouteTable
+}
+
+// associateSubnetRouteTable associates a subnet with the route table of the association, which is created with
+// the routes of the subnet unless an unassociated route table is reused.
+func (s *Service) associateSubnetRouteTable(association *routeTableAssociation) error {
+	sn := association.subnet
+
+	var rt *infrav1.RouteTable
+	if association.unassociated != nil {
+		if err := s.reconcileRoutes(association.routes, association.unassociated); err != nil {
+			return err
+		}
+		rt = &infrav1.RouteTable{ID: *association.unassociated.RouteTableId}
+	} else {
 		// For each subnet that doesn't have a routing table associated with it,
 		// create a new table with the appropriate default routes and associate it to the subnet.
-		rt, err := s.createRouteTableWithRoutes(routes, sn.IsPublic, sn.AvailabilityZone)
+		var err error
+		rt, err = s.createRouteTableWithRoutes(association.routes, sn.IsPublic, sn.AvailabilityZone)
 		if err != nil {
 			return err
 		}
+	}
 
-		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
-			if err := s.associateRouteTable(rt, sn.GetResourceID()); err != nil {
-				s.scope.Error(err, "trying to associate route table", "subnet_id", sn.GetResourceID())
-				return false, err
-			}
-			return true, nil
-		}, awserrors.RouteTableNotFound, awserrors.SubnetNotFound); err != nil {
-			return err
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if err := s.associateRouteTable(rt, sn.GetResourceID()); err != nil {
+			s.scope.Error(err, "trying to associate route table", "subnet_id", sn.GetResourceID())
+			return false, err
 		}
+		return true, nil
+	}, awserrors.RouteTableNotFound, awserrors.SubnetNotFound); err != nil {
+		return err
+	}
 
-		s.scope.Debug("Subnet has been associated with route table", "subnet-id", sn.GetResourceID(), "route-table-id", rt.ID)
-		sn.RouteTableID = aws.String(rt.ID)
+	if association.unassociated != nil {
+		record.Eventf(s.scope.InfraCluster(), "RepairedRouteTableAssociation", "Associated existing managed RouteTable %q with subnet %q", rt.ID, sn.GetResourceID())
 	}
-	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.RouteTablesReadyCondition)
+	s.scope.Debug("Subnet has been associated with route table", "subnet-id", sn.GetResourceID(), "route-table-id", rt.ID)
+	sn.RouteTableID = aws.String(rt.ID)
 	return nil
 }
 
@@ -189,12 +232,67 @@ func (s *Service) fixMismatchedRouting(specRoute *ec2.Route, currentRoute *ec2.R
 	return nil
 }
 
+// reconcileRoutes makes sure that a managed route table contains the expected routes of its subnet.
+func (s *Service) reconcileRoutes(routes []*ec2.Route, rt *ec2.RouteTable) error {
+	// For managed environments we need to reconcile the routes of our tables if there is a mistmatch.
+	// For example, a gateway can be deleted and our controller will re-create it, then we replace the route
+	// for the subnet to allow traffic to flow.
+	for _, currentRoute := range rt.Routes {
+		for i := range routes {
+			// Routes destination cidr blocks must be unique within a routing table.
+			// If there is a mistmatch, we replace the routing association.
+			if err := s.fixMismatchedRouting(routes[i], currentRoute, rt); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.createMissingRoutes(routes, rt)
+}
+
+// createMissingRoutes creates the expected routes whose destination isn't routed by the route table,
+// e.g. because they were deleted manually.
+func (s *Service) createMissingRoutes(routes []*ec2.Route, rt *ec2.RouteTable) error {
+	for _, route := range routes {
+		if hasRouteToDestination(rt.Routes, route) {
+			continue
+		}
+
+		if err := s.createRoute(*rt.RouteTableId, route); err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedCreateRoute", "Failed to create route %s for RouteTable %q: %v", route.GoString(), *rt.RouteTableId, err)
+			return errors.Wrapf(err, "failed to create missing route in route table %q: %s", *rt.RouteTableId, route.GoString())
+		}
+		record.Eventf(s.scope.InfraCluster(), "RepairedRoute", "Created missing route %s for RouteTable %q", route.GoString(), *rt.RouteTableId)
+		s.scope.Info("Created missing route", "route-table-id", *rt.RouteTableId)
+	}
+	return nil
+}
+
+// hasRouteToDestination returns whether one of the routes has the destination of the given route.
+func hasRouteToDestination(routes []*ec2.Route, route *ec2.Route) bool {
+	for _, r := range routes {
+		if route.DestinationCidrBlock != nil && aws.StringValue(r.DestinationCidrBlock) == *route.DestinationCidrBlock {
+			return true
+		}
+		if route.DestinationIpv6CidrBlock != nil && aws.StringValue(r.DestinationIpv6CidrBlock) == *route.DestinationIpv6CidrBlock {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Service) describeVpcRouteTablesBySubnet() (map[string]*ec2.RouteTable, error) {
 	rts, err := s.describeVpcRouteTables()
 	if err != nil {
 		return nil, err
 	}
 
+	return routeTablesBySubnet(rts), nil
+}
+
+// routeTablesBySubnet returns the route tables by the IDs of their associated subnets. The main route
+// table of the VPC is keyed by mainRouteTableInVPCKey.
+func routeTablesBySubnet(rts []*ec2.RouteTable) map[string]*ec2.RouteTable {
 	// Amazon allows a subnet to be associated only with a single routing table
 	// https://docs.aws.amazon.com/vpc/latest/userguide/VPC_Route_Tables.html.
 	res := make(map[string]*ec2.RouteTable)
@@ -211,7 +309,26 @@ func (s *Service) describeVpcRouteTablesBySubnet() (map[string]*ec2.RouteTable,
 		}
 	}
 
-	return res, nil
+	return res
+}
+
+// unassociatedRouteTablesByName returns the route tables which are neither associated with a subnet
+// nor the main route table of the VPC, by their Name tag.
+func unassociatedRouteTablesByName(rts []*ec2.RouteTable) map[string][]*ec2.RouteTable {
+	res := make(map[string][]*ec2.RouteTable)
+	for _, rt := range rts {
+		if len(rt.Associations) > 0 {
+			continue
+		}
+
+		name, ok := converters.TagsToMap(rt.Tags)["Name"]
+		if !ok {
+			continue
+		}
+		res[name] = append(res[name], rt)
+	}
+
+	return res
 }
 
 func (s *Service) deleteRouteTables() error {
@@ -286,22 +403,7 @@ func (s *Service) createRouteTableWithRoutes(routes []*ec2.Route, isPublic bool,
 
 	for i := range routes {
 		route := routes[i]
-		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
-			if _, err := s.EC2Client.CreateRouteWithContext(context.TODO(), &ec2.CreateRouteInput{
-				RouteTableId:                out.RouteTable.RouteTableId,
-				DestinationCidrBlock:        route.DestinationCidrBlock,
-				DestinationIpv6CidrBlock:    route.DestinationIpv6CidrBlock,
-				EgressOnlyInternetGatewayId: route.EgressOnlyInternetGatewayId,
-				GatewayId:                   route.GatewayId,
-				InstanceId:                  route.InstanceId,
-				NatGatewayId:                route.NatGatewayId,
-				NetworkInterfaceId:          route.NetworkInterfaceId,
-				VpcPeeringConnectionId:      route.VpcPeeringConnectionId,
-			}); err != nil {
-				return false, err
-			}
-			return true, nil
-		}, awserrors.RouteTableNotFound, awserrors.NATGatewayNotFound, awserrors.GatewayNotFound); err != nil {
+		if err := s.createRoute(*out.RouteTable.RouteTableId, route); err != nil {
 			// TODO(vincepri): cleanup the route table if this fails.
 			record.Warnf(s.scope.InfraCluster(), "FailedCreateRoute", "Failed to create route %s for RouteTable %q: %v", route.GoString(), *out.RouteTable.RouteTableId, err)
 			return nil, errors.Wrapf(err, "failed to create route in route table %q: %s", *out.RouteTable.RouteTableId, route.GoString())
@@ -314,11 +416,35 @@ func (s *Service) createRouteTableWithRoutes(routes []*ec2.Route, isPublic bool,
 	}, nil
 }
 
+func (s *Service) createRoute(routeTableID string, route *ec2.Route) error {
+	return wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if _, err := s.EC2Client.CreateRouteWithContext(context.TODO(), &ec2.CreateRouteInput{
+			RouteTableId:                aws.String(routeTableID),
+			DestinationCidrBlock:        route.DestinationCidrBlock,
+			DestinationIpv6CidrBlock:    route.DestinationIpv6CidrBlock,
+			EgressOnlyInternetGatewayId: route.EgressOnlyInternetGatewayId,
+			GatewayId:                   route.GatewayId,
+			InstanceId:                  route.InstanceId,
+			NatGatewayId:                route.NatGatewayId,
+			NetworkInterfaceId:          route.NetworkInterfaceId,
+			VpcPeeringConnectionId:      route.VpcPeeringConnectionId,
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.RouteTableNotFound, awserrors.NATGatewayNotFound, awserrors.GatewayNotFound)
+}
+
 func (s *Service) associateRouteTable(rt *infrav1.RouteTable, subnetID string) error {
 	_, err := s.EC2Client.AssociateRouteTableWithContext(context.TODO(), &ec2.AssociateRouteTableInput{
 		RouteTableId: aws.String(rt.ID),
 		SubnetId:     aws.String(subnetID),
 	})
+	if code, _ := awserrors.Code(err); code == awserrors.ResourceAlreadyAssociated {
+		// The subnet is explicitly associated with a route table which isn't the expected one,
+		// e.g. the main route table of the VPC, so the association is replaced.
+		return s.replaceRouteTableAssociation(rt, subnetID)
+	}
 	if err != nil {
 		record.Warnf(s.scope.InfraCluster(), "FailedAssociateRouteTable", "Failed to associate managed RouteTable %q with Subnet %q: %v", rt.ID, subnetID, err)
 		return errors.Wrapf(err, "failed to associate route table %q to subnet %q", rt.ID, subnetID)
@@ -329,6 +455,39 @@ func (s *Service) associateRouteTable(rt *infrav1.RouteTable, subnetID string) e
 	return nil
 }
 
+func (s *Service) replaceRouteTableAssociation(rt *infrav1.RouteTable, subnetID string) error {
+	out, err := s.EC2Client.DescribeRouteTablesWithContext(context.TODO(), &ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			filter.EC2.AssociationSubnet(subnetID),
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to describe route table associated with subnet %q", subnetID)
+	}
+
+	for _, current := range out.RouteTables {
+		for _, as := range current.Associations {
+			if aws.StringValue(as.SubnetId) != subnetID {
+				continue
+			}
+
+			if _, err := s.EC2Client.ReplaceRouteTableAssociationWithContext(context.TODO(), &ec2.ReplaceRouteTableAssociationInput{
+				AssociationId: as.RouteTableAssociationId,
+				RouteTableId:  aws.String(rt.ID),
+			}); err != nil {
+				record.Warnf(s.scope.InfraCluster(), "FailedAssociateRouteTable", "Failed to replace association of RouteTable %q with subnet %q: %v", *current.RouteTableId, subnetID, err)
+				return errors.Wrapf(err, "failed to replace association of route table %q with subnet %q", *current.RouteTableId, subnetID)
+			}
+
+			record.Eventf(s.scope.InfraCluster(), "RepairedRouteTableAssociation", "Replaced association of RouteTable %q with subnet %q by managed RouteTable %q", *current.RouteTableId, subnetID, rt.ID)
+			return nil
+		}
+	}
+
+	return errors.Errorf("failed to find route table associated with subnet %q", subnetID)
+}
+
 func (s *Service) getNatGatewayPrivateRoute(natGatewayID string) *ec2.Route {
 	return &ec2.Route{
 		NatGatewayId:         aws.String(natGatewayID),
@@ -357,7 +516,7 @@ func (s *Service) getGatewayPublicIPv6Route() *ec2.Route {
 	}
 }
 
-func (s *Service) getRouteTableTagParams(id string, public bool, zone string) infrav1.BuildParams {
+func (s *Service) getRouteTableName(public bool, zone string) string {
 	var name strings.Builder
 
 	name.WriteString(s.scope.Name())
@@ -370,6 +529,10 @@ func (s *Service) getRouteTableTagParams(id string, public bool, zone string) in
 	name.WriteString("-")
 	name.WriteString(zone)
 
+	return name.String()
+}
+
+func (s *Service) getRouteTableTagParams(id string, public bool, zone string) infrav1.BuildParams {
 	additionalTags := s.scope.AdditionalTags()
 	additionalTags[infrav1.ClusterAWSCloudProviderTagKey(s.scope.KubernetesClusterName())] = string(infrav1.ResourceLifecycleOwned)
 
@@ -377,7 +540,7 @@ func (s *Service) getRouteTableTagParams(id string, public bool, zone string) in
 		ClusterName: s.scope.Name(),
 		ResourceID:  id,
 		Lifecycle:   infrav1.ResourceLifecycleOwned,
-		Name:        aws.String(name.String()),
+		Name:        aws.String(s.getRouteTableName(public, zone)),
 		Role:        aws.String(infrav1.CommonRoleTagValue),
 		Additional:  additionalTags,
 	}
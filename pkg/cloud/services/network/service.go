@@ -29,6 +29,10 @@ import (
 type Service struct {
 	scope     scope.NetworkScope
 	EC2Client ec2iface.EC2API
+
+	// natGatewayFailovers are the NAT gateways the egress traffic of the private subnets of availability
+	// zones whose NAT gateways are degraded is routed through, by availability zone.
+	natGatewayFailovers map[string]string
 }
 
 // NewService returns a new service given the ec2 api client.
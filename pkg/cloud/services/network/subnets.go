@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/converters"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/filter"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/eni"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/wait"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/tags"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/internal/cidr"
@@ -69,6 +70,21 @@ func (s *Service) reconcileSubnets() error {
 
 	unmanagedVPC := s.scope.VPC().IsUnmanaged(s.scope.Name())
 
+	if len(subnets) == 0 && s.scope.VPC().UseDefault {
+		// The default subnets of the default VPC are used when no subnets are specified.
+		subnets, err = s.describeDefaultSubnets()
+		if err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedDescribeDefaultSubnets", "Failed to find default subnets: %v", err)
+			return err
+		}
+
+		// Persist the default subnets to AWSCluster
+		if err := s.scope.PatchObject(); err != nil {
+			s.scope.Error(err, "failed to patch object to save subnets")
+			return err
+		}
+	}
+
 	if len(subnets) == 0 {
 		if unmanagedVPC {
 			// If we have a unmanaged VPC then subnets must be specified
@@ -190,19 +206,26 @@ func (s *Service) reconcileSubnets() error {
 		}
 
 		// Proceed to create the rest of the subnets that don't have an ID.
+		var missing []*infrav1.SubnetSpec
 		for i := range subnets {
-			subnet := &subnets[i]
-
 			// If we have a ResourceID (i.e. subnet-<xyz>), the resource was already created.
-			if subnet.ResourceID != "" {
-				continue
+			if subnets[i].ResourceID == "" {
+				missing = append(missing, &subnets[i])
 			}
+		}
 
-			nsn, err := s.createSubnet(subnet)
+		// The subnets are independent of each other, so they're created concurrently. The subnets which were
+		// created are recorded even if creating others failed, and the others are found by their CIDR block
+		// if they were created before the reconciliation was interrupted.
+		if err := forEachConcurrently(len(missing), func(i int) error {
+			nsn, err := s.createSubnet(missing[i])
 			if err != nil {
 				return err
 			}
-			nsn.DeepCopyInto(subnet)
+			nsn.DeepCopyInto(missing[i])
+			return nil
+		}); err != nil {
+			return err
 		}
 	}
 
@@ -410,6 +433,37 @@ func (s *Service) describeSubnets() (*ec2.DescribeSubnetsOutput, error) {
 	return out, nil
 }
 
+// describeDefaultSubnets returns the default subnets of the availability zones in the default VPC.
+func (s *Service) describeDefaultSubnets() (infrav1.Subnets, error) {
+	input := &ec2.DescribeSubnetsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			filter.EC2.DefaultForAZ(),
+			filter.EC2.SubnetStates(ec2.SubnetStatePending, ec2.SubnetStateAvailable),
+		},
+	}
+
+	out, err := s.EC2Client.DescribeSubnetsWithContext(context.TODO(), input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe default subnets in vpc %q", s.scope.VPC().ID)
+	}
+	if len(out.Subnets) == 0 {
+		return nil, errors.Errorf("no default subnets found in vpc %q", s.scope.VPC().ID)
+	}
+
+	subnets := make(infrav1.Subnets, 0, len(out.Subnets))
+	for _, sn := range out.Subnets {
+		subnets = append(subnets, infrav1.SubnetSpec{
+			ID:               aws.StringValue(sn.SubnetId),
+			ResourceID:       aws.StringValue(sn.SubnetId),
+			CidrBlock:        aws.StringValue(sn.CidrBlock),
+			AvailabilityZone: aws.StringValue(sn.AvailabilityZone),
+			IsPublic:         aws.BoolValue(sn.MapPublicIpOnLaunch),
+		})
+	}
+	return subnets, nil
+}
+
 func (s *Service) createSubnet(sn *infrav1.SubnetSpec) (*infrav1.SubnetSpec, error) {
 	// When managing subnets, the ID specified in the spec is the name of the subnet.
 	if sn.Tags == nil {
@@ -537,6 +591,7 @@ func (s *Service) deleteSubnet(id string) error {
 		SubnetId: aws.String(id),
 	})
 	if err != nil {
+		err = eni.NewService(s.scope, s.EC2Client).ReleaseBlocking(err, filter.EC2.SubnetID(id))
 		record.Warnf(s.scope.InfraCluster(), "FailedDeleteSubnet", "Failed to delete managed Subnet %q: %v", id, err)
 		return errors.Wrapf(err, "failed to delete subnet %q", id)
 	}
@@ -23,9 +23,12 @@ import (
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -943,7 +946,7 @@ func TestReconcileSubnets(t *testing.T) {
 							MapPublicIpOnLaunch: aws.Bool(false),
 						},
 					}, nil).
-					After(firstSubnet)
+					After(describeCall)
 
 				m.WaitUntilSubnetAvailableWithContext(context.TODO(), gomock.Any()).
 					After(secondSubnet)
@@ -1160,7 +1163,7 @@ func TestReconcileSubnets(t *testing.T) {
 							MapPublicIpOnLaunch: aws.Bool(false),
 						},
 					}, nil).
-					After(firstSubnet)
+					After(describeCall)
 
 				m.WaitUntilSubnetAvailableWithContext(context.TODO(), gomock.Any()).
 					After(secondSubnet)
@@ -1290,15 +1293,6 @@ func TestReconcileSubnets(t *testing.T) {
 					Return(&ec2.ModifySubnetAttributeOutput{}, nil).
 					After(firstSubnet)
 
-				m.ModifySubnetAttributeWithContext(context.TODO(), &ec2.ModifySubnetAttributeInput{
-					AssignIpv6AddressOnCreation: &ec2.AttributeBooleanValue{
-						Value: aws.Bool(true),
-					},
-					SubnetId: aws.String("subnet-2"),
-				}).
-					Return(&ec2.ModifySubnetAttributeOutput{}, nil).
-					After(firstSubnet)
-
 				m.ModifySubnetAttributeWithContext(context.TODO(), &ec2.ModifySubnetAttributeInput{
 					MapPublicIpOnLaunch: &ec2.AttributeBooleanValue{
 						Value: aws.Bool(true),
@@ -1360,10 +1354,19 @@ func TestReconcileSubnets(t *testing.T) {
 							MapPublicIpOnLaunch: aws.Bool(false),
 						},
 					}, nil).
-					After(firstSubnet)
+					After(describeCall)
 
 				m.WaitUntilSubnetAvailableWithContext(context.TODO(), gomock.Any()).
 					After(secondSubnet)
+
+				m.ModifySubnetAttributeWithContext(context.TODO(), &ec2.ModifySubnetAttributeInput{
+					AssignIpv6AddressOnCreation: &ec2.AttributeBooleanValue{
+						Value: aws.Bool(true),
+					},
+					SubnetId: aws.String("subnet-2"),
+				}).
+					Return(&ec2.ModifySubnetAttributeOutput{}, nil).
+					After(secondSubnet)
 			},
 		},
 		{
@@ -1519,7 +1522,7 @@ func TestReconcileSubnets(t *testing.T) {
 							MapPublicIpOnLaunch: aws.Bool(false),
 						},
 					}, nil).
-					After(zone1PublicSubnet)
+					After(describeCall)
 
 				m.WaitUntilSubnetAvailableWithContext(context.TODO(), gomock.Any()).
 					After(zone1PrivateSubnet)
@@ -1567,7 +1570,7 @@ func TestReconcileSubnets(t *testing.T) {
 							MapPublicIpOnLaunch: aws.Bool(false),
 						},
 					}, nil).
-					After(zone1PrivateSubnet)
+					After(describeCall)
 
 				m.WaitUntilSubnetAvailableWithContext(context.TODO(), gomock.Any()).
 					After(zone2PublicSubnet)
@@ -1622,7 +1625,7 @@ func TestReconcileSubnets(t *testing.T) {
 							MapPublicIpOnLaunch: aws.Bool(false),
 						},
 					}, nil).
-					After(zone2PublicSubnet)
+					After(describeCall)
 
 				m.WaitUntilSubnetAvailableWithContext(context.TODO(), gomock.Any()).
 					After(zone2PrivateSubnet)
@@ -1783,7 +1786,7 @@ func TestReconcileSubnets(t *testing.T) {
 							MapPublicIpOnLaunch: aws.Bool(false),
 						},
 					}, nil).
-					After(zone1PublicSubnet)
+					After(describeCall)
 
 				m.WaitUntilSubnetAvailableWithContext(context.TODO(), gomock.Any()).
 					After(zone1PrivateSubnet)
@@ -2207,7 +2210,7 @@ func TestReconcileSubnets(t *testing.T) {
 							MapPublicIpOnLaunch: aws.Bool(false),
 						},
 					}, nil).
-					After(zone1PublicSubnet)
+					After(describeCall)
 
 				m.WaitUntilSubnetAvailableWithContext(context.TODO(), gomock.Any()).
 					After(zone1PrivateSubnet)
@@ -2255,7 +2258,7 @@ func TestReconcileSubnets(t *testing.T) {
 							MapPublicIpOnLaunch: aws.Bool(false),
 						},
 					}, nil).
-					After(zone1PrivateSubnet)
+					After(describeCall)
 
 				m.WaitUntilSubnetAvailableWithContext(context.TODO(), gomock.Any()).
 					After(zone2PublicSubnet)
@@ -2310,7 +2313,7 @@ func TestReconcileSubnets(t *testing.T) {
 							MapPublicIpOnLaunch: aws.Bool(false),
 						},
 					}, nil).
-					After(zone2PublicSubnet)
+					After(describeCall)
 
 				m.WaitUntilSubnetAvailableWithContext(context.TODO(), gomock.Any()).
 					After(zone2PrivateSubnet)
@@ -2345,6 +2348,59 @@ func TestReconcileSubnets(t *testing.T) {
 	}
 }
 
+func TestReconcileSubnetsPartialFailure(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ec2Mock := mocks.NewMockEC2API(mockCtrl)
+
+	scope, err := NewClusterScope().WithNetwork(&infrav1.NetworkSpec{
+		VPC: infrav1.VPCSpec{
+			ID: subnetsVPCID,
+			Tags: infrav1.Tags{
+				infrav1.ClusterTagKey("test-cluster"): "owned",
+			},
+		},
+		Subnets: []infrav1.SubnetSpec{
+			{ID: "private-a", CidrBlock: "10.1.0.0/16", AvailabilityZone: "us-east-1a", IsPublic: false},
+			{ID: "public-b", CidrBlock: "10.2.0.0/16", AvailabilityZone: "us-east-1b", IsPublic: true},
+			{ID: "private-c", CidrBlock: "10.3.0.0/16", AvailabilityZone: "us-east-1c", IsPublic: false},
+		},
+	}).Build()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ec2Mock.EXPECT().DescribeSubnetsWithContext(context.TODO(), gomock.Any()).Return(&ec2.DescribeSubnetsOutput{}, nil)
+	ec2Mock.EXPECT().DescribeRouteTablesWithContext(context.TODO(), gomock.Any()).Return(&ec2.DescribeRouteTablesOutput{}, nil)
+	ec2Mock.EXPECT().DescribeNatGatewaysPagesWithContext(context.TODO(), gomock.Any(), gomock.Any()).Return(nil)
+	// The subnets of the other availability zones are created even though creating the subnet of us-east-1b fails.
+	ec2Mock.EXPECT().CreateSubnetWithContext(context.TODO(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *ec2.CreateSubnetInput, _ ...request.Option) (*ec2.CreateSubnetOutput, error) {
+			if aws.StringValue(input.AvailabilityZone) == "us-east-1b" {
+				return nil, awserr.New("InsufficientFreeAddressesInSubnet", "no free addresses", nil)
+			}
+			return &ec2.CreateSubnetOutput{
+				Subnet: &ec2.Subnet{
+					VpcId:            input.VpcId,
+					SubnetId:         aws.String("subnet-" + aws.StringValue(input.AvailabilityZone)),
+					CidrBlock:        input.CidrBlock,
+					AvailabilityZone: input.AvailabilityZone,
+				},
+			}, nil
+		}).Times(3)
+	ec2Mock.EXPECT().WaitUntilSubnetAvailableWithContext(context.TODO(), gomock.Any()).Return(nil).Times(2)
+
+	s := NewService(scope)
+	s.EC2Client = ec2Mock
+
+	g.Expect(s.reconcileSubnets()).To(MatchError(ContainSubstring("failed to create subnet")))
+	// The subnets which were created are recorded, so that they aren't created again.
+	g.Expect(scope.Subnets()).To(HaveExactElements(
+		HaveField("ResourceID", "subnet-us-east-1a"),
+		HaveField("ResourceID", BeEmpty()),
+		HaveField("ResourceID", "subnet-us-east-1c"),
+	))
+}
+
 func TestDiscoverSubnets(t *testing.T) {
 	testCases := []struct {
 		name   string
@@ -2496,6 +2552,113 @@ func TestDiscoverSubnets(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "default VPC finds the default subnets",
+			input: &infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID:         subnetsVPCID,
+					UseDefault: true,
+				},
+			},
+			mocks: func(m *mocks.MockEC2APIMockRecorder) {
+				defaultSubnets := &ec2.DescribeSubnetsOutput{
+					Subnets: []*ec2.Subnet{
+						{
+							VpcId:               aws.String(subnetsVPCID),
+							SubnetId:            aws.String("subnet-a"),
+							AvailabilityZone:    aws.String("us-east-1a"),
+							CidrBlock:           aws.String("172.31.0.0/20"),
+							MapPublicIpOnLaunch: aws.Bool(true),
+							DefaultForAz:        aws.Bool(true),
+						},
+						{
+							VpcId:               aws.String(subnetsVPCID),
+							SubnetId:            aws.String("subnet-b"),
+							AvailabilityZone:    aws.String("us-east-1b"),
+							CidrBlock:           aws.String("172.31.16.0/20"),
+							MapPublicIpOnLaunch: aws.Bool(true),
+							DefaultForAz:        aws.Bool(true),
+						},
+					},
+				}
+				m.DescribeSubnetsWithContext(context.TODO(), gomock.Eq(&ec2.DescribeSubnetsInput{
+					Filters: []*ec2.Filter{
+						{
+							Name:   aws.String("vpc-id"),
+							Values: []*string{aws.String(subnetsVPCID)},
+						},
+						{
+							Name:   aws.String("default-for-az"),
+							Values: []*string{aws.String("true")},
+						},
+						{
+							Name:   aws.String("state"),
+							Values: []*string{aws.String("pending"), aws.String("available")},
+						},
+					},
+				})).
+					Return(defaultSubnets, nil)
+
+				m.DescribeSubnetsWithContext(context.TODO(), gomock.Eq(&ec2.DescribeSubnetsInput{
+					Filters: []*ec2.Filter{
+						{
+							Name:   aws.String("state"),
+							Values: []*string{aws.String("pending"), aws.String("available")},
+						},
+						{
+							Name:   aws.String("vpc-id"),
+							Values: []*string{aws.String(subnetsVPCID)},
+						},
+					},
+				})).
+					Return(defaultSubnets, nil)
+
+				m.DescribeRouteTablesWithContext(context.TODO(), gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(&ec2.DescribeRouteTablesOutput{
+						RouteTables: []*ec2.RouteTable{
+							{
+								Associations: []*ec2.RouteTableAssociation{
+									{
+										Main: aws.Bool(true),
+									},
+								},
+								Routes: []*ec2.Route{
+									{
+										DestinationCidrBlock: aws.String("0.0.0.0/0"),
+										GatewayId:            aws.String("igw-0"),
+									},
+								},
+								RouteTableId: aws.String("rtb-main"),
+							},
+						},
+					}, nil)
+
+				m.DescribeNatGatewaysPagesWithContext(context.TODO(), gomock.AssignableToTypeOf(&ec2.DescribeNatGatewaysInput{}), gomock.Any()).Return(nil)
+
+				m.CreateTagsWithContext(context.TODO(), gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(&ec2.CreateTagsOutput{}, nil).AnyTimes()
+			},
+			expect: []infrav1.SubnetSpec{
+				{
+					ID:               "subnet-a",
+					ResourceID:       "subnet-a",
+					AvailabilityZone: "us-east-1a",
+					CidrBlock:        "172.31.0.0/20",
+					IsPublic:         true,
+					RouteTableID:     aws.String("rtb-main"),
+					Tags:             infrav1.Tags{},
+				},
+				{
+					ID:               "subnet-b",
+					ResourceID:       "subnet-b",
+					AvailabilityZone: "us-east-1b",
+					CidrBlock:        "172.31.16.0/20",
+					IsPublic:         true,
+					RouteTableID:     aws.String("rtb-main"),
+					Tags:             infrav1.Tags{},
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
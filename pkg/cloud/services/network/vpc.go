@@ -47,6 +47,17 @@ const (
 func (s *Service) reconcileVPC() error {
 	s.scope.Debug("Reconciling VPC")
 
+	// The default VPC is discovered once, then handled like any other unmanaged VPC.
+	if s.scope.VPC().UseDefault && s.scope.VPC().ID == "" {
+		vpc, err := s.describeDefaultVPC()
+		if err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedDescribeDefaultVPC", "Failed to find default VPC: %v", err)
+			return err
+		}
+		s.scope.VPC().ID = vpc.ID
+		record.Eventf(s.scope.InfraCluster(), "SuccessfulUseDefaultVPC", "Using default VPC %q", vpc.ID)
+	}
+
 	// If the ID is not nil, VPC is either managed or unmanaged but should exist in the AWS.
 	if s.scope.VPC().ID != "" {
 		vpc, err := s.describeVPCByID()
@@ -63,7 +74,7 @@ func (s *Service) reconcileVPC() error {
 		}
 
 		// If VPC is unmanaged, return early.
-		if vpc.IsUnmanaged(s.scope.Name()) {
+		if s.scope.VPC().UseDefault || vpc.IsUnmanaged(s.scope.Name()) {
 			s.scope.Debug("Working on unmanaged VPC", "vpc-id", vpc.ID)
 			if err := s.scope.PatchObject(); err != nil {
 				return errors.Wrap(err, "failed to patch unmanaged VPC fields")
@@ -603,6 +614,30 @@ func (s *Service) describeVPCByID() (*infrav1.VPCSpec, error) {
 	return vpc, nil
 }
 
+// describeDefaultVPC finds the default VPC of the region.
+func (s *Service) describeDefaultVPC() (*infrav1.VPCSpec, error) {
+	input := &ec2.DescribeVpcsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.DefaultVPC(),
+			filter.EC2.VPCStates(ec2.VpcStatePending, ec2.VpcStateAvailable),
+		},
+	}
+
+	out, err := s.EC2Client.DescribeVpcsWithContext(context.TODO(), input)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query ec2 for the default VPC")
+	}
+	if len(out.Vpcs) == 0 {
+		return nil, awserrors.NewNotFound(fmt.Sprintf("could not find a default VPC in region %q", s.scope.Region()))
+	}
+
+	return &infrav1.VPCSpec{
+		ID:        aws.StringValue(out.Vpcs[0].VpcId),
+		CidrBlock: aws.StringValue(out.Vpcs[0].CidrBlock),
+		Tags:      converters.TagsToMap(out.Vpcs[0].Tags),
+	}, nil
+}
+
 // describeVPCByName finds the VPC by `Name` tag. Use this if the ID is not available yet, either because no
 // VPC was created until now or if storing the ID could have failed.
 func (s *Service) describeVPCByName() (*infrav1.VPCSpec, error) {
@@ -438,6 +438,59 @@ func TestReconcileVPC(t *testing.T) {
 				}, nil)
 			},
 		},
+		{
+			name:  "Should use the default vpc as an unmanaged vpc, if useDefault is set",
+			input: &infrav1.VPCSpec{UseDefault: true, AvailabilityZoneUsageLimit: &usageLimit, AvailabilityZoneSelection: &selection},
+			want: &infrav1.VPCSpec{
+				ID:                         "vpc-default",
+				UseDefault:                 true,
+				CidrBlock:                  "172.31.0.0/16",
+				Tags:                       nil,
+				AvailabilityZoneUsageLimit: &usageLimit,
+				AvailabilityZoneSelection:  &selection,
+			},
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				defaultVPC := &ec2.DescribeVpcsOutput{
+					Vpcs: []*ec2.Vpc{
+						{
+							State:     aws.String("available"),
+							VpcId:     aws.String("vpc-default"),
+							CidrBlock: aws.String("172.31.0.0/16"),
+							IsDefault: aws.Bool(true),
+						},
+					},
+				}
+				m.DescribeVpcsWithContext(context.TODO(), gomock.Eq(&ec2.DescribeVpcsInput{
+					Filters: []*ec2.Filter{
+						{
+							Name:   aws.String("isDefault"),
+							Values: aws.StringSlice([]string{"true"}),
+						},
+						{
+							Name:   aws.String("state"),
+							Values: aws.StringSlice([]string{ec2.VpcStatePending, ec2.VpcStateAvailable}),
+						},
+					},
+				})).Return(defaultVPC, nil)
+				m.DescribeVpcsWithContext(context.TODO(), gomock.Eq(&ec2.DescribeVpcsInput{
+					VpcIds: aws.StringSlice([]string{"vpc-default"}),
+					Filters: []*ec2.Filter{
+						{
+							Name:   aws.String("state"),
+							Values: aws.StringSlice([]string{ec2.VpcStatePending, ec2.VpcStateAvailable}),
+						},
+					},
+				})).Return(defaultVPC, nil)
+			},
+		},
+		{
+			name:              "Should return error if useDefault is set and there is no default vpc",
+			input:             &infrav1.VPCSpec{UseDefault: true, AvailabilityZoneUsageLimit: &usageLimit, AvailabilityZoneSelection: &selection},
+			wantErrContaining: aws.String("could not find a default VPC"),
+			expect: func(m *mocks.MockEC2APIMockRecorder) {
+				m.DescribeVpcsWithContext(context.TODO(), gomock.AssignableToTypeOf(&ec2.DescribeVpcsInput{})).Return(&ec2.DescribeVpcsOutput{}, nil)
+			},
+		},
 		{
 			name:  "Should retry if vpc not found error occurs during attributes configuration for managed vpc",
 			input: &infrav1.VPCSpec{ID: "managed-vpc-exists", AvailabilityZoneUsageLimit: &usageLimit, AvailabilityZoneSelection: &selection},
@@ -599,6 +652,10 @@ func TestDeleteVPC(t *testing.T) {
 			name:  "Should not delete vpc if vpc is unmanaged",
 			input: &infrav1.VPCSpec{ID: "unmanaged-vpc"},
 		},
+		{
+			name:  "Should not delete vpc if vpc is the default vpc",
+			input: &infrav1.VPCSpec{ID: "vpc-default", UseDefault: true, Tags: tags},
+		},
 		{
 			name: "Should return error if delete vpc failed",
 			input: &infrav1.VPCSpec{
@@ -0,0 +1,354 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphans
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/discovery"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+)
+
+const (
+	// scanInterval is the minimum interval between two scans for orphaned resources.
+	scanInterval = 5 * time.Minute
+
+	// gracePeriod is the age below which a resource is never reported as orphaned, so that the
+	// resources being created aren't reported before the objects of the cluster reference them.
+	gracePeriod = 10 * time.Minute
+
+	// asgDeleteInProgress is the status of an Auto Scaling group being deleted.
+	asgDeleteInProgress = "Delete in progress"
+)
+
+// ReconcileOrphans scans for the AWS resources owned by the cluster which no object of the cluster
+// references anymore when the cluster has the scan-orphans annotation, at most once per scan interval,
+// and reports them in the status of the cluster.
+// With the clean value of the annotation, the orphaned resources which were already reported by the
+// previous scan are deleted, if the service allows it, so that only resources the user could review
+// are deleted.
+func (s *Service) ReconcileOrphans() error {
+	action := s.scope.ScanOrphans()
+	switch action {
+	case "":
+		if s.scope.LastOrphanScanTime() != nil {
+			s.scope.SetOrphanedResources(nil, nil)
+		}
+		return nil
+	case infrav1.ScanOrphansValue, infrav1.CleanOrphansValue:
+	default:
+		record.Warnf(s.scope.InfraCluster(), "InvalidScanOrphansAnnotation", "Ignoring value %q of annotation %s, expected %q or %q",
+			action, infrav1.ScanOrphansAnnotation, infrav1.ScanOrphansValue, infrav1.CleanOrphansValue)
+		return nil
+	}
+
+	if last := s.scope.LastOrphanScanTime(); last != nil && time.Since(last.Time) < scanInterval {
+		return nil
+	}
+
+	s.scope.Debug("Scanning for orphaned resources")
+
+	orphans, err := s.findOrphans()
+	if err != nil {
+		return err
+	}
+
+	if action == infrav1.CleanOrphansValue {
+		if s.allowCleanup {
+			orphans = s.deleteOrphans(orphans)
+		} else if len(orphans) > 0 {
+			record.Warnf(s.scope.InfraCluster(), "OrphanCleanupNotAllowed", "Not deleting %d orphaned resources: the controller must be started with --allow-orphan-cleanup", len(orphans))
+		}
+	}
+
+	for _, orphan := range orphans {
+		record.Warnf(s.scope.InfraCluster(), "OrphanedResourceFound", "%s %s is owned by the cluster but no object of the cluster references it", orphan.Kind, orphan.ID)
+	}
+
+	now := metav1.Now()
+	s.scope.SetOrphanedResources(orphans, &now)
+	return nil
+}
+
+// findOrphans returns the Auto Scaling groups, launch templates and load balancers owned by the
+// cluster which no object of the cluster references.
+func (s *Service) findOrphans() ([]infrav1.OrphanedResource, error) {
+	pools, err := s.scope.AWSMachinePools()
+	if err != nil {
+		return nil, err
+	}
+	// The launch template of a machine pool is named after it, while its Auto Scaling group can be
+	// given another name, or be a pre-existing group the pool adopted.
+	groupNames := sets.New[string]()
+	launchTemplateNames := sets.New[string]()
+	launchTemplateIDs := sets.New[string]()
+	for _, pool := range pools {
+		groupNames.Insert(pool.ASGName())
+		if pool.Status.ASGName != "" {
+			groupNames.Insert(pool.Status.ASGName)
+		}
+		launchTemplateNames.Insert(pool.Name)
+		if pool.Status.LaunchTemplateID != "" {
+			launchTemplateIDs.Insert(pool.Status.LaunchTemplateID)
+		}
+	}
+
+	orphans := []infrav1.OrphanedResource{}
+
+	groups, err := s.ownedAutoScalingGroups()
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		name := aws.StringValue(group.AutoScalingGroupName)
+		if groupNames.Has(name) || aws.StringValue(group.Status) == asgDeleteInProgress || isRecent(group.CreatedTime) {
+			continue
+		}
+		orphans = append(orphans, infrav1.OrphanedResource{Kind: infrav1.OrphanedAutoScalingGroup, ID: name, Name: name})
+	}
+
+	templates, err := s.ownedLaunchTemplates()
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range templates {
+		id, name := aws.StringValue(template.LaunchTemplateId), aws.StringValue(template.LaunchTemplateName)
+		if launchTemplateIDs.Has(id) || launchTemplateNames.Has(name) || isRecent(template.CreateTime) {
+			continue
+		}
+		orphans = append(orphans, infrav1.OrphanedResource{Kind: infrav1.OrphanedLaunchTemplate, ID: id, Name: name})
+	}
+
+	// The load balancers of the API server are referenced by the status of the cluster, which is
+	// updated in the reconciliation that creates them. The recent ones are skipped like the other
+	// resources, in case that update didn't happen yet. While the control plane load balancer is
+	// migrated, both the classic and the network load balancers are referenced by the migration.
+	network := s.scope.Network()
+	loadBalancerNames := sets.New[string]()
+	for _, lb := range []infrav1.LoadBalancer{network.APIServerELB, network.SecondaryAPIServerELB} {
+		if lb.Name != "" {
+			loadBalancerNames.Insert(lb.Name)
+		}
+	}
+	if migration := s.scope.ControlPlaneLoadBalancerMigration(); migration != nil {
+		for _, name := range []string{migration.ClassicLoadBalancerName, migration.LoadBalancerName} {
+			if name != "" {
+				loadBalancerNames.Insert(name)
+			}
+		}
+	}
+	loadBalancers, err := s.ownedLoadBalancers()
+	if err != nil {
+		return nil, err
+	}
+	for _, lb := range loadBalancers {
+		if loadBalancerNames.Has(lb.Name) {
+			continue
+		}
+		// The tags of a load balancer don't tell when it was created, so it is described.
+		created, found, err := s.loadBalancerCreatedTime(lb)
+		if err != nil {
+			return nil, err
+		}
+		if !found || isRecent(created) {
+			continue
+		}
+		orphans = append(orphans, infrav1.OrphanedResource{Kind: infrav1.OrphanedLoadBalancer, ID: lb.ARN, Name: lb.Name})
+	}
+
+	return orphans, nil
+}
+
+func (s *Service) ownedAutoScalingGroups() ([]*autoscaling.Group, error) {
+	input := &autoscaling.DescribeAutoScalingGroupsInput{
+		Filters: []*autoscaling.Filter{
+			{
+				Name:   aws.String(fmt.Sprintf("tag:%s", infrav1.ClusterTagKey(s.scope.KubernetesClusterName()))),
+				Values: aws.StringSlice([]string{string(infrav1.ResourceLifecycleOwned)}),
+			},
+		},
+	}
+
+	groups := []*autoscaling.Group{}
+	if err := s.ASGClient.DescribeAutoScalingGroupsPagesWithContext(context.TODO(), input, func(out *autoscaling.DescribeAutoScalingGroupsOutput, _ bool) bool {
+		groups = append(groups, out.AutoScalingGroups...)
+		return true
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to describe Auto Scaling groups owned by the cluster")
+	}
+	return groups, nil
+}
+
+func (s *Service) ownedLaunchTemplates() ([]*ec2.LaunchTemplate, error) {
+	input := &ec2.DescribeLaunchTemplatesInput{
+		Filters: []*ec2.Filter{filter.EC2.ClusterOwned(s.scope.KubernetesClusterName())},
+	}
+
+	templates := []*ec2.LaunchTemplate{}
+	if err := s.EC2Client.DescribeLaunchTemplatesPagesWithContext(context.TODO(), input, func(out *ec2.DescribeLaunchTemplatesOutput, _ bool) bool {
+		templates = append(templates, out.LaunchTemplates...)
+		return true
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to describe launch templates owned by the cluster")
+	}
+	return templates, nil
+}
+
+// ownedLoadBalancers returns the load balancers owned by the cluster. The classic load balancers which
+// the Resource Groups Tagging API doesn't return are found with the ELB API.
+func (s *Service) ownedLoadBalancers() ([]*discovery.Resource, error) {
+	discoverer := discovery.NewDiscoverer(s.scope.Region(), s.ResourceTaggingClient, s.ELBClient)
+	loadBalancers, err := discoverer.Discover(context.TODO(), infrav1.ClusterTagKey(s.scope.KubernetesClusterName()),
+		[]string{string(infrav1.ResourceLifecycleOwned)}, discovery.ResourceTypeLoadBalancer, discovery.ResourceTypeClassicLoadBalancer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get load balancers owned by the cluster")
+	}
+	return loadBalancers, nil
+}
+
+// loadBalancerCreatedTime returns the creation time of a load balancer, and false if it doesn't exist anymore.
+func (s *Service) loadBalancerCreatedTime(lb *discovery.Resource) (*time.Time, bool, error) {
+	name, classic, err := parseLoadBalancerARN(lb.ARN)
+	if err != nil {
+		return nil, false, err
+	}
+	if classic {
+		out, err := s.ELBClient.DescribeLoadBalancersWithContext(context.TODO(), &elb.DescribeLoadBalancersInput{
+			LoadBalancerNames: aws.StringSlice([]string{name}),
+		})
+		if err != nil {
+			if code, _ := awserrors.Code(err); code == awserrors.LoadBalancerNotFound {
+				return nil, false, nil
+			}
+			return nil, false, errors.Wrapf(err, "failed to describe load balancer %q", name)
+		}
+		if len(out.LoadBalancerDescriptions) == 0 {
+			return nil, false, nil
+		}
+		return out.LoadBalancerDescriptions[0].CreatedTime, true, nil
+	}
+
+	out, err := s.ELBV2Client.DescribeLoadBalancersWithContext(context.TODO(), &elbv2.DescribeLoadBalancersInput{
+		LoadBalancerArns: aws.StringSlice([]string{lb.ARN}),
+	})
+	if err != nil {
+		if code, _ := awserrors.Code(err); code == awserrors.LoadBalancerNotFound {
+			return nil, false, nil
+		}
+		return nil, false, errors.Wrapf(err, "failed to describe load balancer %q", lb.ARN)
+	}
+	if len(out.LoadBalancers) == 0 {
+		return nil, false, nil
+	}
+	return out.LoadBalancers[0].CreatedTime, true, nil
+}
+
+// deleteOrphans deletes the orphaned resources which were already reported by the previous scan, and
+// returns the ones which remain. The Auto Scaling groups are deleted before the launch templates they use.
+func (s *Service) deleteOrphans(orphans []infrav1.OrphanedResource) []infrav1.OrphanedResource {
+	reported := sets.New[infrav1.OrphanedResource](s.scope.OrphanedResources()...)
+
+	remaining := []infrav1.OrphanedResource{}
+	for _, kind := range []infrav1.OrphanedResourceKind{infrav1.OrphanedAutoScalingGroup, infrav1.OrphanedLaunchTemplate, infrav1.OrphanedLoadBalancer} {
+		for _, orphan := range orphans {
+			if orphan.Kind != kind {
+				continue
+			}
+			if !reported.Has(orphan) {
+				remaining = append(remaining, orphan)
+				continue
+			}
+			if err := s.deleteOrphan(orphan); err != nil {
+				record.Warnf(s.scope.InfraCluster(), "FailedDeleteOrphanedResource", "Failed to delete orphaned %s %s: %v", orphan.Kind, orphan.ID, err)
+				remaining = append(remaining, orphan)
+				continue
+			}
+			record.Eventf(s.scope.InfraCluster(), "DeletedOrphanedResource", "Deleted orphaned %s %s", orphan.Kind, orphan.ID)
+		}
+	}
+	return remaining
+}
+
+func (s *Service) deleteOrphan(orphan infrav1.OrphanedResource) error {
+	switch orphan.Kind {
+	case infrav1.OrphanedAutoScalingGroup:
+		_, err := s.ASGClient.DeleteAutoScalingGroupWithContext(context.TODO(), &autoscaling.DeleteAutoScalingGroupInput{
+			AutoScalingGroupName: aws.String(orphan.ID),
+			ForceDelete:          aws.Bool(true),
+		})
+		return err
+	case infrav1.OrphanedLaunchTemplate:
+		_, err := s.EC2Client.DeleteLaunchTemplateWithContext(context.TODO(), &ec2.DeleteLaunchTemplateInput{
+			LaunchTemplateId: aws.String(orphan.ID),
+		})
+		return err
+	case infrav1.OrphanedLoadBalancer:
+		name, classic, err := parseLoadBalancerARN(orphan.ID)
+		if err != nil {
+			return err
+		}
+		if classic {
+			_, err = s.ELBClient.DeleteLoadBalancerWithContext(context.TODO(), &elb.DeleteLoadBalancerInput{
+				LoadBalancerName: aws.String(name),
+			})
+			return err
+		}
+		_, err = s.ELBV2Client.DeleteLoadBalancerWithContext(context.TODO(), &elbv2.DeleteLoadBalancerInput{
+			LoadBalancerArn: aws.String(orphan.ID),
+		})
+		return err
+	}
+	return errors.Errorf("unknown kind of orphaned resource %q", orphan.Kind)
+}
+
+// parseLoadBalancerARN returns the name of a load balancer, and whether it is a classic load balancer,
+// whose resource is loadbalancer/<name> rather than loadbalancer/<type>/<name>/<id>.
+func parseLoadBalancerARN(lbARN string) (string, bool, error) {
+	parsed, err := arn.Parse(lbARN)
+	if err != nil {
+		return "", false, errors.Wrapf(err, "failed to parse load balancer ARN %q", lbARN)
+	}
+	parts := strings.Split(parsed.Resource, "/")
+	switch {
+	case len(parts) == 2 && parts[0] == "loadbalancer":
+		return parts[1], true, nil
+	case len(parts) == 4 && parts[0] == "loadbalancer":
+		return parts[2], false, nil
+	}
+	return "", false, errors.Errorf("unexpected resource %q of load balancer ARN %q", parsed.Resource, lbARN)
+}
+
+// isRecent returns whether a resource was created within the grace period.
+func isRecent(created *time.Time) bool {
+	return created != nil && time.Since(*created) < gracePeriod
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphans
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	rgapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	cgrecord "k8s.io/client-go/tools/record"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/autoscaling/mock_autoscalingiface"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/test/mocks"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+var recorder = cgrecord.NewFakeRecorder(100)
+
+func init() {
+	record.InitFromRecorder(recorder)
+}
+
+const (
+	classicLoadBalancerARN = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/test-cluster-apiserver"
+	networkLoadBalancerARN = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/test-cluster-old/0123456789abcdef"
+	recentLoadBalancerARN  = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/test-cluster-new/fedcba9876543210"
+)
+
+type awsMocks struct {
+	asg       *mock_autoscalingiface.MockAutoScalingAPIMockRecorder
+	ec2       *mocks.MockEC2APIMockRecorder
+	elb       *mocks.MockELBAPIMockRecorder
+	elbv2     *mocks.MockELBV2APIMockRecorder
+	rgtagging *mocks.MockResourceGroupsTaggingAPIAPIMockRecorder
+}
+
+// expectScan expects the resources owned by the cluster to be described. Among them, the Auto Scaling
+// group and launch template of pool-b and the old network load balancer are orphaned. The resources of
+// every kind which were created within the grace period aren't. The creation time of the network load
+// balancers is only described when they aren't referenced by the cluster.
+func (m *awsMocks) expectScan(referencedLoadBalancerARNs ...string) {
+	old := aws.Time(time.Now().Add(-time.Hour))
+	m.asg.DescribeAutoScalingGroupsPagesWithContext(gomock.Any(), &autoscaling.DescribeAutoScalingGroupsInput{
+		Filters: []*autoscaling.Filter{{
+			Name:   aws.String("tag:sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+			Values: aws.StringSlice([]string{"owned"}),
+		}},
+	}, gomock.Any()).DoAndReturn(func(_ aws.Context, _ *autoscaling.DescribeAutoScalingGroupsInput, fn func(*autoscaling.DescribeAutoScalingGroupsOutput, bool) bool, _ ...interface{}) error {
+		fn(&autoscaling.DescribeAutoScalingGroupsOutput{AutoScalingGroups: []*autoscaling.Group{
+			{AutoScalingGroupName: aws.String("pool-a"), CreatedTime: old},
+			{AutoScalingGroupName: aws.String("pool-b"), CreatedTime: old},
+			{AutoScalingGroupName: aws.String("custom-group"), CreatedTime: old},
+			{AutoScalingGroupName: aws.String("adopted-group"), CreatedTime: old},
+			{AutoScalingGroupName: aws.String("recent-pool"), CreatedTime: aws.Time(time.Now())},
+		}}, false)
+		fn(&autoscaling.DescribeAutoScalingGroupsOutput{AutoScalingGroups: []*autoscaling.Group{
+			{AutoScalingGroupName: aws.String("deleted-pool"), CreatedTime: old, Status: aws.String("Delete in progress")},
+		}}, true)
+		return nil
+	})
+	m.ec2.DescribeLaunchTemplatesPagesWithContext(gomock.Any(), &ec2.DescribeLaunchTemplatesInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("tag:sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+			Values: aws.StringSlice([]string{"owned"}),
+		}},
+	}, gomock.Any()).DoAndReturn(func(_ aws.Context, _ *ec2.DescribeLaunchTemplatesInput, fn func(*ec2.DescribeLaunchTemplatesOutput, bool) bool, _ ...interface{}) error {
+		fn(&ec2.DescribeLaunchTemplatesOutput{LaunchTemplates: []*ec2.LaunchTemplate{
+			{LaunchTemplateId: aws.String("lt-a"), LaunchTemplateName: aws.String("pool-a"), CreateTime: old},
+			{LaunchTemplateId: aws.String("lt-b"), LaunchTemplateName: aws.String("pool-b"), CreateTime: old},
+			{LaunchTemplateId: aws.String("lt-c"), LaunchTemplateName: aws.String("pool-c-renamed"), CreateTime: old},
+			{LaunchTemplateId: aws.String("lt-d"), LaunchTemplateName: aws.String("pool-d"), CreateTime: old},
+			{LaunchTemplateId: aws.String("lt-recent"), LaunchTemplateName: aws.String("recent-pool"), CreateTime: aws.Time(time.Now())},
+		}}, true)
+		return nil
+	})
+	m.rgtagging.GetResourcesWithContext(gomock.Any(), &rgapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice([]string{"elasticloadbalancing:loadbalancer"}),
+		TagFilters: []*rgapi.TagFilter{{
+			Key:    aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+			Values: aws.StringSlice([]string{"owned"}),
+		}},
+	}).Return(&rgapi.GetResourcesOutput{ResourceTagMappingList: []*rgapi.ResourceTagMapping{
+		{ResourceARN: aws.String(networkLoadBalancerARN)},
+		{ResourceARN: aws.String(recentLoadBalancerARN)},
+	}}, nil)
+	// The classic load balancer of the API server isn't returned by the Resource Groups Tagging API.
+	m.elb.DescribeLoadBalancersPagesWithContext(gomock.Any(), &elb.DescribeLoadBalancersInput{}, gomock.Any()).
+		DoAndReturn(func(_ aws.Context, _ *elb.DescribeLoadBalancersInput, fn func(*elb.DescribeLoadBalancersOutput, bool) bool, _ ...interface{}) error {
+			fn(&elb.DescribeLoadBalancersOutput{LoadBalancerDescriptions: []*elb.LoadBalancerDescription{
+				{LoadBalancerName: aws.String("test-cluster-apiserver")},
+				{LoadBalancerName: aws.String("other-cluster-apiserver")},
+			}}, true)
+			return nil
+		})
+	m.elb.DescribeTagsWithContext(gomock.Any(), &elb.DescribeTagsInput{
+		LoadBalancerNames: aws.StringSlice([]string{"test-cluster-apiserver", "other-cluster-apiserver"}),
+	}).Return(&elb.DescribeTagsOutput{TagDescriptions: []*elb.TagDescription{
+		{
+			LoadBalancerName: aws.String("test-cluster-apiserver"),
+			Tags:             []*elb.Tag{{Key: aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"), Value: aws.String("owned")}},
+		},
+		{
+			LoadBalancerName: aws.String("other-cluster-apiserver"),
+			Tags:             []*elb.Tag{{Key: aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/other-cluster"), Value: aws.String("owned")}},
+		},
+	}}, nil)
+	for arn, created := range map[string]*time.Time{networkLoadBalancerARN: old, recentLoadBalancerARN: aws.Time(time.Now())} {
+		if sets.New(referencedLoadBalancerARNs...).Has(arn) {
+			continue
+		}
+		m.elbv2.DescribeLoadBalancersWithContext(gomock.Any(), &elbv2.DescribeLoadBalancersInput{LoadBalancerArns: aws.StringSlice([]string{arn})}).
+			Return(&elbv2.DescribeLoadBalancersOutput{LoadBalancers: []*elbv2.LoadBalancer{{LoadBalancerArn: aws.String(arn), CreatedTime: created}}}, nil)
+	}
+}
+
+func TestReconcileOrphans(t *testing.T) {
+	// pool-a still exists, pool-c still references its launch template, pool-d gives its Auto Scaling
+	// group a custom name and pool-e adopted a pre-existing group, while pool-b was force-deleted.
+	pools := []client.Object{
+		&expinfrav1.AWSMachinePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool-a", Namespace: "default", Labels: map[string]string{clusterv1.ClusterNameLabel: "test-cluster"}},
+		},
+		&expinfrav1.AWSMachinePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool-c", Namespace: "default", Labels: map[string]string{clusterv1.ClusterNameLabel: "test-cluster"}},
+			Status:     expinfrav1.AWSMachinePoolStatus{LaunchTemplateID: "lt-c"},
+		},
+		&expinfrav1.AWSMachinePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool-d", Namespace: "default", Labels: map[string]string{clusterv1.ClusterNameLabel: "test-cluster"}},
+			Spec:       expinfrav1.AWSMachinePoolSpec{AutoScalingGroupName: "custom-group"},
+		},
+		&expinfrav1.AWSMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pool-e",
+				Namespace:   "default",
+				Labels:      map[string]string{clusterv1.ClusterNameLabel: "test-cluster"},
+				Annotations: map[string]string{expinfrav1.AdoptAutoScalingGroupAnnotation: "true"},
+			},
+			Status: expinfrav1.AWSMachinePoolStatus{ASGName: "adopted-group"},
+		},
+	}
+	orphans := []infrav1.OrphanedResource{
+		{Kind: infrav1.OrphanedAutoScalingGroup, ID: "pool-b", Name: "pool-b"},
+		{Kind: infrav1.OrphanedLaunchTemplate, ID: "lt-b", Name: "pool-b"},
+		{Kind: infrav1.OrphanedLoadBalancer, ID: networkLoadBalancerARN, Name: "test-cluster-old"},
+	}
+
+	tests := []struct {
+		name         string
+		annotation   string
+		allowCleanup bool
+		status       infrav1.AWSClusterStatus
+		expect       func(m *awsMocks)
+		wantOrphans  []infrav1.OrphanedResource
+		wantScanned  bool
+		wantEvents   []string
+	}{
+		{
+			name: "no annotation clears the status",
+			status: infrav1.AWSClusterStatus{
+				OrphanedResources:  orphans,
+				LastOrphanScanTime: &metav1.Time{Time: time.Now()},
+			},
+		},
+		{
+			name:       "invalid annotation",
+			annotation: "yes",
+			wantEvents: []string{`Warning InvalidScanOrphansAnnotation Ignoring value "yes"`},
+		},
+		{
+			name:       "scan within the interval of the last scan is skipped",
+			annotation: "true",
+			status: infrav1.AWSClusterStatus{
+				OrphanedResources:  orphans[:1],
+				LastOrphanScanTime: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+			},
+			wantOrphans: orphans[:1],
+			wantScanned: true,
+		},
+		{
+			name:        "scan reports the resources no object references",
+			annotation:  "true",
+			expect:      func(m *awsMocks) { m.expectScan() },
+			wantOrphans: orphans,
+			wantScanned: true,
+			wantEvents: []string{
+				"Warning OrphanedResourceFound AutoScalingGroup pool-b",
+				"Warning OrphanedResourceFound LaunchTemplate lt-b",
+				"Warning OrphanedResourceFound LoadBalancer " + networkLoadBalancerARN,
+			},
+		},
+		{
+			name:       "scan doesn't report the load balancers of a migration of the control plane load balancer",
+			annotation: "true",
+			status: infrav1.AWSClusterStatus{
+				ControlPlaneLoadBalancerMigration: &infrav1.LoadBalancerMigration{
+					Phase:                   infrav1.LoadBalancerMigrationPhaseWaitingForHealthyTargets,
+					ClassicLoadBalancerName: "test-cluster-apiserver",
+					LoadBalancerName:        "test-cluster-old",
+				},
+			},
+			expect:      func(m *awsMocks) { m.expectScan(networkLoadBalancerARN) },
+			wantOrphans: orphans[:2],
+			wantScanned: true,
+			wantEvents: []string{
+				"Warning OrphanedResourceFound AutoScalingGroup pool-b",
+				"Warning OrphanedResourceFound LaunchTemplate lt-b",
+			},
+		},
+		{
+			name:       "clean isn't allowed",
+			annotation: "clean",
+			status: infrav1.AWSClusterStatus{
+				OrphanedResources:  orphans,
+				LastOrphanScanTime: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+			},
+			expect:      func(m *awsMocks) { m.expectScan() },
+			wantOrphans: orphans,
+			wantScanned: true,
+			wantEvents: []string{
+				"Warning OrphanCleanupNotAllowed Not deleting 3 orphaned resources",
+				"Warning OrphanedResourceFound AutoScalingGroup pool-b",
+				"Warning OrphanedResourceFound LaunchTemplate lt-b",
+				"Warning OrphanedResourceFound LoadBalancer " + networkLoadBalancerARN,
+			},
+		},
+		{
+			name:         "clean deletes the orphaned resources reported by the previous scan",
+			annotation:   "clean",
+			allowCleanup: true,
+			status: infrav1.AWSClusterStatus{
+				OrphanedResources:  orphans[1:],
+				LastOrphanScanTime: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+			},
+			expect: func(m *awsMocks) {
+				m.expectScan()
+				m.ec2.DeleteLaunchTemplateWithContext(gomock.Any(), &ec2.DeleteLaunchTemplateInput{LaunchTemplateId: aws.String("lt-b")}).
+					Return(&ec2.DeleteLaunchTemplateOutput{}, nil)
+				m.elbv2.DeleteLoadBalancerWithContext(gomock.Any(), &elbv2.DeleteLoadBalancerInput{LoadBalancerArn: aws.String(networkLoadBalancerARN)}).
+					Return(&elbv2.DeleteLoadBalancerOutput{}, nil)
+			},
+			wantOrphans: orphans[:1],
+			wantScanned: true,
+			wantEvents: []string{
+				"Normal DeletedOrphanedResource Deleted orphaned LaunchTemplate lt-b",
+				"Normal DeletedOrphanedResource Deleted orphaned LoadBalancer " + networkLoadBalancerARN,
+				"Warning OrphanedResourceFound AutoScalingGroup pool-b",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachinePool, true)()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			_ = expinfrav1.AddToScheme(scheme)
+			awsCluster := &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+				Status:     tt.status,
+			}
+			awsCluster.Status.Network.APIServerELB = infrav1.LoadBalancer{Name: "test-cluster-apiserver"}
+			if tt.annotation != "" {
+				awsCluster.Annotations = map[string]string{infrav1.ScanOrphansAnnotation: tt.annotation}
+			}
+			cs, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(pools...).Build(),
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+				},
+				AWSCluster: awsCluster,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+			ec2Mock := mocks.NewMockEC2API(mockCtrl)
+			elbMock := mocks.NewMockELBAPI(mockCtrl)
+			elbv2Mock := mocks.NewMockELBV2API(mockCtrl)
+			rgtaggingMock := mocks.NewMockResourceGroupsTaggingAPIAPI(mockCtrl)
+			if tt.expect != nil {
+				tt.expect(&awsMocks{
+					asg:       asgMock.EXPECT(),
+					ec2:       ec2Mock.EXPECT(),
+					elb:       elbMock.EXPECT(),
+					elbv2:     elbv2Mock.EXPECT(),
+					rgtagging: rgtaggingMock.EXPECT(),
+				})
+			}
+			s := NewService(cs, tt.allowCleanup)
+			s.ASGClient = asgMock
+			s.EC2Client = ec2Mock
+			s.ELBClient = elbMock
+			s.ELBV2Client = elbv2Mock
+			s.ResourceTaggingClient = rgtaggingMock
+
+			g.Expect(s.ReconcileOrphans()).To(Succeed())
+
+			g.Expect(awsCluster.Status.OrphanedResources).To(Equal(tt.wantOrphans))
+			g.Expect(awsCluster.Status.LastOrphanScanTime != nil).To(Equal(tt.wantScanned))
+
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			g.Expect(events).To(HaveLen(len(tt.wantEvents)), strings.Join(events, "\n"))
+			for i, event := range events {
+				g.Expect(event).To(HavePrefix(tt.wantEvents[i]))
+			}
+		})
+	}
+}
+
+func TestParseLoadBalancerARN(t *testing.T) {
+	g := NewWithT(t)
+
+	name, classic, err := parseLoadBalancerARN(classicLoadBalancerARN)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(name).To(Equal("test-cluster-apiserver"))
+	g.Expect(classic).To(BeTrue())
+
+	name, classic, err = parseLoadBalancerARN(networkLoadBalancerARN)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(name).To(Equal("test-cluster-old"))
+	g.Expect(classic).To(BeFalse())
+
+	_, _, err = parseLoadBalancerARN("arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/tg/0123456789abcdef")
+	g.Expect(err).To(HaveOccurred())
+}
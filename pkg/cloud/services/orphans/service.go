@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package orphans provides a way to find and delete the AWS resources owned by a cluster which no
+// object of the cluster references anymore.
+package orphans
+
+import (
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+)
+
+// Service holds a collection of interfaces.
+// The interfaces are broken down like this to group functions together.
+type Service struct {
+	scope                 scope.OrphansScope
+	allowCleanup          bool
+	ASGClient             autoscalingiface.AutoScalingAPI
+	EC2Client             ec2iface.EC2API
+	ELBClient             elbiface.ELBAPI
+	ELBV2Client           elbv2iface.ELBV2API
+	ResourceTaggingClient resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
+}
+
+// NewService returns a new service given the api clients. The orphaned resources are only deleted
+// when allowCleanup is true.
+func NewService(orphansScope scope.OrphansScope, allowCleanup bool) *Service {
+	return &Service{
+		scope:                 orphansScope,
+		allowCleanup:          allowCleanup,
+		ASGClient:             scope.NewASGClient(orphansScope, orphansScope, orphansScope, orphansScope.InfraCluster()),
+		EC2Client:             scope.NewEC2Client(orphansScope, orphansScope, orphansScope, orphansScope.InfraCluster()),
+		ELBClient:             scope.NewELBClient(orphansScope, orphansScope, orphansScope, orphansScope.InfraCluster()),
+		ELBV2Client:           scope.NewELBv2Client(orphansScope, orphansScope, orphansScope, orphansScope.InfraCluster()),
+		ResourceTaggingClient: scope.NewResourgeTaggingClient(orphansScope, orphansScope, orphansScope, orphansScope.InfraCluster()),
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/util/system"
+)
+
+// partition holds the S3 behaviours which differ between AWS partitions. The endpoints themselves,
+// including their DNS suffix such as amazonaws.com.cn, are resolved by the SDK for the region.
+type partition struct {
+	// id is the ID of the partition, used in the ARNs of the bucket.
+	id string
+
+	// pathStyle is true when objects must be addressed as <endpoint>/<bucket>/<key> rather than
+	// <bucket>.<endpoint>/<key>. Virtual-hosted-style requests to the China regions require an
+	// ICP license for the bucket domain, which instances fetching bootstrap data don't have.
+	pathStyle bool
+
+	// dualStack is false when the partition has no dual-stack S3 endpoints, in which case they are
+	// disabled even if requested through the shared AWS configuration.
+	dualStack bool
+}
+
+// partitionForRegion returns the S3 behaviours of the partition of a region.
+func partitionForRegion(region string) partition {
+	p := partition{
+		id:        system.GetPartitionFromRegion(region),
+		dualStack: true,
+	}
+	// The SDK also knows the regions added to a partition after the ones listed by the system package.
+	if sdkPartition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		p.id = sdkPartition.ID()
+	}
+
+	switch p.id {
+	case endpoints.AwsCnPartitionID:
+		p.pathStyle = true
+	case endpoints.AwsIsoPartitionID, endpoints.AwsIsoBPartitionID:
+		p.dualStack = false
+	}
+
+	return p
+}
+
+// config returns the configuration of the S3 client for the partition.
+func (p partition) config() *aws.Config {
+	cfg := aws.NewConfig().WithS3ForcePathStyle(p.pathStyle)
+	if !p.dualStack {
+		cfg.UseDualStackEndpoint = endpoints.DualStackEndpointStateDisabled
+	}
+	return cfg
+}
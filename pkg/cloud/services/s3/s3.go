@@ -36,7 +36,6 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	iam "sigs.k8s.io/cluster-api-provider-aws/v2/iam/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
-	"sigs.k8s.io/cluster-api-provider-aws/v2/util/system"
 )
 
 // AWSDefaultRegion is the default AWS region.
@@ -47,17 +46,20 @@ const AWSDefaultRegion string = "us-east-1"
 // One alternative is to have a large list of functions from the ec2 client.
 type Service struct {
 	scope     scope.S3Scope
+	partition partition
 	S3Client  s3iface.S3API
 	STSClient stsiface.STSAPI
 }
 
 // NewService returns a new service given the api clients.
 func NewService(s3Scope scope.S3Scope) *Service {
-	s3Client := scope.NewS3Client(s3Scope, s3Scope, s3Scope, s3Scope.InfraCluster())
+	partition := partitionForRegion(s3Scope.Region())
+	s3Client := scope.NewS3Client(s3Scope, s3Scope, s3Scope, s3Scope.InfraCluster(), partition.config())
 	STSClient := scope.NewSTSClient(s3Scope, s3Scope, s3Scope, s3Scope.InfraCluster())
 
 	return &Service{
 		scope:     s3Scope,
+		partition: partition,
 		S3Client:  s3Client,
 		STSClient: STSClient,
 	}
@@ -151,6 +153,7 @@ func (s *Service) Create(m *scope.MachineScope, data []byte) (string, error) {
 	}
 
 	if exp := s.scope.Bucket().PresignedURLDuration; exp != nil {
+		// The S3 client is configured for the partition, so the URL uses its regional endpoint.
 		s.scope.Info("Generating presigned URL", "bucket_name", bucket, "key", key)
 		req, _ := s.S3Client.GetObjectRequest(&s3.GetObjectInput{
 			Bucket: aws.String(bucket),
@@ -327,7 +330,7 @@ func (s *Service) bucketPolicy(bucketName string) (string, error) {
 	}
 
 	bucket := s.scope.Bucket()
-	partition := system.GetPartitionFromRegion(s.scope.Region())
+	partition := s.partition.id
 
 	statements := []iam.StatementEntry{
 		{
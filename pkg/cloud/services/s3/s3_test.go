@@ -20,13 +20,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	s3svc "github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/golang/mock/gomock"
@@ -219,6 +223,53 @@ func TestReconcileBucket(t *testing.T) {
 		}
 	})
 
+	t.Run("creates_bucket_with_policy_using_partition_of_region", func(t *testing.T) {
+		t.Parallel()
+
+		for region, partition := range map[string]string{
+			"us-west-2":      "aws",
+			"us-gov-west-1":  "aws-us-gov",
+			"cn-north-1":     "aws-cn",
+			"cn-northwest-1": "aws-cn",
+			"us-iso-east-1":  "aws-iso",
+		} {
+			region, partition := region, partition
+			t.Run(region, func(t *testing.T) {
+				t.Parallel()
+
+				bucketName := "bar"
+
+				svc, s3Mock := testService(t, &testServiceInput{
+					Region: region,
+					Bucket: &infrav1.S3Bucket{
+						Name:                     bucketName,
+						NodesIAMInstanceProfiles: []string{fmt.Sprintf("nodes%s", iamv1.DefaultNameSuffix)},
+					},
+				})
+
+				s3Mock.EXPECT().CreateBucket(gomock.Any()).Return(nil, nil).Times(1)
+				s3Mock.EXPECT().PutBucketTagging(gomock.Any()).Return(nil, nil).Times(1)
+				s3Mock.EXPECT().PutBucketPolicy(gomock.Any()).Do(func(input *s3svc.PutBucketPolicyInput) {
+					policy := *input.Policy
+
+					for _, arn := range []string{
+						fmt.Sprintf("arn:%s:s3:::%s/*", partition, bucketName),
+						fmt.Sprintf("arn:%s:s3:::%s/node/*", partition, bucketName),
+						fmt.Sprintf("arn:%s:iam::foo:role/nodes%s", partition, iamv1.DefaultNameSuffix),
+					} {
+						if !strings.Contains(policy, arn) {
+							t.Errorf("Expected policy to contain %q, got: %v", arn, policy)
+						}
+					}
+				}).Return(nil, nil).Times(1)
+
+				if err := svc.ReconcileBucket(); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			})
+		}
+	})
+
 	t.Run("is_idempotent", func(t *testing.T) {
 		t.Parallel()
 
@@ -634,6 +685,100 @@ func TestCreateObject(t *testing.T) {
 	})
 }
 
+// TestCreateObjectPresignedURL generates presigned URLs with the S3 client of the service,
+// whose requests are answered locally, to check their shape in each partition.
+func TestCreateObjectPresignedURL(t *testing.T) {
+	t.Parallel()
+
+	const (
+		bucketName = "foo"
+		nodeName   = "aws-test1"
+	)
+
+	testCases := []struct {
+		region       string
+		expectedHost string
+		expectedPath string
+	}{
+		{
+			region:       "us-west-2",
+			expectedHost: "foo.s3.us-west-2.amazonaws.com",
+			expectedPath: "/node/aws-test1",
+		},
+		{
+			region:       "us-gov-west-1",
+			expectedHost: "foo.s3.us-gov-west-1.amazonaws.com",
+			expectedPath: "/node/aws-test1",
+		},
+		{
+			region:       "cn-north-1",
+			expectedHost: "s3.cn-north-1.amazonaws.com.cn",
+			expectedPath: "/foo/node/aws-test1",
+		},
+		{
+			region:       "cn-northwest-1",
+			expectedHost: "s3.cn-northwest-1.amazonaws.com.cn",
+			expectedPath: "/foo/node/aws-test1",
+		},
+		{
+			region:       "us-iso-east-1",
+			expectedHost: "foo.s3.us-iso-east-1.c2s.ic.gov",
+			expectedPath: "/node/aws-test1",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.region, func(t *testing.T) {
+			t.Parallel()
+
+			svc := testServiceWithLocalS3(t, &testServiceInput{
+				Region: tc.region,
+				Bucket: &infrav1.S3Bucket{
+					Name:                 bucketName,
+					PresignedURLDuration: &metav1.Duration{Duration: time.Hour},
+				},
+			})
+
+			machineScope := &scope.MachineScope{
+				Machine: &clusterv1.Machine{},
+				AWSMachine: &infrav1.AWSMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: nodeName,
+					},
+				},
+			}
+
+			bootstrapDataURL, err := svc.Create(machineScope, []byte("foo"))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			parsedURL, err := url.Parse(bootstrapDataURL)
+			if err != nil {
+				t.Fatalf("Parsing URL %q: %v", bootstrapDataURL, err)
+			}
+
+			if parsedURL.Scheme != "https" {
+				t.Errorf("Unexpected URL scheme, expected %q, got %q", "https", parsedURL.Scheme)
+			}
+
+			if parsedURL.Host != tc.expectedHost {
+				t.Errorf("Unexpected URL host, expected %q, got %q", tc.expectedHost, parsedURL.Host)
+			}
+
+			if parsedURL.Path != tc.expectedPath {
+				t.Errorf("Unexpected URL path, expected %q, got %q", tc.expectedPath, parsedURL.Path)
+			}
+
+			credentialScope := fmt.Sprintf("/%s/s3/aws4_request", tc.region)
+			if credential := parsedURL.Query().Get("X-Amz-Credential"); !strings.HasSuffix(credential, credentialScope) {
+				t.Errorf("Expected URL to be signed for %q, got credential %q", credentialScope, credential)
+			}
+		})
+	}
+}
+
 func TestDeleteObject(t *testing.T) {
 	t.Parallel()
 
@@ -797,6 +942,30 @@ func TestDeleteObject(t *testing.T) {
 	})
 }
 
+// testServiceWithLocalS3 returns a service keeping the S3 client configured for the region, with
+// static credentials and requests answered locally instead of being sent to AWS.
+func testServiceWithLocalS3(t *testing.T, si *testServiceInput) *s3.Service {
+	t.Helper()
+
+	svc := s3.NewService(testScope(t, si))
+
+	s3Client, ok := svc.S3Client.(*s3svc.S3)
+	if !ok {
+		t.Fatalf("Expected S3 client of type %T, got %T", &s3svc.S3{}, svc.S3Client)
+	}
+	s3Client.Config.Credentials = credentials.NewStaticCredentials("AKID", "SECRET", "")
+	s3Client.Handlers.Send.Clear()
+	s3Client.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader("")),
+		}
+	})
+
+	return svc
+}
+
 type testServiceInput struct {
 	Bucket *infrav1.S3Bucket
 	Region string
@@ -814,6 +983,16 @@ func testService(t *testing.T, si *testServiceInput) (*s3.Service, *mock_s3iface
 	getCallerIdentityResult := &sts.GetCallerIdentityOutput{Account: aws.String("foo")}
 	stsMock.EXPECT().GetCallerIdentity(gomock.Any()).Return(getCallerIdentityResult, nil).AnyTimes()
 
+	svc := s3.NewService(testScope(t, si))
+	svc.S3Client = s3Mock
+	svc.STSClient = stsMock
+
+	return svc, s3Mock
+}
+
+func testScope(t *testing.T, si *testServiceInput) *scope.ClusterScope {
+	t.Helper()
+
 	scheme := runtime.NewScheme()
 	_ = infrav1.AddToScheme(scheme)
 	client := fake.NewClientBuilder().WithScheme(scheme).Build()
@@ -847,9 +1026,5 @@ func testService(t *testing.T, si *testServiceInput) (*s3.Service, *mock_s3iface
 		t.Fatalf("Failed to create test context: %v", err)
 	}
 
-	svc := s3.NewService(scope)
-	svc.S3Client = s3Mock
-	svc.STSClient = stsMock
-
-	return svc, s3Mock
+	return scope
 }
@@ -0,0 +1,294 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package securitygroup
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+)
+
+// RuleLimitExceededError is returned when a security group has reached the maximum
+// number of rules allowed per security group.
+type RuleLimitExceededError struct {
+	GroupID   string
+	GroupName string
+	Role      infrav1.SecurityGroupRole
+	Err       error
+}
+
+func (e *RuleLimitExceededError) Error() string {
+	return fmt.Sprintf("security group %q (%s) for role %q has reached the maximum number of rules per security group: %v", e.GroupID, e.GroupName, e.Role, e.Err)
+}
+
+func (e *RuleLimitExceededError) Unwrap() error {
+	return e.Err
+}
+
+// IsRuleLimitExceeded returns true if the error reports a security group that
+// reached the maximum number of rules per security group.
+func IsRuleLimitExceeded(err error) bool {
+	var limitErr *RuleLimitExceededError
+	return errors.As(err, &limitErr)
+}
+
+type sourceKind int
+
+const (
+	sourceIPv4 sourceKind = iota
+	sourceIPv6
+	sourceSecurityGroup
+)
+
+// ruleEntry is a single source of an ingress rule. EC2 counts every entry
+// against the rules per security group quota.
+type ruleEntry struct {
+	protocol    infrav1.SecurityGroupProtocol
+	description string
+	fromPort    int64
+	toPort      int64
+	kind        sourceKind
+	source      string
+}
+
+func (e ruleEntry) less(o ruleEntry) bool {
+	switch {
+	case e.protocol != o.protocol:
+		return e.protocol < o.protocol
+	case e.description != o.description:
+		return e.description < o.description
+	case e.kind != o.kind:
+		return e.kind < o.kind
+	case e.source != o.source:
+		return e.source < o.source
+	case e.fromPort != o.fromPort:
+		return e.fromPort < o.fromPort
+	default:
+		return e.toPort < o.toPort
+	}
+}
+
+// compactIngressRules returns a set of ingress rules allowing exactly the same traffic as
+// the given rules, using as few security group rule entries as possible. Contiguous or
+// overlapping port ranges for the same source are merged, and CIDR blocks sharing the same
+// ports are aggregated into the smallest set of covering networks. Every returned rule has
+// a single source, matching the rules described by EC2, and the result is sorted so that
+// the compaction is deterministic.
+func compactIngressRules(rules infrav1.IngressRules) infrav1.IngressRules {
+	entries := ruleEntries(rules)
+	for {
+		before := len(entries)
+		entries = mergePortRanges(aggregateCIDRBlocks(entries))
+		if len(entries) == before {
+			break
+		}
+	}
+
+	res := make(infrav1.IngressRules, 0, len(entries))
+	for _, e := range entries {
+		rule := infrav1.IngressRule{
+			Description: e.description,
+			Protocol:    e.protocol,
+			FromPort:    e.fromPort,
+			ToPort:      e.toPort,
+		}
+		switch e.kind {
+		case sourceIPv4:
+			rule.CidrBlocks = []string{e.source}
+		case sourceIPv6:
+			rule.IPv6CidrBlocks = []string{e.source}
+		case sourceSecurityGroup:
+			rule.SourceSecurityGroupIDs = []string{e.source}
+		}
+		res = append(res, rule)
+	}
+	return res
+}
+
+// ruleEntries splits the ingress rules into sorted, de-duplicated entries.
+func ruleEntries(rules infrav1.IngressRules) []ruleEntry {
+	seen := map[ruleEntry]struct{}{}
+	var entries []ruleEntry
+	add := func(rule infrav1.IngressRule, kind sourceKind, source string) {
+		e := ruleEntry{
+			protocol:    rule.Protocol,
+			description: rule.Description,
+			kind:        kind,
+			source:      source,
+		}
+		if hasPorts(rule.Protocol) {
+			e.fromPort, e.toPort = rule.FromPort, rule.ToPort
+		}
+		if _, ok := seen[e]; ok {
+			return
+		}
+		seen[e] = struct{}{}
+		entries = append(entries, e)
+	}
+
+	for _, rule := range rules {
+		for _, cidr := range rule.CidrBlocks {
+			add(rule, sourceIPv4, cidr)
+		}
+		for _, cidr := range rule.IPv6CidrBlocks {
+			add(rule, sourceIPv6, cidr)
+		}
+		for _, id := range rule.SourceSecurityGroupIDs {
+			add(rule, sourceSecurityGroup, id)
+		}
+	}
+
+	sortEntries(entries)
+	return entries
+}
+
+func sortEntries(entries []ruleEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].less(entries[j])
+	})
+}
+
+// hasPorts returns true for protocols where the port range applies.
+func hasPorts(protocol infrav1.SecurityGroupProtocol) bool {
+	switch protocol {
+	case infrav1.SecurityGroupProtocolTCP,
+		infrav1.SecurityGroupProtocolUDP,
+		infrav1.SecurityGroupProtocolICMP,
+		infrav1.SecurityGroupProtocolICMPv6:
+		return true
+	}
+	return false
+}
+
+// mergePortRanges merges the contiguous or overlapping TCP and UDP port ranges of
+// entries that only differ by their ports. ICMP entries are left untouched, as EC2
+// uses the port fields for the ICMP type and code.
+func mergePortRanges(entries []ruleEntry) []ruleEntry {
+	var res []ruleEntry
+	for _, e := range entries {
+		if len(res) > 0 {
+			last := &res[len(res)-1]
+			mergeable := e.protocol == infrav1.SecurityGroupProtocolTCP || e.protocol == infrav1.SecurityGroupProtocolUDP
+			if mergeable && last.protocol == e.protocol && last.description == e.description &&
+				last.kind == e.kind && last.source == e.source && e.fromPort <= last.toPort+1 {
+				if e.toPort > last.toPort {
+					last.toPort = e.toPort
+				}
+				continue
+			}
+		}
+		res = append(res, e)
+	}
+	return res
+}
+
+// aggregateCIDRBlocks replaces the CIDR blocks of entries that only differ by their
+// source with the smallest set of networks covering the same addresses.
+func aggregateCIDRBlocks(entries []ruleEntry) []ruleEntry {
+	type groupKey struct {
+		protocol    infrav1.SecurityGroupProtocol
+		description string
+		fromPort    int64
+		toPort      int64
+		kind        sourceKind
+	}
+
+	groups := map[groupKey][]netip.Prefix{}
+	var res []ruleEntry
+	for _, e := range entries {
+		if e.kind == sourceSecurityGroup {
+			res = append(res, e)
+			continue
+		}
+		prefix, err := netip.ParsePrefix(e.source)
+		if err != nil {
+			// Leave anything we can't parse to EC2 validation.
+			res = append(res, e)
+			continue
+		}
+		key := groupKey{protocol: e.protocol, description: e.description, fromPort: e.fromPort, toPort: e.toPort, kind: e.kind}
+		groups[key] = append(groups[key], prefix.Masked())
+	}
+
+	for key, prefixes := range groups {
+		for _, prefix := range aggregatePrefixes(prefixes) {
+			res = append(res, ruleEntry{
+				protocol:    key.protocol,
+				description: key.description,
+				fromPort:    key.fromPort,
+				toPort:      key.toPort,
+				kind:        key.kind,
+				source:      prefix.String(),
+			})
+		}
+	}
+
+	sortEntries(res)
+	return res
+}
+
+// aggregatePrefixes returns the smallest set of prefixes covering the same addresses
+// as the given ones, by dropping prefixes contained in others and joining sibling
+// prefixes into their parent.
+func aggregatePrefixes(prefixes []netip.Prefix) []netip.Prefix {
+	sortPrefixes := func(p []netip.Prefix) {
+		sort.Slice(p, func(i, j int) bool {
+			if c := p[i].Addr().Compare(p[j].Addr()); c != 0 {
+				return c < 0
+			}
+			return p[i].Bits() < p[j].Bits()
+		})
+	}
+
+	for {
+		sortPrefixes(prefixes)
+
+		// Sorted by address then prefix length, a prefix is contained in another one
+		// only if it is contained in the last kept one.
+		var kept []netip.Prefix
+		for _, p := range prefixes {
+			if len(kept) > 0 && kept[len(kept)-1].Contains(p.Addr()) {
+				continue
+			}
+			kept = append(kept, p)
+		}
+
+		var joined []netip.Prefix
+		changed := false
+		for i := 0; i < len(kept); i++ {
+			if i+1 < len(kept) && kept[i].Bits() > 0 && kept[i].Bits() == kept[i+1].Bits() {
+				parent := netip.PrefixFrom(kept[i].Addr(), kept[i].Bits()-1).Masked()
+				if parent.Addr() == kept[i].Addr() && parent.Contains(kept[i+1].Addr()) {
+					joined = append(joined, parent)
+					changed = true
+					i++
+					continue
+				}
+			}
+			joined = append(joined, kept[i])
+		}
+
+		prefixes = joined
+		if !changed {
+			return prefixes
+		}
+	}
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package securitygroup
+
+import (
+	"fmt"
+	"math/rand"
+	"net/netip"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
+)
+
+func TestCompactIngressRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  infrav1.IngressRules
+		expect infrav1.IngressRules
+	}{
+		{
+			name: "merges contiguous and overlapping port ranges",
+			input: infrav1.IngressRules{
+				{Description: "node ports", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 30101, ToPort: 30200, SourceSecurityGroupIDs: []string{"sg-1"}},
+				{Description: "node ports", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 30000, ToPort: 30100, SourceSecurityGroupIDs: []string{"sg-1"}},
+				{Description: "node ports", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 30150, ToPort: 30300, SourceSecurityGroupIDs: []string{"sg-1"}},
+				{Description: "node ports", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 30302, ToPort: 30302, SourceSecurityGroupIDs: []string{"sg-1"}},
+			},
+			expect: infrav1.IngressRules{
+				{Description: "node ports", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 30000, ToPort: 30300, SourceSecurityGroupIDs: []string{"sg-1"}},
+				{Description: "node ports", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 30302, ToPort: 30302, SourceSecurityGroupIDs: []string{"sg-1"}},
+			},
+		},
+		{
+			name: "aggregates adjacent and contained CIDR blocks",
+			input: infrav1.IngressRules{
+				{Description: "api", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 6443, ToPort: 6443, CidrBlocks: []string{"10.0.0.0/25", "10.0.1.5/32"}},
+				{Description: "api", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 6443, ToPort: 6443, CidrBlocks: []string{"10.0.0.128/25", "10.0.1.0/24"}},
+				{Description: "api", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 6443, ToPort: 6443, CidrBlocks: []string{"10.0.3.0/24"}},
+				{Description: "api", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 6443, ToPort: 6443, IPv6CidrBlocks: []string{"2001:db8::/33", "2001:db8:8000::/33"}},
+			},
+			expect: infrav1.IngressRules{
+				{Description: "api", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 6443, ToPort: 6443, CidrBlocks: []string{"10.0.0.0/23"}},
+				{Description: "api", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 6443, ToPort: 6443, CidrBlocks: []string{"10.0.3.0/24"}},
+				{Description: "api", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 6443, ToPort: 6443, IPv6CidrBlocks: []string{"2001:db8::/32"}},
+			},
+		},
+		{
+			name: "merges port ranges once the CIDR blocks are aggregated",
+			input: infrav1.IngressRules{
+				{Protocol: infrav1.SecurityGroupProtocolUDP, FromPort: 100, ToPort: 199, CidrBlocks: []string{"192.168.0.0/24", "192.168.1.0/24"}},
+				{Protocol: infrav1.SecurityGroupProtocolUDP, FromPort: 200, ToPort: 299, CidrBlocks: []string{"192.168.0.0/23"}},
+			},
+			expect: infrav1.IngressRules{
+				{Protocol: infrav1.SecurityGroupProtocolUDP, FromPort: 100, ToPort: 299, CidrBlocks: []string{"192.168.0.0/23"}},
+			},
+		},
+		{
+			name: "keeps rules with different descriptions, protocols or ICMP codes apart",
+			input: infrav1.IngressRules{
+				{Description: "a", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 1, ToPort: 10, SourceSecurityGroupIDs: []string{"sg-1"}},
+				{Description: "b", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 11, ToPort: 20, SourceSecurityGroupIDs: []string{"sg-1"}},
+				{Description: "a", Protocol: infrav1.SecurityGroupProtocolUDP, FromPort: 11, ToPort: 20, SourceSecurityGroupIDs: []string{"sg-1"}},
+				{Description: "a", Protocol: infrav1.SecurityGroupProtocolICMP, FromPort: 3, ToPort: 4, SourceSecurityGroupIDs: []string{"sg-1"}},
+				{Description: "a", Protocol: infrav1.SecurityGroupProtocolICMP, FromPort: 5, ToPort: 6, SourceSecurityGroupIDs: []string{"sg-1"}},
+			},
+			expect: infrav1.IngressRules{
+				{Description: "a", Protocol: infrav1.SecurityGroupProtocolICMP, FromPort: 3, ToPort: 4, SourceSecurityGroupIDs: []string{"sg-1"}},
+				{Description: "a", Protocol: infrav1.SecurityGroupProtocolICMP, FromPort: 5, ToPort: 6, SourceSecurityGroupIDs: []string{"sg-1"}},
+				{Description: "a", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 1, ToPort: 10, SourceSecurityGroupIDs: []string{"sg-1"}},
+				{Description: "b", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 11, ToPort: 20, SourceSecurityGroupIDs: []string{"sg-1"}},
+				{Description: "a", Protocol: infrav1.SecurityGroupProtocolUDP, FromPort: 11, ToPort: 20, SourceSecurityGroupIDs: []string{"sg-1"}},
+			},
+		},
+		{
+			name: "splits multi-source rules and drops duplicates",
+			input: infrav1.IngressRules{
+				{Description: "etcd", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 2379, ToPort: 2379, SourceSecurityGroupIDs: []string{"sg-2", "sg-1"}},
+				{Description: "etcd", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 2379, ToPort: 2379, SourceSecurityGroupIDs: []string{"sg-1"}},
+				{Description: "all", Protocol: infrav1.SecurityGroupProtocolAll, FromPort: 1, ToPort: 2, SourceSecurityGroupIDs: []string{"sg-1"}},
+			},
+			expect: infrav1.IngressRules{
+				{Description: "all", Protocol: infrav1.SecurityGroupProtocolAll, SourceSecurityGroupIDs: []string{"sg-1"}},
+				{Description: "etcd", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 2379, ToPort: 2379, SourceSecurityGroupIDs: []string{"sg-1"}},
+				{Description: "etcd", Protocol: infrav1.SecurityGroupProtocolTCP, FromPort: 2379, ToPort: 2379, SourceSecurityGroupIDs: []string{"sg-2"}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got := compactIngressRules(tc.input)
+			g.Expect(got).To(Equal(tc.expect))
+			expectEquivalentRules(g, tc.input, got)
+		})
+	}
+}
+
+func TestCompactIngressRulesIsDeterministic(t *testing.T) {
+	g := NewWithT(t)
+	r := rand.New(rand.NewSource(1)) //nolint:gosec
+	rules := randomIngressRules(r, 40)
+	want := compactIngressRules(rules)
+
+	for i := 0; i < 10; i++ {
+		shuffled := append(infrav1.IngressRules{}, rules...)
+		r.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		g.Expect(compactIngressRules(shuffled)).To(Equal(want))
+	}
+
+	// Compacting compacted rules is a no-op.
+	g.Expect(compactIngressRules(want)).To(Equal(want))
+}
+
+func TestCompactIngressRulesIsEquivalent(t *testing.T) {
+	r := rand.New(rand.NewSource(42)) //nolint:gosec
+	for i := 0; i < 50; i++ {
+		t.Run(fmt.Sprintf("random rule set %d", i), func(t *testing.T) {
+			g := NewWithT(t)
+			rules := randomIngressRules(r, 30)
+			compacted := compactIngressRules(rules)
+			g.Expect(len(compacted)).To(BeNumerically("<=", len(ruleEntries(rules))))
+			expectEquivalentRules(g, rules, compacted)
+		})
+	}
+}
+
+func TestIsRuleLimitExceeded(t *testing.T) {
+	g := NewWithT(t)
+	limitErr := &RuleLimitExceededError{
+		GroupID:   "sg-node",
+		GroupName: "test-cluster-node",
+		Role:      infrav1.SecurityGroupNode,
+		Err:       awserr.New(awserrors.RulesPerSecurityGroupLimitExceeded, "The maximum number of rules per security group has been reached.", nil),
+	}
+	g.Expect(IsRuleLimitExceeded(limitErr)).To(BeTrue())
+	g.Expect(IsRuleLimitExceeded(errors.Wrap(limitErr, "failed to reconcile security groups"))).To(BeTrue())
+	g.Expect(IsRuleLimitExceeded(errors.New("some other error"))).To(BeFalse())
+	g.Expect(limitErr.Error()).To(ContainSubstring(`security group "sg-node" (test-cluster-node) for role "node"`))
+}
+
+// randomIngressRules generates rules over a small space of ports, networks and
+// security groups, so that many of them can be compacted.
+func randomIngressRules(r *rand.Rand, n int) infrav1.IngressRules {
+	protocols := []infrav1.SecurityGroupProtocol{
+		infrav1.SecurityGroupProtocolTCP,
+		infrav1.SecurityGroupProtocolUDP,
+		infrav1.SecurityGroupProtocolICMP,
+		infrav1.SecurityGroupProtocolAll,
+	}
+	descriptions := []string{"", "a", "b"}
+
+	rules := make(infrav1.IngressRules, 0, n)
+	for i := 0; i < n; i++ {
+		from := int64(r.Intn(50))
+		rule := infrav1.IngressRule{
+			Description: descriptions[r.Intn(len(descriptions))],
+			Protocol:    protocols[r.Intn(len(protocols))],
+			FromPort:    from,
+			ToPort:      from + int64(r.Intn(10)),
+		}
+		for j := r.Intn(3); j > 0; j-- {
+			bits := 22 + r.Intn(5)
+			addr := netip.AddrFrom4([4]byte{10, 0, byte(r.Intn(4)), byte(r.Intn(256))})
+			rule.CidrBlocks = append(rule.CidrBlocks, netip.PrefixFrom(addr, bits).Masked().String())
+		}
+		for j := r.Intn(2); j > 0; j-- {
+			addr := netip.AddrFrom16([16]byte{0x20, 0x01, 0x0d, 0xb8, byte(r.Intn(4))})
+			rule.IPv6CidrBlocks = append(rule.IPv6CidrBlocks, netip.PrefixFrom(addr, 38+r.Intn(3)).Masked().String())
+		}
+		for j := r.Intn(2); j > 0; j-- {
+			rule.SourceSecurityGroupIDs = append(rule.SourceSecurityGroupIDs, fmt.Sprintf("sg-%d", r.Intn(3)))
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// expectEquivalentRules checks that both rule sets allow exactly the same traffic. The
+// rules only change what they allow at the edges of their port ranges and networks, so
+// probing around every edge of both rule sets covers all the traffic they can allow.
+func expectEquivalentRules(g *WithT, before, after infrav1.IngressRules) {
+	rules := append(append(infrav1.IngressRules{}, before...), after...)
+
+	protocols := map[infrav1.SecurityGroupProtocol]struct{}{}
+	ports := map[int64]struct{}{}
+	sources := map[string]struct{}{}
+	var addrs []netip.Addr
+	for _, rule := range rules {
+		protocols[rule.Protocol] = struct{}{}
+		for _, port := range []int64{rule.FromPort - 1, rule.FromPort, rule.ToPort, rule.ToPort + 1} {
+			ports[port] = struct{}{}
+		}
+		for _, id := range rule.SourceSecurityGroupIDs {
+			sources[id] = struct{}{}
+		}
+		for _, cidr := range append(append([]string{}, rule.CidrBlocks...), rule.IPv6CidrBlocks...) {
+			prefix := netip.MustParsePrefix(cidr).Masked()
+			first, last := prefix.Addr(), lastAddr(prefix)
+			addrs = append(addrs, first, last)
+			if prev := first.Prev(); prev.IsValid() {
+				addrs = append(addrs, prev)
+			}
+			if next := last.Next(); next.IsValid() {
+				addrs = append(addrs, next)
+			}
+		}
+	}
+
+	for protocol := range protocols {
+		for port := range ports {
+			for id := range sources {
+				if want := allowsSecurityGroup(before, protocol, port, id); allowsSecurityGroup(after, protocol, port, id) != want {
+					g.Expect(!want).To(Equal(want), "protocol %s port %d from %s", protocol, port, id)
+				}
+			}
+			for _, addr := range addrs {
+				if want := allowsAddr(before, protocol, port, addr); allowsAddr(after, protocol, port, addr) != want {
+					g.Expect(!want).To(Equal(want), "protocol %s port %d from %s", protocol, port, addr)
+				}
+			}
+		}
+	}
+}
+
+func ruleMatches(rule infrav1.IngressRule, protocol infrav1.SecurityGroupProtocol, port int64) bool {
+	if rule.Protocol != protocol {
+		return false
+	}
+	return !hasPorts(protocol) || (rule.FromPort <= port && port <= rule.ToPort)
+}
+
+func allowsSecurityGroup(rules infrav1.IngressRules, protocol infrav1.SecurityGroupProtocol, port int64, id string) bool {
+	for _, rule := range rules {
+		if !ruleMatches(rule, protocol, port) {
+			continue
+		}
+		for _, source := range rule.SourceSecurityGroupIDs {
+			if source == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func allowsAddr(rules infrav1.IngressRules, protocol infrav1.SecurityGroupProtocol, port int64, addr netip.Addr) bool {
+	for _, rule := range rules {
+		if !ruleMatches(rule, protocol, port) {
+			continue
+		}
+		for _, cidr := range append(append([]string{}, rule.CidrBlocks...), rule.IPv6CidrBlocks...) {
+			if netip.MustParsePrefix(cidr).Contains(addr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	b := prefix.Addr().AsSlice()
+	for i := prefix.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 1 << (7 - i%8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
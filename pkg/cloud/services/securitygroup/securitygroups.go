@@ -28,11 +28,13 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/converters"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/filter"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/eni"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/wait"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/tags"
 	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
@@ -52,6 +54,9 @@ const (
 
 	// IPProtocolICMPv6 is how EC2 represents the ICMPv6 protocol in ingress rules.
 	IPProtocolICMPv6 = "58"
+
+	// windowsCNIVXLANPort is the UDP port of the VXLAN overlay used by the Windows CNI.
+	windowsCNIVXLANPort = 4789
 )
 
 // ReconcileSecurityGroups will reconcile security groups against the Service object.
@@ -165,6 +170,12 @@ func (s *Service) ReconcileSecurityGroups() error {
 			return err
 		}
 
+		if feature.Gates.Enabled(feature.SecurityGroupRuleCompaction) {
+			// The current rules are described with a single source each, so the compacted
+			// rules can be compared with them directly.
+			want = compactIngressRules(want)
+		}
+
 		toRevoke := current.Difference(want)
 		if len(toRevoke) > 0 {
 			if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
@@ -187,6 +198,9 @@ func (s *Service) ReconcileSecurityGroups() error {
 				}
 				return true, nil
 			}, awserrors.GroupNotFound); err != nil {
+				if code, ok := awserrors.Code(errors.Cause(err)); ok && code == awserrors.RulesPerSecurityGroupLimitExceeded {
+					return &RuleLimitExceededError{GroupID: sg.ID, GroupName: sg.Name, Role: role, Err: err}
+				}
 				return err
 			}
 
@@ -312,6 +326,7 @@ func (s *Service) deleteSecurityGroup(sg *infrav1.SecurityGroup, typ string) err
 	}
 
 	if _, err := s.EC2Client.DeleteSecurityGroupWithContext(context.TODO(), input); awserrors.IsIgnorableSecurityGroupError(err) != nil { //nolint:gocritic
+		err = eni.NewService(s.scope, s.EC2Client).ReleaseBlocking(err, filter.EC2.SecurityGroupID(sg.ID))
 		record.Warnf(s.scope.InfraCluster(), "FailedDeleteSecurityGroup", "Failed to delete %s SecurityGroup %q with name %q: %v", typ, sg.ID, sg.Name, err)
 		return errors.Wrapf(err, "failed to delete security group %q with name %q", sg.ID, sg.Name)
 	}
@@ -531,6 +546,24 @@ func (s *Service) defaultSSHIngressRule(sourceSecurityGroupID string) infrav1.In
 	}
 }
 
+// getInstanceConnectEndpointIngressRules returns the rule allowing the EC2 Instance Connect Endpoint of the cluster to
+// connect to the instances, from the security groups the endpoint was created with.
+func (s *Service) getInstanceConnectEndpointIngressRules() infrav1.IngressRules {
+	endpoint, status := s.scope.InstanceConnectEndpoint(), s.scope.InstanceConnectEndpointStatus()
+	if !endpoint.IsEnabled() || status == nil || len(status.SecurityGroupIDs) == 0 {
+		return nil
+	}
+	return infrav1.IngressRules{
+		{
+			Description:            "EC2 Instance Connect Endpoint",
+			Protocol:               infrav1.SecurityGroupProtocolTCP,
+			FromPort:               endpoint.GetPort(),
+			ToPort:                 endpoint.GetPort(),
+			SourceSecurityGroupIDs: status.SecurityGroupIDs,
+		},
+	}
+}
+
 func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (infrav1.IngressRules, error) {
 	// Set source of CNI ingress rules to be control plane and node security groups
 	s.scope.Debug("getting security group ingress rules", "role", role)
@@ -591,6 +624,7 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 		if s.scope.Bastion().Enabled {
 			rules = append(rules, s.defaultSSHIngressRule(s.scope.SecurityGroups()[infrav1.SecurityGroupBastion].ID))
 		}
+		rules = append(rules, s.getInstanceConnectEndpointIngressRules()...)
 
 		ingressRules := s.scope.AdditionalControlPlaneIngressRules()
 		for i := range ingressRules {
@@ -611,6 +645,12 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 		}
 		rules = append(rules, ingressRules...)
 
+		windowsRules, err := s.getIngressRulesForWindowsNodes()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, windowsRules...)
+
 		return append(cniRules, rules...), nil
 
 	case infrav1.SecurityGroupNode:
@@ -637,6 +677,7 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 		if s.scope.Bastion().Enabled {
 			rules = append(rules, s.defaultSSHIngressRule(s.scope.SecurityGroups()[infrav1.SecurityGroupBastion].ID))
 		}
+		rules = append(rules, s.getInstanceConnectEndpointIngressRules()...)
 		if s.scope.VPC().IsIPv6Enabled() {
 			rules = append(rules, infrav1.IngressRule{
 				Description:    "Node Port Services IPv6",
@@ -646,6 +687,16 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 				IPv6CidrBlocks: []string{services.AnyIPv6CidrBlock},
 			})
 		}
+		lbRules, err := s.getNodeIngressRulesForLoadBalancerAttachments()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, lbRules...)
+		windowsRules, err := s.getIngressRulesForWindowsNodes()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, windowsRules...)
 		return append(cniRules, rules...), nil
 	case infrav1.SecurityGroupEKSNodeAdditional:
 		if s.scope.Bastion().Enabled {
@@ -710,6 +761,65 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 	return nil, errors.Errorf("Cannot determine ingress rules for unknown security group role %q", role)
 }
 
+// getNodeIngressRulesForLoadBalancerAttachments returns the rules allowing the control plane network load balancer
+// to reach the nodes on the ports of the additional listeners that machine pools are attached to.
+func (s *Service) getNodeIngressRulesForLoadBalancerAttachments() (infrav1.IngressRules, error) {
+	lbSpec := s.scope.ControlPlaneLoadBalancer()
+	if lbSpec == nil || lbSpec.LoadBalancerType != infrav1.LoadBalancerTypeNLB || len(lbSpec.AdditionalListeners) == 0 {
+		return nil, nil
+	}
+	lbSecurityGroup, ok := s.scope.SecurityGroups()[infrav1.SecurityGroupAPIServerLB]
+	if !ok {
+		return nil, nil
+	}
+
+	ports, err := s.scope.MachinePoolLoadBalancerListenerPorts()
+	if err != nil {
+		return nil, err
+	}
+	attached := sets.New[int64](ports...)
+
+	var rules infrav1.IngressRules
+	for _, ln := range lbSpec.AdditionalListeners {
+		if !attached.Has(ln.Port) {
+			continue
+		}
+		rules = append(rules, infrav1.IngressRule{
+			Description:            fmt.Sprintf("Allow NLB traffic to the machine pool nodes on port %d.", ln.Port),
+			Protocol:               infrav1.SecurityGroupProtocolTCP,
+			FromPort:               ln.Port,
+			ToPort:                 ln.Port,
+			SourceSecurityGroupIDs: []string{lbSecurityGroup.ID},
+		})
+	}
+	return rules, nil
+}
+
+// getIngressRulesForWindowsNodes returns the rules allowing the VXLAN overlay traffic of the Windows CNI
+// between the control plane and the nodes, when machines of the cluster run Windows nodes.
+func (s *Service) getIngressRulesForWindowsNodes() (infrav1.IngressRules, error) {
+	hasWindowsNodes, err := s.scope.HasWindowsNodes()
+	if err != nil {
+		return nil, err
+	}
+	if !hasWindowsNodes {
+		return nil, nil
+	}
+
+	return infrav1.IngressRules{
+		{
+			Description: "VXLAN (Windows CNI)",
+			Protocol:    infrav1.SecurityGroupProtocolUDP,
+			FromPort:    windowsCNIVXLANPort,
+			ToPort:      windowsCNIVXLANPort,
+			SourceSecurityGroupIDs: []string{
+				s.scope.SecurityGroups()[infrav1.SecurityGroupControlPlane].ID,
+				s.scope.SecurityGroups()[infrav1.SecurityGroupNode].ID,
+			},
+		},
+	}, nil
+}
+
 func (s *Service) getSecurityGroupName(clusterName string, role infrav1.SecurityGroupRole) string {
 	groupPrefix := clusterName
 	if strings.HasPrefix(clusterName, "sg-") {
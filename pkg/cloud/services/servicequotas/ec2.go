@@ -0,0 +1,223 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicequotas
+
+import (
+	"context"
+	"slices"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/filter"
+)
+
+const ec2ServiceCode = "ec2"
+
+// onDemandInstanceQuotas are the quotas on the vCPUs of the running on-demand instances, with the
+// instance families they apply to.
+var onDemandInstanceQuotas = []struct {
+	code     string
+	name     string
+	families []string
+}{
+	{code: "L-1216C47A", name: "Running On-Demand Standard (A, C, D, H, I, M, R, T, Z) instances", families: []string{"a", "c", "d", "h", "i", "im", "is", "m", "r", "t", "z"}},
+	{code: "L-DB2E81BA", name: "Running On-Demand G and VT instances", families: []string{"g", "vt"}},
+	{code: "L-417A185B", name: "Running On-Demand P instances", families: []string{"p"}},
+	{code: "L-7295265B", name: "Running On-Demand X instances", families: []string{"x"}},
+	{code: "L-74FC7D96", name: "Running On-Demand F instances", families: []string{"f"}},
+	{code: "L-1945791B", name: "Running On-Demand Inf instances", families: []string{"inf"}},
+}
+
+// ec2Quotas returns the checked quotas of the EC2 service.
+func ec2Quotas() []quota {
+	quotas := []quota{
+		{code: "L-0263D0A3", name: "EC2-VPC Elastic IPs", usage: elasticIPUsage},
+	}
+	for _, q := range onDemandInstanceQuotas {
+		quotas = append(quotas, quota{code: q.code, name: q.name, usage: onDemandInstanceUsage(q.families)})
+	}
+	return quotas
+}
+
+// elasticIPUsage returns the usage of the Elastic IPs, projecting an address for each NAT gateway
+// the cluster is still expected to create that can't reuse a free address of the cluster.
+func elasticIPUsage(c *check) (int64, int64, bool, error) {
+	if c.scope.VPC().IsUnmanaged(c.scope.Name()) {
+		return 0, 0, false, nil
+	}
+
+	out, err := c.EC2Client.DescribeAddressesWithContext(context.TODO(), &ec2.DescribeAddressesInput{})
+	if err != nil {
+		return 0, 0, false, errors.Wrap(err, "failed to describe addresses")
+	}
+
+	var freeClusterAddresses int64
+	for _, address := range out.Addresses {
+		if address.AssociationId == nil && hasTag(address.Tags, infrav1.ClusterTagKey(c.scope.Name())) {
+			freeClusterAddresses++
+		}
+	}
+
+	missing, err := c.missingNatGateways()
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	current := int64(len(out.Addresses))
+	return current, current + max(0, int64(len(missing))-freeClusterAddresses), true, nil
+}
+
+// onDemandInstanceUsage returns a function returning the usage of the vCPUs of the running
+// on-demand instances of the given families. The vCPUs of the instances the cluster is still
+// expected to launch are projected. The quota only applies when the cluster uses one of the families.
+func onDemandInstanceUsage(families []string) func(c *check) (int64, int64, bool, error) {
+	return func(c *check) (int64, int64, bool, error) {
+		pending, err := c.pendingOnDemandInstances()
+		if err != nil {
+			return 0, 0, false, err
+		}
+
+		used := false
+		var pendingVCPUs int64
+		for instanceType, count := range pending {
+			if !slices.Contains(families, instanceFamily(instanceType)) {
+				continue
+			}
+			used = true
+			if count == 0 {
+				continue
+			}
+			vcpus, err := c.vcpus(instanceType)
+			if err != nil {
+				return 0, 0, false, err
+			}
+			pendingVCPUs += count * vcpus
+		}
+		if !used {
+			return 0, 0, false, nil
+		}
+
+		instances, err := c.runningInstances()
+		if err != nil {
+			return 0, 0, false, err
+		}
+
+		var current int64
+		for _, instance := range instances {
+			// Spot and scheduled instances have a lifecycle, on-demand instances don't.
+			if instance.InstanceLifecycle != nil || !slices.Contains(families, instanceFamily(aws.StringValue(instance.InstanceType))) {
+				continue
+			}
+			if instance.CpuOptions != nil && instance.CpuOptions.CoreCount != nil && instance.CpuOptions.ThreadsPerCore != nil {
+				current += aws.Int64Value(instance.CpuOptions.CoreCount) * aws.Int64Value(instance.CpuOptions.ThreadsPerCore)
+				continue
+			}
+			vcpus, err := c.vcpus(aws.StringValue(instance.InstanceType))
+			if err != nil {
+				return 0, 0, false, err
+			}
+			current += vcpus
+		}
+
+		return current, current + pendingVCPUs, true, nil
+	}
+}
+
+// instanceFamily returns the family of an instance type, e.g. m for m5.large or inf for inf1.xlarge.
+func instanceFamily(instanceType string) string {
+	for i, r := range instanceType {
+		if !unicode.IsLetter(r) {
+			return instanceType[:i]
+		}
+	}
+	return instanceType
+}
+
+// pendingOnDemandInstances returns, by instance type used by the cluster, the number of on-demand
+// instances the cluster is still expected to launch.
+func (c *check) pendingOnDemandInstances() (map[string]int64, error) {
+	if c.pendingInstances != nil {
+		return c.pendingInstances, nil
+	}
+
+	pending, err := c.scope.PendingOnDemandInstances()
+	if err != nil {
+		return nil, err
+	}
+	c.pendingInstances = pending
+	return pending, nil
+}
+
+// runningInstances returns the pending and running instances of the region.
+func (c *check) runningInstances() ([]*ec2.Instance, error) {
+	if c.instances != nil {
+		return c.instances, nil
+	}
+
+	instances := []*ec2.Instance{}
+	err := c.EC2Client.DescribeInstancesPagesWithContext(context.TODO(), &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{filter.EC2.InstanceStates(ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning)},
+	}, func(out *ec2.DescribeInstancesOutput, _ bool) bool {
+		for _, reservation := range out.Reservations {
+			instances = append(instances, reservation.Instances...)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe instances")
+	}
+	c.instances = instances
+	return instances, nil
+}
+
+// vcpus returns the default number of vCPUs of an instance type.
+func (c *check) vcpus(instanceType string) (int64, error) {
+	if vcpus, ok := c.instanceTypeVCPUs[instanceType]; ok {
+		return vcpus, nil
+	}
+
+	out, err := c.EC2Client.DescribeInstanceTypesWithContext(context.TODO(), &ec2.DescribeInstanceTypesInput{
+		InstanceTypes: []*string{aws.String(instanceType)},
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to describe instance type %q", instanceType)
+	}
+	if len(out.InstanceTypes) == 0 || out.InstanceTypes[0].VCpuInfo == nil {
+		return 0, errors.Errorf("no vCPU information for instance type %q", instanceType)
+	}
+
+	vcpus := aws.Int64Value(out.InstanceTypes[0].VCpuInfo.DefaultVCpus)
+	if c.instanceTypeVCPUs == nil {
+		c.instanceTypeVCPUs = map[string]int64{}
+	}
+	c.instanceTypeVCPUs[instanceType] = vcpus
+	return vcpus, nil
+}
+
+// hasTag returns whether the tags contain the key.
+func hasTag(tags []*ec2.Tag, key string) bool {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicequotas
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+)
+
+// usageWarningThreshold is the percentage of the limit of a quota above which a warning event is
+// emitted for its projected usage.
+const usageWarningThreshold = 80
+
+// quota is a service quota the cluster depends on.
+type quota struct {
+	// code is the code of the quota in the Service Quotas API.
+	code string
+
+	// name is the name of the quota reported in the status.
+	name string
+
+	// usage returns the current usage of the quota and the usage projected once the cluster is
+	// fully provisioned. It returns false when the cluster doesn't depend on the quota, e.g. when
+	// it doesn't launch instances of the families the quota applies to.
+	usage func(c *check) (current, projected int64, ok bool, err error)
+}
+
+// quotasByService are the checked quotas by code of the AWS service they belong to.
+// A quota is checked by adding it to the list of its service.
+var quotasByService = map[string][]quota{
+	ec2ServiceCode: ec2Quotas(),
+	vpcServiceCode: vpcQuotas,
+}
+
+// ReconcileQuotas checks the service quotas the cluster depends on, at most once per interval of
+// the quota check, and reports their usage in the status of the cluster.
+// A quota whose limit or usage can't be read is skipped, so that the check never blocks the
+// reconciliation of the cluster.
+func (s *Service) ReconcileQuotas() {
+	quotaCheck := s.scope.QuotaCheck()
+	if !quotaCheck.IsEnabled() {
+		if s.scope.LastQuotaCheckTime() != nil {
+			s.scope.SetQuotaUsage(nil, nil)
+		}
+		return
+	}
+
+	if last := s.scope.LastQuotaCheckTime(); last != nil && time.Since(last.Time) < quotaCheck.GetInterval() {
+		return
+	}
+
+	s.scope.Debug("Checking service quotas")
+
+	serviceCodes := make([]string, 0, len(quotasByService))
+	for serviceCode := range quotasByService {
+		serviceCodes = append(serviceCodes, serviceCode)
+	}
+	sort.Strings(serviceCodes)
+
+	c := &check{Service: s}
+	usage := []infrav1.QuotaUsage{}
+	for _, serviceCode := range serviceCodes {
+		for _, q := range quotasByService[serviceCode] {
+			quotaUsage, ok := c.quotaUsage(serviceCode, q)
+			if !ok {
+				continue
+			}
+			usage = append(usage, quotaUsage)
+
+			if quotaUsage.ProjectedUsage*100 > quotaUsage.Limit*usageWarningThreshold {
+				record.Warnf(s.scope.InfraCluster(), "ServiceQuotaUsageHigh", "Projected usage of service quota %q (%s/%s) is %d, above %d%% of its limit of %d",
+					quotaUsage.QuotaName, quotaUsage.ServiceCode, quotaUsage.QuotaCode, quotaUsage.ProjectedUsage, usageWarningThreshold, quotaUsage.Limit)
+			}
+		}
+	}
+
+	now := metav1.Now()
+	s.scope.SetQuotaUsage(usage, &now)
+}
+
+// quotaUsage returns the usage of a quota, and false when the quota is skipped.
+func (c *check) quotaUsage(serviceCode string, q quota) (infrav1.QuotaUsage, bool) {
+	current, projected, ok, err := q.usage(c)
+	if err != nil {
+		c.scope.Debug("Skipping service quota, failed to get its usage", "service", serviceCode, "quota", q.code, "error", err.Error())
+		return infrav1.QuotaUsage{}, false
+	}
+	if !ok {
+		return infrav1.QuotaUsage{}, false
+	}
+
+	out, err := c.ServiceQuotasClient.GetServiceQuotaWithContext(context.TODO(), &servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String(serviceCode),
+		QuotaCode:   aws.String(q.code),
+	})
+	if err != nil {
+		c.scope.Debug("Skipping service quota, failed to get its limit", "service", serviceCode, "quota", q.code, "error", err.Error())
+		return infrav1.QuotaUsage{}, false
+	}
+	if out.Quota == nil || out.Quota.Value == nil {
+		c.scope.Debug("Skipping service quota without a limit", "service", serviceCode, "quota", q.code)
+		return infrav1.QuotaUsage{}, false
+	}
+
+	return infrav1.QuotaUsage{
+		ServiceCode:    serviceCode,
+		QuotaCode:      q.code,
+		QuotaName:      q.name,
+		Limit:          int64(aws.Float64Value(out.Quota.Value)),
+		Usage:          current,
+		ProjectedUsage: max(current, projected),
+	}, true
+}
+
+// check holds the resources shared by the quotas during a quota check, described on first use.
+type check struct {
+	*Service
+
+	pendingInstances  map[string]int64
+	instances         []*ec2.Instance
+	instanceTypeVCPUs map[string]int64
+	natGateways       []*ec2.NatGateway
+}
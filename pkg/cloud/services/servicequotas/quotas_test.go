@@ -0,0 +1,306 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicequotas
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cgrecord "k8s.io/client-go/tools/record"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/feature"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/fakeaws"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+var recorder = cgrecord.NewFakeRecorder(100)
+
+func init() {
+	record.InitFromRecorder(recorder)
+}
+
+func TestReconcileQuotas(t *testing.T) {
+	clusterTag := &ec2.Tag{Key: aws.String(infrav1.ClusterTagKey("test-cluster")), Value: aws.String("owned")}
+	subnets := infrav1.Subnets{
+		{ID: "subnet-public-a", ResourceID: "subnet-public-a", AvailabilityZone: "us-east-1a", IsPublic: true},
+		{ID: "subnet-public-b", ResourceID: "subnet-public-b", AvailabilityZone: "us-east-1b", IsPublic: true},
+		{ID: "subnet-private-a", ResourceID: "subnet-private-a", AvailabilityZone: "us-east-1a"},
+		{ID: "subnet-private-b", ResourceID: "subnet-private-b", AvailabilityZone: "us-east-1b"},
+	}
+
+	tests := []struct {
+		name        string
+		awsCluster  infrav1.AWSCluster
+		objects     []client.Object
+		setup       func(ec2API *fakeaws.EC2API, quotasAPI *fakeaws.ServiceQuotasAPI)
+		wantUsage   []infrav1.QuotaUsage
+		wantChecked bool
+		wantEvents  []string
+	}{
+		{
+			name: "disabled check clears the status",
+			awsCluster: infrav1.AWSCluster{
+				Status: infrav1.AWSClusterStatus{
+					QuotaUsage:         []infrav1.QuotaUsage{{ServiceCode: "ec2", QuotaCode: "L-0263D0A3"}},
+					LastQuotaCheckTime: &metav1.Time{Time: time.Now().Add(-24 * time.Hour)},
+				},
+			},
+		},
+		{
+			name: "check within the interval of the last check is skipped",
+			awsCluster: infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{QuotaCheck: &infrav1.QuotaCheck{Enabled: true}},
+				Status: infrav1.AWSClusterStatus{
+					QuotaUsage:         []infrav1.QuotaUsage{{ServiceCode: "ec2", QuotaCode: "L-0263D0A3", Limit: 5}},
+					LastQuotaCheckTime: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+				},
+			},
+			wantUsage:   []infrav1.QuotaUsage{{ServiceCode: "ec2", QuotaCode: "L-0263D0A3", Limit: 5}},
+			wantChecked: true,
+		},
+		{
+			name: "elastic IPs are projected for the missing NAT gateways",
+			awsCluster: infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					QuotaCheck:  &infrav1.QuotaCheck{Enabled: true},
+					NetworkSpec: infrav1.NetworkSpec{Subnets: subnets},
+				},
+			},
+			setup: func(ec2API *fakeaws.EC2API, quotasAPI *fakeaws.ServiceQuotasAPI) {
+				ec2API.AddAddresses(
+					&ec2.Address{AllocationId: aws.String("eipalloc-1"), AssociationId: aws.String("eipassoc-1")},
+					&ec2.Address{AllocationId: aws.String("eipalloc-2"), AssociationId: aws.String("eipassoc-2")},
+					&ec2.Address{AllocationId: aws.String("eipalloc-3")},
+				)
+				ec2API.AddNatGateways(&ec2.NatGateway{NatGatewayId: aws.String("nat-1"), SubnetId: aws.String("subnet-other"), State: aws.String(ec2.NatGatewayStateAvailable)})
+				ec2API.AddSubnets(&ec2.Subnet{SubnetId: aws.String("subnet-other"), AvailabilityZone: aws.String("us-east-1a")})
+				quotasAPI.SetServiceQuota("ec2", "L-0263D0A3", 5)
+				quotasAPI.SetServiceQuota("vpc", "L-FE5A380F", 5)
+			},
+			wantUsage: []infrav1.QuotaUsage{
+				{ServiceCode: "ec2", QuotaCode: "L-0263D0A3", QuotaName: "EC2-VPC Elastic IPs", Limit: 5, Usage: 3, ProjectedUsage: 5},
+				{ServiceCode: "vpc", QuotaCode: "L-FE5A380F", QuotaName: "NAT gateways per Availability Zone", Limit: 5, Usage: 1, ProjectedUsage: 2},
+			},
+			wantChecked: true,
+			wantEvents:  []string{"EC2-VPC Elastic IPs"},
+		},
+		{
+			name: "a free elastic IP of the cluster is reused by a missing NAT gateway",
+			awsCluster: infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					QuotaCheck:  &infrav1.QuotaCheck{Enabled: true},
+					NetworkSpec: infrav1.NetworkSpec{Subnets: subnets},
+				},
+			},
+			setup: func(ec2API *fakeaws.EC2API, quotasAPI *fakeaws.ServiceQuotasAPI) {
+				ec2API.AddAddresses(&ec2.Address{AllocationId: aws.String("eipalloc-1"), Tags: []*ec2.Tag{clusterTag}})
+				quotasAPI.SetServiceQuota("ec2", "L-0263D0A3", 5)
+			},
+			wantUsage: []infrav1.QuotaUsage{
+				{ServiceCode: "ec2", QuotaCode: "L-0263D0A3", QuotaName: "EC2-VPC Elastic IPs", Limit: 5, Usage: 1, ProjectedUsage: 2},
+			},
+			wantChecked: true,
+		},
+		{
+			name: "on-demand vCPUs are projected for the machine pools scaled to their maximum size",
+			awsCluster: infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					QuotaCheck:  &infrav1.QuotaCheck{Enabled: true},
+					NetworkSpec: infrav1.NetworkSpec{VPC: infrav1.VPCSpec{ID: "vpc-unmanaged"}},
+				},
+			},
+			objects: []client.Object{
+				&expinfrav1.AWSMachinePool{
+					ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default", Labels: map[string]string{clusterv1.ClusterNameLabel: "test-cluster"}},
+					Spec: expinfrav1.AWSMachinePoolSpec{
+						MaxSize:           4,
+						AWSLaunchTemplate: expinfrav1.AWSLaunchTemplate{InstanceType: "m5.xlarge"},
+					},
+					Status: expinfrav1.AWSMachinePoolStatus{Replicas: 1},
+				},
+				&expinfrav1.AWSMachinePool{
+					ObjectMeta: metav1.ObjectMeta{Name: "spot", Namespace: "default", Labels: map[string]string{clusterv1.ClusterNameLabel: "test-cluster"}},
+					Spec: expinfrav1.AWSMachinePoolSpec{
+						MaxSize: 4,
+						AWSLaunchTemplate: expinfrav1.AWSLaunchTemplate{
+							InstanceType:      "p3.2xlarge",
+							SpotMarketOptions: &infrav1.SpotMarketOptions{},
+						},
+					},
+				},
+			},
+			setup: func(ec2API *fakeaws.EC2API, quotasAPI *fakeaws.ServiceQuotasAPI) {
+				ec2API.AddInstances(
+					&ec2.Instance{
+						InstanceId:   aws.String("i-1"),
+						InstanceType: aws.String("m5.xlarge"),
+						State:        &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameRunning)},
+						CpuOptions:   &ec2.CpuOptions{CoreCount: aws.Int64(2), ThreadsPerCore: aws.Int64(2)},
+					},
+					&ec2.Instance{
+						InstanceId:   aws.String("i-2"),
+						InstanceType: aws.String("c5.large"),
+						State:        &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNamePending)},
+					},
+					&ec2.Instance{
+						InstanceId:        aws.String("i-3"),
+						InstanceType:      aws.String("m5.xlarge"),
+						InstanceLifecycle: aws.String(ec2.InstanceLifecycleTypeSpot),
+						State:             &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameRunning)},
+					},
+					&ec2.Instance{
+						InstanceId:   aws.String("i-4"),
+						InstanceType: aws.String("m5.xlarge"),
+						State:        &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameStopped)},
+					},
+				)
+				ec2API.AddInstanceTypes(
+					&ec2.InstanceTypeInfo{InstanceType: aws.String("m5.xlarge"), VCpuInfo: &ec2.VCpuInfo{DefaultVCpus: aws.Int64(4)}},
+					&ec2.InstanceTypeInfo{InstanceType: aws.String("c5.large"), VCpuInfo: &ec2.VCpuInfo{DefaultVCpus: aws.Int64(2)}},
+				)
+				quotasAPI.SetServiceQuota("ec2", "L-1216C47A", 32)
+				quotasAPI.SetServiceQuota("ec2", "L-417A185B", 64)
+			},
+			wantUsage: []infrav1.QuotaUsage{
+				{ServiceCode: "ec2", QuotaCode: "L-1216C47A", QuotaName: "Running On-Demand Standard (A, C, D, H, I, M, R, T, Z) instances", Limit: 32, Usage: 6, ProjectedUsage: 18},
+			},
+			wantChecked: true,
+		},
+		{
+			name: "rules of the security groups of the cluster",
+			awsCluster: infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					QuotaCheck:  &infrav1.QuotaCheck{Enabled: true},
+					NetworkSpec: infrav1.NetworkSpec{VPC: infrav1.VPCSpec{ID: "vpc-unmanaged"}},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.NetworkStatus{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupNode: {ID: "sg-node"},
+						},
+					},
+				},
+			},
+			setup: func(ec2API *fakeaws.EC2API, quotasAPI *fakeaws.ServiceQuotasAPI) {
+				ec2API.AddSecurityGroups(&ec2.SecurityGroup{
+					GroupId: aws.String("sg-node"),
+					IpPermissions: []*ec2.IpPermission{
+						{IpRanges: []*ec2.IpRange{{CidrIp: aws.String("10.0.0.0/16")}, {CidrIp: aws.String("10.1.0.0/16")}}},
+						{UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-controlplane")}}},
+					},
+					IpPermissionsEgress: []*ec2.IpPermission{
+						{IpRanges: []*ec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}},
+					},
+				})
+				quotasAPI.SetServiceQuota("vpc", "L-0EA8095F", 60)
+			},
+			wantUsage: []infrav1.QuotaUsage{
+				{ServiceCode: "vpc", QuotaCode: "L-0EA8095F", QuotaName: "Inbound or outbound rules per security group", Limit: 60, Usage: 3, ProjectedUsage: 3},
+			},
+			wantChecked: true,
+		},
+		{
+			name: "quotas that can't be read are skipped",
+			awsCluster: infrav1.AWSCluster{
+				Spec: infrav1.AWSClusterSpec{
+					QuotaCheck:  &infrav1.QuotaCheck{Enabled: true},
+					NetworkSpec: infrav1.NetworkSpec{Subnets: subnets},
+				},
+			},
+			setup: func(ec2API *fakeaws.EC2API, quotasAPI *fakeaws.ServiceQuotasAPI) {
+				ec2API.InjectError("DescribeNatGateways", awserr.New("UnauthorizedOperation", "not allowed", nil))
+				quotasAPI.InjectError("GetServiceQuota", awserr.New("AccessDeniedException", "not allowed", nil))
+			},
+			wantUsage:   []infrav1.QuotaUsage{},
+			wantChecked: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachinePool, true)()
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			_ = expinfrav1.AddToScheme(scheme)
+			awsCluster := tt.awsCluster.DeepCopy()
+			awsCluster.ObjectMeta = metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"}
+			cs, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build(),
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+				},
+				AWSCluster: awsCluster,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			ec2API := fakeaws.NewEC2API()
+			quotasAPI := fakeaws.NewServiceQuotasAPI()
+			if tt.setup != nil {
+				tt.setup(ec2API, quotasAPI)
+			}
+			s := NewService(cs)
+			s.EC2Client = ec2API
+			s.ServiceQuotasClient = quotasAPI
+
+			s.ReconcileQuotas()
+
+			g.Expect(awsCluster.Status.QuotaUsage).To(ConsistOf(tt.wantUsage))
+			g.Expect(awsCluster.Status.LastQuotaCheckTime != nil).To(Equal(tt.wantChecked))
+
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			g.Expect(events).To(HaveLen(len(tt.wantEvents)))
+			for i, event := range events {
+				g.Expect(event).To(HavePrefix("Warning ServiceQuotaUsageHigh"))
+				g.Expect(strings.Contains(event, tt.wantEvents[i])).To(BeTrue(), event)
+			}
+		})
+	}
+}
+
+func TestInstanceFamily(t *testing.T) {
+	tests := map[string]string{
+		"m5.large":     "m",
+		"inf1.xlarge":  "inf",
+		"im4gn.large":  "im",
+		"u-6tb1.metal": "u",
+		"t3":           "t",
+	}
+	for instanceType, want := range tests {
+		t.Run(instanceType, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(instanceFamily(instanceType)).To(Equal(want))
+		})
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicequotas
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/filter"
+)
+
+const (
+	vpcServiceCode = "vpc"
+
+	// defaultAvailabilityZoneUsageLimit is the number of availability zones the subnets of a
+	// managed VPC are created in when VPCSpec.AvailabilityZoneUsageLimit is not set.
+	defaultAvailabilityZoneUsageLimit = 3
+)
+
+// vpcQuotas are the checked quotas of the VPC service.
+var vpcQuotas = []quota{
+	{code: "L-FE5A380F", name: "NAT gateways per Availability Zone", usage: natGatewaysPerAZUsage},
+	{code: "L-0EA8095F", name: "Inbound or outbound rules per security group", usage: securityGroupRulesUsage},
+}
+
+// natGatewaysPerAZUsage returns the number of NAT gateways of the availability zone with the most
+// NAT gateways, projecting the NAT gateways the cluster is still expected to create.
+func natGatewaysPerAZUsage(c *check) (int64, int64, bool, error) {
+	if c.scope.VPC().IsUnmanaged(c.scope.Name()) {
+		return 0, 0, false, nil
+	}
+
+	natGateways, err := c.describeNatGateways()
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	subnetIDs := []*string{}
+	for _, ngw := range natGateways {
+		subnetIDs = append(subnetIDs, ngw.SubnetId)
+	}
+	zones := map[string]string{}
+	if len(subnetIDs) > 0 {
+		out, err := c.EC2Client.DescribeSubnetsWithContext(context.TODO(), &ec2.DescribeSubnetsInput{SubnetIds: subnetIDs})
+		if err != nil {
+			return 0, 0, false, errors.Wrap(err, "failed to describe the subnets of the NAT gateways")
+		}
+		for _, subnet := range out.Subnets {
+			zones[aws.StringValue(subnet.SubnetId)] = aws.StringValue(subnet.AvailabilityZone)
+		}
+	}
+
+	current := map[string]int64{}
+	for _, ngw := range natGateways {
+		current[zones[aws.StringValue(ngw.SubnetId)]]++
+	}
+
+	missing, err := c.missingNatGateways()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	projected := map[string]int64{}
+	for zone, count := range current {
+		projected[zone] = count
+	}
+	unknownZones := false
+	for _, zone := range missing {
+		if zone == "" {
+			unknownZones = true
+			continue
+		}
+		projected[zone]++
+	}
+
+	maxCurrent, maxProjected := maxValue(current), maxValue(projected)
+	if unknownZones {
+		// The NAT gateways are created in distinct zones, which may include the zone with the most NAT gateways.
+		maxProjected = max(maxProjected, maxCurrent+1)
+	}
+	return maxCurrent, maxProjected, true, nil
+}
+
+// securityGroupRulesUsage returns the number of inbound or outbound rules of the security group of
+// the cluster with the most rules.
+func securityGroupRulesUsage(c *check) (int64, int64, bool, error) {
+	groupIDs := []*string{}
+	for _, sg := range c.scope.SecurityGroups() {
+		if sg.ID != "" {
+			groupIDs = append(groupIDs, aws.String(sg.ID))
+		}
+	}
+	if len(groupIDs) == 0 {
+		return 0, 0, false, nil
+	}
+
+	out, err := c.EC2Client.DescribeSecurityGroupsWithContext(context.TODO(), &ec2.DescribeSecurityGroupsInput{GroupIds: groupIDs})
+	if err != nil {
+		return 0, 0, false, errors.Wrap(err, "failed to describe security groups")
+	}
+
+	var current int64
+	for _, sg := range out.SecurityGroups {
+		current = max(current, countRules(sg.IpPermissions), countRules(sg.IpPermissionsEgress))
+	}
+	return current, current, true, nil
+}
+
+// countRules returns the number of rules of a set of permissions: each source or destination of a
+// permission is a rule.
+func countRules(permissions []*ec2.IpPermission) int64 {
+	var rules int64
+	for _, permission := range permissions {
+		rules += int64(len(permission.IpRanges) + len(permission.Ipv6Ranges) + len(permission.PrefixListIds) + len(permission.UserIdGroupPairs))
+	}
+	return rules
+}
+
+// missingNatGateways returns the availability zones of the NAT gateways the cluster is still
+// expected to create, one per public subnet without a NAT gateway. When the subnets are not known
+// yet, one NAT gateway per availability zone used by the VPC is expected, in unknown zones.
+func (c *check) missingNatGateways() ([]string, error) {
+	if c.scope.VPC().IsUnmanaged(c.scope.Name()) {
+		return nil, nil
+	}
+
+	subnets := c.scope.Subnets()
+	if len(subnets) == 0 {
+		zones := defaultAvailabilityZoneUsageLimit
+		if c.scope.VPC().AvailabilityZoneUsageLimit != nil {
+			zones = *c.scope.VPC().AvailabilityZoneUsageLimit
+		}
+		return make([]string, zones), nil
+	}
+	if len(subnets.FilterPrivate()) == 0 {
+		// The NAT gateways are only created for the private subnets.
+		return nil, nil
+	}
+
+	natGateways, err := c.describeNatGateways()
+	if err != nil {
+		return nil, err
+	}
+	withNatGateway := map[string]bool{}
+	for _, ngw := range natGateways {
+		withNatGateway[aws.StringValue(ngw.SubnetId)] = true
+	}
+
+	missing := []string{}
+	for _, subnet := range subnets.FilterPublic() {
+		if subnet.GetResourceID() == "" || !withNatGateway[subnet.GetResourceID()] {
+			missing = append(missing, subnet.AvailabilityZone)
+		}
+	}
+	return missing, nil
+}
+
+// describeNatGateways returns the pending and available NAT gateways of the region.
+func (c *check) describeNatGateways() ([]*ec2.NatGateway, error) {
+	if c.natGateways != nil {
+		return c.natGateways, nil
+	}
+
+	natGateways := []*ec2.NatGateway{}
+	err := c.EC2Client.DescribeNatGatewaysPagesWithContext(context.TODO(), &ec2.DescribeNatGatewaysInput{
+		Filter: []*ec2.Filter{filter.EC2.NATGatewayStates(ec2.NatGatewayStatePending, ec2.NatGatewayStateAvailable)},
+	}, func(out *ec2.DescribeNatGatewaysOutput, _ bool) bool {
+		natGateways = append(natGateways, out.NatGateways...)
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe NAT gateways")
+	}
+	c.natGateways = natGateways
+	return natGateways, nil
+}
+
+// maxValue returns the maximum value of a map, 0 when it is empty.
+func maxValue(values map[string]int64) int64 {
+	var res int64
+	for _, v := range values {
+		res = max(res, v)
+	}
+	return res
+}
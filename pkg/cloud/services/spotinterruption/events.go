@@ -0,0 +1,224 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotinterruption
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+
+	iamv1 "sigs.k8s.io/cluster-api-provider-aws/v2/iam/api/v1beta1"
+)
+
+const (
+	// SpotInterruptionWarning is the detail type of the notice sent two minutes before a spot instance is interrupted.
+	SpotInterruptionWarning = "EC2 Spot Instance Interruption Warning"
+	// RebalanceRecommendation is the detail type of the notice sent when a spot instance is at an elevated risk of interruption.
+	RebalanceRecommendation = "EC2 Instance Rebalance Recommendation"
+)
+
+// ReconcileSpotInterruptionEvents creates the queue receiving the spot interruption notices of the
+// cluster and the rule sending them to it, and returns the ARN of the queue.
+func (s *Service) ReconcileSpotInterruptionEvents() (string, error) {
+	queueURL, err := s.reconcileQueue()
+	if err != nil {
+		return "", err
+	}
+
+	queueAttrs, err := s.SQSClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameQueueArn, sqs.QueueAttributeNamePolicy}),
+		QueueUrl:       queueURL,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to get attributes of queue %s", s.queueName())
+	}
+	queueARN := aws.StringValue(queueAttrs.Attributes[sqs.QueueAttributeNameQueueArn])
+
+	ruleARN, err := s.reconcileRule()
+	if err != nil {
+		return "", err
+	}
+
+	targetsResp, err := s.EventBridgeClient.ListTargetsByRule(&eventbridge.ListTargetsByRuleInput{
+		Rule: aws.String(s.ruleName()),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to list targets for rule %s", s.ruleName())
+	}
+	targetFound := false
+	for _, target := range targetsResp.Targets {
+		if aws.StringValue(target.Id) == s.queueName() && aws.StringValue(target.Arn) == queueARN {
+			targetFound = true
+		}
+	}
+	if !targetFound {
+		_, err = s.EventBridgeClient.PutTargets(&eventbridge.PutTargetsInput{
+			Rule: aws.String(s.ruleName()),
+			Targets: []*eventbridge.Target{{
+				Arn: aws.String(queueARN),
+				Id:  aws.String(s.queueName()),
+			}},
+		})
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to add SQS target %s to rule %s", s.queueName(), s.ruleName())
+		}
+	}
+
+	if queueAttrs.Attributes[sqs.QueueAttributeNamePolicy] == nil {
+		// the rule is only authorized to send messages to the queue by the policy of the queue
+		if err := s.createQueuePolicy(queueURL, queueARN, ruleARN); err != nil {
+			return "", err
+		}
+	}
+
+	return queueARN, nil
+}
+
+// DeleteSpotInterruptionEvents deletes the rule and queue of the cluster.
+func (s *Service) DeleteSpotInterruptionEvents() error {
+	_, err := s.EventBridgeClient.RemoveTargets(&eventbridge.RemoveTargetsInput{
+		Rule: aws.String(s.ruleName()),
+		Ids:  aws.StringSlice([]string{s.queueName()}),
+	})
+	if err != nil && !resourceNotFoundError(err) {
+		return errors.Wrapf(err, "unable to remove target %s for rule %s", s.queueName(), s.ruleName())
+	}
+	_, err = s.EventBridgeClient.DeleteRule(&eventbridge.DeleteRuleInput{Name: aws.String(s.ruleName())})
+	if err != nil && !resourceNotFoundError(err) {
+		return errors.Wrapf(err, "unable to delete rule %s", s.ruleName())
+	}
+
+	resp, err := s.SQSClient.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(s.queueName())})
+	if err != nil {
+		if queueNotFoundError(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "unable to get URL of queue %s", s.queueName())
+	}
+	_, err = s.SQSClient.DeleteQueue(&sqs.DeleteQueueInput{QueueUrl: resp.QueueUrl})
+	if err != nil && !queueNotFoundError(err) {
+		return errors.Wrapf(err, "unable to delete queue %s", s.queueName())
+	}
+
+	return nil
+}
+
+// reconcileQueue creates the queue of the cluster if it doesn't exist and returns its URL.
+func (s *Service) reconcileQueue() (*string, error) {
+	resp, err := s.SQSClient.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(s.queueName())})
+	if err == nil {
+		return resp.QueueUrl, nil
+	}
+	if !queueNotFoundError(err) {
+		return nil, errors.Wrapf(err, "unable to get URL of queue %s", s.queueName())
+	}
+
+	createResp, err := s.SQSClient.CreateQueue(&sqs.CreateQueueInput{
+		QueueName: aws.String(s.queueName()),
+		Attributes: aws.StringMap(map[string]string{
+			sqs.QueueAttributeNameReceiveMessageWaitTimeSeconds: "20",
+		}),
+		Tags: aws.StringMap(s.tags()),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to create queue %s", s.queueName())
+	}
+	return createResp.QueueUrl, nil
+}
+
+// reconcileRule creates the rule of the cluster if it doesn't exist and returns its ARN.
+func (s *Service) reconcileRule() (string, error) {
+	ruleResp, err := s.EventBridgeClient.DescribeRule(&eventbridge.DescribeRuleInput{Name: aws.String(s.ruleName())})
+	if err == nil {
+		return aws.StringValue(ruleResp.Arn), nil
+	}
+	if !resourceNotFoundError(err) {
+		return "", errors.Wrapf(err, "unable to describe rule %s", s.ruleName())
+	}
+
+	data, err := json.Marshal(eventPattern{
+		Source:     []string{"aws.ec2"},
+		DetailType: []string{SpotInterruptionWarning, RebalanceRecommendation},
+	})
+	if err != nil {
+		return "", err
+	}
+	putResp, err := s.EventBridgeClient.PutRule(&eventbridge.PutRuleInput{
+		Name:         aws.String(s.ruleName()),
+		EventPattern: aws.String(string(data)),
+		State:        aws.String(eventbridge.RuleStateEnabled),
+		Tags:         eventBridgeTags(s.tags()),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to create rule %s", s.ruleName())
+	}
+	return aws.StringValue(putResp.RuleArn), nil
+}
+
+// createQueuePolicy authorizes the rule of the cluster to send messages to its queue.
+func (s *Service) createQueuePolicy(queueURL *string, queueARN, ruleARN string) error {
+	policy := iamv1.PolicyDocument{
+		Version: iamv1.CurrentVersion,
+		ID:      queueARN,
+		Statement: iamv1.Statements{
+			iamv1.StatementEntry{
+				Sid:       fmt.Sprintf("CAPAEvents_%s_%s", s.ruleName(), s.queueName()),
+				Effect:    iamv1.EffectAllow,
+				Principal: iamv1.Principals{iamv1.PrincipalService: iamv1.PrincipalID{"events.amazonaws.com"}},
+				Action:    iamv1.Actions{"sqs:SendMessage"},
+				Resource:  iamv1.Resources{queueARN},
+				Condition: iamv1.Conditions{
+					"ArnEquals": map[string]string{"aws:SourceArn": ruleARN},
+				},
+			},
+		},
+	}
+	policyData, err := json.Marshal(policy)
+	if err != nil {
+		return errors.Wrap(err, "unable to JSON marshal policy")
+	}
+
+	_, err = s.SQSClient.SetQueueAttributes(&sqs.SetQueueAttributesInput{
+		QueueUrl:   queueURL,
+		Attributes: aws.StringMap(map[string]string{sqs.QueueAttributeNamePolicy: string(policyData)}),
+	})
+	return errors.Wrapf(err, "unable to set the policy of queue %s", s.queueName())
+}
+
+func resourceNotFoundError(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == eventbridge.ErrCodeResourceNotFoundException {
+		return true
+	}
+	return false
+}
+
+func queueNotFoundError(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == sqs.ErrCodeQueueDoesNotExist {
+		return true
+	}
+	return false
+}
+
+type eventPattern struct {
+	Source     []string `json:"source"`
+	DetailType []string `json:"detail-type"`
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotinterruption
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/instancestate/mock_eventbridgeiface"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/instancestate/mock_sqsiface"
+)
+
+const (
+	testQueueName = "test-cluster-2f7c1b4e-3f5a-4d8e-9c1b-7a6e5d4c3b2a-spot-queue"
+	testRuleName  = "test-cluster-2f7c1b4e-3f5a-4d8e-9c1b-7a6e5d4c3b2a-spot-rule"
+	testQueueARN  = "arn:aws:sqs:us-east-1:123456789012:" + testQueueName
+	testRuleARN   = "arn:aws:events:us-east-1:123456789012:rule/" + testRuleName
+)
+
+func TestReconcileSpotInterruptionEvents(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name              string
+		sqsExpect         func(m *mock_sqsiface.MockSQSAPIMockRecorder)
+		eventBridgeExpect func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder)
+		expectErr         bool
+	}{
+		{
+			name: "creates the queue, the rule and its target",
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(testQueueName)}).
+					Return(nil, awserr.New(sqs.ErrCodeQueueDoesNotExist, "", nil))
+				m.CreateQueue(gomock.Any()).DoAndReturn(func(input *sqs.CreateQueueInput) (*sqs.CreateQueueOutput, error) {
+					if aws.StringValue(input.QueueName) != testQueueName {
+						return nil, errors.New("unexpected queue name")
+					}
+					if aws.StringValue(input.Tags["sigs.k8s.io/cluster-api-provider-aws/role"]) != "spot-interruption" {
+						return nil, errors.New("queue is not tagged with its role")
+					}
+					return &sqs.CreateQueueOutput{QueueUrl: aws.String("queue-url")}, nil
+				})
+				m.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+					AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameQueueArn, sqs.QueueAttributeNamePolicy}),
+					QueueUrl:       aws.String("queue-url"),
+				}).Return(&sqs.GetQueueAttributesOutput{Attributes: map[string]*string{
+					sqs.QueueAttributeNameQueueArn: aws.String(testQueueARN),
+				}}, nil)
+				m.SetQueueAttributes(gomock.AssignableToTypeOf(&sqs.SetQueueAttributesInput{})).
+					Return(&sqs.SetQueueAttributesOutput{}, nil)
+			},
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.DescribeRule(&eventbridge.DescribeRuleInput{Name: aws.String(testRuleName)}).
+					Return(nil, awserr.New(eventbridge.ErrCodeResourceNotFoundException, "", nil))
+				m.PutRule(gomock.Any()).DoAndReturn(func(input *eventbridge.PutRuleInput) (*eventbridge.PutRuleOutput, error) {
+					if aws.StringValue(input.EventPattern) != `{"source":["aws.ec2"],"detail-type":["EC2 Spot Instance Interruption Warning","EC2 Instance Rebalance Recommendation"]}` {
+						return nil, errors.New("unexpected event pattern")
+					}
+					if aws.StringValue(input.State) != eventbridge.RuleStateEnabled {
+						return nil, errors.New("rule is not enabled")
+					}
+					return &eventbridge.PutRuleOutput{RuleArn: aws.String(testRuleARN)}, nil
+				})
+				m.ListTargetsByRule(&eventbridge.ListTargetsByRuleInput{Rule: aws.String(testRuleName)}).
+					Return(&eventbridge.ListTargetsByRuleOutput{}, nil)
+				m.PutTargets(&eventbridge.PutTargetsInput{
+					Rule: aws.String(testRuleName),
+					Targets: []*eventbridge.Target{{
+						Arn: aws.String(testQueueARN),
+						Id:  aws.String(testQueueName),
+					}},
+				}).Return(&eventbridge.PutTargetsOutput{}, nil)
+			},
+		},
+		{
+			name: "doesn't update the queue and rule which exist",
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(testQueueName)}).
+					Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("queue-url")}, nil)
+				m.GetQueueAttributes(gomock.Any()).Return(&sqs.GetQueueAttributesOutput{Attributes: map[string]*string{
+					sqs.QueueAttributeNameQueueArn: aws.String(testQueueARN),
+					sqs.QueueAttributeNamePolicy:   aws.String("policy"),
+				}}, nil)
+			},
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.DescribeRule(&eventbridge.DescribeRuleInput{Name: aws.String(testRuleName)}).
+					Return(&eventbridge.DescribeRuleOutput{Name: aws.String(testRuleName), Arn: aws.String(testRuleARN)}, nil)
+				m.ListTargetsByRule(&eventbridge.ListTargetsByRuleInput{Rule: aws.String(testRuleName)}).
+					Return(&eventbridge.ListTargetsByRuleOutput{Targets: []*eventbridge.Target{{
+						Arn: aws.String(testQueueARN),
+						Id:  aws.String(testQueueName),
+					}}}, nil)
+			},
+		},
+		{
+			name: "returns error if the queue can't be created",
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(testQueueName)}).
+					Return(nil, awserr.New(sqs.ErrCodeQueueDoesNotExist, "", nil))
+				m.CreateQueue(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+			expectErr: true,
+		},
+		{
+			name: "returns error if the rule can't be described",
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(testQueueName)}).
+					Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("queue-url")}, nil)
+				m.GetQueueAttributes(gomock.Any()).Return(&sqs.GetQueueAttributesOutput{Attributes: map[string]*string{
+					sqs.QueueAttributeNameQueueArn: aws.String(testQueueARN),
+				}}, nil)
+			},
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.DescribeRule(&eventbridge.DescribeRuleInput{Name: aws.String(testRuleName)}).
+					Return(nil, errors.New("some error"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			sqsMock := mock_sqsiface.NewMockSQSAPI(mockCtrl)
+			eventBridgeMock := mock_eventbridgeiface.NewMockEventBridgeAPI(mockCtrl)
+			clusterScope, err := setupCluster("test-cluster")
+			g.Expect(err).To(Not(HaveOccurred()))
+
+			if tc.sqsExpect != nil {
+				tc.sqsExpect(sqsMock.EXPECT())
+			}
+			if tc.eventBridgeExpect != nil {
+				tc.eventBridgeExpect(eventBridgeMock.EXPECT())
+			}
+			s := NewService(clusterScope)
+			s.SQSClient = sqsMock
+			s.EventBridgeClient = eventBridgeMock
+
+			queueARN, err := s.ReconcileSpotInterruptionEvents()
+
+			if tc.expectErr {
+				g.Expect(err).NotTo(BeNil())
+				return
+			}
+			g.Expect(err).To(BeNil())
+			g.Expect(queueARN).To(Equal(testQueueARN))
+		})
+	}
+}
+
+func TestDeleteSpotInterruptionEvents(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name              string
+		sqsExpect         func(m *mock_sqsiface.MockSQSAPIMockRecorder)
+		eventBridgeExpect func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder)
+		expectErr         bool
+	}{
+		{
+			name: "deletes the rule and the queue",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.RemoveTargets(&eventbridge.RemoveTargetsInput{
+					Rule: aws.String(testRuleName),
+					Ids:  aws.StringSlice([]string{testQueueName}),
+				}).Return(&eventbridge.RemoveTargetsOutput{}, nil)
+				m.DeleteRule(&eventbridge.DeleteRuleInput{Name: aws.String(testRuleName)}).
+					Return(&eventbridge.DeleteRuleOutput{}, nil)
+			},
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(testQueueName)}).
+					Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("queue-url")}, nil)
+				m.DeleteQueue(&sqs.DeleteQueueInput{QueueUrl: aws.String("queue-url")}).
+					Return(&sqs.DeleteQueueOutput{}, nil)
+			},
+		},
+		{
+			name: "doesn't return error if the rule and the queue don't exist",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.RemoveTargets(gomock.Any()).Return(nil, awserr.New(eventbridge.ErrCodeResourceNotFoundException, "", nil))
+				m.DeleteRule(gomock.Any()).Return(nil, awserr.New(eventbridge.ErrCodeResourceNotFoundException, "", nil))
+			},
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(gomock.Any()).Return(nil, awserr.New(sqs.ErrCodeQueueDoesNotExist, "", nil))
+			},
+		},
+		{
+			name: "returns error if the rule can't be deleted",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.RemoveTargets(gomock.Any()).Return(&eventbridge.RemoveTargetsOutput{}, nil)
+				m.DeleteRule(gomock.Any()).Return(nil, errors.New("some error"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			sqsMock := mock_sqsiface.NewMockSQSAPI(mockCtrl)
+			eventBridgeMock := mock_eventbridgeiface.NewMockEventBridgeAPI(mockCtrl)
+			clusterScope, err := setupCluster("test-cluster")
+			g.Expect(err).To(Not(HaveOccurred()))
+
+			if tc.sqsExpect != nil {
+				tc.sqsExpect(sqsMock.EXPECT())
+			}
+			if tc.eventBridgeExpect != nil {
+				tc.eventBridgeExpect(eventBridgeMock.EXPECT())
+			}
+			s := NewService(clusterScope)
+			s.SQSClient = sqsMock
+			s.EventBridgeClient = eventBridgeMock
+
+			err = s.DeleteSpotInterruptionEvents()
+
+			if tc.expectErr {
+				g.Expect(err).NotTo(BeNil())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	g := NewWithT(t)
+	clusterScope, err := setupCluster("a-very-long.cluster-name-which-is-truncated")
+	g.Expect(err).To(Not(HaveOccurred()))
+	s := NewService(clusterScope)
+
+	g.Expect(s.queueName()).To(Equal("a-very-long-clus-2f7c1b4e-3f5a-4d8e-9c1b-7a6e5d4c3b2a-spot-queue"))
+	g.Expect(s.ruleName()).To(Equal("a-very-long-clus-2f7c1b4e-3f5a-4d8e-9c1b-7a6e5d4c3b2a-spot-rule"))
+	g.Expect(len(s.queueName())).To(BeNumerically("<=", 64))
+}
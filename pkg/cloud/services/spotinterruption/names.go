@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotinterruption
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+)
+
+// maxClusterNameLength is the length of the cluster name kept in the names of the queue and rule,
+// so that the names including the UID of the cluster fit in the 64 characters allowed for the name
+// of a rule and the ID of its target.
+const maxClusterNameLength = 16
+
+// queueName returns the name of the queue receiving the spot interruption notices of the cluster.
+func (s *Service) queueName() string {
+	return fmt.Sprintf("%s-spot-queue", s.namePrefix())
+}
+
+// ruleName returns the name of the rule matching the spot interruption notices of the cluster.
+func (s *Service) ruleName() string {
+	return fmt.Sprintf("%s-spot-rule", s.namePrefix())
+}
+
+// namePrefix returns the prefix of the names of the queue and rule, unique across the clusters
+// recreated with the same name.
+func (s *Service) namePrefix() string {
+	adjusted := strings.ReplaceAll(s.scope.Name(), ".", "-")
+	if len(adjusted) > maxClusterNameLength {
+		adjusted = adjusted[:maxClusterNameLength]
+	}
+	return fmt.Sprintf("%s-%s", adjusted, s.scope.InfraCluster().GetUID())
+}
+
+// tags returns the tags of the rule and queue of the cluster.
+func (s *Service) tags() infrav1.Tags {
+	return infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Role:        aws.String(infrav1.SpotInterruptionRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	})
+}
+
+// eventBridgeTags converts tags to EventBridge tags, sorted by key.
+func eventBridgeTags(tags infrav1.Tags) []*eventbridge.Tag {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := make([]*eventbridge.Tag, 0, len(tags))
+	for _, k := range keys {
+		res = append(res, &eventbridge.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	return res
+}
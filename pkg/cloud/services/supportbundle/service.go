@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package supportbundle provides a way to collect the state of a cluster, in Kubernetes and in AWS, to be
+// attached to bug reports.
+package supportbundle
+
+import (
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
+
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+)
+
+// Service holds a collection of interfaces.
+// The interfaces are broken down like this to group functions together.
+type Service struct {
+	scope                 scope.SupportBundleScope
+	ASGClient             autoscalingiface.AutoScalingAPI
+	EC2Client             ec2iface.EC2API
+	ELBClient             elbiface.ELBAPI
+	ELBV2Client           elbv2iface.ELBV2API
+	ResourceTaggingClient resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
+}
+
+// NewService returns a new service given the api clients.
+func NewService(supportBundleScope scope.SupportBundleScope) *Service {
+	return &Service{
+		scope:                 supportBundleScope,
+		ASGClient:             scope.NewASGClient(supportBundleScope, supportBundleScope, supportBundleScope, supportBundleScope.InfraCluster()),
+		EC2Client:             scope.NewEC2Client(supportBundleScope, supportBundleScope, supportBundleScope, supportBundleScope.InfraCluster()),
+		ELBClient:             scope.NewELBClient(supportBundleScope, supportBundleScope, supportBundleScope, supportBundleScope.InfraCluster()),
+		ELBV2Client:           scope.NewELBv2Client(supportBundleScope, supportBundleScope, supportBundleScope, supportBundleScope.InfraCluster()),
+		ResourceTaggingClient: scope.NewResourgeTaggingClient(supportBundleScope, supportBundleScope, supportBundleScope, supportBundleScope.InfraCluster()),
+	}
+}
@@ -0,0 +1,400 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package supportbundle
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/discovery"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+)
+
+const (
+	// maxEvents is the number of the most recent events of the objects of the cluster kept in a bundle.
+	maxEvents = 100
+
+	// maxSize is the maximum size of the files of a bundle. ConfigMaps and Secrets hold at most 1 MiB, part
+	// of which is left to the summary and the metadata of the object.
+	maxSize = 900 * 1024
+
+	// maxDescribeLoadBalancers is the maximum number of load balancers a single describe request accepts.
+	maxDescribeLoadBalancers = 20
+
+	// summaryFile is the file of a bundle which describes it.
+	summaryFile = "summary.yaml"
+
+	// redacted replaces the values of the sensitive fields of the objects.
+	redacted = "REDACTED"
+
+	// redactedAccountID replaces the AWS account IDs when they are redacted.
+	redactedAccountID = "XXXXXXXXXXXX"
+
+	// lastAppliedConfigAnnotation is set by kubectl apply, and duplicates the spec of the objects.
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+var (
+	// accountIDPattern matches the AWS account IDs, which are 12 digits, e.g. in ARNs and owner IDs.
+	accountIDPattern = regexp.MustCompile(`\b[0-9]{12}\b`)
+
+	// sensitiveFields are the substrings of the names of the fields of the objects whose values are redacted.
+	sensitiveFields = []string{"password", "token", "secretaccesskey", "privatekey", "userdata"}
+)
+
+// summary describes a bundle.
+type summary struct {
+	Cluster            string      `json:"cluster"`
+	Namespace          string      `json:"namespace"`
+	Region             string      `json:"region"`
+	CollectedAt        metav1.Time `json:"collectedAt"`
+	AccountIDsRedacted bool        `json:"accountIDsRedacted"`
+	// Errors are the errors which occurred while collecting the bundle, whose files may be missing.
+	Errors []string `json:"errors,omitempty"`
+	// Omitted are the files left out because the bundle reached its maximum size.
+	Omitted []string `json:"omitted,omitempty"`
+}
+
+// event is an event of an object of the cluster, as written to a bundle.
+type event struct {
+	Time    metav1.Time `json:"time"`
+	Type    string      `json:"type"`
+	Reason  string      `json:"reason"`
+	Object  string      `json:"object"`
+	Message string      `json:"message"`
+	Count   int32       `json:"count,omitempty"`
+}
+
+// bundle holds the files of a support bundle, within its maximum size.
+type bundle struct {
+	summary summary
+	files   map[string]string
+	size    int
+}
+
+// ReconcileSupportBundle collects a support bundle of the cluster when it has the collect-support-bundle
+// annotation, and removes the annotation once the bundle is written. The bundle holds the objects of the
+// cluster, the most recent events of these objects, and the description of the AWS resources tagged with the
+// name of the cluster. It is written to a ConfigMap named after the time of the collection, or to a Secret
+// unless the AWS account IDs are redacted.
+func (s *Service) ReconcileSupportBundle() error {
+	if !s.scope.SupportBundleRequested() {
+		return nil
+	}
+
+	s.scope.Info("Collecting support bundle")
+	now := metav1.Now()
+	b := &bundle{
+		summary: summary{
+			Cluster:            s.scope.Name(),
+			Namespace:          s.scope.Namespace(),
+			Region:             s.scope.Region(),
+			CollectedAt:        now,
+			AccountIDsRedacted: s.scope.SupportBundleRedactsAccountIDs(),
+		},
+		files: map[string]string{},
+	}
+
+	objects, err := s.scope.SupportBundleObjects()
+	if err != nil {
+		b.addError(err)
+	}
+	// The Cluster, the AWSCluster and the control plane come first, as the size of the bundle is limited.
+	sort.SliceStable(objects, func(i, j int) bool {
+		return isClusterObject(&objects[i]) && !isClusterObject(&objects[j])
+	})
+	uids := sets.New[types.UID]()
+	for i := range objects {
+		uids.Insert(objects[i].GetUID())
+	}
+
+	rest := objects
+	for len(rest) > 0 && isClusterObject(&rest[0]) {
+		b.addObject(&rest[0])
+		rest = rest[1:]
+	}
+
+	events, err := s.scope.Events()
+	if err != nil {
+		b.addError(err)
+	} else {
+		b.add("events.yaml", recentEvents(events, uids))
+	}
+
+	s.addAWSResources(b)
+
+	for i := range rest {
+		b.addObject(&rest[i])
+	}
+
+	name := fmt.Sprintf("%s-support-bundle-%s", s.scope.InfraClusterName(), now.UTC().Format("20060102-150405"))
+	kind := "ConfigMap"
+	if !b.summary.AccountIDsRedacted {
+		kind = "Secret"
+	}
+	if err := s.scope.WriteSupportBundle(name, b.finish(), !b.summary.AccountIDsRedacted); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedCollectSupportBundle", "Failed to write support bundle %s: %v", name, err)
+		return err
+	}
+
+	s.scope.SupportBundleCollected()
+	record.Eventf(s.scope.InfraCluster(), "SupportBundleCollected", "Collected support bundle in %s %s", kind, name)
+	return nil
+}
+
+// addAWSResources adds the description of the VPCs, subnets, security groups, load balancers and Auto Scaling
+// groups tagged with the name of the cluster to the bundle. The resources which can't be described are reported
+// in the errors of the bundle.
+func (s *Service) addAWSResources(b *bundle) {
+	ctx := context.TODO()
+	clusterName := s.scope.KubernetesClusterName()
+
+	vpcs := []*ec2.Vpc{}
+	if err := s.EC2Client.DescribeVpcsPagesWithContext(ctx, &ec2.DescribeVpcsInput{
+		Filters: []*ec2.Filter{filter.EC2.Cluster(clusterName)},
+	}, func(out *ec2.DescribeVpcsOutput, _ bool) bool {
+		vpcs = append(vpcs, out.Vpcs...)
+		return true
+	}); err != nil {
+		b.addError(errors.Wrap(err, "failed to describe VPCs"))
+	} else {
+		b.add("aws.vpcs.yaml", vpcs)
+	}
+
+	subnets := []*ec2.Subnet{}
+	if err := s.EC2Client.DescribeSubnetsPagesWithContext(ctx, &ec2.DescribeSubnetsInput{
+		Filters: []*ec2.Filter{filter.EC2.Cluster(clusterName)},
+	}, func(out *ec2.DescribeSubnetsOutput, _ bool) bool {
+		subnets = append(subnets, out.Subnets...)
+		return true
+	}); err != nil {
+		b.addError(errors.Wrap(err, "failed to describe subnets"))
+	} else {
+		b.add("aws.subnets.yaml", subnets)
+	}
+
+	securityGroups := []*ec2.SecurityGroup{}
+	if err := s.EC2Client.DescribeSecurityGroupsPagesWithContext(ctx, &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{filter.EC2.Cluster(clusterName)},
+	}, func(out *ec2.DescribeSecurityGroupsOutput, _ bool) bool {
+		securityGroups = append(securityGroups, out.SecurityGroups...)
+		return true
+	}); err != nil {
+		b.addError(errors.Wrap(err, "failed to describe security groups"))
+	} else {
+		b.add("aws.security-groups.yaml", securityGroups)
+	}
+
+	if err := s.addLoadBalancers(ctx, b, infrav1.ClusterTagKey(clusterName)); err != nil {
+		b.addError(err)
+	}
+
+	groups := []*autoscaling.Group{}
+	if err := s.ASGClient.DescribeAutoScalingGroupsPagesWithContext(ctx, &autoscaling.DescribeAutoScalingGroupsInput{
+		Filters: []*autoscaling.Filter{{Name: aws.String("tag-key"), Values: aws.StringSlice([]string{infrav1.ClusterTagKey(clusterName)})}},
+	}, func(out *autoscaling.DescribeAutoScalingGroupsOutput, _ bool) bool {
+		groups = append(groups, out.AutoScalingGroups...)
+		return true
+	}); err != nil {
+		b.addError(errors.Wrap(err, "failed to describe Auto Scaling groups"))
+	} else {
+		b.add("aws.auto-scaling-groups.yaml", groups)
+	}
+}
+
+// addLoadBalancers adds the description of the load balancers owned by or shared with the cluster to the
+// bundle. The classic load balancers are described separately.
+func (s *Service) addLoadBalancers(ctx context.Context, b *bundle, tagKey string) error {
+	discoverer := discovery.NewDiscoverer(s.scope.Region(), s.ResourceTaggingClient, s.ELBClient)
+	resources, err := discoverer.Discover(ctx, tagKey, []string{string(infrav1.ResourceLifecycleOwned), string(infrav1.ResourceLifecycleShared)},
+		discovery.ResourceTypeLoadBalancer, discovery.ResourceTypeClassicLoadBalancer)
+	if err != nil {
+		return errors.Wrap(err, "failed to get load balancers of the cluster")
+	}
+
+	classicNames, arns := []string{}, []string{}
+	for _, res := range resources {
+		if res.Type == discovery.ResourceTypeClassicLoadBalancer {
+			classicNames = append(classicNames, res.ID)
+		} else {
+			arns = append(arns, res.ARN)
+		}
+	}
+
+	classic := []*elb.LoadBalancerDescription{}
+	for i := 0; i < len(classicNames); i += maxDescribeLoadBalancers {
+		out, err := s.ELBClient.DescribeLoadBalancersWithContext(ctx, &elb.DescribeLoadBalancersInput{
+			LoadBalancerNames: aws.StringSlice(classicNames[i:min(i+maxDescribeLoadBalancers, len(classicNames))]),
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to describe classic load balancers")
+		}
+		classic = append(classic, out.LoadBalancerDescriptions...)
+	}
+	b.add("aws.classic-load-balancers.yaml", classic)
+
+	loadBalancers := []*elbv2.LoadBalancer{}
+	for i := 0; i < len(arns); i += maxDescribeLoadBalancers {
+		out, err := s.ELBV2Client.DescribeLoadBalancersWithContext(ctx, &elbv2.DescribeLoadBalancersInput{
+			LoadBalancerArns: aws.StringSlice(arns[i:min(i+maxDescribeLoadBalancers, len(arns))]),
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to describe load balancers")
+		}
+		loadBalancers = append(loadBalancers, out.LoadBalancers...)
+	}
+	b.add("aws.load-balancers.yaml", loadBalancers)
+	return nil
+}
+
+// add adds a file with the YAML encoding of a value to the bundle, unless the bundle would exceed its maximum
+// size, in which case the file is reported as omitted.
+func (b *bundle) add(name string, value interface{}) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		b.addError(errors.Wrapf(err, "failed to encode %s", name))
+		return
+	}
+	text := b.redact(string(data))
+	if b.size+len(name)+len(text) > maxSize {
+		b.summary.Omitted = append(b.summary.Omitted, name)
+		return
+	}
+	b.files[name] = text
+	b.size += len(name) + len(text)
+}
+
+// addObject adds an object to the bundle, with its sensitive fields redacted.
+func (b *bundle) addObject(obj *unstructured.Unstructured) {
+	obj = obj.DeepCopy()
+	obj.SetManagedFields(nil)
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		delete(annotations, lastAppliedConfigAnnotation)
+		obj.SetAnnotations(annotations)
+	}
+	redactFields(obj.Object)
+	b.add(fmt.Sprintf("%s.%s.yaml", strings.ToLower(obj.GetKind()), obj.GetName()), obj.Object)
+}
+
+func (b *bundle) addError(err error) {
+	b.summary.Errors = append(b.summary.Errors, b.redact(err.Error()))
+}
+
+// redact replaces the AWS account IDs of a text when they are redacted.
+func (b *bundle) redact(text string) string {
+	if !b.summary.AccountIDsRedacted {
+		return text
+	}
+	return accountIDPattern.ReplaceAllString(text, redactedAccountID)
+}
+
+// finish adds the summary to the bundle and returns its files.
+func (b *bundle) finish() map[string]string {
+	data, err := yaml.Marshal(b.summary)
+	if err == nil {
+		b.files[summaryFile] = b.redact(string(data))
+	}
+	return b.files
+}
+
+// isClusterObject returns whether an object is the Cluster, the AWSCluster or the control plane.
+func isClusterObject(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	return gvk.Kind == "Cluster" || gvk.Kind == "AWSCluster" || gvk.Group == "controlplane.cluster.x-k8s.io"
+}
+
+// redactFields replaces the string values of the sensitive fields of an object, at any depth.
+func redactFields(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if _, ok := field.(string); ok && isSensitiveField(key) {
+				v[key] = redacted
+				continue
+			}
+			redactFields(field)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactFields(item)
+		}
+	}
+}
+
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range sensitiveFields {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// recentEvents returns the most recent events of the objects, oldest first.
+func recentEvents(events []corev1.Event, uids sets.Set[types.UID]) []event {
+	res := []event{}
+	for _, e := range events {
+		if !uids.Has(e.InvolvedObject.UID) {
+			continue
+		}
+		res = append(res, event{
+			Time:    eventTime(&e),
+			Type:    e.Type,
+			Reason:  e.Reason,
+			Object:  fmt.Sprintf("%s/%s", e.InvolvedObject.Kind, e.InvolvedObject.Name),
+			Message: e.Message,
+			Count:   e.Count,
+		})
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Time.Before(&res[j].Time)
+	})
+	if len(res) > maxEvents {
+		res = res[len(res)-maxEvents:]
+	}
+	return res
+}
+
+// eventTime returns the time an event last occurred.
+func eventTime(e *corev1.Event) metav1.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp
+	case !e.EventTime.IsZero():
+		return metav1.NewTime(e.EventTime.Time)
+	}
+	return e.CreationTimestamp
+}
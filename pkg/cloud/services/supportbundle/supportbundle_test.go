@@ -0,0 +1,285 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package supportbundle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	rgapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	cgrecord "k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/cloud/services/autoscaling/mock_autoscalingiface"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-aws/v2/test/mocks"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+var recorder = cgrecord.NewFakeRecorder(100)
+
+func init() {
+	record.InitFromRecorder(recorder)
+}
+
+const networkLoadBalancerARN = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/test-cluster-apiserver/0123456789abcdef"
+
+func TestReconcileSupportBundle(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantKind    string
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:        "bundle with account IDs is written to a Secret",
+			annotations: map[string]string{infrav1.CollectSupportBundleAnnotation: "true"},
+			wantKind:    "Secret",
+		},
+		{
+			name: "bundle with redacted account IDs is written to a ConfigMap",
+			annotations: map[string]string{
+				infrav1.CollectSupportBundleAnnotation:          "true",
+				infrav1.SupportBundleRedactAccountIDsAnnotation: "true",
+			},
+			wantKind: "ConfigMap",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme)
+			_ = clusterv1.AddToScheme(scheme)
+			_ = infrav1.AddToScheme(scheme)
+			_ = expinfrav1.AddToScheme(scheme)
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default", UID: "cluster-uid"},
+			}
+			awsCluster := &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default", UID: "awscluster-uid", Annotations: tt.annotations},
+				Status: infrav1.AWSClusterStatus{
+					Bastion: &infrav1.Instance{ID: "i-bastion", IAMProfile: "arn:aws:iam::123456789012:instance-profile/bastion"},
+				},
+			}
+			machine := &infrav1.AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-machine", Namespace: "default", UID: "machine-uid",
+					Labels: map[string]string{clusterv1.ClusterNameLabel: "test-cluster"},
+				},
+			}
+			otherMachine := &infrav1.AWSMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "other-machine", Namespace: "default", UID: "other-machine-uid",
+					Labels: map[string]string{clusterv1.ClusterNameLabel: "other-cluster"},
+				},
+			}
+			events := []client.Object{
+				&corev1.Event{
+					ObjectMeta:     metav1.ObjectMeta{Name: "machine-event", Namespace: "default"},
+					InvolvedObject: corev1.ObjectReference{Kind: "AWSMachine", Name: "test-machine", UID: "machine-uid"},
+					Type:           corev1.EventTypeWarning,
+					Reason:         "FailedCreate",
+					Message:        "Failed to create instance",
+					LastTimestamp:  metav1.Now(),
+				},
+				&corev1.Event{
+					ObjectMeta:     metav1.ObjectMeta{Name: "other-event", Namespace: "default"},
+					InvolvedObject: corev1.ObjectReference{Kind: "AWSMachine", Name: "other-machine", UID: "other-machine-uid"},
+					Reason:         "Other",
+				},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, awsCluster, machine, otherMachine).WithObjects(events...).Build()
+			cs, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     c,
+				Cluster:    cluster,
+				AWSCluster: awsCluster,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			asgMock := mock_autoscalingiface.NewMockAutoScalingAPI(mockCtrl)
+			ec2Mock := mocks.NewMockEC2API(mockCtrl)
+			elbMock := mocks.NewMockELBAPI(mockCtrl)
+			elbv2Mock := mocks.NewMockELBV2API(mockCtrl)
+			rgtaggingMock := mocks.NewMockResourceGroupsTaggingAPIAPI(mockCtrl)
+			if tt.wantKind != "" {
+				expectDescribe(ec2Mock.EXPECT(), elbMock.EXPECT(), elbv2Mock.EXPECT(), rgtaggingMock.EXPECT(), asgMock.EXPECT())
+			}
+			s := NewService(cs)
+			s.ASGClient = asgMock
+			s.EC2Client = ec2Mock
+			s.ELBClient = elbMock
+			s.ELBV2Client = elbv2Mock
+			s.ResourceTaggingClient = rgtaggingMock
+
+			g.Expect(s.ReconcileSupportBundle()).To(Succeed())
+			if tt.wantKind == "" {
+				g.Expect(recorder.Events).To(BeEmpty())
+				return
+			}
+
+			g.Expect(awsCluster.Annotations).NotTo(HaveKey(infrav1.CollectSupportBundleAnnotation))
+			g.Expect(recorder.Events).To(Receive(ContainSubstring("SupportBundleCollected Collected support bundle in " + tt.wantKind)))
+
+			files := map[string]string{}
+			if tt.wantKind == "Secret" {
+				secrets := &corev1.SecretList{}
+				g.Expect(c.List(context.TODO(), secrets)).To(Succeed())
+				g.Expect(secrets.Items).To(HaveLen(1))
+				g.Expect(secrets.Items[0].Name).To(HavePrefix("test-cluster-support-bundle-"))
+				g.Expect(secrets.Items[0].OwnerReferences).To(HaveLen(1))
+				for key, value := range secrets.Items[0].Data {
+					files[key] = string(value)
+				}
+			} else {
+				configMaps := &corev1.ConfigMapList{}
+				g.Expect(c.List(context.TODO(), configMaps)).To(Succeed())
+				g.Expect(configMaps.Items).To(HaveLen(1))
+				files = configMaps.Items[0].Data
+			}
+
+			g.Expect(files).To(HaveKey("cluster.test-cluster.yaml"))
+			g.Expect(files).To(HaveKey("awscluster.test-cluster.yaml"))
+			g.Expect(files).To(HaveKey("awsmachine.test-machine.yaml"))
+			g.Expect(files).NotTo(HaveKey("awsmachine.other-machine.yaml"))
+			g.Expect(files["events.yaml"]).To(ContainSubstring("Failed to create instance"))
+			g.Expect(files["events.yaml"]).NotTo(ContainSubstring("Other"))
+			g.Expect(files["aws.vpcs.yaml"]).To(ContainSubstring("vpc-1"))
+			g.Expect(files["aws.load-balancers.yaml"]).To(ContainSubstring("test-cluster-apiserver"))
+			g.Expect(files["aws.auto-scaling-groups.yaml"]).To(ContainSubstring("test-pool"))
+			g.Expect(files).NotTo(HaveKey("aws.subnets.yaml"))
+			g.Expect(files[summaryFile]).To(ContainSubstring("failed to describe subnets"))
+			// The kinds which aren't installed, e.g. the MachinePools, are skipped.
+			g.Expect(files[summaryFile]).NotTo(ContainSubstring("failed to list"))
+
+			if tt.wantKind == "ConfigMap" {
+				for name, data := range files {
+					g.Expect(data).NotTo(ContainSubstring("123456789012"), name)
+				}
+				g.Expect(files["awscluster.test-cluster.yaml"]).To(ContainSubstring("arn:aws:iam::" + redactedAccountID + ":instance-profile/bastion"))
+			} else {
+				g.Expect(files["aws.vpcs.yaml"]).To(ContainSubstring("123456789012"))
+			}
+		})
+	}
+}
+
+func expectDescribe(ec2Mock *mocks.MockEC2APIMockRecorder, elbMock *mocks.MockELBAPIMockRecorder, elbv2Mock *mocks.MockELBV2APIMockRecorder,
+	rgtaggingMock *mocks.MockResourceGroupsTaggingAPIAPIMockRecorder, asgMock *mock_autoscalingiface.MockAutoScalingAPIMockRecorder) {
+	clusterFilter := []*ec2.Filter{{Name: aws.String("tag-key"), Values: aws.StringSlice([]string{"sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"})}}
+	ec2Mock.DescribeVpcsPagesWithContext(gomock.Any(), &ec2.DescribeVpcsInput{Filters: clusterFilter}, gomock.Any()).
+		DoAndReturn(func(_ aws.Context, _ *ec2.DescribeVpcsInput, fn func(*ec2.DescribeVpcsOutput, bool) bool, _ ...interface{}) error {
+			fn(&ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-1"), OwnerId: aws.String("123456789012")}}}, true)
+			return nil
+		})
+	ec2Mock.DescribeSubnetsPagesWithContext(gomock.Any(), &ec2.DescribeSubnetsInput{Filters: clusterFilter}, gomock.Any()).
+		Return(errors.New("access denied"))
+	ec2Mock.DescribeSecurityGroupsPagesWithContext(gomock.Any(), &ec2.DescribeSecurityGroupsInput{Filters: clusterFilter}, gomock.Any()).
+		Return(nil)
+	rgtaggingMock.GetResourcesWithContext(gomock.Any(), gomock.Any()).Return(&rgapi.GetResourcesOutput{
+		ResourceTagMappingList: []*rgapi.ResourceTagMapping{{ResourceARN: aws.String(networkLoadBalancerARN)}},
+	}, nil)
+	elbMock.DescribeLoadBalancersPagesWithContext(gomock.Any(), &elb.DescribeLoadBalancersInput{}, gomock.Any()).Return(nil)
+	elbv2Mock.DescribeLoadBalancersWithContext(gomock.Any(), &elbv2.DescribeLoadBalancersInput{LoadBalancerArns: aws.StringSlice([]string{networkLoadBalancerARN})}).
+		Return(&elbv2.DescribeLoadBalancersOutput{LoadBalancers: []*elbv2.LoadBalancer{
+			{LoadBalancerArn: aws.String(networkLoadBalancerARN), LoadBalancerName: aws.String("test-cluster-apiserver")},
+		}}, nil)
+	asgMock.DescribeAutoScalingGroupsPagesWithContext(gomock.Any(), &autoscaling.DescribeAutoScalingGroupsInput{
+		Filters: []*autoscaling.Filter{{Name: aws.String("tag-key"), Values: aws.StringSlice([]string{"sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"})}},
+	}, gomock.Any()).DoAndReturn(func(_ aws.Context, _ *autoscaling.DescribeAutoScalingGroupsInput, fn func(*autoscaling.DescribeAutoScalingGroupsOutput, bool) bool, _ ...interface{}) error {
+		fn(&autoscaling.DescribeAutoScalingGroupsOutput{AutoScalingGroups: []*autoscaling.Group{{AutoScalingGroupName: aws.String("test-pool")}}}, true)
+		return nil
+	})
+}
+
+func TestBundleAdd(t *testing.T) {
+	g := NewWithT(t)
+
+	b := &bundle{files: map[string]string{}}
+	b.add("small.yaml", "small")
+	b.add("large.yaml", strings.Repeat("a", maxSize))
+	g.Expect(b.files).To(HaveKey("small.yaml"))
+	g.Expect(b.files).NotTo(HaveKey("large.yaml"))
+	g.Expect(b.summary.Omitted).To(Equal([]string{"large.yaml"}))
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta2",
+		"kind":       "AWSMachine",
+		"metadata": map[string]interface{}{
+			"name":          "test-machine",
+			"annotations":   map[string]interface{}{lastAppliedConfigAnnotation: "{}"},
+			"managedFields": []interface{}{map[string]interface{}{"manager": "kubectl"}},
+		},
+		"spec": map[string]interface{}{
+			"credentials": []interface{}{map[string]interface{}{"password": "hunter2", "passwordRef": map[string]interface{}{"name": "creds"}}},
+		},
+	}}
+	b.addObject(obj)
+	g.Expect(b.files).To(HaveKey("awsmachine.test-machine.yaml"))
+	data := b.files["awsmachine.test-machine.yaml"]
+	g.Expect(data).To(ContainSubstring("password: " + redacted))
+	g.Expect(data).To(ContainSubstring("name: creds"))
+	g.Expect(data).NotTo(ContainSubstring("hunter2"))
+	g.Expect(data).NotTo(ContainSubstring("managedFields"))
+	g.Expect(data).NotTo(ContainSubstring(lastAppliedConfigAnnotation))
+	// The object itself is left unchanged.
+	g.Expect(obj.GetManagedFields()).To(HaveLen(1))
+}
+
+func TestRecentEvents(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	events := []corev1.Event{}
+	for i := 0; i < maxEvents+10; i++ {
+		events = append(events, corev1.Event{
+			InvolvedObject: corev1.ObjectReference{Kind: "AWSCluster", Name: "test-cluster", UID: "uid"},
+			LastTimestamp:  metav1.NewTime(now.Add(-time.Duration(i) * time.Minute)),
+		})
+	}
+	events = append(events, corev1.Event{InvolvedObject: corev1.ObjectReference{UID: "other-uid"}, LastTimestamp: metav1.NewTime(now)})
+
+	recent := recentEvents(events, sets.New[types.UID]("uid"))
+	g.Expect(recent).To(HaveLen(maxEvents))
+	g.Expect(recent[len(recent)-1].Time.Time).To(BeTemporally("~", now, time.Second))
+	g.Expect(recent[0].Time.Time).To(BeTemporally("~", now.Add(-time.Duration(maxEvents-1)*time.Minute), time.Second))
+}
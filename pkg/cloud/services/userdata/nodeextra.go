@@ -0,0 +1,424 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	ignTypes "github.com/coreos/ignition/config/v2_3/types"
+	ignV3Types "github.com/coreos/ignition/v2/config/v3_4/types"
+	"github.com/pkg/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+)
+
+const (
+	// MaxUserDataSize is the maximum size of the user data of an EC2 instance, before it is base64 encoded.
+	MaxUserDataSize = 16 * 1024
+
+	// FormatIgnition is the format of bootstrap data that holds an Ignition config.
+	FormatIgnition = "ignition"
+
+	cloudConfigContentType = "text/cloud-config"
+	shellScriptContentType = "text/x-shellscript"
+
+	preBootstrapScriptPath  = "/etc/capa/pre-bootstrap.sh"
+	postBootstrapScriptPath = "/etc/capa/post-bootstrap.sh"
+
+	// ignitionBootstrapUnit is the systemd unit that runs the bootstrap commands of an Ignition config.
+	ignitionBootstrapUnit = "kubeadm.service"
+)
+
+var (
+	multipartHeader = strings.Join([]string{
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/mixed; boundary=\"%s\"",
+		"\n",
+	}, "\n")
+
+	cloudConfigType = textproto.MIMEHeader{
+		"content-type": {cloudConfigContentType},
+	}
+
+	shellScriptType = textproto.MIMEHeader{
+		"content-type": {shellScriptContentType},
+	}
+
+	// prependMergeHow prepends the lists of a cloud-config part to the lists of the previous parts.
+	prependMergeHow = []cloudConfigMerger{
+		{Name: "list", Settings: []string{"prepend"}},
+		{Name: "dict", Settings: []string{"no_replace", "recurse_list"}},
+	}
+
+	// appendMergeHow appends the lists of a cloud-config part to the lists of the previous parts.
+	appendMergeHow = []cloudConfigMerger{
+		{Name: "list", Settings: []string{"append"}},
+		{Name: "dict", Settings: []string{"no_replace", "recurse_list"}},
+	}
+)
+
+type cloudConfigMerger struct {
+	Name     string   `json:"name"`
+	Settings []string `json:"settings"`
+}
+
+type cloudConfigFile struct {
+	Path        string `json:"path"`
+	Content     string `json:"content"`
+	Owner       string `json:"owner,omitempty"`
+	Permissions string `json:"permissions,omitempty"`
+}
+
+type cloudConfig struct {
+	MergeHow   []cloudConfigMerger `json:"merge_how"`
+	WriteFiles []cloudConfigFile   `json:"write_files,omitempty"`
+	RunCmd     []string            `json:"runcmd,omitempty"`
+}
+
+// AddNodeUserDataExtra adds the commands and files of extra around the bootstrap data. Cloud-init
+// bootstrap data is wrapped in a multipart MIME document and Ignition bootstrap data in an Ignition
+// config that merges it. The bootstrap data is returned unchanged if extra is empty.
+func AddNodeUserDataExtra(bootstrapData []byte, format string, extra *expinfrav1.NodeUserDataExtra) ([]byte, error) {
+	if extra.IsEmpty() {
+		return bootstrapData, nil
+	}
+
+	if format == FormatIgnition {
+		return addIgnitionExtra(bootstrapData, extra)
+	}
+
+	return addCloudInitExtra(bootstrapData, extra)
+}
+
+func addCloudInitExtra(bootstrapData []byte, extra *expinfrav1.NodeUserDataExtra) ([]byte, error) {
+	bootstrapContentType, bootstrapBody, err := bootstrapDataPart(bootstrapData)
+	if err != nil {
+		return nil, err
+	}
+	isCloudConfig := bootstrapContentType == cloudConfigContentType
+
+	var buf bytes.Buffer
+	mpWriter := multipart.NewWriter(&buf)
+	buf.WriteString(fmt.Sprintf(multipartHeader, mpWriter.Boundary()))
+
+	// Cloud-init runs the shell script parts before the runcmd of the cloud-config parts, so the
+	// extra commands are merged into the runcmd of the bootstrap data if it is a cloud-config.
+	// The cloud-config parts come after the bootstrap data, as a cloud-config part replaces the
+	// keys of the previous parts unless it defines how to merge them.
+	if !isCloudConfig && len(extra.PreBootstrap) > 0 {
+		if err := writePart(mpWriter, shellScriptType, []byte(shellScript(extra.PreBootstrap))); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writePart(mpWriter, textproto.MIMEHeader{"content-type": {bootstrapContentType}}, bootstrapBody); err != nil {
+		return nil, err
+	}
+
+	if isCloudConfig && len(extra.PreBootstrap) > 0 {
+		if err := writeCloudConfigPart(mpWriter, cloudConfig{MergeHow: prependMergeHow, RunCmd: extra.PreBootstrap}); err != nil {
+			return nil, err
+		}
+	}
+
+	// Files are written by cloud-init before any command is run.
+	appended := cloudConfig{MergeHow: appendMergeHow}
+	for _, f := range extra.Files {
+		appended.WriteFiles = append(appended.WriteFiles, cloudConfigFile(f))
+	}
+	if isCloudConfig {
+		appended.RunCmd = extra.PostBootstrap
+	}
+	if len(appended.WriteFiles) > 0 || len(appended.RunCmd) > 0 {
+		if err := writeCloudConfigPart(mpWriter, appended); err != nil {
+			return nil, err
+		}
+	}
+
+	if !isCloudConfig && len(extra.PostBootstrap) > 0 {
+		if err := writePart(mpWriter, shellScriptType, []byte(shellScript(extra.PostBootstrap))); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mpWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// bootstrapDataPart returns the content type and body of the part that holds the bootstrap data.
+func bootstrapDataPart(bootstrapData []byte) (string, []byte, error) {
+	switch {
+	case bytes.HasPrefix(bootstrapData, []byte("MIME-Version:")) || bytes.HasPrefix(bootstrapData, []byte("Content-Type:")):
+		msg, err := mail.ReadMessage(bufio.NewReader(bytes.NewReader(bootstrapData)))
+		if err != nil {
+			return "", nil, errors.Wrap(err, "failed to parse multipart bootstrap data")
+		}
+		var body bytes.Buffer
+		if _, err := body.ReadFrom(msg.Body); err != nil {
+			return "", nil, errors.Wrap(err, "failed to read multipart bootstrap data")
+		}
+		return msg.Header.Get("Content-Type"), body.Bytes(), nil
+	case bytes.HasPrefix(bootstrapData, []byte("#cloud-config")):
+		return cloudConfigContentType, bootstrapData, nil
+	default:
+		return shellScriptContentType, bootstrapData, nil
+	}
+}
+
+func writePart(mpWriter *multipart.Writer, header textproto.MIMEHeader, body []byte) error {
+	partWriter, err := mpWriter.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = partWriter.Write(body)
+	return err
+}
+
+func writeCloudConfigPart(mpWriter *multipart.Writer, config cloudConfig) error {
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal cloud-config")
+	}
+	return writePart(mpWriter, cloudConfigType, append([]byte("#cloud-config\n"), out...))
+}
+
+func shellScript(commands []string) string {
+	return "#!/bin/sh\nset -e\n" + strings.Join(commands, "\n") + "\n"
+}
+
+func addIgnitionExtra(bootstrapData []byte, extra *expinfrav1.NodeUserDataExtra) ([]byte, error) {
+	var versioned struct {
+		Ignition struct {
+			Version string `json:"version"`
+		} `json:"ignition"`
+	}
+	if err := json.Unmarshal(bootstrapData, &versioned); err != nil {
+		return nil, errors.Wrap(err, "failed to parse ignition bootstrap data")
+	}
+
+	version := versioned.Ignition.Version
+	source := "data:;base64," + base64.StdEncoding.EncodeToString(bootstrapData)
+
+	switch {
+	case strings.HasPrefix(version, "2."):
+		return addIgnitionV2Extra(version, source, extra)
+	case strings.HasPrefix(version, "3."):
+		return addIgnitionV3Extra(version, source, extra)
+	default:
+		return nil, errors.Errorf("unsupported ignition version %q", version)
+	}
+}
+
+func addIgnitionV2Extra(version, source string, extra *expinfrav1.NodeUserDataExtra) ([]byte, error) {
+	config := ignTypes.Config{
+		Ignition: ignTypes.Ignition{
+			Version: version,
+			Config: ignTypes.IgnitionConfig{
+				Append: []ignTypes.ConfigReference{
+					{
+						Source: source,
+					},
+				},
+			},
+		},
+	}
+
+	addFile := func(f expinfrav1.File) error {
+		mode, err := fileMode(f.Permissions)
+		if err != nil {
+			return err
+		}
+		file := ignTypes.File{
+			Node: ignTypes.Node{
+				Filesystem: "root",
+				Path:       f.Path,
+				Overwrite:  ptr.To(true),
+			},
+			FileEmbedded1: ignTypes.FileEmbedded1{
+				Contents: ignTypes.FileContents{
+					Source: dataURL(f.Content),
+				},
+				Mode: mode,
+			},
+		}
+		if user, group := fileOwner(f.Owner); user != "" {
+			file.Node.User = &ignTypes.NodeUser{Name: user}
+			if group != "" {
+				file.Node.Group = &ignTypes.NodeGroup{Name: group}
+			}
+		}
+		config.Storage.Files = append(config.Storage.Files, file)
+		return nil
+	}
+
+	for _, f := range extra.Files {
+		if err := addFile(f); err != nil {
+			return nil, err
+		}
+	}
+	for _, script := range bootstrapScripts(extra) {
+		if err := addFile(script.file); err != nil {
+			return nil, err
+		}
+		config.Systemd.Units = append(config.Systemd.Units, ignTypes.Unit{
+			Name:     script.unitName,
+			Enabled:  ptr.To(true),
+			Contents: script.unit,
+		})
+	}
+
+	return json.Marshal(config)
+}
+
+func addIgnitionV3Extra(version, source string, extra *expinfrav1.NodeUserDataExtra) ([]byte, error) {
+	config := ignV3Types.Config{
+		Ignition: ignV3Types.Ignition{
+			Version: version,
+			Config: ignV3Types.IgnitionConfig{
+				Merge: []ignV3Types.Resource{
+					{
+						Source: ptr.To(source),
+					},
+				},
+			},
+		},
+	}
+
+	addFile := func(f expinfrav1.File) error {
+		mode, err := fileMode(f.Permissions)
+		if err != nil {
+			return err
+		}
+		file := ignV3Types.File{
+			Node: ignV3Types.Node{
+				Path:      f.Path,
+				Overwrite: ptr.To(true),
+			},
+			FileEmbedded1: ignV3Types.FileEmbedded1{
+				Contents: ignV3Types.Resource{
+					Source: ptr.To(dataURL(f.Content)),
+				},
+				Mode: mode,
+			},
+		}
+		if user, group := fileOwner(f.Owner); user != "" {
+			file.Node.User = ignV3Types.NodeUser{Name: ptr.To(user)}
+			if group != "" {
+				file.Node.Group = ignV3Types.NodeGroup{Name: ptr.To(group)}
+			}
+		}
+		config.Storage.Files = append(config.Storage.Files, file)
+		return nil
+	}
+
+	for _, f := range extra.Files {
+		if err := addFile(f); err != nil {
+			return nil, err
+		}
+	}
+	for _, script := range bootstrapScripts(extra) {
+		if err := addFile(script.file); err != nil {
+			return nil, err
+		}
+		config.Systemd.Units = append(config.Systemd.Units, ignV3Types.Unit{
+			Name:     script.unitName,
+			Enabled:  ptr.To(true),
+			Contents: ptr.To(script.unit),
+		})
+	}
+
+	return json.Marshal(config)
+}
+
+type bootstrapScript struct {
+	file     expinfrav1.File
+	unitName string
+	unit     string
+}
+
+// bootstrapScripts returns the scripts and the systemd units that run the extra commands of an
+// Ignition config before and after the bootstrap unit.
+func bootstrapScripts(extra *expinfrav1.NodeUserDataExtra) []bootstrapScript {
+	var scripts []bootstrapScript
+	if len(extra.PreBootstrap) > 0 {
+		scripts = append(scripts, bootstrapScript{
+			file:     expinfrav1.File{Path: preBootstrapScriptPath, Content: shellScript(extra.PreBootstrap), Permissions: "0700"},
+			unitName: "capa-pre-bootstrap.service",
+			unit:     bootstrapUnit("Run CAPA pre-bootstrap commands", "Before", preBootstrapScriptPath),
+		})
+	}
+	if len(extra.PostBootstrap) > 0 {
+		scripts = append(scripts, bootstrapScript{
+			file:     expinfrav1.File{Path: postBootstrapScriptPath, Content: shellScript(extra.PostBootstrap), Permissions: "0700"},
+			unitName: "capa-post-bootstrap.service",
+			unit:     bootstrapUnit("Run CAPA post-bootstrap commands", "After", postBootstrapScriptPath),
+		})
+	}
+	return scripts
+}
+
+func bootstrapUnit(description, ordering, scriptPath string) string {
+	return strings.Join([]string{
+		"[Unit]",
+		"Description=" + description,
+		ordering + "=" + ignitionBootstrapUnit,
+		"ConditionPathExists=!" + scriptPath + ".done",
+		"",
+		"[Service]",
+		"Type=oneshot",
+		"ExecStart=" + scriptPath,
+		"ExecStartPost=/bin/touch " + scriptPath + ".done",
+		"",
+		"[Install]",
+		"WantedBy=multi-user.target",
+		"",
+	}, "\n")
+}
+
+func dataURL(content string) string {
+	return "data:;base64," + base64.StdEncoding.EncodeToString([]byte(content))
+}
+
+func fileMode(permissions string) (*int, error) {
+	if permissions == "" {
+		return nil, nil
+	}
+	mode, err := strconv.ParseInt(permissions, 8, 32)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse file permissions %q", permissions)
+	}
+	return ptr.To(int(mode)), nil
+}
+
+func fileOwner(owner string) (string, string) {
+	user, group, _ := strings.Cut(owner, ":")
+	return user, group
+}
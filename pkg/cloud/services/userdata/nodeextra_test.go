@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"testing"
+
+	ignTypes "github.com/coreos/ignition/config/v2_3/types"
+	ignV3Types "github.com/coreos/ignition/v2/config/v3_4/types"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/exp/api/v1beta2"
+)
+
+type testPart struct {
+	contentType string
+	body        string
+}
+
+func readParts(g *WithT, data []byte) []testPart {
+	msg, err := mail.ReadMessage(bufio.NewReader(bytes.NewReader(data)))
+	g.Expect(err).NotTo(HaveOccurred())
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(mediaType).To(Equal("multipart/mixed"))
+
+	var parts []testPart
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		g.Expect(err).NotTo(HaveOccurred())
+		body, err := io.ReadAll(part)
+		g.Expect(err).NotTo(HaveOccurred())
+		parts = append(parts, testPart{contentType: part.Header.Get("Content-Type"), body: string(body)})
+	}
+	return parts
+}
+
+func TestAddNodeUserDataExtra(t *testing.T) {
+	extra := &expinfrav1.NodeUserDataExtra{
+		PreBootstrap:  []string{"sysctl -w vm.max_map_count=262144"},
+		PostBootstrap: []string{"echo done"},
+		Files: []expinfrav1.File{
+			{
+				Path:        "/etc/sysctl.d/99-custom.conf",
+				Content:     "vm.max_map_count=262144",
+				Owner:       "root:root",
+				Permissions: "0644",
+			},
+		},
+	}
+
+	t.Run("returns the bootstrap data unchanged without extra", func(t *testing.T) {
+		g := NewWithT(t)
+		data, err := AddNodeUserDataExtra([]byte("#cloud-config\n"), "cloud-config", nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(data).To(Equal([]byte("#cloud-config\n")))
+
+		data, err = AddNodeUserDataExtra([]byte("#cloud-config\n"), "cloud-config", &expinfrav1.NodeUserDataExtra{})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(data).To(Equal([]byte("#cloud-config\n")))
+	})
+
+	t.Run("merges the extra into a cloud-config", func(t *testing.T) {
+		g := NewWithT(t)
+		bootstrap := "#cloud-config\nruncmd:\n- kubeadm join\n"
+		data, err := AddNodeUserDataExtra([]byte(bootstrap), "cloud-config", extra)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		parts := readParts(g, data)
+		g.Expect(parts).To(HaveLen(3))
+		g.Expect(parts[0]).To(Equal(testPart{contentType: "text/cloud-config", body: bootstrap}))
+
+		var pre, post cloudConfig
+		g.Expect(parts[1].contentType).To(Equal("text/cloud-config"))
+		g.Expect(yaml.Unmarshal([]byte(parts[1].body), &pre)).To(Succeed())
+		g.Expect(pre).To(Equal(cloudConfig{MergeHow: prependMergeHow, RunCmd: extra.PreBootstrap}))
+
+		g.Expect(parts[2].contentType).To(Equal("text/cloud-config"))
+		g.Expect(yaml.Unmarshal([]byte(parts[2].body), &post)).To(Succeed())
+		g.Expect(post).To(Equal(cloudConfig{
+			MergeHow: appendMergeHow,
+			WriteFiles: []cloudConfigFile{
+				{Path: "/etc/sysctl.d/99-custom.conf", Content: "vm.max_map_count=262144", Owner: "root:root", Permissions: "0644"},
+			},
+			RunCmd: extra.PostBootstrap,
+		}))
+	})
+
+	t.Run("runs the extra commands around a shell script", func(t *testing.T) {
+		g := NewWithT(t)
+		bootstrap := "#!/bin/bash\n/etc/eks/bootstrap.sh cluster\n"
+		data, err := AddNodeUserDataExtra([]byte(bootstrap), "", extra)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		parts := readParts(g, data)
+		g.Expect(parts).To(HaveLen(4))
+		g.Expect(parts[0]).To(Equal(testPart{contentType: "text/x-shellscript", body: "#!/bin/sh\nset -e\nsysctl -w vm.max_map_count=262144\n"}))
+		g.Expect(parts[1]).To(Equal(testPart{contentType: "text/x-shellscript", body: bootstrap}))
+		g.Expect(parts[2].contentType).To(Equal("text/cloud-config"))
+		g.Expect(parts[3]).To(Equal(testPart{contentType: "text/x-shellscript", body: "#!/bin/sh\nset -e\necho done\n"}))
+	})
+
+	t.Run("nests multipart bootstrap data", func(t *testing.T) {
+		g := NewWithT(t)
+		bootstrap := "MIME-Version: 1.0\nContent-Type: multipart/mixed; boundary=\"BOUNDARY\"\n\n--BOUNDARY\nContent-Type: text/x-shellscript\n\necho hello\n--BOUNDARY--\n"
+		data, err := AddNodeUserDataExtra([]byte(bootstrap), "", &expinfrav1.NodeUserDataExtra{PostBootstrap: []string{"echo done"}})
+		g.Expect(err).NotTo(HaveOccurred())
+
+		parts := readParts(g, data)
+		g.Expect(parts).To(HaveLen(2))
+		g.Expect(parts[0].contentType).To(Equal("multipart/mixed; boundary=\"BOUNDARY\""))
+		g.Expect(parts[0].body).To(ContainSubstring("echo hello"))
+		g.Expect(parts[1]).To(Equal(testPart{contentType: "text/x-shellscript", body: "#!/bin/sh\nset -e\necho done\n"}))
+	})
+
+	t.Run("merges the bootstrap data of an ignition v2 config", func(t *testing.T) {
+		g := NewWithT(t)
+		data, err := AddNodeUserDataExtra([]byte(`{"ignition":{"version":"2.3.0"}}`), FormatIgnition, extra)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		config := ignTypes.Config{}
+		g.Expect(json.Unmarshal(data, &config)).To(Succeed())
+		g.Expect(config.Ignition.Version).To(Equal("2.3.0"))
+		g.Expect(config.Ignition.Config.Append).To(HaveLen(1))
+		g.Expect(config.Ignition.Config.Append[0].Source).To(Equal(dataURL(`{"ignition":{"version":"2.3.0"}}`)))
+		g.Expect(config.Storage.Files).To(HaveLen(3))
+		g.Expect(config.Storage.Files[0].Path).To(Equal("/etc/sysctl.d/99-custom.conf"))
+		g.Expect(*config.Storage.Files[0].Mode).To(Equal(0o644))
+		g.Expect(config.Storage.Files[0].User.Name).To(Equal("root"))
+		g.Expect(config.Storage.Files[1].Path).To(Equal(preBootstrapScriptPath))
+		g.Expect(config.Storage.Files[2].Path).To(Equal(postBootstrapScriptPath))
+		g.Expect(config.Systemd.Units).To(HaveLen(2))
+		g.Expect(config.Systemd.Units[0].Contents).To(ContainSubstring("Before=kubeadm.service"))
+		g.Expect(config.Systemd.Units[1].Contents).To(ContainSubstring("After=kubeadm.service"))
+	})
+
+	t.Run("merges the bootstrap data of an ignition v3 config", func(t *testing.T) {
+		g := NewWithT(t)
+		data, err := AddNodeUserDataExtra([]byte(`{"ignition":{"version":"3.4.0"}}`), FormatIgnition, extra)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		config := ignV3Types.Config{}
+		g.Expect(json.Unmarshal(data, &config)).To(Succeed())
+		g.Expect(config.Ignition.Version).To(Equal("3.4.0"))
+		g.Expect(config.Ignition.Config.Merge).To(HaveLen(1))
+		g.Expect(config.Storage.Files).To(HaveLen(3))
+		g.Expect(*config.Storage.Files[0].Group.Name).To(Equal("root"))
+		g.Expect(config.Systemd.Units).To(HaveLen(2))
+	})
+
+	t.Run("fails on unsupported ignition version", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := AddNodeUserDataExtra([]byte(`{"ignition":{"version":"1.0.0"}}`), FormatIgnition, extra)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// TransformInput is the context the templates of the user data transforms are rendered with.
+type TransformInput struct {
+	// BootstrapData is the bootstrap data generated by the bootstrap provider.
+	BootstrapData string
+
+	// ClusterName is the name of the cluster.
+	ClusterName string
+
+	// ClusterEndpoint is the URL of the API server of the cluster, e.g. https://example.com:6443.
+	ClusterEndpoint string
+
+	// CACertHash is the hash of the public key of the CA of the cluster, in the sha256:<hex> format used
+	// by kubeadm. It is empty if the CA isn't stored in the management cluster.
+	CACertHash string
+
+	// NodeLabels are the labels of the machine which Cluster API propagates to the node.
+	NodeLabels map[string]string
+
+	// S3URL is the URL of the bootstrap data when it is stored in the S3 bucket of the cluster.
+	S3URL string
+}
+
+// ParseTransform parses the template of a user data transform. Referencing a missing key of a map,
+// e.g. a node label which isn't set, is an error when the template is rendered.
+func ParseTransform(text string) (*template.Template, error) {
+	tm, err := template.New("userDataTransform").Funcs(defaultTemplateFuncMap).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse user data transform template")
+	}
+	return tm, nil
+}
+
+// RenderTransform renders the template of a user data transform with the input. The rendered user data
+// can't exceed MaxUserDataSize.
+func RenderTransform(text string, input *TransformInput) ([]byte, error) {
+	tm, err := ParseTransform(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := tm.Execute(&out, input); err != nil {
+		return nil, errors.Wrap(err, "failed to render user data transform template")
+	}
+	if out.Len() == 0 {
+		return nil, errors.New("user data transform template rendered empty user data")
+	}
+	if out.Len() > MaxUserDataSize {
+		return nil, errors.Errorf("user data transform template rendered %d bytes, which exceeds the maximum of %d bytes", out.Len(), MaxUserDataSize)
+	}
+
+	return out.Bytes(), nil
+}
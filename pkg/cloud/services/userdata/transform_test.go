@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRenderTransform(t *testing.T) {
+	input := &TransformInput{
+		BootstrapData:   "#cloud-config\n",
+		ClusterName:     "test-cluster",
+		ClusterEndpoint: "https://example.com:6443",
+		CACertHash:      "sha256:0123456789abcdef",
+		NodeLabels:      map[string]string{"node-role.kubernetes.io/worker": ""},
+		S3URL:           "s3://bucket/node/machine",
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "renders the context",
+			text: `{"endpoint":"{{ .ClusterEndpoint }}","caHash":"{{ .CACertHash }}","url":"{{ .S3URL }}","cluster":"{{ .ClusterName }}"}`,
+			want: `{"endpoint":"https://example.com:6443","caHash":"sha256:0123456789abcdef","url":"s3://bucket/node/machine","cluster":"test-cluster"}`,
+		},
+		{
+			name: "template functions are available",
+			text: "data: {{ Base64Encode .BootstrapData }}\nlabels:\n{{ range $k, $v := .NodeLabels }}{{ Indent 2 $k }}{{ end }}",
+			want: "data: I2Nsb3VkLWNvbmZpZwo=\nlabels:\n  node-role.kubernetes.io/worker",
+		},
+		{
+			name:    "parse error",
+			text:    "{{ .BootstrapData",
+			wantErr: "failed to parse user data transform template",
+		},
+		{
+			name:    "missing node label",
+			text:    "{{ .NodeLabels.missing }}",
+			wantErr: "failed to render user data transform template",
+		},
+		{
+			name:    "empty user data",
+			text:    "{{ if false }}unused{{ end }}",
+			wantErr: "rendered empty user data",
+		},
+		{
+			name:    "user data exceeding the maximum size",
+			text:    strings.Repeat("a", MaxUserDataSize+1),
+			wantErr: "exceeds the maximum",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := RenderTransform(tt.text, input)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(got)).To(Equal(tt.want))
+		})
+	}
+}
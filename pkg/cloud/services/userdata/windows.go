@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+)
+
+var (
+	powershellOpeningTag = []byte("<powershell>")
+	powershellClosingTag = []byte("</powershell>")
+	scriptOpeningTag     = []byte("<script>")
+)
+
+// ForOS frames the bootstrap data for the operating system of the node. EC2Launch only runs the
+// user data of Windows instances that is enclosed in <powershell> or <script> tags, so the bootstrap
+// data of Windows nodes is wrapped in <powershell> tags unless it is already framed.
+// The bootstrap data of Linux nodes is returned unchanged.
+func ForOS(data []byte, format string, os infrav1.OSType) ([]byte, error) {
+	if os != infrav1.OSTypeWindows {
+		return data, nil
+	}
+
+	if format == FormatIgnition {
+		return nil, errors.New("ignition bootstrap data is not supported on windows")
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, powershellOpeningTag) || bytes.HasPrefix(trimmed, scriptOpeningTag) {
+		return data, nil
+	}
+
+	framed := make([]byte, 0, len(powershellOpeningTag)+len(trimmed)+len(powershellClosingTag)+2)
+	framed = append(framed, powershellOpeningTag...)
+	framed = append(framed, '\n')
+	framed = append(framed, trimmed...)
+	framed = append(framed, '\n')
+	framed = append(framed, powershellClosingTag...)
+
+	return framed, nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+)
+
+func TestForOS(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		format  string
+		os      infrav1.OSType
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "unset operating system leaves the bootstrap data unchanged",
+			data: "#cloud-config\nruncmd:\n- kubeadm join\n",
+			want: "#cloud-config\nruncmd:\n- kubeadm join\n",
+		},
+		{
+			name:   "linux leaves the bootstrap data unchanged",
+			data:   "#cloud-config\nruncmd:\n- kubeadm join\n",
+			format: "cloud-config",
+			os:     infrav1.OSTypeLinux,
+			want:   "#cloud-config\nruncmd:\n- kubeadm join\n",
+		},
+		{
+			name:   "linux leaves ignition bootstrap data unchanged",
+			data:   `{"ignition":{"version":"3.4.0"}}`,
+			format: FormatIgnition,
+			os:     infrav1.OSTypeLinux,
+			want:   `{"ignition":{"version":"3.4.0"}}`,
+		},
+		{
+			name: "windows wraps the bootstrap data in powershell tags",
+			data: "& C:\\k\\bootstrap.ps1\n",
+			os:   infrav1.OSTypeWindows,
+			want: "<powershell>\n& C:\\k\\bootstrap.ps1\n</powershell>",
+		},
+		{
+			name: "windows leaves bootstrap data framed with powershell tags unchanged",
+			data: "\n<powershell>\n& C:\\k\\bootstrap.ps1\n</powershell>\n<persist>true</persist>",
+			os:   infrav1.OSTypeWindows,
+			want: "\n<powershell>\n& C:\\k\\bootstrap.ps1\n</powershell>\n<persist>true</persist>",
+		},
+		{
+			name: "windows leaves bootstrap data framed with script tags unchanged",
+			data: "<script>\necho bootstrap\n</script>",
+			os:   infrav1.OSTypeWindows,
+			want: "<script>\necho bootstrap\n</script>",
+		},
+		{
+			name:    "windows rejects ignition bootstrap data",
+			data:    `{"ignition":{"version":"3.4.0"}}`,
+			format:  FormatIgnition,
+			os:      infrav1.OSTypeWindows,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := ForOS([]byte(tt.data), tt.format, tt.os)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(got)).To(Equal(tt.want))
+		})
+	}
+}